@@ -0,0 +1,57 @@
+package benchmarks
+
+import (
+	"testing"
+	"time"
+)
+
+// scenarios are the standard runs of the pipeline, reported by TestScenarios.
+var scenarios = []Scenario{
+	{Name: "trigger", Events: 5000, Keyspace: 1000, Seed: 1},
+	{Name: "updates", Events: 5000, Keyspace: 20, Seed: 2},
+	{Name: "mixed", Events: 5000, Keyspace: 200, ResolveRatio: 0.3, Seed: 3},
+	{Name: "concurrent", Events: 5000, Keyspace: 1000, ResolveRatio: 0.1, Concurrency: 8, Seed: 4},
+}
+
+func TestScenarios(t *testing.T) {
+	for _, s := range scenarios {
+		t.Run(s.Name, func(t *testing.T) {
+			if testing.Short() {
+				s.Events /= 10
+			}
+			report := Run(t, s)
+			if report.Processing.P50 <= 0 || report.Throughput <= 0 {
+				t.Fatalf("Run() measured nothing: %s", report)
+			}
+			t.Log(report)
+		})
+	}
+}
+
+func BenchmarkPipeline_Trigger(b *testing.B) {
+	benchmarkPipeline(b, Scenario{Name: "trigger", Keyspace: 1000, Seed: 1})
+}
+
+func BenchmarkPipeline_Updates(b *testing.B) {
+	benchmarkPipeline(b, Scenario{Name: "updates", Keyspace: 20, Seed: 2})
+}
+
+func BenchmarkPipeline_Mixed(b *testing.B) {
+	benchmarkPipeline(b, Scenario{Name: "mixed", Keyspace: 200, ResolveRatio: 0.3, Seed: 3})
+}
+
+func BenchmarkPipeline_Concurrent(b *testing.B) {
+	benchmarkPipeline(b, Scenario{Name: "concurrent", Keyspace: 1000, ResolveRatio: 0.1, Concurrency: 8, Seed: 4})
+}
+
+// benchmarkPipeline runs a scenario of b.N events, reporting the throughput
+// and processing latency alongside the time per event.
+func benchmarkPipeline(b *testing.B, s Scenario) {
+	s.Events = b.N
+	b.ResetTimer()
+	report := Run(b, s)
+	b.StopTimer()
+
+	b.ReportMetric(report.Throughput, "events/s")
+	b.ReportMetric(float64(report.Processing.P99)/float64(time.Microsecond), "p99-µs")
+}
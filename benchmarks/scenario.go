@@ -0,0 +1,161 @@
+// Package benchmarks measures the throughput and latency of the event
+// pipeline, ingest → queue → processor → store, on the in-memory instance
+// of pkg/argustest, so performance-motivated refactors can be measured.
+//
+// The Go benchmarks report events per second:
+//
+//	go test -run '^$' -bench . -benchmem ./benchmarks
+//
+// and the scenarios report latency percentiles as well:
+//
+//	go test -run TestScenarios -v ./benchmarks
+package benchmarks
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"argus-go/internal/testgen"
+	"argus-go/pkg/argustest"
+)
+
+// Scenario shapes a run of the pipeline. Runs of a scenario ingest the
+// same events, so their reports can be compared.
+type Scenario struct {
+	// Name identifies the scenario in reports.
+	Name string
+
+	// Events is the number of events ingested.
+	Events int
+
+	// Keyspace is the number of distinct dedup keys; a smaller keyspace
+	// updates existing alerts more often. Zero means 100.
+	Keyspace int
+
+	// ResolveRatio is the fraction of events sent as resolve.
+	ResolveRatio float64
+
+	// Concurrency is the number of goroutines ingesting. Zero means 1.
+	Concurrency int
+
+	// Seed seeds the generated events.
+	Seed int64
+}
+
+// Report is the outcome of a run of a scenario.
+type Report struct {
+	Scenario Scenario
+
+	// Duration is how long ingesting and processing every event took.
+	Duration time.Duration
+
+	// Throughput is the number of events processed per second.
+	Throughput float64
+
+	// Ingest holds the percentiles of the time taken to ingest an event,
+	// up to it being queued.
+	Ingest Percentiles
+
+	// Processing holds the percentiles of the time from an event being
+	// queued to it being processed, i.e. stored as an alert.
+	Processing Percentiles
+}
+
+// Percentiles summarizes a distribution of latencies.
+type Percentiles struct {
+	P50, P95, P99, Max time.Duration
+}
+
+// String formats the report as a single line, e.g. for the test log.
+func (r *Report) String() string {
+	return fmt.Sprintf("%s: %d events in %s (%.0f events/s) ingest p50=%s p95=%s p99=%s processing p50=%s p95=%s p99=%s max=%s",
+		r.Scenario.Name, r.Scenario.Events, r.Duration.Round(time.Millisecond), r.Throughput,
+		r.Ingest.P50, r.Ingest.P95, r.Ingest.P99,
+		r.Processing.P50, r.Processing.P95, r.Processing.P99, r.Processing.Max)
+}
+
+// Run ingests the events of a scenario into a new in-memory instance and
+// waits for them to be processed, measuring each event.
+func Run(tb testing.TB, s Scenario) *Report {
+	tb.Helper()
+
+	h := argustest.Start(tb)
+	h.Timeout = time.Minute
+	events := generate(s, h.CreateEventManager(tb, "class", 5*time.Minute))
+
+	var mu sync.Mutex
+	processing := make([]time.Duration, 0, len(events))
+	h.ObserveProcessing(func(latency time.Duration) {
+		mu.Lock()
+		processing = append(processing, latency)
+		mu.Unlock()
+	})
+
+	// Each worker ingests every concurrency-th event
+	concurrency := max(s.Concurrency, 1)
+	ingest := make([]time.Duration, len(events))
+	start := time.Now()
+	var wg sync.WaitGroup
+	for worker := range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := worker; i < len(events); i += concurrency {
+				began := time.Now()
+				h.Ingest(tb, events[i])
+				ingest[i] = time.Since(began)
+			}
+		}()
+	}
+	wg.Wait()
+	if tb.Failed() {
+		tb.FailNow()
+	}
+	h.Sync(tb)
+	duration := time.Since(start)
+
+	mu.Lock()
+	defer mu.Unlock()
+	return &Report{
+		Scenario:   s,
+		Duration:   duration,
+		Throughput: float64(len(events)) / duration.Seconds(),
+		Ingest:     percentiles(ingest),
+		Processing: percentiles(processing),
+	}
+}
+
+// generate returns the events of a scenario for an event manager.
+func generate(s Scenario, eventManagerID string) []*argustest.Event {
+	gen := testgen.New(s.Seed)
+	spec := testgen.EventSpec{EventManagerID: eventManagerID, Keyspace: s.Keyspace, ResolveRatio: s.ResolveRatio}
+
+	events := make([]*argustest.Event, s.Events)
+	for i := range events {
+		events[i] = gen.Event(spec)
+	}
+	return events
+}
+
+// percentiles returns the percentiles of latencies, sorting them.
+func percentiles(latencies []time.Duration) Percentiles {
+	if len(latencies) == 0 {
+		return Percentiles{}
+	}
+	slices.Sort(latencies)
+	return Percentiles{
+		P50: percentile(latencies, 0.50),
+		P95: percentile(latencies, 0.95),
+		P99: percentile(latencies, 0.99),
+		Max: latencies[len(latencies)-1].Round(time.Microsecond),
+	}
+}
+
+// percentile returns the p-th percentile of sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	idx := int(float64(len(sorted)-1) * p)
+	return sorted[idx].Round(time.Microsecond)
+}
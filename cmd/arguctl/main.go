@@ -0,0 +1,347 @@
+// Package main is a command line client for managing the ArgusGo configuration
+// declaratively. It exports the grouping rules and event managers of a running
+// instance as YAML and applies YAML documents to it, e.g. from a Git repository.
+// It also exports the hash-chained audit log and verifies it locally, without
+// trusting the server that holds it.
+//
+// Usage:
+//
+//	arguctl [-server URL] export [-o FILE]
+//	arguctl [-server URL] [-token TOKEN] apply -f FILE [-dry-run]
+//	arguctl [-server URL] [-token TOKEN] audit export [-o FILE]
+//	arguctl [-server URL] [-token TOKEN] audit verify [-f FILE] [-head HASH]
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"argus-go/internal/declarative"
+	"argus-go/internal/domain"
+)
+
+// configPath is the API path of the declarative configuration.
+const configPath = "/v1/config/export"
+
+// applyPath is the admin API path applying a declarative configuration.
+const applyPath = "/v1/admin/config"
+
+// auditExportPath is the API path of the full audit log.
+const auditExportPath = "/v1/admin/audit/export"
+
+func main() {
+	server := flag.String("server", envOr("ARGUS_SERVER", "http://localhost:8080"), "ArgusGo base URL (env ARGUS_SERVER)")
+	token := flag.String("token", os.Getenv("ARGUS_ADMIN_TOKEN"), "admin token of the /v1/admin API (env ARGUS_ADMIN_TOKEN)")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	baseURL := strings.TrimSuffix(*server, "/")
+
+	var err error
+	switch cmd, args := flag.Arg(0), flag.Args()[1:]; cmd {
+	case "export":
+		err = runExport(client, baseURL, args)
+	case "apply":
+		err = runApply(client, baseURL, *token, args)
+	case "audit":
+		err = runAudit(client, baseURL, *token, args)
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage:
+  arguctl [-server URL] export [-o FILE]            Write the configuration as YAML
+  arguctl [-token TOKEN] apply -f FILE [-dry-run]   Apply a YAML configuration
+  arguctl [-token TOKEN] audit export [-o FILE]     Write the audit log as JSON
+  arguctl [-token TOKEN] audit verify [-f FILE] [-head HASH]
+                                                    Verify the hash chain of the audit log
+
+Flags:
+`)
+	flag.PrintDefaults()
+}
+
+// runExport writes the exported configuration to stdout or a file.
+func runExport(client *http.Client, baseURL string, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	output := fs.String("o", "", "write to this file instead of stdout")
+	_ = fs.Parse(args)
+
+	resp, err := client.Get(baseURL + configPath)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return apiError(resp.StatusCode, body)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(body)
+		return err
+	}
+	return os.WriteFile(*output, body, 0o600)
+}
+
+// runApply sends a document to the server and prints the resulting plan.
+func runApply(client *http.Client, baseURL, token string, args []string) error {
+	fs := flag.NewFlagSet("apply", flag.ExitOnError)
+	file := fs.String("f", "", "YAML document to apply (- for stdin)")
+	dryRun := fs.Bool("dry-run", false, "only show the changes that would be made")
+	_ = fs.Parse(args)
+
+	if *file == "" {
+		return errors.New("apply requires -f FILE")
+	}
+
+	var data []byte
+	var err error
+	if *file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(*file)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Catch syntax and validation errors before contacting the server
+	if _, err := declarative.Parse(data); err != nil {
+		return err
+	}
+
+	url := baseURL + applyPath
+	if *dryRun {
+		url += "?dry_run=true"
+	}
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/yaml")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return apiError(resp.StatusCode, body)
+	}
+
+	var result struct {
+		Data declarative.Plan `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	printPlan(&result.Data)
+	return nil
+}
+
+// runAudit runs an audit subcommand.
+func runAudit(client *http.Client, baseURL, token string, args []string) error {
+	if len(args) == 0 {
+		return errors.New("audit requires export or verify")
+	}
+	switch cmd, args := args[0], args[1:]; cmd {
+	case "export":
+		return runAuditExport(client, baseURL, token, args)
+	case "verify":
+		return runAuditVerify(client, baseURL, token, args)
+	default:
+		return fmt.Errorf("unknown audit command %q", cmd)
+	}
+}
+
+// runAuditExport writes every audit log entry, oldest first, as a JSON
+// array to stdout or a file.
+func runAuditExport(client *http.Client, baseURL, token string, args []string) error {
+	fs := flag.NewFlagSet("audit export", flag.ExitOnError)
+	output := fs.String("o", "", "write to this file instead of stdout")
+	_ = fs.Parse(args)
+
+	entries, err := fetchAuditLog(client, baseURL, token)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*output, data, 0o600)
+}
+
+// runAuditVerify verifies the hash chain of an exported audit log, or of
+// the log of the server if no file is given. With -head, it also checks
+// that a head hash kept from an earlier verification is still part of the
+// chain, which proves no entry was removed since.
+func runAuditVerify(client *http.Client, baseURL, token string, args []string) error {
+	fs := flag.NewFlagSet("audit verify", flag.ExitOnError)
+	file := fs.String("f", "", "exported audit log to verify (- for stdin) instead of the server's")
+	head := fs.String("head", "", "hash of an earlier head that must still be in the chain")
+	_ = fs.Parse(args)
+
+	var entries []*domain.AuditEntry
+	var err error
+	if *file == "" {
+		entries, err = fetchAuditLog(client, baseURL, token)
+	} else {
+		entries, err = readAuditLog(*file)
+	}
+	if err != nil {
+		return err
+	}
+
+	result := domain.VerifyAuditLog(entries)
+	if !result.Valid {
+		return fmt.Errorf("audit log is broken at entry %d: %s", result.BrokenAt, result.Reason)
+	}
+	if *head != "" && !domain.ContainsAuditHash(entries, *head) {
+		return fmt.Errorf("head %s is not in the audit log: entries were removed or rewritten", *head)
+	}
+
+	fmt.Printf("audit log intact: %d entries (%d recorded before chaining)\n", result.Entries, result.Unchained)
+	if result.Head != "" {
+		fmt.Printf("head: %s\n", result.Head)
+	}
+	return nil
+}
+
+// fetchAuditLog retrieves every audit log entry from the server.
+func fetchAuditLog(client *http.Client, baseURL, token string) ([]*domain.AuditEntry, error) {
+	req, err := http.NewRequest(http.MethodGet, baseURL+auditExportPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp.StatusCode, body)
+	}
+
+	var result struct {
+		Data []*domain.AuditEntry `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return result.Data, nil
+}
+
+// readAuditLog reads an audit log written by audit export.
+func readAuditLog(file string) ([]*domain.AuditEntry, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*domain.AuditEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// printPlan prints one line per changed resource and a summary.
+func printPlan(plan *declarative.Plan) {
+	counts := make(map[declarative.ChangeAction]int)
+	for _, change := range plan.Changes {
+		counts[change.Action]++
+		if change.Action == declarative.ChangeUnchanged {
+			continue
+		}
+		line := fmt.Sprintf("%-9s %s %s (%s)", change.Action, change.Kind, change.ID, change.Name)
+		if len(change.Fields) > 0 {
+			line += ": " + strings.Join(change.Fields, ", ")
+		}
+		fmt.Println(line)
+	}
+
+	verb := "applied"
+	if plan.DryRun {
+		verb = "would be applied (dry run)"
+	}
+	fmt.Printf("%d to create, %d to update, %d unchanged; %s\n",
+		counts[declarative.ChangeCreate], counts[declarative.ChangeUpdate], counts[declarative.ChangeUnchanged], verb)
+}
+
+// apiError converts an error response of the API to an error.
+func apiError(status int, body []byte) error {
+	var resp struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err == nil && resp.Error.Message != "" {
+		return fmt.Errorf("server returned %d: %s", status, resp.Error.Message)
+	}
+	return fmt.Errorf("server returned %d", status)
+}
+
+// envOr returns the value of an environment variable, or def if it is unset.
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,377 @@
+// Package main is a load generator for ArgusGo.
+// It produces synthetic events at a configurable rate and keyspace, either
+// through the HTTP API or directly onto the Kafka topic, and reports the
+// achieved throughput and the end-to-end latency observed through the API.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	"argus-go/internal/ingest"
+	kafkaqueue "argus-go/internal/queue/kafka"
+	postgresstor "argus-go/internal/store/postgres"
+	"argus-go/internal/testgen"
+)
+
+// Send modes supported by the load generator.
+const (
+	modeHTTP  = "http"
+	modeQueue = "queue"
+)
+
+// options holds the parsed command line flags.
+type options struct {
+	baseURL        string
+	mode           string
+	configPath     string
+	eventManagerID string
+	rate           int
+	duration       time.Duration
+	concurrency    int
+	keyspace       int
+	classes        int
+	resolveRatio   float64
+	sampleRatio    float64
+	seed           int64
+	severities     string
+	probeTimeout   time.Duration
+}
+
+// sender publishes a single event, either over HTTP or directly to the queue.
+type sender func(ctx context.Context, event *domain.Event) error
+
+// stats collects counters and latency samples for a run.
+type stats struct {
+	sent    atomic.Int64
+	failed  atomic.Int64
+	dropped atomic.Int64
+	lost    atomic.Int64
+	elapsed time.Duration
+
+	mu        sync.Mutex
+	latencies []time.Duration
+}
+
+func main() {
+	opts, err := parseFlags(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		os.Exit(2)
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	if err := opts.validate(); err != nil {
+		logger.Error("invalid flags", "error", err)
+		os.Exit(2)
+	}
+	if opts.seed == 0 {
+		opts.seed = time.Now().UnixNano()
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	send, cleanup, err := newSender(ctx, opts, client, logger)
+	if err != nil {
+		logger.Error("failed to initialize sender", "error", err)
+		os.Exit(1)
+	}
+	defer cleanup()
+
+	logger.Info("starting load generation",
+		"mode", opts.mode,
+		"rate", opts.rate,
+		"duration", opts.duration,
+		"concurrency", opts.concurrency,
+		"keyspace", opts.keyspace,
+		"seed", opts.seed,
+	)
+
+	result := run(ctx, opts, send, client, logger)
+	result.print(opts)
+}
+
+// parseFlags reads the command line flags from args. Parse errors are
+// reported on stderr along with the usage.
+func parseFlags(args []string) (*options, error) {
+	opts := &options{}
+	fs := flag.NewFlagSet("argus-loadgen", flag.ContinueOnError)
+	fs.StringVar(&opts.baseURL, "url", "http://localhost:8080", "base URL of the ArgusGo API")
+	fs.StringVar(&opts.mode, "mode", modeHTTP, "how events are sent: http (POST /v1/events) or queue (publish to Kafka)")
+	fs.StringVar(&opts.configPath, "config", "config/config-storage.yaml", "ArgusGo configuration file, used in queue mode")
+	fs.StringVar(&opts.eventManagerID, "event-manager", "", "event manager ID to send events to (required)")
+	fs.IntVar(&opts.rate, "rate", 100, "target events per second")
+	fs.DurationVar(&opts.duration, "duration", 30*time.Second, "how long to generate load")
+	fs.IntVar(&opts.concurrency, "concurrency", 10, "number of concurrent senders")
+	fs.IntVar(&opts.keyspace, "keyspace", 1000, "number of distinct dedup keys to cycle through")
+	fs.IntVar(&opts.classes, "classes", 10, "number of distinct event classes")
+	fs.Float64Var(&opts.resolveRatio, "resolve-ratio", 0, "fraction of events sent as resolve instead of trigger")
+	fs.Float64Var(&opts.sampleRatio, "sample-ratio", 0.01, "fraction of events tracked for end-to-end latency")
+	fs.DurationVar(&opts.probeTimeout, "probe-timeout", 30*time.Second, "how long to wait for a sampled alert to appear")
+	fs.Int64Var(&opts.seed, "seed", 0, "seed of the generated events, to reproduce a run (0 picks one)")
+	fs.StringVar(&opts.severities, "severities", "", "comma-separated severity levels of the target deployment, most severe first (default high,medium,low; queue mode reads them from -config)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// validate checks the flags for consistency.
+func (o *options) validate() error {
+	if o.eventManagerID == "" {
+		return errors.New("-event-manager is required")
+	}
+	if o.mode != modeHTTP && o.mode != modeQueue {
+		return fmt.Errorf("unknown mode %q", o.mode)
+	}
+	if o.rate <= 0 || o.concurrency <= 0 || o.keyspace <= 0 || o.classes <= 0 {
+		return errors.New("-rate, -concurrency, -keyspace and -classes must be positive")
+	}
+	if o.resolveRatio < 0 || o.resolveRatio > 1 || o.sampleRatio < 0 || o.sampleRatio > 1 {
+		return errors.New("-resolve-ratio and -sample-ratio must be between 0 and 1")
+	}
+	if o.severities != "" {
+		levels := config.SeveritiesConfig{Levels: strings.Split(o.severities, ",")}
+		scale, err := levels.Scale()
+		if err != nil {
+			return fmt.Errorf("invalid -severities: %w", err)
+		}
+		domain.SetSeverityScale(scale)
+	}
+	return nil
+}
+
+// newSender returns the event sender for the configured mode and a cleanup function.
+func newSender(ctx context.Context, opts *options, client *http.Client, logger *slog.Logger) (sender, func(), error) {
+	if opts.mode == modeHTTP {
+		return func(ctx context.Context, event *domain.Event) error {
+			return postEvent(ctx, client, opts.baseURL, event)
+		}, func() {}, nil
+	}
+
+	// Queue mode publishes through the ingest service so events are enriched
+	// and partitioned exactly as the API would.
+	cfg, err := config.Load(opts.configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.severities == "" {
+		scale, _ := cfg.Severities.Scale()
+		domain.SetSeverityScale(scale)
+	}
+
+	db, err := postgresstor.NewDB(ctx, &cfg.Postgres)
+	if err != nil {
+		return nil, nil, err
+	}
+	producer := kafkaqueue.NewProducer(&cfg.Kafka)
+
+	service := ingest.NewService(
+		producer,
+		postgresstor.NewEventManagerRepository(db),
+		postgresstor.NewGroupingRuleRepository(db),
+		ingest.NewGroupingDefaults(cfg.Grouping.DefaultRuleID),
+		nil,
+		nil,
+		nil,
+		logger,
+	)
+
+	cleanup := func() {
+		_ = producer.Close()
+		db.Close()
+	}
+
+	return service.IngestEvent, cleanup, nil
+}
+
+// run generates load until the duration elapses or the context is canceled.
+func run(ctx context.Context, opts *options, send sender, client *http.Client, logger *slog.Logger) *stats {
+	ctx, cancel := context.WithTimeout(ctx, opts.duration)
+	defer cancel()
+
+	runID := uuid.New().String()[:8]
+	result := &stats{}
+
+	jobs := make(chan int64, opts.concurrency)
+	var workers, probes sync.WaitGroup
+
+	for i := 0; i < opts.concurrency; i++ {
+		workers.Add(1)
+		gen := testgen.New(opts.seed + int64(i))
+		go func() {
+			defer workers.Done()
+			for seq := range jobs {
+				sampled := gen.Float64() < opts.sampleRatio
+				event := buildEvent(opts, gen, runID, seq, sampled)
+
+				sentAt := time.Now()
+				if err := send(ctx, event); err != nil {
+					if ctx.Err() == nil {
+						result.failed.Add(1)
+						logger.Debug("failed to send event", "error", err)
+					}
+					continue
+				}
+				result.sent.Add(1)
+
+				if sampled {
+					probes.Add(1)
+					go func() {
+						defer probes.Done()
+						result.probe(client, opts, event.DedupKey, sentAt)
+					}()
+				}
+			}
+		}()
+	}
+
+	start := time.Now()
+	ticker := time.NewTicker(time.Second / time.Duration(opts.rate))
+	defer ticker.Stop()
+
+	var seq int64
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-ticker.C:
+			select {
+			case jobs <- seq:
+				seq++
+			default:
+				// All senders busy - the target rate is not achievable
+				result.dropped.Add(1)
+			}
+		}
+	}
+	close(jobs)
+	workers.Wait()
+	result.elapsed = time.Since(start)
+
+	logger.Info("load generation finished, waiting for latency probes", "elapsed", result.elapsed)
+	probes.Wait()
+
+	return result
+}
+
+// buildEvent creates a synthetic event. Sampled events get a unique dedup key
+// so that they always create a new alert whose appearance can be timed.
+func buildEvent(opts *options, gen *testgen.Generator, runID string, seq int64, sampled bool) *domain.Event {
+	event := gen.Event(testgen.EventSpec{
+		EventManagerID: opts.eventManagerID,
+		KeyPrefix:      fmt.Sprintf("loadgen-%s-", runID),
+		Keyspace:       opts.keyspace,
+		Classes:        opts.classes,
+		ResolveRatio:   opts.resolveRatio,
+	})
+
+	if sampled {
+		event.Action = domain.ActionTrigger
+		event.DedupKey = fmt.Sprintf("loadgen-%s-probe-%d", runID, seq)
+	}
+	return event
+}
+
+// postEvent sends an event to POST /v1/events.
+func postEvent(ctx context.Context, client *http.Client, baseURL string, event *domain.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/events", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// probe polls GET /v1/alerts/:dedupKey until the alert exists and records
+// the time elapsed since the event was sent.
+func (s *stats) probe(client *http.Client, opts *options, dedupKey string, sentAt time.Time) {
+	deadline := sentAt.Add(opts.probeTimeout)
+	url := opts.baseURL + "/v1/alerts/" + dedupKey
+
+	for time.Now().Before(deadline) {
+		resp, err := client.Get(url)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				s.mu.Lock()
+				s.latencies = append(s.latencies, time.Since(sentAt))
+				s.mu.Unlock()
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	s.lost.Add(1)
+}
+
+// print writes the run summary to stdout.
+func (s *stats) print(opts *options) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fmt.Printf("mode:         %s\n", opts.mode)
+	fmt.Printf("target rate:  %d events/s\n", opts.rate)
+	fmt.Printf("sent:         %d\n", s.sent.Load())
+	fmt.Printf("failed:       %d\n", s.failed.Load())
+	fmt.Printf("dropped:      %d (senders saturated)\n", s.dropped.Load())
+	fmt.Printf("elapsed:      %s\n", s.elapsed.Round(time.Millisecond))
+	fmt.Printf("throughput:   %.1f events/s\n", float64(s.sent.Load())/s.elapsed.Seconds())
+
+	sort.Slice(s.latencies, func(i, j int) bool { return s.latencies[i] < s.latencies[j] })
+	fmt.Printf("latency samples: %d (%d never appeared)\n", len(s.latencies), s.lost.Load())
+	if len(s.latencies) == 0 {
+		return
+	}
+	fmt.Printf("  p50: %s\n", percentile(s.latencies, 0.50))
+	fmt.Printf("  p90: %s\n", percentile(s.latencies, 0.90))
+	fmt.Printf("  p99: %s\n", percentile(s.latencies, 0.99))
+	fmt.Printf("  max: %s\n", s.latencies[len(s.latencies)-1])
+}
+
+// percentile returns the p-th percentile of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	idx := int(float64(len(sorted)-1) * p)
+	return sorted[idx].Round(time.Millisecond)
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/testgen"
+)
+
+func TestParseFlags(t *testing.T) {
+	opts, err := parseFlags(nil)
+	if err != nil {
+		t.Fatalf("parseFlags() error: %v", err)
+	}
+	if opts.mode != modeHTTP || opts.baseURL != "http://localhost:8080" || opts.rate != 100 || opts.duration != 30*time.Second {
+		t.Errorf("defaults = %+v, want http mode at 100 events/s for 30s against localhost", opts)
+	}
+	if opts.concurrency != 10 || opts.keyspace != 1000 || opts.classes != 10 || opts.sampleRatio != 0.01 || opts.seed != 0 {
+		t.Errorf("defaults = %+v", opts)
+	}
+
+	opts, err = parseFlags([]string{
+		"-mode", "queue",
+		"-config", "config/test.yaml",
+		"-event-manager", "em-1",
+		"-rate", "500",
+		"-duration", "2m",
+		"-keyspace", "50",
+		"-resolve-ratio", "0.25",
+		"-seed", "42",
+		"-severities", "critical,warning",
+	})
+	if err != nil {
+		t.Fatalf("parseFlags() error: %v", err)
+	}
+	want := options{
+		baseURL:        "http://localhost:8080",
+		mode:           modeQueue,
+		configPath:     "config/test.yaml",
+		eventManagerID: "em-1",
+		rate:           500,
+		duration:       2 * time.Minute,
+		concurrency:    10,
+		keyspace:       50,
+		classes:        10,
+		resolveRatio:   0.25,
+		sampleRatio:    0.01,
+		seed:           42,
+		severities:     "critical,warning",
+		probeTimeout:   30 * time.Second,
+	}
+	if *opts != want {
+		t.Errorf("parseFlags() = %+v, want %+v", *opts, want)
+	}
+
+	if _, err := parseFlags([]string{"-rate", "fast"}); err == nil {
+		t.Error("parseFlags() with a non-numeric rate should fail")
+	}
+	if _, err := parseFlags([]string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("parseFlags(-h) error = %v, want flag.ErrHelp", err)
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	scale := domain.CurrentSeverityScale()
+	t.Cleanup(func() { domain.SetSeverityScale(scale) })
+
+	valid := func() *options {
+		opts, err := parseFlags([]string{"-event-manager", "em-1"})
+		if err != nil {
+			t.Fatalf("parseFlags() error: %v", err)
+		}
+		return opts
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*options)
+		wantErr string
+	}{
+		{"defaults", func(*options) {}, ""},
+		{"queue mode", func(o *options) { o.mode = modeQueue }, ""},
+		{"no event manager", func(o *options) { o.eventManagerID = "" }, "-event-manager is required"},
+		{"unknown mode", func(o *options) { o.mode = "grpc" }, `unknown mode "grpc"`},
+		{"zero rate", func(o *options) { o.rate = 0 }, "must be positive"},
+		{"negative keyspace", func(o *options) { o.keyspace = -1 }, "must be positive"},
+		{"resolve ratio above 1", func(o *options) { o.resolveRatio = 1.5 }, "must be between 0 and 1"},
+		{"negative sample ratio", func(o *options) { o.sampleRatio = -0.1 }, "must be between 0 and 1"},
+		{"duplicate severities", func(o *options) { o.severities = "high,high" }, "invalid -severities"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := valid()
+			tt.modify(opts)
+			err := opts.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validate() error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validate() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// The severities of the target deployment become the levels of the
+	// generated events
+	opts := valid()
+	opts.severities = "critical,warning,info"
+	if err := opts.validate(); err != nil {
+		t.Fatalf("validate() error: %v", err)
+	}
+	gen := testgen.New(1)
+	for range 20 {
+		event := buildEvent(opts, gen, "run", 0, false)
+		if !slices.Contains([]domain.Severity{"critical", "warning", "info"}, event.Severity) {
+			t.Fatalf("severity = %q, want a level of -severities", event.Severity)
+		}
+	}
+}
+
+func TestBuildEvent(t *testing.T) {
+	opts := &options{eventManagerID: "em-1", keyspace: 5, classes: 2}
+
+	classes := map[string]bool{}
+	keys := map[string]bool{}
+	gen := testgen.New(7)
+	for seq := range int64(100) {
+		event := buildEvent(opts, gen, "abc", seq, false)
+		if event.EventManagerID != "em-1" || event.Action != domain.ActionTrigger {
+			t.Fatalf("event = %+v, want a trigger for em-1", event)
+		}
+		if !strings.HasPrefix(event.DedupKey, "loadgen-abc-") {
+			t.Fatalf("dedup key = %q, want the prefix of the run", event.DedupKey)
+		}
+		if err := event.Validate(); err != nil {
+			t.Fatalf("event %+v is invalid: %v", event, err)
+		}
+		keys[event.DedupKey] = true
+		classes[event.Class] = true
+	}
+	if len(keys) > opts.keyspace || len(classes) > opts.classes {
+		t.Errorf("events used %d keys and %d classes, want at most %d and %d", len(keys), len(classes), opts.keyspace, opts.classes)
+	}
+
+	// The same seed replays the same events
+	first, second := testgen.New(7), testgen.New(7)
+	for seq := range int64(10) {
+		a, b := buildEvent(opts, first, "abc", seq, false), buildEvent(opts, second, "abc", seq, false)
+		if !reflect.DeepEqual(a, b) {
+			t.Fatalf("event %d = %+v and %+v with the same seed", seq, a, b)
+		}
+	}
+
+	// Sampled events always open an alert of their own
+	opts.resolveRatio = 1
+	event := buildEvent(opts, gen, "abc", 12, true)
+	if event.Action != domain.ActionTrigger || event.DedupKey != "loadgen-abc-probe-12" {
+		t.Errorf("sampled event = %s %s, want a trigger for loadgen-abc-probe-12", event.Action, event.DedupKey)
+	}
+	if event := buildEvent(opts, gen, "abc", 13, false); event.Action != domain.ActionResolve {
+		t.Errorf("action = %s with a resolve ratio of 1, want resolve", event.Action)
+	}
+}
+
+func TestPostEvent(t *testing.T) {
+	status := http.StatusAccepted
+	var received domain.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/events" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request = %s %s (%s), want a JSON POST to /v1/events", r.Method, r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("decoding the event: %v", err)
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	event := buildEvent(&options{eventManagerID: "em-1"}, testgen.New(3), "abc", 0, true)
+	if err := postEvent(context.Background(), server.Client(), server.URL, event); err != nil {
+		t.Fatalf("postEvent() error: %v", err)
+	}
+	if !reflect.DeepEqual(&received, event) {
+		t.Errorf("received %+v, want %+v", received, *event)
+	}
+
+	status = http.StatusBadRequest
+	if err := postEvent(context.Background(), server.Client(), server.URL, event); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("postEvent() error = %v, want the unexpected status", err)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+	for _, tt := range []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0.50, 50 * time.Millisecond},
+		{0.99, 99 * time.Millisecond},
+		{1, 100 * time.Millisecond},
+	} {
+		if got := percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(%v) = %s, want %s", tt.p, got, tt.want)
+		}
+	}
+}
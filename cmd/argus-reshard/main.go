@@ -0,0 +1,63 @@
+// Package main moves the state store keys of ArgusGo between Redis shards
+// after shards are added to or removed from the configuration. Every key is
+// moved to the shard the consistent hash ring of the configuration assigns
+// it; the keys of removed instances, given with -removed, move as well.
+//
+// Pause the processors (POST /v1/admin/processor/pause) while resharding and
+// resume them once it is done.
+//
+// Usage:
+//
+//	argus-reshard [-config FILE] [-removed ADDR,...] [-dry-run]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"argus-go/internal/config"
+	redisstor "argus-go/internal/store/redis"
+)
+
+func main() {
+	configPath := flag.String("config", "config/config-storage.yaml", "ArgusGo configuration file with the new redis shards")
+	removed := flag.String("removed", "", "comma-separated addresses of removed shards whose keys move too")
+	dryRun := flag.Bool("dry-run", false, "only count the keys that would move")
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		logger.Error("failed to load config", "error", err)
+		os.Exit(1)
+	}
+
+	var removedAddrs []string
+	if *removed != "" {
+		removedAddrs = strings.Split(*removed, ",")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	result, err := redisstor.Reshard(ctx, &cfg.Redis, removedAddrs, *dryRun, logger)
+	if result != nil {
+		verb := "moved"
+		if *dryRun {
+			verb = "would move"
+		}
+		fmt.Printf("scanned %d keys, %s %d keys and %d reminder schedule entries\n",
+			result.Scanned, verb, result.Moved, result.RemindersMoved)
+	}
+	if err != nil {
+		logger.Error("resharding failed", "error", err)
+		os.Exit(1)
+	}
+}
@@ -4,27 +4,65 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"slices"
+	"sync"
 	"syscall"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"argus-go/internal/api"
+	"argus-go/internal/approval"
+	"argus-go/internal/archive"
+	"argus-go/internal/chaos"
+	"argus-go/internal/clock"
+	"argus-go/internal/clone"
 	"argus-go/internal/config"
+	"argus-go/internal/declarative"
+	"argus-go/internal/domain"
+	"argus-go/internal/erasure"
+	"argus-go/internal/eventstatus"
+	"argus-go/internal/health"
 	"argus-go/internal/ingest"
+	"argus-go/internal/logging"
+	"argus-go/internal/metrics"
 	"argus-go/internal/notification"
 	"argus-go/internal/processor"
 	"argus-go/internal/queue"
+	"argus-go/internal/queue/buffered"
 	kafkaqueue "argus-go/internal/queue/kafka"
 	memoryqueue "argus-go/internal/queue/memory"
+	mqttqueue "argus-go/internal/queue/mqtt"
+	natsqueue "argus-go/internal/queue/nats"
+	"argus-go/internal/quota"
+	"argus-go/internal/remediation"
+	"argus-go/internal/reports"
+	"argus-go/internal/rules"
+	"argus-go/internal/scaling"
+	"argus-go/internal/selfmon"
+	"argus-go/internal/silence"
+	"argus-go/internal/slo"
 	"argus-go/internal/store"
+	"argus-go/internal/store/cached"
+	"argus-go/internal/store/instrumented"
 	memorystor "argus-go/internal/store/memory"
 	postgresstor "argus-go/internal/store/postgres"
 	redisstor "argus-go/internal/store/redis"
+	"argus-go/internal/testalert"
+	"argus-go/internal/usage"
 )
 
 func main() {
+	// "argus selfcheck" probes a running instance instead of starting one
+	if len(os.Args) > 1 && os.Args[1] == "selfcheck" {
+		os.Exit(runSelfcheck(os.Args[2:]))
+	}
+
 	// Parse command line flags
 	configPath := flag.String("config", "config/config.yaml", "path to configuration file")
 	flag.Parse()
@@ -44,26 +82,154 @@ func main() {
 		"storage_mode", cfg.Storage.Mode,
 	)
 
+	// Events and event managers are validated against the configured
+	// severity levels; Load has already checked they form a scale
+	severityScale, _ := cfg.Severities.Scale()
+	domain.SetSeverityScale(severityScale)
+
+	// Notifications and UIs render severities with the configured
+	// presentation of each level
+	severityPresentations, _ := cfg.Severities.Presentations(severityScale)
+	domain.SetSeverityPresentations(severityPresentations)
+
+	// Notification messages are rendered from the configured catalog, which
+	// event manager locales are validated against
+	messageCatalog, _ := cfg.Notification.MessageCatalog()
+	domain.SetMessageCatalog(messageCatalog)
+
 	// Initialize dependencies based on storage mode
-	deps, cleanup, err := initDependencies(cfg, logger)
+	deps, err := initDependencies(cfg, logger)
 	if err != nil {
 		logger.Error("failed to initialize dependencies", "error", err)
 		os.Exit(1)
 	}
-	defer cleanup()
 
 	// Create context that listens for shutdown signals
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
-	// Start processor in background
+	// Start processor in background. It has its own context so it keeps
+	// consuming while the HTTP server and producer drain during shutdown.
+	processorCtx, stopProcessor := context.WithCancel(context.Background())
+	defer stopProcessor()
+	processorDone := make(chan struct{})
 	go func() {
-		if err := deps.processor.Start(ctx); err != nil && ctx.Err() == nil {
+		defer close(processorDone)
+		if err := deps.processor.Start(processorCtx); err != nil && processorCtx.Err() == nil {
 			logger.Error("processor error", "error", err)
 			cancel()
 		}
 	}()
 
+	// Send escalation reminders until the processor stops
+	go deps.processor.StartReminders(processorCtx, cfg.Reminders.CheckInterval)
+
+	// Keep the active alerts gauge in line with the stored alerts
+	if cfg.Processor.ActiveAlertsReconcileInterval > 0 {
+		go deps.processor.StartActiveAlertsReconciler(processorCtx, cfg.Processor.ActiveAlertsReconcileInterval)
+	}
+
+	// Resolve parents left waiting for children that have resolved
+	if cfg.Processor.PendingResolveSweepInterval > 0 {
+		go deps.processor.StartPendingResolveSweeper(processorCtx, cfg.Processor.PendingResolveSweepInterval, deps.ingest)
+	}
+
+	// Group the new alerts of event managers in an alert storm
+	if cfg.Processor.Storms.CheckInterval > 0 {
+		go deps.processor.StartStormDetection(processorCtx, cfg.Processor.Storms.CheckInterval)
+	}
+
+	// Measure the autoscaling signal until the processor stops
+	if deps.scaling != nil {
+		go deps.scaling.Start(processorCtx)
+	}
+
+	// Store the usage of event managers until the processor stops; the
+	// shutdown flushes what is counted after that
+	if deps.usage != nil {
+		go deps.usage.Start(processorCtx, cfg.Usage.FlushInterval)
+	}
+
+	// Archive queued events until the processor stops; the shutdown writes
+	// what is buffered after that
+	if deps.archive != nil {
+		go deps.archive.Start(processorCtx, cfg.Archive.FlushInterval)
+	}
+	if deps.archiveConsumer != nil {
+		go func() {
+			if err := deps.archiveConsumer.Start(processorCtx, deps.archive.Handle); err != nil && processorCtx.Err() == nil {
+				logger.Error("archive consumer error", "error", err)
+			}
+		}()
+	}
+
+	// Check the quotas of event managers until shutdown
+	if deps.quotas != nil {
+		go deps.quotas.Start(ctx, cfg.Quota.CheckInterval)
+	}
+
+	// Maintain the partitions of the alerts table until shutdown
+	if deps.retention != nil {
+		go deps.retention.Start(ctx)
+	}
+
+	// Run destructive operations whose approval delay has passed
+	go deps.approvals.Start(ctx)
+
+	// Materialize recurring silences and reload the silences in effect
+	if deps.silences != nil {
+		go deps.silences.Start(ctx)
+	}
+
+	// Resolve and delete expired test alerts until shutdown
+	if deps.testAlerts != nil {
+		go deps.testAlerts.Start(ctx)
+	}
+
+	// Email the scheduled reports due until shutdown
+	if deps.reports != nil {
+		go deps.reports.Start(ctx)
+	}
+
+	// Run the query rules due until shutdown
+	if deps.rules != nil {
+		go deps.rules.Start(ctx)
+	}
+
+	// Raise alerts about ArgusGo itself until shutdown
+	if deps.selfMonitor != nil {
+		if err := deps.selfMonitor.EnsureEventManager(ctx); err != nil {
+			logger.Error("failed to set up self-monitoring", "error", err)
+		} else {
+			go deps.selfMonitor.Start(ctx, cfg.SelfMonitoring.Interval)
+		}
+	}
+
+	// Ingest external topics until the shutdown stops them, before the
+	// producer they publish to
+	sourcesCtx, stopSources := context.WithCancel(context.Background())
+	defer stopSources()
+	var sourcesDone sync.WaitGroup
+	for _, source := range deps.sources {
+		sourcesDone.Add(1)
+		go func() {
+			defer sourcesDone.Done()
+			if err := source.Start(sourcesCtx); err != nil && sourcesCtx.Err() == nil {
+				logger.Error("source consumer error", "error", err)
+			}
+		}()
+	}
+
+	// Probe Redis and PostgreSQL until shutdown
+	go deps.health.Start(ctx)
+
+	// Keep the configuration caches consistent with other replicas
+	go func() {
+		if err := deps.listenChanges(ctx); err != nil {
+			logger.Error("config change listener error", "error", err)
+		}
+	}()
+
 	// Start HTTP server
 	go func() {
 		if err := deps.server.Start(); err != nil {
@@ -73,7 +239,7 @@ func main() {
 	}()
 
 	logger.Info("ArgusGo started",
-		"address", cfg.Server.Address(),
+		"addresses", cfg.Server.ListenAddresses(),
 		"storage_mode", cfg.Storage.Mode,
 	)
 
@@ -81,17 +247,95 @@ func main() {
 	<-ctx.Done()
 	logger.Info("shutdown signal received")
 
-	// Graceful shutdown
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.Server.WriteTimeout)
-	defer shutdownCancel()
+	// A paused processor would hold up the drain of the in-memory queue
+	deps.processor.Resume()
 
-	if err := deps.server.Shutdown(shutdownCtx); err != nil {
-		logger.Error("server shutdown error", "error", err)
+	// Graceful shutdown, in dependency order: nothing may write to a
+	// component after it has been stopped.
+	stages := []shutdownStage{
+		{
+			// Stop accepting connections and drain in-flight requests,
+			// so the ingest path no longer publishes.
+			name:    "http",
+			timeout: cfg.Shutdown.HTTPTimeout,
+			run:     deps.server.Shutdown,
+		},
+		{
+			// Stop consuming external topics, so they no longer publish
+			name:    "sources",
+			timeout: cfg.Shutdown.ProducerTimeout,
+			run: func(ctx context.Context) error {
+				stopSources()
+				done := make(chan struct{})
+				go func() {
+					sourcesDone.Wait()
+					close(done)
+				}()
+				select {
+				case <-done:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				var errs []error
+				for _, source := range deps.sources {
+					errs = append(errs, source.Close())
+				}
+				return errors.Join(errs...)
+			},
+		},
+		{
+			// Flush published events. The in-memory queue delivers its
+			// remaining messages to the processor before it closes.
+			name:    "producer",
+			timeout: cfg.Shutdown.ProducerTimeout,
+			run: func(context.Context) error {
+				return deps.producer.Close()
+			},
+		},
+		{
+			name:    "processor",
+			timeout: cfg.Shutdown.ProcessorTimeout,
+			run: func(ctx context.Context) error {
+				stopProcessor()
+				select {
+				case <-processorDone:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				return deps.processor.Stop()
+			},
+		},
+		{
+			// Write the events buffered since the last flush
+			name:    "archive",
+			timeout: cfg.Shutdown.StoreTimeout,
+			run: func(ctx context.Context) error {
+				if deps.archiveConsumer != nil {
+					_ = deps.archiveConsumer.Close()
+				}
+				return deps.archive.Flush(ctx)
+			},
+		},
+		{
+			// Store the usage counted since the last flush
+			name:    "usage",
+			timeout: cfg.Shutdown.StoreTimeout,
+			run:     deps.usage.Flush,
+		},
+		{
+			name:    "stores",
+			timeout: cfg.Shutdown.StoreTimeout,
+			run: func(context.Context) error {
+				deps.closeStores()
+				return nil
+			},
+		},
 	}
-
-	if err := deps.processor.Stop(); err != nil {
-		logger.Error("processor shutdown error", "error", err)
+	if cfg.Shutdown.MetricsSnapshot.Enabled() {
+		// Last, so the snapshot has everything the other stages counted
+		stages = append(stages, metricsSnapshotStage(cfg.Shutdown.MetricsSnapshot))
 	}
+	runShutdown(stages, logger)
 
 	logger.Info("ArgusGo stopped")
 }
@@ -100,21 +344,93 @@ func main() {
 type dependencies struct {
 	server    *api.Server
 	processor *processor.Service
+	ingest    *ingest.Service
+	producer  queue.Producer
+	health    *health.Monitor
+
+	// scaling measures the autoscaling signal; nil unless enabled.
+	scaling *scaling.Monitor
+
+	// selfMonitor raises alerts about ArgusGo itself; nil unless enabled.
+	selfMonitor *selfmon.Monitor
+
+	// usage accounts the usage of event managers; nil unless enabled.
+	usage *usage.Meter
+
+	// archive writes queued events to object storage; nil unless enabled.
+	archive *archive.Archiver
+
+	// archiveConsumer reads the queue for the archive in storage mode; nil
+	// when the archive shares the processor's consumer.
+	archiveConsumer queue.Consumer
+
+	// quotas enforces the quotas of event managers; nil unless enabled.
+	quotas *quota.Enforcer
+
+	// retention maintains the partitions of the alerts table; nil unless in
+	// storage mode with the job enabled.
+	retention *postgresstor.RetentionJob
+
+	// approvals holds destructive admin operations for approval.
+	approvals *approval.Gate
+
+	// silences materializes recurring silences; nil unless enabled.
+	silences *silence.Scheduler
+
+	// testAlerts cleans up expired test alerts; nil unless enabled.
+	testAlerts *testalert.Generator
+
+	// reports emails scheduled reports; nil unless a plugin sends them.
+	reports *reports.Scheduler
+
+	// rules runs the query rules; nil unless enabled.
+	rules *rules.Runner
+
+	// sources ingest the events of external Kafka topics.
+	sources []*ingest.SourceConsumer
+
+	// listenChanges applies configuration changes of other replicas to the
+	// caches until its context is canceled.
+	listenChanges func(ctx context.Context) error
+
+	// closeStores closes the state store and repositories.
+	closeStores func()
 }
 
 // initDependencies creates and wires all service dependencies based on config.
-// Returns the dependencies and a cleanup function.
-func initDependencies(cfg *config.Config, logger *slog.Logger) (*dependencies, func(), error) {
+func initDependencies(cfg *config.Config, logger *slog.Logger) (*dependencies, error) {
 	var (
 		stateStore       store.StateStore
 		alertRepo        store.AlertRepository
+		reportRepo       store.ReportRepository
 		eventManagerRepo store.EventManagerRepository
 		groupingRuleRepo store.GroupingRuleRepository
+		routingRuleRepo  store.RoutingRuleRepository
+		watchRepo        store.WatchRepository
+		silenceRepo      store.SilenceRepository
+		scheduleRepo     store.ReportScheduleRepository
+		queryRuleRepo    store.QueryRuleRepository
+		templateRepo     store.RuleTemplateRepository
+		runnerRegistry   store.RunnerRegistry
+		executionRepo    store.RuleExecutionRepository
+		notificationLog  store.NotificationLogRepository
+		remediationLog   store.RemediationLogRepository
+		feedbackRepo     store.AlertFeedbackRepository
+		auditLog         store.AuditLogRepository
+		usageRepo        store.UsageRepository
+		changeBus        store.ChangeBus
+		retention        *postgresstor.RetentionJob
+		expiredAlerts    erasure.ExpiredAlertPurger
 		producer         queue.Producer
 		consumer         queue.Consumer
+		queueBacklog     func() int64
+		archiveConsumer  queue.Consumer
 		cleanupFuncs     []func()
 	)
 
+	// Storage mode dependencies are probed in the background
+	monitor := health.NewMonitor(cfg.Health, logger)
+
 	if cfg.Storage.UseMemory() {
 		// Initialize in-memory implementations
 		logger.Info("initializing in-memory storage")
@@ -123,14 +439,41 @@ func initDependencies(cfg *config.Config, logger *slog.Logger) (*dependencies, f
 		stateStore = memStateStore
 		cleanupFuncs = append(cleanupFuncs, func() { _ = memStateStore.Close() })
 
-		alertRepo = memorystor.NewAlertRepository()
+		memAlertRepo := memorystor.NewAlertRepository()
+		alertRepo = memAlertRepo
+		reportRepo = memorystor.NewReportRepository(memAlertRepo)
 		eventManagerRepo = memorystor.NewEventManagerRepository()
 		groupingRuleRepo = memorystor.NewGroupingRuleRepository()
+		routingRuleRepo = memorystor.NewRoutingRuleRepository()
+		watchRepo = memorystor.NewWatchRepository()
+		silenceRepo = memorystor.NewSilenceRepository()
+		scheduleRepo = memorystor.NewReportScheduleRepository()
+		queryRuleRepo = memorystor.NewQueryRuleRepository()
+		templateRepo = memorystor.NewRuleTemplateRepository()
+		runnerRegistry = memorystor.NewRunnerRegistry()
+		executionRepo = memorystor.NewRuleExecutionRepository()
+		notificationLog = memorystor.NewNotificationLogRepository()
+		remediationLog = memorystor.NewRemediationLogRepository()
+		feedbackRepo = memorystor.NewAlertFeedbackRepository()
+		auditLog = memorystor.NewAuditLogRepository()
+		usageRepo = memorystor.NewUsageRepository()
+		changeBus = memorystor.NewChangeBus()
 
-		memQueue := memoryqueue.NewQueue(10000)
+		// Partition like the Kafka topic so ordering and concurrency match storage mode
+		var memQueue *memoryqueue.Queue
+		if dir := cfg.Storage.MemoryQueue.CheckpointDir; dir != "" {
+			var err error
+			memQueue, err = memoryqueue.NewPersistentQueue(dir, cfg.Kafka.PartitionCount, 10000, cfg.Storage.MemoryQueue.CheckpointInterval)
+			if err != nil {
+				return nil, err
+			}
+			logger.Info("memory queue checkpointing enabled", "dir", dir, "pending", memQueue.Len())
+		} else {
+			memQueue = memoryqueue.NewPartitionedQueue(cfg.Kafka.PartitionCount, 10000)
+		}
 		producer = memQueue
 		consumer = memQueue
-		cleanupFuncs = append(cleanupFuncs, func() { _ = memQueue.Close() })
+		queueBacklog = func() int64 { return int64(memQueue.Len()) }
 	} else {
 		// Initialize real storage implementations
 		logger.Info("initializing production storage (Kafka, Redis, PostgreSQL)")
@@ -139,46 +482,313 @@ func initDependencies(cfg *config.Config, logger *slog.Logger) (*dependencies, f
 		ctx := context.Background()
 		db, err := postgresstor.NewDB(ctx, &cfg.Postgres)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		cleanupFuncs = append(cleanupFuncs, db.Close)
+		monitor.Add("postgres", db)
+		if err := prometheus.Register(db); err != nil {
+			return nil, err
+		}
 
 		// Run migrations
 		if err := db.RunMigrations(ctx); err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		logger.Info("database migrations completed")
 
 		alertRepo = postgresstor.NewAlertRepository(db)
+		reportRepo = postgresstor.NewReportRepository(db)
 		eventManagerRepo = postgresstor.NewEventManagerRepository(db)
 		groupingRuleRepo = postgresstor.NewGroupingRuleRepository(db)
+		routingRuleRepo = postgresstor.NewRoutingRuleRepository(db)
+		watchRepo = postgresstor.NewWatchRepository(db)
+		silenceRepo = postgresstor.NewSilenceRepository(db)
+		scheduleRepo = postgresstor.NewReportScheduleRepository(db)
+		queryRuleRepo = postgresstor.NewQueryRuleRepository(db)
+		templateRepo = postgresstor.NewRuleTemplateRepository(db)
+		runnerRegistry = postgresstor.NewRunnerRegistry(db)
+		executionRepo = postgresstor.NewRuleExecutionRepository(db)
+		notificationLog = postgresstor.NewNotificationLogRepository(db)
+		remediationLog = postgresstor.NewRemediationLogRepository(db)
+		feedbackRepo = postgresstor.NewAlertFeedbackRepository(db)
+		auditLog = postgresstor.NewAuditLogRepository(db)
+		usageRepo = postgresstor.NewUsageRepository(db)
+		changeBus = postgresstor.NewChangeBus(db, logger)
+
+		if cfg.Retention.CheckInterval > 0 {
+			retention = postgresstor.NewRetentionJob(db, cfg.Retention, clock.Real{}, logger)
+		}
+		expiredAlerts = db
 
 		// Initialize Redis
 		redisStore, err := redisstor.NewStateStore(&cfg.Redis)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		stateStore = redisStore
 		cleanupFuncs = append(cleanupFuncs, func() { _ = redisStore.Close() })
+		monitor.Add("redis", redisStore)
 
-		// Initialize Kafka
-		kafkaProducer := kafkaqueue.NewProducer(&cfg.Kafka)
-		producer = kafkaProducer
-		cleanupFuncs = append(cleanupFuncs, func() { _ = kafkaProducer.Close() })
-
-		kafkaConsumer := kafkaqueue.NewConsumer(&cfg.Kafka, logger)
+		// Initialize Kafka, consuming the dedicated topics of event managers too
+		kafkaCfg, err := withEventManagerTopics(ctx, cfg.Kafka, eventManagerRepo)
+		if err != nil {
+			return nil, err
+		}
+		producer = kafkaqueue.NewProducer(&kafkaCfg)
+		kafkaConsumer := kafkaqueue.NewConsumer(&kafkaCfg, logger)
 		consumer = kafkaConsumer
-		cleanupFuncs = append(cleanupFuncs, func() { _ = kafkaConsumer.Close() })
+		queueBacklog = kafkaConsumer.Backlog
+
+		// The archive reads the topics in a consumer group of its own
+		if cfg.Archive.Enabled() && !cfg.Processor.Shadow {
+			archiveCfg := kafkaCfg
+			archiveCfg.ConsumerGroup = cfg.Archive.ConsumerGroup
+			archiveConsumer = kafkaqueue.NewConsumer(&archiveCfg, logger)
+		}
 	}
 
-	// Initialize notification service (stubbed for now)
-	notifier := notification.NewStubNotifier(logger)
+	// A shadow processor evaluates events against in-memory alert state of
+	// its own, so it never writes to the shared stores; the API serves that
+	// state so the alerts it would have created can be inspected
+	baseNotifier := notification.Notifier(notification.NewStubNotifier(logger))
+	var notificationQueues map[string]queue.Producer
+	var watchChannels map[domain.WatchChannelType]*notification.Plugin
+	var reportEmail *notification.Plugin
+	if cfg.Processor.Shadow {
+		logger.Warn("processor running in shadow mode: alerts and notifications are evaluated but not persisted or sent")
+
+		shadowStateStore := memorystor.NewStateStore()
+		stateStore = shadowStateStore
+		cleanupFuncs = append(cleanupFuncs, func() { _ = shadowStateStore.Close() })
+
+		shadowAlertRepo := memorystor.NewAlertRepository()
+		alertRepo = shadowAlertRepo
+		reportRepo = memorystor.NewReportRepository(shadowAlertRepo)
+		notificationLog = memorystor.NewNotificationLogRepository()
+		remediationLog = memorystor.NewRemediationLogRepository()
+		feedbackRepo = memorystor.NewAlertFeedbackRepository()
+		usageRepo = memorystor.NewUsageRepository()
+		baseNotifier = notification.NewShadowNotifier(logger)
+	} else {
+		if len(cfg.Notification.Plugins) > 0 {
+			// Notifier plugins start on their first notification
+			var plugins []*notification.Plugin
+			for _, pluginCfg := range cfg.Notification.Plugins {
+				plugins = append(plugins, notification.NewPlugin(pluginCfg, logger))
+			}
+			pluginNotifier := notification.NewPluginNotifier(baseNotifier, plugins, logger)
+			watchChannels = newWatchChannels(cfg.Notification.Watches, plugins)
+			for _, p := range plugins {
+				if p.Name() == cfg.Notification.Reports.Email {
+					reportEmail = p
+				}
+			}
+			baseNotifier = pluginNotifier
+			cleanupFuncs = append(cleanupFuncs, func() { _ = pluginNotifier.Close() })
+		}
+		if len(cfg.Notification.Queues) > 0 {
+			queues, err := newNotificationQueues(cfg.Notification.Queues)
+			if err != nil {
+				return nil, err
+			}
+			notificationQueues = queues
+			queueNotifier := notification.NewQueueNotifier(baseNotifier, queues, logger)
+			baseNotifier = queueNotifier
+			cleanupFuncs = append(cleanupFuncs, func() { _ = queueNotifier.Close() })
+		}
+	}
+
+	// Bound store operations and record storage metrics, beneath any fault
+	// injection so they measure the stores themselves
+	ops := cfg.Storage.Operations
+	stateStore = instrumented.NewStateStore(stateStore, ops, logger)
+	alertRepo = instrumented.NewAlertRepository(alertRepo, ops, logger)
+	reportRepo = instrumented.NewReportRepository(reportRepo, ops, logger)
+	eventManagerRepo = instrumented.NewEventManagerRepository(eventManagerRepo, ops, logger)
+	groupingRuleRepo = instrumented.NewGroupingRuleRepository(groupingRuleRepo, ops, logger)
+	routingRuleRepo = instrumented.NewRoutingRuleRepository(routingRuleRepo, ops, logger)
+	watchRepo = instrumented.NewWatchRepository(watchRepo, ops, logger)
+	silenceRepo = instrumented.NewSilenceRepository(silenceRepo, ops, logger)
+	scheduleRepo = instrumented.NewReportScheduleRepository(scheduleRepo, ops, logger)
+	queryRuleRepo = instrumented.NewQueryRuleRepository(queryRuleRepo, ops, logger)
+	templateRepo = instrumented.NewRuleTemplateRepository(templateRepo, ops, logger)
+	runnerRegistry = instrumented.NewRunnerRegistry(runnerRegistry, ops, logger)
+	executionRepo = instrumented.NewRuleExecutionRepository(executionRepo, ops, logger)
+	notificationLog = instrumented.NewNotificationLogRepository(notificationLog, ops, logger)
+	remediationLog = instrumented.NewRemediationLogRepository(remediationLog, ops, logger)
+	feedbackRepo = instrumented.NewAlertFeedbackRepository(feedbackRepo, ops, logger)
+	auditLog = instrumented.NewAuditLogRepository(auditLog, ops, logger)
+	usageRepo = instrumented.NewUsageRepository(usageRepo, ops, logger)
+
+	// Wrap stores and queue with fault injection (chaos builds only)
+	var chaosHandler *api.ChaosHandler
+	if chaos.Enabled && cfg.Chaos.Enabled {
+		injector, err := chaos.NewInjector(&cfg.Chaos)
+		if err != nil {
+			return nil, err
+		}
+		logger.Warn("fault injection enabled")
+
+		stateStore = chaos.NewStateStore(stateStore, injector)
+		alertRepo = chaos.NewAlertRepository(alertRepo, injector)
+		eventManagerRepo = chaos.NewEventManagerRepository(eventManagerRepo, injector)
+		groupingRuleRepo = chaos.NewGroupingRuleRepository(groupingRuleRepo, injector)
+		routingRuleRepo = chaos.NewRoutingRuleRepository(routingRuleRepo, injector)
+		watchRepo = chaos.NewWatchRepository(watchRepo, injector)
+		silenceRepo = chaos.NewSilenceRepository(silenceRepo, injector)
+		scheduleRepo = chaos.NewReportScheduleRepository(scheduleRepo, injector)
+		queryRuleRepo = chaos.NewQueryRuleRepository(queryRuleRepo, injector)
+		templateRepo = chaos.NewRuleTemplateRepository(templateRepo, injector)
+		runnerRegistry = chaos.NewRunnerRegistry(runnerRegistry, injector)
+		executionRepo = chaos.NewRuleExecutionRepository(executionRepo, injector)
+		notificationLog = chaos.NewNotificationLogRepository(notificationLog, injector)
+		remediationLog = chaos.NewRemediationLogRepository(remediationLog, injector)
+		feedbackRepo = chaos.NewAlertFeedbackRepository(feedbackRepo, injector)
+		auditLog = chaos.NewAuditLogRepository(auditLog, injector)
+		usageRepo = chaos.NewUsageRepository(usageRepo, injector)
+		producer = chaos.NewProducer(producer, injector)
+		consumer = chaos.NewConsumer(consumer, injector)
+		chaosHandler = api.NewChaosHandler(injector, logger)
+	} else if cfg.Chaos.Enabled {
+		logger.Warn("chaos.enabled is set but this binary was built without the chaos tag; ignoring")
+	}
+
+	// Archive every queued event, unless disabled; the events of a shadow
+	// processor are archived by the processor it shadows. The in-memory queue
+	// delivers each event once, so its archive shares the processor's consumer
+	var archiver *archive.Archiver
+	if cfg.Archive.Enabled() && !cfg.Processor.Shadow {
+		bucket, err := archive.NewBucket(cfg.Archive, clock.Real{})
+		if err != nil {
+			return nil, err
+		}
+		archiver = archive.NewArchiver(bucket, cfg.Archive, clock.Real{}, logger)
+		if archiveConsumer == nil {
+			consumer = archive.NewConsumer(consumer, archiver)
+		}
+	}
+
+	// Publish ingested events in the background, so ingest doesn't wait
+	// for a slow queue
+	if cfg.Ingest.Async.Enabled {
+		producer = buffered.NewProducer(producer, cfg.Ingest.Async.BufferSize, logger)
+	}
+
+	// Cache event manager and grouping rule lookups, invalidated across
+	// replicas through the change bus
+	var caches []cached.Invalidator
+	if cfg.Cache.TTL > 0 {
+		cachedEventManagers := cached.NewEventManagerRepository(eventManagerRepo, changeBus, cfg.Cache.TTL, logger)
+		cachedGroupingRules := cached.NewGroupingRuleRepository(groupingRuleRepo, changeBus, cfg.Cache.TTL, logger)
+		eventManagerRepo = cachedEventManagers
+		groupingRuleRepo = cachedGroupingRules
+		caches = append(caches, cachedEventManagers, cachedGroupingRules)
+	}
+
+	// Cache the alerts dashboards poll, invalidated by the processor's writes
+	cachedAlerts := cached.NewAlertRepository(alertRepo, cfg.Cache.Alerts.Get, cfg.Cache.Alerts.Children)
+	alertRepo = cachedAlerts
+
+	// Account the usage of each event manager, unless disabled
+	var meter *usage.Meter
+	if cfg.Usage.FlushInterval > 0 {
+		meter = usage.NewMeter(usageRepo, clock.Real{}, logger)
+	}
+
+	// Enforce the quotas of event managers, unless disabled
+	var quotas *quota.Enforcer
+	if cfg.Quota.CheckInterval > 0 {
+		quotas = quota.NewEnforcer(eventManagerRepo, usageRepo, alertRepo, clock.Real{}, logger)
+	}
+
+	// Mask secrets and personal data in alert text, before events are queued
+	// or only before notifications leave
+	scrubber, err := domain.NewScrubber(cfg.Scrubbing.Rules, func(rule string) {
+		metrics.ScrubbedValues.WithLabelValues(rule).Inc()
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ingestScrubber, notificationScrubber *domain.Scrubber
+	if cfg.Scrubbing.Stage == config.ScrubStageNotification {
+		notificationScrubber = scrubber
+	} else {
+		ingestScrubber = scrubber
+	}
+	if notificationScrubber != nil {
+		baseNotifier = notification.NewScrubNotifier(baseNotifier, notificationScrubber)
+	}
+
+	// Initialize notification service (stubbed for now), recording what is sent
+	notifier := notification.Notifier(notification.NewRecordingNotifier(baseNotifier, notificationLog, logger))
+	if meter != nil {
+		notifier = notification.NewUsageNotifier(notifier, meter)
+	}
+	if quotas != nil {
+		notifier = notification.NewQuotaNotifier(notifier, quotas, logger)
+	}
+
+	// Mute the notifications of silenced alerts, unless silences are disabled
+	var silences *silence.Scheduler
+	if cfg.Silences.CheckInterval > 0 {
+		silences = silence.NewScheduler(silenceRepo, cfg.Silences.CheckInterval, clock.Real{}, logger)
+		notifier = notification.NewSilenceNotifier(notifier, silences, logger)
+	}
+
+	// List the alerts whose notifications are silenced or suppressed as such
+	muter := notification.NewMuter(silences, quotas)
+
+	// Send each notification once, however often its change is processed
+	if cfg.Notification.Dedup.Window > 0 {
+		notifier = notification.NewDedupNotifier(notifier, stateStore, cfg.Notification.Dedup, clock.Real{}, logger)
+	}
+
+	// Run the remediation actions of event managers, sharing the notification
+	// queues; a shadow processor must not act on the alerts it evaluates
+	var remediator *remediation.Executor
+	if !cfg.Processor.Shadow {
+		remediator = remediation.NewExecutor(notificationQueues, remediationLog, cfg.Remediation.Timeout, clock.Real{}, logger)
+	}
+
+	// Send the personal notifications of alert watches through the plugins
+	// of their channels; a shadow processor sends none
+	var watchers *notification.WatchNotifier
+	if len(watchChannels) > 0 {
+		watchers = notification.NewWatchNotifier(watchRepo, watchChannels, notificationScrubber, logger)
+	}
+
+	// Email scheduled reports through their plugin; a shadow processor
+	// sends none
+	var reportScheduler *reports.Scheduler
+	if reportEmail != nil && cfg.Notification.Reports.CheckInterval > 0 {
+		reportScheduler = reports.NewScheduler(scheduleRepo, reportRepo, reportEmail, notificationScrubber, cfg.Notification.Reports.CheckInterval, clock.Real{}, logger)
+	}
+
+	// Initialize SLO tracking and export its burn rates
+	sloTracker, err := slo.NewTracker(&cfg.SLO)
+	if err != nil {
+		return nil, err
+	}
+	if err := prometheus.Register(sloTracker); err != nil {
+		return nil, err
+	}
+
+	// Track the status of accepted events, unless disabled
+	var eventTracker *eventstatus.Tracker
+	if cfg.Ingest.EventStatusTTL > 0 {
+		eventTracker = eventstatus.NewTracker(stateStore, cfg.Ingest.EventStatusTTL, clock.Real{}, logger)
+	}
 
 	// Initialize ingest service
+	groupingDefaults := ingest.NewGroupingDefaults(cfg.Grouping.DefaultRuleID)
 	ingestService := ingest.NewService(
 		producer,
 		eventManagerRepo,
 		groupingRuleRepo,
+		groupingDefaults,
+		quotas,
+		ingestScrubber,
+		eventTracker,
 		logger,
 	)
 
@@ -190,14 +800,108 @@ func initDependencies(cfg *config.Config, logger *slog.Logger) (*dependencies, f
 		eventManagerRepo,
 		groupingRuleRepo,
 		notifier,
+		remediator,
+		watchers,
+		muter,
+		sloTracker,
+		meter,
+		eventTracker,
+		cfg.Dedup,
+		cfg.Processor,
+		clock.Real{},
 		logger,
 	)
 
+	// Initialize self-monitoring, which reports through the ingest path
+	var selfMonitor *selfmon.Monitor
+	if cfg.SelfMonitoring.Enabled {
+		selfMonitor = selfmon.New(cfg.SelfMonitoring, ingestService, eventManagerRepo, selfmon.Sources{
+			ProcessorLag:         processorService.Lag,
+			PoisonMessages:       processorService.PoisonMessages,
+			NotificationFailures: notification.Failures,
+			QuotaWarnings:        quotas.Warnings,
+			ChannelDeliveries:    notification.Deliveries,
+		}, logger)
+	}
+
+	// Derive the autoscaling signal of the processor, unless disabled
+	var scalingMonitor *scaling.Monitor
+	if cfg.Processor.Scaling.Interval > 0 {
+		scalingMonitor = scaling.New(cfg.Processor.Scaling, scaling.Sources{
+			Backlog:   queueBacklog,
+			Lag:       processorService.Lag,
+			Processed: processorService.Processed,
+			Paused:    func() bool { return processorService.Status().Paused },
+		}, clock.Real{}, logger)
+	}
+
+	// Initialize the runner of query rules, which reports through the ingest
+	// path; a shadow processor must not raise alerts of its own
+	var ruleRunner *rules.Runner
+	if cfg.Rules.CheckInterval > 0 && !cfg.Processor.Shadow {
+		datasources, err := rules.NewDatasources(cfg.Rules.Datasources, cfg.Rules.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		ruleRunner = rules.NewRunner(queryRuleRepo, executionRepo, runnerRegistry, alertRepo, ingestService, datasources, cfg.Rules, clock.Real{}, logger)
+	}
+
+	// Initialize the consumers of external topics, which ingest like the API
+	router := ingest.NewRouter(routingRuleRepo, logger)
+	var sources []*ingest.SourceConsumer
+	for _, sourceCfg := range cfg.SourceConsumers {
+		var sourceConsumer queue.Consumer
+		if sourceCfg.Type == config.SourceTypeMQTT {
+			sourceConsumer = mqttqueue.NewConsumer(&sourceCfg.MQTT, logger)
+		} else {
+			sourceConsumer = kafkaqueue.NewConsumer(&config.KafkaConfig{
+				Brokers:       sourceCfg.Brokers,
+				Topic:         sourceCfg.Topic,
+				ConsumerGroup: sourceCfg.ConsumerGroup,
+				Commit:        cfg.Kafka.Commit,
+			}, logger)
+		}
+		sources = append(sources, ingest.NewSourceConsumer(sourceCfg, sourceConsumer, ingestService, router, logger))
+	}
+
+	// Hold destructive admin operations for approval, if configured
+	approvals := approval.NewGate(cfg.Approvals, auditLog, clock.Real{}, logger)
+
+	// Delete the data of event managers on request, wherever it is stored
+	deleter := erasure.NewDeleter(processorService, expiredAlerts, notificationLog, remediationLog, feedbackRepo, usageRepo, archiver, clock.Real{}, logger)
+
 	// Initialize API handlers
-	eventManagerHandler := api.NewEventManagerHandler(eventManagerRepo, logger)
-	groupingRuleHandler := api.NewGroupingRuleHandler(groupingRuleRepo, logger)
-	alertHandler := api.NewAlertHandler(alertRepo, logger)
-	ingestHandler := api.NewIngestHandler(ingestService, logger)
+	// Raise test alerts on request, unless their cleanup is disabled
+	var testAlerts *testalert.Generator
+	if cfg.TestAlerts.CheckInterval > 0 {
+		testAlerts = testalert.NewGenerator(ingestService, alertRepo, processorService, cfg.TestAlerts, clock.Real{}, logger)
+	}
+
+	// Copy event managers with their grouping rules, silences and routing rules
+	cloner := clone.NewCloner(eventManagerRepo, groupingRuleRepo, routingRuleRepo, silenceRepo, silences, clock.Real{}, logger)
+
+	eventManagerHandler := api.NewEventManagerHandler(eventManagerRepo, groupingRuleRepo, processorService, notification.NewTester(logger), testAlerts, cloner, approvals, logger)
+	groupingRuleHandler := api.NewGroupingRuleHandler(groupingRuleRepo, eventManagerRepo, groupingDefaults, stateStore, approvals, logger)
+	alertHandler := api.NewAlertHandler(alertRepo, cachedAlerts, stateStore, notificationLog, remediationLog, processorService, ingestService, approvals, muter, clock.Real{}, logger)
+	ingestHandler := api.NewIngestHandler(ingestService, router, stateStore, eventTracker, logger)
+	reportHandler := api.NewReportHandler(reportRepo, logger)
+	scheduleHandler := api.NewReportScheduleHandler(scheduleRepo, eventManagerRepo, reportScheduler, logger)
+	usageHandler := api.NewUsageHandler(usageRepo, eventManagerRepo, quotas, logger)
+	sloHandler := api.NewSLOHandler(sloTracker, logger)
+	configHandler := api.NewConfigHandler(declarative.NewService(eventManagerRepo, groupingRuleRepo, logger), logger)
+	routingRuleHandler := api.NewRoutingRuleHandler(routingRuleRepo, eventManagerRepo, logger)
+	processorHandler := api.NewProcessorHandler(processorService, scalingMonitor, logger)
+	approvalHandler := api.NewApprovalHandler(approvals, auditLog, logger)
+	dataHandler := api.NewDataHandler(deleter, approvals, logger)
+	watchHandler := api.NewWatchHandler(watchRepo, watchers, logger)
+	feedbackHandler := api.NewFeedbackHandler(feedbackRepo, alertRepo, eventManagerRepo, clock.Real{}, logger)
+	silenceHandler := api.NewSilenceHandler(silenceRepo, silences, clock.Real{}, logger)
+	queryRuleHandler := api.NewQueryRuleHandler(queryRuleRepo, executionRepo, eventManagerRepo, cfg.Rules.Datasources, clock.Real{}, logger)
+	ruleTemplateHandler := api.NewRuleTemplateHandler(templateRepo, queryRuleHandler, logger)
+	graphQLHandler, err := api.NewGraphQLHandler(alertRepo, eventManagerRepo, groupingRuleRepo, logger)
+	if err != nil {
+		return nil, fmt.Errorf("graphql schema: %w", err)
+	}
 
 	// Initialize HTTP server
 	server := api.NewServer(api.ServerDeps{
@@ -207,19 +911,113 @@ func initDependencies(cfg *config.Config, logger *slog.Logger) (*dependencies, f
 		GroupingRuleHandler: groupingRuleHandler,
 		AlertHandler:        alertHandler,
 		IngestHandler:       ingestHandler,
+		ReportHandler:       reportHandler,
+		ScheduleHandler:     scheduleHandler,
+		UsageHandler:        usageHandler,
+		SLOHandler:          sloHandler,
+		ConfigHandler:       configHandler,
+		RoutingRuleHandler:  routingRuleHandler,
+		ProcessorHandler:    processorHandler,
+		ApprovalHandler:     approvalHandler,
+		DataHandler:         dataHandler,
+		WatchHandler:        watchHandler,
+		FeedbackHandler:     feedbackHandler,
+		SilenceHandler:      silenceHandler,
+		QueryRuleHandler:    queryRuleHandler,
+		RuleTemplateHandler: ruleTemplateHandler,
+		GraphQLHandler:      graphQLHandler,
+		ChaosHandler:        chaosHandler,
+		Health:              monitor,
 	})
 
-	// Build cleanup function
-	cleanup := func() {
+	// The queue is closed by the shutdown sequence, so only stores are cleaned up here
+	closeStores := func() {
 		for i := len(cleanupFuncs) - 1; i >= 0; i-- {
 			cleanupFuncs[i]()
 		}
 	}
 
 	return &dependencies{
-		server:    server,
-		processor: processorService,
-	}, cleanup, nil
+		server:          server,
+		processor:       processorService,
+		ingest:          ingestService,
+		producer:        producer,
+		health:          monitor,
+		scaling:         scalingMonitor,
+		selfMonitor:     selfMonitor,
+		usage:           meter,
+		archive:         archiver,
+		archiveConsumer: archiveConsumer,
+		quotas:          quotas,
+		retention:       retention,
+		approvals:       approvals,
+		silences:        silences,
+		testAlerts:      testAlerts,
+		reports:         reportScheduler,
+		rules:           ruleRunner,
+		sources:         sources,
+		closeStores:     closeStores,
+		listenChanges: func(ctx context.Context) error {
+			return cached.Listen(ctx, changeBus, caches...)
+		},
+	}, nil
+}
+
+// newWatchChannels maps each watch channel with a configured plugin to the
+// plugin.
+func newWatchChannels(cfg config.WatchChannelsConfig, plugins []*notification.Plugin) map[domain.WatchChannelType]*notification.Plugin {
+	names := map[domain.WatchChannelType]string{
+		domain.WatchChannelEmail: cfg.Email,
+		domain.WatchChannelSlack: cfg.Slack,
+	}
+	channels := make(map[domain.WatchChannelType]*notification.Plugin)
+	for _, p := range plugins {
+		for channel, name := range names {
+			if name == p.Name() {
+				channels[channel] = p
+			}
+		}
+	}
+	return channels
+}
+
+// newNotificationQueues creates a producer for each notification queue, by
+// name. NATS queues connect right away, so a bad URL fails startup.
+func newNotificationQueues(cfgs []config.NotificationQueueConfig) (map[string]queue.Producer, error) {
+	queues := make(map[string]queue.Producer, len(cfgs))
+	for _, q := range cfgs {
+		switch q.Type {
+		case config.NotificationQueueNATS:
+			producer, err := natsqueue.NewProducer(q.URL, q.Subject)
+			if err != nil {
+				for _, p := range queues {
+					_ = p.Close()
+				}
+				return nil, fmt.Errorf("notification queue %s: %w", q.Name, err)
+			}
+			queues[q.Name] = producer
+		default:
+			queues[q.Name] = kafkaqueue.NewProducer(&config.KafkaConfig{Brokers: q.Brokers, Topic: q.Topic})
+		}
+	}
+	return queues, nil
+}
+
+// withEventManagerTopics returns the Kafka config with the dedicated topics
+// of the event managers in repo added to its topics. Topics set later are
+// consumed after a restart; their events wait in the topic meanwhile.
+func withEventManagerTopics(ctx context.Context, cfg config.KafkaConfig, repo store.EventManagerRepository) (config.KafkaConfig, error) {
+	eventManagers, err := repo.List(ctx)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to list event managers: %w", err)
+	}
+	cfg.Topics = slices.Clone(cfg.Topics)
+	for _, em := range eventManagers {
+		if em.Topic != "" {
+			cfg.Topics = append(cfg.Topics, em.Topic)
+		}
+	}
+	return cfg, nil
 }
 
 // initLogger creates and configures the application logger.
@@ -228,7 +1026,8 @@ func initLogger() *slog.Logger {
 		Level: slog.LevelDebug,
 	}
 
-	handler := slog.NewJSONHandler(os.Stdout, opts)
+	// Records logged with a context carry its correlation ID
+	handler := logging.NewHandler(slog.NewJSONHandler(os.Stdout, opts))
 	logger := slog.New(handler)
 	slog.SetDefault(logger)
 
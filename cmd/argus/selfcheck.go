@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"argus-go/internal/config"
+	"argus-go/internal/processor"
+)
+
+// runSelfcheck probes the ArgusGo running next to it, e.g. in its container:
+// /healthz must answer and the processor must run without being stalled.
+// Degraded dependencies are not a failure, since restarting ArgusGo doesn't
+// fix them. It returns the exit code, so the binary serves as a Docker
+// HEALTHCHECK without curl in the image.
+func runSelfcheck(args []string) int {
+	flags := flag.NewFlagSet("selfcheck", flag.ContinueOnError)
+	configPath := flags.String("config", "config/config.yaml", "path to configuration file")
+	address := flags.String("address", "", "address to probe (default: the first admin or listen address of the configuration)")
+	timeout := flags.Duration("timeout", 3*time.Second, "timeout of the whole check")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *address == "" {
+		cfg, err := config.Load(*configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "selfcheck: %v\n", err)
+			return 1
+		}
+		*address = selfcheckAddress(&cfg.Server)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	if err := selfcheck(ctx, *address); err != nil {
+		fmt.Fprintf(os.Stderr, "selfcheck: %v\n", err)
+		return 1
+	}
+	fmt.Println("ok")
+	return 0
+}
+
+// selfcheckAddress returns the address to probe: the first admin address,
+// which answers health checks when admin listeners are configured, or else
+// the first listen address.
+func selfcheckAddress(cfg *config.ServerConfig) string {
+	if len(cfg.AdminAddresses) > 0 {
+		return cfg.AdminAddresses[0]
+	}
+	return cfg.ListenAddresses()[0]
+}
+
+// selfcheck checks /healthz answers and /readyz reports a healthy processor.
+func selfcheck(ctx context.Context, address string) error {
+	client, baseURL := selfcheckClient(address)
+
+	resp, err := selfcheckGet(ctx, client, baseURL+"/healthz")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("/healthz returned %d", resp.StatusCode)
+	}
+
+	// /readyz reports the processor both when ready and when degraded
+	resp, err = selfcheckGet(ctx, client, baseURL+"/readyz")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Data struct {
+			Processor *processor.Status `json:"processor"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Data.Processor == nil {
+		return fmt.Errorf("/readyz returned %d without a processor status", resp.StatusCode)
+	}
+	switch status := body.Data.Processor; {
+	case !status.Running:
+		return errors.New("processor is not running")
+	case status.Stalled:
+		return errors.New("processor is stalled")
+	}
+	return nil
+}
+
+// selfcheckClient returns an HTTP client and the base URL reaching a listen
+// address. Unspecified hosts are reached on the loopback interface, and Unix
+// sockets through a dialer.
+func selfcheckClient(address string) (*http.Client, string) {
+	if path, ok := strings.CutPrefix(address, config.UnixAddressPrefix); ok {
+		var dialer net.Dialer
+		return &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", path)
+			},
+		}}, "http://argus"
+	}
+
+	host, port, _ := net.SplitHostPort(address)
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+	return &http.Client{}, "http://" + net.JoinHostPort(host, port)
+}
+
+// selfcheckGet sends a GET request with the context of the check.
+func selfcheckGet(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"argus-go/internal/config"
+	"argus-go/internal/metrics"
+)
+
+// shutdownStage is one step of the ordered shutdown.
+type shutdownStage struct {
+	name    string
+	timeout time.Duration
+	run     func(ctx context.Context) error
+}
+
+// runShutdown runs the stages in order, each bounded by its own timeout.
+// A stage that fails or times out is logged and shutdown moves on to the next
+// one, so a stuck component cannot keep the stores from being closed.
+func runShutdown(stages []shutdownStage, logger *slog.Logger) {
+	for _, stage := range stages {
+		start := time.Now()
+		logger.Info("shutdown stage started", "stage", stage.name, "timeout", stage.timeout)
+
+		ctx, cancel := context.WithTimeout(context.Background(), stage.timeout)
+		done := make(chan error, 1)
+		go func() { done <- stage.run(ctx) }()
+
+		select {
+		case err := <-done:
+			if err != nil {
+				logger.Error("shutdown stage failed", "stage", stage.name, "duration", time.Since(start), "error", err)
+			} else {
+				logger.Info("shutdown stage completed", "stage", stage.name, "duration", time.Since(start))
+			}
+		case <-ctx.Done():
+			logger.Warn("shutdown stage timed out", "stage", stage.name, "timeout", stage.timeout)
+		}
+		cancel()
+	}
+}
+
+// metricsSnapshotStage returns the stage writing the final metrics to a file
+// and pushing them to a Pushgateway, as configured. Pushed metrics are
+// grouped by the host name, so replicas don't replace each other's.
+func metricsSnapshotStage(cfg config.MetricsSnapshotConfig) shutdownStage {
+	return shutdownStage{
+		name:    "metrics",
+		timeout: cfg.Timeout,
+		run: func(ctx context.Context) error {
+			var errs []error
+			if cfg.File != "" {
+				errs = append(errs, metrics.WriteSnapshot(cfg.File, prometheus.DefaultGatherer))
+			}
+			if cfg.PushgatewayURL != "" {
+				instance, _ := os.Hostname()
+				errs = append(errs, metrics.PushSnapshot(ctx, cfg.PushgatewayURL, cfg.Job, instance, prometheus.DefaultGatherer))
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"argus-go/internal/config"
+)
+
+func TestRunShutdown(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	// Stages run in goroutines; a stage that times out is left running
+	var mu sync.Mutex
+	var ran []string
+	stage := func(name string, timeout time.Duration, run func(ctx context.Context) error) shutdownStage {
+		return shutdownStage{name: name, timeout: timeout, run: func(ctx context.Context) error {
+			mu.Lock()
+			ran = append(ran, name)
+			mu.Unlock()
+			return run(ctx)
+		}}
+	}
+	stuck := make(chan struct{})
+	defer close(stuck)
+
+	start := time.Now()
+	runShutdown([]shutdownStage{
+		stage("http", time.Second, func(ctx context.Context) error { return nil }),
+		// A failed stage doesn't stop the later ones
+		stage("producer", time.Second, func(ctx context.Context) error { return errors.New("flush failed") }),
+		// Nor does one stuck past its timeout
+		stage("processor", 20*time.Millisecond, func(ctx context.Context) error {
+			<-stuck
+			return nil
+		}),
+		// Each stage gets its own timeout
+		stage("stores", time.Second, func(ctx context.Context) error {
+			if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) < 500*time.Millisecond {
+				return errors.New("stage started without its own timeout")
+			}
+			return nil
+		}),
+	}, logger)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"http", "producer", "processor", "stores"}; !slices.Equal(ran, want) {
+		t.Errorf("stages run = %v, want %v", ran, want)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("shutdown took %v, want the stuck stage cut off at its timeout", elapsed)
+	}
+
+	out := logs.String()
+	for _, want := range []string{
+		`"shutdown stage completed" stage=http`,
+		`"shutdown stage failed" stage=producer`,
+		`"shutdown stage timed out" stage=processor`,
+		`"shutdown stage completed" stage=stores`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("logs missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestMetricsSnapshotStage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "argus.prom")
+	stage := metricsSnapshotStage(config.MetricsSnapshotConfig{File: path, Timeout: time.Second})
+	if stage.timeout != time.Second {
+		t.Errorf("timeout = %v, want 1s", stage.timeout)
+	}
+
+	if err := stage.run(context.Background()); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+	if !strings.Contains(string(data), "go_goroutines") {
+		t.Errorf("snapshot lacks the default metrics:\n%.200s", data)
+	}
+}
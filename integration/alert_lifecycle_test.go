@@ -10,6 +10,8 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
 	"argus-go/internal/domain"
 	"argus-go/internal/notification"
 	"argus-go/internal/processor"
@@ -58,6 +60,15 @@ var _ = Describe("Alert Lifecycle", func() {
 			eventManagerRepo,
 			groupingRuleRepo,
 			notifier,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			config.DedupConfig{},
+			config.ProcessorConfig{},
+			clock.Real{},
 			logger,
 		)
 
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// protectAdmin requires an admin token on the routes of admin, if any are
+// configured. Without admin tokens the admin API is open, which is only safe
+// on admin listeners of its own, so serving it with the rest of the API is
+// warned about.
+func (s *Server) protectAdmin(admin fiber.Router) {
+	if len(s.config.AdminTokens) > 0 {
+		admin.Use(adminAuth(s.config.AdminTokens))
+		return
+	}
+	if len(s.config.AdminAddresses) == 0 {
+		s.logger.Warn("the admin API is unauthenticated and served on the API addresses; set server.admin_tokens or server.admin_addresses")
+	}
+}
+
+// adminAuth returns middleware admitting only requests that carry one of
+// the admin tokens as a bearer token. The admin API has credentials of its
+// own, so ingest tokens can never perform admin operations.
+func adminAuth(tokens []string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if ok && isAdminToken(tokens, token) {
+			return c.Next()
+		}
+		c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="argus-admin"`)
+		return Unauthorized(c, "a valid admin token is required")
+	}
+}
+
+// isAdminToken reports whether token is one of the admin tokens, comparing
+// every token in constant time.
+func isAdminToken(tokens []string, token string) bool {
+	match := 0
+	for _, t := range tokens {
+		match |= subtle.ConstantTimeCompare([]byte(t), []byte(token))
+	}
+	return match == 1
+}
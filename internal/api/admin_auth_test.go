@@ -0,0 +1,124 @@
+package api
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"argus-go/internal/config"
+)
+
+const (
+	testAdminToken      = "admin-token-0123456789"
+	testOtherAdminToken = "admin-token-9876543210"
+)
+
+// newAdminTestApp returns an app serving GET /v1/admin/ping, protected like
+// the admin API of a server with cfg, and the log of the server.
+func newAdminTestApp(cfg *config.ServerConfig) (*fiber.App, *bytes.Buffer) {
+	var logs bytes.Buffer
+	s := &Server{config: cfg, logger: slog.New(slog.NewTextHandler(&logs, nil))}
+
+	app := fiber.New()
+	admin := app.Group("/v1/admin")
+	s.protectAdmin(admin)
+	admin.Get("/ping", func(c *fiber.Ctx) error { return c.SendString("pong") })
+	return app, &logs
+}
+
+// adminRequest sends GET /v1/admin/ping with the Authorization header, if
+// any, and returns the response.
+func adminRequest(t *testing.T, app *fiber.App, authorization string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/v1/admin/ping", nil)
+	if authorization != "" {
+		req.Header.Set(fiber.HeaderAuthorization, authorization)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	return resp
+}
+
+func TestAdminAuth(t *testing.T) {
+	app, _ := newAdminTestApp(&config.ServerConfig{AdminTokens: []string{testAdminToken, testOtherAdminToken}})
+
+	tests := []struct {
+		name          string
+		authorization string
+		want          int
+	}{
+		{"missing token", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer wrong-token-0123456789", http.StatusUnauthorized},
+		{"token prefix", "Bearer " + testAdminToken[:10], http.StatusUnauthorized},
+		{"not a bearer token", "Basic " + testAdminToken, http.StatusUnauthorized},
+		{"correct token", "Bearer " + testAdminToken, http.StatusOK},
+		{"another correct token", "Bearer " + testOtherAdminToken, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := adminRequest(t, app, tt.authorization)
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+			challenge := resp.Header.Get(fiber.HeaderWWWAuthenticate)
+			if (tt.want == http.StatusUnauthorized) != (challenge != "") {
+				t.Errorf("WWW-Authenticate = %q with status %d", challenge, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAdminAuth_NoTokens(t *testing.T) {
+	const warning = "the admin API is unauthenticated"
+
+	// Served with the rest of the API, the open admin API is warned about
+	app, logs := newAdminTestApp(&config.ServerConfig{})
+	if resp := adminRequest(t, app, ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if !strings.Contains(logs.String(), warning) {
+		t.Errorf("log = %q, want a warning about the open admin API", logs.String())
+	}
+
+	// On admin addresses of its own, it is not
+	app, logs = newAdminTestApp(&config.ServerConfig{AdminAddresses: []string{"127.0.0.1:9090"}})
+	if resp := adminRequest(t, app, ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if strings.Contains(logs.String(), warning) {
+		t.Errorf("log = %q, want no warning with admin addresses", logs.String())
+	}
+}
+
+func TestServer_ConfigApplyRequiresAdminToken(t *testing.T) {
+	s := NewServer(ServerDeps{
+		Config: &config.ServerConfig{AdminTokens: []string{testAdminToken}},
+		Logger: slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)),
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/v1/admin/config", strings.NewReader("version: 1\n"))
+	resp, err := s.app.Test(req)
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("PUT /v1/admin/config without token status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+
+	// The configuration is no longer applied outside the admin API
+	req = httptest.NewRequest(http.MethodPut, "/v1/config/export", strings.NewReader("version: 1\n"))
+	resp, err = s.app.Test(req)
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	if resp.StatusCode != http.StatusMethodNotAllowed && resp.StatusCode != http.StatusNotFound {
+		t.Errorf("PUT /v1/config/export status = %d, want it not routed", resp.StatusCode)
+	}
+}
@@ -1,37 +1,191 @@
 package api
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 
+	"argus-go/internal/approval"
+	"argus-go/internal/clock"
 	"argus-go/internal/domain"
+	"argus-go/internal/ingest"
+	"argus-go/internal/notification"
+	"argus-go/internal/processor"
 	"argus-go/internal/store"
 )
 
 // AlertHandler handles HTTP requests for alert operations.
-// For MVP, alerts are read-only through the API.
+// Alerts are created by the processor; the API reads them and has the
+// processor acknowledge alerts and force-resolve alert groups.
 type AlertHandler struct {
-	repo   store.AlertRepository
-	logger *slog.Logger
+	repo            store.AlertRepository
+	cache           AlertCache
+	stateStore      store.StateStore
+	notificationLog store.NotificationLogRepository
+	remediationLog  store.RemediationLogRepository
+	processor       *processor.Service
+	resolves        *ingest.Service
+	approvals       *approval.Gate
+	muter           *notification.Muter
+	clock           clock.Clock
+	logger          *slog.Logger
+}
+
+// AlertCache serves the alerts of GET /v1/alerts/:dedupKey and its
+// children, which dashboards poll, e.g. cached.AlertRepository.
+type AlertCache interface {
+	CachedGetByDedupKey(ctx context.Context, dedupKey string) (*domain.Alert, error)
+	CachedGetChildrenByParent(ctx context.Context, parentDedupKey string) ([]*domain.Alert, error)
 }
 
 // NewAlertHandler creates a new alert handler.
-func NewAlertHandler(repo store.AlertRepository, logger *slog.Logger) *AlertHandler {
+// A nil cache reads every alert from the repository.
+// The state store provides the child counts of parent alerts, the
+// notification log the notifications listed in incident reports, the
+// remediation log the remediation actions run for alerts, and the processor
+// acknowledges and patches alerts. The ingest service queues force-resolves
+// of parent alerts and explains the grouping of alerts, and the approval
+// gate holds force-resolves for approval. The muter lists the active alerts
+// whose notifications are muted as silenced or suppressed. The clock tells
+// how long parent lookups remain.
+func NewAlertHandler(
+	repo store.AlertRepository,
+	cache AlertCache,
+	stateStore store.StateStore,
+	notificationLog store.NotificationLogRepository,
+	remediationLog store.RemediationLogRepository,
+	processor *processor.Service,
+	resolves *ingest.Service,
+	approvals *approval.Gate,
+	muter *notification.Muter,
+	clk clock.Clock,
+	logger *slog.Logger,
+) *AlertHandler {
+	if cache == nil {
+		cache = uncachedAlerts{repo}
+	}
 	return &AlertHandler{
-		repo:   repo,
-		logger: logger,
+		repo:            repo,
+		cache:           cache,
+		stateStore:      stateStore,
+		notificationLog: notificationLog,
+		remediationLog:  remediationLog,
+		processor:       processor,
+		resolves:        resolves,
+		approvals:       approvals,
+		muter:           muter,
+		clock:           clk,
+		logger:          logger,
+	}
+}
+
+// uncachedAlerts serves the cached reads from the repository.
+type uncachedAlerts struct {
+	store.AlertRepository
+}
+
+func (r uncachedAlerts) CachedGetByDedupKey(ctx context.Context, dedupKey string) (*domain.Alert, error) {
+	return r.GetByDedupKey(ctx, dedupKey)
+}
+
+func (r uncachedAlerts) CachedGetChildrenByParent(ctx context.Context, parentDedupKey string) ([]*domain.Alert, error) {
+	return r.GetChildrenByParent(ctx, parentDedupKey)
+}
+
+// alertResponse is an alert as returned by the API, with the state of its
+// lifecycle. Parent alerts also carry the number of active children, so
+// clients don't need to fetch them.
+type alertResponse struct {
+	*domain.Alert
+	State            domain.AlertState `json:"state"`
+	ActiveChildCount *int              `json:"active_child_count,omitempty"`
+
+	// Status replaces the stored status of active alerts whose notifications
+	// are muted with silenced or suppressed; SilencedBy is the silence.
+	Status     domain.AlertStatus `json:"status"`
+	SilencedBy string             `json:"silenced_by,omitempty"`
+}
+
+// childCountResponse is the body returned by GET /v1/alerts/:dedupKey/children/count.
+type childCountResponse struct {
+	DedupKey         string `json:"dedupKey"`
+	ChildCount       int    `json:"child_count"`
+	ActiveChildCount int    `json:"active_child_count"`
+}
+
+// liveCountsResponse is the body returned by GET /v1/alerts/live-counts.
+type liveCountsResponse struct {
+	Total  int                `json:"total"`
+	Counts []*store.LiveCount `json:"counts"`
+
+	// Silenced and Suppressed count the active alerts whose notifications
+	// are muted, included in Total.
+	Silenced   int `json:"silenced"`
+	Suppressed int `json:"suppressed"`
+}
+
+// alertTreeResponse is the body returned by GET /v1/alerts/:dedupKey/tree:
+// a parent alert with its children embedded.
+type alertTreeResponse struct {
+	alertResponse
+	Children []*domain.Alert `json:"children"`
+}
+
+// defaultListLimit is the page size used when a list request has no limit.
+const defaultListLimit = 100
+
+// parsePagination reads the limit and offset query parameters.
+// Invalid values are ignored; the limit defaults to defaultListLimit.
+func parsePagination(c *fiber.Ctx) (limit, offset int) {
+	limit = defaultListLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if o, err := strconv.Atoi(c.Query("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+	return limit, offset
+}
+
+// toResponse adds the status alerts are listed with and the active child
+// count of a parent alert from the state store.
+// The count is omitted if the state store cannot be read.
+func (h *AlertHandler) toResponse(ctx context.Context, alert *domain.Alert) alertResponse {
+	resp := alertResponse{Alert: alert, State: alert.State()}
+	resp.Status, resp.SilencedBy = h.muter.Status(alert)
+	if !alert.IsParent() {
+		return resp
+	}
+
+	count, err := h.stateStore.GetActiveChildCount(ctx, alert.DedupKey)
+	if err != nil {
+		h.logger.Warn("failed to get active child count", "dedupKey", alert.DedupKey, "error", err)
+		return resp
 	}
+	resp.ActiveChildCount = &count
+	return resp
 }
 
 // List handles GET /v1/alerts
-// Returns alerts matching query parameters.
+// Returns alerts matching query parameters. ?status=silenced or suppressed
+// lists the active alerts whose notifications are muted, and ?status=active
+// the others.
 func (h *AlertHandler) List(c *fiber.Ctx) error {
 	// Parse query parameters for filtering
+	// Alerts shared through global dedup are listed for every subscriber
 	filter := domain.AlertFilter{
-		EventManagerID: c.Query("event_manager_id"),
+		EventManagerID:    c.Query("event_manager_id"),
+		IncludeSubscribed: true,
+		Query:             strings.TrimSpace(c.Query("q")),
+		Service:           c.Query("service"),
+		Component:         c.Query("component"),
 	}
 
 	// Parse status filter
@@ -44,21 +198,18 @@ func (h *AlertHandler) List(c *fiber.Ctx) error {
 		filter.Type = domain.AlertType(alertType)
 	}
 
-	// Parse pagination
-	if limit := c.Query("limit"); limit != "" {
-		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
-			filter.Limit = l
-		}
-	}
-	if offset := c.Query("offset"); offset != "" {
-		if o, err := strconv.Atoi(offset); err == nil && o >= 0 {
-			filter.Offset = o
-		}
+	sort, err := domain.ParseAlertSort(c.Query("sort"))
+	if err != nil {
+		return ValidationError(c, err.Error())
 	}
+	filter.Sort = sort
+
+	filter.Limit, filter.Offset = parsePagination(c)
 
-	// Default limit if not specified
-	if filter.Limit == 0 {
-		filter.Limit = 100
+	// The repository decides the muted statuses, so pages are taken from
+	// the alerts with the status
+	if filter.Status == domain.AlertStatusActive || filter.Status.IsMuted() {
+		filter.Muting = h.muter.Snapshot()
 	}
 
 	alerts, err := h.repo.List(c.Context(), filter)
@@ -67,7 +218,14 @@ func (h *AlertHandler) List(c *fiber.Ctx) error {
 		return InternalError(c, "failed to list alerts")
 	}
 
-	return Success(c, alerts)
+	resp := make([]alertResponse, len(alerts))
+	for i, alert := range alerts {
+		resp[i] = h.toResponse(c.Context(), alert)
+	}
+
+	return SuccessWithLastModified(c, resp, latestUpdate(alerts, func(alert *domain.Alert) time.Time {
+		return alert.UpdatedAt
+	}))
 }
 
 // GetByDedupKey handles GET /v1/alerts/:dedupKey
@@ -78,31 +236,79 @@ func (h *AlertHandler) GetByDedupKey(c *fiber.Ctx) error {
 		return BadRequest(c, "dedupKey is required")
 	}
 
-	alert, err := h.repo.GetByDedupKey(c.Context(), dedupKey)
+	alert, err := h.cache.CachedGetByDedupKey(c.Context(), dedupKey)
 	if err != nil {
-		if errors.Is(err, domain.ErrAlertNotFound) {
-			return NotFound(c, "alert not found")
+		if isDomainError(err) {
+			return DomainError(c, err)
 		}
 		h.logger.Error("failed to get alert", "dedupKey", dedupKey, "error", err)
 		return InternalError(c, "failed to get alert")
 	}
 
-	return Success(c, alert)
+	return SuccessWithLastModified(c, h.toResponse(c.Context(), alert), alert.UpdatedAt)
+}
+
+// Patch handles PATCH /v1/alerts/:dedupKey
+// Changes the severity, tags, links, assignee or runbook of an alert. With
+// updated_at in the body, the patch is rejected with 409 Conflict if the
+// alert changed since.
+func (h *AlertHandler) Patch(c *fiber.Ctx) error {
+	dedupKey := c.Params("dedupKey")
+	if dedupKey == "" {
+		return BadRequest(c, "dedupKey is required")
+	}
+
+	var patch domain.AlertPatch
+	if err := c.BodyParser(&patch); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+	if err := patch.Validate(); err != nil {
+		return ValidationError(c, err.Error())
+	}
+
+	alert, err := h.processor.PatchAlert(c.Context(), dedupKey, &patch)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to patch alert", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to patch alert")
+	}
+
+	return Success(c, h.toResponse(c.Context(), alert))
 }
 
 // GetChildren handles GET /v1/alerts/:dedupKey/children
-// Returns all child alerts for a given parent alert.
+// Returns the child alerts of a parent alert, newest first. Children can be
+// filtered with ?status= and ?severity=, sorted with ?sort= and paginated
+// with ?limit= and ?offset=; the response counts the children matching.
+// Without parameters, every child is returned from the cache.
 func (h *AlertHandler) GetChildren(c *fiber.Ctx) error {
 	dedupKey := c.Params("dedupKey")
 	if dedupKey == "" {
 		return BadRequest(c, "dedupKey is required")
 	}
 
+	filter := domain.AlertFilter{
+		ParentDedupKey: dedupKey,
+		Status:         domain.AlertStatus(c.Query("status")),
+		Severity:       domain.Severity(c.Query("severity")),
+	}
+	if filter.Severity != "" && !filter.Severity.IsValid() {
+		return DomainError(c, domain.ErrInvalidSeverity)
+	}
+	sort, err := domain.ParseAlertSort(c.Query("sort"))
+	if err != nil {
+		return ValidationError(c, err.Error())
+	}
+	filter.Sort = sort
+
 	// First verify the parent exists
-	parent, err := h.repo.GetByDedupKey(c.Context(), dedupKey)
+	parent, err := h.cache.CachedGetByDedupKey(c.Context(), dedupKey)
 	if err != nil {
-		if errors.Is(err, domain.ErrAlertNotFound) {
-			return NotFound(c, "alert not found")
+		if isDomainError(err) {
+			return DomainError(c, err)
 		}
 		h.logger.Error("failed to get alert", "dedupKey", dedupKey, "error", err)
 		return InternalError(c, "failed to get alert")
@@ -110,15 +316,578 @@ func (h *AlertHandler) GetChildren(c *fiber.Ctx) error {
 
 	// Verify it's a parent alert
 	if !parent.IsParent() {
-		return BadRequest(c, "alert is not a parent alert")
+		return DomainError(c, domain.ErrNotParentAlert)
+	}
+
+	// Dashboards polling every child are served from the cache
+	if len(c.Queries()) == 0 {
+		children, err := h.cache.CachedGetChildrenByParent(c.Context(), dedupKey)
+		if err != nil {
+			h.logger.Error("failed to get children", "parentDedupKey", dedupKey, "error", err)
+			return InternalError(c, "failed to get children")
+		}
+		slices.SortStableFunc(children, filter.Sort.Compare)
+		return SuccessWithPagination(c, children, Pagination{Total: len(children)})
+	}
+
+	filter.Limit, filter.Offset = parsePagination(c)
+	children, err := h.repo.List(c.Context(), filter)
+	if err != nil {
+		h.logger.Error("failed to get children", "parentDedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to get children")
+	}
+	if children == nil {
+		children = []*domain.Alert{}
+	}
+	total, err := h.repo.Count(c.Context(), filter)
+	if err != nil {
+		h.logger.Error("failed to count children", "parentDedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to count children")
+	}
+
+	return SuccessWithPagination(c, children, Pagination{Total: total, Limit: filter.Limit, Offset: filter.Offset})
+}
+
+// Tree handles GET /v1/alerts/:dedupKey/tree
+// Returns a parent alert with its child alerts embedded, newest first.
+// Children can be filtered with ?status= and paginated with ?limit= and ?offset=.
+func (h *AlertHandler) Tree(c *fiber.Ctx) error {
+	dedupKey := c.Params("dedupKey")
+	if dedupKey == "" {
+		return BadRequest(c, "dedupKey is required")
+	}
+
+	parent, err := h.repo.GetByDedupKey(c.Context(), dedupKey)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get alert", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to get alert")
+	}
+
+	if !parent.IsParent() {
+		return DomainError(c, domain.ErrNotParentAlert)
+	}
+
+	filter := domain.AlertFilter{
+		ParentDedupKey: parent.DedupKey,
+		Status:         domain.AlertStatus(c.Query("status")),
+	}
+	filter.Limit, filter.Offset = parsePagination(c)
+
+	children, err := h.repo.List(c.Context(), filter)
+	if err != nil {
+		h.logger.Error("failed to get children", "parentDedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to get children")
+	}
+	if children == nil {
+		children = []*domain.Alert{}
+	}
+
+	return Success(c, alertTreeResponse{
+		alertResponse: h.toResponse(c.Context(), parent),
+		Children:      children,
+	})
+}
+
+// Diagnostics handles GET /v1/alerts/:dedupKey/diagnostics
+// Explains the grouping of an alert under the current configuration of its
+// event manager: the grouping rule selected, the grouping value extracted,
+// the parent lookup key with the parent registered and its TTL remaining,
+// and the children of its group.
+func (h *AlertHandler) Diagnostics(c *fiber.Ctx) error {
+	dedupKey := c.Params("dedupKey")
+	if dedupKey == "" {
+		return BadRequest(c, "dedupKey is required")
+	}
+
+	alert, err := h.repo.GetByDedupKey(c.Context(), dedupKey)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get alert", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to get alert")
+	}
+
+	diagnostics, err := h.resolves.DiagnoseGrouping(c.Context(), alert, h.stateStore, h.clock.Now())
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to diagnose alert grouping", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to diagnose alert grouping")
+	}
+
+	return Success(c, diagnostics)
+}
+
+// ChildCount handles GET /v1/alerts/:dedupKey/children/count
+// Returns the total and active number of children of a parent alert,
+// read from the state store without fetching the children.
+func (h *AlertHandler) ChildCount(c *fiber.Ctx) error {
+	dedupKey := c.Params("dedupKey")
+	if dedupKey == "" {
+		return BadRequest(c, "dedupKey is required")
+	}
+
+	parent, err := h.repo.GetByDedupKey(c.Context(), dedupKey)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get alert", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to get alert")
+	}
+
+	if !parent.IsParent() {
+		return DomainError(c, domain.ErrNotParentAlert)
+	}
+
+	total, err := h.stateStore.GetChildCount(c.Context(), dedupKey)
+	if err != nil {
+		h.logger.Error("failed to get child count", "parentDedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to get child count")
+	}
+	active, err := h.stateStore.GetActiveChildCount(c.Context(), dedupKey)
+	if err != nil {
+		h.logger.Error("failed to get active child count", "parentDedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to get child count")
+	}
+
+	return Success(c, childCountResponse{
+		DedupKey:         parent.DedupKey,
+		ChildCount:       total,
+		ActiveChildCount: active,
+	})
+}
+
+// LiveCounts handles GET /v1/alerts/live-counts
+// Returns the number of active alerts by event manager and severity, kept up
+// to date by the processor in the state store, so wallboards can poll it
+// without aggregating the alert repository. Counts are soft real-time: they
+// can drift until the processor reconciles them with the repository. While
+// notifications are muted, the active alerts are read to count those
+// silenced or suppressed.
+func (h *AlertHandler) LiveCounts(c *fiber.Ctx) error {
+	counts, err := h.stateStore.GetLiveCounts(c.Context())
+	if err != nil {
+		h.logger.Error("failed to get live counts", "error", err)
+		return InternalError(c, "failed to get live counts")
+	}
+
+	resp := liveCountsResponse{Counts: []*store.LiveCount{}}
+	eventManagerID := c.Query("event_manager_id")
+	for _, count := range counts {
+		if eventManagerID != "" && count.EventManagerID != eventManagerID {
+			continue
+		}
+		resp.Total += count.Count
+		resp.Counts = append(resp.Counts, count)
+	}
+
+	muting := h.muter.Snapshot()
+	switch {
+	case muting == nil:
+	case muting.BySeverity():
+		// Silences matching by event manager and severity alone mute every
+		// alert of a count or none
+		for _, count := range resp.Counts {
+			alert := &domain.Alert{EventManagerID: count.EventManagerID, Severity: domain.Severity(count.Severity), Status: domain.AlertStatusActive}
+			switch status, _ := muting.Status(alert); status {
+			case domain.AlertStatusSilenced:
+				resp.Silenced += count.Count
+			case domain.AlertStatusSuppressed:
+				resp.Suppressed += count.Count
+			}
+		}
+	default:
+		filter := domain.AlertFilter{EventManagerID: eventManagerID, Status: domain.AlertStatusSilenced, Muting: muting}
+		if resp.Silenced, err = h.repo.Count(c.Context(), filter); err == nil {
+			filter.Status = domain.AlertStatusSuppressed
+			resp.Suppressed, err = h.repo.Count(c.Context(), filter)
+		}
+		if err != nil {
+			h.logger.Error("failed to count muted alerts", "error", err)
+			return InternalError(c, "failed to get live counts")
+		}
+	}
+	return Success(c, resp)
+}
+
+// History handles GET /v1/alerts/:dedupKey/history
+// Returns every revision of an alert, oldest first. With ?at=<RFC3339>,
+// returns only the revision that was current at that time.
+func (h *AlertHandler) History(c *fiber.Ctx) error {
+	dedupKey := c.Params("dedupKey")
+	if dedupKey == "" {
+		return BadRequest(c, "dedupKey is required")
+	}
+
+	if at := c.Query("at"); at != "" {
+		t, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			return BadRequest(c, "'at' must be an RFC3339 timestamp")
+		}
+
+		revision, err := h.repo.RevisionAt(c.Context(), dedupKey, t.UTC())
+		if err != nil {
+			if errors.Is(err, domain.ErrAlertNotFound) {
+				return NotFound(c, "alert did not exist at the given time")
+			}
+			h.logger.Error("failed to get alert revision", "dedupKey", dedupKey, "at", at, "error", err)
+			return InternalError(c, "failed to get alert history")
+		}
+
+		return Success(c, revision)
+	}
+
+	revisions, err := h.repo.History(c.Context(), dedupKey)
+	if err != nil {
+		h.logger.Error("failed to get alert history", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to get alert history")
+	}
+
+	if len(revisions) == 0 {
+		return DomainError(c, domain.ErrAlertNotFound)
+	}
+
+	return Success(c, revisions)
+}
+
+// Episodes handles GET /v1/alerts/:dedupKey/episodes
+// Returns every episode of an alert, from triggering or reactivation to
+// resolution, oldest first; the last is the current episode.
+func (h *AlertHandler) Episodes(c *fiber.Ctx) error {
+	dedupKey := c.Params("dedupKey")
+	if dedupKey == "" {
+		return BadRequest(c, "dedupKey is required")
+	}
+
+	alert, err := h.repo.GetByDedupKey(c.Context(), dedupKey)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get alert", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to get alert")
+	}
+
+	return Success(c, alert.AllEpisodes())
+}
+
+// relatedCandidates is the number of most recent alerts of an event manager
+// compared to find related alerts.
+const relatedCandidates = 1000
+
+// defaultRelatedLimit is the number of related alerts returned by default.
+const defaultRelatedLimit = 10
+
+// Related handles GET /v1/alerts/:dedupKey/related
+// Returns the alerts of the same event manager most similar to an alert, by
+// class, summary words and dedup key parts, with their similarity scores.
+// Accepts limit (default 10) and min_score (default 0.3).
+func (h *AlertHandler) Related(c *fiber.Ctx) error {
+	dedupKey := c.Params("dedupKey")
+	if dedupKey == "" {
+		return BadRequest(c, "dedupKey is required")
+	}
+
+	limit := defaultRelatedLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	minScore := domain.DefaultRelatedMinScore
+	if s := c.Query("min_score"); s != "" {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 0 || v > 1 {
+			return BadRequest(c, "'min_score' must be a number between 0 and 1")
+		}
+		minScore = v
+	}
+
+	alert, err := h.repo.GetByDedupKey(c.Context(), dedupKey)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get alert", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to get alert")
+	}
+
+	candidates, err := h.repo.List(c.Context(), domain.AlertFilter{
+		EventManagerID:    alert.EventManagerID,
+		IncludeSubscribed: true,
+		Limit:             relatedCandidates,
+	})
+	if err != nil {
+		h.logger.Error("failed to list alerts", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to find related alerts")
+	}
+
+	return Success(c, domain.RelatedAlerts(alert, candidates, minScore, limit))
+}
+
+// Remediations handles GET /v1/alerts/:dedupKey/remediations
+// Returns the remediation actions run for an alert and their outcomes,
+// oldest first.
+func (h *AlertHandler) Remediations(c *fiber.Ctx) error {
+	dedupKey := c.Params("dedupKey")
+	if dedupKey == "" {
+		return BadRequest(c, "dedupKey is required")
+	}
+
+	if _, err := h.repo.GetByDedupKey(c.Context(), dedupKey); err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get alert", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to get alert")
+	}
+
+	records, err := h.remediationLog.ListByDedupKey(c.Context(), dedupKey)
+	if err != nil {
+		h.logger.Error("failed to list remediations", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to list remediations")
+	}
+
+	return Success(c, records)
+}
+
+// Report handles GET /v1/alerts/:dedupKey/report
+// Returns an incident report of a parent alert: the parent, its children, the
+// timeline of their changes and the notifications sent. With ?format=markdown,
+// returns the report as a Markdown document.
+func (h *AlertHandler) Report(c *fiber.Ctx) error {
+	dedupKey := c.Params("dedupKey")
+	if dedupKey == "" {
+		return BadRequest(c, "dedupKey is required")
+	}
+
+	format := c.Query("format", "json")
+	if format != "json" && format != "markdown" {
+		return BadRequest(c, "format must be 'json' or 'markdown'")
+	}
+
+	parent, err := h.repo.GetByDedupKey(c.Context(), dedupKey)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get alert", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to get alert")
+	}
+
+	if !parent.IsParent() {
+		return DomainError(c, domain.ErrNotParentAlert)
 	}
 
-	// Get children
 	children, err := h.repo.GetChildrenByParent(c.Context(), dedupKey)
 	if err != nil {
 		h.logger.Error("failed to get children", "parentDedupKey", dedupKey, "error", err)
 		return InternalError(c, "failed to get children")
 	}
 
-	return Success(c, children)
+	revisions := make([][]*domain.AlertRevision, 0, len(children)+1)
+	var remediations []*domain.RemediationRecord
+	for _, alert := range append([]*domain.Alert{parent}, children...) {
+		history, err := h.repo.History(c.Context(), alert.DedupKey)
+		if err != nil {
+			h.logger.Error("failed to get alert history", "dedupKey", alert.DedupKey, "error", err)
+			return InternalError(c, "failed to get alert history")
+		}
+		revisions = append(revisions, history)
+
+		records, err := h.remediationLog.ListByDedupKey(c.Context(), alert.DedupKey)
+		if err != nil {
+			h.logger.Error("failed to list remediations", "dedupKey", alert.DedupKey, "error", err)
+			return InternalError(c, "failed to list remediations")
+		}
+		remediations = append(remediations, records...)
+	}
+
+	notifications, err := h.notificationLog.ListByDedupKey(c.Context(), dedupKey)
+	if err != nil {
+		h.logger.Error("failed to list notifications", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to list notifications")
+	}
+
+	report := domain.NewIncidentReport(parent, children, revisions, notifications, remediations)
+	if format == "markdown" {
+		c.Set(fiber.HeaderContentType, "text/markdown; charset=utf-8")
+		return c.SendString(report.Markdown())
+	}
+	return Success(c, report)
+}
+
+// Acknowledge handles POST /v1/alerts/:dedupKey/acknowledge
+// Marks an active alert as acknowledged by a responder.
+func (h *AlertHandler) Acknowledge(c *fiber.Ctx) error {
+	dedupKey := c.Params("dedupKey")
+	if dedupKey == "" {
+		return BadRequest(c, "dedupKey is required")
+	}
+
+	alert, err := h.processor.Acknowledge(c.Context(), dedupKey)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to acknowledge alert", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to acknowledge alert")
+	}
+
+	return Success(c, h.toResponse(c.Context(), alert))
+}
+
+// UpdateLinks handles PATCH /v1/alerts/:dedupKey/links
+// Adds links to an alert, replacing links with the same URL, and removes the
+// links with the URLs listed in remove.
+func (h *AlertHandler) UpdateLinks(c *fiber.Ctx) error {
+	dedupKey := c.Params("dedupKey")
+	if dedupKey == "" {
+		return BadRequest(c, "dedupKey is required")
+	}
+
+	var req domain.UpdateLinksRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+	if err := req.Validate(); err != nil {
+		return ValidationError(c, err.Error())
+	}
+
+	err := h.repo.UpdateLinks(c.Context(), dedupKey, req.Links, req.Remove)
+	if isDomainError(err) {
+		return DomainError(c, err)
+	}
+	if err != nil {
+		h.logger.Error("failed to update alert links", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to update alert links")
+	}
+
+	alert, err := h.repo.GetByDedupKey(c.Context(), dedupKey)
+	if err != nil {
+		h.logger.Error("failed to get alert", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to get alert")
+	}
+	return Success(c, h.toResponse(c.Context(), alert))
+}
+
+// Import handles POST /v1/admin/import
+// Writes historical alerts, e.g. exported from another alerting system, with
+// their original timestamps and without sending notifications. Alerts that
+// already exist are skipped.
+func (h *AlertHandler) Import(c *fiber.Ctx) error {
+	var req domain.AlertImportRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+
+	result, err := h.processor.ImportAlerts(c.Context(), &req)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to import alerts", "error", err)
+		return InternalError(c, "failed to import alerts")
+	}
+
+	return Success(c, result)
+}
+
+// ForceResolve handles POST /v1/alerts/:dedupKey/force-resolve
+// Resolves a parent alert and all its active children at once. The optional
+// body records the actor and reason of the resolution. The resolve is queued
+// behind the events of the alert received before, and 202 Accepted is
+// returned with the alert as it was when queued. With approvals enabled,
+// the resolve is held and 202 Accepted is returned with the pending
+// approval.
+func (h *AlertHandler) ForceResolve(c *fiber.Ctx) error {
+	dedupKey := c.Params("dedupKey")
+	if dedupKey == "" {
+		return BadRequest(c, "dedupKey is required")
+	}
+
+	var req domain.ForceResolveRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			h.logger.Debug("failed to parse request body", "error", err)
+			return BadRequest(c, "invalid request body")
+		}
+	}
+
+	// Check the alert now, so a request that can't succeed isn't queued or
+	// held
+	parent, err := h.resolvableParent(c.Context(), dedupKey)
+	if err != nil {
+		return h.forceResolveError(c, dedupKey, err)
+	}
+
+	resolution := req.Resolution()
+	if h.approvals.Enabled() {
+		return requestApproval(c, h.approvals, domain.OperationForceResolve, dedupKey, func(ctx context.Context) (string, error) {
+			parent, err := h.resolvableParent(ctx, dedupKey)
+			if err != nil {
+				return "", err
+			}
+			if err := h.queueResolve(ctx, parent, resolution); err != nil {
+				return "", err
+			}
+			return "queued resolve of parent and its children", nil
+		})
+	}
+
+	if err := h.queueResolve(c.Context(), parent, resolution); err != nil {
+		return h.forceResolveError(c, dedupKey, err)
+	}
+
+	h.approvals.Executed(c.Context(), domain.OperationForceResolve, dedupKey, c.Get(ActorHeader), "queued resolve of parent and its children")
+	return Accepted(c, h.toResponse(c.Context(), parent))
+}
+
+// resolvableParent returns an alert if it is an active parent alert.
+func (h *AlertHandler) resolvableParent(ctx context.Context, dedupKey string) (*domain.Alert, error) {
+	parent, err := h.repo.GetByDedupKey(ctx, dedupKey)
+	if err != nil {
+		return nil, err
+	}
+	if !parent.IsParent() {
+		return nil, domain.ErrNotParentAlert
+	}
+	if !parent.IsActive() {
+		return nil, domain.ErrAlertAlreadyResolved
+	}
+	return parent, nil
+}
+
+// queueResolve queues the resolve of a parent alert under the partition key
+// recorded in its state.
+func (h *AlertHandler) queueResolve(ctx context.Context, parent *domain.Alert, resolution domain.Resolution) error {
+	state, err := h.stateStore.GetAlert(ctx, parent.DedupKey)
+	if err != nil {
+		return fmt.Errorf("failed to get alert state: %w", err)
+	}
+	var partitionKey string
+	if state != nil {
+		partitionKey = state.PartitionKey
+	}
+	return h.resolves.PublishResolve(ctx, parent, partitionKey, resolution)
+}
+
+// forceResolveError maps an error force-resolving an alert to a response.
+func (h *AlertHandler) forceResolveError(c *fiber.Ctx, dedupKey string, err error) error {
+	switch {
+	case isDomainError(err):
+		return DomainError(c, err)
+	case errors.Is(err, ingest.ErrBacklogged):
+		return Unavailable(c, ErrCodeQueueUnavailable, time.Second, err.Error())
+	case errors.Is(err, ingest.ErrPublishFailed):
+		return Unavailable(c, ErrCodeQueueUnavailable, 5*time.Second, err.Error())
+	}
+	h.logger.Error("failed to force-resolve alert", "dedupKey", dedupKey, "error", err)
+	return InternalError(c, "failed to force-resolve alert")
 }
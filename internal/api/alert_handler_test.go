@@ -0,0 +1,300 @@
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/pkg/argustest"
+)
+
+func TestAlertHandler_ChildCount(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	ingest(t, h, emID, domain.ActionTrigger, "storage", "host-1", "host-2", "host-3")
+	ingest(t, h, emID, domain.ActionResolve, "storage", "host-3")
+	h.AwaitStatus(t, "host-3", domain.AlertStatusResolved)
+
+	var counts struct {
+		Data struct {
+			ChildCount       int `json:"child_count"`
+			ActiveChildCount int `json:"active_child_count"`
+		} `json:"data"`
+	}
+	if status := call(t, h, http.MethodGet, "/v1/alerts/host-1/children/count", "", &counts); status != http.StatusOK {
+		t.Fatalf("GET children/count status = %d, want 200", status)
+	}
+	if counts.Data.ChildCount != 2 || counts.Data.ActiveChildCount != 1 {
+		t.Errorf("counts = %+v, want 2 children, 1 active", counts.Data)
+	}
+	if status := call(t, h, http.MethodGet, "/v1/alerts/host-2/children/count", "", nil); status != http.StatusBadRequest {
+		t.Errorf("GET children/count of a child status = %d, want 400", status)
+	}
+
+	var parent struct {
+		Data struct {
+			ActiveChildCount *int `json:"active_child_count"`
+		} `json:"data"`
+	}
+	call(t, h, http.MethodGet, "/v1/alerts/host-1", "", &parent)
+	if parent.Data.ActiveChildCount == nil || *parent.Data.ActiveChildCount != 1 {
+		t.Errorf("parent active_child_count = %v, want 1", parent.Data.ActiveChildCount)
+	}
+}
+
+func TestAlertHandler_Tree(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	ingest(t, h, emID, domain.ActionTrigger, "storage", "host-1", "host-2", "host-3", "host-4")
+	ingest(t, h, emID, domain.ActionResolve, "storage", "host-4")
+	h.AwaitStatus(t, "host-4", domain.AlertStatusResolved)
+
+	getTree := func(query string) (int, []*domain.Alert) {
+		t.Helper()
+		var body struct {
+			Data struct {
+				DedupKey string          `json:"dedupKey"`
+				Children []*domain.Alert `json:"children"`
+			} `json:"data"`
+		}
+		status := call(t, h, http.MethodGet, "/v1/alerts/host-1/tree"+query, "", &body)
+		if status == http.StatusOK && body.Data.DedupKey != "host-1" {
+			t.Errorf("tree dedupKey = %q, want host-1", body.Data.DedupKey)
+		}
+		return status, body.Data.Children
+	}
+
+	if status, children := getTree(""); status != http.StatusOK || len(children) != 3 {
+		t.Fatalf("GET tree = %d with %d children, want 200 with 3", status, len(children))
+	}
+	if _, children := getTree("?status=active"); len(children) != 2 {
+		t.Errorf("active children = %d, want 2", len(children))
+	}
+	if _, children := getTree("?limit=2&offset=2"); len(children) != 1 {
+		t.Errorf("children on second page = %d, want 1", len(children))
+	}
+
+	var children struct {
+		Data       []*domain.Alert `json:"data"`
+		Pagination struct {
+			Total int `json:"total"`
+		} `json:"pagination"`
+	}
+	getChildren := func(query string) int {
+		t.Helper()
+		children.Data = nil
+		return call(t, h, http.MethodGet, "/v1/alerts/host-1/children"+query, "", &children)
+	}
+	if status := getChildren(""); status != http.StatusOK || len(children.Data) != 3 || children.Pagination.Total != 3 {
+		t.Errorf("GET children = %d with %d of %d children, want 200 with 3", status, len(children.Data), children.Pagination.Total)
+	}
+	getChildren("?status=active&sort=created_at")
+	active := children.Data
+	if len(active) != 2 || children.Pagination.Total != 2 || active[0].CreatedAt.After(active[1].CreatedAt) {
+		t.Fatalf("active children oldest first = %+v of %d, want 2", active, children.Pagination.Total)
+	}
+	if getChildren("?status=active&sort=created_at&limit=1"); len(children.Data) != 1 || children.Pagination.Total != 2 || children.Data[0].DedupKey != active[0].DedupKey {
+		t.Errorf("first active child = %+v of %d, want %s of 2", children.Data, children.Pagination.Total, active[0].DedupKey)
+	}
+	if status := getChildren("?sort=size"); status != http.StatusBadRequest {
+		t.Errorf("GET children sorted by an unknown field = %d, want 400", status)
+	}
+
+	if status := call(t, h, http.MethodGet, "/v1/alerts/host-2/tree", "", nil); status != http.StatusBadRequest {
+		t.Errorf("GET tree of a child status = %d, want 400", status)
+	}
+}
+
+func TestAlertHandler_Report(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	ingest(t, h, emID, domain.ActionTrigger, "storage", "host-1", "host-2")
+	h.AwaitStatus(t, "host-2", domain.AlertStatusActive)
+
+	var body struct {
+		Data domain.IncidentReport `json:"data"`
+	}
+	if status := call(t, h, http.MethodGet, "/v1/alerts/host-1/report", "", &body); status != http.StatusOK {
+		t.Fatalf("GET report status = %d, want 200", status)
+	}
+	report := body.Data
+	if report.Parent == nil || report.Parent.DedupKey != "host-1" || len(report.Children) != 1 {
+		t.Errorf("report = %+v, want host-1 with one child", report)
+	}
+	if len(report.Timeline) < 2 {
+		t.Errorf("timeline = %v, want the creation of both alerts", report.Timeline)
+	}
+	if len(report.Notifications) != 1 || report.Notifications[0].Kind != domain.NotificationNewParent {
+		t.Errorf("notifications = %v, want the new parent notification", report.Notifications)
+	}
+
+	resp, err := http.Get(h.URL + "/v1/alerts/host-1/report?format=markdown")
+	if err != nil {
+		t.Fatalf("GET markdown report error: %v", err)
+	}
+	md, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("reading the markdown report: %v", err)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/markdown") || !strings.Contains(string(md), "# Incident report: storage failure on host-1") {
+		t.Errorf("markdown report = (%s) %s", resp.Header.Get("Content-Type"), md)
+	}
+
+	// Reports cover parent alerts only
+	if status := call(t, h, http.MethodGet, "/v1/alerts/host-2/report", "", nil); status != http.StatusBadRequest {
+		t.Errorf("child report status = %d, want 400", status)
+	}
+}
+
+func TestAlertHandler_ForceResolve(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	ingest(t, h, emID, domain.ActionTrigger, "database", "db-1", "db-2", "db-3")
+	h.AwaitStatus(t, "db-3", domain.AlertStatusActive)
+
+	// Children cannot be force-resolved on their own
+	if status := call(t, h, http.MethodPost, "/v1/alerts/db-2/force-resolve", "", nil); status != http.StatusBadRequest {
+		t.Errorf("child force-resolve status = %d, want 400", status)
+	}
+	if status := call(t, h, http.MethodPost, "/v1/alerts/db-1/force-resolve", `{"actor":"alice","reason":"failover completed"}`, nil); status != http.StatusAccepted {
+		t.Fatalf("force-resolve status = %d, want 202", status)
+	}
+
+	// The resolve is queued and processed like the events of the group
+	h.Sync(t)
+
+	for _, dedupKey := range []string{"db-1", "db-2", "db-3"} {
+		alert := h.AwaitStatus(t, dedupKey, domain.AlertStatusResolved)
+		if alert.Resolution == nil || alert.Resolution.Actor != "alice" || alert.Resolution.ResolvedBy != domain.ResolvedByAPI {
+			t.Errorf("%s resolution = %+v, want resolved by alice through the api", dedupKey, alert.Resolution)
+		}
+		state, err := h.StateStore.GetAlert(context.Background(), dedupKey)
+		if err != nil {
+			t.Fatalf("GetAlert error: %v", err)
+		}
+		if state == nil || state.Status != string(domain.AlertStatusResolved) {
+			t.Errorf("%s state = %+v, want resolved", dedupKey, state)
+		}
+	}
+
+	// A new trigger reactivates the parent as usual
+	ingest(t, h, emID, domain.ActionTrigger, "database", "db-1")
+	h.AwaitStatus(t, "db-1", domain.AlertStatusActive)
+
+	if status := call(t, h, http.MethodPost, "/v1/alerts/missing/force-resolve", "", nil); status != http.StatusNotFound {
+		t.Errorf("missing force-resolve status = %d, want 404", status)
+	}
+}
+
+func TestAlertHandler_Diagnostics(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+	em := eventManager(t, h, emID)
+
+	ingest(t, h, emID, domain.ActionTrigger, "disk", "disk-1", "disk-2")
+	h.AwaitStatus(t, "disk-2", domain.AlertStatusActive)
+
+	diagnose := func(dedupKey string) (int, domain.AlertDiagnostics) {
+		t.Helper()
+		var result struct {
+			Data domain.AlertDiagnostics `json:"data"`
+		}
+		status := call(t, h, http.MethodGet, "/v1/alerts/"+dedupKey+"/diagnostics", "", &result)
+		return status, result.Data
+	}
+
+	h.Advance(time.Minute)
+	code, parent := diagnose("disk-1")
+	if code != http.StatusOK {
+		t.Fatalf("GET diagnostics status = %d, want 200", code)
+	}
+	if parent.GroupingRuleID != em.GroupingRuleID || parent.GroupingKey != "class" || parent.GroupingValue != "disk" {
+		t.Errorf("grouping = rule %s on %s=%q, want rule %s on class=\"disk\"", parent.GroupingRuleID, parent.GroupingKey, parent.GroupingValue, em.GroupingRuleID)
+	}
+	lookup := parent.ParentLookup
+	if lookup == nil || lookup.Key != emID+":class:disk" || lookup.ParentDedupKey != "disk-1" || lookup.TTLRemaining != domain.Duration(4*time.Minute) {
+		t.Errorf("parent lookup = %+v, want disk-1 for another 4m", lookup)
+	}
+	if len(parent.Children) != 1 || parent.Children[0] != "disk-2" {
+		t.Errorf("children = %v, want [disk-2]", parent.Children)
+	}
+	if want := `opened the group of class="disk"; new alerts with the value are grouped under it for another 4m`; parent.Decision != want {
+		t.Errorf("decision = %q, want %q", parent.Decision, want)
+	}
+
+	if _, child := diagnose("disk-2"); child.Type != domain.AlertTypeChild || child.ParentDedupKey != "disk-1" || len(child.Children) != 1 {
+		t.Errorf("child diagnostics = %+v, want disk-2 grouped under disk-1", child)
+	}
+
+	// Once the time window ends, the next alert opens a new group
+	h.Advance(5 * time.Minute)
+	if _, parent := diagnose("disk-1"); parent.ParentLookup == nil || parent.ParentLookup.ParentDedupKey != "" {
+		t.Errorf("parent lookup after the window = %+v, want no parent", parent.ParentLookup)
+	}
+
+	if code, _ := diagnose("missing"); code != http.StatusNotFound {
+		t.Errorf("GET diagnostics of a missing alert status = %d, want 404", code)
+	}
+}
+
+func TestAlertHandler_MutedStatuses(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	ingest(t, h, emID, domain.ActionTrigger, "batch", "batch-1")
+	ingest(t, h, emID, domain.ActionTrigger, "db", "db-1")
+
+	body := fmt.Sprintf(`{"match":{"dedup_key_pattern":"batch-*"},"ends_at":%q}`, h.Now().Add(time.Hour).Format(time.RFC3339))
+	var created struct {
+		Data domain.Silence `json:"data"`
+	}
+	if status := call(t, h, http.MethodPost, "/v1/silences", body, &created); status != http.StatusCreated {
+		t.Fatalf("POST silence = %d, want 201", status)
+	}
+
+	type listed struct {
+		DedupKey   string             `json:"dedupKey"`
+		Status     domain.AlertStatus `json:"status"`
+		SilencedBy string             `json:"silenced_by"`
+	}
+	list := func(status domain.AlertStatus) []listed {
+		t.Helper()
+		var result struct {
+			Data []listed `json:"data"`
+		}
+		call(t, h, http.MethodGet, "/v1/alerts?status="+string(status), "", &result)
+		return result.Data
+	}
+
+	silenced := list(domain.AlertStatusSilenced)
+	if len(silenced) != 1 || silenced[0].DedupKey != "batch-1" || silenced[0].SilencedBy != created.Data.ID {
+		t.Errorf("silenced alerts = %+v, want batch-1 silenced by %s", silenced, created.Data.ID)
+	}
+	if active := list(domain.AlertStatusActive); len(active) != 1 || active[0].DedupKey != "db-1" || active[0].Status != domain.AlertStatusActive {
+		t.Errorf("active alerts = %+v, want db-1 only", active)
+	}
+	if suppressed := list(domain.AlertStatusSuppressed); len(suppressed) != 0 {
+		t.Errorf("suppressed alerts = %+v, want none", suppressed)
+	}
+
+	var counts struct {
+		Data struct {
+			Total    int `json:"total"`
+			Silenced int `json:"silenced"`
+		} `json:"data"`
+	}
+	call(t, h, http.MethodGet, "/v1/alerts/live-counts", "", &counts)
+	if counts.Data.Total != 2 || counts.Data.Silenced != 1 {
+		t.Errorf("live counts = %+v, want 2 active alerts with 1 silenced", counts.Data)
+	}
+}
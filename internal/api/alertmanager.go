@@ -0,0 +1,210 @@
+package api
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"argus-go/internal/domain"
+)
+
+// alertmanagerResolveTimeout is added to the last update of an active alert
+// for its endsAt, like Alertmanager does for alerts without an end time.
+const alertmanagerResolveTimeout = 5 * time.Minute
+
+// alertmanagerAlert is an alert in the shape of the Alertmanager v2 API
+// (GettableAlert), for dashboards such as Grafana and Karma.
+type alertmanagerAlert struct {
+	Labels       map[string]string      `json:"labels"`
+	Annotations  map[string]string      `json:"annotations"`
+	StartsAt     time.Time              `json:"startsAt"`
+	EndsAt       time.Time              `json:"endsAt"`
+	UpdatedAt    time.Time              `json:"updatedAt"`
+	Fingerprint  string                 `json:"fingerprint"`
+	Receivers    []alertmanagerReceiver `json:"receivers"`
+	Status       alertmanagerStatus     `json:"status"`
+	GeneratorURL string                 `json:"generatorURL"`
+}
+
+// alertmanagerReceiver names the receiver of an alert: its event manager.
+type alertmanagerReceiver struct {
+	Name string `json:"name"`
+}
+
+// alertmanagerStatus is the state of an alert. Child alerts are reported as
+// inhibited by their parent, so grouped alerts can be hidden like in
+// Alertmanager.
+type alertmanagerStatus struct {
+	State       string   `json:"state"`
+	SilencedBy  []string `json:"silencedBy"`
+	InhibitedBy []string `json:"inhibitedBy"`
+}
+
+// alertmanagerMatcher is a label matcher of the filter query parameter,
+// e.g. severity="high" or class=~"db.*".
+type alertmanagerMatcher struct {
+	name     string
+	value    string
+	negative bool
+	re       *regexp.Regexp
+}
+
+// matcherPattern parses a matcher: a label name, an operator and a value,
+// optionally quoted.
+var matcherPattern = regexp.MustCompile(`^\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*(=~|!~|!=|=)\s*(.*?)\s*$`)
+
+// parseAlertmanagerMatcher parses a matcher of the filter query parameter.
+func parseAlertmanagerMatcher(s string) (*alertmanagerMatcher, error) {
+	parts := matcherPattern.FindStringSubmatch(s)
+	if parts == nil {
+		return nil, fmt.Errorf("invalid filter %q", s)
+	}
+
+	value := parts[3]
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		value = strings.ReplaceAll(value[1:len(value)-1], `\"`, `"`)
+	}
+
+	m := &alertmanagerMatcher{name: parts[1], value: value, negative: strings.HasPrefix(parts[2], "!")}
+	if strings.HasSuffix(parts[2], "~") {
+		re, err := regexp.Compile("^(?:" + value + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter %q: %v", s, err)
+		}
+		m.re = re
+	}
+	return m, nil
+}
+
+// matches reports whether the labels match. A missing label matches as an
+// empty value, as in Alertmanager.
+func (m *alertmanagerMatcher) matches(labels map[string]string) bool {
+	value := labels[m.name]
+	matched := value == m.value
+	if m.re != nil {
+		matched = m.re.MatchString(value)
+	}
+	return matched != m.negative
+}
+
+// Alertmanager handles GET /api/v2/alerts
+// Returns the active alerts in the shape of the Alertmanager v2 API, so
+// dashboards built for Alertmanager can read ArgusGo unchanged. Supports the
+// filter, receiver, active, silenced and inhibited query parameters.
+// Alerts whose notifications are muted, by a silence or by the quota of
+// their event manager, are suppressed.
+func (h *AlertHandler) Alertmanager(c *fiber.Ctx) error {
+	var matchers []*alertmanagerMatcher
+	for _, filter := range c.Context().QueryArgs().PeekMulti("filter") {
+		m, err := parseAlertmanagerMatcher(string(filter))
+		if err != nil {
+			return ValidationError(c, err.Error())
+		}
+		matchers = append(matchers, m)
+	}
+
+	var receiver *regexp.Regexp
+	if pattern := c.Query("receiver"); pattern != "" {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return ValidationError(c, "invalid receiver: "+err.Error())
+		}
+		receiver = re
+	}
+
+	showActive := c.QueryBool("active", true)
+	showSilenced := c.QueryBool("silenced", true)
+	showInhibited := c.QueryBool("inhibited", true)
+
+	alerts, err := h.repo.List(c.Context(), domain.AlertFilter{Status: domain.AlertStatusActive})
+	if err != nil {
+		h.logger.Error("failed to list alerts", "error", err)
+		return InternalError(c, "failed to list alerts")
+	}
+
+	resp := make([]alertmanagerAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		if alert.IsChild() && !showInhibited || alert.IsParent() && !showActive {
+			continue
+		}
+		if receiver != nil && !receiver.MatchString(alert.EventManagerID) {
+			continue
+		}
+		status, silenceID := h.muter.Status(alert)
+		if status.IsMuted() && !showSilenced {
+			continue
+		}
+
+		am := toAlertmanagerAlert(alert, status, silenceID)
+		matched := true
+		for _, m := range matchers {
+			if !m.matches(am.Labels) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			resp = append(resp, am)
+		}
+	}
+
+	// Alertmanager returns a bare array, without the ArgusGo envelope
+	return c.JSON(resp)
+}
+
+// toAlertmanagerAlert maps an alert to the Alertmanager v2 shape. Alert
+// fields become labels, with the summary as alertname. Muted alerts are
+// suppressed, and silenced ones silenced by their silence.
+func toAlertmanagerAlert(alert *domain.Alert, status domain.AlertStatus, silenceID string) alertmanagerAlert {
+	labels := map[string]string{
+		"alertname":        alert.Summary,
+		"dedupKey":         alert.DedupKey,
+		"event_manager_id": alert.EventManagerID,
+		"severity":         string(alert.Severity),
+		"type":             string(alert.Type),
+	}
+	if alert.Class != "" {
+		labels["class"] = alert.Class
+	}
+
+	annotations := map[string]string{"summary": alert.Summary}
+	if alert.AcknowledgedAt != nil {
+		annotations["acknowledged_at"] = alert.AcknowledgedAt.Format(time.RFC3339)
+	}
+
+	amStatus := alertmanagerStatus{State: "active", SilencedBy: []string{}, InhibitedBy: []string{}}
+	if alert.IsChild() {
+		labels["parent_dedupKey"] = alert.ParentDedupKey
+		amStatus.State = "suppressed"
+		amStatus.InhibitedBy = []string{alertmanagerFingerprint(alert.ParentDedupKey)}
+	}
+	if status.IsMuted() {
+		amStatus.State = "suppressed"
+	}
+	if silenceID != "" {
+		amStatus.SilencedBy = []string{silenceID}
+	}
+
+	return alertmanagerAlert{
+		Labels:      labels,
+		Annotations: annotations,
+		StartsAt:    alert.CreatedAt,
+		EndsAt:      alert.UpdatedAt.Add(alertmanagerResolveTimeout),
+		UpdatedAt:   alert.UpdatedAt,
+		Fingerprint: alertmanagerFingerprint(alert.DedupKey),
+		Receivers:   []alertmanagerReceiver{{Name: alert.EventManagerID}},
+		Status:      amStatus,
+	}
+}
+
+// alertmanagerFingerprint identifies an alert by its dedup key, as 16 hex
+// digits like Alertmanager fingerprints.
+func alertmanagerFingerprint(dedupKey string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(dedupKey))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
@@ -0,0 +1,67 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/pkg/argustest"
+)
+
+func TestAlertmanagerAlerts(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	ingest(t, h, emID, domain.ActionTrigger, "storage", "am-1", "am-2", "am-3")
+	h.AwaitStatus(t, "am-3", domain.AlertStatusActive)
+
+	type alertmanagerAlert struct {
+		Labels      map[string]string `json:"labels"`
+		StartsAt    time.Time         `json:"startsAt"`
+		Fingerprint string            `json:"fingerprint"`
+		Status      struct {
+			State       string   `json:"state"`
+			InhibitedBy []string `json:"inhibitedBy"`
+		} `json:"status"`
+	}
+	getAlerts := func(query string) []alertmanagerAlert {
+		t.Helper()
+		var alerts []alertmanagerAlert
+		if status := call(t, h, http.MethodGet, "/api/v2/alerts"+query, "", &alerts); status != http.StatusOK {
+			t.Fatalf("GET /api/v2/alerts%s status = %d, want 200", query, status)
+		}
+		return alerts
+	}
+
+	alerts := getAlerts("")
+	if len(alerts) != 3 {
+		t.Fatalf("GET /api/v2/alerts = %d alerts, want 3", len(alerts))
+	}
+	fingerprints := make(map[string]string)
+	for _, alert := range alerts {
+		fingerprints[alert.Labels["dedupKey"]] = alert.Fingerprint
+		if alert.StartsAt.IsZero() || alert.Labels["severity"] != string(domain.SeverityHigh) {
+			t.Errorf("alert = %+v, want startsAt and severity label", alert)
+		}
+	}
+	for _, alert := range alerts {
+		if alert.Labels["type"] == string(domain.AlertTypeChild) &&
+			(alert.Status.State != "suppressed" || len(alert.Status.InhibitedBy) != 1 || alert.Status.InhibitedBy[0] != fingerprints["am-1"]) {
+			t.Errorf("child status = %+v, want suppressed by the parent", alert.Status)
+		}
+	}
+
+	if alerts := getAlerts("?inhibited=false"); len(alerts) != 1 || alerts[0].Labels["dedupKey"] != "am-1" {
+		t.Errorf("uninhibited alerts = %+v, want the parent only", alerts)
+	}
+	if alerts := getAlerts(`?filter=dedupKey%3D~%22am-%5B23%5D%22`); len(alerts) != 2 {
+		t.Errorf("filtered alerts = %d, want 2", len(alerts))
+	}
+	if alerts := getAlerts("?receiver=other"); len(alerts) != 0 {
+		t.Errorf("alerts of another receiver = %d, want 0", len(alerts))
+	}
+	if status := call(t, h, http.MethodGet, "/api/v2/alerts?filter=bad", "", nil); status != http.StatusBadRequest {
+		t.Errorf("GET with an invalid filter status = %d, want 400", status)
+	}
+}
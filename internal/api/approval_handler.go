@@ -0,0 +1,138 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"argus-go/internal/approval"
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// ActorHeader names who makes a request, for the approval of destructive
+// operations and the audit log.
+const ActorHeader = "X-Argus-Actor"
+
+// defaultAuditLimit is the number of audit log entries returned by default.
+const defaultAuditLimit = 100
+
+// ApprovalHandler handles HTTP requests for the approvals of destructive
+// operations and the audit log.
+type ApprovalHandler struct {
+	gate   *approval.Gate
+	audit  store.AuditLogRepository
+	logger *slog.Logger
+}
+
+// NewApprovalHandler creates a new approval handler.
+func NewApprovalHandler(gate *approval.Gate, audit store.AuditLogRepository, logger *slog.Logger) *ApprovalHandler {
+	return &ApprovalHandler{
+		gate:   gate,
+		audit:  audit,
+		logger: logger,
+	}
+}
+
+// List handles GET /v1/admin/approvals
+// Returns the operations held for approval on this replica, newest first.
+func (h *ApprovalHandler) List(c *fiber.Ctx) error {
+	return Success(c, h.gate.List())
+}
+
+// GetByID handles GET /v1/admin/approvals/:id
+func (h *ApprovalHandler) GetByID(c *fiber.Ctx) error {
+	approval, err := h.gate.Get(c.Params("id"))
+	if err != nil {
+		return NotFound(c, err.Error())
+	}
+	return Success(c, approval)
+}
+
+// Approve handles POST /v1/admin/approvals/:id/approve
+// Runs a held operation, approved by the actor of the request, who must not
+// be its requester. Returns the approval with the outcome of the operation.
+func (h *ApprovalHandler) Approve(c *fiber.Ctx) error {
+	approval, err := h.gate.Approve(c.Context(), c.Params("id"), c.Get(ActorHeader))
+	if err != nil {
+		return h.decisionError(c, err)
+	}
+	return Success(c, approval)
+}
+
+// Cancel handles POST /v1/admin/approvals/:id/cancel
+// Drops a held operation without running it.
+func (h *ApprovalHandler) Cancel(c *fiber.Ctx) error {
+	approval, err := h.gate.Cancel(c.Context(), c.Params("id"), c.Get(ActorHeader))
+	if err != nil {
+		return h.decisionError(c, err)
+	}
+	return Success(c, approval)
+}
+
+// Audit handles GET /v1/admin/audit
+// Returns the most recent audit log entries, newest first. Accepts limit
+// (default 100).
+func (h *ApprovalHandler) Audit(c *fiber.Ctx) error {
+	limit := defaultAuditLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = l
+	}
+
+	entries, err := h.audit.List(c.Context(), limit)
+	if err != nil {
+		h.logger.Error("failed to list audit log", "error", err)
+		return InternalError(c, "failed to list audit log")
+	}
+	return Success(c, entries)
+}
+
+// ExportAudit handles GET /v1/admin/audit/export
+// Returns every audit log entry, oldest first, with the hashes that chain
+// them, to verify elsewhere.
+func (h *ApprovalHandler) ExportAudit(c *fiber.Ctx) error {
+	entries, err := h.audit.Export(c.Context())
+	if err != nil {
+		h.logger.Error("failed to export audit log", "error", err)
+		return InternalError(c, "failed to export audit log")
+	}
+	return Success(c, entries)
+}
+
+// VerifyAudit handles GET /v1/admin/audit/verify
+// Verifies the chain of the audit log. Returns whether it is intact, its
+// head hash and, if not, the first entry that fails.
+func (h *ApprovalHandler) VerifyAudit(c *fiber.Ctx) error {
+	entries, err := h.audit.Export(c.Context())
+	if err != nil {
+		h.logger.Error("failed to export audit log", "error", err)
+		return InternalError(c, "failed to verify audit log")
+	}
+	return Success(c, domain.VerifyAuditLog(entries))
+}
+
+// decisionError maps an error approving or canceling an operation to a
+// response.
+func (h *ApprovalHandler) decisionError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrApprovalActor):
+		return DomainError(c, fmt.Errorf("%s header: %w", ActorHeader, err))
+	case isDomainError(err):
+		return DomainError(c, err)
+	}
+	h.logger.Error("failed to decide approval", "id", c.Params("id"), "error", err)
+	return InternalError(c, "failed to decide approval")
+}
+
+// requestApproval holds a destructive operation for approval and responds
+// 202 Accepted with the pending approval.
+func requestApproval(c *fiber.Ctx, gate *approval.Gate, operation, target string, run approval.Operation) error {
+	pending, err := gate.Request(c.Context(), operation, target, c.Get(ActorHeader), run)
+	if err != nil {
+		return BadRequest(c, ActorHeader+" header: "+err.Error())
+	}
+	return Accepted(c, pending)
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+
+	"argus-go/internal/chaos"
+)
+
+// ChaosHandler handles HTTP requests for the fault-injection admin API.
+// It is only registered in binaries built with the "chaos" build tag.
+type ChaosHandler struct {
+	injector *chaos.Injector
+	logger   *slog.Logger
+}
+
+// NewChaosHandler creates a new chaos handler.
+func NewChaosHandler(injector *chaos.Injector, logger *slog.Logger) *ChaosHandler {
+	return &ChaosHandler{
+		injector: injector,
+		logger:   logger,
+	}
+}
+
+// List handles GET /v1/admin/chaos
+// Returns the faults currently injected per target.
+func (h *ChaosHandler) List(c *fiber.Ctx) error {
+	return Success(c, h.injector.All())
+}
+
+// Set handles PUT /v1/admin/chaos/:target
+// Replaces the faults injected into a target.
+func (h *ChaosHandler) Set(c *fiber.Ctx) error {
+	target := chaos.Target(c.Params("target"))
+
+	var faults chaos.Faults
+	if err := c.BodyParser(&faults); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+
+	if err := h.injector.Set(target, faults); err != nil {
+		if errors.Is(err, chaos.ErrUnknownTarget) {
+			return NotFound(c, err.Error())
+		}
+		return ValidationError(c, err.Error())
+	}
+
+	h.logger.Warn("chaos faults updated",
+		"target", target,
+		"latency", faults.Latency,
+		"errorRate", faults.ErrorRate,
+		"dropRate", faults.DropRate,
+	)
+	return Success(c, faults)
+}
+
+// Reset handles DELETE /v1/admin/chaos
+// Clears all injected faults.
+func (h *ChaosHandler) Reset(c *fiber.Ctx) error {
+	h.injector.Reset()
+	h.logger.Warn("chaos faults reset")
+	return NoContent(c)
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+
+	"argus-go/internal/config"
+)
+
+// compression returns middleware that compresses responses whose content type
+// matches one of the configured types, using the encoding negotiated from the
+// request's Accept-Encoding header. Small bodies are sent as is.
+func compression(cfg config.CompressionConfig) fiber.Handler {
+	brotliLevel, gzipLevel := fasthttp.CompressBrotliDefaultCompression, fasthttp.CompressDefaultCompression
+	switch cfg.Level {
+	case "best_speed":
+		brotliLevel, gzipLevel = fasthttp.CompressBrotliBestSpeed, fasthttp.CompressBestSpeed
+	case "best_compression":
+		brotliLevel, gzipLevel = fasthttp.CompressBrotliBestCompression, fasthttp.CompressBestCompression
+	}
+	compress := fasthttp.CompressHandlerBrotliLevel(func(*fasthttp.RequestCtx) {}, brotliLevel, gzipLevel)
+
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		contentType := string(c.Response().Header.ContentType())
+		for _, t := range cfg.ContentTypes {
+			if strings.HasPrefix(contentType, t) {
+				compress(c.Context())
+				break
+			}
+		}
+		return nil
+	}
+}
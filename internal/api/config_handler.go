@@ -0,0 +1,69 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+
+	"argus-go/internal/declarative"
+)
+
+// ConfigHandler handles HTTP requests for exporting and applying the
+// configuration as a declarative YAML document.
+type ConfigHandler struct {
+	service *declarative.Service
+	logger  *slog.Logger
+}
+
+// NewConfigHandler creates a new config handler.
+func NewConfigHandler(service *declarative.Service, logger *slog.Logger) *ConfigHandler {
+	return &ConfigHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Export handles GET /v1/config/export
+// Returns the grouping rules and event managers as a YAML document.
+func (h *ConfigHandler) Export(c *fiber.Ctx) error {
+	doc, err := h.service.Export(c.Context())
+	if err != nil {
+		h.logger.Error("failed to export config", "error", err)
+		return InternalError(c, "failed to export config")
+	}
+
+	data, err := doc.Marshal()
+	if err != nil {
+		h.logger.Error("failed to encode config", "error", err)
+		return InternalError(c, "failed to export config")
+	}
+
+	c.Set(fiber.HeaderContentType, "application/yaml")
+	return c.Send(data)
+}
+
+// Apply handles PUT /v1/admin/config
+// Creates and updates resources to match a YAML document and returns the
+// changes made. With ?dry_run=true, only returns the changes that would be made.
+func (h *ConfigHandler) Apply(c *fiber.Ctx) error {
+	doc, err := declarative.Parse(c.Body())
+	if err != nil {
+		h.logger.Debug("invalid config document", "error", err)
+		return ValidationError(c, err.Error())
+	}
+
+	plan, err := h.service.Apply(c.Context(), doc, c.QueryBool("dry_run"))
+	if err != nil {
+		if errors.Is(err, declarative.ErrInvalidDocument) {
+			return ValidationError(c, err.Error())
+		}
+		if errors.Is(err, declarative.ErrDeletedResource) {
+			return Conflict(c, err.Error())
+		}
+		h.logger.Error("failed to apply config", "error", err)
+		return InternalError(c, "failed to apply config")
+	}
+
+	return Success(c, plan)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"argus-go/internal/config"
+)
+
+// newCORSTestApp returns an app serving GET /v1/alerts behind the
+// middleware of a server with cfg.
+func newCORSTestApp(cfg config.CORSConfig) *fiber.App {
+	s := &Server{config: &config.ServerConfig{CORS: cfg}, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	app := fiber.New()
+	s.registerMiddleware(app)
+	app.Get("/v1/alerts", func(c *fiber.Ctx) error { return c.SendString("[]") })
+	return app
+}
+
+func TestCORS(t *testing.T) {
+	enabled := config.CORSConfig{
+		Enabled:          true,
+		AllowOrigins:     []string{"https://dashboard.example.com"},
+		AllowMethods:     []string{"GET", "POST"},
+		AllowHeaders:     []string{"Content-Type"},
+		ExposeHeaders:    []string{"ETag"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+
+	tests := []struct {
+		name       string
+		cfg        config.CORSConfig
+		method     string
+		origin     string
+		wantOrigin string
+		wantStatus int
+	}{
+		{"preflight", enabled, http.MethodOptions, "https://dashboard.example.com", "https://dashboard.example.com", http.StatusNoContent},
+		{"request", enabled, http.MethodGet, "https://dashboard.example.com", "https://dashboard.example.com", http.StatusOK},
+		{"other origin", enabled, http.MethodGet, "https://evil.example.com", "", http.StatusOK},
+		{"disabled", config.CORSConfig{}, http.MethodGet, "https://dashboard.example.com", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/v1/alerts", nil)
+			req.Header.Set(fiber.HeaderOrigin, tt.origin)
+			if tt.method == http.MethodOptions {
+				req.Header.Set(fiber.HeaderAccessControlRequestMethod, http.MethodGet)
+			}
+			resp, err := newCORSTestApp(tt.cfg).Test(req)
+			if err != nil {
+				t.Fatalf("request error: %v", err)
+			}
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := resp.Header.Get(fiber.HeaderAccessControlAllowOrigin); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if tt.wantOrigin == "" {
+				return
+			}
+			if got := resp.Header.Get(fiber.HeaderAccessControlAllowCredentials); got != "true" {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want true", got)
+			}
+			if tt.method == http.MethodOptions {
+				if got := resp.Header.Get(fiber.HeaderAccessControlAllowMethods); got != "GET,POST" {
+					t.Errorf("Access-Control-Allow-Methods = %q, want GET,POST", got)
+				}
+				if got := resp.Header.Get(fiber.HeaderAccessControlMaxAge); got != "600" {
+					t.Errorf("Access-Control-Max-Age = %q, want 600", got)
+				}
+			} else if got := resp.Header.Get(fiber.HeaderAccessControlExposeHeaders); got != "ETag" {
+				t.Errorf("Access-Control-Expose-Headers = %q, want ETag", got)
+			}
+		})
+	}
+}
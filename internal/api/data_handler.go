@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+
+	"argus-go/internal/approval"
+	"argus-go/internal/domain"
+	"argus-go/internal/erasure"
+)
+
+// DataHandler handles HTTP requests to delete the data of event managers.
+type DataHandler struct {
+	deleter   *erasure.Deleter
+	approvals *approval.Gate
+	logger    *slog.Logger
+}
+
+// NewDataHandler creates a new data handler.
+func NewDataHandler(deleter *erasure.Deleter, approvals *approval.Gate, logger *slog.Logger) *DataHandler {
+	return &DataHandler{
+		deleter:   deleter,
+		approvals: approvals,
+		logger:    logger,
+	}
+}
+
+// Delete handles DELETE /v1/admin/data?event_manager_id=...
+// Removes every alert, notification, remediation, feedback and usage record
+// and archived event of an event manager, including alerts already expired
+// by retention, and returns a report of what was removed. The event manager
+// need not exist anymore. With approvals enabled, the deletion is held and
+// 202 Accepted is returned with the pending approval.
+func (h *DataHandler) Delete(c *fiber.Ctx) error {
+	id := c.Query("event_manager_id")
+	if id == "" {
+		return BadRequest(c, "event_manager_id is required")
+	}
+
+	if h.approvals.Enabled() {
+		return requestApproval(c, h.approvals, domain.OperationDeleteData, id, func(ctx context.Context) (string, error) {
+			report, err := h.deleter.Delete(ctx, id)
+			if err != nil {
+				return "", err
+			}
+			return report.String(), nil
+		})
+	}
+
+	report, err := h.deleter.Delete(c.Context(), id)
+	if err != nil {
+		h.logger.Error("failed to delete data of event manager", "event_manager_id", id, "report", report.String(), "error", err)
+		return InternalError(c, "failed to delete data of event manager; the deletion can be retried")
+	}
+
+	h.approvals.Executed(c.Context(), domain.OperationDeleteData, id, c.Get(ActorHeader), report.String())
+	return Success(c, report)
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"argus-go/internal/domain"
+)
+
+// errorResponse is the status and code of an error response.
+type errorResponse struct {
+	status int
+	code   string
+}
+
+// domainErrors maps the kinds of domain errors to their response. Every
+// handler answers the errors of a kind the same way, with the same code.
+var domainErrors = map[domain.ErrorKind]errorResponse{
+	domain.ErrorKindNotFound:          {fiber.StatusNotFound, ErrCodeNotFound},
+	domain.ErrorKindConflict:          {fiber.StatusConflict, ErrCodeConflict},
+	domain.ErrorKindInvalid:           {fiber.StatusBadRequest, ErrCodeValidationFailed},
+	domain.ErrorKindInvalidTransition: {fiber.StatusConflict, ErrCodeInvalidTransition},
+}
+
+// isDomainError reports whether err wraps a domain error DomainError can
+// answer. Other errors are failures the handler logs and answers with an
+// internal error.
+func isDomainError(err error) bool {
+	_, ok := domainErrors[domain.KindOf(err)]
+	return ok
+}
+
+// DomainError sends the error response of a domain error, by its kind, with
+// the message of err. Errors that aren't domain errors are answered with an
+// internal error without their message, which may expose internals.
+func DomainError(c *fiber.Ctx, err error) error {
+	resp, ok := domainErrors[domain.KindOf(err)]
+	if !ok {
+		return InternalError(c, "internal error")
+	}
+	return Error(c, resp.status, resp.code, err.Error())
+}
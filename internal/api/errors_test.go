@@ -0,0 +1,43 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/pkg/argustest"
+)
+
+func TestErrorCodes(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+	ingest(t, h, emID, domain.ActionTrigger, "disk", "disk-1")
+	ingest(t, h, emID, domain.ActionResolve, "disk", "disk-1")
+	h.AwaitStatus(t, "disk-1", domain.AlertStatusResolved)
+
+	// Every kind of domain error maps to one status and code, whatever the
+	// resource
+	tests := []struct {
+		name, method, path, body string
+		wantStatus               int
+		wantCode                 string
+	}{
+		{"missing alert", http.MethodGet, "/v1/alerts/missing", "", http.StatusNotFound, "NOT_FOUND"},
+		{"missing event manager", http.MethodGet, "/v1/event-managers/missing", "", http.StatusNotFound, "NOT_FOUND"},
+		{"missing silence", http.MethodDelete, "/v1/silences/missing", "", http.StatusNotFound, "NOT_FOUND"},
+		{"taken id", http.MethodPost, "/v1/event-managers/" + emID + "/clone", `{"id":"` + emID + `","name":"Copy"}`, http.StatusConflict, "CONFLICT"},
+		{"purge of an active event manager", http.MethodDelete, "/v1/admin/event-managers/" + emID, "", http.StatusConflict, "INVALID_STATE_TRANSITION"},
+		{"acknowledge of a resolved alert", http.MethodPost, "/v1/alerts/disk-1/acknowledge", "", http.StatusConflict, "INVALID_STATE_TRANSITION"},
+		{"invalid grouping rule", http.MethodPost, "/v1/grouping-rules", `{"grouping_key":"class","time_window_minutes":5}`, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"invalid alert sort", http.MethodGet, "/v1/alerts/disk-1/children?sort=name", "", http.StatusBadRequest, "VALIDATION_FAILED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result errorCode
+			if status := call(t, h, tt.method, tt.path, tt.body, &result); status != tt.wantStatus || result.Error.Code != tt.wantCode {
+				t.Errorf("%s %s = (%d, %s), want (%d, %s)", tt.method, tt.path, status, result.Error.Code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
@@ -1,27 +1,62 @@
 package api
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
+	"argus-go/internal/approval"
+	"argus-go/internal/clone"
 	"argus-go/internal/domain"
+	"argus-go/internal/notification"
+	"argus-go/internal/processor"
 	"argus-go/internal/store"
+	"argus-go/internal/testalert"
 )
 
 // EventManagerHandler handles HTTP requests for event manager operations.
 type EventManagerHandler struct {
-	repo   store.EventManagerRepository
-	logger *slog.Logger
+	repo             store.EventManagerRepository
+	groupingRuleRepo store.GroupingRuleRepository
+	processor        *processor.Service
+	tester           *notification.Tester
+	testAlerts       *testalert.Generator
+	cloner           *clone.Cloner
+	approvals        *approval.Gate
+	logger           *slog.Logger
 }
 
 // NewEventManagerHandler creates a new event manager handler.
-func NewEventManagerHandler(repo store.EventManagerRepository, logger *slog.Logger) *EventManagerHandler {
+// The grouping rule repository validates grouping_rule_id references, and the
+// processor resolves and purges the alerts of deleted event managers. The
+// tester sends test notifications, testAlerts raises test alerts, nil if
+// they are disabled, the cloner copies event managers with their
+// configuration, and the approval gate holds purges for approval.
+func NewEventManagerHandler(
+	repo store.EventManagerRepository,
+	groupingRuleRepo store.GroupingRuleRepository,
+	processor *processor.Service,
+	tester *notification.Tester,
+	testAlerts *testalert.Generator,
+	cloner *clone.Cloner,
+	approvals *approval.Gate,
+	logger *slog.Logger,
+) *EventManagerHandler {
 	return &EventManagerHandler{
-		repo:   repo,
-		logger: logger,
+		repo:             repo,
+		groupingRuleRepo: groupingRuleRepo,
+		processor:        processor,
+		tester:           tester,
+		testAlerts:       testAlerts,
+		cloner:           cloner,
+		approvals:        approvals,
+		logger:           logger,
 	}
 }
 
@@ -40,14 +75,47 @@ func (h *EventManagerHandler) Create(c *fiber.Ctx) error {
 		return ValidationError(c, err.Error())
 	}
 
-	// Generate ID and create the event manager
-	id := uuid.New().String()
-	em := req.ToEventManager(id)
+	// Use the client-supplied ID, if any, so creates can be retried safely:
+	// repeating a create with the same configuration returns the existing one
+	id := req.ID
+	if id == "" {
+		id = uuid.New().String()
+	} else {
+		existing, err := h.repo.GetByID(c.Context(), id)
+		if err == nil {
+			if existing.IsDeleted() {
+				return DomainError(c, domain.ErrEventManagerDeleted)
+			}
+			if !req.Matches(existing) {
+				return DomainError(c, domain.ErrResourceMismatch)
+			}
+			return Success(c, existing)
+		}
+		if !errors.Is(err, domain.ErrEventManagerNotFound) {
+			h.logger.Error("failed to get event manager", "id", id, "error", err)
+			return InternalError(c, "failed to get event manager")
+		}
+	}
+
+	return h.create(c, req.ToEventManager(id))
+}
+
+// create verifies the grouping rules of a new event manager, persists it and
+// responds with 201 Created.
+func (h *EventManagerHandler) create(c *fiber.Ctx, em *domain.EventManager) error {
+	// Verify the referenced grouping rules
+	if ruleID, err := h.checkGroupingRules(c.Context(), em.GroupingRuleIDs()); err != nil {
+		if errors.Is(err, domain.ErrGroupingRuleNotFound) || errors.Is(err, domain.ErrGroupingRuleDeleted) {
+			return ValidationError(c, "grouping_rule_id "+ruleID+": "+err.Error())
+		}
+		h.logger.Error("failed to get grouping rule", "id", ruleID, "error", err)
+		return InternalError(c, "failed to get grouping rule")
+	}
 
 	// Persist to repository
 	if err := h.repo.Create(c.Context(), em); err != nil {
-		if errors.Is(err, domain.ErrEventManagerAlreadyExists) {
-			return Conflict(c, "event manager already exists")
+		if isDomainError(err) {
+			return DomainError(c, err)
 		}
 		h.logger.Error("failed to create event manager", "error", err)
 		return InternalError(c, "failed to create event manager")
@@ -58,7 +126,7 @@ func (h *EventManagerHandler) Create(c *fiber.Ctx) error {
 }
 
 // List handles GET /v1/event-managers
-// Returns all event managers.
+// Returns all event managers that are not deleted.
 func (h *EventManagerHandler) List(c *fiber.Ctx) error {
 	eventManagers, err := h.repo.List(c.Context())
 	if err != nil {
@@ -66,11 +134,13 @@ func (h *EventManagerHandler) List(c *fiber.Ctx) error {
 		return InternalError(c, "failed to list event managers")
 	}
 
-	return Success(c, eventManagers)
+	return SuccessWithLastModified(c, eventManagers, latestUpdate(eventManagers, func(em *domain.EventManager) time.Time {
+		return em.UpdatedAt
+	}))
 }
 
 // GetByID handles GET /v1/event-managers/:id
-// Returns a single event manager by ID.
+// Returns a single event manager by ID, including deleted ones.
 func (h *EventManagerHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
@@ -79,18 +149,19 @@ func (h *EventManagerHandler) GetByID(c *fiber.Ctx) error {
 
 	em, err := h.repo.GetByID(c.Context(), id)
 	if err != nil {
-		if errors.Is(err, domain.ErrEventManagerNotFound) {
-			return NotFound(c, "event manager not found")
+		if isDomainError(err) {
+			return DomainError(c, err)
 		}
 		h.logger.Error("failed to get event manager", "id", id, "error", err)
 		return InternalError(c, "failed to get event manager")
 	}
 
-	return Success(c, em)
+	return SuccessWithLastModified(c, em, em.UpdatedAt)
 }
 
 // Update handles PUT /v1/event-managers/:id
-// Updates an existing event manager.
+// Updates an existing event manager, or creates it with the given ID if it
+// does not exist.
 func (h *EventManagerHandler) Update(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
@@ -113,14 +184,40 @@ func (h *EventManagerHandler) Update(c *fiber.Ctx) error {
 	em, err := h.repo.GetByID(c.Context(), id)
 	if err != nil {
 		if errors.Is(err, domain.ErrEventManagerNotFound) {
-			return NotFound(c, "event manager not found")
+			// PUT creates missing event managers, for declarative tooling
+			if err := domain.ValidateResourceID(id); err != nil {
+				return ValidationError(c, err.Error())
+			}
+			// Copy the ID, which refers to Fiber's reused request buffer
+			em = &domain.EventManager{ID: strings.Clone(id), IngestToken: domain.NewIngestToken()}
+			req.ApplyTo(em)
+			em.CreatedAt = em.UpdatedAt
+			return h.create(c, em)
 		}
 		h.logger.Error("failed to get event manager", "id", id, "error", err)
 		return InternalError(c, "failed to get event manager")
 	}
+	if em.IsDeleted() {
+		return DomainError(c, domain.ErrEventManagerDeleted)
+	}
 
-	// Apply updates
-	req.ApplyTo(em)
+	// Verify the grouping rules that are newly referenced
+	updated := *em
+	req.ApplyTo(&updated)
+	var added []string
+	for _, ruleID := range updated.GroupingRuleIDs() {
+		if !em.UsesGroupingRule(ruleID) {
+			added = append(added, ruleID)
+		}
+	}
+	if ruleID, err := h.checkGroupingRules(c.Context(), added); err != nil {
+		if errors.Is(err, domain.ErrGroupingRuleNotFound) || errors.Is(err, domain.ErrGroupingRuleDeleted) {
+			return ValidationError(c, "grouping_rule_id "+ruleID+": "+err.Error())
+		}
+		h.logger.Error("failed to get grouping rule", "id", ruleID, "error", err)
+		return InternalError(c, "failed to get grouping rule")
+	}
+	em = &updated
 
 	// Persist changes
 	if err := h.repo.Update(c.Context(), em); err != nil {
@@ -133,21 +230,262 @@ func (h *EventManagerHandler) Update(c *fiber.Ctx) error {
 }
 
 // Delete handles DELETE /v1/event-managers/:id
-// Deletes an event manager.
+// Soft-deletes an event manager, rejecting further events for it, and
+// resolves its open alerts. Deleting an already deleted event manager
+// retries the alert resolution.
 func (h *EventManagerHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
 		return BadRequest(c, "id is required")
 	}
 
-	if err := h.repo.Delete(c.Context(), id); err != nil {
-		if errors.Is(err, domain.ErrEventManagerNotFound) {
-			return NotFound(c, "event manager not found")
+	em, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
 		}
-		h.logger.Error("failed to delete event manager", "id", id, "error", err)
-		return InternalError(c, "failed to delete event manager")
+		h.logger.Error("failed to get event manager", "id", id, "error", err)
+		return InternalError(c, "failed to get event manager")
+	}
+
+	// Delete first so no new alerts are opened while resolving
+	if !em.IsDeleted() {
+		if err := h.repo.Delete(c.Context(), id); err != nil && !errors.Is(err, domain.ErrEventManagerNotFound) {
+			h.logger.Error("failed to delete event manager", "id", id, "error", err)
+			return InternalError(c, "failed to delete event manager")
+		}
+	}
+
+	resolved, err := h.processor.ResolveEventManagerAlerts(c.Context(), id)
+	if err != nil {
+		h.logger.Error("failed to resolve alerts of deleted event manager", "id", id, "error", err)
+		return InternalError(c, "event manager deleted but failed to resolve its alerts")
 	}
 
-	h.logger.Info("deleted event manager", "id", id)
+	h.logger.Info("deleted event manager", "id", id, "resolvedAlerts", resolved)
 	return NoContent(c)
 }
+
+// Purge handles DELETE /v1/admin/event-managers/:id
+// Permanently removes a deleted event manager together with all of its alerts.
+// With approvals enabled, the purge is held and 202 Accepted is returned with
+// the pending approval.
+func (h *EventManagerHandler) Purge(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	em, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get event manager", "id", id, "error", err)
+		return InternalError(c, "failed to get event manager")
+	}
+	if !em.IsDeleted() {
+		return DomainError(c, domain.ErrEventManagerNotDeleted)
+	}
+
+	if h.approvals.Enabled() {
+		return requestApproval(c, h.approvals, domain.OperationPurgeEventManager, id, func(ctx context.Context) (string, error) {
+			return h.purge(ctx, id)
+		})
+	}
+
+	result, err := h.purge(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to purge event manager", "id", id, "error", err)
+		return InternalError(c, "failed to purge event manager")
+	}
+
+	h.approvals.Executed(c.Context(), domain.OperationPurgeEventManager, id, c.Get(ActorHeader), result)
+	return NoContent(c)
+}
+
+// purge removes an event manager and all of its alerts, and describes what
+// it removed.
+func (h *EventManagerHandler) purge(ctx context.Context, id string) (string, error) {
+	purged, err := h.processor.PurgeEventManagerAlerts(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("failed to purge alerts: %w", err)
+	}
+
+	if err := h.repo.Purge(ctx, id); err != nil {
+		return "", err
+	}
+
+	h.logger.Warn("purged event manager", "id", id, "purgedAlerts", purged)
+	return fmt.Sprintf("purged event manager and %d alerts", purged), nil
+}
+
+// testNotificationResponse is the body returned by
+// POST /v1/event-managers/:id/test-notification.
+type testNotificationResponse struct {
+	EventManagerID string                        `json:"event_manager_id"`
+	Delivered      bool                          `json:"delivered"`
+	Results        []notification.DeliveryResult `json:"results"`
+}
+
+// TestNotification handles POST /v1/event-managers/:id/test-notification
+// Sends a synthetic notification through every configured channel and returns
+// the delivery result per channel.
+func (h *EventManagerHandler) TestNotification(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	em, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get event manager", "id", id, "error", err)
+		return InternalError(c, "failed to get event manager")
+	}
+	if em.IsDeleted() {
+		return DomainError(c, domain.ErrEventManagerDeleted)
+	}
+
+	results, err := h.tester.Test(c.Context(), em)
+	if err != nil {
+		if errors.Is(err, notification.ErrNoChannels) {
+			return ValidationError(c, err.Error())
+		}
+		h.logger.Error("failed to send test notification", "id", id, "error", err)
+		return InternalError(c, "failed to send test notification")
+	}
+
+	resp := testNotificationResponse{EventManagerID: em.ID, Delivered: true, Results: results}
+	for _, result := range results {
+		resp.Delivered = resp.Delivered && result.Delivered
+	}
+	return Success(c, resp)
+}
+
+// TestAlert handles POST /v1/event-managers/:id/test-alert
+// Raises a synthetic alert, tagged argus_test, that goes through grouping and
+// notification like any other and is resolved and deleted once its TTL has
+// passed. Accepts an optional body with severity and ttl. Returns 202
+// Accepted with the dedup key and expiry of the alert.
+func (h *EventManagerHandler) TestAlert(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	var req domain.TestAlertRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return BadRequest(c, "invalid request body")
+		}
+	}
+
+	em, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get event manager", "id", id, "error", err)
+		return InternalError(c, "failed to get event manager")
+	}
+	if em.IsDeleted() {
+		return DomainError(c, domain.ErrEventManagerDeleted)
+	}
+
+	testAlert, err := h.testAlerts.Create(c.Context(), em.ID, req.Severity, time.Duration(req.TTL))
+	if err != nil {
+		switch {
+		case errors.Is(err, testalert.ErrInvalidTTL):
+			return ValidationError(c, err.Error())
+		case isDomainError(err):
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to raise test alert", "id", id, "error", err)
+		return InternalError(c, "failed to raise test alert")
+	}
+	return Accepted(c, testAlert)
+}
+
+// RotateIngestToken handles POST /v1/event-managers/:id/ingest-token
+// Replaces the ingest token of an event manager; the previous token stops
+// working immediately. Returns the updated event manager.
+func (h *EventManagerHandler) RotateIngestToken(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	em, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get event manager", "id", id, "error", err)
+		return InternalError(c, "failed to get event manager")
+	}
+	if em.IsDeleted() {
+		return DomainError(c, domain.ErrEventManagerDeleted)
+	}
+
+	em.IngestToken = domain.NewIngestToken()
+	em.UpdatedAt = time.Now().UTC()
+	if err := h.repo.Update(c.Context(), em); err != nil {
+		h.logger.Error("failed to rotate ingest token", "id", id, "error", err)
+		return InternalError(c, "failed to rotate ingest token")
+	}
+
+	h.logger.Info("rotated ingest token", "id", em.ID)
+	return Success(c, em)
+}
+
+// Clone handles POST /v1/event-managers/:id/clone
+// Copies an event manager under a new name, with copies of its grouping
+// rules, its silences that are not expired and the routing rules targeting
+// it. Returns 201 Created with the copies.
+func (h *EventManagerHandler) Clone(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	var req domain.CloneEventManagerRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+	if err := req.Validate(); err != nil {
+		h.logger.Debug("validation failed", "error", err)
+		return ValidationError(c, err.Error())
+	}
+
+	result, err := h.cloner.Clone(c.Context(), id, &req)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to clone event manager", "id", id, "error", err)
+		return InternalError(c, "failed to clone event manager")
+	}
+	return Created(c, result)
+}
+
+// checkGroupingRules verifies that grouping rules exist and are not deleted.
+// On failure it returns the ID of the offending rule.
+func (h *EventManagerHandler) checkGroupingRules(ctx context.Context, ids []string) (string, error) {
+	for _, id := range ids {
+		rule, err := h.groupingRuleRepo.GetByID(ctx, id)
+		if err != nil {
+			return id, err
+		}
+		if rule.IsDeleted() {
+			return id, domain.ErrGroupingRuleDeleted
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,61 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/pkg/argustest"
+)
+
+func TestEventManagerHandler_TestNotification(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	received := make(chan map[string]any, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decoding the notification: %v", err)
+		}
+		received <- payload
+	}))
+	defer receiver.Close()
+
+	send := func() (int, bool) {
+		t.Helper()
+		var body struct {
+			Data struct {
+				Delivered bool `json:"delivered"`
+			} `json:"data"`
+		}
+		status := call(t, h, http.MethodPost, "/v1/event-managers/"+emID+"/test-notification", "", &body)
+		return status, body.Data.Delivered
+	}
+
+	// Without channels there is nothing to test
+	if status, _ := send(); status != http.StatusBadRequest {
+		t.Errorf("test without channels status = %d, want 400", status)
+	}
+
+	updateEventManager(t, h, emID, func(em *domain.EventManager) {
+		em.NotificationConfig.WebhookURL = receiver.URL
+	})
+
+	status, delivered := send()
+	if status != http.StatusOK || !delivered {
+		t.Fatalf("test notification = (%d, delivered %v), want (200, true)", status, delivered)
+	}
+	if payload := <-received; payload["test"] != true || payload["event_manager_id"] != emID {
+		t.Errorf("payload = %v, want a test notification for %s", payload, emID)
+	}
+
+	// Failed deliveries are reported, not returned as errors
+	receiver.Close()
+	if status, delivered := send(); status != http.StatusOK || delivered {
+		t.Errorf("test notification to a closed receiver = (%d, delivered %v), want (200, false)", status, delivered)
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+	"argus-go/internal/store"
+)
+
+// FeedbackHandler handles HTTP requests for the feedback of responders on
+// alerts, and the alert quality report aggregating it.
+type FeedbackHandler struct {
+	repo             store.AlertFeedbackRepository
+	alertRepo        store.AlertRepository
+	eventManagerRepo store.EventManagerRepository
+	clock            clock.Clock
+	logger           *slog.Logger
+}
+
+// NewFeedbackHandler creates a new feedback handler. The event managers
+// select the grouping rule feedback is counted towards.
+func NewFeedbackHandler(
+	repo store.AlertFeedbackRepository,
+	alertRepo store.AlertRepository,
+	eventManagerRepo store.EventManagerRepository,
+	clk clock.Clock,
+	logger *slog.Logger,
+) *FeedbackHandler {
+	return &FeedbackHandler{
+		repo:             repo,
+		alertRepo:        alertRepo,
+		eventManagerRepo: eventManagerRepo,
+		clock:            clk,
+		logger:           logger,
+	}
+}
+
+// Create handles POST /v1/alerts/:dedupKey/feedback
+// Records a responder's rating of an alert: useful, noisy or wrongly_grouped,
+// with an optional comment. The actor of the request is recorded as its
+// author.
+func (h *FeedbackHandler) Create(c *fiber.Ctx) error {
+	dedupKey := c.Params("dedupKey")
+	if dedupKey == "" {
+		return BadRequest(c, "dedupKey is required")
+	}
+
+	var req domain.AlertFeedbackRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+	if err := req.Validate(); err != nil {
+		return DomainError(c, err)
+	}
+
+	alert, err := h.alertRepo.GetByDedupKey(c.Context(), dedupKey)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get alert", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to get alert")
+	}
+
+	// Feedback on the alerts of a deleted event manager counts towards no
+	// grouping rule
+	em, err := h.eventManagerRepo.GetByID(c.Context(), alert.EventManagerID)
+	if errors.Is(err, domain.ErrEventManagerNotFound) {
+		em, err = &domain.EventManager{ID: alert.EventManagerID}, nil
+	}
+	if err != nil {
+		h.logger.Error("failed to get event manager", "id", alert.EventManagerID, "error", err)
+		return InternalError(c, "failed to get event manager")
+	}
+
+	feedback := req.ToFeedback(alert, em, strings.Clone(c.Get(ActorHeader)), h.clock.Now().UTC())
+	if err := h.repo.Record(c.Context(), feedback); err != nil {
+		h.logger.Error("failed to record alert feedback", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to record alert feedback")
+	}
+	metrics.AlertFeedback.WithLabelValues(feedback.EventManagerID, feedback.GroupingRuleID, string(feedback.Rating)).Inc()
+
+	h.logger.Info("recorded alert feedback", "dedupKey", dedupKey, "rating", feedback.Rating, "author", feedback.Author)
+	return Created(c, feedback)
+}
+
+// List handles GET /v1/alerts/:dedupKey/feedback
+// Returns the feedback on an alert, oldest first.
+func (h *FeedbackHandler) List(c *fiber.Ctx) error {
+	dedupKey := c.Params("dedupKey")
+	if dedupKey == "" {
+		return BadRequest(c, "dedupKey is required")
+	}
+
+	if _, err := h.alertRepo.GetByDedupKey(c.Context(), dedupKey); err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get alert", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to get alert")
+	}
+
+	feedback, err := h.repo.ListByDedupKey(c.Context(), dedupKey)
+	if err != nil {
+		h.logger.Error("failed to list alert feedback", "dedupKey", dedupKey, "error", err)
+		return InternalError(c, "failed to list alert feedback")
+	}
+
+	return Success(c, feedback)
+}
+
+// Quality handles GET /v1/reports/alert-quality
+// Returns the feedback given within the report range aggregated per event
+// manager and grouping rule, least useful first.
+func (h *FeedbackHandler) Quality(c *fiber.Ctx) error {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		return ValidationError(c, err.Error())
+	}
+
+	feedback, err := h.repo.List(c.Context(), filter)
+	if err != nil {
+		h.logger.Error("failed to compute alert quality report", "error", err)
+		return InternalError(c, "failed to compute alert quality report")
+	}
+
+	return Success(c, domain.BuildAlertQualityReport(feedback, filter))
+}
@@ -0,0 +1,69 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/pkg/argustest"
+)
+
+func TestFeedbackHandler_Quality(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+	em := eventManager(t, h, emID)
+
+	ingest(t, h, emID, domain.ActionTrigger, "disk", "disk-1", "disk-2")
+	h.AwaitStatus(t, "disk-2", domain.AlertStatusActive)
+
+	give := func(dedupKey, body string) (int, domain.AlertFeedback) {
+		t.Helper()
+		req := newRequest(t, h, http.MethodPost, "/v1/alerts/"+dedupKey+"/feedback", body)
+		req.Header.Set("X-Argus-Actor", "alice")
+		var result struct {
+			Data domain.AlertFeedback `json:"data"`
+		}
+		status := send(t, req, &result)
+		return status, result.Data
+	}
+
+	status, feedback := give("disk-1", `{"rating":"useful","comment":" needed a cleanup "}`)
+	if status != http.StatusCreated {
+		t.Fatalf("POST feedback status = %d, want 201", status)
+	}
+	if feedback.EventManagerID != emID || feedback.GroupingRuleID != em.GroupingRuleID || feedback.Author != "alice" || feedback.Comment != "needed a cleanup" {
+		t.Errorf("feedback = %+v, want alice's on the grouping rule of %s", feedback, emID)
+	}
+	give("disk-2", `{"rating":"noisy"}`)
+	give("disk-2", `{"rating":"wrongly_grouped"}`)
+
+	if status, _ := give("disk-1", `{"rating":"great"}`); status != http.StatusBadRequest {
+		t.Errorf("POST feedback with an unknown rating status = %d, want 400", status)
+	}
+	if status, _ := give("missing", `{"rating":"noisy"}`); status != http.StatusNotFound {
+		t.Errorf("POST feedback on a missing alert status = %d, want 404", status)
+	}
+
+	var listed struct {
+		Data []domain.AlertFeedback `json:"data"`
+	}
+	call(t, h, http.MethodGet, "/v1/alerts/disk-2/feedback", "", &listed)
+	if len(listed.Data) != 2 || listed.Data[0].Rating != domain.FeedbackNoisy {
+		t.Errorf("feedback of disk-2 = %+v, want noisy then wrongly_grouped", listed.Data)
+	}
+
+	from := h.Now().Add(-time.Hour).Format(time.RFC3339)
+	to := h.Now().Add(time.Hour).Format(time.RFC3339)
+	var report struct {
+		Data domain.AlertQualityReport `json:"data"`
+	}
+	call(t, h, http.MethodGet, "/v1/reports/alert-quality?from="+from+"&to="+to, "", &report)
+	if len(report.Data.EventManagers) != 1 || len(report.Data.GroupingRules) != 1 {
+		t.Fatalf("report = %+v, want the event manager and its grouping rule", report.Data)
+	}
+	quality := report.Data.GroupingRules[0]
+	if quality.GroupingRuleID != em.GroupingRuleID || quality.Total != 3 || quality.Useful != 1 || quality.Noisy != 1 || quality.WronglyGrouped != 1 {
+		t.Errorf("grouping rule quality = %+v, want 1 of each rating", quality)
+	}
+}
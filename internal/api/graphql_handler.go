@@ -0,0 +1,427 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/graphql-go/graphql"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// maxGraphQLLimit caps the page size of GraphQL list fields, so a nested
+// query can't load unbounded alerts at every level.
+const maxGraphQLLimit = 1000
+
+// graphQLRequest is the body of POST /graphql.
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// GraphQLHandler serves a read-only GraphQL API over alerts, event managers
+// and grouping rules, so clients can fetch the nested data they need, e.g.
+// alerts with their children and event managers, in one request.
+type GraphQLHandler struct {
+	alertRepo        store.AlertRepository
+	eventManagerRepo store.EventManagerRepository
+	groupingRuleRepo store.GroupingRuleRepository
+	schema           graphql.Schema
+	logger           *slog.Logger
+}
+
+// NewGraphQLHandler creates a new GraphQL handler and builds its schema.
+func NewGraphQLHandler(
+	alertRepo store.AlertRepository,
+	eventManagerRepo store.EventManagerRepository,
+	groupingRuleRepo store.GroupingRuleRepository,
+	logger *slog.Logger,
+) (*GraphQLHandler, error) {
+	h := &GraphQLHandler{
+		alertRepo:        alertRepo,
+		eventManagerRepo: eventManagerRepo,
+		groupingRuleRepo: groupingRuleRepo,
+		logger:           logger,
+	}
+
+	schema, err := h.buildSchema()
+	if err != nil {
+		return nil, err
+	}
+	h.schema = schema
+	return h, nil
+}
+
+// Query handles GET and POST /graphql
+// Executes a GraphQL query, from the JSON body of a POST or the query,
+// variables and operationName parameters of a GET. Returns the standard
+// GraphQL response: the data, and the errors of fields that failed.
+func (h *GraphQLHandler) Query(c *fiber.Ctx) error {
+	var req graphQLRequest
+	if c.Method() == fiber.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return BadRequest(c, "variables must be a JSON object")
+			}
+		}
+	} else if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		return BadRequest(c, "query is required")
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         h.schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        c.Context(),
+	})
+	return c.JSON(result)
+}
+
+// buildSchema defines the GraphQL types and their resolvers. Types refer to
+// each other, so their fields are thunks.
+func (h *GraphQLHandler) buildSchema() (graphql.Schema, error) {
+	var alertType, eventManagerType, groupingRuleType *graphql.Object
+
+	pagination := graphql.FieldConfigArgument{
+		"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultListLimit},
+		"offset": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+	}
+	alertListArgs := func(extra graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+		args := graphql.FieldConfigArgument{
+			"status": &graphql.ArgumentConfig{Type: graphql.String, Description: "active or resolved"},
+		}
+		for name, arg := range pagination {
+			args[name] = arg
+		}
+		for name, arg := range extra {
+			args[name] = arg
+		}
+		return args
+	}
+
+	resolutionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Resolution",
+		Fields: graphql.Fields{
+			"resolvedBy": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"actor":      &graphql.Field{Type: graphql.String},
+			"reason":     &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	runbookType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Runbook",
+		Fields: graphql.Fields{
+			"url":   &graphql.Field{Type: graphql.String},
+			"steps": &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		},
+	})
+
+	linkType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Link",
+		Fields: graphql.Fields{
+			"type":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"url":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"title": &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	alertType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Alert",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":               &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+				"dedupKey":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"originalDedupKey": &graphql.Field{Type: graphql.String, Resolve: optionalString(func(a *domain.Alert) string { return a.OriginalDedupKey })},
+				"eventManagerId":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"summary":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"severity":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"class":            &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"service":          &graphql.Field{Type: graphql.String, Resolve: optionalString(func(a *domain.Alert) string { return a.Service })},
+				"components": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if components := p.Source.(*domain.Alert).Components; components != nil {
+							return components, nil
+						}
+						return []string{}, nil
+					},
+				},
+				"type":             &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"status":           &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"parentDedupKey":   &graphql.Field{Type: graphql.String, Resolve: optionalString(func(a *domain.Alert) string { return a.ParentDedupKey })},
+				"childCount":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"triggerCount":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"resolveCount":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"resolveRequested": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+				"acknowledgedAt":   &graphql.Field{Type: graphql.DateTime},
+				"createdAt":        &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"updatedAt":        &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"resolvedAt":       &graphql.Field{Type: graphql.DateTime},
+				"resolution":       &graphql.Field{Type: resolutionType},
+				"runbook":          &graphql.Field{Type: runbookType},
+				"assignee":         &graphql.Field{Type: graphql.String, Resolve: optionalString(func(a *domain.Alert) string { return a.Assignee })},
+				"links": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(linkType))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if links := p.Source.(*domain.Alert).Links; links != nil {
+							return links, nil
+						}
+						return []domain.AlertLink{}, nil
+					},
+				},
+				"children": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(alertType))),
+					Description: "The children of a parent alert, newest first; empty for other alerts.",
+					Args:        alertListArgs(nil),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						alert := p.Source.(*domain.Alert)
+						if !alert.IsParent() {
+							return []*domain.Alert{}, nil
+						}
+						filter := domain.AlertFilter{ParentDedupKey: alert.DedupKey}
+						if err := alertFilterArgs(p.Args, &filter); err != nil {
+							return nil, err
+						}
+						return h.alertRepo.List(p.Context, filter)
+					},
+				},
+				"parent": &graphql.Field{
+					Type:        alertType,
+					Description: "The parent of a child alert; null for other alerts.",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						alert := p.Source.(*domain.Alert)
+						if alert.ParentDedupKey == "" {
+							return nil, nil
+						}
+						return h.alert(p, alert.ParentDedupKey)
+					},
+				},
+				"eventManager": &graphql.Field{
+					Type: eventManagerType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return h.eventManager(p, p.Source.(*domain.Alert).EventManagerID)
+					},
+				},
+			}
+		}),
+	})
+
+	eventManagerType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "EventManager",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":               &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+				"name":             &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"description":      &graphql.Field{Type: graphql.String},
+				"groupingRuleId":   &graphql.Field{Type: graphql.String, Resolve: optionalString(func(em *domain.EventManager) string { return em.GroupingRuleID })},
+				"groupingDisabled": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+				"createdAt":        &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"updatedAt":        &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"deletedAt":        &graphql.Field{Type: graphql.DateTime},
+				"groupingRule": &graphql.Field{
+					Type: groupingRuleType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						id := p.Source.(*domain.EventManager).GroupingRuleID
+						if id == "" {
+							return nil, nil
+						}
+						return h.groupingRule(p, id)
+					},
+				},
+				"alerts": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(alertType))),
+					Description: "The alerts of the event manager, including those it subscribes to, newest first.",
+					Args: alertListArgs(graphql.FieldConfigArgument{
+						"type": &graphql.ArgumentConfig{Type: graphql.String, Description: "parent or child"},
+						"q":    &graphql.ArgumentConfig{Type: graphql.String, Description: "words the summary contains"},
+					}),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						filter := domain.AlertFilter{
+							EventManagerID:    p.Source.(*domain.EventManager).ID,
+							IncludeSubscribed: true,
+						}
+						if err := alertFilterArgs(p.Args, &filter); err != nil {
+							return nil, err
+						}
+						return h.alertRepo.List(p.Context, filter)
+					},
+				},
+			}
+		}),
+	})
+
+	groupingRuleType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "GroupingRule",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+				"name":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"groupingKey": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"timeWindow": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*domain.GroupingRule).Window.String(), nil
+				}},
+				"valueTemplate": &graphql.Field{Type: graphql.String, Resolve: optionalString(func(r *domain.GroupingRule) string { return r.ValueTemplate })},
+				"valuePattern":  &graphql.Field{Type: graphql.String, Resolve: optionalString(func(r *domain.GroupingRule) string { return r.ValuePattern })},
+				"runbook":       &graphql.Field{Type: runbookType},
+				"createdAt":     &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"updatedAt":     &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"deletedAt":     &graphql.Field{Type: graphql.DateTime},
+				"eventManagers": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(eventManagerType))),
+					Description: "The event managers using the rule.",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return h.eventManagerRepo.ListByGroupingRule(p.Context, p.Source.(*domain.GroupingRule).ID)
+					},
+				},
+			}
+		}),
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"alert": &graphql.Field{
+				Type: alertType,
+				Args: graphql.FieldConfigArgument{
+					"dedupKey": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return h.alert(p, p.Args["dedupKey"].(string))
+				},
+			},
+			"alerts": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(alertType))),
+				Description: "Alerts, newest first. Alerts shared through global dedup are listed for every subscriber.",
+				Args: alertListArgs(graphql.FieldConfigArgument{
+					"eventManagerId": &graphql.ArgumentConfig{Type: graphql.String},
+					"type":           &graphql.ArgumentConfig{Type: graphql.String, Description: "parent or child"},
+					"q":              &graphql.ArgumentConfig{Type: graphql.String, Description: "words the summary contains"},
+					"service":        &graphql.ArgumentConfig{Type: graphql.String},
+					"component":      &graphql.ArgumentConfig{Type: graphql.String, Description: "a component the alert affects"},
+				}),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					filter := domain.AlertFilter{IncludeSubscribed: true}
+					if id, ok := p.Args["eventManagerId"].(string); ok {
+						filter.EventManagerID = id
+					}
+					if err := alertFilterArgs(p.Args, &filter); err != nil {
+						return nil, err
+					}
+					return h.alertRepo.List(p.Context, filter)
+				},
+			},
+			"eventManager": &graphql.Field{
+				Type: eventManagerType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return h.eventManager(p, p.Args["id"].(string))
+				},
+			},
+			"eventManagers": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(eventManagerType))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return h.eventManagerRepo.List(p.Context)
+				},
+			},
+			"groupingRule": &graphql.Field{
+				Type: groupingRuleType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return h.groupingRule(p, p.Args["id"].(string))
+				},
+			},
+			"groupingRules": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(groupingRuleType))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return h.groupingRuleRepo.List(p.Context)
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+// alert resolves an alert by dedup key, or null if there is none.
+func (h *GraphQLHandler) alert(p graphql.ResolveParams, dedupKey string) (interface{}, error) {
+	alert, err := h.alertRepo.GetByDedupKey(p.Context, dedupKey)
+	if errors.Is(err, domain.ErrAlertNotFound) {
+		return nil, nil
+	}
+	return alert, err
+}
+
+// eventManager resolves an event manager by ID, or null if there is none.
+func (h *GraphQLHandler) eventManager(p graphql.ResolveParams, id string) (interface{}, error) {
+	em, err := h.eventManagerRepo.GetByID(p.Context, id)
+	if errors.Is(err, domain.ErrEventManagerNotFound) {
+		return nil, nil
+	}
+	return em, err
+}
+
+// groupingRule resolves a grouping rule by ID, or null if there is none.
+func (h *GraphQLHandler) groupingRule(p graphql.ResolveParams, id string) (interface{}, error) {
+	rule, err := h.groupingRuleRepo.GetByID(p.Context, id)
+	if errors.Is(err, domain.ErrGroupingRuleNotFound) {
+		return nil, nil
+	}
+	return rule, err
+}
+
+// alertFilterArgs applies the filter and pagination arguments of an alert
+// list field to filter.
+func alertFilterArgs(args map[string]interface{}, filter *domain.AlertFilter) error {
+	if status, ok := args["status"].(string); ok {
+		filter.Status = domain.AlertStatus(status)
+	}
+	if alertType, ok := args["type"].(string); ok {
+		filter.Type = domain.AlertType(alertType)
+	}
+	if q, ok := args["q"].(string); ok {
+		filter.Query = strings.TrimSpace(q)
+	}
+	if service, ok := args["service"].(string); ok {
+		filter.Service = service
+	}
+	if component, ok := args["component"].(string); ok {
+		filter.Component = component
+	}
+
+	filter.Limit, _ = args["limit"].(int)
+	filter.Offset, _ = args["offset"].(int)
+	if filter.Limit <= 0 || filter.Limit > maxGraphQLLimit {
+		return errors.New("limit must be between 1 and 1000")
+	}
+	if filter.Offset < 0 {
+		return errors.New("offset must not be negative")
+	}
+	return nil
+}
+
+// optionalString resolves a string field of T as null when it is empty.
+func optionalString[T any](get func(*T) string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if s := get(p.Source.(*T)); s != "" {
+			return s, nil
+		}
+		return nil, nil
+	}
+}
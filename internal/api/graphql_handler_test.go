@@ -0,0 +1,64 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/pkg/argustest"
+)
+
+func TestGraphQLHandler_Query(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	ingest(t, h, emID, domain.ActionTrigger, "storage", "host-1", "host-2", "host-3")
+	h.AwaitAlert(t, "host-1", func(a *domain.Alert) bool { return a.ChildCount == 2 })
+
+	query := func(body string) (int, map[string]any) {
+		t.Helper()
+		var result map[string]any
+		status := call(t, h, http.MethodPost, "/graphql", body, &result)
+		return status, result
+	}
+
+	status, result := query(`{"query": "query($key: String!) { alert(dedupKey: $key) { dedupKey type eventManager { id groupingRule { groupingKey timeWindow } } children(limit: 1) { dedupKey parent { dedupKey } } } }", "variables": {"key": "host-1"}}`)
+	if status != http.StatusOK || result["errors"] != nil {
+		t.Fatalf("POST /graphql = %d with errors %v, want 200", status, result["errors"])
+	}
+	alert := result["data"].(map[string]any)["alert"].(map[string]any)
+	if alert["type"] != string(domain.AlertTypeParent) {
+		t.Errorf("alert type = %v, want parent", alert["type"])
+	}
+	em := alert["eventManager"].(map[string]any)
+	if em["id"] != emID {
+		t.Errorf("eventManager id = %v, want %s", em["id"], emID)
+	}
+	if rule := em["groupingRule"].(map[string]any); rule["groupingKey"] != "class" || rule["timeWindow"] != "5m" {
+		t.Errorf("groupingRule = %v, want class over 5m", rule)
+	}
+	children := alert["children"].([]any)
+	if len(children) != 1 {
+		t.Fatalf("children = %d, want 1 with limit 1", len(children))
+	}
+	if parent := children[0].(map[string]any)["parent"].(map[string]any); parent["dedupKey"] != "host-1" {
+		t.Errorf("child parent = %v, want host-1", parent["dedupKey"])
+	}
+
+	_, result = query(`{"query": "{ alerts(type: \"child\") { dedupKey } missing: alert(dedupKey: \"none\") { dedupKey } }"}`)
+	data := result["data"].(map[string]any)
+	if alerts := data["alerts"].([]any); len(alerts) != 2 {
+		t.Errorf("child alerts = %d, want 2", len(alerts))
+	}
+	if data["missing"] != nil {
+		t.Errorf("unknown alert = %v, want null", data["missing"])
+	}
+
+	if _, result = query(`{"query": "{ alerts(limit: 0) { dedupKey } }"}`); result["errors"] == nil {
+		t.Error("alerts with limit 0 succeeded, want an error")
+	}
+	if status := call(t, h, http.MethodPost, "/graphql", `{"query": ""}`, nil); status != http.StatusBadRequest {
+		t.Errorf("POST /graphql without a query status = %d, want 400", status)
+	}
+}
@@ -1,27 +1,53 @@
 package api
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
+	"argus-go/internal/approval"
 	"argus-go/internal/domain"
+	"argus-go/internal/ingest"
 	"argus-go/internal/store"
 )
 
 // GroupingRuleHandler handles HTTP requests for grouping rule operations.
 type GroupingRuleHandler struct {
-	repo   store.GroupingRuleRepository
-	logger *slog.Logger
+	repo             store.GroupingRuleRepository
+	eventManagerRepo store.EventManagerRepository
+	groupingDefaults *ingest.GroupingDefaults
+	stateStore       store.StateStore
+	approvals        *approval.Gate
+	logger           *slog.Logger
 }
 
 // NewGroupingRuleHandler creates a new grouping rule handler.
-func NewGroupingRuleHandler(repo store.GroupingRuleRepository, logger *slog.Logger) *GroupingRuleHandler {
+// The event manager repository and grouping defaults are used to block
+// deleting rules that are still in use, the state store reports their
+// grouping statistics, and the approval gate holds purges for approval.
+func NewGroupingRuleHandler(
+	repo store.GroupingRuleRepository,
+	eventManagerRepo store.EventManagerRepository,
+	groupingDefaults *ingest.GroupingDefaults,
+	stateStore store.StateStore,
+	approvals *approval.Gate,
+	logger *slog.Logger,
+) *GroupingRuleHandler {
 	return &GroupingRuleHandler{
-		repo:   repo,
-		logger: logger,
+		repo:             repo,
+		eventManagerRepo: eventManagerRepo,
+		groupingDefaults: groupingDefaults,
+		stateStore:       stateStore,
+		approvals:        approvals,
+		logger:           logger,
 	}
 }
 
@@ -40,12 +66,37 @@ func (h *GroupingRuleHandler) Create(c *fiber.Ctx) error {
 		return ValidationError(c, err.Error())
 	}
 
-	// Generate ID and create the grouping rule
-	id := uuid.New().String()
-	rule := req.ToGroupingRule(id)
+	// Use the client-supplied ID, if any, so creates can be retried safely:
+	// repeating a create with the same configuration returns the existing rule
+	id := req.ID
+	if id == "" {
+		id = uuid.New().String()
+	} else {
+		existing, err := h.repo.GetByID(c.Context(), id)
+		if err == nil {
+			if existing.IsDeleted() {
+				return DomainError(c, domain.ErrGroupingRuleDeleted)
+			}
+			if !req.Matches(existing) {
+				return DomainError(c, domain.ErrResourceMismatch)
+			}
+			return Success(c, existing)
+		}
+		if !errors.Is(err, domain.ErrGroupingRuleNotFound) {
+			h.logger.Error("failed to get grouping rule", "id", id, "error", err)
+			return InternalError(c, "failed to get grouping rule")
+		}
+	}
 
-	// Persist to repository
+	return h.create(c, req.ToGroupingRule(id))
+}
+
+// create persists a new grouping rule and responds with 201 Created.
+func (h *GroupingRuleHandler) create(c *fiber.Ctx, rule *domain.GroupingRule) error {
 	if err := h.repo.Create(c.Context(), rule); err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
 		h.logger.Error("failed to create grouping rule", "error", err)
 		return InternalError(c, "failed to create grouping rule")
 	}
@@ -55,7 +106,7 @@ func (h *GroupingRuleHandler) Create(c *fiber.Ctx) error {
 }
 
 // List handles GET /v1/grouping-rules
-// Returns all grouping rules.
+// Returns all grouping rules that are not deleted.
 func (h *GroupingRuleHandler) List(c *fiber.Ctx) error {
 	rules, err := h.repo.List(c.Context())
 	if err != nil {
@@ -63,11 +114,13 @@ func (h *GroupingRuleHandler) List(c *fiber.Ctx) error {
 		return InternalError(c, "failed to list grouping rules")
 	}
 
-	return Success(c, rules)
+	return SuccessWithLastModified(c, rules, latestUpdate(rules, func(rule *domain.GroupingRule) time.Time {
+		return rule.UpdatedAt
+	}))
 }
 
 // GetByID handles GET /v1/grouping-rules/:id
-// Returns a single grouping rule by ID.
+// Returns a single grouping rule by ID, including deleted ones.
 func (h *GroupingRuleHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
@@ -76,18 +129,60 @@ func (h *GroupingRuleHandler) GetByID(c *fiber.Ctx) error {
 
 	rule, err := h.repo.GetByID(c.Context(), id)
 	if err != nil {
-		if errors.Is(err, domain.ErrGroupingRuleNotFound) {
-			return NotFound(c, "grouping rule not found")
+		if isDomainError(err) {
+			return DomainError(c, err)
 		}
 		h.logger.Error("failed to get grouping rule", "id", id, "error", err)
 		return InternalError(c, "failed to get grouping rule")
 	}
 
-	return Success(c, rule)
+	return SuccessWithLastModified(c, rule, rule.UpdatedAt)
+}
+
+// previewResponse is the body returned by POST /v1/grouping-rules/:id/preview.
+type previewResponse struct {
+	GroupingRuleID string                   `json:"grouping_rule_id"`
+	Results        []domain.GroupingPreview `json:"results"`
+}
+
+// Preview handles POST /v1/grouping-rules/:id/preview
+// Shows how sample events would be grouped by the rule, without persisting
+// anything, so a rule can be tried out before event managers use it.
+func (h *GroupingRuleHandler) Preview(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	var req domain.PreviewGroupingRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+
+	if err := req.Validate(); err != nil {
+		h.logger.Debug("validation failed", "error", err)
+		return ValidationError(c, err.Error())
+	}
+
+	rule, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get grouping rule", "id", id, "error", err)
+		return InternalError(c, "failed to get grouping rule")
+	}
+
+	return Success(c, previewResponse{
+		GroupingRuleID: rule.ID,
+		Results:        rule.Preview(req.Events),
+	})
 }
 
 // Update handles PUT /v1/grouping-rules/:id
-// Updates an existing grouping rule.
+// Updates an existing grouping rule, or creates it with the given ID if it
+// does not exist.
 func (h *GroupingRuleHandler) Update(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
@@ -110,11 +205,22 @@ func (h *GroupingRuleHandler) Update(c *fiber.Ctx) error {
 	rule, err := h.repo.GetByID(c.Context(), id)
 	if err != nil {
 		if errors.Is(err, domain.ErrGroupingRuleNotFound) {
-			return NotFound(c, "grouping rule not found")
+			// PUT creates missing rules, for declarative tooling
+			if err := domain.ValidateResourceID(id); err != nil {
+				return ValidationError(c, err.Error())
+			}
+			// Copy the ID, which refers to Fiber's reused request buffer
+			rule = &domain.GroupingRule{ID: strings.Clone(id)}
+			req.ApplyTo(rule)
+			rule.CreatedAt = rule.UpdatedAt
+			return h.create(c, rule)
 		}
 		h.logger.Error("failed to get grouping rule", "id", id, "error", err)
 		return InternalError(c, "failed to get grouping rule")
 	}
+	if rule.IsDeleted() {
+		return DomainError(c, domain.ErrGroupingRuleDeleted)
+	}
 
 	// Apply updates
 	req.ApplyTo(rule)
@@ -130,16 +236,36 @@ func (h *GroupingRuleHandler) Update(c *fiber.Ctx) error {
 }
 
 // Delete handles DELETE /v1/grouping-rules/:id
-// Deletes a grouping rule.
+// Soft-deletes a grouping rule. Rules still referenced by active event
+// managers, or set as the system default, cannot be deleted. Rules with
+// other usage, i.e. referenced by deleted event managers or having grouped
+// alerts in the last days, are only deleted with ?force=true.
 func (h *GroupingRuleHandler) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
 		return BadRequest(c, "id is required")
 	}
 
+	if id == h.groupingDefaults.RuleID() {
+		return DomainError(c, domain.ErrGroupingRuleIsDefault)
+	}
+
+	force := c.QueryBool("force")
+	usage, err := h.usage(c, id, defaultUsageDays, !force)
+	if err != nil {
+		h.logger.Error("failed to get grouping rule usage", "groupingRuleID", id, "error", err)
+		return InternalError(c, "failed to delete grouping rule")
+	}
+	if len(usage.EventManagers) > 0 {
+		return DomainError(c, fmt.Errorf("%w: %s", domain.ErrGroupingRuleInUse, strings.Join(usage.EventManagers, ", ")))
+	}
+	if !force && usage.inUse() {
+		return DomainError(c, domain.ErrGroupingRuleRecentlyUsed)
+	}
+
 	if err := h.repo.Delete(c.Context(), id); err != nil {
-		if errors.Is(err, domain.ErrGroupingRuleNotFound) {
-			return NotFound(c, "grouping rule not found")
+		if isDomainError(err) {
+			return DomainError(c, err)
 		}
 		h.logger.Error("failed to delete grouping rule", "id", id, "error", err)
 		return InternalError(c, "failed to delete grouping rule")
@@ -148,3 +274,227 @@ func (h *GroupingRuleHandler) Delete(c *fiber.Ctx) error {
 	h.logger.Info("deleted grouping rule", "id", id)
 	return NoContent(c)
 }
+
+// defaultUsageDays is the number of days of grouping statistics reported by
+// default, and considered when deleting a grouping rule.
+const defaultUsageDays = 7
+
+// groupingRuleUsageResponse is the body returned by
+// GET /v1/grouping-rules/:id/usage.
+type groupingRuleUsageResponse struct {
+	GroupingRuleID string `json:"grouping_rule_id"`
+
+	// EventManagers and DeletedEventManagers list the active and deleted
+	// event managers referencing the rule.
+	EventManagers        []string `json:"event_managers"`
+	DeletedEventManagers []string `json:"deleted_event_managers"`
+
+	// Days is the number of days, up to today, the statistics cover.
+	Days            int `json:"days"`
+	ParentsCreated  int `json:"parents_created"`
+	ChildrenGrouped int `json:"children_grouped"`
+
+	// AverageGroupSize is the number of alerts per group the rule opened,
+	// parent included; 0 without groups.
+	AverageGroupSize float64 `json:"average_group_size"`
+
+	ByDay []*store.GroupingStats `json:"by_day"`
+}
+
+// inUse returns true if event managers reference the rule or it grouped
+// alerts in the days covered.
+func (u *groupingRuleUsageResponse) inUse() bool {
+	return len(u.EventManagers) > 0 || len(u.DeletedEventManagers) > 0 || u.ParentsCreated > 0 || u.ChildrenGrouped > 0
+}
+
+// Usage handles GET /v1/grouping-rules/:id/usage
+// Returns the event managers referencing a grouping rule and its grouping
+// statistics over the last days: the parents it created, the children it
+// grouped and the average group size. Accepts days (1-31, default 7).
+func (h *GroupingRuleHandler) Usage(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	days := defaultUsageDays
+	if raw := c.Query("days"); raw != "" {
+		d, err := strconv.Atoi(raw)
+		if err != nil || d < 1 || d > int(store.GroupingStatsRetention/(24*time.Hour)) {
+			return BadRequest(c, "days must be between 1 and 31")
+		}
+		days = d
+	}
+
+	if _, err := h.repo.GetByID(c.Context(), id); err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get grouping rule", "id", id, "error", err)
+		return InternalError(c, "failed to get grouping rule")
+	}
+
+	usage, err := h.usage(c, id, days, true)
+	if err != nil {
+		h.logger.Error("failed to get grouping rule usage", "groupingRuleID", id, "error", err)
+		return InternalError(c, "failed to get grouping rule usage")
+	}
+	return Success(c, usage)
+}
+
+// usage collects the usage of a grouping rule over the last days, up to
+// today. Grouping statistics are only read if withStats is set.
+func (h *GroupingRuleHandler) usage(c *fiber.Ctx, id string, days int, withStats bool) (*groupingRuleUsageResponse, error) {
+	usage := &groupingRuleUsageResponse{
+		GroupingRuleID:       id,
+		EventManagers:        []string{},
+		DeletedEventManagers: []string{},
+		Days:                 days,
+		ByDay:                []*store.GroupingStats{},
+	}
+
+	managers, err := h.eventManagerRepo.ListByGroupingRule(c.Context(), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list event managers: %w", err)
+	}
+	for _, em := range managers {
+		if em.IsDeleted() {
+			usage.DeletedEventManagers = append(usage.DeletedEventManagers, em.ID)
+		} else {
+			usage.EventManagers = append(usage.EventManagers, em.ID)
+		}
+	}
+	sort.Strings(usage.EventManagers)
+	sort.Strings(usage.DeletedEventManagers)
+
+	if !withStats {
+		return usage, nil
+	}
+	now := time.Now()
+	dayList := make([]string, days)
+	for i := range dayList {
+		dayList[i] = domain.ReportDay(now.AddDate(0, 0, i-days+1))
+	}
+	stats, err := h.stateStore.GetGroupingStats(c.Context(), id, dayList)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get grouping stats: %w", err)
+	}
+	for _, s := range stats {
+		usage.ParentsCreated += s.ParentsCreated
+		usage.ChildrenGrouped += s.ChildrenGrouped
+		usage.ByDay = append(usage.ByDay, s)
+	}
+	if usage.ParentsCreated > 0 {
+		usage.AverageGroupSize = float64(usage.ParentsCreated+usage.ChildrenGrouped) / float64(usage.ParentsCreated)
+	}
+	return usage, nil
+}
+
+// referencingEventManagers returns the IDs of event managers using a grouping rule.
+// Deleted event managers are only included if includeDeleted is set.
+func (h *GroupingRuleHandler) referencingEventManagers(c *fiber.Ctx, id string, includeDeleted bool) ([]string, error) {
+	managers, err := h.eventManagerRepo.ListByGroupingRule(c.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(managers))
+	for _, em := range managers {
+		if em.IsDeleted() && !includeDeleted {
+			continue
+		}
+		ids = append(ids, em.ID)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Purge handles DELETE /v1/admin/grouping-rules/:id
+// Permanently removes a deleted grouping rule. With approvals enabled, the
+// purge is held and 202 Accepted is returned with the pending approval.
+func (h *GroupingRuleHandler) Purge(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	rule, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get grouping rule", "id", id, "error", err)
+		return InternalError(c, "failed to get grouping rule")
+	}
+	if !rule.IsDeleted() {
+		return DomainError(c, domain.ErrGroupingRuleNotDeleted)
+	}
+
+	// Deleted event managers keep their reference until they are purged
+	users, err := h.referencingEventManagers(c, id, true)
+	if err != nil {
+		h.logger.Error("failed to list event managers", "groupingRuleID", id, "error", err)
+		return InternalError(c, "failed to purge grouping rule")
+	}
+	if len(users) > 0 {
+		return DomainError(c, fmt.Errorf("%w: %s", domain.ErrGroupingRuleInUse, strings.Join(users, ", ")))
+	}
+
+	if h.approvals.Enabled() {
+		return requestApproval(c, h.approvals, domain.OperationPurgeGroupingRule, id, func(ctx context.Context) (string, error) {
+			if err := h.repo.Purge(ctx, id); err != nil {
+				return "", err
+			}
+			h.logger.Warn("purged grouping rule", "id", id)
+			return "purged grouping rule", nil
+		})
+	}
+
+	if err := h.repo.Purge(c.Context(), id); err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to purge grouping rule", "id", id, "error", err)
+		return InternalError(c, "failed to purge grouping rule")
+	}
+
+	h.logger.Warn("purged grouping rule", "id", id)
+	h.approvals.Executed(c.Context(), domain.OperationPurgeGroupingRule, id, c.Get(ActorHeader), "purged grouping rule")
+	return NoContent(c)
+}
+
+// GetDefault handles GET /v1/admin/default-grouping-rule
+// Returns the system-wide default grouping rule.
+func (h *GroupingRuleHandler) GetDefault(c *fiber.Ctx) error {
+	return Success(c, domain.DefaultGroupingRule{GroupingRuleID: h.groupingDefaults.RuleID()})
+}
+
+// SetDefault handles PUT /v1/admin/default-grouping-rule
+// Sets the system-wide default grouping rule; an empty ID clears it.
+// The setting is kept in memory and reverts to the configured value on restart.
+func (h *GroupingRuleHandler) SetDefault(c *fiber.Ctx) error {
+	var req domain.DefaultGroupingRule
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+
+	if req.GroupingRuleID != "" {
+		rule, err := h.repo.GetByID(c.Context(), req.GroupingRuleID)
+		if err != nil {
+			if errors.Is(err, domain.ErrGroupingRuleNotFound) {
+				return ValidationError(c, "grouping_rule_id: "+err.Error())
+			}
+			h.logger.Error("failed to get grouping rule", "id", req.GroupingRuleID, "error", err)
+			return InternalError(c, "failed to get grouping rule")
+		}
+		if rule.IsDeleted() {
+			return ValidationError(c, "grouping_rule_id: "+domain.ErrGroupingRuleDeleted.Error())
+		}
+	}
+
+	h.groupingDefaults.SetRuleID(req.GroupingRuleID)
+
+	h.logger.Info("set default grouping rule", "id", req.GroupingRuleID)
+	return Success(c, req)
+}
@@ -0,0 +1,138 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/pkg/argustest"
+)
+
+func TestGroupingRuleHandler_References(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+	ruleID := eventManager(t, h, emID).GroupingRuleID
+
+	tests := []struct {
+		name, method, path, body string
+		want                     int
+	}{
+		{"create with unknown grouping rule", http.MethodPost, "/v1/event-managers", `{"name":"x","grouping_rule_id":"missing"}`, http.StatusBadRequest},
+		{"delete of referenced grouping rule", http.MethodDelete, "/v1/grouping-rules/" + ruleID, "", http.StatusConflict},
+		{"usage of grouping rule", http.MethodGet, "/v1/grouping-rules/" + ruleID + "/usage", "", http.StatusOK},
+
+		// Once the event manager is deleted, the rule can be deleted with
+		// force but not purged
+		{"delete event manager", http.MethodDelete, "/v1/event-managers/" + emID, "", http.StatusNoContent},
+		{"delete of grouping rule used by a deleted event manager", http.MethodDelete, "/v1/grouping-rules/" + ruleID, "", http.StatusConflict},
+		{"forced delete of unreferenced grouping rule", http.MethodDelete, "/v1/grouping-rules/" + ruleID + "?force=true", "", http.StatusNoContent},
+		{"purge of grouping rule used by a deleted event manager", http.MethodDelete, "/v1/admin/grouping-rules/" + ruleID, "", http.StatusConflict},
+		{"purge event manager", http.MethodDelete, "/v1/admin/event-managers/" + emID, "", http.StatusNoContent},
+		{"purge grouping rule", http.MethodDelete, "/v1/admin/grouping-rules/" + ruleID, "", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		if status := call(t, h, tt.method, tt.path, tt.body, nil); status != tt.want {
+			t.Fatalf("%s status = %d, want %d", tt.name, status, tt.want)
+		}
+	}
+}
+
+func TestGroupingRuleHandler_Default(t *testing.T) {
+	h := argustest.Start(t)
+	ctx := context.Background()
+
+	rule := &domain.GroupingRule{ID: "default-rule", Name: "default", GroupingKey: "class", TimeWindowMinutes: 5}
+	if err := h.GroupingRuleRepo.Create(ctx, rule); err != nil {
+		t.Fatalf("Create grouping rule error: %v", err)
+	}
+	em := &domain.EventManager{ID: "em-no-rule", Name: "no rule"}
+	if err := h.EventManagerRepo.Create(ctx, em); err != nil {
+		t.Fatalf("Create event manager error: %v", err)
+	}
+
+	if status := call(t, h, http.MethodPut, "/v1/admin/default-grouping-rule", `{"grouping_rule_id":"missing"}`, nil); status != http.StatusBadRequest {
+		t.Errorf("set unknown default status = %d, want 400", status)
+	}
+	if status := call(t, h, http.MethodPut, "/v1/admin/default-grouping-rule", `{"grouping_rule_id":"default-rule"}`, nil); status != http.StatusOK {
+		t.Fatalf("set default status = %d, want 200", status)
+	}
+	if status := call(t, h, http.MethodDelete, "/v1/grouping-rules/default-rule", "", nil); status != http.StatusConflict {
+		t.Errorf("delete of default grouping rule status = %d, want 409", status)
+	}
+
+	// Events of an event manager without a rule are grouped by the default rule
+	ingest(t, h, em.ID, domain.ActionTrigger, "storage", "host-1", "host-2")
+
+	child := h.AwaitStatus(t, "host-2", domain.AlertStatusActive)
+	if child.ParentDedupKey != "host-1" {
+		t.Errorf("host-2 parent = %q, want host-1", child.ParentDedupKey)
+	}
+}
+
+func TestGroupingRuleHandler_Preview(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+	ruleID := eventManager(t, h, emID).GroupingRuleID
+
+	body := `{"events":[
+		{"event_manager_id":"` + emID + `","class":"db","dedupKey":"a"},
+		{"event_manager_id":"` + emID + `","class":"db","dedupKey":"b"}
+	]}`
+	var preview struct {
+		Data struct {
+			Results []domain.GroupingPreview `json:"results"`
+		} `json:"data"`
+	}
+	if status := call(t, h, http.MethodPost, "/v1/grouping-rules/"+ruleID+"/preview", body, &preview); status != http.StatusOK {
+		t.Fatalf("POST preview status = %d, want 200", status)
+	}
+	results := preview.Data.Results
+	if len(results) != 2 || results[0].Outcome != domain.GroupingOutcomeParent || results[1].ParentDedupKey != "a" {
+		t.Errorf("results = %+v, want a parent and its child", results)
+	}
+
+	// Nothing is persisted
+	alerts, err := h.AlertRepo.List(context.Background(), domain.AlertFilter{})
+	if err != nil {
+		t.Fatalf("List alerts error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Errorf("preview created %d alerts, want 0", len(alerts))
+	}
+}
+
+func TestGroupingRuleHandler_ClientSuppliedIDs(t *testing.T) {
+	h := argustest.Start(t)
+
+	rule := `{"id":"by-class","name":"By class","grouping_key":"class","time_window_minutes":5}`
+	if status := call(t, h, http.MethodPost, "/v1/grouping-rules", rule, nil); status != http.StatusCreated {
+		t.Fatalf("create grouping rule status = %d, want 201", status)
+	}
+	if status := call(t, h, http.MethodPost, "/v1/grouping-rules", rule, nil); status != http.StatusOK {
+		t.Errorf("repeated create status = %d, want 200", status)
+	}
+	changed := `{"id":"by-class","name":"By class","grouping_key":"class","time_window_minutes":10}`
+	if status := call(t, h, http.MethodPost, "/v1/grouping-rules", changed, nil); status != http.StatusConflict {
+		t.Errorf("create with a different configuration status = %d, want 409", status)
+	}
+	if status := call(t, h, http.MethodPost, "/v1/grouping-rules", `{"id":"not/safe","name":"x","grouping_key":"class","time_window_minutes":5}`, nil); status != http.StatusBadRequest {
+		t.Errorf("create with an invalid id status = %d, want 400", status)
+	}
+
+	// PUT creates missing resources, then updates them
+	if status := call(t, h, http.MethodPut, "/v1/event-managers/payments", `{"name":"Payments","grouping_rule_id":"by-class"}`, nil); status != http.StatusCreated {
+		t.Fatalf("PUT of a missing event manager status = %d, want 201", status)
+	}
+	if status := call(t, h, http.MethodPut, "/v1/event-managers/payments", `{"name":"Payments team","grouping_rule_id":"by-class"}`, nil); status != http.StatusOK {
+		t.Errorf("PUT of an existing event manager status = %d, want 200", status)
+	}
+	if status := call(t, h, http.MethodPut, "/v1/event-managers/billing", `{"name":"Billing","grouping_rule_id":"missing"}`, nil); status != http.StatusBadRequest {
+		t.Errorf("PUT with an unknown grouping rule status = %d, want 400", status)
+	}
+
+	if got := eventManager(t, h, "payments"); got.Name != "Payments team" || got.GroupingRuleID != "by-class" {
+		t.Errorf("event manager = %+v, want the updated configuration", got)
+	}
+}
@@ -0,0 +1,95 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"argus-go/internal/domain"
+	"argus-go/pkg/argustest"
+)
+
+// The tests of this package in package api_test run the handlers end to end
+// on an argustest harness.
+
+// newRequest returns a request to the API of h with a JSON body, if any.
+func newRequest(t *testing.T, h *argustest.Harness, method, path, body string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(method, h.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest error: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+// send sends a request, decodes the JSON response into v, if not nil, and
+// returns the response status.
+func send(t *testing.T, req *http.Request, v any) int {
+	t.Helper()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s error: %v", req.Method, req.URL, err)
+	}
+	defer resp.Body.Close()
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("decoding the response to %s %s: %v", req.Method, req.URL, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// call sends a request with a JSON body, if any, to the API of h, decodes
+// the JSON response into v, if not nil, and returns the response status.
+func call(t *testing.T, h *argustest.Harness, method, path, body string, v any) int {
+	t.Helper()
+	return send(t, newRequest(t, h, method, path, body), v)
+}
+
+// errorCode is the body of an error response.
+type errorCode struct {
+	Error struct {
+		Code string `json:"code"`
+	} `json:"error"`
+}
+
+// ingest queues events of a class for the dedup keys, in order, and waits
+// until they are processed.
+func ingest(t *testing.T, h *argustest.Harness, emID string, action domain.Action, class string, dedupKeys ...string) {
+	t.Helper()
+	for _, dedupKey := range dedupKeys {
+		h.Ingest(t, &domain.Event{
+			EventManagerID: emID,
+			Summary:        class + " failure on " + dedupKey,
+			Severity:       domain.SeverityHigh,
+			Action:         action,
+			Class:          class,
+			DedupKey:       dedupKey,
+		})
+	}
+	h.Sync(t)
+}
+
+// updateEventManager applies update to an event manager of h.
+func updateEventManager(t *testing.T, h *argustest.Harness, emID string, update func(*domain.EventManager)) *domain.EventManager {
+	t.Helper()
+	em := eventManager(t, h, emID)
+	update(em)
+	if err := h.EventManagerRepo.Update(context.Background(), em); err != nil {
+		t.Fatalf("Update event manager error: %v", err)
+	}
+	return em
+}
+
+// eventManager returns an event manager of h.
+func eventManager(t *testing.T, h *argustest.Harness, emID string) *domain.EventManager {
+	t.Helper()
+	em, err := h.EventManagerRepo.GetByID(context.Background(), emID)
+	if err != nil {
+		t.Fatalf("GetByID error: %v", err)
+	}
+	return em
+}
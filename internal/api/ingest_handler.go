@@ -1,31 +1,72 @@
 package api
 
 import (
+	"errors"
 	"log/slog"
+	"net/url"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 
 	"argus-go/internal/domain"
+	"argus-go/internal/eventstatus"
 	"argus-go/internal/ingest"
+	"argus-go/internal/logging"
+	"argus-go/internal/queue"
+	"argus-go/internal/store"
 )
 
 // IngestHandler handles HTTP requests for event ingestion.
 type IngestHandler struct {
-	service *ingest.Service
-	logger  *slog.Logger
+	service    *ingest.Service
+	router     *ingest.Router
+	stateStore store.StateStore
+	tracker    *eventstatus.Tracker
+	logger     *slog.Logger
 }
 
-// NewIngestHandler creates a new ingest handler.
-func NewIngestHandler(service *ingest.Service, logger *slog.Logger) *IngestHandler {
+// NewIngestHandler creates a new ingest handler. The router selects the
+// event manager of events sent without one; the state store tells senders
+// whether a trigger hit an active alert, and the tracker where an accepted
+// event is in the pipeline. A nil tracker disables event status.
+func NewIngestHandler(service *ingest.Service, router *ingest.Router, stateStore store.StateStore, tracker *eventstatus.Tracker, logger *slog.Logger) *IngestHandler {
 	return &IngestHandler{
-		service: service,
-		logger:  logger,
+		service:    service,
+		router:     router,
+		stateStore: stateStore,
+		tracker:    tracker,
+		logger:     logger,
 	}
 }
 
+// duplicateHint describes the active alert a trigger was deduplicated into.
+type duplicateHint struct {
+	DedupKey       string `json:"dedupKey"`
+	Status         string `json:"status"`
+	Type           string `json:"type"`
+	ParentDedupKey string `json:"parent_dedupKey,omitempty"`
+	Link           string `json:"link"`
+	ParentLink     string `json:"parent_link,omitempty"`
+}
+
 // IngestEvent handles POST /v1/events
 // Receives an event, validates it, and publishes to the message queue.
 // Returns 202 Accepted immediately - processing happens asynchronously.
+// Events without an event_manager_id are routed by the routing rules.
+// The response carries the event manager and the dedup key after normalization,
+// and the quota status if the event manager has a quota. Trigger events over
+// a quota enforced by rejection return 429 Too Many Requests. Events for an
+// unknown event manager or grouping rule return 422 Unprocessable Entity, and
+// a queue that can't take the event, unreachable or with a full async
+// publish buffer, returns 503 Service Unavailable with Retry-After.
+// A trigger for an alert that is already active is marked as a duplicate,
+// with the alert's status and links to it and its parent, so senders know
+// it raised no new alert. With event status tracking, the response carries
+// the event ID and a link to the status of the event.
+//
+// With ?dry_run=true, on this and the other ingest endpoints, the event is
+// validated and routed but not published, and 200 OK returns how it would
+// be handled: a new parent, a child of which parent, or a duplicate.
 func (h *IngestHandler) IngestEvent(c *fiber.Ctx) error {
 	var event domain.Event
 	if err := c.BodyParser(&event); err != nil {
@@ -33,23 +74,246 @@ func (h *IngestHandler) IngestEvent(c *fiber.Ctx) error {
 		return BadRequest(c, "invalid request body")
 	}
 
+	// Select the event manager if the sender left it out
+	if err := h.router.Route(c.Context(), &event); err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to route event", "error", err, "dedupKey", event.DedupKey)
+		return InternalError(c, "failed to route event")
+	}
+
+	return h.ingest(c, "api", &event)
+}
+
+// IngestWithToken handles POST /v1/events/:ingest_token
+// Like IngestEvent, but the event manager is identified by the ingest token in
+// the URL, so senders need no headers or event_manager_id field. An unknown
+// token returns 401 Unauthorized.
+func (h *IngestHandler) IngestWithToken(c *fiber.Ctx) error {
+	var event domain.Event
+	if err := c.BodyParser(&event); err != nil {
+		h.logger.Debug("failed to parse event body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+
+	emID, err := h.service.ResolveIngestToken(c.Context(), c.Params("ingest_token"))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidIngestToken) {
+			return Unauthorized(c, err.Error())
+		}
+		h.logger.Error("failed to resolve ingest token", "error", err)
+		return InternalError(c, "failed to resolve ingest token")
+	}
+	if event.EventManagerID != "" && event.EventManagerID != emID {
+		return ValidationError(c, "event_manager_id does not match the ingest token")
+	}
+	event.EventManagerID = emID
+
+	return h.ingest(c, "ingest_token", &event)
+}
+
+// IngestWebhook handles POST /v1/webhooks/:ingest_token
+// Receives the native JSON payload of a third-party tool and maps it to an
+// event with the webhook transform of the token's event manager, so tools
+// need no adapter. An unknown token or a webhook not signed with the event
+// manager's secret returns 401 Unauthorized; an event manager without a
+// transform returns 404 Not Found.
+func (h *IngestHandler) IngestWebhook(c *fiber.Ctx) error {
+	header := func(key string) string { return c.Get(key) }
+	event, err := h.service.TransformWebhook(c.Context(), c.Params("ingest_token"), header, c.Body())
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidIngestToken), errors.Is(err, domain.ErrInvalidWebhookSignature):
+			return Unauthorized(c, err.Error())
+		case errors.Is(err, domain.ErrNoWebhookTransform):
+			return NotFound(c, err.Error())
+		case errors.Is(err, domain.ErrInvalidWebhookPayload), errors.Is(err, domain.ErrInvalidWebhookTransform):
+			return ValidationError(c, err.Error())
+		}
+		h.logger.Error("failed to transform webhook", "error", err)
+		return InternalError(c, "failed to transform webhook")
+	}
+
+	return h.ingest(c, "webhook", event)
+}
+
+// IngestIntegration handles POST /v1/integrations/:integration/:ingest_token
+// Receives the native webhooks of CI systems (GitHub workflow runs, GitLab
+// pipelines), error trackers (Sentry issues, Rollbar items) and cloud
+// monitoring (Azure Monitor, Google Cloud Monitoring) and turns failures into
+// trigger events and fixes into resolves. Webhooks about
+// anything else are acknowledged with 200 OK and ignored, so the sender
+// doesn't report delivery failures. An unknown token or a webhook not signed
+// with the event manager's secret for the integration returns 401
+// Unauthorized; an unknown integration 404 Not Found.
+func (h *IngestHandler) IngestIntegration(c *fiber.Ctx) error {
+	integration := c.Params("integration")
+	header := func(key string) string { return c.Get(key) }
+	emID, err := h.service.ResolveSignedWebhook(c.Context(), c.Params("ingest_token"), integration, header, c.Body())
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidIngestToken) || errors.Is(err, domain.ErrInvalidWebhookSignature) {
+			return Unauthorized(c, err.Error())
+		}
+		h.logger.Error("failed to resolve ingest token", "error", err)
+		return InternalError(c, "failed to resolve ingest token")
+	}
+
+	event, err := domain.ParseIntegrationWebhook(integration, header, c.Body())
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnknownIntegration):
+			return NotFound(c, err.Error())
+		case errors.Is(err, domain.ErrIntegrationWebhookIgnored):
+			h.logger.Debug("ignoring integration webhook", "integration", integration, "reason", err)
+			return Success(c, map[string]string{"status": "ignored"})
+		default:
+			return ValidationError(c, err.Error())
+		}
+	}
+	event.EventManagerID = emID
+
+	return h.ingest(c, "integration:"+integration, event)
+}
+
+// ingest validates an event whose event manager is known and submits it,
+// recording the client it was received from and the endpoint, via.
+func (h *IngestHandler) ingest(c *fiber.Ctx, via string, event *domain.Event) error {
 	// Validate the event
 	if err := event.Validate(); err != nil {
 		h.logger.Debug("event validation failed", "error", err)
 		return ValidationError(c, err.Error())
 	}
 
+	// The request ID correlates the logs of the event across components, and
+	// the trace context of the request is passed on with the event
+	ctx := logging.WithCorrelationID(c.Context(), c.GetRespHeader(fiber.HeaderXRequestID))
+	ctx = logging.WithTraceContext(ctx, logging.TraceContext{
+		TraceParent: c.Get(queue.TraceParentHeader),
+		TraceState:  c.Get(queue.TraceStateHeader),
+	})
+
+	// With dry_run, report how the event would be handled instead
+	if c.QueryBool("dry_run") {
+		result, err := h.service.DryRun(ctx, event, h.stateStore)
+		if err != nil {
+			return h.ingestFailed(c, event, err)
+		}
+		return Success(c, result)
+	}
+
 	// Submit event for processing
-	if err := h.service.IngestEvent(c.Context(), &event); err != nil {
-		h.logger.Error("failed to ingest event", "error", err, "dedupKey", event.DedupKey)
-		return InternalError(c, "failed to ingest event")
+	ctx = ingest.WithOrigin(ctx, domain.EventOrigin{
+		IP:        c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		Via:       via,
+	})
+	eventID := ingest.NewEventID()
+	ctx = ingest.WithEventID(ctx, eventID)
+	if err := h.service.IngestEvent(ctx, event); err != nil {
+		return h.ingestFailed(c, event, err)
 	}
 
-	h.logger.Debug("event accepted", "dedupKey", event.DedupKey, "action", event.Action)
+	h.logger.DebugContext(ctx, "event accepted", "dedupKey", event.DedupKey, "action", event.Action)
 
 	// Return 202 Accepted - event will be processed asynchronously
-	return Accepted(c, map[string]string{
-		"status":   "accepted",
-		"dedupKey": event.DedupKey,
-	})
+	response := map[string]any{
+		"status":           "accepted",
+		"event_manager_id": event.EventManagerID,
+		"dedupKey":         event.DedupKey,
+	}
+	if h.tracker != nil {
+		response["event_id"] = eventID
+		response["status_link"] = eventStatusLink(eventID)
+	}
+	if status := h.service.QuotaStatus(event.EventManagerID); status != nil {
+		response["quota"] = status
+	}
+	if event.Action == domain.ActionTrigger {
+		hint := h.duplicateOf(c, event.DedupKey)
+		response["duplicate"] = hint != nil
+		if hint != nil {
+			response["alert"] = hint
+		}
+	}
+	return Accepted(c, response)
+}
+
+// EventStatus handles GET /v1/events/:eventId/status
+// Returns where an event accepted by ingest is in the pipeline: queued,
+// processing, which alert it created, reactivated or was deduplicated into,
+// or why it was dropped or failed. Statuses are kept for the configured
+// event status TTL after their last update; unknown and expired event IDs
+// return 404 Not Found.
+func (h *IngestHandler) EventStatus(c *fiber.Ctx) error {
+	status, err := h.tracker.Get(c.Context(), c.Params("eventId"))
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get event status", "error", err)
+		return InternalError(c, "failed to get event status")
+	}
+	return Success(c, status)
+}
+
+// duplicateOf returns the active alert of a dedup key, or nil if there is
+// none. The hint reflects the alert when the event was accepted, before it
+// is processed; it is left out if the state store can't be read.
+func (h *IngestHandler) duplicateOf(c *fiber.Ctx, dedupKey string) *duplicateHint {
+	if h.stateStore == nil {
+		return nil
+	}
+	state, err := h.stateStore.GetAlert(c.Context(), dedupKey)
+	if err != nil {
+		h.logger.Debug("failed to look up alert state for duplicate hint", "dedupKey", dedupKey, "error", err)
+		return nil
+	}
+	if state == nil || state.Status != string(domain.AlertStatusActive) {
+		return nil
+	}
+
+	hint := &duplicateHint{
+		DedupKey:       state.DedupKey,
+		Status:         state.Status,
+		Type:           state.Type,
+		ParentDedupKey: state.ParentDedupKey,
+		Link:           alertLink(state.DedupKey),
+	}
+	if state.ParentDedupKey != "" {
+		hint.ParentLink = alertLink(state.ParentDedupKey)
+	}
+	return hint
+}
+
+// ingestFailed responds to an event the ingest service failed.
+func (h *IngestHandler) ingestFailed(c *fiber.Ctx, event *domain.Event, err error) error {
+	switch {
+	case errors.Is(err, ingest.ErrEventManagerNotFound):
+		return Unprocessable(c, ErrCodeEventManagerNotFound, err.Error())
+	case errors.Is(err, ingest.ErrEventManagerDeleted):
+		return Gone(c, "event manager has been deleted")
+	case errors.Is(err, ingest.ErrGroupingRuleNotFound):
+		return Unprocessable(c, ErrCodeGroupingRuleNotFound, err.Error())
+	case errors.Is(err, domain.ErrEmptyDedupKey):
+		return ValidationError(c, err.Error())
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		return QuotaExceeded(c, err.Error())
+	case errors.Is(err, ingest.ErrBacklogged):
+		return Unavailable(c, ErrCodeQueueUnavailable, time.Second, err.Error())
+	case errors.Is(err, ingest.ErrPublishFailed):
+		return Unavailable(c, ErrCodeQueueUnavailable, 5*time.Second, err.Error())
+	}
+	h.logger.Error("failed to ingest event", "error", err, "dedupKey", event.DedupKey)
+	return InternalError(c, "failed to ingest event")
+}
+
+// eventStatusLink returns the API path of the status of an event.
+func eventStatusLink(eventID string) string {
+	return "/v1/events/" + url.PathEscape(eventID) + "/status"
+}
+
+// alertLink returns the API path of an alert.
+func alertLink(dedupKey string) string {
+	return "/v1/alerts/" + url.PathEscape(dedupKey)
 }
@@ -0,0 +1,380 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/pkg/argustest"
+)
+
+// eventBody returns the JSON body of an event of class disk for an event
+// manager.
+func eventBody(emID, action, dedupKey string) string {
+	return `{"event_manager_id":"` + emID + `","summary":"Disk full","action":"` + action + `","class":"disk","dedupKey":"` + dedupKey + `"}`
+}
+
+func TestIngestHandler_ErrorCodes(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	post := func(body string) (int, string) {
+		t.Helper()
+		var result errorCode
+		status := call(t, h, http.MethodPost, "/v1/events", body, &result)
+		return status, result.Error.Code
+	}
+
+	if status, code := post(eventBody("missing", "trigger", "disk-1")); status != http.StatusUnprocessableEntity || code != "EVENT_MANAGER_NOT_FOUND" {
+		t.Errorf("unknown event manager = (%d, %s), want (422, EVENT_MANAGER_NOT_FOUND)", status, code)
+	}
+	if status, code := post(`{"event_manager_id":"` + emID + `","action":"trigger","class":"disk","dedupKey":"disk-1"}`); status != http.StatusBadRequest || code != "VALIDATION_FAILED" {
+		t.Errorf("event without summary = (%d, %s), want (400, VALIDATION_FAILED)", status, code)
+	}
+
+	if err := h.GroupingRuleRepo.Purge(context.Background(), eventManager(t, h, emID).GroupingRuleID); err != nil {
+		t.Fatalf("Purge grouping rule error: %v", err)
+	}
+	if status, code := post(eventBody(emID, "trigger", "disk-1")); status != http.StatusUnprocessableEntity || code != "GROUPING_RULE_NOT_FOUND" {
+		t.Errorf("missing grouping rule = (%d, %s), want (422, GROUPING_RULE_NOT_FOUND)", status, code)
+	}
+}
+
+func TestIngestHandler_IngestToken(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	em := eventManager(t, h, emID)
+	if em.IngestToken == "" {
+		t.Fatal("event manager has no ingest token")
+	}
+
+	post := func(token, body string) int {
+		t.Helper()
+		return call(t, h, http.MethodPost, "/v1/events/"+token, body, nil)
+	}
+
+	event := `{"summary":"Disk full","severity":"low","action":"trigger","class":"disk","dedupKey":"disk-1"}`
+	if status := post(em.IngestToken, event); status != http.StatusAccepted {
+		t.Fatalf("ingest with token status = %d, want 202", status)
+	}
+	h.Sync(t)
+	h.AwaitStatus(t, "disk-1", domain.AlertStatusActive)
+
+	if status := post("unknown", event); status != http.StatusUnauthorized {
+		t.Errorf("ingest with an unknown token status = %d, want 401", status)
+	}
+	mismatch := `{"event_manager_id":"other","summary":"Disk full","action":"trigger","dedupKey":"disk-2"}`
+	if status := post(em.IngestToken, mismatch); status != http.StatusBadRequest {
+		t.Errorf("ingest with a mismatched event_manager_id status = %d, want 400", status)
+	}
+
+	// Rotating the token revokes the old one
+	var rotated struct {
+		Data domain.EventManager `json:"data"`
+	}
+	status := call(t, h, http.MethodPost, "/v1/event-managers/"+emID+"/ingest-token", "", &rotated)
+	if status != http.StatusOK || rotated.Data.IngestToken == "" || rotated.Data.IngestToken == em.IngestToken {
+		t.Fatalf("rotate = (%d, %q), want (200, a new token)", status, rotated.Data.IngestToken)
+	}
+	if status := post(em.IngestToken, event); status != http.StatusUnauthorized {
+		t.Errorf("ingest with the rotated-out token status = %d, want 401", status)
+	}
+	if status := post(rotated.Data.IngestToken, event); status != http.StatusAccepted {
+		t.Errorf("ingest with the new token status = %d, want 202", status)
+	}
+}
+
+func TestIngestHandler_EventOrigin(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	body := `{"event_manager_id":"` + emID + `","summary":"Disk full","source":"nagios","action":"trigger","class":"disk","dedupKey":"disk-1"}`
+	req := newRequest(t, h, http.MethodPost, "/v1/events", body)
+	req.Header.Set("User-Agent", "nagios-notifier/4.4")
+	if status := send(t, req, nil); status != http.StatusAccepted {
+		t.Fatalf("POST event status = %d, want 202", status)
+	}
+	h.Sync(t)
+	alert := h.AwaitStatus(t, "disk-1", domain.AlertStatusActive)
+
+	want := domain.EventOrigin{Source: "nagios", IP: "127.0.0.1", UserAgent: "nagios-notifier/4.4", Via: "api"}
+	if alert.Origin == nil || *alert.Origin != want {
+		t.Fatalf("alert origin = %+v, want %+v", alert.Origin, want)
+	}
+
+	query := url.Values{
+		"event_manager_id": {emID},
+		"from":             {h.Now().Add(-time.Hour).Format(time.RFC3339)},
+		"to":               {h.Now().Add(time.Hour).Format(time.RFC3339)},
+	}
+	var report struct {
+		Data domain.SourceReport `json:"data"`
+	}
+	if status := call(t, h, http.MethodGet, "/v1/reports/sources?"+query.Encode(), "", &report); status != http.StatusOK {
+		t.Fatalf("GET source report status = %d, want 200", status)
+	}
+	if sources := report.Data.Sources; len(sources) != 1 || sources[0].UserAgent != want.UserAgent || sources[0].AlertCount != 1 {
+		t.Errorf("sources = %+v, want one for %+v", sources, want)
+	}
+}
+
+func TestIngestHandler_DuplicateHint(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	type hint struct {
+		Duplicate bool `json:"duplicate"`
+		Alert     *struct {
+			DedupKey       string `json:"dedupKey"`
+			Status         string `json:"status"`
+			ParentDedupKey string `json:"parent_dedupKey"`
+			Link           string `json:"link"`
+			ParentLink     string `json:"parent_link"`
+		} `json:"alert"`
+	}
+	trigger := func(dedupKey string) hint {
+		t.Helper()
+		var result struct {
+			Data hint `json:"data"`
+		}
+		if status := call(t, h, http.MethodPost, "/v1/events", eventBody(emID, "trigger", dedupKey), &result); status != http.StatusAccepted {
+			t.Fatalf("POST event status = %d, want 202", status)
+		}
+		return result.Data
+	}
+
+	if got := trigger("disk-1"); got.Duplicate || got.Alert != nil {
+		t.Errorf("first trigger = %+v, want no duplicate", got)
+	}
+	trigger("disk-2")
+	h.Sync(t)
+	h.AwaitStatus(t, "disk-2", domain.AlertStatusActive)
+
+	got := trigger("disk-2")
+	if !got.Duplicate || got.Alert == nil {
+		t.Fatalf("repeated trigger = %+v, want a duplicate", got)
+	}
+	if a := got.Alert; a.DedupKey != "disk-2" || a.Status != "active" || a.Link != "/v1/alerts/disk-2" ||
+		a.ParentDedupKey != "disk-1" || a.ParentLink != "/v1/alerts/disk-1" {
+		t.Errorf("duplicate alert = %+v, want active child disk-2 of disk-1", *a)
+	}
+}
+
+func TestIngestHandler_DryRun(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	dryRun := func(action, dedupKey string) domain.IngestDryRun {
+		t.Helper()
+		var result struct {
+			Data domain.IngestDryRun `json:"data"`
+		}
+		if status := call(t, h, http.MethodPost, "/v1/events?dry_run=true", eventBody(emID, action, dedupKey), &result); status != http.StatusOK {
+			t.Fatalf("dry run status = %d, want 200", status)
+		}
+		return result.Data
+	}
+
+	got := dryRun("trigger", "disk-1")
+	if got.Outcome != domain.GroupingOutcomeParent || got.GroupingKey != "class" || got.GroupingValue != "disk" {
+		t.Errorf("dry run without alerts = %+v, want a new parent grouped by class disk", got)
+	}
+	h.Sync(t)
+	if alert, _ := h.AlertRepo.GetByDedupKey(context.Background(), "disk-1"); alert != nil {
+		t.Fatal("dry run should not create an alert")
+	}
+
+	ingest(t, h, emID, domain.ActionTrigger, "disk", "disk-1")
+	h.AwaitStatus(t, "disk-1", domain.AlertStatusActive)
+
+	if got := dryRun("trigger", "disk-2"); got.Outcome != domain.GroupingOutcomeChild || got.ParentDedupKey != "disk-1" {
+		t.Errorf("dry run of another disk trigger = %+v, want a child of disk-1", got)
+	}
+	if got := dryRun("trigger", "disk-1"); got.Outcome != domain.GroupingOutcomeDuplicate || got.AlertStatus != "active" {
+		t.Errorf("dry run of a repeated trigger = %+v, want a duplicate", got)
+	}
+	if got := dryRun("resolve", "disk-1"); got.Outcome != domain.GroupingOutcomeResolve {
+		t.Errorf("dry run of a resolve = %+v, want resolve", got)
+	}
+	if got := dryRun("resolve", "disk-3"); got.Outcome != domain.GroupingOutcomeIgnored {
+		t.Errorf("dry run of a resolve without alert = %+v, want ignored", got)
+	}
+}
+
+func TestIngestHandler_EventStatus(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	post := func(action, dedupKey string) (eventID, statusLink string) {
+		t.Helper()
+		var result struct {
+			Data struct {
+				EventID    string `json:"event_id"`
+				StatusLink string `json:"status_link"`
+			} `json:"data"`
+		}
+		if status := call(t, h, http.MethodPost, "/v1/events", eventBody(emID, action, dedupKey), &result); status != http.StatusAccepted || result.Data.EventID == "" {
+			t.Fatalf("POST event status = %d, event ID %q, want 202 with an event ID", status, result.Data.EventID)
+		}
+		return result.Data.EventID, result.Data.StatusLink
+	}
+	status := func(link string) (int, domain.EventStatus) {
+		t.Helper()
+		var result struct {
+			Data domain.EventStatus `json:"data"`
+		}
+		code := call(t, h, http.MethodGet, link, "", &result)
+		return code, result.Data
+	}
+
+	created, createdLink := post("trigger", "disk-1")
+	if createdLink != "/v1/events/"+created+"/status" {
+		t.Errorf("status link = %q, want the status of event %s", createdLink, created)
+	}
+	h.Sync(t)
+	_, duplicateLink := post("trigger", "disk-1")
+	_, droppedLink := post("resolve", "missing")
+	h.Sync(t)
+
+	tests := []struct {
+		name   string
+		link   string
+		state  domain.EventState
+		reason string
+	}{
+		{"new alert", createdLink, domain.EventAlertCreated, ""},
+		{"repeated trigger", duplicateLink, domain.EventDeduplicated, ""},
+		{"resolve of a missing alert", droppedLink, domain.EventDropped, "no alert with the dedup key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, got := status(tt.link)
+			if code != http.StatusOK {
+				t.Fatalf("GET event status = %d, want 200", code)
+			}
+			if got.State != tt.state || got.Reason != tt.reason || got.EventManagerID != emID {
+				t.Errorf("status = %+v, want %s with reason %q", got, tt.state, tt.reason)
+			}
+		})
+	}
+
+	if _, got := status(createdLink); got.DedupKey != "disk-1" || got.Action != domain.ActionTrigger || got.ReceivedAt.IsZero() {
+		t.Errorf("status = %+v, want the trigger of disk-1", got)
+	}
+	if code, _ := status("/v1/events/unknown/status"); code != http.StatusNotFound {
+		t.Errorf("GET unknown event status = %d, want 404", code)
+	}
+}
+
+func TestIngestHandler_WebhookTransform(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+	token := eventManager(t, h, emID).IngestToken
+
+	post := func(token, body string) int {
+		t.Helper()
+		return call(t, h, http.MethodPost, "/v1/webhooks/"+token, body, nil)
+	}
+
+	webhook := `{"event":"incident.triggered","incident":{"id":7,"title":"Checkout down","urgency":"high","service":"payments"}}`
+	if status := post(token, webhook); status != http.StatusNotFound {
+		t.Errorf("webhook without a transform status = %d, want 404", status)
+	}
+
+	// The transform applies to the next webhook, without a restart
+	updateEventManager(t, h, emID, func(em *domain.EventManager) {
+		em.WebhookTransform = domain.WebhookTransform{
+			DedupKey: `"incident-" + string(payload.incident.id)`,
+			Summary:  `payload.incident.title`,
+			Severity: `payload.incident.urgency == "high" ? "high" : "low"`,
+			Action:   `payload.event == "incident.resolved" ? "resolve" : "trigger"`,
+			Class:    `payload.incident.service`,
+		}
+	})
+
+	if status := post(token, webhook); status != http.StatusAccepted {
+		t.Fatalf("webhook status = %d, want 202", status)
+	}
+	h.Sync(t)
+	alert := h.AwaitStatus(t, "incident-7", domain.AlertStatusActive)
+	if alert.Summary != "Checkout down" || alert.Severity != domain.SeverityHigh || alert.Class != "payments" {
+		t.Errorf("alert = (%q, %q, %q), want (Checkout down, high, payments)", alert.Summary, alert.Severity, alert.Class)
+	}
+
+	resolved := strings.Replace(webhook, "incident.triggered", "incident.resolved", 1)
+	if status := post(token, resolved); status != http.StatusAccepted {
+		t.Fatalf("resolve webhook status = %d, want 202", status)
+	}
+	h.Sync(t)
+	h.AwaitStatus(t, "incident-7", domain.AlertStatusResolved)
+
+	if status := post(token, `{"incident":{"id":{"nested":1}}}`); status != http.StatusBadRequest {
+		t.Errorf("webhook with an unmappable payload status = %d, want 400", status)
+	}
+	if status := post(token, `not json`); status != http.StatusBadRequest {
+		t.Errorf("webhook with an invalid body status = %d, want 400", status)
+	}
+	if status := post("unknown", webhook); status != http.StatusUnauthorized {
+		t.Errorf("webhook with an unknown token status = %d, want 401", status)
+	}
+}
+
+func TestIngestHandler_Integrations(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+	token := eventManager(t, h, emID).IngestToken
+
+	post := func(provider, token, eventType, body string) int {
+		t.Helper()
+		req := newRequest(t, h, http.MethodPost, "/v1/integrations/"+provider+"/"+token, body)
+		req.Header.Set("X-GitHub-Event", eventType)
+		req.Header.Set("X-Gitlab-Event", eventType)
+		req.Header.Set("Sentry-Hook-Resource", eventType)
+		return send(t, req, nil)
+	}
+	run := func(conclusion string) string {
+		return `{"action":"completed","workflow_run":{"name":"CI","conclusion":"` + conclusion +
+			`","head_branch":"main"},"repository":{"full_name":"acme/shop"}}`
+	}
+
+	if status := post("github", token, "workflow_run", run("failure")); status != http.StatusAccepted {
+		t.Fatalf("failed workflow status = %d, want 202", status)
+	}
+	h.Sync(t)
+	alert := h.AwaitStatus(t, "github:acme/shop:CI", domain.AlertStatusActive)
+	if alert.Class != "acme/shop" {
+		t.Errorf("alert class = %q, want acme/shop", alert.Class)
+	}
+
+	if status := post("github", token, "workflow_run", run("success")); status != http.StatusAccepted {
+		t.Fatalf("successful workflow status = %d, want 202", status)
+	}
+	h.Sync(t)
+	h.AwaitStatus(t, "github:acme/shop:CI", domain.AlertStatusResolved)
+
+	if status := post("github", token, "ping", `{}`); status != http.StatusOK {
+		t.Errorf("ping status = %d, want 200", status)
+	}
+	pipeline := `{"object_attributes":{"id":1,"ref":"main","status":"failed"},"project":{"path_with_namespace":"acme/api"}}`
+	if status := post("gitlab", token, "Pipeline Hook", pipeline); status != http.StatusAccepted {
+		t.Errorf("failed pipeline status = %d, want 202", status)
+	}
+	issue := `{"action":"created","data":{"issue":{"id":"42","title":"TypeError","project":{"slug":"shop"}}}}`
+	if status := post("sentry", token, "issue", issue); status != http.StatusAccepted {
+		t.Fatalf("new Sentry issue status = %d, want 202", status)
+	}
+	h.Sync(t)
+	h.AwaitStatus(t, "sentry:42", domain.AlertStatusActive)
+
+	if status := post("jenkins", token, "build", `{}`); status != http.StatusNotFound {
+		t.Errorf("unknown integration status = %d, want 404", status)
+	}
+	if status := post("github", "unknown", "workflow_run", run("failure")); status != http.StatusUnauthorized {
+		t.Errorf("unknown token status = %d, want 401", status)
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"argus-go/internal/config"
+)
+
+// dial connects to a listener and returns the connection it accepted.
+func dial(t *testing.T, ln net.Listener, network, address string) net.Conn {
+	t.Helper()
+	client, err := net.DialTimeout(network, address, time.Second)
+	if err != nil {
+		t.Fatalf("Dial(%s) error: %v", address, err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("Accept() error: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestListen_Single(t *testing.T) {
+	ln, err := listen([]string{"127.0.0.1:0"})
+	if err != nil {
+		t.Fatalf("listen() error: %v", err)
+	}
+	defer ln.Close()
+
+	// A single address needs no multiListener
+	if _, ok := ln.(*multiListener); ok {
+		t.Error("listen() returned a multiListener for one address")
+	}
+	dial(t, ln, "tcp", ln.Addr().String())
+}
+
+func TestListen_UnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "argus.sock")
+
+	// A stale socket file left by a previous run is replaced
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+
+	ln, err := listen([]string{config.UnixAddressPrefix + path})
+	if err != nil {
+		t.Fatalf("listen() error: %v", err)
+	}
+	defer ln.Close()
+
+	if ln.Addr().Network() != "unix" {
+		t.Errorf("Addr().Network() = %q, want unix", ln.Addr().Network())
+	}
+	dial(t, ln, "unix", path)
+}
+
+func TestListen_Multiple(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "argus.sock")
+	ln, err := listen([]string{"127.0.0.1:0", config.UnixAddressPrefix + path})
+	if err != nil {
+		t.Fatalf("listen() error: %v", err)
+	}
+
+	// Connections to every address are accepted by the one listener
+	m, ok := ln.(*multiListener)
+	if !ok {
+		t.Fatalf("listen() = %T, want *multiListener", ln)
+	}
+	if got, want := ln.Addr(), m.listeners[0].Addr(); got != want {
+		t.Errorf("Addr() = %v, want the first address %v", got, want)
+	}
+	dial(t, ln, "tcp", m.listeners[0].Addr().String())
+	dial(t, ln, "unix", path)
+
+	// Closing closes every listener and unblocks Accept
+	if err := ln.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	if _, err := ln.Accept(); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("Accept() after Close() error = %v, want net.ErrClosed", err)
+	}
+	if _, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		t.Error("Dial() after Close() succeeded, want the socket closed")
+	}
+	if err := ln.Close(); err != nil {
+		t.Errorf("second Close() error: %v", err)
+	}
+}
+
+func TestListen_FailureClosesOpened(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error: %v", err)
+	}
+	defer taken.Close()
+
+	path := filepath.Join(t.TempDir(), "argus.sock")
+	if _, err := listen([]string{config.UnixAddressPrefix + path, taken.Addr().String()}); err == nil {
+		t.Fatal("listen() on a taken address succeeded, want an error")
+	}
+
+	// The socket opened before the failure is closed again
+	if _, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		t.Error("Dial() succeeded, want the socket opened before the failure closed")
+	}
+}
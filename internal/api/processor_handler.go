@@ -0,0 +1,56 @@
+package api
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+
+	"argus-go/internal/processor"
+	"argus-go/internal/scaling"
+)
+
+// ProcessorHandler handles HTTP requests for pausing and resuming event
+// consumption, e.g. during store maintenance, and for the autoscaling signal.
+type ProcessorHandler struct {
+	processor *processor.Service
+	scaling   *scaling.Monitor
+	logger    *slog.Logger
+}
+
+// NewProcessorHandler creates a new processor handler. The scaling monitor
+// is nil if the autoscaling signal is disabled.
+func NewProcessorHandler(processor *processor.Service, scaling *scaling.Monitor, logger *slog.Logger) *ProcessorHandler {
+	return &ProcessorHandler{
+		processor: processor,
+		scaling:   scaling,
+		logger:    logger,
+	}
+}
+
+// Status handles GET /v1/admin/processor
+// Returns whether event consumption is paused.
+func (h *ProcessorHandler) Status(c *fiber.Ctx) error {
+	return Success(c, h.processor.Status())
+}
+
+// Pause handles POST /v1/admin/processor/pause
+// Stops consuming events; the API keeps accepting them. Idempotent.
+func (h *ProcessorHandler) Pause(c *fiber.Ctx) error {
+	return Success(c, h.processor.Pause())
+}
+
+// Resume handles POST /v1/admin/processor/resume
+// Continues consuming events, starting with those queued while paused. Idempotent.
+func (h *ProcessorHandler) Resume(c *fiber.Ctx) error {
+	return Success(c, h.processor.Resume())
+}
+
+// Scaling handles GET /v1/admin/scaling
+// Returns the autoscaling signal of this replica: the pressure on the
+// processor, 1 when it keeps up with its targets, and what it derives from.
+func (h *ProcessorHandler) Scaling(c *fiber.Ctx) error {
+	if h.scaling == nil {
+		return NotFound(c, "the scaling signal is disabled")
+	}
+	return Success(c, h.scaling.Signal())
+}
@@ -0,0 +1,375 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// QueryRuleHandler handles HTTP requests for query rule operations.
+type QueryRuleHandler struct {
+	repo             store.QueryRuleRepository
+	executionRepo    store.RuleExecutionRepository
+	eventManagerRepo store.EventManagerRepository
+	datasources      map[string]string
+	clock            clock.Clock
+	logger           *slog.Logger
+}
+
+// NewQueryRuleHandler creates a new query rule handler. Rules must name one
+// of the configured datasources, and their event manager must exist.
+func NewQueryRuleHandler(
+	repo store.QueryRuleRepository,
+	executionRepo store.RuleExecutionRepository,
+	eventManagerRepo store.EventManagerRepository,
+	datasources []config.DatasourceConfig,
+	clk clock.Clock,
+	logger *slog.Logger,
+) *QueryRuleHandler {
+	types := make(map[string]string, len(datasources))
+	for _, ds := range datasources {
+		types[ds.Name] = ds.Type
+	}
+	return &QueryRuleHandler{
+		repo:             repo,
+		executionRepo:    executionRepo,
+		eventManagerRepo: eventManagerRepo,
+		datasources:      types,
+		clock:            clk,
+		logger:           logger,
+	}
+}
+
+// Create handles POST /v1/rules
+// Creates a new query rule, run from the next check of the runner.
+func (h *QueryRuleHandler) Create(c *fiber.Ctx) error {
+	var req domain.CreateQueryRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+
+	// Validate the request
+	if err := req.Validate(); err != nil {
+		h.logger.Debug("validation failed", "error", err)
+		return ValidationError(c, err.Error())
+	}
+
+	// Generate ID and create the rule
+	rule := req.ToQueryRule(uuid.New().String(), h.clock.Now().UTC())
+	if message, err := h.check(c.Context(), rule, ""); err != nil {
+		return InternalError(c, "failed to check rule")
+	} else if message != "" {
+		return ValidationError(c, message)
+	}
+
+	if err := h.repo.Create(c.Context(), rule); err != nil {
+		h.logger.Error("failed to create query rule", "error", err)
+		return InternalError(c, "failed to create rule")
+	}
+
+	h.logger.Info("created query rule", "id", rule.ID, "name", rule.Name, "datasource", rule.Datasource)
+	return Created(c, rule)
+}
+
+// List handles GET /v1/rules
+// Returns all query rules, oldest first. Accepts template_id to return the
+// instances of a rule template only.
+func (h *QueryRuleHandler) List(c *fiber.Ctx) error {
+	rules, err := h.repo.List(c.Context())
+	if err != nil {
+		h.logger.Error("failed to list query rules", "error", err)
+		return InternalError(c, "failed to list rules")
+	}
+	if templateID := c.Query("template_id"); templateID != "" {
+		rules = templateInstances(rules, templateID)
+	}
+
+	return SuccessWithLastModified(c, rules, latestUpdate(rules, func(rule *domain.QueryRule) time.Time {
+		return rule.UpdatedAt
+	}))
+}
+
+// GetByID handles GET /v1/rules/:id
+// Returns a single query rule by ID.
+func (h *QueryRuleHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	rule, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get query rule", "id", id, "error", err)
+		return InternalError(c, "failed to get rule")
+	}
+
+	return SuccessWithLastModified(c, rule, rule.UpdatedAt)
+}
+
+// Update handles PUT /v1/rules/:id
+// Updates an existing query rule.
+func (h *QueryRuleHandler) Update(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	var req domain.UpdateQueryRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+
+	// Validate the request
+	if err := req.Validate(); err != nil {
+		h.logger.Debug("validation failed", "error", err)
+		return ValidationError(c, err.Error())
+	}
+
+	// Fetch existing rule
+	rule, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get query rule", "id", id, "error", err)
+		return InternalError(c, "failed to get rule")
+	}
+	if rule.TemplateID != "" {
+		return Conflict(c, domain.ErrRuleManagedByTemplate.Error()+"; update rule template "+rule.TemplateID+" instead")
+	}
+
+	// Apply, check and persist changes
+	previousEventManagerID := rule.EventManagerID
+	req.ApplyTo(rule, h.clock.Now().UTC())
+	if message, err := h.check(c.Context(), rule, previousEventManagerID); err != nil {
+		return InternalError(c, "failed to check rule")
+	} else if message != "" {
+		return ValidationError(c, message)
+	}
+	if err := h.repo.Update(c.Context(), rule); err != nil {
+		h.logger.Error("failed to update query rule", "id", id, "error", err)
+		return InternalError(c, "failed to update rule")
+	}
+
+	h.logger.Info("updated query rule", "id", rule.ID)
+	return Success(c, rule)
+}
+
+// Delete handles DELETE /v1/rules/:id
+// Permanently removes a query rule. The runner resolves its alerts at its
+// next check.
+func (h *QueryRuleHandler) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	if err := h.repo.Delete(c.Context(), id); err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to delete query rule", "id", id, "error", err)
+		return InternalError(c, "failed to delete rule")
+	}
+
+	if err := h.executionRepo.DeleteByRule(c.Context(), id); err != nil {
+		h.logger.Warn("failed to delete rule executions", "id", id, "error", err)
+	}
+
+	h.logger.Info("deleted query rule", "id", id)
+	return NoContent(c)
+}
+
+// Enable handles POST /v1/rules/:id/enable
+// Runs a disabled rule again from the next check of the runner.
+func (h *QueryRuleHandler) Enable(c *fiber.Ctx) error {
+	return h.modify(c, "enabled", func(rule *domain.QueryRule) {
+		rule.Disabled = false
+	})
+}
+
+// Disable handles POST /v1/rules/:id/disable
+// Stops running a rule without deleting it. The runner resolves its alerts
+// at its next check.
+func (h *QueryRuleHandler) Disable(c *fiber.Ctx) error {
+	return h.modify(c, "disabled", func(rule *domain.QueryRule) {
+		rule.Disabled = true
+	})
+}
+
+// Mute handles POST /v1/rules/:id/mute
+// Keeps a rule from raising new alerts until a time, or for a duration.
+func (h *QueryRuleHandler) Mute(c *fiber.Ctx) error {
+	var req domain.MuteQueryRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+	until, err := req.MutedUntil(h.clock.Now().UTC())
+	if err != nil {
+		return ValidationError(c, err.Error())
+	}
+
+	return h.modify(c, "muted", func(rule *domain.QueryRule) {
+		rule.MutedUntil = &until
+	})
+}
+
+// Unmute handles DELETE /v1/rules/:id/mute
+// Lets a muted rule raise alerts again.
+func (h *QueryRuleHandler) Unmute(c *fiber.Ctx) error {
+	return h.modify(c, "unmuted", func(rule *domain.QueryRule) {
+		rule.MutedUntil = nil
+	})
+}
+
+// modify applies a change to a query rule and persists it.
+func (h *QueryRuleHandler) modify(c *fiber.Ctx, change string, apply func(rule *domain.QueryRule)) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	rule, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get query rule", "id", id, "error", err)
+		return InternalError(c, "failed to get rule")
+	}
+
+	apply(rule)
+	rule.UpdatedAt = h.clock.Now().UTC()
+	if err := h.repo.Update(c.Context(), rule); err != nil {
+		h.logger.Error("failed to update query rule", "id", id, "error", err)
+		return InternalError(c, "failed to update rule")
+	}
+
+	h.logger.Info(change+" query rule", "id", rule.ID)
+	return Success(c, rule)
+}
+
+// Executions handles GET /v1/rules/:id/executions
+// Returns the latest runs of a rule, newest first, to debug why it did or
+// didn't fire. Accepts limit (default 100).
+func (h *QueryRuleHandler) Executions(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	limit := defaultListLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = l
+	}
+
+	if _, err := h.repo.GetByID(c.Context(), id); err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get query rule", "id", id, "error", err)
+		return InternalError(c, "failed to get rule")
+	}
+
+	executions, err := h.executionRepo.ListByRule(c.Context(), id, limit)
+	if err != nil {
+		h.logger.Error("failed to list rule executions", "id", id, "error", err)
+		return InternalError(c, "failed to list rule executions")
+	}
+
+	return Success(c, executions)
+}
+
+// check verifies the references of a rule: its datasource, or the rules it
+// combines, and its event manager unless it is previousEventManagerID. It
+// returns a validation message for an invalid reference, or the error the
+// check failed with.
+func (h *QueryRuleHandler) check(ctx context.Context, rule *domain.QueryRule, previousEventManagerID string) (string, error) {
+	if rule.Composite == nil {
+		if err := h.checkDatasource(rule.Datasource, rule.Params); err != nil {
+			return err.Error(), nil
+		}
+	} else if variable, err := h.checkCompositeRules(ctx, rule.ID, rule.Composite); err != nil {
+		if errors.Is(err, domain.ErrQueryRuleNotFound) || errors.Is(err, domain.ErrInvalidCompositeReference) {
+			return "composite.rules." + variable + ": " + err.Error(), nil
+		}
+		h.logger.Error("failed to get query rule", "id", rule.Composite.Rules[variable], "error", err)
+		return "", err
+	}
+
+	if rule.EventManagerID != previousEventManagerID {
+		if err := h.checkEventManager(ctx, rule.EventManagerID); err != nil {
+			if errors.Is(err, domain.ErrEventManagerNotFound) || errors.Is(err, domain.ErrEventManagerDeleted) {
+				return "event_manager_id " + rule.EventManagerID + ": " + err.Error(), nil
+			}
+			h.logger.Error("failed to get event manager", "id", rule.EventManagerID, "error", err)
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// checkDatasource verifies that the datasource of a rule is configured, and
+// supports the params of the rule.
+func (h *QueryRuleHandler) checkDatasource(name string, params map[string]string) error {
+	dsType, ok := h.datasources[name]
+	if !ok {
+		return fmt.Errorf("datasource %s is not configured", name)
+	}
+	if dsType == config.DatasourceTypeLoki && len(params) > 0 {
+		return fmt.Errorf("datasource %s: params are not supported by loki datasources", name)
+	}
+	return nil
+}
+
+// checkCompositeRules verifies that the rules a composite rule references
+// exist, and run a query. id is the ID of the composite rule, empty for a
+// new one. On failure it returns the variable of the offending rule.
+func (h *QueryRuleHandler) checkCompositeRules(ctx context.Context, id string, composite *domain.CompositeCondition) (string, error) {
+	for _, variable := range slices.Sorted(maps.Keys(composite.Rules)) {
+		ruleID := composite.Rules[variable]
+		if ruleID == id {
+			return variable, domain.ErrInvalidCompositeReference
+		}
+		rule, err := h.repo.GetByID(ctx, ruleID)
+		if err != nil {
+			return variable, err
+		}
+		if rule.Composite != nil {
+			return variable, domain.ErrInvalidCompositeReference
+		}
+	}
+	return "", nil
+}
+
+// checkEventManager verifies that the event manager of a rule exists and is
+// not deleted.
+func (h *QueryRuleHandler) checkEventManager(ctx context.Context, id string) error {
+	em, err := h.eventManagerRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if em.IsDeleted() {
+		return domain.ErrEventManagerDeleted
+	}
+	return nil
+}
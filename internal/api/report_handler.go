@@ -0,0 +1,163 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// ReportHandler handles HTTP requests for alert statistics and reports.
+type ReportHandler struct {
+	repo   store.ReportRepository
+	logger *slog.Logger
+}
+
+// NewReportHandler creates a new report handler.
+func NewReportHandler(repo store.ReportRepository, logger *slog.Logger) *ReportHandler {
+	return &ReportHandler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// MTTR handles GET /v1/reports/mttr
+// Returns mean time to acknowledge and resolve per event manager.
+func (h *ReportHandler) MTTR(c *fiber.Ctx) error {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		return ValidationError(c, err.Error())
+	}
+
+	stats, err := h.repo.MTTR(c.Context(), filter)
+	if err != nil {
+		h.logger.Error("failed to compute mttr report", "error", err)
+		return InternalError(c, "failed to compute mttr report")
+	}
+
+	return Success(c, &domain.MTTRReport{
+		From:          filter.From,
+		To:            filter.To,
+		EventManagers: stats,
+	})
+}
+
+// Volume handles GET /v1/reports/volume
+// Returns alert volume by day and the noisiest dedup keys per event manager.
+func (h *ReportHandler) Volume(c *fiber.Ctx) error {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		return ValidationError(c, err.Error())
+	}
+
+	byDay, err := h.repo.DailyVolume(c.Context(), filter)
+	if err != nil {
+		h.logger.Error("failed to compute volume report", "error", err)
+		return InternalError(c, "failed to compute volume report")
+	}
+
+	top, err := h.repo.TopDedupKeys(c.Context(), filter)
+	if err != nil {
+		h.logger.Error("failed to compute top dedup keys", "error", err)
+		return InternalError(c, "failed to compute volume report")
+	}
+
+	return Success(c, &domain.VolumeReport{
+		From:         filter.From,
+		To:           filter.To,
+		ByDay:        byDay,
+		TopDedupKeys: top,
+	})
+}
+
+// Noise handles GET /v1/reports/noise
+// Returns the noisiest dedup keys and classes ranked by noise score.
+// With suggest=true, dedup keys scoring at or above min_score are returned as suppression suggestions.
+func (h *ReportHandler) Noise(c *fiber.Ctx) error {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		return ValidationError(c, err.Error())
+	}
+
+	minScore := 0.0
+	if c.QueryBool("suggest") {
+		minScore = domain.DefaultNoiseSuggestionScore
+		if raw := c.Query("min_score"); raw != "" {
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil || v <= 0 {
+				return ValidationError(c, "'min_score' must be a positive number")
+			}
+			minScore = v
+		}
+	}
+
+	stats, err := h.repo.NoiseStats(c.Context(), filter)
+	if err != nil {
+		h.logger.Error("failed to compute noise report", "error", err)
+		return InternalError(c, "failed to compute noise report")
+	}
+
+	return Success(c, domain.BuildNoiseReport(stats, filter, minScore))
+}
+
+// Sources handles GET /v1/reports/sources
+// Returns alert counts per event manager and origin of the triggering events,
+// most triggered first, to trace which system generates an alert stream.
+func (h *ReportHandler) Sources(c *fiber.Ctx) error {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		return ValidationError(c, err.Error())
+	}
+
+	stats, err := h.repo.SourceStats(c.Context(), filter)
+	if err != nil {
+		h.logger.Error("failed to compute source report", "error", err)
+		return InternalError(c, "failed to compute source report")
+	}
+
+	return Success(c, &domain.SourceReport{
+		From:    filter.From,
+		To:      filter.To,
+		Sources: stats,
+	})
+}
+
+// parseReportFilter reads event_manager_id, from, to (RFC3339) and top from the query string.
+// The range defaults to the last seven days.
+func parseReportFilter(c *fiber.Ctx) (domain.ReportFilter, error) {
+	filter := domain.ReportFilter{
+		EventManagerID: c.Query("event_manager_id"),
+		To:             time.Now().UTC(),
+		TopLimit:       domain.DefaultReportTopLimit,
+	}
+
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return filter, errors.New("'to' must be an RFC3339 timestamp")
+		}
+		filter.To = t.UTC()
+	}
+
+	filter.From = filter.To.Add(-domain.DefaultReportRange)
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return filter, errors.New("'from' must be an RFC3339 timestamp")
+		}
+		filter.From = t.UTC()
+	}
+
+	if top := c.Query("top"); top != "" {
+		if n, err := strconv.Atoi(top); err == nil && n > 0 {
+			filter.TopLimit = n
+		}
+	}
+
+	return filter, filter.Validate()
+}
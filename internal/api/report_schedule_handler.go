@@ -0,0 +1,193 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/reports"
+	"argus-go/internal/store"
+)
+
+// ReportScheduleHandler handles HTTP requests for the schedules of reports
+// emailed every day or week.
+type ReportScheduleHandler struct {
+	repo             store.ReportScheduleRepository
+	eventManagerRepo store.EventManagerRepository
+	scheduler        *reports.Scheduler
+	logger           *slog.Logger
+}
+
+// NewReportScheduleHandler creates a new report schedule handler. The event
+// manager repository validates event_manager_id references; schedules can
+// only be created with a scheduler to send them.
+func NewReportScheduleHandler(
+	repo store.ReportScheduleRepository,
+	eventManagerRepo store.EventManagerRepository,
+	scheduler *reports.Scheduler,
+	logger *slog.Logger,
+) *ReportScheduleHandler {
+	return &ReportScheduleHandler{
+		repo:             repo,
+		eventManagerRepo: eventManagerRepo,
+		scheduler:        scheduler,
+		logger:           logger,
+	}
+}
+
+// Create handles POST /v1/reports/schedules
+// Creates a new report schedule.
+func (h *ReportScheduleHandler) Create(c *fiber.Ctx) error {
+	var req domain.ReportScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+
+	// Validate the request
+	if err := req.Validate(); err != nil {
+		h.logger.Debug("validation failed", "error", err)
+		return ValidationError(c, err.Error())
+	}
+	if h.scheduler == nil {
+		return DomainError(c, domain.ErrUnavailableReportEmail)
+	}
+	if err := h.checkEventManager(c.Context(), req.EventManagerID); err != nil {
+		return h.eventManagerError(c, req.EventManagerID, err)
+	}
+
+	// Generate ID and create the schedule
+	schedule := req.ToReportSchedule(uuid.New().String())
+	if err := h.repo.Create(c.Context(), schedule); err != nil {
+		h.logger.Error("failed to create report schedule", "error", err)
+		return InternalError(c, "failed to create report schedule")
+	}
+
+	h.logger.Info("created report schedule", "id", schedule.ID, "name", schedule.Name)
+	return Created(c, schedule)
+}
+
+// List handles GET /v1/reports/schedules
+// Returns all report schedules, oldest first.
+func (h *ReportScheduleHandler) List(c *fiber.Ctx) error {
+	schedules, err := h.repo.List(c.Context())
+	if err != nil {
+		h.logger.Error("failed to list report schedules", "error", err)
+		return InternalError(c, "failed to list report schedules")
+	}
+
+	return SuccessWithLastModified(c, schedules, latestUpdate(schedules, func(schedule *domain.ReportSchedule) time.Time {
+		return schedule.UpdatedAt
+	}))
+}
+
+// GetByID handles GET /v1/reports/schedules/:id
+// Returns a single report schedule by ID.
+func (h *ReportScheduleHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	schedule, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get report schedule", "id", id, "error", err)
+		return InternalError(c, "failed to get report schedule")
+	}
+
+	return SuccessWithLastModified(c, schedule, schedule.UpdatedAt)
+}
+
+// Update handles PUT /v1/reports/schedules/:id
+// Replaces the settings of a report schedule. Reports already sent are not
+// sent again.
+func (h *ReportScheduleHandler) Update(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req domain.ReportScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+
+	// Validate the request
+	if err := req.Validate(); err != nil {
+		h.logger.Debug("validation failed", "error", err)
+		return ValidationError(c, err.Error())
+	}
+
+	// Fetch the existing schedule
+	schedule, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get report schedule", "id", id, "error", err)
+		return InternalError(c, "failed to get report schedule")
+	}
+
+	if req.EventManagerID != schedule.EventManagerID {
+		if err := h.checkEventManager(c.Context(), req.EventManagerID); err != nil {
+			return h.eventManagerError(c, req.EventManagerID, err)
+		}
+	}
+
+	// Apply and persist changes
+	req.ApplyTo(schedule)
+	if err := h.repo.Update(c.Context(), schedule); err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to update report schedule", "id", id, "error", err)
+		return InternalError(c, "failed to update report schedule")
+	}
+
+	h.logger.Info("updated report schedule", "id", schedule.ID)
+	return Success(c, schedule)
+}
+
+// Delete handles DELETE /v1/reports/schedules/:id
+// Permanently removes a report schedule.
+func (h *ReportScheduleHandler) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if err := h.repo.Delete(c.Context(), id); err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to delete report schedule", "id", id, "error", err)
+		return InternalError(c, "failed to delete report schedule")
+	}
+
+	h.logger.Info("deleted report schedule", "id", id)
+	return NoContent(c)
+}
+
+// checkEventManager returns an error unless the event manager a schedule
+// reports on exists and is not deleted. An empty ID reports on every event
+// manager.
+func (h *ReportScheduleHandler) checkEventManager(ctx context.Context, id string) error {
+	if id == "" {
+		return nil
+	}
+	em, err := h.eventManagerRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if em.IsDeleted() {
+		return domain.ErrEventManagerDeleted
+	}
+	return nil
+}
+
+// eventManagerError responds to a failed checkEventManager.
+func (h *ReportScheduleHandler) eventManagerError(c *fiber.Ctx, id string, err error) error {
+	if errors.Is(err, domain.ErrEventManagerNotFound) || errors.Is(err, domain.ErrEventManagerDeleted) {
+		return ValidationError(c, "event_manager_id "+id+": "+err.Error())
+	}
+	h.logger.Error("failed to get event manager", "id", id, "error", err)
+	return InternalError(c, "failed to get event manager")
+}
@@ -2,14 +2,29 @@
 package api
 
 import (
+	"net/http"
+	"strconv"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 )
 
 // APIResponse is the standard response envelope for all API responses.
 type APIResponse struct {
-	Success bool        `json:"success"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   *APIError   `json:"error,omitempty"`
+	Success    bool        `json:"success"`
+	Data       interface{} `json:"data,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+	Error      *APIError   `json:"error,omitempty"`
+}
+
+// Pagination describes the page of a list returned in Data.
+type Pagination struct {
+	// Total is the number of items matching the request across all pages.
+	Total int `json:"total"`
+
+	// Limit is the page size; zero if the list isn't paginated.
+	Limit  int `json:"limit,omitempty"`
+	Offset int `json:"offset"`
 }
 
 // APIError represents an error response.
@@ -21,10 +36,26 @@ type APIError struct {
 // Common error codes for consistent API responses.
 const (
 	ErrCodeBadRequest       = "BAD_REQUEST"
+	ErrCodeUnauthorized     = "UNAUTHORIZED"
 	ErrCodeNotFound         = "NOT_FOUND"
 	ErrCodeConflict         = "CONFLICT"
+	ErrCodeGone             = "GONE"
+	ErrCodeQuotaExceeded    = "QUOTA_EXCEEDED"
 	ErrCodeInternalError    = "INTERNAL_ERROR"
+	ErrCodeUnavailable      = "SERVICE_UNAVAILABLE"
 	ErrCodeValidationFailed = "VALIDATION_FAILED"
+
+	// ErrCodeInvalidTransition rejects an operation the state of a resource
+	// doesn't allow, e.g. acknowledging a resolved alert: retrying fails
+	// until the resource changes.
+	ErrCodeInvalidTransition = "INVALID_STATE_TRANSITION"
+
+	// Ingest error codes, telling senders whether retrying can succeed:
+	// events for an unknown event manager or grouping rule fail until the
+	// configuration changes, while an unavailable queue is transient.
+	ErrCodeEventManagerNotFound = "EVENT_MANAGER_NOT_FOUND"
+	ErrCodeGroupingRuleNotFound = "GROUPING_RULE_NOT_FOUND"
+	ErrCodeQueueUnavailable     = "QUEUE_UNAVAILABLE"
 )
 
 // Success sends a successful JSON response with the given data.
@@ -35,6 +66,36 @@ func Success(c *fiber.Ctx, data interface{}) error {
 	})
 }
 
+// SuccessWithPagination sends a successful JSON response with a page of a
+// list.
+func SuccessWithPagination(c *fiber.Ctx, data interface{}, pagination Pagination) error {
+	return c.JSON(APIResponse{
+		Success:    true,
+		Data:       data,
+		Pagination: &pagination,
+	})
+}
+
+// SuccessWithLastModified sends a successful JSON response with a Last-Modified
+// header. A zero lastModified (e.g. for an empty list) omits the header.
+func SuccessWithLastModified(c *fiber.Ctx, data interface{}, lastModified time.Time) error {
+	if !lastModified.IsZero() {
+		c.Set(fiber.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+	return Success(c, data)
+}
+
+// latestUpdate returns the most recent modification time of a list of resources.
+func latestUpdate[T any](items []T, updatedAt func(T) time.Time) time.Time {
+	var latest time.Time
+	for _, item := range items {
+		if t := updatedAt(item); t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
+
 // SuccessWithStatus sends a successful JSON response with a custom status code.
 func SuccessWithStatus(c *fiber.Ctx, status int, data interface{}) error {
 	return c.Status(status).JSON(APIResponse{
@@ -79,6 +140,11 @@ func ValidationError(c *fiber.Ctx, message string) error {
 	return Error(c, fiber.StatusBadRequest, ErrCodeValidationFailed, message)
 }
 
+// Unauthorized sends a 401 Unauthorized error response.
+func Unauthorized(c *fiber.Ctx, message string) error {
+	return Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, message)
+}
+
 // NotFound sends a 404 Not Found error response.
 func NotFound(c *fiber.Ctx, message string) error {
 	return Error(c, fiber.StatusNotFound, ErrCodeNotFound, message)
@@ -89,6 +155,29 @@ func Conflict(c *fiber.Ctx, message string) error {
 	return Error(c, fiber.StatusConflict, ErrCodeConflict, message)
 }
 
+// Gone sends a 410 Gone error response.
+func Gone(c *fiber.Ctx, message string) error {
+	return Error(c, fiber.StatusGone, ErrCodeGone, message)
+}
+
+// QuotaExceeded sends a 429 Too Many Requests error response.
+func QuotaExceeded(c *fiber.Ctx, message string) error {
+	return Error(c, fiber.StatusTooManyRequests, ErrCodeQuotaExceeded, message)
+}
+
+// Unavailable sends a 503 Service Unavailable error response with the given
+// code, asking the client to retry after retryAfter.
+func Unavailable(c *fiber.Ctx, code string, retryAfter time.Duration, message string) error {
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(retryAfter.Seconds())))
+	return Error(c, fiber.StatusServiceUnavailable, code, message)
+}
+
+// Unprocessable sends a 422 Unprocessable Entity error response with the
+// given code, for a well-formed request referring to something missing.
+func Unprocessable(c *fiber.Ctx, code, message string) error {
+	return Error(c, fiber.StatusUnprocessableEntity, code, message)
+}
+
 // InternalError sends a 500 Internal Server Error response.
 func InternalError(c *fiber.Ctx, message string) error {
 	return Error(c, fiber.StatusInternalServerError, ErrCodeInternalError, message)
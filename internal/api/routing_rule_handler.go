@@ -0,0 +1,187 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// RoutingRuleHandler handles HTTP requests for routing rule operations.
+type RoutingRuleHandler struct {
+	repo             store.RoutingRuleRepository
+	eventManagerRepo store.EventManagerRepository
+	logger           *slog.Logger
+}
+
+// NewRoutingRuleHandler creates a new routing rule handler.
+// The event manager repository validates event_manager_id references.
+func NewRoutingRuleHandler(
+	repo store.RoutingRuleRepository,
+	eventManagerRepo store.EventManagerRepository,
+	logger *slog.Logger,
+) *RoutingRuleHandler {
+	return &RoutingRuleHandler{
+		repo:             repo,
+		eventManagerRepo: eventManagerRepo,
+		logger:           logger,
+	}
+}
+
+// Create handles POST /v1/routing-rules
+// Creates a new routing rule.
+func (h *RoutingRuleHandler) Create(c *fiber.Ctx) error {
+	var req domain.CreateRoutingRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+
+	// Validate the request
+	if err := req.Validate(); err != nil {
+		h.logger.Debug("validation failed", "error", err)
+		return ValidationError(c, err.Error())
+	}
+	if err := h.checkEventManager(c.Context(), req.EventManagerID); err != nil {
+		if errors.Is(err, domain.ErrEventManagerNotFound) || errors.Is(err, domain.ErrEventManagerDeleted) {
+			return ValidationError(c, "event_manager_id "+req.EventManagerID+": "+err.Error())
+		}
+		h.logger.Error("failed to get event manager", "id", req.EventManagerID, "error", err)
+		return InternalError(c, "failed to get event manager")
+	}
+
+	// Generate ID and create the routing rule
+	rule := req.ToRoutingRule(uuid.New().String())
+
+	// Persist to repository
+	if err := h.repo.Create(c.Context(), rule); err != nil {
+		h.logger.Error("failed to create routing rule", "error", err)
+		return InternalError(c, "failed to create routing rule")
+	}
+
+	h.logger.Info("created routing rule", "id", rule.ID, "name", rule.Name)
+	return Created(c, rule)
+}
+
+// List handles GET /v1/routing-rules
+// Returns all routing rules in evaluation order.
+func (h *RoutingRuleHandler) List(c *fiber.Ctx) error {
+	rules, err := h.repo.List(c.Context())
+	if err != nil {
+		h.logger.Error("failed to list routing rules", "error", err)
+		return InternalError(c, "failed to list routing rules")
+	}
+
+	return SuccessWithLastModified(c, rules, latestUpdate(rules, func(rule *domain.RoutingRule) time.Time {
+		return rule.UpdatedAt
+	}))
+}
+
+// GetByID handles GET /v1/routing-rules/:id
+// Returns a single routing rule by ID.
+func (h *RoutingRuleHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	rule, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get routing rule", "id", id, "error", err)
+		return InternalError(c, "failed to get routing rule")
+	}
+
+	return SuccessWithLastModified(c, rule, rule.UpdatedAt)
+}
+
+// Update handles PUT /v1/routing-rules/:id
+// Updates an existing routing rule.
+func (h *RoutingRuleHandler) Update(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	var req domain.UpdateRoutingRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+
+	// Validate the request
+	if err := req.Validate(); err != nil {
+		h.logger.Debug("validation failed", "error", err)
+		return ValidationError(c, err.Error())
+	}
+
+	// Fetch existing routing rule
+	rule, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get routing rule", "id", id, "error", err)
+		return InternalError(c, "failed to get routing rule")
+	}
+
+	if req.EventManagerID != rule.EventManagerID {
+		if err := h.checkEventManager(c.Context(), req.EventManagerID); err != nil {
+			if errors.Is(err, domain.ErrEventManagerNotFound) || errors.Is(err, domain.ErrEventManagerDeleted) {
+				return ValidationError(c, "event_manager_id "+req.EventManagerID+": "+err.Error())
+			}
+			h.logger.Error("failed to get event manager", "id", req.EventManagerID, "error", err)
+			return InternalError(c, "failed to get event manager")
+		}
+	}
+
+	// Apply and persist changes
+	req.ApplyTo(rule)
+	if err := h.repo.Update(c.Context(), rule); err != nil {
+		h.logger.Error("failed to update routing rule", "id", id, "error", err)
+		return InternalError(c, "failed to update routing rule")
+	}
+
+	h.logger.Info("updated routing rule", "id", rule.ID)
+	return Success(c, rule)
+}
+
+// Delete handles DELETE /v1/routing-rules/:id
+// Permanently removes a routing rule.
+func (h *RoutingRuleHandler) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	if err := h.repo.Delete(c.Context(), id); err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to delete routing rule", "id", id, "error", err)
+		return InternalError(c, "failed to delete routing rule")
+	}
+
+	h.logger.Info("deleted routing rule", "id", id)
+	return NoContent(c)
+}
+
+// checkEventManager verifies that a routing target exists and is not deleted.
+func (h *RoutingRuleHandler) checkEventManager(ctx context.Context, id string) error {
+	em, err := h.eventManagerRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if em.IsDeleted() {
+		return domain.ErrEventManagerDeleted
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/pkg/argustest"
+)
+
+func TestRoutingRuleHandler_RoutesEvents(t *testing.T) {
+	h := argustest.Start(t)
+	paymentsID := h.CreateEventManager(t, "class", 5*time.Minute)
+	defaultID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	if status := call(t, h, http.MethodPost, "/v1/routing-rules", `{"name":"Catch-all","priority":100,"event_manager_id":"`+defaultID+`"}`, nil); status != http.StatusCreated {
+		t.Fatalf("create catch-all status = %d, want 201", status)
+	}
+	if status := call(t, h, http.MethodPost, "/v1/routing-rules", `{"name":"Payments","priority":10,"match":{"labels":{"team":"payments"}},"event_manager_id":"`+paymentsID+`"}`, nil); status != http.StatusCreated {
+		t.Fatalf("create payments rule status = %d, want 201", status)
+	}
+	if status := call(t, h, http.MethodPost, "/v1/routing-rules", `{"name":"Unknown","event_manager_id":"missing"}`, nil); status != http.StatusBadRequest {
+		t.Errorf("create rule for an unknown event manager status = %d, want 400", status)
+	}
+
+	post := func(body string) (int, string) {
+		t.Helper()
+		var result struct {
+			Data struct {
+				EventManagerID string `json:"event_manager_id"`
+			} `json:"data"`
+		}
+		status := call(t, h, http.MethodPost, "/v1/events", body, &result)
+		return status, result.Data.EventManagerID
+	}
+
+	status, emID := post(`{"summary":"Card declines","severity":"high","action":"trigger","class":"payments","dedupKey":"pay-1","labels":{"team":"payments"}}`)
+	if status != http.StatusAccepted || emID != paymentsID {
+		t.Errorf("labelled event = (%d, %s), want (202, %s)", status, emID, paymentsID)
+	}
+	status, emID = post(`{"summary":"Disk full","severity":"low","action":"trigger","class":"disk","dedupKey":"disk-1"}`)
+	if status != http.StatusAccepted || emID != defaultID {
+		t.Errorf("unlabelled event = (%d, %s), want (202, %s)", status, emID, defaultID)
+	}
+
+	h.Sync(t)
+	if alert := h.AwaitStatus(t, "pay-1", domain.AlertStatusActive); alert.EventManagerID != paymentsID {
+		t.Errorf("alert event manager = %s, want %s", alert.EventManagerID, paymentsID)
+	}
+}
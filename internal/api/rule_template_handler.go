@@ -0,0 +1,297 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// RuleTemplateHandler handles HTTP requests for rule template operations.
+type RuleTemplateHandler struct {
+	repo   store.RuleTemplateRepository
+	rules  *QueryRuleHandler
+	logger *slog.Logger
+}
+
+// NewRuleTemplateHandler creates a new rule template handler. The instances
+// of the templates are managed, and checked, through the query rule handler.
+func NewRuleTemplateHandler(repo store.RuleTemplateRepository, rules *QueryRuleHandler, logger *slog.Logger) *RuleTemplateHandler {
+	return &RuleTemplateHandler{
+		repo:   repo,
+		rules:  rules,
+		logger: logger,
+	}
+}
+
+// Create handles POST /v1/rule-templates
+// Creates a new rule template, without instances.
+func (h *RuleTemplateHandler) Create(c *fiber.Ctx) error {
+	var req domain.CreateRuleTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+
+	// Validate the request
+	if err := req.Validate(); err != nil {
+		h.logger.Debug("validation failed", "error", err)
+		return ValidationError(c, err.Error())
+	}
+
+	// Generate ID and create the template
+	template := req.ToRuleTemplate(uuid.New().String(), h.rules.clock.Now().UTC())
+	if err := h.repo.Create(c.Context(), template); err != nil {
+		h.logger.Error("failed to create rule template", "error", err)
+		return InternalError(c, "failed to create rule template")
+	}
+
+	h.logger.Info("created rule template", "id", template.ID, "name", template.Name, "variables", template.Variables)
+	return Created(c, template)
+}
+
+// List handles GET /v1/rule-templates
+// Returns all rule templates, oldest first.
+func (h *RuleTemplateHandler) List(c *fiber.Ctx) error {
+	templates, err := h.repo.List(c.Context())
+	if err != nil {
+		h.logger.Error("failed to list rule templates", "error", err)
+		return InternalError(c, "failed to list rule templates")
+	}
+
+	return SuccessWithLastModified(c, templates, latestUpdate(templates, func(template *domain.RuleTemplate) time.Time {
+		return template.UpdatedAt
+	}))
+}
+
+// GetByID handles GET /v1/rule-templates/:id
+// Returns a single rule template by ID.
+func (h *RuleTemplateHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	template, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get rule template", "id", id, "error", err)
+		return InternalError(c, "failed to get rule template")
+	}
+
+	return SuccessWithLastModified(c, template, template.UpdatedAt)
+}
+
+// Update handles PUT /v1/rule-templates/:id
+// Updates a rule template, and re-renders every instance with its values.
+// Nothing is updated if any instance would be invalid, e.g. if the update
+// adds a variable the instances have no value for.
+func (h *RuleTemplateHandler) Update(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	var req domain.UpdateRuleTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+
+	// Validate the request
+	if err := req.Validate(); err != nil {
+		h.logger.Debug("validation failed", "error", err)
+		return ValidationError(c, err.Error())
+	}
+
+	// Fetch existing template and its instances
+	template, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get rule template", "id", id, "error", err)
+		return InternalError(c, "failed to get rule template")
+	}
+	instances, err := h.instances(c.Context(), id)
+	if err != nil {
+		return InternalError(c, "failed to list rules")
+	}
+
+	// Apply the changes to the template, then re-render and check every
+	// instance before persisting any
+	now := h.rules.clock.Now().UTC()
+	req.ApplyTo(template, now)
+	for _, rule := range instances {
+		previousEventManagerID := rule.EventManagerID
+		if err := template.ApplyTo(rule, now); err != nil {
+			return ValidationError(c, "rule "+rule.ID+": "+err.Error())
+		}
+		if message, err := h.rules.check(c.Context(), rule, previousEventManagerID); err != nil {
+			return InternalError(c, "failed to check rule")
+		} else if message != "" {
+			return ValidationError(c, "rule "+rule.ID+": "+message)
+		}
+	}
+
+	if err := h.repo.Update(c.Context(), template); err != nil {
+		h.logger.Error("failed to update rule template", "id", id, "error", err)
+		return InternalError(c, "failed to update rule template")
+	}
+	for _, rule := range instances {
+		if err := h.rules.repo.Update(c.Context(), rule); err != nil {
+			h.logger.Error("failed to update query rule", "id", rule.ID, "template_id", id, "error", err)
+			return InternalError(c, "failed to update rule")
+		}
+	}
+
+	h.logger.Info("updated rule template", "id", id, "instances", len(instances))
+	return Success(c, template)
+}
+
+// Delete handles DELETE /v1/rule-templates/:id
+// Permanently removes a rule template and every instance of it. The runner
+// resolves the alerts of the instances at its next check.
+func (h *RuleTemplateHandler) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	if err := h.repo.Delete(c.Context(), id); err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to delete rule template", "id", id, "error", err)
+		return InternalError(c, "failed to delete rule template")
+	}
+
+	instances, err := h.instances(c.Context(), id)
+	if err != nil {
+		return InternalError(c, "failed to list rules")
+	}
+	for _, rule := range instances {
+		if err := h.rules.repo.Delete(c.Context(), rule.ID); err != nil && !errors.Is(err, domain.ErrQueryRuleNotFound) {
+			h.logger.Error("failed to delete query rule", "id", rule.ID, "template_id", id, "error", err)
+			return InternalError(c, "failed to delete rule")
+		}
+		if err := h.rules.executionRepo.DeleteByRule(c.Context(), rule.ID); err != nil {
+			h.logger.Warn("failed to delete rule executions", "id", rule.ID, "error", err)
+		}
+	}
+
+	h.logger.Info("deleted rule template", "id", id, "instances", len(instances))
+	return NoContent(c)
+}
+
+// Instantiate handles POST /v1/rule-templates/:id/instantiate
+// Creates a rule linked to the template for each set of values. A set of
+// values the template already has an instance for reuses it, so the request
+// can be repeated. Returns the instances of every set, in order.
+func (h *RuleTemplateHandler) Instantiate(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return BadRequest(c, "id is required")
+	}
+
+	var req domain.InstantiateRuleTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+
+	// Validate the request
+	if err := req.Validate(); err != nil {
+		h.logger.Debug("validation failed", "error", err)
+		return ValidationError(c, err.Error())
+	}
+
+	template, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get rule template", "id", id, "error", err)
+		return InternalError(c, "failed to get rule template")
+	}
+	instances, err := h.instances(c.Context(), id)
+	if err != nil {
+		return InternalError(c, "failed to list rules")
+	}
+
+	// Render and check the new instances before creating any
+	now := h.rules.clock.Now().UTC()
+	rules := make([]*domain.QueryRule, 0, len(req.Values))
+	var created []*domain.QueryRule
+	for i, values := range req.Values {
+		if rule := instanceWithValues(instances, values); rule != nil {
+			rules = append(rules, rule)
+			continue
+		}
+
+		rule, err := template.Instantiate(uuid.New().String(), values, now)
+		if err != nil {
+			return ValidationError(c, fmt.Sprintf("values[%d]: %v", i, err))
+		}
+		if message, err := h.rules.check(c.Context(), rule, ""); err != nil {
+			return InternalError(c, "failed to check rule")
+		} else if message != "" {
+			return ValidationError(c, fmt.Sprintf("values[%d]: %s", i, message))
+		}
+
+		instances = append(instances, rule)
+		created = append(created, rule)
+		rules = append(rules, rule)
+	}
+
+	for _, rule := range created {
+		if err := h.rules.repo.Create(c.Context(), rule); err != nil {
+			h.logger.Error("failed to create query rule", "template_id", id, "error", err)
+			return InternalError(c, "failed to create rule")
+		}
+	}
+
+	h.logger.Info("instantiated rule template", "id", id, "created", len(created), "instances", len(instances))
+	return Created(c, rules)
+}
+
+// instances returns the rules instantiated from a template.
+func (h *RuleTemplateHandler) instances(ctx context.Context, templateID string) ([]*domain.QueryRule, error) {
+	rules, err := h.rules.repo.List(ctx)
+	if err != nil {
+		h.logger.Error("failed to list query rules", "error", err)
+		return nil, err
+	}
+	return templateInstances(rules, templateID), nil
+}
+
+// templateInstances returns the rules instantiated from a template.
+func templateInstances(rules []*domain.QueryRule, templateID string) []*domain.QueryRule {
+	instances := []*domain.QueryRule{}
+	for _, rule := range rules {
+		if rule.TemplateID == templateID {
+			instances = append(instances, rule)
+		}
+	}
+	return instances
+}
+
+// instanceWithValues returns the instance rendered with values, or nil.
+func instanceWithValues(instances []*domain.QueryRule, values map[string]string) *domain.QueryRule {
+	for _, rule := range instances {
+		if maps.Equal(rule.TemplateValues, values) {
+			return rule
+		}
+	}
+	return nil
+}
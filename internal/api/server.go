@@ -2,21 +2,36 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/etag"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	"argus-go/internal/health"
+	"argus-go/internal/processor"
 )
 
 // Server represents the HTTP server with all configured routes and middleware.
 type Server struct {
 	app    *fiber.App
 	config *config.ServerConfig
+
+	// admin serves /metrics and the admin API on the admin addresses, if
+	// any; otherwise app serves them.
+	admin *fiber.App
+
 	logger *slog.Logger
 
 	// Handlers
@@ -24,6 +39,23 @@ type Server struct {
 	groupingRuleHandler *GroupingRuleHandler
 	alertHandler        *AlertHandler
 	ingestHandler       *IngestHandler
+	reportHandler       *ReportHandler
+	scheduleHandler     *ReportScheduleHandler
+	usageHandler        *UsageHandler
+	sloHandler          *SLOHandler
+	configHandler       *ConfigHandler
+	routingRuleHandler  *RoutingRuleHandler
+	processorHandler    *ProcessorHandler
+	approvalHandler     *ApprovalHandler
+	dataHandler         *DataHandler
+	watchHandler        *WatchHandler
+	feedbackHandler     *FeedbackHandler
+	silenceHandler      *SilenceHandler
+	queryRuleHandler    *QueryRuleHandler
+	ruleTemplateHandler *RuleTemplateHandler
+	graphQLHandler      *GraphQLHandler
+	chaosHandler        *ChaosHandler
+	health              *health.Monitor
 }
 
 // ServerDeps contains all dependencies required to create a new Server.
@@ -34,40 +66,67 @@ type ServerDeps struct {
 	GroupingRuleHandler *GroupingRuleHandler
 	AlertHandler        *AlertHandler
 	IngestHandler       *IngestHandler
+	ReportHandler       *ReportHandler
+	ScheduleHandler     *ReportScheduleHandler
+	UsageHandler        *UsageHandler
+	SLOHandler          *SLOHandler
+	ConfigHandler       *ConfigHandler
+	RoutingRuleHandler  *RoutingRuleHandler
+	ProcessorHandler    *ProcessorHandler
+	ApprovalHandler     *ApprovalHandler
+	DataHandler         *DataHandler
+	WatchHandler        *WatchHandler
+	FeedbackHandler     *FeedbackHandler
+	SilenceHandler      *SilenceHandler
+	QueryRuleHandler    *QueryRuleHandler
+	RuleTemplateHandler *RuleTemplateHandler
+	GraphQLHandler      *GraphQLHandler
+
+	// ChaosHandler is optional; the fault-injection admin API is only
+	// registered when it is set.
+	ChaosHandler *ChaosHandler
+
+	// Health is optional; without it /readyz always reports ready.
+	Health *health.Monitor
 }
 
 // NewServer creates a new HTTP server with all routes configured.
 func NewServer(deps ServerDeps) *Server {
-	// Create Fiber app with optimized settings for high throughput
-	app := fiber.New(fiber.Config{
-		// Disable startup message for cleaner logs
-		DisableStartupMessage: true,
-		// Enable strict routing for consistency
-		StrictRouting: true,
-		// Case sensitive routing
-		CaseSensitive: true,
-		// Read timeout from config
-		ReadTimeout: deps.Config.ReadTimeout,
-		// Write timeout from config
-		WriteTimeout: deps.Config.WriteTimeout,
-		// Idle timeout from config
-		IdleTimeout: deps.Config.IdleTimeout,
-		// Custom error handler
-		ErrorHandler: customErrorHandler,
-	})
-
 	s := &Server{
-		app:                 app,
+		app:                 newApp(deps.Config),
 		config:              deps.Config,
 		logger:              deps.Logger,
 		eventManagerHandler: deps.EventManagerHandler,
 		groupingRuleHandler: deps.GroupingRuleHandler,
 		alertHandler:        deps.AlertHandler,
 		ingestHandler:       deps.IngestHandler,
+		reportHandler:       deps.ReportHandler,
+		scheduleHandler:     deps.ScheduleHandler,
+		usageHandler:        deps.UsageHandler,
+		sloHandler:          deps.SLOHandler,
+		configHandler:       deps.ConfigHandler,
+		routingRuleHandler:  deps.RoutingRuleHandler,
+		processorHandler:    deps.ProcessorHandler,
+		approvalHandler:     deps.ApprovalHandler,
+		dataHandler:         deps.DataHandler,
+		watchHandler:        deps.WatchHandler,
+		feedbackHandler:     deps.FeedbackHandler,
+		silenceHandler:      deps.SilenceHandler,
+		queryRuleHandler:    deps.QueryRuleHandler,
+		ruleTemplateHandler: deps.RuleTemplateHandler,
+		graphQLHandler:      deps.GraphQLHandler,
+		chaosHandler:        deps.ChaosHandler,
+		health:              deps.Health,
+	}
+	if len(deps.Config.AdminAddresses) > 0 {
+		s.admin = newApp(deps.Config)
 	}
 
 	// Register middleware
-	s.registerMiddleware()
+	s.registerMiddleware(s.app)
+	if s.admin != nil {
+		s.registerMiddleware(s.admin)
+	}
 
 	// Register routes
 	s.registerRoutes()
@@ -75,52 +134,260 @@ func NewServer(deps ServerDeps) *Server {
 	return s
 }
 
-// registerMiddleware sets up all middleware for the server.
-func (s *Server) registerMiddleware() {
+// newApp creates a Fiber app with the server settings.
+func newApp(cfg *config.ServerConfig) *fiber.App {
+	// Create Fiber app with optimized settings for high throughput
+	return fiber.New(fiber.Config{
+		// Disable startup message for cleaner logs
+		DisableStartupMessage: true,
+		// Enable strict routing for consistency
+		StrictRouting: true,
+		// Case sensitive routing
+		CaseSensitive: true,
+		// Read timeout from config
+		ReadTimeout: cfg.ReadTimeout,
+		// Write timeout from config
+		WriteTimeout: cfg.WriteTimeout,
+		// Idle timeout from config
+		IdleTimeout: cfg.IdleTimeout,
+		// Client IP header set by a proxy, if any
+		ProxyHeader: cfg.ProxyHeader,
+		// Custom error handler
+		ErrorHandler: customErrorHandler,
+	})
+}
+
+// registerMiddleware sets up all middleware of an app of the server.
+func (s *Server) registerMiddleware(app *fiber.App) {
 	// Recovery middleware to handle panics
-	s.app.Use(recover.New(recover.Config{
+	app.Use(recover.New(recover.Config{
 		EnableStackTrace: true,
 	}))
 
 	// Request ID middleware for tracing
-	s.app.Use(requestid.New())
+	app.Use(requestid.New())
 
 	// Logger middleware for request logging
-	s.app.Use(logger.New(logger.Config{
-		Format:     "${time} | ${status} | ${latency} | ${method} | ${path} | ${error}\n",
+	app.Use(logger.New(logger.Config{
+		Format:     "${time} | ${status} | ${latency} | ${method} | ${path} | ${locals:requestid} | ${error}\n",
 		TimeFormat: "2006-01-02 15:04:05",
 	}))
+
+	// Cross-origin requests from browser frontends
+	if s.config.CORS.Enabled {
+		app.Use(cors.New(cors.Config{
+			AllowOrigins:     strings.Join(s.config.CORS.AllowOrigins, ","),
+			AllowMethods:     strings.Join(s.config.CORS.AllowMethods, ","),
+			AllowHeaders:     strings.Join(s.config.CORS.AllowHeaders, ","),
+			ExposeHeaders:    strings.Join(s.config.CORS.ExposeHeaders, ","),
+			AllowCredentials: s.config.CORS.AllowCredentials,
+			MaxAge:           int(s.config.CORS.MaxAge.Seconds()),
+		}))
+	}
+
+	// Response compression
+	if s.config.Compression.Enabled {
+		app.Use(compression(s.config.Compression))
+	}
 }
 
 // registerRoutes sets up all API routes.
 func (s *Server) registerRoutes() {
 	// Health check endpoint (outside versioned API)
 	s.app.Get("/healthz", s.healthCheck)
+	s.app.Get("/readyz", s.readyCheck)
+
+	// Metrics and the admin API are served by the admin listeners, if any,
+	// which also answer health checks
+	admin := s.app
+	if s.admin != nil {
+		admin = s.admin
+		admin.Get("/healthz", s.healthCheck)
+		admin.Get("/readyz", s.readyCheck)
+	}
+
+	// Prometheus metrics (outside versioned API)
+	admin.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
+
+	// Alertmanager-compatible alerts for existing dashboards (outside versioned API)
+	s.app.Get("/api/v2/alerts", s.alertHandler.Alertmanager)
+
+	// GraphQL queries over alerts and configuration (outside versioned API)
+	s.app.Get("/graphql", s.graphQLHandler.Query)
+	s.app.Post("/graphql", s.graphQLHandler.Query)
 
 	// API v1 routes
 	v1 := s.app.Group("/v1")
 
+	// Read endpoints polled by dashboards support conditional requests
+	conditional := conditionalGet()
+
+	// Severity levels and how channels render them
+	v1.Get("/severities", conditional, s.severities)
+
 	// Event ingestion
 	v1.Post("/events", s.ingestHandler.IngestEvent)
+	v1.Post("/events/:ingest_token", s.ingestHandler.IngestWithToken)
+	v1.Get("/events/:eventId/status", s.ingestHandler.EventStatus)
+	v1.Post("/webhooks/:ingest_token", s.ingestHandler.IngestWebhook)
+	v1.Post("/integrations/:integration/:ingest_token", s.ingestHandler.IngestIntegration)
 
 	// Event Manager CRUD
 	v1.Post("/event-managers", s.eventManagerHandler.Create)
-	v1.Get("/event-managers", s.eventManagerHandler.List)
-	v1.Get("/event-managers/:id", s.eventManagerHandler.GetByID)
+	v1.Get("/event-managers", conditional, s.eventManagerHandler.List)
+	v1.Get("/event-managers/:id", conditional, s.eventManagerHandler.GetByID)
 	v1.Put("/event-managers/:id", s.eventManagerHandler.Update)
 	v1.Delete("/event-managers/:id", s.eventManagerHandler.Delete)
+	v1.Post("/event-managers/:id/test-notification", s.eventManagerHandler.TestNotification)
+	v1.Post("/event-managers/:id/test-alert", s.eventManagerHandler.TestAlert)
+	v1.Post("/event-managers/:id/ingest-token", s.eventManagerHandler.RotateIngestToken)
+	v1.Post("/event-managers/:id/clone", s.eventManagerHandler.Clone)
 
 	// Grouping Rules CRUD
 	v1.Post("/grouping-rules", s.groupingRuleHandler.Create)
-	v1.Get("/grouping-rules", s.groupingRuleHandler.List)
-	v1.Get("/grouping-rules/:id", s.groupingRuleHandler.GetByID)
+	v1.Get("/grouping-rules", conditional, s.groupingRuleHandler.List)
+	v1.Get("/grouping-rules/:id", conditional, s.groupingRuleHandler.GetByID)
 	v1.Put("/grouping-rules/:id", s.groupingRuleHandler.Update)
 	v1.Delete("/grouping-rules/:id", s.groupingRuleHandler.Delete)
+	v1.Post("/grouping-rules/:id/preview", s.groupingRuleHandler.Preview)
+	v1.Get("/grouping-rules/:id/usage", s.groupingRuleHandler.Usage)
+
+	// Routing Rules CRUD
+	v1.Post("/routing-rules", s.routingRuleHandler.Create)
+	v1.Get("/routing-rules", conditional, s.routingRuleHandler.List)
+	v1.Get("/routing-rules/:id", conditional, s.routingRuleHandler.GetByID)
+	v1.Put("/routing-rules/:id", s.routingRuleHandler.Update)
+	v1.Delete("/routing-rules/:id", s.routingRuleHandler.Delete)
+
+	// Personal alert watches of the actor
+	v1.Post("/watches", s.watchHandler.Create)
+	v1.Get("/watches", s.watchHandler.List)
+	v1.Get("/watches/:id", s.watchHandler.GetByID)
+	v1.Delete("/watches/:id", s.watchHandler.Delete)
+
+	// Silences muting the notifications of matching alerts
+	v1.Post("/silences", s.silenceHandler.Create)
+	v1.Get("/silences", s.silenceHandler.List)
+	v1.Get("/silences/:id", s.silenceHandler.GetByID)
+	v1.Delete("/silences/:id", s.silenceHandler.Delete)
+
+	// Query rules alerting on the results of datasource queries
+	v1.Post("/rules", s.queryRuleHandler.Create)
+	v1.Get("/rules", conditional, s.queryRuleHandler.List)
+	v1.Get("/rules/:id", conditional, s.queryRuleHandler.GetByID)
+	v1.Put("/rules/:id", s.queryRuleHandler.Update)
+	v1.Delete("/rules/:id", s.queryRuleHandler.Delete)
+	v1.Post("/rules/:id/enable", s.queryRuleHandler.Enable)
+	v1.Post("/rules/:id/disable", s.queryRuleHandler.Disable)
+	v1.Post("/rules/:id/mute", s.queryRuleHandler.Mute)
+	v1.Delete("/rules/:id/mute", s.queryRuleHandler.Unmute)
+	v1.Get("/rules/:id/executions", s.queryRuleHandler.Executions)
+
+	// Rule templates instantiating query rules over sets of values
+	v1.Post("/rule-templates", s.ruleTemplateHandler.Create)
+	v1.Get("/rule-templates", conditional, s.ruleTemplateHandler.List)
+	v1.Get("/rule-templates/:id", conditional, s.ruleTemplateHandler.GetByID)
+	v1.Put("/rule-templates/:id", s.ruleTemplateHandler.Update)
+	v1.Delete("/rule-templates/:id", s.ruleTemplateHandler.Delete)
+	v1.Post("/rule-templates/:id/instantiate", s.ruleTemplateHandler.Instantiate)
+
+	adminV1 := admin.Group("/v1/admin")
+	s.protectAdmin(adminV1)
+
+	// Admin: permanent removal of soft-deleted resources
+	adminV1.Delete("/event-managers/:id", s.eventManagerHandler.Purge)
+	adminV1.Delete("/grouping-rules/:id", s.groupingRuleHandler.Purge)
+
+	// Admin: system-wide default grouping rule
+	adminV1.Get("/default-grouping-rule", s.groupingRuleHandler.GetDefault)
+	adminV1.Put("/default-grouping-rule", s.groupingRuleHandler.SetDefault)
+
+	// Admin: pause event consumption, e.g. during store maintenance
+	adminV1.Get("/processor", s.processorHandler.Status)
+	adminV1.Post("/processor/pause", s.processorHandler.Pause)
+	adminV1.Post("/processor/resume", s.processorHandler.Resume)
+
+	// Admin: the autoscaling signal of the processor
+	adminV1.Get("/scaling", s.processorHandler.Scaling)
+
+	// Admin: approvals of destructive operations, and the audit log
+	adminV1.Get("/approvals", s.approvalHandler.List)
+	adminV1.Get("/approvals/:id", s.approvalHandler.GetByID)
+	adminV1.Post("/approvals/:id/approve", s.approvalHandler.Approve)
+	adminV1.Post("/approvals/:id/cancel", s.approvalHandler.Cancel)
+	adminV1.Get("/audit", s.approvalHandler.Audit)
+	adminV1.Get("/audit/export", s.approvalHandler.ExportAudit)
+	adminV1.Get("/audit/verify", s.approvalHandler.VerifyAudit)
+
+	// Admin: deletion of all the data of an event manager, for data deletion requests
+	adminV1.Delete("/data", s.dataHandler.Delete)
+
+	// Admin: import of historical alerts, e.g. after migrating from another system
+	adminV1.Post("/import", s.alertHandler.Import)
+
+	// Declarative configuration; applying it is an admin operation
+	v1.Get("/config/export", s.configHandler.Export)
+	adminV1.Put("/config", s.configHandler.Apply)
+
+	// Alerts
+	v1.Get("/alerts", conditional, s.alertHandler.List)
+	v1.Get("/alerts/live-counts", s.alertHandler.LiveCounts)
+	v1.Get("/alerts/:dedupKey", conditional, s.alertHandler.GetByDedupKey)
+	v1.Patch("/alerts/:dedupKey", s.alertHandler.Patch)
+	v1.Get("/alerts/:dedupKey/children", conditional, s.alertHandler.GetChildren)
+	v1.Get("/alerts/:dedupKey/children/count", conditional, s.alertHandler.ChildCount)
+	v1.Get("/alerts/:dedupKey/tree", conditional, s.alertHandler.Tree)
+	v1.Get("/alerts/:dedupKey/related", s.alertHandler.Related)
+	v1.Get("/alerts/:dedupKey/diagnostics", s.alertHandler.Diagnostics)
+	v1.Get("/alerts/:dedupKey/history", s.alertHandler.History)
+	v1.Get("/alerts/:dedupKey/episodes", s.alertHandler.Episodes)
+	v1.Get("/alerts/:dedupKey/remediations", s.alertHandler.Remediations)
+	v1.Get("/alerts/:dedupKey/report", s.alertHandler.Report)
+	v1.Post("/alerts/:dedupKey/acknowledge", s.alertHandler.Acknowledge)
+	v1.Post("/alerts/:dedupKey/force-resolve", s.alertHandler.ForceResolve)
+	v1.Patch("/alerts/:dedupKey/links", s.alertHandler.UpdateLinks)
+	v1.Post("/alerts/:dedupKey/feedback", s.feedbackHandler.Create)
+	v1.Get("/alerts/:dedupKey/feedback", s.feedbackHandler.List)
+
+	// Reports
+	v1.Get("/reports/mttr", s.reportHandler.MTTR)
+	v1.Get("/reports/volume", s.reportHandler.Volume)
+	v1.Get("/reports/noise", s.reportHandler.Noise)
+	v1.Get("/reports/sources", s.reportHandler.Sources)
+	v1.Get("/reports/alert-quality", s.feedbackHandler.Quality)
+
+	// Reports emailed every day or week
+	v1.Post("/reports/schedules", s.scheduleHandler.Create)
+	v1.Get("/reports/schedules", s.scheduleHandler.List)
+	v1.Get("/reports/schedules/:id", s.scheduleHandler.GetByID)
+	v1.Put("/reports/schedules/:id", s.scheduleHandler.Update)
+	v1.Delete("/reports/schedules/:id", s.scheduleHandler.Delete)
+
+	// Usage per event manager, for chargeback and capacity planning
+	v1.Get("/usage", s.usageHandler.List)
+	v1.Get("/usage/:event_manager_id", s.usageHandler.GetByEventManager)
+	v1.Get("/usage/:event_manager_id/quota", s.usageHandler.GetQuota)
+
+	// Service level objectives
+	v1.Get("/slo", s.sloHandler.Status)
+
+	// Fault injection (chaos builds only)
+	if s.chaosHandler != nil {
+		adminV1.Get("/chaos", s.chaosHandler.List)
+		adminV1.Put("/chaos/:target", s.chaosHandler.Set)
+		adminV1.Delete("/chaos", s.chaosHandler.Reset)
+	}
+}
 
-	// Alerts (read-only for MVP)
-	v1.Get("/alerts", s.alertHandler.List)
-	v1.Get("/alerts/:dedupKey", s.alertHandler.GetByDedupKey)
-	v1.Get("/alerts/:dedupKey/children", s.alertHandler.GetChildren)
+// conditionalGet returns middleware that adds an ETag to successful responses
+// and answers a matching If-None-Match with 304 Not Modified. Responses are
+// marked no-cache so clients always revalidate rather than reuse stale data.
+func conditionalGet() fiber.Handler {
+	tag := etag.New()
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		return tag(c)
+	}
 }
 
 // healthCheck returns the health status of the service.
@@ -130,17 +397,102 @@ func (s *Server) healthCheck(c *fiber.Ctx) error {
 	})
 }
 
-// Start begins listening for HTTP requests.
+// severityLevel is a level of the severity scale in /v1/severities.
+type severityLevel struct {
+	Severity     domain.Severity             `json:"severity"`
+	Rank         int                         `json:"rank"`
+	Default      bool                        `json:"default,omitempty"`
+	Presentation domain.SeverityPresentation `json:"presentation"`
+}
+
+// severities lists the levels of the deployment's severity scale, most
+// severe first, with the presentation notifications and UIs render them
+// with.
+func (s *Server) severities(c *fiber.Ctx) error {
+	scale := domain.CurrentSeverityScale()
+	levels := make([]severityLevel, 0, len(scale.Levels()))
+	for _, level := range scale.Levels() {
+		levels = append(levels, severityLevel{
+			Severity:     level,
+			Rank:         scale.Rank(level),
+			Default:      level == scale.Default(),
+			Presentation: level.Presentation(),
+		})
+	}
+	return Success(c, levels)
+}
+
+// readinessResponse is the body of /readyz.
+type readinessResponse struct {
+	Status       string           `json:"status"`
+	Dependencies []health.Status  `json:"dependencies"`
+	Processor    processor.Status `json:"processor"`
+}
+
+// readyCheck reports whether the service can handle traffic: 200 when every
+// dependency passed its last probe, 503 while any is degraded. A paused
+// processor is reported but doesn't affect readiness, since events are
+// still accepted.
+func (s *Server) readyCheck(c *fiber.Ctx) error {
+	resp := readinessResponse{
+		Status:       "ready",
+		Dependencies: s.health.Statuses(),
+		Processor:    s.processorHandler.processor.Status(),
+	}
+	if s.health.Ready() {
+		return Success(c, resp)
+	}
+	resp.Status = "degraded"
+	return c.Status(fiber.StatusServiceUnavailable).JSON(APIResponse{
+		Success: false,
+		Data:    resp,
+		Error:   &APIError{Code: ErrCodeUnavailable, Message: "a dependency is degraded"},
+	})
+}
+
+// Start begins listening for HTTP requests on the configured addresses,
+// and for admin requests on the admin addresses. It returns when either
+// server stops.
 func (s *Server) Start() error {
-	addr := s.config.Address()
-	s.logger.Info("starting HTTP server", "address", addr)
-	return s.app.Listen(addr)
+	ln, err := listen(s.config.ListenAddresses())
+	if err != nil {
+		return err
+	}
+	if s.admin == nil {
+		s.logger.Info("starting HTTP server", "addresses", s.config.ListenAddresses())
+		return s.app.Listener(ln)
+	}
+
+	adminLn, err := listen(s.config.AdminAddresses)
+	if err != nil {
+		_ = ln.Close()
+		return err
+	}
+	s.logger.Info("starting HTTP server",
+		"addresses", s.config.ListenAddresses(),
+		"adminAddresses", s.config.AdminAddresses,
+	)
+	errs := make(chan error, 2)
+	go func() { errs <- s.app.Listener(ln) }()
+	go func() { errs <- s.admin.Listener(adminLn) }()
+	return <-errs
+}
+
+// Serve accepts HTTP requests on an existing listener.
+// This is used by tests that bind to an ephemeral port.
+func (s *Server) Serve(ln net.Listener) error {
+	s.logger.Info("starting HTTP server", "address", ln.Addr().String())
+	return s.app.Listener(ln)
 }
 
 // Shutdown gracefully stops the HTTP server.
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("shutting down HTTP server")
-	return s.app.ShutdownWithContext(ctx)
+	err := s.app.ShutdownWithContext(ctx)
+	if s.admin != nil {
+		err = errors.Join(err, s.admin.ShutdownWithContext(ctx))
+	}
+	return err
 }
 
 // customErrorHandler handles errors returned from handlers.
@@ -0,0 +1,107 @@
+package api_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/pkg/argustest"
+)
+
+func TestServer_ConditionalGet(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	get := func(etag string) *http.Response {
+		t.Helper()
+		req := newRequest(t, h, http.MethodGet, "/v1/event-managers/"+emID, "")
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET event manager error: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	first := get("")
+	etag := first.Header.Get("ETag")
+	if first.StatusCode != http.StatusOK || etag == "" {
+		t.Fatalf("GET status = %d, ETag = %q, want 200 with an ETag", first.StatusCode, etag)
+	}
+	if first.Header.Get("Last-Modified") == "" {
+		t.Error("GET should set Last-Modified")
+	}
+
+	if resp := get(etag); resp.StatusCode != http.StatusNotModified {
+		t.Errorf("GET with matching If-None-Match status = %d, want 304", resp.StatusCode)
+	}
+
+	if status := call(t, h, http.MethodPut, "/v1/event-managers/"+emID, `{"name":"renamed"}`, nil); status != http.StatusOK {
+		t.Fatalf("PUT event manager status = %d, want 200", status)
+	}
+	if resp := get(etag); resp.StatusCode != http.StatusOK {
+		t.Errorf("GET after update with stale If-None-Match status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestServer_CompressesResponses(t *testing.T) {
+	h := argustest.Start(t)
+	for range 3 {
+		h.CreateEventManager(t, "class", 5*time.Minute)
+	}
+
+	req := newRequest(t, h, http.MethodGet, "/v1/event-managers", "")
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET event managers error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got := resp.Header.Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader error: %v", err)
+	}
+	var body struct {
+		Data []domain.EventManager `json:"data"`
+	}
+	if err := json.NewDecoder(zr).Decode(&body); err != nil {
+		t.Fatalf("decoding the response: %v", err)
+	}
+	if len(body.Data) != 3 {
+		t.Errorf("event managers = %d, want 3", len(body.Data))
+	}
+}
+
+func TestServer_Severities(t *testing.T) {
+	h := argustest.Start(t)
+
+	var result struct {
+		Data []struct {
+			Severity     domain.Severity             `json:"severity"`
+			Rank         int                         `json:"rank"`
+			Default      bool                        `json:"default"`
+			Presentation domain.SeverityPresentation `json:"presentation"`
+		} `json:"data"`
+	}
+	if status := call(t, h, http.MethodGet, "/v1/severities", "", &result); status != http.StatusOK || len(result.Data) != 3 {
+		t.Fatalf("GET severities = %d with %d levels, want 200 with 3", status, len(result.Data))
+	}
+
+	high, low := result.Data[0], result.Data[2]
+	if high.Severity != domain.SeverityHigh || high.Rank != 1 || high.Presentation != domain.SeverityHigh.Presentation() {
+		t.Errorf("first level = %+v, want high with its presentation", high)
+	}
+	if low.Severity != domain.SeverityLow || !low.Default {
+		t.Errorf("last level = %+v, want low as the default", low)
+	}
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
+	"argus-go/internal/silence"
+	"argus-go/internal/store"
+)
+
+// SilenceHandler handles HTTP requests for silences.
+type SilenceHandler struct {
+	repo      store.SilenceRepository
+	scheduler *silence.Scheduler
+	clock     clock.Clock
+	logger    *slog.Logger
+}
+
+// NewSilenceHandler creates a new silence handler. The scheduler is
+// refreshed after every change, so the change takes effect on this replica
+// right away; other replicas pick it up at their next check.
+func NewSilenceHandler(repo store.SilenceRepository, scheduler *silence.Scheduler, clk clock.Clock, logger *slog.Logger) *SilenceHandler {
+	return &SilenceHandler{
+		repo:      repo,
+		scheduler: scheduler,
+		clock:     clk,
+		logger:    logger,
+	}
+}
+
+// Create handles POST /v1/silences
+// Creates a silence for a time range, or a recurring silence with a cron
+// schedule. The actor of the request is recorded as its creator.
+func (h *SilenceHandler) Create(c *fiber.Ctx) error {
+	var req domain.CreateSilenceRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+
+	// Validate the request
+	if err := req.Validate(); err != nil {
+		h.logger.Debug("validation failed", "error", err)
+		return ValidationError(c, err.Error())
+	}
+
+	// Generate ID and create the silence
+	s := req.ToSilence(uuid.New().String(), strings.Clone(c.Get(ActorHeader)), h.clock.Now().UTC())
+	if !s.IsRecurring() && !s.EndsAt.After(s.StartsAt) {
+		return DomainError(c, domain.ErrInvalidSilenceRange)
+	}
+	if err := h.repo.Create(c.Context(), s); err != nil {
+		h.logger.Error("failed to create silence", "error", err)
+		return InternalError(c, "failed to create silence")
+	}
+
+	h.refresh(c)
+	h.logger.Info("created silence", "id", s.ID, "created_by", s.CreatedBy, "recurring", s.IsRecurring())
+	return Created(c, s)
+}
+
+// List handles GET /v1/silences
+// Returns the silences, oldest first. Accepts state (pending, active,
+// expired or recurring) to return only the silences in that state.
+func (h *SilenceHandler) List(c *fiber.Ctx) error {
+	state := domain.SilenceState(c.Query("state"))
+	switch state {
+	case "", domain.SilencePending, domain.SilenceActive, domain.SilenceExpired, domain.SilenceRecurring:
+	default:
+		return BadRequest(c, "state must be pending, active, expired or recurring")
+	}
+
+	silences, err := h.repo.List(c.Context())
+	if err != nil {
+		h.logger.Error("failed to list silences", "error", err)
+		return InternalError(c, "failed to list silences")
+	}
+
+	if state != "" {
+		now := h.clock.Now()
+		filtered := make([]*domain.Silence, 0, len(silences))
+		for _, s := range silences {
+			if s.State(now) == state {
+				filtered = append(filtered, s)
+			}
+		}
+		silences = filtered
+	}
+
+	return Success(c, silences)
+}
+
+// GetByID handles GET /v1/silences/:id
+func (h *SilenceHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+	s, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get silence", "id", id, "error", err)
+		return InternalError(c, "failed to get silence")
+	}
+	return Success(c, s)
+}
+
+// Delete handles DELETE /v1/silences/:id
+// Permanently removes a silence. Deleting a recurring silence also removes
+// the occurrences materialized from it, ending one in progress.
+func (h *SilenceHandler) Delete(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if err := h.repo.Delete(c.Context(), id); err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to delete silence", "id", id, "error", err)
+		return InternalError(c, "failed to delete silence")
+	}
+	if err := h.repo.DeleteByRecurringID(c.Context(), id); err != nil {
+		h.logger.Error("failed to delete recurring silence occurrences", "id", id, "error", err)
+		return InternalError(c, "failed to delete silence")
+	}
+
+	h.refresh(c)
+	h.logger.Info("deleted silence", "id", id)
+	return NoContent(c)
+}
+
+// refresh reloads the silences in effect. A failure is logged; the next
+// check of the scheduler reloads them.
+func (h *SilenceHandler) refresh(c *fiber.Ctx) {
+	if err := h.scheduler.Refresh(c.Context()); err != nil {
+		h.logger.Warn("failed to refresh silences", "error", err)
+	}
+}
@@ -0,0 +1,95 @@
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/pkg/argustest"
+)
+
+func TestSilenceHandler_MutesNotifications(t *testing.T) {
+	ctx := context.Background()
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	create := func(body string) (int, domain.Silence) {
+		t.Helper()
+		req := newRequest(t, h, http.MethodPost, "/v1/silences", body)
+		req.Header.Set("X-Argus-Actor", "alice")
+		var result struct {
+			Data domain.Silence `json:"data"`
+		}
+		status := send(t, req, &result)
+		return status, result.Data
+	}
+	list := func(state domain.SilenceState) []domain.Silence {
+		t.Helper()
+		var result struct {
+			Data []domain.Silence `json:"data"`
+		}
+		call(t, h, http.MethodGet, "/v1/silences?state="+string(state), "", &result)
+		return result.Data
+	}
+	notifications := func(dedupKey string) int {
+		t.Helper()
+		records, err := h.NotificationLog.ListByDedupKey(ctx, dedupKey)
+		if err != nil {
+			t.Fatalf("ListByDedupKey error: %v", err)
+		}
+		return len(records)
+	}
+
+	// A silence for a time range mutes matching alerts from its creation
+	endsAt := h.Now().Add(time.Hour).Format(time.RFC3339)
+	status, oneShot := create(fmt.Sprintf(`{"comment":"batch maintenance","match":{"dedup_key_pattern":"batch-*"},"ends_at":%q}`, endsAt))
+	if status != http.StatusCreated || oneShot.CreatedBy != "alice" {
+		t.Fatalf("POST silence = %d created by %q, want 201 created by alice", status, oneShot.CreatedBy)
+	}
+
+	// A recurring silence mutes matching alerts once its occurrence is
+	// materialized; every minute for a minute is always in effect
+	status, recurring := create(`{"match":{"classes":["web"]},"schedule":{"cron":"* * * * *","duration":"1m"}}`)
+	if status != http.StatusCreated || !recurring.IsRecurring() {
+		t.Fatalf("POST recurring silence = %d %+v, want 201 with a schedule", status, recurring)
+	}
+	h.CheckSilences(t)
+
+	ingest(t, h, emID, domain.ActionTrigger, "batch", "batch-1")
+	ingest(t, h, emID, domain.ActionTrigger, "web", "web-1")
+	ingest(t, h, emID, domain.ActionTrigger, "db", "db-1")
+	for dedupKey, want := range map[string]int{"batch-1": 0, "web-1": 0, "db-1": 1} {
+		h.AwaitStatus(t, dedupKey, domain.AlertStatusActive)
+		if n := notifications(dedupKey); n != want {
+			t.Errorf("notifications of %s = %d, want %d", dedupKey, n, want)
+		}
+	}
+
+	active := list(domain.SilenceActive)
+	if len(active) != 2 || !slices.ContainsFunc(active, func(s domain.Silence) bool { return s.RecurringID == recurring.ID }) {
+		t.Fatalf("active silences = %+v, want the silence and an occurrence of the recurring one", active)
+	}
+
+	// Deleting a recurring silence ends its occurrence
+	if status := call(t, h, http.MethodDelete, "/v1/silences/"+recurring.ID, "", nil); status != http.StatusNoContent {
+		t.Fatalf("DELETE silence status = %d, want 204", status)
+	}
+	if active = list(domain.SilenceActive); len(active) != 1 || active[0].ID != oneShot.ID {
+		t.Errorf("active silences after delete = %+v, want only %s", active, oneShot.ID)
+	}
+
+	h.Advance(6 * time.Minute)
+	ingest(t, h, emID, domain.ActionTrigger, "web", "web-2")
+	h.AwaitStatus(t, "web-2", domain.AlertStatusActive)
+	if n := notifications("web-2"); n != 1 {
+		t.Errorf("notifications of web-2 after delete = %d, want 1", n)
+	}
+
+	if status, _ := create(`{"match":{},"ends_at":"2030-01-01T00:00:00Z"}`); status != http.StatusBadRequest {
+		t.Errorf("POST silence without a matcher status = %d, want 400", status)
+	}
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+
+	"argus-go/internal/slo"
+)
+
+// SLOHandler handles HTTP requests for service level objective status.
+type SLOHandler struct {
+	tracker *slo.Tracker
+	logger  *slog.Logger
+}
+
+// NewSLOHandler creates a new SLO handler.
+func NewSLOHandler(tracker *slo.Tracker, logger *slog.Logger) *SLOHandler {
+	return &SLOHandler{
+		tracker: tracker,
+		logger:  logger,
+	}
+}
+
+// Status handles GET /v1/slo
+// Returns compliance, remaining error budget and burn rates for every objective.
+func (h *SLOHandler) Status(c *fiber.Ctx) error {
+	return Success(c, h.tracker.Status())
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/quota"
+	"argus-go/internal/store"
+)
+
+// UsageHandler handles HTTP requests for the usage and quotas of event
+// managers.
+type UsageHandler struct {
+	repo             store.UsageRepository
+	eventManagerRepo store.EventManagerRepository
+	quotas           *quota.Enforcer
+	logger           *slog.Logger
+}
+
+// NewUsageHandler creates a new usage handler. quotas is nil if quotas are
+// not enforced.
+func NewUsageHandler(
+	repo store.UsageRepository,
+	eventManagerRepo store.EventManagerRepository,
+	quotas *quota.Enforcer,
+	logger *slog.Logger,
+) *UsageHandler {
+	return &UsageHandler{
+		repo:             repo,
+		eventManagerRepo: eventManagerRepo,
+		quotas:           quotas,
+		logger:           logger,
+	}
+}
+
+// List handles GET /v1/usage
+// Returns the events ingested, alerts created and notifications sent per
+// event manager, by day and in total, for the days overlapping from and to.
+func (h *UsageHandler) List(c *fiber.Ctx) error {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		return ValidationError(c, err.Error())
+	}
+	return h.report(c, filter)
+}
+
+// GetByEventManager handles GET /v1/usage/:event_manager_id
+// Returns the usage of a single event manager, including a deleted one.
+func (h *UsageHandler) GetByEventManager(c *fiber.Ctx) error {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		return ValidationError(c, err.Error())
+	}
+
+	filter.EventManagerID = c.Params("event_manager_id")
+	if _, err := h.eventManagerRepo.GetByID(c.Context(), filter.EventManagerID); err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get event manager", "id", filter.EventManagerID, "error", err)
+		return InternalError(c, "failed to get event manager")
+	}
+
+	return h.report(c, filter)
+}
+
+// GetQuota handles GET /v1/usage/:event_manager_id/quota
+// Returns the quota status of an event manager: its limits, what it used
+// today, this month and in open alerts, and the limits near exhaustion or
+// reached. An event manager without a quota returns 404 Not Found; while
+// quotas are not enforced, or not checked yet, it returns 503.
+func (h *UsageHandler) GetQuota(c *fiber.Ctx) error {
+	id := c.Params("event_manager_id")
+	em, err := h.eventManagerRepo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to get event manager", "id", id, "error", err)
+		return InternalError(c, "failed to get event manager")
+	}
+	if !em.Quota.IsEnabled() {
+		return NotFound(c, "event manager has no quota")
+	}
+
+	status := h.quotas.Status(id)
+	if status == nil {
+		return Error(c, fiber.StatusServiceUnavailable, ErrCodeUnavailable, "quota not checked yet")
+	}
+	return Success(c, status)
+}
+
+// report responds with the usage report of a filter.
+func (h *UsageHandler) report(c *fiber.Ctx, filter domain.ReportFilter) error {
+	byDay, err := h.repo.List(c.Context(), filter)
+	if err != nil {
+		h.logger.Error("failed to list usage", "error", err)
+		return InternalError(c, "failed to list usage")
+	}
+
+	return Success(c, domain.BuildUsageReport(byDay, filter))
+}
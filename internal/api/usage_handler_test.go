@@ -0,0 +1,118 @@
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/pkg/argustest"
+)
+
+func TestUsageHandler_GetByEventManager(t *testing.T) {
+	h := argustest.Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	ingest(t, h, emID, domain.ActionTrigger, "storage", "host-1", "host-2", "host-2")
+	h.AwaitStatus(t, "host-2", domain.AlertStatusActive)
+	h.FlushUsage(t)
+
+	var body struct {
+		Data domain.UsageReport `json:"data"`
+	}
+	if status := call(t, h, http.MethodGet, "/v1/usage/"+emID, "", &body); status != http.StatusOK {
+		t.Fatalf("GET usage status = %d, want 200", status)
+	}
+
+	want := domain.Usage{EventManagerID: emID, EventsIngested: 3, AlertsCreated: 2, NotificationsSent: 1}
+	if len(body.Data.Totals) != 1 || *body.Data.Totals[0] != want {
+		t.Errorf("totals = %+v, want %+v", body.Data.Totals, want)
+	}
+	want.Day = domain.ReportDay(h.Now())
+	if len(body.Data.ByDay) != 1 || *body.Data.ByDay[0] != want {
+		t.Errorf("by day = %+v, want %+v", body.Data.ByDay, want)
+	}
+
+	if status := call(t, h, http.MethodGet, "/v1/usage/unknown", "", nil); status != http.StatusNotFound {
+		t.Errorf("unknown event manager status = %d, want 404", status)
+	}
+}
+
+func TestUsageHandler_Quota(t *testing.T) {
+	ctx := context.Background()
+	h := argustest.Start(t)
+
+	setQuota := func(emID string, quota domain.Quota) {
+		t.Helper()
+		updateEventManager(t, h, emID, func(em *domain.EventManager) { em.Quota = quota })
+	}
+	post := func(emID, dedupKey string, action domain.Action) (int, *domain.QuotaStatus) {
+		t.Helper()
+		body := fmt.Sprintf(`{"event_manager_id":%q,"summary":"disk full","action":%q,"class":"storage","dedupKey":%q}`, emID, action, dedupKey)
+		var result struct {
+			Data struct {
+				Quota *domain.QuotaStatus `json:"quota"`
+			} `json:"data"`
+		}
+		status := call(t, h, http.MethodPost, "/v1/events", body, &result)
+		return status, result.Data.Quota
+	}
+
+	// Triggers over the daily limit are rejected, resolves still accepted
+	rejecting := h.CreateEventManager(t, "class", 5*time.Minute)
+	setQuota(rejecting, domain.Quota{MaxEventsPerDay: 3})
+	h.CheckQuotas(t)
+
+	for _, dedupKey := range []string{"host-1", "host-2", "host-3"} {
+		if status, _ := post(rejecting, dedupKey, domain.ActionTrigger); status != http.StatusAccepted {
+			t.Fatalf("trigger %s status = %d, want 202", dedupKey, status)
+		}
+	}
+	if status, _ := post(rejecting, "host-4", domain.ActionTrigger); status != http.StatusTooManyRequests {
+		t.Fatalf("trigger over quota status = %d, want 429", status)
+	}
+	h.Sync(t)
+	h.CheckQuotas(t)
+
+	var body struct {
+		Data domain.QuotaStatus `json:"data"`
+	}
+	status := call(t, h, http.MethodGet, "/v1/usage/"+rejecting+"/quota", "", &body)
+	if status != http.StatusOK || body.Data.EventsToday != 3 || !slices.Equal(body.Data.Exceeded, []string{domain.QuotaEventsPerDay}) {
+		t.Errorf("GET quota = %d %+v, want 3 events today exceeding events_per_day", status, body.Data)
+	}
+
+	status, quota := post(rejecting, "host-1", domain.ActionResolve)
+	if status != http.StatusAccepted || quota == nil || !quota.IsExceeded() {
+		t.Errorf("resolve over quota = %d with quota %+v, want 202 with the exceeded quota", status, quota)
+	}
+
+	// Under degrade, triggers become alerts but notify no one
+	degrading := h.CreateEventManager(t, "class", 5*time.Minute)
+	setQuota(degrading, domain.Quota{MaxOpenAlerts: 1, Enforcement: domain.QuotaDegrade})
+	ingest(t, h, degrading, domain.ActionTrigger, "db", "db-1")
+	h.CheckQuotas(t)
+
+	if status, _ := post(degrading, "web-1", domain.ActionTrigger); status != http.StatusAccepted {
+		t.Fatalf("trigger under degrade status = %d, want 202", status)
+	}
+	h.Sync(t)
+	h.AwaitStatus(t, "web-1", domain.AlertStatusActive)
+	for dedupKey, want := range map[string]int{"db-1": 1, "web-1": 0} {
+		records, err := h.NotificationLog.ListByDedupKey(ctx, dedupKey)
+		if err != nil {
+			t.Fatalf("ListByDedupKey error: %v", err)
+		}
+		if len(records) != want {
+			t.Errorf("notifications of %s = %d, want %d", dedupKey, len(records), want)
+		}
+	}
+
+	// Event managers without a quota have no quota status
+	if status := call(t, h, http.MethodGet, "/v1/usage/"+h.CreateEventManager(t, "class", time.Minute)+"/quota", "", nil); status != http.StatusNotFound {
+		t.Errorf("GET quota without a quota status = %d, want 404", status)
+	}
+}
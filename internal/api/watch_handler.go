@@ -0,0 +1,137 @@
+package api
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/notification"
+	"argus-go/internal/store"
+)
+
+// WatchHandler handles HTTP requests for the personal alert watches of users.
+// The user is the actor of the request, named by the ActorHeader; users only
+// see and delete their own watches.
+type WatchHandler struct {
+	repo     store.WatchRepository
+	watchers *notification.WatchNotifier
+	logger   *slog.Logger
+}
+
+// NewWatchHandler creates a new watch handler. The watch notifier tells
+// which channels can be watched.
+func NewWatchHandler(repo store.WatchRepository, watchers *notification.WatchNotifier, logger *slog.Logger) *WatchHandler {
+	return &WatchHandler{
+		repo:     repo,
+		watchers: watchers,
+		logger:   logger,
+	}
+}
+
+// Create handles POST /v1/watches
+// Creates a watch of the actor.
+func (h *WatchHandler) Create(c *fiber.Ctx) error {
+	user := c.Get(ActorHeader)
+	if user == "" {
+		return BadRequest(c, ActorHeader+" header: "+domain.ErrEmptyWatchUser.Error())
+	}
+
+	var req domain.CreateWatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Debug("failed to parse request body", "error", err)
+		return BadRequest(c, "invalid request body")
+	}
+
+	// Validate the request
+	if err := req.Validate(); err != nil {
+		h.logger.Debug("validation failed", "error", err)
+		return ValidationError(c, err.Error())
+	}
+	if !h.watchers.Delivers(req.Channel.Type) {
+		return ValidationError(c, "channel.type "+string(req.Channel.Type)+": "+domain.ErrUnavailableWatchChannel.Error())
+	}
+
+	// Generate ID and create the watch
+	watch := req.ToWatch(uuid.New().String(), strings.Clone(user))
+	if err := h.repo.Create(c.Context(), watch); err != nil {
+		h.logger.Error("failed to create watch", "error", err)
+		return InternalError(c, "failed to create watch")
+	}
+
+	h.logger.Info("created watch", "id", watch.ID, "user", user)
+	return Created(c, watch)
+}
+
+// List handles GET /v1/watches
+// Returns the watches of the actor, oldest first.
+func (h *WatchHandler) List(c *fiber.Ctx) error {
+	user := c.Get(ActorHeader)
+	if user == "" {
+		return BadRequest(c, ActorHeader+" header: "+domain.ErrEmptyWatchUser.Error())
+	}
+
+	watches, err := h.repo.ListByUser(c.Context(), user)
+	if err != nil {
+		h.logger.Error("failed to list watches", "error", err)
+		return InternalError(c, "failed to list watches")
+	}
+
+	return Success(c, watches)
+}
+
+// GetByID handles GET /v1/watches/:id
+// Returns a watch of the actor by ID.
+func (h *WatchHandler) GetByID(c *fiber.Ctx) error {
+	watch, err := h.own(c)
+	if err != nil || watch == nil {
+		return err
+	}
+	return Success(c, watch)
+}
+
+// Delete handles DELETE /v1/watches/:id
+// Permanently removes a watch of the actor.
+func (h *WatchHandler) Delete(c *fiber.Ctx) error {
+	watch, err := h.own(c)
+	if err != nil || watch == nil {
+		return err
+	}
+
+	if err := h.repo.Delete(c.Context(), watch.ID); err != nil {
+		if isDomainError(err) {
+			return DomainError(c, err)
+		}
+		h.logger.Error("failed to delete watch", "id", watch.ID, "error", err)
+		return InternalError(c, "failed to delete watch")
+	}
+
+	h.logger.Info("deleted watch", "id", watch.ID, "user", watch.User)
+	return NoContent(c)
+}
+
+// own fetches the watch of the request's id parameter if it belongs to the
+// actor. Otherwise it responds, and returns a nil watch with the error of
+// the response; the watches of other users are not found.
+func (h *WatchHandler) own(c *fiber.Ctx) (*domain.Watch, error) {
+	user := c.Get(ActorHeader)
+	if user == "" {
+		return nil, BadRequest(c, ActorHeader+" header: "+domain.ErrEmptyWatchUser.Error())
+	}
+
+	id := c.Params("id")
+	watch, err := h.repo.GetByID(c.Context(), id)
+	if err != nil {
+		if isDomainError(err) {
+			return nil, DomainError(c, err)
+		}
+		h.logger.Error("failed to get watch", "id", id, "error", err)
+		return nil, InternalError(c, "failed to get watch")
+	}
+	if watch.User != user {
+		return nil, DomainError(c, domain.ErrWatchNotFound)
+	}
+	return watch, nil
+}
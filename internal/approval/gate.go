@@ -0,0 +1,297 @@
+// Package approval holds destructive admin operations for approval, so a
+// single mistaken request can't purge configuration or resolve alert groups
+// in bulk.
+package approval
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// Operation carries out a destructive operation and describes what it did.
+type Operation func(ctx context.Context) (string, error)
+
+// pending is an approval with the operation it holds.
+type pending struct {
+	approval domain.Approval
+	run      Operation
+}
+
+// Gate holds destructive operations until they are approved, per the
+// configured mode, and records them and their approvals in the audit log.
+// Held operations live in the memory of the replica that received them, so
+// they must be approved or canceled through the same replica.
+type Gate struct {
+	cfg    config.ApprovalsConfig
+	audit  store.AuditLogRepository
+	clock  clock.Clock
+	logger *slog.Logger
+
+	mu        sync.Mutex
+	approvals map[string]*pending
+}
+
+// NewGate creates a gate recording to audit.
+func NewGate(cfg config.ApprovalsConfig, audit store.AuditLogRepository, clk clock.Clock, logger *slog.Logger) *Gate {
+	return &Gate{
+		cfg:       cfg,
+		audit:     audit,
+		clock:     clk,
+		logger:    logger,
+		approvals: make(map[string]*pending),
+	}
+}
+
+// Enabled returns true if operations must be held for approval. A nil gate
+// holds nothing.
+func (g *Gate) Enabled() bool {
+	return g != nil && g.cfg.Mode != ""
+}
+
+// Request holds an operation on target, requested by actor. In two-person
+// mode the actor is required, so a different approver can be told apart.
+func (g *Gate) Request(ctx context.Context, operation, target, actor string, run Operation) (*domain.Approval, error) {
+	if g.cfg.Mode == config.ApprovalModeTwoPerson && actor == "" {
+		return nil, domain.ErrApprovalActor
+	}
+
+	now := g.clock.Now().UTC()
+	p := &pending{
+		approval: domain.Approval{
+			ID:          uuid.New().String(),
+			Operation:   operation,
+			Target:      target,
+			RequestedBy: actor,
+			RequestedAt: now,
+			Status:      domain.ApprovalPending,
+		},
+		run: run,
+	}
+	if g.cfg.Mode == config.ApprovalModeDelay {
+		executeAfter := now.Add(g.cfg.Delay)
+		p.approval.ExecuteAfter = &executeAfter
+	}
+
+	g.mu.Lock()
+	g.approvals[p.approval.ID] = p
+	approval := p.approval
+	g.mu.Unlock()
+
+	g.record(ctx, &approval, actor, domain.AuditApprovalRequested, "")
+	return &approval, nil
+}
+
+// Approve runs a held operation. In two-person mode the approver must differ
+// from the requester; in delay mode operations need no approval.
+func (g *Gate) Approve(ctx context.Context, id, actor string) (*domain.Approval, error) {
+	if g.cfg.Mode == config.ApprovalModeDelay {
+		return nil, domain.ErrApprovalDelayed
+	}
+	if actor == "" {
+		return nil, domain.ErrApprovalActor
+	}
+
+	p, err := g.claim(id, func(a *domain.Approval) error {
+		if a.RequestedBy == actor {
+			return domain.ErrSameApprover
+		}
+		return nil
+	}, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	g.record(ctx, &p.approval, actor, domain.AuditApprovalApproved, "")
+	return g.execute(ctx, p, actor), nil
+}
+
+// Cancel drops a held operation without running it.
+func (g *Gate) Cancel(ctx context.Context, id, actor string) (*domain.Approval, error) {
+	p, err := g.claim(id, nil, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	p.approval.Status = domain.ApprovalCanceled
+	approval := p.approval
+	g.mu.Unlock()
+
+	g.record(ctx, &approval, actor, domain.AuditApprovalCanceled, "")
+	return &approval, nil
+}
+
+// Get returns an approval by ID.
+func (g *Gate) Get(id string) (*domain.Approval, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	p, ok := g.approvals[id]
+	if !ok {
+		return nil, domain.ErrApprovalNotFound
+	}
+	approval := p.approval
+	return &approval, nil
+}
+
+// List returns every approval, newest first.
+func (g *Gate) List() []*domain.Approval {
+	g.mu.Lock()
+	approvals := make([]*domain.Approval, 0, len(g.approvals))
+	for _, p := range g.approvals {
+		approval := p.approval
+		approvals = append(approvals, &approval)
+	}
+	g.mu.Unlock()
+
+	sort.Slice(approvals, func(i, j int) bool {
+		return approvals[i].RequestedAt.After(approvals[j].RequestedAt)
+	})
+	return approvals
+}
+
+// Start runs operations whose delay has passed at every interval until ctx
+// is canceled. It returns right away unless the gate is in delay mode.
+func (g *Gate) Start(ctx context.Context) {
+	if g.cfg.Mode != config.ApprovalModeDelay {
+		return
+	}
+	g.logger.Info("starting delayed operations", "delay", g.cfg.Delay, "check_interval", g.cfg.CheckInterval)
+
+	ticker := time.NewTicker(g.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		g.RunDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue runs the held operations whose delay has passed, oldest first.
+func (g *Gate) RunDue(ctx context.Context) {
+	now := g.clock.Now().UTC()
+
+	g.mu.Lock()
+	var due []*pending
+	for _, p := range g.approvals {
+		a := &p.approval
+		if a.IsPending() && a.ExecuteAfter != nil && !now.Before(*a.ExecuteAfter) {
+			// Claimed under the lock, so a concurrent cancel finds it decided
+			a.Status = domain.ApprovalExecuted
+			a.DecidedAt = &now
+			due = append(due, p)
+		}
+	}
+	g.mu.Unlock()
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].approval.RequestedAt.Before(due[j].approval.RequestedAt)
+	})
+	for _, p := range due {
+		g.execute(ctx, p, "")
+	}
+}
+
+// Executed records an operation run right away, without approval.
+func (g *Gate) Executed(ctx context.Context, operation, target, actor, result string) {
+	if g == nil {
+		return
+	}
+	g.write(ctx, &domain.AuditEntry{
+		At:        g.clock.Now().UTC(),
+		Actor:     actor,
+		Action:    domain.AuditOperationExecuted,
+		Operation: operation,
+		Target:    target,
+		Detail:    result,
+	})
+}
+
+// claim takes a pending approval for a decision by actor, after check
+// accepts it, so two decisions can't both act on it.
+func (g *Gate) claim(id string, check func(*domain.Approval) error, actor string) (*pending, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	p, ok := g.approvals[id]
+	if !ok {
+		return nil, domain.ErrApprovalNotFound
+	}
+	if !p.approval.IsPending() {
+		return nil, domain.ErrApprovalNotPending
+	}
+	if check != nil {
+		if err := check(&p.approval); err != nil {
+			return nil, err
+		}
+	}
+
+	now := g.clock.Now().UTC()
+	p.approval.Status = domain.ApprovalExecuted
+	p.approval.DecidedBy = actor
+	p.approval.DecidedAt = &now
+	return p, nil
+}
+
+// execute runs a claimed operation and records its outcome.
+func (g *Gate) execute(ctx context.Context, p *pending, actor string) *domain.Approval {
+	result, err := p.run(ctx)
+
+	g.mu.Lock()
+	action := domain.AuditOperationExecuted
+	if err != nil {
+		p.approval.Status = domain.ApprovalFailed
+		result = err.Error()
+		action = domain.AuditOperationFailed
+	}
+	p.approval.Result = result
+	approval := p.approval
+	g.mu.Unlock()
+
+	g.record(ctx, &approval, actor, action, result)
+	return &approval
+}
+
+// record writes an audit entry about an approval.
+func (g *Gate) record(ctx context.Context, approval *domain.Approval, actor, action, detail string) {
+	g.write(ctx, &domain.AuditEntry{
+		At:         g.clock.Now().UTC(),
+		Actor:      actor,
+		Action:     action,
+		Operation:  approval.Operation,
+		Target:     approval.Target,
+		ApprovalID: approval.ID,
+		Detail:     detail,
+	})
+}
+
+// write logs an audit entry and appends it to the audit log. A failed write
+// is logged; the structured log keeps the entry.
+func (g *Gate) write(ctx context.Context, entry *domain.AuditEntry) {
+	g.logger.Warn("audit",
+		"action", entry.Action,
+		"operation", entry.Operation,
+		"target", entry.Target,
+		"actor", entry.Actor,
+		"approval_id", entry.ApprovalID,
+		"detail", entry.Detail,
+	)
+	if err := g.audit.Record(ctx, entry); err != nil {
+		g.logger.Error("failed to record audit entry", "action", entry.Action, "operation", entry.Operation, "error", err)
+	}
+}
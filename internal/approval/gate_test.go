@@ -0,0 +1,172 @@
+package approval
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	storemem "argus-go/internal/store/memory"
+)
+
+func newTestGate(mode string) (*Gate, *storemem.AuditLogRepository, *clock.Fake) {
+	audit := storemem.NewAuditLogRepository()
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := config.ApprovalsConfig{Mode: mode, Delay: 5 * time.Minute, CheckInterval: time.Second}
+	return NewGate(cfg, audit, clk, logger), audit, clk
+}
+
+func auditActions(t *testing.T, audit *storemem.AuditLogRepository) []string {
+	t.Helper()
+	entries, err := audit.List(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	actions := make([]string, len(entries))
+	for i, entry := range entries {
+		// Oldest first
+		actions[len(entries)-1-i] = entry.Action
+	}
+	return actions
+}
+
+func TestGate_TwoPerson(t *testing.T) {
+	gate, audit, _ := newTestGate(config.ApprovalModeTwoPerson)
+	ctx := context.Background()
+
+	runs := 0
+	run := func(context.Context) (string, error) {
+		runs++
+		return "purged", nil
+	}
+
+	if _, err := gate.Request(ctx, domain.OperationPurgeEventManager, "em-1", "", run); !errors.Is(err, domain.ErrApprovalActor) {
+		t.Fatalf("Request() without actor error = %v, want %v", err, domain.ErrApprovalActor)
+	}
+
+	pending, err := gate.Request(ctx, domain.OperationPurgeEventManager, "em-1", "alice", run)
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	if !pending.IsPending() || runs != 0 {
+		t.Fatalf("operation ran before approval: status %s, runs %d", pending.Status, runs)
+	}
+
+	if _, err := gate.Approve(ctx, pending.ID, "alice"); !errors.Is(err, domain.ErrSameApprover) {
+		t.Errorf("Approve() by the requester error = %v, want %v", err, domain.ErrSameApprover)
+	}
+
+	approved, err := gate.Approve(ctx, pending.ID, "bob")
+	if err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if approved.Status != domain.ApprovalExecuted || approved.DecidedBy != "bob" || approved.Result != "purged" || runs != 1 {
+		t.Errorf("approved = %+v, runs %d", approved, runs)
+	}
+
+	if _, err := gate.Approve(ctx, pending.ID, "carol"); !errors.Is(err, domain.ErrApprovalNotPending) {
+		t.Errorf("second Approve() error = %v, want %v", err, domain.ErrApprovalNotPending)
+	}
+
+	want := []string{domain.AuditApprovalRequested, domain.AuditApprovalApproved, domain.AuditOperationExecuted}
+	if got := auditActions(t, audit); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("audit log = %v, want %v", got, want)
+	}
+}
+
+func TestGate_Delay(t *testing.T) {
+	gate, audit, clk := newTestGate(config.ApprovalModeDelay)
+	ctx := context.Background()
+
+	var ran []string
+	request := func(target string, err error) *domain.Approval {
+		t.Helper()
+		pending, reqErr := gate.Request(ctx, domain.OperationForceResolve, target, "", func(context.Context) (string, error) {
+			ran = append(ran, target)
+			return "resolved", err
+		})
+		if reqErr != nil {
+			t.Fatalf("Request() error = %v", reqErr)
+		}
+		return pending
+	}
+
+	kept := request("db-down", nil)
+	failing := request("web-down", errors.New("store unavailable"))
+	canceled := request("api-down", nil)
+
+	if _, err := gate.Approve(ctx, kept.ID, "bob"); !errors.Is(err, domain.ErrApprovalDelayed) {
+		t.Errorf("Approve() error = %v, want %v", err, domain.ErrApprovalDelayed)
+	}
+	if _, err := gate.Cancel(ctx, canceled.ID, "alice"); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+
+	clk.Advance(4 * time.Minute)
+	gate.RunDue(ctx)
+	if len(ran) != 0 {
+		t.Fatalf("ran %v before the delay passed", ran)
+	}
+
+	clk.Advance(time.Minute)
+	gate.RunDue(ctx)
+	if len(ran) != 2 {
+		t.Fatalf("ran %v, want db-down and web-down", ran)
+	}
+
+	for _, tt := range []struct {
+		id   string
+		want domain.ApprovalStatus
+	}{
+		{kept.ID, domain.ApprovalExecuted},
+		{failing.ID, domain.ApprovalFailed},
+		{canceled.ID, domain.ApprovalCanceled},
+	} {
+		approval, err := gate.Get(tt.id)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if approval.Status != tt.want {
+			t.Errorf("%s status = %s, want %s", approval.Target, approval.Status, tt.want)
+		}
+	}
+
+	// Operations run once
+	gate.RunDue(ctx)
+	if len(ran) != 2 {
+		t.Errorf("ran %v again", ran)
+	}
+
+	var failed int
+	for _, action := range auditActions(t, audit) {
+		if action == domain.AuditOperationFailed {
+			failed++
+		}
+	}
+	if failed != 1 {
+		t.Errorf("audit log has %d failed operations, want 1", failed)
+	}
+}
+
+func TestGate_Disabled(t *testing.T) {
+	var gate *Gate
+	if gate.Enabled() {
+		t.Error("nil gate is enabled")
+	}
+	gate.Executed(context.Background(), domain.OperationPurgeGroupingRule, "rule-1", "alice", "purged")
+
+	gate, audit, _ := newTestGate("")
+	if gate.Enabled() {
+		t.Error("gate without mode is enabled")
+	}
+	gate.Executed(context.Background(), domain.OperationPurgeGroupingRule, "rule-1", "alice", "purged")
+	if got := auditActions(t, audit); len(got) != 1 || got[0] != domain.AuditOperationExecuted {
+		t.Errorf("audit log = %v, want the executed operation", got)
+	}
+}
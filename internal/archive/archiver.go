@@ -0,0 +1,353 @@
+// Package archive writes every queued event to object storage as NDJSON,
+// partitioned by the hour the event was received, for compliance and
+// offline replay. Events are buffered in memory and written in batches, so
+// archiving adds no request per event.
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
+	"argus-go/internal/metrics"
+	"argus-go/internal/queue"
+)
+
+// Results of archiving an event, the "result" label of metrics.ArchivedEvents.
+const (
+	resultArchived = "archived"
+	resultFailed   = "failed"
+	resultDropped  = "dropped"
+)
+
+// contentType is the type of archive objects: gzipped NDJSON.
+const contentType = "application/gzip"
+
+// Store reads and writes archive objects, such as a Bucket.
+type Store interface {
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Archiver buffers queued events and writes them to a store, one object per
+// batch of events received in the same hour. A nil Archiver archives
+// nothing. It is safe for concurrent use.
+type Archiver struct {
+	store      Store
+	prefix     string
+	batchSize  int
+	maxPending int
+	clock      clock.Clock
+	logger     *slog.Logger
+
+	// full signals Start that a batch is ready to be written.
+	full chan struct{}
+
+	mu      sync.Mutex
+	pending map[time.Time][][]byte
+	count   int
+}
+
+// NewArchiver creates an archiver writing to store, with the prefix, batch
+// size and pending limit of cfg.
+func NewArchiver(store Store, cfg config.ArchiveConfig, clk clock.Clock, logger *slog.Logger) *Archiver {
+	return &Archiver{
+		store:      store,
+		prefix:     cfg.Prefix,
+		batchSize:  cfg.BatchSize,
+		maxPending: cfg.MaxPending,
+		clock:      clk,
+		logger:     logger,
+		full:       make(chan struct{}, 1),
+		pending:    make(map[time.Time][][]byte),
+	}
+}
+
+// Handle buffers a queued event, as a queue.MessageHandler. It never fails,
+// so the queue moves on: events that aren't JSON, or arrive while the
+// pending buffer is full, are dropped.
+func (a *Archiver) Handle(_ context.Context, msg *queue.Message) error {
+	var event struct {
+		ReceivedAt time.Time `json:"received_at"`
+	}
+	var line bytes.Buffer
+	if err := json.Compact(&line, msg.Value); err != nil || json.Unmarshal(msg.Value, &event) != nil {
+		metrics.ArchivedEvents.WithLabelValues(resultDropped).Inc()
+		a.logger.Warn("dropping event that is not JSON from the archive")
+		return nil
+	}
+	if event.ReceivedAt.IsZero() {
+		event.ReceivedAt = a.clock.Now()
+	}
+	hour := event.ReceivedAt.UTC().Truncate(time.Hour)
+
+	a.mu.Lock()
+	if a.count >= a.maxPending {
+		a.mu.Unlock()
+		metrics.ArchivedEvents.WithLabelValues(resultDropped).Inc()
+		return nil
+	}
+	a.pending[hour] = append(a.pending[hour], line.Bytes())
+	a.count++
+	full := len(a.pending[hour]) >= a.batchSize
+	a.mu.Unlock()
+
+	if full {
+		select {
+		case a.full <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Start writes the buffered events every interval, and as soon as a batch
+// is full, until the context is canceled. Events buffered after that are
+// written by a last call to Flush.
+func (a *Archiver) Start(ctx context.Context, interval time.Duration) {
+	a.logger.Info("starting event archive", "flush_interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-a.full:
+		}
+		if err := a.Flush(ctx); err != nil && ctx.Err() == nil {
+			a.logger.Warn("failed to archive events", "error", err)
+		}
+	}
+}
+
+// Flush writes the buffered events, in objects of at most batch size
+// events. Events that fail to be written stay buffered for the next flush,
+// and the first error is returned.
+func (a *Archiver) Flush(ctx context.Context) error {
+	if a == nil {
+		return nil
+	}
+
+	a.mu.Lock()
+	pending := a.pending
+	a.pending = make(map[time.Time][][]byte)
+	a.count = 0
+	a.mu.Unlock()
+
+	hours := make([]time.Time, 0, len(pending))
+	for hour := range pending {
+		hours = append(hours, hour)
+	}
+	sort.Slice(hours, func(i, j int) bool { return hours[i].Before(hours[j]) })
+
+	var firstErr error
+	for _, hour := range hours {
+		lines := pending[hour]
+		for start := 0; start < len(lines); start += a.batchSize {
+			batch := lines[start:min(start+a.batchSize, len(lines))]
+			if err := a.write(ctx, hour, batch); err != nil {
+				metrics.ArchivedEvents.WithLabelValues(resultFailed).Add(float64(len(batch)))
+				if firstErr == nil {
+					firstErr = err
+				}
+				a.restore(hour, batch)
+				continue
+			}
+			metrics.ArchivedEvents.WithLabelValues(resultArchived).Add(float64(len(batch)))
+		}
+	}
+	return firstErr
+}
+
+// write stores a batch of events received in an hour as a new object.
+func (a *Archiver) write(ctx context.Context, hour time.Time, lines [][]byte) error {
+	body, err := compress(lines)
+	if err != nil {
+		return err
+	}
+	return a.store.Put(ctx, a.key(hour), body, contentType)
+}
+
+// Erase removes the events of an event manager from the archive: those
+// still buffered, and those in every object written, which are rewritten
+// without them, or deleted if nothing is left. Returns the number of events
+// removed and of objects changed. Objects that fail are skipped, so a
+// later call can pick up where this one failed, and the first error is
+// returned.
+func (a *Archiver) Erase(ctx context.Context, eventManagerID string) (events, objects int, err error) {
+	a.mu.Lock()
+	for hour, lines := range a.pending {
+		kept, removed := withoutEventManager(lines, eventManagerID)
+		a.pending[hour] = kept
+		a.count -= removed
+		events += removed
+	}
+	a.mu.Unlock()
+
+	keys, err := a.store.List(ctx, a.prefix+"/")
+	if err != nil {
+		return events, objects, err
+	}
+
+	var firstErr error
+	for _, key := range keys {
+		removed, err := a.eraseObject(ctx, key, eventManagerID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return events, objects, err
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if removed > 0 {
+			events += removed
+			objects++
+		}
+	}
+	return events, objects, firstErr
+}
+
+// eraseObject removes the events of an event manager from an object and
+// returns how many it removed.
+func (a *Archiver) eraseObject(ctx context.Context, key, eventManagerID string) (int, error) {
+	body, err := a.store.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	lines, err := decompress(body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+
+	kept, removed := withoutEventManager(lines, eventManagerID)
+	switch {
+	case removed == 0:
+		return 0, nil
+	case len(kept) == 0:
+		return removed, a.store.Delete(ctx, key)
+	}
+
+	body, err = compress(kept)
+	if err != nil {
+		return 0, err
+	}
+	return removed, a.store.Put(ctx, key, body, contentType)
+}
+
+// withoutEventManager returns the archived events that don't belong to an
+// event manager, and the number of those that do.
+func withoutEventManager(lines [][]byte, eventManagerID string) (kept [][]byte, removed int) {
+	kept = lines[:0:0]
+	for _, line := range lines {
+		var event struct {
+			EventManagerID string `json:"event_manager_id"`
+		}
+		if json.Unmarshal(line, &event) == nil && event.EventManagerID == eventManagerID {
+			removed++
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return kept, removed
+}
+
+// compress encodes events as gzipped NDJSON.
+func compress(lines [][]byte) ([]byte, error) {
+	var body bytes.Buffer
+	zw := gzip.NewWriter(&body)
+	for _, line := range lines {
+		zw.Write(line)
+		zw.Write([]byte{'\n'})
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress events: %w", err)
+	}
+	return body.Bytes(), nil
+}
+
+// decompress decodes the events of gzipped NDJSON.
+func decompress(body []byte) ([][]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines [][]byte
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// key names a new object of the partition of an hour. Objects are named by
+// the time they are written and a random suffix, so replicas never write
+// the same key.
+func (a *Archiver) key(hour time.Time) string {
+	return fmt.Sprintf("%s/year=%04d/month=%02d/day=%02d/hour=%02d/%s-%s.ndjson.gz",
+		a.prefix, hour.Year(), hour.Month(), hour.Day(), hour.Hour(),
+		a.clock.Now().UTC().Format("20060102T150405.000000000Z"), uuid.New().String()[:8])
+}
+
+// restore puts back events that could not be written, unless the pending
+// buffer has since filled up.
+func (a *Archiver) restore(hour time.Time, lines [][]byte) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if room := a.maxPending - a.count; len(lines) > room {
+		metrics.ArchivedEvents.WithLabelValues(resultDropped).Add(float64(len(lines) - max(room, 0)))
+		lines = lines[:max(room, 0)]
+	}
+	a.pending[hour] = append(a.pending[hour], lines...)
+	a.count += len(lines)
+}
+
+// Consumer wraps a queue.Consumer to hand every message to an archiver
+// before the handler, for queues that deliver a message to a single
+// consumer, like the in-memory queue.
+type Consumer struct {
+	next     queue.Consumer
+	archiver *Archiver
+}
+
+// NewConsumer wraps next to archive its messages with archiver.
+func NewConsumer(next queue.Consumer, archiver *Archiver) *Consumer {
+	return &Consumer{next: next, archiver: archiver}
+}
+
+// Start implements queue.Consumer.
+func (c *Consumer) Start(ctx context.Context, handler queue.MessageHandler) error {
+	return c.next.Start(ctx, func(ctx context.Context, msg *queue.Message) error {
+		_ = c.archiver.Handle(ctx, msg)
+		return handler(ctx, msg)
+	})
+}
+
+// Close closes the wrapped consumer.
+func (c *Consumer) Close() error {
+	return c.next.Close()
+}
@@ -0,0 +1,261 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
+	"argus-go/internal/queue"
+)
+
+// memoryStore records the objects put, failing while err is set.
+type memoryStore struct {
+	mu      sync.Mutex
+	objects map[string]string
+	err     error
+}
+
+func (s *memoryStore) Put(_ context.Context, key string, body []byte, _ string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return err
+	}
+	if s.objects == nil {
+		s.objects = make(map[string]string)
+	}
+	s.objects[key] = string(data)
+	return nil
+}
+
+func (s *memoryStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, errors.New("no such key")
+	}
+	var lines [][]byte
+	for _, line := range strings.SplitAfter(data, "\n") {
+		if line != "" {
+			lines = append(lines, []byte(strings.TrimSuffix(line, "\n")))
+		}
+	}
+	return compress(lines)
+}
+
+func (s *memoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *memoryStore) List(_ context.Context, prefix string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func newTestArchiver(store Store, batchSize, maxPending int) *Archiver {
+	return NewArchiver(store, config.ArchiveConfig{
+		Prefix:     "events",
+		BatchSize:  batchSize,
+		MaxPending: maxPending,
+	}, clock.NewFake(time.Date(2026, 10, 15, 14, 30, 0, 0, time.UTC)), slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func message(value string) *queue.Message {
+	return &queue.Message{Value: []byte(value)}
+}
+
+func TestArchiver_FlushPartitionsByHour(t *testing.T) {
+	store := &memoryStore{}
+	archiver := newTestArchiver(store, 10, 100)
+	ctx := context.Background()
+
+	for _, value := range []string{
+		`{"dedupKey": "a", "received_at": "2026-10-15T13:05:00Z"}`,
+		`{"dedupKey": "b", "received_at": "2026-10-15T13:59:59Z"}`,
+		`{"dedupKey": "c", "received_at": "2026-10-15T14:01:00Z"}`,
+		`{"dedupKey": "d"}`,
+		`not json`,
+	} {
+		if err := archiver.Handle(ctx, message(value)); err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+	}
+	if err := archiver.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	byHour := make(map[string]string)
+	for key, data := range store.objects {
+		if !strings.HasSuffix(key, ".ndjson.gz") {
+			t.Errorf("key %q has no .ndjson.gz suffix", key)
+		}
+		byHour[key[:strings.LastIndex(key, "/")]] = data
+	}
+	want := map[string]string{
+		"events/year=2026/month=10/day=15/hour=13": `{"dedupKey":"a","received_at":"2026-10-15T13:05:00Z"}` + "\n" +
+			`{"dedupKey":"b","received_at":"2026-10-15T13:59:59Z"}` + "\n",
+		// Events without received_at are archived at the time they are handled
+		"events/year=2026/month=10/day=15/hour=14": `{"dedupKey":"c","received_at":"2026-10-15T14:01:00Z"}` + "\n" +
+			`{"dedupKey":"d"}` + "\n",
+	}
+	if len(byHour) != len(want) {
+		t.Fatalf("objects = %v, want %v", byHour, want)
+	}
+	for partition, data := range want {
+		if byHour[partition] != data {
+			t.Errorf("object of %s = %q, want %q", partition, byHour[partition], data)
+		}
+	}
+}
+
+func TestArchiver_SplitsBatches(t *testing.T) {
+	store := &memoryStore{}
+	archiver := newTestArchiver(store, 2, 100)
+	ctx := context.Background()
+
+	for range 5 {
+		_ = archiver.Handle(ctx, message(`{"received_at": "2026-10-15T13:00:00Z"}`))
+	}
+	select {
+	case <-archiver.full:
+	default:
+		t.Error("full batch was not signaled")
+	}
+	if err := archiver.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if len(store.objects) != 3 {
+		t.Errorf("objects = %d, want 3", len(store.objects))
+	}
+}
+
+func TestArchiver_RetriesFailedWrites(t *testing.T) {
+	store := &memoryStore{err: errors.New("unavailable")}
+	archiver := newTestArchiver(store, 10, 3)
+	ctx := context.Background()
+
+	for range 4 {
+		_ = archiver.Handle(ctx, message(`{"received_at": "2026-10-15T13:00:00Z"}`))
+	}
+	if err := archiver.Flush(ctx); err == nil {
+		t.Fatal("Flush() error = nil, want the error of the store")
+	}
+
+	store.err = nil
+	if err := archiver.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if len(store.objects) != 1 {
+		t.Fatalf("objects = %d, want 1", len(store.objects))
+	}
+	for _, data := range store.objects {
+		// The event beyond max pending was dropped
+		if lines := strings.Count(data, "\n"); lines != 3 {
+			t.Errorf("archived events = %d, want 3", lines)
+		}
+	}
+}
+
+func TestArchiver_Erase(t *testing.T) {
+	store := &memoryStore{}
+	archiver := newTestArchiver(store, 10, 100)
+	ctx := context.Background()
+
+	for _, value := range []string{
+		`{"event_manager_id": "em-1", "received_at": "2026-10-15T12:00:00Z"}`,
+		`{"event_manager_id": "em-1", "received_at": "2026-10-15T13:00:00Z"}`,
+		`{"event_manager_id": "em-2", "received_at": "2026-10-15T13:00:00Z"}`,
+	} {
+		_ = archiver.Handle(ctx, message(value))
+	}
+	if err := archiver.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	// Still buffered
+	_ = archiver.Handle(ctx, message(`{"event_manager_id": "em-1"}`))
+
+	events, objects, err := archiver.Erase(ctx, "em-1")
+	if err != nil {
+		t.Fatalf("Erase() error = %v", err)
+	}
+	if events != 3 || objects != 2 {
+		t.Errorf("Erase() = %d events, %d objects, want 3 and 2", events, objects)
+	}
+	if archiver.count != 0 {
+		t.Errorf("pending = %d, want 0", archiver.count)
+	}
+
+	// The object of hour 12 held only em-1 and is gone
+	if len(store.objects) != 1 {
+		t.Fatalf("objects = %v, want 1", store.objects)
+	}
+	for key, data := range store.objects {
+		if !strings.Contains(key, "hour=13") || data != `{"event_manager_id":"em-2","received_at":"2026-10-15T13:00:00Z"}`+"\n" {
+			t.Errorf("object %s = %q", key, data)
+		}
+	}
+}
+
+func TestConsumer_ArchivesBeforeHandler(t *testing.T) {
+	store := &memoryStore{}
+	archiver := newTestArchiver(store, 10, 100)
+	next := &stubConsumer{messages: []*queue.Message{message(`{"dedupKey": "a"}`)}}
+
+	var handled int
+	err := NewConsumer(next, archiver).Start(context.Background(), func(context.Context, *queue.Message) error {
+		handled++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if handled != 1 || archiver.count != 1 {
+		t.Errorf("handled = %d, archived = %d, want 1 and 1", handled, archiver.count)
+	}
+}
+
+// stubConsumer delivers its messages once.
+type stubConsumer struct {
+	messages []*queue.Message
+}
+
+func (c *stubConsumer) Start(ctx context.Context, handler queue.MessageHandler) error {
+	for _, msg := range c.messages {
+		if err := handler(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *stubConsumer) Close() error { return nil }
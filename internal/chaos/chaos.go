@@ -0,0 +1,228 @@
+// Package chaos provides fault-injection wrappers around the store and queue
+// implementations. Faults (latency, error rate, dropped writes) are configured
+// per target and can be changed at runtime through the admin API.
+//
+// Fault injection is only available in binaries built with the "chaos" build
+// tag; see Enabled.
+package chaos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"argus-go/internal/config"
+)
+
+// ErrInjected is returned by wrapped operations that were made to fail.
+var ErrInjected = errors.New("chaos: injected fault")
+
+// ErrUnknownTarget is returned when faults are set for an unknown target.
+var ErrUnknownTarget = errors.New("chaos: unknown target")
+
+// ErrInvalidFaults is returned when a fault definition is out of range.
+var ErrInvalidFaults = errors.New("chaos: invalid faults")
+
+// Target identifies a group of wrapped components sharing one fault definition.
+type Target string
+
+const (
+	// TargetStateStore covers the state store.
+	TargetStateStore Target = "state_store"
+	// TargetRepositories covers the alert, event manager, grouping rule, routing rule
+	// and notification log repositories.
+	TargetRepositories Target = "repositories"
+	// TargetQueue covers the queue producer and consumer.
+	TargetQueue Target = "queue"
+)
+
+// Targets lists every known target.
+var Targets = []Target{TargetStateStore, TargetRepositories, TargetQueue}
+
+// Faults describes the faults injected into every operation of a target.
+type Faults struct {
+	// Latency is added before each operation.
+	Latency time.Duration `json:"latency"`
+
+	// ErrorRate is the fraction (0-1) of operations that fail with ErrInjected.
+	ErrorRate float64 `json:"error_rate"`
+
+	// DropRate is the fraction (0-1) of write operations that are silently
+	// skipped while reporting success.
+	DropRate float64 `json:"drop_rate"`
+}
+
+// faultsJSON is the wire form of Faults, with latency as a duration string (e.g. "250ms").
+type faultsJSON struct {
+	Latency   string  `json:"latency"`
+	ErrorRate float64 `json:"error_rate"`
+	DropRate  float64 `json:"drop_rate"`
+}
+
+// MarshalJSON encodes the latency as a duration string.
+func (f Faults) MarshalJSON() ([]byte, error) {
+	return json.Marshal(faultsJSON{
+		Latency:   f.Latency.String(),
+		ErrorRate: f.ErrorRate,
+		DropRate:  f.DropRate,
+	})
+}
+
+// UnmarshalJSON decodes a latency given as a duration string.
+func (f *Faults) UnmarshalJSON(data []byte) error {
+	var raw faultsJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var latency time.Duration
+	if raw.Latency != "" {
+		d, err := time.ParseDuration(raw.Latency)
+		if err != nil {
+			return fmt.Errorf("%w: latency: %v", ErrInvalidFaults, err)
+		}
+		latency = d
+	}
+
+	*f = Faults{Latency: latency, ErrorRate: raw.ErrorRate, DropRate: raw.DropRate}
+	return nil
+}
+
+// Validate checks that rates are within [0, 1] and latency is not negative.
+func (f Faults) Validate() error {
+	if f.Latency < 0 {
+		return fmt.Errorf("%w: latency must not be negative", ErrInvalidFaults)
+	}
+	if f.ErrorRate < 0 || f.ErrorRate > 1 {
+		return fmt.Errorf("%w: error_rate must be between 0 and 1", ErrInvalidFaults)
+	}
+	if f.DropRate < 0 || f.DropRate > 1 {
+		return fmt.Errorf("%w: drop_rate must be between 0 and 1", ErrInvalidFaults)
+	}
+	return nil
+}
+
+// faultsFromConfig converts a fault config section.
+func faultsFromConfig(cfg config.FaultConfig) Faults {
+	return Faults{
+		Latency:   cfg.Latency,
+		ErrorRate: cfg.ErrorRate,
+		DropRate:  cfg.DropRate,
+	}
+}
+
+// Injector holds the current faults per target and decides, per operation,
+// whether to delay, fail or drop it. It is safe for concurrent use.
+type Injector struct {
+	mu     sync.Mutex
+	faults map[Target]Faults
+	rng    *rand.Rand
+}
+
+// NewInjector creates an injector with the initial faults from config.
+func NewInjector(cfg *config.ChaosConfig) (*Injector, error) {
+	inj := &Injector{
+		faults: make(map[Target]Faults, len(Targets)),
+		rng:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	initial := map[Target]config.FaultConfig{
+		TargetStateStore:   cfg.StateStore,
+		TargetRepositories: cfg.Repositories,
+		TargetQueue:        cfg.Queue,
+	}
+	for target, fc := range initial {
+		if err := inj.Set(target, faultsFromConfig(fc)); err != nil {
+			return nil, err
+		}
+	}
+
+	return inj, nil
+}
+
+// Set replaces the faults of a target.
+func (i *Injector) Set(target Target, faults Faults) error {
+	if !isKnown(target) {
+		return fmt.Errorf("%w: %s", ErrUnknownTarget, target)
+	}
+	if err := faults.Validate(); err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.faults[target] = faults
+	return nil
+}
+
+// Get returns the faults of a target.
+func (i *Injector) Get(target Target) Faults {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.faults[target]
+}
+
+// All returns the faults of every target.
+func (i *Injector) All() map[Target]Faults {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	result := make(map[Target]Faults, len(i.faults))
+	for target, faults := range i.faults {
+		result[target] = faults
+	}
+	return result
+}
+
+// Reset clears all faults.
+func (i *Injector) Reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, target := range Targets {
+		i.faults[target] = Faults{}
+	}
+}
+
+// before applies the faults of a target ahead of an operation.
+// It returns drop=true when a write should be skipped, or an error when the
+// operation should fail.
+func (i *Injector) before(ctx context.Context, target Target, write bool) (drop bool, err error) {
+	i.mu.Lock()
+	faults := i.faults[target]
+	failRoll := i.rng.Float64()
+	dropRoll := i.rng.Float64()
+	i.mu.Unlock()
+
+	if faults.Latency > 0 {
+		timer := time.NewTimer(faults.Latency)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return false, ctx.Err()
+		}
+	}
+
+	if failRoll < faults.ErrorRate {
+		return false, fmt.Errorf("%w: %s", ErrInjected, target)
+	}
+
+	return write && dropRoll < faults.DropRate, nil
+}
+
+// isKnown returns true if target is a known target.
+func isKnown(target Target) bool {
+	for _, t := range Targets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package chaos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"argus-go/internal/config"
+	"argus-go/internal/store"
+	storemem "argus-go/internal/store/memory"
+)
+
+func newTestInjector(t *testing.T) *Injector {
+	t.Helper()
+	inj, err := NewInjector(&config.ChaosConfig{})
+	if err != nil {
+		t.Fatalf("NewInjector error: %v", err)
+	}
+	return inj
+}
+
+func TestInjector_Set_Validation(t *testing.T) {
+	inj := newTestInjector(t)
+
+	if err := inj.Set("bogus", Faults{}); !errors.Is(err, ErrUnknownTarget) {
+		t.Errorf("Set(bogus) error = %v, want ErrUnknownTarget", err)
+	}
+	if err := inj.Set(TargetQueue, Faults{ErrorRate: 1.5}); !errors.Is(err, ErrInvalidFaults) {
+		t.Errorf("Set(error_rate=1.5) error = %v, want ErrInvalidFaults", err)
+	}
+	if err := inj.Set(TargetQueue, Faults{Latency: -time.Second}); !errors.Is(err, ErrInvalidFaults) {
+		t.Errorf("Set(latency<0) error = %v, want ErrInvalidFaults", err)
+	}
+}
+
+func TestStateStore_ErrorRate(t *testing.T) {
+	inj := newTestInjector(t)
+	s := NewStateStore(storemem.NewStateStore(), inj)
+	ctx := context.Background()
+
+	_ = inj.Set(TargetStateStore, Faults{ErrorRate: 1})
+	if _, err := s.GetAlert(ctx, "a"); !errors.Is(err, ErrInjected) {
+		t.Errorf("GetAlert error = %v, want ErrInjected", err)
+	}
+
+	inj.Reset()
+	if _, err := s.GetAlert(ctx, "a"); err != nil {
+		t.Errorf("GetAlert after reset error = %v", err)
+	}
+}
+
+func TestStateStore_DropRate(t *testing.T) {
+	inj := newTestInjector(t)
+	next := storemem.NewStateStore()
+	s := NewStateStore(next, inj)
+	ctx := context.Background()
+
+	_ = inj.Set(TargetStateStore, Faults{DropRate: 1})
+	if err := s.SetAlert(ctx, &store.AlertState{DedupKey: "a"}); err != nil {
+		t.Fatalf("SetAlert error = %v, want dropped silently", err)
+	}
+
+	// Reads are never dropped
+	state, err := s.GetAlert(ctx, "a")
+	if err != nil {
+		t.Fatalf("GetAlert error: %v", err)
+	}
+	if state != nil {
+		t.Error("dropped write should not reach the wrapped store")
+	}
+}
+
+func TestFaults_JSON(t *testing.T) {
+	var f Faults
+	if err := json.Unmarshal([]byte(`{"latency":"250ms","error_rate":0.1}`), &f); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if f.Latency != 250*time.Millisecond || f.ErrorRate != 0.1 {
+		t.Errorf("Unmarshal = %+v", f)
+	}
+
+	data, _ := json.Marshal(f)
+	if string(data) != `{"latency":"250ms","error_rate":0.1,"drop_rate":0}` {
+		t.Errorf("Marshal = %s", data)
+	}
+}
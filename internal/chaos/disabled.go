@@ -0,0 +1,7 @@
+//go:build !chaos
+
+package chaos
+
+// Enabled reports whether this binary was built with fault injection support.
+// Build with -tags chaos to enable it.
+const Enabled = false
@@ -0,0 +1,6 @@
+//go:build chaos
+
+package chaos
+
+// Enabled reports whether this binary was built with fault injection support.
+const Enabled = true
@@ -0,0 +1,60 @@
+package chaos
+
+import (
+	"context"
+
+	"argus-go/internal/queue"
+)
+
+// Producer wraps a queue.Producer with the faults of TargetQueue.
+// Dropped messages are reported as published but never reach the queue.
+type Producer struct {
+	next queue.Producer
+	inj  *Injector
+}
+
+// NewProducer wraps next with fault injection.
+func NewProducer(next queue.Producer, inj *Injector) *Producer {
+	return &Producer{next: next, inj: inj}
+}
+
+// Publish implements queue.Producer.
+func (p *Producer) Publish(ctx context.Context, msg *queue.Message) error {
+	if drop, err := p.inj.before(ctx, TargetQueue, true); drop || err != nil {
+		return err
+	}
+	return p.next.Publish(ctx, msg)
+}
+
+// Close closes the wrapped producer.
+func (p *Producer) Close() error {
+	return p.next.Close()
+}
+
+// Consumer wraps a queue.Consumer with the faults of TargetQueue.
+// Faults are applied ahead of the handler for every consumed message, so a
+// failure looks like a handler error and a drop skips the message.
+type Consumer struct {
+	next queue.Consumer
+	inj  *Injector
+}
+
+// NewConsumer wraps next with fault injection.
+func NewConsumer(next queue.Consumer, inj *Injector) *Consumer {
+	return &Consumer{next: next, inj: inj}
+}
+
+// Start implements queue.Consumer.
+func (c *Consumer) Start(ctx context.Context, handler queue.MessageHandler) error {
+	return c.next.Start(ctx, func(ctx context.Context, msg *queue.Message) error {
+		if drop, err := c.inj.before(ctx, TargetQueue, true); drop || err != nil {
+			return err
+		}
+		return handler(ctx, msg)
+	})
+}
+
+// Close closes the wrapped consumer.
+func (c *Consumer) Close() error {
+	return c.next.Close()
+}
@@ -0,0 +1,882 @@
+package chaos
+
+import (
+	"context"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// repoFaults applies the faults of TargetRepositories.
+type repoFaults struct {
+	inj *Injector
+}
+
+// read applies faults ahead of a read operation.
+func (r repoFaults) read(ctx context.Context) error {
+	_, err := r.inj.before(ctx, TargetRepositories, false)
+	return err
+}
+
+// write applies faults ahead of a write operation.
+func (r repoFaults) write(ctx context.Context) (bool, error) {
+	return r.inj.before(ctx, TargetRepositories, true)
+}
+
+// AlertRepository wraps a store.AlertRepository with the faults of TargetRepositories.
+type AlertRepository struct {
+	repoFaults
+	next store.AlertRepository
+}
+
+// NewAlertRepository wraps next with fault injection.
+func NewAlertRepository(next store.AlertRepository, inj *Injector) *AlertRepository {
+	return &AlertRepository{repoFaults: repoFaults{inj: inj}, next: next}
+}
+
+// Create implements store.AlertRepository.
+func (r *AlertRepository) Create(ctx context.Context, alert *domain.Alert) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Create(ctx, alert)
+}
+
+// Update implements store.AlertRepository.
+func (r *AlertRepository) Update(ctx context.Context, alert *domain.Alert) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Update(ctx, alert)
+}
+
+// UpdateIfUnmodified implements store.AlertRepository.
+func (r *AlertRepository) UpdateIfUnmodified(ctx context.Context, alert *domain.Alert, updatedAt time.Time) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.UpdateIfUnmodified(ctx, alert, updatedAt)
+}
+
+// UpdateAll implements store.AlertRepository.
+func (r *AlertRepository) UpdateAll(ctx context.Context, alerts []*domain.Alert) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.UpdateAll(ctx, alerts)
+}
+
+// GetByID implements store.AlertRepository.
+func (r *AlertRepository) GetByID(ctx context.Context, id string) (*domain.Alert, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.GetByID(ctx, id)
+}
+
+// GetByDedupKey implements store.AlertRepository.
+func (r *AlertRepository) GetByDedupKey(ctx context.Context, dedupKey string) (*domain.Alert, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.GetByDedupKey(ctx, dedupKey)
+}
+
+// List implements store.AlertRepository.
+func (r *AlertRepository) List(ctx context.Context, filter domain.AlertFilter) ([]*domain.Alert, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx, filter)
+}
+
+// Count implements store.AlertRepository.
+func (r *AlertRepository) Count(ctx context.Context, filter domain.AlertFilter) (int, error) {
+	if err := r.read(ctx); err != nil {
+		return 0, err
+	}
+	return r.next.Count(ctx, filter)
+}
+
+// GetChildrenByParent implements store.AlertRepository.
+func (r *AlertRepository) GetChildrenByParent(ctx context.Context, parentDedupKey string) ([]*domain.Alert, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.GetChildrenByParent(ctx, parentDedupKey)
+}
+
+// CountActiveChildren implements store.AlertRepository.
+func (r *AlertRepository) CountActiveChildren(ctx context.Context, parentDedupKey string) (int, error) {
+	if err := r.read(ctx); err != nil {
+		return 0, err
+	}
+	return r.next.CountActiveChildren(ctx, parentDedupKey)
+}
+
+// CountActive implements store.AlertRepository.
+func (r *AlertRepository) CountActive(ctx context.Context) ([]*domain.ActiveAlertCount, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.CountActive(ctx)
+}
+
+// IncrementTriggerCount implements store.AlertRepository.
+func (r *AlertRepository) IncrementTriggerCount(ctx context.Context, dedupKey string) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.IncrementTriggerCount(ctx, dedupKey)
+}
+
+// AddSampledEvents implements store.AlertRepository.
+func (r *AlertRepository) AddSampledEvents(ctx context.Context, dedupKey string, count int) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.AddSampledEvents(ctx, dedupKey, count)
+}
+
+// MergeTags implements store.AlertRepository.
+func (r *AlertRepository) MergeTags(ctx context.Context, dedupKey string, tags map[string]string, replace bool) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.MergeTags(ctx, dedupKey, tags, replace)
+}
+
+// UpdateLinks implements store.AlertRepository.
+func (r *AlertRepository) UpdateLinks(ctx context.Context, dedupKey string, add []domain.AlertLink, remove []string) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.UpdateLinks(ctx, dedupKey, add, remove)
+}
+
+// History implements store.AlertRepository.
+func (r *AlertRepository) History(ctx context.Context, dedupKey string) ([]*domain.AlertRevision, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.History(ctx, dedupKey)
+}
+
+// RevisionAt implements store.AlertRepository.
+func (r *AlertRepository) RevisionAt(ctx context.Context, dedupKey string, at time.Time) (*domain.AlertRevision, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.RevisionAt(ctx, dedupKey, at)
+}
+
+// PurgeByEventManager implements store.AlertRepository.
+func (r *AlertRepository) PurgeByEventManager(ctx context.Context, eventManagerID string) (int, error) {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return 0, err
+	}
+	return r.next.PurgeByEventManager(ctx, eventManagerID)
+}
+
+// Delete implements store.AlertRepository.
+func (r *AlertRepository) Delete(ctx context.Context, dedupKey string) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Delete(ctx, dedupKey)
+}
+
+// EventManagerRepository wraps a store.EventManagerRepository with the faults of TargetRepositories.
+type EventManagerRepository struct {
+	repoFaults
+	next store.EventManagerRepository
+}
+
+// NewEventManagerRepository wraps next with fault injection.
+func NewEventManagerRepository(next store.EventManagerRepository, inj *Injector) *EventManagerRepository {
+	return &EventManagerRepository{repoFaults: repoFaults{inj: inj}, next: next}
+}
+
+// Create implements store.EventManagerRepository.
+func (r *EventManagerRepository) Create(ctx context.Context, em *domain.EventManager) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Create(ctx, em)
+}
+
+// Update implements store.EventManagerRepository.
+func (r *EventManagerRepository) Update(ctx context.Context, em *domain.EventManager) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Update(ctx, em)
+}
+
+// Delete implements store.EventManagerRepository.
+func (r *EventManagerRepository) Delete(ctx context.Context, id string) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Delete(ctx, id)
+}
+
+// Purge implements store.EventManagerRepository.
+func (r *EventManagerRepository) Purge(ctx context.Context, id string) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Purge(ctx, id)
+}
+
+// GetByID implements store.EventManagerRepository.
+func (r *EventManagerRepository) GetByID(ctx context.Context, id string) (*domain.EventManager, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.GetByID(ctx, id)
+}
+
+// GetByIngestToken implements store.EventManagerRepository.
+func (r *EventManagerRepository) GetByIngestToken(ctx context.Context, token string) (*domain.EventManager, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.GetByIngestToken(ctx, token)
+}
+
+// List implements store.EventManagerRepository.
+func (r *EventManagerRepository) List(ctx context.Context) ([]*domain.EventManager, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx)
+}
+
+// ListByGroupingRule implements store.EventManagerRepository.
+func (r *EventManagerRepository) ListByGroupingRule(ctx context.Context, groupingRuleID string) ([]*domain.EventManager, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.ListByGroupingRule(ctx, groupingRuleID)
+}
+
+// GroupingRuleRepository wraps a store.GroupingRuleRepository with the faults of TargetRepositories.
+type GroupingRuleRepository struct {
+	repoFaults
+	next store.GroupingRuleRepository
+}
+
+// NewGroupingRuleRepository wraps next with fault injection.
+func NewGroupingRuleRepository(next store.GroupingRuleRepository, inj *Injector) *GroupingRuleRepository {
+	return &GroupingRuleRepository{repoFaults: repoFaults{inj: inj}, next: next}
+}
+
+// Create implements store.GroupingRuleRepository.
+func (r *GroupingRuleRepository) Create(ctx context.Context, rule *domain.GroupingRule) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Create(ctx, rule)
+}
+
+// Update implements store.GroupingRuleRepository.
+func (r *GroupingRuleRepository) Update(ctx context.Context, rule *domain.GroupingRule) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Update(ctx, rule)
+}
+
+// Delete implements store.GroupingRuleRepository.
+func (r *GroupingRuleRepository) Delete(ctx context.Context, id string) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Delete(ctx, id)
+}
+
+// Purge implements store.GroupingRuleRepository.
+func (r *GroupingRuleRepository) Purge(ctx context.Context, id string) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Purge(ctx, id)
+}
+
+// GetByID implements store.GroupingRuleRepository.
+func (r *GroupingRuleRepository) GetByID(ctx context.Context, id string) (*domain.GroupingRule, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.GetByID(ctx, id)
+}
+
+// List implements store.GroupingRuleRepository.
+func (r *GroupingRuleRepository) List(ctx context.Context) ([]*domain.GroupingRule, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx)
+}
+
+// RoutingRuleRepository wraps a store.RoutingRuleRepository with the faults of TargetRepositories.
+type RoutingRuleRepository struct {
+	repoFaults
+	next store.RoutingRuleRepository
+}
+
+// NewRoutingRuleRepository wraps next with fault injection.
+func NewRoutingRuleRepository(next store.RoutingRuleRepository, inj *Injector) *RoutingRuleRepository {
+	return &RoutingRuleRepository{repoFaults: repoFaults{inj: inj}, next: next}
+}
+
+// Create implements store.RoutingRuleRepository.
+func (r *RoutingRuleRepository) Create(ctx context.Context, rule *domain.RoutingRule) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Create(ctx, rule)
+}
+
+// Update implements store.RoutingRuleRepository.
+func (r *RoutingRuleRepository) Update(ctx context.Context, rule *domain.RoutingRule) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Update(ctx, rule)
+}
+
+// Delete implements store.RoutingRuleRepository.
+func (r *RoutingRuleRepository) Delete(ctx context.Context, id string) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Delete(ctx, id)
+}
+
+// GetByID implements store.RoutingRuleRepository.
+func (r *RoutingRuleRepository) GetByID(ctx context.Context, id string) (*domain.RoutingRule, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.GetByID(ctx, id)
+}
+
+// List implements store.RoutingRuleRepository.
+func (r *RoutingRuleRepository) List(ctx context.Context) ([]*domain.RoutingRule, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx)
+}
+
+// WatchRepository wraps a store.WatchRepository with the faults of TargetRepositories.
+type WatchRepository struct {
+	repoFaults
+	next store.WatchRepository
+}
+
+// NewWatchRepository wraps next with fault injection.
+func NewWatchRepository(next store.WatchRepository, inj *Injector) *WatchRepository {
+	return &WatchRepository{repoFaults: repoFaults{inj: inj}, next: next}
+}
+
+// Create implements store.WatchRepository.
+func (r *WatchRepository) Create(ctx context.Context, watch *domain.Watch) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Create(ctx, watch)
+}
+
+// Delete implements store.WatchRepository.
+func (r *WatchRepository) Delete(ctx context.Context, id string) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Delete(ctx, id)
+}
+
+// GetByID implements store.WatchRepository.
+func (r *WatchRepository) GetByID(ctx context.Context, id string) (*domain.Watch, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.GetByID(ctx, id)
+}
+
+// List implements store.WatchRepository.
+func (r *WatchRepository) List(ctx context.Context) ([]*domain.Watch, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx)
+}
+
+// ListByUser implements store.WatchRepository.
+func (r *WatchRepository) ListByUser(ctx context.Context, user string) ([]*domain.Watch, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.ListByUser(ctx, user)
+}
+
+// SilenceRepository wraps a store.SilenceRepository with the faults of TargetRepositories.
+type SilenceRepository struct {
+	repoFaults
+	next store.SilenceRepository
+}
+
+// NewSilenceRepository wraps next with fault injection.
+func NewSilenceRepository(next store.SilenceRepository, inj *Injector) *SilenceRepository {
+	return &SilenceRepository{repoFaults: repoFaults{inj: inj}, next: next}
+}
+
+// Create implements store.SilenceRepository.
+func (r *SilenceRepository) Create(ctx context.Context, silence *domain.Silence) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Create(ctx, silence)
+}
+
+// Delete implements store.SilenceRepository.
+func (r *SilenceRepository) Delete(ctx context.Context, id string) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Delete(ctx, id)
+}
+
+// DeleteByRecurringID implements store.SilenceRepository.
+func (r *SilenceRepository) DeleteByRecurringID(ctx context.Context, recurringID string) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.DeleteByRecurringID(ctx, recurringID)
+}
+
+// GetByID implements store.SilenceRepository.
+func (r *SilenceRepository) GetByID(ctx context.Context, id string) (*domain.Silence, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.GetByID(ctx, id)
+}
+
+// List implements store.SilenceRepository.
+func (r *SilenceRepository) List(ctx context.Context) ([]*domain.Silence, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx)
+}
+
+// QueryRuleRepository wraps a store.QueryRuleRepository with the faults of TargetRepositories.
+type QueryRuleRepository struct {
+	repoFaults
+	next store.QueryRuleRepository
+}
+
+// NewQueryRuleRepository wraps next with fault injection.
+func NewQueryRuleRepository(next store.QueryRuleRepository, inj *Injector) *QueryRuleRepository {
+	return &QueryRuleRepository{repoFaults: repoFaults{inj: inj}, next: next}
+}
+
+// Create implements store.QueryRuleRepository.
+func (r *QueryRuleRepository) Create(ctx context.Context, rule *domain.QueryRule) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Create(ctx, rule)
+}
+
+// Update implements store.QueryRuleRepository.
+func (r *QueryRuleRepository) Update(ctx context.Context, rule *domain.QueryRule) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Update(ctx, rule)
+}
+
+// Delete implements store.QueryRuleRepository.
+func (r *QueryRuleRepository) Delete(ctx context.Context, id string) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Delete(ctx, id)
+}
+
+// GetByID implements store.QueryRuleRepository.
+func (r *QueryRuleRepository) GetByID(ctx context.Context, id string) (*domain.QueryRule, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.GetByID(ctx, id)
+}
+
+// List implements store.QueryRuleRepository.
+func (r *QueryRuleRepository) List(ctx context.Context) ([]*domain.QueryRule, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx)
+}
+
+// RuleTemplateRepository wraps a store.RuleTemplateRepository with the faults of TargetRepositories.
+type RuleTemplateRepository struct {
+	repoFaults
+	next store.RuleTemplateRepository
+}
+
+// NewRuleTemplateRepository wraps next with fault injection.
+func NewRuleTemplateRepository(next store.RuleTemplateRepository, inj *Injector) *RuleTemplateRepository {
+	return &RuleTemplateRepository{repoFaults: repoFaults{inj: inj}, next: next}
+}
+
+// Create implements store.RuleTemplateRepository.
+func (r *RuleTemplateRepository) Create(ctx context.Context, template *domain.RuleTemplate) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Create(ctx, template)
+}
+
+// Update implements store.RuleTemplateRepository.
+func (r *RuleTemplateRepository) Update(ctx context.Context, template *domain.RuleTemplate) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Update(ctx, template)
+}
+
+// Delete implements store.RuleTemplateRepository.
+func (r *RuleTemplateRepository) Delete(ctx context.Context, id string) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Delete(ctx, id)
+}
+
+// GetByID implements store.RuleTemplateRepository.
+func (r *RuleTemplateRepository) GetByID(ctx context.Context, id string) (*domain.RuleTemplate, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.GetByID(ctx, id)
+}
+
+// List implements store.RuleTemplateRepository.
+func (r *RuleTemplateRepository) List(ctx context.Context) ([]*domain.RuleTemplate, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx)
+}
+
+// NotificationLogRepository wraps a store.NotificationLogRepository with the faults of TargetRepositories.
+type NotificationLogRepository struct {
+	repoFaults
+	next store.NotificationLogRepository
+}
+
+// NewNotificationLogRepository wraps next with fault injection.
+func NewNotificationLogRepository(next store.NotificationLogRepository, inj *Injector) *NotificationLogRepository {
+	return &NotificationLogRepository{repoFaults: repoFaults{inj: inj}, next: next}
+}
+
+// Record implements store.NotificationLogRepository.
+func (r *NotificationLogRepository) Record(ctx context.Context, record *domain.NotificationRecord) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Record(ctx, record)
+}
+
+// ListByDedupKey implements store.NotificationLogRepository.
+func (r *NotificationLogRepository) ListByDedupKey(ctx context.Context, dedupKey string) ([]*domain.NotificationRecord, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.ListByDedupKey(ctx, dedupKey)
+}
+
+// DeleteByEventManager implements store.NotificationLogRepository.
+func (r *NotificationLogRepository) DeleteByEventManager(ctx context.Context, eventManagerID string) (int, error) {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return 0, err
+	}
+	return r.next.DeleteByEventManager(ctx, eventManagerID)
+}
+
+// RemediationLogRepository wraps a store.RemediationLogRepository with the faults of TargetRepositories.
+type RemediationLogRepository struct {
+	repoFaults
+	next store.RemediationLogRepository
+}
+
+// NewRemediationLogRepository wraps next with fault injection.
+func NewRemediationLogRepository(next store.RemediationLogRepository, inj *Injector) *RemediationLogRepository {
+	return &RemediationLogRepository{repoFaults: repoFaults{inj: inj}, next: next}
+}
+
+// Record implements store.RemediationLogRepository.
+func (r *RemediationLogRepository) Record(ctx context.Context, record *domain.RemediationRecord) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Record(ctx, record)
+}
+
+// ListByDedupKey implements store.RemediationLogRepository.
+func (r *RemediationLogRepository) ListByDedupKey(ctx context.Context, dedupKey string) ([]*domain.RemediationRecord, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.ListByDedupKey(ctx, dedupKey)
+}
+
+// DeleteByEventManager implements store.RemediationLogRepository.
+func (r *RemediationLogRepository) DeleteByEventManager(ctx context.Context, eventManagerID string) (int, error) {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return 0, err
+	}
+	return r.next.DeleteByEventManager(ctx, eventManagerID)
+}
+
+// AlertFeedbackRepository wraps a store.AlertFeedbackRepository with the faults of TargetRepositories.
+type AlertFeedbackRepository struct {
+	repoFaults
+	next store.AlertFeedbackRepository
+}
+
+// NewAlertFeedbackRepository wraps next with fault injection.
+func NewAlertFeedbackRepository(next store.AlertFeedbackRepository, inj *Injector) *AlertFeedbackRepository {
+	return &AlertFeedbackRepository{repoFaults: repoFaults{inj: inj}, next: next}
+}
+
+// Record implements store.AlertFeedbackRepository.
+func (r *AlertFeedbackRepository) Record(ctx context.Context, feedback *domain.AlertFeedback) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Record(ctx, feedback)
+}
+
+// ListByDedupKey implements store.AlertFeedbackRepository.
+func (r *AlertFeedbackRepository) ListByDedupKey(ctx context.Context, dedupKey string) ([]*domain.AlertFeedback, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.ListByDedupKey(ctx, dedupKey)
+}
+
+// List implements store.AlertFeedbackRepository.
+func (r *AlertFeedbackRepository) List(ctx context.Context, filter domain.ReportFilter) ([]*domain.AlertFeedback, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx, filter)
+}
+
+// DeleteByEventManager implements store.AlertFeedbackRepository.
+func (r *AlertFeedbackRepository) DeleteByEventManager(ctx context.Context, eventManagerID string) (int, error) {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return 0, err
+	}
+	return r.next.DeleteByEventManager(ctx, eventManagerID)
+}
+
+// AuditLogRepository wraps a store.AuditLogRepository with the faults of TargetRepositories.
+type AuditLogRepository struct {
+	repoFaults
+	next store.AuditLogRepository
+}
+
+// NewAuditLogRepository wraps next with fault injection.
+func NewAuditLogRepository(next store.AuditLogRepository, inj *Injector) *AuditLogRepository {
+	return &AuditLogRepository{repoFaults: repoFaults{inj: inj}, next: next}
+}
+
+// Record implements store.AuditLogRepository.
+func (r *AuditLogRepository) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Record(ctx, entry)
+}
+
+// List implements store.AuditLogRepository.
+func (r *AuditLogRepository) List(ctx context.Context, limit int) ([]*domain.AuditEntry, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx, limit)
+}
+
+// Export implements store.AuditLogRepository.
+func (r *AuditLogRepository) Export(ctx context.Context) ([]*domain.AuditEntry, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.Export(ctx)
+}
+
+// UsageRepository wraps a store.UsageRepository with the faults of TargetRepositories.
+type UsageRepository struct {
+	repoFaults
+	next store.UsageRepository
+}
+
+// NewUsageRepository wraps next with fault injection.
+func NewUsageRepository(next store.UsageRepository, inj *Injector) *UsageRepository {
+	return &UsageRepository{repoFaults: repoFaults{inj: inj}, next: next}
+}
+
+// Add implements store.UsageRepository.
+func (r *UsageRepository) Add(ctx context.Context, usage *domain.Usage) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Add(ctx, usage)
+}
+
+// List implements store.UsageRepository.
+func (r *UsageRepository) List(ctx context.Context, filter domain.ReportFilter) ([]*domain.Usage, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx, filter)
+}
+
+// DeleteByEventManager implements store.UsageRepository.
+func (r *UsageRepository) DeleteByEventManager(ctx context.Context, eventManagerID string) (int, error) {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return 0, err
+	}
+	return r.next.DeleteByEventManager(ctx, eventManagerID)
+}
+
+// RuleExecutionRepository wraps a store.RuleExecutionRepository with the faults of TargetRepositories.
+type RuleExecutionRepository struct {
+	repoFaults
+	next store.RuleExecutionRepository
+}
+
+// NewRuleExecutionRepository wraps next with fault injection.
+func NewRuleExecutionRepository(next store.RuleExecutionRepository, inj *Injector) *RuleExecutionRepository {
+	return &RuleExecutionRepository{repoFaults: repoFaults{inj: inj}, next: next}
+}
+
+// Record implements store.RuleExecutionRepository.
+func (r *RuleExecutionRepository) Record(ctx context.Context, execution *domain.RuleExecution, keep int) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Record(ctx, execution, keep)
+}
+
+// ListByRule implements store.RuleExecutionRepository.
+func (r *RuleExecutionRepository) ListByRule(ctx context.Context, ruleID string, limit int) ([]*domain.RuleExecution, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.ListByRule(ctx, ruleID, limit)
+}
+
+// DeleteByRule implements store.RuleExecutionRepository.
+func (r *RuleExecutionRepository) DeleteByRule(ctx context.Context, ruleID string) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.DeleteByRule(ctx, ruleID)
+}
+
+// RunnerRegistry wraps a store.RunnerRegistry with the faults of TargetRepositories.
+type RunnerRegistry struct {
+	repoFaults
+	next store.RunnerRegistry
+}
+
+// NewRunnerRegistry wraps next with fault injection.
+func NewRunnerRegistry(next store.RunnerRegistry, inj *Injector) *RunnerRegistry {
+	return &RunnerRegistry{repoFaults: repoFaults{inj: inj}, next: next}
+}
+
+// Heartbeat implements store.RunnerRegistry.
+func (r *RunnerRegistry) Heartbeat(ctx context.Context, instanceID string, now time.Time, timeout time.Duration) ([]string, error) {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return nil, err
+	}
+	return r.next.Heartbeat(ctx, instanceID, now, timeout)
+}
+
+// Leave implements store.RunnerRegistry.
+func (r *RunnerRegistry) Leave(ctx context.Context, instanceID string) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Leave(ctx, instanceID)
+}
+
+// ReportScheduleRepository wraps a store.ReportScheduleRepository with the faults of TargetRepositories.
+type ReportScheduleRepository struct {
+	repoFaults
+	next store.ReportScheduleRepository
+}
+
+// NewReportScheduleRepository wraps next with fault injection.
+func NewReportScheduleRepository(next store.ReportScheduleRepository, inj *Injector) *ReportScheduleRepository {
+	return &ReportScheduleRepository{repoFaults: repoFaults{inj: inj}, next: next}
+}
+
+// Create implements store.ReportScheduleRepository.
+func (r *ReportScheduleRepository) Create(ctx context.Context, schedule *domain.ReportSchedule) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Create(ctx, schedule)
+}
+
+// Update implements store.ReportScheduleRepository.
+func (r *ReportScheduleRepository) Update(ctx context.Context, schedule *domain.ReportSchedule) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Update(ctx, schedule)
+}
+
+// Delete implements store.ReportScheduleRepository.
+func (r *ReportScheduleRepository) Delete(ctx context.Context, id string) error {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err
+	}
+	return r.next.Delete(ctx, id)
+}
+
+// GetByID implements store.ReportScheduleRepository.
+func (r *ReportScheduleRepository) GetByID(ctx context.Context, id string) (*domain.ReportSchedule, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.GetByID(ctx, id)
+}
+
+// List implements store.ReportScheduleRepository.
+func (r *ReportScheduleRepository) List(ctx context.Context) ([]*domain.ReportSchedule, error) {
+	if err := r.read(ctx); err != nil {
+		return nil, err
+	}
+	return r.next.List(ctx)
+}
+
+// MarkSent implements store.ReportScheduleRepository. A dropped write
+// reports the schedule marked, as if the write was lost.
+func (r *ReportScheduleRepository) MarkSent(ctx context.Context, id string, previous, to *time.Time) (bool, error) {
+	if drop, err := r.write(ctx); drop || err != nil {
+		return err == nil, err
+	}
+	return r.next.MarkSent(ctx, id, previous, to)
+}
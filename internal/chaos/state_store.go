@@ -0,0 +1,237 @@
+package chaos
+
+import (
+	"context"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// StateStore wraps a store.StateStore with the faults of TargetStateStore.
+type StateStore struct {
+	next store.StateStore
+	inj  *Injector
+}
+
+// NewStateStore wraps next with fault injection.
+func NewStateStore(next store.StateStore, inj *Injector) *StateStore {
+	return &StateStore{next: next, inj: inj}
+}
+
+// read applies faults ahead of a read operation.
+func (s *StateStore) read(ctx context.Context) error {
+	_, err := s.inj.before(ctx, TargetStateStore, false)
+	return err
+}
+
+// write applies faults ahead of a write operation.
+func (s *StateStore) write(ctx context.Context) (bool, error) {
+	return s.inj.before(ctx, TargetStateStore, true)
+}
+
+// GetParent implements store.StateStore.
+func (s *StateStore) GetParent(ctx context.Context, eventManagerID, groupingKey, groupingValue string) (*store.ParentState, error) {
+	if err := s.read(ctx); err != nil {
+		return nil, err
+	}
+	return s.next.GetParent(ctx, eventManagerID, groupingKey, groupingValue)
+}
+
+// SetParent implements store.StateStore.
+func (s *StateStore) SetParent(ctx context.Context, eventManagerID, groupingKey, groupingValue string, state *store.ParentState, ttl time.Duration) error {
+	if drop, err := s.write(ctx); drop || err != nil {
+		return err
+	}
+	return s.next.SetParent(ctx, eventManagerID, groupingKey, groupingValue, state, ttl)
+}
+
+// DeleteParent implements store.StateStore.
+func (s *StateStore) DeleteParent(ctx context.Context, eventManagerID, groupingKey, groupingValue string) error {
+	if drop, err := s.write(ctx); drop || err != nil {
+		return err
+	}
+	return s.next.DeleteParent(ctx, eventManagerID, groupingKey, groupingValue)
+}
+
+// GetAlert implements store.StateStore.
+func (s *StateStore) GetAlert(ctx context.Context, dedupKey string) (*store.AlertState, error) {
+	if err := s.read(ctx); err != nil {
+		return nil, err
+	}
+	return s.next.GetAlert(ctx, dedupKey)
+}
+
+// SetAlert implements store.StateStore.
+func (s *StateStore) SetAlert(ctx context.Context, state *store.AlertState) error {
+	if drop, err := s.write(ctx); drop || err != nil {
+		return err
+	}
+	return s.next.SetAlert(ctx, state)
+}
+
+// DeleteAlert implements store.StateStore.
+func (s *StateStore) DeleteAlert(ctx context.Context, dedupKey string) error {
+	if drop, err := s.write(ctx); drop || err != nil {
+		return err
+	}
+	return s.next.DeleteAlert(ctx, dedupKey)
+}
+
+// AddChild implements store.StateStore.
+func (s *StateStore) AddChild(ctx context.Context, parentDedupKey, childDedupKey string) error {
+	if drop, err := s.write(ctx); drop || err != nil {
+		return err
+	}
+	return s.next.AddChild(ctx, parentDedupKey, childDedupKey)
+}
+
+// RemoveChild implements store.StateStore.
+func (s *StateStore) RemoveChild(ctx context.Context, parentDedupKey, childDedupKey string) error {
+	if drop, err := s.write(ctx); drop || err != nil {
+		return err
+	}
+	return s.next.RemoveChild(ctx, parentDedupKey, childDedupKey)
+}
+
+// GetChildren implements store.StateStore.
+func (s *StateStore) GetChildren(ctx context.Context, parentDedupKey string) ([]string, error) {
+	if err := s.read(ctx); err != nil {
+		return nil, err
+	}
+	return s.next.GetChildren(ctx, parentDedupKey)
+}
+
+// GetChildCount implements store.StateStore.
+func (s *StateStore) GetChildCount(ctx context.Context, parentDedupKey string) (int, error) {
+	if err := s.read(ctx); err != nil {
+		return 0, err
+	}
+	return s.next.GetChildCount(ctx, parentDedupKey)
+}
+
+// GetActiveChildCount implements store.StateStore.
+func (s *StateStore) GetActiveChildCount(ctx context.Context, parentDedupKey string) (int, error) {
+	if err := s.read(ctx); err != nil {
+		return 0, err
+	}
+	return s.next.GetActiveChildCount(ctx, parentDedupKey)
+}
+
+// SetPendingResolve implements store.StateStore.
+func (s *StateStore) SetPendingResolve(ctx context.Context, parentDedupKey string, pending *store.PendingResolve) error {
+	if drop, err := s.write(ctx); drop || err != nil {
+		return err
+	}
+	return s.next.SetPendingResolve(ctx, parentDedupKey, pending)
+}
+
+// GetPendingResolve implements store.StateStore.
+func (s *StateStore) GetPendingResolve(ctx context.Context, parentDedupKey string) (*store.PendingResolve, error) {
+	if err := s.read(ctx); err != nil {
+		return nil, err
+	}
+	return s.next.GetPendingResolve(ctx, parentDedupKey)
+}
+
+// DeletePendingResolve implements store.StateStore.
+func (s *StateStore) DeletePendingResolve(ctx context.Context, parentDedupKey string) error {
+	if drop, err := s.write(ctx); drop || err != nil {
+		return err
+	}
+	return s.next.DeletePendingResolve(ctx, parentDedupKey)
+}
+
+// SetReminder implements store.StateStore.
+func (s *StateStore) SetReminder(ctx context.Context, reminder *store.Reminder) error {
+	if drop, err := s.write(ctx); drop || err != nil {
+		return err
+	}
+	return s.next.SetReminder(ctx, reminder)
+}
+
+// GetDueReminders implements store.StateStore.
+func (s *StateStore) GetDueReminders(ctx context.Context, now time.Time, limit int) ([]*store.Reminder, error) {
+	if err := s.read(ctx); err != nil {
+		return nil, err
+	}
+	return s.next.GetDueReminders(ctx, now, limit)
+}
+
+// DeleteReminder implements store.StateStore.
+func (s *StateStore) DeleteReminder(ctx context.Context, dedupKey string) error {
+	if drop, err := s.write(ctx); drop || err != nil {
+		return err
+	}
+	return s.next.DeleteReminder(ctx, dedupKey)
+}
+
+// MarkNotificationSent implements store.StateStore. A dropped write reports
+// the notification as not yet sent, so it is sent.
+func (s *StateStore) MarkNotificationSent(ctx context.Context, notificationID string, ttl time.Duration) (bool, error) {
+	if drop, err := s.write(ctx); drop || err != nil {
+		return drop, err
+	}
+	return s.next.MarkNotificationSent(ctx, notificationID, ttl)
+}
+
+// AdjustLiveCount implements store.StateStore.
+func (s *StateStore) AdjustLiveCount(ctx context.Context, eventManagerID, severity string, delta int) error {
+	if drop, err := s.write(ctx); drop || err != nil {
+		return err
+	}
+	return s.next.AdjustLiveCount(ctx, eventManagerID, severity, delta)
+}
+
+// GetLiveCounts implements store.StateStore.
+func (s *StateStore) GetLiveCounts(ctx context.Context) ([]*store.LiveCount, error) {
+	if err := s.read(ctx); err != nil {
+		return nil, err
+	}
+	return s.next.GetLiveCounts(ctx)
+}
+
+// SetLiveCounts implements store.StateStore.
+func (s *StateStore) SetLiveCounts(ctx context.Context, counts []*store.LiveCount) error {
+	if drop, err := s.write(ctx); drop || err != nil {
+		return err
+	}
+	return s.next.SetLiveCounts(ctx, counts)
+}
+
+// RecordGrouping implements store.StateStore.
+func (s *StateStore) RecordGrouping(ctx context.Context, groupingRuleID, day string, child bool) error {
+	if drop, err := s.write(ctx); drop || err != nil {
+		return err
+	}
+	return s.next.RecordGrouping(ctx, groupingRuleID, day, child)
+}
+
+// GetGroupingStats implements store.StateStore.
+func (s *StateStore) GetGroupingStats(ctx context.Context, groupingRuleID string, days []string) ([]*store.GroupingStats, error) {
+	if err := s.read(ctx); err != nil {
+		return nil, err
+	}
+	return s.next.GetGroupingStats(ctx, groupingRuleID, days)
+}
+
+// SetEventStatus implements store.StateStore.
+func (s *StateStore) SetEventStatus(ctx context.Context, status *domain.EventStatus, ttl time.Duration) error {
+	if drop, err := s.write(ctx); drop || err != nil {
+		return err
+	}
+	return s.next.SetEventStatus(ctx, status, ttl)
+}
+
+// GetEventStatus implements store.StateStore.
+func (s *StateStore) GetEventStatus(ctx context.Context, eventID string) (*domain.EventStatus, error) {
+	if err := s.read(ctx); err != nil {
+		return nil, err
+	}
+	return s.next.GetEventStatus(ctx, eventID)
+}
+
+// Close closes the wrapped store. Faults are never injected on Close.
+func (s *StateStore) Close() error {
+	return s.next.Close()
+}
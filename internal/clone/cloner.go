@@ -0,0 +1,211 @@
+// Package clone copies an event manager together with the configuration
+// around it: the grouping rules it references, the silences muting its
+// alerts and the routing rules sending events to it. Teams bootstrap new
+// event managers from a golden configuration this way in one call.
+package clone
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
+	"argus-go/internal/silence"
+	"argus-go/internal/store"
+)
+
+// Cloner copies event managers.
+type Cloner struct {
+	eventManagers store.EventManagerRepository
+	groupingRules store.GroupingRuleRepository
+	routingRules  store.RoutingRuleRepository
+	silences      store.SilenceRepository
+	scheduler     *silence.Scheduler
+	clock         clock.Clock
+	logger        *slog.Logger
+}
+
+// NewCloner creates a cloner of the event managers in the given stores. The
+// scheduler, nil if silences are disabled, is refreshed once silences are
+// copied.
+func NewCloner(
+	eventManagers store.EventManagerRepository,
+	groupingRules store.GroupingRuleRepository,
+	routingRules store.RoutingRuleRepository,
+	silences store.SilenceRepository,
+	scheduler *silence.Scheduler,
+	clk clock.Clock,
+	logger *slog.Logger,
+) *Cloner {
+	return &Cloner{
+		eventManagers: eventManagers,
+		groupingRules: groupingRules,
+		routingRules:  routingRules,
+		silences:      silences,
+		scheduler:     scheduler,
+		clock:         clk,
+		logger:        logger,
+	}
+}
+
+// Clone copies an event manager under a new name, with a new ingest token.
+// Every grouping rule it references is copied for the new event manager, so
+// the copies can be tuned without affecting the source. The silences of the
+// source that are not expired and the routing rules targeting it are copied
+// to target the new event manager; materialized occurrences of recurring
+// silences are not, since the scheduler materializes those of the copies.
+//
+// It returns domain.ErrEventManagerNotFound or domain.ErrEventManagerDeleted
+// for a missing source, and domain.ErrEventManagerAlreadyExists if the ID of
+// the copy is taken. On other errors, the copies made so far are removed.
+func (c *Cloner) Clone(ctx context.Context, sourceID string, req *domain.CloneEventManagerRequest) (*domain.EventManagerClone, error) {
+	source, err := c.eventManagers.GetByID(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if source.IsDeleted() {
+		return nil, domain.ErrEventManagerDeleted
+	}
+
+	id := req.ID
+	if id == "" {
+		id = uuid.New().String()
+	} else if _, err := c.eventManagers.GetByID(ctx, id); err == nil {
+		return nil, domain.ErrEventManagerAlreadyExists
+	}
+
+	now := c.clock.Now().UTC()
+	em, err := source.Copy(id, req.Name, now)
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.EventManagerClone{SourceID: sourceID, EventManager: em}
+
+	if err := c.clone(ctx, source, result, now); err != nil {
+		c.undo(ctx, result)
+		return nil, err
+	}
+
+	if len(result.Silences) > 0 {
+		if err := c.scheduler.Refresh(ctx); err != nil {
+			c.logger.WarnContext(ctx, "failed to refresh silences", "error", err)
+		}
+	}
+
+	c.logger.InfoContext(ctx, "cloned event manager", "source_id", sourceID, "id", em.ID, "name", em.Name,
+		"grouping_rules", len(result.GroupingRules), "routing_rules", len(result.RoutingRules), "silences", len(result.Silences))
+	return result, nil
+}
+
+// clone persists the copies into result: first the grouping rules the new
+// event manager references, then the event manager, then its routing rules
+// and silences.
+func (c *Cloner) clone(ctx context.Context, source *domain.EventManager, result *domain.EventManagerClone, now time.Time) error {
+	em := result.EventManager
+
+	ids := make(map[string]string)
+	for _, ruleID := range source.GroupingRuleIDs() {
+		rule, err := c.groupingRules.GetByID(ctx, ruleID)
+		if err != nil {
+			return fmt.Errorf("failed to get grouping rule %s: %w", ruleID, err)
+		}
+		ruleCopy := *rule
+		ruleCopy.ID = uuid.New().String()
+		ruleCopy.Name = fmt.Sprintf("%s (%s)", rule.Name, em.Name)
+		ruleCopy.Runbook.Steps = slices.Clone(rule.Runbook.Steps)
+		ruleCopy.CreatedAt = now
+		ruleCopy.UpdatedAt = now
+		ruleCopy.DeletedAt = nil
+		if err := c.groupingRules.Create(ctx, &ruleCopy); err != nil {
+			return fmt.Errorf("failed to copy grouping rule %s: %w", ruleID, err)
+		}
+		result.GroupingRules = append(result.GroupingRules, &ruleCopy)
+		ids[ruleID] = ruleCopy.ID
+	}
+	em.RemapGroupingRules(ids)
+
+	if err := c.eventManagers.Create(ctx, em); err != nil {
+		result.EventManager = nil
+		return fmt.Errorf("failed to create event manager: %w", err)
+	}
+
+	routingRules, err := c.routingRules.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list routing rules: %w", err)
+	}
+	for _, rule := range routingRules {
+		if rule.EventManagerID != source.ID {
+			continue
+		}
+		ruleCopy := *rule
+		ruleCopy.ID = uuid.New().String()
+		ruleCopy.Name = fmt.Sprintf("%s (%s)", rule.Name, em.Name)
+		ruleCopy.Match.Classes = slices.Clone(rule.Match.Classes)
+		ruleCopy.Match.Sources = slices.Clone(rule.Match.Sources)
+		ruleCopy.Match.Labels = maps.Clone(rule.Match.Labels)
+		ruleCopy.EventManagerID = em.ID
+		ruleCopy.CreatedAt = now
+		ruleCopy.UpdatedAt = now
+		if err := c.routingRules.Create(ctx, &ruleCopy); err != nil {
+			return fmt.Errorf("failed to copy routing rule %s: %w", rule.ID, err)
+		}
+		result.RoutingRules = append(result.RoutingRules, &ruleCopy)
+	}
+
+	silences, err := c.silences.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list silences: %w", err)
+	}
+	for _, s := range silences {
+		if s.Match.EventManagerID != source.ID || s.RecurringID != "" || s.State(now) == domain.SilenceExpired {
+			continue
+		}
+		silenceCopy := *s
+		silenceCopy.ID = uuid.New().String()
+		silenceCopy.Match.EventManagerID = em.ID
+		silenceCopy.Match.Classes = slices.Clone(s.Match.Classes)
+		silenceCopy.Match.Severities = slices.Clone(s.Match.Severities)
+		if s.Schedule != nil {
+			schedule := *s.Schedule
+			silenceCopy.Schedule = &schedule
+		}
+		silenceCopy.CreatedAt = now
+		if err := c.silences.Create(ctx, &silenceCopy); err != nil {
+			return fmt.Errorf("failed to copy silence %s: %w", s.ID, err)
+		}
+		result.Silences = append(result.Silences, &silenceCopy)
+	}
+	return nil
+}
+
+// undo removes the copies of a failed clone, in the reverse order of their
+// creation. Failures are logged: the copies left are ordinary resources
+// that can be deleted by hand.
+func (c *Cloner) undo(ctx context.Context, result *domain.EventManagerClone) {
+	for _, s := range result.Silences {
+		if err := c.silences.Delete(ctx, s.ID); err != nil {
+			c.logger.WarnContext(ctx, "failed to remove copied silence", "id", s.ID, "error", err)
+		}
+	}
+	for _, rule := range result.RoutingRules {
+		if err := c.routingRules.Delete(ctx, rule.ID); err != nil {
+			c.logger.WarnContext(ctx, "failed to remove copied routing rule", "id", rule.ID, "error", err)
+		}
+	}
+	if result.EventManager != nil {
+		if err := c.eventManagers.Purge(ctx, result.EventManager.ID); err != nil {
+			c.logger.WarnContext(ctx, "failed to remove copied event manager", "id", result.EventManager.ID, "error", err)
+		}
+	}
+	for _, rule := range result.GroupingRules {
+		if err := c.groupingRules.Purge(ctx, rule.ID); err != nil {
+			c.logger.WarnContext(ctx, "failed to remove copied grouping rule", "id", rule.ID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package clone
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
+	storemem "argus-go/internal/store/memory"
+)
+
+func TestCloner_Clone(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	eventManagers := storemem.NewEventManagerRepository()
+	groupingRules := storemem.NewGroupingRuleRepository()
+	routingRules := storemem.NewRoutingRuleRepository()
+	silences := storemem.NewSilenceRepository()
+
+	_ = groupingRules.Create(ctx, &domain.GroupingRule{ID: "rule-1", Name: "by host", GroupingKey: "host", TimeWindowMinutes: 5, CreatedAt: now})
+	_ = groupingRules.Create(ctx, &domain.GroupingRule{ID: "rule-2", Name: "by service", GroupingKey: "service", TimeWindowMinutes: 5, CreatedAt: now})
+	source := &domain.EventManager{
+		ID:             "golden",
+		Name:           "Golden",
+		GroupingRuleID: "rule-1",
+		GroupingRules:  []domain.GroupingRuleBinding{{GroupingRuleID: "rule-2", Match: domain.EventMatcher{Classes: []string{"db"}}}},
+		NotificationConfig: domain.NotificationConfig{
+			WebhookURL: "https://hooks.example.com/golden",
+		},
+		IngestToken: domain.NewIngestToken(),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	_ = eventManagers.Create(ctx, source)
+
+	_ = routingRules.Create(ctx, &domain.RoutingRule{ID: "route-1", Name: "db", Match: domain.RouteMatcher{Classes: []string{"db"}}, EventManagerID: "golden"})
+	_ = routingRules.Create(ctx, &domain.RoutingRule{ID: "route-2", Name: "web", Match: domain.RouteMatcher{Classes: []string{"web"}}, EventManagerID: "other"})
+
+	match := domain.SilenceMatcher{EventManagerID: "golden"}
+	_ = silences.Create(ctx, &domain.Silence{ID: "active", Match: match, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)})
+	_ = silences.Create(ctx, &domain.Silence{ID: "expired", Match: match, StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)})
+	_ = silences.Create(ctx, &domain.Silence{ID: "nightly", Match: match, Schedule: &domain.SilenceSchedule{Cron: "@daily", Duration: domain.Duration(time.Hour)}})
+	_ = silences.Create(ctx, &domain.Silence{ID: "nightly-occurrence", Match: match, StartsAt: now, EndsAt: now.Add(time.Hour), RecurringID: "nightly"})
+	_ = silences.Create(ctx, &domain.Silence{ID: "other", Match: domain.SilenceMatcher{EventManagerID: "other"}, StartsAt: now, EndsAt: now.Add(time.Hour)})
+
+	cloner := NewCloner(eventManagers, groupingRules, routingRules, silences, nil, clock.NewFake(now), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	result, err := cloner.Clone(ctx, "golden", &domain.CloneEventManagerRequest{ID: "payments", Name: "Payments"})
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+
+	em, err := eventManagers.GetByID(ctx, "payments")
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if em.Name != "Payments" || em.NotificationConfig.WebhookURL != source.NotificationConfig.WebhookURL {
+		t.Errorf("copy = %+v, want the configuration of the source under the new name", em)
+	}
+	if em.IngestToken == "" || em.IngestToken == source.IngestToken {
+		t.Error("copy shares the ingest token of the source, want a new one")
+	}
+
+	// The grouping rules are copied, and the copy references the copies
+	if len(result.GroupingRules) != 2 {
+		t.Fatalf("copied %d grouping rules, want 2", len(result.GroupingRules))
+	}
+	if em.GroupingRuleID != result.GroupingRules[0].ID || em.GroupingRules[0].GroupingRuleID != result.GroupingRules[1].ID {
+		t.Errorf("copy references grouping rules %s and %s, want the copies", em.GroupingRuleID, em.GroupingRules[0].GroupingRuleID)
+	}
+	if result.GroupingRules[0].Name != "by host (Payments)" {
+		t.Errorf("grouping rule copy name = %q, want %q", result.GroupingRules[0].Name, "by host (Payments)")
+	}
+	if source.GroupingRuleID != "rule-1" {
+		t.Errorf("source references grouping rule %s, want rule-1", source.GroupingRuleID)
+	}
+
+	// Only the routing rules targeting the source are copied
+	if len(result.RoutingRules) != 1 || result.RoutingRules[0].EventManagerID != "payments" || result.RoutingRules[0].Match.Classes[0] != "db" {
+		t.Errorf("routing rules = %+v, want a copy of route-1 targeting the copy", result.RoutingRules)
+	}
+
+	// Expired silences and materialized occurrences are not copied
+	if len(result.Silences) != 2 {
+		t.Fatalf("copied %d silences, want the active and recurring ones", len(result.Silences))
+	}
+	for _, s := range result.Silences {
+		if s.Match.EventManagerID != "payments" {
+			t.Errorf("silence copy matches event manager %s, want payments", s.Match.EventManagerID)
+		}
+	}
+
+	// Cloning again under the same ID conflicts
+	if _, err := cloner.Clone(ctx, "golden", &domain.CloneEventManagerRequest{ID: "payments", Name: "Payments"}); !errors.Is(err, domain.ErrEventManagerAlreadyExists) {
+		t.Errorf("Clone() error = %v, want ErrEventManagerAlreadyExists", err)
+	}
+	if _, err := cloner.Clone(ctx, "missing", &domain.CloneEventManagerRequest{Name: "Missing"}); !errors.Is(err, domain.ErrEventManagerNotFound) {
+		t.Errorf("Clone() error = %v, want ErrEventManagerNotFound", err)
+	}
+}
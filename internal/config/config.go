@@ -3,12 +3,19 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"argus-go/internal/domain"
 )
 
 // StorageMode represents the storage backend mode.
@@ -28,17 +35,67 @@ func (m StorageMode) IsValid() bool {
 
 // Config represents the complete application configuration.
 type Config struct {
-	Storage  StorageConfig  `yaml:"storage"`
-	Server   ServerConfig   `yaml:"server"`
-	Kafka    KafkaConfig    `yaml:"kafka"`
-	Redis    RedisConfig    `yaml:"redis"`
-	Postgres PostgresConfig `yaml:"postgres"`
-	Logger   LoggerConfig   `yaml:"logger"`
+	Storage    StorageConfig    `yaml:"storage"`
+	Server     ServerConfig     `yaml:"server"`
+	Kafka      KafkaConfig      `yaml:"kafka"`
+	Redis      RedisConfig      `yaml:"redis"`
+	Postgres   PostgresConfig   `yaml:"postgres"`
+	Logger     LoggerConfig     `yaml:"logger"`
+	SLO        SLOConfig        `yaml:"slo"`
+	Chaos      ChaosConfig      `yaml:"chaos"`
+	Grouping   GroupingConfig   `yaml:"grouping"`
+	Dedup      DedupConfig      `yaml:"dedup"`
+	Severities SeveritiesConfig `yaml:"severities"`
+	Reminders  RemindersConfig  `yaml:"reminders"`
+	Processor  ProcessorConfig  `yaml:"processor"`
+	Health     HealthConfig     `yaml:"health"`
+	Cache      CacheConfig      `yaml:"cache"`
+	Shutdown   ShutdownConfig   `yaml:"shutdown"`
+	Usage      UsageConfig      `yaml:"usage"`
+	Quota      QuotaConfig      `yaml:"quota"`
+	Retention  RetentionConfig  `yaml:"retention"`
+	Archive    ArchiveConfig    `yaml:"archive"`
+	Ingest     IngestConfig     `yaml:"ingest"`
+	Scrubbing  ScrubbingConfig  `yaml:"scrubbing"`
+
+	Remediation RemediationConfig `yaml:"remediation"`
+	Approvals   ApprovalsConfig   `yaml:"approvals"`
+	Silences    SilencesConfig    `yaml:"silences"`
+	TestAlerts  TestAlertsConfig  `yaml:"test_alerts"`
+	Rules       RulesConfig       `yaml:"rules"`
+
+	SelfMonitoring SelfMonitoringConfig `yaml:"self_monitoring"`
+	Notification   NotificationConfig   `yaml:"notification"`
+
+	SourceConsumers []SourceConsumerConfig `yaml:"source_consumers"`
 }
 
 // StorageConfig holds the storage mode configuration.
 type StorageConfig struct {
-	Mode StorageMode `yaml:"mode"`
+	Mode        StorageMode           `yaml:"mode"`
+	MemoryQueue MemoryQueueConfig     `yaml:"memory_queue"`
+	Operations  StoreOperationsConfig `yaml:"operations"`
+}
+
+// StoreOperationsConfig bounds the state store and repository operations.
+type StoreOperationsConfig struct {
+	// Timeout fails an operation that takes longer, so a stuck store cannot
+	// block the processor indefinitely. A negative value disables it.
+	Timeout time.Duration `yaml:"timeout"`
+
+	// SlowThreshold logs a warning for operations that take longer.
+	// A negative value disables it.
+	SlowThreshold time.Duration `yaml:"slow_threshold"`
+}
+
+// MemoryQueueConfig holds settings for the in-memory queue used in memory mode.
+type MemoryQueueConfig struct {
+	// CheckpointDir enables persisting queued messages and the consumer
+	// position to this directory, so they survive a restart. Empty disables it.
+	CheckpointDir string `yaml:"checkpoint_dir"`
+
+	// CheckpointInterval is how often the consumer position is saved.
+	CheckpointInterval time.Duration `yaml:"checkpoint_interval"`
 }
 
 // UseMemory returns true if in-memory storage should be used.
@@ -58,14 +115,226 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `yaml:"read_timeout"`
 	WriteTimeout time.Duration `yaml:"write_timeout"`
 	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+
+	// ProxyHeader is the header holding the client IP set by a proxy in
+	// front of the server, such as "X-Forwarded-For". Empty uses the
+	// address of the connection.
+	ProxyHeader string `yaml:"proxy_header"`
+
+	// Addresses the API listens on, as "host:port" ("[::1]:8080" for IPv6)
+	// or "unix:" followed by the path of a Unix domain socket. Empty listens
+	// on Host and Port.
+	Addresses []string `yaml:"addresses"`
+
+	// AdminAddresses move /metrics and the /v1/admin API to listeners of
+	// their own, e.g. an internal port, in the format of Addresses. Empty
+	// serves them with the rest of the API.
+	AdminAddresses []string `yaml:"admin_addresses"`
+
+	// AdminTokens are the credentials of the /v1/admin API: requests must
+	// carry one as "Authorization: Bearer <token>". Empty leaves the admin
+	// API open, e.g. when only the admin addresses can reach it.
+	AdminTokens []string `yaml:"admin_tokens"`
+
+	Compression CompressionConfig `yaml:"compression"`
+	CORS        CORSConfig        `yaml:"cors"`
+}
+
+// CORSConfig holds the cross-origin resource sharing settings that let
+// browser-based frontends on other origins call the API.
+type CORSConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// AllowOrigins lists the origins allowed to call the API, such as
+	// "https://dashboard.example.com", or "*" for any origin.
+	AllowOrigins []string `yaml:"allow_origins"`
+
+	AllowMethods  []string `yaml:"allow_methods"`
+	AllowHeaders  []string `yaml:"allow_headers"`
+	ExposeHeaders []string `yaml:"expose_headers"`
+
+	// AllowCredentials lets browsers send cookies and authorization headers.
+	// It cannot be combined with the "*" origin.
+	AllowCredentials bool `yaml:"allow_credentials"`
+
+	// MaxAge is how long browsers may cache the result of a preflight request.
+	MaxAge time.Duration `yaml:"max_age"`
 }
 
+// CompressionConfig holds HTTP response compression settings.
+// Responses are compressed with brotli, gzip or deflate, whichever the
+// client accepts first in that order.
+type CompressionConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Level is "default", "best_speed" or "best_compression".
+	Level string `yaml:"level"`
+
+	// ContentTypes lists the response content types that are compressed,
+	// matched by prefix so parameters such as charset are ignored.
+	ContentTypes []string `yaml:"content_types"`
+}
+
+// defaultConsumerGroup is the Kafka consumer group of the processors.
+const defaultConsumerGroup = "argus-processor"
+
 // KafkaConfig holds Kafka connection and topic settings.
 type KafkaConfig struct {
 	Brokers        []string `yaml:"brokers"`
 	Topic          string   `yaml:"topic"`
 	ConsumerGroup  string   `yaml:"consumer_group"`
 	PartitionCount int      `yaml:"partition_count"`
+
+	// Topics lists the dedicated topics of event managers the processor
+	// consumes besides Topic. The topics of existing event managers are
+	// added at startup.
+	Topics []string `yaml:"topics"`
+
+	// Commit sets when consumers commit the offsets of their messages.
+	Commit KafkaCommitConfig `yaml:"commit"`
+}
+
+// Modes of committing the offsets of consumed Kafka messages.
+const (
+	KafkaCommitAfterProcessing  = "after_processing"
+	KafkaCommitBeforeProcessing = "before_processing"
+)
+
+// KafkaCommitConfig sets when Kafka consumers commit the offsets of the
+// messages they consume, which decides the delivery guarantee of the queue.
+type KafkaCommitConfig struct {
+	// Mode is "after_processing", the default, committing a message once it
+	// is handled: a crash before the commit handles it again after the
+	// restart (at least once). "before_processing" commits a message before
+	// handling it: a crash while handling it loses it (at most once).
+	Mode string `yaml:"mode"`
+
+	// Interval batches commits, committing the offsets reached every
+	// interval instead of each message before the next, which saves a round
+	// trip to the brokers per message. A crash loses the commits of up to an
+	// interval, whose messages are handled again after the restart, even
+	// before_processing. Zero commits each message.
+	Interval time.Duration `yaml:"interval"`
+}
+
+// CommitBeforeProcessing returns true if messages are committed before they
+// are handled.
+func (c *KafkaCommitConfig) CommitBeforeProcessing() bool {
+	return c.Mode == KafkaCommitBeforeProcessing
+}
+
+// validate checks the mode is known and the interval isn't negative.
+func (c *KafkaCommitConfig) validate() error {
+	if c.Mode != KafkaCommitAfterProcessing && c.Mode != KafkaCommitBeforeProcessing {
+		return fmt.Errorf("commit mode must be %q or %q", KafkaCommitAfterProcessing, KafkaCommitBeforeProcessing)
+	}
+	if c.Interval < 0 {
+		return errors.New("commit interval must not be negative")
+	}
+	return nil
+}
+
+// AllTopics returns Topic and Topics, without duplicates.
+func (c *KafkaConfig) AllTopics() []string {
+	topics := []string{c.Topic}
+	for _, topic := range c.Topics {
+		if topic != "" && !slices.Contains(topics, topic) {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+// Types of source consumers.
+const (
+	SourceTypeKafka = "kafka"
+	SourceTypeMQTT  = "mqtt"
+)
+
+// SourceConsumerConfig describes an external Kafka topic, or MQTT topics,
+// whose messages are ingested as events, without going through the HTTP API.
+type SourceConsumerConfig struct {
+	// Name identifies the source in logs and metrics.
+	Name string `yaml:"name"`
+
+	// Type is "kafka", the default, or "mqtt".
+	Type string `yaml:"type"`
+
+	// Brokers defaults to kafka.brokers.
+	Brokers []string `yaml:"brokers"`
+	Topic   string   `yaml:"topic"`
+
+	// ConsumerGroup defaults to "argus-source-<name>".
+	ConsumerGroup string `yaml:"consumer_group"`
+
+	// MQTT holds the settings of MQTT sources, which ignore the Kafka ones.
+	MQTT MQTTSourceConfig `yaml:"mqtt"`
+
+	// EventManagerID receives the events of the source. If empty, events
+	// without an event_manager_id are routed by the routing rules.
+	EventManagerID string `yaml:"event_manager_id"`
+
+	// Mapping maps each message, parsed as JSON, to an event. If unset,
+	// messages must be events in the ArgusGo format.
+	Mapping domain.WebhookTransform `yaml:"mapping"`
+}
+
+// MQTTSourceConfig describes the MQTT subscription of a source, e.g. of a
+// fleet of edge devices.
+type MQTTSourceConfig struct {
+	// Broker is the URL of the MQTT broker, e.g. tcp://mqtt:1883 or
+	// ssl://mqtt:8883.
+	Broker string `yaml:"broker"`
+
+	// Topics are subscribed to, and may hold wildcards, e.g. devices/+/alerts.
+	Topics []string `yaml:"topics"`
+
+	// QoS of the subscriptions: 0 (at most once), 1 (at least once) or 2
+	// (exactly once). With 1 or 2, messages are redelivered if ArgusGo
+	// disconnects before acknowledging them.
+	QoS int `yaml:"qos"`
+
+	// ClientID defaults to "argus-source-<name>". With a persistent
+	// session, the broker keeps QoS 1 and 2 messages while ArgusGo is away.
+	ClientID string `yaml:"client_id"`
+
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// validateSourceConsumers checks that every source has a unique name, what
+// to consume and a valid mapping.
+func validateSourceConsumers(sources []SourceConsumerConfig) error {
+	names := make(map[string]bool)
+	for i, s := range sources {
+		if s.Name == "" {
+			return fmt.Errorf("[%d]: name is required", i)
+		}
+		if names[s.Name] {
+			return fmt.Errorf("[%d]: duplicate name %q", i, s.Name)
+		}
+		names[s.Name] = true
+
+		switch s.Type {
+		case SourceTypeKafka:
+			if s.Topic == "" || len(s.Brokers) == 0 {
+				return fmt.Errorf("%s: topic and brokers are required", s.Name)
+			}
+		case SourceTypeMQTT:
+			if s.MQTT.Broker == "" || len(s.MQTT.Topics) == 0 {
+				return fmt.Errorf("%s: mqtt.broker and mqtt.topics are required", s.Name)
+			}
+			if s.MQTT.QoS < 0 || s.MQTT.QoS > 2 {
+				return fmt.Errorf("%s: mqtt.qos must be 0, 1 or 2", s.Name)
+			}
+		default:
+			return fmt.Errorf("%s: unknown type %q", s.Name, s.Type)
+		}
+		if err := s.Mapping.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", s.Name, err)
+		}
+	}
+	return nil
 }
 
 // RedisConfig holds Redis connection settings.
@@ -74,6 +343,22 @@ type RedisConfig struct {
 	Port     int    `yaml:"port"`
 	Password string `yaml:"password"`
 	DB       int    `yaml:"db"`
+
+	// Shards lists the Redis instances the state store spreads its keys
+	// across by consistent hashing, for deployments where a single instance
+	// limits alert cardinality. When set, Host and Port are ignored; Password
+	// and DB apply to every shard.
+	Shards []RedisShardConfig `yaml:"shards"`
+}
+
+// RedisShardConfig is one Redis instance of a sharded state store.
+type RedisShardConfig struct {
+	// Name places the shard on the hash ring, so keys stay on a shard whose
+	// address changes as long as its name is kept. Defaults to Addr.
+	Name string `yaml:"name"`
+
+	// Addr is the host:port of the instance.
+	Addr string `yaml:"addr"`
 }
 
 // PostgresConfig holds PostgreSQL connection settings.
@@ -94,6 +379,862 @@ type LoggerConfig struct {
 	Format string `yaml:"format"` // "json" or "text"
 }
 
+// SLOConfig holds the service level objectives tracked by the processor.
+type SLOConfig struct {
+	Objectives []SLOObjectiveConfig `yaml:"objectives"`
+}
+
+// SLOObjectiveConfig defines a latency objective: Target fraction of alerts
+// must be created within Threshold of ingestion, measured over Window.
+type SLOObjectiveConfig struct {
+	Name        string        `yaml:"name"`
+	Description string        `yaml:"description"`
+	Target      float64       `yaml:"target"`
+	Threshold   time.Duration `yaml:"threshold"`
+	Window      time.Duration `yaml:"window"`
+}
+
+// GroupingConfig holds system-wide grouping settings.
+type GroupingConfig struct {
+	// DefaultRuleID is the grouping rule applied to event managers without a
+	// grouping rule of their own. Empty means such events are not grouped.
+	// It can be changed at runtime through the admin API.
+	DefaultRuleID string `yaml:"default_rule_id"`
+}
+
+// DedupConfig holds deduplication settings.
+type DedupConfig struct {
+	// Global makes dedup keys unique service-wide: an event whose dedup key
+	// already has an alert of another event manager subscribes its event
+	// manager to that alert instead of only counting as a re-trigger.
+	Global bool `yaml:"global"`
+}
+
+// SeveritiesConfig holds the severity levels events may carry. Without
+// levels, the default scale of high, medium and low applies.
+type SeveritiesConfig struct {
+	// Levels are the accepted severity levels, most severe first, e.g.
+	// P1 to P5.
+	Levels []string `yaml:"levels"`
+
+	// Default is applied to events without a severity whose event manager
+	// has no default of its own. Empty means the least severe level.
+	Default string `yaml:"default"`
+
+	// Presentation overrides the display name, color and emoji of levels in
+	// notifications and UIs. Levels default to their capitalized name and a
+	// color and emoji by rank, from red to grey.
+	Presentation map[string]domain.SeverityPresentation `yaml:"presentation"`
+}
+
+// Scale returns the severity scale of the configured levels.
+func (c *SeveritiesConfig) Scale() (*domain.SeverityScale, error) {
+	if len(c.Levels) == 0 && c.Default == "" {
+		return domain.DefaultSeverityScale, nil
+	}
+
+	levels := domain.DefaultSeverityScale.Levels()
+	if len(c.Levels) > 0 {
+		levels = make([]domain.Severity, len(c.Levels))
+		for i, level := range c.Levels {
+			levels[i] = domain.Severity(level)
+		}
+	}
+	return domain.NewSeverityScale(levels, domain.Severity(c.Default))
+}
+
+// Presentations returns the presentations of the levels of scale, with the
+// configured overrides.
+func (c *SeveritiesConfig) Presentations(scale *domain.SeverityScale) (*domain.SeverityPresentations, error) {
+	if len(c.Presentation) == 0 && scale == domain.DefaultSeverityScale {
+		return domain.DefaultSeverityPresentations, nil
+	}
+
+	overrides := make(map[domain.Severity]domain.SeverityPresentation, len(c.Presentation))
+	for level, presentation := range c.Presentation {
+		overrides[domain.Severity(level)] = presentation
+	}
+	return domain.NewSeverityPresentations(scale, overrides)
+}
+
+// RemindersConfig holds the settings of the scheduler resending the
+// notifications of unacknowledged parent alerts. Reminders are enabled per
+// event manager in its notification config.
+type RemindersConfig struct {
+	// CheckInterval is how often due reminders are sent.
+	CheckInterval time.Duration `yaml:"check_interval"`
+}
+
+// UsageConfig holds the settings of per event manager usage accounting.
+type UsageConfig struct {
+	// FlushInterval is how often the counts of the process are added to the
+	// stored usage. It defaults to 30s; a negative value disables usage
+	// accounting.
+	FlushInterval time.Duration `yaml:"flush_interval"`
+}
+
+// IngestConfig holds the settings of event ingestion.
+type IngestConfig struct {
+	Async AsyncIngestConfig `yaml:"async"`
+
+	// EventStatusTTL is how long the status of an accepted event can be
+	// looked up by its event ID. It defaults to 1h; a negative value
+	// disables event status tracking.
+	EventStatusTTL time.Duration `yaml:"event_status_ttl"`
+}
+
+// AsyncIngestConfig holds the settings of async ingest: accepted events are
+// added to a bounded in-process buffer and published to the queue in the
+// background, so ingest doesn't wait for a slow queue. Events buffered when
+// the process dies are lost.
+//
+// A single goroutine publishes the buffer in order and retries an event the
+// queue fails until it's published, so a failing event holds back all those
+// behind it and, if the queue stays down, the buffer fills up and ingest
+// rejects events. Shutdown gives each event left one more attempt and drops
+// those the queue fails, counted by argus_publish_buffer_dropped_total.
+type AsyncIngestConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// BufferSize is how many events may wait to be published; events are
+	// rejected while the buffer is full. It defaults to 10000.
+	BufferSize int `yaml:"buffer_size"`
+}
+
+// QuotaConfig holds the settings of event manager quota enforcement.
+type QuotaConfig struct {
+	// CheckInterval is how often the usage of event managers with a quota is
+	// checked against it. It defaults to 30s; a negative value disables
+	// quota enforcement.
+	CheckInterval time.Duration `yaml:"check_interval"`
+}
+
+// RetentionConfig holds the settings of the retention job, which maintains
+// the monthly partitions of the alerts table in storage mode.
+type RetentionConfig struct {
+	// CheckInterval is how often partitions are maintained. It defaults to
+	// 1h; a negative value disables the job.
+	CheckInterval time.Duration `yaml:"check_interval"`
+
+	// PartitionsAhead is the number of months after the current one whose
+	// partitions are created in advance. It defaults to 2.
+	PartitionsAhead int `yaml:"partitions_ahead"`
+
+	// Alerts is how long alerts are kept. The partitions of months that
+	// ended longer ago are detached once none of their alerts is open. Zero
+	// keeps alerts forever.
+	Alerts time.Duration `yaml:"alerts"`
+}
+
+// Stages of scrubbing.
+const (
+	ScrubStageIngest       = "ingest"
+	ScrubStageNotification = "notification"
+)
+
+// ScrubbingConfig holds the rules masking secrets and personal data that
+// leak into the summaries, labels and tags of events.
+type ScrubbingConfig struct {
+	// Stage is where text is scrubbed: "ingest", the default, before events
+	// are queued, so nothing unscrubbed is stored or sent; or
+	// "notification", before notifications leave, keeping the stored text
+	// intact.
+	Stage string `yaml:"stage"`
+
+	// Rules are applied in order.
+	Rules []domain.ScrubRule `yaml:"rules"`
+}
+
+// validate checks the stage is known and the rules compile.
+func (c *ScrubbingConfig) validate() error {
+	if c.Stage != ScrubStageIngest && c.Stage != ScrubStageNotification {
+		return fmt.Errorf("stage must be %q or %q", ScrubStageIngest, ScrubStageNotification)
+	}
+	_, err := domain.NewScrubber(c.Rules, nil)
+	return err
+}
+
+// ArchiveConfig holds the settings of the event archive: every queued event
+// is written as NDJSON to a bucket of S3, or of another store with an
+// S3-compatible API such as Google Cloud Storage with HMAC keys, in one
+// partition per hour, for compliance and offline replay.
+type ArchiveConfig struct {
+	// Bucket is the bucket events are archived to. Empty disables the
+	// archive.
+	Bucket string `yaml:"bucket"`
+
+	// Endpoint is the URL of the S3-compatible API, e.g.
+	// https://storage.googleapis.com. It defaults to the S3 endpoint of
+	// Region.
+	Endpoint string `yaml:"endpoint"`
+
+	// Region signs the requests. It defaults to us-east-1.
+	Region string `yaml:"region"`
+
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+
+	// Prefix is prepended to object keys. It defaults to "events".
+	Prefix string `yaml:"prefix"`
+
+	// FlushInterval is how often buffered events are written. It defaults
+	// to 1m.
+	FlushInterval time.Duration `yaml:"flush_interval"`
+
+	// BatchSize is the number of events of an hour that are written as soon
+	// as they are buffered, without waiting for the flush. It defaults to
+	// 10000.
+	BatchSize int `yaml:"batch_size"`
+
+	// MaxPending bounds the events buffered while the bucket can't be
+	// written; further events are dropped. It defaults to 100000.
+	MaxPending int `yaml:"max_pending"`
+
+	// ConsumerGroup is the Kafka consumer group of the archive in storage
+	// mode, so it reads the queue independently of the processor. It
+	// defaults to kafka.consumer_group with an "-archive" suffix.
+	ConsumerGroup string `yaml:"consumer_group"`
+}
+
+// Enabled reports whether events are archived.
+func (c *ArchiveConfig) Enabled() bool {
+	return c.Bucket != ""
+}
+
+// validate checks the endpoint is an HTTP URL and the credentials are set
+// when the archive is enabled.
+func (c *ArchiveConfig) validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	u, err := url.Parse(c.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("endpoint %q must be an http or https URL", c.Endpoint)
+	}
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+		return errors.New("access_key_id and secret_access_key are required")
+	}
+	if c.BatchSize < 0 || c.MaxPending < 0 {
+		return errors.New("batch_size and max_pending must not be negative")
+	}
+	return nil
+}
+
+// RemediationConfig holds the settings of the remediation actions event
+// managers run when their alerts trigger.
+type RemediationConfig struct {
+	// Timeout bounds a single remediation action: the HTTP call or the
+	// publish to a queue. It defaults to 10s.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// SilencesConfig holds the settings of the scheduler of silences.
+type SilencesConfig struct {
+	// CheckInterval is how often the occurrences of recurring silences are
+	// materialized and the silences in effect are reloaded from the store.
+	// It defaults to 30s; a negative value disables silences.
+	CheckInterval time.Duration `yaml:"check_interval"`
+}
+
+// TestAlertsConfig holds the settings of the synthetic alerts created to
+// verify the setup of an event manager.
+type TestAlertsConfig struct {
+	// TTL is how long a test alert stays active before it is resolved and
+	// deleted, unless the request sets its own. It defaults to 5m.
+	TTL time.Duration `yaml:"ttl"`
+
+	// MaxTTL is the longest TTL a request may set. It defaults to 1h.
+	MaxTTL time.Duration `yaml:"max_ttl"`
+
+	// CheckInterval is how often expired test alerts are cleaned up. It
+	// defaults to 30s; a negative value disables test alerts.
+	CheckInterval time.Duration `yaml:"check_interval"`
+}
+
+// validate checks the default TTL is within the maximum.
+func (c *TestAlertsConfig) validate() error {
+	if c.TTL < 0 || c.TTL > c.MaxTTL {
+		return fmt.Errorf("ttl must be between 0 and max_ttl (%s)", c.MaxTTL)
+	}
+	return nil
+}
+
+// RulesConfig holds the settings of the runner of query rules.
+type RulesConfig struct {
+	// CheckInterval is how often the rules due are run. It defaults to 10s;
+	// a negative value disables query rules.
+	CheckInterval time.Duration `yaml:"check_interval"`
+
+	// DefaultInterval is how often a rule without an interval runs. It
+	// defaults to 1m.
+	DefaultInterval time.Duration `yaml:"default_interval"`
+
+	// Timeout bounds each query. It defaults to 30s.
+	Timeout time.Duration `yaml:"timeout"`
+
+	// Executions is how many runs of each rule are kept, to debug rules. It
+	// defaults to 100; a negative value disables recording runs.
+	Executions int `yaml:"executions"`
+
+	// Datasources are the systems rules query, by name.
+	Datasources []DatasourceConfig `yaml:"datasources"`
+
+	// Sharding shares the rules between the replicas, instead of every
+	// replica running every rule.
+	Sharding RuleShardingConfig `yaml:"sharding"`
+}
+
+// RuleShardingConfig holds the settings of the sharing of query rules
+// between replicas. Replicas register through heartbeats, and each runs the
+// rules hashed to it among the live ones; the rules of a replica missing its
+// heartbeats move to the others.
+type RuleShardingConfig struct {
+	// Enabled shares the rules between the replicas.
+	Enabled bool `yaml:"enabled"`
+
+	// InstanceID identifies this replica among the others. It defaults to
+	// the hostname.
+	InstanceID string `yaml:"instance_id"`
+
+	// HeartbeatTimeout is how long a replica missing its heartbeats keeps
+	// its rules. It defaults to three check intervals.
+	HeartbeatTimeout time.Duration `yaml:"heartbeat_timeout"`
+}
+
+// Types of datasources.
+const (
+	DatasourceTypeLoki       = "loki"
+	DatasourceTypeClickHouse = "clickhouse"
+	DatasourceTypeSQL        = "sql"
+)
+
+// DatasourceConfig describes a system query rules run against.
+type DatasourceConfig struct {
+	// Name identifies the datasource in rules, logs and metrics.
+	Name string `yaml:"name"`
+
+	// Type is "loki", the default, "clickhouse" or "sql".
+	Type string `yaml:"type"`
+
+	// URL is the base URL of the datasource, e.g. http://loki:3100 or the
+	// HTTP interface of ClickHouse, http://clickhouse:8123. For sql
+	// datasources, it is the data source name passed to the driver.
+	URL string `yaml:"url"`
+
+	// Tenant is sent as X-Scope-OrgID to multi-tenant Loki deployments.
+	Tenant string `yaml:"tenant"`
+
+	// Username and Password authenticate to ClickHouse.
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+
+	// Database is the default database of ClickHouse queries.
+	Database string `yaml:"database"`
+
+	// Driver is the database/sql driver of sql datasources. It defaults to
+	// "pgx", for PostgreSQL and compatible databases.
+	Driver string `yaml:"driver"`
+}
+
+// validate checks that every datasource has a unique name, a known type
+// and a URL.
+func (c *RulesConfig) validate() error {
+	names := make(map[string]bool)
+	for i, ds := range c.Datasources {
+		if ds.Name == "" {
+			return fmt.Errorf("datasources[%d]: name is required", i)
+		}
+		if names[ds.Name] {
+			return fmt.Errorf("datasources[%d]: duplicate name %q", i, ds.Name)
+		}
+		names[ds.Name] = true
+
+		switch ds.Type {
+		case DatasourceTypeLoki, DatasourceTypeClickHouse, DatasourceTypeSQL:
+			if ds.URL == "" {
+				return fmt.Errorf("%s: url is required", ds.Name)
+			}
+		default:
+			return fmt.Errorf("%s: unknown type %q", ds.Name, ds.Type)
+		}
+	}
+
+	if c.Sharding.Enabled && c.CheckInterval > 0 {
+		if c.Sharding.InstanceID == "" {
+			return fmt.Errorf("sharding: instance_id is required")
+		}
+		if c.Sharding.HeartbeatTimeout <= c.CheckInterval {
+			return fmt.Errorf("sharding: heartbeat_timeout must be longer than check_interval")
+		}
+	}
+	return nil
+}
+
+// Modes of the approval of destructive admin operations.
+const (
+	ApprovalModeTwoPerson = "two_person"
+	ApprovalModeDelay     = "delay"
+)
+
+// ApprovalsConfig holds the settings of the approval workflow guarding
+// destructive admin operations: purges and force-resolves.
+type ApprovalsConfig struct {
+	// Mode selects how operations are approved: "two_person" holds them
+	// until an actor other than the requester approves them, "delay" runs
+	// them after Delay unless they are canceled. Empty runs them right away.
+	Mode string `yaml:"mode"`
+
+	// Delay is how long operations wait in delay mode. It defaults to 5m.
+	Delay time.Duration `yaml:"delay"`
+
+	// CheckInterval is how often operations whose delay has passed are run.
+	// It defaults to 10s.
+	CheckInterval time.Duration `yaml:"check_interval"`
+}
+
+// validate checks the mode is known.
+func (c *ApprovalsConfig) validate() error {
+	switch c.Mode {
+	case "", ApprovalModeTwoPerson, ApprovalModeDelay:
+		return nil
+	}
+	return fmt.Errorf("mode must be %q or %q", ApprovalModeTwoPerson, ApprovalModeDelay)
+}
+
+// ProcessorConfig holds the retry settings of the event processor. Events
+// failing with a store error are retried with exponential backoff and jitter
+// before they are given up on and left to the queue's failure handling.
+type ProcessorConfig struct {
+	// MaxRetries is how often a failed event is retried. It defaults to 5;
+	// a negative value disables retries.
+	MaxRetries int `yaml:"max_retries"`
+
+	// RetryBackoff is the delay before the first retry; it doubles with
+	// every further retry.
+	RetryBackoff time.Duration `yaml:"retry_backoff"`
+
+	// MaxRetryBackoff caps the delay between retries.
+	MaxRetryBackoff time.Duration `yaml:"max_retry_backoff"`
+
+	// StallTimeout is how long the processor may handle one event, retries
+	// included, before health checks report it stalled. It defaults to 2m;
+	// a negative value disables the check.
+	StallTimeout time.Duration `yaml:"stall_timeout"`
+
+	// Shadow runs the processor in dry-run mode: it consumes and evaluates
+	// events against in-memory alert state, logging the alerts and
+	// notifications it would create without writing to the state store and
+	// alert repositories or notifying anyone. In storage mode the shadow must
+	// have its own Kafka consumer group.
+	Shadow bool `yaml:"shadow"`
+
+	// ActiveAlertsReconcileInterval is how often the active alert gauge is
+	// reset to the counts of the alert repository. It defaults to 5m; a
+	// negative value disables reconciliation.
+	ActiveAlertsReconcileInterval time.Duration `yaml:"active_alerts_reconcile_interval"`
+
+	// PendingResolveSweepInterval is how often parent alerts waiting for
+	// children that have all resolved are resolved, should the processor
+	// have stopped before checking on them. It defaults to 5m; a negative
+	// value disables the sweep.
+	PendingResolveSweepInterval time.Duration `yaml:"pending_resolve_sweep_interval"`
+
+	// MaxPendingResolve is how long the resolve of a parent alert may wait
+	// for its children before the sweep force-resolves the parent, so no
+	// parent is stuck waiting on a child that never resolves. Zero, the
+	// default, waits indefinitely. With ForceResolveChildren the children
+	// still active are resolved with it; otherwise they stay active on their
+	// own.
+	MaxPendingResolve    time.Duration `yaml:"max_pending_resolve"`
+	ForceResolveChildren bool          `yaml:"force_resolve_children"`
+
+	// Storms configures the detection of alert storms.
+	Storms StormsConfig `yaml:"storms"`
+
+	// Scaling configures the autoscaling signal of the processor.
+	Scaling ScalingConfig `yaml:"scaling"`
+}
+
+// ScalingConfig holds the settings of the autoscaling signal: the pressure
+// on the processor, normalized so that 1 means it keeps up with its targets,
+// above 1 that it needs more replicas and below 1 that it could do with
+// fewer. External scalers, like KEDA or the HPA, target a pressure of 1.
+type ScalingConfig struct {
+	// Interval is how often the processing rate is measured, each over the
+	// events since the last measurement, and the scaling metrics updated. It
+	// defaults to 15s; a negative interval disables the signal.
+	Interval time.Duration `yaml:"interval"`
+
+	// TargetLag is the longest events should wait in the queue. It defaults
+	// to 30s.
+	TargetLag time.Duration `yaml:"target_lag"`
+
+	// TargetDrainTime is the longest the processor should take to work off
+	// the queued events at its current rate. It defaults to 1m.
+	TargetDrainTime time.Duration `yaml:"target_drain_time"`
+}
+
+// StormsConfig holds the settings of alert storm detection. The processor
+// measures the event rate of every event manager; above the threshold the
+// event manager is in a storm, and its new alerts are grouped under a single
+// storm alert until the rate stays at or below the threshold for the quiet
+// period. Event managers can override the threshold and quiet period.
+type StormsConfig struct {
+	// CheckInterval is how often event rates are measured, each over the
+	// events since the last measurement. It defaults to 10s; a negative
+	// interval disables storm detection.
+	CheckInterval time.Duration `yaml:"check_interval"`
+
+	// EventsPerSecond is the default rate above which an event manager is in
+	// a storm. Zero detects storms only for event managers with a threshold
+	// of their own.
+	EventsPerSecond float64 `yaml:"events_per_second"`
+
+	// QuietPeriod is how long the rate must stay at or below the threshold
+	// for a storm to end. It defaults to 5m.
+	QuietPeriod time.Duration `yaml:"quiet_period"`
+}
+
+// validate checks that a shadow processor doesn't take events from the live
+// processors, and that the scaling targets are positive.
+func (c *ProcessorConfig) validate(storage StorageConfig, kafka KafkaConfig) error {
+	if c.Shadow && !storage.UseMemory() && kafka.ConsumerGroup == defaultConsumerGroup {
+		return errors.New("shadow mode requires a kafka.consumer_group other than the default")
+	}
+	if c.Scaling.TargetLag < 0 || c.Scaling.TargetDrainTime < 0 {
+		return errors.New("processor.scaling targets must be positive")
+	}
+	if c.MaxPendingResolve < 0 {
+		return errors.New("processor.max_pending_resolve must not be negative")
+	}
+	if c.MaxPendingResolve > 0 && c.PendingResolveSweepInterval < 0 {
+		return errors.New("processor.max_pending_resolve is enforced by the pending resolve sweep, which is disabled")
+	}
+	return nil
+}
+
+// HealthConfig holds the settings of the background probes of Redis and
+// PostgreSQL in storage mode. A failing dependency is probed again with
+// exponential backoff until it recovers.
+type HealthConfig struct {
+	// Interval is how often a healthy dependency is probed.
+	Interval time.Duration `yaml:"interval"`
+
+	// Timeout bounds each probe.
+	Timeout time.Duration `yaml:"timeout"`
+
+	// RetryBackoff is the delay before probing a failing dependency again;
+	// it doubles with every further failure.
+	RetryBackoff time.Duration `yaml:"retry_backoff"`
+
+	// MaxRetryBackoff caps the delay between probes of a failing dependency.
+	MaxRetryBackoff time.Duration `yaml:"max_retry_backoff"`
+}
+
+// CacheConfig holds the settings of the event manager and grouping rule
+// caches. Changes are broadcast to every replica (over PostgreSQL
+// LISTEN/NOTIFY in storage mode), so the TTL only bounds how long a replica
+// that missed a change serves the old configuration.
+type CacheConfig struct {
+	// TTL is how long a cached event manager or grouping rule is used.
+	// A negative value disables the caches.
+	TTL time.Duration `yaml:"ttl"`
+
+	// Alerts holds the read-through alert caches of the API.
+	Alerts AlertCacheConfig `yaml:"alerts"`
+}
+
+// AlertCacheConfig holds how long the alerts served by each cached API route
+// are reused, to protect the database from dashboards polling them. Writes
+// by the processor of the same replica invalidate the entries; those of
+// other replicas show once the entries expire. A negative value disables the
+// cache of a route.
+type AlertCacheConfig struct {
+	// Get is the TTL of the alerts of GET /v1/alerts/:dedupKey. It
+	// defaults to 2s.
+	Get time.Duration `yaml:"get"`
+
+	// Children is the TTL of the children of GET
+	// /v1/alerts/:dedupKey/children. It defaults to 2s.
+	Children time.Duration `yaml:"children"`
+}
+
+// SelfMonitoringConfig holds the settings of the alerts ArgusGo raises about
+// itself. They are ingested like any other event, for a reserved event
+// manager whose notification settings route them.
+type SelfMonitoringConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// EventManagerID is the reserved event manager of the alerts; it is
+	// created at startup if missing. It defaults to "argus-system".
+	EventManagerID string `yaml:"event_manager_id"`
+
+	// Interval is how often the conditions are checked. It defaults to 30s.
+	Interval time.Duration `yaml:"interval"`
+
+	// ProcessorLagThreshold alerts when events wait in the queue this long
+	// before being processed. It defaults to 1m; a negative value disables
+	// the check.
+	ProcessorLagThreshold time.Duration `yaml:"processor_lag_threshold"`
+
+	// PoisonMessagesThreshold alerts when this many events are given up on
+	// within one interval. It defaults to 1; a negative value disables the
+	// check.
+	PoisonMessagesThreshold int `yaml:"poison_messages_threshold"`
+
+	// NotificationFailuresThreshold alerts when this many notifications fail
+	// within one interval. It defaults to 5; a negative value disables the
+	// check.
+	NotificationFailuresThreshold int `yaml:"notification_failures_threshold"`
+
+	// ChannelFailureRateThreshold alerts, for each notification channel, when
+	// this fraction or more of its deliveries fail within one interval. It
+	// defaults to 0.5; a negative value disables the check.
+	ChannelFailureRateThreshold float64 `yaml:"channel_failure_rate_threshold"`
+
+	// ChannelMinDeliveries is the number of deliveries a channel needs within
+	// one interval for its failure rate to raise an alert, so a single failed
+	// delivery to a quiet channel doesn't. It defaults to 5.
+	ChannelMinDeliveries int `yaml:"channel_min_deliveries"`
+}
+
+// NotificationConfig holds the settings of notification delivery.
+type NotificationConfig struct {
+	// Plugins are external notifiers, for channels ArgusGo doesn't support.
+	// Event managers select one by name in their notification config.
+	Plugins []NotifierPluginConfig `yaml:"plugins"`
+
+	// Queues publish notifications to a Kafka topic or NATS subject, for
+	// consumers that prefer streaming to webhooks. Event managers select one
+	// by name in their notification config.
+	Queues []NotificationQueueConfig `yaml:"queues"`
+
+	// Watches names the plugins delivering the personal notifications of
+	// alert watches, by channel. A channel without a plugin can't be watched.
+	Watches WatchChannelsConfig `yaml:"watches"`
+
+	// Reports holds the settings of scheduled report emails.
+	Reports ReportsConfig `yaml:"reports"`
+
+	// Dedup sends each notification once, however often the change it
+	// notifies is processed.
+	Dedup NotificationDedupConfig `yaml:"dedup"`
+
+	// Messages are the notification messages of each locale, as Go
+	// templates by notification kind, added to the built-in English ones.
+	// Event managers select a locale by its language tag.
+	Messages map[string]map[domain.NotificationKind]string `yaml:"messages"`
+}
+
+// MessageCatalog returns the catalog of the configured messages.
+func (c *NotificationConfig) MessageCatalog() (*domain.MessageCatalog, error) {
+	if len(c.Messages) == 0 {
+		return domain.DefaultMessageCatalog, nil
+	}
+	return domain.NewMessageCatalog(c.Messages)
+}
+
+// NotificationDedupConfig holds the settings of notification deduplication.
+// Notifications of the same change to an alert within a window are sent once.
+type NotificationDedupConfig struct {
+	// Window is the attempt window of a change: attempts to notify it
+	// within the window are duplicates. It defaults to 1m; a negative
+	// window disables deduplication.
+	Window time.Duration `yaml:"window"`
+
+	// TTL is how long a sent notification is remembered. It defaults to 24h.
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// ReportsConfig holds the settings of scheduled reports.
+type ReportsConfig struct {
+	// Email is the plugin emailing scheduled reports. Without it, report
+	// schedules can't be created.
+	Email string `yaml:"email"`
+
+	// CheckInterval is how often report schedules are checked for reports
+	// due. It defaults to 1m; a negative value stops sending reports.
+	CheckInterval time.Duration `yaml:"check_interval"`
+}
+
+// WatchChannelsConfig names the notifier plugin of each personal channel.
+type WatchChannelsConfig struct {
+	// Email is the plugin sending email to the address of a watch.
+	Email string `yaml:"email"`
+
+	// Slack is the plugin sending Slack direct messages to the user ID of a
+	// watch.
+	Slack string `yaml:"slack"`
+}
+
+// Types of notification queues.
+const (
+	NotificationQueueKafka = "kafka"
+	NotificationQueueNATS  = "nats"
+)
+
+// NotificationQueueConfig describes a queue notifications are published to.
+type NotificationQueueConfig struct {
+	// Name is how event managers refer to the queue.
+	Name string `yaml:"name"`
+
+	// Type is "kafka", the default, or "nats".
+	Type string `yaml:"type"`
+
+	// Brokers and Topic of a Kafka queue. Brokers default to kafka.brokers.
+	Brokers []string `yaml:"brokers"`
+	Topic   string   `yaml:"topic"`
+
+	// URL and Subject of a NATS queue, e.g. nats://nats:4222.
+	URL     string `yaml:"url"`
+	Subject string `yaml:"subject"`
+}
+
+// NotifierPluginConfig describes a notifier plugin: a long-running process
+// that receives notifications as JSON lines on stdin and answers each with a
+// JSON line on stdout.
+type NotifierPluginConfig struct {
+	// Name is how event managers refer to the plugin.
+	Name string `yaml:"name"`
+
+	// Command is the executable of the plugin, with its arguments.
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"`
+
+	// Env is added to the environment of the plugin, e.g. for credentials.
+	Env map[string]string `yaml:"env"`
+
+	// Timeout bounds delivering one notification; a plugin that doesn't
+	// answer in time is restarted. It defaults to 5s.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// validate checks that every plugin has a unique name and a command, and
+// every queue a unique name and a destination.
+func (c *NotificationConfig) validate() error {
+	names := make(map[string]bool)
+	for i, p := range c.Plugins {
+		if p.Name == "" || p.Command == "" {
+			return fmt.Errorf("plugins[%d]: name and command are required", i)
+		}
+		if names[p.Name] {
+			return fmt.Errorf("plugins[%d]: duplicate name %q", i, p.Name)
+		}
+		names[p.Name] = true
+	}
+
+	names = make(map[string]bool)
+	for i, q := range c.Queues {
+		if q.Name == "" {
+			return fmt.Errorf("queues[%d]: name is required", i)
+		}
+		if names[q.Name] {
+			return fmt.Errorf("queues[%d]: duplicate name %q", i, q.Name)
+		}
+		names[q.Name] = true
+
+		switch q.Type {
+		case NotificationQueueKafka:
+			if q.Topic == "" || len(q.Brokers) == 0 {
+				return fmt.Errorf("queues[%d]: topic and brokers are required", i)
+			}
+		case NotificationQueueNATS:
+			if q.URL == "" || q.Subject == "" {
+				return fmt.Errorf("queues[%d]: url and subject are required", i)
+			}
+		default:
+			return fmt.Errorf("queues[%d]: unknown type %q", i, q.Type)
+		}
+	}
+
+	plugins := make(map[string]bool, len(c.Plugins))
+	for _, p := range c.Plugins {
+		plugins[p.Name] = true
+	}
+	for channel, name := range map[string]string{"email": c.Watches.Email, "slack": c.Watches.Slack} {
+		if name != "" && !plugins[name] {
+			return fmt.Errorf("watches.%s: unknown plugin %q", channel, name)
+		}
+	}
+	if c.Reports.Email != "" && !plugins[c.Reports.Email] {
+		return fmt.Errorf("reports.email: unknown plugin %q", c.Reports.Email)
+	}
+	return nil
+}
+
+// ShutdownConfig holds the timeout of each stage of the ordered shutdown.
+// A stage that exceeds its timeout is abandoned and the next one starts.
+type ShutdownConfig struct {
+	// HTTPTimeout bounds draining in-flight HTTP requests after the server
+	// stops accepting new connections.
+	HTTPTimeout time.Duration `yaml:"http_timeout"`
+
+	// ProducerTimeout bounds flushing events published by the ingest path.
+	ProducerTimeout time.Duration `yaml:"producer_timeout"`
+
+	// ProcessorTimeout bounds waiting for the processor to finish the events
+	// it is handling.
+	ProcessorTimeout time.Duration `yaml:"processor_timeout"`
+
+	// StoreTimeout bounds closing the state store and repositories.
+	StoreTimeout time.Duration `yaml:"store_timeout"`
+
+	// MetricsSnapshot saves the final metrics as the last stage.
+	MetricsSnapshot MetricsSnapshotConfig `yaml:"metrics_snapshot"`
+}
+
+// MetricsSnapshotConfig configures a snapshot of the metrics taken at the end
+// of a graceful shutdown, so short-lived and batch deployments don't lose the
+// data counted since the last scrape.
+type MetricsSnapshotConfig struct {
+	// File, if set, is written with the metrics in the Prometheus text
+	// format, e.g. for the node exporter's textfile collector.
+	File string `yaml:"file"`
+
+	// PushgatewayURL, if set, is the Prometheus Pushgateway the metrics are
+	// pushed to, grouped by job and instance.
+	PushgatewayURL string `yaml:"pushgateway_url"`
+
+	// Job is the job the metrics are pushed as. It defaults to "argus".
+	Job string `yaml:"job"`
+
+	// Timeout bounds writing and pushing the snapshot. It defaults to 5s.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Enabled reports whether a snapshot is taken.
+func (c *MetricsSnapshotConfig) Enabled() bool {
+	return c.File != "" || c.PushgatewayURL != ""
+}
+
+// validate checks the Pushgateway URL is an HTTP URL when set.
+func (c *MetricsSnapshotConfig) validate() error {
+	if c.PushgatewayURL == "" {
+		return nil
+	}
+	u, err := url.Parse(c.PushgatewayURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("pushgateway_url %q must be an http or https URL", c.PushgatewayURL)
+	}
+	return nil
+}
+
+// ChaosConfig holds the initial fault-injection settings.
+// It only takes effect in binaries built with the "chaos" build tag.
+type ChaosConfig struct {
+	Enabled      bool        `yaml:"enabled"`
+	StateStore   FaultConfig `yaml:"state_store"`
+	Repositories FaultConfig `yaml:"repositories"`
+	Queue        FaultConfig `yaml:"queue"`
+}
+
+// FaultConfig defines the faults injected into one group of components.
+type FaultConfig struct {
+	Latency   time.Duration `yaml:"latency"`
+	ErrorRate float64       `yaml:"error_rate"` // fraction of operations that fail
+	DropRate  float64       `yaml:"drop_rate"`  // fraction of writes silently skipped
+}
+
 // Load reads configuration from the specified YAML file path.
 // Returns an error if the file cannot be read or parsed.
 func Load(path string) (*Config, error) {
@@ -112,6 +1253,54 @@ func Load(path string) (*Config, error) {
 	// Apply defaults for any unset values
 	applyDefaults(cfg)
 
+	if err := cfg.Redis.validate(); err != nil {
+		return nil, fmt.Errorf("invalid redis config: %w", err)
+	}
+	if err := cfg.Server.validate(); err != nil {
+		return nil, fmt.Errorf("invalid server config: %w", err)
+	}
+	if err := cfg.Server.CORS.validate(); err != nil {
+		return nil, fmt.Errorf("invalid server.cors config: %w", err)
+	}
+	if err := cfg.Kafka.Commit.validate(); err != nil {
+		return nil, fmt.Errorf("invalid kafka config: %w", err)
+	}
+	if err := cfg.Processor.validate(cfg.Storage, cfg.Kafka); err != nil {
+		return nil, fmt.Errorf("invalid processor config: %w", err)
+	}
+	if scale, err := cfg.Severities.Scale(); err != nil {
+		return nil, fmt.Errorf("invalid severities config: %w", err)
+	} else if _, err := cfg.Severities.Presentations(scale); err != nil {
+		return nil, fmt.Errorf("invalid severities.presentation config: %w", err)
+	}
+	if err := cfg.Notification.validate(); err != nil {
+		return nil, fmt.Errorf("invalid notification config: %w", err)
+	}
+	if _, err := cfg.Notification.MessageCatalog(); err != nil {
+		return nil, fmt.Errorf("invalid notification.messages config: %w", err)
+	}
+	if err := cfg.Approvals.validate(); err != nil {
+		return nil, fmt.Errorf("invalid approvals config: %w", err)
+	}
+	if err := cfg.Rules.validate(); err != nil {
+		return nil, fmt.Errorf("invalid rules config: %w", err)
+	}
+	if err := cfg.Scrubbing.validate(); err != nil {
+		return nil, fmt.Errorf("invalid scrubbing config: %w", err)
+	}
+	if err := cfg.Archive.validate(); err != nil {
+		return nil, fmt.Errorf("invalid archive config: %w", err)
+	}
+	if err := cfg.TestAlerts.validate(); err != nil {
+		return nil, fmt.Errorf("invalid test_alerts config: %w", err)
+	}
+	if err := validateSourceConsumers(cfg.SourceConsumers); err != nil {
+		return nil, fmt.Errorf("invalid source_consumers config: %w", err)
+	}
+	if err := cfg.Shutdown.MetricsSnapshot.validate(); err != nil {
+		return nil, fmt.Errorf("invalid shutdown.metrics_snapshot config: %w", err)
+	}
+
 	return cfg, nil
 }
 
@@ -122,6 +1311,15 @@ func applyDefaults(cfg *Config) {
 	if cfg.Storage.Mode == "" {
 		cfg.Storage.Mode = StorageModeMemory
 	}
+	if cfg.Storage.MemoryQueue.CheckpointInterval == 0 {
+		cfg.Storage.MemoryQueue.CheckpointInterval = time.Second
+	}
+	if cfg.Storage.Operations.Timeout == 0 {
+		cfg.Storage.Operations.Timeout = 30 * time.Second
+	}
+	if cfg.Storage.Operations.SlowThreshold == 0 {
+		cfg.Storage.Operations.SlowThreshold = 500 * time.Millisecond
+	}
 
 	// Server defaults
 	if cfg.Server.Host == "" {
@@ -139,6 +1337,21 @@ func applyDefaults(cfg *Config) {
 	if cfg.Server.IdleTimeout == 0 {
 		cfg.Server.IdleTimeout = 120 * time.Second
 	}
+	if cfg.Server.Compression.Level == "" {
+		cfg.Server.Compression.Level = "default"
+	}
+	if len(cfg.Server.Compression.ContentTypes) == 0 {
+		cfg.Server.Compression.ContentTypes = []string{"application/json"}
+	}
+	if len(cfg.Server.CORS.AllowMethods) == 0 {
+		cfg.Server.CORS.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "HEAD"}
+	}
+	if len(cfg.Server.CORS.AllowHeaders) == 0 {
+		cfg.Server.CORS.AllowHeaders = []string{"Content-Type", "If-None-Match"}
+	}
+	if len(cfg.Server.CORS.ExposeHeaders) == 0 {
+		cfg.Server.CORS.ExposeHeaders = []string{"ETag"}
+	}
 
 	// Kafka defaults
 	if len(cfg.Kafka.Brokers) == 0 {
@@ -148,11 +1361,17 @@ func applyDefaults(cfg *Config) {
 		cfg.Kafka.Topic = "argus-events"
 	}
 	if cfg.Kafka.ConsumerGroup == "" {
-		cfg.Kafka.ConsumerGroup = "argus-processor"
+		cfg.Kafka.ConsumerGroup = defaultConsumerGroup
+	}
+	if cfg.Archive.ConsumerGroup == "" {
+		cfg.Archive.ConsumerGroup = cfg.Kafka.ConsumerGroup + "-archive"
 	}
 	if cfg.Kafka.PartitionCount == 0 {
 		cfg.Kafka.PartitionCount = 32
 	}
+	if cfg.Kafka.Commit.Mode == "" {
+		cfg.Kafka.Commit.Mode = KafkaCommitAfterProcessing
+	}
 
 	// Redis defaults
 	if cfg.Redis.Host == "" {
@@ -161,6 +1380,11 @@ func applyDefaults(cfg *Config) {
 	if cfg.Redis.Port == 0 {
 		cfg.Redis.Port = 6379
 	}
+	for i := range cfg.Redis.Shards {
+		if cfg.Redis.Shards[i].Name == "" {
+			cfg.Redis.Shards[i].Name = cfg.Redis.Shards[i].Addr
+		}
+	}
 
 	// Postgres defaults
 	if cfg.Postgres.Host == "" {
@@ -186,6 +1410,334 @@ func applyDefaults(cfg *Config) {
 	if cfg.Logger.Format == "" {
 		cfg.Logger.Format = "json"
 	}
+
+	// Reminder defaults
+	if cfg.Reminders.CheckInterval == 0 {
+		cfg.Reminders.CheckInterval = 30 * time.Second
+	}
+	if cfg.Usage.FlushInterval == 0 {
+		cfg.Usage.FlushInterval = 30 * time.Second
+	}
+	if cfg.Quota.CheckInterval == 0 {
+		cfg.Quota.CheckInterval = 30 * time.Second
+	}
+	if cfg.Retention.CheckInterval == 0 {
+		cfg.Retention.CheckInterval = time.Hour
+	}
+	if cfg.Retention.PartitionsAhead == 0 {
+		cfg.Retention.PartitionsAhead = 2
+	}
+	if cfg.Scrubbing.Stage == "" {
+		cfg.Scrubbing.Stage = ScrubStageIngest
+	}
+	if cfg.Archive.Region == "" {
+		cfg.Archive.Region = "us-east-1"
+	}
+	if cfg.Archive.Endpoint == "" {
+		cfg.Archive.Endpoint = "https://s3." + cfg.Archive.Region + ".amazonaws.com"
+	}
+	if cfg.Archive.Prefix == "" {
+		cfg.Archive.Prefix = "events"
+	}
+	if cfg.Archive.FlushInterval == 0 {
+		cfg.Archive.FlushInterval = time.Minute
+	}
+	if cfg.Archive.BatchSize == 0 {
+		cfg.Archive.BatchSize = 10000
+	}
+	if cfg.Archive.MaxPending == 0 {
+		cfg.Archive.MaxPending = 100000
+	}
+	if cfg.Remediation.Timeout == 0 {
+		cfg.Remediation.Timeout = 10 * time.Second
+	}
+	if cfg.Approvals.Delay == 0 {
+		cfg.Approvals.Delay = 5 * time.Minute
+	}
+	if cfg.Approvals.CheckInterval == 0 {
+		cfg.Approvals.CheckInterval = 10 * time.Second
+	}
+	if cfg.Silences.CheckInterval == 0 {
+		cfg.Silences.CheckInterval = 30 * time.Second
+	}
+	if cfg.TestAlerts.TTL == 0 {
+		cfg.TestAlerts.TTL = 5 * time.Minute
+	}
+	if cfg.TestAlerts.MaxTTL == 0 {
+		cfg.TestAlerts.MaxTTL = time.Hour
+	}
+	if cfg.TestAlerts.CheckInterval == 0 {
+		cfg.TestAlerts.CheckInterval = 30 * time.Second
+	}
+	if cfg.Rules.CheckInterval == 0 {
+		cfg.Rules.CheckInterval = 10 * time.Second
+	}
+	if cfg.Rules.DefaultInterval == 0 {
+		cfg.Rules.DefaultInterval = time.Minute
+	}
+	if cfg.Rules.Timeout == 0 {
+		cfg.Rules.Timeout = 30 * time.Second
+	}
+	if cfg.Rules.Executions == 0 {
+		cfg.Rules.Executions = 100
+	}
+	if cfg.Rules.Sharding.InstanceID == "" {
+		cfg.Rules.Sharding.InstanceID, _ = os.Hostname()
+	}
+	if cfg.Rules.Sharding.HeartbeatTimeout == 0 {
+		cfg.Rules.Sharding.HeartbeatTimeout = 3 * cfg.Rules.CheckInterval
+	}
+	for i := range cfg.Rules.Datasources {
+		if cfg.Rules.Datasources[i].Type == "" {
+			cfg.Rules.Datasources[i].Type = DatasourceTypeLoki
+		}
+		if cfg.Rules.Datasources[i].Type == DatasourceTypeSQL && cfg.Rules.Datasources[i].Driver == "" {
+			cfg.Rules.Datasources[i].Driver = "pgx"
+		}
+	}
+
+	// Processor defaults
+	if cfg.Processor.MaxRetries == 0 {
+		cfg.Processor.MaxRetries = 5
+	}
+	if cfg.Processor.RetryBackoff == 0 {
+		cfg.Processor.RetryBackoff = 100 * time.Millisecond
+	}
+	if cfg.Processor.MaxRetryBackoff == 0 {
+		cfg.Processor.MaxRetryBackoff = 5 * time.Second
+	}
+	if cfg.Processor.StallTimeout == 0 {
+		cfg.Processor.StallTimeout = 2 * time.Minute
+	}
+	if cfg.Processor.ActiveAlertsReconcileInterval == 0 {
+		cfg.Processor.ActiveAlertsReconcileInterval = 5 * time.Minute
+	}
+	if cfg.Processor.PendingResolveSweepInterval == 0 {
+		cfg.Processor.PendingResolveSweepInterval = 5 * time.Minute
+	}
+	if cfg.Processor.Storms.CheckInterval == 0 {
+		cfg.Processor.Storms.CheckInterval = 10 * time.Second
+	}
+	if cfg.Processor.Storms.QuietPeriod == 0 {
+		cfg.Processor.Storms.QuietPeriod = 5 * time.Minute
+	}
+	if cfg.Processor.Scaling.Interval == 0 {
+		cfg.Processor.Scaling.Interval = 15 * time.Second
+	}
+	if cfg.Processor.Scaling.TargetLag == 0 {
+		cfg.Processor.Scaling.TargetLag = 30 * time.Second
+	}
+	if cfg.Processor.Scaling.TargetDrainTime == 0 {
+		cfg.Processor.Scaling.TargetDrainTime = time.Minute
+	}
+
+	// Self-monitoring defaults
+	if cfg.SelfMonitoring.EventManagerID == "" {
+		cfg.SelfMonitoring.EventManagerID = "argus-system"
+	}
+	if cfg.SelfMonitoring.Interval == 0 {
+		cfg.SelfMonitoring.Interval = 30 * time.Second
+	}
+	if cfg.SelfMonitoring.ProcessorLagThreshold == 0 {
+		cfg.SelfMonitoring.ProcessorLagThreshold = time.Minute
+	}
+	if cfg.SelfMonitoring.PoisonMessagesThreshold == 0 {
+		cfg.SelfMonitoring.PoisonMessagesThreshold = 1
+	}
+	if cfg.SelfMonitoring.NotificationFailuresThreshold == 0 {
+		cfg.SelfMonitoring.NotificationFailuresThreshold = 5
+	}
+	if cfg.SelfMonitoring.ChannelFailureRateThreshold == 0 {
+		cfg.SelfMonitoring.ChannelFailureRateThreshold = 0.5
+	}
+	if cfg.SelfMonitoring.ChannelMinDeliveries == 0 {
+		cfg.SelfMonitoring.ChannelMinDeliveries = 5
+	}
+
+	// Notification defaults
+	if cfg.Notification.Dedup.Window == 0 {
+		cfg.Notification.Dedup.Window = time.Minute
+	}
+	if cfg.Notification.Dedup.TTL == 0 {
+		cfg.Notification.Dedup.TTL = 24 * time.Hour
+	}
+	if cfg.Notification.Reports.CheckInterval == 0 {
+		cfg.Notification.Reports.CheckInterval = time.Minute
+	}
+	for i := range cfg.Notification.Plugins {
+		if cfg.Notification.Plugins[i].Timeout == 0 {
+			cfg.Notification.Plugins[i].Timeout = 5 * time.Second
+		}
+	}
+	for i := range cfg.Notification.Queues {
+		q := &cfg.Notification.Queues[i]
+		if q.Type == "" {
+			q.Type = NotificationQueueKafka
+		}
+		if q.Type == NotificationQueueKafka && len(q.Brokers) == 0 {
+			q.Brokers = cfg.Kafka.Brokers
+		}
+	}
+
+	// Source consumer defaults
+	for i := range cfg.SourceConsumers {
+		source := &cfg.SourceConsumers[i]
+		if source.Type == "" {
+			source.Type = SourceTypeKafka
+		}
+		if source.MQTT.ClientID == "" {
+			source.MQTT.ClientID = "argus-source-" + source.Name
+		}
+		if len(source.Brokers) == 0 {
+			source.Brokers = cfg.Kafka.Brokers
+		}
+		if source.ConsumerGroup == "" {
+			source.ConsumerGroup = "argus-source-" + source.Name
+		}
+	}
+
+	// Health defaults
+	if cfg.Health.Interval == 0 {
+		cfg.Health.Interval = 10 * time.Second
+	}
+	if cfg.Health.Timeout == 0 {
+		cfg.Health.Timeout = 2 * time.Second
+	}
+	if cfg.Health.RetryBackoff == 0 {
+		cfg.Health.RetryBackoff = time.Second
+	}
+	if cfg.Health.MaxRetryBackoff == 0 {
+		cfg.Health.MaxRetryBackoff = 30 * time.Second
+	}
+
+	// Ingest defaults
+	if cfg.Ingest.Async.BufferSize <= 0 {
+		cfg.Ingest.Async.BufferSize = 10000
+	}
+	if cfg.Ingest.EventStatusTTL == 0 {
+		cfg.Ingest.EventStatusTTL = time.Hour
+	}
+
+	// Cache defaults
+	if cfg.Cache.TTL == 0 {
+		cfg.Cache.TTL = time.Minute
+	}
+	if cfg.Cache.Alerts.Get == 0 {
+		cfg.Cache.Alerts.Get = 2 * time.Second
+	}
+	if cfg.Cache.Alerts.Children == 0 {
+		cfg.Cache.Alerts.Children = 2 * time.Second
+	}
+
+	// Shutdown defaults
+	if cfg.Shutdown.HTTPTimeout == 0 {
+		cfg.Shutdown.HTTPTimeout = cfg.Server.WriteTimeout
+	}
+	if cfg.Shutdown.ProducerTimeout == 0 {
+		cfg.Shutdown.ProducerTimeout = 10 * time.Second
+	}
+	if cfg.Shutdown.ProcessorTimeout == 0 {
+		cfg.Shutdown.ProcessorTimeout = 10 * time.Second
+	}
+	if cfg.Shutdown.StoreTimeout == 0 {
+		cfg.Shutdown.StoreTimeout = 5 * time.Second
+	}
+	if cfg.Shutdown.MetricsSnapshot.Job == "" {
+		cfg.Shutdown.MetricsSnapshot.Job = "argus"
+	}
+	if cfg.Shutdown.MetricsSnapshot.Timeout == 0 {
+		cfg.Shutdown.MetricsSnapshot.Timeout = 5 * time.Second
+	}
+
+	// SLO defaults
+	if len(cfg.SLO.Objectives) == 0 {
+		cfg.SLO.Objectives = []SLOObjectiveConfig{{
+			Name:        "alert-creation-latency",
+			Description: "99% of alerts created within 5s of ingestion",
+			Target:      0.99,
+			Threshold:   5 * time.Second,
+		}}
+	}
+	for i := range cfg.SLO.Objectives {
+		if cfg.SLO.Objectives[i].Window == 0 {
+			cfg.SLO.Objectives[i].Window = 24 * time.Hour
+		}
+	}
+}
+
+// minAdminTokenLength is the length of the shortest accepted admin token.
+const minAdminTokenLength = 16
+
+// UnixAddressPrefix marks listen addresses of Unix domain sockets.
+const UnixAddressPrefix = "unix:"
+
+// ListenAddresses returns the addresses the API listens on.
+func (c *ServerConfig) ListenAddresses() []string {
+	if len(c.Addresses) == 0 {
+		return []string{c.Address()}
+	}
+	return c.Addresses
+}
+
+// validate checks the listen addresses are valid, the admin listeners don't
+// share an address with the API, and admin tokens aren't trivially short.
+func (c *ServerConfig) validate() error {
+	seen := make(map[string]bool)
+	for _, addresses := range [][]string{c.Addresses, c.AdminAddresses} {
+		for _, address := range addresses {
+			if err := validateListenAddress(address); err != nil {
+				return err
+			}
+			if seen[address] {
+				return fmt.Errorf("address %q is listed twice", address)
+			}
+			seen[address] = true
+		}
+	}
+	for i, token := range c.AdminTokens {
+		if len(token) < minAdminTokenLength {
+			return fmt.Errorf("admin_tokens[%d] must be at least %d characters", i, minAdminTokenLength)
+		}
+	}
+	return nil
+}
+
+// validateListenAddress checks an address is a Unix socket path or a
+// host:port.
+func validateListenAddress(address string) error {
+	if path, ok := strings.CutPrefix(address, UnixAddressPrefix); ok {
+		if path == "" {
+			return fmt.Errorf("address %q: socket path is required", address)
+		}
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return fmt.Errorf("address %q: %w", address, err)
+	}
+	return nil
+}
+
+// validate checks that the CORS settings are safe to apply.
+func (c *CORSConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if len(c.AllowOrigins) == 0 {
+		return errors.New("allow_origins is required")
+	}
+	for _, origin := range c.AllowOrigins {
+		if origin == "*" {
+			if c.AllowCredentials {
+				return errors.New("allow_credentials cannot be used with the \"*\" origin")
+			}
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" {
+			return fmt.Errorf("invalid origin %q: must be scheme://host[:port]", origin)
+		}
+	}
+	return nil
 }
 
 // Address returns the full server address in host:port format.
@@ -205,3 +1757,27 @@ func (c *PostgresConfig) DSN() string {
 func (c *RedisConfig) RedisAddr() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
+
+// ShardList returns the shards of the state store: the configured shards, or
+// the single instance at Host and Port.
+func (c *RedisConfig) ShardList() []RedisShardConfig {
+	if len(c.Shards) > 0 {
+		return c.Shards
+	}
+	return []RedisShardConfig{{Name: c.RedisAddr(), Addr: c.RedisAddr()}}
+}
+
+// validate checks that every shard has an address and a unique name.
+func (c *RedisConfig) validate() error {
+	names := make(map[string]bool)
+	for i, shard := range c.Shards {
+		if shard.Addr == "" {
+			return fmt.Errorf("shards[%d]: addr is required", i)
+		}
+		if names[shard.Name] {
+			return fmt.Errorf("shards[%d]: duplicate name %q", i, shard.Name)
+		}
+		names[shard.Name] = true
+	}
+	return nil
+}
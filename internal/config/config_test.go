@@ -0,0 +1,215 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// load writes a configuration file and loads it.
+func load(t *testing.T, yaml string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+	return Load(path)
+}
+
+func TestLoad_KafkaCommit(t *testing.T) {
+	tests := []struct {
+		name       string
+		yaml       string
+		wantBefore bool
+		wantEvery  time.Duration
+		wantErr    string
+	}{
+		{name: "default", yaml: "kafka: {}"},
+		{name: "after", yaml: "kafka: {commit: {mode: after_processing}}"},
+		{name: "before", yaml: "kafka: {commit: {mode: before_processing}}", wantBefore: true},
+		{name: "interval", yaml: "kafka: {commit: {interval: 5s}}", wantEvery: 5 * time.Second},
+		{name: "unknown mode", yaml: "kafka: {commit: {mode: sometimes}}", wantErr: "commit mode must be"},
+		{name: "negative interval", yaml: "kafka: {commit: {interval: -1s}}", wantErr: "commit interval must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := load(t, tt.yaml)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if got := cfg.Kafka.Commit.CommitBeforeProcessing(); got != tt.wantBefore {
+				t.Errorf("CommitBeforeProcessing() = %v, want %v", got, tt.wantBefore)
+			}
+			if cfg.Kafka.Commit.Interval != tt.wantEvery {
+				t.Errorf("Interval = %v, want %v", cfg.Kafka.Commit.Interval, tt.wantEvery)
+			}
+		})
+	}
+}
+
+func TestLoad_CORS(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{name: "disabled", yaml: "server: {cors: {allow_origins: [not-an-origin]}}"},
+		{name: "origin", yaml: "server: {cors: {enabled: true, allow_origins: ['https://dashboard.example.com:8443']}}"},
+		{name: "any origin", yaml: "server: {cors: {enabled: true, allow_origins: ['*']}}"},
+		{name: "no origins", yaml: "server: {cors: {enabled: true}}", wantErr: "allow_origins is required"},
+		{name: "origin with path", yaml: "server: {cors: {enabled: true, allow_origins: ['https://example.com/ui']}}", wantErr: "invalid origin"},
+		{name: "origin without scheme", yaml: "server: {cors: {enabled: true, allow_origins: [example.com]}}", wantErr: "invalid origin"},
+		{
+			name:    "credentials with any origin",
+			yaml:    "server: {cors: {enabled: true, allow_origins: ['*'], allow_credentials: true}}",
+			wantErr: "allow_credentials cannot be used",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := load(t, tt.yaml)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			// Methods and headers default to what the API and UI use
+			if len(cfg.Server.CORS.AllowMethods) == 0 || len(cfg.Server.CORS.AllowHeaders) == 0 || len(cfg.Server.CORS.ExposeHeaders) == 0 {
+				t.Errorf("CORS = %+v, want default methods and headers", cfg.Server.CORS)
+			}
+		})
+	}
+}
+
+func TestLoad_ShutdownTimeouts(t *testing.T) {
+	cfg, err := load(t, "server: {write_timeout: 7s}\nshutdown: {producer_timeout: 3s}")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// Draining HTTP requests waits as long as a response may take
+	want := ShutdownConfig{
+		HTTPTimeout:      7 * time.Second,
+		ProducerTimeout:  3 * time.Second,
+		ProcessorTimeout: 10 * time.Second,
+		StoreTimeout:     5 * time.Second,
+	}
+	got := cfg.Shutdown
+	got.MetricsSnapshot = MetricsSnapshotConfig{}
+	if got != want {
+		t.Errorf("Shutdown = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoad_ProcessorShadow(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{name: "memory", yaml: "processor: {shadow: true}"},
+		{name: "storage with own group", yaml: "storage: {mode: storage}\nkafka: {consumer_group: argus-shadow}\nprocessor: {shadow: true}"},
+		{name: "storage with default group", yaml: "storage: {mode: storage}\nprocessor: {shadow: true}", wantErr: true},
+		{name: "live with default group", yaml: "storage: {mode: storage}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := load(t, tt.yaml)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "shadow mode requires a kafka.consumer_group") {
+					t.Fatalf("Load() error = %v, want the shadow consumer group error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestLoad_MQTTSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{name: "mqtt", source: "{name: edge, type: mqtt, mqtt: {broker: 'tcp://mqtt:1883', topics: [devices/+/alerts], qos: 1}}"},
+		{name: "kafka by default", source: "{name: legacy, topic: alerts}"},
+		{name: "no broker", source: "{name: edge, type: mqtt, mqtt: {topics: [devices/+/alerts]}}", wantErr: "mqtt.broker and mqtt.topics are required"},
+		{name: "no topics", source: "{name: edge, type: mqtt, mqtt: {broker: 'tcp://mqtt:1883'}}", wantErr: "mqtt.broker and mqtt.topics are required"},
+		{name: "bad qos", source: "{name: edge, type: mqtt, mqtt: {broker: 'tcp://mqtt:1883', topics: [a], qos: 3}}", wantErr: "mqtt.qos must be 0, 1 or 2"},
+		{name: "unknown type", source: "{name: edge, type: amqp}", wantErr: `unknown type "amqp"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := load(t, "source_consumers: ["+tt.source+"]")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			// Sources default to Kafka, and MQTT clients are named after them
+			source := cfg.SourceConsumers[0]
+			if source.Type == "" {
+				t.Error("Type is empty, want a default")
+			}
+			if want := "argus-source-" + source.Name; source.MQTT.ClientID != want {
+				t.Errorf("MQTT.ClientID = %q, want %q", source.MQTT.ClientID, want)
+			}
+		})
+	}
+}
+
+func TestLoad_ServerAddresses(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    []string
+		wantErr string
+	}{
+		{name: "host and port", yaml: "server: {host: 127.0.0.1, port: 9090}", want: []string{"127.0.0.1:9090"}},
+		{name: "addresses", yaml: "server: {addresses: ['[::1]:8080', 'unix:/run/argus.sock']}", want: []string{"[::1]:8080", "unix:/run/argus.sock"}},
+		{name: "admin addresses", yaml: "server: {addresses: [':8080'], admin_addresses: [':9090']}", want: []string{":8080"}},
+		{name: "no port", yaml: "server: {addresses: [localhost]}", wantErr: `address "localhost"`},
+		{name: "no socket path", yaml: "server: {addresses: ['unix:']}", wantErr: "socket path is required"},
+		{name: "bad admin address", yaml: "server: {admin_addresses: [localhost]}", wantErr: `address "localhost"`},
+		{name: "shared with admin", yaml: "server: {addresses: [':8080'], admin_addresses: [':8080']}", wantErr: `address ":8080" is listed twice`},
+		{name: "short admin token", yaml: "server: {admin_tokens: [secret]}", wantErr: "admin_tokens[0] must be at least 16 characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := load(t, tt.yaml)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if got := cfg.Server.ListenAddresses(); !slices.Equal(got, tt.want) {
+				t.Errorf("ListenAddresses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+// Package declarative exports and applies the ArgusGo configuration (grouping
+// rules and event managers with their notification settings) as a single YAML
+// document, so it can be kept in version control and applied GitOps style.
+package declarative
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"argus-go/internal/domain"
+)
+
+// DocumentVersion is the version of the document format.
+const DocumentVersion = 1
+
+// ErrInvalidDocument is returned for documents that cannot be applied.
+var ErrInvalidDocument = errors.New("invalid config document")
+
+// Document is the declarative form of the configuration.
+// Resources are identified by ID, so applying a document again is a no-op.
+type Document struct {
+	Version       int            `yaml:"version"`
+	GroupingRules []GroupingRule `yaml:"grouping_rules"`
+	EventManagers []EventManager `yaml:"event_managers"`
+}
+
+// GroupingRule is the declarative form of a domain.GroupingRule.
+type GroupingRule struct {
+	ID                string          `yaml:"id"`
+	Name              string          `yaml:"name"`
+	GroupingKey       string          `yaml:"grouping_key"`
+	TimeWindow        domain.Duration `yaml:"time_window,omitempty"`
+	TimeWindowMinutes int             `yaml:"time_window_minutes,omitempty"`
+	ValueTemplate     string          `yaml:"value_template,omitempty"`
+	ValuePattern      string          `yaml:"value_pattern,omitempty"`
+	Runbook           domain.Runbook  `yaml:"runbook,omitempty"`
+}
+
+// timeWindow returns the window of the rule: TimeWindow if set, otherwise
+// TimeWindowMinutes, which documents written before time_window still use.
+func (r *GroupingRule) timeWindow() time.Duration {
+	if r.TimeWindow != 0 {
+		return time.Duration(r.TimeWindow)
+	}
+	return time.Duration(r.TimeWindowMinutes) * time.Minute
+}
+
+// EventManager is the declarative form of a domain.EventManager.
+type EventManager struct {
+	ID                      string                       `yaml:"id"`
+	Name                    string                       `yaml:"name"`
+	Description             string                       `yaml:"description,omitempty"`
+	GroupingRuleID          string                       `yaml:"grouping_rule_id,omitempty"`
+	GroupingRules           []domain.GroupingRuleBinding `yaml:"grouping_rules,omitempty"`
+	CandidateGroupingRuleID string                       `yaml:"candidate_grouping_rule_id,omitempty"`
+	GroupingDisabled        bool                         `yaml:"grouping_disabled,omitempty"`
+	DedupKeyConfig          domain.DedupKeyConfig        `yaml:"dedup_key_config,omitempty"`
+	EventDefaults           domain.EventDefaults         `yaml:"event_defaults,omitempty"`
+	WebhookTransform        domain.WebhookTransform      `yaml:"webhook_transform,omitempty"`
+	Quota                   domain.Quota                 `yaml:"quota,omitempty"`
+	Sampling                domain.SamplingPolicy        `yaml:"sampling,omitempty"`
+	Storm                   domain.StormPolicy           `yaml:"storm,omitempty"`
+	Topic                   string                       `yaml:"topic,omitempty"`
+	Runbooks                domain.RunbookConfig         `yaml:"runbooks,omitempty"`
+	Remediations            []domain.RemediationAction   `yaml:"remediations,omitempty"`
+	NotificationConfig      domain.NotificationConfig    `yaml:"notification_config,omitempty"`
+	ResolutionPolicy        domain.ResolutionPolicy      `yaml:"resolution_policy,omitempty"`
+	TagPolicy               domain.TagPolicy             `yaml:"tag_policy,omitempty"`
+	ParentSummary           domain.ParentSummaryPolicy   `yaml:"parent_summary,omitempty"`
+	Locale                  string                       `yaml:"locale,omitempty"`
+	WebhookSignatures       domain.WebhookSignatures     `yaml:"webhook_signatures,omitempty"`
+	Links                   []domain.AlertLinkTemplate   `yaml:"links,omitempty"`
+}
+
+// Parse decodes a YAML document, rejecting unknown fields so typos are not
+// silently ignored, and validates it.
+func Parse(data []byte) (*Document, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	var doc Document
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// Validate checks the document on its own: its version, that every resource
+// has a unique ID and that every resource is valid. References to grouping
+// rules outside the document are checked when it is applied.
+func (d *Document) Validate() error {
+	if d.Version != DocumentVersion {
+		return fmt.Errorf("%w: version must be %d", ErrInvalidDocument, DocumentVersion)
+	}
+
+	ruleIDs := make(map[string]bool, len(d.GroupingRules))
+	for i := range d.GroupingRules {
+		rule := &d.GroupingRules[i]
+		if err := checkID(rule.ID, ruleIDs); err != nil {
+			return fmt.Errorf("%w: grouping_rules[%d]: %v", ErrInvalidDocument, i, err)
+		}
+		req := rule.createRequest()
+		if err := req.Validate(); err != nil {
+			return fmt.Errorf("%w: grouping rule %s: %v", ErrInvalidDocument, rule.ID, err)
+		}
+	}
+
+	emIDs := make(map[string]bool, len(d.EventManagers))
+	for i := range d.EventManagers {
+		em := &d.EventManagers[i]
+		if err := checkID(em.ID, emIDs); err != nil {
+			return fmt.Errorf("%w: event_managers[%d]: %v", ErrInvalidDocument, i, err)
+		}
+		req := em.createRequest()
+		if err := req.Validate(); err != nil {
+			return fmt.Errorf("%w: event manager %s: %v", ErrInvalidDocument, em.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// checkID checks a resource ID is usable and not already in seen, then adds it.
+func checkID(id string, seen map[string]bool) error {
+	if err := domain.ValidateResourceID(id); err != nil {
+		return err
+	}
+	if seen[id] {
+		return fmt.Errorf("duplicate id %q", id)
+	}
+	seen[id] = true
+	return nil
+}
+
+// Marshal encodes the document as YAML.
+func (d *Document) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(d); err != nil {
+		return nil, fmt.Errorf("failed to encode config document: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode config document: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fromGroupingRule converts a grouping rule to its declarative form.
+func fromGroupingRule(rule *domain.GroupingRule) GroupingRule {
+	return GroupingRule{
+		ID:            rule.ID,
+		Name:          rule.Name,
+		GroupingKey:   rule.GroupingKey,
+		TimeWindow:    domain.Duration(rule.TimeWindow()),
+		ValueTemplate: rule.ValueTemplate,
+		ValuePattern:  rule.ValuePattern,
+		Runbook:       rule.Runbook,
+	}
+}
+
+// createRequest returns the API request that creates the grouping rule.
+func (r *GroupingRule) createRequest() domain.CreateGroupingRuleRequest {
+	return domain.CreateGroupingRuleRequest{
+		Name:              r.Name,
+		GroupingKey:       r.GroupingKey,
+		TimeWindow:        r.TimeWindow,
+		TimeWindowMinutes: r.TimeWindowMinutes,
+		ValueTemplate:     r.ValueTemplate,
+		ValuePattern:      r.ValuePattern,
+		Runbook:           r.Runbook,
+	}
+}
+
+// updateRequest returns the API request that updates the grouping rule.
+func (r *GroupingRule) updateRequest() domain.UpdateGroupingRuleRequest {
+	return domain.UpdateGroupingRuleRequest{
+		Name:              r.Name,
+		GroupingKey:       r.GroupingKey,
+		TimeWindow:        r.TimeWindow,
+		TimeWindowMinutes: r.TimeWindowMinutes,
+		ValueTemplate:     r.ValueTemplate,
+		ValuePattern:      r.ValuePattern,
+		Runbook:           r.Runbook,
+	}
+}
+
+// fromEventManager converts an event manager to its declarative form.
+func fromEventManager(em *domain.EventManager) EventManager {
+	var bindings []domain.GroupingRuleBinding
+	if len(em.GroupingRules) > 0 {
+		bindings = em.GroupingRules
+	}
+	var remediations []domain.RemediationAction
+	if len(em.Remediations) > 0 {
+		remediations = em.Remediations
+	}
+	var links []domain.AlertLinkTemplate
+	if len(em.Links) > 0 {
+		links = em.Links
+	}
+	return EventManager{
+		ID:                      em.ID,
+		Name:                    em.Name,
+		Description:             em.Description,
+		GroupingRuleID:          em.GroupingRuleID,
+		GroupingRules:           bindings,
+		CandidateGroupingRuleID: em.CandidateGroupingRuleID,
+		GroupingDisabled:        em.GroupingDisabled,
+		DedupKeyConfig:          em.DedupKeyConfig,
+		EventDefaults:           em.EventDefaults,
+		WebhookTransform:        em.WebhookTransform,
+		Quota:                   em.Quota,
+		Sampling:                em.Sampling,
+		Storm:                   em.Storm,
+		Topic:                   em.Topic,
+		Runbooks:                em.Runbooks,
+		Remediations:            remediations,
+		NotificationConfig:      em.NotificationConfig,
+		ResolutionPolicy:        em.ResolutionPolicy,
+		TagPolicy:               em.TagPolicy,
+		ParentSummary:           em.ParentSummary,
+		Locale:                  em.Locale,
+		WebhookSignatures:       em.WebhookSignatures,
+		Links:                   links,
+	}
+}
+
+// createRequest returns the API request that creates the event manager.
+func (e *EventManager) createRequest() domain.CreateEventManagerRequest {
+	return domain.CreateEventManagerRequest{
+		Name:                    e.Name,
+		Description:             e.Description,
+		GroupingRuleID:          e.GroupingRuleID,
+		GroupingRules:           e.GroupingRules,
+		CandidateGroupingRuleID: e.CandidateGroupingRuleID,
+		GroupingDisabled:        e.GroupingDisabled,
+		DedupKeyConfig:          e.DedupKeyConfig,
+		EventDefaults:           e.EventDefaults,
+		WebhookTransform:        e.WebhookTransform,
+		Quota:                   e.Quota,
+		Sampling:                e.Sampling,
+		Storm:                   e.Storm,
+		Topic:                   e.Topic,
+		Runbooks:                e.Runbooks,
+		Remediations:            e.Remediations,
+		NotificationConfig:      e.NotificationConfig,
+		ResolutionPolicy:        e.ResolutionPolicy,
+		TagPolicy:               e.TagPolicy,
+		ParentSummary:           e.ParentSummary,
+		Locale:                  e.Locale,
+		WebhookSignatures:       e.WebhookSignatures,
+		Links:                   e.Links,
+	}
+}
+
+// updateRequest returns the API request that updates the event manager.
+func (e *EventManager) updateRequest() domain.UpdateEventManagerRequest {
+	return domain.UpdateEventManagerRequest{
+		Name:                    e.Name,
+		Description:             e.Description,
+		GroupingRuleID:          e.GroupingRuleID,
+		GroupingRules:           e.GroupingRules,
+		CandidateGroupingRuleID: e.CandidateGroupingRuleID,
+		GroupingDisabled:        e.GroupingDisabled,
+		DedupKeyConfig:          e.DedupKeyConfig,
+		EventDefaults:           e.EventDefaults,
+		WebhookTransform:        e.WebhookTransform,
+		Quota:                   e.Quota,
+		Sampling:                e.Sampling,
+		Storm:                   e.Storm,
+		Topic:                   e.Topic,
+		Runbooks:                e.Runbooks,
+		Remediations:            e.Remediations,
+		NotificationConfig:      e.NotificationConfig,
+		ResolutionPolicy:        e.ResolutionPolicy,
+		TagPolicy:               e.TagPolicy,
+		ParentSummary:           e.ParentSummary,
+		Locale:                  e.Locale,
+		WebhookSignatures:       e.WebhookSignatures,
+		Links:                   e.Links,
+	}
+}
@@ -0,0 +1,342 @@
+package declarative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"reflect"
+	"sort"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// ErrDeletedResource is returned when a document declares a resource that has
+// been soft-deleted. It must be purged before its ID can be reused.
+var ErrDeletedResource = errors.New("resource has been deleted")
+
+// Resource kinds reported in a Plan.
+const (
+	KindGroupingRule = "grouping_rule"
+	KindEventManager = "event_manager"
+)
+
+// ChangeAction is what applying a document does to a resource.
+type ChangeAction string
+
+const (
+	ChangeCreate    ChangeAction = "create"
+	ChangeUpdate    ChangeAction = "update"
+	ChangeUnchanged ChangeAction = "unchanged"
+)
+
+// Change describes the effect of a document on one resource.
+type Change struct {
+	Kind   string       `json:"kind"`
+	ID     string       `json:"id"`
+	Name   string       `json:"name"`
+	Action ChangeAction `json:"action"`
+
+	// Fields lists the fields that differ, for updates.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Plan is the diff between a document and the stored configuration.
+type Plan struct {
+	DryRun  bool     `json:"dry_run"`
+	Changes []Change `json:"changes"`
+}
+
+// Service exports the configuration as a Document and applies documents.
+type Service struct {
+	eventManagerRepo store.EventManagerRepository
+	groupingRuleRepo store.GroupingRuleRepository
+	logger           *slog.Logger
+}
+
+// NewService creates a new declarative configuration service.
+func NewService(
+	eventManagerRepo store.EventManagerRepository,
+	groupingRuleRepo store.GroupingRuleRepository,
+	logger *slog.Logger,
+) *Service {
+	return &Service{
+		eventManagerRepo: eventManagerRepo,
+		groupingRuleRepo: groupingRuleRepo,
+		logger:           logger,
+	}
+}
+
+// Export returns the current configuration: every grouping rule and event
+// manager that is not deleted, sorted by name for stable diffs.
+func (s *Service) Export(ctx context.Context) (*Document, error) {
+	rules, err := s.groupingRuleRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list grouping rules: %w", err)
+	}
+	managers, err := s.eventManagerRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list event managers: %w", err)
+	}
+
+	doc := &Document{
+		Version:       DocumentVersion,
+		GroupingRules: make([]GroupingRule, 0, len(rules)),
+		EventManagers: make([]EventManager, 0, len(managers)),
+	}
+	for _, rule := range rules {
+		doc.GroupingRules = append(doc.GroupingRules, fromGroupingRule(rule))
+	}
+	for _, em := range managers {
+		doc.EventManagers = append(doc.EventManagers, fromEventManager(em))
+	}
+
+	sort.Slice(doc.GroupingRules, func(i, j int) bool {
+		return lessByName(doc.GroupingRules[i].Name, doc.GroupingRules[i].ID, doc.GroupingRules[j].Name, doc.GroupingRules[j].ID)
+	})
+	sort.Slice(doc.EventManagers, func(i, j int) bool {
+		return lessByName(doc.EventManagers[i].Name, doc.EventManagers[i].ID, doc.EventManagers[j].Name, doc.EventManagers[j].ID)
+	})
+
+	return doc, nil
+}
+
+// lessByName orders resources by name, then ID.
+func lessByName(nameA, idA, nameB, idB string) bool {
+	if nameA != nameB {
+		return nameA < nameB
+	}
+	return idA < idB
+}
+
+// Apply creates and updates resources so the stored configuration matches the
+// document, grouping rules first so event managers can reference new rules.
+// Resources missing from the document are left alone. With dryRun, only the
+// plan is computed. The document must have been validated.
+//
+// Changes are not applied atomically: if a write fails, the resources before
+// it in the plan have already been changed, and applying again resumes.
+func (s *Service) Apply(ctx context.Context, doc *Document, dryRun bool) (*Plan, error) {
+	plan := &Plan{DryRun: dryRun, Changes: []Change{}}
+
+	existingRules := make(map[string]*domain.GroupingRule, len(doc.GroupingRules))
+	for i := range doc.GroupingRules {
+		spec := &doc.GroupingRules[i]
+		existing, err := s.groupingRuleRepo.GetByID(ctx, spec.ID)
+		if err != nil && !errors.Is(err, domain.ErrGroupingRuleNotFound) {
+			return nil, fmt.Errorf("failed to get grouping rule %s: %w", spec.ID, err)
+		}
+		if existing != nil && existing.IsDeleted() {
+			return nil, fmt.Errorf("%w: grouping rule %s", ErrDeletedResource, spec.ID)
+		}
+		existingRules[spec.ID] = existing
+		plan.Changes = append(plan.Changes, diffGroupingRule(spec, existing))
+	}
+
+	existingManagers := make(map[string]*domain.EventManager, len(doc.EventManagers))
+	for i := range doc.EventManagers {
+		spec := &doc.EventManagers[i]
+		if err := s.checkReferences(ctx, doc, spec); err != nil {
+			return nil, err
+		}
+
+		existing, err := s.eventManagerRepo.GetByID(ctx, spec.ID)
+		if err != nil && !errors.Is(err, domain.ErrEventManagerNotFound) {
+			return nil, fmt.Errorf("failed to get event manager %s: %w", spec.ID, err)
+		}
+		if existing != nil && existing.IsDeleted() {
+			return nil, fmt.Errorf("%w: event manager %s", ErrDeletedResource, spec.ID)
+		}
+		existingManagers[spec.ID] = existing
+		plan.Changes = append(plan.Changes, diffEventManager(spec, existing))
+	}
+
+	if dryRun {
+		return plan, nil
+	}
+
+	for i := range doc.GroupingRules {
+		if err := s.applyGroupingRule(ctx, &doc.GroupingRules[i], existingRules[doc.GroupingRules[i].ID]); err != nil {
+			return nil, err
+		}
+	}
+	for i := range doc.EventManagers {
+		if err := s.applyEventManager(ctx, &doc.EventManagers[i], existingManagers[doc.EventManagers[i].ID]); err != nil {
+			return nil, err
+		}
+	}
+
+	s.logger.Info("applied config document",
+		"grouping_rules", len(doc.GroupingRules),
+		"event_managers", len(doc.EventManagers),
+	)
+	return plan, nil
+}
+
+// checkReferences verifies that every grouping rule an event manager references
+// is declared in the document or exists and is not deleted.
+func (s *Service) checkReferences(ctx context.Context, doc *Document, spec *EventManager) error {
+	em := domain.EventManager{
+		GroupingRuleID:          spec.GroupingRuleID,
+		GroupingRules:           spec.GroupingRules,
+		CandidateGroupingRuleID: spec.CandidateGroupingRuleID,
+	}
+	for _, id := range em.GroupingRuleIDs() {
+		if doc.declaresGroupingRule(id) {
+			continue
+		}
+		rule, err := s.groupingRuleRepo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, domain.ErrGroupingRuleNotFound) {
+				return fmt.Errorf("%w: event manager %s: grouping rule %s not found", ErrInvalidDocument, spec.ID, id)
+			}
+			return fmt.Errorf("failed to get grouping rule %s: %w", id, err)
+		}
+		if rule.IsDeleted() {
+			return fmt.Errorf("%w: event manager %s: grouping rule %s has been deleted", ErrInvalidDocument, spec.ID, id)
+		}
+	}
+	return nil
+}
+
+// declaresGroupingRule returns true if the document contains the grouping rule.
+func (d *Document) declaresGroupingRule(id string) bool {
+	for i := range d.GroupingRules {
+		if d.GroupingRules[i].ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// applyGroupingRule creates or updates a grouping rule if it differs.
+func (s *Service) applyGroupingRule(ctx context.Context, spec *GroupingRule, existing *domain.GroupingRule) error {
+	if existing == nil {
+		req := spec.createRequest()
+		if err := s.groupingRuleRepo.Create(ctx, req.ToGroupingRule(spec.ID)); err != nil {
+			return fmt.Errorf("failed to create grouping rule %s: %w", spec.ID, err)
+		}
+		return nil
+	}
+	current := fromGroupingRule(existing)
+	if len(groupingRuleFields(&current, spec)) == 0 {
+		return nil
+	}
+
+	req := spec.updateRequest()
+	req.ApplyTo(existing)
+	if err := s.groupingRuleRepo.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update grouping rule %s: %w", spec.ID, err)
+	}
+	return nil
+}
+
+// applyEventManager creates or updates an event manager if it differs.
+func (s *Service) applyEventManager(ctx context.Context, spec *EventManager, existing *domain.EventManager) error {
+	if existing == nil {
+		req := spec.createRequest()
+		if err := s.eventManagerRepo.Create(ctx, req.ToEventManager(spec.ID)); err != nil {
+			return fmt.Errorf("failed to create event manager %s: %w", spec.ID, err)
+		}
+		return nil
+	}
+	current := fromEventManager(existing)
+	if len(eventManagerFields(&current, spec)) == 0 {
+		return nil
+	}
+
+	req := spec.updateRequest()
+	req.ApplyTo(existing)
+	if err := s.eventManagerRepo.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update event manager %s: %w", spec.ID, err)
+	}
+	return nil
+}
+
+// diffGroupingRule describes the change a grouping rule spec makes.
+func diffGroupingRule(spec *GroupingRule, existing *domain.GroupingRule) Change {
+	change := Change{Kind: KindGroupingRule, ID: spec.ID, Name: spec.Name, Action: ChangeCreate}
+	if existing == nil {
+		return change
+	}
+
+	current := fromGroupingRule(existing)
+	change.Fields = groupingRuleFields(&current, spec)
+	change.Action = actionFor(change.Fields)
+	return change
+}
+
+// groupingRuleFields returns the fields in which two grouping rules differ.
+func groupingRuleFields(current, spec *GroupingRule) []string {
+	return changedFields(map[string]bool{
+		"name":           current.Name != spec.Name,
+		"grouping_key":   current.GroupingKey != spec.GroupingKey,
+		"time_window":    current.timeWindow() != spec.timeWindow(),
+		"value_template": current.ValueTemplate != spec.ValueTemplate,
+		"value_pattern":  current.ValuePattern != spec.ValuePattern,
+		"runbook":        !current.Runbook.Equal(&spec.Runbook),
+	})
+}
+
+// diffEventManager describes the change an event manager spec makes.
+func diffEventManager(spec *EventManager, existing *domain.EventManager) Change {
+	change := Change{Kind: KindEventManager, ID: spec.ID, Name: spec.Name, Action: ChangeCreate}
+	if existing == nil {
+		return change
+	}
+
+	current := fromEventManager(existing)
+	change.Fields = eventManagerFields(&current, spec)
+	change.Action = actionFor(change.Fields)
+	return change
+}
+
+// eventManagerFields returns the fields in which two event managers differ.
+func eventManagerFields(current, spec *EventManager) []string {
+	return changedFields(map[string]bool{
+		"name":                       current.Name != spec.Name,
+		"description":                current.Description != spec.Description,
+		"grouping_rule_id":           current.GroupingRuleID != spec.GroupingRuleID,
+		"grouping_rules":             !reflect.DeepEqual(current.GroupingRules, spec.GroupingRules),
+		"candidate_grouping_rule_id": current.CandidateGroupingRuleID != spec.CandidateGroupingRuleID,
+		"grouping_disabled":          current.GroupingDisabled != spec.GroupingDisabled,
+		"dedup_key_config":           current.DedupKeyConfig != spec.DedupKeyConfig,
+		"event_defaults":             current.EventDefaults != spec.EventDefaults,
+		"webhook_transform":          current.WebhookTransform != spec.WebhookTransform,
+		"quota":                      current.Quota != spec.Quota,
+		"sampling":                   current.Sampling != spec.Sampling,
+		"storm":                      current.Storm != spec.Storm,
+		"topic":                      current.Topic != spec.Topic,
+		"runbooks":                   !current.Runbooks.Equal(&spec.Runbooks),
+		"remediations":               !reflect.DeepEqual(current.Remediations, spec.Remediations),
+		"notification_config":        current.NotificationConfig != spec.NotificationConfig,
+		"resolution_policy":          current.ResolutionPolicy != spec.ResolutionPolicy,
+		"tag_policy":                 current.TagPolicy != spec.TagPolicy,
+		"parent_summary":             current.ParentSummary != spec.ParentSummary,
+		"locale":                     current.Locale != spec.Locale,
+		"webhook_signatures":         !maps.Equal(current.WebhookSignatures, spec.WebhookSignatures),
+		"links":                      !reflect.DeepEqual(current.Links, spec.Links),
+	})
+}
+
+// changedFields returns the sorted names of the fields marked as changed.
+func changedFields(fields map[string]bool) []string {
+	var changed []string
+	for name, differs := range fields {
+		if differs {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// actionFor returns the action for an existing resource with the given changed fields.
+func actionFor(fields []string) ChangeAction {
+	if len(fields) == 0 {
+		return ChangeUnchanged
+	}
+	return ChangeUpdate
+}
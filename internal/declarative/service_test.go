@@ -0,0 +1,139 @@
+package declarative
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"argus-go/internal/domain"
+	storemem "argus-go/internal/store/memory"
+)
+
+const testDocument = `
+version: 1
+grouping_rules:
+  - id: by-class
+    name: By class
+    grouping_key: class
+    time_window_minutes: 5
+event_managers:
+  - id: payments
+    name: Payments
+    grouping_rule_id: by-class
+    dedup_key_config:
+      trim: true
+    notification_config:
+      webhook_url: https://hooks.example.com/payments
+`
+
+func newTestService() (*Service, *storemem.EventManagerRepository, *storemem.GroupingRuleRepository) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	emRepo := storemem.NewEventManagerRepository()
+	grRepo := storemem.NewGroupingRuleRepository()
+	return NewService(emRepo, grRepo, logger), emRepo, grRepo
+}
+
+func actions(plan *Plan) []ChangeAction {
+	var result []ChangeAction
+	for _, change := range plan.Changes {
+		result = append(result, change.Action)
+	}
+	return result
+}
+
+func TestService_ApplyAndExport(t *testing.T) {
+	service, emRepo, _ := newTestService()
+	ctx := context.Background()
+
+	doc, err := Parse([]byte(testDocument))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	// A dry run reports the changes without making them
+	plan, err := service.Apply(ctx, doc, true)
+	if err != nil {
+		t.Fatalf("Apply(dry run) error: %v", err)
+	}
+	if got := actions(plan); len(got) != 2 || got[0] != ChangeCreate || got[1] != ChangeCreate {
+		t.Errorf("dry run actions = %v, want [create create]", got)
+	}
+	if _, err := emRepo.GetByID(ctx, "payments"); !errors.Is(err, domain.ErrEventManagerNotFound) {
+		t.Fatalf("dry run created the event manager (GetByID error = %v)", err)
+	}
+
+	if _, err := service.Apply(ctx, doc, false); err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+	em, err := emRepo.GetByID(ctx, "payments")
+	if err != nil {
+		t.Fatalf("GetByID error: %v", err)
+	}
+	if em.GroupingRuleID != "by-class" || !em.DedupKeyConfig.Trim {
+		t.Errorf("event manager = %+v, want the declared configuration", em)
+	}
+
+	// Exporting and applying the export again changes nothing
+	exported, err := service.Export(ctx)
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	data, err := exported.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	reparsed, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse(export) error: %v\n%s", err, data)
+	}
+	plan, err = service.Apply(ctx, reparsed, false)
+	if err != nil {
+		t.Fatalf("Apply(export) error: %v", err)
+	}
+	if got := actions(plan); len(got) != 2 || got[0] != ChangeUnchanged || got[1] != ChangeUnchanged {
+		t.Errorf("re-apply actions = %v, want [unchanged unchanged]", got)
+	}
+
+	// Changed fields are reported
+	reparsed.EventManagers[0].Description = "Payments team"
+	plan, err = service.Apply(ctx, reparsed, false)
+	if err != nil {
+		t.Fatalf("Apply(update) error: %v", err)
+	}
+	change := plan.Changes[1]
+	if change.Action != ChangeUpdate || len(change.Fields) != 1 || change.Fields[0] != "description" {
+		t.Errorf("update change = %+v, want an update of description", change)
+	}
+}
+
+func TestService_Apply_UnknownGroupingRule(t *testing.T) {
+	service, _, _ := newTestService()
+
+	doc := &Document{
+		Version:       DocumentVersion,
+		EventManagers: []EventManager{{ID: "payments", Name: "Payments", GroupingRuleID: "missing"}},
+	}
+	if _, err := service.Apply(context.Background(), doc, false); !errors.Is(err, ErrInvalidDocument) {
+		t.Errorf("Apply error = %v, want %v", err, ErrInvalidDocument)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":  "version: 1\ngrouping_rulez: []\n",
+		"wrong version":  "version: 2\n",
+		"missing id":     "version: 1\ngrouping_rules:\n  - name: r\n    grouping_key: class\n    time_window_minutes: 5\n",
+		"duplicate id":   "version: 1\nevent_managers:\n  - {id: a, name: A}\n  - {id: a, name: B}\n",
+		"invalid window": "version: 1\ngrouping_rules:\n  - {id: r, name: r, grouping_key: class, time_window_minutes: 0}\n",
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(data)); !errors.Is(err, ErrInvalidDocument) {
+				t.Errorf("Parse error = %v, want %v", err, ErrInvalidDocument)
+			}
+		})
+	}
+}
@@ -1,12 +1,20 @@
 package domain
 
 import (
-	"errors"
+	"cmp"
+	"maps"
+	"slices"
 	"time"
 )
 
-// ErrAlertNotFound is returned when an alert cannot be found.
-var ErrAlertNotFound = errors.New("alert not found")
+// Errors returned for alert operations.
+var (
+	ErrAlertNotFound        = notFoundError("alert not found")
+	ErrAlertAlreadyExists   = conflictError("alert already exists")
+	ErrAlertAlreadyResolved = transitionError("alert is already resolved")
+	ErrNotParentAlert       = invalidError("alert is not a parent alert")
+	ErrInvalidAlertSort     = invalidError("sort must be created_at, updated_at, severity or child_count, prefixed with - for descending order")
+)
 
 // AlertType indicates whether an alert is a parent or child in the grouping hierarchy.
 type AlertType string
@@ -26,8 +34,84 @@ const (
 	AlertStatusActive AlertStatus = "active"
 	// AlertStatusResolved indicates the alert has been resolved.
 	AlertStatusResolved AlertStatus = "resolved"
+
+	// AlertStatusSilenced and AlertStatusSuppressed are the statuses active
+	// alerts are listed with while their notifications are muted, by a
+	// silence or by the quota of their event manager. They are not stored:
+	// muting is decided when alerts are read, so an alert is listed active
+	// again once the silence ends.
+	AlertStatusSilenced   AlertStatus = "silenced"
+	AlertStatusSuppressed AlertStatus = "suppressed"
+)
+
+// IsMuted returns true for the statuses of active alerts whose
+// notifications are muted.
+func (s AlertStatus) IsMuted() bool {
+	return s == AlertStatusSilenced || s == AlertStatusSuppressed
+}
+
+// ResolutionSource identifies the path through which an alert was resolved.
+type ResolutionSource string
+
+const (
+	// ResolvedByEvent indicates a resolve event sent by the monitoring source.
+	ResolvedByEvent ResolutionSource = "event"
+	// ResolvedByAPI indicates an API operation, such as deleting the event manager.
+	ResolvedByAPI ResolutionSource = "api"
+	// ResolvedByAutoTTL indicates the alert expired without further events,
+	// as test alerts do once their TTL passes.
+	ResolvedByAutoTTL ResolutionSource = "auto-ttl"
+	// ResolvedByImport indicates a historical alert imported already resolved.
+	ResolvedByImport ResolutionSource = "import"
+	// ResolvedByPendingTimeout indicates a parent alert force-resolved after
+	// its resolve waited too long for its children, or a child resolved
+	// with it.
+	ResolvedByPendingTimeout ResolutionSource = "pending-timeout"
 )
 
+// Resolution records how, by whom and why an alert was resolved, for
+// post-incident reviews.
+type Resolution struct {
+	// ResolvedBy is the resolution path.
+	ResolvedBy ResolutionSource `json:"resolved_by"`
+
+	// Actor identifies who resolved the alert, e.g. the source of the resolve event.
+	Actor string `json:"actor,omitempty"`
+
+	// Reason is a free-text explanation.
+	Reason string `json:"reason,omitempty"`
+}
+
+// EventResolution returns the resolution of an alert resolved by a resolve
+// event: the event source is the actor and its summary the reason. The
+// resolve events of expired test alerts resolve them as auto-ttl.
+func EventResolution(event *Event) Resolution {
+	resolvedBy := ResolvedByEvent
+	if event.IsTestAlert() {
+		resolvedBy = ResolvedByAutoTTL
+	}
+	return Resolution{
+		ResolvedBy: resolvedBy,
+		Actor:      event.Source,
+		Reason:     event.Summary,
+	}
+}
+
+// ForceResolveRequest is the optional body of a force-resolve of a parent
+// alert and its children through the API.
+type ForceResolveRequest struct {
+	// Actor identifies the operator resolving the alerts.
+	Actor string `json:"actor"`
+
+	// Reason explains why the whole group is resolved.
+	Reason string `json:"reason"`
+}
+
+// Resolution returns the resolution recorded on the force-resolved alerts.
+func (r *ForceResolveRequest) Resolution() Resolution {
+	return Resolution{ResolvedBy: ResolvedByAPI, Actor: r.Actor, Reason: r.Reason}
+}
+
 // Alert represents a processed alert in the system.
 // Alerts are created from incoming events after applying grouping logic.
 type Alert struct {
@@ -38,9 +122,18 @@ type Alert struct {
 	// This is the primary business identifier for the alert.
 	DedupKey string `json:"dedupKey"`
 
+	// OriginalDedupKey is the dedup key as received (after normalization) when
+	// DedupKey is its hash. Empty if the key was not hashed.
+	OriginalDedupKey string `json:"original_dedupKey,omitempty"`
+
 	// EventManagerID identifies the namespace/tenant this alert belongs to.
 	EventManagerID string `json:"event_manager_id"`
 
+	// SubscriberIDs lists the other event managers that sent events with this
+	// dedup key while global dedup is enabled. They share the alert with its
+	// owner instead of getting an alert of their own.
+	SubscriberIDs []string `json:"subscriber_ids,omitempty"`
+
 	// Summary is a human-readable description of the alert.
 	Summary string `json:"summary"`
 
@@ -50,6 +143,11 @@ type Alert struct {
 	// Class is the classification/category of the alert.
 	Class string `json:"class"`
 
+	// Service and Components describe what the alert affects, from the
+	// event that created it.
+	Service    string   `json:"service,omitempty"`
+	Components []string `json:"components,omitempty"`
+
 	// Type indicates whether this is a parent or child alert.
 	Type AlertType `json:"type"`
 
@@ -68,6 +166,21 @@ type Alert struct {
 	// cannot be resolved yet (e.g., parent waiting for children to resolve).
 	ResolveRequested bool `json:"resolve_requested"`
 
+	// TriggerCount is the number of trigger events received for this dedup key,
+	// including duplicates and reactivations.
+	TriggerCount int `json:"trigger_count"`
+
+	// SampledEvents is the number of trigger events dropped by the sampling
+	// of the event manager instead of being processed. They are included in
+	// TriggerCount.
+	SampledEvents int `json:"sampled_events,omitempty"`
+
+	// ResolveCount is the number of times this alert has been resolved.
+	ResolveCount int `json:"resolve_count"`
+
+	// AcknowledgedAt is when a responder acknowledged the alert. Nil if not acknowledged.
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+
 	// CreatedAt is when the alert was first created.
 	CreatedAt time.Time `json:"created_at"`
 
@@ -76,37 +189,111 @@ type Alert struct {
 
 	// ResolvedAt is when the alert was resolved. Zero value if still active.
 	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+
+	// Resolution describes the last resolution. While ResolveRequested is
+	// set, it holds the resolution requested. Nil if never resolved or
+	// reactivated since.
+	Resolution *Resolution `json:"resolution,omitempty"`
+
+	// Runbook tells responders how to handle the alert, as configured on its
+	// event manager or grouping rule when it was created. Nil if none was.
+	Runbook *Runbook `json:"runbook,omitempty"`
+
+	// Episode numbers the episodes of the alert, from triggering to
+	// resolution, starting at 1; each reactivation starts a new one.
+	Episode int `json:"episode"`
+
+	// ReactivatedAt is when the current episode started, if the alert was
+	// reactivated. Nil during the first episode.
+	ReactivatedAt *time.Time `json:"reactivated_at,omitempty"`
+
+	// Episodes records the earlier episodes of the alert, oldest first.
+	Episodes []AlertEpisode `json:"episodes,omitempty"`
+
+	// Origin records where the event that triggered the current episode was
+	// received from. Nil if unknown.
+	Origin *EventOrigin `json:"origin,omitempty"`
+
+	// Tags are merged from the trigger events of the alert, as decided by
+	// the TagPolicy of its event manager.
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Links point responders to external objects, like dashboards or
+	// tickets. They are added by trigger events, the link templates of the
+	// event manager and the API.
+	Links []AlertLink `json:"links,omitempty"`
+
+	// Assignee is the responder owning the alert, as set through the API.
+	// Empty if unassigned.
+	Assignee string `json:"assignee,omitempty"`
+
+	// EventSummary is the summary of the event that opened a parent alert
+	// while Summary shows a summary of its group instead, as decided by the
+	// ParentSummary policy of its event manager. Empty otherwise.
+	EventSummary string `json:"event_summary,omitempty"`
+}
+
+// AlertEpisode is one episode of an alert: from when it triggered, or was
+// reactivated, until it resolved.
+type AlertEpisode struct {
+	// Episode numbers the episode, starting at 1.
+	Episode int `json:"episode"`
+
+	// StartedAt is when the alert triggered or was reactivated.
+	StartedAt time.Time `json:"started_at"`
+
+	// AcknowledgedAt is when a responder acknowledged the episode. Nil if
+	// not acknowledged.
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+
+	// ResolvedAt is when the episode ended. Nil while it is current and
+	// active.
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+
+	// Resolution describes how the episode ended.
+	Resolution *Resolution `json:"resolution,omitempty"`
 }
 
-// NewParentAlert creates a new parent alert from an event.
-func NewParentAlert(event *Event) *Alert {
-	now := time.Now().UTC()
+// NewParentAlert creates a new parent alert from an event, created at now.
+func NewParentAlert(event *Event, now time.Time) *Alert {
 	return &Alert{
 		DedupKey:       event.DedupKey,
 		EventManagerID: event.EventManagerID,
 		Summary:        event.Summary,
 		Severity:       event.Severity,
 		Class:          event.Class,
+		Service:        event.Service,
+		Components:     slices.Clone(event.Components),
 		Type:           AlertTypeParent,
 		Status:         AlertStatusActive,
 		ChildCount:     0,
+		TriggerCount:   1,
+		Episode:        1,
+		Tags:           maps.Clone(event.Tags),
+		Links:          slices.Clone(event.Links),
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
 }
 
-// NewChildAlert creates a new child alert from an event, linked to a parent.
-func NewChildAlert(event *Event, parentDedupKey string) *Alert {
-	now := time.Now().UTC()
+// NewChildAlert creates a new child alert from an event, linked to a parent,
+// created at now.
+func NewChildAlert(event *Event, parentDedupKey string, now time.Time) *Alert {
 	return &Alert{
 		DedupKey:       event.DedupKey,
 		EventManagerID: event.EventManagerID,
 		Summary:        event.Summary,
 		Severity:       event.Severity,
 		Class:          event.Class,
+		Service:        event.Service,
+		Components:     slices.Clone(event.Components),
 		Type:           AlertTypeChild,
 		Status:         AlertStatusActive,
 		ParentDedupKey: parentDedupKey,
+		TriggerCount:   1,
+		Episode:        1,
+		Tags:           maps.Clone(event.Tags),
+		Links:          slices.Clone(event.Links),
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
@@ -132,33 +319,255 @@ func (a *Alert) IsResolved() bool {
 	return a.Status == AlertStatusResolved
 }
 
-// Resolve marks the alert as resolved.
-func (a *Alert) Resolve() {
-	now := time.Now().UTC()
-	a.Status = AlertStatusResolved
-	a.UpdatedAt = now
-	a.ResolvedAt = &now
-	a.ResolveRequested = false
+// IsAcknowledged returns true if the alert has been acknowledged.
+func (a *Alert) IsAcknowledged() bool {
+	return a.AcknowledgedAt != nil
+}
+
+// Acknowledge marks the alert as acknowledged by a responder at now and
+// returns the transition. Acknowledging an already acknowledged alert keeps
+// the original timestamp and returns a nil transition; acknowledging a
+// resolved alert returns ErrAlertAlreadyResolved.
+func (a *Alert) Acknowledge(now time.Time) (*AlertTransition, error) {
+	if a.IsAcknowledged() && !a.IsResolved() {
+		return nil, nil
+	}
+	return a.transition(TransitionAcknowledge, now, func() {
+		a.AcknowledgedAt = &now
+	})
+}
+
+// Resolve marks the alert as resolved at now, records how and returns the
+// transition. It returns ErrAlertAlreadyResolved if the alert is resolved.
+func (a *Alert) Resolve(resolution Resolution, now time.Time) (*AlertTransition, error) {
+	return a.transition(TransitionResolve, now, func() {
+		a.Status = AlertStatusResolved
+		a.ResolvedAt = &now
+		a.ResolveRequested = false
+		a.ResolveCount++
+		a.Resolution = &resolution
+	})
+}
+
+// Reactivate starts a new episode of a resolved alert at now, recording the
+// episode that ended in its history, and returns the transition. It returns
+// ErrInvalidAlertTransition and leaves the alert unchanged if the alert is
+// not resolved, e.g. when a retried reactivation already applied.
+func (a *Alert) Reactivate(now time.Time) (*AlertTransition, error) {
+	return a.transition(TransitionReactivate, now, func() {
+		// Copy so the caller's earlier copies of the alert are unaffected
+		a.Episodes = append(slices.Clone(a.Episodes), a.CurrentEpisode())
+		a.Episode = a.episode() + 1
+		a.ReactivatedAt = &now
+		a.Status = AlertStatusActive
+		a.ResolveRequested = false
+		a.ResolvedAt = nil
+		a.Resolution = nil
+		a.AcknowledgedAt = nil
+		a.TriggerCount++
+	})
+}
+
+// CurrentEpisode returns the current episode of the alert, which has ended
+// if the alert is resolved.
+func (a *Alert) CurrentEpisode() AlertEpisode {
+	episode := AlertEpisode{
+		Episode:        a.episode(),
+		StartedAt:      a.CreatedAt,
+		AcknowledgedAt: a.AcknowledgedAt,
+		Resolution:     a.Resolution,
+	}
+	if a.ReactivatedAt != nil {
+		episode.StartedAt = *a.ReactivatedAt
+	}
+	if a.IsResolved() {
+		episode.ResolvedAt = a.ResolvedAt
+	} else {
+		// A requested resolution is not the end of the episode
+		episode.Resolution = nil
+	}
+	return episode
+}
+
+// AllEpisodes returns every episode of the alert, oldest first, ending with
+// the current one.
+func (a *Alert) AllEpisodes() []AlertEpisode {
+	return append(slices.Clone(a.Episodes), a.CurrentEpisode())
+}
+
+// episode returns the number of the current episode. Alerts stored before
+// episodes were numbered are in their first.
+func (a *Alert) episode() int {
+	return max(a.Episode, 1)
 }
 
 // MarkResolveRequested marks that a resolve was requested but cannot be completed yet.
-// This is used for parent alerts waiting for children to resolve.
-func (a *Alert) MarkResolveRequested() {
-	a.ResolveRequested = true
-	a.UpdatedAt = time.Now().UTC()
+// This is used for parent alerts waiting for children to resolve; the resolution
+// is kept until the alert is resolved. It returns the transition, or
+// ErrAlertAlreadyResolved if the alert is resolved.
+func (a *Alert) MarkResolveRequested(resolution Resolution, now time.Time) (*AlertTransition, error) {
+	return a.transition(TransitionRequestResolve, now, func() {
+		a.ResolveRequested = true
+		a.Resolution = &resolution
+	})
 }
 
 // IncrementChildCount increases the child counter for a parent alert.
-func (a *Alert) IncrementChildCount() {
+func (a *Alert) IncrementChildCount(now time.Time) {
 	a.ChildCount++
-	a.UpdatedAt = time.Now().UTC()
+	a.UpdatedAt = now
+}
+
+// EventSummaryOrSummary returns the summary of the event that opened the
+// alert, which Summary shows unless a group summary replaced it.
+func (a *Alert) EventSummaryOrSummary() string {
+	if a.EventSummary != "" {
+		return a.EventSummary
+	}
+	return a.Summary
+}
+
+// SetGroupSummary sets the summary the alert displays for its group,
+// keeping the summary of its own event. It returns false if the summary is
+// unchanged.
+func (a *Alert) SetGroupSummary(summary string) bool {
+	if summary == a.Summary {
+		return false
+	}
+	own := a.EventSummaryOrSummary()
+	a.Summary = summary
+	a.EventSummary = ""
+	if summary != own {
+		a.EventSummary = own
+	}
+	return true
+}
+
+// HasEventManager returns true if the event manager owns or subscribes to the alert.
+func (a *Alert) HasEventManager(eventManagerID string) bool {
+	return a.EventManagerID == eventManagerID || slices.Contains(a.SubscriberIDs, eventManagerID)
+}
+
+// Subscribe adds an event manager to the subscribers of the alert.
+// Returns false if it already owns or subscribes to the alert.
+func (a *Alert) Subscribe(eventManagerID string, now time.Time) bool {
+	if a.HasEventManager(eventManagerID) {
+		return false
+	}
+	// Copy so the caller's earlier copies of the alert are unaffected
+	a.SubscriberIDs = append(slices.Clone(a.SubscriberIDs), eventManagerID)
+	a.UpdatedAt = now
+	return true
 }
 
 // AlertFilter provides filtering options for querying alerts.
 type AlertFilter struct {
 	EventManagerID string
-	Status         AlertStatus
+	// IncludeSubscribed also matches alerts EventManagerID subscribes to.
+	IncludeSubscribed bool
+	ParentDedupKey    string
+	// Status matches the stored status of alerts, or with Muting the
+	// status they are listed with, so the muted statuses match none
+	// without it and active matches only unmuted alerts with it.
+	Status AlertStatus
+	// Muting is the muting the statuses of alerts are decided by, if any.
+	Muting   *AlertMuting
+	Type     AlertType
+	Severity Severity
+	Service  string
+	// ResolveRequested matches only alerts whose resolve was requested.
+	ResolveRequested bool
+	// Component matches alerts listing the component.
+	Component string
+	// Query matches alerts whose summary contains its words, in any order.
+	Query string
+	// Sort orders the alerts; newest first by default.
+	Sort   AlertSort
+	Limit  int
+	Offset int
+}
+
+// AlertSort orders listed alerts by a field, ascending or, prefixed with
+// "-", descending. Descending severity lists the most severe alerts first,
+// descending child count the largest groups. Alerts that sort equal are
+// listed newest first, then by ID, so pages of a listing don't overlap.
+type AlertSort string
+
+// Sort orders of alerts.
+const (
+	AlertSortNewest               AlertSort = "-created_at"
+	AlertSortOldest               AlertSort = "created_at"
+	AlertSortRecentlyUpdated      AlertSort = "-updated_at"
+	AlertSortLeastRecentlyUpdated AlertSort = "updated_at"
+	AlertSortMostSevere           AlertSort = "-severity"
+	AlertSortLeastSevere          AlertSort = "severity"
+	AlertSortMostChildren         AlertSort = "-child_count"
+	AlertSortFewestChildren       AlertSort = "child_count"
+)
+
+// ParseAlertSort parses a sort order; empty is the default order.
+func ParseAlertSort(s string) (AlertSort, error) {
+	switch sort := AlertSort(s); sort {
+	case "":
+		return AlertSortNewest, nil
+	case AlertSortNewest, AlertSortOldest, AlertSortRecentlyUpdated, AlertSortLeastRecentlyUpdated,
+		AlertSortMostSevere, AlertSortLeastSevere, AlertSortMostChildren, AlertSortFewestChildren:
+		return sort, nil
+	}
+	return "", ErrInvalidAlertSort
+}
+
+// Compare compares two alerts in the sort order, for slices.SortFunc. The
+// empty sort is the default order.
+func (s AlertSort) Compare(a, b *Alert) int {
+	newestFirst := cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	switch s {
+	case AlertSortOldest:
+		return -newestFirst
+	case AlertSortRecentlyUpdated:
+		return cmp.Or(b.UpdatedAt.Compare(a.UpdatedAt), newestFirst)
+	case AlertSortLeastRecentlyUpdated:
+		return cmp.Or(a.UpdatedAt.Compare(b.UpdatedAt), newestFirst)
+	case AlertSortMostSevere:
+		return cmp.Or(severityOrder(a.Severity)-severityOrder(b.Severity), newestFirst)
+	case AlertSortLeastSevere:
+		return cmp.Or(severityOrder(b.Severity)-severityOrder(a.Severity), newestFirst)
+	case AlertSortMostChildren:
+		return cmp.Or(cmp.Compare(b.ChildCount, a.ChildCount), newestFirst)
+	case AlertSortFewestChildren:
+		return cmp.Or(cmp.Compare(a.ChildCount, b.ChildCount), newestFirst)
+	}
+	return newestFirst
+}
+
+// severityOrder returns the rank of a severity, ranking severities that
+// aren't on the scale below its least severe level.
+func severityOrder(s Severity) int {
+	if rank := s.Rank(); rank > 0 {
+		return rank
+	}
+	return len(CurrentSeverityScale().levels) + 1
+}
+
+// ActiveAlertCount is the number of active alerts of one type, severity and
+// class of an event manager.
+type ActiveAlertCount struct {
+	EventManagerID string
 	Type           AlertType
-	Limit          int
-	Offset         int
+	Severity       Severity
+	Class          string
+	Count          int
+}
+
+// AlertRevision is a snapshot of an alert as it was after one change.
+// Every create and update of an alert records a new revision.
+type AlertRevision struct {
+	// Revision numbers the snapshots of an alert, starting at 1.
+	Revision int `json:"revision"`
+
+	// RecordedAt is when the change was stored.
+	RecordedAt time.Time `json:"recorded_at"`
+
+	// Alert is the state of the alert after the change.
+	Alert Alert `json:"alert"`
 }
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// AlertDiagnostics explains how an alert is grouped under the current
+// configuration of its event manager: the grouping rule selected for it,
+// the grouping value extracted, the parent lookup new alerts with the value
+// go through, and the children of its group.
+type AlertDiagnostics struct {
+	DedupKey       string      `json:"dedupKey"`
+	EventManagerID string      `json:"event_manager_id"`
+	Type           AlertType   `json:"type"`
+	Status         AlertStatus `json:"status"`
+	ParentDedupKey string      `json:"parent_dedupKey,omitempty"`
+
+	// GroupingDisabled is set if the event manager opts out of grouping.
+	GroupingDisabled bool `json:"grouping_disabled,omitempty"`
+
+	// GroupingRuleID is the grouping rule selected for the alert, empty if
+	// none is. DefaultGroupingRule is set if it is the system default, as no
+	// rule of the event manager matched. GroupingRuleMissing is set if the
+	// selected rule doesn't exist.
+	GroupingRuleID      string   `json:"grouping_rule_id,omitempty"`
+	GroupingRuleName    string   `json:"grouping_rule_name,omitempty"`
+	DefaultGroupingRule bool     `json:"default_grouping_rule,omitempty"`
+	GroupingRuleMissing bool     `json:"grouping_rule_missing,omitempty"`
+	GroupingKey         string   `json:"grouping_key,omitempty"`
+	GroupingValue       string   `json:"grouping_value,omitempty"`
+	TimeWindow          Duration `json:"time_window,omitempty"`
+
+	// ParentLookup is the entry new alerts with the grouping value look up
+	// their parent in. Nil without a grouping rule.
+	ParentLookup *ParentLookup `json:"parent_lookup,omitempty"`
+
+	// Children are the dedup keys of the children of the alert's group: its
+	// own for a parent, its siblings and itself for a child.
+	Children []string `json:"children"`
+
+	// Decision explains the grouping of the alert in a sentence.
+	Decision string `json:"decision"`
+}
+
+// ParentLookup is the entry of a grouping value in the state store, naming
+// the parent new alerts with the value are grouped under until it expires.
+type ParentLookup struct {
+	// Key is the lookup key: event manager, grouping key and grouping value.
+	Key string `json:"key"`
+
+	// ParentDedupKey is the parent registered for the value, empty if none
+	// is, e.g. since the time window of the last one ended.
+	ParentDedupKey string `json:"parent_dedupKey,omitempty"`
+
+	// ExpiresAt is when the time window of the parent ends, and TTLRemaining
+	// how long until then. Both are unset without a parent.
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	TTLRemaining Duration   `json:"ttl_remaining,omitempty"`
+}
+
+// GroupingEvent returns an event with the fields of the alert that grouping
+// rules are selected by and extract grouping values from. Labels are not
+// kept on alerts, so values templated from labels can't be reproduced.
+func (a *Alert) GroupingEvent() *Event {
+	return &Event{
+		EventManagerID: a.EventManagerID,
+		Summary:        a.EventSummaryOrSummary(),
+		Severity:       a.Severity,
+		Class:          a.Class,
+		DedupKey:       a.DedupKey,
+		Service:        a.Service,
+		Components:     a.Components,
+	}
+}
+
+// Explain returns the decision of the diagnostics.
+func (d *AlertDiagnostics) Explain() string {
+	switch {
+	case d.Type == AlertTypeChild && d.GroupingRuleID == "":
+		return fmt.Sprintf("grouped under parent %s", d.ParentDedupKey)
+	case d.Type == AlertTypeChild:
+		return fmt.Sprintf("grouped under parent %s by grouping rule %s on %s=%q", d.ParentDedupKey, d.GroupingRuleID, d.GroupingKey, d.GroupingValue)
+	case d.GroupingDisabled:
+		return "grouping is disabled for the event manager, so the alert is an independent parent"
+	case d.GroupingRuleMissing:
+		return fmt.Sprintf("grouping rule %s selected for the alert doesn't exist, so its events are rejected", d.GroupingRuleID)
+	case d.GroupingRuleID == "":
+		return "no grouping rule matches the alert, so it is an independent parent"
+	}
+
+	group := fmt.Sprintf("opened the group of %s=%q", d.GroupingKey, d.GroupingValue)
+	switch lookup := d.ParentLookup; {
+	case lookup.ParentDedupKey == d.DedupKey:
+		return fmt.Sprintf("%s; new alerts with the value are grouped under it for another %s", group, lookup.TTLRemaining)
+	case lookup.ParentDedupKey != "":
+		return fmt.Sprintf("%s, whose time window ended; new alerts with the value are grouped under %s", group, lookup.ParentDedupKey)
+	default:
+		return fmt.Sprintf("%s, whose time window ended; the next alert with the value opens a new group", group)
+	}
+}
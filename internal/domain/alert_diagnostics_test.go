@@ -0,0 +1,53 @@
+package domain
+
+import "testing"
+
+func TestAlertDiagnostics_Explain(t *testing.T) {
+	grouped := func(lookup *ParentLookup) AlertDiagnostics {
+		return AlertDiagnostics{DedupKey: "disk-1", Type: AlertTypeParent, GroupingRuleID: "rule-1", GroupingKey: "class", GroupingValue: "disk", ParentLookup: lookup}
+	}
+
+	tests := []struct {
+		name string
+		d    AlertDiagnostics
+		want string
+	}{
+		{
+			name: "child",
+			d:    AlertDiagnostics{Type: AlertTypeChild, ParentDedupKey: "disk-1", GroupingRuleID: "rule-1", GroupingKey: "class", GroupingValue: "disk"},
+			want: `grouped under parent disk-1 by grouping rule rule-1 on class="disk"`,
+		},
+		{
+			name: "grouping disabled",
+			d:    AlertDiagnostics{Type: AlertTypeParent, GroupingDisabled: true},
+			want: "grouping is disabled for the event manager, so the alert is an independent parent",
+		},
+		{
+			name: "no grouping rule",
+			d:    AlertDiagnostics{Type: AlertTypeParent},
+			want: "no grouping rule matches the alert, so it is an independent parent",
+		},
+		{
+			name: "missing grouping rule",
+			d:    AlertDiagnostics{Type: AlertTypeParent, GroupingRuleID: "rule-1", GroupingRuleMissing: true},
+			want: "grouping rule rule-1 selected for the alert doesn't exist, so its events are rejected",
+		},
+		{
+			name: "window ended",
+			d:    grouped(&ParentLookup{}),
+			want: `opened the group of class="disk", whose time window ended; the next alert with the value opens a new group`,
+		},
+		{
+			name: "another parent",
+			d:    grouped(&ParentLookup{ParentDedupKey: "disk-9"}),
+			want: `opened the group of class="disk", whose time window ended; new alerts with the value are grouped under disk-9`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.Explain(); got != tt.want {
+				t.Errorf("Explain() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxImportAlerts bounds the alerts of one import request.
+const MaxImportAlerts = 1000
+
+// ErrInvalidImport is returned for import requests that cannot be applied.
+var ErrInvalidImport = invalidError("invalid import")
+
+// Validation errors for AlertImportRecord.
+var (
+	ErrEmptyCreatedAt       = invalidError("created_at is required")
+	ErrCreatedAtInFuture    = invalidError("created_at must not be in the future")
+	ErrInvalidAlertType     = invalidError("type must be 'parent' or 'child'")
+	ErrInvalidAlertStatus   = invalidError("status must be 'active' or 'resolved'")
+	ErrInvalidResolvedAt    = invalidError("resolved_at is required for resolved alerts only, and must not precede created_at")
+	ErrInvalidImportParent  = invalidError("child alerts must reference a parent alert imported in the same request")
+	ErrDuplicateImportAlert = invalidError("dedupKey is imported more than once")
+)
+
+// AlertImportRequest is the body of an import of historical alerts, e.g.
+// exported from another alerting system before migrating to ArgusGo.
+type AlertImportRequest struct {
+	Alerts []AlertImportRecord `json:"alerts"`
+}
+
+// AlertImportRecord is one historical alert. Children are imported together
+// with their parent.
+type AlertImportRecord struct {
+	DedupKey       string      `json:"dedupKey"`
+	EventManagerID string      `json:"event_manager_id"`
+	Summary        string      `json:"summary"`
+	Severity       Severity    `json:"severity"`
+	Class          string      `json:"class"`
+	Type           AlertType   `json:"type"`
+	ParentDedupKey string      `json:"parent_dedupKey,omitempty"`
+	Status         AlertStatus `json:"status"`
+	TriggerCount   int         `json:"trigger_count"`
+	CreatedAt      time.Time   `json:"created_at"`
+	AcknowledgedAt *time.Time  `json:"acknowledged_at,omitempty"`
+	ResolvedAt     *time.Time  `json:"resolved_at,omitempty"`
+	Resolution     *Resolution `json:"resolution,omitempty"`
+}
+
+// AlertImportResult reports the outcome of an import.
+type AlertImportResult struct {
+	// Imported is the number of alerts written.
+	Imported int `json:"imported"`
+
+	// Skipped lists the dedup keys that already had an alert, and children
+	// of skipped parents.
+	Skipped []string `json:"skipped"`
+}
+
+// Validate checks every record of the request, at now. Errors wrap
+// ErrInvalidImport and name the index of the offending record.
+func (r *AlertImportRequest) Validate(now time.Time) error {
+	if len(r.Alerts) == 0 {
+		return fmt.Errorf("%w: alerts is required", ErrInvalidImport)
+	}
+	if len(r.Alerts) > MaxImportAlerts {
+		return fmt.Errorf("%w: at most %d alerts can be imported at once", ErrInvalidImport, MaxImportAlerts)
+	}
+
+	parents := make(map[string]bool, len(r.Alerts))
+	seen := make(map[string]bool, len(r.Alerts))
+	for i := range r.Alerts {
+		record := &r.Alerts[i]
+		if err := record.validate(now); err != nil {
+			return fmt.Errorf("%w: alerts[%d]: %w", ErrInvalidImport, i, err)
+		}
+		if seen[record.DedupKey] {
+			return fmt.Errorf("%w: alerts[%d]: %w", ErrInvalidImport, i, ErrDuplicateImportAlert)
+		}
+		seen[record.DedupKey] = true
+		if record.Type != AlertTypeChild {
+			parents[record.DedupKey] = true
+		}
+	}
+
+	for i := range r.Alerts {
+		record := &r.Alerts[i]
+		if record.Type == AlertTypeChild && !parents[record.ParentDedupKey] {
+			return fmt.Errorf("%w: alerts[%d]: %w", ErrInvalidImport, i, ErrInvalidImportParent)
+		}
+	}
+	return nil
+}
+
+// validate checks the fields of one record.
+func (r *AlertImportRecord) validate(now time.Time) error {
+	if r.DedupKey == "" {
+		return ErrEmptyDedupKey
+	}
+	if r.EventManagerID == "" {
+		return ErrEmptyEventManagerID
+	}
+	if r.Summary == "" {
+		return ErrEmptySummary
+	}
+	if r.Severity != "" && !r.Severity.IsValid() {
+		return ErrInvalidSeverity
+	}
+	switch r.Type {
+	case "", AlertTypeParent:
+		if r.ParentDedupKey != "" {
+			return ErrInvalidImportParent
+		}
+	case AlertTypeChild:
+		if r.ParentDedupKey == "" || r.ParentDedupKey == r.DedupKey {
+			return ErrInvalidImportParent
+		}
+	default:
+		return ErrInvalidAlertType
+	}
+	if r.CreatedAt.IsZero() {
+		return ErrEmptyCreatedAt
+	}
+	if r.CreatedAt.After(now) {
+		return ErrCreatedAtInFuture
+	}
+
+	switch r.Status {
+	case "":
+		// Inferred from resolved_at
+	case AlertStatusActive:
+		if r.ResolvedAt != nil {
+			return ErrInvalidResolvedAt
+		}
+	case AlertStatusResolved:
+		if r.ResolvedAt == nil {
+			return ErrInvalidResolvedAt
+		}
+	default:
+		return ErrInvalidAlertStatus
+	}
+	if r.ResolvedAt != nil && r.ResolvedAt.Before(r.CreatedAt) {
+		return ErrInvalidResolvedAt
+	}
+	return nil
+}
+
+// ToAlert returns the alert of a validated record with its original
+// timestamps. Omitted fields get the values of a new alert: a parent, active
+// unless resolved_at is set, triggered once, with the default severity.
+func (r *AlertImportRecord) ToAlert() *Alert {
+	alert := &Alert{
+		DedupKey:       r.DedupKey,
+		EventManagerID: r.EventManagerID,
+		Summary:        r.Summary,
+		Severity:       r.Severity,
+		Class:          r.Class,
+		Type:           r.Type,
+		Status:         r.Status,
+		ParentDedupKey: r.ParentDedupKey,
+		TriggerCount:   r.TriggerCount,
+		Episode:        1,
+		AcknowledgedAt: r.AcknowledgedAt,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.CreatedAt,
+		ResolvedAt:     r.ResolvedAt,
+	}
+	if alert.Severity == "" {
+		alert.Severity = CurrentSeverityScale().Default()
+	}
+	if alert.Type == "" {
+		alert.Type = AlertTypeParent
+	}
+	if alert.Status == "" {
+		alert.Status = AlertStatusActive
+		if r.ResolvedAt != nil {
+			alert.Status = AlertStatusResolved
+		}
+	}
+	if alert.TriggerCount < 1 {
+		alert.TriggerCount = 1
+	}
+
+	if alert.AcknowledgedAt != nil && alert.AcknowledgedAt.After(alert.UpdatedAt) {
+		alert.UpdatedAt = *alert.AcknowledgedAt
+	}
+	if alert.ResolvedAt != nil {
+		alert.ResolveCount = 1
+		resolution := Resolution{ResolvedBy: ResolvedByImport}
+		if r.Resolution != nil {
+			resolution.Actor = r.Resolution.Actor
+			resolution.Reason = r.Resolution.Reason
+		}
+		alert.Resolution = &resolution
+		if alert.ResolvedAt.After(alert.UpdatedAt) {
+			alert.UpdatedAt = *alert.ResolvedAt
+		}
+	}
+	return alert
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAlertImportRequest_Validate(t *testing.T) {
+	now := time.Now().UTC()
+	created := now.Add(-time.Hour)
+	resolved := now.Add(-time.Minute)
+	early := created.Add(-time.Minute)
+	record := func(key string) AlertImportRecord {
+		return AlertImportRecord{DedupKey: key, EventManagerID: "em-1", Summary: "Test", CreatedAt: created}
+	}
+	with := func(r AlertImportRecord, f func(*AlertImportRecord)) AlertImportRecord {
+		f(&r)
+		return r
+	}
+	child := with(record("c1"), func(r *AlertImportRecord) { r.Type = AlertTypeChild; r.ParentDedupKey = "p1" })
+
+	tests := []struct {
+		name    string
+		alerts  []AlertImportRecord
+		wantErr bool
+	}{
+		{name: "valid", alerts: []AlertImportRecord{record("p1"), child}},
+		{name: "resolved", alerts: []AlertImportRecord{with(record("p1"), func(r *AlertImportRecord) { r.ResolvedAt = &resolved })}},
+		{name: "empty", wantErr: true},
+		{name: "missing summary", alerts: []AlertImportRecord{with(record("p1"), func(r *AlertImportRecord) { r.Summary = "" })}, wantErr: true},
+		{name: "unknown severity", alerts: []AlertImportRecord{with(record("p1"), func(r *AlertImportRecord) { r.Severity = "critical" })}, wantErr: true},
+		{name: "missing created_at", alerts: []AlertImportRecord{with(record("p1"), func(r *AlertImportRecord) { r.CreatedAt = time.Time{} })}, wantErr: true},
+		{name: "created in the future", alerts: []AlertImportRecord{with(record("p1"), func(r *AlertImportRecord) { r.CreatedAt = now.Add(time.Hour) })}, wantErr: true},
+		{name: "resolved before created", alerts: []AlertImportRecord{with(record("p1"), func(r *AlertImportRecord) { r.ResolvedAt = &early })}, wantErr: true},
+		{name: "active with resolved_at", alerts: []AlertImportRecord{with(record("p1"), func(r *AlertImportRecord) { r.Status = AlertStatusActive; r.ResolvedAt = &resolved })}, wantErr: true},
+		{name: "resolved without resolved_at", alerts: []AlertImportRecord{with(record("p1"), func(r *AlertImportRecord) { r.Status = AlertStatusResolved })}, wantErr: true},
+		{name: "duplicate dedup key", alerts: []AlertImportRecord{record("p1"), record("p1")}, wantErr: true},
+		{name: "child without parent in request", alerts: []AlertImportRecord{child}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &AlertImportRequest{Alerts: tt.alerts}
+			err := req.Validate(now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidImport) {
+				t.Errorf("Validate() error = %v, want %v", err, ErrInvalidImport)
+			}
+		})
+	}
+}
+
+func TestAlertImportRecord_ToAlert(t *testing.T) {
+	created := time.Now().UTC().Add(-time.Hour)
+	resolved := created.Add(30 * time.Minute)
+	record := AlertImportRecord{DedupKey: "p1", EventManagerID: "em-1", Summary: "Test", CreatedAt: created, ResolvedAt: &resolved}
+
+	alert := record.ToAlert()
+	if alert.Type != AlertTypeParent || alert.Status != AlertStatusResolved || alert.TriggerCount != 1 {
+		t.Errorf("ToAlert() = %+v, want a resolved parent triggered once", alert)
+	}
+	if alert.Severity != CurrentSeverityScale().Default() {
+		t.Errorf("Severity = %s, want the default %s", alert.Severity, CurrentSeverityScale().Default())
+	}
+	if !alert.UpdatedAt.Equal(resolved) || alert.Resolution == nil || alert.Resolution.ResolvedBy != ResolvedByImport {
+		t.Errorf("ToAlert() = %+v, want updated at resolution by import", alert)
+	}
+}
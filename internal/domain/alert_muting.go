@@ -0,0 +1,47 @@
+package domain
+
+import "slices"
+
+// AlertMuting is how the notifications of active alerts are muted at a
+// time: by the active silences, checked first, or by the quotas of event
+// managers. Muting is decided when alerts are read, so alerts are filtered
+// and counted by a snapshot of it rather than stored with a muted status.
+type AlertMuting struct {
+	// Silences are the active silences.
+	Silences []*Silence
+
+	// SuppressedEventManagers are the event managers whose quota
+	// suppresses notifications.
+	SuppressedEventManagers []string
+}
+
+// Status returns the status an alert is listed with: silenced, with the ID
+// of the silence, if it is active and silenced; suppressed if it is active
+// and the quota of its event manager suppresses notifications; and its
+// stored status otherwise.
+func (m *AlertMuting) Status(alert *Alert) (AlertStatus, string) {
+	if m == nil || !alert.IsActive() {
+		return alert.Status, ""
+	}
+	for _, s := range m.Silences {
+		if s.Match.Matches(alert) {
+			return AlertStatusSilenced, s.ID
+		}
+	}
+	if slices.Contains(m.SuppressedEventManagers, alert.EventManagerID) {
+		return AlertStatusSuppressed, ""
+	}
+	return alert.Status, ""
+}
+
+// BySeverity returns true if the silences match alerts by event manager
+// and severity alone, so the muted alerts can be counted from the active
+// alert counts of each event manager and severity.
+func (m *AlertMuting) BySeverity() bool {
+	for _, s := range m.Silences {
+		if s.Match.DedupKeyPattern != "" || len(s.Match.Classes) > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "testing"
+
+func TestAlertMuting_Status(t *testing.T) {
+	muting := &AlertMuting{
+		Silences:                []*Silence{{ID: "s-1", Match: SilenceMatcher{Severities: []Severity{SeverityLow}}}},
+		SuppressedEventManagers: []string{"em-1"},
+	}
+
+	tests := []struct {
+		name        string
+		muting      *AlertMuting
+		alert       *Alert
+		want        AlertStatus
+		wantSilence string
+	}{
+		{"silenced first", muting, &Alert{EventManagerID: "em-1", Severity: SeverityLow, Status: AlertStatusActive}, AlertStatusSilenced, "s-1"},
+		{"suppressed", muting, &Alert{EventManagerID: "em-1", Severity: SeverityHigh, Status: AlertStatusActive}, AlertStatusSuppressed, ""},
+		{"unmuted", muting, &Alert{EventManagerID: "em-2", Severity: SeverityHigh, Status: AlertStatusActive}, AlertStatusActive, ""},
+		{"resolved", muting, &Alert{EventManagerID: "em-1", Severity: SeverityLow, Status: AlertStatusResolved}, AlertStatusResolved, ""},
+		{"no muting", nil, &Alert{EventManagerID: "em-1", Severity: SeverityLow, Status: AlertStatusActive}, AlertStatusActive, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, silenceID := tt.muting.Status(tt.alert)
+			if status != tt.want || silenceID != tt.wantSilence {
+				t.Errorf("Status() = %s, %q, want %s, %q", status, silenceID, tt.want, tt.wantSilence)
+			}
+		})
+	}
+
+	if !muting.BySeverity() {
+		t.Error("BySeverity() = false for a severity silence, want true")
+	}
+	muting.Silences = append(muting.Silences, &Silence{Match: SilenceMatcher{DedupKeyPattern: "db-*"}})
+	if muting.BySeverity() {
+		t.Error("BySeverity() = true with a dedup key silence, want false")
+	}
+}
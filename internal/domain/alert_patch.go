@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+// MaxAssigneeLength limits the assignee of an alert.
+const MaxAssigneeLength = 128
+
+// Errors returned for alert patches.
+var (
+	ErrEmptyAlertPatch = invalidError("patch changes no field")
+	ErrAssigneeTooLong = invalidError(fmt.Sprintf("assignee must be at most %d characters", MaxAssigneeLength))
+	ErrAlertModified   = conflictError("alert was modified since updated_at")
+)
+
+// AlertPatch changes the fields of an alert that responders manage, as sent
+// to PATCH /v1/alerts/:dedupKey. Fields left out are unchanged.
+type AlertPatch struct {
+	// UpdatedAt, if set, must be the updated_at of the alert the patch was
+	// made from, so changes made meanwhile aren't overwritten unseen.
+	UpdatedAt *time.Time `json:"updated_at"`
+
+	// Severity overrides the severity of the alert.
+	Severity *Severity `json:"severity"`
+
+	// Tags are merged onto the tags of the alert, replacing their values; a
+	// null value removes the tag.
+	Tags map[string]*string `json:"tags"`
+
+	// Links replaces the links of the alert.
+	Links *[]AlertLink `json:"links"`
+
+	// Assignee sets the responder owning the alert; empty unassigns it.
+	Assignee *string `json:"assignee"`
+
+	// Runbook replaces the runbook of the alert; an empty runbook removes it.
+	Runbook *Runbook `json:"runbook"`
+}
+
+// Validate checks the patch changes a field and every field it sets. The
+// number of tags is checked as merged onto the alert, by Apply.
+func (p *AlertPatch) Validate() error {
+	if p.Severity == nil && p.Tags == nil && p.Links == nil && p.Assignee == nil && p.Runbook == nil {
+		return ErrEmptyAlertPatch
+	}
+	if p.Severity != nil && !p.Severity.IsValid() {
+		return ErrInvalidSeverity
+	}
+	tags := make(map[string]string, len(p.Tags))
+	for key, value := range p.Tags {
+		if value != nil {
+			tags[key] = *value
+		}
+	}
+	if err := validateTags(tags); err != nil {
+		return err
+	}
+	if p.Links != nil {
+		if err := validateLinks(*p.Links); err != nil {
+			return err
+		}
+	}
+	if p.Assignee != nil && len(*p.Assignee) > MaxAssigneeLength {
+		return ErrAssigneeTooLong
+	}
+	if p.Runbook != nil {
+		return p.Runbook.Validate()
+	}
+	return nil
+}
+
+// Apply applies the patch to an alert. It returns ErrAlertModified if the
+// alert was updated after UpdatedAt, and ErrTooManyTags if the alert would
+// carry too many tags, leaving the alert unchanged on error.
+func (p *AlertPatch) Apply(alert *Alert, now time.Time) error {
+	if p.UpdatedAt != nil && !p.UpdatedAt.Equal(alert.UpdatedAt) {
+		return ErrAlertModified
+	}
+
+	tags := alert.Tags
+	if p.Tags != nil {
+		tags = maps.Clone(alert.Tags)
+		if tags == nil {
+			tags = make(map[string]string)
+		}
+		for key, value := range p.Tags {
+			if value == nil {
+				delete(tags, key)
+			} else {
+				tags[key] = *value
+			}
+		}
+		if len(tags) > MaxEventTags {
+			return ErrTooManyTags
+		}
+		if len(tags) == 0 {
+			tags = nil
+		}
+	}
+	alert.Tags = tags
+
+	if p.Severity != nil {
+		alert.Severity = *p.Severity
+	}
+	if p.Links != nil {
+		alert.Links = slices.Clone(*p.Links)
+	}
+	if p.Assignee != nil {
+		alert.Assignee = *p.Assignee
+	}
+	if p.Runbook != nil {
+		alert.Runbook = nil
+		if !p.Runbook.IsZero() {
+			runbook := *p.Runbook
+			alert.Runbook = &runbook
+		}
+	}
+	alert.UpdatedAt = now
+	return nil
+}
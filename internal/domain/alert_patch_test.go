@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAlertPatch_Validate(t *testing.T) {
+	high, unknown := SeverityHigh, Severity("urgent")
+	longAssignee := strings.Repeat("a", MaxAssigneeLength+1)
+	emptyKey := "v"
+	links := []AlertLink{{Type: LinkTypeTicket, URL: "jira"}}
+
+	tests := []struct {
+		name  string
+		patch AlertPatch
+		want  error
+	}{
+		{"valid", AlertPatch{Severity: &high}, nil},
+		{"empty", AlertPatch{UpdatedAt: &time.Time{}}, ErrEmptyAlertPatch},
+		{"severity", AlertPatch{Severity: &unknown}, ErrInvalidSeverity},
+		{"tag key", AlertPatch{Tags: map[string]*string{"": &emptyKey}}, ErrEmptyTagKey},
+		{"tag removal", AlertPatch{Tags: map[string]*string{"host": nil}}, nil},
+		{"links", AlertPatch{Links: &links}, ErrInvalidLinkURL},
+		{"assignee", AlertPatch{Assignee: &longAssignee}, ErrAssigneeTooLong},
+		{"runbook", AlertPatch{Runbook: &Runbook{URL: "wiki"}}, ErrInvalidRunbookURL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.patch.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertPatch_Apply(t *testing.T) {
+	updatedAt := time.Date(2026, 10, 15, 14, 30, 0, 0, time.UTC)
+	now := updatedAt.Add(time.Minute)
+	alert := &Alert{
+		Severity:  SeverityLow,
+		Tags:      map[string]string{"host": "db-1"},
+		Runbook:   &Runbook{URL: "https://wiki.example.com/db"},
+		UpdatedAt: updatedAt,
+	}
+
+	stale := updatedAt.Add(-time.Second)
+	if err := (&AlertPatch{UpdatedAt: &stale, Runbook: &Runbook{}}).Apply(alert, now); !errors.Is(err, ErrAlertModified) {
+		t.Errorf("Apply() with a stale updated_at = %v, want ErrAlertModified", err)
+	}
+
+	patch := &AlertPatch{UpdatedAt: &updatedAt, Tags: map[string]*string{"host": nil}, Runbook: &Runbook{}}
+	if err := patch.Apply(alert, now); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if alert.Tags != nil || alert.Runbook != nil || !alert.UpdatedAt.Equal(now) || alert.Severity != SeverityLow {
+		t.Errorf("Apply() = %+v, want tags and runbook removed", alert)
+	}
+
+	tags := make(map[string]*string)
+	for i := range MaxEventTags + 1 {
+		value := "v"
+		tags[strings.Repeat("k", i+1)] = &value
+	}
+	if err := (&AlertPatch{Tags: tags}).Apply(alert, now); !errors.Is(err, ErrTooManyTags) || alert.Tags != nil {
+		t.Errorf("Apply() with %d tags = %v, want ErrTooManyTags and the tags unchanged", len(tags), err)
+	}
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ErrInvalidAlertTransition is returned for a transition the state of an
+// alert doesn't allow, other than changing a resolved alert, which returns
+// ErrAlertAlreadyResolved.
+var ErrInvalidAlertTransition = transitionError("alert transition not allowed")
+
+// AlertState is the state of an alert in its lifecycle. It is derived from
+// the stored fields of the alert, Status, ResolveRequested and
+// AcknowledgedAt, which only the transitions of the state machine change.
+type AlertState string
+
+const (
+	// AlertStateActive is the state of a triggered alert nobody handles yet.
+	AlertStateActive AlertState = "active"
+	// AlertStateAcknowledged is the state of an active alert a responder
+	// acknowledged.
+	AlertStateAcknowledged AlertState = "acknowledged"
+	// AlertStateResolvePending is the state of a parent alert whose resolve
+	// waits for its children, acknowledged or not.
+	AlertStateResolvePending AlertState = "resolve_pending"
+	// AlertStateResolved is the state of a resolved alert.
+	AlertStateResolved AlertState = "resolved"
+)
+
+// AlertTransitionKind names a transition of the alert state machine.
+type AlertTransitionKind string
+
+const (
+	TransitionAcknowledge    AlertTransitionKind = "acknowledge"
+	TransitionRequestResolve AlertTransitionKind = "request_resolve"
+	TransitionResolve        AlertTransitionKind = "resolve"
+	TransitionReactivate     AlertTransitionKind = "reactivate"
+)
+
+// alertTransitions lists the states each transition can leave. Creating an
+// alert enters AlertStateActive.
+var alertTransitions = map[AlertTransitionKind][]AlertState{
+	TransitionAcknowledge:    {AlertStateActive, AlertStateResolvePending},
+	TransitionRequestResolve: {AlertStateActive, AlertStateAcknowledged, AlertStateResolvePending},
+	TransitionResolve:        {AlertStateActive, AlertStateAcknowledged, AlertStateResolvePending},
+	TransitionReactivate:     {AlertStateResolved},
+}
+
+// AlertTransition is a transition an alert went through, for its consumers:
+// the timeline of incident reports, metrics and notifications.
+type AlertTransition struct {
+	Kind AlertTransitionKind `json:"kind"`
+	From AlertState          `json:"from"`
+	To   AlertState          `json:"to"`
+	At   time.Time           `json:"at"`
+
+	// Resolution is the resolution requested or recorded by resolve
+	// transitions.
+	Resolution *Resolution `json:"resolution,omitempty"`
+}
+
+// State returns the state of the alert. A parent waiting for its children
+// is pending even if acknowledged.
+func (a *Alert) State() AlertState {
+	switch {
+	case a.IsResolved():
+		return AlertStateResolved
+	case a.ResolveRequested:
+		return AlertStateResolvePending
+	case a.IsAcknowledged():
+		return AlertStateAcknowledged
+	}
+	return AlertStateActive
+}
+
+// CanTransition returns nil if the state of the alert allows the transition,
+// ErrAlertAlreadyResolved for a resolved alert and ErrInvalidAlertTransition
+// otherwise.
+func (a *Alert) CanTransition(kind AlertTransitionKind) error {
+	from := a.State()
+	if slices.Contains(alertTransitions[kind], from) {
+		return nil
+	}
+	if from == AlertStateResolved {
+		return ErrAlertAlreadyResolved
+	}
+	return fmt.Errorf("%w: %s an alert in state %s", ErrInvalidAlertTransition, kind, from)
+}
+
+// transition applies a transition allowed by the state of the alert at now,
+// changing its fields with apply, and returns the transition.
+func (a *Alert) transition(kind AlertTransitionKind, now time.Time, apply func()) (*AlertTransition, error) {
+	if err := a.CanTransition(kind); err != nil {
+		return nil, err
+	}
+	from := a.State()
+	apply()
+	a.UpdatedAt = now
+	t := &AlertTransition{Kind: kind, From: from, To: a.State(), At: now}
+	if kind == TransitionResolve || kind == TransitionRequestResolve {
+		t.Resolution = a.Resolution
+	}
+	return t, nil
+}
+
+// TransitionsBetween returns the transitions an alert went through between
+// two of its revisions, prev and next, recorded at at. A reactivation,
+// resolve or resolve request comes first, then an acknowledgement.
+func TransitionsBetween(prev, next *Alert, at time.Time) []AlertTransition {
+	var transitions []AlertTransition
+	add := func(kind AlertTransitionKind, from, to AlertState) {
+		t := AlertTransition{Kind: kind, From: from, To: to, At: at}
+		if kind == TransitionResolve || kind == TransitionRequestResolve {
+			t.Resolution = next.Resolution
+		}
+		transitions = append(transitions, t)
+	}
+
+	from, to := prev.State(), next.State()
+	switch {
+	case prev.IsResolved() && !next.IsResolved():
+		add(TransitionReactivate, from, to)
+	case !prev.IsResolved() && next.IsResolved():
+		add(TransitionResolve, from, to)
+	case !prev.ResolveRequested && next.ResolveRequested:
+		add(TransitionRequestResolve, from, to)
+	}
+	if !prev.IsAcknowledged() && next.IsAcknowledged() {
+		add(TransitionAcknowledge, from, to)
+	}
+	return transitions
+}
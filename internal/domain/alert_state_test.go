@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAlert_StateMachine(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	alert := NewParentAlert(&Event{DedupKey: "db-1"}, now)
+	if alert.State() != AlertStateActive {
+		t.Fatalf("State() of a new alert = %s, want active", alert.State())
+	}
+
+	steps := []struct {
+		name     string
+		apply    func() (*AlertTransition, error)
+		wantKind AlertTransitionKind
+		wantFrom AlertState
+		wantTo   AlertState
+	}{
+		{"acknowledge", func() (*AlertTransition, error) { return alert.Acknowledge(now) },
+			TransitionAcknowledge, AlertStateActive, AlertStateAcknowledged},
+		{"request resolve", func() (*AlertTransition, error) {
+			return alert.MarkResolveRequested(Resolution{ResolvedBy: ResolvedByEvent}, now)
+		}, TransitionRequestResolve, AlertStateAcknowledged, AlertStateResolvePending},
+		{"resolve", func() (*AlertTransition, error) { return alert.Resolve(Resolution{ResolvedBy: ResolvedByEvent}, now) },
+			TransitionResolve, AlertStateResolvePending, AlertStateResolved},
+		{"reactivate", func() (*AlertTransition, error) { return alert.Reactivate(now) },
+			TransitionReactivate, AlertStateResolved, AlertStateActive},
+	}
+	for _, step := range steps {
+		transition, err := step.apply()
+		if err != nil {
+			t.Fatalf("%s error = %v", step.name, err)
+		}
+		if transition.Kind != step.wantKind || transition.From != step.wantFrom || transition.To != step.wantTo {
+			t.Errorf("%s transition = %+v, want %s from %s to %s", step.name, transition, step.wantKind, step.wantFrom, step.wantTo)
+		}
+	}
+}
+
+func TestAlert_IllegalTransitions(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	resolved := NewParentAlert(&Event{DedupKey: "db-1"}, now)
+	_, _ = resolved.Resolve(Resolution{ResolvedBy: ResolvedByEvent}, now)
+
+	if _, err := resolved.Resolve(Resolution{ResolvedBy: ResolvedByAPI}, now); !errors.Is(err, ErrAlertAlreadyResolved) {
+		t.Errorf("Resolve() of a resolved alert error = %v, want ErrAlertAlreadyResolved", err)
+	}
+	if resolved.ResolveCount != 1 || resolved.Resolution.ResolvedBy != ResolvedByEvent {
+		t.Errorf("resolved alert = %+v, want the first resolution kept", resolved)
+	}
+	if _, err := resolved.MarkResolveRequested(Resolution{}, now); !errors.Is(err, ErrAlertAlreadyResolved) {
+		t.Errorf("MarkResolveRequested() of a resolved alert error = %v, want ErrAlertAlreadyResolved", err)
+	}
+
+	active := NewParentAlert(&Event{DedupKey: "db-2"}, now)
+	if _, err := active.Reactivate(now); !errors.Is(err, ErrInvalidAlertTransition) || KindOf(err) != ErrorKindInvalidTransition {
+		t.Errorf("Reactivate() of an active alert error = %v, want ErrInvalidAlertTransition", err)
+	}
+}
+
+func TestTransitionsBetween(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	prev := NewParentAlert(&Event{DedupKey: "db-1"}, now)
+	next := *prev
+	_, _ = next.Acknowledge(now)
+	_, _ = next.Resolve(Resolution{ResolvedBy: ResolvedByAPI, Actor: "alice"}, now)
+
+	transitions := TransitionsBetween(prev, &next, now)
+	if len(transitions) != 2 {
+		t.Fatalf("TransitionsBetween() = %+v, want a resolve and an acknowledgement", transitions)
+	}
+	if transitions[0].Kind != TransitionResolve || transitions[0].Resolution == nil || transitions[0].Resolution.Actor != "alice" {
+		t.Errorf("first transition = %+v, want the resolve by alice", transitions[0])
+	}
+	if transitions[1].Kind != TransitionAcknowledge {
+		t.Errorf("second transition = %+v, want the acknowledgement", transitions[1])
+	}
+	if got := TransitionsBetween(&next, &next, now); len(got) != 0 {
+		t.Errorf("TransitionsBetween() of equal revisions = %+v, want none", got)
+	}
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -14,7 +15,8 @@ func TestNewParentAlert(t *testing.T) {
 		DedupKey:       "db-alert-1",
 	}
 
-	alert := NewParentAlert(event)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	alert := NewParentAlert(event, now)
 
 	if alert.DedupKey != event.DedupKey {
 		t.Errorf("DedupKey = %v, want %v", alert.DedupKey, event.DedupKey)
@@ -34,6 +36,9 @@ func TestNewParentAlert(t *testing.T) {
 	if alert.ParentDedupKey != "" {
 		t.Errorf("ParentDedupKey = %v, want empty", alert.ParentDedupKey)
 	}
+	if !alert.CreatedAt.Equal(now) || !alert.UpdatedAt.Equal(now) {
+		t.Errorf("CreatedAt, UpdatedAt = %v, %v, want %v", alert.CreatedAt, alert.UpdatedAt, now)
+	}
 }
 
 func TestNewChildAlert(t *testing.T) {
@@ -46,7 +51,7 @@ func TestNewChildAlert(t *testing.T) {
 	}
 	parentDedupKey := "db-alert-1"
 
-	alert := NewChildAlert(event, parentDedupKey)
+	alert := NewChildAlert(event, parentDedupKey, time.Now().UTC())
 
 	if alert.DedupKey != event.DedupKey {
 		t.Errorf("DedupKey = %v, want %v", alert.DedupKey, event.DedupKey)
@@ -102,7 +107,7 @@ func TestAlert_Resolve(t *testing.T) {
 	}
 
 	beforeResolve := time.Now()
-	alert.Resolve()
+	alert.Resolve(Resolution{ResolvedBy: ResolvedByEvent, Actor: "prometheus", Reason: "disk freed"}, time.Now().UTC())
 	afterResolve := time.Now()
 
 	if alert.Status != AlertStatusResolved {
@@ -117,6 +122,48 @@ func TestAlert_Resolve(t *testing.T) {
 	if alert.ResolvedAt.Before(beforeResolve) || alert.ResolvedAt.After(afterResolve) {
 		t.Error("ResolvedAt should be set to current time")
 	}
+	if alert.Resolution == nil || alert.Resolution.ResolvedBy != ResolvedByEvent || alert.Resolution.Actor != "prometheus" {
+		t.Errorf("Resolution = %+v, want the resolution passed to Resolve()", alert.Resolution)
+	}
+}
+
+func TestAlert_Reactivate(t *testing.T) {
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	alert := NewParentAlert(&Event{DedupKey: "alert-1"}, created)
+
+	if _, err := alert.Reactivate(created.Add(time.Minute)); !errors.Is(err, ErrInvalidAlertTransition) {
+		t.Fatalf("Reactivate() of an active alert error = %v, want ErrInvalidAlertTransition", err)
+	}
+
+	// Each resolution and reactivation closes an episode
+	now := created
+	for i := range 4 {
+		now = now.Add(time.Hour)
+		alert.Resolve(Resolution{ResolvedBy: ResolvedByEvent, Reason: "fixed"}, now)
+		now = now.Add(time.Hour)
+		if _, err := alert.Reactivate(now); err != nil {
+			t.Fatalf("Reactivate() %d of a resolved alert error = %v", i+1, err)
+		}
+	}
+
+	if alert.Episode != 5 || !alert.IsActive() || alert.ResolvedAt != nil || alert.Resolution != nil {
+		t.Errorf("alert after reactivations = %+v, want active in episode 5", alert)
+	}
+	episodes := alert.AllEpisodes()
+	if len(episodes) != 5 {
+		t.Fatalf("AllEpisodes() returned %d episodes, want 5", len(episodes))
+	}
+	if !episodes[0].StartedAt.Equal(created) || episodes[0].ResolvedAt == nil || episodes[0].Resolution == nil {
+		t.Errorf("first episode = %+v, want started at creation and resolved", episodes[0])
+	}
+	for i, episode := range episodes[1:] {
+		if episode.Episode != i+2 || !episode.StartedAt.Equal(created.Add(time.Duration(2*(i+1))*time.Hour)) {
+			t.Errorf("episode %d = %+v, want started at its reactivation", i+2, episode)
+		}
+	}
+	if last := episodes[4]; last.ResolvedAt != nil || last.Resolution != nil {
+		t.Errorf("current episode = %+v, want unresolved", last)
+	}
 }
 
 func TestAlert_MarkResolveRequested(t *testing.T) {
@@ -125,23 +172,53 @@ func TestAlert_MarkResolveRequested(t *testing.T) {
 		ResolveRequested: false,
 	}
 
-	alert.MarkResolveRequested()
+	alert.MarkResolveRequested(Resolution{ResolvedBy: ResolvedByEvent}, time.Now().UTC())
 
 	if !alert.ResolveRequested {
 		t.Error("ResolveRequested should be true after MarkResolveRequested()")
 	}
+	if alert.Resolution == nil || alert.Resolution.ResolvedBy != ResolvedByEvent {
+		t.Errorf("Resolution = %+v, want the requested resolution", alert.Resolution)
+	}
 }
 
 func TestAlert_IncrementChildCount(t *testing.T) {
 	alert := &Alert{ChildCount: 0}
 
-	alert.IncrementChildCount()
+	alert.IncrementChildCount(time.Now().UTC())
 	if alert.ChildCount != 1 {
 		t.Errorf("ChildCount = %v, want 1", alert.ChildCount)
 	}
 
-	alert.IncrementChildCount()
+	alert.IncrementChildCount(time.Now().UTC())
 	if alert.ChildCount != 2 {
 		t.Errorf("ChildCount = %v, want 2", alert.ChildCount)
 	}
 }
+
+func TestAlert_Acknowledge(t *testing.T) {
+	alert := NewParentAlert(&Event{DedupKey: "alert-1"}, time.Now().UTC())
+
+	if transition, err := alert.Acknowledge(time.Now().UTC()); err != nil || transition == nil {
+		t.Fatalf("Acknowledge() = %v, %v, want a transition", transition, err)
+	}
+	if !alert.IsAcknowledged() {
+		t.Fatal("Alert should be acknowledged")
+	}
+
+	// Acknowledging again keeps the first timestamp
+	first := *alert.AcknowledgedAt
+	time.Sleep(time.Millisecond)
+	if transition, err := alert.Acknowledge(time.Now().UTC()); err != nil || transition != nil {
+		t.Fatalf("Acknowledge() second call = %v, %v, want no transition", transition, err)
+	}
+	if !alert.AcknowledgedAt.Equal(first) {
+		t.Errorf("AcknowledgedAt changed on second acknowledge")
+	}
+
+	resolved := NewParentAlert(&Event{DedupKey: "alert-2"}, time.Now().UTC())
+	resolved.Resolve(Resolution{ResolvedBy: ResolvedByAPI}, time.Now().UTC())
+	if _, err := resolved.Acknowledge(time.Now().UTC()); err != ErrAlertAlreadyResolved {
+		t.Errorf("Acknowledge() on resolved alert error = %v, want %v", err, ErrAlertAlreadyResolved)
+	}
+}
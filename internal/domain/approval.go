@@ -0,0 +1,111 @@
+package domain
+
+import "time"
+
+// Errors of the approval workflow.
+var (
+	ErrApprovalNotFound   = notFoundError("approval not found")
+	ErrApprovalNotPending = transitionError("approval is no longer pending")
+	ErrApprovalActor      = invalidError("the actor of the request is required")
+	ErrSameApprover       = conflictError("an operation must be approved by someone other than its requester")
+	ErrApprovalDelayed    = transitionError("the operation runs after its delay and needs no approval; cancel it to stop it")
+)
+
+// Destructive operations held for approval, by name.
+const (
+	OperationPurgeEventManager = "purge_event_manager"
+	OperationPurgeGroupingRule = "purge_grouping_rule"
+	OperationForceResolve      = "force_resolve"
+	OperationDeleteData        = "delete_data"
+)
+
+// ApprovalStatus is the state of an approval.
+type ApprovalStatus string
+
+const (
+	// ApprovalPending means the operation waits for its approval or delay.
+	ApprovalPending ApprovalStatus = "pending"
+	// ApprovalExecuted means the operation ran.
+	ApprovalExecuted ApprovalStatus = "executed"
+	// ApprovalFailed means the operation ran and failed.
+	ApprovalFailed ApprovalStatus = "failed"
+	// ApprovalCanceled means the operation was canceled before it ran.
+	ApprovalCanceled ApprovalStatus = "canceled"
+)
+
+// Approval is a destructive operation held until a second person approves
+// it or, in delay mode, until its delay has passed without it being
+// canceled.
+type Approval struct {
+	ID string `json:"id"`
+
+	// Operation names the operation, e.g. "purge_event_manager".
+	Operation string `json:"operation"`
+
+	// Target identifies what the operation changes, e.g. the event manager ID.
+	Target string `json:"target"`
+
+	// RequestedBy is the actor who requested the operation.
+	RequestedBy string `json:"requested_by"`
+
+	RequestedAt time.Time `json:"requested_at"`
+
+	// ExecuteAfter is when the operation runs unless canceled; set in delay
+	// mode only.
+	ExecuteAfter *time.Time `json:"execute_after,omitempty"`
+
+	Status ApprovalStatus `json:"status"`
+
+	// DecidedBy is the actor who approved or canceled the operation.
+	DecidedBy string `json:"decided_by,omitempty"`
+
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+
+	// Result describes what the operation did, or why it failed.
+	Result string `json:"result,omitempty"`
+}
+
+// IsPending returns true if the operation has neither run nor been canceled.
+func (a *Approval) IsPending() bool {
+	return a.Status == ApprovalPending
+}
+
+// Audit log actions.
+const (
+	AuditApprovalRequested = "approval_requested"
+	AuditApprovalApproved  = "approval_approved"
+	AuditApprovalCanceled  = "approval_canceled"
+	AuditOperationExecuted = "operation_executed"
+	AuditOperationFailed   = "operation_failed"
+)
+
+// AuditEntry is an entry of the audit log: a destructive operation, or a
+// step of its approval, and who took it.
+type AuditEntry struct {
+	At time.Time `json:"at"`
+
+	// Actor is who took the action; empty when the system did, e.g. when
+	// the delay of an operation passed.
+	Actor string `json:"actor,omitempty"`
+
+	// Action is what happened, e.g. "approval_requested".
+	Action string `json:"action"`
+
+	// Operation and Target identify the destructive operation.
+	Operation string `json:"operation"`
+	Target    string `json:"target"`
+
+	// ApprovalID links the entry to its approval, if the operation was held
+	// for one.
+	ApprovalID string `json:"approval_id,omitempty"`
+
+	// Detail describes the outcome.
+	Detail string `json:"detail,omitempty"`
+
+	// Sequence, PrevHash and Hash chain the entry to the one before it, so
+	// changes to the log can be detected; see VerifyAuditLog. They are set
+	// by the audit log when the entry is recorded.
+	Sequence int64  `json:"sequence,omitempty"`
+	PrevHash string `json:"prev_hash,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+}
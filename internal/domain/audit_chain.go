@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// auditDigest lists the fields of an audit entry covered by its hash, in a
+// fixed order, so the hash doesn't depend on how AuditEntry is encoded.
+type auditDigest struct {
+	Sequence   int64  `json:"sequence"`
+	PrevHash   string `json:"prev_hash"`
+	At         string `json:"at"`
+	Actor      string `json:"actor"`
+	Action     string `json:"action"`
+	Operation  string `json:"operation"`
+	Target     string `json:"target"`
+	ApprovalID string `json:"approval_id"`
+	Detail     string `json:"detail"`
+}
+
+// Digest returns the hex SHA-256 of the entry: its sequence, the hash of
+// the entry before it, and its content. Hash is the digest of a chained
+// entry.
+func (e *AuditEntry) Digest() string {
+	data, _ := json.Marshal(auditDigest{
+		Sequence:   e.Sequence,
+		PrevHash:   e.PrevHash,
+		At:         e.At.UTC().Format(time.RFC3339Nano),
+		Actor:      e.Actor,
+		Action:     e.Action,
+		Operation:  e.Operation,
+		Target:     e.Target,
+		ApprovalID: e.ApprovalID,
+		Detail:     e.Detail,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Chain links the entry to prev, the last chained entry of the audit log,
+// or nil if it is the first: it takes the next sequence and prev's hash,
+// and sets its own hash. The time is truncated to the microsecond, the
+// precision of PostgreSQL, so the hash still matches once stored.
+func (e *AuditEntry) Chain(prev *AuditEntry) {
+	e.Sequence = 1
+	e.PrevHash = ""
+	if prev != nil {
+		e.Sequence = prev.Sequence + 1
+		e.PrevHash = prev.Hash
+	}
+	e.At = e.At.UTC().Truncate(time.Microsecond)
+	e.Hash = e.Digest()
+}
+
+// IsChained returns true if the entry was hashed into the chain; entries
+// recorded before the audit log was chained are not.
+func (e *AuditEntry) IsChained() bool {
+	return e.Sequence > 0
+}
+
+// AuditVerification is the outcome of verifying the chain of an audit log.
+type AuditVerification struct {
+	// Valid is true if every chained entry follows the one before it and
+	// matches its hash.
+	Valid bool `json:"valid"`
+
+	// Entries is the number of entries verified, and Unchained how many of
+	// them were recorded before the audit log was chained.
+	Entries   int `json:"entries"`
+	Unchained int `json:"unchained"`
+
+	// Head is the hash of the last entry. Keeping it elsewhere lets a later
+	// verification prove that no entry was removed from the end of the log.
+	Head string `json:"head,omitempty"`
+
+	// BrokenAt is the sequence of the first entry that fails verification,
+	// and Reason why.
+	BrokenAt int64  `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// VerifyAuditLog verifies the chain of an audit log, given oldest first.
+// Entries recorded before the log was chained are only allowed before the
+// first chained entry.
+func VerifyAuditLog(entries []*AuditEntry) *AuditVerification {
+	result := &AuditVerification{Valid: true, Entries: len(entries)}
+
+	var prev *AuditEntry
+	for _, entry := range entries {
+		if !entry.IsChained() {
+			if prev != nil {
+				return result.broken(prev.Sequence+1, "unchained entry after the start of the chain")
+			}
+			result.Unchained++
+			continue
+		}
+
+		wantSequence, wantPrev := int64(1), ""
+		if prev != nil {
+			wantSequence, wantPrev = prev.Sequence+1, prev.Hash
+		}
+		switch {
+		case entry.Sequence != wantSequence:
+			return result.broken(wantSequence, "missing or reordered entry")
+		case entry.PrevHash != wantPrev:
+			return result.broken(entry.Sequence, "previous hash does not match")
+		case entry.Hash != entry.Digest():
+			return result.broken(entry.Sequence, "entry was modified")
+		}
+		prev = entry
+	}
+
+	if prev != nil {
+		result.Head = prev.Hash
+	}
+	return result
+}
+
+// broken marks the verification as failed at an entry.
+func (v *AuditVerification) broken(sequence int64, reason string) *AuditVerification {
+	v.Valid = false
+	v.BrokenAt = sequence
+	v.Reason = reason
+	v.Head = ""
+	return v
+}
+
+// ContainsAuditHash returns true if an entry of the log has the hash, e.g.
+// a head kept from an earlier verification.
+func ContainsAuditHash(entries []*AuditEntry, hash string) bool {
+	for _, entry := range entries {
+		if entry.Hash == hash {
+			return true
+		}
+	}
+	return false
+}
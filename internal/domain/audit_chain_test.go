@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// chainedLog returns an audit log of n chained entries, oldest first.
+func chainedLog(n int) []*AuditEntry {
+	at := time.Date(2026, 10, 15, 9, 0, 0, 123456789, time.UTC)
+	var entries []*AuditEntry
+	var prev *AuditEntry
+	for i := range n {
+		entry := &AuditEntry{
+			At:        at.Add(time.Duration(i) * time.Minute),
+			Actor:     "alice",
+			Action:    AuditOperationExecuted,
+			Operation: "delete_event_manager",
+			Target:    fmt.Sprintf("em-%d", i),
+		}
+		entry.Chain(prev)
+		entries = append(entries, entry)
+		prev = entry
+	}
+	return entries
+}
+
+func TestAuditEntry_Chain(t *testing.T) {
+	entries := chainedLog(2)
+
+	if entries[0].Sequence != 1 || entries[0].PrevHash != "" {
+		t.Errorf("first entry: sequence = %d, prev_hash = %q", entries[0].Sequence, entries[0].PrevHash)
+	}
+	if entries[1].Sequence != 2 || entries[1].PrevHash != entries[0].Hash {
+		t.Errorf("second entry: sequence = %d, prev_hash = %q", entries[1].Sequence, entries[1].PrevHash)
+	}
+	if entries[0].At.Nanosecond() != 123456000 {
+		t.Errorf("At = %v, want truncated to the microsecond", entries[0].At)
+	}
+
+	// The hash survives a round trip through JSON, as in an export
+	data, _ := json.Marshal(entries)
+	var exported []*AuditEntry
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if result := VerifyAuditLog(exported); !result.Valid {
+		t.Errorf("exported log is not valid: %s", result.Reason)
+	}
+}
+
+func TestVerifyAuditLog(t *testing.T) {
+	tests := []struct {
+		name         string
+		tamper       func([]*AuditEntry) []*AuditEntry
+		wantBrokenAt int64
+	}{
+		{
+			name:   "intact",
+			tamper: func(e []*AuditEntry) []*AuditEntry { return e },
+		},
+		{
+			name: "modified entry",
+			tamper: func(e []*AuditEntry) []*AuditEntry {
+				e[1].Actor = "mallory"
+				return e
+			},
+			wantBrokenAt: 2,
+		},
+		{
+			name: "deleted entry",
+			tamper: func(e []*AuditEntry) []*AuditEntry {
+				return append(e[:1], e[2:]...)
+			},
+			wantBrokenAt: 2,
+		},
+		{
+			name: "reordered entries",
+			tamper: func(e []*AuditEntry) []*AuditEntry {
+				e[1], e[2] = e[2], e[1]
+				return e
+			},
+			wantBrokenAt: 2,
+		},
+		{
+			name: "renumbered after deletion",
+			tamper: func(e []*AuditEntry) []*AuditEntry {
+				e = append(e[:1], e[2:]...)
+				e[1].Sequence = 2
+				e[2].Sequence = 3
+				return e
+			},
+			wantBrokenAt: 2,
+		},
+		{
+			name: "rehashed entry",
+			tamper: func(e []*AuditEntry) []*AuditEntry {
+				e[1].Detail = "nothing happened"
+				e[1].Hash = e[1].Digest()
+				return e
+			},
+			wantBrokenAt: 3,
+		},
+		{
+			name: "unchained entries before the chain",
+			tamper: func(e []*AuditEntry) []*AuditEntry {
+				legacy := &AuditEntry{Action: AuditApprovalRequested}
+				return append([]*AuditEntry{legacy}, e...)
+			},
+		},
+		{
+			name: "unchained entry inside the chain",
+			tamper: func(e []*AuditEntry) []*AuditEntry {
+				legacy := &AuditEntry{Action: AuditApprovalRequested}
+				return append(e[:2], append([]*AuditEntry{legacy}, e[2:]...)...)
+			},
+			wantBrokenAt: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := VerifyAuditLog(tt.tamper(chainedLog(4)))
+
+			if tt.wantBrokenAt == 0 {
+				if !result.Valid || result.Head == "" {
+					t.Errorf("VerifyAuditLog() = %+v, want valid with a head", result)
+				}
+				return
+			}
+			if result.Valid || result.BrokenAt != tt.wantBrokenAt {
+				t.Errorf("VerifyAuditLog() = %+v, want broken at %d", result, tt.wantBrokenAt)
+			}
+		})
+	}
+}
+
+func TestContainsAuditHash(t *testing.T) {
+	entries := chainedLog(3)
+	head := VerifyAuditLog(entries).Head
+
+	if !ContainsAuditHash(entries, head) {
+		t.Error("ContainsAuditHash() = false for the head")
+	}
+	// Removing the last entry passes verification, but loses the old head
+	truncated := entries[:2]
+	if !VerifyAuditLog(truncated).Valid || ContainsAuditHash(truncated, head) {
+		t.Error("truncated log should verify but not contain the old head")
+	}
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ciWebhookSource is the source of CI events.
+const ciWebhookSource = "ci"
+
+// CI webhooks turn finished pipelines into events: failures trigger an alert
+// and successes resolve it. Runs of the same workflow of a repository share
+// the dedup key, so a fixed pipeline resolves the alert of its last failure.
+
+// gitHubWorkflowRun is the part of a GitHub workflow_run webhook used.
+type gitHubWorkflowRun struct {
+	Action      string `json:"action"`
+	WorkflowRun struct {
+		Name       string `json:"name"`
+		Conclusion string `json:"conclusion"`
+		HeadBranch string `json:"head_branch"`
+		HTMLURL    string `json:"html_url"`
+		RunNumber  int    `json:"run_number"`
+	} `json:"workflow_run"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// parseGitHubWebhook maps a completed GitHub Actions workflow run.
+func parseGitHubWebhook(header func(string) string, body []byte) (*Event, error) {
+	if eventType := header("X-GitHub-Event"); eventType != "workflow_run" {
+		return nil, fmt.Errorf("%w: event %q", ErrIntegrationWebhookIgnored, eventType)
+	}
+
+	var payload gitHubWorkflowRun
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIntegrationWebhook, err)
+	}
+	run := payload.WorkflowRun
+	if payload.Action != "completed" {
+		return nil, fmt.Errorf("%w: action %q", ErrIntegrationWebhookIgnored, payload.Action)
+	}
+
+	var action Action
+	switch run.Conclusion {
+	case "failure", "timed_out", "startup_failure":
+		action = ActionTrigger
+	case "success":
+		action = ActionResolve
+	default:
+		return nil, fmt.Errorf("%w: conclusion %q", ErrIntegrationWebhookIgnored, run.Conclusion)
+	}
+
+	return newCIEvent(IntegrationGitHub, payload.Repository.FullName, run.Name, action, map[string]string{
+		"branch":     run.HeadBranch,
+		"conclusion": run.Conclusion,
+		"run":        fmt.Sprint(run.RunNumber),
+		"url":        run.HTMLURL,
+	})
+}
+
+// gitLabPipeline is the part of a GitLab pipeline webhook used.
+type gitLabPipeline struct {
+	ObjectAttributes struct {
+		ID     int    `json:"id"`
+		Name   string `json:"name"`
+		Ref    string `json:"ref"`
+		Status string `json:"status"`
+		URL    string `json:"url"`
+	} `json:"object_attributes"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+}
+
+// parseGitLabWebhook maps a finished GitLab pipeline.
+func parseGitLabWebhook(header func(string) string, body []byte) (*Event, error) {
+	if eventType := header("X-Gitlab-Event"); eventType != "Pipeline Hook" {
+		return nil, fmt.Errorf("%w: event %q", ErrIntegrationWebhookIgnored, eventType)
+	}
+
+	var payload gitLabPipeline
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIntegrationWebhook, err)
+	}
+	pipeline := payload.ObjectAttributes
+
+	var action Action
+	switch pipeline.Status {
+	case "failed":
+		action = ActionTrigger
+	case "success":
+		action = ActionResolve
+	default:
+		return nil, fmt.Errorf("%w: status %q", ErrIntegrationWebhookIgnored, pipeline.Status)
+	}
+
+	// Pipelines are unnamed unless the CI config names them
+	workflow := pipeline.Name
+	if workflow == "" {
+		workflow = "pipeline"
+	}
+	return newCIEvent(IntegrationGitLab, payload.Project.PathWithNamespace, workflow, action, map[string]string{
+		"branch": pipeline.Ref,
+		"status": pipeline.Status,
+		"run":    fmt.Sprint(pipeline.ID),
+		"url":    pipeline.URL,
+	})
+}
+
+// newCIEvent builds the event of a pipeline of a repository, keyed by
+// provider, repository and workflow. The repository is the class, so alerts
+// group per repository. Empty labels are left out.
+func newCIEvent(provider, repository, workflow string, action Action, labels map[string]string) (*Event, error) {
+	if repository == "" || workflow == "" {
+		return nil, fmt.Errorf("%w: repository and workflow are required", ErrInvalidIntegrationWebhook)
+	}
+	for key, value := range labels {
+		if value == "" {
+			delete(labels, key)
+		}
+	}
+	labels["provider"] = provider
+	labels["workflow"] = workflow
+
+	return &Event{
+		Summary:  fmt.Sprintf("CI failure: %s on %s", workflow, repository),
+		Action:   action,
+		Class:    repository,
+		DedupKey: provider + ":" + repository + ":" + workflow,
+		Source:   ciWebhookSource,
+		Labels:   labels,
+	}, nil
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseIntegrationWebhook_CI(t *testing.T) {
+	githubRun := func(action, conclusion string) string {
+		return `{"action":"` + action + `","workflow_run":{"name":"CI","conclusion":"` + conclusion +
+			`","head_branch":"main","html_url":"https://github.com/acme/shop/actions/runs/1","run_number":12},` +
+			`"repository":{"full_name":"acme/shop"}}`
+	}
+	gitlabPipeline := func(status string) string {
+		return `{"object_kind":"pipeline","object_attributes":{"id":99,"ref":"main","status":"` + status +
+			`","url":"https://gitlab.com/acme/api/-/pipelines/99"},"project":{"path_with_namespace":"acme/api"}}`
+	}
+
+	tests := []struct {
+		name         string
+		provider     string
+		eventType    string
+		body         string
+		wantAction   Action
+		wantDedupKey string
+		wantErr      error
+	}{
+		{
+			name: "github failure", provider: IntegrationGitHub, eventType: "workflow_run",
+			body: githubRun("completed", "failure"), wantAction: ActionTrigger, wantDedupKey: "github:acme/shop:CI",
+		},
+		{
+			name: "github timeout", provider: IntegrationGitHub, eventType: "workflow_run",
+			body: githubRun("completed", "timed_out"), wantAction: ActionTrigger, wantDedupKey: "github:acme/shop:CI",
+		},
+		{
+			name: "github success", provider: IntegrationGitHub, eventType: "workflow_run",
+			body: githubRun("completed", "success"), wantAction: ActionResolve, wantDedupKey: "github:acme/shop:CI",
+		},
+		{
+			name: "github cancelled", provider: IntegrationGitHub, eventType: "workflow_run",
+			body: githubRun("completed", "cancelled"), wantErr: ErrIntegrationWebhookIgnored,
+		},
+		{
+			name: "github in progress", provider: IntegrationGitHub, eventType: "workflow_run",
+			body: githubRun("in_progress", ""), wantErr: ErrIntegrationWebhookIgnored,
+		},
+		{
+			name: "github ping", provider: IntegrationGitHub, eventType: "ping",
+			body: `{"zen":"Keep it simple."}`, wantErr: ErrIntegrationWebhookIgnored,
+		},
+		{
+			name: "github without repository", provider: IntegrationGitHub, eventType: "workflow_run",
+			body: `{"action":"completed","workflow_run":{"name":"CI","conclusion":"failure"}}`, wantErr: ErrInvalidIntegrationWebhook,
+		},
+		{
+			name: "gitlab failure", provider: IntegrationGitLab, eventType: "Pipeline Hook",
+			body: gitlabPipeline("failed"), wantAction: ActionTrigger, wantDedupKey: "gitlab:acme/api:pipeline",
+		},
+		{
+			name: "gitlab success", provider: IntegrationGitLab, eventType: "Pipeline Hook",
+			body: gitlabPipeline("success"), wantAction: ActionResolve, wantDedupKey: "gitlab:acme/api:pipeline",
+		},
+		{
+			name: "gitlab running", provider: IntegrationGitLab, eventType: "Pipeline Hook",
+			body: gitlabPipeline("running"), wantErr: ErrIntegrationWebhookIgnored,
+		},
+		{
+			name: "gitlab invalid body", provider: IntegrationGitLab, eventType: "Pipeline Hook",
+			body: `not json`, wantErr: ErrInvalidIntegrationWebhook,
+		},
+		{
+			name: "unknown provider", provider: "jenkins", body: `{}`, wantErr: ErrUnknownIntegration,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := func(key string) string {
+				if key == "X-GitHub-Event" || key == "X-Gitlab-Event" {
+					return tt.eventType
+				}
+				return ""
+			}
+			event, err := ParseIntegrationWebhook(tt.provider, header, []byte(tt.body))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseIntegrationWebhook() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseIntegrationWebhook() error = %v", err)
+			}
+			if event.Action != tt.wantAction || event.DedupKey != tt.wantDedupKey {
+				t.Errorf("event = (%q, %q), want (%q, %q)", event.Action, event.DedupKey, tt.wantAction, tt.wantDedupKey)
+			}
+			if event.Summary == "" || event.Class == "" || event.Labels["branch"] != "main" || event.Labels["url"] == "" {
+				t.Errorf("event = %+v, want summary, class, branch and url", event)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Cloud monitoring webhooks turn alert conditions into events: a condition
+// that fires triggers an alert and one that clears resolves it. Both
+// notifications of a condition share the dedup key, and the monitored
+// resource is the class, so the alerts of a resource group together.
+
+// azureCommonAlert is the part of an Azure Monitor alert in the common alert
+// schema used.
+type azureCommonAlert struct {
+	SchemaID string `json:"schemaId"`
+	Data     struct {
+		Essentials struct {
+			AlertID            string   `json:"alertId"`
+			AlertRule          string   `json:"alertRule"`
+			Severity           string   `json:"severity"`
+			SignalType         string   `json:"signalType"`
+			MonitorCondition   string   `json:"monitorCondition"`
+			MonitoringService  string   `json:"monitoringService"`
+			ConfigurationItems []string `json:"configurationItems"`
+			Description        string   `json:"description"`
+		} `json:"essentials"`
+	} `json:"data"`
+}
+
+// parseAzureWebhook maps an Azure Monitor alert sent by an action group with
+// the common alert schema enabled. The alert ID is the same when the alert
+// fires and when it resolves.
+func parseAzureWebhook(_ func(string) string, body []byte) (*Event, error) {
+	var payload azureCommonAlert
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIntegrationWebhook, err)
+	}
+	if payload.SchemaID != "azureMonitorCommonAlertSchema" {
+		return nil, fmt.Errorf("%w: schemaId %q, want azureMonitorCommonAlertSchema", ErrInvalidIntegrationWebhook, payload.SchemaID)
+	}
+	alert := payload.Data.Essentials
+
+	var action Action
+	switch alert.MonitorCondition {
+	case "Fired":
+		action = ActionTrigger
+	case "Resolved":
+		action = ActionResolve
+	default:
+		return nil, fmt.Errorf("%w: monitorCondition %q", ErrIntegrationWebhookIgnored, alert.MonitorCondition)
+	}
+
+	resource := alert.AlertRule
+	if len(alert.ConfigurationItems) > 0 {
+		resource = strings.Join(alert.ConfigurationItems, ",")
+	}
+	return newCloudMonitoringEvent(IntegrationAzure, alert.AlertID, alert.AlertRule, resource, action, map[string]string{
+		"severity":           alert.Severity,
+		"signal_type":        alert.SignalType,
+		"monitoring_service": alert.MonitoringService,
+		"description":        alert.Description,
+	})
+}
+
+// gcpIncidentWebhook is the part of a Google Cloud Monitoring webhook used.
+type gcpIncidentWebhook struct {
+	Incident struct {
+		IncidentID          string `json:"incident_id"`
+		State               string `json:"state"`
+		Summary             string `json:"summary"`
+		URL                 string `json:"url"`
+		PolicyName          string `json:"policy_name"`
+		ScopingProjectID    string `json:"scoping_project_id"`
+		ResourceName        string `json:"resource_name"`
+		ResourceDisplayName string `json:"resource_display_name"`
+		Severity            string `json:"severity"`
+		Condition           struct {
+			Name string `json:"name"`
+		} `json:"condition"`
+		ConditionName string `json:"condition_name"`
+	} `json:"incident"`
+}
+
+// parseGCPWebhook maps a Google Cloud Monitoring incident. A condition opens
+// an incident per monitored resource, so the resource is part of the key.
+func parseGCPWebhook(_ func(string) string, body []byte) (*Event, error) {
+	var payload gcpIncidentWebhook
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIntegrationWebhook, err)
+	}
+	incident := payload.Incident
+
+	var action Action
+	switch incident.State {
+	case "open":
+		action = ActionTrigger
+	case "closed":
+		action = ActionResolve
+	default:
+		return nil, fmt.Errorf("%w: state %q", ErrIntegrationWebhookIgnored, incident.State)
+	}
+
+	conditionID := incident.Condition.Name
+	if conditionID != "" && incident.ResourceName != "" {
+		conditionID += ":" + incident.ResourceName
+	}
+	summary := incident.Summary
+	if summary == "" {
+		summary = incident.PolicyName
+	}
+	resource := incident.ResourceDisplayName
+	if resource == "" {
+		resource = incident.ResourceName
+	}
+	return newCloudMonitoringEvent(IntegrationGCP, conditionID, summary, resource, action, map[string]string{
+		"severity":  incident.Severity,
+		"policy":    incident.PolicyName,
+		"condition": incident.ConditionName,
+		"project":   incident.ScopingProjectID,
+		"incident":  incident.IncidentID,
+		"url":       incident.URL,
+	})
+}
+
+// newCloudMonitoringEvent builds the event of an alert condition on a
+// resource, keyed by integration and condition ID. Empty labels are left out.
+func newCloudMonitoringEvent(integration, conditionID, summary, resource string, action Action, labels map[string]string) (*Event, error) {
+	if conditionID == "" {
+		return nil, fmt.Errorf("%w: alert or condition id is required", ErrInvalidIntegrationWebhook)
+	}
+	if action == ActionTrigger && summary == "" {
+		return nil, fmt.Errorf("%w: alert rule or policy name is required", ErrInvalidIntegrationWebhook)
+	}
+	for key, value := range labels {
+		if value == "" {
+			delete(labels, key)
+		}
+	}
+	labels["provider"] = integration
+
+	return &Event{
+		Summary:  summary,
+		Action:   action,
+		Class:    resource,
+		DedupKey: integration + ":" + conditionID,
+		Source:   integration,
+		Labels:   labels,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseIntegrationWebhook_CloudMonitoring(t *testing.T) {
+	azureAlert := func(condition string) string {
+		return `{"schemaId":"azureMonitorCommonAlertSchema","data":{"essentials":{` +
+			`"alertId":"/subscriptions/s1/providers/Microsoft.AlertsManagement/alerts/b9569717","alertRule":"High CPU",` +
+			`"severity":"Sev1","signalType":"Metric","monitorCondition":"` + condition + `","monitoringService":"Platform",` +
+			`"configurationItems":["vm-web-1"]},"alertContext":{}}}`
+	}
+	gcpIncident := func(state string) string {
+		return `{"incident":{"incident_id":"0.opqiw61fsv7p","state":"` + state + `","summary":"CPU above 90% on web-1",` +
+			`"url":"https://console.cloud.google.com/monitoring/alerting/incidents/0.opqiw61fsv7p","policy_name":"High CPU",` +
+			`"scoping_project_id":"shop-prod","resource_name":"web-1","resource_display_name":"web-1","severity":"Critical",` +
+			`"condition":{"name":"projects/shop-prod/alertPolicies/1/conditions/2"},"condition_name":"CPU"},"version":"1.2"}`
+	}
+
+	tests := []struct {
+		name         string
+		integration  string
+		body         string
+		wantAction   Action
+		wantDedupKey string
+		wantClass    string
+		wantErr      error
+	}{
+		{
+			name: "azure fired", integration: IntegrationAzure, body: azureAlert("Fired"), wantAction: ActionTrigger,
+			wantDedupKey: "azure:/subscriptions/s1/providers/Microsoft.AlertsManagement/alerts/b9569717", wantClass: "vm-web-1",
+		},
+		{
+			name: "azure resolved", integration: IntegrationAzure, body: azureAlert("Resolved"), wantAction: ActionResolve,
+			wantDedupKey: "azure:/subscriptions/s1/providers/Microsoft.AlertsManagement/alerts/b9569717", wantClass: "vm-web-1",
+		},
+		{
+			name: "azure unknown condition", integration: IntegrationAzure, body: azureAlert("Unknown"),
+			wantErr: ErrIntegrationWebhookIgnored,
+		},
+		{
+			name: "azure legacy schema", integration: IntegrationAzure, body: `{"schemaId":"AzureMonitorMetricAlert","data":{}}`,
+			wantErr: ErrInvalidIntegrationWebhook,
+		},
+		{
+			name: "gcp open", integration: IntegrationGCP, body: gcpIncident("open"), wantAction: ActionTrigger,
+			wantDedupKey: "gcp:projects/shop-prod/alertPolicies/1/conditions/2:web-1", wantClass: "web-1",
+		},
+		{
+			name: "gcp closed", integration: IntegrationGCP, body: gcpIncident("closed"), wantAction: ActionResolve,
+			wantDedupKey: "gcp:projects/shop-prod/alertPolicies/1/conditions/2:web-1", wantClass: "web-1",
+		},
+		{
+			name: "gcp without condition", integration: IntegrationGCP, body: `{"incident":{"state":"open","summary":"x"}}`,
+			wantErr: ErrInvalidIntegrationWebhook,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := ParseIntegrationWebhook(tt.integration, func(string) string { return "" }, []byte(tt.body))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseIntegrationWebhook() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseIntegrationWebhook() error = %v", err)
+			}
+			if event.Action != tt.wantAction || event.DedupKey != tt.wantDedupKey || event.Class != tt.wantClass {
+				t.Errorf("event = (%q, %q, %q), want (%q, %q, %q)",
+					event.Action, event.DedupKey, event.Class, tt.wantAction, tt.wantDedupKey, tt.wantClass)
+			}
+			if event.Summary == "" || event.Labels["severity"] == "" || event.Source != tt.integration {
+				t.Errorf("event = %+v, want a summary, severity label and source %q", event, tt.integration)
+			}
+		})
+	}
+}
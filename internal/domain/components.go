@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Limits on the service and components of an event.
+const (
+	MaxEventComponents = 16
+	MaxComponentLength = 128
+)
+
+// Validation errors for the service and components of an event.
+var (
+	ErrTooManyComponents  = invalidError(fmt.Sprintf("an event can list at most %d components", MaxEventComponents))
+	ErrInvalidComponent   = invalidError(fmt.Sprintf("components must be 1 to %d characters", MaxComponentLength))
+	ErrDuplicateComponent = invalidError("components must not repeat")
+	ErrServiceTooLong     = invalidError(fmt.Sprintf("service must be at most %d characters", MaxComponentLength))
+)
+
+// validateComponents checks the service and components of an event.
+func validateComponents(service string, components []string) error {
+	if len(service) > MaxComponentLength {
+		return ErrServiceTooLong
+	}
+	if len(components) > MaxEventComponents {
+		return ErrTooManyComponents
+	}
+	for i, component := range components {
+		if component == "" || len(component) > MaxComponentLength {
+			return ErrInvalidComponent
+		}
+		if slices.Contains(components[:i], component) {
+			return ErrDuplicateComponent
+		}
+	}
+	return nil
+}
+
+// HasComponent reports whether the alert lists the component.
+func (a *Alert) HasComponent(component string) bool {
+	return slices.Contains(a.Components, component)
+}
+
+// componentSet returns the components sorted and joined with commas, so
+// events listing the same components in any order share a grouping value.
+func componentSet(components []string) string {
+	sorted := slices.Clone(components)
+	slices.Sort(sorted)
+	return strings.Join(sorted, ",")
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvent_ValidateComponents(t *testing.T) {
+	many := make([]string, MaxEventComponents+1)
+	for i := range many {
+		many[i] = strings.Repeat("c", i+1)
+	}
+
+	tests := []struct {
+		name       string
+		service    string
+		components []string
+		want       error
+	}{
+		{"none", "", nil, nil},
+		{"valid", "checkout", []string{"db-1", "api-gateway"}, nil},
+		{"long service", strings.Repeat("s", MaxComponentLength+1), nil, ErrServiceTooLong},
+		{"empty component", "", []string{"db-1", ""}, ErrInvalidComponent},
+		{"long component", "", []string{strings.Repeat("c", MaxComponentLength+1)}, ErrInvalidComponent},
+		{"duplicate component", "", []string{"db-1", "db-1"}, ErrDuplicateComponent},
+		{"too many components", "", many, ErrTooManyComponents},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := Event{
+				EventManagerID: "em-1", Summary: "Disk full", Action: ActionTrigger, DedupKey: "disk-1",
+				Service: tt.service, Components: tt.components,
+			}
+			if err := event.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupingRule_ExtractGroupingValue_Components(t *testing.T) {
+	event := &Event{Service: "checkout", Components: []string{"db-1", "api-gateway"}}
+	reordered := &Event{Service: "checkout", Components: []string{"api-gateway", "db-1"}}
+
+	tests := []struct {
+		groupingKey string
+		want        string
+	}{
+		{"service", "checkout"},
+		{"component", "db-1"},
+		{"components", "api-gateway,db-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.groupingKey, func(t *testing.T) {
+			rule := GroupingRule{GroupingKey: tt.groupingKey}
+			if got := rule.ExtractGroupingValue(event); got != tt.want {
+				t.Errorf("ExtractGroupingValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	rule := GroupingRule{GroupingKey: "components"}
+	if a, b := rule.ExtractGroupingValue(event), rule.ExtractGroupingValue(reordered); a != b {
+		t.Errorf("components grouping value depends on order: %q != %q", a, b)
+	}
+	rule = GroupingRule{GroupingKey: "component"}
+	if got := rule.ExtractGroupingValue(&Event{}); got != "" {
+		t.Errorf("component grouping value without components = %q, want empty", got)
+	}
+}
+
+func TestNewChildAlert_Components(t *testing.T) {
+	event := &Event{Service: "checkout", Components: []string{"db-1", "api-gateway"}}
+	alert := NewChildAlert(event, "parent-1", time.Now())
+
+	if alert.Service != "checkout" || !alert.HasComponent("api-gateway") || alert.HasComponent("db-2") {
+		t.Errorf("alert service/components = %q %v", alert.Service, alert.Components)
+	}
+	event.Components[0] = "db-2"
+	if !alert.HasComponent("db-1") {
+		t.Errorf("alert components share the event's slice: %v", alert.Components)
+	}
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Stores a data deletion can skip, by name.
+const (
+	DataStoreExpiredAlerts = "expired_alerts"
+	DataStoreArchive       = "archive"
+)
+
+// DataDeletionReport describes what the deletion of the data of an event
+// manager removed, store by store, e.g. to answer a data deletion request.
+type DataDeletionReport struct {
+	EventManagerID string `json:"event_manager_id"`
+
+	StartedAt time.Time `json:"started_at"`
+
+	// CompletedAt is when the last store was cleared; nil if the deletion
+	// failed before.
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	// Alerts counts the alerts removed, with their history and state.
+	Alerts int `json:"alerts"`
+
+	// ExpiredAlerts counts the alerts removed from partitions detached by
+	// the retention job.
+	ExpiredAlerts int `json:"expired_alerts"`
+
+	Notifications      int `json:"notifications"`
+	RemediationActions int `json:"remediation_actions"`
+	Feedback           int `json:"feedback"`
+	UsageDays          int `json:"usage_days"`
+
+	// ArchivedEvents counts the events removed from the archive, and
+	// ArchiveObjects the objects rewritten or deleted to remove them.
+	ArchivedEvents int `json:"archived_events"`
+	ArchiveObjects int `json:"archive_objects"`
+
+	// Skipped lists the stores that are not configured, e.g. "archive".
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// String summarizes the report in one line, e.g. for the audit log.
+func (r *DataDeletionReport) String() string {
+	summary := fmt.Sprintf("deleted %d alerts, %d expired alerts, %d notifications, %d remediation actions, %d feedback entries, %d usage days and %d archived events in %d objects",
+		r.Alerts, r.ExpiredAlerts, r.Notifications, r.RemediationActions, r.Feedback, r.UsageDays, r.ArchivedEvents, r.ArchiveObjects)
+	if len(r.Skipped) > 0 {
+		summary += "; skipped " + strings.Join(r.Skipped, ", ")
+	}
+	return summary
+}
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Error tracker webhooks turn issues into events: new and regressed issues
+// trigger an alert and resolved ones resolve it. The issue ID is the dedup
+// key, so every change of an issue applies to the same alert, and the
+// project is the class, so errors of a project group together.
+
+// sentryIssueWebhook is the part of a Sentry issue webhook used.
+type sentryIssueWebhook struct {
+	Action string `json:"action"`
+	Data   struct {
+		Issue struct {
+			ID      string `json:"id"`
+			ShortID string `json:"shortId"`
+			Title   string `json:"title"`
+			Culprit string `json:"culprit"`
+			Level   string `json:"level"`
+			WebURL  string `json:"web_url"`
+			Project struct {
+				Slug string `json:"slug"`
+			} `json:"project"`
+		} `json:"issue"`
+	} `json:"data"`
+}
+
+// parseSentryWebhook maps a Sentry issue webhook. Unresolved issues were
+// resolved before and regressed.
+func parseSentryWebhook(header func(string) string, body []byte) (*Event, error) {
+	if resource := header("Sentry-Hook-Resource"); resource != "issue" {
+		return nil, fmt.Errorf("%w: resource %q", ErrIntegrationWebhookIgnored, resource)
+	}
+
+	var payload sentryIssueWebhook
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIntegrationWebhook, err)
+	}
+	issue := payload.Data.Issue
+
+	var action Action
+	switch payload.Action {
+	case "created", "unresolved":
+		action = ActionTrigger
+	case "resolved":
+		action = ActionResolve
+	default:
+		return nil, fmt.Errorf("%w: action %q", ErrIntegrationWebhookIgnored, payload.Action)
+	}
+
+	summary := issue.Title
+	if issue.ShortID != "" {
+		summary = issue.ShortID + ": " + issue.Title
+	}
+	return newErrorTrackerEvent(IntegrationSentry, issue.ID, issue.Project.Slug, summary, action, map[string]string{
+		"action":  payload.Action,
+		"culprit": issue.Culprit,
+		"level":   issue.Level,
+		"url":     issue.WebURL,
+	})
+}
+
+// rollbarItemWebhook is the part of a Rollbar item webhook used.
+type rollbarItemWebhook struct {
+	EventName string `json:"event_name"`
+	Data      struct {
+		Item struct {
+			ID          int64  `json:"id"`
+			Counter     int64  `json:"counter"`
+			Title       string `json:"title"`
+			Environment string `json:"environment"`
+			ProjectID   int64  `json:"project_id"`
+			Level       any    `json:"level"`
+		} `json:"item"`
+		URL string `json:"url"`
+	} `json:"data"`
+}
+
+// rollbarLevels names the numeric levels of Rollbar items.
+var rollbarLevels = map[float64]string{
+	10: "debug",
+	20: "info",
+	30: "warning",
+	40: "error",
+	50: "critical",
+}
+
+// rollbarLevel returns the name of the level of a Rollbar item, which
+// webhooks send as a number or a name.
+func rollbarLevel(level any) string {
+	switch v := level.(type) {
+	case string:
+		return v
+	case float64:
+		return rollbarLevels[v]
+	default:
+		return ""
+	}
+}
+
+// parseRollbarWebhook maps a Rollbar item webhook. Reactivated and reopened
+// items regressed; exp_repeat_item reports an item occurring again and again.
+func parseRollbarWebhook(_ func(string) string, body []byte) (*Event, error) {
+	var payload rollbarItemWebhook
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIntegrationWebhook, err)
+	}
+	item := payload.Data.Item
+
+	var action Action
+	switch payload.EventName {
+	case "new_item", "reactivated_item", "reopened_item", "exp_repeat_item":
+		action = ActionTrigger
+	case "resolved_item":
+		action = ActionResolve
+	default:
+		return nil, fmt.Errorf("%w: event %q", ErrIntegrationWebhookIgnored, payload.EventName)
+	}
+	if item.ID == 0 || item.ProjectID == 0 {
+		return nil, fmt.Errorf("%w: item id and project_id are required", ErrInvalidIntegrationWebhook)
+	}
+
+	summary := item.Title
+	if item.Counter != 0 {
+		summary = fmt.Sprintf("#%d: %s", item.Counter, item.Title)
+	}
+	return newErrorTrackerEvent(IntegrationRollbar, fmt.Sprint(item.ID), fmt.Sprint(item.ProjectID), summary, action, map[string]string{
+		"event":       payload.EventName,
+		"environment": item.Environment,
+		"level":       rollbarLevel(item.Level),
+		"url":         payload.Data.URL,
+	})
+}
+
+// newErrorTrackerEvent builds the event of an issue of a project, keyed by
+// integration and issue ID. Empty labels are left out.
+func newErrorTrackerEvent(integration, issueID, project, summary string, action Action, labels map[string]string) (*Event, error) {
+	if issueID == "" || project == "" {
+		return nil, fmt.Errorf("%w: issue id and project are required", ErrInvalidIntegrationWebhook)
+	}
+	if action == ActionTrigger && summary == "" {
+		return nil, fmt.Errorf("%w: issue title is required", ErrInvalidIntegrationWebhook)
+	}
+	for key, value := range labels {
+		if value == "" {
+			delete(labels, key)
+		}
+	}
+	labels["provider"] = integration
+
+	return &Event{
+		Summary:  summary,
+		Action:   action,
+		Class:    project,
+		DedupKey: integration + ":" + issueID,
+		Source:   integration,
+		Labels:   labels,
+	}, nil
+}
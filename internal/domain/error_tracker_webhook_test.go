@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseIntegrationWebhook_ErrorTrackers(t *testing.T) {
+	sentryIssue := func(action string) string {
+		return `{"action":"` + action + `","data":{"issue":{"id":"1170820242","shortId":"SHOP-1","title":"TypeError: x is undefined",` +
+			`"culprit":"checkout.js","level":"error","web_url":"https://sentry.io/issues/1170820242/","project":{"slug":"shop"}}}}`
+	}
+	rollbarItem := func(event, level string) string {
+		return `{"event_name":"` + event + `","data":{"item":{"id":272505123,"counter":4,"title":"NameError",` +
+			`"environment":"production","project_id":90,"level":` + level + `},"url":"https://rollbar.com/acme/shop/items/4/"}}`
+	}
+
+	tests := []struct {
+		name         string
+		integration  string
+		resource     string
+		body         string
+		wantAction   Action
+		wantDedupKey string
+		wantClass    string
+		wantLevel    string
+		wantErr      error
+	}{
+		{
+			name: "sentry new issue", integration: IntegrationSentry, resource: "issue", body: sentryIssue("created"),
+			wantAction: ActionTrigger, wantDedupKey: "sentry:1170820242", wantClass: "shop", wantLevel: "error",
+		},
+		{
+			name: "sentry regression", integration: IntegrationSentry, resource: "issue", body: sentryIssue("unresolved"),
+			wantAction: ActionTrigger, wantDedupKey: "sentry:1170820242", wantClass: "shop", wantLevel: "error",
+		},
+		{
+			name: "sentry resolved", integration: IntegrationSentry, resource: "issue", body: sentryIssue("resolved"),
+			wantAction: ActionResolve, wantDedupKey: "sentry:1170820242", wantClass: "shop", wantLevel: "error",
+		},
+		{
+			name: "sentry assigned", integration: IntegrationSentry, resource: "issue", body: sentryIssue("assigned"),
+			wantErr: ErrIntegrationWebhookIgnored,
+		},
+		{
+			name: "sentry installation", integration: IntegrationSentry, resource: "installation", body: `{}`,
+			wantErr: ErrIntegrationWebhookIgnored,
+		},
+		{
+			name: "sentry without issue", integration: IntegrationSentry, resource: "issue", body: `{"action":"created"}`,
+			wantErr: ErrInvalidIntegrationWebhook,
+		},
+		{
+			name: "rollbar new item", integration: IntegrationRollbar, body: rollbarItem("new_item", "40"),
+			wantAction: ActionTrigger, wantDedupKey: "rollbar:272505123", wantClass: "90", wantLevel: "error",
+		},
+		{
+			name: "rollbar reactivated item", integration: IntegrationRollbar, body: rollbarItem("reactivated_item", `"critical"`),
+			wantAction: ActionTrigger, wantDedupKey: "rollbar:272505123", wantClass: "90", wantLevel: "critical",
+		},
+		{
+			name: "rollbar resolved item", integration: IntegrationRollbar, body: rollbarItem("resolved_item", "40"),
+			wantAction: ActionResolve, wantDedupKey: "rollbar:272505123", wantClass: "90", wantLevel: "error",
+		},
+		{
+			name: "rollbar occurrence", integration: IntegrationRollbar, body: rollbarItem("occurrence", "40"),
+			wantErr: ErrIntegrationWebhookIgnored,
+		},
+		{
+			name: "rollbar invalid body", integration: IntegrationRollbar, body: `[]`,
+			wantErr: ErrInvalidIntegrationWebhook,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := func(key string) string {
+				if key == "Sentry-Hook-Resource" {
+					return tt.resource
+				}
+				return ""
+			}
+			event, err := ParseIntegrationWebhook(tt.integration, header, []byte(tt.body))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseIntegrationWebhook() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseIntegrationWebhook() error = %v", err)
+			}
+			if event.Action != tt.wantAction || event.DedupKey != tt.wantDedupKey || event.Class != tt.wantClass {
+				t.Errorf("event = (%q, %q, %q), want (%q, %q, %q)",
+					event.Action, event.DedupKey, event.Class, tt.wantAction, tt.wantDedupKey, tt.wantClass)
+			}
+			if event.Labels["level"] != tt.wantLevel || event.Labels["url"] == "" || event.Source != tt.integration {
+				t.Errorf("event = %+v, want level %q, a url and source %q", event, tt.wantLevel, tt.integration)
+			}
+		})
+	}
+}
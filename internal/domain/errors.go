@@ -0,0 +1,63 @@
+package domain
+
+import "errors"
+
+// ErrorKind classifies domain errors by what they tell the caller, so every
+// API maps the errors of a kind the same way.
+type ErrorKind string
+
+const (
+	// ErrorKindNotFound is the kind of errors for a missing resource.
+	ErrorKindNotFound ErrorKind = "not_found"
+	// ErrorKindConflict is the kind of errors for a change conflicting with
+	// the current resources: an ID taken, a resource still referenced.
+	ErrorKindConflict ErrorKind = "conflict"
+	// ErrorKindInvalid is the kind of errors for invalid input.
+	ErrorKindInvalid ErrorKind = "invalid"
+	// ErrorKindInvalidTransition is the kind of errors for an operation the
+	// state of a resource doesn't allow, e.g. acknowledging a resolved alert.
+	ErrorKindInvalidTransition ErrorKind = "invalid_transition"
+)
+
+// Error is a domain error of a kind. Errors of the domain are sentinels of
+// this type, compared with errors.Is; wrapping them keeps their kind.
+type Error struct {
+	kind    ErrorKind
+	message string
+}
+
+// Error returns the message of the error.
+func (e *Error) Error() string {
+	return e.message
+}
+
+// Kind returns the kind of the error.
+func (e *Error) Kind() ErrorKind {
+	return e.kind
+}
+
+// KindOf returns the kind of the first domain error err wraps, or "" if it
+// wraps none, e.g. for a failure of a store.
+func KindOf(err error) ErrorKind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.kind
+	}
+	return ""
+}
+
+func notFoundError(message string) error {
+	return &Error{kind: ErrorKindNotFound, message: message}
+}
+
+func conflictError(message string) error {
+	return &Error{kind: ErrorKindConflict, message: message}
+}
+
+func invalidError(message string) error {
+	return &Error{kind: ErrorKindInvalid, message: message}
+}
+
+func transitionError(message string) error {
+	return &Error{kind: ErrorKindInvalidTransition, message: message}
+}
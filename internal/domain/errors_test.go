@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorKind
+	}{
+		{name: "not found", err: ErrAlertNotFound, want: ErrorKindNotFound},
+		{name: "conflict", err: ErrEventManagerAlreadyExists, want: ErrorKindConflict},
+		{name: "invalid", err: ErrEmptySummary, want: ErrorKindInvalid},
+		{name: "invalid transition", err: ErrAlertAlreadyResolved, want: ErrorKindInvalidTransition},
+		{name: "wrapped", err: fmt.Errorf("failed to purge: %w", ErrGroupingRuleNotDeleted), want: ErrorKindInvalidTransition},
+		{name: "validation of an import", err: (&AlertImportRequest{}).Validate(time.Now()), want: ErrorKindInvalid},
+		{name: "joined", err: errors.Join(errors.New("timeout"), ErrSilenceNotFound), want: ErrorKindNotFound},
+		{name: "other", err: errors.New("connection refused"), want: ""},
+		{name: "nil", err: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KindOf(tt.err); got != tt.want {
+				t.Errorf("KindOf(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	err := fmt.Errorf("%w: payments", ErrGroupingRuleInUse)
+	if !errors.Is(err, ErrGroupingRuleInUse) || errors.Is(err, ErrGroupingRuleRecentlyUsed) {
+		t.Errorf("errors.Is(%v) matches the wrong sentinels", err)
+	}
+	if err.Error() != "grouping rule is still referenced by event managers: payments" {
+		t.Errorf("Error() = %q, want the message of the sentinel", err.Error())
+	}
+}
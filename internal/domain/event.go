@@ -2,10 +2,7 @@
 // These models represent the ubiquitous language of the alert management domain.
 package domain
 
-import (
-	"errors"
-	"time"
-)
+import "time"
 
 // Action represents the intent of an incoming event.
 type Action string
@@ -17,9 +14,11 @@ const (
 	ActionResolve Action = "resolve"
 )
 
-// Severity represents the severity level of an alert.
+// Severity represents the severity level of an alert. The levels are those
+// of the current SeverityScale.
 type Severity string
 
+// Severity levels of the default scale.
 const (
 	SeverityHigh   Severity = "high"
 	SeverityMedium Severity = "medium"
@@ -44,29 +43,54 @@ type Event struct {
 	// Class is the classification/category of the alert.
 	Class string `json:"class"`
 
+	// Service optionally names the service or group of the affected
+	// components, e.g. "checkout".
+	Service string `json:"service,omitempty"`
+
+	// Components optionally lists the affected components, e.g. "db-1" and
+	// "api-gateway", most significant first.
+	Components []string `json:"components,omitempty"`
+
 	// DedupKey is the unique identifier for deduplication.
 	DedupKey string `json:"dedupKey"`
+
+	// Source optionally identifies the system that sent the event.
+	Source string `json:"source,omitempty"`
+
+	// Labels are optional key/value pairs describing the event.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Tags are optional key/value pairs merged onto the alert of a trigger,
+	// so senders can enrich an open alert across repeated triggers.
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Links are optional links to external objects, like dashboards or
+	// tickets, added to the alert of a trigger.
+	Links []AlertLink `json:"links,omitempty"`
 }
 
 // Validation errors for Event.
 var (
-	ErrEmptyEventManagerID = errors.New("event_manager_id is required")
-	ErrEmptySummary        = errors.New("summary is required")
-	ErrInvalidSeverity     = errors.New("severity must be 'high', 'medium', or 'low'")
-	ErrInvalidAction       = errors.New("action must be 'trigger' or 'resolve'")
-	ErrEmptyDedupKey       = errors.New("dedupKey is required")
+	ErrEmptyEventManagerID = invalidError("event_manager_id is required")
+	ErrEmptySummary        = invalidError("summary is required")
+	ErrInvalidSeverity     = invalidError("severity is not a configured severity level")
+	ErrInvalidAction       = invalidError("action must be 'trigger' or 'resolve'")
+	ErrEmptyDedupKey       = invalidError("dedupKey is required")
 )
 
 // Validate checks if the event has all required fields with valid values.
-// Returns an error describing the first validation failure, or nil if valid.
+// Resolve events only need an event manager and a dedup key. A missing
+// severity is allowed; it is filled in from the event manager's defaults
+// at ingestion. Returns an error describing the first validation failure,
+// or nil if valid.
 func (e *Event) Validate() error {
 	if e.EventManagerID == "" {
 		return ErrEmptyEventManagerID
 	}
-	if e.Summary == "" {
+	if e.Action == ActionTrigger && e.Summary == "" {
 		return ErrEmptySummary
 	}
-	if !e.Severity.IsValid() {
+	if e.Severity != "" && !e.Severity.IsValid() {
 		return ErrInvalidSeverity
 	}
 	if !e.Action.IsValid() {
@@ -75,17 +99,13 @@ func (e *Event) Validate() error {
 	if e.DedupKey == "" {
 		return ErrEmptyDedupKey
 	}
-	return nil
-}
-
-// IsValid returns true if the severity is a known valid value.
-func (s Severity) IsValid() bool {
-	switch s {
-	case SeverityHigh, SeverityMedium, SeverityLow:
-		return true
-	default:
-		return false
+	if err := validateComponents(e.Service, e.Components); err != nil {
+		return err
+	}
+	if err := validateTags(e.Tags); err != nil {
+		return err
 	}
+	return validateLinks(e.Links)
 }
 
 // IsValid returns true if the action is a known valid value.
@@ -103,14 +123,66 @@ func (a Action) IsValid() bool {
 type InternalEvent struct {
 	Event
 
+	// EventID identifies the event in the pipeline, so its status can be
+	// looked up. Empty for events queued before IDs were assigned.
+	EventID string `json:"event_id,omitempty"`
+
 	// PartitionKey is the computed key for message queue partitioning.
 	// Format: hash(event_manager_id + grouping_key_value)
 	PartitionKey string `json:"partition_key"`
 
+	// OriginalDedupKey is the dedup key before it was replaced by its hash at
+	// ingestion. Empty if the key was not hashed.
+	OriginalDedupKey string `json:"original_dedupKey,omitempty"`
+
+	// GroupingRuleID is the grouping rule selected for the event at ingestion.
+	GroupingRuleID string `json:"grouping_rule_id,omitempty"`
+
 	// GroupingValue is the value extracted from the event based on the grouping rule.
 	// For example, if grouping_key is "class", this would be the event's class value.
 	GroupingValue string `json:"grouping_value"`
 
+	// SampledEvents is the number of triggers of the dedup key dropped by
+	// sampling since the last one queued.
+	SampledEvents int `json:"sampled_events,omitempty"`
+
+	// Origin records where the event was received from. Nil for events
+	// ingested without a known origin.
+	Origin *EventOrigin `json:"origin,omitempty"`
+
+	// Resolution is set on the resolves of parent alerts requested through
+	// the API: the parent is resolved with its active children at once,
+	// recording the resolution, instead of waiting for the children.
+	Resolution *Resolution `json:"resolution,omitempty"`
+
+	// SweepPendingResolve is set on the resolves queued by the pending
+	// resolve sweeper: the processor checks again whether the parent alert
+	// left waiting for its children can be resolved, in order with the
+	// events of its group.
+	SweepPendingResolve bool `json:"sweep_pending_resolve,omitempty"`
+
 	// ReceivedAt is the timestamp when the event was received by the ingest service.
 	ReceivedAt time.Time `json:"received_at"`
+
+	// CorrelationID identifies the request or message the event was
+	// ingested from, e.g. its request ID, in the logs of every component
+	// handling it.
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// EventOrigin identifies the system that sent an event, so a noisy alert
+// stream can be traced back to it.
+type EventOrigin struct {
+	// Source is the event's own source field.
+	Source string `json:"source,omitempty"`
+
+	// IP is the address of the client that sent the event.
+	IP string `json:"ip,omitempty"`
+
+	// UserAgent is the User-Agent header of the request.
+	UserAgent string `json:"user_agent,omitempty"`
+
+	// Via is how the event was received, e.g. "api", "ingest_token" or
+	// "integration:<name>". Credentials themselves are never recorded.
+	Via string `json:"via,omitempty"`
 }
@@ -1,7 +1,13 @@
 package domain
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"maps"
+	"reflect"
+	"strings"
 	"time"
 )
 
@@ -17,84 +23,671 @@ type EventManager struct {
 	// Description provides additional context about this event manager.
 	Description string `json:"description"`
 
-	// GroupingRuleID links to the grouping rule that applies to this event manager.
-	// For MVP, this is a 1:1 relationship.
+	// GroupingRuleID links to the default grouping rule of this event manager,
+	// used for events not matched by any entry of GroupingRules. If empty, the
+	// system-wide default grouping rule applies.
 	GroupingRuleID string `json:"grouping_rule_id"`
 
+	// GroupingRules is an ordered list of grouping rules selected by event
+	// matchers. The first entry matching an event decides how it is grouped.
+	GroupingRules []GroupingRuleBinding `json:"grouping_rules,omitempty"`
+
+	// CandidateGroupingRuleID links to a grouping rule evaluated alongside
+	// the active rules without affecting alerts: the processor records how
+	// often it would have grouped events differently, so a rule change can be
+	// validated on live traffic before it is made. Empty disables it.
+	CandidateGroupingRuleID string `json:"candidate_grouping_rule_id,omitempty"`
+
+	// GroupingDisabled opts the event manager out of grouping: every event
+	// creates an independent parent alert.
+	GroupingDisabled bool `json:"grouping_disabled"`
+
+	// DedupKeyConfig controls how dedup keys of incoming events are normalized.
+	DedupKeyConfig DedupKeyConfig `json:"dedup_key_config"`
+
+	// EventDefaults fills in optional fields missing from incoming events.
+	EventDefaults EventDefaults `json:"event_defaults"`
+
+	// WebhookTransform maps third-party webhook bodies sent to
+	// POST /v1/webhooks/:ingest_token to events. Unset disables the endpoint
+	// for this event manager.
+	WebhookTransform WebhookTransform `json:"webhook_transform"`
+
+	// Quota limits the events and open alerts of the event manager. Unset
+	// means unlimited.
+	Quota Quota `json:"quota"`
+
+	// Sampling thins out the repeated triggers of active alerts. Unset
+	// processes every trigger.
+	Sampling SamplingPolicy `json:"sampling"`
+
+	// Storm overrides the storm detection settings for the event manager.
+	// Unset uses the configured defaults.
+	Storm StormPolicy `json:"storm"`
+
+	// Topic is the dedicated queue topic of the event manager's events, to
+	// isolate a large tenant from the shared topic. Empty uses the shared
+	// topic.
+	Topic string `json:"topic"`
+
+	// Runbooks are copied onto the alerts of the event manager and sent with
+	// their notifications.
+	Runbooks RunbookConfig `json:"runbooks"`
+
+	// Remediations run automatically when alerts of the event manager
+	// trigger.
+	Remediations []RemediationAction `json:"remediations,omitempty"`
+
 	// NotificationConfig contains webhook configuration for alert notifications.
 	NotificationConfig NotificationConfig `json:"notification_config"`
 
+	// ResolutionPolicy decides how resolving a parent alert treats its
+	// active children. Empty means ResolveWithAll.
+	ResolutionPolicy ResolutionPolicy `json:"resolution_policy"`
+
+	// TagPolicy decides how the tags of trigger events are merged onto an
+	// alert that has them. Empty means TagPolicyAdd.
+	TagPolicy TagPolicy `json:"tag_policy"`
+
+	// ParentSummary decides the summary parent alerts display for their
+	// group. By default they keep the summary of their first event.
+	ParentSummary ParentSummaryPolicy `json:"parent_summary"`
+
+	// Locale is the language of the event manager's notification messages,
+	// e.g. "de" or "pt-BR". Empty means DefaultLocale.
+	Locale string `json:"locale"`
+
+	// WebhookSignatures holds the secrets inbound webhooks are signed with,
+	// by source. Webhooks of a source with a secret are rejected unless they
+	// are signed with it.
+	WebhookSignatures WebhookSignatures `json:"webhook_signatures,omitempty"`
+
+	// Links are link templates rendered for every new alert, adding links
+	// to the dashboards and log queries of what the alert is about.
+	Links []AlertLinkTemplate `json:"links,omitempty"`
+
+	// IngestToken is a secret that identifies the event manager in the URL
+	// POST /v1/events/:ingest_token, for senders that cannot set a body field
+	// or headers. Empty for event managers created before tokens existed
+	// until one is rotated in.
+	IngestToken string `json:"ingest_token,omitempty"`
+
 	// CreatedAt is when the event manager was created.
 	CreatedAt time.Time `json:"created_at"`
 
 	// UpdatedAt is when the event manager was last modified.
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// DeletedAt is when the event manager was soft-deleted. Nil if not deleted.
+	// Deleted event managers reject new events until they are purged.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// ResolutionPolicy decides how resolving a parent alert treats its active children.
+type ResolutionPolicy string
+
+const (
+	// ResolveWithAll keeps a parent with active children in resolve_requested
+	// until its last child resolves. It is the default.
+	ResolveWithAll ResolutionPolicy = "resolve-with-all"
+	// ResolveWithAny resolves the parent immediately, leaving its children active.
+	ResolveWithAny ResolutionPolicy = "resolve-with-any"
+	// AutoResolveChildren resolves the parent immediately together with all
+	// its active children.
+	AutoResolveChildren ResolutionPolicy = "auto-resolve-children"
+)
+
+// IsValid reports whether the policy is empty or a known policy.
+func (p ResolutionPolicy) IsValid() bool {
+	switch p {
+	case "", ResolveWithAll, ResolveWithAny, AutoResolveChildren:
+		return true
+	}
+	return false
+}
+
+// GroupingRuleBinding applies a grouping rule to the events selected by a matcher.
+type GroupingRuleBinding struct {
+	// GroupingRuleID is the grouping rule applied to matching events.
+	GroupingRuleID string `json:"grouping_rule_id" yaml:"grouping_rule_id"`
+
+	// Match selects the events this binding applies to.
+	Match EventMatcher `json:"match" yaml:"match"`
+}
+
+// EventMatcher selects events by class, severity and expression.
+// An empty list matches any value; a matcher with no conditions matches every event.
+type EventMatcher struct {
+	// Classes lists the event classes to match.
+	Classes []string `json:"classes,omitempty" yaml:"classes,omitempty"`
+
+	// Severities lists the event severities to match.
+	Severities []Severity `json:"severities,omitempty" yaml:"severities,omitempty"`
+
+	// Expression is a condition over any event field, for matches the
+	// lists can't express.
+	Expression Expression `json:"expression,omitempty" yaml:"expression,omitempty"`
+}
+
+// Matches returns true if the event satisfies every condition of the matcher.
+func (m *EventMatcher) Matches(event *Event) bool {
+	if len(m.Classes) > 0 && !containsString(m.Classes, event.Class) {
+		return false
+	}
+	if len(m.Severities) > 0 {
+		matched := false
+		for _, severity := range m.Severities {
+			if severity == event.Severity {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return m.Expression.Matches(event)
+}
+
+// containsString returns true if values contains s.
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// validateGrouping checks the grouping settings of an event manager: rules may
+// only be set while grouping is enabled, and every binding must name a rule
+// and use known severities and a valid expression.
+func validateGrouping(disabled bool, groupingRuleID, candidateRuleID string, bindings []GroupingRuleBinding) error {
+	if disabled && (groupingRuleID != "" || candidateRuleID != "" || len(bindings) > 0) {
+		return ErrGroupingDisabledWithRules
+	}
+	for _, b := range bindings {
+		if b.GroupingRuleID == "" {
+			return ErrEmptyGroupingRuleID
+		}
+		for _, severity := range b.Match.Severities {
+			if !severity.IsValid() {
+				return ErrInvalidSeverity
+			}
+		}
+		if err := b.Match.Expression.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hashedDedupKeyPrefix marks dedup keys replaced by their hash.
+const hashedDedupKeyPrefix = "sha256:"
+
+// HashedDedupKeyLength is the length of a hashed dedup key.
+const HashedDedupKeyLength = len(hashedDedupKeyPrefix) + 2*sha256.Size
+
+// DefaultSeverity is the default level of the default severity scale.
+const DefaultSeverity = SeverityLow
+
+// EventDefaults holds the values applied to optional fields that incoming
+// events of an event manager leave empty.
+type EventDefaults struct {
+	// Severity is applied to events without a severity. Empty means the
+	// default level of the severity scale.
+	Severity Severity `json:"severity,omitempty" yaml:"severity,omitempty"`
+}
+
+// Validate checks the defaults are valid values.
+func (d *EventDefaults) Validate() error {
+	if d.Severity != "" && !d.Severity.IsValid() {
+		return ErrInvalidDefaultSeverity
+	}
+	return nil
+}
+
+// Apply fills in the fields the event leaves empty.
+func (d *EventDefaults) Apply(event *Event) {
+	if event.Severity == "" {
+		event.Severity = d.Severity
+		if event.Severity == "" {
+			event.Severity = CurrentSeverityScale().Default()
+		}
+	}
+}
+
+// DedupKeyConfig controls how the dedup keys of an event manager's events are
+// normalized at ingestion, before they are used for deduplication.
+type DedupKeyConfig struct {
+	// Trim removes leading and trailing whitespace.
+	Trim bool `json:"trim" yaml:"trim,omitempty"`
+
+	// Lowercase converts the key to lower case.
+	Lowercase bool `json:"lowercase" yaml:"lowercase,omitempty"`
+
+	// HashThreshold replaces keys longer than this many bytes (after trimming
+	// and lowercasing) by their SHA-256 hash. Zero disables hashing; otherwise
+	// it must be at least HashedDedupKeyLength.
+	HashThreshold int `json:"hash_threshold" yaml:"hash_threshold,omitempty"`
+}
+
+// Validate checks the hash threshold is disabled or large enough to hold a hash.
+func (c *DedupKeyConfig) Validate() error {
+	if c.HashThreshold != 0 && c.HashThreshold < HashedDedupKeyLength {
+		return ErrInvalidHashThreshold
+	}
+	return nil
+}
+
+// Normalize applies the configuration to a dedup key. If the key is hashed,
+// the normalized key before hashing is returned as original; otherwise
+// original is empty.
+func (c *DedupKeyConfig) Normalize(key string) (normalized, original string) {
+	if c.Trim {
+		key = strings.TrimSpace(key)
+	}
+	if c.Lowercase {
+		key = strings.ToLower(key)
+	}
+	if c.HashThreshold > 0 && len(key) > c.HashThreshold {
+		sum := sha256.Sum256([]byte(key))
+		return hashedDedupKeyPrefix + hex.EncodeToString(sum[:]), key
+	}
+	return key, ""
 }
 
 // NotificationConfig holds webhook settings for sending alert notifications.
 type NotificationConfig struct {
 	// WebhookURL is the endpoint to send notifications to.
-	WebhookURL string `json:"webhook_url"`
+	WebhookURL string `json:"webhook_url" yaml:"webhook_url,omitempty"`
+
+	// Plugin names a notifier plugin of the deployment that also receives
+	// the notifications, for channels ArgusGo doesn't support natively.
+	Plugin string `json:"plugin,omitempty" yaml:"plugin,omitempty"`
+
+	// Queue names a notification queue of the deployment that the
+	// notifications are also published to, for streaming consumers.
+	Queue string `json:"queue,omitempty" yaml:"queue,omitempty"`
+
+	// ReminderIntervalMinutes resends the notification of a parent alert that
+	// is still active and unacknowledged after this many minutes, as an
+	// escalating reminder. Zero disables reminders.
+	ReminderIntervalMinutes int `json:"reminder_interval_minutes" yaml:"reminder_interval_minutes,omitempty"`
+
+	// MaxReminders is how many reminders are sent at most per alert.
+	MaxReminders int `json:"max_reminders" yaml:"max_reminders,omitempty"`
+
+	// Events opts into notifications of alert changes beyond new and
+	// resolved parents and reminders, which are always sent.
+	Events NotificationEvents `json:"events" yaml:"events,omitempty"`
+}
+
+// NotificationEvents enables the optional lifecycle notifications of an
+// event manager, each independently.
+type NotificationEvents struct {
+	// ChildAdded notifies when a child alert is grouped under a parent.
+	ChildAdded bool `json:"child_added" yaml:"child_added,omitempty"`
+
+	// Reactivated notifies when a resolved alert is triggered again.
+	Reactivated bool `json:"reactivated" yaml:"reactivated,omitempty"`
+
+	// Acknowledged notifies when a responder acknowledges an alert.
+	Acknowledged bool `json:"acknowledged" yaml:"acknowledged,omitempty"`
+
+	// Escalated notifies when a parent alert is still unacknowledged after
+	// its last reminder.
+	Escalated bool `json:"escalated" yaml:"escalated,omitempty"`
+}
+
+// Sends reports whether notifications of the given kind are sent. New
+// parent, resolved and reminder notifications are not optional.
+func (c *NotificationConfig) Sends(kind NotificationKind) bool {
+	switch kind {
+	case NotificationChildAdded:
+		return c.Events.ChildAdded
+	case NotificationReactivated:
+		return c.Events.Reactivated
+	case NotificationAcknowledged:
+		return c.Events.Acknowledged
+	case NotificationEscalated:
+		return c.Events.Escalated
+	default:
+		return true
+	}
+}
+
+// Validate checks the reminder settings are consistent.
+func (c *NotificationConfig) Validate() error {
+	if c.ReminderIntervalMinutes < 0 || c.MaxReminders < 0 {
+		return ErrInvalidReminderConfig
+	}
+	if (c.ReminderIntervalMinutes == 0) != (c.MaxReminders == 0) {
+		return ErrInvalidReminderConfig
+	}
+	return nil
+}
+
+// RemindersEnabled reports whether reminders are sent for unacknowledged alerts.
+func (c *NotificationConfig) RemindersEnabled() bool {
+	return c.ReminderIntervalMinutes > 0 && c.MaxReminders > 0
+}
+
+// ReminderInterval returns the time between reminders.
+func (c *NotificationConfig) ReminderInterval() time.Duration {
+	return time.Duration(c.ReminderIntervalMinutes) * time.Minute
 }
 
 // Validation errors for EventManager.
 var (
-	ErrEmptyEventManagerName     = errors.New("name is required")
-	ErrEmptyGroupingRuleID       = errors.New("grouping_rule_id is required")
-	ErrEventManagerNotFound      = errors.New("event manager not found")
-	ErrEventManagerAlreadyExists = errors.New("event manager already exists")
+	ErrEmptyEventManagerName     = invalidError("name is required")
+	ErrEmptyGroupingRuleID       = invalidError("grouping_rule_id is required")
+	ErrGroupingDisabledWithRules = invalidError("grouping rules cannot be set when grouping is disabled")
+	ErrInvalidHashThreshold      = invalidError("dedup_key_config.hash_threshold must be 0 or at least 71")
+	ErrInvalidDefaultSeverity    = invalidError("event_defaults.severity is not a configured severity level")
+	ErrInvalidResolutionPolicy   = invalidError("resolution_policy must be 'resolve-with-all', 'resolve-with-any', or 'auto-resolve-children'")
+	ErrInvalidReminderConfig     = invalidError("notification_config.reminder_interval_minutes and max_reminders must both be positive or both be 0")
+	ErrEventManagerNotFound      = notFoundError("event manager not found")
+	ErrEventManagerAlreadyExists = conflictError("event manager already exists")
+	ErrEventManagerDeleted       = conflictError("event manager has been deleted")
+	ErrEventManagerNotDeleted    = transitionError("event manager must be deleted before it can be purged")
+	ErrInvalidIngestToken        = errors.New("invalid ingest token")
+	ErrInvalidTopic              = invalidError("topic must be at most 249 letters, digits, '.', '_' or '-'")
 )
 
+// maxTopicLength is the longest topic name Kafka accepts.
+const maxTopicLength = 249
+
+// validateTopic checks a dedicated topic is a valid Kafka topic name. Empty
+// is valid and uses the shared topic.
+func validateTopic(topic string) error {
+	if len(topic) > maxTopicLength || topic == "." || topic == ".." {
+		return ErrInvalidTopic
+	}
+	for _, c := range topic {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '.', c == '_', c == '-':
+		default:
+			return ErrInvalidTopic
+		}
+	}
+	return nil
+}
+
 // Validate checks if the event manager has all required fields.
 func (em *EventManager) Validate() error {
 	if em.Name == "" {
 		return ErrEmptyEventManagerName
 	}
-	if em.GroupingRuleID == "" {
-		return ErrEmptyGroupingRuleID
+	if err := em.DedupKeyConfig.Validate(); err != nil {
+		return err
 	}
-	return nil
+	if err := em.EventDefaults.Validate(); err != nil {
+		return err
+	}
+	if err := em.WebhookTransform.Validate(); err != nil {
+		return err
+	}
+	if err := em.Quota.Validate(); err != nil {
+		return err
+	}
+	if err := em.Sampling.Validate(); err != nil {
+		return err
+	}
+	if err := em.Storm.Validate(); err != nil {
+		return err
+	}
+	if err := validateTopic(em.Topic); err != nil {
+		return err
+	}
+	if err := em.Runbooks.Validate(); err != nil {
+		return err
+	}
+	if err := validateRemediations(em.Remediations); err != nil {
+		return err
+	}
+	if err := em.NotificationConfig.Validate(); err != nil {
+		return err
+	}
+	if !em.ResolutionPolicy.IsValid() {
+		return ErrInvalidResolutionPolicy
+	}
+	if !em.TagPolicy.IsValid() {
+		return ErrInvalidTagPolicy
+	}
+	if err := em.ParentSummary.Validate(); err != nil {
+		return err
+	}
+	if err := validateLocale(em.Locale); err != nil {
+		return err
+	}
+	if err := em.WebhookSignatures.Validate(); err != nil {
+		return err
+	}
+	if err := validateLinkTemplates(em.Links); err != nil {
+		return err
+	}
+	return validateGrouping(em.GroupingDisabled, em.GroupingRuleID, em.CandidateGroupingRuleID, em.GroupingRules)
+}
+
+// GroupingRuleFor returns the ID of the grouping rule that applies to an event:
+// the first matching entry of GroupingRules, or the default GroupingRuleID.
+// It returns an empty string if the event manager has no rule for the event.
+func (em *EventManager) GroupingRuleFor(event *Event) string {
+	for i := range em.GroupingRules {
+		if em.GroupingRules[i].Match.Matches(event) {
+			return em.GroupingRules[i].GroupingRuleID
+		}
+	}
+	return em.GroupingRuleID
+}
+
+// GroupingRuleIDs returns the IDs of every grouping rule the event manager
+// references, default rule first and candidate rule last, without duplicates.
+func (em *EventManager) GroupingRuleIDs() []string {
+	var ids []string
+	if em.GroupingRuleID != "" {
+		ids = append(ids, em.GroupingRuleID)
+	}
+	for _, b := range em.GroupingRules {
+		if !containsString(ids, b.GroupingRuleID) {
+			ids = append(ids, b.GroupingRuleID)
+		}
+	}
+	if em.CandidateGroupingRuleID != "" && !containsString(ids, em.CandidateGroupingRuleID) {
+		ids = append(ids, em.CandidateGroupingRuleID)
+	}
+	return ids
+}
+
+// UsesGroupingRule returns true if the event manager references the grouping rule.
+func (em *EventManager) UsesGroupingRule(id string) bool {
+	return containsString(em.GroupingRuleIDs(), id)
+}
+
+// NewIngestToken generates a random ingest token with 128 bits of entropy.
+func NewIngestToken() string {
+	return rand.Text()
+}
+
+// IsDeleted returns true if the event manager has been soft-deleted.
+func (em *EventManager) IsDeleted() bool {
+	return em.DeletedAt != nil
 }
 
 // CreateEventManagerRequest represents the input for creating a new event manager.
 type CreateEventManagerRequest struct {
-	Name               string             `json:"name"`
-	Description        string             `json:"description"`
-	GroupingRuleID     string             `json:"grouping_rule_id"`
-	NotificationConfig NotificationConfig `json:"notification_config"`
+	// ID is an optional client-supplied ID; one is generated if empty.
+	ID                      string                `json:"id"`
+	Name                    string                `json:"name"`
+	Description             string                `json:"description"`
+	GroupingRuleID          string                `json:"grouping_rule_id"`
+	GroupingRules           []GroupingRuleBinding `json:"grouping_rules"`
+	CandidateGroupingRuleID string                `json:"candidate_grouping_rule_id"`
+	GroupingDisabled        bool                  `json:"grouping_disabled"`
+	DedupKeyConfig          DedupKeyConfig        `json:"dedup_key_config"`
+	EventDefaults           EventDefaults         `json:"event_defaults"`
+	WebhookTransform        WebhookTransform      `json:"webhook_transform"`
+	Quota                   Quota                 `json:"quota"`
+	Sampling                SamplingPolicy        `json:"sampling"`
+	Storm                   StormPolicy           `json:"storm"`
+	Topic                   string                `json:"topic"`
+	Runbooks                RunbookConfig         `json:"runbooks"`
+	Remediations            []RemediationAction   `json:"remediations"`
+	NotificationConfig      NotificationConfig    `json:"notification_config"`
+	ResolutionPolicy        ResolutionPolicy      `json:"resolution_policy"`
+	TagPolicy               TagPolicy             `json:"tag_policy"`
+	ParentSummary           ParentSummaryPolicy   `json:"parent_summary"`
+	Locale                  string                `json:"locale"`
+	WebhookSignatures       WebhookSignatures     `json:"webhook_signatures"`
+	Links                   []AlertLinkTemplate   `json:"links"`
 }
 
 // Validate checks the create request has required fields.
 func (r *CreateEventManagerRequest) Validate() error {
+	if r.ID != "" {
+		if err := ValidateResourceID(r.ID); err != nil {
+			return err
+		}
+	}
 	if r.Name == "" {
 		return ErrEmptyEventManagerName
 	}
-	if r.GroupingRuleID == "" {
-		return ErrEmptyGroupingRuleID
+	if err := r.DedupKeyConfig.Validate(); err != nil {
+		return err
 	}
-	return nil
+	if err := r.EventDefaults.Validate(); err != nil {
+		return err
+	}
+	if err := r.WebhookTransform.Validate(); err != nil {
+		return err
+	}
+	if err := r.Quota.Validate(); err != nil {
+		return err
+	}
+	if err := r.Sampling.Validate(); err != nil {
+		return err
+	}
+	if err := r.Storm.Validate(); err != nil {
+		return err
+	}
+	if err := validateTopic(r.Topic); err != nil {
+		return err
+	}
+	if err := r.Runbooks.Validate(); err != nil {
+		return err
+	}
+	if err := validateRemediations(r.Remediations); err != nil {
+		return err
+	}
+	if err := r.NotificationConfig.Validate(); err != nil {
+		return err
+	}
+	if !r.ResolutionPolicy.IsValid() {
+		return ErrInvalidResolutionPolicy
+	}
+	if !r.TagPolicy.IsValid() {
+		return ErrInvalidTagPolicy
+	}
+	if err := r.ParentSummary.Validate(); err != nil {
+		return err
+	}
+	if err := validateLocale(r.Locale); err != nil {
+		return err
+	}
+	if err := r.WebhookSignatures.Validate(); err != nil {
+		return err
+	}
+	if err := validateLinkTemplates(r.Links); err != nil {
+		return err
+	}
+	return validateGrouping(r.GroupingDisabled, r.GroupingRuleID, r.CandidateGroupingRuleID, r.GroupingRules)
 }
 
 // ToEventManager converts the request to an EventManager entity.
 func (r *CreateEventManagerRequest) ToEventManager(id string) *EventManager {
 	now := time.Now().UTC()
 	return &EventManager{
-		ID:                 id,
-		Name:               r.Name,
-		Description:        r.Description,
-		GroupingRuleID:     r.GroupingRuleID,
-		NotificationConfig: r.NotificationConfig,
-		CreatedAt:          now,
-		UpdatedAt:          now,
+		ID:                      id,
+		Name:                    r.Name,
+		Description:             r.Description,
+		GroupingRuleID:          r.GroupingRuleID,
+		GroupingRules:           r.GroupingRules,
+		CandidateGroupingRuleID: r.CandidateGroupingRuleID,
+		GroupingDisabled:        r.GroupingDisabled,
+		DedupKeyConfig:          r.DedupKeyConfig,
+		EventDefaults:           r.EventDefaults,
+		WebhookTransform:        r.WebhookTransform,
+		Quota:                   r.Quota,
+		Sampling:                r.Sampling,
+		Storm:                   r.Storm,
+		Topic:                   r.Topic,
+		Runbooks:                r.Runbooks,
+		Remediations:            r.Remediations,
+		NotificationConfig:      r.NotificationConfig,
+		ResolutionPolicy:        r.ResolutionPolicy,
+		TagPolicy:               r.TagPolicy,
+		ParentSummary:           r.ParentSummary,
+		Locale:                  r.Locale,
+		WebhookSignatures:       r.WebhookSignatures,
+		Links:                   r.Links,
+		IngestToken:             NewIngestToken(),
+		CreatedAt:               now,
+		UpdatedAt:               now,
 	}
 }
 
+// Matches returns true if creating the request would produce the given event
+// manager, so a repeated create with the same ID can succeed idempotently.
+func (r *CreateEventManagerRequest) Matches(em *EventManager) bool {
+	return r.Name == em.Name &&
+		r.Description == em.Description &&
+		r.GroupingRuleID == em.GroupingRuleID &&
+		(len(r.GroupingRules) == 0 && len(em.GroupingRules) == 0 || reflect.DeepEqual(r.GroupingRules, em.GroupingRules)) &&
+		r.CandidateGroupingRuleID == em.CandidateGroupingRuleID &&
+		r.GroupingDisabled == em.GroupingDisabled &&
+		r.DedupKeyConfig == em.DedupKeyConfig &&
+		r.EventDefaults == em.EventDefaults &&
+		r.WebhookTransform == em.WebhookTransform &&
+		r.Quota == em.Quota &&
+		r.Sampling == em.Sampling &&
+		r.Storm == em.Storm &&
+		r.Topic == em.Topic &&
+		r.Runbooks.Equal(&em.Runbooks) &&
+		(len(r.Remediations) == 0 && len(em.Remediations) == 0 || reflect.DeepEqual(r.Remediations, em.Remediations)) &&
+		r.NotificationConfig == em.NotificationConfig &&
+		r.ResolutionPolicy == em.ResolutionPolicy &&
+		r.TagPolicy == em.TagPolicy &&
+		r.ParentSummary == em.ParentSummary &&
+		r.Locale == em.Locale &&
+		maps.Equal(r.WebhookSignatures, em.WebhookSignatures) &&
+		(len(r.Links) == 0 && len(em.Links) == 0 || reflect.DeepEqual(r.Links, em.Links))
+}
+
 // UpdateEventManagerRequest represents the input for updating an event manager.
 type UpdateEventManagerRequest struct {
-	Name               string             `json:"name"`
-	Description        string             `json:"description"`
-	GroupingRuleID     string             `json:"grouping_rule_id"`
-	NotificationConfig NotificationConfig `json:"notification_config"`
+	Name                    string                `json:"name"`
+	Description             string                `json:"description"`
+	GroupingRuleID          string                `json:"grouping_rule_id"`
+	GroupingRules           []GroupingRuleBinding `json:"grouping_rules"`
+	CandidateGroupingRuleID string                `json:"candidate_grouping_rule_id"`
+	GroupingDisabled        bool                  `json:"grouping_disabled"`
+	DedupKeyConfig          DedupKeyConfig        `json:"dedup_key_config"`
+	EventDefaults           EventDefaults         `json:"event_defaults"`
+	WebhookTransform        WebhookTransform      `json:"webhook_transform"`
+	Quota                   Quota                 `json:"quota"`
+	Sampling                SamplingPolicy        `json:"sampling"`
+	Storm                   StormPolicy           `json:"storm"`
+	Topic                   string                `json:"topic"`
+	Runbooks                RunbookConfig         `json:"runbooks"`
+	Remediations            []RemediationAction   `json:"remediations"`
+	NotificationConfig      NotificationConfig    `json:"notification_config"`
+	ResolutionPolicy        ResolutionPolicy      `json:"resolution_policy"`
+	TagPolicy               TagPolicy             `json:"tag_policy"`
+	ParentSummary           ParentSummaryPolicy   `json:"parent_summary"`
+	Locale                  string                `json:"locale"`
+	WebhookSignatures       WebhookSignatures     `json:"webhook_signatures"`
+	Links                   []AlertLinkTemplate   `json:"links"`
 }
 
 // Validate checks the update request has required fields.
@@ -102,10 +695,55 @@ func (r *UpdateEventManagerRequest) Validate() error {
 	if r.Name == "" {
 		return ErrEmptyEventManagerName
 	}
-	if r.GroupingRuleID == "" {
-		return ErrEmptyGroupingRuleID
+	if err := r.DedupKeyConfig.Validate(); err != nil {
+		return err
 	}
-	return nil
+	if err := r.EventDefaults.Validate(); err != nil {
+		return err
+	}
+	if err := r.WebhookTransform.Validate(); err != nil {
+		return err
+	}
+	if err := r.Quota.Validate(); err != nil {
+		return err
+	}
+	if err := r.Sampling.Validate(); err != nil {
+		return err
+	}
+	if err := r.Storm.Validate(); err != nil {
+		return err
+	}
+	if err := validateTopic(r.Topic); err != nil {
+		return err
+	}
+	if err := r.Runbooks.Validate(); err != nil {
+		return err
+	}
+	if err := validateRemediations(r.Remediations); err != nil {
+		return err
+	}
+	if err := r.NotificationConfig.Validate(); err != nil {
+		return err
+	}
+	if !r.ResolutionPolicy.IsValid() {
+		return ErrInvalidResolutionPolicy
+	}
+	if !r.TagPolicy.IsValid() {
+		return ErrInvalidTagPolicy
+	}
+	if err := r.ParentSummary.Validate(); err != nil {
+		return err
+	}
+	if err := validateLocale(r.Locale); err != nil {
+		return err
+	}
+	if err := r.WebhookSignatures.Validate(); err != nil {
+		return err
+	}
+	if err := validateLinkTemplates(r.Links); err != nil {
+		return err
+	}
+	return validateGrouping(r.GroupingDisabled, r.GroupingRuleID, r.CandidateGroupingRuleID, r.GroupingRules)
 }
 
 // ApplyTo updates an existing EventManager with the request values.
@@ -113,6 +751,24 @@ func (r *UpdateEventManagerRequest) ApplyTo(em *EventManager) {
 	em.Name = r.Name
 	em.Description = r.Description
 	em.GroupingRuleID = r.GroupingRuleID
+	em.GroupingRules = r.GroupingRules
+	em.CandidateGroupingRuleID = r.CandidateGroupingRuleID
+	em.GroupingDisabled = r.GroupingDisabled
+	em.DedupKeyConfig = r.DedupKeyConfig
+	em.EventDefaults = r.EventDefaults
+	em.WebhookTransform = r.WebhookTransform
+	em.Quota = r.Quota
+	em.Sampling = r.Sampling
+	em.Storm = r.Storm
+	em.Topic = r.Topic
+	em.Runbooks = r.Runbooks
+	em.Remediations = r.Remediations
 	em.NotificationConfig = r.NotificationConfig
+	em.ResolutionPolicy = r.ResolutionPolicy
+	em.TagPolicy = r.TagPolicy
+	em.ParentSummary = r.ParentSummary
+	em.Locale = r.Locale
+	em.WebhookSignatures = r.WebhookSignatures
+	em.Links = r.Links
 	em.UpdatedAt = time.Now().UTC()
 }
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CloneEventManagerRequest names the copy of an event manager.
+type CloneEventManagerRequest struct {
+	// ID is the ID of the copy; generated if empty.
+	ID string `json:"id"`
+
+	// Name is the name of the copy.
+	Name string `json:"name"`
+}
+
+// Validate checks the clone request has a name and a valid ID, if any.
+func (r *CloneEventManagerRequest) Validate() error {
+	if r.ID != "" {
+		if err := ValidateResourceID(r.ID); err != nil {
+			return err
+		}
+	}
+	if r.Name == "" {
+		return ErrEmptyEventManagerName
+	}
+	return nil
+}
+
+// EventManagerClone is the copy of an event manager together with the
+// copies of the grouping rules, routing rules and silences made for it.
+type EventManagerClone struct {
+	// SourceID is the ID of the event manager that was copied.
+	SourceID string `json:"source_id"`
+
+	EventManager  *EventManager   `json:"event_manager"`
+	GroupingRules []*GroupingRule `json:"grouping_rules"`
+	RoutingRules  []*RoutingRule  `json:"routing_rules"`
+	Silences      []*Silence      `json:"silences"`
+}
+
+// Copy returns a deep copy of the event manager with a new ID, name and
+// ingest token, created at now. The notification channels, policies and
+// grouping rule references are copied as is.
+func (em *EventManager) Copy(id, name string, now time.Time) (*EventManager, error) {
+	emCopy, err := deepCopy(em)
+	if err != nil {
+		return nil, err
+	}
+	emCopy.ID = id
+	emCopy.Name = name
+	emCopy.IngestToken = NewIngestToken()
+	emCopy.CreatedAt = now
+	emCopy.UpdatedAt = now
+	emCopy.DeletedAt = nil
+	return emCopy, nil
+}
+
+// RemapGroupingRules replaces the grouping rules the event manager
+// references by those mapped to them in ids.
+func (em *EventManager) RemapGroupingRules(ids map[string]string) {
+	if id, ok := ids[em.GroupingRuleID]; ok {
+		em.GroupingRuleID = id
+	}
+	for i, b := range em.GroupingRules {
+		if id, ok := ids[b.GroupingRuleID]; ok {
+			em.GroupingRules[i].GroupingRuleID = id
+		}
+	}
+	if id, ok := ids[em.CandidateGroupingRuleID]; ok {
+		em.CandidateGroupingRuleID = id
+	}
+}
+
+// deepCopy copies a value through its JSON encoding, which holds every
+// field of the configuration types.
+func deepCopy[T any](v *T) (*T, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy %T: %w", v, err)
+	}
+	var c T
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to copy %T: %w", v, err)
+	}
+	return &c, nil
+}
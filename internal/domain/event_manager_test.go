@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDedupKeyConfig_Normalize(t *testing.T) {
+	long := strings.Repeat("x", 200)
+
+	tests := []struct {
+		name         string
+		config       DedupKeyConfig
+		key          string
+		want         string
+		wantOriginal string
+	}{
+		{
+			name: "disabled keeps key",
+			key:  "  Host-1 ",
+			want: "  Host-1 ",
+		},
+		{
+			name:   "trim and lowercase",
+			config: DedupKeyConfig{Trim: true, Lowercase: true},
+			key:    "  Host-1 ",
+			want:   "host-1",
+		},
+		{
+			name:   "short key is not hashed",
+			config: DedupKeyConfig{HashThreshold: 100},
+			key:    "host-1",
+			want:   "host-1",
+		},
+		{
+			name:         "long key is hashed",
+			config:       DedupKeyConfig{Lowercase: true, HashThreshold: 100},
+			key:          strings.ToUpper(long),
+			wantOriginal: long,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, original := tt.config.Normalize(tt.key)
+			if tt.wantOriginal != "" {
+				// Hashed keys are checked by their form and stability
+				if len(got) != HashedDedupKeyLength || !strings.HasPrefix(got, "sha256:") {
+					t.Errorf("Normalize() = %q, want a sha256 hash", got)
+				}
+				if again, _ := tt.config.Normalize(tt.key); again != got {
+					t.Errorf("Normalize() is not stable: %q != %q", again, got)
+				}
+			} else if got != tt.want {
+				t.Errorf("Normalize() = %q, want %q", got, tt.want)
+			}
+			if original != tt.wantOriginal {
+				t.Errorf("Normalize() original = %q, want %q", original, tt.wantOriginal)
+			}
+		})
+	}
+}
+
+func TestDedupKeyConfig_Validate(t *testing.T) {
+	for _, threshold := range []int{0, HashedDedupKeyLength, 255} {
+		c := DedupKeyConfig{HashThreshold: threshold}
+		if err := c.Validate(); err != nil {
+			t.Errorf("Validate(hash_threshold=%d) error = %v, want nil", threshold, err)
+		}
+	}
+	for _, threshold := range []int{-1, 10, HashedDedupKeyLength - 1} {
+		c := DedupKeyConfig{HashThreshold: threshold}
+		if err := c.Validate(); err != ErrInvalidHashThreshold {
+			t.Errorf("Validate(hash_threshold=%d) error = %v, want %v", threshold, err, ErrInvalidHashThreshold)
+		}
+	}
+}
+
+func TestNotificationConfig_Validate(t *testing.T) {
+	valid := []NotificationConfig{{}, {ReminderIntervalMinutes: 15, MaxReminders: 3}}
+	for _, c := range valid {
+		if err := c.Validate(); err != nil {
+			t.Errorf("Validate(%+v) error = %v, want nil", c, err)
+		}
+	}
+	invalid := []NotificationConfig{
+		{ReminderIntervalMinutes: 15},
+		{MaxReminders: 3},
+		{ReminderIntervalMinutes: -1, MaxReminders: 3},
+		{ReminderIntervalMinutes: 15, MaxReminders: -1},
+	}
+	for _, c := range invalid {
+		if err := c.Validate(); err != ErrInvalidReminderConfig {
+			t.Errorf("Validate(%+v) error = %v, want %v", c, err, ErrInvalidReminderConfig)
+		}
+	}
+}
+
+func TestResolutionPolicy_IsValid(t *testing.T) {
+	for _, policy := range []ResolutionPolicy{"", ResolveWithAll, ResolveWithAny, AutoResolveChildren} {
+		if !policy.IsValid() {
+			t.Errorf("IsValid(%q) = false, want true", policy)
+		}
+	}
+	if ResolutionPolicy("resolve-with-some").IsValid() {
+		t.Error("IsValid(resolve-with-some) = true, want false")
+	}
+}
+
+func TestEventDefaults_Apply(t *testing.T) {
+	event := &Event{}
+	(&EventDefaults{}).Apply(event)
+	if event.Severity != DefaultSeverity {
+		t.Errorf("Severity = %q, want %q", event.Severity, DefaultSeverity)
+	}
+
+	event = &Event{}
+	(&EventDefaults{Severity: SeverityMedium}).Apply(event)
+	if event.Severity != SeverityMedium {
+		t.Errorf("Severity = %q, want %q", event.Severity, SeverityMedium)
+	}
+
+	// Severities set by the client are kept
+	event = &Event{Severity: SeverityHigh}
+	(&EventDefaults{Severity: SeverityMedium}).Apply(event)
+	if event.Severity != SeverityHigh {
+		t.Errorf("Severity = %q, want %q", event.Severity, SeverityHigh)
+	}
+
+	if err := (&EventDefaults{Severity: "critical"}).Validate(); err != ErrInvalidDefaultSeverity {
+		t.Errorf("Validate(critical) error = %v, want %v", err, ErrInvalidDefaultSeverity)
+	}
+}
+
+func TestValidateResourceID(t *testing.T) {
+	valid := []string{"payments", "team-a.prod_1", "123e4567-e89b-12d3-a456-426614174000"}
+	for _, id := range valid {
+		if err := ValidateResourceID(id); err != nil {
+			t.Errorf("ValidateResourceID(%q) error = %v, want nil", id, err)
+		}
+	}
+
+	invalid := []string{"", "has space", "a/b", strings.Repeat("x", MaxResourceIDLength+1)}
+	for _, id := range invalid {
+		if err := ValidateResourceID(id); !errors.Is(err, ErrInvalidResourceID) {
+			t.Errorf("ValidateResourceID(%q) error = %v, want %v", id, err, ErrInvalidResourceID)
+		}
+	}
+}
+
+func TestValidateTopic(t *testing.T) {
+	for _, topic := range []string{"", "argus-events-payments", "tenant_a.events"} {
+		if err := validateTopic(topic); err != nil {
+			t.Errorf("validateTopic(%q) error = %v, want nil", topic, err)
+		}
+	}
+	for _, topic := range []string{".", "..", "has space", "a/b", strings.Repeat("x", maxTopicLength+1)} {
+		if err := validateTopic(topic); !errors.Is(err, ErrInvalidTopic) {
+			t.Errorf("validateTopic(%q) error = %v, want %v", topic, err, ErrInvalidTopic)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// ErrEventStatusNotFound is returned for an event ID without a status: never
+// issued, expired, or issued while event status tracking was disabled.
+var ErrEventStatusNotFound = notFoundError("event status not found")
+
+// EventState is where an accepted event is in the pipeline.
+type EventState string
+
+const (
+	// EventQueued is the state of an event published to the queue and not
+	// yet picked up by the processor.
+	EventQueued EventState = "queued"
+	// EventProcessing is the state of an event the processor is handling.
+	EventProcessing EventState = "processing"
+	// EventAlertCreated is the state of a trigger that created an alert.
+	EventAlertCreated EventState = "alert_created"
+	// EventAlertReactivated is the state of a trigger that reactivated the
+	// resolved alert of its dedup key.
+	EventAlertReactivated EventState = "alert_reactivated"
+	// EventDeduplicated is the state of a trigger counted on the active
+	// alert of its dedup key.
+	EventDeduplicated EventState = "deduplicated"
+	// EventResolved is the state of a resolve applied to its alert.
+	EventResolved EventState = "resolved"
+	// EventDropped is the state of an event that changed nothing, with the
+	// reason.
+	EventDropped EventState = "dropped"
+	// EventFailed is the state of an event the processor gave up on, with
+	// the error.
+	EventFailed EventState = "failed"
+)
+
+// EventStatus is where an event accepted by ingest is in the pipeline,
+// recorded under the event ID returned when it was accepted.
+type EventStatus struct {
+	EventID        string     `json:"event_id"`
+	State          EventState `json:"state"`
+	EventManagerID string     `json:"event_manager_id"`
+	DedupKey       string     `json:"dedupKey"`
+	Action         Action     `json:"action"`
+
+	// Reason explains why the event was dropped or failed.
+	Reason string `json:"reason,omitempty"`
+
+	ReceivedAt time.Time `json:"received_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -42,6 +42,35 @@ func TestEvent_Validate(t *testing.T) {
 			},
 			wantErr: ErrEmptySummary,
 		},
+		{
+			name: "missing severity is defaulted later",
+			event: Event{
+				EventManagerID: "em-1",
+				Summary:        "Test alert",
+				Action:         ActionTrigger,
+				DedupKey:       "db-alert-1",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "resolve needs only event manager and dedupKey",
+			event: Event{
+				EventManagerID: "em-1",
+				Action:         ActionResolve,
+				DedupKey:       "db-alert-1",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "resolve with invalid severity",
+			event: Event{
+				EventManagerID: "em-1",
+				Severity:       "critical", // invalid
+				Action:         ActionResolve,
+				DedupKey:       "db-alert-1",
+			},
+			wantErr: ErrInvalidSeverity,
+		},
 		{
 			name: "invalid severity",
 			event: Event{
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// maxExpressionLength bounds the source of an expression.
+const maxExpressionLength = 1024
+
+// ErrInvalidExpression is returned for expressions that don't compile to a
+// boolean condition.
+var ErrInvalidExpression = invalidError("invalid expression")
+
+// Expression is a boolean condition over an event, in the expr language
+// (https://expr-lang.org), e.g.
+//
+//	event.class == "db" && event.severity in ["high", "medium"]
+//
+// The event exposes event_manager_id, summary, severity, class, service,
+// components, dedupKey, source and labels. An empty expression matches every
+// event.
+type Expression string
+
+// expressionEnv is the environment expressions are evaluated in.
+type expressionEnv struct {
+	Event expressionEvent `expr:"event"`
+}
+
+// expressionEvent is the view of an event expressions see.
+type expressionEvent struct {
+	EventManagerID string            `expr:"event_manager_id"`
+	Summary        string            `expr:"summary"`
+	Severity       string            `expr:"severity"`
+	Class          string            `expr:"class"`
+	Service        string            `expr:"service"`
+	Components     []string          `expr:"components"`
+	DedupKey       string            `expr:"dedupKey"`
+	Source         string            `expr:"source"`
+	Labels         map[string]string `expr:"labels"`
+}
+
+// compiledExpressions caches the programs of expressions by source; they are
+// compiled once and evaluated for every matching event.
+var compiledExpressions sync.Map
+
+// compile returns the program of the expression.
+func (e Expression) compile() (*vm.Program, error) {
+	if program, ok := compiledExpressions.Load(e); ok {
+		return program.(*vm.Program), nil
+	}
+	if len(e) > maxExpressionLength {
+		return nil, fmt.Errorf("%w: longer than %d characters", ErrInvalidExpression, maxExpressionLength)
+	}
+
+	program, err := expr.Compile(string(e), expr.Env(expressionEnv{}), expr.AsBool())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExpression, err)
+	}
+	compiledExpressions.Store(e, program)
+	return program, nil
+}
+
+// Validate checks the expression compiles to a boolean condition.
+func (e Expression) Validate() error {
+	if e == "" {
+		return nil
+	}
+	_, err := e.compile()
+	return err
+}
+
+// Matches returns true if the event satisfies the expression. Invalid
+// expressions, and expressions failing at run time, match no event.
+func (e Expression) Matches(event *Event) bool {
+	if e == "" {
+		return true
+	}
+	program, err := e.compile()
+	if err != nil {
+		return false
+	}
+
+	env := expressionEnv{Event: expressionEvent{
+		EventManagerID: event.EventManagerID,
+		Summary:        event.Summary,
+		Severity:       string(event.Severity),
+		Class:          event.Class,
+		Service:        event.Service,
+		Components:     event.Components,
+		DedupKey:       event.DedupKey,
+		Source:         event.Source,
+		Labels:         event.Labels,
+	}}
+	matched, err := expr.Run(program, env)
+	if err != nil {
+		return false
+	}
+	return matched.(bool)
+}
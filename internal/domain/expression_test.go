@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExpression_Matches(t *testing.T) {
+	event := &Event{
+		EventManagerID: "em-1",
+		Summary:        "Replica lag on db-1",
+		Severity:       SeverityHigh,
+		Class:          "db",
+		DedupKey:       "db-1-lag",
+		Source:         "prometheus",
+		Labels:         map[string]string{"team": "payments"},
+	}
+
+	tests := []struct {
+		expression Expression
+		want       bool
+	}{
+		{expression: "", want: true},
+		{expression: `event.class == "db" && event.severity in ["high"]`, want: true},
+		{expression: `event.class == "db" && event.severity in ["low", "medium"]`, want: false},
+		{expression: `event.summary contains "lag" and event.source startsWith "prom"`, want: true},
+		{expression: `event.labels["team"] == "payments"`, want: true},
+		{expression: `event.labels["region"] == "eu"`, want: false},
+		{expression: `event.dedupKey matches "^db-[0-9]+-"`, want: true},
+		{expression: `event.event_manager_id != "em-1"`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.expression), func(t *testing.T) {
+			if err := tt.expression.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if got := tt.expression.Matches(event); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpression_Validate(t *testing.T) {
+	tests := []Expression{
+		`event.class ==`,
+		`event.class`,
+		`event.unknown == "x"`,
+		`os.Exit(1)`,
+		Expression(`event.class == "` + strings.Repeat("x", maxExpressionLength) + `"`),
+	}
+
+	for _, expression := range tests {
+		if err := expression.Validate(); !errors.Is(err, ErrInvalidExpression) {
+			t.Errorf("Validate(%.40q) error = %v, want %v", expression, err, ErrInvalidExpression)
+		}
+		if expression.Matches(&Event{Class: "x"}) {
+			t.Errorf("invalid expression %.40q matched", expression)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxFeedbackCommentLength bounds the comment of alert feedback.
+const MaxFeedbackCommentLength = 1000
+
+// FeedbackRating is a responder's verdict on the quality of an alert.
+type FeedbackRating string
+
+const (
+	// FeedbackUseful rates an alert that needed a responder.
+	FeedbackUseful FeedbackRating = "useful"
+	// FeedbackNoisy rates an alert that needed nobody.
+	FeedbackNoisy FeedbackRating = "noisy"
+	// FeedbackWronglyGrouped rates an alert grouped with alerts it isn't
+	// related to, or apart from those it is.
+	FeedbackWronglyGrouped FeedbackRating = "wrongly_grouped"
+)
+
+// Errors of alert feedback.
+var (
+	ErrInvalidFeedbackRating  = invalidError("rating must be useful, noisy or wrongly_grouped")
+	ErrFeedbackCommentTooLong = invalidError(fmt.Sprintf("comment must be at most %d characters", MaxFeedbackCommentLength))
+)
+
+// IsValid returns true if the rating is known.
+func (r FeedbackRating) IsValid() bool {
+	switch r {
+	case FeedbackUseful, FeedbackNoisy, FeedbackWronglyGrouped:
+		return true
+	}
+	return false
+}
+
+// AlertFeedback is a responder's rating of an alert, aggregated into the
+// quality of its event manager and grouping rule to drive their tuning.
+type AlertFeedback struct {
+	DedupKey       string `json:"dedupKey"`
+	EventManagerID string `json:"event_manager_id"`
+
+	// GroupingRuleID is the grouping rule the event manager selects for the
+	// class and severity of the alert, empty if it has none.
+	GroupingRuleID string `json:"grouping_rule_id,omitempty"`
+
+	Rating  FeedbackRating `json:"rating"`
+	Comment string         `json:"comment,omitempty"`
+
+	// Author is the actor of the request, if named.
+	Author string `json:"author,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AlertFeedbackRequest is the body of POST /v1/alerts/:dedupKey/feedback.
+type AlertFeedbackRequest struct {
+	Rating  FeedbackRating `json:"rating"`
+	Comment string         `json:"comment,omitempty"`
+}
+
+// Validate checks the rating is known and the comment not too long.
+func (r *AlertFeedbackRequest) Validate() error {
+	if !r.Rating.IsValid() {
+		return ErrInvalidFeedbackRating
+	}
+	if len(r.Comment) > MaxFeedbackCommentLength {
+		return ErrFeedbackCommentTooLong
+	}
+	return nil
+}
+
+// ToFeedback creates the feedback on an alert of the request, by author at
+// now, for the grouping rule em selects for the alert.
+func (r *AlertFeedbackRequest) ToFeedback(alert *Alert, em *EventManager, author string, now time.Time) *AlertFeedback {
+	return &AlertFeedback{
+		DedupKey:       alert.DedupKey,
+		EventManagerID: alert.EventManagerID,
+		GroupingRuleID: em.GroupingRuleFor(&Event{Class: alert.Class, Severity: alert.Severity, Summary: alert.Summary}),
+		Rating:         r.Rating,
+		Comment:        strings.TrimSpace(r.Comment),
+		Author:         author,
+		CreatedAt:      now,
+	}
+}
+
+// AlertQuality aggregates the feedback on the alerts of an event manager,
+// or of a grouping rule of it.
+type AlertQuality struct {
+	EventManagerID string `json:"event_manager_id"`
+	GroupingRuleID string `json:"grouping_rule_id,omitempty"`
+
+	Useful         int `json:"useful"`
+	Noisy          int `json:"noisy"`
+	WronglyGrouped int `json:"wrongly_grouped"`
+	Total          int `json:"total"`
+
+	// UsefulRate is the share of the feedback rating the alerts useful.
+	UsefulRate float64 `json:"useful_rate"`
+}
+
+// add counts a rating.
+func (q *AlertQuality) add(rating FeedbackRating) {
+	switch rating {
+	case FeedbackUseful:
+		q.Useful++
+	case FeedbackNoisy:
+		q.Noisy++
+	case FeedbackWronglyGrouped:
+		q.WronglyGrouped++
+	}
+	q.Total++
+	q.UsefulRate = float64(q.Useful) / float64(q.Total)
+}
+
+// AlertQualityReport is the response for the alert quality report.
+type AlertQualityReport struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	// EventManagers and GroupingRules hold the quality of each event manager
+	// and of each of its grouping rules with feedback, least useful first.
+	EventManagers []*AlertQuality `json:"event_managers"`
+	GroupingRules []*AlertQuality `json:"grouping_rules"`
+}
+
+// BuildAlertQualityReport aggregates feedback given within the report range
+// by event manager and by grouping rule. Feedback on alerts without a
+// grouping rule only counts towards its event manager.
+func BuildAlertQualityReport(feedback []*AlertFeedback, filter ReportFilter) *AlertQualityReport {
+	eventManagers := make(map[string]*AlertQuality)
+	rules := make(map[[2]string]*AlertQuality)
+	for _, f := range feedback {
+		em, ok := eventManagers[f.EventManagerID]
+		if !ok {
+			em = &AlertQuality{EventManagerID: f.EventManagerID}
+			eventManagers[f.EventManagerID] = em
+		}
+		em.add(f.Rating)
+
+		if f.GroupingRuleID == "" {
+			continue
+		}
+		key := [2]string{f.EventManagerID, f.GroupingRuleID}
+		rule, ok := rules[key]
+		if !ok {
+			rule = &AlertQuality{EventManagerID: f.EventManagerID, GroupingRuleID: f.GroupingRuleID}
+			rules[key] = rule
+		}
+		rule.add(f.Rating)
+	}
+
+	return &AlertQualityReport{
+		From:          filter.From,
+		To:            filter.To,
+		EventManagers: sortedQuality(eventManagers),
+		GroupingRules: sortedQuality(rules),
+	}
+}
+
+// sortedQuality returns the qualities, least useful first, then by event
+// manager and grouping rule.
+func sortedQuality[K comparable](qualities map[K]*AlertQuality) []*AlertQuality {
+	sorted := make([]*AlertQuality, 0, len(qualities))
+	for _, q := range qualities {
+		sorted = append(sorted, q)
+	}
+	slices.SortFunc(sorted, func(a, b *AlertQuality) int {
+		if c := cmp.Compare(a.UsefulRate, b.UsefulRate); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.EventManagerID, b.EventManagerID); c != 0 {
+			return c
+		}
+		return strings.Compare(a.GroupingRuleID, b.GroupingRuleID)
+	})
+	return sorted
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAlertFeedbackRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     AlertFeedbackRequest
+		wantErr error
+	}{
+		{name: "useful", req: AlertFeedbackRequest{Rating: FeedbackUseful}},
+		{name: "wrongly grouped with a comment", req: AlertFeedbackRequest{Rating: FeedbackWronglyGrouped, Comment: "unrelated to the outage"}},
+		{name: "no rating", req: AlertFeedbackRequest{}, wantErr: ErrInvalidFeedbackRating},
+		{name: "unknown rating", req: AlertFeedbackRequest{Rating: "great"}, wantErr: ErrInvalidFeedbackRating},
+		{name: "comment too long", req: AlertFeedbackRequest{Rating: FeedbackNoisy, Comment: strings.Repeat("x", MaxFeedbackCommentLength+1)}, wantErr: ErrFeedbackCommentTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); err != tt.wantErr {
+				t.Errorf("Validate() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAlertFeedbackRequest_ToFeedback(t *testing.T) {
+	em := &EventManager{
+		ID:             "em-1",
+		GroupingRuleID: "rule-default",
+		GroupingRules:  []GroupingRuleBinding{{Match: EventMatcher{Classes: []string{"disk"}}, GroupingRuleID: "rule-disk"}},
+	}
+	req := AlertFeedbackRequest{Rating: FeedbackNoisy}
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	feedback := req.ToFeedback(&Alert{DedupKey: "disk-1", EventManagerID: "em-1", Class: "disk"}, em, "alice", now)
+	if feedback.GroupingRuleID != "rule-disk" || feedback.Author != "alice" || !feedback.CreatedAt.Equal(now) {
+		t.Errorf("feedback = %+v, want alice's on rule-disk", feedback)
+	}
+	if feedback := req.ToFeedback(&Alert{DedupKey: "cpu-1", EventManagerID: "em-1", Class: "cpu"}, em, "", now); feedback.GroupingRuleID != "rule-default" {
+		t.Errorf("grouping rule of a cpu alert = %q, want rule-default", feedback.GroupingRuleID)
+	}
+}
+
+func TestBuildAlertQualityReport(t *testing.T) {
+	feedback := []*AlertFeedback{
+		{EventManagerID: "em-1", GroupingRuleID: "rule-a", Rating: FeedbackUseful},
+		{EventManagerID: "em-1", GroupingRuleID: "rule-a", Rating: FeedbackUseful},
+		{EventManagerID: "em-1", GroupingRuleID: "rule-b", Rating: FeedbackWronglyGrouped},
+		{EventManagerID: "em-1", Rating: FeedbackNoisy},
+		{EventManagerID: "em-2", GroupingRuleID: "rule-a", Rating: FeedbackNoisy},
+	}
+
+	report := BuildAlertQualityReport(feedback, ReportFilter{})
+
+	if len(report.EventManagers) != 2 {
+		t.Fatalf("event managers = %d, want 2", len(report.EventManagers))
+	}
+	if em := report.EventManagers[0]; em.EventManagerID != "em-2" || em.Total != 1 || em.UsefulRate != 0 {
+		t.Errorf("least useful event manager = %+v, want em-2 with 1 noisy", em)
+	}
+	if em := report.EventManagers[1]; em.EventManagerID != "em-1" || em.Total != 4 || em.Useful != 2 || em.Noisy != 1 || em.WronglyGrouped != 1 || em.UsefulRate != 0.5 {
+		t.Errorf("em-1 quality = %+v, want 4 ratings, half useful", em)
+	}
+
+	// Rules are per event manager, and feedback without a rule counts
+	// towards none
+	var rules []string
+	for _, q := range report.GroupingRules {
+		rules = append(rules, q.EventManagerID+"/"+q.GroupingRuleID)
+	}
+	if got := strings.Join(rules, ","); got != "em-1/rule-b,em-2/rule-a,em-1/rule-a" {
+		t.Errorf("grouping rules = %s, want least useful first", got)
+	}
+}
@@ -0,0 +1,111 @@
+package domain
+
+import "fmt"
+
+// MaxPreviewEvents is the largest number of sample events a preview accepts.
+const MaxPreviewEvents = 1000
+
+// GroupingOutcome describes how the processor would handle an event.
+type GroupingOutcome string
+
+const (
+	// GroupingOutcomeParent means the event opens a new parent alert.
+	GroupingOutcomeParent GroupingOutcome = "parent"
+	// GroupingOutcomeChild means the event is grouped under an earlier parent.
+	GroupingOutcomeChild GroupingOutcome = "child"
+	// GroupingOutcomeDuplicate means an earlier event already opened an alert
+	// with the same dedup key, so only its trigger count would change.
+	GroupingOutcomeDuplicate GroupingOutcome = "duplicate"
+	// GroupingOutcomeReactivate means the event reactivates the resolved
+	// alert with its dedup key.
+	GroupingOutcomeReactivate GroupingOutcome = "reactivate"
+	// GroupingOutcomeResolve means a resolve event resolves the active alert
+	// with its dedup key.
+	GroupingOutcomeResolve GroupingOutcome = "resolve"
+	// GroupingOutcomeIgnored means a resolve event has no active alert to
+	// resolve.
+	GroupingOutcomeIgnored GroupingOutcome = "ignored"
+)
+
+// Validation errors for PreviewGroupingRequest.
+var (
+	ErrNoPreviewEvents      = invalidError("events is required")
+	ErrTooManyPreviewEvents = invalidError(fmt.Sprintf("at most %d events can be previewed", MaxPreviewEvents))
+	ErrPreviewResolveEvent  = invalidError("only trigger events can be previewed")
+	ErrPreviewEmptyDedupKey = invalidError("every event needs a dedupKey")
+)
+
+// PreviewGroupingRequest is the input for previewing a grouping rule.
+type PreviewGroupingRequest struct {
+	Events []Event `json:"events"`
+}
+
+// Validate checks the preview request has usable sample events.
+// A missing action is treated as a trigger.
+func (r *PreviewGroupingRequest) Validate() error {
+	if len(r.Events) == 0 {
+		return ErrNoPreviewEvents
+	}
+	if len(r.Events) > MaxPreviewEvents {
+		return ErrTooManyPreviewEvents
+	}
+	for i := range r.Events {
+		if r.Events[i].Action != "" && r.Events[i].Action != ActionTrigger {
+			return ErrPreviewResolveEvent
+		}
+		if r.Events[i].DedupKey == "" {
+			return ErrPreviewEmptyDedupKey
+		}
+	}
+	return nil
+}
+
+// GroupingPreview is the simulated result of one sample event.
+type GroupingPreview struct {
+	DedupKey       string          `json:"dedupKey"`
+	GroupingValue  string          `json:"grouping_value"`
+	Outcome        GroupingOutcome `json:"outcome"`
+	ParentDedupKey string          `json:"parent_dedupKey,omitempty"`
+}
+
+// Preview simulates grouping a sequence of trigger events with the rule, as
+// if they arrived in order within one time window and no alerts existed yet.
+// Like the processor, the first event of an event manager with a given
+// grouping value becomes the parent and later ones its children.
+func (gr *GroupingRule) Preview(events []Event) []GroupingPreview {
+	type groupKey struct{ eventManagerID, value string }
+
+	parents := make(map[groupKey]string)
+	seen := make(map[string]GroupingPreview)
+	results := make([]GroupingPreview, 0, len(events))
+
+	for i := range events {
+		event := &events[i]
+		result := GroupingPreview{
+			DedupKey:      event.DedupKey,
+			GroupingValue: gr.ExtractGroupingValue(event),
+		}
+
+		if first, ok := seen[event.DedupKey]; ok {
+			result.Outcome = GroupingOutcomeDuplicate
+			result.GroupingValue = first.GroupingValue
+			result.ParentDedupKey = first.ParentDedupKey
+			results = append(results, result)
+			continue
+		}
+
+		key := groupKey{event.EventManagerID, result.GroupingValue}
+		if parent, ok := parents[key]; ok {
+			result.Outcome = GroupingOutcomeChild
+			result.ParentDedupKey = parent
+		} else {
+			result.Outcome = GroupingOutcomeParent
+			parents[key] = event.DedupKey
+		}
+
+		seen[event.DedupKey] = result
+		results = append(results, result)
+	}
+
+	return results
+}
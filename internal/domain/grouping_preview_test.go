@@ -0,0 +1,55 @@
+package domain
+
+import "testing"
+
+func TestGroupingRule_Preview(t *testing.T) {
+	rule := &GroupingRule{GroupingKey: "class", TimeWindowMinutes: 5}
+	events := []Event{
+		{EventManagerID: "em-1", Class: "db", DedupKey: "a"},
+		{EventManagerID: "em-1", Class: "db", DedupKey: "b"},
+		{EventManagerID: "em-1", Class: "net", DedupKey: "c"},
+		{EventManagerID: "em-1", Class: "db", DedupKey: "b"},
+		{EventManagerID: "em-2", Class: "db", DedupKey: "d"},
+	}
+
+	want := []GroupingPreview{
+		{DedupKey: "a", GroupingValue: "db", Outcome: GroupingOutcomeParent},
+		{DedupKey: "b", GroupingValue: "db", Outcome: GroupingOutcomeChild, ParentDedupKey: "a"},
+		{DedupKey: "c", GroupingValue: "net", Outcome: GroupingOutcomeParent},
+		{DedupKey: "b", GroupingValue: "db", Outcome: GroupingOutcomeDuplicate, ParentDedupKey: "a"},
+		// Groups never span event managers
+		{DedupKey: "d", GroupingValue: "db", Outcome: GroupingOutcomeParent},
+	}
+
+	got := rule.Preview(events)
+	if len(got) != len(want) {
+		t.Fatalf("Preview returned %d results, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPreviewGroupingRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     PreviewGroupingRequest
+		wantErr error
+	}{
+		{"valid", PreviewGroupingRequest{Events: []Event{{DedupKey: "a"}}}, nil},
+		{"no events", PreviewGroupingRequest{}, ErrNoPreviewEvents},
+		{"too many events", PreviewGroupingRequest{Events: make([]Event, MaxPreviewEvents+1)}, ErrTooManyPreviewEvents},
+		{"resolve event", PreviewGroupingRequest{Events: []Event{{Action: ActionResolve, DedupKey: "a"}}}, ErrPreviewResolveEvent},
+		{"missing dedupKey", PreviewGroupingRequest{Events: []Event{{Action: ActionTrigger}}}, ErrPreviewEmptyDedupKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); err != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
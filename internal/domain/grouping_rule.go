@@ -1,7 +1,11 @@
 package domain
 
 import (
-	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
 	"time"
 )
 
@@ -18,25 +22,64 @@ type GroupingRule struct {
 	// For example, "class" would group events by their class field value.
 	GroupingKey string `json:"grouping_key"`
 
-	// TimeWindowMinutes defines how long a parent alert remains "open" for grouping.
-	// New events with the same grouping key value within this window become children.
+	// Window defines how long a parent alert remains "open" for grouping.
+	// New events with the same grouping key value within this window become
+	// children. It is a whole number of seconds.
+	Window Duration `json:"time_window"`
+
+	// TimeWindowMinutes is the window in whole minutes, kept for clients that
+	// predate Window. It is used when Window is not set.
 	TimeWindowMinutes int `json:"time_window_minutes"`
 
+	// ValueTemplate optionally composes the grouping value from several event
+	// fields with a Go template, e.g. "{{.Class}}/{{.Severity}}". When set, it
+	// replaces the value of the GroupingKey field.
+	ValueTemplate string `json:"value_template,omitempty"`
+
+	// ValuePattern optionally normalizes the grouping value with a regular
+	// expression: the first capture group (or the whole match, if the pattern
+	// has no groups) becomes the grouping value. Values that don't match are
+	// used unchanged.
+	ValuePattern string `json:"value_pattern,omitempty"`
+
+	// Runbook is copied onto the alerts grouped by this rule, unless their
+	// event manager overrides it for their class.
+	Runbook Runbook `json:"runbook"`
+
 	// CreatedAt is when the grouping rule was created.
 	CreatedAt time.Time `json:"created_at"`
 
 	// UpdatedAt is when the grouping rule was last modified.
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// DeletedAt is when the grouping rule was soft-deleted. Nil if not deleted.
+	// A rule can only be deleted once no active event manager references it.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 // Validation errors for GroupingRule.
 var (
-	ErrEmptyGroupingRuleName = errors.New("name is required")
-	ErrEmptyGroupingKey      = errors.New("grouping_key is required")
-	ErrInvalidTimeWindow     = errors.New("time_window_minutes must be positive")
-	ErrGroupingRuleNotFound  = errors.New("grouping rule not found")
+	ErrEmptyGroupingRuleName     = invalidError("name is required")
+	ErrEmptyGroupingKey          = invalidError("grouping_key is required")
+	ErrInvalidTimeWindow         = invalidError("time_window (or time_window_minutes) must be a positive whole number of seconds")
+	ErrGroupingRuleNotFound      = notFoundError("grouping rule not found")
+	ErrGroupingRuleAlreadyExists = conflictError("grouping rule already exists")
+	ErrGroupingRuleDeleted       = conflictError("grouping rule has been deleted")
+	ErrGroupingRuleNotDeleted    = transitionError("grouping rule must be deleted before it can be purged")
+	ErrGroupingRuleInUse         = conflictError("grouping rule is still referenced by event managers")
+	ErrGroupingRuleRecentlyUsed  = conflictError("grouping rule is referenced by deleted event managers or grouped alerts recently; delete it with force=true")
+	ErrGroupingRuleIsDefault     = conflictError("grouping rule is the system default")
+	ErrInvalidValuePattern       = invalidError("value_pattern is not a valid regular expression")
+	ErrInvalidValueTemplate      = invalidError("value_template is not a valid template")
 )
 
+// DefaultGroupingRule is the system-wide default grouping rule, applied to
+// event managers that have no grouping rule of their own.
+type DefaultGroupingRule struct {
+	// GroupingRuleID is the ID of the default rule; empty if there is none.
+	GroupingRuleID string `json:"grouping_rule_id"`
+}
+
 // Validate checks if the grouping rule has all required fields with valid values.
 func (gr *GroupingRule) Validate() error {
 	if gr.Name == "" {
@@ -45,74 +88,235 @@ func (gr *GroupingRule) Validate() error {
 	if gr.GroupingKey == "" {
 		return ErrEmptyGroupingKey
 	}
-	if gr.TimeWindowMinutes <= 0 {
+	if err := validateTimeWindow(gr.TimeWindow()); err != nil {
+		return err
+	}
+	if err := gr.Runbook.Validate(); err != nil {
+		return err
+	}
+	return validateValueTransforms(gr.ValueTemplate, gr.ValuePattern)
+}
+
+// IsDeleted returns true if the grouping rule has been soft-deleted.
+func (gr *GroupingRule) IsDeleted() bool {
+	return gr.DeletedAt != nil
+}
+
+// TimeWindow returns the time window as a time.Duration: Window if set,
+// otherwise TimeWindowMinutes.
+func (gr *GroupingRule) TimeWindow() time.Duration {
+	return effectiveTimeWindow(gr.Window, gr.TimeWindowMinutes)
+}
+
+// effectiveTimeWindow returns window if set, otherwise minutes as a duration.
+func effectiveTimeWindow(window Duration, minutes int) time.Duration {
+	if window != 0 {
+		return time.Duration(window)
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// validateTimeWindow checks a time window is positive and can be stored in
+// whole seconds.
+func validateTimeWindow(window time.Duration) error {
+	if window <= 0 || window%time.Second != 0 {
 		return ErrInvalidTimeWindow
 	}
 	return nil
 }
 
-// TimeWindow returns the time window as a time.Duration.
-func (gr *GroupingRule) TimeWindow() time.Duration {
-	return time.Duration(gr.TimeWindowMinutes) * time.Minute
+// setTimeWindow sets both representations of the time window.
+func (gr *GroupingRule) setTimeWindow(window time.Duration) {
+	gr.Window = Duration(window)
+	gr.TimeWindowMinutes = int(window / time.Minute)
 }
 
-// ExtractGroupingValue extracts the value of the grouping key from an event.
-// For MVP, we support extracting from known fields: class.
-// Returns empty string if the field is not found or not supported.
+// ExtractGroupingValue extracts the grouping value from an event: the value
+// of the grouping key field (or the rendered ValueTemplate), normalized by
+// ValuePattern. Returns empty string if the field is not supported.
 func (gr *GroupingRule) ExtractGroupingValue(event *Event) string {
-	switch gr.GroupingKey {
+	value := eventField(event, gr.GroupingKey)
+
+	if gr.ValueTemplate != "" {
+		if tmpl, err := compiledTemplate(gr.ValueTemplate); err == nil {
+			var sb strings.Builder
+			if err := tmpl.Execute(&sb, event); err == nil {
+				value = sb.String()
+			}
+		}
+	}
+
+	if gr.ValuePattern != "" {
+		if re, err := compiledPattern(gr.ValuePattern); err == nil {
+			if match := re.FindStringSubmatch(value); match != nil {
+				if len(match) > 1 {
+					value = match[1]
+				} else {
+					value = match[0]
+				}
+			}
+		}
+	}
+
+	return value
+}
+
+// eventField returns the value of a known event field.
+// For MVP, only known fields are supported.
+// Future: support arbitrary fields via reflection or map-based events
+func eventField(event *Event, field string) string {
+	switch field {
 	case "class":
 		return event.Class
 	case "severity":
 		return string(event.Severity)
 	case "event_manager_id":
 		return event.EventManagerID
+	case "summary":
+		return event.Summary
+	case "dedup_key":
+		return event.DedupKey
+	case "service":
+		return event.Service
+	case "component":
+		// The most significant component
+		if len(event.Components) > 0 {
+			return event.Components[0]
+		}
+		return ""
+	case "components":
+		return componentSet(event.Components)
 	default:
-		// For MVP, only support known fields
-		// Future: support arbitrary fields via reflection or map-based events
 		return ""
 	}
 }
 
+// Compiled value transforms, cached by source since rules are loaded from the
+// repository for every event.
+var (
+	patternCache  sync.Map // string -> *regexp.Regexp
+	templateCache sync.Map // string -> *template.Template
+)
+
+// compiledPattern returns the compiled form of a value pattern.
+func compiledPattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patternCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCache.Store(pattern, re)
+	return re, nil
+}
+
+// compiledTemplate returns the parsed form of a value template.
+// Unknown event fields fail when the template is executed.
+func compiledTemplate(text string) (*template.Template, error) {
+	if tmpl, ok := templateCache.Load(text); ok {
+		return tmpl.(*template.Template), nil
+	}
+	tmpl, err := template.New("grouping_value").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	templateCache.Store(text, tmpl)
+	return tmpl, nil
+}
+
+// validateValueTransforms checks that a value template parses and renders
+// against an event, and that a value pattern compiles.
+func validateValueTransforms(valueTemplate, valuePattern string) error {
+	if valueTemplate != "" {
+		tmpl, err := compiledTemplate(valueTemplate)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidValueTemplate, err)
+		}
+		if err := tmpl.Execute(&strings.Builder{}, &Event{}); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidValueTemplate, err)
+		}
+	}
+	if valuePattern != "" {
+		if _, err := compiledPattern(valuePattern); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidValuePattern, err)
+		}
+	}
+	return nil
+}
+
 // CreateGroupingRuleRequest represents the input for creating a new grouping rule.
 type CreateGroupingRuleRequest struct {
-	Name              string `json:"name"`
-	GroupingKey       string `json:"grouping_key"`
-	TimeWindowMinutes int    `json:"time_window_minutes"`
+	// ID is an optional client-supplied ID; one is generated if empty.
+	ID                string   `json:"id"`
+	Name              string   `json:"name"`
+	GroupingKey       string   `json:"grouping_key"`
+	TimeWindow        Duration `json:"time_window"`
+	TimeWindowMinutes int      `json:"time_window_minutes"`
+	ValueTemplate     string   `json:"value_template"`
+	ValuePattern      string   `json:"value_pattern"`
+	Runbook           Runbook  `json:"runbook"`
 }
 
 // Validate checks the create request has required fields.
 func (r *CreateGroupingRuleRequest) Validate() error {
+	if r.ID != "" {
+		if err := ValidateResourceID(r.ID); err != nil {
+			return err
+		}
+	}
 	if r.Name == "" {
 		return ErrEmptyGroupingRuleName
 	}
 	if r.GroupingKey == "" {
 		return ErrEmptyGroupingKey
 	}
-	if r.TimeWindowMinutes <= 0 {
-		return ErrInvalidTimeWindow
+	if err := validateTimeWindow(effectiveTimeWindow(r.TimeWindow, r.TimeWindowMinutes)); err != nil {
+		return err
 	}
-	return nil
+	if err := r.Runbook.Validate(); err != nil {
+		return err
+	}
+	return validateValueTransforms(r.ValueTemplate, r.ValuePattern)
 }
 
 // ToGroupingRule converts the request to a GroupingRule entity.
 func (r *CreateGroupingRuleRequest) ToGroupingRule(id string) *GroupingRule {
 	now := time.Now().UTC()
-	return &GroupingRule{
-		ID:                id,
-		Name:              r.Name,
-		GroupingKey:       r.GroupingKey,
-		TimeWindowMinutes: r.TimeWindowMinutes,
-		CreatedAt:         now,
-		UpdatedAt:         now,
+	rule := &GroupingRule{
+		ID:            id,
+		Name:          r.Name,
+		GroupingKey:   r.GroupingKey,
+		ValueTemplate: r.ValueTemplate,
+		ValuePattern:  r.ValuePattern,
+		Runbook:       r.Runbook,
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}
+	rule.setTimeWindow(effectiveTimeWindow(r.TimeWindow, r.TimeWindowMinutes))
+	return rule
+}
+
+// Matches returns true if creating the request would produce the given
+// grouping rule, so a repeated create with the same ID can succeed idempotently.
+func (r *CreateGroupingRuleRequest) Matches(gr *GroupingRule) bool {
+	return r.Name == gr.Name &&
+		r.GroupingKey == gr.GroupingKey &&
+		effectiveTimeWindow(r.TimeWindow, r.TimeWindowMinutes) == gr.TimeWindow() &&
+		r.ValueTemplate == gr.ValueTemplate &&
+		r.ValuePattern == gr.ValuePattern &&
+		r.Runbook.Equal(&gr.Runbook)
 }
 
 // UpdateGroupingRuleRequest represents the input for updating a grouping rule.
 type UpdateGroupingRuleRequest struct {
-	Name              string `json:"name"`
-	GroupingKey       string `json:"grouping_key"`
-	TimeWindowMinutes int    `json:"time_window_minutes"`
+	Name              string   `json:"name"`
+	GroupingKey       string   `json:"grouping_key"`
+	TimeWindow        Duration `json:"time_window"`
+	TimeWindowMinutes int      `json:"time_window_minutes"`
+	ValueTemplate     string   `json:"value_template"`
+	ValuePattern      string   `json:"value_pattern"`
+	Runbook           Runbook  `json:"runbook"`
 }
 
 // Validate checks the update request has required fields.
@@ -123,16 +327,22 @@ func (r *UpdateGroupingRuleRequest) Validate() error {
 	if r.GroupingKey == "" {
 		return ErrEmptyGroupingKey
 	}
-	if r.TimeWindowMinutes <= 0 {
-		return ErrInvalidTimeWindow
+	if err := validateTimeWindow(effectiveTimeWindow(r.TimeWindow, r.TimeWindowMinutes)); err != nil {
+		return err
 	}
-	return nil
+	if err := r.Runbook.Validate(); err != nil {
+		return err
+	}
+	return validateValueTransforms(r.ValueTemplate, r.ValuePattern)
 }
 
 // ApplyTo updates an existing GroupingRule with the request values.
 func (r *UpdateGroupingRuleRequest) ApplyTo(gr *GroupingRule) {
 	gr.Name = r.Name
 	gr.GroupingKey = r.GroupingKey
-	gr.TimeWindowMinutes = r.TimeWindowMinutes
+	gr.setTimeWindow(effectiveTimeWindow(r.TimeWindow, r.TimeWindowMinutes))
+	gr.ValueTemplate = r.ValueTemplate
+	gr.ValuePattern = r.ValuePattern
+	gr.Runbook = r.Runbook
 	gr.UpdatedAt = time.Now().UTC()
 }
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 )
@@ -54,6 +56,24 @@ func TestGroupingRule_Validate(t *testing.T) {
 			},
 			wantErr: ErrInvalidTimeWindow,
 		},
+		{
+			name: "sub-minute time window",
+			rule: GroupingRule{
+				Name:        "Test Rule",
+				GroupingKey: "class",
+				Window:      Duration(30 * time.Second),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "fractional second time window",
+			rule: GroupingRule{
+				Name:        "Test Rule",
+				GroupingKey: "class",
+				Window:      Duration(1500 * time.Millisecond),
+			},
+			wantErr: ErrInvalidTimeWindow,
+		},
 	}
 
 	for _, tt := range tests {
@@ -73,6 +93,40 @@ func TestGroupingRule_TimeWindow(t *testing.T) {
 	if got := rule.TimeWindow(); got != expected {
 		t.Errorf("TimeWindow() = %v, want %v", got, expected)
 	}
+
+	rule.Window = Duration(90 * time.Second)
+	if got := rule.TimeWindow(); got != 90*time.Second {
+		t.Errorf("TimeWindow() with Window = %v, want %v", got, 90*time.Second)
+	}
+}
+
+func TestDuration_JSON(t *testing.T) {
+	var req CreateGroupingRuleRequest
+	if err := json.Unmarshal([]byte(`{"time_window":"1h30m"}`), &req); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if time.Duration(req.TimeWindow) != 90*time.Minute {
+		t.Errorf("TimeWindow = %v, want %v", time.Duration(req.TimeWindow), 90*time.Minute)
+	}
+
+	if err := json.Unmarshal([]byte(`{"time_window":"soon"}`), &req); err == nil {
+		t.Error("Unmarshal() of an invalid duration should fail")
+	}
+
+	for d, want := range map[time.Duration]string{
+		30 * time.Second: `"30s"`,
+		5 * time.Minute:  `"5m"`,
+		2 * time.Hour:    `"2h"`,
+		90 * time.Minute: `"1h30m"`,
+	} {
+		got, err := json.Marshal(Duration(d))
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		if string(got) != want {
+			t.Errorf("Marshal(%v) = %s, want %s", d, got, want)
+		}
+	}
 }
 
 func TestGroupingRule_ExtractGroupingValue(t *testing.T) {
@@ -119,6 +173,78 @@ func TestGroupingRule_ExtractGroupingValue(t *testing.T) {
 	}
 }
 
+func TestGroupingRule_ExtractGroupingValue_Transforms(t *testing.T) {
+	event := &Event{
+		Summary:  "disk full on web-01.prod-eu.example.com",
+		Severity: SeverityHigh,
+		Class:    "storage",
+		DedupKey: "web-01.prod-eu.example.com",
+	}
+
+	tests := []struct {
+		name string
+		rule GroupingRule
+		want string
+	}{
+		{
+			name: "pattern capture group",
+			rule: GroupingRule{GroupingKey: "dedup_key", ValuePattern: `^[^.]+\.([^.]+)\.`},
+			want: "prod-eu",
+		},
+		{
+			name: "pattern without groups keeps whole match",
+			rule: GroupingRule{GroupingKey: "summary", ValuePattern: `web-\d+`},
+			want: "web-01",
+		},
+		{
+			name: "unmatched pattern keeps value",
+			rule: GroupingRule{GroupingKey: "class", ValuePattern: `^db-(.*)`},
+			want: "storage",
+		},
+		{
+			name: "template composes fields",
+			rule: GroupingRule{GroupingKey: "class", ValueTemplate: "{{.Class}}/{{.Severity}}"},
+			want: "storage/high",
+		},
+		{
+			name: "pattern applied to template output",
+			rule: GroupingRule{GroupingKey: "class", ValueTemplate: "{{.Class}}:{{.DedupKey}}", ValuePattern: `^(\w+:[^.]+)`},
+			want: "storage:web-01",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.ExtractGroupingValue(event); got != tt.want {
+				t.Errorf("ExtractGroupingValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupingRule_Validate_Transforms(t *testing.T) {
+	base := GroupingRule{Name: "Test Rule", GroupingKey: "class", TimeWindowMinutes: 5}
+
+	rule := base
+	rule.ValuePattern = "("
+	if err := rule.Validate(); !errors.Is(err, ErrInvalidValuePattern) {
+		t.Errorf("Validate() error = %v, want %v", err, ErrInvalidValuePattern)
+	}
+
+	rule = base
+	rule.ValueTemplate = "{{.Hostname}}"
+	if err := rule.Validate(); !errors.Is(err, ErrInvalidValueTemplate) {
+		t.Errorf("Validate() error = %v, want %v", err, ErrInvalidValueTemplate)
+	}
+
+	rule = base
+	rule.ValueTemplate = "{{.Class}}-{{.Severity}}"
+	rule.ValuePattern = `^(\w+)`
+	if err := rule.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+}
+
 func TestCreateGroupingRuleRequest_ToGroupingRule(t *testing.T) {
 	req := CreateGroupingRuleRequest{
 		Name:              "Test Rule",
@@ -179,3 +305,62 @@ func TestUpdateGroupingRuleRequest_ApplyTo(t *testing.T) {
 		t.Errorf("ID should not change, got %v", rule.ID)
 	}
 }
+
+func TestEventManager_GroupingRuleFor(t *testing.T) {
+	em := &EventManager{
+		GroupingRuleID: "default",
+		GroupingRules: []GroupingRuleBinding{
+			{GroupingRuleID: "db-critical", Match: EventMatcher{Classes: []string{"database"}, Severities: []Severity{SeverityHigh}}},
+			{GroupingRuleID: "db", Match: EventMatcher{Classes: []string{"database"}}},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		event Event
+		want  string
+	}{
+		{"first match wins", Event{Class: "database", Severity: SeverityHigh}, "db-critical"},
+		{"later match", Event{Class: "database", Severity: SeverityLow}, "db"},
+		{"no match uses default", Event{Class: "network", Severity: SeverityHigh}, "default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := em.GroupingRuleFor(&tt.event); got != tt.want {
+				t.Errorf("GroupingRuleFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := em.GroupingRuleIDs(); len(got) != 3 || got[0] != "default" {
+		t.Errorf("GroupingRuleIDs() = %v, want default rule first and 3 IDs", got)
+	}
+
+	em.CandidateGroupingRuleID = "candidate"
+	if got := em.GroupingRuleIDs(); len(got) != 4 || got[3] != "candidate" {
+		t.Errorf("GroupingRuleIDs() = %v, want candidate rule last", got)
+	}
+}
+
+func TestCreateEventManagerRequest_Validate_GroupingRules(t *testing.T) {
+	req := CreateEventManagerRequest{
+		Name:           "EM",
+		GroupingRuleID: "default",
+		GroupingRules:  []GroupingRuleBinding{{Match: EventMatcher{Classes: []string{"database"}}}},
+	}
+	if err := req.Validate(); err != ErrEmptyGroupingRuleID {
+		t.Errorf("Validate() error = %v, want %v", err, ErrEmptyGroupingRuleID)
+	}
+
+	req.GroupingRules[0].GroupingRuleID = "db"
+	req.GroupingRules[0].Match.Severities = []Severity{"urgent"}
+	if err := req.Validate(); err != ErrInvalidSeverity {
+		t.Errorf("Validate() error = %v, want %v", err, ErrInvalidSeverity)
+	}
+
+	disabled := CreateEventManagerRequest{Name: "EM", GroupingDisabled: true, CandidateGroupingRuleID: "candidate"}
+	if err := disabled.Validate(); err != ErrGroupingDisabledWithRules {
+		t.Errorf("Validate() error = %v, want %v", err, ErrGroupingDisabledWithRules)
+	}
+}
@@ -0,0 +1,219 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Timeline events derived from alert revisions and the remediation log.
+const (
+	TimelineCreated          = "created"
+	TimelineRetriggered      = "retriggered"
+	TimelineAcknowledged     = "acknowledged"
+	TimelineResolveRequested = "resolve_requested"
+	TimelineResolved         = "resolved"
+	TimelineReactivated      = "reactivated"
+	TimelineRemediation      = "remediation"
+)
+
+// timelineEvents are the timeline events of the transitions of alerts.
+var timelineEvents = map[AlertTransitionKind]string{
+	TransitionAcknowledge:    TimelineAcknowledged,
+	TransitionRequestResolve: TimelineResolveRequested,
+	TransitionResolve:        TimelineResolved,
+	TransitionReactivate:     TimelineReactivated,
+}
+
+// TimelineEntry is one change to an alert of an incident.
+type TimelineEntry struct {
+	At       time.Time `json:"at"`
+	DedupKey string    `json:"dedupKey"`
+	Event    string    `json:"event"`
+	Detail   string    `json:"detail,omitempty"`
+}
+
+// IncidentReport is a self-contained snapshot of a parent alert and its
+// children, for pasting into postmortems.
+type IncidentReport struct {
+	GeneratedAt   time.Time             `json:"generated_at"`
+	Parent        *Alert                `json:"parent"`
+	Children      []*Alert              `json:"children"`
+	Timeline      []TimelineEntry       `json:"timeline"`
+	Notifications []*NotificationRecord `json:"notifications"`
+}
+
+// NewIncidentReport builds the report of a parent alert from its children, the
+// revisions of the parent and every child, the notifications sent about the
+// parent and the remediation actions run for any of them. Notifications and
+// remediations older than the parent, left over from an earlier alert with
+// the same dedup key, are dropped.
+func NewIncidentReport(
+	parent *Alert,
+	children []*Alert,
+	revisions [][]*AlertRevision,
+	notifications []*NotificationRecord,
+	remediations []*RemediationRecord,
+) *IncidentReport {
+	report := &IncidentReport{
+		GeneratedAt:   time.Now().UTC(),
+		Parent:        parent,
+		Children:      children,
+		Timeline:      []TimelineEntry{},
+		Notifications: []*NotificationRecord{},
+	}
+	if report.Children == nil {
+		report.Children = []*Alert{}
+	}
+
+	for _, history := range revisions {
+		report.Timeline = append(report.Timeline, timelineOf(history)...)
+	}
+	for _, record := range remediations {
+		if record.ExecutedAt.Before(parent.CreatedAt) {
+			continue
+		}
+		detail := fmt.Sprintf("%s %s", record.Action, record.Status)
+		if record.Detail != "" {
+			detail += ": " + record.Detail
+		}
+		report.Timeline = append(report.Timeline, TimelineEntry{
+			At:       record.ExecutedAt,
+			DedupKey: record.DedupKey,
+			Event:    TimelineRemediation,
+			Detail:   detail,
+		})
+	}
+	sort.SliceStable(report.Timeline, func(i, j int) bool {
+		return report.Timeline[i].At.Before(report.Timeline[j].At)
+	})
+
+	for _, record := range notifications {
+		if !record.SentAt.Before(parent.CreatedAt) {
+			report.Notifications = append(report.Notifications, record)
+		}
+	}
+
+	return report
+}
+
+// timelineOf derives the timeline entries of one alert from its revisions,
+// oldest first, from the transitions between each revision and the previous
+// one.
+func timelineOf(history []*AlertRevision) []TimelineEntry {
+	var entries []TimelineEntry
+	var prev *Alert
+	for _, revision := range history {
+		alert := &revision.Alert
+		add := func(event, detail string) {
+			entries = append(entries, TimelineEntry{At: revision.RecordedAt, DedupKey: alert.DedupKey, Event: event, Detail: detail})
+		}
+
+		if prev == nil {
+			add(TimelineCreated, fmt.Sprintf("%s alert: %s", alert.Type, alert.Summary))
+			prev = alert
+			continue
+		}
+
+		transitions := TransitionsBetween(prev, alert, revision.RecordedAt)
+		if alert.TriggerCount > prev.TriggerCount && (len(transitions) == 0 || transitions[0].Kind == TransitionAcknowledge) {
+			add(TimelineRetriggered, fmt.Sprintf("trigger count %d", alert.TriggerCount))
+		}
+		for _, t := range transitions {
+			add(timelineEvents[t.Kind], t.Resolution.describe())
+		}
+
+		prev = alert
+	}
+	return entries
+}
+
+// describe summarizes a resolution for the timeline.
+func (r *Resolution) describe() string {
+	if r == nil {
+		return ""
+	}
+	parts := []string{"by " + string(r.ResolvedBy)}
+	if r.Actor != "" {
+		parts = append(parts, "actor "+r.Actor)
+	}
+	if r.Reason != "" {
+		parts = append(parts, r.Reason)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Markdown renders the report as a Markdown document.
+func (r *IncidentReport) Markdown() string {
+	var sb strings.Builder
+	p := r.Parent
+
+	fmt.Fprintf(&sb, "# Incident report: %s\n\n", p.Summary)
+	fmt.Fprintf(&sb, "- **Dedup key:** `%s`\n", p.DedupKey)
+	fmt.Fprintf(&sb, "- **Event manager:** `%s`\n", p.EventManagerID)
+	fmt.Fprintf(&sb, "- **Severity:** %s\n", p.Severity)
+	fmt.Fprintf(&sb, "- **Class:** %s\n", p.Class)
+	fmt.Fprintf(&sb, "- **Status:** %s\n", p.Status)
+	fmt.Fprintf(&sb, "- **Created:** %s\n", formatReportTime(p.CreatedAt))
+	if p.AcknowledgedAt != nil {
+		fmt.Fprintf(&sb, "- **Acknowledged:** %s\n", formatReportTime(*p.AcknowledgedAt))
+	}
+	if p.ResolvedAt != nil {
+		fmt.Fprintf(&sb, "- **Resolved:** %s (after %s)\n", formatReportTime(*p.ResolvedAt), p.ResolvedAt.Sub(p.CreatedAt).Round(time.Second))
+	}
+	if p.IsResolved() && p.Resolution != nil {
+		fmt.Fprintf(&sb, "- **Resolution:** %s\n", markdownCell(p.Resolution.describe()))
+	}
+	fmt.Fprintf(&sb, "- **Report generated:** %s\n", formatReportTime(r.GeneratedAt))
+
+	fmt.Fprintf(&sb, "\n## Grouped alerts (%d)\n\n", len(r.Children))
+	if len(r.Children) == 0 {
+		sb.WriteString("No child alerts.\n")
+	} else {
+		sb.WriteString("| Dedup key | Summary | Severity | Status | Created | Resolved |\n")
+		sb.WriteString("|---|---|---|---|---|---|\n")
+		for _, child := range r.Children {
+			resolved := ""
+			if child.ResolvedAt != nil {
+				resolved = formatReportTime(*child.ResolvedAt)
+			}
+			fmt.Fprintf(&sb, "| `%s` | %s | %s | %s | %s | %s |\n",
+				markdownCell(child.DedupKey), markdownCell(child.Summary), child.Severity, child.Status,
+				formatReportTime(child.CreatedAt), resolved)
+		}
+	}
+
+	sb.WriteString("\n## Timeline\n\n")
+	sb.WriteString("| Time | Alert | Event | Detail |\n")
+	sb.WriteString("|---|---|---|---|\n")
+	for _, entry := range r.Timeline {
+		fmt.Fprintf(&sb, "| %s | `%s` | %s | %s |\n",
+			formatReportTime(entry.At), markdownCell(entry.DedupKey), entry.Event, markdownCell(entry.Detail))
+	}
+
+	sb.WriteString("\n## Notifications\n\n")
+	if len(r.Notifications) == 0 {
+		sb.WriteString("No notifications sent.\n")
+	} else {
+		sb.WriteString("| Time | Kind | Event manager | Target |\n")
+		sb.WriteString("|---|---|---|---|\n")
+		for _, record := range r.Notifications {
+			fmt.Fprintf(&sb, "| %s | %s | `%s` | %s |\n",
+				formatReportTime(record.SentAt), record.Kind, markdownCell(record.EventManagerID), markdownCell(record.Target))
+		}
+	}
+
+	return sb.String()
+}
+
+// formatReportTime formats a time for the Markdown report.
+func formatReportTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// markdownCell escapes text for a Markdown table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
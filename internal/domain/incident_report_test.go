@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewIncidentReport(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	parent := NewParentAlert(&Event{DedupKey: "db-down", Summary: "Database | primary down", Class: "database"}, at(0))
+	child := NewChildAlert(&Event{DedupKey: "api-errors", Summary: "API errors"}, "db-down", at(1))
+
+	revision := func(n, minutes int, alert Alert) *AlertRevision {
+		return &AlertRevision{Revision: n, RecordedAt: at(minutes), Alert: alert}
+	}
+	parentHistory := []*AlertRevision{revision(1, 0, *parent)}
+	parent.MarkResolveRequested(Resolution{ResolvedBy: ResolvedByEvent, Actor: "prometheus", Reason: "failover done"}, at(5))
+	parentHistory = append(parentHistory, revision(2, 5, *parent))
+	parent.Resolve(*parent.Resolution, at(9))
+	parentHistory = append(parentHistory, revision(3, 9, *parent))
+
+	childHistory := []*AlertRevision{revision(1, 1, *child)}
+	_, _ = child.Acknowledge(at(3))
+	childHistory = append(childHistory, revision(2, 3, *child))
+	child.Resolve(Resolution{ResolvedBy: ResolvedByEvent}, at(8))
+	childHistory = append(childHistory, revision(3, 8, *child))
+
+	notifications := []*NotificationRecord{
+		{DedupKey: "db-down", Kind: NotificationResolved, SentAt: at(-60)},
+		{DedupKey: "db-down", Kind: NotificationNewParent, SentAt: at(0)},
+	}
+
+	remediations := []*RemediationRecord{
+		{DedupKey: "db-down", Action: "failover", Status: RemediationSucceeded, Detail: "HTTP 200", ExecutedAt: at(0)},
+		{DedupKey: "db-down", Action: "failover", Status: RemediationFailed, ExecutedAt: at(-60)},
+	}
+
+	report := NewIncidentReport(parent, []*Alert{child}, [][]*AlertRevision{parentHistory, childHistory}, notifications, remediations)
+
+	want := []string{
+		"db-down " + TimelineCreated,
+		"db-down " + TimelineRemediation,
+		"api-errors " + TimelineCreated,
+		"api-errors " + TimelineAcknowledged,
+		"db-down " + TimelineResolveRequested,
+		"api-errors " + TimelineResolved,
+		"db-down " + TimelineResolved,
+	}
+	var got []string
+	for _, entry := range report.Timeline {
+		got = append(got, entry.DedupKey+" "+entry.Event)
+	}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("timeline = %v, want %v", got, want)
+	}
+	if detail := report.Timeline[1].Detail; detail != "failover succeeded: HTTP 200" {
+		t.Errorf("remediation detail = %q", detail)
+	}
+	if detail := report.Timeline[6].Detail; detail != "by event, actor prometheus, failover done" {
+		t.Errorf("resolved detail = %q", detail)
+	}
+
+	// Notifications of an earlier alert with the same dedup key are dropped
+	if len(report.Notifications) != 1 || report.Notifications[0].Kind != NotificationNewParent {
+		t.Errorf("notifications = %v, want only the new_parent notification", report.Notifications)
+	}
+
+	md := report.Markdown()
+	for _, s := range []string{
+		`# Incident report: Database | primary down`,
+		"## Grouped alerts (1)",
+		"| `api-errors` | API errors |",
+		"- **Resolution:** by event, actor prometheus, failover done",
+		"## Notifications",
+	} {
+		if !strings.Contains(md, s) {
+			t.Errorf("Markdown() is missing %q:\n%s", s, md)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package domain
+
+// IngestDryRun is the result of ingesting an event with dry_run: how it was
+// normalized and routed, and how the processor would handle it given the
+// alerts at the time.
+type IngestDryRun struct {
+	EventManagerID string `json:"event_manager_id"`
+	DedupKey       string `json:"dedupKey"`
+
+	// OriginalDedupKey is the dedup key as sent, if normalization hashed it.
+	OriginalDedupKey string `json:"original_dedupKey,omitempty"`
+
+	// GroupingRuleID is the grouping rule selected for the event, empty if
+	// the event would not be grouped.
+	GroupingRuleID string `json:"grouping_rule_id,omitempty"`
+	GroupingKey    string `json:"grouping_key,omitempty"`
+	GroupingValue  string `json:"grouping_value,omitempty"`
+	PartitionKey   string `json:"partition_key"`
+
+	// Outcome is what the processor would do with the event.
+	Outcome GroupingOutcome `json:"outcome"`
+
+	// ParentDedupKey is the parent the alert is or would be grouped under.
+	ParentDedupKey string `json:"parent_dedupKey,omitempty"`
+
+	// AlertStatus is the status of the existing alert with the dedup key,
+	// empty if there is none.
+	AlertStatus string `json:"alert_status,omitempty"`
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Integrations whose native webhooks are turned into events.
+const (
+	IntegrationGitHub  = "github"
+	IntegrationGitLab  = "gitlab"
+	IntegrationSentry  = "sentry"
+	IntegrationRollbar = "rollbar"
+	IntegrationAzure   = "azure"
+	IntegrationGCP     = "gcp"
+)
+
+// Errors of integration webhooks.
+var (
+	ErrUnknownIntegration        = notFoundError("unknown integration")
+	ErrInvalidIntegrationWebhook = invalidError("invalid integration webhook payload")
+
+	// ErrIntegrationWebhookIgnored is returned for webhooks that neither
+	// raise nor clear a problem, e.g. a canceled pipeline or an assigned
+	// issue.
+	ErrIntegrationWebhookIgnored = errors.New("integration webhook ignored")
+)
+
+// integrationParsers map the webhooks of each integration to events, given
+// the request headers and body.
+var integrationParsers = map[string]func(header func(string) string, body []byte) (*Event, error){
+	IntegrationGitHub:  parseGitHubWebhook,
+	IntegrationGitLab:  parseGitLabWebhook,
+	IntegrationSentry:  parseSentryWebhook,
+	IntegrationRollbar: parseRollbarWebhook,
+	IntegrationAzure:   parseAzureWebhook,
+	IntegrationGCP:     parseGCPWebhook,
+}
+
+// ParseIntegrationWebhook turns the native webhook of an integration into an
+// event. header returns the value of a request header. The event manager is
+// left for the caller to set.
+func ParseIntegrationWebhook(integration string, header func(string) string, body []byte) (*Event, error) {
+	parse, ok := integrationParsers[integration]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownIntegration, integration)
+	}
+	return parse(header, body)
+}
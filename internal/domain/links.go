@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Limits on the links of an alert.
+const (
+	MaxAlertLinks      = 16
+	MaxLinkTypeLength  = 32
+	MaxLinkTitleLength = 128
+	MaxLinkURLLength   = 2048
+)
+
+// Common link types. Any type is accepted; these are the ones integrations
+// and notification channels know to present.
+const (
+	LinkTypeDashboard = "dashboard"
+	LinkTypeLogs      = "logs"
+	LinkTypeTicket    = "ticket"
+	LinkTypeTrace     = "trace"
+)
+
+// Validation errors for alert links.
+var (
+	ErrTooManyLinks        = invalidError(fmt.Sprintf("an alert can carry at most %d links", MaxAlertLinks))
+	ErrEmptyLinkType       = invalidError("links need a type")
+	ErrLinkTypeTooLong     = invalidError(fmt.Sprintf("link types must be at most %d characters", MaxLinkTypeLength))
+	ErrLinkTitleTooLong    = invalidError(fmt.Sprintf("link titles must be at most %d characters", MaxLinkTitleLength))
+	ErrInvalidLinkURL      = invalidError(fmt.Sprintf("link url must be an absolute http or https URL of at most %d characters", MaxLinkURLLength))
+	ErrInvalidLinkTemplate = invalidError("invalid link url template")
+	ErrEmptyLinksUpdate    = invalidError("links or remove is required")
+)
+
+// AlertLink links an alert to an external object responders need, such as a
+// dashboard, a log query or a ticket. Links are sent with the notifications
+// of the alert.
+type AlertLink struct {
+	// Type says what the link points to, e.g. "dashboard".
+	Type string `json:"type" yaml:"type"`
+
+	URL string `json:"url" yaml:"url"`
+
+	// Title is shown instead of the URL, if set.
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+}
+
+// Validate checks the type, title and URL of the link.
+func (l *AlertLink) Validate() error {
+	if l.Type == "" {
+		return ErrEmptyLinkType
+	}
+	if len(l.Type) > MaxLinkTypeLength {
+		return ErrLinkTypeTooLong
+	}
+	if len(l.Title) > MaxLinkTitleLength {
+		return ErrLinkTitleTooLong
+	}
+	return validateLinkURL(l.URL)
+}
+
+// validateLinkURL checks a URL is an absolute http(s) URL within the limit.
+func validateLinkURL(raw string) error {
+	if len(raw) > MaxLinkURLLength {
+		return ErrInvalidLinkURL
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidLinkURL
+	}
+	return nil
+}
+
+// validateLinks checks every link and their number.
+func validateLinks(links []AlertLink) error {
+	if len(links) > MaxAlertLinks {
+		return ErrTooManyLinks
+	}
+	for i := range links {
+		if err := links[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MergeLinks adds links to the links of an alert, returning the result
+// without modifying either. A link replaces the link of the alert with the
+// same URL; beyond MaxAlertLinks the oldest links are dropped.
+func MergeLinks(alertLinks, links []AlertLink) []AlertLink {
+	if len(links) == 0 {
+		return alertLinks
+	}
+	merged := make([]AlertLink, 0, len(alertLinks)+len(links))
+	for _, link := range alertLinks {
+		if !slices.ContainsFunc(links, func(l AlertLink) bool { return l.URL == link.URL }) {
+			merged = append(merged, link)
+		}
+	}
+	merged = append(merged, links...)
+	if len(merged) > MaxAlertLinks {
+		merged = merged[len(merged)-MaxAlertLinks:]
+	}
+	return merged
+}
+
+// RemoveLinks returns the links of an alert without those with the given
+// URLs, without modifying them.
+func RemoveLinks(alertLinks []AlertLink, urls []string) []AlertLink {
+	if len(urls) == 0 {
+		return alertLinks
+	}
+	return slices.DeleteFunc(slices.Clone(alertLinks), func(l AlertLink) bool {
+		return slices.Contains(urls, l.URL)
+	})
+}
+
+// UpdateLinksRequest changes the links of an alert.
+type UpdateLinksRequest struct {
+	// Links are added, replacing the links with the same URL.
+	Links []AlertLink `json:"links"`
+
+	// Remove lists the URLs of links to remove.
+	Remove []string `json:"remove"`
+}
+
+// Validate checks the links to add, and that the request changes something.
+func (r *UpdateLinksRequest) Validate() error {
+	if len(r.Links) == 0 && len(r.Remove) == 0 {
+		return ErrEmptyLinksUpdate
+	}
+	return validateLinks(r.Links)
+}
+
+// AlertLinkTemplate adds a link to the alerts of an event manager, so every
+// alert carries the dashboards and log queries of what it is about without
+// senders having to link them.
+type AlertLinkTemplate struct {
+	Type  string `json:"type" yaml:"type"`
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+
+	// URL is a Go template rendered with the alert, e.g.
+	// "https://grafana.example.com/d/svc?var-service={{urlquery .Service}}".
+	URL string `json:"url" yaml:"url"`
+
+	// Classes limits the link to the alerts of these classes; empty links
+	// every alert.
+	Classes []string `json:"classes,omitempty" yaml:"classes,omitempty"`
+}
+
+// parse parses the URL template of the link.
+func (t *AlertLinkTemplate) parse() (*template.Template, error) {
+	tmpl, err := template.New("link").Option("missingkey=zero").Parse(t.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidLinkTemplate, err)
+	}
+	return tmpl, nil
+}
+
+// render returns the link of an alert, or false if the template doesn't
+// apply to its class or doesn't render to a valid URL.
+func (t *AlertLinkTemplate) render(alert *Alert) (AlertLink, bool) {
+	if len(t.Classes) > 0 && !slices.Contains(t.Classes, alert.Class) {
+		return AlertLink{}, false
+	}
+	tmpl, err := t.parse()
+	if err != nil {
+		return AlertLink{}, false
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, alert); err != nil || validateLinkURL(b.String()) != nil {
+		return AlertLink{}, false
+	}
+	return AlertLink{Type: t.Type, URL: b.String(), Title: t.Title}, true
+}
+
+// validateLinkTemplates checks the link templates of an event manager.
+func validateLinkTemplates(templates []AlertLinkTemplate) error {
+	if len(templates) > MaxAlertLinks {
+		return ErrTooManyLinks
+	}
+	for i := range templates {
+		t := &templates[i]
+		link := AlertLink{Type: t.Type, URL: "https://example.com", Title: t.Title}
+		if err := link.Validate(); err != nil {
+			return err
+		}
+		if t.URL == "" {
+			return ErrInvalidLinkURL
+		}
+		if _, err := t.parse(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LinksFor returns the links the templates of an event manager add to an
+// alert. Templates that don't render to a valid URL for the alert are
+// skipped.
+func LinksFor(em *EventManager, alert *Alert) []AlertLink {
+	var links []AlertLink
+	for i := range em.Links {
+		if link, ok := em.Links[i].render(alert); ok {
+			links = append(links, link)
+		}
+	}
+	return links
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMergeLinks(t *testing.T) {
+	alertLinks := []AlertLink{
+		{Type: LinkTypeDashboard, URL: "https://grafana.example.com/d/db"},
+		{Type: LinkTypeTicket, URL: "https://jira.example.com/OPS-1"},
+	}
+	links := []AlertLink{{Type: LinkTypeTicket, URL: "https://jira.example.com/OPS-1", Title: "OPS-1"}}
+
+	merged := MergeLinks(alertLinks, links)
+	want := []AlertLink{alertLinks[0], links[0]}
+	if !slices.Equal(merged, want) {
+		t.Errorf("MergeLinks() = %v, want %v", merged, want)
+	}
+	if alertLinks[1].Title != "" {
+		t.Errorf("MergeLinks modified the alert links: %v", alertLinks)
+	}
+
+	var many []AlertLink
+	for i := range MaxAlertLinks + 2 {
+		many = append(many, AlertLink{Type: LinkTypeLogs, URL: "https://logs.example.com/" + strings.Repeat("q", i+1)})
+	}
+	if merged := MergeLinks(nil, many); len(merged) != MaxAlertLinks || merged[0] != many[2] {
+		t.Errorf("MergeLinks() over the limit = %d links starting with %v, want the newest %d", len(merged), merged[0], MaxAlertLinks)
+	}
+}
+
+func TestRemoveLinks(t *testing.T) {
+	alertLinks := []AlertLink{
+		{Type: LinkTypeDashboard, URL: "https://grafana.example.com/d/db"},
+		{Type: LinkTypeTicket, URL: "https://jira.example.com/OPS-1"},
+	}
+
+	removed := RemoveLinks(alertLinks, []string{"https://jira.example.com/OPS-1", "https://unknown.example.com"})
+	if want := alertLinks[:1]; !slices.Equal(removed, want) {
+		t.Errorf("RemoveLinks() = %v, want %v", removed, want)
+	}
+	if len(alertLinks) != 2 || alertLinks[1].Type != LinkTypeTicket {
+		t.Errorf("RemoveLinks modified the alert links: %v", alertLinks)
+	}
+}
+
+func TestAlertLink_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		link AlertLink
+		want error
+	}{
+		{"valid", AlertLink{Type: LinkTypeDashboard, URL: "https://grafana.example.com/d/db"}, nil},
+		{"no type", AlertLink{URL: "https://grafana.example.com"}, ErrEmptyLinkType},
+		{"long type", AlertLink{Type: strings.Repeat("t", MaxLinkTypeLength+1), URL: "https://grafana.example.com"}, ErrLinkTypeTooLong},
+		{"long title", AlertLink{Type: LinkTypeLogs, URL: "https://grafana.example.com", Title: strings.Repeat("t", MaxLinkTitleLength+1)}, ErrLinkTitleTooLong},
+		{"relative url", AlertLink{Type: LinkTypeLogs, URL: "/d/db"}, ErrInvalidLinkURL},
+		{"other scheme", AlertLink{Type: LinkTypeLogs, URL: "javascript:alert(1)"}, ErrInvalidLinkURL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.link.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinksFor(t *testing.T) {
+	em := &EventManager{Links: []AlertLinkTemplate{
+		{Type: LinkTypeDashboard, Title: "Service dashboard", URL: "https://grafana.example.com/d/svc?var-service={{urlquery .Service}}"},
+		{Type: LinkTypeLogs, URL: "https://logs.example.com/?q={{urlquery .DedupKey}}", Classes: []string{"database"}},
+		// Renders to a relative URL without a service, and is skipped
+		{Type: LinkTypeTrace, URL: "{{.Service}}"},
+	}}
+	alert := &Alert{DedupKey: "disk full", Class: "host", Service: "checkout api"}
+
+	links := LinksFor(em, alert)
+	want := []AlertLink{{Type: LinkTypeDashboard, Title: "Service dashboard", URL: "https://grafana.example.com/d/svc?var-service=checkout+api"}}
+	if !slices.Equal(links, want) {
+		t.Errorf("LinksFor() = %v, want %v", links, want)
+	}
+
+	alert.Class = "database"
+	if links := LinksFor(em, alert); len(links) != 2 || links[1].URL != "https://logs.example.com/?q=disk+full" {
+		t.Errorf("LinksFor() for the class = %v", links)
+	}
+}
+
+func TestValidateLinkTemplates(t *testing.T) {
+	valid := AlertLinkTemplate{Type: LinkTypeDashboard, URL: "https://grafana.example.com/d/{{.Service}}"}
+	if err := validateLinkTemplates([]AlertLinkTemplate{valid}); err != nil {
+		t.Errorf("validateLinkTemplates() = %v, want nil", err)
+	}
+	if err := validateLinkTemplates([]AlertLinkTemplate{{Type: LinkTypeDashboard, URL: "https://grafana.example.com/{{.Service"}}); !errors.Is(err, ErrInvalidLinkTemplate) {
+		t.Errorf("validateLinkTemplates() with a malformed template = %v, want ErrInvalidLinkTemplate", err)
+	}
+	if err := validateLinkTemplates([]AlertLinkTemplate{{URL: valid.URL}}); !errors.Is(err, ErrEmptyLinkType) {
+		t.Errorf("validateLinkTemplates() without a type = %v, want ErrEmptyLinkType", err)
+	}
+}
@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"text/template"
+)
+
+// DefaultLocale is the locale of the built-in notification messages, used
+// for event managers without a locale and for messages a locale lacks.
+const DefaultLocale = "en"
+
+// ErrUnknownLocale is returned for a locale the message catalog has no
+// messages for.
+var ErrUnknownLocale = invalidError("locale has no notification messages")
+
+// localePattern matches BCP 47 style language tags such as "de" or "pt-BR".
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// defaultMessages are the English notification messages.
+var defaultMessages = map[NotificationKind]string{
+	NotificationNewParent:    "New alert: {{.Summary}}",
+	NotificationResolved:     "Resolved: {{.Summary}}",
+	NotificationReminder:     "Reminder {{.ReminderCount}}: {{.Summary}} is still unacknowledged",
+	NotificationChildAdded:   "Grouped under {{.ParentDedupKey}}: {{.Summary}}",
+	NotificationReactivated:  "Triggered again: {{.Summary}}",
+	NotificationAcknowledged: "Acknowledged: {{.Summary}}",
+	NotificationEscalated:    "Escalated: {{.Summary}} was not acknowledged",
+}
+
+// MessageData is what notification message templates are rendered with:
+// the fields of the alert, e.g. {{.Summary}} or {{.ChildCount}}, and the
+// number of a reminder.
+type MessageData struct {
+	*Alert
+	ReminderCount int
+}
+
+// MessageCatalog holds the notification messages of each locale, as Go
+// templates by notification kind.
+type MessageCatalog struct {
+	locales map[string]map[NotificationKind]*template.Template
+}
+
+// NewMessageCatalog creates a catalog of the English messages and the given
+// messages by locale and notification kind. A locale may leave out kinds;
+// they fall back to its language, then to English.
+func NewMessageCatalog(locales map[string]map[NotificationKind]string) (*MessageCatalog, error) {
+	catalog := &MessageCatalog{locales: make(map[string]map[NotificationKind]*template.Template)}
+	if err := catalog.add(DefaultLocale, defaultMessages); err != nil {
+		return nil, err
+	}
+	for locale, messages := range locales {
+		if !localePattern.MatchString(locale) {
+			return nil, fmt.Errorf("locale %q is not a language tag such as \"de\" or \"pt-BR\"", locale)
+		}
+		if err := catalog.add(locale, messages); err != nil {
+			return nil, err
+		}
+	}
+	return catalog, nil
+}
+
+// add parses the messages of a locale, checking they render.
+func (c *MessageCatalog) add(locale string, messages map[NotificationKind]string) error {
+	templates := c.locales[locale]
+	if templates == nil {
+		templates = make(map[NotificationKind]*template.Template)
+		c.locales[locale] = templates
+	}
+	for kind, message := range messages {
+		if !kind.IsValid() {
+			return fmt.Errorf("locale %q: unknown notification kind %q", locale, kind)
+		}
+		tmpl, err := template.New(string(kind)).Parse(message)
+		if err == nil {
+			err = tmpl.Execute(&strings.Builder{}, &MessageData{Alert: &Alert{}})
+		}
+		if err != nil {
+			return fmt.Errorf("locale %q: invalid %s message: %w", locale, kind, err)
+		}
+		templates[kind] = tmpl
+	}
+	return nil
+}
+
+// HasLocale reports whether the catalog has messages for a locale or its
+// language.
+func (c *MessageCatalog) HasLocale(locale string) bool {
+	for _, candidate := range localeChain(locale) {
+		if _, ok := c.locales[candidate]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Message renders the message of a notification kind in a locale, falling
+// back to the locale's language, then to English. It returns an empty
+// message for unknown kinds.
+func (c *MessageCatalog) Message(locale string, kind NotificationKind, data *MessageData) string {
+	for _, candidate := range append(localeChain(locale), DefaultLocale) {
+		tmpl, ok := c.locales[candidate][kind]
+		if !ok {
+			continue
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, data); err == nil {
+			return b.String()
+		}
+	}
+	return ""
+}
+
+// localeChain returns a locale followed by its less specific tags, e.g.
+// "pt-BR" and "pt".
+func localeChain(locale string) []string {
+	var chain []string
+	for locale != "" {
+		chain = append(chain, locale)
+		i := strings.LastIndexByte(locale, '-')
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return chain
+}
+
+// DefaultMessageCatalog holds only the English messages.
+var DefaultMessageCatalog = mustMessageCatalog()
+
+func mustMessageCatalog() *MessageCatalog {
+	catalog, err := NewMessageCatalog(nil)
+	if err != nil {
+		panic(err)
+	}
+	return catalog
+}
+
+// currentMessageCatalog is the message catalog of the deployment.
+var currentMessageCatalog atomic.Pointer[MessageCatalog]
+
+func init() {
+	currentMessageCatalog.Store(DefaultMessageCatalog)
+}
+
+// SetMessageCatalog replaces the catalog notification messages are rendered
+// from and event manager locales are validated against. It is set once at
+// startup, from the configuration.
+func SetMessageCatalog(catalog *MessageCatalog) {
+	currentMessageCatalog.Store(catalog)
+}
+
+// CurrentMessageCatalog returns the message catalog of the deployment.
+func CurrentMessageCatalog() *MessageCatalog {
+	return currentMessageCatalog.Load()
+}
+
+// validateLocale checks the message catalog has messages for a locale.
+// Empty means DefaultLocale.
+func validateLocale(locale string) error {
+	if locale != "" && !CurrentMessageCatalog().HasLocale(locale) {
+		return ErrUnknownLocale
+	}
+	return nil
+}
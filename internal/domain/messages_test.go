@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMessageCatalog_Message(t *testing.T) {
+	catalog, err := NewMessageCatalog(map[string]map[NotificationKind]string{
+		"de":    {NotificationNewParent: "Neuer Alarm: {{.Summary}}", NotificationResolved: "Behoben: {{.Summary}}"},
+		"de-AT": {NotificationResolved: "Erledigt: {{.Summary}}"},
+	})
+	if err != nil {
+		t.Fatalf("NewMessageCatalog() error: %v", err)
+	}
+	data := &MessageData{Alert: &Alert{Summary: "Disk full"}, ReminderCount: 2}
+
+	tests := []struct {
+		locale string
+		kind   NotificationKind
+		want   string
+	}{
+		{"", NotificationNewParent, "New alert: Disk full"},
+		{"en", NotificationReminder, "Reminder 2: Disk full is still unacknowledged"},
+		{"de", NotificationNewParent, "Neuer Alarm: Disk full"},
+		// Regional locales fall back to their language, then to English
+		{"de-AT", NotificationResolved, "Erledigt: Disk full"},
+		{"de-AT", NotificationNewParent, "Neuer Alarm: Disk full"},
+		{"de", NotificationAcknowledged, "Acknowledged: Disk full"},
+		{"fr", NotificationNewParent, "New alert: Disk full"},
+	}
+	for _, tt := range tests {
+		if got := catalog.Message(tt.locale, tt.kind, data); got != tt.want {
+			t.Errorf("Message(%q, %s) = %q, want %q", tt.locale, tt.kind, got, tt.want)
+		}
+	}
+
+	if !catalog.HasLocale("de-CH") || catalog.HasLocale("fr") {
+		t.Errorf("HasLocale(de-CH), HasLocale(fr) = %v, %v, want true, false", catalog.HasLocale("de-CH"), catalog.HasLocale("fr"))
+	}
+}
+
+func TestNewMessageCatalog_Invalid(t *testing.T) {
+	tests := map[string]map[string]map[NotificationKind]string{
+		"locale":   {"German": {NotificationNewParent: "Neuer Alarm"}},
+		"kind":     {"de": {"paged": "Neuer Alarm"}},
+		"template": {"de": {NotificationNewParent: "Neuer Alarm: {{.Summary"}},
+		"field":    {"de": {NotificationNewParent: "Neuer Alarm: {{.Title}}"}},
+	}
+	for name, locales := range tests {
+		if _, err := NewMessageCatalog(locales); err == nil {
+			t.Errorf("NewMessageCatalog() with an invalid %s succeeded", name)
+		}
+	}
+}
+
+func TestEventManager_ValidateLocale(t *testing.T) {
+	catalog, err := NewMessageCatalog(map[string]map[NotificationKind]string{"de": {NotificationNewParent: "Neuer Alarm: {{.Summary}}"}})
+	if err != nil {
+		t.Fatalf("NewMessageCatalog() error: %v", err)
+	}
+	SetMessageCatalog(catalog)
+	t.Cleanup(func() { SetMessageCatalog(DefaultMessageCatalog) })
+
+	for locale, want := range map[string]error{"": nil, "en-GB": nil, "de": nil, "fr": ErrUnknownLocale} {
+		if err := validateLocale(locale); !errors.Is(err, want) {
+			t.Errorf("validateLocale(%q) = %v, want %v", locale, err, want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultNoiseSuggestionScore is the noise score above which a suppression is suggested.
+const DefaultNoiseSuggestionScore = 10.0
+
+// NoiseStats holds the raw trigger and resolve counters of one alert
+// that was active within a report range.
+type NoiseStats struct {
+	EventManagerID string
+	DedupKey       string
+	Class          string
+	Summary        string
+	TriggerCount   int
+	ResolveCount   int
+	CreatedAt      time.Time
+}
+
+// DedupKeyNoise is the computed noise score of a single dedup key.
+type DedupKeyNoise struct {
+	EventManagerID  string  `json:"event_manager_id"`
+	DedupKey        string  `json:"dedupKey"`
+	Class           string  `json:"class"`
+	Summary         string  `json:"summary"`
+	TriggerCount    int     `json:"trigger_count"`
+	ResolveCount    int     `json:"resolve_count"`
+	TriggersPerHour float64 `json:"triggers_per_hour"`
+	ResolutionRate  float64 `json:"resolution_rate"`
+	Score           float64 `json:"score"`
+}
+
+// ClassNoise aggregates noise for every dedup key of a class within an event manager.
+type ClassNoise struct {
+	EventManagerID string  `json:"event_manager_id"`
+	Class          string  `json:"class"`
+	AlertCount     int     `json:"alert_count"`
+	TriggerCount   int     `json:"trigger_count"`
+	ResolveCount   int     `json:"resolve_count"`
+	Score          float64 `json:"score"`
+}
+
+// SuppressionSuggestion proposes suppressing an alert source that is consistently noisy.
+type SuppressionSuggestion struct {
+	EventManagerID string  `json:"event_manager_id"`
+	DedupKey       string  `json:"dedupKey,omitempty"`
+	Class          string  `json:"class,omitempty"`
+	Score          float64 `json:"score"`
+	Reason         string  `json:"reason"`
+}
+
+// NoiseReport is the response for the noise report.
+type NoiseReport struct {
+	From         time.Time                `json:"from"`
+	To           time.Time                `json:"to"`
+	TopDedupKeys []*DedupKeyNoise         `json:"top_dedup_keys"`
+	TopClasses   []*ClassNoise            `json:"top_classes"`
+	Suggestions  []*SuppressionSuggestion `json:"suggestions,omitempty"`
+}
+
+// NoiseScore computes the noise score of an alert source.
+//
+// The score is the trigger frequency (triggers per hour of observed lifetime)
+// weighted by how often the alert resolves and re-fires: a source that flaps
+// between trigger and resolve is noisier than one that stays open.
+func NoiseScore(triggers, resolves int, hours float64) (triggersPerHour, resolutionRate, score float64) {
+	if hours < 1 {
+		hours = 1
+	}
+	triggersPerHour = float64(triggers) / hours
+	if triggers > 0 {
+		resolutionRate = float64(resolves) / float64(triggers)
+	}
+	return triggersPerHour, resolutionRate, triggersPerHour * (1 + resolutionRate)
+}
+
+// BuildNoiseReport scores the given stats and returns the top offenders per
+// dedup key and class. When minSuggestScore is positive, dedup keys scoring at
+// or above it are returned as suppression suggestions.
+func BuildNoiseReport(stats []*NoiseStats, filter ReportFilter, minSuggestScore float64) *NoiseReport {
+	report := &NoiseReport{
+		From:         filter.From,
+		To:           filter.To,
+		TopDedupKeys: []*DedupKeyNoise{},
+		TopClasses:   []*ClassNoise{},
+	}
+
+	classes := make(map[[2]string]*ClassNoise)
+	classHours := make(map[[2]string]float64)
+
+	for _, st := range stats {
+		// Only the part of the alert's lifetime inside the report range counts
+		start := st.CreatedAt
+		if start.Before(filter.From) {
+			start = filter.From
+		}
+		hours := filter.To.Sub(start).Hours()
+
+		perHour, rate, score := NoiseScore(st.TriggerCount, st.ResolveCount, hours)
+		report.TopDedupKeys = append(report.TopDedupKeys, &DedupKeyNoise{
+			EventManagerID:  st.EventManagerID,
+			DedupKey:        st.DedupKey,
+			Class:           st.Class,
+			Summary:         st.Summary,
+			TriggerCount:    st.TriggerCount,
+			ResolveCount:    st.ResolveCount,
+			TriggersPerHour: perHour,
+			ResolutionRate:  rate,
+			Score:           score,
+		})
+
+		key := [2]string{st.EventManagerID, st.Class}
+		cn, ok := classes[key]
+		if !ok {
+			cn = &ClassNoise{EventManagerID: st.EventManagerID, Class: st.Class}
+			classes[key] = cn
+		}
+		cn.AlertCount++
+		cn.TriggerCount += st.TriggerCount
+		cn.ResolveCount += st.ResolveCount
+		if hours > classHours[key] {
+			classHours[key] = hours
+		}
+	}
+
+	for key, cn := range classes {
+		_, _, cn.Score = NoiseScore(cn.TriggerCount, cn.ResolveCount, classHours[key])
+		report.TopClasses = append(report.TopClasses, cn)
+	}
+
+	sort.Slice(report.TopDedupKeys, func(i, j int) bool {
+		if report.TopDedupKeys[i].Score != report.TopDedupKeys[j].Score {
+			return report.TopDedupKeys[i].Score > report.TopDedupKeys[j].Score
+		}
+		return report.TopDedupKeys[i].DedupKey < report.TopDedupKeys[j].DedupKey
+	})
+	sort.Slice(report.TopClasses, func(i, j int) bool {
+		if report.TopClasses[i].Score != report.TopClasses[j].Score {
+			return report.TopClasses[i].Score > report.TopClasses[j].Score
+		}
+		return report.TopClasses[i].Class < report.TopClasses[j].Class
+	})
+
+	limit := filter.TopLimit
+	if limit <= 0 {
+		limit = DefaultReportTopLimit
+	}
+	if len(report.TopDedupKeys) > limit {
+		report.TopDedupKeys = report.TopDedupKeys[:limit]
+	}
+	if len(report.TopClasses) > limit {
+		report.TopClasses = report.TopClasses[:limit]
+	}
+
+	if minSuggestScore > 0 {
+		for _, dk := range report.TopDedupKeys {
+			if dk.Score < minSuggestScore {
+				break
+			}
+			report.Suggestions = append(report.Suggestions, &SuppressionSuggestion{
+				EventManagerID: dk.EventManagerID,
+				DedupKey:       dk.DedupKey,
+				Score:          dk.Score,
+				Reason:         "dedup key triggers frequently relative to its lifetime",
+			})
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNoiseScore(t *testing.T) {
+	perHour, rate, score := NoiseScore(20, 10, 10)
+	if perHour != 2 {
+		t.Errorf("triggersPerHour = %v, want 2", perHour)
+	}
+	if rate != 0.5 {
+		t.Errorf("resolutionRate = %v, want 0.5", rate)
+	}
+	if score != 3 {
+		t.Errorf("score = %v, want 3", score)
+	}
+
+	// Lifetimes shorter than an hour are treated as one hour
+	perHour, _, _ = NoiseScore(5, 0, 0.1)
+	if perHour != 5 {
+		t.Errorf("triggersPerHour = %v, want 5", perHour)
+	}
+}
+
+func TestBuildNoiseReport(t *testing.T) {
+	to := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	filter := ReportFilter{From: to.Add(-24 * time.Hour), To: to, TopLimit: 2}
+
+	stats := []*NoiseStats{
+		{EventManagerID: "em-1", DedupKey: "quiet", Class: "db", TriggerCount: 1, CreatedAt: filter.From},
+		{EventManagerID: "em-1", DedupKey: "flappy", Class: "net", TriggerCount: 240, ResolveCount: 240, CreatedAt: filter.From},
+		{EventManagerID: "em-1", DedupKey: "busy", Class: "db", TriggerCount: 48, CreatedAt: filter.From},
+	}
+
+	report := BuildNoiseReport(stats, filter, 0)
+	if len(report.TopDedupKeys) != 2 {
+		t.Fatalf("TopDedupKeys = %d, want 2", len(report.TopDedupKeys))
+	}
+	if report.TopDedupKeys[0].DedupKey != "flappy" {
+		t.Errorf("top dedup key = %v, want flappy", report.TopDedupKeys[0].DedupKey)
+	}
+	if report.TopDedupKeys[0].Score != 20 {
+		t.Errorf("flappy score = %v, want 20", report.TopDedupKeys[0].Score)
+	}
+	if len(report.TopClasses) != 2 || report.TopClasses[0].Class != "net" {
+		t.Errorf("TopClasses = %+v, want net first", report.TopClasses)
+	}
+	if report.Suggestions != nil {
+		t.Error("Suggestions should be empty when not requested")
+	}
+
+	report = BuildNoiseReport(stats, filter, DefaultNoiseSuggestionScore)
+	if len(report.Suggestions) != 1 || report.Suggestions[0].DedupKey != "flappy" {
+		t.Errorf("Suggestions = %+v, want only flappy", report.Suggestions)
+	}
+}
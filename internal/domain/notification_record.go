@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// NotificationKind identifies the alert change a notification was sent for.
+type NotificationKind string
+
+const (
+	// NotificationNewParent is sent when a new parent alert is created.
+	NotificationNewParent NotificationKind = "new_parent"
+	// NotificationResolved is sent when a parent alert is resolved.
+	NotificationResolved NotificationKind = "resolved"
+	// NotificationReminder is resent while a parent alert stays active and
+	// unacknowledged.
+	NotificationReminder NotificationKind = "reminder"
+	// NotificationChildAdded is sent when a child alert is grouped under a
+	// parent.
+	NotificationChildAdded NotificationKind = "child_added"
+	// NotificationReactivated is sent when a resolved alert is triggered again.
+	NotificationReactivated NotificationKind = "reactivated"
+	// NotificationAcknowledged is sent when an alert is acknowledged.
+	NotificationAcknowledged NotificationKind = "acknowledged"
+	// NotificationEscalated is sent when a parent alert is still
+	// unacknowledged after its last reminder.
+	NotificationEscalated NotificationKind = "escalated"
+)
+
+// IsValid returns true if the kind is a known notification kind.
+func (k NotificationKind) IsValid() bool {
+	switch k {
+	case NotificationNewParent, NotificationResolved, NotificationReminder, NotificationChildAdded,
+		NotificationReactivated, NotificationAcknowledged, NotificationEscalated:
+		return true
+	}
+	return false
+}
+
+// NotificationRecord is an entry of the notification log: one notification
+// sent about an alert to one event manager.
+type NotificationRecord struct {
+	// DedupKey identifies the alert the notification was about.
+	DedupKey string `json:"dedupKey"`
+
+	// EventManagerID is the event manager notified. It differs from the
+	// alert's owner for subscribers under global dedup.
+	EventManagerID string `json:"event_manager_id"`
+
+	// Kind is the alert change notified.
+	Kind NotificationKind `json:"kind"`
+
+	// Target is where the notification was sent, e.g. the webhook URL.
+	Target string `json:"target,omitempty"`
+
+	// SentAt is when the notification was sent.
+	SentAt time.Time `json:"sent_at"`
+}
@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// ParentSummaryStrategy selects the summary a parent alert displays for its
+// group.
+type ParentSummaryStrategy string
+
+const (
+	// ParentSummaryFirst keeps the summary of the event that opened the
+	// parent. It is the default.
+	ParentSummaryFirst ParentSummaryStrategy = "first"
+	// ParentSummaryLatest shows the summary of the alert of the group, the
+	// parent or an active child, that triggered last.
+	ParentSummaryLatest ParentSummaryStrategy = "latest"
+	// ParentSummaryMostSevereChild shows the summary of the most severe
+	// alert of the group, preferring the latest among equally severe ones.
+	ParentSummaryMostSevereChild ParentSummaryStrategy = "most_severe_child"
+	// ParentSummaryTemplate renders a template over the GroupStats of the
+	// group.
+	ParentSummaryTemplate ParentSummaryStrategy = "template"
+)
+
+// Validation errors for ParentSummaryPolicy.
+var (
+	ErrInvalidParentSummaryStrategy = invalidError("parent_summary.strategy must be 'first', 'latest', 'most_severe_child' or 'template'")
+	ErrParentSummaryTemplateMissing = invalidError("parent_summary.template is required by the template strategy")
+	ErrInvalidParentSummaryTemplate = invalidError("parent_summary.template is not a valid template")
+)
+
+// ParentSummaryPolicy decides the summary parent alerts display, recomputed
+// as their children are created, resolved and reactivated.
+type ParentSummaryPolicy struct {
+	Strategy ParentSummaryStrategy `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+
+	// Template is the Go template of the template strategy, rendered with
+	// GroupStats, e.g. "{{.ActiveChildren}} hosts: {{.MostSevereSummary}}".
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
+}
+
+// GroupStats describes a parent alert and its children for the template of
+// the template strategy.
+type GroupStats struct {
+	// Summary is the summary of the event that opened the parent.
+	Summary  string
+	Class    string
+	Severity Severity
+
+	// ChildCount is the number of children; ActiveChildren those active.
+	ChildCount     int
+	ActiveChildren int
+
+	// MostSevere and MostSevereSummary describe the most severe alert of
+	// the group, the parent or an active child.
+	MostSevere        Severity
+	MostSevereSummary string
+
+	// LatestSummary is the summary of the alert of the group that
+	// triggered last.
+	LatestSummary string
+}
+
+// IsEnabled reports whether the policy replaces the summary of parents.
+func (p *ParentSummaryPolicy) IsEnabled() bool {
+	return p.Strategy != "" && p.Strategy != ParentSummaryFirst
+}
+
+// Validate checks the strategy is known and the template renders.
+func (p *ParentSummaryPolicy) Validate() error {
+	switch p.Strategy {
+	case "", ParentSummaryFirst, ParentSummaryLatest, ParentSummaryMostSevereChild:
+		return nil
+	case ParentSummaryTemplate:
+		if p.Template == "" {
+			return ErrParentSummaryTemplateMissing
+		}
+		tmpl, err := template.New("parent_summary").Option("missingkey=error").Parse(p.Template)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidParentSummaryTemplate, err)
+		}
+		if err := tmpl.Execute(&strings.Builder{}, &GroupStats{}); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidParentSummaryTemplate, err)
+		}
+		return nil
+	default:
+		return ErrInvalidParentSummaryStrategy
+	}
+}
+
+// Summary returns the summary the parent displays for its group with the
+// given children. A template that fails to render leaves the parent with
+// the summary of its own event.
+func (p *ParentSummaryPolicy) Summary(parent *Alert, children []*Alert) string {
+	stats := NewGroupStats(parent, children)
+	switch p.Strategy {
+	case ParentSummaryLatest:
+		return stats.LatestSummary
+	case ParentSummaryMostSevereChild:
+		return stats.MostSevereSummary
+	case ParentSummaryTemplate:
+		tmpl, err := template.New("parent_summary").Parse(p.Template)
+		if err != nil {
+			return stats.Summary
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, stats); err != nil {
+			return stats.Summary
+		}
+		return b.String()
+	default:
+		return stats.Summary
+	}
+}
+
+// NewGroupStats describes a parent alert and its children.
+func NewGroupStats(parent *Alert, children []*Alert) *GroupStats {
+	stats := &GroupStats{
+		Summary:           parent.EventSummaryOrSummary(),
+		Class:             parent.Class,
+		Severity:          parent.Severity,
+		ChildCount:        len(children),
+		MostSevere:        parent.Severity,
+		MostSevereSummary: parent.EventSummaryOrSummary(),
+		LatestSummary:     parent.EventSummaryOrSummary(),
+	}
+	mostSevereAt := parent.CurrentEpisode().StartedAt
+	latestAt := mostSevereAt
+
+	for _, child := range children {
+		if !child.IsActive() {
+			continue
+		}
+		stats.ActiveChildren++
+
+		startedAt := child.CurrentEpisode().StartedAt
+		if !startedAt.Before(latestAt) {
+			stats.LatestSummary = child.Summary
+			latestAt = startedAt
+		}
+		if child.Severity.MoreSevereThan(stats.MostSevere) ||
+			child.Severity == stats.MostSevere && !startedAt.Before(mostSevereAt) {
+			stats.MostSevere = child.Severity
+			stats.MostSevereSummary = child.Summary
+			mostSevereAt = startedAt
+		}
+	}
+	return stats
+}
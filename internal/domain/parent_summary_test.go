@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParentSummaryPolicy_Summary(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	parent := NewParentAlert(&Event{EventManagerID: "em-1", Summary: "Disk full on db-1", Severity: SeverityMedium, Class: "disk", DedupKey: "db-1"}, now)
+	child := func(dedupKey, summary string, severity Severity, after time.Duration) *Alert {
+		return NewChildAlert(&Event{EventManagerID: "em-1", Summary: summary, Severity: severity, Class: "disk", DedupKey: dedupKey}, "db-1", now.Add(after))
+	}
+	critical := child("db-2", "Disk full on db-2", SeverityHigh, time.Minute)
+	latest := child("db-3", "Disk full on db-3", SeverityMedium, 2*time.Minute)
+	resolved := child("db-4", "Disk full on db-4", SeverityHigh, 3*time.Minute)
+	resolved.Resolve(Resolution{}, now.Add(4*time.Minute))
+	children := []*Alert{critical, latest, resolved}
+
+	tests := []struct {
+		policy ParentSummaryPolicy
+		want   string
+	}{
+		{ParentSummaryPolicy{}, "Disk full on db-1"},
+		{ParentSummaryPolicy{Strategy: ParentSummaryFirst}, "Disk full on db-1"},
+		{ParentSummaryPolicy{Strategy: ParentSummaryLatest}, "Disk full on db-3"},
+		{ParentSummaryPolicy{Strategy: ParentSummaryMostSevereChild}, "Disk full on db-2"},
+		{
+			ParentSummaryPolicy{Strategy: ParentSummaryTemplate, Template: "{{.ActiveChildren}}/{{.ChildCount}} {{.Class}}: {{.MostSevereSummary}}"},
+			"2/3 disk: Disk full on db-2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy.Strategy), func(t *testing.T) {
+			if got := tt.policy.Summary(parent, children); got != tt.want {
+				t.Errorf("Summary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlert_SetGroupSummary(t *testing.T) {
+	alert := &Alert{Summary: "Disk full on db-1"}
+
+	if !alert.SetGroupSummary("3 hosts with full disks") {
+		t.Fatal("SetGroupSummary() = false, want true")
+	}
+	if alert.Summary != "3 hosts with full disks" || alert.EventSummary != "Disk full on db-1" {
+		t.Errorf("Summary, EventSummary = %q, %q", alert.Summary, alert.EventSummary)
+	}
+	if alert.SetGroupSummary("3 hosts with full disks") {
+		t.Error("SetGroupSummary() with the same summary = true, want false")
+	}
+
+	// Going back to the summary of the alert's own event clears EventSummary
+	alert.SetGroupSummary("Disk full on db-1")
+	if alert.Summary != "Disk full on db-1" || alert.EventSummary != "" {
+		t.Errorf("Summary, EventSummary = %q, %q", alert.Summary, alert.EventSummary)
+	}
+}
+
+func TestParentSummaryPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ParentSummaryPolicy
+		want   error
+	}{
+		{"default", ParentSummaryPolicy{}, nil},
+		{"latest", ParentSummaryPolicy{Strategy: ParentSummaryLatest}, nil},
+		{"template", ParentSummaryPolicy{Strategy: ParentSummaryTemplate, Template: "{{.ActiveChildren}} active"}, nil},
+		{"unknown strategy", ParentSummaryPolicy{Strategy: "longest"}, ErrInvalidParentSummaryStrategy},
+		{"missing template", ParentSummaryPolicy{Strategy: ParentSummaryTemplate}, ErrParentSummaryTemplateMissing},
+		{"unparsable template", ParentSummaryPolicy{Strategy: ParentSummaryTemplate, Template: "{{.ActiveChildren"}, ErrInvalidParentSummaryTemplate},
+		{"unknown field", ParentSummaryPolicy{Strategy: ParentSummaryTemplate, Template: "{{.Hosts}}"}, ErrInvalidParentSummaryTemplate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,654 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// Validation errors for QueryRule.
+var (
+	ErrQueryRuleNotFound      = notFoundError("rule not found")
+	ErrEmptyQueryRuleName     = invalidError("name is required")
+	ErrEmptyQueryRuleTarget   = invalidError("event_manager_id is required")
+	ErrEmptyQueryRuleSource   = invalidError("datasource is required")
+	ErrEmptyQueryRuleQuery    = invalidError("query is required")
+	ErrInvalidQueryOperator   = invalidError("condition.operator must be one of >, >=, <, <=, == or !=")
+	ErrInvalidQueryInterval   = invalidError("interval must not be negative")
+	ErrInvalidQueryDedupLabel = invalidError("dedup_labels must not hold empty or duplicate labels")
+	ErrInvalidMissingLabels   = invalidError("missing_labels must be one of omit, skip or placeholder")
+	ErrInvalidQuerySummary    = invalidError("summary is not a valid template")
+	ErrInvalidQueryParam      = invalidError("params must be named with letters, digits and underscores, and not " + QueryParamNow)
+	ErrCompositeQuery         = invalidError("composite rules take no datasource, query or params")
+	ErrEmptyCompositeRules    = invalidError("composite.rules is required")
+	ErrInvalidCompositeRule   = invalidError("composite.rules must map variables of letters, digits and underscores to rule IDs")
+	ErrInvalidCompositeWithin = invalidError("composite.within must not be negative")
+
+	ErrInvalidCompositeReference = invalidError("composite rules must reference rules running a query")
+	ErrInvalidMute               = invalidError("exactly one of until, in the future, and for, positive, is required")
+)
+
+// QueryParamNow is the parameter of rule queries bound to the evaluation
+// time. Rules can't set it.
+const QueryParamNow = "now"
+
+// QueryRuleClass is the class of the alerts of query rules without a class.
+const QueryRuleClass = "argus-rule"
+
+// queryParamName matches the names of query parameters and of the variables
+// of composite conditions.
+var queryParamName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// queryRuleDedupKeyPrefix starts the dedup keys of the alerts of query rules.
+const queryRuleDedupKeyPrefix = "rule/"
+
+// QueryOperator compares the value of a query result to the threshold of a
+// rule.
+type QueryOperator string
+
+// Operators of query conditions.
+const (
+	QueryAbove    QueryOperator = ">"
+	QueryAtLeast  QueryOperator = ">="
+	QueryBelow    QueryOperator = "<"
+	QueryAtMost   QueryOperator = "<="
+	QueryEqual    QueryOperator = "=="
+	QueryNotEqual QueryOperator = "!="
+)
+
+// IsValid returns true if the operator is a known value.
+func (o QueryOperator) IsValid() bool {
+	switch o {
+	case QueryAbove, QueryAtLeast, QueryBelow, QueryAtMost, QueryEqual, QueryNotEqual:
+		return true
+	}
+	return false
+}
+
+// MissingLabelPolicy is how a rule handles a series lacking some of its
+// dedup labels.
+type MissingLabelPolicy string
+
+// Policies for missing dedup labels.
+const (
+	// MissingLabelOmit leaves the missing labels out of the dedup key. It
+	// is the default.
+	MissingLabelOmit MissingLabelPolicy = "omit"
+
+	// MissingLabelSkip raises no alert for the series.
+	MissingLabelSkip MissingLabelPolicy = "skip"
+
+	// MissingLabelPlaceholder uses MissingLabelValue as the value of the
+	// missing labels.
+	MissingLabelPlaceholder MissingLabelPolicy = "placeholder"
+)
+
+// MissingLabelValue is the value of missing dedup labels under
+// MissingLabelPlaceholder.
+const MissingLabelValue = "<missing>"
+
+// IsValid returns true if the policy is a known value.
+func (p MissingLabelPolicy) IsValid() bool {
+	switch p {
+	case MissingLabelOmit, MissingLabelSkip, MissingLabelPlaceholder:
+		return true
+	}
+	return false
+}
+
+// QueryCondition is the threshold breach a rule alerts on.
+type QueryCondition struct {
+	// Operator compares the value of each result series to the threshold.
+	// It defaults to ">".
+	Operator QueryOperator `json:"operator,omitempty"`
+
+	// Threshold is the value compared against.
+	Threshold float64 `json:"threshold"`
+}
+
+// Validate checks the operator, if set, is known.
+func (c *QueryCondition) Validate() error {
+	if c.Operator != "" && !c.Operator.IsValid() {
+		return ErrInvalidQueryOperator
+	}
+	return nil
+}
+
+// Breached returns true if the value breaches the threshold.
+func (c *QueryCondition) Breached(value float64) bool {
+	switch c.operator() {
+	case QueryAtLeast:
+		return value >= c.Threshold
+	case QueryBelow:
+		return value < c.Threshold
+	case QueryAtMost:
+		return value <= c.Threshold
+	case QueryEqual:
+		return value == c.Threshold
+	case QueryNotEqual:
+		return value != c.Threshold
+	}
+	return value > c.Threshold
+}
+
+// String describes a breach of the condition by value, e.g. "153 > 100".
+func (c *QueryCondition) String(value float64) string {
+	return fmt.Sprintf("%s %s %s", formatQueryValue(value), c.operator(), formatQueryValue(c.Threshold))
+}
+
+// operator returns the operator of the condition, or the default.
+func (c *QueryCondition) operator() QueryOperator {
+	if c.Operator == "" {
+		return QueryAbove
+	}
+	return c.Operator
+}
+
+// formatQueryValue formats a value without trailing zeros.
+func formatQueryValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// CompositeCondition combines the outcomes of other rules with boolean
+// logic. Each referenced rule is a variable of the expression, true while
+// the rule has a series breaching its condition, or if it had one within the
+// last Within. For example, with A and B naming rules,
+//
+//	A and B       both breached, within Within of each other
+//	A and not B   A breached while B didn't
+type CompositeCondition struct {
+	// Expression is a boolean expression over the variables, in the expr
+	// language (https://expr-lang.org).
+	Expression string `json:"expression"`
+
+	// Rules maps the variables of the expression to the IDs of the rules
+	// they stand for. Composite rules can't be referenced.
+	Rules map[string]string `json:"rules"`
+
+	// Within is how long a breach of a referenced rule counts after it
+	// ended. Zero counts only the current breaches.
+	Within Duration `json:"within,omitempty"`
+}
+
+// Validate checks the variables and the rule IDs are set, and the
+// expression compiles to a boolean over the variables.
+func (c *CompositeCondition) Validate() error {
+	if len(c.Rules) == 0 {
+		return ErrEmptyCompositeRules
+	}
+	for variable, ruleID := range c.Rules {
+		if !queryParamName.MatchString(variable) || ruleID == "" {
+			return ErrInvalidCompositeRule
+		}
+	}
+	if c.Within < 0 {
+		return ErrInvalidCompositeWithin
+	}
+	_, err := c.compile()
+	return err
+}
+
+// Eval evaluates the expression given the outcome of each variable.
+func (c *CompositeCondition) Eval(outcomes map[string]bool) (bool, error) {
+	program, err := c.compile()
+	if err != nil {
+		return false, err
+	}
+	env := make(map[string]any, len(outcomes))
+	for variable, breached := range outcomes {
+		env[variable] = breached
+	}
+	result, err := expr.Run(program, env)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidExpression, err)
+	}
+	return result.(bool), nil
+}
+
+// compile returns the program of the expression, over the variables.
+func (c *CompositeCondition) compile() (*vm.Program, error) {
+	if c.Expression == "" {
+		return nil, fmt.Errorf("%w: composite.expression is required", ErrInvalidExpression)
+	}
+	if len(c.Expression) > maxExpressionLength {
+		return nil, fmt.Errorf("%w: longer than %d characters", ErrInvalidExpression, maxExpressionLength)
+	}
+
+	env := make(map[string]any, len(c.Rules))
+	for variable := range c.Rules {
+		env[variable] = false
+	}
+	program, err := expr.Compile(c.Expression, expr.Env(env), expr.AsBool())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExpression, err)
+	}
+	return program, nil
+}
+
+// QuerySeries is one series of the result of a rule query: a value and the
+// labels identifying it, e.g. the stream labels of a LogQL metric query.
+type QuerySeries struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	Value  float64           `json:"value"`
+}
+
+// QueryRule runs a query against a datasource on a schedule, and raises an
+// alert for each result series whose value breaches the condition. The
+// alert is resolved once its series no longer breaches it, or is gone from
+// the result. Series are told apart by their labels, which make up the dedup
+// keys of their alerts.
+//
+// A composite rule runs no query; it raises a single alert while its
+// Composite condition over other rules holds.
+type QueryRule struct {
+	// ID is the unique identifier for this rule.
+	ID string `json:"id"`
+
+	// Name is a human-readable name, used in the alert summaries.
+	Name string `json:"name"`
+
+	// EventManagerID receives the events of the rule.
+	EventManagerID string `json:"event_manager_id"`
+
+	// Datasource names the configured datasource the query runs against.
+	Datasource string `json:"datasource"`
+
+	// Query is run as is by the datasource, e.g. a LogQL query for Loki or
+	// a SQL query for ClickHouse.
+	Query string `json:"query"`
+
+	// Params are bound to the parameters of the query by name, by the
+	// datasources supporting parameters. QueryParamNow is always bound.
+	Params map[string]string `json:"params,omitempty"`
+
+	// Condition is the threshold breach alerted on.
+	Condition QueryCondition `json:"condition"`
+
+	// Composite makes the rule a composite rule. Its Datasource, Query and
+	// Params are empty, and its Condition unused.
+	Composite *CompositeCondition `json:"composite,omitempty"`
+
+	// DedupLabels are the labels of a series that identify its alert. Empty
+	// uses every label of the series.
+	DedupLabels []string `json:"dedup_labels,omitempty"`
+
+	// MissingLabels is how a series lacking some of the DedupLabels is
+	// handled. Empty uses MissingLabelOmit.
+	MissingLabels MissingLabelPolicy `json:"missing_labels,omitempty"`
+
+	// Summary of the alerts, a Go template rendered with the QueryAlertData
+	// of each breaching series, e.g.
+	// "cpu {{printf \"%.0f\" .Value}}% on {{.Labels.host}}". Empty, or a
+	// template failing or rendering nothing, describes the breach.
+	Summary string `json:"summary,omitempty"`
+
+	// Severity of the alerts. Empty uses the event manager's default.
+	Severity Severity `json:"severity,omitempty"`
+
+	// Class of the alerts. It defaults to QueryRuleClass.
+	Class string `json:"class,omitempty"`
+
+	// Interval is how often the rule runs. Zero uses the configured default.
+	Interval Duration `json:"interval,omitempty"`
+
+	// Disabled stops running the rule, and resolves its alerts.
+	Disabled bool `json:"disabled"`
+
+	// MutedUntil keeps the rule from raising new alerts until then. It
+	// still runs, and its alerts are still resolved.
+	MutedUntil *time.Time `json:"muted_until,omitempty"`
+
+	// TemplateID links the rule to the RuleTemplate it was instantiated
+	// from, which updates it. Empty for a rule created on its own.
+	TemplateID string `json:"template_id,omitempty"`
+
+	// TemplateValues are the values of the template variables the rule was
+	// rendered with.
+	TemplateValues map[string]string `json:"template_values,omitempty"`
+
+	// CreatedAt is when the rule was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is when the rule was last modified.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IntervalOr returns how often the rule runs, given the configured default.
+func (r *QueryRule) IntervalOr(defaultInterval time.Duration) time.Duration {
+	if r.Interval > 0 {
+		return time.Duration(r.Interval)
+	}
+	return defaultInterval
+}
+
+// IsMuted returns true if the rule is muted at now.
+func (r *QueryRule) IsMuted(now time.Time) bool {
+	return r.MutedUntil != nil && now.Before(*r.MutedUntil)
+}
+
+// DedupKeyPrefix returns the prefix of the dedup keys of the alerts of the
+// rule.
+func (r *QueryRule) DedupKeyPrefix() string {
+	return queryRuleDedupKeyPrefix + r.ID
+}
+
+// DedupKey returns the dedup key of the alert of a series: the rule ID and
+// the dedup labels of the series, e.g. "rule/<id>/service=api". Labels the
+// series lacks are left out, or valued MissingLabelValue, per MissingLabels.
+func (r *QueryRule) DedupKey(series *QuerySeries) string {
+	pairs := r.dedupPairs(series.Labels)
+	if len(pairs) == 0 {
+		return r.DedupKeyPrefix()
+	}
+	return r.DedupKeyPrefix() + "/" + strings.Join(pairs, ",")
+}
+
+// SkipsSeries returns true if the rule raises no alert for a series, as it
+// lacks some of the dedup labels and MissingLabels is MissingLabelSkip.
+func (r *QueryRule) SkipsSeries(series *QuerySeries) bool {
+	if r.MissingLabels != MissingLabelSkip {
+		return false
+	}
+	for _, name := range r.DedupLabels {
+		if _, ok := series.Labels[name]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// OwnsDedupKey returns true if the dedup key is that of an alert of the rule.
+func (r *QueryRule) OwnsDedupKey(dedupKey string) bool {
+	rest, ok := strings.CutPrefix(dedupKey, r.DedupKeyPrefix())
+	return ok && (rest == "" || strings.HasPrefix(rest, "/"))
+}
+
+// AlertSummary returns the summary of the alert of a series breaching the
+// condition.
+func (r *QueryRule) AlertSummary(series *QuerySeries) string {
+	if r.Summary != "" {
+		if summary, err := renderQuerySummary(r.Summary, r.alertData(series)); err == nil && summary != "" {
+			return summary
+		}
+	}
+	if r.Composite != nil {
+		return r.Name + ": " + r.Composite.Expression
+	}
+	summary := r.Name + ": " + r.Condition.String(series.Value)
+	if pairs := r.dedupPairs(series.Labels); len(pairs) > 0 {
+		summary += " {" + strings.Join(pairs, ", ") + "}"
+	}
+	return summary
+}
+
+// alertData returns the data the summary template is rendered with for a
+// series.
+func (r *QueryRule) alertData(series *QuerySeries) *QueryAlertData {
+	data := &QueryAlertData{
+		Name:   r.Name,
+		Value:  series.Value,
+		Labels: series.Labels,
+	}
+	if r.Composite == nil {
+		data.Threshold = r.Condition.Threshold
+		data.Operator = r.Condition.operator()
+		data.Breach = r.Condition.String(series.Value)
+	}
+	return data
+}
+
+// AlertClass returns the class of the alerts of the rule.
+func (r *QueryRule) AlertClass() string {
+	if r.Class != "" {
+		return r.Class
+	}
+	return QueryRuleClass
+}
+
+// dedupPairs returns the dedup labels of a series as name=value pairs, in
+// the order of DedupLabels, or sorted by name if the rule has none.
+func (r *QueryRule) dedupPairs(labels map[string]string) []string {
+	names := r.DedupLabels
+	if len(names) == 0 {
+		names = make([]string, 0, len(labels))
+		for name := range labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		if value, ok := labels[name]; ok {
+			pairs = append(pairs, name+"="+value)
+		} else if r.MissingLabels == MissingLabelPlaceholder {
+			pairs = append(pairs, name+"="+MissingLabelValue)
+		}
+	}
+	return pairs
+}
+
+// QueryAlertData is the data the summary template of a rule is rendered
+// with, for a series breaching its condition. Labels the series lacks
+// render empty.
+type QueryAlertData struct {
+	// Name is the name of the rule.
+	Name string
+
+	// Value is the value of the series, e.g. a count of log lines.
+	Value float64
+
+	// Threshold and Operator are those of the condition, unset for
+	// composite rules.
+	Threshold float64
+	Operator  QueryOperator
+
+	// Breach describes the breach, e.g. "153 > 100"; empty for composite
+	// rules.
+	Breach string
+
+	// Labels are the labels of the series, e.g. its host.
+	Labels map[string]string
+}
+
+// summaryTemplateCache holds the parsed summary templates by source, since
+// summaries are rendered for every breach.
+var summaryTemplateCache sync.Map // string -> *template.Template
+
+// compiledSummaryTemplate returns the parsed form of a summary template.
+func compiledSummaryTemplate(text string) (*template.Template, error) {
+	if tmpl, ok := summaryTemplateCache.Load(text); ok {
+		return tmpl.(*template.Template), nil
+	}
+	tmpl, err := template.New("summary").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	summaryTemplateCache.Store(text, tmpl)
+	return tmpl, nil
+}
+
+// renderQuerySummary renders a summary template with the data of a series.
+func renderQuerySummary(text string, data *QueryAlertData) (string, error) {
+	tmpl, err := compiledSummaryTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// CreateQueryRuleRequest represents the input for creating a new query rule.
+type CreateQueryRuleRequest struct {
+	Name           string              `json:"name"`
+	EventManagerID string              `json:"event_manager_id"`
+	Datasource     string              `json:"datasource"`
+	Query          string              `json:"query"`
+	Params         map[string]string   `json:"params"`
+	Condition      QueryCondition      `json:"condition"`
+	Composite      *CompositeCondition `json:"composite"`
+	DedupLabels    []string            `json:"dedup_labels"`
+	MissingLabels  MissingLabelPolicy  `json:"missing_labels"`
+	Summary        string              `json:"summary"`
+	Severity       Severity            `json:"severity"`
+	Class          string              `json:"class"`
+	Interval       Duration            `json:"interval"`
+	Disabled       bool                `json:"disabled"`
+	MutedUntil     *time.Time          `json:"muted_until"`
+}
+
+// Validate checks the create request has required fields.
+func (r *CreateQueryRuleRequest) Validate() error {
+	return validateQueryRule(r.ToQueryRule("", time.Time{}))
+}
+
+// ToQueryRule converts the request to a QueryRule created at now.
+func (r *CreateQueryRuleRequest) ToQueryRule(id string, now time.Time) *QueryRule {
+	return &QueryRule{
+		ID:             id,
+		Name:           r.Name,
+		EventManagerID: r.EventManagerID,
+		Datasource:     r.Datasource,
+		Query:          r.Query,
+		Params:         r.Params,
+		Condition:      r.Condition,
+		Composite:      r.Composite,
+		DedupLabels:    r.DedupLabels,
+		MissingLabels:  r.MissingLabels,
+		Summary:        r.Summary,
+		Severity:       r.Severity,
+		Class:          r.Class,
+		Interval:       r.Interval,
+		Disabled:       r.Disabled,
+		MutedUntil:     r.MutedUntil,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// UpdateQueryRuleRequest represents the input for updating a query rule.
+type UpdateQueryRuleRequest struct {
+	Name           string              `json:"name"`
+	EventManagerID string              `json:"event_manager_id"`
+	Datasource     string              `json:"datasource"`
+	Query          string              `json:"query"`
+	Params         map[string]string   `json:"params"`
+	Condition      QueryCondition      `json:"condition"`
+	Composite      *CompositeCondition `json:"composite"`
+	DedupLabels    []string            `json:"dedup_labels"`
+	MissingLabels  MissingLabelPolicy  `json:"missing_labels"`
+	Summary        string              `json:"summary"`
+	Severity       Severity            `json:"severity"`
+	Class          string              `json:"class"`
+	Interval       Duration            `json:"interval"`
+	Disabled       bool                `json:"disabled"`
+	MutedUntil     *time.Time          `json:"muted_until"`
+}
+
+// Validate checks the update request has required fields.
+func (r *UpdateQueryRuleRequest) Validate() error {
+	var rule QueryRule
+	r.ApplyTo(&rule, time.Time{})
+	return validateQueryRule(&rule)
+}
+
+// ApplyTo updates an existing QueryRule with the request values at now.
+func (r *UpdateQueryRuleRequest) ApplyTo(rule *QueryRule, now time.Time) {
+	rule.Name = r.Name
+	rule.EventManagerID = r.EventManagerID
+	rule.Datasource = r.Datasource
+	rule.Query = r.Query
+	rule.Params = r.Params
+	rule.Condition = r.Condition
+	rule.Composite = r.Composite
+	rule.DedupLabels = r.DedupLabels
+	rule.MissingLabels = r.MissingLabels
+	rule.Summary = r.Summary
+	rule.Severity = r.Severity
+	rule.Class = r.Class
+	rule.Interval = r.Interval
+	rule.Disabled = r.Disabled
+	rule.MutedUntil = r.MutedUntil
+	rule.UpdatedAt = now
+}
+
+// MuteQueryRuleRequest represents the input for muting a query rule, until
+// a time or for a duration.
+type MuteQueryRuleRequest struct {
+	Until *time.Time `json:"until"`
+	For   Duration   `json:"for"`
+}
+
+// MutedUntil validates the request and returns when the mute ends.
+func (r *MuteQueryRuleRequest) MutedUntil(now time.Time) (time.Time, error) {
+	switch {
+	case r.Until != nil && r.For == 0 && r.Until.After(now):
+		return r.Until.UTC(), nil
+	case r.Until == nil && r.For > 0:
+		return now.Add(time.Duration(r.For)), nil
+	}
+	return time.Time{}, ErrInvalidMute
+}
+
+// validateQueryRule checks the fields set by the create and update
+// requests. Whether the datasource is configured, and the rules a composite
+// rule references exist, is checked by the caller.
+func validateQueryRule(rule *QueryRule) error {
+	switch {
+	case rule.Name == "":
+		return ErrEmptyQueryRuleName
+	case rule.EventManagerID == "":
+		return ErrEmptyQueryRuleTarget
+	case rule.Severity != "" && !rule.Severity.IsValid():
+		return ErrInvalidSeverity
+	case rule.Interval < 0:
+		return ErrInvalidQueryInterval
+	case rule.MissingLabels != "" && !rule.MissingLabels.IsValid():
+		return ErrInvalidMissingLabels
+	}
+
+	if rule.Summary != "" {
+		if _, err := renderQuerySummary(rule.Summary, &QueryAlertData{}); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidQuerySummary, err)
+		}
+	}
+
+	seen := make(map[string]bool, len(rule.DedupLabels))
+	for _, label := range rule.DedupLabels {
+		if label == "" || seen[label] {
+			return ErrInvalidQueryDedupLabel
+		}
+		seen[label] = true
+	}
+
+	if rule.Composite != nil {
+		if rule.Datasource != "" || rule.Query != "" || len(rule.Params) > 0 {
+			return ErrCompositeQuery
+		}
+		return rule.Composite.Validate()
+	}
+
+	switch {
+	case rule.Datasource == "":
+		return ErrEmptyQueryRuleSource
+	case rule.Query == "":
+		return ErrEmptyQueryRuleQuery
+	}
+	for param := range rule.Params {
+		if param == QueryParamNow || !queryParamName.MatchString(param) {
+			return ErrInvalidQueryParam
+		}
+	}
+	return rule.Condition.Validate()
+}
@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueryRule_DedupKey(t *testing.T) {
+	series := &QuerySeries{Labels: map[string]string{"service": "api", "env": "prod"}, Value: 153}
+
+	rule := &QueryRule{ID: "r1", Name: "Errors", Condition: QueryCondition{Threshold: 100}}
+	if got := rule.DedupKey(series); got != "rule/r1/env=prod,service=api" {
+		t.Errorf("DedupKey() = %s, want every label sorted", got)
+	}
+	if got := rule.AlertSummary(series); got != "Errors: 153 > 100 {env=prod, service=api}" {
+		t.Errorf("AlertSummary() = %s", got)
+	}
+
+	rule.DedupLabels = []string{"service", "region"}
+	if got := rule.DedupKey(series); got != "rule/r1/service=api" {
+		t.Errorf("DedupKey() with dedup labels = %s, want rule/r1/service=api", got)
+	}
+	if !rule.OwnsDedupKey("rule/r1/service=api") || !rule.OwnsDedupKey("rule/r1") || rule.OwnsDedupKey("rule/r10/service=api") {
+		t.Error("OwnsDedupKey() must match the keys of the rule only")
+	}
+}
+
+func TestQueryRule_MissingLabels(t *testing.T) {
+	series := &QuerySeries{Labels: map[string]string{"service": "api"}, Value: 1}
+	rule := &QueryRule{ID: "r1", DedupLabels: []string{"service", "kubernetes.pod.name"}}
+
+	if got := rule.DedupKey(series); got != "rule/r1/service=api" || rule.SkipsSeries(series) {
+		t.Errorf("omit: DedupKey() = %s, skipped = %v", got, rule.SkipsSeries(series))
+	}
+
+	rule.MissingLabels = MissingLabelPlaceholder
+	if got := rule.DedupKey(series); got != "rule/r1/service=api,kubernetes.pod.name=<missing>" || rule.SkipsSeries(series) {
+		t.Errorf("placeholder: DedupKey() = %s, skipped = %v", got, rule.SkipsSeries(series))
+	}
+
+	rule.MissingLabels = MissingLabelSkip
+	if !rule.SkipsSeries(series) {
+		t.Error("skip: SkipsSeries() = false, want true for a series lacking a dedup label")
+	}
+	series.Labels["kubernetes.pod.name"] = "api-1"
+	if rule.SkipsSeries(series) {
+		t.Error("skip: SkipsSeries() = true, want false for a series with every dedup label")
+	}
+}
+
+func TestQueryRule_AlertSummaryTemplate(t *testing.T) {
+	series := &QuerySeries{Labels: map[string]string{"host": "host-1"}, Value: 97.2}
+	rule := &QueryRule{ID: "r1", Name: "CPU", Condition: QueryCondition{Threshold: 90}}
+
+	for _, tt := range []struct {
+		summary string
+		want    string
+	}{
+		{summary: `cpu {{printf "%.0f" .Value}}% on {{.Labels.host}}`, want: "cpu 97% on host-1"},
+		{summary: `{{.Name}}: {{.Breach}}{{with .Labels.region}} in {{.}}{{end}}`, want: "CPU: 97.2 > 90"},
+		{summary: "static", want: "static"},
+		// Rendering nothing falls back to describing the breach
+		{summary: "{{.Labels.region}}", want: "CPU: 97.2 > 90 {host=host-1}"},
+	} {
+		rule.Summary = tt.summary
+		if got := rule.AlertSummary(series); got != tt.want {
+			t.Errorf("AlertSummary() with %q = %q, want %q", tt.summary, got, tt.want)
+		}
+	}
+}
+
+func TestCreateQueryRuleRequest_Validate(t *testing.T) {
+	valid := func() CreateQueryRuleRequest {
+		return CreateQueryRuleRequest{Name: "Errors", EventManagerID: "em-1", Datasource: "logs", Query: "q"}
+	}
+	composite := func(expression string) CreateQueryRuleRequest {
+		return CreateQueryRuleRequest{
+			Name:           "Checkout down",
+			EventManagerID: "em-1",
+			Composite: &CompositeCondition{
+				Expression: expression,
+				Rules:      map[string]string{"A": "r1", "B": "r2"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		req     CreateQueryRuleRequest
+		wantErr error
+	}{
+		{name: "query", req: valid()},
+		{name: "no query", req: func() CreateQueryRuleRequest { r := valid(); r.Query = ""; return r }(), wantErr: ErrEmptyQueryRuleQuery},
+		{name: "reserved param", req: func() CreateQueryRuleRequest {
+			r := valid()
+			r.Params = map[string]string{QueryParamNow: "1"}
+			return r
+		}(), wantErr: ErrInvalidQueryParam},
+		{name: "unknown missing labels policy", req: func() CreateQueryRuleRequest {
+			r := valid()
+			r.MissingLabels = "drop"
+			return r
+		}(), wantErr: ErrInvalidMissingLabels},
+		{name: "summary template", req: func() CreateQueryRuleRequest {
+			r := valid()
+			r.Summary = "{{.Value}} on {{.Labels.host}}"
+			return r
+		}()},
+		{name: "invalid summary template", req: func() CreateQueryRuleRequest {
+			r := valid()
+			r.Summary = "{{.Value"
+			return r
+		}(), wantErr: ErrInvalidQuerySummary},
+		{name: "composite", req: composite("A and not B")},
+		{name: "composite with a query", req: func() CreateQueryRuleRequest { r := composite("A"); r.Query = "q"; return r }(), wantErr: ErrCompositeQuery},
+		{name: "unknown variable", req: composite("A and C"), wantErr: ErrInvalidExpression},
+		{name: "not boolean", req: composite("1 + 1"), wantErr: ErrInvalidExpression},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompositeCondition_Eval(t *testing.T) {
+	condition := &CompositeCondition{Expression: "A and not B", Rules: map[string]string{"A": "r1", "B": "r2"}}
+
+	for _, tt := range []struct {
+		a, b, want bool
+	}{
+		{a: true, b: false, want: true},
+		{a: true, b: true, want: false},
+		{a: false, b: false, want: false},
+	} {
+		got, err := condition.Eval(map[string]bool{"A": tt.a, "B": tt.b})
+		if err != nil {
+			t.Fatalf("Eval() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Eval(A=%v, B=%v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMuteQueryRuleRequest_MutedUntil(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		req     MuteQueryRuleRequest
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "until", req: MuteQueryRuleRequest{Until: &later}, want: later},
+		{name: "for", req: MuteQueryRuleRequest{For: Duration(30 * time.Minute)}, want: now.Add(30 * time.Minute)},
+		{name: "until in the past", req: MuteQueryRuleRequest{Until: &earlier}, wantErr: true},
+		{name: "both", req: MuteQueryRuleRequest{Until: &later, For: Duration(time.Minute)}, wantErr: true},
+		{name: "neither", req: MuteQueryRuleRequest{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.req.MutedUntil(now)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidMute) {
+					t.Errorf("MutedUntil() error = %v, want ErrInvalidMute", err)
+				}
+				return
+			}
+			if err != nil || !got.Equal(tt.want) {
+				t.Errorf("MutedUntil() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// QuotaEnforcement decides what happens to events of an event manager that
+// exhausted a quota.
+type QuotaEnforcement string
+
+const (
+	// QuotaReject rejects trigger events until the quota frees up. It is
+	// the default.
+	QuotaReject QuotaEnforcement = "reject"
+
+	// QuotaDegrade accepts trigger events, but suppresses the notifications
+	// of the event manager until the quota frees up.
+	QuotaDegrade QuotaEnforcement = "degrade"
+)
+
+// DefaultQuotaWarnPercent is the share of a quota, in percent, from which it
+// is reported as near exhaustion.
+const DefaultQuotaWarnPercent = 80
+
+// Names of the quotas, as reported in quota statuses.
+const (
+	QuotaEventsPerDay   = "events_per_day"
+	QuotaEventsPerMonth = "events_per_month"
+	QuotaOpenAlerts     = "open_alerts"
+)
+
+// Quota errors.
+var (
+	ErrInvalidQuota  = invalidError("invalid quota")
+	ErrQuotaExceeded = errors.New("quota exceeded")
+)
+
+// Quota limits what an event manager may use. Day and month are UTC. A zero
+// limit is unlimited; a quota without limits is disabled. Resolve events are
+// never limited, so alerts can always be closed.
+type Quota struct {
+	MaxEventsPerDay   int64 `json:"max_events_per_day,omitempty" yaml:"max_events_per_day,omitempty"`
+	MaxEventsPerMonth int64 `json:"max_events_per_month,omitempty" yaml:"max_events_per_month,omitempty"`
+	MaxOpenAlerts     int64 `json:"max_open_alerts,omitempty" yaml:"max_open_alerts,omitempty"`
+
+	// Enforcement applies once a limit is reached. Empty means QuotaReject.
+	Enforcement QuotaEnforcement `json:"enforcement,omitempty" yaml:"enforcement,omitempty"`
+
+	// WarnPercent is the share of a limit, in percent, from which the quota
+	// is near exhaustion and raises a self-monitoring alert. Zero means
+	// DefaultQuotaWarnPercent.
+	WarnPercent int `json:"warn_percent,omitempty" yaml:"warn_percent,omitempty"`
+}
+
+// IsEnabled returns true if the quota has any limit.
+func (q *Quota) IsEnabled() bool {
+	return q.MaxEventsPerDay > 0 || q.MaxEventsPerMonth > 0 || q.MaxOpenAlerts > 0
+}
+
+// Validate checks the limits and enforcement of the quota.
+func (q *Quota) Validate() error {
+	if q.MaxEventsPerDay < 0 || q.MaxEventsPerMonth < 0 || q.MaxOpenAlerts < 0 {
+		return fmt.Errorf("%w: limits must not be negative", ErrInvalidQuota)
+	}
+	switch q.Enforcement {
+	case "", QuotaReject, QuotaDegrade:
+	default:
+		return fmt.Errorf("%w: enforcement must be 'reject' or 'degrade'", ErrInvalidQuota)
+	}
+	if q.WarnPercent < 0 || q.WarnPercent > 100 {
+		return fmt.Errorf("%w: warn_percent must be between 0 and 100", ErrInvalidQuota)
+	}
+	return nil
+}
+
+// Evaluate returns the status of the quota for what an event manager used.
+func (q *Quota) Evaluate(eventsToday, eventsThisMonth, openAlerts int64, at time.Time) *QuotaStatus {
+	status := &QuotaStatus{
+		Quota:           *q,
+		EventsToday:     eventsToday,
+		EventsThisMonth: eventsThisMonth,
+		OpenAlerts:      openAlerts,
+		CheckedAt:       at,
+	}
+	if status.Enforcement == "" {
+		status.Enforcement = QuotaReject
+	}
+	warnPercent := q.WarnPercent
+	if warnPercent == 0 {
+		warnPercent = DefaultQuotaWarnPercent
+	}
+
+	check := func(name string, used, limit int64) {
+		switch {
+		case limit <= 0:
+		case used >= limit:
+			status.Exceeded = append(status.Exceeded, name)
+		case used*100 >= limit*int64(warnPercent):
+			status.NearExhaustion = append(status.NearExhaustion, name)
+		}
+	}
+	check(QuotaEventsPerDay, eventsToday, q.MaxEventsPerDay)
+	check(QuotaEventsPerMonth, eventsThisMonth, q.MaxEventsPerMonth)
+	check(QuotaOpenAlerts, openAlerts, q.MaxOpenAlerts)
+	return status
+}
+
+// QuotaStatus is what an event manager used of its quota when last checked.
+type QuotaStatus struct {
+	Quota
+
+	EventsToday     int64 `json:"events_today"`
+	EventsThisMonth int64 `json:"events_this_month"`
+	OpenAlerts      int64 `json:"open_alerts"`
+
+	// Exceeded lists the limits reached, NearExhaustion those past the warn
+	// percent but not reached.
+	Exceeded       []string `json:"exceeded,omitempty"`
+	NearExhaustion []string `json:"near_exhaustion,omitempty"`
+
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// IsExceeded returns true if any limit is reached.
+func (s *QuotaStatus) IsExceeded() bool {
+	return len(s.Exceeded) > 0
+}
+
+// Rejects returns true if an event with the action is rejected: only trigger
+// events are, and only while a limit is reached under QuotaReject.
+func (s *QuotaStatus) Rejects(action Action) bool {
+	return action == ActionTrigger && s.IsExceeded() && s.Enforcement == QuotaReject
+}
+
+// Suppresses returns true if notifications are suppressed, while a limit is
+// reached under QuotaDegrade.
+func (s *QuotaStatus) Suppresses() bool {
+	return s.IsExceeded() && s.Enforcement == QuotaDegrade
+}
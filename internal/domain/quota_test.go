@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestQuota_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		quota   Quota
+		wantErr bool
+	}{
+		{name: "disabled", quota: Quota{}},
+		{name: "limits", quota: Quota{MaxEventsPerDay: 100, MaxOpenAlerts: 10, Enforcement: QuotaDegrade, WarnPercent: 90}},
+		{name: "negative limit", quota: Quota{MaxEventsPerMonth: -1}, wantErr: true},
+		{name: "unknown enforcement", quota: Quota{MaxEventsPerDay: 100, Enforcement: "drop"}, wantErr: true},
+		{name: "warn percent over 100", quota: Quota{MaxEventsPerDay: 100, WarnPercent: 120}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.quota.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidQuota) {
+				t.Errorf("Validate() error = %v, want %v", err, ErrInvalidQuota)
+			}
+		})
+	}
+}
+
+func TestQuota_Evaluate(t *testing.T) {
+	quota := Quota{MaxEventsPerDay: 100, MaxEventsPerMonth: 1000, MaxOpenAlerts: 10}
+	at := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                   string
+		today, month, open     int64
+		wantExceeded, wantNear []string
+		wantRejects            bool
+	}{
+		{name: "within limits", today: 10, month: 100, open: 1},
+		{name: "near exhaustion", today: 80, month: 100, open: 9, wantNear: []string{QuotaEventsPerDay, QuotaOpenAlerts}},
+		{name: "exceeded", today: 100, month: 850, open: 1, wantExceeded: []string{QuotaEventsPerDay}, wantNear: []string{QuotaEventsPerMonth}, wantRejects: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := quota.Evaluate(tt.today, tt.month, tt.open, at)
+			if !slices.Equal(status.Exceeded, tt.wantExceeded) || !slices.Equal(status.NearExhaustion, tt.wantNear) {
+				t.Errorf("exceeded, near = %v, %v, want %v, %v", status.Exceeded, status.NearExhaustion, tt.wantExceeded, tt.wantNear)
+			}
+			if status.Enforcement != QuotaReject || !status.CheckedAt.Equal(at) {
+				t.Errorf("status = %+v, want reject enforcement checked at %s", status, at)
+			}
+			if got := status.Rejects(ActionTrigger); got != tt.wantRejects {
+				t.Errorf("Rejects(trigger) = %v, want %v", got, tt.wantRejects)
+			}
+			if status.Rejects(ActionResolve) || status.Suppresses() {
+				t.Error("a reject quota should neither reject resolves nor suppress notifications")
+			}
+		})
+	}
+
+	degrade := Quota{MaxOpenAlerts: 10, Enforcement: QuotaDegrade, WarnPercent: 50}
+	status := degrade.Evaluate(0, 0, 5, at)
+	if !slices.Equal(status.NearExhaustion, []string{QuotaOpenAlerts}) {
+		t.Errorf("near exhaustion = %v, want open_alerts from the warn percent", status.NearExhaustion)
+	}
+	status = degrade.Evaluate(0, 0, 10, at)
+	if status.Rejects(ActionTrigger) || !status.Suppresses() {
+		t.Error("an exceeded degrade quota should suppress notifications instead of rejecting")
+	}
+}
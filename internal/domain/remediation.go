@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Validation errors for remediation actions.
+var (
+	ErrEmptyRemediationName        = invalidError("remediations[].name is required")
+	ErrDuplicateRemediationName    = invalidError("remediations[].name must be unique")
+	ErrInvalidRemediationTarget    = invalidError("remediations[] must set exactly one of url and queue")
+	ErrInvalidRemediationURL       = invalidError("remediations[].url must be an absolute http or https URL")
+	ErrInvalidRemediationCommand   = invalidError("remediations[].command is required with a queue")
+	ErrNegativeRemediationCooldown = invalidError("remediations[].cooldown must not be negative")
+)
+
+// RemediationAction runs automatically when an alert of its event manager
+// triggers: it calls a URL or publishes a command to a notification queue,
+// e.g. to restart a service or scale a pool before a responder steps in.
+type RemediationAction struct {
+	// Name identifies the action in the remediation log.
+	Name string `json:"name" yaml:"name"`
+
+	// Match guards the action: it runs only for alerts triggered by events
+	// the matcher selects. An empty matcher runs it for every alert.
+	Match EventMatcher `json:"match" yaml:"match,omitempty"`
+
+	// URL receives the remediation request as a JSON POST. A 2xx response
+	// is a success.
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// Queue names a notification queue of the deployment the remediation
+	// request is published to, for a worker to carry out Command.
+	Queue string `json:"queue,omitempty" yaml:"queue,omitempty"`
+
+	// Command is passed to the target in the remediation request. Required
+	// with Queue.
+	Command string `json:"command,omitempty" yaml:"command,omitempty"`
+
+	// Cooldown is the least time between two runs of the action for the
+	// same alert. Triggers within it are recorded as skipped. Zero runs the
+	// action on every trigger.
+	Cooldown Duration `json:"cooldown,omitempty" yaml:"cooldown,omitempty"`
+}
+
+// Validate checks the action has a name and exactly one valid target.
+func (a *RemediationAction) Validate() error {
+	if a.Name == "" {
+		return ErrEmptyRemediationName
+	}
+	if (a.URL == "") == (a.Queue == "") {
+		return ErrInvalidRemediationTarget
+	}
+	if a.URL != "" {
+		u, err := url.Parse(a.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ErrInvalidRemediationURL
+		}
+	}
+	if a.Queue != "" && a.Command == "" {
+		return ErrInvalidRemediationCommand
+	}
+	if a.Cooldown < 0 {
+		return ErrNegativeRemediationCooldown
+	}
+	for _, severity := range a.Match.Severities {
+		if !severity.IsValid() {
+			return ErrInvalidSeverity
+		}
+	}
+	return a.Match.Expression.Validate()
+}
+
+// Target returns where the action sends its request: the URL, or the queue
+// as "queue:<name>".
+func (a *RemediationAction) Target() string {
+	if a.URL != "" {
+		return a.URL
+	}
+	return "queue:" + a.Queue
+}
+
+// validateRemediations checks every action and that names are unique.
+func validateRemediations(actions []RemediationAction) error {
+	names := make(map[string]bool, len(actions))
+	for i := range actions {
+		if err := actions[i].Validate(); err != nil {
+			return err
+		}
+		if names[actions[i].Name] {
+			return fmt.Errorf("%w: %s", ErrDuplicateRemediationName, actions[i].Name)
+		}
+		names[actions[i].Name] = true
+	}
+	return nil
+}
+
+// RemediationStatus is the outcome of a remediation action.
+type RemediationStatus string
+
+const (
+	// RemediationSucceeded means the target accepted the request.
+	RemediationSucceeded RemediationStatus = "succeeded"
+	// RemediationFailed means the request could not be delivered or the
+	// target rejected it.
+	RemediationFailed RemediationStatus = "failed"
+	// RemediationSkipped means the action was still cooling down.
+	RemediationSkipped RemediationStatus = "skipped"
+)
+
+// RemediationRecord is an entry of the remediation log: one run of a
+// remediation action for an alert.
+type RemediationRecord struct {
+	// DedupKey identifies the alert the action ran for.
+	DedupKey string `json:"dedupKey"`
+
+	// EventManagerID is the event manager whose action ran.
+	EventManagerID string `json:"event_manager_id"`
+
+	// Action is the name of the action.
+	Action string `json:"action"`
+
+	// Target is where the request was sent.
+	Target string `json:"target"`
+
+	// Status is the outcome.
+	Status RemediationStatus `json:"status"`
+
+	// Detail describes the outcome, e.g. the HTTP status or the error.
+	Detail string `json:"detail,omitempty"`
+
+	// ExecutedAt is when the action ran.
+	ExecutedAt time.Time `json:"executed_at"`
+}
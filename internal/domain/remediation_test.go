@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateRemediations(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []RemediationAction
+		wantErr error
+	}{
+		{name: "none"},
+		{
+			name: "url and queue actions",
+			actions: []RemediationAction{
+				{Name: "restart", URL: "https://runner.example.com/restart", Cooldown: Duration(10 * time.Minute)},
+				{Name: "scale", Queue: "ops", Command: "scale-up", Match: EventMatcher{Classes: []string{"capacity"}}},
+			},
+		},
+		{
+			name:    "missing name",
+			actions: []RemediationAction{{URL: "https://runner.example.com"}},
+			wantErr: ErrEmptyRemediationName,
+		},
+		{
+			name: "duplicate name",
+			actions: []RemediationAction{
+				{Name: "restart", URL: "https://runner.example.com/a"},
+				{Name: "restart", URL: "https://runner.example.com/b"},
+			},
+			wantErr: ErrDuplicateRemediationName,
+		},
+		{
+			name:    "no target",
+			actions: []RemediationAction{{Name: "restart"}},
+			wantErr: ErrInvalidRemediationTarget,
+		},
+		{
+			name:    "both targets",
+			actions: []RemediationAction{{Name: "restart", URL: "https://runner.example.com", Queue: "ops", Command: "restart"}},
+			wantErr: ErrInvalidRemediationTarget,
+		},
+		{
+			name:    "relative url",
+			actions: []RemediationAction{{Name: "restart", URL: "/restart"}},
+			wantErr: ErrInvalidRemediationURL,
+		},
+		{
+			name:    "queue without command",
+			actions: []RemediationAction{{Name: "restart", Queue: "ops"}},
+			wantErr: ErrInvalidRemediationCommand,
+		},
+		{
+			name:    "negative cooldown",
+			actions: []RemediationAction{{Name: "restart", URL: "https://runner.example.com", Cooldown: Duration(-time.Second)}},
+			wantErr: ErrNegativeRemediationCooldown,
+		},
+		{
+			name:    "invalid severity",
+			actions: []RemediationAction{{Name: "restart", URL: "https://runner.example.com", Match: EventMatcher{Severities: []Severity{"urgent"}}}},
+			wantErr: ErrInvalidSeverity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateRemediations(tt.actions); !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateRemediations() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
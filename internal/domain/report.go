@@ -0,0 +1,112 @@
+package domain
+
+import "time"
+
+// DefaultReportRange is the time range covered by a report when none is given.
+const DefaultReportRange = 7 * 24 * time.Hour
+
+// DefaultReportTopLimit is the default number of noisy dedup keys returned per event manager.
+const DefaultReportTopLimit = 10
+
+// ErrInvalidReportRange is returned when the report range end is before its start.
+var ErrInvalidReportRange = invalidError("report 'to' must be after 'from'")
+
+// ReportFilter selects the alerts included in a report.
+// Alerts are included when their CreatedAt falls within [From, To).
+type ReportFilter struct {
+	EventManagerID string
+	From           time.Time
+	To             time.Time
+
+	// TopLimit caps the number of noisy dedup keys returned per event manager.
+	TopLimit int
+}
+
+// Validate checks that the report range is well formed.
+func (f *ReportFilter) Validate() error {
+	if !f.To.After(f.From) {
+		return ErrInvalidReportRange
+	}
+	return nil
+}
+
+// MTTRStats holds acknowledgement and resolution timings for one event manager.
+type MTTRStats struct {
+	EventManagerID string `json:"event_manager_id"`
+
+	// AlertCount is the number of alerts created in the range.
+	AlertCount int `json:"alert_count"`
+
+	// AcknowledgedCount is how many of those alerts were acknowledged.
+	AcknowledgedCount int `json:"acknowledged_count"`
+
+	// ResolvedCount is how many of those alerts were resolved.
+	ResolvedCount int `json:"resolved_count"`
+
+	// MTTASeconds is the mean time from creation to acknowledgement.
+	MTTASeconds float64 `json:"mtta_seconds"`
+
+	// MTTRSeconds is the mean time from creation to resolution.
+	MTTRSeconds float64 `json:"mttr_seconds"`
+}
+
+// MTTRReport is the response for the MTTR/MTTA report.
+type MTTRReport struct {
+	From          time.Time    `json:"from"`
+	To            time.Time    `json:"to"`
+	EventManagers []*MTTRStats `json:"event_managers"`
+}
+
+// DailyVolume is the number of alerts created on a single UTC day.
+type DailyVolume struct {
+	EventManagerID string `json:"event_manager_id"`
+	Day            string `json:"day"` // YYYY-MM-DD
+	Count          int    `json:"count"`
+}
+
+// DedupKeyVolume describes how often a single dedup key has triggered.
+type DedupKeyVolume struct {
+	EventManagerID string `json:"event_manager_id"`
+	DedupKey       string `json:"dedupKey"`
+	Summary        string `json:"summary"`
+	TriggerCount   int    `json:"trigger_count"`
+}
+
+// VolumeReport is the response for the alert volume report.
+type VolumeReport struct {
+	From         time.Time         `json:"from"`
+	To           time.Time         `json:"to"`
+	ByDay        []*DailyVolume    `json:"by_day"`
+	TopDedupKeys []*DedupKeyVolume `json:"top_dedup_keys"`
+}
+
+// SourceStats aggregates the alerts of one event manager by the origin of
+// the events that triggered them.
+type SourceStats struct {
+	EventManagerID string `json:"event_manager_id"`
+	Source         string `json:"source"`
+	IP             string `json:"ip"`
+	UserAgent      string `json:"user_agent"`
+	Via            string `json:"via"`
+
+	// AlertCount is the number of alerts created in the range.
+	AlertCount int `json:"alert_count"`
+
+	// ActiveCount is how many of those alerts are still active.
+	ActiveCount int `json:"active_count"`
+
+	// TriggerCount is the sum of the trigger counts of those alerts.
+	TriggerCount int `json:"trigger_count"`
+}
+
+// SourceReport is the response for the alert source report.
+type SourceReport struct {
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	Sources []*SourceStats `json:"sources"`
+}
+
+// ReportDay formats a timestamp as the UTC day used in volume reports.
+func ReportDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
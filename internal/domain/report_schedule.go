@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"net/mail"
+	"time"
+)
+
+// MaxReportRecipients bounds the recipients of a report schedule.
+const MaxReportRecipients = 50
+
+// Validation errors for ReportSchedule.
+var (
+	ErrReportScheduleNotFound  = notFoundError("report schedule not found")
+	ErrEmptyReportScheduleName = invalidError("name is required")
+	ErrInvalidReportFrequency  = invalidError("frequency must be daily or weekly")
+	ErrInvalidReportFormat     = invalidError("format must be csv or html")
+	ErrEmptyReportRecipients   = invalidError("recipients is required")
+	ErrTooManyReportRecipients = invalidError("recipients lists too many addresses")
+	ErrInvalidReportRecipient  = invalidError("recipients must be email addresses")
+	ErrInvalidReportTopLimit   = invalidError("top must not be negative")
+	ErrUnavailableReportEmail  = invalidError("no plugin sends report email")
+)
+
+// ReportFrequency is how often a scheduled report is sent.
+type ReportFrequency string
+
+const (
+	// ReportDaily reports on the previous UTC day, every day.
+	ReportDaily ReportFrequency = "daily"
+	// ReportWeekly reports on the previous week, from Monday to Sunday UTC,
+	// every Monday.
+	ReportWeekly ReportFrequency = "weekly"
+)
+
+// ReportFormat is how a scheduled report is attached to its email.
+type ReportFormat string
+
+const (
+	// ReportCSV attaches the report as CSV files to a plain text email.
+	ReportCSV ReportFormat = "csv"
+	// ReportHTML sends the report as the HTML body of the email.
+	ReportHTML ReportFormat = "html"
+)
+
+// ReportSchedule emails the alert volume, MTTR and top offenders of event
+// managers to its recipients every day or week.
+type ReportSchedule struct {
+	// ID is the unique identifier for this schedule.
+	ID string `json:"id"`
+
+	// Name is a human-readable name, used in the subject of the email.
+	Name string `json:"name"`
+
+	// EventManagerID limits the report to one event manager. Empty reports
+	// on every event manager.
+	EventManagerID string `json:"event_manager_id,omitempty"`
+
+	// Frequency is how often the report is sent.
+	Frequency ReportFrequency `json:"frequency"`
+
+	// Format is how the report is attached to the email.
+	Format ReportFormat `json:"format"`
+
+	// Recipients are the email addresses the report is sent to.
+	Recipients []string `json:"recipients"`
+
+	// TopLimit caps the top offenders listed per event manager. Zero uses
+	// DefaultReportTopLimit.
+	TopLimit int `json:"top"`
+
+	// LastReportTo is the end of the period of the last report sent.
+	LastReportTo *time.Time `json:"last_report_to,omitempty"`
+
+	// CreatedAt is when the schedule was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is when the schedule was last changed.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Period returns the latest complete period of the schedule at now: the
+// previous UTC day for daily reports, the previous week from Monday for
+// weekly ones.
+func (s *ReportSchedule) Period(now time.Time) (from, to time.Time) {
+	now = now.UTC()
+	to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if s.Frequency == ReportWeekly {
+		// Days since Monday
+		to = to.AddDate(0, 0, -(int(to.Weekday())+6)%7)
+		return to.AddDate(0, 0, -7), to
+	}
+	return to.AddDate(0, 0, -1), to
+}
+
+// Due returns true if the report of the latest complete period at now is
+// yet to be sent. Periods ending before the schedule was created are not
+// reported.
+func (s *ReportSchedule) Due(now time.Time) bool {
+	_, to := s.Period(now)
+	return s.CreatedAt.Before(to) && (s.LastReportTo == nil || s.LastReportTo.Before(to))
+}
+
+// Filter returns the report filter of a period of the schedule.
+func (s *ReportSchedule) Filter(from, to time.Time) ReportFilter {
+	top := s.TopLimit
+	if top == 0 {
+		top = DefaultReportTopLimit
+	}
+	return ReportFilter{
+		EventManagerID: s.EventManagerID,
+		From:           from,
+		To:             to,
+		TopLimit:       top,
+	}
+}
+
+// ScheduledReport is the content of a report sent for a schedule.
+type ScheduledReport struct {
+	Schedule *ReportSchedule
+	From     time.Time
+	To       time.Time
+
+	// EventManagers holds the alert volume and MTTR of each event manager.
+	EventManagers []*MTTRStats
+
+	// TopOffenders lists the most triggered dedup keys of each event manager.
+	TopOffenders []*DedupKeyVolume
+}
+
+// ReportScheduleRequest is the request body for creating or replacing a
+// report schedule.
+type ReportScheduleRequest struct {
+	Name           string          `json:"name"`
+	EventManagerID string          `json:"event_manager_id"`
+	Frequency      ReportFrequency `json:"frequency"`
+	Format         ReportFormat    `json:"format"`
+	Recipients     []string        `json:"recipients"`
+	TopLimit       int             `json:"top"`
+}
+
+// Validate checks the request has a name, a known frequency and format, and
+// valid recipients.
+func (r *ReportScheduleRequest) Validate() error {
+	if r.Name == "" {
+		return ErrEmptyReportScheduleName
+	}
+	switch r.Frequency {
+	case ReportDaily, ReportWeekly:
+	default:
+		return ErrInvalidReportFrequency
+	}
+	switch r.Format {
+	case ReportCSV, ReportHTML:
+	default:
+		return ErrInvalidReportFormat
+	}
+	if len(r.Recipients) == 0 {
+		return ErrEmptyReportRecipients
+	}
+	if len(r.Recipients) > MaxReportRecipients {
+		return ErrTooManyReportRecipients
+	}
+	for _, recipient := range r.Recipients {
+		if addr, err := mail.ParseAddress(recipient); err != nil || addr.Address != recipient {
+			return ErrInvalidReportRecipient
+		}
+	}
+	if r.TopLimit < 0 {
+		return ErrInvalidReportTopLimit
+	}
+	return nil
+}
+
+// ToReportSchedule converts the request to a new ReportSchedule.
+func (r *ReportScheduleRequest) ToReportSchedule(id string) *ReportSchedule {
+	now := time.Now().UTC()
+	return &ReportSchedule{
+		ID:             id,
+		Name:           r.Name,
+		EventManagerID: r.EventManagerID,
+		Frequency:      r.Frequency,
+		Format:         r.Format,
+		Recipients:     r.Recipients,
+		TopLimit:       r.TopLimit,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// ApplyTo replaces the settings of a schedule with those of the request.
+func (r *ReportScheduleRequest) ApplyTo(s *ReportSchedule) {
+	s.Name = r.Name
+	s.EventManagerID = r.EventManagerID
+	s.Frequency = r.Frequency
+	s.Format = r.Format
+	s.Recipients = r.Recipients
+	s.TopLimit = r.TopLimit
+	s.UpdatedAt = time.Now().UTC()
+}
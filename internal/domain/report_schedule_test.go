@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReportSchedule_Period(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		frequency ReportFrequency
+		from, to  time.Time
+	}{
+		{ReportDaily, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{ReportWeekly, time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.frequency), func(t *testing.T) {
+			schedule := ReportSchedule{Frequency: tt.frequency}
+			from, to := schedule.Period(now)
+			if !from.Equal(tt.from) || !to.Equal(tt.to) {
+				t.Errorf("Period() = [%s, %s), want [%s, %s)", from, to, tt.from, tt.to)
+			}
+		})
+	}
+
+	// On a Monday the week just ended is reported
+	monday := time.Date(2026, 3, 2, 0, 0, 1, 0, time.UTC)
+	schedule := ReportSchedule{Frequency: ReportWeekly}
+	if _, to := schedule.Period(monday); !to.Equal(monday.Truncate(time.Hour)) {
+		t.Errorf("Period() on Monday ends %s, want %s", to, monday.Truncate(time.Hour))
+	}
+}
+
+func TestReportSchedule_Due(t *testing.T) {
+	created := time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC)
+	schedule := ReportSchedule{Frequency: ReportDaily, CreatedAt: created}
+
+	if schedule.Due(created.Add(time.Hour)) {
+		t.Error("Due() on the day of creation = true, want false")
+	}
+	nextDay := created.Add(24 * time.Hour)
+	if !schedule.Due(nextDay) {
+		t.Fatal("Due() the next day = false, want true")
+	}
+	_, to := schedule.Period(nextDay)
+	schedule.LastReportTo = &to
+	if schedule.Due(nextDay.Add(time.Hour)) {
+		t.Error("Due() after the report was sent = true, want false")
+	}
+}
+
+func TestReportScheduleRequest_Validate(t *testing.T) {
+	valid := ReportScheduleRequest{
+		Name:       "Weekly ops",
+		Frequency:  ReportWeekly,
+		Format:     ReportCSV,
+		Recipients: []string{"ops@example.com"},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(r *ReportScheduleRequest)
+		want   error
+	}{
+		{"valid", func(r *ReportScheduleRequest) {}, nil},
+		{"no name", func(r *ReportScheduleRequest) { r.Name = "" }, ErrEmptyReportScheduleName},
+		{"unknown frequency", func(r *ReportScheduleRequest) { r.Frequency = "hourly" }, ErrInvalidReportFrequency},
+		{"unknown format", func(r *ReportScheduleRequest) { r.Format = "pdf" }, ErrInvalidReportFormat},
+		{"no recipients", func(r *ReportScheduleRequest) { r.Recipients = nil }, ErrEmptyReportRecipients},
+		{"invalid recipient", func(r *ReportScheduleRequest) { r.Recipients = []string{"Ops <ops@example.com>"} }, ErrInvalidReportRecipient},
+		{"negative top", func(r *ReportScheduleRequest) { r.TopLimit = -1 }, ErrInvalidReportTopLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.modify(&req)
+			if err := req.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
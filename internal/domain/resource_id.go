@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// MaxResourceIDLength is the longest ID of an event manager or grouping rule
+// the repositories can store; generated IDs are 36-character UUIDs.
+const MaxResourceIDLength = 36
+
+// resourceIDPattern restricts client-supplied IDs to characters that need no
+// escaping in URL paths.
+var resourceIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// Errors for client-supplied resource IDs.
+var (
+	ErrInvalidResourceID = invalidError("invalid id")
+	ErrResourceMismatch  = conflictError("a resource with this id already exists with a different configuration")
+)
+
+// ValidateResourceID checks that a client-supplied event manager or grouping
+// rule ID is non-empty, short enough to be stored and URL-safe.
+func ValidateResourceID(id string) error {
+	if id == "" {
+		return fmt.Errorf("%w: id is required", ErrInvalidResourceID)
+	}
+	if len(id) > MaxResourceIDLength {
+		return fmt.Errorf("%w: %q is longer than %d characters", ErrInvalidResourceID, id, MaxResourceIDLength)
+	}
+	if !resourceIDPattern.MatchString(id) {
+		return fmt.Errorf("%w: %q may only contain letters, digits, '.', '_' and '-'", ErrInvalidResourceID, id)
+	}
+	return nil
+}
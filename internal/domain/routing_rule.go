@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// RoutingRule selects the event manager of events sent without an
+// event_manager_id, so senders don't need to know it. Rules are evaluated by
+// ascending priority; the first one whose matcher accepts the event wins.
+type RoutingRule struct {
+	// ID is the unique identifier for this routing rule.
+	ID string `json:"id"`
+
+	// Name is a human-readable name for the routing rule.
+	Name string `json:"name"`
+
+	// Priority orders the rules; lower values are evaluated first.
+	Priority int `json:"priority"`
+
+	// Match selects the events routed by this rule. A matcher without
+	// conditions is a catch-all.
+	Match RouteMatcher `json:"match"`
+
+	// EventManagerID is the event manager matching events are sent to.
+	EventManagerID string `json:"event_manager_id"`
+
+	// CreatedAt is when the routing rule was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is when the routing rule was last modified.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RouteMatcher selects events by class, source, labels and expression.
+// An empty list or map matches any value.
+type RouteMatcher struct {
+	// Classes lists the event classes to match.
+	Classes []string `json:"classes,omitempty"`
+
+	// Sources lists the event sources to match.
+	Sources []string `json:"sources,omitempty"`
+
+	// Labels lists labels the event must carry with exactly these values.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Expression is a condition over any event field, for matches the
+	// other conditions can't express.
+	Expression Expression `json:"expression,omitempty"`
+}
+
+// Validation errors for RoutingRule.
+var (
+	ErrEmptyRoutingRuleName = invalidError("name is required")
+	ErrEmptyRouteTarget     = invalidError("event_manager_id is required")
+	ErrRoutingRuleNotFound  = notFoundError("routing rule not found")
+	ErrNoRoute              = invalidError("event_manager_id is required: no routing rule matches the event")
+)
+
+// Matches returns true if the event satisfies every condition of the matcher.
+func (m *RouteMatcher) Matches(event *Event) bool {
+	if len(m.Classes) > 0 && !containsString(m.Classes, event.Class) {
+		return false
+	}
+	if len(m.Sources) > 0 && !containsString(m.Sources, event.Source) {
+		return false
+	}
+	for key, value := range m.Labels {
+		if got, ok := event.Labels[key]; !ok || got != value {
+			return false
+		}
+	}
+	return m.Expression.Matches(event)
+}
+
+// IsCatchAll returns true if the matcher has no conditions.
+func (m *RouteMatcher) IsCatchAll() bool {
+	return len(m.Classes) == 0 && len(m.Sources) == 0 && len(m.Labels) == 0 && m.Expression == ""
+}
+
+// SortRoutingRules orders rules for evaluation: by priority, then by creation
+// time and ID so rules with equal priority are evaluated in a stable order.
+func SortRoutingRules(rules []*RoutingRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		a, b := rules[i], rules[j]
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// RouteEvent returns the first rule, in evaluation order, that matches the
+// event, or nil if none does. The rules must be sorted with SortRoutingRules.
+func RouteEvent(rules []*RoutingRule, event *Event) *RoutingRule {
+	for _, rule := range rules {
+		if rule.Match.Matches(event) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// CreateRoutingRuleRequest represents the input for creating a new routing rule.
+type CreateRoutingRuleRequest struct {
+	Name           string       `json:"name"`
+	Priority       int          `json:"priority"`
+	Match          RouteMatcher `json:"match"`
+	EventManagerID string       `json:"event_manager_id"`
+}
+
+// Validate checks the create request has required fields.
+func (r *CreateRoutingRuleRequest) Validate() error {
+	return validateRoutingRule(r.Name, r.EventManagerID, &r.Match)
+}
+
+// ToRoutingRule converts the request to a RoutingRule entity.
+func (r *CreateRoutingRuleRequest) ToRoutingRule(id string) *RoutingRule {
+	now := time.Now().UTC()
+	return &RoutingRule{
+		ID:             id,
+		Name:           r.Name,
+		Priority:       r.Priority,
+		Match:          r.Match,
+		EventManagerID: r.EventManagerID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// UpdateRoutingRuleRequest represents the input for updating a routing rule.
+type UpdateRoutingRuleRequest struct {
+	Name           string       `json:"name"`
+	Priority       int          `json:"priority"`
+	Match          RouteMatcher `json:"match"`
+	EventManagerID string       `json:"event_manager_id"`
+}
+
+// Validate checks the update request has required fields.
+func (r *UpdateRoutingRuleRequest) Validate() error {
+	return validateRoutingRule(r.Name, r.EventManagerID, &r.Match)
+}
+
+// ApplyTo updates an existing RoutingRule with the request values.
+func (r *UpdateRoutingRuleRequest) ApplyTo(rule *RoutingRule) {
+	rule.Name = r.Name
+	rule.Priority = r.Priority
+	rule.Match = r.Match
+	rule.EventManagerID = r.EventManagerID
+	rule.UpdatedAt = time.Now().UTC()
+}
+
+// validateRoutingRule checks the fields shared by the create and update requests.
+func validateRoutingRule(name, eventManagerID string, match *RouteMatcher) error {
+	if name == "" {
+		return ErrEmptyRoutingRuleName
+	}
+	if eventManagerID == "" {
+		return ErrEmptyRouteTarget
+	}
+	return match.Expression.Validate()
+}
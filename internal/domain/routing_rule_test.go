@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRouteMatcher_Matches(t *testing.T) {
+	event := &Event{
+		Class:  "database",
+		Source: "prometheus",
+		Labels: map[string]string{"team": "payments", "env": "prod"},
+	}
+
+	tests := []struct {
+		name    string
+		matcher RouteMatcher
+		want    bool
+	}{
+		{name: "catch-all", matcher: RouteMatcher{}, want: true},
+		{name: "class", matcher: RouteMatcher{Classes: []string{"network", "database"}}, want: true},
+		{name: "other class", matcher: RouteMatcher{Classes: []string{"network"}}, want: false},
+		{name: "source", matcher: RouteMatcher{Sources: []string{"prometheus"}}, want: true},
+		{name: "other source", matcher: RouteMatcher{Sources: []string{"datadog"}}, want: false},
+		{name: "labels", matcher: RouteMatcher{Labels: map[string]string{"team": "payments"}}, want: true},
+		{name: "label value differs", matcher: RouteMatcher{Labels: map[string]string{"env": "staging"}}, want: false},
+		{name: "label missing", matcher: RouteMatcher{Labels: map[string]string{"region": "eu"}}, want: false},
+		{name: "expression", matcher: RouteMatcher{Expression: `event.labels["env"] == "prod"`}, want: true},
+		{name: "expression and class", matcher: RouteMatcher{Classes: []string{"database"}, Expression: `event.source != "prometheus"`}, want: false},
+		{
+			name: "all conditions",
+			matcher: RouteMatcher{
+				Classes: []string{"database"},
+				Sources: []string{"prometheus"},
+				Labels:  map[string]string{"team": "payments", "env": "prod"},
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.matcher.Matches(event); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouteEvent(t *testing.T) {
+	now := time.Now()
+	rules := []*RoutingRule{
+		{ID: "default", Priority: 100, EventManagerID: "em-default", CreatedAt: now},
+		{ID: "db-late", Priority: 10, Match: RouteMatcher{Classes: []string{"database"}}, EventManagerID: "em-late", CreatedAt: now.Add(time.Minute)},
+		{ID: "db", Priority: 10, Match: RouteMatcher{Classes: []string{"database"}}, EventManagerID: "em-db", CreatedAt: now},
+	}
+	SortRoutingRules(rules)
+
+	if rule := RouteEvent(rules, &Event{Class: "database"}); rule == nil || rule.ID != "db" {
+		t.Errorf("RouteEvent(database) = %v, want the earliest rule with the lowest priority", rule)
+	}
+	if rule := RouteEvent(rules, &Event{Class: "network"}); rule == nil || rule.ID != "default" {
+		t.Errorf("RouteEvent(network) = %v, want the catch-all rule", rule)
+	}
+	if rule := RouteEvent(rules[:2], &Event{Class: "network"}); rule != nil {
+		t.Errorf("RouteEvent without catch-all = %v, want nil", rule)
+	}
+}
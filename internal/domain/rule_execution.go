@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// RuleExecution records one run of a query rule, to debug why it did or
+// didn't fire.
+type RuleExecution struct {
+	// RuleID identifies the rule that ran.
+	RuleID string `json:"rule_id"`
+
+	// StartedAt is when the rule ran; queries are evaluated at this time,
+	// bound as their now parameter.
+	StartedAt time.Time `json:"started_at"`
+
+	// Duration is how long the run took, the query included.
+	Duration Duration `json:"duration"`
+
+	// Datasource is the datasource queried; empty for composite rules.
+	Datasource string `json:"datasource,omitempty"`
+
+	// Query is the query sent to the datasource, or the expression of a
+	// composite rule.
+	Query string `json:"query"`
+
+	// Params are the params bound to the query, besides now.
+	Params map[string]string `json:"params,omitempty"`
+
+	// Muted is true if the rule was muted, so new breaches raised no alerts.
+	Muted bool `json:"muted,omitempty"`
+
+	// Outcomes are the values of the variables of a composite rule.
+	Outcomes map[string]bool `json:"outcomes,omitempty"`
+
+	// Series is how many series the query returned.
+	Series int `json:"series"`
+
+	// Breaching holds the dedup keys of the series breaching the condition.
+	Breaching []string `json:"breaching,omitempty"`
+
+	// Triggered and Resolved hold the dedup keys of the alerts the run
+	// triggered and resolved.
+	Triggered []string `json:"triggered,omitempty"`
+	Resolved  []string `json:"resolved,omitempty"`
+
+	// Error is why the run failed; empty if it succeeded.
+	Error string `json:"error,omitempty"`
+}
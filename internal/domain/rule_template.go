@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"time"
+)
+
+// Validation errors for RuleTemplate.
+var (
+	ErrRuleTemplateNotFound  = notFoundError("rule template not found")
+	ErrEmptyRuleTemplateName = invalidError("name is required")
+	ErrRuleTemplateComposite = invalidError("rule templates can't define composite rules")
+	ErrEmptyTemplateValues   = invalidError("values is required")
+	ErrInvalidTemplateValues = invalidError("values must set every variable of the template, and no other")
+	ErrRuleManagedByTemplate = conflictError("rule is managed by a template")
+)
+
+// templateVariable matches the placeholders of rule templates, e.g.
+// {{service}}, capturing the variable name.
+var templateVariable = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// templateKeywords are the Go template actions matching templateVariable,
+// left in place for the summary template of the rule, e.g. {{end}}.
+var templateKeywords = map[string]bool{"end": true, "else": true, "break": true, "continue": true, "nil": true}
+
+// RuleTemplate defines a query rule with {{variable}} placeholders, to
+// create near-identical rules, e.g. one per service. Each set of values
+// instantiates a rule linked to the template; updating the template updates
+// every instance.
+//
+// Placeholders are substituted in the name, event manager, datasource,
+// query, param values, summary and class of the rule.
+type RuleTemplate struct {
+	// ID is the unique identifier for this template.
+	ID string `json:"id"`
+
+	// Name is a human-readable name for the template.
+	Name string `json:"name"`
+
+	// Rule is the rule instantiated, holding the placeholders.
+	Rule CreateQueryRuleRequest `json:"rule"`
+
+	// Variables are the names of the placeholders of Rule, sorted.
+	Variables []string `json:"variables"`
+
+	// CreatedAt is when the template was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is when the template was last modified.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Render returns the rule of the template with each placeholder replaced by
+// its value. The values must set every variable, and no other.
+func (t *RuleTemplate) Render(values map[string]string) (*CreateQueryRuleRequest, error) {
+	for _, variable := range t.Variables {
+		if _, ok := values[variable]; !ok {
+			return nil, fmt.Errorf("%w: %s is not set", ErrInvalidTemplateValues, variable)
+		}
+	}
+	for variable := range values {
+		if !slices.Contains(t.Variables, variable) {
+			return nil, fmt.Errorf("%w: %s is not a variable", ErrInvalidTemplateValues, variable)
+		}
+	}
+
+	render := func(s string) string {
+		return templateVariable.ReplaceAllStringFunc(s, func(placeholder string) string {
+			variable := templateVariable.FindStringSubmatch(placeholder)[1]
+			if templateKeywords[variable] {
+				return placeholder
+			}
+			return values[variable]
+		})
+	}
+
+	rule := t.Rule
+	rule.Name = render(rule.Name)
+	rule.EventManagerID = render(rule.EventManagerID)
+	rule.Datasource = render(rule.Datasource)
+	rule.Query = render(rule.Query)
+	rule.Summary = render(rule.Summary)
+	rule.Class = render(rule.Class)
+	if t.Rule.Params != nil {
+		rule.Params = make(map[string]string, len(t.Rule.Params))
+		for name, value := range t.Rule.Params {
+			rule.Params[name] = render(value)
+		}
+	}
+	rule.DedupLabels = slices.Clone(t.Rule.DedupLabels)
+	return &rule, nil
+}
+
+// Instantiate returns a new rule rendered from the template with values,
+// linked to the template. The rendered rule is validated, as values can
+// make it invalid, e.g. by leaving its name empty.
+func (t *RuleTemplate) Instantiate(id string, values map[string]string, now time.Time) (*QueryRule, error) {
+	req, err := t.Render(values)
+	if err != nil {
+		return nil, err
+	}
+	rule := req.ToQueryRule(id, now)
+	rule.TemplateID = t.ID
+	rule.TemplateValues = maps.Clone(values)
+	if err := validateQueryRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// ApplyTo re-renders an instance of the template with its values, and
+// validates it. Whether the instance is disabled or muted is kept.
+func (t *RuleTemplate) ApplyTo(rule *QueryRule, now time.Time) error {
+	req, err := t.Render(rule.TemplateValues)
+	if err != nil {
+		return err
+	}
+	disabled, mutedUntil := rule.Disabled, rule.MutedUntil
+	update := UpdateQueryRuleRequest(*req)
+	update.ApplyTo(rule, now)
+	rule.Disabled, rule.MutedUntil = disabled, mutedUntil
+	return validateQueryRule(rule)
+}
+
+// templateVariables returns the names of the placeholders of a rule, sorted.
+func templateVariables(rule *CreateQueryRuleRequest) []string {
+	fields := []string{rule.Name, rule.EventManagerID, rule.Datasource, rule.Query, rule.Summary, rule.Class}
+	for _, value := range rule.Params {
+		fields = append(fields, value)
+	}
+
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		for _, match := range templateVariable.FindAllStringSubmatch(field, -1) {
+			if !templateKeywords[match[1]] {
+				seen[match[1]] = true
+			}
+		}
+	}
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// CreateRuleTemplateRequest represents the input for creating a new rule
+// template.
+type CreateRuleTemplateRequest struct {
+	Name string                 `json:"name"`
+	Rule CreateQueryRuleRequest `json:"rule"`
+}
+
+// Validate checks the create request has required fields, and its rule is
+// valid with the placeholders left in.
+func (r *CreateRuleTemplateRequest) Validate() error {
+	return validateRuleTemplate(r.Name, &r.Rule)
+}
+
+// ToRuleTemplate converts the request to a RuleTemplate created at now.
+func (r *CreateRuleTemplateRequest) ToRuleTemplate(id string, now time.Time) *RuleTemplate {
+	return &RuleTemplate{
+		ID:        id,
+		Name:      r.Name,
+		Rule:      r.Rule,
+		Variables: templateVariables(&r.Rule),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// UpdateRuleTemplateRequest represents the input for updating a rule
+// template.
+type UpdateRuleTemplateRequest struct {
+	Name string                 `json:"name"`
+	Rule CreateQueryRuleRequest `json:"rule"`
+}
+
+// Validate checks the update request has required fields, and its rule is
+// valid with the placeholders left in.
+func (r *UpdateRuleTemplateRequest) Validate() error {
+	return validateRuleTemplate(r.Name, &r.Rule)
+}
+
+// ApplyTo updates an existing RuleTemplate with the request values at now.
+func (r *UpdateRuleTemplateRequest) ApplyTo(template *RuleTemplate, now time.Time) {
+	template.Name = r.Name
+	template.Rule = r.Rule
+	template.Variables = templateVariables(&r.Rule)
+	template.UpdatedAt = now
+}
+
+// InstantiateRuleTemplateRequest represents the input for instantiating a
+// rule template, once per set of values, e.g.
+//
+//	{"values": [{"service": "api"}, {"service": "web"}]}
+type InstantiateRuleTemplateRequest struct {
+	Values []map[string]string `json:"values"`
+}
+
+// Validate checks the request holds values.
+func (r *InstantiateRuleTemplateRequest) Validate() error {
+	if len(r.Values) == 0 {
+		return ErrEmptyTemplateValues
+	}
+	return nil
+}
+
+// validateRuleTemplate checks the name of a template is set, and its rule
+// is a valid rule running a query once each placeholder is replaced by the
+// name of its variable. Values can still make it invalid, so the rendered
+// rules are validated again when instantiated.
+func validateRuleTemplate(name string, rule *CreateQueryRuleRequest) error {
+	if name == "" {
+		return ErrEmptyRuleTemplateName
+	}
+	if rule.Composite != nil {
+		return ErrRuleTemplateComposite
+	}
+
+	sample := &RuleTemplate{Rule: *rule, Variables: templateVariables(rule)}
+	values := make(map[string]string, len(sample.Variables))
+	for _, variable := range sample.Variables {
+		values[variable] = variable
+	}
+	rendered, err := sample.Render(values)
+	if err != nil {
+		return err
+	}
+	if err := rendered.Validate(); err != nil {
+		return fmt.Errorf("rule.%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRuleTemplate_Instantiate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	req := CreateRuleTemplateRequest{
+		Name: "Service errors",
+		Rule: CreateQueryRuleRequest{
+			Name:           "{{service}} errors",
+			EventManagerID: "em-1",
+			Datasource:     "logs",
+			Query:          `sum(count_over_time({app="{{ service }}", env="{{env}}"} |= "error" [5m]))`,
+			Params:         map[string]string{"team": "{{service}}-oncall"},
+			Summary:        "{{service}}: {{.Value}} errors{{if .Labels.pod}} on {{.Labels.pod}}{{end}}",
+		},
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	template := req.ToRuleTemplate("t1", now)
+	if len(template.Variables) != 2 || template.Variables[0] != "env" || template.Variables[1] != "service" {
+		t.Fatalf("Variables = %v, want [env service]", template.Variables)
+	}
+
+	rule, err := template.Instantiate("r1", map[string]string{"service": "api", "env": "prod"}, now)
+	if err != nil {
+		t.Fatalf("Instantiate() error = %v", err)
+	}
+	if rule.Name != "api errors" || rule.Query != `sum(count_over_time({app="api", env="prod"} |= "error" [5m]))` || rule.Params["team"] != "api-oncall" {
+		t.Errorf("rendered rule = %+v", rule)
+	}
+	if rule.Summary != "api: {{.Value}} errors{{if .Labels.pod}} on {{.Labels.pod}}{{end}}" {
+		t.Errorf("rendered summary = %s, want its template actions kept", rule.Summary)
+	}
+	if rule.TemplateID != "t1" || rule.TemplateValues["service"] != "api" {
+		t.Errorf("instance link = %s %v", rule.TemplateID, rule.TemplateValues)
+	}
+	if template.Rule.Params["team"] != "{{service}}-oncall" {
+		t.Error("Instantiate() must not modify the template")
+	}
+
+	for _, values := range []map[string]string{
+		{"service": "api"},
+		{"service": "api", "env": "prod", "region": "eu"},
+	} {
+		if _, err := template.Instantiate("r2", values, now); !errors.Is(err, ErrInvalidTemplateValues) {
+			t.Errorf("Instantiate(%v) error = %v, want ErrInvalidTemplateValues", values, err)
+		}
+	}
+	if _, err := template.Instantiate("r2", map[string]string{"service": "api", "env": "prod"}, now); err != nil {
+		t.Errorf("Instantiate() error = %v", err)
+	}
+
+	// Updating the template re-renders the instance, keeping its mute
+	mutedUntil := now.Add(time.Hour)
+	rule.MutedUntil = &mutedUntil
+	update := UpdateRuleTemplateRequest{Name: template.Name, Rule: template.Rule}
+	update.Rule.Name = "{{service}} errors in {{env}}"
+	update.ApplyTo(template, now.Add(time.Minute))
+	if err := template.ApplyTo(rule, now.Add(time.Minute)); err != nil {
+		t.Fatalf("ApplyTo() error = %v", err)
+	}
+	if rule.Name != "api errors in prod" || rule.MutedUntil == nil || !rule.UpdatedAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("re-rendered rule = %+v", rule)
+	}
+}
+
+func TestCreateRuleTemplateRequest_Validate(t *testing.T) {
+	req := CreateRuleTemplateRequest{Rule: CreateQueryRuleRequest{Name: "{{service}}", EventManagerID: "em-1", Datasource: "logs", Query: "q"}}
+	if err := req.Validate(); !errors.Is(err, ErrEmptyRuleTemplateName) {
+		t.Errorf("Validate() error = %v, want ErrEmptyRuleTemplateName", err)
+	}
+
+	req.Name = "Template"
+	req.Rule.Query = ""
+	if err := req.Validate(); !errors.Is(err, ErrEmptyQueryRuleQuery) || err.Error() != "rule.query is required" {
+		t.Errorf("Validate() error = %v, want rule.query is required", err)
+	}
+
+	req.Rule = CreateQueryRuleRequest{Name: "Down", EventManagerID: "em-1", Composite: &CompositeCondition{Expression: "A", Rules: map[string]string{"A": "r1"}}}
+	if err := req.Validate(); !errors.Is(err, ErrRuleTemplateComposite) {
+		t.Errorf("Validate() error = %v, want ErrRuleTemplateComposite", err)
+	}
+}
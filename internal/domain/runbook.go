@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"net/url"
+	"slices"
+)
+
+// Validation errors for runbooks.
+var (
+	ErrInvalidRunbookURL   = invalidError("runbook url must be an absolute http or https URL")
+	ErrEmptyRunbookClass   = invalidError("runbooks.classes keys must not be empty")
+	ErrEmptyRunbookContent = invalidError("runbook must have a url or steps")
+)
+
+// Runbook tells responders how to handle an alert: a link to the runbook
+// and remediation steps. It is copied onto alerts when they are created and
+// sent with their notifications, so responders needn't hunt for it.
+type Runbook struct {
+	// URL links to the runbook.
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// Steps are remediation steps to try first.
+	Steps []string `json:"steps,omitempty" yaml:"steps,omitempty"`
+}
+
+// IsZero returns true if the runbook has neither a URL nor steps.
+func (r *Runbook) IsZero() bool {
+	return r.URL == "" && len(r.Steps) == 0
+}
+
+// Validate checks the URL is empty or an absolute http(s) URL.
+func (r *Runbook) Validate() error {
+	if r.URL == "" {
+		return nil
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidRunbookURL
+	}
+	return nil
+}
+
+// Equal returns true if both runbooks have the same URL and steps. No steps
+// and an empty list of steps are equal.
+func (r *Runbook) Equal(other *Runbook) bool {
+	return r.URL == other.URL && slices.Equal(r.Steps, other.Steps)
+}
+
+// RunbookConfig holds the runbooks of an event manager: a default and
+// overrides for alerts of particular classes.
+type RunbookConfig struct {
+	// Default applies to alerts without a more specific runbook.
+	Default Runbook `json:"default" yaml:"default,omitempty"`
+
+	// Classes overrides the runbook for alerts of a class, by class.
+	Classes map[string]Runbook `json:"classes,omitempty" yaml:"classes,omitempty"`
+}
+
+// Validate checks every runbook and that class overrides are not empty.
+func (c *RunbookConfig) Validate() error {
+	if err := c.Default.Validate(); err != nil {
+		return err
+	}
+	for class, runbook := range c.Classes {
+		if class == "" {
+			return ErrEmptyRunbookClass
+		}
+		if runbook.IsZero() {
+			return ErrEmptyRunbookContent
+		}
+		if err := runbook.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Equal returns true if both configurations hold the same runbooks.
+func (c *RunbookConfig) Equal(other *RunbookConfig) bool {
+	if !c.Default.Equal(&other.Default) || len(c.Classes) != len(other.Classes) {
+		return false
+	}
+	for class, runbook := range c.Classes {
+		otherRunbook, ok := other.Classes[class]
+		if !ok || !runbook.Equal(&otherRunbook) {
+			return false
+		}
+	}
+	return true
+}
+
+// RunbookFor returns a copy of the runbook of an alert of the class created
+// by the event manager, or nil if none applies. A class override of the event
+// manager wins over the runbook of the grouping rule, which wins over the
+// default of the event manager. rule may be nil.
+func RunbookFor(em *EventManager, rule *GroupingRule, class string) *Runbook {
+	var runbook Runbook
+	if override, ok := em.Runbooks.Classes[class]; ok {
+		runbook = override
+	} else if rule != nil && !rule.Runbook.IsZero() {
+		runbook = rule.Runbook
+	} else {
+		runbook = em.Runbooks.Default
+	}
+	if runbook.IsZero() {
+		return nil
+	}
+	runbook.Steps = slices.Clone(runbook.Steps)
+	return &runbook
+}
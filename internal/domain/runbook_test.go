@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRunbookConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  RunbookConfig
+		wantErr error
+	}{
+		{name: "empty"},
+		{
+			name: "default and class",
+			config: RunbookConfig{
+				Default: Runbook{URL: "https://wiki.example.com/oncall"},
+				Classes: map[string]Runbook{"db": {Steps: []string{"Check replication lag"}}},
+			},
+		},
+		{
+			name:    "relative url",
+			config:  RunbookConfig{Default: Runbook{URL: "/wiki/oncall"}},
+			wantErr: ErrInvalidRunbookURL,
+		},
+		{
+			name:    "other scheme",
+			config:  RunbookConfig{Classes: map[string]Runbook{"db": {URL: "ftp://example.com/db"}}},
+			wantErr: ErrInvalidRunbookURL,
+		},
+		{
+			name:    "empty class",
+			config:  RunbookConfig{Classes: map[string]Runbook{"": {URL: "https://example.com"}}},
+			wantErr: ErrEmptyRunbookClass,
+		},
+		{
+			name:    "empty override",
+			config:  RunbookConfig{Classes: map[string]Runbook{"db": {}}},
+			wantErr: ErrEmptyRunbookContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunbookFor(t *testing.T) {
+	em := &EventManager{Runbooks: RunbookConfig{
+		Default: Runbook{URL: "https://wiki.example.com/default"},
+		Classes: map[string]Runbook{"db": {URL: "https://wiki.example.com/db", Steps: []string{"Fail over"}}},
+	}}
+	rule := &GroupingRule{Runbook: Runbook{URL: "https://wiki.example.com/rule"}}
+
+	tests := []struct {
+		name    string
+		em      *EventManager
+		rule    *GroupingRule
+		class   string
+		wantURL string
+	}{
+		{name: "class override wins", em: em, rule: rule, class: "db", wantURL: "https://wiki.example.com/db"},
+		{name: "rule before default", em: em, rule: rule, class: "web", wantURL: "https://wiki.example.com/rule"},
+		{name: "default without rule", em: em, class: "web", wantURL: "https://wiki.example.com/default"},
+		{name: "default with rule without runbook", em: em, rule: &GroupingRule{}, class: "web", wantURL: "https://wiki.example.com/default"},
+		{name: "none", em: &EventManager{}, rule: &GroupingRule{}, class: "web"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runbook := RunbookFor(tt.em, tt.rule, tt.class)
+			if tt.wantURL == "" {
+				if runbook != nil {
+					t.Errorf("RunbookFor() = %+v, want nil", runbook)
+				}
+				return
+			}
+			if runbook == nil || runbook.URL != tt.wantURL {
+				t.Errorf("RunbookFor() = %+v, want url %s", runbook, tt.wantURL)
+			}
+		})
+	}
+
+	// The alert gets a copy, so later changes don't alter it
+	runbook := RunbookFor(em, nil, "db")
+	runbook.Steps[0] = "changed"
+	if em.Runbooks.Classes["db"].Steps[0] != "Fail over" {
+		t.Error("RunbookFor() shares the steps of the event manager")
+	}
+}
+
+func TestRunbookConfig_Equal(t *testing.T) {
+	a := RunbookConfig{Default: Runbook{URL: "https://example.com", Steps: []string{}}}
+	b := RunbookConfig{Default: Runbook{URL: "https://example.com"}, Classes: map[string]Runbook{}}
+	if !a.Equal(&b) {
+		t.Error("Equal() = false for empty and nil steps and classes")
+	}
+
+	b.Classes["db"] = Runbook{URL: "https://example.com/db"}
+	if a.Equal(&b) {
+		t.Error("Equal() = true with a class override on one side")
+	}
+}
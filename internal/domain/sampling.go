@@ -0,0 +1,28 @@
+package domain
+
+// ErrInvalidSampling is returned for a sampling policy with a negative rate.
+var ErrInvalidSampling = invalidError("sampling.keep_one_in must not be negative")
+
+// SamplingPolicy thins out the trigger events of chatty sources. Once the
+// alert of a dedup key is active, only one in every KeepOneIn further
+// triggers is processed; the others are dropped at ingestion and counted on
+// the alert with the next trigger processed. Resolve events are never
+// sampled.
+type SamplingPolicy struct {
+	// KeepOneIn is the share of triggers kept: one in KeepOneIn. Zero or
+	// one keeps every trigger.
+	KeepOneIn int `json:"keep_one_in,omitempty" yaml:"keep_one_in,omitempty"`
+}
+
+// IsEnabled returns true if the policy drops any triggers.
+func (p *SamplingPolicy) IsEnabled() bool {
+	return p.KeepOneIn > 1
+}
+
+// Validate checks the rate is not negative.
+func (p *SamplingPolicy) Validate() error {
+	if p.KeepOneIn < 0 {
+		return ErrInvalidSampling
+	}
+	return nil
+}
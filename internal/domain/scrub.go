@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+)
+
+// DefaultScrubReplacement replaces the matches of scrub rules without a
+// replacement of their own.
+const DefaultScrubReplacement = "[REDACTED]"
+
+// Validation errors for scrub rules.
+var (
+	ErrEmptyScrubPattern  = invalidError("scrub rules need a pattern")
+	ErrInvalidScrubRegexp = invalidError("invalid scrub pattern")
+)
+
+// ScrubRule masks the matches of a regular expression in alert text, such
+// as secrets and personal data.
+type ScrubRule struct {
+	// Name identifies the rule in metrics.
+	Name string `yaml:"name"`
+
+	// Pattern is an RE2 regular expression.
+	Pattern string `yaml:"pattern"`
+
+	// Replacement replaces every match, with $1 expanding to the first
+	// submatch. It defaults to DefaultScrubReplacement.
+	Replacement string `yaml:"replacement"`
+}
+
+// compiledScrubRule is a scrub rule with its pattern compiled.
+type compiledScrubRule struct {
+	name        string
+	re          *regexp.Regexp
+	replacement string
+}
+
+// Scrubber masks the text of events and alerts with scrub rules, applied in
+// order. A nil Scrubber leaves text as is.
+type Scrubber struct {
+	rules []compiledScrubRule
+
+	// matched is called with the name of a rule for every value it
+	// changed, e.g. to count it.
+	matched func(rule string)
+}
+
+// NewScrubber compiles scrub rules. It returns nil without rules. matched,
+// if not nil, is called with the name of a rule for every value it changes.
+func NewScrubber(rules []ScrubRule, matched func(rule string)) (*Scrubber, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	s := &Scrubber{matched: matched}
+	for i, rule := range rules {
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("rule %d: %w", i, ErrEmptyScrubPattern)
+		}
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w: %v", i, ErrInvalidScrubRegexp, err)
+		}
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("rule_%d", i)
+		}
+		replacement := rule.Replacement
+		if replacement == "" {
+			replacement = DefaultScrubReplacement
+		}
+		s.rules = append(s.rules, compiledScrubRule{name: name, re: re, replacement: replacement})
+	}
+	return s, nil
+}
+
+// String returns text with the matches of every rule replaced.
+func (s *Scrubber) String(text string) string {
+	if s == nil {
+		return text
+	}
+	for _, rule := range s.rules {
+		if !rule.re.MatchString(text) {
+			continue
+		}
+		text = rule.re.ReplaceAllString(text, rule.replacement)
+		if s.matched != nil {
+			s.matched(rule.name)
+		}
+	}
+	return text
+}
+
+// Event scrubs the summary, labels and tags of an event in place. The
+// label and tag maps are replaced, not changed, so maps the event shares
+// are left as they are.
+func (s *Scrubber) Event(event *Event) {
+	if s == nil {
+		return
+	}
+	event.Summary = s.String(event.Summary)
+	event.Labels = s.values(event.Labels)
+	event.Tags = s.values(event.Tags)
+}
+
+// Alert returns a copy of an alert with its summaries and tags scrubbed.
+// A nil Scrubber returns an unscrubbed copy.
+func (s *Scrubber) Alert(alert *Alert) *Alert {
+	scrubbed := *alert
+	if s == nil {
+		return &scrubbed
+	}
+	scrubbed.Summary = s.String(alert.Summary)
+	scrubbed.EventSummary = s.String(alert.EventSummary)
+	scrubbed.Tags = s.values(alert.Tags)
+	return &scrubbed
+}
+
+// values returns a copy of a map with its values scrubbed.
+func (s *Scrubber) values(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return m
+	}
+	scrubbed := maps.Clone(m)
+	for k, v := range scrubbed {
+		scrubbed[k] = s.String(v)
+	}
+	return scrubbed
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewScrubber(t *testing.T) {
+	if s, err := NewScrubber(nil, nil); s != nil || err != nil {
+		t.Errorf("NewScrubber(nil) = %v, %v, want nil, nil", s, err)
+	}
+	if _, err := NewScrubber([]ScrubRule{{Name: "empty"}}, nil); !errors.Is(err, ErrEmptyScrubPattern) {
+		t.Errorf("NewScrubber() error = %v, want %v", err, ErrEmptyScrubPattern)
+	}
+	if _, err := NewScrubber([]ScrubRule{{Pattern: "("}}, nil); !errors.Is(err, ErrInvalidScrubRegexp) {
+		t.Errorf("NewScrubber() error = %v, want %v", err, ErrInvalidScrubRegexp)
+	}
+}
+
+func TestScrubber_String(t *testing.T) {
+	var matched []string
+	s, err := NewScrubber([]ScrubRule{
+		{Name: "email", Pattern: `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`, Replacement: "[email]"},
+		{Name: "bearer", Pattern: `(?i)(bearer\s+)[A-Za-z0-9._~+/-]+=*`, Replacement: "${1}[REDACTED]"},
+		{Pattern: `\b\d{4}-\d{4}-\d{4}-\d{4}\b`},
+	}, func(rule string) { matched = append(matched, rule) })
+	if err != nil {
+		t.Fatalf("NewScrubber() error = %v", err)
+	}
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"disk full on db-1", "disk full on db-1"},
+		{"mail to jane.doe@example.com and joe@example.org bounced", "mail to [email] and [email] bounced"},
+		{"401 with Authorization: Bearer eyJhbGciOi.abc", "401 with Authorization: Bearer [REDACTED]"},
+		{"card 4111-1111-1111-1111 declined", "card [REDACTED] declined"},
+	}
+	for _, tt := range tests {
+		if got := s.String(tt.text); got != tt.want {
+			t.Errorf("String(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+	want := []string{"email", "bearer", "rule_2"}
+	if len(matched) != len(want) || matched[0] != want[0] || matched[1] != want[1] || matched[2] != want[2] {
+		t.Errorf("matched rules = %v, want %v", matched, want)
+	}
+}
+
+func TestScrubber_EventAndAlert(t *testing.T) {
+	s, err := NewScrubber([]ScrubRule{{Pattern: `secret-\w+`}}, nil)
+	if err != nil {
+		t.Fatalf("NewScrubber() error = %v", err)
+	}
+
+	labels := map[string]string{"token": "secret-abc", "host": "db-1"}
+	event := &Event{Summary: "leaked secret-abc", Labels: labels, Tags: map[string]string{"key": "secret-xyz"}}
+	s.Event(event)
+	if event.Summary != "leaked [REDACTED]" || event.Labels["token"] != "[REDACTED]" || event.Labels["host"] != "db-1" || event.Tags["key"] != "[REDACTED]" {
+		t.Errorf("scrubbed event = %+v", event)
+	}
+	if labels["token"] != "secret-abc" {
+		t.Error("Event() changed a shared label map")
+	}
+
+	alert := &Alert{Summary: "leaked secret-abc", EventSummary: "secret-def", Tags: map[string]string{"key": "secret-xyz"}}
+	scrubbed := s.Alert(alert)
+	if scrubbed.Summary != "leaked [REDACTED]" || scrubbed.EventSummary != "[REDACTED]" || scrubbed.Tags["key"] != "[REDACTED]" {
+		t.Errorf("scrubbed alert = %+v", scrubbed)
+	}
+	if alert.Summary != "leaked secret-abc" || alert.Tags["key"] != "secret-xyz" {
+		t.Error("Alert() changed the alert")
+	}
+
+	var none *Scrubber
+	if copied := none.Alert(alert); copied == alert || copied.Summary != alert.Summary {
+		t.Error("a nil scrubber should return an unscrubbed copy")
+	}
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// maxSeverityLength is the longest severity level that can be stored.
+const maxSeverityLength = 20
+
+// SeverityScale is the ordered set of severity levels a deployment accepts,
+// most severe first, e.g. P1 to P5. Levels are compared by their position.
+type SeverityScale struct {
+	levels       []Severity
+	ranks        map[Severity]int
+	defaultLevel Severity
+}
+
+// NewSeverityScale creates a scale of the given levels, most severe first.
+// defaultLevel is applied to events without a severity; empty means the
+// least severe level.
+func NewSeverityScale(levels []Severity, defaultLevel Severity) (*SeverityScale, error) {
+	if len(levels) == 0 {
+		return nil, errors.New("at least one severity level is required")
+	}
+
+	scale := &SeverityScale{
+		levels: append([]Severity(nil), levels...),
+		ranks:  make(map[Severity]int, len(levels)),
+	}
+	for i, level := range levels {
+		if level == "" || len(level) > maxSeverityLength {
+			return nil, fmt.Errorf("severity level %q must be 1 to %d characters", level, maxSeverityLength)
+		}
+		if _, ok := scale.ranks[level]; ok {
+			return nil, fmt.Errorf("duplicate severity level %q", level)
+		}
+		scale.ranks[level] = i + 1
+	}
+
+	if defaultLevel == "" {
+		defaultLevel = levels[len(levels)-1]
+	}
+	if _, ok := scale.ranks[defaultLevel]; !ok {
+		return nil, fmt.Errorf("default severity %q is not a severity level", defaultLevel)
+	}
+	scale.defaultLevel = defaultLevel
+
+	return scale, nil
+}
+
+// DefaultSeverityScale is the scale of high, medium and low, defaulting to low.
+var DefaultSeverityScale = mustSeverityScale(
+	[]Severity{SeverityHigh, SeverityMedium, SeverityLow},
+	DefaultSeverity,
+)
+
+func mustSeverityScale(levels []Severity, defaultLevel Severity) *SeverityScale {
+	scale, err := NewSeverityScale(levels, defaultLevel)
+	if err != nil {
+		panic(err)
+	}
+	return scale
+}
+
+// Levels returns the levels of the scale, most severe first.
+func (s *SeverityScale) Levels() []Severity {
+	return append([]Severity(nil), s.levels...)
+}
+
+// Default returns the level applied to events without a severity.
+func (s *SeverityScale) Default() Severity {
+	return s.defaultLevel
+}
+
+// Rank returns the position of a level on the scale, from 1 for the most
+// severe, or 0 if the scale has no such level.
+func (s *SeverityScale) Rank(level Severity) int {
+	return s.ranks[level]
+}
+
+// currentSeverityScale is the scale of the deployment.
+var currentSeverityScale atomic.Pointer[SeverityScale]
+
+func init() {
+	currentSeverityScale.Store(DefaultSeverityScale)
+}
+
+// SetSeverityScale replaces the severity scale events and event managers are
+// validated against. It is set once at startup, from the configuration.
+func SetSeverityScale(scale *SeverityScale) {
+	currentSeverityScale.Store(scale)
+}
+
+// CurrentSeverityScale returns the severity scale of the deployment.
+func CurrentSeverityScale() *SeverityScale {
+	return currentSeverityScale.Load()
+}
+
+// IsValid returns true if the severity is a level of the current scale.
+func (s Severity) IsValid() bool {
+	return s.Rank() > 0
+}
+
+// Rank returns the position of the severity on the current scale, from 1 for
+// the most severe, or 0 if it is not a level of the scale.
+func (s Severity) Rank() int {
+	return CurrentSeverityScale().Rank(s)
+}
+
+// MoreSevereThan reports whether the severity ranks above other on the
+// current scale. Any level is more severe than an unknown one.
+func (s Severity) MoreSevereThan(other Severity) bool {
+	rank, otherRank := s.Rank(), other.Rank()
+	return rank > 0 && (otherRank == 0 || rank < otherRank)
+}
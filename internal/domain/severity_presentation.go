@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SeverityPresentation is how notifications and UIs render a severity
+// level, so every channel shows it alike.
+type SeverityPresentation struct {
+	// DisplayName is the name shown for the level, e.g. "High".
+	DisplayName string `json:"display_name" yaml:"display_name"`
+
+	// Color is the hex RGB color of the level, e.g. "#D32F2F", for the
+	// attachment bar of chat messages or the badge of a UI.
+	Color string `json:"color" yaml:"color"`
+
+	// Emoji prefixes the level in plain text channels, e.g. "🔴".
+	Emoji string `json:"emoji" yaml:"emoji"`
+}
+
+// severityPalette holds the default color and emoji of levels by rank, most
+// severe first; levels below the last share it.
+var severityPalette = []SeverityPresentation{
+	{Color: "#D32F2F", Emoji: "🔴"},
+	{Color: "#F57C00", Emoji: "🟠"},
+	{Color: "#FBC02D", Emoji: "🟡"},
+	{Color: "#1976D2", Emoji: "🔵"},
+	{Color: "#757575", Emoji: "⚪"},
+}
+
+// unknownSeverityPresentation is the color and emoji of severities that are
+// not a level of the scale.
+var unknownSeverityPresentation = SeverityPresentation{Color: "#9E9E9E", Emoji: "⚪"}
+
+// colorPattern matches hex RGB colors.
+var colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// SeverityPresentations is the registry of the presentation of each level
+// of a severity scale.
+type SeverityPresentations struct {
+	levels map[Severity]SeverityPresentation
+}
+
+// NewSeverityPresentations creates the presentations of the levels of scale:
+// the level's name, capitalized, and a color and emoji by rank, with the
+// fields set in overrides replacing them. Overrides must name levels of the
+// scale.
+func NewSeverityPresentations(scale *SeverityScale, overrides map[Severity]SeverityPresentation) (*SeverityPresentations, error) {
+	p := &SeverityPresentations{levels: make(map[Severity]SeverityPresentation, len(scale.levels))}
+	for i, level := range scale.levels {
+		presentation := severityPalette[min(i, len(severityPalette)-1)]
+		first, size := utf8.DecodeRuneInString(string(level))
+		presentation.DisplayName = string(unicode.ToUpper(first)) + string(level[size:])
+		p.levels[level] = presentation
+	}
+
+	for level, override := range overrides {
+		presentation, ok := p.levels[level]
+		if !ok {
+			return nil, fmt.Errorf("severity %q is not a severity level", level)
+		}
+		if override.Color != "" && !colorPattern.MatchString(override.Color) {
+			return nil, fmt.Errorf("severity %q: color %q is not a hex color such as \"#D32F2F\"", level, override.Color)
+		}
+		if override.DisplayName != "" {
+			presentation.DisplayName = override.DisplayName
+		}
+		if override.Color != "" {
+			presentation.Color = override.Color
+		}
+		if override.Emoji != "" {
+			presentation.Emoji = override.Emoji
+		}
+		p.levels[level] = presentation
+	}
+	return p, nil
+}
+
+// DefaultSeverityPresentations are the presentations of the default scale.
+var DefaultSeverityPresentations = mustSeverityPresentations(DefaultSeverityScale)
+
+func mustSeverityPresentations(scale *SeverityScale) *SeverityPresentations {
+	p, err := NewSeverityPresentations(scale, nil)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Of returns the presentation of a severity. Severities that are not a level
+// of the scale are shown by name, in grey.
+func (p *SeverityPresentations) Of(severity Severity) SeverityPresentation {
+	if presentation, ok := p.levels[severity]; ok {
+		return presentation
+	}
+	presentation := unknownSeverityPresentation
+	presentation.DisplayName = string(severity)
+	return presentation
+}
+
+// currentSeverityPresentations are the presentations of the deployment.
+var currentSeverityPresentations atomic.Pointer[SeverityPresentations]
+
+func init() {
+	currentSeverityPresentations.Store(DefaultSeverityPresentations)
+}
+
+// SetSeverityPresentations replaces the presentations severities are
+// rendered with. They are set once at startup, from the configuration.
+func SetSeverityPresentations(p *SeverityPresentations) {
+	currentSeverityPresentations.Store(p)
+}
+
+// CurrentSeverityPresentations returns the presentations of the deployment.
+func CurrentSeverityPresentations() *SeverityPresentations {
+	return currentSeverityPresentations.Load()
+}
+
+// Presentation returns how the severity is rendered in the deployment, e.g.
+// {{.Severity.Presentation.Emoji}} in notification messages.
+func (s Severity) Presentation() SeverityPresentation {
+	return CurrentSeverityPresentations().Of(s)
+}
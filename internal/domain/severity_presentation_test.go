@@ -0,0 +1,72 @@
+package domain
+
+import "testing"
+
+func TestNewSeverityPresentations(t *testing.T) {
+	scale, err := NewSeverityScale([]Severity{"P1", "P2", "P3", "P4", "P5", "P6"}, "")
+	if err != nil {
+		t.Fatalf("NewSeverityScale error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		overrides map[Severity]SeverityPresentation
+		severity  Severity
+		want      SeverityPresentation
+		wantErr   bool
+	}{
+		{name: "most severe", severity: "P1", want: SeverityPresentation{DisplayName: "P1", Color: "#D32F2F", Emoji: "🔴"}},
+		{name: "below the palette", severity: "P6", want: SeverityPresentation{DisplayName: "P6", Color: "#757575", Emoji: "⚪"}},
+		{
+			name:      "override",
+			overrides: map[Severity]SeverityPresentation{"P1": {DisplayName: "Critical", Emoji: "🚨"}},
+			severity:  "P1",
+			want:      SeverityPresentation{DisplayName: "Critical", Color: "#D32F2F", Emoji: "🚨"},
+		},
+		{name: "unknown severity", severity: "P9", want: SeverityPresentation{DisplayName: "P9", Color: "#9E9E9E", Emoji: "⚪"}},
+		{name: "override of unknown level", overrides: map[Severity]SeverityPresentation{"high": {Color: "#000000"}}, wantErr: true},
+		{name: "invalid color", overrides: map[Severity]SeverityPresentation{"P2": {Color: "red"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewSeverityPresentations(scale, tt.overrides)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSeverityPresentations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && p.Of(tt.severity) != tt.want {
+				t.Errorf("Of(%q) = %+v, want %+v", tt.severity, p.Of(tt.severity), tt.want)
+			}
+		})
+	}
+}
+
+func TestSeverity_Presentation(t *testing.T) {
+	if got := SeverityMedium.Presentation(); got.DisplayName != "Medium" || got.Color != "#F57C00" {
+		t.Errorf("Presentation(medium) = %+v, want Medium in orange", got)
+	}
+
+	p, err := NewSeverityPresentations(DefaultSeverityScale, map[Severity]SeverityPresentation{SeverityHigh: {Color: "#B71C1C"}})
+	if err != nil {
+		t.Fatalf("NewSeverityPresentations error: %v", err)
+	}
+	SetSeverityPresentations(p)
+	t.Cleanup(func() { SetSeverityPresentations(DefaultSeverityPresentations) })
+
+	if got := SeverityHigh.Presentation(); got.DisplayName != "High" || got.Color != "#B71C1C" {
+		t.Errorf("Presentation(high) = %+v, want High in the configured color", got)
+	}
+}
+
+func TestSeverity_PresentationInMessages(t *testing.T) {
+	catalog, err := NewMessageCatalog(map[string]map[NotificationKind]string{
+		DefaultLocale: {NotificationNewParent: "{{.Severity.Presentation.Emoji}} {{.Summary}}"},
+	})
+	if err != nil {
+		t.Fatalf("NewMessageCatalog error: %v", err)
+	}
+	alert := &Alert{Summary: "Disk full", Severity: SeverityHigh}
+	if got := catalog.Message(DefaultLocale, NotificationNewParent, &MessageData{Alert: alert}); got != "🔴 Disk full" {
+		t.Errorf("Message() = %q, want the emoji of high", got)
+	}
+}
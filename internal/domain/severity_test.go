@@ -0,0 +1,66 @@
+package domain
+
+import "testing"
+
+func TestNewSeverityScale(t *testing.T) {
+	tests := []struct {
+		name         string
+		levels       []Severity
+		defaultLevel Severity
+		wantDefault  Severity
+		wantErr      bool
+	}{
+		{name: "default is least severe", levels: []Severity{"P1", "P2", "P3"}, wantDefault: "P3"},
+		{name: "explicit default", levels: []Severity{"P1", "P2", "P3"}, defaultLevel: "P2", wantDefault: "P2"},
+		{name: "no levels", wantErr: true},
+		{name: "empty level", levels: []Severity{"P1", ""}, wantErr: true},
+		{name: "level too long", levels: []Severity{"a-very-long-severity-level"}, wantErr: true},
+		{name: "duplicate level", levels: []Severity{"P1", "P1"}, wantErr: true},
+		{name: "unknown default", levels: []Severity{"P1", "P2"}, defaultLevel: "P9", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scale, err := NewSeverityScale(tt.levels, tt.defaultLevel)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSeverityScale() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && scale.Default() != tt.wantDefault {
+				t.Errorf("Default() = %q, want %q", scale.Default(), tt.wantDefault)
+			}
+		})
+	}
+}
+
+func TestSeverity_CustomScale(t *testing.T) {
+	scale, err := NewSeverityScale([]Severity{"P1", "P2", "P3", "P4", "P5"}, "")
+	if err != nil {
+		t.Fatalf("NewSeverityScale error: %v", err)
+	}
+	SetSeverityScale(scale)
+	t.Cleanup(func() { SetSeverityScale(DefaultSeverityScale) })
+
+	if !Severity("P2").IsValid() || SeverityHigh.IsValid() {
+		t.Error("IsValid should accept the levels of the current scale only")
+	}
+	if rank := Severity("P4").Rank(); rank != 4 {
+		t.Errorf("Rank(P4) = %d, want 4", rank)
+	}
+	if !Severity("P1").MoreSevereThan("P2") || Severity("P3").MoreSevereThan("P2") {
+		t.Error("MoreSevereThan should follow the order of the scale")
+	}
+	if !Severity("P5").MoreSevereThan("unknown") || Severity("unknown").MoreSevereThan("P5") {
+		t.Error("any level should be more severe than an unknown one")
+	}
+
+	event := &Event{EventManagerID: "em-1", Summary: "disk full", Severity: "high", Action: ActionTrigger, DedupKey: "a"}
+	if err := event.Validate(); err != ErrInvalidSeverity {
+		t.Errorf("Validate(high) error = %v, want %v", err, ErrInvalidSeverity)
+	}
+
+	event.Severity = ""
+	(&EventDefaults{}).Apply(event)
+	if event.Severity != "P5" {
+		t.Errorf("default severity = %q, want P5", event.Severity)
+	}
+}
@@ -0,0 +1,244 @@
+package domain
+
+import (
+	"path"
+	"slices"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Validation errors for Silence.
+var (
+	ErrSilenceNotFound       = notFoundError("silence not found")
+	ErrSilenceExists         = conflictError("silence already exists")
+	ErrEmptySilenceMatcher   = invalidError("match needs event_manager_id, dedup_key_pattern, classes or severities")
+	ErrInvalidSilencePattern = invalidError("match.dedup_key_pattern is not a valid pattern")
+	ErrInvalidSilenceRange   = invalidError("ends_at must be after starts_at")
+	ErrSilenceRangeSchedule  = invalidError("set either starts_at and ends_at or schedule, not both")
+	ErrInvalidSilenceCron    = invalidError("schedule.cron is not a valid cron expression")
+	ErrInvalidSilenceLength  = invalidError("schedule.duration must be positive")
+)
+
+// cronParser parses the standard five-field cron expressions of silence
+// schedules, with an optional CRON_TZ= or TZ= prefix naming their time zone.
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// SilenceState is where a silence is in its lifetime.
+type SilenceState string
+
+const (
+	// SilencePending means the silence starts in the future.
+	SilencePending SilenceState = "pending"
+	// SilenceActive means the silence currently mutes notifications.
+	SilenceActive SilenceState = "active"
+	// SilenceExpired means the silence has ended.
+	SilenceExpired SilenceState = "expired"
+	// SilenceRecurring means the silence is a schedule, active through the
+	// silences materialized from it.
+	SilenceRecurring SilenceState = "recurring"
+)
+
+// SilenceMatcher selects the alerts a silence mutes. Every condition set
+// must hold; an empty list matches any value.
+type SilenceMatcher struct {
+	// EventManagerID selects the alerts of one event manager.
+	EventManagerID string `json:"event_manager_id,omitempty"`
+
+	// DedupKeyPattern selects alerts by a glob over their dedup key, e.g.
+	// "batch-*".
+	DedupKeyPattern string `json:"dedup_key_pattern,omitempty"`
+
+	// Classes lists the alert classes to match.
+	Classes []string `json:"classes,omitempty"`
+
+	// Severities lists the alert severities to match.
+	Severities []Severity `json:"severities,omitempty"`
+}
+
+// Validate checks the matcher has a condition and a valid pattern.
+func (m *SilenceMatcher) Validate() error {
+	if m.EventManagerID == "" && m.DedupKeyPattern == "" && len(m.Classes) == 0 && len(m.Severities) == 0 {
+		return ErrEmptySilenceMatcher
+	}
+	if _, err := path.Match(m.DedupKeyPattern, ""); err != nil {
+		return ErrInvalidSilencePattern
+	}
+	for _, severity := range m.Severities {
+		if !severity.IsValid() {
+			return ErrInvalidSeverity
+		}
+	}
+	return nil
+}
+
+// Matches returns true if the alert satisfies every condition of the matcher.
+func (m *SilenceMatcher) Matches(alert *Alert) bool {
+	if m.EventManagerID != "" && m.EventManagerID != alert.EventManagerID {
+		return false
+	}
+	if m.DedupKeyPattern != "" {
+		if ok, _ := path.Match(m.DedupKeyPattern, alert.DedupKey); !ok {
+			return false
+		}
+	}
+	if len(m.Classes) > 0 && !containsString(m.Classes, alert.Class) {
+		return false
+	}
+	return len(m.Severities) == 0 || slices.Contains(m.Severities, alert.Severity)
+}
+
+// SilenceSchedule repeats a silence: it is active for Duration from every
+// time the cron expression fires, e.g. "0 2 * * *" for 1h is a nightly
+// window from 02:00 to 03:00.
+type SilenceSchedule struct {
+	// Cron is a standard five-field cron expression, or a descriptor such
+	// as "@daily". A CRON_TZ=<zone> prefix sets its time zone, UTC by
+	// default.
+	Cron string `json:"cron"`
+
+	// Duration is how long each occurrence lasts.
+	Duration Duration `json:"duration"`
+}
+
+// Validate checks the cron expression parses and the duration is positive.
+func (s *SilenceSchedule) Validate() error {
+	if _, err := cronParser.Parse(s.Cron); err != nil {
+		return ErrInvalidSilenceCron
+	}
+	if s.Duration <= 0 {
+		return ErrInvalidSilenceLength
+	}
+	return nil
+}
+
+// Occurrences returns the start times of the occurrences that are active at
+// now or start within lookahead, oldest first.
+func (s *SilenceSchedule) Occurrences(now time.Time, lookahead time.Duration) []time.Time {
+	schedule, err := cronParser.Parse(s.Cron)
+	if err != nil {
+		return nil
+	}
+
+	var starts []time.Time
+	end := now.Add(lookahead)
+	for start := schedule.Next(now.Add(-time.Duration(s.Duration))); !start.IsZero() && !start.After(end); start = schedule.Next(start) {
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+// Silence mutes the notifications of the alerts it matches, for a time
+// range or on a recurring schedule. Alerts are still created, grouped and
+// resolved while silenced. A recurring silence mutes nothing itself: a
+// scheduler materializes each occurrence into a silence of its own, with
+// the time range of the occurrence.
+type Silence struct {
+	// ID is the unique identifier for this silence.
+	ID string `json:"id"`
+
+	// Comment says why the alerts are silenced.
+	Comment string `json:"comment,omitempty"`
+
+	// CreatedBy is who created the silence.
+	CreatedBy string `json:"created_by,omitempty"`
+
+	// Match selects the alerts silenced.
+	Match SilenceMatcher `json:"match"`
+
+	// StartsAt and EndsAt bound when a silence mutes notifications. They
+	// are zero on recurring silences.
+	StartsAt time.Time `json:"starts_at,omitzero"`
+	EndsAt   time.Time `json:"ends_at,omitzero"`
+
+	// Schedule makes the silence recurring.
+	Schedule *SilenceSchedule `json:"schedule,omitempty"`
+
+	// RecurringID is the recurring silence this one was materialized from.
+	RecurringID string `json:"recurring_id,omitempty"`
+
+	// CreatedAt is when the silence was created.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsRecurring returns true if the silence is a schedule.
+func (s *Silence) IsRecurring() bool {
+	return s.Schedule != nil
+}
+
+// State returns where the silence is in its lifetime at now.
+func (s *Silence) State(now time.Time) SilenceState {
+	switch {
+	case s.IsRecurring():
+		return SilenceRecurring
+	case now.Before(s.StartsAt):
+		return SilencePending
+	case now.Before(s.EndsAt):
+		return SilenceActive
+	}
+	return SilenceExpired
+}
+
+// Occurrence returns the silence materializing the occurrence of a recurring
+// silence that starts at start. Its ID is derived from the recurring silence
+// and the start, so replicas materializing it concurrently create it once.
+func (s *Silence) Occurrence(id string, start, now time.Time) *Silence {
+	return &Silence{
+		ID:          id,
+		Comment:     s.Comment,
+		CreatedBy:   s.CreatedBy,
+		Match:       s.Match,
+		StartsAt:    start.UTC(),
+		EndsAt:      start.Add(time.Duration(s.Schedule.Duration)).UTC(),
+		RecurringID: s.ID,
+		CreatedAt:   now,
+	}
+}
+
+// CreateSilenceRequest represents the input for creating a new silence.
+type CreateSilenceRequest struct {
+	Comment  string           `json:"comment"`
+	Match    SilenceMatcher   `json:"match"`
+	StartsAt *time.Time       `json:"starts_at"`
+	EndsAt   *time.Time       `json:"ends_at"`
+	Schedule *SilenceSchedule `json:"schedule"`
+}
+
+// Validate checks the request has a valid matcher and either a time range
+// or a schedule.
+func (r *CreateSilenceRequest) Validate() error {
+	if err := r.Match.Validate(); err != nil {
+		return err
+	}
+	if r.Schedule != nil {
+		if r.StartsAt != nil || r.EndsAt != nil {
+			return ErrSilenceRangeSchedule
+		}
+		return r.Schedule.Validate()
+	}
+	if r.EndsAt == nil || (r.StartsAt != nil && !r.EndsAt.After(*r.StartsAt)) {
+		return ErrInvalidSilenceRange
+	}
+	return nil
+}
+
+// ToSilence converts the request to a Silence created by actor at now. A
+// time range without a start starts at now.
+func (r *CreateSilenceRequest) ToSilence(id, actor string, now time.Time) *Silence {
+	silence := &Silence{
+		ID:        id,
+		Comment:   r.Comment,
+		CreatedBy: actor,
+		Match:     r.Match,
+		Schedule:  r.Schedule,
+		CreatedAt: now,
+	}
+	if r.Schedule == nil {
+		silence.StartsAt = now
+		if r.StartsAt != nil {
+			silence.StartsAt = r.StartsAt.UTC()
+		}
+		silence.EndsAt = r.EndsAt.UTC()
+	}
+	return silence
+}
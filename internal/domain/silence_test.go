@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCreateSilenceRequest_Validate(t *testing.T) {
+	match := SilenceMatcher{Classes: []string{"batch"}}
+	start := time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	nightly := &SilenceSchedule{Cron: "0 2 * * *", Duration: Duration(time.Hour)}
+
+	tests := []struct {
+		name    string
+		req     CreateSilenceRequest
+		wantErr error
+	}{
+		{name: "range", req: CreateSilenceRequest{Match: match, StartsAt: &start, EndsAt: &end}},
+		{name: "until", req: CreateSilenceRequest{Match: match, EndsAt: &end}},
+		{name: "schedule", req: CreateSilenceRequest{Match: match, Schedule: nightly}},
+		{
+			name: "schedule with time zone",
+			req:  CreateSilenceRequest{Match: match, Schedule: &SilenceSchedule{Cron: "CRON_TZ=Europe/Berlin 0 2 * * 1-5", Duration: Duration(time.Hour)}},
+		},
+		{name: "no matcher", req: CreateSilenceRequest{EndsAt: &end}, wantErr: ErrEmptySilenceMatcher},
+		{name: "bad pattern", req: CreateSilenceRequest{Match: SilenceMatcher{DedupKeyPattern: "db-["}, EndsAt: &end}, wantErr: ErrInvalidSilencePattern},
+		{name: "bad severity", req: CreateSilenceRequest{Match: SilenceMatcher{Severities: []Severity{"urgent"}}, EndsAt: &end}, wantErr: ErrInvalidSeverity},
+		{name: "no end", req: CreateSilenceRequest{Match: match, StartsAt: &start}, wantErr: ErrInvalidSilenceRange},
+		{name: "end before start", req: CreateSilenceRequest{Match: match, StartsAt: &end, EndsAt: &start}, wantErr: ErrInvalidSilenceRange},
+		{name: "range and schedule", req: CreateSilenceRequest{Match: match, EndsAt: &end, Schedule: nightly}, wantErr: ErrSilenceRangeSchedule},
+		{
+			name:    "bad cron",
+			req:     CreateSilenceRequest{Match: match, Schedule: &SilenceSchedule{Cron: "0 25 * * *", Duration: Duration(time.Hour)}},
+			wantErr: ErrInvalidSilenceCron,
+		},
+		{
+			name:    "no duration",
+			req:     CreateSilenceRequest{Match: match, Schedule: &SilenceSchedule{Cron: "0 2 * * *"}},
+			wantErr: ErrInvalidSilenceLength,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSilenceMatcher_Matches(t *testing.T) {
+	alert := &Alert{DedupKey: "batch-1", EventManagerID: "em-1", Severity: SeverityLow, Class: "batch"}
+
+	tests := []struct {
+		name  string
+		match SilenceMatcher
+		want  bool
+	}{
+		{name: "event manager", match: SilenceMatcher{EventManagerID: "em-1"}, want: true},
+		{name: "other event manager", match: SilenceMatcher{EventManagerID: "em-2"}},
+		{name: "pattern", match: SilenceMatcher{DedupKeyPattern: "batch-*"}, want: true},
+		{name: "other pattern", match: SilenceMatcher{DedupKeyPattern: "db-*"}},
+		{name: "class and severity", match: SilenceMatcher{Classes: []string{"batch"}, Severities: []Severity{SeverityLow}}, want: true},
+		{name: "class but not severity", match: SilenceMatcher{Classes: []string{"batch"}, Severities: []Severity{SeverityHigh}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.match.Matches(alert); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSilence_State(t *testing.T) {
+	start := time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC)
+	silence := &Silence{StartsAt: start, EndsAt: start.Add(time.Hour)}
+
+	for now, want := range map[time.Time]SilenceState{
+		start.Add(-time.Minute):     SilencePending,
+		start:                       SilenceActive,
+		start.Add(59 * time.Minute): SilenceActive,
+		start.Add(time.Hour):        SilenceExpired,
+	} {
+		if got := silence.State(now); got != want {
+			t.Errorf("State(%s) = %s, want %s", now, got, want)
+		}
+	}
+
+	recurring := &Silence{Schedule: &SilenceSchedule{Cron: "0 2 * * *", Duration: Duration(time.Hour)}}
+	if got := recurring.State(start); got != SilenceRecurring {
+		t.Errorf("recurring State() = %s, want %s", got, SilenceRecurring)
+	}
+}
+
+func TestSilenceSchedule_Occurrences(t *testing.T) {
+	nightly := &SilenceSchedule{Cron: "0 2 * * *", Duration: Duration(time.Hour)}
+	day := func(d, h, m int) time.Time { return time.Date(2026, 3, d, h, m, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		lookahead time.Duration
+		want      []time.Time
+	}{
+		{name: "before the window", now: day(1, 1, 0), lookahead: 30 * time.Minute},
+		{name: "within lookahead", now: day(1, 1, 45), lookahead: 30 * time.Minute, want: []time.Time{day(1, 2, 0)}},
+		{name: "in progress", now: day(1, 2, 30), lookahead: time.Minute, want: []time.Time{day(1, 2, 0)}},
+		{name: "after the window", now: day(1, 3, 0), lookahead: time.Minute},
+		{name: "several days", now: day(1, 2, 30), lookahead: 48 * time.Hour, want: []time.Time{day(1, 2, 0), day(2, 2, 0), day(3, 2, 0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nightly.Occurrences(tt.now, tt.lookahead)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("Occurrences()[%d] = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
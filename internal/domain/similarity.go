@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultRelatedMinScore is the similarity score below which an alert is not
+// considered related.
+const DefaultRelatedMinScore = 0.3
+
+// Weights of the parts of the similarity of two alerts, summing to 1.
+const (
+	similarityClassWeight   = 0.3
+	similaritySummaryWeight = 0.5
+	similarityKeyWeight     = 0.2
+)
+
+// summaryStopWords are left out of summary tokens, since they say nothing
+// about the problem.
+var summaryStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "at": true, "for": true, "in": true,
+	"is": true, "of": true, "on": true, "or": true, "the": true, "to": true,
+}
+
+// RelatedAlert is an alert similar to another one, e.g. a prior occurrence
+// of the same problem whose resolution may help responders.
+type RelatedAlert struct {
+	Alert *Alert `json:"alert"`
+
+	// Score is the similarity, from 0 (nothing in common) to 1.
+	Score float64 `json:"score"`
+
+	// Reasons name what the alerts have in common: "class", "summary" and
+	// "resource" (shared parts of the dedup keys, such as host names).
+	Reasons []string `json:"reasons"`
+}
+
+// Similarity scores how similar two alerts are: a shared class, the overlap
+// of the words of their summaries and the overlap of the parts of their
+// dedup keys, which usually name the affected hosts or resources.
+func Similarity(a, b *Alert) (float64, []string) {
+	var score float64
+	var reasons []string
+
+	if a.Class != "" && strings.EqualFold(a.Class, b.Class) {
+		score += similarityClassWeight
+		reasons = append(reasons, "class")
+	}
+	if overlap := jaccard(tokens(a.Summary), tokens(b.Summary)); overlap > 0 {
+		score += similaritySummaryWeight * overlap
+		reasons = append(reasons, "summary")
+	}
+	if overlap := jaccard(tokens(alertKey(a)), tokens(alertKey(b))); overlap > 0 {
+		score += similarityKeyWeight * overlap
+		reasons = append(reasons, "resource")
+	}
+	return score, reasons
+}
+
+// RelatedAlerts returns the candidates scoring at least minScore against the
+// alert, most similar first and at most limit. The alert itself and its own
+// children are skipped.
+func RelatedAlerts(alert *Alert, candidates []*Alert, minScore float64, limit int) []RelatedAlert {
+	related := []RelatedAlert{}
+	for _, candidate := range candidates {
+		if candidate.DedupKey == alert.DedupKey || candidate.ParentDedupKey == alert.DedupKey {
+			continue
+		}
+		score, reasons := Similarity(alert, candidate)
+		if score < minScore {
+			continue
+		}
+		related = append(related, RelatedAlert{Alert: candidate, Score: score, Reasons: reasons})
+	}
+
+	// Ties go to the most recent occurrence
+	sort.SliceStable(related, func(i, j int) bool {
+		if related[i].Score != related[j].Score {
+			return related[i].Score > related[j].Score
+		}
+		return related[i].Alert.CreatedAt.After(related[j].Alert.CreatedAt)
+	})
+	if limit > 0 && len(related) > limit {
+		related = related[:limit]
+	}
+	return related
+}
+
+// alertKey returns the dedup key of an alert as received, before hashing.
+func alertKey(alert *Alert) string {
+	if alert.OriginalDedupKey != "" {
+		return alert.OriginalDedupKey
+	}
+	return alert.DedupKey
+}
+
+// tokens splits text into its lowercase words, without stop words. Dots,
+// dashes and underscores don't split words, so host names stay whole.
+func tokens(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '-' && r != '_'
+	})
+
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		word = strings.Trim(word, ".-_")
+		if word != "" && !summaryStopWords[word] {
+			set[word] = true
+		}
+	}
+	return set
+}
+
+// jaccard returns the share of the words of a and b that both have.
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
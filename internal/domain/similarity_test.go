@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSimilarity(t *testing.T) {
+	base := &Alert{DedupKey: "disk-full:db-1.prod", Summary: "Disk full on db-1.prod", Class: "storage"}
+
+	tests := []struct {
+		name        string
+		other       *Alert
+		wantScore   float64
+		wantReasons []string
+	}{
+		{
+			name:        "same problem on the same host",
+			other:       &Alert{DedupKey: "disk-full:db-1.prod", Summary: "Disk full on db-1.prod", Class: "Storage"},
+			wantScore:   1,
+			wantReasons: []string{"class", "summary", "resource"},
+		},
+		{
+			name:        "same problem on another host",
+			other:       &Alert{DedupKey: "disk-full:db-2.prod", Summary: "Disk full on db-2.prod", Class: "storage"},
+			wantScore:   0.3 + 0.5*2/4 + 0.2*1/3,
+			wantReasons: []string{"class", "summary", "resource"},
+		},
+		{
+			name:        "hashed key matched by its original",
+			other:       &Alert{DedupKey: "5f1c", OriginalDedupKey: "cpu:db-1.prod", Summary: "CPU high", Class: "compute"},
+			wantScore:   0.2 * 1 / 3,
+			wantReasons: []string{"resource"},
+		},
+		{
+			name:      "unrelated",
+			other:     &Alert{DedupKey: "latency", Summary: "Checkout latency", Class: "web"},
+			wantScore: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reasons := Similarity(base, tt.other)
+			if math.Abs(score-tt.wantScore) > 1e-9 {
+				t.Errorf("score = %v, want %v", score, tt.wantScore)
+			}
+			if len(reasons) != len(tt.wantReasons) {
+				t.Fatalf("reasons = %v, want %v", reasons, tt.wantReasons)
+			}
+			for i := range reasons {
+				if reasons[i] != tt.wantReasons[i] {
+					t.Errorf("reasons = %v, want %v", reasons, tt.wantReasons)
+				}
+			}
+		})
+	}
+}
+
+func TestRelatedAlerts(t *testing.T) {
+	now := time.Now()
+	alert := &Alert{DedupKey: "disk:db-1", Summary: "Disk full on db-1", Class: "storage", Type: AlertTypeParent}
+	candidates := []*Alert{
+		alert,
+		{DedupKey: "child", ParentDedupKey: "disk:db-1", Summary: "Disk full on db-1", Class: "storage"},
+		{DedupKey: "disk:db-2", Summary: "Disk full on db-2", Class: "storage", CreatedAt: now.Add(-2 * time.Hour)},
+		{DedupKey: "disk:db-3", Summary: "Disk full on db-3", Class: "storage", CreatedAt: now.Add(-time.Hour)},
+		{DedupKey: "disk:db-1:old", Summary: "Disk full on db-1", Class: "storage", CreatedAt: now.Add(-72 * time.Hour)},
+		{DedupKey: "latency", Summary: "Checkout latency", Class: "web"},
+	}
+
+	related := RelatedAlerts(alert, candidates, DefaultRelatedMinScore, 2)
+	if len(related) != 2 {
+		t.Fatalf("related = %d alerts, want 2", len(related))
+	}
+	if related[0].Alert.DedupKey != "disk:db-1:old" {
+		t.Errorf("most related = %s, want disk:db-1:old", related[0].Alert.DedupKey)
+	}
+	// db-2 and db-3 tie; the more recent comes first
+	if related[1].Alert.DedupKey != "disk:db-3" {
+		t.Errorf("second related = %s, want disk:db-3", related[1].Alert.DedupKey)
+	}
+
+	if got := RelatedAlerts(alert, candidates[:2], DefaultRelatedMinScore, 10); len(got) != 0 {
+		t.Errorf("related = %d alerts, want the alert and its children skipped", len(got))
+	}
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// ErrInvalidStorm is returned for a storm policy with a negative quiet period.
+var ErrInvalidStorm = invalidError("storm.quiet_period must not be negative")
+
+const (
+	// StormClass is the class of storm alerts.
+	StormClass = "argus-storm"
+
+	// stormDedupKeyPrefix starts the dedup keys of storm alerts.
+	stormDedupKeyPrefix = "argus-storm/"
+)
+
+// StormDedupKey returns the dedup key of the storm alert of an event manager.
+func StormDedupKey(eventManagerID string) string {
+	return stormDedupKeyPrefix + eventManagerID
+}
+
+// IsStormDedupKey returns true if the dedup key is that of a storm alert.
+func IsStormDedupKey(dedupKey string) bool {
+	return strings.HasPrefix(dedupKey, stormDedupKeyPrefix)
+}
+
+// StormPolicy overrides the storm detection settings of the processor for an
+// event manager. An event manager is in a storm while its events arrive
+// faster than EventsPerSecond: its new alerts are then grouped under a single
+// storm alert, notified once, until the rate stays at or below the threshold
+// for QuietPeriod.
+type StormPolicy struct {
+	// EventsPerSecond is the event rate above which a storm starts. Zero
+	// uses the configured default; a negative rate disables detection.
+	EventsPerSecond float64 `json:"events_per_second,omitempty" yaml:"events_per_second,omitempty"`
+
+	// QuietPeriod is how long the rate must stay at or below the threshold
+	// for a storm to end. Zero uses the configured default.
+	QuietPeriod Duration `json:"quiet_period,omitempty" yaml:"quiet_period,omitempty"`
+}
+
+// Threshold returns the event rate above which a storm starts, given the
+// configured default, or zero if storms are not detected.
+func (p *StormPolicy) Threshold(defaultRate float64) float64 {
+	switch {
+	case p.EventsPerSecond < 0:
+		return 0
+	case p.EventsPerSecond > 0:
+		return p.EventsPerSecond
+	}
+	return defaultRate
+}
+
+// Quiet returns how long the rate must stay at or below the threshold for a
+// storm to end, given the configured default.
+func (p *StormPolicy) Quiet(defaultPeriod time.Duration) time.Duration {
+	if p.QuietPeriod > 0 {
+		return time.Duration(p.QuietPeriod)
+	}
+	return defaultPeriod
+}
+
+// Validate checks the quiet period is not negative.
+func (p *StormPolicy) Validate() error {
+	if p.QuietPeriod < 0 {
+		return ErrInvalidStorm
+	}
+	return nil
+}
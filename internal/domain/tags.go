@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"fmt"
+	"maps"
+)
+
+// Limits on the tags of an event.
+const (
+	MaxEventTags      = 32
+	MaxTagKeyLength   = 64
+	MaxTagValueLength = 256
+)
+
+// Validation errors for event tags.
+var (
+	ErrTooManyTags      = invalidError(fmt.Sprintf("an event can carry at most %d tags", MaxEventTags))
+	ErrEmptyTagKey      = invalidError("tag keys must not be empty")
+	ErrTagKeyTooLong    = invalidError(fmt.Sprintf("tag keys must be at most %d characters", MaxTagKeyLength))
+	ErrTagValueTooLong  = invalidError(fmt.Sprintf("tag values must be at most %d characters", MaxTagValueLength))
+	ErrInvalidTagPolicy = invalidError("tag_policy must be 'add' or 'replace'")
+)
+
+// TagPolicy decides how the tags of a trigger event are merged onto an alert
+// that already has them.
+type TagPolicy string
+
+const (
+	// TagPolicyAdd adds the tags the alert doesn't have yet, keeping the
+	// values of those it has. It is the default.
+	TagPolicyAdd TagPolicy = "add"
+	// TagPolicyReplace sets every tag of the event, replacing the values
+	// the alert has.
+	TagPolicyReplace TagPolicy = "replace"
+)
+
+// IsValid reports whether the policy is empty or a known policy.
+func (p TagPolicy) IsValid() bool {
+	switch p {
+	case "", TagPolicyAdd, TagPolicyReplace:
+		return true
+	}
+	return false
+}
+
+// Replaces reports whether the policy replaces the values of existing tags.
+func (p TagPolicy) Replaces() bool {
+	return p == TagPolicyReplace
+}
+
+// validateTags checks the tags of an event against the limits.
+func validateTags(tags map[string]string) error {
+	if len(tags) > MaxEventTags {
+		return ErrTooManyTags
+	}
+	for key, value := range tags {
+		if key == "" {
+			return ErrEmptyTagKey
+		}
+		if len(key) > MaxTagKeyLength {
+			return ErrTagKeyTooLong
+		}
+		if len(value) > MaxTagValueLength {
+			return ErrTagValueTooLong
+		}
+	}
+	return nil
+}
+
+// MergeTags merges tags onto the tags of an alert, returning the result
+// without modifying either. With replace the values of tags replace those
+// of the alert; otherwise the alert keeps them.
+func MergeTags(alertTags, tags map[string]string, replace bool) map[string]string {
+	if len(tags) == 0 {
+		return alertTags
+	}
+	merged := make(map[string]string, len(alertTags)+len(tags))
+	maps.Copy(merged, alertTags)
+	for key, value := range tags {
+		if _, exists := merged[key]; exists && !replace {
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
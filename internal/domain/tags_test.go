@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestMergeTags(t *testing.T) {
+	alertTags := map[string]string{"host": "db-1", "region": "eu"}
+	tags := map[string]string{"region": "us", "pod": "api-7"}
+
+	added := MergeTags(alertTags, tags, false)
+	if want := map[string]string{"host": "db-1", "region": "eu", "pod": "api-7"}; !maps.Equal(added, want) {
+		t.Errorf("MergeTags(add) = %v, want %v", added, want)
+	}
+	replaced := MergeTags(alertTags, tags, true)
+	if want := map[string]string{"host": "db-1", "region": "us", "pod": "api-7"}; !maps.Equal(replaced, want) {
+		t.Errorf("MergeTags(replace) = %v, want %v", replaced, want)
+	}
+	if alertTags["region"] != "eu" || len(alertTags) != 2 {
+		t.Errorf("MergeTags modified the alert tags: %v", alertTags)
+	}
+}
+
+func TestEvent_ValidateTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags map[string]string
+		want error
+	}{
+		{"valid", map[string]string{"host": "db-1"}, nil},
+		{"empty key", map[string]string{"": "db-1"}, ErrEmptyTagKey},
+		{"long key", map[string]string{strings.Repeat("k", MaxTagKeyLength+1): "v"}, ErrTagKeyTooLong},
+		{"long value", map[string]string{"k": strings.Repeat("v", MaxTagValueLength+1)}, ErrTagValueTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := Event{EventManagerID: "em-1", Summary: "Disk full", Action: ActionTrigger, DedupKey: "disk-1", Tags: tt.tags}
+			if err := event.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	many := make(map[string]string)
+	for i := range MaxEventTags + 1 {
+		many[strings.Repeat("k", i+1)] = "v"
+	}
+	event := Event{EventManagerID: "em-1", Summary: "Disk full", Action: ActionTrigger, DedupKey: "disk-1", Tags: many}
+	if err := event.Validate(); !errors.Is(err, ErrTooManyTags) {
+		t.Errorf("Validate() with %d tags = %v, want ErrTooManyTags", len(many), err)
+	}
+}
@@ -0,0 +1,88 @@
+package domain
+
+import "time"
+
+// Tags marking a synthetic test alert, and when it expires.
+const (
+	TestAlertTag        = "argus_test"
+	TestAlertExpiresTag = "argus_test_expires_at"
+)
+
+// TestAlertSummary is the summary of test alerts, which also finds them.
+const TestAlertSummary = "[TEST] ArgusGo synthetic test alert"
+
+// ErrTestAlertsDisabled is returned when test alerts are requested while
+// they are disabled.
+var ErrTestAlertsDisabled = conflictError("test alerts are disabled")
+
+// TestAlertRequest is the optional body of a request for a test alert.
+type TestAlertRequest struct {
+	// Severity defaults to the default severity.
+	Severity Severity `json:"severity,omitempty"`
+
+	// TTL is how long the alert stays active; it defaults to the
+	// configured TTL.
+	TTL Duration `json:"ttl,omitempty"`
+}
+
+// TestAlert describes a synthetic test alert raised for an event manager.
+type TestAlert struct {
+	EventManagerID string `json:"event_manager_id"`
+
+	// DedupKey identifies the alert once it is processed.
+	DedupKey string `json:"dedupKey"`
+
+	// ExpiresAt is when the alert is resolved and then deleted.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Event returns the trigger event raising the test alert, marked with
+// TestAlertTag in its tags, class and source.
+func (t *TestAlert) Event(severity Severity) *Event {
+	return &Event{
+		EventManagerID: t.EventManagerID,
+		Summary:        TestAlertSummary,
+		Severity:       severity,
+		Action:         ActionTrigger,
+		Class:          TestAlertTag,
+		DedupKey:       t.DedupKey,
+		Source:         TestAlertTag,
+		Tags: map[string]string{
+			TestAlertTag:        "true",
+			TestAlertExpiresTag: t.ExpiresAt.UTC().Format(time.RFC3339),
+		},
+	}
+}
+
+// TestAlertExpiredEvent returns the resolve event of an expired test
+// alert. It is marked like the trigger, so the alert is resolved as expired
+// rather than by its source.
+func TestAlertExpiredEvent(alert *Alert) *Event {
+	return &Event{
+		EventManagerID: alert.EventManagerID,
+		Summary:        "test alert expired",
+		Action:         ActionResolve,
+		DedupKey:       alert.DedupKey,
+		Source:         TestAlertTag,
+		Tags:           map[string]string{TestAlertTag: "true"},
+	}
+}
+
+// IsTestAlert returns true if the event raises a test alert. Test alerts are
+// never grouped, so a real alert never becomes the child of one.
+func (e *Event) IsTestAlert() bool {
+	return e.Tags[TestAlertTag] == "true"
+}
+
+// TestAlertExpiry returns when a test alert expires, and false if the alert
+// is not a test alert.
+func TestAlertExpiry(alert *Alert) (time.Time, bool) {
+	if alert.Tags[TestAlertTag] != "true" {
+		return time.Time{}, false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, alert.Tags[TestAlertExpiresTag])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return expiresAt, true
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// Usage is what an event manager used on a single UTC day, for chargeback
+// and capacity planning. Totals over a range leave Day empty.
+type Usage struct {
+	EventManagerID    string `json:"event_manager_id"`
+	Day               string `json:"day,omitempty"` // YYYY-MM-DD
+	EventsIngested    int64  `json:"events_ingested"`
+	AlertsCreated     int64  `json:"alerts_created"`
+	NotificationsSent int64  `json:"notifications_sent"`
+}
+
+// Add adds the counts of other to u.
+func (u *Usage) Add(other *Usage) {
+	u.EventsIngested += other.EventsIngested
+	u.AlertsCreated += other.AlertsCreated
+	u.NotificationsSent += other.NotificationsSent
+}
+
+// IsZero reports whether nothing was used.
+func (u *Usage) IsZero() bool {
+	return u.EventsIngested == 0 && u.AlertsCreated == 0 && u.NotificationsSent == 0
+}
+
+// UsageReport is the usage of event managers over a range of days.
+type UsageReport struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	// ByDay is the usage per event manager per day, ordered by event
+	// manager and day.
+	ByDay []*Usage `json:"by_day"`
+
+	// Totals is the usage per event manager over the whole range.
+	Totals []*Usage `json:"totals"`
+}
+
+// UsageDays returns the first and last UTC day, as YYYY-MM-DD, of the usage
+// within the range of a filter: every day overlapping [From, To).
+func UsageDays(filter ReportFilter) (first, last string) {
+	return ReportDay(filter.From), ReportDay(filter.To.Add(-time.Nanosecond))
+}
+
+// BuildUsageReport sums the daily usage of a range per event manager.
+func BuildUsageReport(byDay []*Usage, filter ReportFilter) *UsageReport {
+	totals := make(map[string]*Usage)
+	for _, u := range byDay {
+		total, ok := totals[u.EventManagerID]
+		if !ok {
+			total = &Usage{EventManagerID: u.EventManagerID}
+			totals[u.EventManagerID] = total
+		}
+		total.Add(u)
+	}
+
+	report := &UsageReport{
+		From:   filter.From,
+		To:     filter.To,
+		ByDay:  byDay,
+		Totals: make([]*Usage, 0, len(totals)),
+	}
+	if report.ByDay == nil {
+		report.ByDay = []*Usage{}
+	}
+	for _, total := range totals {
+		report.Totals = append(report.Totals, total)
+	}
+	sort.Slice(report.Totals, func(i, j int) bool {
+		return report.Totals[i].EventManagerID < report.Totals[j].EventManagerID
+	})
+	return report
+}
@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"path"
+	"slices"
+	"time"
+)
+
+// Validation errors for Watch.
+var (
+	ErrWatchNotFound           = notFoundError("watch not found")
+	ErrEmptyWatchUser          = invalidError("the user of a watch is required")
+	ErrInvalidWatchChannel     = invalidError("channel.type must be email or slack")
+	ErrEmptyWatchAddress       = invalidError("channel.address is required")
+	ErrEmptyWatch              = invalidError("a watch needs dedup_keys, dedup_key_pattern or a filter")
+	ErrInvalidWatchPattern     = invalidError("dedup_key_pattern is not a valid pattern")
+	ErrInvalidWatchKind        = invalidError("kinds lists an unknown notification kind")
+	ErrUnavailableWatchChannel = invalidError("no plugin delivers notifications to this channel type")
+)
+
+// WatchChannelType is a personal channel watch notifications are sent to.
+type WatchChannelType string
+
+const (
+	// WatchChannelEmail sends email to an address.
+	WatchChannelEmail WatchChannelType = "email"
+	// WatchChannelSlack sends Slack direct messages to a user ID.
+	WatchChannelSlack WatchChannelType = "slack"
+)
+
+// WatchChannel is where the notifications of a watch are sent.
+type WatchChannel struct {
+	// Type is the kind of channel.
+	Type WatchChannelType `json:"type"`
+
+	// Address is the recipient on the channel: an email address or a Slack
+	// user ID.
+	Address string `json:"address"`
+}
+
+// WatchFilter selects alerts by event manager, severity and class. An empty
+// list matches any value.
+type WatchFilter struct {
+	// EventManagerID selects the alerts of one event manager.
+	EventManagerID string `json:"event_manager_id,omitempty"`
+
+	// Severities lists the alert severities to match.
+	Severities []Severity `json:"severities,omitempty"`
+
+	// Classes lists the alert classes to match.
+	Classes []string `json:"classes,omitempty"`
+}
+
+// IsEmpty returns true if the filter has no conditions.
+func (f *WatchFilter) IsEmpty() bool {
+	return f.EventManagerID == "" && len(f.Severities) == 0 && len(f.Classes) == 0
+}
+
+// Matches returns true if the alert satisfies every condition of the filter.
+func (f *WatchFilter) Matches(alert *Alert) bool {
+	if f.EventManagerID != "" && f.EventManagerID != alert.EventManagerID {
+		return false
+	}
+	if len(f.Severities) > 0 && !slices.Contains(f.Severities, alert.Severity) {
+		return false
+	}
+	return len(f.Classes) == 0 || containsString(f.Classes, alert.Class)
+}
+
+// Watch is a personal subscription of a user to alert changes, delivered to
+// their own channel independently of the notification config of event
+// managers. A watch selects alerts by dedup key, dedup key pattern or
+// filter; an alert must satisfy every condition set.
+type Watch struct {
+	// ID is the unique identifier for this watch.
+	ID string `json:"id"`
+
+	// User is who created the watch; only they can delete it.
+	User string `json:"user"`
+
+	// Channel is where notifications are sent.
+	Channel WatchChannel `json:"channel"`
+
+	// DedupKeys lists the alerts to watch. Watching a parent alert includes
+	// the children grouped under it.
+	DedupKeys []string `json:"dedup_keys,omitempty"`
+
+	// DedupKeyPattern selects alerts by a glob over their dedup key, e.g.
+	// "db-*".
+	DedupKeyPattern string `json:"dedup_key_pattern,omitempty"`
+
+	// Filter selects alerts by their fields.
+	Filter WatchFilter `json:"filter"`
+
+	// Kinds lists the alert changes to notify. Empty notifies every change.
+	Kinds []NotificationKind `json:"kinds,omitempty"`
+
+	// CreatedAt is when the watch was created.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Matches returns true if a change of the given kind to the alert is
+// notified by the watch.
+func (w *Watch) Matches(alert *Alert, kind NotificationKind) bool {
+	if len(w.Kinds) > 0 && !slices.Contains(w.Kinds, kind) {
+		return false
+	}
+	if len(w.DedupKeys) > 0 &&
+		!containsString(w.DedupKeys, alert.DedupKey) &&
+		(alert.ParentDedupKey == "" || !containsString(w.DedupKeys, alert.ParentDedupKey)) {
+		return false
+	}
+	if w.DedupKeyPattern != "" {
+		if ok, _ := path.Match(w.DedupKeyPattern, alert.DedupKey); !ok {
+			return false
+		}
+	}
+	return w.Filter.Matches(alert)
+}
+
+// CreateWatchRequest represents the input for creating a new watch.
+type CreateWatchRequest struct {
+	Channel         WatchChannel       `json:"channel"`
+	DedupKeys       []string           `json:"dedup_keys"`
+	DedupKeyPattern string             `json:"dedup_key_pattern"`
+	Filter          WatchFilter        `json:"filter"`
+	Kinds           []NotificationKind `json:"kinds"`
+}
+
+// Validate checks the request selects alerts and has a valid channel.
+func (r *CreateWatchRequest) Validate() error {
+	switch r.Channel.Type {
+	case WatchChannelEmail, WatchChannelSlack:
+	default:
+		return ErrInvalidWatchChannel
+	}
+	if r.Channel.Address == "" {
+		return ErrEmptyWatchAddress
+	}
+	if len(r.DedupKeys) == 0 && r.DedupKeyPattern == "" && r.Filter.IsEmpty() {
+		return ErrEmptyWatch
+	}
+	if _, err := path.Match(r.DedupKeyPattern, ""); err != nil {
+		return ErrInvalidWatchPattern
+	}
+	for _, severity := range r.Filter.Severities {
+		if !severity.IsValid() {
+			return ErrInvalidSeverity
+		}
+	}
+	for _, kind := range r.Kinds {
+		if !kind.IsValid() {
+			return ErrInvalidWatchKind
+		}
+	}
+	return nil
+}
+
+// ToWatch converts the request to a Watch of user.
+func (r *CreateWatchRequest) ToWatch(id, user string) *Watch {
+	return &Watch{
+		ID:              id,
+		User:            user,
+		Channel:         r.Channel,
+		DedupKeys:       r.DedupKeys,
+		DedupKeyPattern: r.DedupKeyPattern,
+		Filter:          r.Filter,
+		Kinds:           r.Kinds,
+		CreatedAt:       time.Now().UTC(),
+	}
+}
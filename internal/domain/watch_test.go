@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCreateWatchRequest_Validate(t *testing.T) {
+	email := WatchChannel{Type: WatchChannelEmail, Address: "oncall@example.com"}
+	tests := []struct {
+		name    string
+		req     CreateWatchRequest
+		wantErr error
+	}{
+		{name: "dedup keys", req: CreateWatchRequest{Channel: email, DedupKeys: []string{"db-1"}}},
+		{name: "pattern", req: CreateWatchRequest{Channel: WatchChannel{Type: WatchChannelSlack, Address: "U123"}, DedupKeyPattern: "db-*"}},
+		{name: "filter", req: CreateWatchRequest{Channel: email, Filter: WatchFilter{Severities: []Severity{SeverityHigh}}}},
+		{
+			name:    "unknown channel",
+			req:     CreateWatchRequest{Channel: WatchChannel{Type: "sms", Address: "+15550100"}, DedupKeys: []string{"db-1"}},
+			wantErr: ErrInvalidWatchChannel,
+		},
+		{
+			name:    "missing address",
+			req:     CreateWatchRequest{Channel: WatchChannel{Type: WatchChannelEmail}, DedupKeys: []string{"db-1"}},
+			wantErr: ErrEmptyWatchAddress,
+		},
+		{name: "nothing watched", req: CreateWatchRequest{Channel: email}, wantErr: ErrEmptyWatch},
+		{name: "bad pattern", req: CreateWatchRequest{Channel: email, DedupKeyPattern: "db-["}, wantErr: ErrInvalidWatchPattern},
+		{
+			name:    "bad severity",
+			req:     CreateWatchRequest{Channel: email, Filter: WatchFilter{Severities: []Severity{"urgent"}}},
+			wantErr: ErrInvalidSeverity,
+		},
+		{
+			name:    "bad kind",
+			req:     CreateWatchRequest{Channel: email, DedupKeys: []string{"db-1"}, Kinds: []NotificationKind{"deleted"}},
+			wantErr: ErrInvalidWatchKind,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWatch_Matches(t *testing.T) {
+	parent := &Alert{DedupKey: "db-1", EventManagerID: "em-1", Severity: SeverityHigh, Class: "database"}
+	child := &Alert{DedupKey: "db-2", ParentDedupKey: "db-1", EventManagerID: "em-1", Severity: SeverityLow, Class: "database"}
+	other := &Alert{DedupKey: "web-1", EventManagerID: "em-2", Severity: SeverityHigh, Class: "http"}
+
+	tests := []struct {
+		name  string
+		watch Watch
+		alert *Alert
+		kind  NotificationKind
+		want  bool
+	}{
+		{name: "dedup key", watch: Watch{DedupKeys: []string{"db-1"}}, alert: parent, kind: NotificationNewParent, want: true},
+		{name: "child of a watched parent", watch: Watch{DedupKeys: []string{"db-1"}}, alert: child, kind: NotificationChildAdded, want: true},
+		{name: "other dedup key", watch: Watch{DedupKeys: []string{"db-1"}}, alert: other, kind: NotificationNewParent},
+		{name: "pattern", watch: Watch{DedupKeyPattern: "db-*"}, alert: child, kind: NotificationChildAdded, want: true},
+		{name: "pattern mismatch", watch: Watch{DedupKeyPattern: "db-*"}, alert: other, kind: NotificationNewParent},
+		{name: "filter", watch: Watch{Filter: WatchFilter{EventManagerID: "em-1", Severities: []Severity{SeverityHigh}}}, alert: parent, kind: NotificationResolved, want: true},
+		{name: "filter severity mismatch", watch: Watch{Filter: WatchFilter{EventManagerID: "em-1", Severities: []Severity{SeverityHigh}}}, alert: child, kind: NotificationChildAdded},
+		{name: "filter class mismatch", watch: Watch{Filter: WatchFilter{Classes: []string{"database"}}}, alert: other, kind: NotificationNewParent},
+		{name: "selected kind", watch: Watch{DedupKeys: []string{"db-1"}, Kinds: []NotificationKind{NotificationResolved}}, alert: parent, kind: NotificationResolved, want: true},
+		{name: "unselected kind", watch: Watch{DedupKeys: []string{"db-1"}, Kinds: []NotificationKind{NotificationResolved}}, alert: parent, kind: NotificationReminder},
+		{name: "every condition", watch: Watch{DedupKeyPattern: "db-*", Filter: WatchFilter{Classes: []string{"http"}}}, alert: parent, kind: NotificationNewParent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.watch.Matches(tt.alert, tt.kind); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// WebhookSourceTransform is the webhook source of POST /v1/webhooks, whose
+// bodies are mapped by the webhook transform of the event manager, e.g.
+// Alertmanager and Grafana webhooks.
+const WebhookSourceTransform = "webhook"
+
+// DefaultWebhookSignatureHeader is the header carrying the HMAC signature of
+// webhook sources without a signature scheme of their own.
+const DefaultWebhookSignatureHeader = "X-Signature"
+
+// Errors of webhook signatures.
+var (
+	ErrInvalidWebhookSignature       = errors.New("invalid webhook signature")
+	ErrUnknownWebhookSignatureSource = invalidError("webhook_signatures: unknown webhook source")
+	ErrEmptyWebhookSecret            = invalidError("webhook_signatures: secret is required")
+	ErrWebhookSignatureHeader        = invalidError("webhook_signatures: header can't be set for integrations with a signature scheme of their own")
+)
+
+// nativeSignatures verify the webhooks of integrations signing them in a
+// scheme of their own, given the secret, request headers and body.
+var nativeSignatures = map[string]func(secret string, header func(string) string, body []byte) bool{
+	// GitHub signs the body with HMAC-SHA256 in X-Hub-Signature-256, and
+	// with HMAC-SHA1 in X-Hub-Signature for older hooks.
+	IntegrationGitHub: func(secret string, header func(string) string, body []byte) bool {
+		if signature := header("X-Hub-Signature-256"); signature != "" {
+			return validHMAC(sha256.New, secret, body, strings.TrimPrefix(signature, "sha256="))
+		}
+		signature, ok := strings.CutPrefix(header("X-Hub-Signature"), "sha1=")
+		return ok && validHMAC(sha1.New, secret, body, signature)
+	},
+	// GitLab sends the secret token itself in X-Gitlab-Token.
+	IntegrationGitLab: func(secret string, header func(string) string, body []byte) bool {
+		return subtle.ConstantTimeCompare([]byte(header("X-Gitlab-Token")), []byte(secret)) == 1
+	},
+	// Sentry signs the body with HMAC-SHA256 in Sentry-Hook-Signature.
+	IntegrationSentry: func(secret string, header func(string) string, body []byte) bool {
+		return validHMAC(sha256.New, secret, body, header("Sentry-Hook-Signature"))
+	},
+}
+
+// WebhookSignature is the secret the webhooks of one source are signed with.
+type WebhookSignature struct {
+	// Secret is the secret shared with the sender.
+	Secret string `json:"secret" yaml:"secret"`
+
+	// Header names the header carrying the hex HMAC-SHA256 of the body,
+	// optionally prefixed with "sha256=", for sources without a signature
+	// scheme of their own. It defaults to DefaultWebhookSignatureHeader;
+	// Grafana sends X-Grafana-Alerting-Signature.
+	Header string `json:"header,omitempty" yaml:"header,omitempty"`
+}
+
+// WebhookSignatures holds the secrets of inbound webhooks by source: an
+// integration of POST /v1/integrations, or WebhookSourceTransform. The
+// webhooks of a source with a secret are rejected unless they are signed
+// with it; those of other sources are accepted unsigned.
+type WebhookSignatures map[string]WebhookSignature
+
+// Validate checks that every source is known and has a secret.
+func (s WebhookSignatures) Validate() error {
+	for source, signature := range s {
+		_, integration := integrationParsers[source]
+		if !integration && source != WebhookSourceTransform {
+			return fmt.Errorf("%w %q", ErrUnknownWebhookSignatureSource, source)
+		}
+		if signature.Secret == "" {
+			return fmt.Errorf("%w for %s", ErrEmptyWebhookSecret, source)
+		}
+		if _, native := nativeSignatures[source]; native && signature.Header != "" {
+			return fmt.Errorf("%w (%s)", ErrWebhookSignatureHeader, source)
+		}
+	}
+	return nil
+}
+
+// Verify checks the signature of a webhook of source. header returns the
+// value of a request header. It returns ErrInvalidWebhookSignature if the
+// source has a secret and the webhook isn't signed with it.
+func (s WebhookSignatures) Verify(source string, header func(string) string, body []byte) error {
+	signature, ok := s[source]
+	if !ok {
+		return nil
+	}
+
+	if verify, native := nativeSignatures[source]; native {
+		if !verify(signature.Secret, header, body) {
+			return ErrInvalidWebhookSignature
+		}
+		return nil
+	}
+
+	name := signature.Header
+	if name == "" {
+		name = DefaultWebhookSignatureHeader
+	}
+	if !validHMAC(sha256.New, signature.Secret, body, strings.TrimPrefix(header(name), "sha256=")) {
+		return ErrInvalidWebhookSignature
+	}
+	return nil
+}
+
+// validHMAC reports whether signature is the hex HMAC of body with secret.
+func validHMAC(h func() hash.Hash, secret string, body []byte, signature string) bool {
+	got, err := hex.DecodeString(signature)
+	if err != nil || len(got) == 0 {
+		return false
+	}
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
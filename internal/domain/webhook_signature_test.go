@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"testing"
+)
+
+func sign(h func() hash.Hash, secret string, body []byte) string {
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookSignatures_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		signatures WebhookSignatures
+		want       error
+	}{
+		{"none", nil, nil},
+		{"integrations and transform", WebhookSignatures{
+			IntegrationGitHub:      {Secret: "s"},
+			IntegrationRollbar:     {Secret: "s", Header: "X-Rollbar-Signature"},
+			WebhookSourceTransform: {Secret: "s", Header: "X-Grafana-Alerting-Signature"},
+		}, nil},
+		{"unknown source", WebhookSignatures{"pagerduty": {Secret: "s"}}, ErrUnknownWebhookSignatureSource},
+		{"no secret", WebhookSignatures{WebhookSourceTransform: {}}, ErrEmptyWebhookSecret},
+		{"header of native scheme", WebhookSignatures{IntegrationGitLab: {Secret: "s", Header: "X-Token"}}, ErrWebhookSignatureHeader},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.signatures.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookSignatures_Verify(t *testing.T) {
+	const secret = "s3cret"
+	body := []byte(`{"action":"completed"}`)
+	signatures := WebhookSignatures{
+		IntegrationGitHub:      {Secret: secret},
+		IntegrationGitLab:      {Secret: secret},
+		IntegrationSentry:      {Secret: secret},
+		IntegrationAzure:       {Secret: secret},
+		WebhookSourceTransform: {Secret: secret, Header: "X-Grafana-Alerting-Signature"},
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		headers map[string]string
+		want    error
+	}{
+		{"github sha256", IntegrationGitHub, map[string]string{"X-Hub-Signature-256": "sha256=" + sign(sha256.New, secret, body)}, nil},
+		{"github sha1", IntegrationGitHub, map[string]string{"X-Hub-Signature": "sha1=" + sign(sha1.New, secret, body)}, nil},
+		{"github wrong secret", IntegrationGitHub, map[string]string{"X-Hub-Signature-256": "sha256=" + sign(sha256.New, "other", body)}, ErrInvalidWebhookSignature},
+		{"github unsigned", IntegrationGitHub, nil, ErrInvalidWebhookSignature},
+		{"gitlab token", IntegrationGitLab, map[string]string{"X-Gitlab-Token": secret}, nil},
+		{"gitlab wrong token", IntegrationGitLab, map[string]string{"X-Gitlab-Token": "other"}, ErrInvalidWebhookSignature},
+		{"sentry", IntegrationSentry, map[string]string{"Sentry-Hook-Signature": sign(sha256.New, secret, body)}, nil},
+		{"default header", IntegrationAzure, map[string]string{DefaultWebhookSignatureHeader: sign(sha256.New, secret, body)}, nil},
+		{"custom header", WebhookSourceTransform, map[string]string{"X-Grafana-Alerting-Signature": "sha256=" + sign(sha256.New, secret, body)}, nil},
+		{"custom header missing", WebhookSourceTransform, map[string]string{DefaultWebhookSignatureHeader: sign(sha256.New, secret, body)}, ErrInvalidWebhookSignature},
+		{"not hex", WebhookSourceTransform, map[string]string{"X-Grafana-Alerting-Signature": "zz"}, ErrInvalidWebhookSignature},
+		{"source without secret", IntegrationRollbar, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := func(key string) string { return tt.headers[key] }
+			if err := signatures.Verify(tt.source, header, body); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("tampered body", func(t *testing.T) {
+		header := func(string) string { return sign(sha256.New, secret, body) }
+		if err := signatures.Verify(IntegrationSentry, header, []byte(`{}`)); !errors.Is(err, ErrInvalidWebhookSignature) {
+			t.Errorf("Verify() = %v, want %v", err, ErrInvalidWebhookSignature)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// Errors of webhook transforms.
+var (
+	ErrNoWebhookTransform      = notFoundError("event manager has no webhook transform")
+	ErrInvalidWebhookTransform = invalidError("invalid webhook_transform")
+	ErrInvalidWebhookPayload   = invalidError("webhook payload cannot be transformed")
+)
+
+// WebhookTransform maps the JSON body of a third-party webhook to an event,
+// so a tool can send its native payload without an adapter. Each field is an
+// expression in the expr language (https://expr-lang.org) over the parsed
+// body, named payload, e.g.
+//
+//	summary:  payload.incident.title
+//	severity: payload.incident.urgency == "high" ? "high" : "low"
+//	action:   payload.event == "resolved" ? "resolve" : "trigger"
+//
+// Empty fields are left empty, so event defaults apply. Labels must evaluate
+// to a map; the other fields to strings, numbers or booleans.
+type WebhookTransform struct {
+	DedupKey string `json:"dedupKey,omitempty" yaml:"dedupKey,omitempty"`
+	Summary  string `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Severity string `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Action   string `json:"action,omitempty" yaml:"action,omitempty"`
+	Class    string `json:"class,omitempty" yaml:"class,omitempty"`
+	Source   string `json:"source,omitempty" yaml:"source,omitempty"`
+	Labels   string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// IsEnabled returns true if the transform maps any field.
+func (t *WebhookTransform) IsEnabled() bool {
+	return *t != WebhookTransform{}
+}
+
+// fields returns the expressions of the transform by field name.
+func (t *WebhookTransform) fields() []struct{ name, source string } {
+	return []struct{ name, source string }{
+		{"dedupKey", t.DedupKey},
+		{"summary", t.Summary},
+		{"severity", t.Severity},
+		{"action", t.Action},
+		{"class", t.Class},
+		{"source", t.Source},
+		{"labels", t.Labels},
+	}
+}
+
+// Validate checks that an enabled transform maps the dedup key and summary
+// and that every expression compiles.
+func (t *WebhookTransform) Validate() error {
+	if !t.IsEnabled() {
+		return nil
+	}
+	if t.DedupKey == "" || t.Summary == "" {
+		return fmt.Errorf("%w: dedupKey and summary are required", ErrInvalidWebhookTransform)
+	}
+	for _, field := range t.fields() {
+		if field.source == "" {
+			continue
+		}
+		if _, err := compileTransform(field.source); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidWebhookTransform, field.name, err)
+		}
+	}
+	return nil
+}
+
+// Apply maps a parsed webhook body to an event. The event manager is left
+// for the caller to set.
+func (t *WebhookTransform) Apply(payload any) (*Event, error) {
+	event := &Event{}
+	targets := map[string]*string{
+		"dedupKey": &event.DedupKey,
+		"summary":  &event.Summary,
+		"class":    &event.Class,
+		"source":   &event.Source,
+	}
+	var severity, action string
+	targets["severity"] = &severity
+	targets["action"] = &action
+
+	env := transformEnv{Payload: payload}
+	for _, field := range t.fields() {
+		if field.source == "" {
+			continue
+		}
+		program, err := compileTransform(field.source)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidWebhookTransform, field.name, err)
+		}
+		value, err := expr.Run(program, env)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidWebhookPayload, field.name, err)
+		}
+
+		if field.name == "labels" {
+			if event.Labels, err = transformLabels(value); err != nil {
+				return nil, fmt.Errorf("%w: labels: %v", ErrInvalidWebhookPayload, err)
+			}
+			continue
+		}
+		if *targets[field.name], err = transformString(value); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidWebhookPayload, field.name, err)
+		}
+	}
+
+	event.Severity = Severity(severity)
+	event.Action = Action(action)
+	if event.Action == "" {
+		event.Action = ActionTrigger
+	}
+	return event, nil
+}
+
+// transformEnv is the environment transform expressions are evaluated in.
+// The payload is untyped, so its fields are resolved at run time.
+type transformEnv struct {
+	Payload any `expr:"payload"`
+}
+
+// compiledTransforms caches the programs of transform expressions by source.
+var compiledTransforms sync.Map
+
+// compileTransform returns the program of a transform expression.
+func compileTransform(source string) (*vm.Program, error) {
+	if program, ok := compiledTransforms.Load(source); ok {
+		return program.(*vm.Program), nil
+	}
+	if len(source) > maxExpressionLength {
+		return nil, fmt.Errorf("longer than %d characters", maxExpressionLength)
+	}
+
+	program, err := expr.Compile(source, expr.Env(transformEnv{}))
+	if err != nil {
+		return nil, err
+	}
+	compiledTransforms.Store(source, program)
+	return program, nil
+}
+
+// transformString converts the value of a field expression to a string.
+// Missing values become empty strings.
+func transformString(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool, int, int64, float64:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("got %T, want a string", value)
+	}
+}
+
+// transformLabels converts the value of the labels expression to labels.
+func transformLabels(value any) (map[string]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	m, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("got %T, want a map", value)
+	}
+
+	labels := make(map[string]string, len(m))
+	for key, v := range m {
+		s, err := transformString(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", key, err)
+		}
+		labels[key] = s
+	}
+	return labels, nil
+}
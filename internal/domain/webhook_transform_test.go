@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWebhookTransform_Apply(t *testing.T) {
+	transform := WebhookTransform{
+		DedupKey: `"incident-" + string(payload.incident.id)`,
+		Summary:  `payload.incident.title`,
+		Severity: `payload.incident.urgency == "high" ? "high" : "low"`,
+		Action:   `payload.event == "incident.resolved" ? "resolve" : "trigger"`,
+		Class:    `payload.incident.service?.name`,
+		Labels:   `{"team": payload.incident.team, "priority": payload.incident.priority}`,
+	}
+	if err := transform.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	var payload any
+	body := `{"event":"incident.resolved","incident":{"id":42,"title":"Checkout down","urgency":"high","team":"payments","priority":1}}`
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := transform.Apply(payload)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	want := &Event{
+		DedupKey: "incident-42",
+		Summary:  "Checkout down",
+		Severity: SeverityHigh,
+		Action:   ActionResolve,
+		Labels:   map[string]string{"team": "payments", "priority": "1"},
+	}
+	if !reflect.DeepEqual(event, want) {
+		t.Errorf("Apply() = %+v, want %+v", event, want)
+	}
+}
+
+func TestWebhookTransform_ApplyDefaultsToTrigger(t *testing.T) {
+	transform := WebhookTransform{DedupKey: `payload.id`, Summary: `payload.title`}
+
+	event, err := transform.Apply(map[string]any{"id": "a-1", "title": "Disk full"})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if event.Action != ActionTrigger {
+		t.Errorf("Action = %q, want %q", event.Action, ActionTrigger)
+	}
+}
+
+func TestWebhookTransform_ApplyInvalidPayload(t *testing.T) {
+	transform := WebhookTransform{DedupKey: `payload.id`, Summary: `payload.title`, Labels: `payload.labels`}
+
+	payloads := []any{
+		map[string]any{"id": map[string]any{"nested": true}, "title": "x"},
+		map[string]any{"id": "a-1", "title": "x", "labels": "not a map"},
+		[]any{"not", "an", "object"},
+	}
+	for _, payload := range payloads {
+		if _, err := transform.Apply(payload); !errors.Is(err, ErrInvalidWebhookPayload) {
+			t.Errorf("Apply(%v) error = %v, want %v", payload, err, ErrInvalidWebhookPayload)
+		}
+	}
+}
+
+func TestWebhookTransform_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform WebhookTransform
+		wantErr   bool
+	}{
+		{name: "disabled", transform: WebhookTransform{}},
+		{name: "minimal", transform: WebhookTransform{DedupKey: `payload.id`, Summary: `payload.title`}},
+		{name: "missing summary", transform: WebhookTransform{DedupKey: `payload.id`}, wantErr: true},
+		{name: "missing dedup key", transform: WebhookTransform{Summary: `payload.title`, Class: `"db"`}, wantErr: true},
+		{name: "syntax error", transform: WebhookTransform{DedupKey: `payload.id`, Summary: `payload.title +`}, wantErr: true},
+		{name: "unknown variable", transform: WebhookTransform{DedupKey: `body.id`, Summary: `payload.title`}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.transform.Validate()
+			if tt.wantErr && !errors.Is(err, ErrInvalidWebhookTransform) {
+				t.Errorf("Validate() error = %v, want %v", err, ErrInvalidWebhookTransform)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
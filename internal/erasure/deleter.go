@@ -0,0 +1,117 @@
+// Package erasure deletes all the data of an event manager, in every store
+// that holds it, to answer data deletion requests: its alerts and their
+// state, the alerts already expired by retention, its notification,
+// remediation, feedback and usage records, and its archived events.
+package erasure
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"argus-go/internal/archive"
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// AlertPurger removes the alerts of an event manager together with their
+// cached state, like processor.Service.
+type AlertPurger interface {
+	PurgeEventManagerAlerts(ctx context.Context, eventManagerID string) (int, error)
+}
+
+// ExpiredAlertPurger removes the alerts of an event manager from the
+// partitions detached by retention, like postgres.DB.
+type ExpiredAlertPurger interface {
+	PurgeDetachedAlerts(ctx context.Context, eventManagerID string) (int, error)
+}
+
+// Deleter deletes the data of event managers.
+type Deleter struct {
+	alerts        AlertPurger
+	expired       ExpiredAlertPurger
+	notifications store.NotificationLogRepository
+	remediations  store.RemediationLogRepository
+	feedback      store.AlertFeedbackRepository
+	usage         store.UsageRepository
+	archiver      *archive.Archiver
+	clock         clock.Clock
+	logger        *slog.Logger
+}
+
+// NewDeleter creates a deleter of the data in the given stores. expired is
+// nil without partitioned alerts, e.g. in memory mode, and archiver is nil
+// when the archive is disabled; those stores are then reported as skipped.
+func NewDeleter(
+	alerts AlertPurger,
+	expired ExpiredAlertPurger,
+	notifications store.NotificationLogRepository,
+	remediations store.RemediationLogRepository,
+	feedback store.AlertFeedbackRepository,
+	usage store.UsageRepository,
+	archiver *archive.Archiver,
+	clk clock.Clock,
+	logger *slog.Logger,
+) *Deleter {
+	return &Deleter{
+		alerts:        alerts,
+		expired:       expired,
+		notifications: notifications,
+		remediations:  remediations,
+		feedback:      feedback,
+		usage:         usage,
+		archiver:      archiver,
+		clock:         clk,
+		logger:        logger,
+	}
+}
+
+// Delete removes the data of an event manager from every store and reports
+// what it removed. The event manager itself is left as is, and may already
+// be purged. On error, the report covers the stores cleared so far; every
+// step is idempotent, so the deletion can be run again.
+func (d *Deleter) Delete(ctx context.Context, eventManagerID string) (*domain.DataDeletionReport, error) {
+	report := &domain.DataDeletionReport{
+		EventManagerID: eventManagerID,
+		StartedAt:      d.clock.Now(),
+	}
+
+	var err error
+	if report.Alerts, err = d.alerts.PurgeEventManagerAlerts(ctx, eventManagerID); err != nil {
+		return report, fmt.Errorf("failed to delete alerts: %w", err)
+	}
+
+	if d.expired == nil {
+		report.Skipped = append(report.Skipped, domain.DataStoreExpiredAlerts)
+	} else if report.ExpiredAlerts, err = d.expired.PurgeDetachedAlerts(ctx, eventManagerID); err != nil {
+		return report, fmt.Errorf("failed to delete expired alerts: %w", err)
+	}
+
+	if report.Notifications, err = d.notifications.DeleteByEventManager(ctx, eventManagerID); err != nil {
+		return report, fmt.Errorf("failed to delete notifications: %w", err)
+	}
+	if report.RemediationActions, err = d.remediations.DeleteByEventManager(ctx, eventManagerID); err != nil {
+		return report, fmt.Errorf("failed to delete remediation actions: %w", err)
+	}
+	if report.Feedback, err = d.feedback.DeleteByEventManager(ctx, eventManagerID); err != nil {
+		return report, fmt.Errorf("failed to delete alert feedback: %w", err)
+	}
+	if report.UsageDays, err = d.usage.DeleteByEventManager(ctx, eventManagerID); err != nil {
+		return report, fmt.Errorf("failed to delete usage: %w", err)
+	}
+
+	if d.archiver == nil {
+		report.Skipped = append(report.Skipped, domain.DataStoreArchive)
+	} else {
+		report.ArchivedEvents, report.ArchiveObjects, err = d.archiver.Erase(ctx, eventManagerID)
+		if err != nil {
+			return report, fmt.Errorf("failed to delete archived events: %w", err)
+		}
+	}
+
+	completedAt := d.clock.Now()
+	report.CompletedAt = &completedAt
+	d.logger.WarnContext(ctx, "deleted data of event manager", "event_manager_id", eventManagerID, "report", report.String())
+	return report, nil
+}
@@ -0,0 +1,102 @@
+package erasure
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
+	storemem "argus-go/internal/store/memory"
+)
+
+// stubPurger reports a number of purged alerts, or fails with err.
+type stubPurger struct {
+	purged int
+	err    error
+}
+
+func (p *stubPurger) PurgeEventManagerAlerts(context.Context, string) (int, error) {
+	return p.purged, p.err
+}
+
+func (p *stubPurger) PurgeDetachedAlerts(context.Context, string) (int, error) {
+	return p.purged, p.err
+}
+
+func TestDeleter_Delete(t *testing.T) {
+	ctx := context.Background()
+	notifications := storemem.NewNotificationLogRepository()
+	remediations := storemem.NewRemediationLogRepository()
+	feedback := storemem.NewAlertFeedbackRepository()
+	usage := storemem.NewUsageRepository()
+
+	for _, emID := range []string{"em-1", "em-2"} {
+		_ = notifications.Record(ctx, &domain.NotificationRecord{DedupKey: "disk", EventManagerID: emID, Kind: domain.NotificationNewParent})
+		_ = remediations.Record(ctx, &domain.RemediationRecord{DedupKey: "disk", EventManagerID: emID, Action: "restart"})
+		_ = feedback.Record(ctx, &domain.AlertFeedback{DedupKey: "disk", EventManagerID: emID, Rating: domain.FeedbackNoisy})
+		_ = usage.Add(ctx, &domain.Usage{EventManagerID: emID, Day: "2026-10-14", EventsIngested: 1})
+		_ = usage.Add(ctx, &domain.Usage{EventManagerID: emID, Day: "2026-10-15", EventsIngested: 1})
+	}
+
+	clk := clock.NewFake(time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC))
+	deleter := NewDeleter(&stubPurger{purged: 3}, nil, notifications, remediations, feedback, usage, nil, clk, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	report, err := deleter.Delete(ctx, "em-1")
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if report.Alerts != 3 || report.Notifications != 1 || report.RemediationActions != 1 || report.Feedback != 1 || report.UsageDays != 2 {
+		t.Errorf("report = %+v", report)
+	}
+	if report.CompletedAt == nil {
+		t.Error("CompletedAt = nil, want set")
+	}
+	if len(report.Skipped) != 2 || report.Skipped[0] != domain.DataStoreExpiredAlerts || report.Skipped[1] != domain.DataStoreArchive {
+		t.Errorf("Skipped = %v, want expired alerts and archive", report.Skipped)
+	}
+
+	// The data of other event managers is kept
+	records, _ := notifications.ListByDedupKey(ctx, "disk")
+	if len(records) != 1 || records[0].EventManagerID != "em-2" {
+		t.Errorf("notifications = %v, want only em-2", records)
+	}
+	runs, _ := remediations.ListByDedupKey(ctx, "disk")
+	if len(runs) != 1 || runs[0].EventManagerID != "em-2" {
+		t.Errorf("remediation actions = %v, want only em-2", runs)
+	}
+	days, _ := usage.List(ctx, domain.ReportFilter{From: clk.Now().AddDate(0, 0, -7), To: clk.Now()})
+	if len(days) != 2 || days[0].EventManagerID != "em-2" {
+		t.Errorf("usage = %v, want only em-2", days)
+	}
+
+	// Deleting again finds nothing left
+	report, err = deleter.Delete(ctx, "em-1")
+	if err != nil || report.Notifications != 0 || report.UsageDays != 0 {
+		t.Errorf("second Delete() = %+v, %v", report, err)
+	}
+}
+
+func TestDeleter_DeleteStopsAtFailure(t *testing.T) {
+	ctx := context.Background()
+	notifications := storemem.NewNotificationLogRepository()
+	_ = notifications.Record(ctx, &domain.NotificationRecord{DedupKey: "disk", EventManagerID: "em-1"})
+
+	deleter := NewDeleter(&stubPurger{purged: 2}, &stubPurger{err: errors.New("unavailable")},
+		notifications, storemem.NewRemediationLogRepository(), storemem.NewAlertFeedbackRepository(), storemem.NewUsageRepository(), nil,
+		clock.NewFake(time.Now()), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	report, err := deleter.Delete(ctx, "em-1")
+	if err == nil {
+		t.Fatal("Delete() error = nil, want the error of expired alerts")
+	}
+	if report.Alerts != 2 || report.CompletedAt != nil {
+		t.Errorf("report = %+v, want the alerts deleted and no completion", report)
+	}
+	if records, _ := notifications.ListByDedupKey(ctx, "disk"); len(records) != 1 {
+		t.Errorf("notifications = %d, want 1 left after the failure", len(records))
+	}
+}
@@ -0,0 +1,75 @@
+// Package eventstatus tracks where the events accepted by ingest are in the
+// pipeline, so a sender who sees no alert for an event can look up what
+// became of it: still queued, being processed, turned into an alert, or
+// dropped and why.
+package eventstatus
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// Tracker records the status of events in the state store. A nil Tracker
+// tracks nothing, so tracking can be disabled. It is safe for concurrent use.
+type Tracker struct {
+	states store.StateStore
+	ttl    time.Duration
+	clock  clock.Clock
+	logger *slog.Logger
+}
+
+// NewTracker creates a tracker keeping the status of each event in states
+// for ttl after its last update.
+func NewTracker(states store.StateStore, ttl time.Duration, clk clock.Clock, logger *slog.Logger) *Tracker {
+	return &Tracker{
+		states: states,
+		ttl:    ttl,
+		clock:  clk,
+		logger: logger,
+	}
+}
+
+// Record records the state an event reached, with the reason it was dropped
+// or failed. Events without an event ID, queued before IDs were assigned,
+// are skipped. Tracking is best effort: a failure to record is logged and
+// never fails the event.
+func (t *Tracker) Record(ctx context.Context, event *domain.InternalEvent, state domain.EventState, reason string) {
+	if t == nil || event.EventID == "" {
+		return
+	}
+
+	status := &domain.EventStatus{
+		EventID:        event.EventID,
+		State:          state,
+		EventManagerID: event.EventManagerID,
+		DedupKey:       event.DedupKey,
+		Action:         event.Action,
+		Reason:         reason,
+		ReceivedAt:     event.ReceivedAt,
+		UpdatedAt:      t.clock.Now().UTC(),
+	}
+	if err := t.states.SetEventStatus(ctx, status, t.ttl); err != nil {
+		t.logger.WarnContext(ctx, "failed to record event status", "eventID", event.EventID, "state", state, "error", err)
+	}
+}
+
+// Get returns the status of an event by its event ID. Returns
+// domain.ErrEventStatusNotFound if it has none, or tracking is disabled.
+func (t *Tracker) Get(ctx context.Context, eventID string) (*domain.EventStatus, error) {
+	if t == nil {
+		return nil, domain.ErrEventStatusNotFound
+	}
+	status, err := t.states.GetEventStatus(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if status == nil {
+		return nil, domain.ErrEventStatusNotFound
+	}
+	return status, nil
+}
@@ -0,0 +1,180 @@
+// Package health probes the external dependencies of ArgusGo (Redis and
+// PostgreSQL) in the background, so an outage shows up in /readyz and the
+// argus_dependency_up gauge instead of only failing the calls that hit it.
+// A failing dependency is probed again with exponential backoff; each probe
+// lets its client reconnect, so recovery is noticed without a restart.
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"argus-go/internal/config"
+	"argus-go/internal/metrics"
+)
+
+// Pinger is a dependency client that can check its connection. A failed
+// Ping should drop broken connections so the next one reconnects.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Status is the health of one dependency.
+type Status struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	// Error is the error of the last probe of a degraded dependency.
+	Error string `json:"error,omitempty"`
+	// Failures counts the consecutive failed probes.
+	Failures int `json:"consecutive_failures"`
+	// Since is when the dependency became healthy or degraded.
+	Since time.Time `json:"since"`
+}
+
+// probe is a registered dependency with its current status.
+type probe struct {
+	pinger Pinger
+	status Status
+}
+
+// Monitor probes dependencies and reports their health. A Monitor without
+// dependencies, as in memory mode, is always ready. A nil Monitor is valid
+// and always ready too.
+type Monitor struct {
+	cfg    config.HealthConfig
+	logger *slog.Logger
+
+	mu     sync.RWMutex
+	probes []*probe
+}
+
+// NewMonitor creates a monitor with the given probe settings.
+func NewMonitor(cfg config.HealthConfig, logger *slog.Logger) *Monitor {
+	return &Monitor{cfg: cfg, logger: logger}
+}
+
+// Add registers a dependency. Dependencies start out healthy, since they are
+// connected to before the monitor is created. Add must be called before Start.
+func (m *Monitor) Add(name string, pinger Pinger) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.probes = append(m.probes, &probe{
+		pinger: pinger,
+		status: Status{Name: name, Healthy: true, Since: time.Now().UTC()},
+	})
+	metrics.DependencyUp.WithLabelValues(name).Set(1)
+}
+
+// Start probes every dependency until the context is canceled.
+func (m *Monitor) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, p := range m.probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.run(ctx, p)
+		}()
+	}
+	wg.Wait()
+}
+
+// run probes one dependency every interval while it is healthy, and with
+// backoff while it is degraded.
+func (m *Monitor) run(ctx context.Context, p *probe) {
+	timer := time.NewTimer(m.cfg.Interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		failures := m.check(ctx, p)
+		timer.Reset(m.nextProbe(failures))
+	}
+}
+
+// nextProbe returns the delay before the next probe of a dependency after
+// the given number of consecutive failures.
+func (m *Monitor) nextProbe(failures int) time.Duration {
+	if failures == 0 {
+		return m.cfg.Interval
+	}
+	backoff := m.cfg.RetryBackoff
+	for i := 1; i < failures && backoff < m.cfg.MaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if m.cfg.MaxRetryBackoff > 0 && backoff > m.cfg.MaxRetryBackoff {
+		backoff = m.cfg.MaxRetryBackoff
+	}
+	return backoff
+}
+
+// Check probes every dependency once.
+func (m *Monitor) Check(ctx context.Context) {
+	for _, p := range m.probes {
+		m.check(ctx, p)
+	}
+}
+
+// check probes a dependency, records the result and returns the number of
+// consecutive failures.
+func (m *Monitor) check(ctx context.Context, p *probe) int {
+	probeCtx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+	err := p.pinger.Ping(probeCtx)
+	cancel()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := &p.status
+	switch {
+	case err == nil && !status.Healthy:
+		m.logger.Info("dependency recovered", "dependency", status.Name, "failures", status.Failures)
+		*status = Status{Name: status.Name, Healthy: true, Since: time.Now().UTC()}
+	case err != nil && status.Healthy:
+		m.logger.Error("dependency degraded", "dependency", status.Name, "error", err)
+		*status = Status{Name: status.Name, Error: err.Error(), Failures: 1, Since: time.Now().UTC()}
+	case err != nil:
+		m.logger.Warn("dependency still degraded", "dependency", status.Name, "failures", status.Failures+1, "error", err)
+		status.Error = err.Error()
+		status.Failures++
+	}
+
+	up := 0.0
+	if status.Healthy {
+		up = 1
+	}
+	metrics.DependencyUp.WithLabelValues(status.Name).Set(up)
+	return status.Failures
+}
+
+// Statuses returns the health of every dependency.
+func (m *Monitor) Statuses() []Status {
+	if m == nil {
+		return []Status{}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	statuses := make([]Status, 0, len(m.probes))
+	for _, p := range m.probes {
+		statuses = append(statuses, p.status)
+	}
+	return statuses
+}
+
+// Ready reports whether every dependency is healthy.
+func (m *Monitor) Ready() bool {
+	for _, status := range m.Statuses() {
+		if !status.Healthy {
+			return false
+		}
+	}
+	return true
+}
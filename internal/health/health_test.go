@@ -0,0 +1,92 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"argus-go/internal/config"
+)
+
+// fakePinger fails while err is set.
+type fakePinger struct {
+	err error
+}
+
+func (p *fakePinger) Ping(context.Context) error {
+	return p.err
+}
+
+func newTestMonitor() *Monitor {
+	return NewMonitor(config.HealthConfig{
+		Interval:        10 * time.Second,
+		Timeout:         time.Second,
+		RetryBackoff:    time.Second,
+		MaxRetryBackoff: 5 * time.Second,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestMonitor_Check(t *testing.T) {
+	m := newTestMonitor()
+	redis := &fakePinger{}
+	m.Add("redis", redis)
+	m.Add("postgres", &fakePinger{})
+	ctx := context.Background()
+
+	if !m.Ready() {
+		t.Fatal("new monitor should be ready")
+	}
+
+	redis.err = errors.New("connection refused")
+	m.Check(ctx)
+	m.Check(ctx)
+	if m.Ready() {
+		t.Fatal("monitor should not be ready while redis fails")
+	}
+	status := m.Statuses()[0]
+	if status.Healthy || status.Failures != 2 || status.Error != "connection refused" {
+		t.Errorf("redis status = %+v, want 2 failures with the error", status)
+	}
+	if !m.Statuses()[1].Healthy {
+		t.Error("postgres should stay healthy")
+	}
+
+	redis.err = nil
+	m.Check(ctx)
+	if !m.Ready() {
+		t.Error("monitor should be ready after redis recovers")
+	}
+	if status := m.Statuses()[0]; status.Failures != 0 || status.Error != "" {
+		t.Errorf("recovered status = %+v, want failures and error cleared", status)
+	}
+}
+
+func TestMonitor_NextProbe(t *testing.T) {
+	m := newTestMonitor()
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 10 * time.Second},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{10, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := m.nextProbe(tt.failures); got != tt.want {
+			t.Errorf("nextProbe(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestMonitor_Nil(t *testing.T) {
+	var m *Monitor
+	if !m.Ready() || len(m.Statuses()) != 0 {
+		t.Error("nil monitor should be ready without dependencies")
+	}
+}
@@ -0,0 +1,39 @@
+package ingest
+
+import "sync"
+
+// GroupingDefaults holds the system-wide default grouping rule, applied to
+// events of event managers that have no grouping rule of their own.
+// It is initialized from config and can be changed at runtime through the
+// admin API. It is safe for concurrent use; a nil GroupingDefaults has no
+// default rule.
+type GroupingDefaults struct {
+	mu     sync.RWMutex
+	ruleID string
+}
+
+// NewGroupingDefaults creates grouping defaults with the given default rule ID.
+// An empty ID means there is no default rule.
+func NewGroupingDefaults(ruleID string) *GroupingDefaults {
+	return &GroupingDefaults{ruleID: ruleID}
+}
+
+// RuleID returns the default grouping rule ID, or an empty string if none is set.
+func (d *GroupingDefaults) RuleID() string {
+	if d == nil {
+		return ""
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.ruleID
+}
+
+// SetRuleID replaces the default grouping rule ID. An empty ID clears it.
+func (d *GroupingDefaults) SetRuleID(ruleID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.ruleID = ruleID
+}
@@ -0,0 +1,90 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// DiagnoseGrouping explains the grouping of an alert with the grouping rule
+// ingest selects for it today and the parent lookup and children in states
+// at now. The grouping value is extracted from the fields of the alert, as
+// the event that created it is not kept.
+func (s *Service) DiagnoseGrouping(ctx context.Context, alert *domain.Alert, states store.StateStore, now time.Time) (*domain.AlertDiagnostics, error) {
+	em, err := s.eventManagerRepo.GetByID(ctx, alert.EventManagerID)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &domain.AlertDiagnostics{
+		DedupKey:       alert.DedupKey,
+		EventManagerID: alert.EventManagerID,
+		Type:           alert.Type,
+		Status:         alert.Status,
+		ParentDedupKey: alert.ParentDedupKey,
+		Children:       []string{},
+	}
+
+	parent := alert.DedupKey
+	if alert.IsChild() {
+		parent = alert.ParentDedupKey
+	}
+	children, err := states.GetChildren(ctx, parent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get children: %w", err)
+	}
+	if children != nil {
+		d.Children = children
+	}
+
+	if em.GroupingDisabled {
+		d.GroupingDisabled = true
+		d.Decision = d.Explain()
+		return d, nil
+	}
+
+	event := alert.GroupingEvent()
+	d.GroupingRuleID = em.GroupingRuleFor(event)
+	if d.GroupingRuleID == "" {
+		d.GroupingRuleID = s.groupingDefaults.RuleID()
+		d.DefaultGroupingRule = d.GroupingRuleID != ""
+	}
+	if d.GroupingRuleID == "" {
+		d.Decision = d.Explain()
+		return d, nil
+	}
+
+	rule, err := s.groupingRuleRepo.GetByID(ctx, d.GroupingRuleID)
+	if errors.Is(err, domain.ErrGroupingRuleNotFound) {
+		d.GroupingRuleMissing = true
+		d.Decision = d.Explain()
+		return d, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch grouping rule: %w", err)
+	}
+	d.GroupingRuleName = rule.Name
+	d.GroupingKey = rule.GroupingKey
+	d.GroupingValue = rule.ExtractGroupingValue(event)
+	d.TimeWindow = domain.Duration(rule.TimeWindow())
+
+	// The lookup of a parent expires a time window after it was created
+	d.ParentLookup = &domain.ParentLookup{Key: em.ID + ":" + rule.GroupingKey + ":" + d.GroupingValue}
+	state, err := states.GetParent(ctx, em.ID, rule.GroupingKey, d.GroupingValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up parent: %w", err)
+	}
+	if state != nil {
+		expiresAt := state.CreatedAt.Add(rule.TimeWindow()).UTC()
+		d.ParentLookup.ParentDedupKey = state.DedupKey
+		d.ParentLookup.ExpiresAt = &expiresAt
+		d.ParentLookup.TTLRemaining = domain.Duration(max(expiresAt.Sub(now), 0).Round(time.Second))
+	}
+
+	d.Decision = d.Explain()
+	return d, nil
+}
@@ -0,0 +1,30 @@
+package ingest
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// eventIDKey is the context key of the ID of an event being ingested.
+type eventIDKey struct{}
+
+// WithEventID returns a context assigning the ID of the event ingested with
+// it, so the caller knows the ID before IngestEvent returns.
+func WithEventID(ctx context.Context, eventID string) context.Context {
+	return context.WithValue(ctx, eventIDKey{}, eventID)
+}
+
+// NewEventID returns a new event ID.
+func NewEventID() string {
+	return uuid.NewString()
+}
+
+// eventID returns the ID of an event: the one assigned on the context, or a
+// new one.
+func eventID(ctx context.Context) string {
+	if id, _ := ctx.Value(eventIDKey{}).(string); id != "" {
+		return id
+	}
+	return NewEventID()
+}
@@ -0,0 +1,27 @@
+package ingest
+
+import (
+	"context"
+
+	"argus-go/internal/domain"
+)
+
+// originKey is the context key of the origin of an event being ingested.
+type originKey struct{}
+
+// WithOrigin returns a context recording where the event ingested with it
+// was received from, so IngestEvent records it on the event.
+func WithOrigin(ctx context.Context, origin domain.EventOrigin) context.Context {
+	return context.WithValue(ctx, originKey{}, origin)
+}
+
+// eventOrigin returns the origin of an event: the one recorded on the
+// context, with the event's own source. Nil if neither is known.
+func eventOrigin(ctx context.Context, event *domain.Event) *domain.EventOrigin {
+	origin, _ := ctx.Value(originKey{}).(domain.EventOrigin)
+	origin.Source = event.Source
+	if origin == (domain.EventOrigin{}) {
+		return nil
+	}
+	return &origin
+}
@@ -0,0 +1,80 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/logging"
+)
+
+// PublishResolve queues the resolution of a parent alert and its active
+// children requested through the API. Instead of writing to the stores
+// directly, the resolve travels the queue like the resolve events of
+// clients, under the partition key of the alert, so the processor handles
+// it in order with the triggers of the group received before.
+//
+// partitionKey is the one recorded in the state of the alert; alerts
+// created before it was recorded fall back to the partition of their
+// dedup key. Resolves are never rejected by quotas.
+func (s *Service) PublishResolve(ctx context.Context, alert *domain.Alert, partitionKey string, resolution domain.Resolution) error {
+	return s.publishResolve(ctx, alert, partitionKey, func(event *domain.InternalEvent) {
+		event.Summary = resolution.Reason
+		event.Resolution = &resolution
+	})
+}
+
+// PublishPendingResolveSweep queues a check of a parent alert left waiting
+// for its children, for the processor to complete or force its resolve in
+// order with the events of the group, like PublishResolve.
+func (s *Service) PublishPendingResolveSweep(ctx context.Context, alert *domain.Alert, partitionKey string) error {
+	return s.publishResolve(ctx, alert, partitionKey, func(event *domain.InternalEvent) {
+		event.SweepPendingResolve = true
+	})
+}
+
+// publishResolve queues a resolve event of an alert under its partition key,
+// as set up by configure.
+func (s *Service) publishResolve(ctx context.Context, alert *domain.Alert, partitionKey string, configure func(*domain.InternalEvent)) error {
+	em, err := s.eventManagerRepo.GetByID(ctx, alert.EventManagerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEventManagerNotFound) {
+			return ErrEventManagerNotFound
+		}
+		return fmt.Errorf("failed to fetch event manager: %w", err)
+	}
+
+	if partitionKey == "" {
+		partitionKey = computePartitionKey(alert.EventManagerID, alert.DedupKey)
+	}
+	internalEvent := &domain.InternalEvent{
+		Event: domain.Event{
+			EventManagerID: alert.EventManagerID,
+			Severity:       alert.Severity,
+			Action:         domain.ActionResolve,
+			Class:          alert.Class,
+			DedupKey:       alert.DedupKey,
+		},
+		PartitionKey:  partitionKey,
+		ReceivedAt:    time.Now().UTC(),
+		CorrelationID: logging.CorrelationID(ctx),
+	}
+	configure(internalEvent)
+	if err := s.publish(ctx, internalEvent, em.Topic); err != nil {
+		return err
+	}
+
+	var actor string
+	if internalEvent.Resolution != nil {
+		actor = internalEvent.Resolution.Actor
+	}
+	s.logger.InfoContext(ctx, "resolve queued",
+		"dedupKey", alert.DedupKey,
+		"partitionKey", partitionKey,
+		"actor", actor,
+		"sweep", internalEvent.SweepPendingResolve,
+	)
+	return nil
+}
@@ -0,0 +1,50 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// Router selects the event manager of events sent without an
+// event_manager_id using the routing rules.
+type Router struct {
+	repo   store.RoutingRuleRepository
+	logger *slog.Logger
+}
+
+// NewRouter creates a new event router.
+func NewRouter(repo store.RoutingRuleRepository, logger *slog.Logger) *Router {
+	return &Router{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Route sets the event manager of an event from the first matching routing
+// rule. Events that already name an event manager are left unchanged.
+// Returns domain.ErrNoRoute if no rule matches.
+func (r *Router) Route(ctx context.Context, event *domain.Event) error {
+	if event.EventManagerID != "" {
+		return nil
+	}
+
+	// For MVP, rules are fetched from the repository for every routed event
+	rules, err := r.repo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list routing rules: %w", err)
+	}
+
+	rule := domain.RouteEvent(rules, event)
+	if rule == nil {
+		r.logger.Debug("no routing rule matches event", "dedupKey", event.DedupKey, "class", event.Class, "source", event.Source)
+		return domain.ErrNoRoute
+	}
+
+	event.EventManagerID = rule.EventManagerID
+	r.logger.Debug("routed event", "dedupKey", event.DedupKey, "routingRuleID", rule.ID, "event_manager_id", rule.EventManagerID)
+	return nil
+}
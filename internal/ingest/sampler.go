@@ -0,0 +1,94 @@
+package ingest
+
+import (
+	"sync"
+	"time"
+
+	"argus-go/internal/domain"
+)
+
+// samplingIdleTimeout is how long a dedup key may go without triggers before
+// sampling starts over for it, so the first trigger after a quiet period is
+// always processed. Triggers dropped since the last one processed before a
+// key goes idle are not counted.
+const samplingIdleTimeout = 5 * time.Minute
+
+// sampledKey tracks the triggers of a dedup key under sampling.
+type sampledKey struct {
+	// seen counts the triggers since the first one processed.
+	seen int
+
+	// dropped counts the triggers dropped since the last one processed.
+	dropped int
+
+	// lastSeen is when the last trigger arrived.
+	lastSeen time.Time
+}
+
+// sampler applies the sampling policies of event managers to their trigger
+// events. The alert of a dedup key is taken to be active from the first
+// trigger processed until a resolve is ingested, or the key goes idle. State
+// is kept per replica, so each replica samples the events it receives.
+type sampler struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]*sampledKey
+	lastSweep time.Time
+}
+
+// newSampler creates a sampler without state.
+func newSampler(now func() time.Time) *sampler {
+	return &sampler{
+		now:  now,
+		keys: make(map[string]*sampledKey),
+	}
+}
+
+// admit decides whether an event is processed. For a processed trigger it
+// also returns the number of triggers of its dedup key dropped since the
+// last one processed.
+func (s *sampler) admit(policy *domain.SamplingPolicy, event *domain.Event) (bool, int) {
+	key := event.EventManagerID + "\x00" + event.DedupKey
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	if event.Action != domain.ActionTrigger || !policy.IsEnabled() {
+		delete(s.keys, key)
+		return true, 0
+	}
+
+	k, ok := s.keys[key]
+	if !ok || now.Sub(k.lastSeen) >= samplingIdleTimeout {
+		// The first trigger opens or reactivates the alert
+		s.keys[key] = &sampledKey{lastSeen: now}
+		return true, 0
+	}
+
+	k.lastSeen = now
+	k.seen++
+	if k.seen%policy.KeepOneIn != 0 {
+		k.dropped++
+		return false, 0
+	}
+	dropped := k.dropped
+	k.dropped = 0
+	return true, dropped
+}
+
+// sweep forgets the dedup keys gone idle, at most once per idle timeout.
+func (s *sampler) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < samplingIdleTimeout {
+		return
+	}
+	s.lastSweep = now
+	for key, k := range s.keys {
+		if now.Sub(k.lastSeen) >= samplingIdleTimeout {
+			delete(s.keys, key)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package ingest
+
+import (
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+)
+
+func TestSampler_Admit(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := newSampler(func() time.Time { return now })
+	policy := &domain.SamplingPolicy{KeepOneIn: 3}
+
+	trigger := &domain.Event{EventManagerID: "em-1", DedupKey: "disk-full", Action: domain.ActionTrigger}
+	resolve := &domain.Event{EventManagerID: "em-1", DedupKey: "disk-full", Action: domain.ActionResolve}
+
+	// The first trigger opens the alert, then 1 in 3 triggers is kept
+	var kept []int
+	for i := range 7 {
+		if keep, sampled := s.admit(policy, trigger); keep {
+			kept = append(kept, i)
+			if i > 0 && sampled != 2 {
+				t.Errorf("trigger %d carries %d sampled events, want 2", i, sampled)
+			}
+		}
+	}
+	if len(kept) != 3 || kept[0] != 0 || kept[1] != 3 || kept[2] != 6 {
+		t.Errorf("kept triggers %v, want [0 3 6]", kept)
+	}
+
+	// Resolves are always kept and start sampling over
+	if keep, _ := s.admit(policy, trigger); keep {
+		t.Error("trigger after the last kept one should be dropped")
+	}
+	if keep, _ := s.admit(policy, resolve); !keep {
+		t.Error("resolve should be kept")
+	}
+	if keep, sampled := s.admit(policy, trigger); !keep || sampled != 0 {
+		t.Errorf("trigger after a resolve = (%v, %d), want (true, 0)", keep, sampled)
+	}
+
+	// A key gone idle starts over
+	if keep, _ := s.admit(policy, trigger); keep {
+		t.Error("second trigger should be dropped")
+	}
+	now = now.Add(samplingIdleTimeout)
+	if keep, _ := s.admit(policy, trigger); !keep {
+		t.Error("trigger after the idle timeout should be kept")
+	}
+}
+
+func TestSampler_AdmitDisabled(t *testing.T) {
+	s := newSampler(time.Now)
+	event := &domain.Event{EventManagerID: "em-1", DedupKey: "disk-full", Action: domain.ActionTrigger}
+
+	for _, policy := range []domain.SamplingPolicy{{}, {KeepOneIn: 1}} {
+		for range 3 {
+			if keep, sampled := s.admit(&policy, event); !keep || sampled != 0 {
+				t.Errorf("admit with keep_one_in %d = (%v, %d), want (true, 0)", policy.KeepOneIn, keep, sampled)
+			}
+		}
+	}
+}
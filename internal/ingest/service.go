@@ -14,7 +14,12 @@ import (
 	"time"
 
 	"argus-go/internal/domain"
+	"argus-go/internal/eventstatus"
+	"argus-go/internal/logging"
+	"argus-go/internal/metrics"
 	"argus-go/internal/queue"
+	"argus-go/internal/queue/buffered"
+	"argus-go/internal/quota"
 	"argus-go/internal/store"
 )
 
@@ -28,11 +33,12 @@ type Service struct {
 	producer         queue.Producer
 	eventManagerRepo store.EventManagerRepository
 	groupingRuleRepo store.GroupingRuleRepository
+	groupingDefaults *GroupingDefaults
+	quotas           *quota.Enforcer
+	scrubber         *domain.Scrubber
+	sampler          *sampler
+	tracker          *eventstatus.Tracker
 	logger           *slog.Logger
-
-	// eventManagerCache provides fast lookups for event managers.
-	// In production, this would be a proper cache with TTL and invalidation.
-	// For MVP, we fetch from repo on each request.
 }
 
 // NewService creates a new ingest service.
@@ -40,12 +46,21 @@ func NewService(
 	producer queue.Producer,
 	eventManagerRepo store.EventManagerRepository,
 	groupingRuleRepo store.GroupingRuleRepository,
+	groupingDefaults *GroupingDefaults,
+	quotas *quota.Enforcer,
+	scrubber *domain.Scrubber,
+	tracker *eventstatus.Tracker,
 	logger *slog.Logger,
 ) *Service {
 	return &Service{
 		producer:         producer,
 		eventManagerRepo: eventManagerRepo,
 		groupingRuleRepo: groupingRuleRepo,
+		groupingDefaults: groupingDefaults,
+		quotas:           quotas,
+		scrubber:         scrubber,
+		sampler:          newSampler(time.Now),
+		tracker:          tracker,
 		logger:           logger,
 	}
 }
@@ -53,88 +68,342 @@ func NewService(
 // Errors returned by the ingest service.
 var (
 	ErrEventManagerNotFound = errors.New("event manager not found")
+	ErrEventManagerDeleted  = errors.New("event manager has been deleted")
 	ErrGroupingRuleNotFound = errors.New("grouping rule not found")
 	ErrPublishFailed        = errors.New("failed to publish event to queue")
+	ErrBacklogged           = errors.New("too many events waiting to be published")
 )
 
+// ResolveIngestToken returns the ID of the event manager an ingest token
+// belongs to. Deleted event managers are returned too; IngestEvent rejects
+// their events. Returns domain.ErrInvalidIngestToken for unknown tokens.
+func (s *Service) ResolveIngestToken(ctx context.Context, token string) (string, error) {
+	em, err := s.eventManagerRepo.GetByIngestToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, domain.ErrEventManagerNotFound) {
+			return "", domain.ErrInvalidIngestToken
+		}
+		return "", fmt.Errorf("failed to fetch event manager: %w", err)
+	}
+	return em.ID, nil
+}
+
+// QuotaStatus returns the quota status of an event manager, or nil if it has
+// no quota or quotas are not enforced.
+func (s *Service) QuotaStatus(eventManagerID string) *domain.QuotaStatus {
+	return s.quotas.Status(eventManagerID)
+}
+
+// ResolveSignedWebhook is ResolveIngestToken for the webhooks of a source,
+// such as an integration: it also returns domain.ErrInvalidWebhookSignature
+// if the event manager has a secret for the source and the webhook isn't
+// signed with it. header returns the value of a request header.
+func (s *Service) ResolveSignedWebhook(ctx context.Context, token, source string, header func(string) string, body []byte) (string, error) {
+	em, err := s.signedEventManager(ctx, token, source, header, body)
+	if err != nil {
+		return "", err
+	}
+	return em.ID, nil
+}
+
+// TransformWebhook maps the body of a third-party webhook sent with an ingest
+// token to an event, with the webhook transform of the token's event manager.
+// Returns domain.ErrInvalidIngestToken for unknown tokens,
+// domain.ErrInvalidWebhookSignature for webhooks not signed with the secret
+// of domain.WebhookSourceTransform, domain.ErrNoWebhookTransform if the event
+// manager has no transform, and domain.ErrInvalidWebhookPayload if the body
+// doesn't fit the transform.
+func (s *Service) TransformWebhook(ctx context.Context, token string, header func(string) string, body []byte) (*domain.Event, error) {
+	em, err := s.signedEventManager(ctx, token, domain.WebhookSourceTransform, header, body)
+	if err != nil {
+		return nil, err
+	}
+	if !em.WebhookTransform.IsEnabled() {
+		return nil, domain.ErrNoWebhookTransform
+	}
+
+	var payload any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidWebhookPayload, err)
+	}
+	event, err := em.WebhookTransform.Apply(payload)
+	if err != nil {
+		return nil, err
+	}
+	event.EventManagerID = em.ID
+	return event, nil
+}
+
+// signedEventManager returns the event manager of an ingest token, after
+// checking the signature of a webhook of source sent with it.
+func (s *Service) signedEventManager(ctx context.Context, token, source string, header func(string) string, body []byte) (*domain.EventManager, error) {
+	em, err := s.eventManagerRepo.GetByIngestToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, domain.ErrEventManagerNotFound) {
+			return nil, domain.ErrInvalidIngestToken
+		}
+		return nil, fmt.Errorf("failed to fetch event manager: %w", err)
+	}
+	if err := em.WebhookSignatures.Verify(source, header, body); err != nil {
+		return nil, err
+	}
+	return em, nil
+}
+
+// routed is an event's route to the processor, computed by route.
+type routed struct {
+	em               *domain.EventManager
+	groupingRule     *domain.GroupingRule
+	originalDedupKey string
+	groupingValue    string
+	partitionKey     string
+}
+
 // IngestEvent processes an incoming event and publishes it to the message queue.
 // This is the main entry point for event ingestion.
 //
 // The processing flow:
-// 1. Look up the event manager by ID
-// 2. Look up the associated grouping rule
+// 1. Look up the event manager, rejecting deleted ones, and normalize and default the event
+// 2. Look up the grouping rule selected for the event, unless grouping is disabled
 // 3. Extract the grouping value from the event
 // 4. Compute the partition key for ordering
-// 5. Publish to the message queue
+// 5. Drop the trigger if the event manager samples the triggers of active alerts
+// 6. Publish to the message queue, unless the event manager's quota rejects the event
+//
+// The event is identified by the ID assigned with WithEventID, or a new one,
+// and its status is tracked from being sampled or queued.
 func (s *Service) IngestEvent(ctx context.Context, event *domain.Event) error {
-	// Step 1: Look up event manager
-	em, err := s.eventManagerRepo.GetByID(ctx, event.EventManagerID)
+	r, err := s.route(ctx, event)
 	if err != nil {
-		if errors.Is(err, domain.ErrEventManagerNotFound) {
-			s.logger.Warn("event manager not found", "event_manager_id", event.EventManagerID)
-			return ErrEventManagerNotFound
-		}
-		s.logger.Error("failed to fetch event manager", "error", err)
-		return fmt.Errorf("failed to fetch event manager: %w", err)
+		return err
 	}
 
-	// Step 2: Look up the grouping rule
-	groupingRule, err := s.groupingRuleRepo.GetByID(ctx, em.GroupingRuleID)
-	if err != nil {
-		if errors.Is(err, domain.ErrGroupingRuleNotFound) {
-			s.logger.Warn("grouping rule not found", "grouping_rule_id", em.GroupingRuleID)
-			return ErrGroupingRuleNotFound
-		}
-		s.logger.Error("failed to fetch grouping rule", "error", err)
-		return fmt.Errorf("failed to fetch grouping rule: %w", err)
+	// Create internal event with enriched data
+	internalEvent := &domain.InternalEvent{
+		Event:            *event,
+		EventID:          eventID(ctx),
+		OriginalDedupKey: r.originalDedupKey,
+		PartitionKey:     r.partitionKey,
+		GroupingRuleID:   r.groupingRuleID(),
+		GroupingValue:    r.groupingValue,
+		Origin:           eventOrigin(ctx, event),
+		ReceivedAt:       time.Now().UTC(),
+		CorrelationID:    logging.CorrelationID(ctx),
 	}
 
-	// Step 3: Extract the grouping value from the event
-	groupingValue := groupingRule.ExtractGroupingValue(event)
-
-	// Step 4: Compute partition key
-	// Events with the same partition key go to the same partition,
-	// ensuring they are processed in order by a single consumer.
-	partitionKey := computePartitionKey(event.EventManagerID, groupingValue)
+	// Step 5: Sample the triggers of active alerts
+	// A dropped trigger is accepted but never queued; the next trigger kept
+	// carries the count, so the processor records it on the alert.
+	keep, sampled := s.sampler.admit(&r.em.Sampling, event)
+	if !keep {
+		metrics.SampledEvents.WithLabelValues(event.EventManagerID).Inc()
+		s.logger.DebugContext(ctx, "event dropped by sampling", "event_manager_id", event.EventManagerID, "dedupKey", event.DedupKey)
+		s.tracker.Record(ctx, internalEvent, domain.EventDropped, "sampled: the alert is active and the event manager samples its triggers")
+		return nil
+	}
+	internalEvent.SampledEvents = sampled
 
-	// Step 5: Create internal event with enriched data
-	internalEvent := &domain.InternalEvent{
-		Event:         *event,
-		PartitionKey:  partitionKey,
-		GroupingValue: groupingValue,
-		ReceivedAt:    time.Now().UTC(),
+	// Step 6: Publish to message queue
+	// Triggers of an event manager that exhausted its quota are rejected;
+	// resolves are always accepted, so alerts can still be closed.
+	if err := s.quotas.Admit(event.EventManagerID, event.Action); err != nil {
+		s.logger.WarnContext(ctx, "rejecting event over quota", "event_manager_id", event.EventManagerID, "error", err)
+		return err
+	}
+	// The status is recorded before publishing, so the processor's updates
+	// come after it
+	s.tracker.Record(ctx, internalEvent, domain.EventQueued, "")
+	if err := s.publish(ctx, internalEvent, r.em.Topic); err != nil {
+		s.tracker.Record(ctx, internalEvent, domain.EventFailed, err.Error())
+		return err
 	}
 
-	// Serialize the internal event
+	s.logger.DebugContext(ctx, "event published to queue",
+		"dedupKey", event.DedupKey,
+		"partitionKey", r.partitionKey,
+		"groupingValue", r.groupingValue,
+	)
+
+	return nil
+}
+
+// publish serializes an internal event and publishes it to the queue, or
+// the dedicated topic of its event manager if set.
+func (s *Service) publish(ctx context.Context, internalEvent *domain.InternalEvent, topic string) error {
 	payload, err := json.Marshal(internalEvent)
 	if err != nil {
-		s.logger.Error("failed to serialize event", "error", err)
+		s.logger.ErrorContext(ctx, "failed to serialize event", "error", err)
 		return fmt.Errorf("failed to serialize event: %w", err)
 	}
 
-	// Step 6: Publish to message queue
+	trace := logging.TraceContextFrom(ctx)
 	msg := &queue.Message{
-		Key:   []byte(partitionKey),
+		Key:   []byte(internalEvent.PartitionKey),
 		Value: payload,
-		Headers: map[string]string{
-			"event_manager_id": event.EventManagerID,
-			"action":           string(event.Action),
-			"dedupKey":         event.DedupKey,
-		},
+		Headers: queue.Headers{
+			EventManagerID: internalEvent.EventManagerID,
+			Action:         string(internalEvent.Action),
+			DedupKey:       internalEvent.DedupKey,
+			CorrelationID:  internalEvent.CorrelationID,
+			TraceParent:    trace.TraceParent,
+			TraceState:     trace.TraceState,
+			ReceivedAt:     internalEvent.ReceivedAt,
+		}.Encode(),
+		Topic: topic,
 	}
 
 	if err := s.producer.Publish(ctx, msg); err != nil {
-		s.logger.Error("failed to publish event", "error", err, "dedupKey", event.DedupKey)
+		if errors.Is(err, buffered.ErrBufferFull) {
+			s.logger.WarnContext(ctx, "rejecting event, publish buffer full", "dedupKey", internalEvent.DedupKey)
+			return ErrBacklogged
+		}
+		s.logger.ErrorContext(ctx, "failed to publish event", "error", err, "dedupKey", internalEvent.DedupKey)
 		return ErrPublishFailed
 	}
+	return nil
+}
 
-	s.logger.Debug("event published to queue",
-		"dedupKey", event.DedupKey,
-		"partitionKey", partitionKey,
-		"groupingValue", groupingValue,
-	)
+// DryRun runs steps 1 to 4 of IngestEvent without sampling, quotas or
+// publishing, and predicts from the alert states in states how the
+// processor would handle the event. The prediction doesn't account for
+// events processed in the meantime or for alert storms.
+func (s *Service) DryRun(ctx context.Context, event *domain.Event, states store.StateStore) (*domain.IngestDryRun, error) {
+	r, err := s.route(ctx, event)
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	result := &domain.IngestDryRun{
+		EventManagerID:   event.EventManagerID,
+		DedupKey:         event.DedupKey,
+		OriginalDedupKey: r.originalDedupKey,
+		GroupingRuleID:   r.groupingRuleID(),
+		GroupingValue:    r.groupingValue,
+		PartitionKey:     r.partitionKey,
+	}
+	if r.groupingRule != nil {
+		result.GroupingKey = r.groupingRule.GroupingKey
+	}
+
+	existing, err := states.GetAlert(ctx, event.DedupKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up alert state: %w", err)
+	}
+	if existing != nil {
+		result.AlertStatus = existing.Status
+		result.ParentDedupKey = existing.ParentDedupKey
+	}
+	active := existing != nil && existing.Status == string(domain.AlertStatusActive)
+
+	switch {
+	case event.Action == domain.ActionResolve && active:
+		result.Outcome = domain.GroupingOutcomeResolve
+	case event.Action == domain.ActionResolve:
+		result.Outcome = domain.GroupingOutcomeIgnored
+	case active:
+		result.Outcome = domain.GroupingOutcomeDuplicate
+	case existing != nil:
+		result.Outcome = domain.GroupingOutcomeReactivate
+	case r.groupingRule == nil:
+		result.Outcome = domain.GroupingOutcomeParent
+	default:
+		parent, err := states.GetParent(ctx, event.EventManagerID, r.groupingRule.GroupingKey, r.groupingValue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up parent state: %w", err)
+		}
+		result.Outcome = domain.GroupingOutcomeParent
+		if parent != nil {
+			result.Outcome = domain.GroupingOutcomeChild
+			result.ParentDedupKey = parent.DedupKey
+		}
+	}
+	return result, nil
+}
+
+// route runs steps 1 to 4 of IngestEvent, normalizing and defaulting the
+// event in place.
+func (s *Service) route(ctx context.Context, event *domain.Event) (*routed, error) {
+	// Step 1: Look up event manager
+	em, err := s.eventManagerRepo.GetByID(ctx, event.EventManagerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEventManagerNotFound) {
+			s.logger.WarnContext(ctx, "event manager not found", "event_manager_id", event.EventManagerID)
+			return nil, ErrEventManagerNotFound
+		}
+		s.logger.ErrorContext(ctx, "failed to fetch event manager", "error", err)
+		return nil, fmt.Errorf("failed to fetch event manager: %w", err)
+	}
+	if em.IsDeleted() {
+		s.logger.WarnContext(ctx, "rejecting event for deleted event manager", "event_manager_id", event.EventManagerID)
+		return nil, ErrEventManagerDeleted
+	}
+
+	// Normalize the dedup key as configured by the event manager, so
+	// variants of the same key deduplicate and long keys are bounded
+	var originalDedupKey string
+	event.DedupKey, originalDedupKey = em.DedupKeyConfig.Normalize(event.DedupKey)
+	if event.DedupKey == "" {
+		return nil, domain.ErrEmptyDedupKey
+	}
+
+	// Fill in optional fields the event left empty
+	em.EventDefaults.Apply(event)
+
+	// Mask secrets and personal data before the event is queued
+	s.scrubber.Event(event)
+
+	// Step 2: Look up the grouping rule
+	// The first grouping rule whose matcher accepts the event applies,
+	// falling back to the system-wide default rule.
+	var groupingRule *domain.GroupingRule
+	if !em.GroupingDisabled {
+		groupingRuleID := em.GroupingRuleFor(event)
+		if groupingRuleID == "" {
+			groupingRuleID = s.groupingDefaults.RuleID()
+		}
+		if groupingRuleID != "" {
+			groupingRule, err = s.groupingRuleRepo.GetByID(ctx, groupingRuleID)
+			if err != nil {
+				if errors.Is(err, domain.ErrGroupingRuleNotFound) {
+					s.logger.WarnContext(ctx, "grouping rule not found", "grouping_rule_id", groupingRuleID)
+					return nil, ErrGroupingRuleNotFound
+				}
+				s.logger.ErrorContext(ctx, "failed to fetch grouping rule", "error", err)
+				return nil, fmt.Errorf("failed to fetch grouping rule: %w", err)
+			}
+		}
+	}
+
+	// Step 3: Extract the grouping value from the event
+	// Without a grouping rule every event is an independent parent, so only
+	// events with the same dedup key need to be processed in order.
+	var groupingValue string
+	orderingValue := event.DedupKey
+	if groupingRule != nil {
+		groupingValue = groupingRule.ExtractGroupingValue(event)
+		orderingValue = groupingValue
+	}
+
+	// Step 4: Compute partition key
+	// Events with the same partition key go to the same partition,
+	// ensuring they are processed in order by a single consumer.
+	partitionKey := computePartitionKey(event.EventManagerID, orderingValue)
+
+	return &routed{
+		em:               em,
+		groupingRule:     groupingRule,
+		originalDedupKey: originalDedupKey,
+		groupingValue:    groupingValue,
+		partitionKey:     partitionKey,
+	}, nil
+}
+
+// groupingRuleID returns the ID of the grouping rule of the route, if any.
+func (r *routed) groupingRuleID() string {
+	if r.groupingRule == nil {
+		return ""
+	}
+	return r.groupingRule.ID
 }
 
 // computePartitionKey generates a deterministic partition key for an event.
@@ -3,12 +3,15 @@ package ingest
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"argus-go/internal/domain"
+	"argus-go/internal/logging"
 	"argus-go/internal/queue"
 	"argus-go/internal/queue/memory"
 	storemem "argus-go/internal/store/memory"
@@ -21,7 +24,7 @@ func TestService_IngestEvent(t *testing.T) {
 	eventManagerRepo := storemem.NewEventManagerRepository()
 	groupingRuleRepo := storemem.NewGroupingRuleRepository()
 
-	service := NewService(msgQueue, eventManagerRepo, groupingRuleRepo, logger)
+	service := NewService(msgQueue, eventManagerRepo, groupingRuleRepo, nil, nil, nil, nil, logger)
 
 	// Create test data
 	ctx := context.Background()
@@ -71,7 +74,7 @@ func TestService_IngestEvent_EventManagerNotFound(t *testing.T) {
 	eventManagerRepo := storemem.NewEventManagerRepository()
 	groupingRuleRepo := storemem.NewGroupingRuleRepository()
 
-	service := NewService(msgQueue, eventManagerRepo, groupingRuleRepo, logger)
+	service := NewService(msgQueue, eventManagerRepo, groupingRuleRepo, nil, nil, nil, nil, logger)
 
 	// Test with non-existent event manager
 	event := &domain.Event{
@@ -89,6 +92,38 @@ func TestService_IngestEvent_EventManagerNotFound(t *testing.T) {
 	}
 }
 
+func TestService_IngestEvent_EventManagerDeleted(t *testing.T) {
+	// Setup
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	msgQueue := memory.NewQueue(100)
+	eventManagerRepo := storemem.NewEventManagerRepository()
+	groupingRuleRepo := storemem.NewGroupingRuleRepository()
+
+	service := NewService(msgQueue, eventManagerRepo, groupingRuleRepo, nil, nil, nil, nil, logger)
+
+	ctx := context.Background()
+
+	_ = eventManagerRepo.Create(ctx, &domain.EventManager{ID: "em-1", Name: "Test EM", GroupingRuleID: "rule-1"})
+	_ = eventManagerRepo.Delete(ctx, "em-1")
+
+	event := &domain.Event{
+		EventManagerID: "em-1",
+		Summary:        "Test alert",
+		Severity:       domain.SeverityHigh,
+		Action:         domain.ActionTrigger,
+		Class:          "database",
+		DedupKey:       "alert-1",
+	}
+
+	err := service.IngestEvent(ctx, event)
+	if err != ErrEventManagerDeleted {
+		t.Errorf("Expected ErrEventManagerDeleted, got %v", err)
+	}
+	if msgQueue.Len() != 0 {
+		t.Errorf("Queue should be empty, got %d", msgQueue.Len())
+	}
+}
+
 func TestService_IngestEvent_GroupingRuleNotFound(t *testing.T) {
 	// Setup
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
@@ -96,7 +131,7 @@ func TestService_IngestEvent_GroupingRuleNotFound(t *testing.T) {
 	eventManagerRepo := storemem.NewEventManagerRepository()
 	groupingRuleRepo := storemem.NewGroupingRuleRepository()
 
-	service := NewService(msgQueue, eventManagerRepo, groupingRuleRepo, logger)
+	service := NewService(msgQueue, eventManagerRepo, groupingRuleRepo, nil, nil, nil, nil, logger)
 
 	ctx := context.Background()
 
@@ -131,7 +166,7 @@ func TestService_IngestEvent_MessageFormat(t *testing.T) {
 	eventManagerRepo := storemem.NewEventManagerRepository()
 	groupingRuleRepo := storemem.NewGroupingRuleRepository()
 
-	service := NewService(msgQueue, eventManagerRepo, groupingRuleRepo, logger)
+	service := NewService(msgQueue, eventManagerRepo, groupingRuleRepo, nil, nil, nil, nil, logger)
 
 	ctx := context.Background()
 
@@ -161,15 +196,17 @@ func TestService_IngestEvent_MessageFormat(t *testing.T) {
 		DedupKey:       "alert-1",
 	}
 
-	_ = service.IngestEvent(ctx, event)
+	_ = service.IngestEvent(logging.WithCorrelationID(ctx, "req-1"), event)
 
 	// Start a consumer to read the message
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
 
 	var receivedEvent domain.InternalEvent
+	var headers map[string]string
 	_ = msgQueue.Start(ctx, func(ctx context.Context, msg *queue.Message) error {
 		_ = json.Unmarshal(msg.Value, &receivedEvent)
+		headers = msg.Headers
 		return nil
 	})
 
@@ -186,6 +223,51 @@ func TestService_IngestEvent_MessageFormat(t *testing.T) {
 	if receivedEvent.ReceivedAt.IsZero() {
 		t.Error("ReceivedAt should be set")
 	}
+	if receivedEvent.CorrelationID != "req-1" || headers[queue.CorrelationIDHeader] != "req-1" {
+		t.Errorf("CorrelationID = %q, header = %q, want req-1", receivedEvent.CorrelationID, headers[queue.CorrelationIDHeader])
+	}
+}
+
+func TestService_IngestEvent_Scrubs(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	msgQueue := memory.NewQueue(100)
+	eventManagerRepo := storemem.NewEventManagerRepository()
+	scrubber, err := domain.NewScrubber([]domain.ScrubRule{{Pattern: `[a-z]+@example\.com`, Replacement: "[email]"}}, nil)
+	if err != nil {
+		t.Fatalf("NewScrubber() error = %v", err)
+	}
+
+	service := NewService(msgQueue, eventManagerRepo, storemem.NewGroupingRuleRepository(), nil, nil, scrubber, nil, logger)
+
+	ctx := context.Background()
+	_ = eventManagerRepo.Create(ctx, &domain.EventManager{ID: "em-1", Name: "Test EM", GroupingDisabled: true, CreatedAt: time.Now()})
+
+	labels := map[string]string{"user": "jane@example.com"}
+	event := &domain.Event{
+		EventManagerID: "em-1",
+		Summary:        "Login failed for jane@example.com",
+		Severity:       domain.SeverityHigh,
+		Action:         domain.ActionTrigger,
+		DedupKey:       "login-failed",
+		Labels:         labels,
+	}
+	if err := service.IngestEvent(ctx, event); err != nil {
+		t.Fatalf("IngestEvent() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	var received domain.InternalEvent
+	_ = msgQueue.Start(ctx, func(ctx context.Context, msg *queue.Message) error {
+		return json.Unmarshal(msg.Value, &received)
+	})
+
+	if received.Summary != "Login failed for [email]" || received.Labels["user"] != "[email]" {
+		t.Errorf("queued summary = %q, labels = %v, want them scrubbed", received.Summary, received.Labels)
+	}
+	if labels["user"] != "jane@example.com" {
+		t.Error("the labels of the caller were changed")
+	}
 }
 
 func TestComputePartitionKey(t *testing.T) {
@@ -213,3 +295,204 @@ func TestComputePartitionKey(t *testing.T) {
 		t.Error("Partition key should not be empty")
 	}
 }
+
+func TestService_IngestEvent_GroupingRulePrecedence(t *testing.T) {
+	// Setup
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	msgQueue := memory.NewQueue(100)
+	eventManagerRepo := storemem.NewEventManagerRepository()
+	groupingRuleRepo := storemem.NewGroupingRuleRepository()
+
+	service := NewService(msgQueue, eventManagerRepo, groupingRuleRepo, nil, nil, nil, nil, logger)
+
+	ctx := context.Background()
+
+	_ = groupingRuleRepo.Create(ctx, &domain.GroupingRule{ID: "by-class", Name: "By class", GroupingKey: "class", TimeWindowMinutes: 5})
+	_ = groupingRuleRepo.Create(ctx, &domain.GroupingRule{ID: "by-severity", Name: "By severity", GroupingKey: "severity", TimeWindowMinutes: 5})
+
+	eventManager := &domain.EventManager{
+		ID:             "em-1",
+		Name:           "Test EM",
+		GroupingRuleID: "by-class",
+		GroupingRules: []domain.GroupingRuleBinding{
+			{GroupingRuleID: "by-severity", Match: domain.EventMatcher{Classes: []string{"database"}}},
+		},
+	}
+	_ = eventManagerRepo.Create(ctx, eventManager)
+
+	for _, class := range []string{"database", "network"} {
+		event := &domain.Event{
+			EventManagerID: "em-1",
+			Summary:        "Test alert",
+			Severity:       domain.SeverityHigh,
+			Action:         domain.ActionTrigger,
+			Class:          class,
+			DedupKey:       "alert-" + class,
+		}
+		if err := service.IngestEvent(ctx, event); err != nil {
+			t.Fatalf("IngestEvent(%s) error: %v", class, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	received := make(map[string]domain.InternalEvent)
+	_ = msgQueue.Start(ctx, func(ctx context.Context, msg *queue.Message) error {
+		var ie domain.InternalEvent
+		_ = json.Unmarshal(msg.Value, &ie)
+		received[ie.Class] = ie
+		return nil
+	})
+
+	// The matching rule groups database events by severity
+	if got := received["database"]; got.GroupingRuleID != "by-severity" || got.GroupingValue != "high" {
+		t.Errorf("database event grouped by %q/%q, want by-severity/high", got.GroupingRuleID, got.GroupingValue)
+	}
+	// Other events fall back to the default rule
+	if got := received["network"]; got.GroupingRuleID != "by-class" || got.GroupingValue != "network" {
+		t.Errorf("network event grouped by %q/%q, want by-class/network", got.GroupingRuleID, got.GroupingValue)
+	}
+}
+
+func TestService_IngestEvent_NormalizesDedupKey(t *testing.T) {
+	// Setup
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	msgQueue := memory.NewQueue(100)
+	eventManagerRepo := storemem.NewEventManagerRepository()
+	groupingRuleRepo := storemem.NewGroupingRuleRepository()
+
+	service := NewService(msgQueue, eventManagerRepo, groupingRuleRepo, nil, nil, nil, nil, logger)
+
+	ctx := context.Background()
+
+	_ = eventManagerRepo.Create(ctx, &domain.EventManager{
+		ID:               "em-1",
+		Name:             "Test EM",
+		GroupingDisabled: true,
+		DedupKeyConfig:   domain.DedupKeyConfig{Trim: true, Lowercase: true, HashThreshold: domain.HashedDedupKeyLength},
+	})
+
+	longKey := strings.Repeat("k", 300)
+	for _, key := range []string{" Host-1 ", longKey} {
+		event := &domain.Event{
+			EventManagerID: "em-1",
+			Summary:        "Test alert",
+			Severity:       domain.SeverityHigh,
+			Action:         domain.ActionTrigger,
+			Class:          "database",
+			DedupKey:       key,
+		}
+		if err := service.IngestEvent(ctx, event); err != nil {
+			t.Fatalf("IngestEvent error: %v", err)
+		}
+	}
+
+	// Whitespace-only keys are empty after trimming
+	blank := &domain.Event{EventManagerID: "em-1", Action: domain.ActionTrigger, DedupKey: "   "}
+	if err := service.IngestEvent(ctx, blank); !errors.Is(err, domain.ErrEmptyDedupKey) {
+		t.Errorf("IngestEvent(blank key) error = %v, want %v", err, domain.ErrEmptyDedupKey)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var received []domain.InternalEvent
+	_ = msgQueue.Start(ctx, func(ctx context.Context, msg *queue.Message) error {
+		var ie domain.InternalEvent
+		_ = json.Unmarshal(msg.Value, &ie)
+		received = append(received, ie)
+		return nil
+	})
+
+	if len(received) != 2 {
+		t.Fatalf("received %d events, want 2", len(received))
+	}
+	if received[0].DedupKey != "host-1" || received[0].OriginalDedupKey != "" {
+		t.Errorf("short key = %q (original %q), want host-1 without original", received[0].DedupKey, received[0].OriginalDedupKey)
+	}
+	if !strings.HasPrefix(received[1].DedupKey, "sha256:") || received[1].OriginalDedupKey != longKey {
+		t.Errorf("long key = %q (original %q), want a hash with the original kept", received[1].DedupKey, received[1].OriginalDedupKey)
+	}
+}
+
+func TestService_PublishResolve(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	msgQueue := memory.NewQueue(100)
+	eventManagerRepo := storemem.NewEventManagerRepository()
+	service := NewService(msgQueue, eventManagerRepo, storemem.NewGroupingRuleRepository(), nil, nil, nil, nil, logger)
+
+	ctx := context.Background()
+	_ = eventManagerRepo.Create(ctx, &domain.EventManager{ID: "em-1", Name: "Test EM", CreatedAt: time.Now()})
+
+	alert := &domain.Alert{DedupKey: "alert-1", EventManagerID: "em-1", Severity: domain.SeverityHigh, Class: "database"}
+	resolution := domain.Resolution{ResolvedBy: domain.ResolvedByAPI, Actor: "alice", Reason: "fixed"}
+	if err := service.PublishResolve(ctx, alert, "partition-1", resolution); err != nil {
+		t.Fatalf("PublishResolve() error = %v", err)
+	}
+	// Alerts without a recorded partition key fall back to their dedup key
+	if err := service.PublishResolve(ctx, alert, "", resolution); err != nil {
+		t.Fatalf("PublishResolve() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+
+	var keys []string
+	var events []domain.InternalEvent
+	_ = msgQueue.Start(ctx, func(ctx context.Context, msg *queue.Message) error {
+		var event domain.InternalEvent
+		_ = json.Unmarshal(msg.Value, &event)
+		keys = append(keys, string(msg.Key))
+		events = append(events, event)
+		return nil
+	})
+
+	if len(events) != 2 {
+		t.Fatalf("published %d messages, want 2", len(events))
+	}
+	if keys[0] != "partition-1" || keys[1] != computePartitionKey("em-1", "alert-1") {
+		t.Errorf("partition keys = %v, want partition-1 and the key of the dedup key", keys)
+	}
+	event := events[0]
+	if event.Action != domain.ActionResolve || event.DedupKey != "alert-1" || event.Resolution == nil || *event.Resolution != resolution {
+		t.Errorf("published event = %+v, want a resolve of alert-1 with the resolution", event)
+	}
+}
+
+func TestService_PublishPendingResolveSweep(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	msgQueue := memory.NewQueue(100)
+	eventManagerRepo := storemem.NewEventManagerRepository()
+	service := NewService(msgQueue, eventManagerRepo, storemem.NewGroupingRuleRepository(), nil, nil, nil, nil, logger)
+
+	ctx := context.Background()
+	_ = eventManagerRepo.Create(ctx, &domain.EventManager{ID: "em-1", Name: "Test EM", CreatedAt: time.Now()})
+
+	alert := &domain.Alert{DedupKey: "alert-1", EventManagerID: "em-1", Severity: domain.SeverityHigh, Class: "database"}
+	if err := service.PublishPendingResolveSweep(ctx, alert, "partition-1"); err != nil {
+		t.Fatalf("PublishPendingResolveSweep() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+
+	var keys []string
+	var events []domain.InternalEvent
+	_ = msgQueue.Start(ctx, func(ctx context.Context, msg *queue.Message) error {
+		var event domain.InternalEvent
+		_ = json.Unmarshal(msg.Value, &event)
+		keys = append(keys, string(msg.Key))
+		events = append(events, event)
+		return nil
+	})
+
+	// The check travels in order with the events of the alert's group
+	if len(events) != 1 || keys[0] != "partition-1" {
+		t.Fatalf("published %d messages with keys %v, want 1 under partition-1", len(events), keys)
+	}
+	event := events[0]
+	if event.Action != domain.ActionResolve || event.DedupKey != "alert-1" || !event.SweepPendingResolve || event.Resolution != nil {
+		t.Errorf("published event = %+v, want a pending resolve check of alert-1", event)
+	}
+}
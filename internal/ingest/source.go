@@ -0,0 +1,135 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	"argus-go/internal/logging"
+	"argus-go/internal/metrics"
+	"argus-go/internal/queue"
+)
+
+// Results of source messages, used as the label of the source metric.
+const (
+	sourceIngested = "ingested"
+	sourceInvalid  = "invalid"
+	sourceRejected = "rejected"
+	sourceFailed   = "failed"
+)
+
+// SourceConsumer ingests the messages of an external topic as events, for
+// producers that already publish alerts to Kafka or MQTT. Each message is
+// mapped to an event, routed and ingested exactly like an event posted to
+// the API.
+type SourceConsumer struct {
+	cfg      config.SourceConsumerConfig
+	consumer queue.Consumer
+	service  *Service
+	router   *Router
+	logger   *slog.Logger
+}
+
+// NewSourceConsumer creates a consumer of the configured source topic.
+func NewSourceConsumer(
+	cfg config.SourceConsumerConfig,
+	consumer queue.Consumer,
+	service *Service,
+	router *Router,
+	logger *slog.Logger,
+) *SourceConsumer {
+	return &SourceConsumer{
+		cfg:      cfg,
+		consumer: consumer,
+		service:  service,
+		router:   router,
+		logger:   logger.With("source", cfg.Name),
+	}
+}
+
+// Start consumes the topic until the context is canceled.
+func (s *SourceConsumer) Start(ctx context.Context) error {
+	s.logger.InfoContext(ctx, "starting source consumer", "type", s.cfg.Type)
+	return s.consumer.Start(ctx, s.handleMessage)
+}
+
+// Close stops consuming the topic.
+func (s *SourceConsumer) Close() error {
+	return s.consumer.Close()
+}
+
+// handleMessage ingests one message. Messages that can't become a valid event
+// are logged and skipped, since consuming them again would fail the same way,
+// and so are events rejected by a quota; only failures to ingest are returned.
+func (s *SourceConsumer) handleMessage(ctx context.Context, msg *queue.Message) error {
+	// Producers may correlate their messages with their own logs and traces
+	ctx = logging.WithCorrelationID(ctx, msg.Headers[queue.CorrelationIDHeader])
+	ctx = logging.WithTraceContext(ctx, logging.TraceContext{
+		TraceParent: msg.Headers[queue.TraceParentHeader],
+		TraceState:  msg.Headers[queue.TraceStateHeader],
+	})
+
+	event, err := s.toEvent(msg.Value)
+	if err == nil {
+		err = s.router.Route(ctx, event)
+	}
+	if err == nil {
+		err = event.Validate()
+	}
+	if err != nil {
+		s.logger.WarnContext(ctx, "skipping invalid source message", "error", err)
+		metrics.SourceMessages.WithLabelValues(s.cfg.Name, sourceInvalid).Inc()
+		return nil
+	}
+
+	if err := s.service.IngestEvent(ctx, event); err != nil {
+		if errors.Is(err, ErrEventManagerNotFound) || errors.Is(err, ErrEventManagerDeleted) || errors.Is(err, domain.ErrEmptyDedupKey) {
+			s.logger.WarnContext(ctx, "skipping source event", "dedupKey", event.DedupKey, "error", err)
+			metrics.SourceMessages.WithLabelValues(s.cfg.Name, sourceInvalid).Inc()
+			return nil
+		}
+		if errors.Is(err, domain.ErrQuotaExceeded) {
+			s.logger.WarnContext(ctx, "skipping source event over quota", "dedupKey", event.DedupKey, "error", err)
+			metrics.SourceMessages.WithLabelValues(s.cfg.Name, sourceRejected).Inc()
+			return nil
+		}
+		metrics.SourceMessages.WithLabelValues(s.cfg.Name, sourceFailed).Inc()
+		return fmt.Errorf("failed to ingest source event: %w", err)
+	}
+
+	metrics.SourceMessages.WithLabelValues(s.cfg.Name, sourceIngested).Inc()
+	return nil
+}
+
+// toEvent maps a message to an event, with the source mapping if it has one.
+func (s *SourceConsumer) toEvent(value []byte) (*domain.Event, error) {
+	var event *domain.Event
+	if s.cfg.Mapping.IsEnabled() {
+		var payload any
+		if err := json.Unmarshal(value, &payload); err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidWebhookPayload, err)
+		}
+		mapped, err := s.cfg.Mapping.Apply(payload)
+		if err != nil {
+			return nil, err
+		}
+		event = mapped
+	} else {
+		event = &domain.Event{}
+		if err := json.Unmarshal(value, event); err != nil {
+			return nil, fmt.Errorf("invalid event: %w", err)
+		}
+	}
+
+	if s.cfg.EventManagerID != "" {
+		if event.EventManagerID != "" && event.EventManagerID != s.cfg.EventManagerID {
+			return nil, errors.New("event_manager_id does not match the source")
+		}
+		event.EventManagerID = s.cfg.EventManagerID
+	}
+	return event, nil
+}
@@ -0,0 +1,96 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	"argus-go/internal/queue"
+	"argus-go/internal/queue/memory"
+	storemem "argus-go/internal/store/memory"
+)
+
+func newTestSourceConsumer(t *testing.T, cfg config.SourceConsumerConfig) (*SourceConsumer, *memory.Queue) {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	msgQueue := memory.NewQueue(100)
+	eventManagerRepo := storemem.NewEventManagerRepository()
+	groupingRuleRepo := storemem.NewGroupingRuleRepository()
+
+	ctx := context.Background()
+	_ = groupingRuleRepo.Create(ctx, &domain.GroupingRule{ID: "rule-1", Name: "Test Rule", GroupingKey: "class", TimeWindowMinutes: 5, CreatedAt: time.Now()})
+	_ = eventManagerRepo.Create(ctx, &domain.EventManager{ID: "em-1", Name: "Test EM", GroupingRuleID: "rule-1", CreatedAt: time.Now()})
+
+	service := NewService(msgQueue, eventManagerRepo, groupingRuleRepo, nil, nil, nil, nil, logger)
+	router := NewRouter(storemem.NewRoutingRuleRepository(), logger)
+	return NewSourceConsumer(cfg, memory.NewQueue(1), service, router, logger), msgQueue
+}
+
+func TestSourceConsumer_HandleMessage(t *testing.T) {
+	source, msgQueue := newTestSourceConsumer(t, config.SourceConsumerConfig{Name: "legacy", EventManagerID: "em-1"})
+
+	value := `{"summary":"Disk full","severity":"high","action":"trigger","class":"disk","dedupKey":"disk-1"}`
+	if err := source.handleMessage(context.Background(), &queue.Message{Value: []byte(value)}); err != nil {
+		t.Fatalf("handleMessage() error = %v", err)
+	}
+	if msgQueue.Len() != 1 {
+		t.Fatalf("Queue should have 1 message, got %d", msgQueue.Len())
+	}
+
+	// Invalid messages are skipped, not retried
+	for _, value := range []string{
+		`not json`,
+		`{"summary":"Disk full","action":"trigger"}`,
+		`{"event_manager_id":"em-2","summary":"Disk full","action":"trigger","dedupKey":"disk-2"}`,
+	} {
+		if err := source.handleMessage(context.Background(), &queue.Message{Value: []byte(value)}); err != nil {
+			t.Errorf("handleMessage(%s) error = %v, want nil", value, err)
+		}
+	}
+	if msgQueue.Len() != 1 {
+		t.Errorf("Queue should still have 1 message, got %d", msgQueue.Len())
+	}
+}
+
+func TestSourceConsumer_HandleMessageWithMapping(t *testing.T) {
+	source, msgQueue := newTestSourceConsumer(t, config.SourceConsumerConfig{
+		Name:           "zabbix",
+		EventManagerID: "em-1",
+		Mapping: domain.WebhookTransform{
+			DedupKey: `"zabbix-" + payload.trigger_id`,
+			Summary:  `payload.name`,
+			Action:   `payload.status == "OK" ? "resolve" : "trigger"`,
+			Class:    `payload.host`,
+		},
+	})
+
+	value := `{"trigger_id":"1042","name":"High CPU on web-1","status":"PROBLEM","host":"web-1"}`
+	if err := source.handleMessage(context.Background(), &queue.Message{Value: []byte(value)}); err != nil {
+		t.Fatalf("handleMessage() error = %v", err)
+	}
+	if msgQueue.Len() != 1 {
+		t.Fatalf("Queue should have 1 message, got %d", msgQueue.Len())
+	}
+
+	var published domain.Event
+	msgQueue.Start(contextAfterOne(t), func(ctx context.Context, msg *queue.Message) error {
+		return json.Unmarshal(msg.Value, &published)
+	})
+	if published.DedupKey != "zabbix-1042" || published.Summary != "High CPU on web-1" || published.Class != "web-1" || published.Action != domain.ActionTrigger {
+		t.Errorf("published event = %+v", published)
+	}
+}
+
+// contextAfterOne returns a context canceled shortly after the test starts
+// consuming, long enough for a queued message to be handled.
+func contextAfterOne(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	t.Cleanup(cancel)
+	return ctx
+}
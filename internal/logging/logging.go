@@ -0,0 +1,120 @@
+// Package logging threads correlation IDs and trace contexts through
+// contexts into logs, so the logs of an event can be followed from its
+// ingest request to the processor that handles it.
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// Log attributes of correlation IDs and trace IDs.
+const (
+	CorrelationIDKey = "correlation_id"
+	TraceIDKey       = "trace_id"
+)
+
+// correlationKey is the context key of the correlation ID.
+type correlationKey struct{}
+
+// WithCorrelationID returns a context whose logs carry the correlation ID.
+// An empty ID leaves the context unchanged.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID returns the correlation ID of a context, or "" if none.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// TraceContext is a W3C trace context, as in the traceparent and
+// tracestate HTTP headers.
+type TraceContext struct {
+	TraceParent string
+	TraceState  string
+}
+
+// TraceID returns the trace ID of the traceparent, or "" if it is not a
+// valid traceparent.
+func (t TraceContext) TraceID() string {
+	// version-traceid-parentid-flags, e.g. 00-4bf9...4736-00f0...02b7-01
+	parts := strings.Split(t.TraceParent, "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return ""
+	}
+	if !isLowerHex(parts[1]) || strings.Trim(parts[1], "0") == "" {
+		return ""
+	}
+	return parts[1]
+}
+
+// isLowerHex returns true if s consists of lowercase hex digits only.
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// traceKey is the context key of the trace context.
+type traceKey struct{}
+
+// WithTraceContext returns a context carrying the trace context, whose logs
+// carry its trace ID. A context without a valid traceparent is left
+// unchanged, so malformed trace contexts are not passed on.
+func WithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	if tc.TraceID() == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, traceKey{}, tc)
+}
+
+// TraceContextFrom returns the trace context of a context, or the zero
+// TraceContext if none.
+func TraceContextFrom(ctx context.Context) TraceContext {
+	tc, _ := ctx.Value(traceKey{}).(TraceContext)
+	return tc
+}
+
+// Handler adds the correlation ID and trace ID of the context to the
+// records logged with it, e.g. with Logger.InfoContext.
+type Handler struct {
+	slog.Handler
+}
+
+// NewHandler wraps a handler to add correlation IDs.
+func NewHandler(h slog.Handler) *Handler {
+	return &Handler{Handler: h}
+}
+
+// Handle adds the correlation ID and trace ID of ctx, if any, and passes
+// the record on.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if id := CorrelationID(ctx); id != "" {
+		r.AddAttrs(slog.String(CorrelationIDKey, id))
+	}
+	if id := TraceContextFrom(ctx).TraceID(); id != "" {
+		r.AddAttrs(slog.String(TraceIDKey, id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a Handler adding correlation IDs to the handler with
+// the attributes.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a Handler adding correlation IDs to the handler with
+// the group.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestHandler_AddsCorrelationID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil))).With("component", "processor")
+
+	ctx := WithCorrelationID(context.Background(), "req-1")
+	logger.InfoContext(ctx, "processing event")
+	logger.InfoContext(context.Background(), "no request")
+
+	var lines []map[string]any
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var line map[string]any
+		if err := dec.Decode(&line); err != nil {
+			t.Fatalf("invalid log line: %v", err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("logged %d lines, want 2", len(lines))
+	}
+	if lines[0][CorrelationIDKey] != "req-1" || lines[0]["component"] != "processor" {
+		t.Errorf("first line = %v, want correlation_id req-1 and component processor", lines[0])
+	}
+	if _, ok := lines[1][CorrelationIDKey]; ok {
+		t.Errorf("second line = %v, want no correlation_id", lines[1])
+	}
+}
+
+func TestTraceContext_TraceID(t *testing.T) {
+	tests := []struct {
+		traceParent string
+		want        string
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", ""},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", ""},
+		{"00-4bf92f3577b34da6-00f067aa0ba902b7-01", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := (TraceContext{TraceParent: tt.traceParent}).TraceID(); got != tt.want {
+			t.Errorf("TraceID(%q) = %q, want %q", tt.traceParent, got, tt.want)
+		}
+	}
+
+	ctx := WithTraceContext(context.Background(), TraceContext{TraceParent: "invalid"})
+	if tc := TraceContextFrom(ctx); tc.TraceParent != "" {
+		t.Errorf("TraceContextFrom() = %+v, want an invalid trace context dropped", tc)
+	}
+}
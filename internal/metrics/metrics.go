@@ -0,0 +1,438 @@
+// Package metrics defines the Prometheus metrics exported by ArgusGo.
+// Collectors are registered with the default registry and served on /metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "argus"
+
+var (
+	// AlertCreationLatency measures the time from event ingestion until the
+	// resulting alert is persisted, labelled by alert type (parent or child).
+	AlertCreationLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "alert_creation_latency_seconds",
+		Help:      "Time from event ingestion to alert creation.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"type"})
+
+	// GroupingDecisions counts the alerts created, labelled by grouping
+	// outcome: "parent" for a new parent under a grouping rule, "child" for an
+	// alert attached to an open parent and "standalone" for an alert of an
+	// event manager without grouping. The share of children is the share of
+	// alerts grouping kept from notifying.
+	GroupingDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grouping_decisions_total",
+		Help:      "Alerts created, by grouping outcome (parent, child or standalone).",
+	}, []string{"outcome"})
+
+	// DuplicateTriggers counts the triggers of an already active alert,
+	// which only increment its trigger count, labelled by the event manager
+	// of the event.
+	DuplicateTriggers = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "duplicate_triggers_total",
+		Help:      "Triggers of an already active alert, suppressed by deduplication.",
+	}, []string{"event_manager_id"})
+
+	// AlertReactivations counts the resolved alerts reactivated by a new
+	// trigger, labelled by the event manager of the event.
+	AlertReactivations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "alert_reactivations_total",
+		Help:      "Resolved alerts reactivated by a new trigger.",
+	}, []string{"event_manager_id"})
+
+	// AlertTransitions counts the transitions of the alert state machine,
+	// labelled by transition and the state it entered.
+	AlertTransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "alert_transitions_total",
+		Help:      "Transitions of alerts between lifecycle states.",
+	}, []string{"transition", "state"})
+
+	// UnknownResolves counts the resolve events of dedup keys without an
+	// alert, labelled by the event manager of the event.
+	UnknownResolves = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "unknown_resolves_total",
+		Help:      "Resolve events for dedup keys without an alert.",
+	}, []string{"event_manager_id"})
+
+	// ActiveAlerts reports the active alerts, labelled by event manager and
+	// alert type. The processor updates it as it creates, resolves and
+	// reactivates alerts, and periodically resets it to the counts of the
+	// alert repository. Every replica reconciles to the fleet-wide counts, so
+	// aggregate replicas with max rather than sum.
+	ActiveAlerts = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_alerts",
+		Help:      "Active alerts, by event manager and type.",
+	}, []string{"event_manager_id", "type"})
+
+	// AlertActive exports the alert state for Prometheus rules and
+	// dashboards: the active alerts, parents and children, labelled by event
+	// manager, severity and class. It is maintained and reconciled with
+	// ActiveAlerts; the number of series grows with the classes in use.
+	AlertActive = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "alert_active",
+		Help:      "Active alerts, by event manager, severity and class.",
+	}, []string{"event_manager_id", "severity", "class"})
+
+	// MutedAlerts reports the active alerts whose notifications are muted,
+	// labelled by event manager and status: "silenced" or "suppressed". It
+	// is reset with ActiveAlerts, so it follows silences and quotas as they
+	// start and end at the pace of reconciliation.
+	MutedAlerts = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "muted_alerts",
+		Help:      "Active alerts whose notifications are muted, by event manager and status (silenced or suppressed).",
+	}, []string{"event_manager_id", "status"})
+
+	// NotificationFailures counts the notifications that failed, labelled by
+	// what failed: "record" for the notification log, "test_delivery" for
+	// test notifications.
+	NotificationFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notification_failures_total",
+		Help:      "Failed notifications, by what failed (record or test_delivery).",
+	}, []string{"reason"})
+
+	// WatchNotifications counts the personal notifications sent for alert
+	// watches, labelled by channel ("email" or "slack").
+	WatchNotifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "watch_notifications_total",
+		Help:      "Personal notifications sent for alert watches, by channel (email or slack).",
+	}, []string{"channel"})
+
+	// NotificationDeliveries counts the deliveries of notifications to their
+	// channels, labelled by channel ("plugin:<name>", "queue:<name>" or
+	// "watch:<type>") and result ("success" or "failure").
+	NotificationDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notification_deliveries_total",
+		Help:      "Notification deliveries, by channel and result (success or failure).",
+	}, []string{"channel", "result"})
+
+	// NotificationDeliveryLatency measures how long the deliveries of
+	// notifications take, labelled by channel.
+	NotificationDeliveryLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "notification_delivery_duration_seconds",
+		Help:      "Time to deliver a notification, by channel.",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"channel"})
+
+	// NotificationChannelSuccessRate is the fraction of the recent deliveries
+	// of a channel that succeeded, labelled by channel.
+	NotificationChannelSuccessRate = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "notification_channel_success_ratio",
+		Help:      "Fraction of the recent notification deliveries that succeeded, by channel.",
+	}, []string{"channel"})
+
+	// NotificationChannelP95Latency is the 95th percentile latency of the
+	// recent deliveries of a channel, labelled by channel.
+	NotificationChannelP95Latency = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "notification_channel_delivery_p95_seconds",
+		Help:      "95th percentile latency of the recent notification deliveries, by channel.",
+	}, []string{"channel"})
+
+	// AlertFeedback counts the feedback of responders on alerts, labelled by
+	// event manager, grouping rule (empty for alerts without one) and rating
+	// ("useful", "noisy" or "wrongly_grouped").
+	AlertFeedback = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "alert_feedback_total",
+		Help:      "Responder feedback on alerts, by event manager, grouping rule and rating (useful, noisy or wrongly_grouped).",
+	}, []string{"event_manager_id", "grouping_rule_id", "rating"})
+
+	// SilencedNotifications counts the notifications muted by a silence,
+	// labelled by notification kind.
+	SilencedNotifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "silenced_notifications_total",
+		Help:      "Notifications muted by a silence, by notification kind.",
+	}, []string{"kind"})
+
+	// SuppressedNotifications counts the notifications suppressed by the
+	// quota of their event manager, labelled by notification kind.
+	SuppressedNotifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "suppressed_notifications_total",
+		Help:      "Notifications suppressed by the quota of their event manager, by notification kind.",
+	}, []string{"kind"})
+
+	// PendingResolvesRecovered counts the parent alerts the pending resolve
+	// sweep resolved, left waiting for children that had resolved.
+	PendingResolvesRecovered = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pending_resolves_recovered_total",
+		Help:      "Parent alerts resolved by the pending resolve sweep, left waiting for resolved children.",
+	})
+
+	// PendingResolvesForced counts the parent alerts force-resolved after
+	// their resolve waited longer than the maximum for their children.
+	PendingResolvesForced = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pending_resolves_forced_total",
+		Help:      "Parent alerts force-resolved after their resolve waited longer than the maximum for their children.",
+	})
+
+	// RemediationActions counts the remediation actions run for alerts,
+	// labelled by outcome: "succeeded", "failed" or "skipped" (cooling down).
+	RemediationActions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "remediation_actions_total",
+		Help:      "Remediation actions run for alerts, by outcome (succeeded, failed or skipped).",
+	}, []string{"status"})
+
+	// GroupingCacheLookups counts the lookups of an open parent in the state
+	// store, labelled by result ("hit" or "miss").
+	GroupingCacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grouping_cache_lookups_total",
+		Help:      "Open parent lookups in the state store, by result (hit or miss).",
+	}, []string{"result"})
+
+	// AlertGroupSize observes the number of children attached to a parent
+	// alert, when the parent is resolved.
+	AlertGroupSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "alert_group_size",
+		Help:      "Children attached to a parent alert, observed when it is resolved.",
+		Buckets:   []float64{0, 1, 2, 5, 10, 25, 50, 100, 250, 500, 1000},
+	})
+
+	// StorageOperationLatency measures the duration of state store and
+	// repository operations, labelled by store and operation.
+	StorageOperationLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "storage_operation_latency_seconds",
+		Help:      "Duration of state store and repository operations.",
+		Buckets:   []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+	}, []string{"store", "operation"})
+
+	// StorageOperationsTotal counts state store and repository operations,
+	// labelled by store, operation and result ("ok" or "error").
+	StorageOperationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "storage_operations_total",
+		Help:      "State store and repository operations, by result (ok or error).",
+	}, []string{"store", "operation", "result"})
+
+	// DependencyUp reports whether an external dependency (Redis or
+	// PostgreSQL) passed its last health probe: 1 if so, 0 if degraded.
+	DependencyUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "dependency_up",
+		Help:      "Whether a dependency passed its last health probe (1) or is degraded (0).",
+	}, []string{"dependency"})
+
+	// ProcessorPaused reports whether event consumption is paused through
+	// the admin API: 1 if so, 0 if running.
+	ProcessorPaused = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "processor_paused",
+		Help:      "Whether event consumption is paused (1) or running (0).",
+	})
+
+	// CandidateGroupingDecisions counts the new alerts of event managers with
+	// a candidate grouping rule, labelled by event manager and by whether the
+	// candidate would have grouped them the same way ("match") or not
+	// ("diverged").
+	CandidateGroupingDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "candidate_grouping_decisions_total",
+		Help:      "Grouping decisions of candidate grouping rules, by whether they match the active rules.",
+	}, []string{"event_manager_id", "result"})
+
+	// ShadowNotifications counts the notifications a processor in shadow
+	// mode would have sent, labelled by event manager and type ("new_parent",
+	// "resolved", "reminder" or one of the optional lifecycle kinds such as
+	// "child_added").
+	ShadowNotifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "shadow_notifications_total",
+		Help:      "Notifications a processor in shadow mode would have sent, by type.",
+	}, []string{"event_manager_id", "type"})
+
+	// ProcessorRetries counts the retries of events that failed processing,
+	// labelled by error class.
+	ProcessorRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "processor_retries_total",
+		Help:      "Retries of events that failed processing, by error class.",
+	}, []string{"class"})
+
+	// ProcessorPoisonMessages counts the events given up on, because their
+	// error is not retryable or they still failed after the last retry,
+	// labelled by error class.
+	ProcessorPoisonMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "processor_poison_messages_total",
+		Help:      "Events given up on after failing processing, by error class.",
+	}, []string{"class"})
+
+	// SourceMessages counts the messages consumed from source topics,
+	// labelled by source and result: ingested, invalid, rejected or failed.
+	SourceMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "source_messages_total",
+		Help:      "Messages consumed from source topics, by source and result.",
+	}, []string{"source", "result"})
+
+	// QuotaUtilization reports the share of each quota limit an event
+	// manager used when last checked, labelled by event manager and quota
+	// (events_per_day, events_per_month or open_alerts).
+	QuotaUtilization = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "quota_utilization_ratio",
+		Help:      "Share of a quota limit used by an event manager.",
+	}, []string{"event_manager_id", "quota"})
+
+	// StormActive reports whether an event manager is in an alert storm on
+	// this replica: 1 if so, 0 once the storm ended.
+	StormActive = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "storm_active",
+		Help:      "Whether an event manager is in an alert storm (1) or not (0).",
+	}, []string{"event_manager_id"})
+
+	// QueryRuleEvaluations counts the runs of query rules, labelled by rule
+	// and result: ok or failed.
+	QueryRuleEvaluations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "query_rule_evaluations_total",
+		Help:      "Runs of query rules, by rule and result.",
+	}, []string{"rule_id", "result"})
+
+	// ScheduledReports counts the emails of scheduled reports, labelled by
+	// result: sent or failed. Failed reports are retried.
+	ScheduledReports = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scheduled_reports_total",
+		Help:      "Scheduled report emails, by result.",
+	}, []string{"result"})
+
+	// ScrubbedValues counts the summaries, labels and tags changed by scrub
+	// rules, labelled by rule.
+	ScrubbedValues = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scrubbed_values_total",
+		Help:      "Values masked by scrub rules, by rule.",
+	}, []string{"rule"})
+
+	// ArchivedEvents counts the events written to the event archive,
+	// labelled by result: archived, failed (kept to be retried) or dropped
+	// (the pending buffer was full).
+	ArchivedEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "archived_events_total",
+		Help:      "Events written to the event archive, by result.",
+	}, []string{"result"})
+
+	// PublishBufferDepth reports the events accepted by the ingest API and
+	// not yet published to the queue, with async ingest.
+	PublishBufferDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "publish_buffer_depth",
+		Help:      "Accepted events waiting to be published to the queue.",
+	})
+
+	// PublishBufferRejected counts the events rejected because the publish
+	// buffer was full.
+	PublishBufferRejected = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "publish_buffer_rejected_total",
+		Help:      "Events rejected because the publish buffer was full.",
+	})
+
+	// PublishBufferFailures counts the attempts to publish a buffered event
+	// that the queue failed; the event is retried.
+	PublishBufferFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "publish_buffer_failures_total",
+		Help:      "Failed attempts to publish a buffered event to the queue.",
+	})
+
+	// PublishBufferDropped counts the buffered events dropped because the
+	// queue failed them while the producer was closing.
+	PublishBufferDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "publish_buffer_dropped_total",
+		Help:      "Buffered events dropped because the queue failed them during shutdown.",
+	})
+
+	// QueryRulesOwned reports how many query rules this replica runs, out
+	// of all rules when they are sharded between replicas.
+	QueryRulesOwned = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "query_rules_owned",
+		Help:      "Query rules run by this replica.",
+	})
+
+	// DuplicateNotifications counts the notifications skipped because they
+	// were already sent, labelled by notification kind.
+	DuplicateNotifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "duplicate_notifications_total",
+		Help:      "Notifications skipped because they were already sent.",
+	}, []string{"kind"})
+
+	// SampledEvents counts the trigger events dropped by the sampling policy
+	// of their event manager, labelled by the event manager.
+	SampledEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sampled_events_total",
+		Help:      "Trigger events dropped by sampling.",
+	}, []string{"event_manager_id"})
+
+	// QuotaRejectedEvents counts the trigger events rejected because their
+	// event manager exhausted a quota, labelled by the event manager.
+	QuotaRejectedEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "quota_rejected_events_total",
+		Help:      "Trigger events rejected by an exhausted quota.",
+	}, []string{"event_manager_id"})
+
+	// ProcessorBacklog reports the events queued for the processor and not
+	// yet consumed, as far as the queue reports them.
+	ProcessorBacklog = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "processor_backlog",
+		Help:      "Events queued for the processor and not yet consumed.",
+	})
+
+	// ProcessorEventRate reports the events the processor handled per
+	// second, over the last scaling interval.
+	ProcessorEventRate = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "processor_events_per_second",
+		Help:      "Events handled by the processor per second.",
+	})
+
+	// ProcessorLag reports how long the last event processed waited in the
+	// queue, while the processor handles events.
+	ProcessorLag = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "processor_lag_seconds",
+		Help:      "Time the last processed event waited in the queue.",
+	})
+
+	// ScalingPressure reports the pressure on the processor for external
+	// autoscalers: 1 when it keeps up with its scaling targets, above 1
+	// when it needs more replicas.
+	ScalingPressure = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "processor_scaling_pressure",
+		Help:      "Pressure on the processor relative to its scaling targets; scale to keep it at 1.",
+	})
+)
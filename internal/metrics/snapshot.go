@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/prometheus/common/expfmt"
+)
+
+// WriteSnapshot writes the metrics of a gatherer to a file in the Prometheus
+// text format. The file is replaced atomically, so a collector reading it
+// never sees a partial snapshot.
+func WriteSnapshot(path string, gatherer prometheus.Gatherer) error {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	var buf bytes.Buffer
+	for _, family := range families {
+		if _, err := expfmt.MetricFamilyToText(&buf, family); err != nil {
+			return fmt.Errorf("failed to encode metrics: %w", err)
+		}
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write metrics snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write metrics snapshot: %w", err)
+	}
+	return nil
+}
+
+// PushSnapshot pushes the metrics of a gatherer to a Prometheus Pushgateway,
+// replacing the metrics of the job and instance pushed before.
+func PushSnapshot(ctx context.Context, url, job, instance string, gatherer prometheus.Gatherer) error {
+	err := push.New(url, job).
+		Gatherer(gatherer).
+		Grouping("instance", instance).
+		PushContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to push metrics snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func newTestRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "argus_test_events_total", Help: "Test events."})
+	counter.Add(3)
+	registry.MustRegister(counter)
+	return registry
+}
+
+func TestWriteSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "argus.prom")
+	if err := WriteSnapshot(path, newTestRegistry()); err != nil {
+		t.Fatalf("WriteSnapshot error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+	if !strings.Contains(string(data), "argus_test_events_total 3") {
+		t.Errorf("snapshot = %q, want the test counter", data)
+	}
+}
+
+func TestPushSnapshot(t *testing.T) {
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.Path, string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if err := PushSnapshot(context.Background(), server.URL, "argus", "host-1", newTestRegistry()); err != nil {
+		t.Fatalf("PushSnapshot error: %v", err)
+	}
+	if method != http.MethodPut || path != "/metrics/job/argus/instance/host-1" {
+		t.Errorf("request = %s %s, want PUT /metrics/job/argus/instance/host-1", method, path)
+	}
+	if !strings.Contains(body, "argus_test_events_total") {
+		t.Error("pushed metrics are missing the test counter")
+	}
+}
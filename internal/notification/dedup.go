@@ -0,0 +1,147 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+	"argus-go/internal/store"
+)
+
+// DedupNotifier wraps a Notifier and sends each notification once, even if
+// the change it notifies is processed again after a redelivery, a retry or a
+// processor restart, or by several replicas at once. Every notification gets
+// a deterministic ID from the event manager, the alert, the change and the
+// attempt window of the change; the IDs sent are recorded in the state
+// store, and notifications already recorded are skipped.
+//
+// A notification is recorded before it is sent, so one whose delivery fails
+// is not sent again by a later attempt. If the state store fails, the
+// notification is sent anyway.
+type DedupNotifier struct {
+	next       Notifier
+	stateStore store.StateStore
+	window     time.Duration
+	ttl        time.Duration
+	clock      clock.Clock
+	logger     *slog.Logger
+}
+
+// NewDedupNotifier creates a notifier that sends through next the
+// notifications not sent yet.
+func NewDedupNotifier(next Notifier, stateStore store.StateStore, cfg config.NotificationDedupConfig, clk clock.Clock, logger *slog.Logger) *DedupNotifier {
+	return &DedupNotifier{
+		next:       next,
+		stateStore: stateStore,
+		window:     cfg.Window,
+		ttl:        cfg.TTL,
+		clock:      clk,
+		logger:     logger,
+	}
+}
+
+// notificationID derives the ID of a notification of a change to an alert.
+// The change is identified by its kind and the attempt window its time
+// falls into, so attempts within the window share an ID.
+func (n *DedupNotifier) notificationID(em *domain.EventManager, alert *domain.Alert, change string, at time.Time) string {
+	window := at.UTC().Truncate(n.window).Unix()
+	return fmt.Sprintf("%s/%s/%s/%d", em.ID, alert.DedupKey, change, window)
+}
+
+// duplicate records a notification as sent and returns true, logs it and
+// counts it, if it was sent already.
+func (n *DedupNotifier) duplicate(ctx context.Context, kind domain.NotificationKind, id string, alert *domain.Alert) bool {
+	marked, err := n.stateStore.MarkNotificationSent(ctx, id, n.ttl)
+	if err != nil {
+		n.logger.Warn("failed to record notification, sending it anyway",
+			"kind", kind,
+			"dedupKey", alert.DedupKey,
+			"error", err,
+		)
+		return false
+	}
+	if marked {
+		return false
+	}
+	n.logger.Info("skipping notification already sent",
+		"kind", kind,
+		"dedupKey", alert.DedupKey,
+		"notification_id", id,
+	)
+	metrics.DuplicateNotifications.WithLabelValues(string(kind)).Inc()
+	return true
+}
+
+// changedAt returns the recorded time of a change, or now if the alert
+// doesn't record it.
+func (n *DedupNotifier) changedAt(at *time.Time) time.Time {
+	if at == nil {
+		return n.clock.Now()
+	}
+	return *at
+}
+
+// NotifyNewParent sends a notification for a new parent alert, once.
+func (n *DedupNotifier) NotifyNewParent(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	kind := domain.NotificationNewParent
+	if !n.duplicate(ctx, kind, n.notificationID(em, alert, string(kind), alert.CreatedAt), alert) {
+		n.next.NotifyNewParent(ctx, alert, em)
+	}
+}
+
+// NotifyResolved sends a notification for a resolved parent alert, once.
+func (n *DedupNotifier) NotifyResolved(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	kind := domain.NotificationResolved
+	if !n.duplicate(ctx, kind, n.notificationID(em, alert, string(kind), n.changedAt(alert.ResolvedAt)), alert) {
+		n.next.NotifyResolved(ctx, alert, em)
+	}
+}
+
+// NotifyReminder sends a reminder for an unacknowledged parent alert, once
+// per count.
+func (n *DedupNotifier) NotifyReminder(ctx context.Context, alert *domain.Alert, em *domain.EventManager, count int) {
+	kind := domain.NotificationReminder
+	change := fmt.Sprintf("%s-%d", kind, count)
+	if !n.duplicate(ctx, kind, n.notificationID(em, alert, change, n.clock.Now()), alert) {
+		n.next.NotifyReminder(ctx, alert, em, count)
+	}
+}
+
+// NotifyChildAdded sends a notification for a new child alert, once.
+func (n *DedupNotifier) NotifyChildAdded(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	kind := domain.NotificationChildAdded
+	if !n.duplicate(ctx, kind, n.notificationID(em, alert, string(kind), alert.CreatedAt), alert) {
+		n.next.NotifyChildAdded(ctx, alert, em)
+	}
+}
+
+// NotifyReactivated sends a notification for a reactivated alert, once per
+// episode.
+func (n *DedupNotifier) NotifyReactivated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	kind := domain.NotificationReactivated
+	change := fmt.Sprintf("%s-%d", kind, alert.Episode)
+	if !n.duplicate(ctx, kind, n.notificationID(em, alert, change, n.changedAt(alert.ReactivatedAt)), alert) {
+		n.next.NotifyReactivated(ctx, alert, em)
+	}
+}
+
+// NotifyAcknowledged sends a notification for an acknowledged alert, once.
+func (n *DedupNotifier) NotifyAcknowledged(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	kind := domain.NotificationAcknowledged
+	if !n.duplicate(ctx, kind, n.notificationID(em, alert, string(kind), n.changedAt(alert.AcknowledgedAt)), alert) {
+		n.next.NotifyAcknowledged(ctx, alert, em)
+	}
+}
+
+// NotifyEscalated sends a notification for an escalated parent alert, once.
+func (n *DedupNotifier) NotifyEscalated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	kind := domain.NotificationEscalated
+	if !n.duplicate(ctx, kind, n.notificationID(em, alert, string(kind), n.clock.Now()), alert) {
+		n.next.NotifyEscalated(ctx, alert, em)
+	}
+}
@@ -0,0 +1,78 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	"argus-go/internal/store/memory"
+)
+
+// failingStateStore fails to record notifications.
+type failingStateStore struct {
+	*memory.StateStore
+}
+
+func (s failingStateStore) MarkNotificationSent(ctx context.Context, notificationID string, ttl time.Duration) (bool, error) {
+	return false, errors.New("state store unavailable")
+}
+
+func TestDedupNotifier_Notify(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.Background()
+	clk := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	cfg := config.NotificationDedupConfig{Window: time.Minute, TTL: time.Hour}
+
+	log := memory.NewNotificationLogRepository()
+	notifier := NewDedupNotifier(NewRecordingNotifier(NewStubNotifier(logger), log, logger), memory.NewStateStoreWithClock(clk), cfg, clk, logger)
+
+	sent := func() int {
+		records, err := log.ListByDedupKey(ctx, "disk-1")
+		if err != nil {
+			t.Fatalf("ListByDedupKey() error = %v", err)
+		}
+		return len(records)
+	}
+
+	em := &domain.EventManager{ID: "em-1"}
+	alert := &domain.Alert{DedupKey: "disk-1", EventManagerID: "em-1", CreatedAt: clk.Now()}
+
+	// A redelivered change is notified once
+	notifier.NotifyNewParent(ctx, alert, em)
+	notifier.NotifyNewParent(ctx, alert, em)
+	if got := sent(); got != 1 {
+		t.Fatalf("sent %d notifications for a redelivered alert, want 1", got)
+	}
+
+	// Different changes and reminder counts are notified
+	notifier.NotifyReminder(ctx, alert, em, 1)
+	notifier.NotifyReminder(ctx, alert, em, 2)
+	notifier.NotifyReminder(ctx, alert, em, 2)
+	if got := sent(); got != 3 {
+		t.Fatalf("sent %d notifications after the reminders, want 3", got)
+	}
+
+	// The same change in a later window is notified again
+	clk.Advance(time.Minute)
+	notifier.NotifyEscalated(ctx, alert, em)
+	notifier.NotifyEscalated(ctx, alert, em)
+	clk.Advance(time.Minute)
+	notifier.NotifyEscalated(ctx, alert, em)
+	if got := sent(); got != 5 {
+		t.Fatalf("sent %d notifications after the escalations, want 5", got)
+	}
+
+	// Notifications are sent anyway if the state store fails
+	failing := NewDedupNotifier(NewRecordingNotifier(NewStubNotifier(logger), log, logger), failingStateStore{memory.NewStateStore()}, cfg, clk, logger)
+	failing.NotifyNewParent(ctx, alert, em)
+	failing.NotifyNewParent(ctx, alert, em)
+	if got := sent(); got != 7 {
+		t.Errorf("sent %d notifications with a failing state store, want 7", got)
+	}
+}
@@ -0,0 +1,130 @@
+package notification
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"argus-go/internal/metrics"
+)
+
+// Kinds of notification channels, prefixing the name of the plugin, queue or
+// watch channel delivering in channel labels, e.g. "plugin:pagerduty".
+const (
+	channelPlugin = "plugin"
+	channelQueue  = "queue"
+	channelWatch  = "watch"
+)
+
+// deliveryWindow is the number of recent deliveries of a channel its success
+// rate and p95 latency are computed over.
+const deliveryWindow = 100
+
+// ChannelDeliveries are the deliveries to a notification channel: totals
+// since the process started, and the performance of recent deliveries.
+type ChannelDeliveries struct {
+	Total  uint64
+	Failed uint64
+
+	// SuccessRate is the fraction of the recent deliveries that succeeded.
+	SuccessRate float64
+
+	// P95Latency is the 95th percentile latency of the recent deliveries.
+	P95Latency time.Duration
+}
+
+// delivery is a delivery attempt to a channel.
+type delivery struct {
+	latency time.Duration
+	failed  bool
+}
+
+// channelDeliveries tracks the deliveries to a channel. recent is a ring of
+// the last deliveryWindow deliveries; next is where the next one goes.
+type channelDeliveries struct {
+	total  uint64
+	failed uint64
+	recent []delivery
+	next   int
+}
+
+// deliveries tracks the deliveries of the process, by channel label.
+var deliveries = struct {
+	sync.Mutex
+	channels map[string]*channelDeliveries
+}{channels: make(map[string]*channelDeliveries)}
+
+// channelLabel returns the label of the channel of kind named name.
+func channelLabel(kind, name string) string {
+	return kind + ":" + name
+}
+
+// recordDelivery counts a delivery attempt to channel that took latency and
+// failed with err, if not nil, and updates the metrics of the channel.
+func recordDelivery(channel string, latency time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	metrics.NotificationDeliveries.WithLabelValues(channel, result).Inc()
+	metrics.NotificationDeliveryLatency.WithLabelValues(channel).Observe(latency.Seconds())
+
+	deliveries.Lock()
+	c, ok := deliveries.channels[channel]
+	if !ok {
+		c = &channelDeliveries{recent: make([]delivery, 0, deliveryWindow)}
+		deliveries.channels[channel] = c
+	}
+	c.total++
+	d := delivery{latency: latency, failed: err != nil}
+	if d.failed {
+		c.failed++
+	}
+	if len(c.recent) < deliveryWindow {
+		c.recent = append(c.recent, d)
+	} else {
+		c.recent[c.next] = d
+	}
+	c.next = (c.next + 1) % deliveryWindow
+	stats := c.stats()
+	deliveries.Unlock()
+
+	metrics.NotificationChannelSuccessRate.WithLabelValues(channel).Set(stats.SuccessRate)
+	metrics.NotificationChannelP95Latency.WithLabelValues(channel).Set(stats.P95Latency.Seconds())
+}
+
+// stats returns the totals and recent performance of the channel.
+func (c *channelDeliveries) stats() ChannelDeliveries {
+	stats := ChannelDeliveries{Total: c.total, Failed: c.failed}
+	if len(c.recent) == 0 {
+		return stats
+	}
+
+	latencies := make([]time.Duration, len(c.recent))
+	succeeded := 0
+	for i, d := range c.recent {
+		latencies[i] = d.latency
+		if !d.failed {
+			succeeded++
+		}
+	}
+	slices.Sort(latencies)
+	// The nearest rank: the smallest latency at least 95% of them don't exceed
+	rank := (len(latencies)*95 + 99) / 100
+	stats.SuccessRate = float64(succeeded) / float64(len(c.recent))
+	stats.P95Latency = latencies[rank-1]
+	return stats
+}
+
+// Deliveries returns the deliveries of the process to each notification
+// channel it delivered to, by channel label, for self-monitoring.
+func Deliveries() map[string]ChannelDeliveries {
+	deliveries.Lock()
+	defer deliveries.Unlock()
+
+	channels := make(map[string]ChannelDeliveries, len(deliveries.channels))
+	for channel, c := range deliveries.channels {
+		channels[channel] = c.stats()
+	}
+	return channels
+}
@@ -0,0 +1,41 @@
+package notification
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRecordDelivery(t *testing.T) {
+	channel := channelLabel(channelPlugin, "delivery-test")
+	for i := 1; i <= 20; i++ {
+		var err error
+		if i%4 == 0 {
+			err = errors.New("webhook returned 500")
+		}
+		recordDelivery(channel, time.Duration(i)*time.Millisecond, err)
+	}
+
+	got := Deliveries()[channel]
+	if got.Total != 20 || got.Failed != 5 {
+		t.Errorf("totals = %d/%d failed, want 20/5", got.Total, got.Failed)
+	}
+	if got.SuccessRate != 0.75 {
+		t.Errorf("success rate = %v, want 0.75", got.SuccessRate)
+	}
+	if got.P95Latency != 19*time.Millisecond {
+		t.Errorf("p95 latency = %s, want 19ms", got.P95Latency)
+	}
+
+	// Only the recent deliveries count towards the rate and latency
+	for range deliveryWindow {
+		recordDelivery(channel, time.Second, nil)
+	}
+	got = Deliveries()[channel]
+	if got.Total != 20+deliveryWindow || got.Failed != 5 {
+		t.Errorf("totals = %d/%d failed, want %d/5", got.Total, got.Failed, 20+deliveryWindow)
+	}
+	if got.SuccessRate != 1 || got.P95Latency != time.Second {
+		t.Errorf("recent deliveries = %v success, %s p95, want 1 and 1s", got.SuccessRate, got.P95Latency)
+	}
+}
@@ -0,0 +1,31 @@
+package notification
+
+import (
+	"sync/atomic"
+
+	"argus-go/internal/metrics"
+)
+
+// Reasons of notification failures, used as the label of the failure metric.
+const (
+	failureRecord       = "record"
+	failureTestDelivery = "test_delivery"
+	failurePlugin       = "plugin"
+	failureQueue        = "queue"
+	failureWatch        = "watch"
+)
+
+// failures counts the notification failures of the process.
+var failures atomic.Uint64
+
+// Failures returns the number of notifications that failed since the process
+// started, for self-monitoring.
+func Failures() uint64 {
+	return failures.Load()
+}
+
+// recordFailure counts a failed notification.
+func recordFailure(reason string) {
+	failures.Add(1)
+	metrics.NotificationFailures.WithLabelValues(reason).Inc()
+}
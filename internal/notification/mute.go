@@ -0,0 +1,59 @@
+package notification
+
+import (
+	"argus-go/internal/domain"
+	"argus-go/internal/quota"
+	"argus-go/internal/silence"
+)
+
+// Muter tells whether the notifications of alerts are muted, the way
+// SilenceNotifier and QuotaNotifier mute them, so alerts can be listed as
+// silenced or suppressed rather than appear active while nobody is told.
+// A nil Muter mutes nothing.
+type Muter struct {
+	silences *silence.Scheduler
+	quotas   *quota.Enforcer
+}
+
+// NewMuter creates a muter of the alerts silenced by silences or suppressed
+// by quotas. Either may be nil.
+func NewMuter(silences *silence.Scheduler, quotas *quota.Enforcer) *Muter {
+	return &Muter{
+		silences: silences,
+		quotas:   quotas,
+	}
+}
+
+// Snapshot returns the muting in effect now, for filtering and counting
+// alerts by their muted status, or nil if nothing is muted.
+func (m *Muter) Snapshot() *domain.AlertMuting {
+	if m == nil {
+		return nil
+	}
+	muting := &domain.AlertMuting{
+		Silences:                m.silences.Active(),
+		SuppressedEventManagers: m.quotas.SuppressedEventManagers(),
+	}
+	if len(muting.Silences) == 0 && len(muting.SuppressedEventManagers) == 0 {
+		return nil
+	}
+	return muting
+}
+
+// Status returns the status an alert is listed with: silenced, with the ID
+// of the silence, if it is active and silenced; suppressed if it is active
+// and the quota of its event manager suppresses notifications; and its
+// stored status otherwise. Silences are checked first, as SilenceNotifier
+// wraps QuotaNotifier.
+func (m *Muter) Status(alert *domain.Alert) (domain.AlertStatus, string) {
+	if m == nil || !alert.IsActive() {
+		return alert.Status, ""
+	}
+	if s := m.silences.Silenced(alert); s != nil {
+		return domain.AlertStatusSilenced, s.ID
+	}
+	if status := m.quotas.Status(alert.EventManagerID); status != nil && status.Suppresses() {
+		return domain.AlertStatusSuppressed, ""
+	}
+	return alert.Status, ""
+}
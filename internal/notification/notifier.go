@@ -4,6 +4,7 @@
 package notification
 
 import (
+	"cmp"
 	"context"
 	"log/slog"
 	"time"
@@ -22,6 +23,41 @@ type NotificationPayload struct {
 	Type           string    `json:"type"`
 	ChildCount     int       `json:"child_count"`
 	Timestamp      time.Time `json:"timestamp"`
+
+	// SeverityRank is the position of Severity on the deployment's severity
+	// scale, from 1 for the most severe, so receivers can compare custom
+	// levels.
+	SeverityRank int `json:"severity_rank,omitempty"`
+
+	// SeverityPresentation is the display name, color and emoji of Severity,
+	// for channels to render every alert alike.
+	SeverityPresentation domain.SeverityPresentation `json:"severity_presentation"`
+
+	// Kind is the alert change notified, e.g. "new_parent" or "child_added".
+	Kind string `json:"kind,omitempty"`
+
+	// ParentDedupKey is the parent of a child alert.
+	ParentDedupKey string `json:"parent_dedupKey,omitempty"`
+
+	// Test marks synthetic notifications sent to verify a channel.
+	Test bool `json:"test,omitempty"`
+
+	// Reminder marks escalating reminders of a parent alert that is still
+	// active and unacknowledged; ReminderCount numbers them from 1.
+	Reminder      bool `json:"reminder,omitempty"`
+	ReminderCount int  `json:"reminder_count,omitempty"`
+
+	// Runbook tells responders how to handle the alert.
+	Runbook *domain.Runbook `json:"runbook,omitempty"`
+
+	// Links point responders to dashboards, log queries or tickets of the
+	// alert.
+	Links []domain.AlertLink `json:"links,omitempty"`
+
+	// Message is the notification for people, rendered from the message
+	// catalog in Locale, the locale of the event manager.
+	Message string `json:"message,omitempty"`
+	Locale  string `json:"locale,omitempty"`
 }
 
 // Notifier defines the interface for sending alert notifications.
@@ -31,6 +67,25 @@ type Notifier interface {
 
 	// NotifyResolved sends a notification when a parent alert is resolved.
 	NotifyResolved(ctx context.Context, alert *domain.Alert, em *domain.EventManager)
+
+	// NotifyReminder resends the notification of a parent alert that is still
+	// active and unacknowledged. count is the number of the reminder, from 1.
+	NotifyReminder(ctx context.Context, alert *domain.Alert, em *domain.EventManager, count int)
+
+	// NotifyChildAdded sends a notification when a child alert is grouped
+	// under a parent.
+	NotifyChildAdded(ctx context.Context, alert *domain.Alert, em *domain.EventManager)
+
+	// NotifyReactivated sends a notification when a resolved alert is
+	// triggered again.
+	NotifyReactivated(ctx context.Context, alert *domain.Alert, em *domain.EventManager)
+
+	// NotifyAcknowledged sends a notification when an alert is acknowledged.
+	NotifyAcknowledged(ctx context.Context, alert *domain.Alert, em *domain.EventManager)
+
+	// NotifyEscalated sends a notification when a parent alert is still
+	// unacknowledged after its last reminder.
+	NotifyEscalated(ctx context.Context, alert *domain.Alert, em *domain.EventManager)
 }
 
 // StubNotifier is a no-op implementation that logs notifications.
@@ -48,7 +103,7 @@ func NewStubNotifier(logger *slog.Logger) *StubNotifier {
 
 // NotifyNewParent logs a notification for a new parent alert.
 func (n *StubNotifier) NotifyNewParent(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
-	payload := buildPayload(alert)
+	payload := buildPayload(alert, em, domain.NotificationNewParent)
 
 	n.logger.Info("STUB: would send new parent notification",
 		"webhookURL", em.NotificationConfig.WebhookURL,
@@ -61,7 +116,7 @@ func (n *StubNotifier) NotifyNewParent(ctx context.Context, alert *domain.Alert,
 
 // NotifyResolved logs a notification for a resolved parent alert.
 func (n *StubNotifier) NotifyResolved(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
-	payload := buildPayload(alert)
+	payload := buildPayload(alert, em, domain.NotificationResolved)
 
 	n.logger.Info("STUB: would send resolved notification",
 		"webhookURL", em.NotificationConfig.WebhookURL,
@@ -72,17 +127,81 @@ func (n *StubNotifier) NotifyResolved(ctx context.Context, alert *domain.Alert,
 	)
 }
 
-// buildPayload creates a notification payload from an alert.
-func buildPayload(alert *domain.Alert) *NotificationPayload {
-	return &NotificationPayload{
-		AlertID:        alert.ID,
-		DedupKey:       alert.DedupKey,
-		EventManagerID: alert.EventManagerID,
-		Summary:        alert.Summary,
-		Severity:       string(alert.Severity),
-		Status:         string(alert.Status),
-		Type:           string(alert.Type),
-		ChildCount:     alert.ChildCount,
-		Timestamp:      time.Now().UTC(),
+// NotifyReminder logs an escalating reminder for an unacknowledged parent alert.
+func (n *StubNotifier) NotifyReminder(ctx context.Context, alert *domain.Alert, em *domain.EventManager, count int) {
+	payload := buildReminderPayload(alert, em, count)
+
+	n.logger.Info("STUB: would send reminder notification",
+		"webhookURL", em.NotificationConfig.WebhookURL,
+		"alertID", payload.AlertID,
+		"dedupKey", payload.DedupKey,
+		"summary", payload.Summary,
+		"reminderCount", payload.ReminderCount,
+	)
+}
+
+// NotifyChildAdded logs a notification for a new child alert.
+func (n *StubNotifier) NotifyChildAdded(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.logLifecycle(buildPayload(alert, em, domain.NotificationChildAdded), em)
+}
+
+// NotifyReactivated logs a notification for a reactivated alert.
+func (n *StubNotifier) NotifyReactivated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.logLifecycle(buildPayload(alert, em, domain.NotificationReactivated), em)
+}
+
+// NotifyAcknowledged logs a notification for an acknowledged alert.
+func (n *StubNotifier) NotifyAcknowledged(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.logLifecycle(buildPayload(alert, em, domain.NotificationAcknowledged), em)
+}
+
+// NotifyEscalated logs a notification for an escalated parent alert.
+func (n *StubNotifier) NotifyEscalated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.logLifecycle(buildPayload(alert, em, domain.NotificationEscalated), em)
+}
+
+// logLifecycle logs an optional lifecycle notification.
+func (n *StubNotifier) logLifecycle(payload *NotificationPayload, em *domain.EventManager) {
+	n.logger.Info("STUB: would send "+payload.Kind+" notification",
+		"webhookURL", em.NotificationConfig.WebhookURL,
+		"alertID", payload.AlertID,
+		"dedupKey", payload.DedupKey,
+		"parentDedupKey", payload.ParentDedupKey,
+		"summary", payload.Summary,
+	)
+}
+
+// buildPayload creates a notification payload of the given kind from an alert,
+// with the message in the locale of its event manager.
+func buildPayload(alert *domain.Alert, em *domain.EventManager, kind domain.NotificationKind) *NotificationPayload {
+	payload := &NotificationPayload{
+		AlertID:              alert.ID,
+		DedupKey:             alert.DedupKey,
+		EventManagerID:       alert.EventManagerID,
+		Summary:              alert.Summary,
+		Severity:             string(alert.Severity),
+		SeverityRank:         alert.Severity.Rank(),
+		SeverityPresentation: alert.Severity.Presentation(),
+		Status:               string(alert.Status),
+		Type:                 string(alert.Type),
+		ChildCount:           alert.ChildCount,
+		Timestamp:            time.Now().UTC(),
+		Kind:                 string(kind),
+		ParentDedupKey:       alert.ParentDedupKey,
+		Runbook:              alert.Runbook,
+		Links:                alert.Links,
+		Locale:               cmp.Or(em.Locale, domain.DefaultLocale),
 	}
+	payload.Message = domain.CurrentMessageCatalog().Message(payload.Locale, kind, &domain.MessageData{Alert: alert})
+	return payload
+}
+
+// buildReminderPayload creates the payload of the count-th reminder of an
+// alert.
+func buildReminderPayload(alert *domain.Alert, em *domain.EventManager, count int) *NotificationPayload {
+	payload := buildPayload(alert, em, domain.NotificationReminder)
+	payload.Reminder = true
+	payload.ReminderCount = count
+	payload.Message = domain.CurrentMessageCatalog().Message(payload.Locale, domain.NotificationReminder, &domain.MessageData{Alert: alert, ReminderCount: count})
+	return payload
 }
@@ -0,0 +1,372 @@
+package notification
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+)
+
+// PluginRequest is the line a notifier plugin reads from stdin for each
+// notification or scheduled report. The plugin answers with a
+// PluginResponse of the same ID.
+type PluginRequest struct {
+	ID           uint64               `json:"id"`
+	Notification *NotificationPayload `json:"notification,omitempty"`
+	EventManager PluginEventManager   `json:"event_manager"`
+
+	// Recipient is set on the personal notifications of alert watches: the
+	// plugin delivers them to the recipient rather than the event manager.
+	Recipient *PluginRecipient `json:"recipient,omitempty"`
+
+	// Report is set instead of Notification on scheduled reports, which the
+	// plugin emails to their recipients.
+	Report *PluginReport `json:"report,omitempty"`
+}
+
+// PluginReport is a scheduled report to email.
+type PluginReport struct {
+	ScheduleID string `json:"schedule_id"`
+
+	// EventManagerID is the event manager reported on; empty if the report
+	// covers every event manager.
+	EventManagerID string   `json:"event_manager_id,omitempty"`
+	Recipients     []string `json:"recipients"`
+	Subject        string   `json:"subject"`
+
+	// Body is the text of the email, of type ContentType: text/plain or
+	// text/html.
+	Body        string `json:"body"`
+	ContentType string `json:"content_type"`
+
+	Attachments []PluginAttachment `json:"attachments,omitempty"`
+}
+
+// PluginAttachment is a file attached to a report email. Its content is
+// base64 encoded in JSON.
+type PluginAttachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"content"`
+}
+
+// PluginRecipient is the user a personal notification is delivered to.
+type PluginRecipient struct {
+	WatchID string `json:"watch_id"`
+	User    string `json:"user"`
+	Channel string `json:"channel"`
+	Address string `json:"address"`
+}
+
+// PluginEventManager identifies the event manager of a notification.
+type PluginEventManager struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	WebhookURL string `json:"webhook_url,omitempty"`
+}
+
+// PluginResponse is the line a notifier plugin writes to stdout once it has
+// handled a request. A non-empty Error reports a failed delivery.
+type PluginResponse struct {
+	ID    uint64 `json:"id"`
+	Error string `json:"error,omitempty"`
+}
+
+// Plugin is a notifier plugin process. It is started on the first
+// notification and restarted after it exits or times out. Requests are sent
+// one at a time.
+type Plugin struct {
+	cfg    config.NotifierPluginConfig
+	logger *slog.Logger
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	lastID uint64
+}
+
+// NewPlugin creates a plugin from its configuration.
+func NewPlugin(cfg config.NotifierPluginConfig, logger *slog.Logger) *Plugin {
+	return &Plugin{
+		cfg:    cfg,
+		logger: logger.With("plugin", cfg.Name),
+	}
+}
+
+// Name returns the name event managers select the plugin by.
+func (p *Plugin) Name() string {
+	return p.cfg.Name
+}
+
+// Send delivers a notification to the plugin and waits for its answer.
+func (p *Plugin) Send(ctx context.Context, payload *NotificationPayload, em *domain.EventManager) error {
+	return p.SendTo(ctx, payload, em, nil)
+}
+
+// SendTo delivers a notification for recipient to the plugin and waits for
+// its answer. A nil recipient sends it to the event manager, as Send does.
+func (p *Plugin) SendTo(ctx context.Context, payload *NotificationPayload, em *domain.EventManager, recipient *PluginRecipient) error {
+	return p.send(ctx, PluginRequest{
+		Notification: payload,
+		EventManager: PluginEventManager{ID: em.ID, Name: em.Name, WebhookURL: em.NotificationConfig.WebhookURL},
+		Recipient:    recipient,
+	})
+}
+
+// SendReport delivers a scheduled report to the plugin and waits for its
+// answer.
+func (p *Plugin) SendReport(ctx context.Context, report *PluginReport) error {
+	return p.send(ctx, PluginRequest{Report: report})
+}
+
+// send numbers a request, writes it to the plugin and waits for the answer.
+func (p *Plugin) send(ctx context.Context, req PluginRequest) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cmd == nil {
+		if err := p.start(); err != nil {
+			return err
+		}
+	}
+
+	p.lastID++
+	req.ID = p.lastID
+	line, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	if _, err := p.stdin.Write(append(line, '\n')); err != nil {
+		p.stop()
+		return fmt.Errorf("failed to write request: %w", err)
+	}
+
+	resp, err := p.read(ctx, req.ID)
+	if err != nil {
+		// The plugin is out of step with its requests, so start afresh
+		p.stop()
+		return err
+	}
+	if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
+
+// read waits for the response to a request, within the plugin timeout.
+func (p *Plugin) read(ctx context.Context, id uint64) (*PluginResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	type result struct {
+		resp *PluginResponse
+		err  error
+	}
+	done := make(chan result, 1)
+	stdout := p.stdout
+	go func() {
+		line, err := stdout.ReadBytes('\n')
+		if err != nil {
+			done <- result{err: fmt.Errorf("failed to read response: %w", err)}
+			return
+		}
+		var resp PluginResponse
+		if err := json.Unmarshal(line, &resp); err != nil {
+			done <- result{err: fmt.Errorf("invalid response %q: %w", line, err)}
+			return
+		}
+		done <- result{resp: &resp}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("no response: %w", ctx.Err())
+	case r := <-done:
+		if r.err != nil {
+			return nil, r.err
+		}
+		if r.resp.ID != id {
+			return nil, fmt.Errorf("response to request %d, want %d", r.resp.ID, id)
+		}
+		return r.resp, nil
+	}
+}
+
+// start runs the plugin process. Its stderr is passed through to ours.
+func (p *Plugin) start() error {
+	cmd := exec.Command(p.cfg.Command, p.cfg.Args...)
+	cmd.Env = os.Environ()
+	for key, value := range p.cfg.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start plugin: %w", err)
+	}
+
+	p.cmd = cmd
+	p.stdin = stdin
+	p.stdout = bufio.NewReader(stdout)
+	p.logger.Info("started notifier plugin", "command", p.cfg.Command, "pid", cmd.Process.Pid)
+	return nil
+}
+
+// stop kills the plugin process, if it runs; the next request restarts it.
+func (p *Plugin) stop() {
+	if p.cmd == nil {
+		return
+	}
+	_ = p.stdin.Close()
+	_ = p.cmd.Process.Kill()
+	_ = p.cmd.Wait()
+	p.cmd = nil
+	p.logger.Warn("stopped notifier plugin")
+}
+
+// Close stops the plugin. It first closes stdin, so a plugin that exits at
+// the end of its input can shut down cleanly within its timeout.
+func (p *Plugin) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cmd == nil {
+		return nil
+	}
+	_ = p.stdin.Close()
+	exited := make(chan error, 1)
+	cmd := p.cmd
+	go func() { exited <- cmd.Wait() }()
+
+	select {
+	case err := <-exited:
+		p.cmd = nil
+		return err
+	case <-time.After(p.cfg.Timeout):
+		_ = cmd.Process.Kill()
+		<-exited
+		p.cmd = nil
+		return fmt.Errorf("plugin %s did not exit within %s", p.cfg.Name, p.cfg.Timeout)
+	}
+}
+
+// PluginNotifier sends notifications through next, and also to the plugin
+// the event manager selects. Plugin failures are logged and counted, never
+// returned to alert processing.
+type PluginNotifier struct {
+	next    Notifier
+	plugins map[string]*Plugin
+	logger  *slog.Logger
+}
+
+// NewPluginNotifier creates a notifier that delivers to plugins as well as next.
+func NewPluginNotifier(next Notifier, plugins []*Plugin, logger *slog.Logger) *PluginNotifier {
+	byName := make(map[string]*Plugin, len(plugins))
+	for _, p := range plugins {
+		byName[p.Name()] = p
+	}
+	return &PluginNotifier{
+		next:    next,
+		plugins: byName,
+		logger:  logger,
+	}
+}
+
+// NotifyNewParent sends a notification for a new parent alert.
+func (n *PluginNotifier) NotifyNewParent(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyNewParent(ctx, alert, em)
+	n.send(ctx, buildPayload(alert, em, domain.NotificationNewParent), em)
+}
+
+// NotifyResolved sends a notification for a resolved parent alert.
+func (n *PluginNotifier) NotifyResolved(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyResolved(ctx, alert, em)
+	n.send(ctx, buildPayload(alert, em, domain.NotificationResolved), em)
+}
+
+// NotifyReminder sends a reminder for an unacknowledged parent alert.
+func (n *PluginNotifier) NotifyReminder(ctx context.Context, alert *domain.Alert, em *domain.EventManager, count int) {
+	n.next.NotifyReminder(ctx, alert, em, count)
+
+	payload := buildReminderPayload(alert, em, count)
+	n.send(ctx, payload, em)
+}
+
+// NotifyChildAdded sends a notification for a new child alert.
+func (n *PluginNotifier) NotifyChildAdded(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyChildAdded(ctx, alert, em)
+	n.send(ctx, buildPayload(alert, em, domain.NotificationChildAdded), em)
+}
+
+// NotifyReactivated sends a notification for a reactivated alert.
+func (n *PluginNotifier) NotifyReactivated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyReactivated(ctx, alert, em)
+	n.send(ctx, buildPayload(alert, em, domain.NotificationReactivated), em)
+}
+
+// NotifyAcknowledged sends a notification for an acknowledged alert.
+func (n *PluginNotifier) NotifyAcknowledged(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyAcknowledged(ctx, alert, em)
+	n.send(ctx, buildPayload(alert, em, domain.NotificationAcknowledged), em)
+}
+
+// NotifyEscalated sends a notification for an escalated parent alert.
+func (n *PluginNotifier) NotifyEscalated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyEscalated(ctx, alert, em)
+	n.send(ctx, buildPayload(alert, em, domain.NotificationEscalated), em)
+}
+
+// send delivers a notification to the plugin of the event manager, if any.
+func (n *PluginNotifier) send(ctx context.Context, payload *NotificationPayload, em *domain.EventManager) {
+	name := em.NotificationConfig.Plugin
+	if name == "" {
+		return
+	}
+	plugin, ok := n.plugins[name]
+	if !ok {
+		n.logger.Warn("event manager selects an unknown notifier plugin", "event_manager_id", em.ID, "plugin", name)
+		recordFailure(failurePlugin)
+		return
+	}
+
+	start := time.Now()
+	err := plugin.Send(ctx, payload, em)
+	recordDelivery(channelLabel(channelPlugin, name), time.Since(start), err)
+	if err != nil {
+		n.logger.Warn("notifier plugin failed",
+			"plugin", name,
+			"dedupKey", payload.DedupKey,
+			"kind", payload.Kind,
+			"error", err,
+		)
+		recordFailure(failurePlugin)
+	}
+}
+
+// Close stops every plugin.
+func (n *PluginNotifier) Close() error {
+	var errs []error
+	for _, p := range n.plugins {
+		errs = append(errs, p.Close())
+	}
+	return errors.Join(errs...)
+}
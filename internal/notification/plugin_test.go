@@ -0,0 +1,134 @@
+package notification
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+)
+
+// TestPluginHelperProcess is not a test: it runs as the plugin process of
+// the tests below, behaving as PLUGIN_HELPER_MODE says.
+func TestPluginHelperProcess(t *testing.T) {
+	mode := os.Getenv("PLUGIN_HELPER_MODE")
+	if mode == "" {
+		return
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req PluginRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			os.Exit(2)
+		}
+		resp := PluginResponse{ID: req.ID}
+		switch mode {
+		case "hang":
+			time.Sleep(time.Hour)
+		case "fail":
+			resp.Error = "channel unavailable"
+		case "watch":
+			if req.Recipient == nil || req.Recipient.Address != "oncall@example.com" {
+				resp.Error = fmt.Sprintf("unexpected recipient %+v", req.Recipient)
+			}
+		}
+		if req.Notification.Summary != "Disk full" || req.EventManager.ID != "em-1" {
+			resp.Error = fmt.Sprintf("unexpected request %+v", req)
+		}
+		line, _ := json.Marshal(resp)
+		fmt.Println(string(line))
+	}
+	os.Exit(0)
+}
+
+func newTestPlugin(mode string) *Plugin {
+	return NewPlugin(config.NotifierPluginConfig{
+		Name:    "test",
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^TestPluginHelperProcess$"},
+		Env:     map[string]string{"PLUGIN_HELPER_MODE": mode},
+		Timeout: 2 * time.Second,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestPlugin_Send(t *testing.T) {
+	plugin := newTestPlugin("ok")
+	defer plugin.Close()
+
+	em := &domain.EventManager{ID: "em-1", Name: "Payments"}
+	alert := &domain.Alert{DedupKey: "disk-1", EventManagerID: "em-1", Summary: "Disk full"}
+	for i := 0; i < 3; i++ {
+		if err := plugin.Send(context.Background(), buildPayload(alert, em, domain.NotificationNewParent), em); err != nil {
+			t.Fatalf("Send() #%d error = %v", i+1, err)
+		}
+	}
+}
+
+func TestPlugin_SendFailure(t *testing.T) {
+	plugin := newTestPlugin("fail")
+	defer plugin.Close()
+
+	em := &domain.EventManager{ID: "em-1"}
+	alert := &domain.Alert{DedupKey: "disk-1", Summary: "Disk full"}
+	err := plugin.Send(context.Background(), buildPayload(alert, em, domain.NotificationNewParent), em)
+	if err == nil || err.Error() != "channel unavailable" {
+		t.Errorf("Send() error = %v, want channel unavailable", err)
+	}
+}
+
+func TestPlugin_SendTimeoutRestarts(t *testing.T) {
+	plugin := newTestPlugin("hang")
+	plugin.cfg.Timeout = 200 * time.Millisecond
+	defer plugin.Close()
+
+	em := &domain.EventManager{ID: "em-1"}
+	alert := &domain.Alert{DedupKey: "disk-1", Summary: "Disk full"}
+	if err := plugin.Send(context.Background(), buildPayload(alert, em, domain.NotificationNewParent), em); err == nil {
+		t.Fatal("Send() to a hung plugin succeeded")
+	}
+	if plugin.cmd != nil {
+		t.Error("hung plugin was not stopped")
+	}
+
+	// The next notification starts the plugin again
+	plugin.cfg.Env["PLUGIN_HELPER_MODE"] = "ok"
+	if err := plugin.Send(context.Background(), buildPayload(alert, em, domain.NotificationNewParent), em); err != nil {
+		t.Errorf("Send() after restart error = %v", err)
+	}
+}
+
+func TestPluginNotifier_CountsFailures(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	plugin := newTestPlugin("fail")
+	notifier := NewPluginNotifier(NewStubNotifier(logger), []*Plugin{plugin}, logger)
+	defer notifier.Close()
+
+	alert := &domain.Alert{DedupKey: "disk-1", Summary: "Disk full"}
+	before := Failures()
+
+	notifier.NotifyNewParent(context.Background(), alert, &domain.EventManager{ID: "em-1"})
+	if got := Failures() - before; got != 0 {
+		t.Errorf("failures without a plugin selected = %d, want 0", got)
+	}
+
+	em := &domain.EventManager{ID: "em-1", NotificationConfig: domain.NotificationConfig{Plugin: "test"}}
+	notifier.NotifyNewParent(context.Background(), alert, em)
+	notifier.NotifyResolved(context.Background(), alert, em)
+	if got := Failures() - before; got != 2 {
+		t.Errorf("failures = %d, want 2", got)
+	}
+
+	em.NotificationConfig.Plugin = "unknown"
+	notifier.NotifyNewParent(context.Background(), alert, em)
+	if got := Failures() - before; got != 3 {
+		t.Errorf("failures after an unknown plugin = %d, want 3", got)
+	}
+}
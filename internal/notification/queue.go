@@ -0,0 +1,128 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/queue"
+)
+
+// Headers of notifications published to queues.
+const (
+	QueueKindHeader         = "kind"
+	QueueEventManagerHeader = "event_manager_id"
+)
+
+// QueueNotifier sends notifications through next, and also publishes them
+// to the queue the event manager selects. Notifications are keyed by dedup
+// key, so those of an alert stay in order. Publish failures are logged and
+// counted, never returned to alert processing.
+type QueueNotifier struct {
+	next   Notifier
+	queues map[string]queue.Producer
+	logger *slog.Logger
+}
+
+// NewQueueNotifier creates a notifier that publishes to the named queues as
+// well as delivering through next.
+func NewQueueNotifier(next Notifier, queues map[string]queue.Producer, logger *slog.Logger) *QueueNotifier {
+	return &QueueNotifier{
+		next:   next,
+		queues: queues,
+		logger: logger,
+	}
+}
+
+// NotifyNewParent sends a notification for a new parent alert.
+func (n *QueueNotifier) NotifyNewParent(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyNewParent(ctx, alert, em)
+	n.publish(ctx, buildPayload(alert, em, domain.NotificationNewParent), em)
+}
+
+// NotifyResolved sends a notification for a resolved parent alert.
+func (n *QueueNotifier) NotifyResolved(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyResolved(ctx, alert, em)
+	n.publish(ctx, buildPayload(alert, em, domain.NotificationResolved), em)
+}
+
+// NotifyReminder sends a reminder for an unacknowledged parent alert.
+func (n *QueueNotifier) NotifyReminder(ctx context.Context, alert *domain.Alert, em *domain.EventManager, count int) {
+	n.next.NotifyReminder(ctx, alert, em, count)
+
+	payload := buildReminderPayload(alert, em, count)
+	n.publish(ctx, payload, em)
+}
+
+// NotifyChildAdded sends a notification for a new child alert.
+func (n *QueueNotifier) NotifyChildAdded(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyChildAdded(ctx, alert, em)
+	n.publish(ctx, buildPayload(alert, em, domain.NotificationChildAdded), em)
+}
+
+// NotifyReactivated sends a notification for a reactivated alert.
+func (n *QueueNotifier) NotifyReactivated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyReactivated(ctx, alert, em)
+	n.publish(ctx, buildPayload(alert, em, domain.NotificationReactivated), em)
+}
+
+// NotifyAcknowledged sends a notification for an acknowledged alert.
+func (n *QueueNotifier) NotifyAcknowledged(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyAcknowledged(ctx, alert, em)
+	n.publish(ctx, buildPayload(alert, em, domain.NotificationAcknowledged), em)
+}
+
+// NotifyEscalated sends a notification for an escalated parent alert.
+func (n *QueueNotifier) NotifyEscalated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyEscalated(ctx, alert, em)
+	n.publish(ctx, buildPayload(alert, em, domain.NotificationEscalated), em)
+}
+
+// publish sends a notification to the queue of the event manager, if any.
+func (n *QueueNotifier) publish(ctx context.Context, payload *NotificationPayload, em *domain.EventManager) {
+	name := em.NotificationConfig.Queue
+	if name == "" {
+		return
+	}
+	producer, ok := n.queues[name]
+	if !ok {
+		n.logger.Warn("event manager selects an unknown notification queue", "event_manager_id", em.ID, "queue", name)
+		recordFailure(failureQueue)
+		return
+	}
+
+	value, err := json.Marshal(payload)
+	if err == nil {
+		start := time.Now()
+		err = producer.Publish(ctx, &queue.Message{
+			Key:   []byte(payload.DedupKey),
+			Value: value,
+			Headers: map[string]string{
+				QueueKindHeader:         payload.Kind,
+				QueueEventManagerHeader: em.ID,
+			},
+		})
+		recordDelivery(channelLabel(channelQueue, name), time.Since(start), err)
+	}
+	if err != nil {
+		n.logger.Warn("failed to publish notification",
+			"queue", name,
+			"dedupKey", payload.DedupKey,
+			"kind", payload.Kind,
+			"error", err,
+		)
+		recordFailure(failureQueue)
+	}
+}
+
+// Close closes every queue producer.
+func (n *QueueNotifier) Close() error {
+	var errs []error
+	for _, p := range n.queues {
+		errs = append(errs, p.Close())
+	}
+	return errors.Join(errs...)
+}
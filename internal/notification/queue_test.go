@@ -0,0 +1,86 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/queue"
+)
+
+// recordingProducer records published messages, or fails with err.
+type recordingProducer struct {
+	messages []*queue.Message
+	err      error
+}
+
+func (p *recordingProducer) Publish(_ context.Context, msg *queue.Message) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+func (p *recordingProducer) Close() error { return nil }
+
+func TestQueueNotifier_Publishes(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	producer := &recordingProducer{}
+	notifier := NewQueueNotifier(NewStubNotifier(logger), map[string]queue.Producer{"stream": producer}, logger)
+
+	alert := &domain.Alert{DedupKey: "disk-1", Summary: "Disk full"}
+	notifier.NotifyNewParent(context.Background(), alert, &domain.EventManager{ID: "em-1"})
+	if len(producer.messages) != 0 {
+		t.Fatalf("published %d messages without a queue selected, want 0", len(producer.messages))
+	}
+
+	em := &domain.EventManager{ID: "em-1", NotificationConfig: domain.NotificationConfig{Queue: "stream"}}
+	notifier.NotifyReminder(context.Background(), alert, em, 2)
+	if len(producer.messages) != 1 {
+		t.Fatalf("published %d messages, want 1", len(producer.messages))
+	}
+
+	msg := producer.messages[0]
+	if string(msg.Key) != "disk-1" {
+		t.Errorf("key = %q, want disk-1", msg.Key)
+	}
+	if msg.Headers[QueueKindHeader] != string(domain.NotificationReminder) || msg.Headers[QueueEventManagerHeader] != "em-1" {
+		t.Errorf("headers = %v", msg.Headers)
+	}
+	var payload NotificationPayload
+	if err := json.Unmarshal(msg.Value, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.Summary != "Disk full" || !payload.Reminder || payload.ReminderCount != 2 {
+		t.Errorf("payload = %+v", payload)
+	}
+	if want := "Reminder 2: Disk full is still unacknowledged"; payload.Message != want || payload.Locale != domain.DefaultLocale {
+		t.Errorf("message, locale = %q, %q, want %q, %q", payload.Message, payload.Locale, want, domain.DefaultLocale)
+	}
+}
+
+func TestQueueNotifier_CountsFailures(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	producer := &recordingProducer{err: errors.New("broker unavailable")}
+	notifier := NewQueueNotifier(NewStubNotifier(logger), map[string]queue.Producer{"stream": producer}, logger)
+
+	alert := &domain.Alert{DedupKey: "disk-1", Summary: "Disk full"}
+	em := &domain.EventManager{ID: "em-1", NotificationConfig: domain.NotificationConfig{Queue: "stream"}}
+	before := Failures()
+
+	notifier.NotifyNewParent(context.Background(), alert, em)
+	if got := Failures() - before; got != 1 {
+		t.Errorf("failures = %d, want 1", got)
+	}
+
+	em.NotificationConfig.Queue = "unknown"
+	notifier.NotifyNewParent(context.Background(), alert, em)
+	if got := Failures() - before; got != 2 {
+		t.Errorf("failures after an unknown queue = %d, want 2", got)
+	}
+}
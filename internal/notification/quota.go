@@ -0,0 +1,95 @@
+package notification
+
+import (
+	"context"
+	"log/slog"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+	"argus-go/internal/quota"
+)
+
+// QuotaNotifier wraps a Notifier and suppresses every notification of an
+// event manager that exhausted its quota under degrade enforcement. Its
+// events still become alerts; only the notifications are dropped.
+type QuotaNotifier struct {
+	next     Notifier
+	enforcer *quota.Enforcer
+	logger   *slog.Logger
+}
+
+// NewQuotaNotifier creates a notifier that sends through next unless the
+// quota of the event manager suppresses notifications.
+func NewQuotaNotifier(next Notifier, enforcer *quota.Enforcer, logger *slog.Logger) *QuotaNotifier {
+	return &QuotaNotifier{
+		next:     next,
+		enforcer: enforcer,
+		logger:   logger,
+	}
+}
+
+// suppressed returns true, logs it and counts it, if the notification of an alert is
+// suppressed by the quota of its event manager.
+func (n *QuotaNotifier) suppressed(kind domain.NotificationKind, alert *domain.Alert, em *domain.EventManager) bool {
+	status := n.enforcer.Status(em.ID)
+	if status == nil || !status.Suppresses() {
+		return false
+	}
+	n.logger.Debug("suppressing notification over quota",
+		"kind", kind,
+		"dedupKey", alert.DedupKey,
+		"event_manager_id", em.ID,
+		"exceeded", status.Exceeded,
+	)
+	metrics.SuppressedNotifications.WithLabelValues(string(kind)).Inc()
+	return true
+}
+
+// NotifyNewParent sends a notification for a new parent alert, unless suppressed.
+func (n *QuotaNotifier) NotifyNewParent(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	if !n.suppressed(domain.NotificationNewParent, alert, em) {
+		n.next.NotifyNewParent(ctx, alert, em)
+	}
+}
+
+// NotifyResolved sends a notification for a resolved parent alert, unless suppressed.
+func (n *QuotaNotifier) NotifyResolved(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	if !n.suppressed(domain.NotificationResolved, alert, em) {
+		n.next.NotifyResolved(ctx, alert, em)
+	}
+}
+
+// NotifyReminder sends a reminder for an unacknowledged parent alert, unless suppressed.
+func (n *QuotaNotifier) NotifyReminder(ctx context.Context, alert *domain.Alert, em *domain.EventManager, count int) {
+	if !n.suppressed(domain.NotificationReminder, alert, em) {
+		n.next.NotifyReminder(ctx, alert, em, count)
+	}
+}
+
+// NotifyChildAdded sends a notification for a new child alert, unless suppressed.
+func (n *QuotaNotifier) NotifyChildAdded(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	if !n.suppressed(domain.NotificationChildAdded, alert, em) {
+		n.next.NotifyChildAdded(ctx, alert, em)
+	}
+}
+
+// NotifyReactivated sends a notification for a reactivated alert, unless suppressed.
+func (n *QuotaNotifier) NotifyReactivated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	if !n.suppressed(domain.NotificationReactivated, alert, em) {
+		n.next.NotifyReactivated(ctx, alert, em)
+	}
+}
+
+// NotifyAcknowledged sends a notification for an acknowledged alert, unless suppressed.
+func (n *QuotaNotifier) NotifyAcknowledged(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	if !n.suppressed(domain.NotificationAcknowledged, alert, em) {
+		n.next.NotifyAcknowledged(ctx, alert, em)
+	}
+}
+
+// NotifyEscalated sends a notification for an escalated parent alert, unless suppressed.
+func (n *QuotaNotifier) NotifyEscalated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	if !n.suppressed(domain.NotificationEscalated, alert, em) {
+		n.next.NotifyEscalated(ctx, alert, em)
+	}
+}
@@ -0,0 +1,85 @@
+package notification
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// RecordingNotifier wraps a Notifier and records every notification it sends
+// in the notification log, for incident reports.
+type RecordingNotifier struct {
+	next   Notifier
+	log    store.NotificationLogRepository
+	logger *slog.Logger
+}
+
+// NewRecordingNotifier creates a notifier that records the notifications sent through next.
+func NewRecordingNotifier(next Notifier, log store.NotificationLogRepository, logger *slog.Logger) *RecordingNotifier {
+	return &RecordingNotifier{
+		next:   next,
+		log:    log,
+		logger: logger,
+	}
+}
+
+// NotifyNewParent sends and records a notification for a new parent alert.
+func (n *RecordingNotifier) NotifyNewParent(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyNewParent(ctx, alert, em)
+	n.record(ctx, alert, em, domain.NotificationNewParent)
+}
+
+// NotifyResolved sends and records a notification for a resolved parent alert.
+func (n *RecordingNotifier) NotifyResolved(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyResolved(ctx, alert, em)
+	n.record(ctx, alert, em, domain.NotificationResolved)
+}
+
+// NotifyReminder sends and records a reminder for an unacknowledged parent alert.
+func (n *RecordingNotifier) NotifyReminder(ctx context.Context, alert *domain.Alert, em *domain.EventManager, count int) {
+	n.next.NotifyReminder(ctx, alert, em, count)
+	n.record(ctx, alert, em, domain.NotificationReminder)
+}
+
+// NotifyChildAdded sends and records a notification for a new child alert.
+func (n *RecordingNotifier) NotifyChildAdded(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyChildAdded(ctx, alert, em)
+	n.record(ctx, alert, em, domain.NotificationChildAdded)
+}
+
+// NotifyReactivated sends and records a notification for a reactivated alert.
+func (n *RecordingNotifier) NotifyReactivated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyReactivated(ctx, alert, em)
+	n.record(ctx, alert, em, domain.NotificationReactivated)
+}
+
+// NotifyAcknowledged sends and records a notification for an acknowledged alert.
+func (n *RecordingNotifier) NotifyAcknowledged(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyAcknowledged(ctx, alert, em)
+	n.record(ctx, alert, em, domain.NotificationAcknowledged)
+}
+
+// NotifyEscalated sends and records a notification for an escalated parent alert.
+func (n *RecordingNotifier) NotifyEscalated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyEscalated(ctx, alert, em)
+	n.record(ctx, alert, em, domain.NotificationEscalated)
+}
+
+// record appends a notification to the log. Failures are logged only, so
+// they never affect alert processing.
+func (n *RecordingNotifier) record(ctx context.Context, alert *domain.Alert, em *domain.EventManager, kind domain.NotificationKind) {
+	record := &domain.NotificationRecord{
+		DedupKey:       alert.DedupKey,
+		EventManagerID: em.ID,
+		Kind:           kind,
+		Target:         em.NotificationConfig.WebhookURL,
+		SentAt:         time.Now().UTC(),
+	}
+	if err := n.log.Record(ctx, record); err != nil {
+		n.logger.Warn("failed to record notification", "dedupKey", alert.DedupKey, "kind", kind, "error", err)
+		recordFailure(failureRecord)
+	}
+}
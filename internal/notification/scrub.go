@@ -0,0 +1,59 @@
+package notification
+
+import (
+	"context"
+
+	"argus-go/internal/domain"
+)
+
+// ScrubNotifier wraps a Notifier and masks the text of alerts before they
+// are sent, so secrets and personal data in stored alerts never reach
+// third-party channels.
+type ScrubNotifier struct {
+	next     Notifier
+	scrubber *domain.Scrubber
+}
+
+// NewScrubNotifier creates a notifier that sends scrubbed copies of alerts
+// through next.
+func NewScrubNotifier(next Notifier, scrubber *domain.Scrubber) *ScrubNotifier {
+	return &ScrubNotifier{
+		next:     next,
+		scrubber: scrubber,
+	}
+}
+
+// NotifyNewParent sends a scrubbed notification for a new parent alert.
+func (n *ScrubNotifier) NotifyNewParent(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyNewParent(ctx, n.scrubber.Alert(alert), em)
+}
+
+// NotifyResolved sends a scrubbed notification for a resolved parent alert.
+func (n *ScrubNotifier) NotifyResolved(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyResolved(ctx, n.scrubber.Alert(alert), em)
+}
+
+// NotifyReminder sends a scrubbed reminder for an unacknowledged parent alert.
+func (n *ScrubNotifier) NotifyReminder(ctx context.Context, alert *domain.Alert, em *domain.EventManager, count int) {
+	n.next.NotifyReminder(ctx, n.scrubber.Alert(alert), em, count)
+}
+
+// NotifyChildAdded sends a scrubbed notification for a new child alert.
+func (n *ScrubNotifier) NotifyChildAdded(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyChildAdded(ctx, n.scrubber.Alert(alert), em)
+}
+
+// NotifyReactivated sends a scrubbed notification for a reactivated alert.
+func (n *ScrubNotifier) NotifyReactivated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyReactivated(ctx, n.scrubber.Alert(alert), em)
+}
+
+// NotifyAcknowledged sends a scrubbed notification for an acknowledged alert.
+func (n *ScrubNotifier) NotifyAcknowledged(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyAcknowledged(ctx, n.scrubber.Alert(alert), em)
+}
+
+// NotifyEscalated sends a scrubbed notification for an escalated parent alert.
+func (n *ScrubNotifier) NotifyEscalated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyEscalated(ctx, n.scrubber.Alert(alert), em)
+}
@@ -0,0 +1,69 @@
+package notification
+
+import (
+	"context"
+	"log/slog"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+)
+
+// ShadowNotifier is the notifier of a processor in shadow mode. It never
+// delivers anything: it logs and counts the notifications that would have
+// been sent, so they can be compared with those of the live processors.
+type ShadowNotifier struct {
+	logger *slog.Logger
+}
+
+// NewShadowNotifier creates a new shadow notifier.
+func NewShadowNotifier(logger *slog.Logger) *ShadowNotifier {
+	return &ShadowNotifier{
+		logger: logger,
+	}
+}
+
+// NotifyNewParent records a new parent notification that would have been sent.
+func (n *ShadowNotifier) NotifyNewParent(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.record("new_parent", alert)
+}
+
+// NotifyResolved records a resolved notification that would have been sent.
+func (n *ShadowNotifier) NotifyResolved(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.record("resolved", alert)
+}
+
+// NotifyReminder records a reminder that would have been sent.
+func (n *ShadowNotifier) NotifyReminder(ctx context.Context, alert *domain.Alert, em *domain.EventManager, count int) {
+	n.record("reminder", alert)
+}
+
+// NotifyChildAdded records a child added notification that would have been sent.
+func (n *ShadowNotifier) NotifyChildAdded(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.record(string(domain.NotificationChildAdded), alert)
+}
+
+// NotifyReactivated records a reactivated notification that would have been sent.
+func (n *ShadowNotifier) NotifyReactivated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.record(string(domain.NotificationReactivated), alert)
+}
+
+// NotifyAcknowledged records an acknowledged notification that would have been sent.
+func (n *ShadowNotifier) NotifyAcknowledged(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.record(string(domain.NotificationAcknowledged), alert)
+}
+
+// NotifyEscalated records an escalated notification that would have been sent.
+func (n *ShadowNotifier) NotifyEscalated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.record(string(domain.NotificationEscalated), alert)
+}
+
+func (n *ShadowNotifier) record(kind string, alert *domain.Alert) {
+	metrics.ShadowNotifications.WithLabelValues(alert.EventManagerID, kind).Inc()
+	n.logger.Info("SHADOW: would send notification",
+		"type", kind,
+		"alertID", alert.ID,
+		"dedupKey", alert.DedupKey,
+		"summary", alert.Summary,
+		"childCount", alert.ChildCount,
+	)
+}
@@ -0,0 +1,58 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+)
+
+func TestShadowNotifier(t *testing.T) {
+	var logs bytes.Buffer
+	notifier := NewShadowNotifier(slog.New(slog.NewTextHandler(&logs, nil)))
+	ctx := context.Background()
+	alert := &domain.Alert{ID: "alert-1", DedupKey: "disk-1", EventManagerID: "em-shadow", Summary: "Disk full"}
+	em := &domain.EventManager{ID: "em-shadow"}
+
+	counted := func(kind string) float64 {
+		var m dto.Metric
+		if err := metrics.ShadowNotifications.WithLabelValues("em-shadow", kind).Write(&m); err != nil {
+			t.Fatalf("Write error: %v", err)
+		}
+		return m.GetCounter().GetValue()
+	}
+
+	tests := []struct {
+		kind   string
+		notify func()
+	}{
+		{"new_parent", func() { notifier.NotifyNewParent(ctx, alert, em) }},
+		{"resolved", func() { notifier.NotifyResolved(ctx, alert, em) }},
+		{"reminder", func() { notifier.NotifyReminder(ctx, alert, em, 1) }},
+		{string(domain.NotificationChildAdded), func() { notifier.NotifyChildAdded(ctx, alert, em) }},
+		{string(domain.NotificationReactivated), func() { notifier.NotifyReactivated(ctx, alert, em) }},
+		{string(domain.NotificationAcknowledged), func() { notifier.NotifyAcknowledged(ctx, alert, em) }},
+		{string(domain.NotificationEscalated), func() { notifier.NotifyEscalated(ctx, alert, em) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			before := counted(tt.kind)
+			logs.Reset()
+
+			tt.notify()
+
+			if got := counted(tt.kind); got != before+1 {
+				t.Errorf("argus_shadow_notifications_total{type=%q} = %v, want %v", tt.kind, got, before+1)
+			}
+			if out := logs.String(); !strings.Contains(out, "SHADOW: would send notification") || !strings.Contains(out, "type="+tt.kind) {
+				t.Errorf("log = %q, want the notification that would have been sent", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package notification
+
+import (
+	"context"
+	"log/slog"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+	"argus-go/internal/silence"
+)
+
+// SilenceNotifier wraps a Notifier and mutes the notifications of alerts
+// matched by an active silence. Silenced alerts are still created, grouped
+// and resolved, and the personal notifications of watches are still sent.
+type SilenceNotifier struct {
+	next      Notifier
+	scheduler *silence.Scheduler
+	logger    *slog.Logger
+}
+
+// NewSilenceNotifier creates a notifier that sends through next unless the
+// alert is silenced.
+func NewSilenceNotifier(next Notifier, scheduler *silence.Scheduler, logger *slog.Logger) *SilenceNotifier {
+	return &SilenceNotifier{
+		next:      next,
+		scheduler: scheduler,
+		logger:    logger,
+	}
+}
+
+// silenced returns true, logs it and counts it, if the notification of an
+// alert is muted by a silence.
+func (n *SilenceNotifier) silenced(kind domain.NotificationKind, alert *domain.Alert) bool {
+	s := n.scheduler.Silenced(alert)
+	if s == nil {
+		return false
+	}
+	n.logger.Debug("silencing notification",
+		"kind", kind,
+		"dedupKey", alert.DedupKey,
+		"silence_id", s.ID,
+	)
+	metrics.SilencedNotifications.WithLabelValues(string(kind)).Inc()
+	return true
+}
+
+// NotifyNewParent sends a notification for a new parent alert, unless silenced.
+func (n *SilenceNotifier) NotifyNewParent(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	if !n.silenced(domain.NotificationNewParent, alert) {
+		n.next.NotifyNewParent(ctx, alert, em)
+	}
+}
+
+// NotifyResolved sends a notification for a resolved parent alert, unless silenced.
+func (n *SilenceNotifier) NotifyResolved(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	if !n.silenced(domain.NotificationResolved, alert) {
+		n.next.NotifyResolved(ctx, alert, em)
+	}
+}
+
+// NotifyReminder sends a reminder for an unacknowledged parent alert, unless silenced.
+func (n *SilenceNotifier) NotifyReminder(ctx context.Context, alert *domain.Alert, em *domain.EventManager, count int) {
+	if !n.silenced(domain.NotificationReminder, alert) {
+		n.next.NotifyReminder(ctx, alert, em, count)
+	}
+}
+
+// NotifyChildAdded sends a notification for a new child alert, unless silenced.
+func (n *SilenceNotifier) NotifyChildAdded(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	if !n.silenced(domain.NotificationChildAdded, alert) {
+		n.next.NotifyChildAdded(ctx, alert, em)
+	}
+}
+
+// NotifyReactivated sends a notification for a reactivated alert, unless silenced.
+func (n *SilenceNotifier) NotifyReactivated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	if !n.silenced(domain.NotificationReactivated, alert) {
+		n.next.NotifyReactivated(ctx, alert, em)
+	}
+}
+
+// NotifyAcknowledged sends a notification for an acknowledged alert, unless silenced.
+func (n *SilenceNotifier) NotifyAcknowledged(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	if !n.silenced(domain.NotificationAcknowledged, alert) {
+		n.next.NotifyAcknowledged(ctx, alert, em)
+	}
+}
+
+// NotifyEscalated sends a notification for an escalated parent alert, unless silenced.
+func (n *SilenceNotifier) NotifyEscalated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	if !n.silenced(domain.NotificationEscalated, alert) {
+		n.next.NotifyEscalated(ctx, alert, em)
+	}
+}
@@ -0,0 +1,129 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"argus-go/internal/domain"
+)
+
+// ChannelWebhook is the channel name of NotificationConfig.WebhookURL.
+const ChannelWebhook = "webhook"
+
+// testTimeout bounds the delivery of a test notification to one channel.
+const testTimeout = 10 * time.Second
+
+// ErrNoChannels is returned when testing an event manager without any
+// notification channel configured.
+var ErrNoChannels = errors.New("no notification channels configured")
+
+// DeliveryResult is the outcome of sending a notification to one channel.
+type DeliveryResult struct {
+	Channel    string `json:"channel"`
+	Target     string `json:"target"`
+	Delivered  bool   `json:"delivered"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Tester sends synthetic notifications so channels can be verified before a
+// real incident. Unlike the Notifier, it always delivers for real.
+type Tester struct {
+	client *http.Client
+	logger *slog.Logger
+}
+
+// NewTester creates a new notification tester.
+func NewTester(logger *slog.Logger) *Tester {
+	return &Tester{
+		client: &http.Client{Timeout: testTimeout},
+		logger: logger,
+	}
+}
+
+// Test sends a test notification through every channel of the event manager
+// and returns one result per channel. Delivery failures are reported in the
+// results, not as an error.
+func (t *Tester) Test(ctx context.Context, em *domain.EventManager) ([]DeliveryResult, error) {
+	var results []DeliveryResult
+
+	if url := em.NotificationConfig.WebhookURL; url != "" {
+		results = append(results, t.sendWebhook(ctx, url, testPayload(em)))
+	}
+
+	if len(results) == 0 {
+		return nil, ErrNoChannels
+	}
+	return results, nil
+}
+
+// sendWebhook POSTs the payload as JSON; any 2xx response counts as delivered.
+func (t *Tester) sendWebhook(ctx context.Context, url string, payload *NotificationPayload) DeliveryResult {
+	result := DeliveryResult{Channel: ChannelWebhook, Target: url}
+	start := time.Now()
+	defer func() {
+		result.DurationMs = time.Since(start).Milliseconds()
+		if !result.Delivered {
+			recordFailure(failureTestDelivery)
+		}
+		t.logger.Info("sent test notification",
+			"channel", result.Channel,
+			"eventManagerID", payload.EventManagerID,
+			"delivered", result.Delivered,
+			"statusCode", result.StatusCode,
+		)
+	}()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to encode payload: %v", err)
+		return result
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		result.Error = fmt.Sprintf("invalid webhook URL: %v", err)
+		return result
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	result.StatusCode = resp.StatusCode
+	result.Delivered = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !result.Delivered {
+		result.Error = "unexpected status " + resp.Status
+	}
+	return result
+}
+
+// testPayload builds the synthetic notification sent by Test.
+func testPayload(em *domain.EventManager) *NotificationPayload {
+	severity := domain.CurrentSeverityScale().Default()
+	return &NotificationPayload{
+		DedupKey:             "argus-test-notification",
+		EventManagerID:       em.ID,
+		Summary:              "Test notification from ArgusGo for " + em.Name,
+		Severity:             string(severity),
+		SeverityRank:         severity.Rank(),
+		SeverityPresentation: severity.Presentation(),
+		Status:               string(domain.AlertStatusActive),
+		Type:                 string(domain.AlertTypeParent),
+		Timestamp:            time.Now().UTC(),
+		Test:                 true,
+	}
+}
@@ -0,0 +1,65 @@
+package notification
+
+import (
+	"context"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/usage"
+)
+
+// UsageNotifier wraps a Notifier and counts every notification it sends in
+// the usage of the event manager.
+type UsageNotifier struct {
+	next  Notifier
+	meter *usage.Meter
+}
+
+// NewUsageNotifier creates a notifier that counts the notifications sent through next.
+func NewUsageNotifier(next Notifier, meter *usage.Meter) *UsageNotifier {
+	return &UsageNotifier{
+		next:  next,
+		meter: meter,
+	}
+}
+
+// NotifyNewParent sends and counts a notification for a new parent alert.
+func (n *UsageNotifier) NotifyNewParent(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyNewParent(ctx, alert, em)
+	n.meter.RecordNotification(em.ID)
+}
+
+// NotifyResolved sends and counts a notification for a resolved parent alert.
+func (n *UsageNotifier) NotifyResolved(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyResolved(ctx, alert, em)
+	n.meter.RecordNotification(em.ID)
+}
+
+// NotifyReminder sends and counts a reminder for an unacknowledged parent alert.
+func (n *UsageNotifier) NotifyReminder(ctx context.Context, alert *domain.Alert, em *domain.EventManager, count int) {
+	n.next.NotifyReminder(ctx, alert, em, count)
+	n.meter.RecordNotification(em.ID)
+}
+
+// NotifyChildAdded sends and counts a notification for a new child alert.
+func (n *UsageNotifier) NotifyChildAdded(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyChildAdded(ctx, alert, em)
+	n.meter.RecordNotification(em.ID)
+}
+
+// NotifyReactivated sends and counts a notification for a reactivated alert.
+func (n *UsageNotifier) NotifyReactivated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyReactivated(ctx, alert, em)
+	n.meter.RecordNotification(em.ID)
+}
+
+// NotifyAcknowledged sends and counts a notification for an acknowledged alert.
+func (n *UsageNotifier) NotifyAcknowledged(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyAcknowledged(ctx, alert, em)
+	n.meter.RecordNotification(em.ID)
+}
+
+// NotifyEscalated sends and counts a notification for an escalated parent alert.
+func (n *UsageNotifier) NotifyEscalated(ctx context.Context, alert *domain.Alert, em *domain.EventManager) {
+	n.next.NotifyEscalated(ctx, alert, em)
+	n.meter.RecordNotification(em.ID)
+}
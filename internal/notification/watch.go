@@ -0,0 +1,117 @@
+package notification
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+	"argus-go/internal/store"
+)
+
+// WatchNotifier sends the personal notifications of alert watches, through
+// the plugin of each watch's channel. It is independent of the notification
+// config of event managers: watchers hear of every change their watches
+// select, whether or not the event manager opted in to it. Notifications are
+// sent in the background, so alert processing never waits on them; failures
+// are logged and counted.
+type WatchNotifier struct {
+	watches  store.WatchRepository
+	channels map[domain.WatchChannelType]*Plugin
+	scrubber *domain.Scrubber
+	logger   *slog.Logger
+
+	wg sync.WaitGroup
+}
+
+// NewWatchNotifier creates a notifier of the watches in watches, delivering
+// each channel through its plugin. Alert text is masked with scrubber, which
+// may be nil.
+func NewWatchNotifier(watches store.WatchRepository, channels map[domain.WatchChannelType]*Plugin, scrubber *domain.Scrubber, logger *slog.Logger) *WatchNotifier {
+	return &WatchNotifier{
+		watches:  watches,
+		channels: channels,
+		scrubber: scrubber,
+		logger:   logger,
+	}
+}
+
+// Delivers returns true if notifications can be sent to the channel. A nil
+// notifier delivers to none.
+func (n *WatchNotifier) Delivers(channel domain.WatchChannelType) bool {
+	if n == nil {
+		return false
+	}
+	_, ok := n.channels[channel]
+	return ok
+}
+
+// Notify sends a notification of the given kind about the alert to the
+// watches matching it. A nil notifier sends nothing.
+func (n *WatchNotifier) Notify(ctx context.Context, alert *domain.Alert, em *domain.EventManager, kind domain.NotificationKind) {
+	if n == nil {
+		return
+	}
+
+	// Snapshot the alert: processing goes on changing it
+	snapshot := *alert
+	ctx = context.WithoutCancel(ctx)
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		n.send(ctx, &snapshot, em, kind)
+	}()
+}
+
+// Wait blocks until the notifications started so far have been sent.
+func (n *WatchNotifier) Wait() {
+	if n != nil {
+		n.wg.Wait()
+	}
+}
+
+// send delivers the notification to every matching watch.
+func (n *WatchNotifier) send(ctx context.Context, alert *domain.Alert, em *domain.EventManager, kind domain.NotificationKind) {
+	watches, err := n.watches.List(ctx)
+	if err != nil {
+		n.logger.Warn("failed to list watches", "dedupKey", alert.DedupKey, "kind", kind, "error", err)
+		recordFailure(failureWatch)
+		return
+	}
+
+	payload := buildPayload(n.scrubber.Alert(alert), em, kind)
+	for _, watch := range watches {
+		if !watch.Matches(alert, kind) {
+			continue
+		}
+		plugin, ok := n.channels[watch.Channel.Type]
+		if !ok {
+			n.logger.Warn("no plugin delivers the channel of a watch", "watch_id", watch.ID, "channel", watch.Channel.Type)
+			recordFailure(failureWatch)
+			continue
+		}
+
+		start := time.Now()
+		err := plugin.SendTo(ctx, payload, em, &PluginRecipient{
+			WatchID: watch.ID,
+			User:    watch.User,
+			Channel: string(watch.Channel.Type),
+			Address: watch.Channel.Address,
+		})
+		recordDelivery(channelLabel(channelWatch, string(watch.Channel.Type)), time.Since(start), err)
+		if err != nil {
+			n.logger.Warn("failed to send watch notification",
+				"watch_id", watch.ID,
+				"dedupKey", alert.DedupKey,
+				"kind", kind,
+				"error", err,
+			)
+			recordFailure(failureWatch)
+			continue
+		}
+		metrics.WatchNotifications.WithLabelValues(string(watch.Channel.Type)).Inc()
+	}
+}
@@ -0,0 +1,51 @@
+package notification
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/store/memory"
+)
+
+func TestWatchNotifier_Notify(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	plugin := newTestPlugin("watch")
+	defer plugin.Close()
+
+	ctx := context.Background()
+	watches := memory.NewWatchRepository()
+	for _, watch := range []*domain.Watch{
+		{ID: "w-1", User: "ana", Channel: domain.WatchChannel{Type: domain.WatchChannelEmail, Address: "oncall@example.com"}, DedupKeys: []string{"disk-1"}},
+		{ID: "w-2", User: "ana", Channel: domain.WatchChannel{Type: domain.WatchChannelEmail, Address: "oncall@example.com"}, DedupKeyPattern: "net-*"},
+		{ID: "w-3", User: "ben", Channel: domain.WatchChannel{Type: domain.WatchChannelSlack, Address: "U123"}, DedupKeys: []string{"disk-1"}},
+	} {
+		if err := watches.Create(ctx, watch); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	notifier := NewWatchNotifier(watches, map[domain.WatchChannelType]*Plugin{domain.WatchChannelEmail: plugin}, nil, logger)
+	if !notifier.Delivers(domain.WatchChannelEmail) || notifier.Delivers(domain.WatchChannelSlack) {
+		t.Error("Delivers() should report only the email channel")
+	}
+
+	// The email watch is delivered to its address; the slack watch has no
+	// plugin and fails; the pattern watch doesn't match
+	alert := &domain.Alert{DedupKey: "disk-1", EventManagerID: "em-1", Summary: "Disk full"}
+	before := Failures()
+	notifier.Notify(ctx, alert, &domain.EventManager{ID: "em-1"}, domain.NotificationNewParent)
+	notifier.Wait()
+	if got := Failures() - before; got != 1 {
+		t.Errorf("failures = %d, want 1 for the watch without a plugin", got)
+	}
+
+	var nilNotifier *WatchNotifier
+	nilNotifier.Notify(ctx, alert, &domain.EventManager{ID: "em-1"}, domain.NotificationNewParent)
+	nilNotifier.Wait()
+	if nilNotifier.Delivers(domain.WatchChannelEmail) {
+		t.Error("a nil notifier delivers nothing")
+	}
+}
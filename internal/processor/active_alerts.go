@@ -0,0 +1,116 @@
+package processor
+
+import (
+	"context"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+	"argus-go/internal/store"
+)
+
+// activeAlertsChanged adjusts the active alert gauges of the alert's event
+// manager, type, severity and class, and its live count, by delta. A live
+// count that fails to update is corrected by the next reconciliation.
+func (s *Service) activeAlertsChanged(ctx context.Context, alert *domain.Alert, delta int) {
+	metrics.ActiveAlerts.WithLabelValues(alert.EventManagerID, string(alert.Type)).Add(float64(delta))
+	metrics.AlertActive.WithLabelValues(alert.EventManagerID, string(alert.Severity), alert.Class).Add(float64(delta))
+
+	if err := s.stateStore.AdjustLiveCount(ctx, alert.EventManagerID, string(alert.Severity), delta); err != nil {
+		s.logger.WarnContext(ctx, "failed to adjust live count", "dedupKey", alert.DedupKey, "error", err)
+	}
+}
+
+// StartActiveAlertsReconciler resets the active alert gauges and live counts
+// to the counts of the alert repository now and then every interval, until
+// the context is canceled.
+func (s *Service) StartActiveAlertsReconciler(ctx context.Context, interval time.Duration) {
+	s.logger.InfoContext(ctx, "starting active alerts reconciler", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.ReconcileActiveAlerts(ctx); err != nil && ctx.Err() == nil {
+			s.logger.WarnContext(ctx, "failed to reconcile active alerts", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ReconcileActiveAlerts resets the active alert gauges and the live counts of
+// the state store to the counts of the alert repository. This corrects drift
+// from updates that failed after the gauge was adjusted, alerts changed by
+// other replicas or through the API, and alerts that were active before the
+// process started.
+func (s *Service) ReconcileActiveAlerts(ctx context.Context) error {
+	counts, err := s.alertRepo.CountActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Series without active alerts are dropped. The counts are finer than
+	// either gauge, so several add up to one series.
+	metrics.ActiveAlerts.Reset()
+	metrics.AlertActive.Reset()
+	for _, count := range counts {
+		metrics.ActiveAlerts.WithLabelValues(count.EventManagerID, string(count.Type)).Add(float64(count.Count))
+		metrics.AlertActive.WithLabelValues(count.EventManagerID, string(count.Severity), count.Class).Add(float64(count.Count))
+	}
+
+	if err := s.reconcileMutedAlerts(ctx, counts); err != nil {
+		s.logger.WarnContext(ctx, "failed to count muted alerts", "error", err)
+	}
+
+	liveCounts := make([]*store.LiveCount, len(counts))
+	for i, count := range counts {
+		liveCounts[i] = &store.LiveCount{EventManagerID: count.EventManagerID, Severity: string(count.Severity), Count: count.Count}
+	}
+	return s.stateStore.SetLiveCounts(ctx, liveCounts)
+}
+
+// reconcileMutedAlerts resets the muted alerts gauge to the active alerts
+// muted now. Silences matching alerts by event manager and severity alone
+// mute every alert of a count or none; others are counted by the alert
+// repository, for each event manager with active alerts.
+func (s *Service) reconcileMutedAlerts(ctx context.Context, counts []*domain.ActiveAlertCount) error {
+	metrics.MutedAlerts.Reset()
+	muting := s.muter.Snapshot()
+	if muting == nil {
+		return nil
+	}
+
+	if muting.BySeverity() {
+		for _, count := range counts {
+			alert := &domain.Alert{EventManagerID: count.EventManagerID, Severity: count.Severity, Status: domain.AlertStatusActive}
+			if status, _ := muting.Status(alert); status.IsMuted() {
+				metrics.MutedAlerts.WithLabelValues(count.EventManagerID, string(status)).Add(float64(count.Count))
+			}
+		}
+		return nil
+	}
+
+	counted := make(map[string]bool)
+	for _, count := range counts {
+		if counted[count.EventManagerID] {
+			continue
+		}
+		counted[count.EventManagerID] = true
+
+		for _, status := range []domain.AlertStatus{domain.AlertStatusSilenced, domain.AlertStatusSuppressed} {
+			n, err := s.alertRepo.Count(ctx, domain.AlertFilter{EventManagerID: count.EventManagerID, Status: status, Muting: muting})
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				metrics.MutedAlerts.WithLabelValues(count.EventManagerID, string(status)).Set(float64(n))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"context"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+	"argus-go/internal/store"
+)
+
+// candidateNamespace prefixes the event manager ID under which the parents
+// of candidate grouping rules are kept in the state store, apart from those
+// of the active rules.
+const candidateNamespace = "candidate:"
+
+// evaluateCandidate groups a new alert's event with the candidate grouping
+// rule of its event manager, if any, and records whether that decision
+// diverges from the one taken with the active rules: parentDedupKey is the
+// parent the alert was grouped under, or empty if it became a parent itself.
+// The candidate only tracks parents of its own, so it never affects alerts,
+// and failures are only logged.
+func (s *Service) evaluateCandidate(ctx context.Context, event *domain.InternalEvent, em *domain.EventManager, parentDedupKey string) {
+	if em.CandidateGroupingRuleID == "" {
+		return
+	}
+
+	rule, err := s.groupingRuleRepo.GetByID(ctx, em.CandidateGroupingRuleID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to fetch candidate grouping rule", "id", em.CandidateGroupingRuleID, "error", err)
+		return
+	}
+
+	namespace := candidateNamespace + em.ID
+	groupingValue := rule.ExtractGroupingValue(&event.Event)
+	parent, err := s.stateStore.GetParent(ctx, namespace, rule.GroupingKey, groupingValue)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to check for candidate parent", "dedupKey", event.DedupKey, "error", err)
+		return
+	}
+
+	// A retried event finds the parent it registered itself
+	candidateParent := ""
+	if parent != nil && parent.DedupKey != event.DedupKey {
+		candidateParent = parent.DedupKey
+	} else if parent == nil {
+		parent = &store.ParentState{DedupKey: event.DedupKey, CreatedAt: s.now()}
+		if err := s.stateStore.SetParent(ctx, namespace, rule.GroupingKey, groupingValue, parent, rule.TimeWindow()); err != nil {
+			s.logger.WarnContext(ctx, "failed to save candidate parent", "dedupKey", event.DedupKey, "error", err)
+			return
+		}
+	}
+
+	if candidateParent == parentDedupKey {
+		metrics.CandidateGroupingDecisions.WithLabelValues(em.ID, "match").Inc()
+		return
+	}
+	metrics.CandidateGroupingDecisions.WithLabelValues(em.ID, "diverged").Inc()
+	s.logger.InfoContext(ctx, "candidate grouping rule diverged",
+		"dedupKey", event.DedupKey,
+		"eventManagerID", em.ID,
+		"candidateRuleID", rule.ID,
+		"parentDedupKey", parentDedupKey,
+		"candidateParentDedupKey", candidateParent,
+	)
+}
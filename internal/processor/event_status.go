@@ -0,0 +1,52 @@
+package processor
+
+import (
+	"context"
+
+	"argus-go/internal/domain"
+)
+
+// outcomeKey is the context key of the outcome of the event being processed.
+type outcomeKey struct{}
+
+// eventOutcome is what processing an event did, set by the handlers that
+// don't create an alert or apply a resolve, for the status of the event.
+type eventOutcome struct {
+	state  domain.EventState
+	reason string
+}
+
+// withOutcome returns a context the handlers record the outcome of an event
+// on, and the outcome.
+func withOutcome(ctx context.Context) (context.Context, *eventOutcome) {
+	outcome := &eventOutcome{}
+	return context.WithValue(ctx, outcomeKey{}, outcome), outcome
+}
+
+// setOutcome records the outcome of the event processed with ctx, if it was
+// consumed from the queue.
+func setOutcome(ctx context.Context, state domain.EventState, reason string) {
+	if outcome, ok := ctx.Value(outcomeKey{}).(*eventOutcome); ok {
+		outcome.state = state
+		outcome.reason = reason
+	}
+}
+
+// recordOutcome records the status an event reached once processed with
+// err. Events without a recorded outcome created their alert, or applied
+// their resolve. Canceled events keep their status, since the queue
+// delivers them again.
+func (s *Service) recordOutcome(ctx context.Context, event *domain.InternalEvent, outcome *eventOutcome, err error) {
+	switch {
+	case err != nil && classifyError(err) == errorClassCanceled && ctx.Err() != nil:
+		return
+	case err != nil:
+		s.tracker.Record(ctx, event, domain.EventFailed, err.Error())
+	case outcome.state != "":
+		s.tracker.Record(ctx, event, outcome.state, outcome.reason)
+	case event.Action == domain.ActionResolve:
+		s.tracker.Record(ctx, event, domain.EventResolved, "")
+	default:
+		s.tracker.Record(ctx, event, domain.EventAlertCreated, "")
+	}
+}
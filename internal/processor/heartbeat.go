@@ -0,0 +1,45 @@
+package processor
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// heartbeat tracks the progress of the consume loop, so health checks can
+// tell a processor that stopped or hangs on an event from an idle one.
+type heartbeat struct {
+	running atomic.Bool
+
+	// handlingSince is when the event being handled was taken up, in Unix
+	// nanoseconds; 0 while no event is handled.
+	handlingSince atomic.Int64
+
+	// lastEventAt is when the last event was handled, in Unix nanoseconds.
+	lastEventAt atomic.Int64
+}
+
+// begin records that handling an event started.
+func (h *heartbeat) begin(now time.Time) {
+	h.handlingSince.Store(now.UnixNano())
+}
+
+// end records that handling an event finished.
+func (h *heartbeat) end(now time.Time) {
+	h.handlingSince.Store(0)
+	h.lastEventAt.Store(now.UnixNano())
+}
+
+// status adds the heartbeat to a status. The processor is stalled when one
+// event has been handled for longer than stallTimeout; a non-positive
+// timeout never reports it stalled.
+func (h *heartbeat) status(status Status, now time.Time, stallTimeout time.Duration) Status {
+	status.Running = h.running.Load()
+	if last := h.lastEventAt.Load(); last != 0 {
+		lastEventAt := time.Unix(0, last).UTC()
+		status.LastEventAt = &lastEventAt
+	}
+	if since := h.handlingSince.Load(); since != 0 && stallTimeout > 0 {
+		status.Stalled = now.Sub(time.Unix(0, since)) > stallTimeout
+	}
+	return status
+}
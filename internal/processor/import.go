@@ -0,0 +1,150 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// ImportAlerts writes historical alerts, e.g. exported from another alerting
+// system, with their original timestamps. Imported alerts are not grouped and
+// send no notifications or reminders; later events deduplicate against them
+// like against any other alert. Alerts whose dedup key already exists, and
+// the children of such parents, are skipped, so an import can be retried.
+func (s *Service) ImportAlerts(ctx context.Context, req *domain.AlertImportRequest) (*domain.AlertImportResult, error) {
+	if err := req.Validate(s.now()); err != nil {
+		return nil, err
+	}
+
+	// Every event manager must exist before anything is written
+	for _, id := range importEventManagerIDs(req) {
+		em, err := s.eventManagerRepo.GetByID(ctx, id)
+		if errors.Is(err, domain.ErrEventManagerNotFound) {
+			return nil, fmt.Errorf("%w: event manager %s not found", domain.ErrInvalidImport, id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if em.IsDeleted() {
+			return nil, fmt.Errorf("%w: event manager %s has been deleted", domain.ErrInvalidImport, id)
+		}
+	}
+
+	result := &domain.AlertImportResult{Skipped: []string{}}
+	skipped := make(map[string]bool)
+	children := make(map[string]int)
+	for i := range req.Alerts {
+		record := &req.Alerts[i]
+		exists, err := s.alertExists(ctx, record.DedupKey)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			skipped[record.DedupKey] = true
+		}
+	}
+	for i := range req.Alerts {
+		record := &req.Alerts[i]
+		if record.Type == domain.AlertTypeChild && !skipped[record.ParentDedupKey] {
+			children[record.ParentDedupKey]++
+		}
+	}
+
+	// Parents are written before their children
+	for _, childPass := range []bool{false, true} {
+		for i := range req.Alerts {
+			record := &req.Alerts[i]
+			if (record.Type == domain.AlertTypeChild) != childPass {
+				continue
+			}
+			if skipped[record.DedupKey] || skipped[record.ParentDedupKey] {
+				result.Skipped = append(result.Skipped, record.DedupKey)
+				continue
+			}
+
+			alert := record.ToAlert()
+			alert.ChildCount = children[alert.DedupKey]
+			if err := s.importAlert(ctx, alert); err != nil {
+				return result, fmt.Errorf("alerts[%d]: %w", i, err)
+			}
+			result.Imported++
+		}
+	}
+
+	s.logger.InfoContext(ctx, "imported alerts", "imported", result.Imported, "skipped", len(result.Skipped))
+	return result, nil
+}
+
+// alertExists reports whether an alert with the dedup key exists. The
+// repository is checked too, as the state store may have evicted the alert.
+func (s *Service) alertExists(ctx context.Context, dedupKey string) (bool, error) {
+	state, err := s.stateStore.GetAlert(ctx, dedupKey)
+	if err != nil {
+		return false, err
+	}
+	if state != nil {
+		return true, nil
+	}
+	_, err = s.alertRepo.GetByDedupKey(ctx, dedupKey)
+	if errors.Is(err, domain.ErrAlertNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// importAlert saves an imported alert to the state store and the repository,
+// rolling back the cached state if the repository fails.
+func (s *Service) importAlert(ctx context.Context, alert *domain.Alert) error {
+	alert.ID = uuid.New().String()
+
+	alertState := &store.AlertState{
+		DedupKey:       alert.DedupKey,
+		EventManagerID: alert.EventManagerID,
+		Type:           string(alert.Type),
+		Status:         string(alert.Status),
+		ParentDedupKey: alert.ParentDedupKey,
+	}
+	if err := s.stateStore.SetAlert(ctx, alertState); err != nil {
+		s.logger.ErrorContext(ctx, "failed to save alert state", "error", err)
+		return err
+	}
+	if alert.IsChild() {
+		if err := s.stateStore.AddChild(ctx, alert.ParentDedupKey, alert.DedupKey); err != nil {
+			s.logger.ErrorContext(ctx, "failed to add child to parent", "error", err)
+			s.rollback(s.stateStore.DeleteAlert(ctx, alert.DedupKey))
+			return err
+		}
+	}
+
+	if err := s.alertRepo.Create(ctx, alert); err != nil {
+		s.logger.ErrorContext(ctx, "failed to persist alert", "error", err)
+		if alert.IsChild() {
+			s.rollback(s.stateStore.RemoveChild(ctx, alert.ParentDedupKey, alert.DedupKey))
+		}
+		s.rollback(s.stateStore.DeleteAlert(ctx, alert.DedupKey))
+		return err
+	}
+	if alert.IsActive() {
+		s.activeAlertsChanged(ctx, alert, 1)
+	}
+	return nil
+}
+
+// importEventManagerIDs returns the distinct event managers of an import.
+func importEventManagerIDs(req *domain.AlertImportRequest) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for i := range req.Alerts {
+		id := req.Alerts[i].EventManagerID
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,108 @@
+package processor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"argus-go/internal/metrics"
+)
+
+// Status is the consumption state of the processor.
+type Status struct {
+	Paused   bool       `json:"paused"`
+	PausedAt *time.Time `json:"paused_at,omitempty"`
+
+	// Running is whether the processor consumes the queue.
+	Running bool `json:"running"`
+
+	// LastEventAt is when the processor last finished handling an event.
+	LastEventAt *time.Time `json:"last_event_at,omitempty"`
+
+	// Stalled is whether one event has been handled for longer than
+	// processor.stall_timeout, e.g. because the processor hangs.
+	Stalled bool `json:"stalled"`
+}
+
+// Healthy reports whether the processor runs and isn't stalled. A paused
+// processor is healthy.
+func (s Status) Healthy() bool {
+	return s.Running && !s.Stalled
+}
+
+// pauseGate holds back message handling while the processor is paused.
+// The zero value is running.
+type pauseGate struct {
+	mu       sync.Mutex
+	pausedAt time.Time
+
+	// resumed is closed on resume; it is nil while running.
+	resumed chan struct{}
+}
+
+// wait blocks while the gate is paused, until it is resumed or the context
+// is canceled.
+func (g *pauseGate) wait(ctx context.Context) error {
+	g.mu.Lock()
+	resumed := g.resumed
+	g.mu.Unlock()
+
+	if resumed == nil {
+		return nil
+	}
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// status returns the state of the gate.
+func (g *pauseGate) status() Status {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.resumed == nil {
+		return Status{}
+	}
+	pausedAt := g.pausedAt
+	return Status{Paused: true, PausedAt: &pausedAt}
+}
+
+// Pause stops consuming events: messages being processed complete, the
+// following ones wait until Resume. Reminders are not sent meanwhile either.
+// The HTTP API keeps accepting events, which queue up. Pausing a paused
+// processor has no effect.
+func (s *Service) Pause() Status {
+	s.pause.mu.Lock()
+	if s.pause.resumed == nil {
+		s.pause.resumed = make(chan struct{})
+		s.pause.pausedAt = s.now()
+		metrics.ProcessorPaused.Set(1)
+		s.logger.Warn("processor paused")
+	}
+	s.pause.mu.Unlock()
+
+	return s.Status()
+}
+
+// Resume continues consuming events after Pause. Resuming a running
+// processor has no effect.
+func (s *Service) Resume() Status {
+	s.pause.mu.Lock()
+	if s.pause.resumed != nil {
+		close(s.pause.resumed)
+		s.pause.resumed = nil
+		metrics.ProcessorPaused.Set(0)
+		s.logger.Info("processor resumed", "paused_for", s.now().Sub(s.pause.pausedAt))
+	}
+	s.pause.mu.Unlock()
+
+	return s.Status()
+}
+
+// Status returns whether the processor is paused, running and stalled.
+func (s *Service) Status() Status {
+	return s.heartbeat.status(s.pause.status(), s.now(), s.cfg.StallTimeout)
+}
@@ -0,0 +1,257 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+	"argus-go/internal/store"
+)
+
+// PendingResolvePublisher queues the checks of parent alerts left waiting
+// for their children, e.g. the ingest service.
+type PendingResolvePublisher interface {
+	PublishPendingResolveSweep(ctx context.Context, alert *domain.Alert, partitionKey string) error
+}
+
+// StartPendingResolveSweeper queues checks of the parent alerts left waiting
+// for children that are no longer active, or longer than the maximum, right
+// away and then every interval, until the context is canceled. Parents
+// updated within the last interval are left to the events being processed
+// for them. Every processor sweeps; a parent checked twice is resolved once.
+func (s *Service) StartPendingResolveSweeper(ctx context.Context, interval time.Duration, resolves PendingResolvePublisher) {
+	s.logger.InfoContext(ctx, "starting pending resolve sweeper", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.SweepPendingResolves(ctx, interval, resolves); err != nil && ctx.Err() == nil {
+			s.logger.WarnContext(ctx, "failed to sweep pending resolves", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SweepPendingResolves finds the active parent alerts whose resolve was
+// requested and may be overdue, queues a check of each through resolves,
+// and returns the number queued. A parent waits for its children until the
+// resolve of the last one checks on it; if the processor stops between
+// resolving that child and checking, for example in a crash, nothing checks
+// on the parent again once events stop. A parent that fails to be queued is
+// skipped, and the errors are returned together.
+//
+// Parents are checked whose resolve waited longer than the maximum pending
+// resolve, however recently updated, and those last updated at least
+// olderThan ago that have no active children or lost their pending resolve
+// from the state store. The sweep only reads: the checks travel the queue
+// under the partition key of the parent, so the processor makes them in
+// order with the events of the group, such as a child grouped meanwhile.
+func (s *Service) SweepPendingResolves(ctx context.Context, olderThan time.Duration, resolves PendingResolvePublisher) (int, error) {
+	parents, err := s.alertRepo.List(ctx, domain.AlertFilter{
+		Type:             domain.AlertTypeParent,
+		Status:           domain.AlertStatusActive,
+		ResolveRequested: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := s.now().Add(-olderThan)
+	queued := 0
+	var errs []error
+	for _, parent := range parents {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+
+		// A parent that fails to be checked is left to the next sweep, and
+		// does not hold up the others
+		ok, err := s.queuePendingResolveCheck(ctx, parent, parent.UpdatedAt.After(cutoff), resolves)
+		if err != nil {
+			s.logger.WarnContext(ctx, "failed to queue pending resolve check", "dedupKey", parent.DedupKey, "error", err)
+			errs = append(errs, fmt.Errorf("parent %s: %w", parent.DedupKey, err))
+			continue
+		}
+		if ok {
+			queued++
+		}
+	}
+
+	if queued > 0 {
+		s.logger.InfoContext(ctx, "queued pending resolve checks", "parents", queued)
+	}
+	return queued, errors.Join(errs...)
+}
+
+// queuePendingResolveCheck queues a check of a parent alert through
+// resolves if its pending resolve is due, and reports whether it did.
+func (s *Service) queuePendingResolveCheck(ctx context.Context, parent *domain.Alert, recent bool, resolves PendingResolvePublisher) (bool, error) {
+	due, err := s.pendingResolveDue(ctx, parent, recent)
+	if err != nil || !due {
+		return false, err
+	}
+
+	alertState, err := s.stateStore.GetAlert(ctx, parent.DedupKey)
+	if err != nil {
+		return false, err
+	}
+	var partitionKey string
+	if alertState != nil {
+		partitionKey = alertState.PartitionKey
+	}
+	if err := resolves.PublishPendingResolveSweep(ctx, parent, partitionKey); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// pendingResolveDue reports whether the pending resolve of a parent alert
+// needs a check, without changing anything.
+func (s *Service) pendingResolveDue(ctx context.Context, parent *domain.Alert, recent bool) (bool, error) {
+	pending, err := s.stateStore.GetPendingResolve(ctx, parent.DedupKey)
+	if err != nil {
+		return false, err
+	}
+	requestedAt := parent.UpdatedAt
+	if pending != nil {
+		requestedAt = pending.RequestedAt
+	}
+	if s.pendingResolveExpired(requestedAt) {
+		return true, nil
+	}
+	if recent {
+		return false, nil
+	}
+	if pending == nil {
+		return true, nil
+	}
+
+	activeChildren, err := s.alertRepo.CountActiveChildren(ctx, parent.DedupKey)
+	if err != nil {
+		return false, err
+	}
+	return activeChildren == 0, nil
+}
+
+// pendingResolveExpired reports whether a resolve requested at requestedAt
+// waited longer than the maximum pending resolve.
+func (s *Service) pendingResolveExpired(requestedAt time.Time) bool {
+	return s.cfg.MaxPendingResolve > 0 && s.now().Sub(requestedAt) >= s.cfg.MaxPendingResolve
+}
+
+// sweepPendingResolve handles a check queued by the sweeper: it
+// force-resolves a parent alert whose resolve waited too long, completes
+// its requested resolve if none of its children is active, and otherwise
+// gives it its pending resolve back, should the state store have lost it.
+// The stored alert decides, as its cached state may be lost too.
+func (s *Service) sweepPendingResolve(ctx context.Context, event *domain.InternalEvent) error {
+	parent, err := s.alertRepo.GetByDedupKey(ctx, event.DedupKey)
+	if errors.Is(err, domain.ErrAlertNotFound) {
+		setOutcome(ctx, domain.EventDropped, "no alert with the dedup key")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !parent.IsParent() || !parent.IsActive() || !parent.ResolveRequested {
+		s.logger.DebugContext(ctx, "no pending resolve to check", "dedupKey", event.DedupKey)
+		setOutcome(ctx, domain.EventDropped, "no pending resolve")
+		return nil
+	}
+
+	pending, err := s.stateStore.GetPendingResolve(ctx, parent.DedupKey)
+	if err != nil {
+		return err
+	}
+	if pending == nil {
+		pending = &store.PendingResolve{RequestedAt: parent.UpdatedAt}
+	}
+	if s.pendingResolveExpired(pending.RequestedAt) {
+		return s.forceResolvePending(ctx, parent, s.now().Sub(pending.RequestedAt))
+	}
+
+	activeChildren, err := s.alertRepo.CountActiveChildren(ctx, parent.DedupKey)
+	if err != nil {
+		return err
+	}
+
+	if activeChildren > 0 {
+		pending.RemainingChildren = activeChildren
+		return s.stateStore.SetPendingResolve(ctx, parent.DedupKey, pending)
+	}
+
+	alertState, err := s.parentState(ctx, parent)
+	if err != nil {
+		return err
+	}
+
+	// The parent is resolved as recorded with its resolve request
+	if err := s.completeParentResolution(ctx, parent.DedupKey, alertState, nil); err != nil {
+		return err
+	}
+	metrics.PendingResolvesRecovered.Inc()
+	s.logger.InfoContext(ctx, "resolved parent left waiting for resolved children", "dedupKey", parent.DedupKey)
+	return nil
+}
+
+// forceResolvePending resolves a parent alert whose resolve waited longer
+// than the maximum for its children, with the children still active if so
+// configured. The resolution names the timeout, for the timeline of the
+// alert.
+func (s *Service) forceResolvePending(ctx context.Context, parent *domain.Alert, waited time.Duration) error {
+	resolution := domain.Resolution{
+		ResolvedBy: domain.ResolvedByPendingTimeout,
+		Reason:     fmt.Sprintf("resolve waited %s for the children, longer than %s", waited.Round(time.Second), s.cfg.MaxPendingResolve),
+	}
+	if requested := parent.Resolution; requested != nil {
+		resolution.Actor = requested.Actor
+	}
+
+	children := 0
+	if s.cfg.ForceResolveChildren {
+		resolved, err := s.resolveGroup(ctx, parent, resolution)
+		if err != nil {
+			return err
+		}
+		children = resolved
+	} else {
+		alertState, err := s.parentState(ctx, parent)
+		if err != nil {
+			return err
+		}
+		if err := s.completeParentResolution(ctx, parent.DedupKey, alertState, &resolution); err != nil {
+			return err
+		}
+	}
+
+	metrics.PendingResolvesForced.Inc()
+	s.logger.WarnContext(ctx, "force-resolved parent waiting for its children", "dedupKey", parent.DedupKey, "waited", waited, "children", children)
+	return nil
+}
+
+// parentState returns the cached state of a parent alert, or one made from
+// the alert if the state store lost it.
+func (s *Service) parentState(ctx context.Context, parent *domain.Alert) (*store.AlertState, error) {
+	alertState, err := s.stateStore.GetAlert(ctx, parent.DedupKey)
+	if err != nil {
+		return nil, err
+	}
+	if alertState == nil {
+		alertState = &store.AlertState{
+			DedupKey:       parent.DedupKey,
+			EventManagerID: parent.EventManagerID,
+			Type:           string(parent.Type),
+		}
+	}
+	return alertState, nil
+}
@@ -0,0 +1,139 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// reminderBatchSize bounds the reminders sent per check; the rest are sent
+// at the next check.
+const reminderBatchSize = 100
+
+// scheduleReminder schedules the first reminder of a parent alert that became
+// active at from, if its event manager sends reminders. Failures are logged
+// only, so they never affect alert processing.
+func (s *Service) scheduleReminder(ctx context.Context, alert *domain.Alert, em *domain.EventManager, from time.Time) {
+	if !em.NotificationConfig.RemindersEnabled() {
+		return
+	}
+
+	reminder := &store.Reminder{
+		DedupKey: alert.DedupKey,
+		DueAt:    from.Add(em.NotificationConfig.ReminderInterval()),
+	}
+	if err := s.stateStore.SetReminder(ctx, reminder); err != nil {
+		s.logger.WarnContext(ctx, "failed to schedule reminder", "dedupKey", alert.DedupKey, "error", err)
+	}
+}
+
+// StartReminders sends the due reminders every interval until the context is
+// canceled. Ticks are skipped while the processor is paused.
+func (s *Service) StartReminders(ctx context.Context, interval time.Duration) {
+	s.logger.InfoContext(ctx, "starting reminder scheduler", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s.Status().Paused {
+				continue
+			}
+			if _, err := s.SendDueReminders(ctx, s.now()); err != nil {
+				s.logger.WarnContext(ctx, "failed to send reminders", "error", err)
+			}
+		}
+	}
+}
+
+// SendDueReminders sends the reminders due at now and returns the number of
+// alerts reminded of. Reminders of alerts that were resolved or acknowledged
+// in the meantime are canceled, as are those of event managers that no longer
+// send reminders.
+func (s *Service) SendDueReminders(ctx context.Context, now time.Time) (int, error) {
+	reminders, err := s.stateStore.GetDueReminders(ctx, now, reminderBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, reminder := range reminders {
+		ok, err := s.sendReminder(ctx, reminder, now)
+		if err != nil {
+			return sent, err
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// sendReminder sends one due reminder to the owner of the alert and its
+// subscribers, then schedules the next one or, after the last, cancels it.
+// It reports whether the reminder was sent.
+func (s *Service) sendReminder(ctx context.Context, reminder *store.Reminder, now time.Time) (bool, error) {
+	alert, err := s.alertRepo.GetByDedupKey(ctx, reminder.DedupKey)
+	if errors.Is(err, domain.ErrAlertNotFound) {
+		return false, s.stateStore.DeleteReminder(ctx, reminder.DedupKey)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	em, err := s.eventManagerRepo.GetByID(ctx, alert.EventManagerID)
+	if errors.Is(err, domain.ErrEventManagerNotFound) {
+		return false, s.stateStore.DeleteReminder(ctx, reminder.DedupKey)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	config := em.NotificationConfig
+	if !alert.IsParent() || !alert.IsActive() || alert.IsAcknowledged() || em.IsDeleted() || !config.RemindersEnabled() {
+		return false, s.stateStore.DeleteReminder(ctx, reminder.DedupKey)
+	}
+
+	reminder.Sent++
+	s.notifier.NotifyReminder(ctx, alert, em, reminder.Sent)
+	s.watchers.Notify(ctx, alert, em, domain.NotificationReminder)
+	s.notifySubscribersReminder(ctx, alert, reminder.Sent)
+
+	s.logger.InfoContext(ctx, "sent alert reminder",
+		"dedupKey", alert.DedupKey,
+		"eventManagerID", alert.EventManagerID,
+		"count", reminder.Sent,
+	)
+
+	if reminder.Sent >= config.MaxReminders {
+		// Still unacknowledged after the last reminder
+		s.notifyLifecycle(ctx, alert, em, domain.NotificationEscalated)
+		return true, s.stateStore.DeleteReminder(ctx, reminder.DedupKey)
+	}
+	reminder.DueAt = now.Add(config.ReminderInterval())
+	return true, s.stateStore.SetReminder(ctx, reminder)
+}
+
+// notifySubscribersReminder sends a reminder of a parent alert to the event
+// managers subscribed to it that are not deleted.
+func (s *Service) notifySubscribersReminder(ctx context.Context, alert *domain.Alert, count int) {
+	for _, id := range alert.SubscriberIDs {
+		em, err := s.eventManagerRepo.GetByID(ctx, id)
+		if err != nil {
+			s.logger.WarnContext(ctx, "failed to get subscribed event manager for notification", "eventManagerID", id, "error", err)
+			continue
+		}
+		if em.IsDeleted() {
+			continue
+		}
+		s.notifier.NotifyReminder(ctx, alert, em, count)
+	}
+}
@@ -0,0 +1,99 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+)
+
+// Error classes of failed events, used as the label of the retry metrics.
+const (
+	errorClassCanceled = "canceled"
+	errorClassNotFound = "not_found"
+	errorClassStore    = "store"
+)
+
+// classifyError returns the error class of a processing error. Only store
+// errors are retried: a missing event manager, grouping rule or alert fails
+// the same way on every attempt, and canceled work must stop.
+func classifyError(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return errorClassCanceled
+	case errors.Is(err, domain.ErrEventManagerNotFound),
+		errors.Is(err, domain.ErrGroupingRuleNotFound),
+		errors.Is(err, domain.ErrAlertNotFound):
+		return errorClassNotFound
+	default:
+		return errorClassStore
+	}
+}
+
+// processWithRetry processes an event, retrying store errors with
+// exponential backoff and jitter up to the configured number of retries.
+// Events that still fail are poison: they are logged and counted, and the
+// error is returned to the queue.
+func (s *Service) processWithRetry(ctx context.Context, event *domain.InternalEvent) error {
+	for attempt := 0; ; attempt++ {
+		err := s.processEvent(ctx, event)
+		if err == nil {
+			return nil
+		}
+
+		class := classifyError(err)
+		if class == errorClassCanceled && ctx.Err() != nil {
+			return err
+		}
+		if class != errorClassStore || attempt >= s.cfg.MaxRetries {
+			metrics.ProcessorPoisonMessages.WithLabelValues(class).Inc()
+			s.poisoned.Add(1)
+			s.logger.ErrorContext(ctx, "giving up on event",
+				"dedupKey", event.DedupKey,
+				"action", event.Action,
+				"errorClass", class,
+				"retries", attempt,
+				"error", err,
+			)
+			return err
+		}
+
+		metrics.ProcessorRetries.WithLabelValues(class).Inc()
+		backoff := s.retryBackoff(attempt)
+		s.logger.WarnContext(ctx, "retrying event",
+			"dedupKey", event.DedupKey,
+			"attempt", attempt+1,
+			"backoff", backoff,
+			"error", err,
+		)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// retryBackoff returns the delay before the given retry, counted from 0: the
+// initial backoff doubled per retry and capped, with jitter drawing it from
+// its upper half so concurrent retries spread out.
+func (s *Service) retryBackoff(attempt int) time.Duration {
+	backoff := s.cfg.RetryBackoff
+	for i := 0; i < attempt && backoff < s.cfg.MaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if s.cfg.MaxRetryBackoff > 0 && backoff > s.cfg.MaxRetryBackoff {
+		backoff = s.cfg.MaxRetryBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	half := backoff / 2
+	return half + rand.N(backoff-half+1)
+}
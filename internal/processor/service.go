@@ -8,14 +8,24 @@ import (
 	"encoding/json"
 	"errors"
 	"log/slog"
+	"sort"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
 	"argus-go/internal/domain"
+	"argus-go/internal/eventstatus"
+	"argus-go/internal/logging"
+	"argus-go/internal/metrics"
 	"argus-go/internal/notification"
 	"argus-go/internal/queue"
+	"argus-go/internal/remediation"
+	"argus-go/internal/slo"
 	"argus-go/internal/store"
+	"argus-go/internal/usage"
 )
 
 // Service processes events from the queue and manages alert lifecycle.
@@ -32,7 +42,31 @@ type Service struct {
 	eventManagerRepo store.EventManagerRepository
 	groupingRuleRepo store.GroupingRuleRepository
 	notifier         notification.Notifier
+	remediation      *remediation.Executor
+	watchers         *notification.WatchNotifier
+	muter            *notification.Muter
+	sloTracker       *slo.Tracker
+	usage            *usage.Meter
+	tracker          *eventstatus.Tracker
+	storms           *stormDetector
+	stormConfig      config.StormsConfig
+	globalDedup      bool
+	cfg              config.ProcessorConfig
+	clock            clock.Clock
 	logger           *slog.Logger
+
+	// pause holds back consumption while the processor is paused
+	pause pauseGate
+
+	// heartbeat tracks whether the processor runs and handles events
+	heartbeat heartbeat
+
+	// lag is how long the last event waited in the queue, in nanoseconds;
+	// poisoned counts the events given up on. Both feed self-monitoring.
+	// processed counts the events handled, for the autoscaling signal.
+	lag       atomic.Int64
+	poisoned  atomic.Uint64
+	processed atomic.Uint64
 }
 
 // NewService creates a new processor service.
@@ -43,6 +77,15 @@ func NewService(
 	eventManagerRepo store.EventManagerRepository,
 	groupingRuleRepo store.GroupingRuleRepository,
 	notifier notification.Notifier,
+	remediator *remediation.Executor,
+	watchers *notification.WatchNotifier,
+	muter *notification.Muter,
+	sloTracker *slo.Tracker,
+	meter *usage.Meter,
+	tracker *eventstatus.Tracker,
+	dedupConfig config.DedupConfig,
+	processorConfig config.ProcessorConfig,
+	clk clock.Clock,
 	logger *slog.Logger,
 ) *Service {
 	return &Service{
@@ -52,41 +95,118 @@ func NewService(
 		eventManagerRepo: eventManagerRepo,
 		groupingRuleRepo: groupingRuleRepo,
 		notifier:         notifier,
+		remediation:      remediator,
+		watchers:         watchers,
+		muter:            muter,
+		sloTracker:       sloTracker,
+		usage:            meter,
+		tracker:          tracker,
+		storms:           newStormDetector(clk.Now().UTC()),
+		stormConfig:      processorConfig.Storms,
+		globalDedup:      dedupConfig.Global,
+		cfg:              processorConfig,
+		clock:            clk,
 		logger:           logger,
 	}
 }
 
+// now returns the current time of the service's clock in UTC.
+func (s *Service) now() time.Time {
+	return s.clock.Now().UTC()
+}
+
+// Lag returns how long the last processed event waited between ingestion and
+// processing.
+func (s *Service) Lag() time.Duration {
+	return time.Duration(s.lag.Load())
+}
+
+// PoisonMessages returns the number of events given up on since the service
+// started.
+func (s *Service) PoisonMessages() uint64 {
+	return s.poisoned.Load()
+}
+
+// Processed returns the number of events handled since the service started,
+// whether they succeeded or not.
+func (s *Service) Processed() uint64 {
+	return s.processed.Load()
+}
+
 // Start begins consuming events from the queue and processing them.
 // This is a blocking call that runs until the context is canceled.
 func (s *Service) Start(ctx context.Context) error {
-	s.logger.Info("starting processor service")
+	s.logger.InfoContext(ctx, "starting processor service")
+	s.heartbeat.running.Store(true)
+	defer s.heartbeat.running.Store(false)
 	return s.consumer.Start(ctx, s.handleMessage)
 }
 
 // handleMessage is the callback for processing each message from the queue.
 func (s *Service) handleMessage(ctx context.Context, msg *queue.Message) error {
+	if err := s.pause.wait(ctx); err != nil {
+		return err
+	}
+	s.heartbeat.begin(s.now())
+	defer func() { s.heartbeat.end(s.now()) }()
+
 	// Deserialize the internal event
 	var event domain.InternalEvent
 	if err := json.Unmarshal(msg.Value, &event); err != nil {
-		s.logger.Error("failed to deserialize event", "error", err)
+		s.logger.ErrorContext(ctx, "failed to deserialize event", "error", err)
 		// Return nil to avoid reprocessing malformed messages
 		return nil
 	}
 
-	s.logger.Debug("processing event",
+	// The payload is the source of truth for the event; the headers add
+	// the trace context and retry count of the message
+	headers, err := queue.DecodeHeaders(msg.Headers)
+	if err != nil {
+		s.logger.WarnContext(ctx, "invalid message headers", "dedupKey", event.DedupKey, "error", err)
+	}
+
+	// The logs of the event carry the correlation ID and trace ID of its
+	// ingest request
+	ctx = logging.WithCorrelationID(ctx, event.CorrelationID)
+	ctx = logging.WithTraceContext(ctx, logging.TraceContext{
+		TraceParent: headers.TraceParent,
+		TraceState:  headers.TraceState,
+	})
+
+	if !event.ReceivedAt.IsZero() {
+		s.lag.Store(int64(s.now().Sub(event.ReceivedAt)))
+	}
+	// Checks queued by the pending resolve sweeper are not ingested events
+	if !event.SweepPendingResolve {
+		s.usage.RecordEvent(event.EventManagerID)
+		s.storms.record(event.EventManagerID, 1+event.SampledEvents)
+	}
+
+	s.logger.DebugContext(ctx, "processing event",
 		"dedupKey", event.DedupKey,
 		"action", event.Action,
 		"groupingValue", event.GroupingValue,
+		"retryCount", headers.RetryCount,
 	)
 
-	// Route to appropriate handler based on action
+	defer s.processed.Add(1)
+	s.tracker.Record(ctx, &event, domain.EventProcessing, "")
+	ctx, outcome := withOutcome(ctx)
+	err = s.processWithRetry(ctx, &event)
+	s.recordOutcome(ctx, &event, outcome, err)
+	return err
+}
+
+// processEvent routes an event to the handler of its action.
+func (s *Service) processEvent(ctx context.Context, event *domain.InternalEvent) error {
 	switch event.Action {
 	case domain.ActionTrigger:
-		return s.handleTrigger(ctx, &event)
+		return s.handleTrigger(ctx, event)
 	case domain.ActionResolve:
-		return s.handleResolve(ctx, &event)
+		return s.handleResolve(ctx, event)
 	default:
-		s.logger.Warn("unknown action", "action", event.Action, "dedupKey", event.DedupKey)
+		s.logger.WarnContext(ctx, "unknown action", "action", event.Action, "dedupKey", event.DedupKey)
+		setOutcome(ctx, domain.EventDropped, "unknown action")
 		return nil
 	}
 }
@@ -97,36 +217,110 @@ func (s *Service) handleTrigger(ctx context.Context, event *domain.InternalEvent
 	// Check if alert already exists (deduplication)
 	existingAlert, err := s.stateStore.GetAlert(ctx, event.DedupKey)
 	if err != nil {
-		s.logger.Error("failed to check existing alert", "error", err)
+		s.logger.ErrorContext(ctx, "failed to check existing alert", "error", err)
 		return err
 	}
 
 	if existingAlert != nil {
 		// Alert already exists - update it if needed
-		s.logger.Debug("alert already exists", "dedupKey", event.DedupKey, "status", existingAlert.Status)
+		s.logger.DebugContext(ctx, "alert already exists", "dedupKey", event.DedupKey, "status", existingAlert.Status)
+
+		// With global dedup, event managers share the alerts of their dedup keys
+		if s.globalDedup && existingAlert.EventManagerID != event.EventManagerID {
+			return s.handleSharedTrigger(ctx, event, existingAlert)
+		}
 
 		// If it was resolved and we get a new trigger, reactivate it
 		if existingAlert.Status == string(domain.AlertStatusResolved) {
+			em, err := s.eventManagerRepo.GetByID(ctx, event.EventManagerID)
+			if err != nil {
+				s.logger.ErrorContext(ctx, "failed to fetch event manager", "error", err)
+				return err
+			}
+			if em.IsDeleted() {
+				s.logger.WarnContext(ctx, "dropping event for deleted event manager", "event_manager_id", em.ID, "dedupKey", event.DedupKey)
+				setOutcome(ctx, domain.EventDropped, "event manager deleted")
+				return nil
+			}
 			return s.reactivateAlert(ctx, event, existingAlert)
 		}
 
-		// Already active, nothing to do
+		// Already active - only record the additional trigger
+		setOutcome(ctx, domain.EventDeduplicated, "")
+		metrics.DuplicateTriggers.WithLabelValues(event.EventManagerID).Inc()
+		if err := s.alertRepo.IncrementTriggerCount(ctx, event.DedupKey); err != nil {
+			s.logger.WarnContext(ctx, "failed to increment trigger count", "dedupKey", event.DedupKey, "error", err)
+		}
+		s.recordSampledEvents(ctx, event)
+		s.mergeTags(ctx, event, existingAlert.EventManagerID)
+		s.mergeLinks(ctx, event)
 		return nil
 	}
 
 	// Look up event manager to get grouping rule
 	em, err := s.eventManagerRepo.GetByID(ctx, event.EventManagerID)
 	if err != nil {
-		s.logger.Error("failed to fetch event manager", "error", err)
+		s.logger.ErrorContext(ctx, "failed to fetch event manager", "error", err)
 		return err
 	}
 
-	groupingRule, err := s.groupingRuleRepo.GetByID(ctx, em.GroupingRuleID)
+	// Events queued before the event manager was deleted must not open new alerts
+	if em.IsDeleted() {
+		s.logger.WarnContext(ctx, "dropping event for deleted event manager", "event_manager_id", em.ID, "dedupKey", event.DedupKey)
+		setOutcome(ctx, domain.EventDropped, "event manager deleted")
+		return nil
+	}
+
+	// Test alerts are independent parents, even during a storm, so no real
+	// alert is grouped under one
+	if event.IsTestAlert() {
+		return s.createParentAlert(ctx, event, nil, em)
+	}
+
+	// During a storm new alerts are grouped under the storm alert
+	if s.storms.active(em.ID) {
+		return s.createStormChild(ctx, event, em)
+	}
+
+	// Events of event managers that opt out of grouping are independent parents
+	if em.GroupingDisabled {
+		return s.createParentAlert(ctx, event, nil, em)
+	}
+
+	parentDedupKey, err := s.groupNewAlert(ctx, event, em)
 	if err != nil {
-		s.logger.Error("failed to fetch grouping rule", "error", err)
 		return err
 	}
 
+	// Compare with the candidate grouping rule, if any, once the alert exists
+	s.evaluateCandidate(ctx, event, em, parentDedupKey)
+	return nil
+}
+
+// groupNewAlert creates the alert of an event with the grouping rule selected
+// for it: a child if a parent exists in the rule's time window, otherwise a
+// new parent. It returns the parent of a child, and an empty string for a
+// parent.
+func (s *Service) groupNewAlert(ctx context.Context, event *domain.InternalEvent, em *domain.EventManager) (string, error) {
+	// Use the rule selected at ingestion; events queued by older versions
+	// don't carry one, so select it again.
+	groupingRuleID := event.GroupingRuleID
+	if groupingRuleID == "" {
+		groupingRuleID = em.GroupingRuleFor(&event.Event)
+	}
+
+	// Without any grouping rule (none on the event manager and no system
+	// default at ingestion) the event is not grouped either
+	if groupingRuleID == "" {
+		return "", s.createParentAlert(ctx, event, nil, em)
+	}
+
+	groupingRule, err := s.groupingRuleRepo.GetByID(ctx, groupingRuleID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to fetch grouping rule", "error", err)
+		return "", err
+	}
+
 	// Check for existing parent in the time window
 	parentState, err := s.stateStore.GetParent(
 		ctx,
@@ -135,20 +329,89 @@ func (s *Service) handleTrigger(ctx context.Context, event *domain.InternalEvent
 		event.GroupingValue,
 	)
 	if err != nil {
-		s.logger.Error("failed to check for parent", "error", err)
-		return err
+		s.logger.ErrorContext(ctx, "failed to check for parent", "error", err)
+		return "", err
 	}
 
 	if parentState != nil {
 		// Parent exists - create as child
-		return s.createChildAlert(ctx, event, parentState, groupingRule)
+		metrics.GroupingCacheLookups.WithLabelValues("hit").Inc()
+		return parentState.DedupKey, s.createChildAlert(ctx, event, parentState, groupingRule, em)
 	}
+	metrics.GroupingCacheLookups.WithLabelValues("miss").Inc()
 
 	// No parent exists - create as new parent
-	return s.createParentAlert(ctx, event, groupingRule, em)
+	return "", s.createParentAlert(ctx, event, groupingRule, em)
+}
+
+// handleSharedTrigger handles a trigger for an alert owned by another event
+// manager when global dedup is enabled. The event's event manager is
+// subscribed to the alert, and notified if it is an active parent, and the
+// event then counts as a re-trigger of the alert.
+func (s *Service) handleSharedTrigger(
+	ctx context.Context,
+	event *domain.InternalEvent,
+	existingState *store.AlertState,
+) error {
+	em, err := s.eventManagerRepo.GetByID(ctx, event.EventManagerID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to fetch event manager", "error", err)
+		return err
+	}
+	if em.IsDeleted() {
+		s.logger.WarnContext(ctx, "dropping event for deleted event manager", "event_manager_id", em.ID, "dedupKey", event.DedupKey)
+		setOutcome(ctx, domain.EventDropped, "event manager deleted")
+		return nil
+	}
+
+	alert, err := s.alertRepo.GetByDedupKey(ctx, event.DedupKey)
+	if errors.Is(err, domain.ErrAlertNotFound) && existingState.Status == string(domain.AlertStatusResolved) {
+		return s.forgetReleasedAlert(ctx, event)
+	}
+	if err != nil {
+		return err
+	}
+	if alert.Subscribe(em.ID, s.now()) {
+		if err := s.alertRepo.Update(ctx, alert); err != nil {
+			return err
+		}
+		s.logger.InfoContext(ctx, "subscribed event manager to alert",
+			"dedupKey", alert.DedupKey,
+			"eventManagerID", em.ID,
+			"ownerID", alert.EventManagerID,
+		)
+		if alert.IsParent() && alert.IsActive() {
+			s.notifier.NotifyNewParent(ctx, alert, em)
+		}
+	}
+
+	if existingState.Status == string(domain.AlertStatusResolved) {
+		return s.reactivateAlert(ctx, event, existingState)
+	}
+	setOutcome(ctx, domain.EventDeduplicated, "")
+	metrics.DuplicateTriggers.WithLabelValues(event.EventManagerID).Inc()
+	if err := s.alertRepo.IncrementTriggerCount(ctx, event.DedupKey); err != nil {
+		s.logger.WarnContext(ctx, "failed to increment trigger count", "dedupKey", event.DedupKey, "error", err)
+	}
+	s.recordSampledEvents(ctx, event)
+	s.mergeTags(ctx, event, existingState.EventManagerID)
+	s.mergeLinks(ctx, event)
+	return nil
+}
+
+// recordSampledEvents adds the triggers dropped by sampling before the event
+// to the trigger count of its alert.
+func (s *Service) recordSampledEvents(ctx context.Context, event *domain.InternalEvent) {
+	if event.SampledEvents == 0 {
+		return
+	}
+	if err := s.alertRepo.AddSampledEvents(ctx, event.DedupKey, event.SampledEvents); err != nil {
+		s.logger.WarnContext(ctx, "failed to record sampled events", "dedupKey", event.DedupKey, "count", event.SampledEvents, "error", err)
+	}
 }
 
-// createParentAlert creates a new parent alert.
+// createParentAlert creates a new parent alert. A nil rule creates an
+// ungrouped alert that is not registered as a parent for later events.
 func (s *Service) createParentAlert(
 	ctx context.Context,
 	event *domain.InternalEvent,
@@ -156,8 +419,12 @@ func (s *Service) createParentAlert(
 	em *domain.EventManager,
 ) error {
 	// Create the alert
-	alert := domain.NewParentAlert(&event.Event)
+	alert := domain.NewParentAlert(&event.Event, s.now())
 	alert.ID = uuid.New().String()
+	alert.OriginalDedupKey = event.OriginalDedupKey
+	alert.Runbook = domain.RunbookFor(em, rule, alert.Class)
+	alert.Origin = event.Origin
+	alert.Links = domain.MergeLinks(domain.LinksFor(em, alert), alert.Links)
 
 	// Save to state store
 	alertState := &store.AlertState{
@@ -165,43 +432,63 @@ func (s *Service) createParentAlert(
 		EventManagerID: alert.EventManagerID,
 		Type:           string(alert.Type),
 		Status:         string(alert.Status),
+		PartitionKey:   event.PartitionKey,
 	}
 	if err := s.stateStore.SetAlert(ctx, alertState); err != nil {
-		s.logger.Error("failed to save alert state", "error", err)
+		s.logger.ErrorContext(ctx, "failed to save alert state", "error", err)
 		return err
 	}
 
-	// Save parent lookup with TTL based on grouping rule time window
-	parentState := &store.ParentState{
-		DedupKey:   alert.DedupKey,
-		CreatedAt:  alert.CreatedAt,
-		ChildCount: 0,
-	}
-	if err := s.stateStore.SetParent(
-		ctx,
-		event.EventManagerID,
-		rule.GroupingKey,
-		event.GroupingValue,
-		parentState,
-		rule.TimeWindow(),
-	); err != nil {
-		s.logger.Error("failed to save parent state", "error", err)
-		return err
+	// Save parent lookup with TTL based on grouping rule time window.
+	// Ungrouped alerts (nil rule) never accept children.
+	if rule != nil {
+		parentState := &store.ParentState{
+			DedupKey:   alert.DedupKey,
+			CreatedAt:  alert.CreatedAt,
+			ChildCount: 0,
+		}
+		if err := s.stateStore.SetParent(
+			ctx,
+			event.EventManagerID,
+			rule.GroupingKey,
+			event.GroupingValue,
+			parentState,
+			rule.TimeWindow(),
+		); err != nil {
+			s.logger.ErrorContext(ctx, "failed to save parent state", "error", err)
+			return err
+		}
 	}
 
-	// Persist to database
+	// Persist to database. On failure, the cached state is rolled back so
+	// a retry creates the alert again instead of deduplicating against it.
 	if err := s.alertRepo.Create(ctx, alert); err != nil {
-		s.logger.Error("failed to persist alert", "error", err)
+		s.logger.ErrorContext(ctx, "failed to persist alert", "error", err)
+		if rule != nil {
+			s.rollback(s.stateStore.DeleteParent(ctx, event.EventManagerID, rule.GroupingKey, event.GroupingValue))
+		}
+		s.rollback(s.stateStore.DeleteAlert(ctx, alert.DedupKey))
 		return err
 	}
+	s.recordAlertCreation(event, alert)
+	s.activeAlertsChanged(ctx, alert, 1)
+	if rule != nil {
+		metrics.GroupingDecisions.WithLabelValues("parent").Inc()
+		s.recordGrouping(ctx, rule, alert)
+	} else {
+		metrics.GroupingDecisions.WithLabelValues("standalone").Inc()
+	}
 
-	s.logger.Info("created parent alert",
+	s.logger.InfoContext(ctx, "created parent alert",
 		"dedupKey", alert.DedupKey,
 		"eventManagerID", alert.EventManagerID,
 	)
 
 	// Send notification for new parent alert
 	s.notifier.NotifyNewParent(ctx, alert, em)
+	s.watchers.Notify(ctx, alert, em, domain.NotificationNewParent)
+	s.scheduleReminder(ctx, alert, em, alert.CreatedAt)
+	s.remediation.Trigger(ctx, alert, &event.Event, em)
 
 	return nil
 }
@@ -212,10 +499,15 @@ func (s *Service) createChildAlert(
 	event *domain.InternalEvent,
 	parentState *store.ParentState,
 	rule *domain.GroupingRule,
+	em *domain.EventManager,
 ) error {
 	// Create the child alert
-	alert := domain.NewChildAlert(&event.Event, parentState.DedupKey)
+	alert := domain.NewChildAlert(&event.Event, parentState.DedupKey, s.now())
 	alert.ID = uuid.New().String()
+	alert.OriginalDedupKey = event.OriginalDedupKey
+	alert.Runbook = domain.RunbookFor(em, rule, alert.Class)
+	alert.Origin = event.Origin
+	alert.Links = domain.MergeLinks(domain.LinksFor(em, alert), alert.Links)
 
 	// Save to state store
 	alertState := &store.AlertState{
@@ -224,90 +516,216 @@ func (s *Service) createChildAlert(
 		Type:           string(alert.Type),
 		Status:         string(alert.Status),
 		ParentDedupKey: alert.ParentDedupKey,
+		PartitionKey:   event.PartitionKey,
 	}
 	if err := s.stateStore.SetAlert(ctx, alertState); err != nil {
-		s.logger.Error("failed to save alert state", "error", err)
+		s.logger.ErrorContext(ctx, "failed to save alert state", "error", err)
 		return err
 	}
 
 	// Add to parent's children set
 	if err := s.stateStore.AddChild(ctx, parentState.DedupKey, alert.DedupKey); err != nil {
-		s.logger.Error("failed to add child to parent", "error", err)
+		s.logger.ErrorContext(ctx, "failed to add child to parent", "error", err)
 		return err
 	}
 
-	// Persist to database
+	// Persist to database, rolling back the cached state on failure
 	if err := s.alertRepo.Create(ctx, alert); err != nil {
-		s.logger.Error("failed to persist alert", "error", err)
+		s.logger.ErrorContext(ctx, "failed to persist alert", "error", err)
+		s.rollback(s.stateStore.RemoveChild(ctx, parentState.DedupKey, alert.DedupKey))
+		s.rollback(s.stateStore.DeleteAlert(ctx, alert.DedupKey))
 		return err
 	}
+	s.recordAlertCreation(event, alert)
+	s.activeAlertsChanged(ctx, alert, 1)
+	metrics.GroupingDecisions.WithLabelValues("child").Inc()
+	if rule != nil {
+		s.recordGrouping(ctx, rule, alert)
+	}
 
 	// Update parent's child count in database
 	parentAlert, err := s.alertRepo.GetByDedupKey(ctx, parentState.DedupKey)
 	if err == nil {
-		parentAlert.IncrementChildCount()
+		parentAlert.IncrementChildCount(s.now())
+		s.summarizeGroup(ctx, parentAlert, em)
 		if updateErr := s.alertRepo.Update(ctx, parentAlert); updateErr != nil {
-			s.logger.Warn("failed to update parent child count", "error", updateErr)
+			s.logger.WarnContext(ctx, "failed to update parent child count", "error", updateErr)
 		}
 	}
 
-	s.logger.Info("created child alert",
+	s.logger.InfoContext(ctx, "created child alert",
 		"dedupKey", alert.DedupKey,
 		"parentDedupKey", parentState.DedupKey,
 	)
 
+	// The storm alert is notified once for all of its children
+	if !domain.IsStormDedupKey(parentState.DedupKey) {
+		s.notifyLifecycle(ctx, alert, em, domain.NotificationChildAdded)
+	}
+	s.remediation.Trigger(ctx, alert, &event.Event, em)
+
 	return nil
 }
 
-// reactivateAlert reactivates a previously resolved alert.
+// recordGrouping counts an alert in the grouping statistics of its rule.
+func (s *Service) recordGrouping(ctx context.Context, rule *domain.GroupingRule, alert *domain.Alert) {
+	if err := s.stateStore.RecordGrouping(ctx, rule.ID, domain.ReportDay(alert.CreatedAt), alert.IsChild()); err != nil {
+		s.logger.WarnContext(ctx, "failed to record grouping", "dedupKey", alert.DedupKey, "groupingRuleID", rule.ID, "error", err)
+	}
+}
+
+// rollback logs the failure to roll back cached state after a failed write.
+func (s *Service) rollback(err error) {
+	if err != nil {
+		s.logger.Warn("failed to roll back alert state", "error", err)
+	}
+}
+
+// recordAlertCreation counts a new alert in the usage of its event manager,
+// and records its ingestion-to-creation latency in the latency histogram and
+// the SLO tracker.
+func (s *Service) recordAlertCreation(event *domain.InternalEvent, alert *domain.Alert) {
+	s.usage.RecordAlert(alert.EventManagerID)
+	if event.ReceivedAt.IsZero() {
+		return
+	}
+	latency := s.now().Sub(event.ReceivedAt)
+	metrics.AlertCreationLatency.WithLabelValues(string(alert.Type)).Observe(latency.Seconds())
+	s.sloTracker.RecordAlertCreation(latency)
+}
+
+// reactivateAlert reactivates a previously resolved alert, starting a new
+// episode of it. The database is updated before the state store, so a retry
+// after a partial failure finds the alert resolved in the state store and
+// reactivates it again; the alert is reactivated once, and the retry only
+// brings the state store in line.
 func (s *Service) reactivateAlert(
 	ctx context.Context,
 	event *domain.InternalEvent,
 	existingState *store.AlertState,
 ) error {
+	// Update database
+	alert, err := s.alertRepo.GetByDedupKey(ctx, event.DedupKey)
+	if errors.Is(err, domain.ErrAlertNotFound) {
+		return s.forgetReleasedAlert(ctx, event)
+	}
+	if err != nil {
+		return err
+	}
+
+	transition, err := alert.Reactivate(s.now())
+	reactivated := err == nil
+	if reactivated {
+		// The new episode was triggered by this event's sender
+		if event.Origin != nil {
+			alert.Origin = event.Origin
+		}
+		if len(event.Tags) > 0 {
+			replace, err := s.replacesTags(ctx, alert.EventManagerID)
+			if err != nil {
+				return err
+			}
+			alert.Tags = domain.MergeTags(alert.Tags, event.Tags, replace)
+		}
+		alert.Links = domain.MergeLinks(alert.Links, event.Links)
+		if err := s.alertRepo.Update(ctx, alert); err != nil {
+			return err
+		}
+		if alert.IsParent() {
+			s.refreshGroupSummary(ctx, alert.DedupKey, alert.EventManagerID)
+		} else {
+			s.refreshGroupSummary(ctx, alert.ParentDedupKey, alert.EventManagerID)
+		}
+	}
+
 	// Update state store
 	existingState.Status = string(domain.AlertStatusActive)
 	existingState.ResolveRequested = false
 	if err := s.stateStore.SetAlert(ctx, existingState); err != nil {
 		return err
 	}
+	setOutcome(ctx, domain.EventAlertReactivated, "")
+
+	if reactivated {
+		s.activeAlertsChanged(ctx, alert, 1)
+		metrics.AlertReactivations.WithLabelValues(event.EventManagerID).Inc()
+		s.logger.InfoContext(ctx, "reactivated alert", "dedupKey", event.DedupKey, "episode", alert.Episode)
+	} else {
+		s.logger.DebugContext(ctx, "alert already reactivated", "dedupKey", event.DedupKey, "episode", alert.Episode)
+	}
 
-	// Update database
-	alert, err := s.alertRepo.GetByDedupKey(ctx, event.DedupKey)
+	em, err := s.eventManagerRepo.GetByID(ctx, alert.EventManagerID)
 	if err != nil {
-		return err
+		s.logger.WarnContext(ctx, "failed to get event manager for notification", "error", err)
+		s.dispatchTransitions(ctx, alert, nil, transition)
+		return nil
+	}
+	s.dispatchTransitions(ctx, alert, em, transition)
+	if !reactivated {
+		// A retry notifies the reactivation its failed attempt didn't
+		s.notifyLifecycle(ctx, alert, em, domain.NotificationReactivated)
 	}
+	s.remediation.Trigger(ctx, alert, &event.Event, em)
+
+	// Reminders start over for the reactivated parent
+	if alert.IsParent() {
+		s.scheduleReminder(ctx, alert, em, alert.UpdatedAt)
+	}
+	return nil
+}
 
-	alert.Status = domain.AlertStatusActive
-	alert.ResolveRequested = false
-	alert.ResolvedAt = nil
-	alert.UpdatedAt = time.Now().UTC()
+// forgetReleasedAlert handles a trigger for a resolved alert the repository
+// no longer has, because the retention job detached its partition and
+// released its dedup key. The cached state of the alert is removed, and the
+// event then opens a new alert like the first event of the key.
+func (s *Service) forgetReleasedAlert(ctx context.Context, event *domain.InternalEvent) error {
+	s.logger.InfoContext(ctx, "resolved alert no longer stored, opening a new one", "dedupKey", event.DedupKey)
 
-	if err := s.alertRepo.Update(ctx, alert); err != nil {
+	children, err := s.stateStore.GetChildren(ctx, event.DedupKey)
+	if err != nil {
 		return err
 	}
-
-	s.logger.Info("reactivated alert", "dedupKey", event.DedupKey)
-	return nil
+	for _, child := range children {
+		if err := s.stateStore.RemoveChild(ctx, event.DedupKey, child); err != nil {
+			return err
+		}
+	}
+	if err := s.stateStore.DeleteReminder(ctx, event.DedupKey); err != nil {
+		return err
+	}
+	if err := s.stateStore.DeletePendingResolve(ctx, event.DedupKey); err != nil {
+		return err
+	}
+	if err := s.stateStore.DeleteAlert(ctx, event.DedupKey); err != nil {
+		return err
+	}
+	return s.handleTrigger(ctx, event)
 }
 
 // handleResolve processes a resolve action event.
 func (s *Service) handleResolve(ctx context.Context, event *domain.InternalEvent) error {
+	if event.SweepPendingResolve {
+		return s.sweepPendingResolve(ctx, event)
+	}
+
 	// Look up existing alert state
 	alertState, err := s.stateStore.GetAlert(ctx, event.DedupKey)
 	if err != nil {
-		s.logger.Error("failed to get alert state", "error", err)
+		s.logger.ErrorContext(ctx, "failed to get alert state", "error", err)
 		return err
 	}
 
 	if alertState == nil {
-		s.logger.Warn("resolve requested for unknown alert", "dedupKey", event.DedupKey)
+		metrics.UnknownResolves.WithLabelValues(event.EventManagerID).Inc()
+		s.logger.WarnContext(ctx, "resolve requested for unknown alert", "dedupKey", event.DedupKey)
+		setOutcome(ctx, domain.EventDropped, "no alert with the dedup key")
 		return nil
 	}
 
 	// Already resolved - nothing to do
 	if alertState.Status == string(domain.AlertStatusResolved) {
-		s.logger.Debug("alert already resolved", "dedupKey", event.DedupKey)
+		s.logger.DebugContext(ctx, "alert already resolved", "dedupKey", event.DedupKey)
+		setOutcome(ctx, domain.EventDropped, "alert already resolved")
 		return nil
 	}
 
@@ -315,9 +733,38 @@ func (s *Service) handleResolve(ctx context.Context, event *domain.InternalEvent
 		return s.resolveChildAlert(ctx, event, alertState)
 	}
 
+	// Resolves requested through the API resolve the group at once
+	if event.Resolution != nil {
+		return s.resolveRequestedGroup(ctx, event)
+	}
+
 	return s.resolveParentAlert(ctx, event, alertState)
 }
 
+// resolveRequestedGroup resolves a parent alert with its active children,
+// for when an operator knows the whole group is fixed. The resolve was
+// requested through the API and queued behind the earlier events of the
+// alert.
+func (s *Service) resolveRequestedGroup(ctx context.Context, event *domain.InternalEvent) error {
+	parent, err := s.alertRepo.GetByDedupKey(ctx, event.DedupKey)
+	if errors.Is(err, domain.ErrAlertNotFound) {
+		s.logger.WarnContext(ctx, "resolve requested for missing alert", "dedupKey", event.DedupKey)
+		setOutcome(ctx, domain.EventDropped, "no alert with the dedup key")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if parent.IsResolved() {
+		s.logger.DebugContext(ctx, "alert already resolved", "dedupKey", event.DedupKey)
+		setOutcome(ctx, domain.EventDropped, "alert already resolved")
+		return nil
+	}
+
+	_, err = s.resolveGroup(ctx, parent, *event.Resolution)
+	return err
+}
+
 // resolveChildAlert handles resolution of a child alert.
 func (s *Service) resolveChildAlert(
 	ctx context.Context,
@@ -335,12 +782,27 @@ func (s *Service) resolveChildAlert(
 	if err != nil {
 		return err
 	}
-	alert.Resolve()
+	transition, err := alert.Resolve(domain.EventResolution(&event.Event), s.now())
+	if errors.Is(err, domain.ErrAlertAlreadyResolved) {
+		// The state store lagged behind the database; the parent may still
+		// wait for this child
+		s.logger.DebugContext(ctx, "child alert already resolved", "dedupKey", event.DedupKey)
+		if alertState.ParentDedupKey != "" {
+			return s.checkParentResolution(ctx, alertState.ParentDedupKey)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
 	if err := s.alertRepo.Update(ctx, alert); err != nil {
 		return err
 	}
+	s.activeAlertsChanged(ctx, alert, -1)
+	s.dispatchTransitions(ctx, alert, nil, transition)
 
-	s.logger.Info("resolved child alert", "dedupKey", event.DedupKey)
+	s.logger.InfoContext(ctx, "resolved child alert", "dedupKey", event.DedupKey)
+	s.refreshGroupSummary(ctx, alert.ParentDedupKey, alert.EventManagerID)
 
 	// Check if parent has pending resolve and all children are now resolved
 	if alertState.ParentDedupKey != "" {
@@ -356,10 +818,33 @@ func (s *Service) resolveParentAlert(
 	event *domain.InternalEvent,
 	alertState *store.AlertState,
 ) error {
+	resolution := domain.EventResolution(&event.Event)
+
+	policy, err := s.resolutionPolicy(ctx, alertState.EventManagerID)
+	if err != nil {
+		return err
+	}
+	switch policy {
+	case domain.ResolveWithAny:
+		// Children stay active on their own
+		return s.completeParentResolution(ctx, event.DedupKey, alertState, &resolution)
+	case domain.AutoResolveChildren:
+		parent, err := s.alertRepo.GetByDedupKey(ctx, event.DedupKey)
+		if err != nil {
+			return err
+		}
+		if parent.IsResolved() {
+			s.logger.DebugContext(ctx, "alert already resolved", "dedupKey", event.DedupKey)
+			return nil
+		}
+		_, err = s.resolveGroup(ctx, parent, resolution)
+		return err
+	}
+
 	// Check if there are any active children
 	activeChildren, err := s.alertRepo.CountActiveChildren(ctx, event.DedupKey)
 	if err != nil {
-		s.logger.Error("failed to count active children", "error", err)
+		s.logger.ErrorContext(ctx, "failed to count active children", "error", err)
 		return err
 	}
 
@@ -372,7 +857,7 @@ func (s *Service) resolveParentAlert(
 
 		// Set pending resolve
 		pending := &store.PendingResolve{
-			RequestedAt:       time.Now().UTC(),
+			RequestedAt:       s.now(),
 			RemainingChildren: activeChildren,
 		}
 		if err := s.stateStore.SetPendingResolve(ctx, event.DedupKey, pending); err != nil {
@@ -384,12 +869,20 @@ func (s *Service) resolveParentAlert(
 		if err != nil {
 			return err
 		}
-		alert.MarkResolveRequested()
+		transition, err := alert.MarkResolveRequested(domain.EventResolution(&event.Event), s.now())
+		if errors.Is(err, domain.ErrAlertAlreadyResolved) {
+			s.logger.DebugContext(ctx, "alert already resolved", "dedupKey", event.DedupKey)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
 		if err := s.alertRepo.Update(ctx, alert); err != nil {
 			return err
 		}
+		s.dispatchTransitions(ctx, alert, nil, transition)
 
-		s.logger.Info("parent resolve requested, waiting for children",
+		s.logger.InfoContext(ctx, "parent resolve requested, waiting for children",
 			"dedupKey", event.DedupKey,
 			"activeChildren", activeChildren,
 		)
@@ -397,7 +890,102 @@ func (s *Service) resolveParentAlert(
 	}
 
 	// No active children - can resolve immediately
-	return s.completeParentResolution(ctx, event.DedupKey, alertState)
+	return s.completeParentResolution(ctx, event.DedupKey, alertState, &resolution)
+}
+
+// mergeTags merges the tags of a trigger onto its existing alert, as decided
+// by the tag policy of the alert's event manager.
+func (s *Service) mergeTags(ctx context.Context, event *domain.InternalEvent, eventManagerID string) {
+	if len(event.Tags) == 0 {
+		return
+	}
+	replace, err := s.replacesTags(ctx, eventManagerID)
+	if err == nil {
+		err = s.alertRepo.MergeTags(ctx, event.DedupKey, event.Tags, replace)
+	}
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to merge tags", "dedupKey", event.DedupKey, "error", err)
+	}
+}
+
+// mergeLinks adds the links of a trigger to its existing alert.
+func (s *Service) mergeLinks(ctx context.Context, event *domain.InternalEvent) {
+	if len(event.Links) == 0 {
+		return
+	}
+	if err := s.alertRepo.UpdateLinks(ctx, event.DedupKey, event.Links, nil); err != nil {
+		s.logger.WarnContext(ctx, "failed to merge links", "dedupKey", event.DedupKey, "error", err)
+	}
+}
+
+// replacesTags reports whether the tag policy of an event manager replaces
+// the values of existing tags. Event managers without a policy, or that no
+// longer exist, only add tags.
+func (s *Service) replacesTags(ctx context.Context, eventManagerID string) (bool, error) {
+	em, err := s.eventManagerRepo.GetByID(ctx, eventManagerID)
+	if errors.Is(err, domain.ErrEventManagerNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return em.TagPolicy.Replaces(), nil
+}
+
+// summarizeGroup sets the summary a parent alert displays for its group, as
+// decided by the parent summary policy of its event manager, and reports
+// whether it changed. Parents keep the summary of their first event unless
+// a policy was, or is, configured.
+func (s *Service) summarizeGroup(ctx context.Context, parent *domain.Alert, em *domain.EventManager) bool {
+	if !em.ParentSummary.IsEnabled() && parent.EventSummary == "" {
+		return false
+	}
+	children, err := s.alertRepo.GetChildrenByParent(ctx, parent.DedupKey)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to get children for group summary", "dedupKey", parent.DedupKey, "error", err)
+		return false
+	}
+	return parent.SetGroupSummary(em.ParentSummary.Summary(parent, children))
+}
+
+// refreshGroupSummary recomputes the summary of a parent alert after one of
+// its children changed. Failures are logged; the summary is refreshed again
+// as the group changes.
+func (s *Service) refreshGroupSummary(ctx context.Context, parentDedupKey, eventManagerID string) {
+	if parentDedupKey == "" {
+		return
+	}
+	em, err := s.eventManagerRepo.GetByID(ctx, eventManagerID)
+	if errors.Is(err, domain.ErrEventManagerNotFound) {
+		return
+	}
+	var parent *domain.Alert
+	if err == nil {
+		parent, err = s.alertRepo.GetByDedupKey(ctx, parentDedupKey)
+	}
+	if err == nil && s.summarizeGroup(ctx, parent, em) {
+		err = s.alertRepo.Update(ctx, parent)
+	}
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to refresh group summary", "dedupKey", parentDedupKey, "error", err)
+	}
+}
+
+// resolutionPolicy returns the parent resolution policy of an event manager.
+// Event managers without a policy, or that no longer exist, wait for all
+// children.
+func (s *Service) resolutionPolicy(ctx context.Context, eventManagerID string) (domain.ResolutionPolicy, error) {
+	em, err := s.eventManagerRepo.GetByID(ctx, eventManagerID)
+	if errors.Is(err, domain.ErrEventManagerNotFound) {
+		return domain.ResolveWithAll, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if em.ResolutionPolicy == "" {
+		return domain.ResolveWithAll, nil
+	}
+	return em.ResolutionPolicy, nil
 }
 
 // checkParentResolution checks if a parent can now be resolved after a child resolution.
@@ -435,14 +1023,17 @@ func (s *Service) checkParentResolution(ctx context.Context, parentDedupKey stri
 		return errors.New("parent alert state not found")
 	}
 
-	return s.completeParentResolution(ctx, parentDedupKey, parentState)
+	// The parent is resolved as recorded with its resolve request
+	return s.completeParentResolution(ctx, parentDedupKey, parentState, nil)
 }
 
 // completeParentResolution finalizes the resolution of a parent alert.
+// A nil resolution uses the one recorded when the resolve was requested.
 func (s *Service) completeParentResolution(
 	ctx context.Context,
 	dedupKey string,
 	alertState *store.AlertState,
+	resolution *domain.Resolution,
 ) error {
 	// Update state store
 	alertState.Status = string(domain.AlertStatusResolved)
@@ -453,7 +1044,7 @@ func (s *Service) completeParentResolution(
 
 	// Clean up pending resolve
 	if err := s.stateStore.DeletePendingResolve(ctx, dedupKey); err != nil {
-		s.logger.Warn("failed to delete pending resolve", "error", err)
+		s.logger.WarnContext(ctx, "failed to delete pending resolve", "error", err)
 	}
 
 	// Update database
@@ -461,28 +1052,330 @@ func (s *Service) completeParentResolution(
 	if err != nil {
 		return err
 	}
-	alert.Resolve()
+	if resolution == nil {
+		resolution = alert.Resolution
+	}
+	if resolution == nil {
+		resolution = &domain.Resolution{ResolvedBy: domain.ResolvedByEvent}
+	}
+	transition, err := alert.Resolve(*resolution, s.now())
+	if errors.Is(err, domain.ErrAlertAlreadyResolved) {
+		s.logger.DebugContext(ctx, "parent alert already resolved", "dedupKey", dedupKey)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
 	if err := s.alertRepo.Update(ctx, alert); err != nil {
 		return err
 	}
+	s.activeAlertsChanged(ctx, alert, -1)
+
+	metrics.AlertGroupSize.Observe(float64(alert.ChildCount))
+
+	s.logger.InfoContext(ctx, "resolved parent alert", "dedupKey", dedupKey)
 
-	s.logger.Info("resolved parent alert", "dedupKey", dedupKey)
+	// Subscribers are notified even if the owner cannot be
+	defer s.notifySubscribersResolved(ctx, alert)
 
 	// Get event manager for notification
 	em, err := s.eventManagerRepo.GetByID(ctx, alertState.EventManagerID)
 	if err != nil {
-		s.logger.Warn("failed to get event manager for notification", "error", err)
-		return nil // Don't fail the resolution just because notification setup failed
+		// Don't fail the resolution just because notification setup failed
+		s.logger.WarnContext(ctx, "failed to get event manager for notification", "error", err)
+		em = nil
 	}
 
 	// Send notification for resolved parent alert
-	s.notifier.NotifyResolved(ctx, alert, em)
+	s.dispatchTransitions(ctx, alert, em, transition)
 
 	return nil
 }
 
+// notifyLifecycle sends an optional lifecycle notification of an alert to
+// its event manager, if the event manager opted in to the kind. Watchers are
+// notified regardless.
+func (s *Service) notifyLifecycle(ctx context.Context, alert *domain.Alert, em *domain.EventManager, kind domain.NotificationKind) {
+	s.watchers.Notify(ctx, alert, em, kind)
+	if em.IsDeleted() || !em.NotificationConfig.Sends(kind) {
+		return
+	}
+	switch kind {
+	case domain.NotificationChildAdded:
+		s.notifier.NotifyChildAdded(ctx, alert, em)
+	case domain.NotificationReactivated:
+		s.notifier.NotifyReactivated(ctx, alert, em)
+	case domain.NotificationAcknowledged:
+		s.notifier.NotifyAcknowledged(ctx, alert, em)
+	case domain.NotificationEscalated:
+		s.notifier.NotifyEscalated(ctx, alert, em)
+	}
+}
+
+// Acknowledge marks an active alert as acknowledged by a responder. The
+// first acknowledgement is notified to the alert's event manager if it opted
+// in; acknowledging again changes nothing.
+func (s *Service) Acknowledge(ctx context.Context, dedupKey string) (*domain.Alert, error) {
+	alert, err := s.alertRepo.GetByDedupKey(ctx, dedupKey)
+	if err != nil {
+		return nil, err
+	}
+	transition, err := alert.Acknowledge(s.now())
+	if err != nil || transition == nil {
+		return alert, err
+	}
+	if err := s.alertRepo.Update(ctx, alert); err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "acknowledged alert", "dedupKey", dedupKey)
+
+	em, err := s.eventManagerRepo.GetByID(ctx, alert.EventManagerID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to get event manager for notification", "error", err)
+		em = nil
+	}
+	s.dispatchTransitions(ctx, alert, em, transition)
+	return alert, nil
+}
+
+// patchAttempts bounds how often a patch without updated_at is applied again
+// when the processor updates the alert concurrently.
+const patchAttempts = 3
+
+// PatchAlert applies a patch to an alert. A patch with updated_at fails with
+// domain.ErrAlertModified if the alert was updated since; one without is
+// applied again to the latest alert.
+func (s *Service) PatchAlert(ctx context.Context, dedupKey string, patch *domain.AlertPatch) (*domain.Alert, error) {
+	for attempt := 1; ; attempt++ {
+		alert, err := s.alertRepo.GetByDedupKey(ctx, dedupKey)
+		if err != nil {
+			return nil, err
+		}
+		updatedAt := alert.UpdatedAt
+		previous := *alert
+
+		// PostgreSQL stores microseconds; truncating keeps the updated_at
+		// returned equal to the one stored, for the next patch
+		if err := patch.Apply(alert, s.now().Truncate(time.Microsecond)); err != nil {
+			return nil, err
+		}
+		err = s.alertRepo.UpdateIfUnmodified(ctx, alert, updatedAt)
+		if errors.Is(err, domain.ErrAlertModified) && patch.UpdatedAt == nil && attempt < patchAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// The active alert gauges count alerts by severity
+		if alert.IsActive() && alert.Severity != previous.Severity {
+			s.activeAlertsChanged(ctx, &previous, -1)
+			s.activeAlertsChanged(ctx, alert, 1)
+		}
+		s.logger.InfoContext(ctx, "patched alert", "dedupKey", dedupKey)
+		return alert, nil
+	}
+}
+
+// notifySubscribersResolved sends the resolved notification of a parent alert
+// to the event managers subscribed to it that are not deleted.
+func (s *Service) notifySubscribersResolved(ctx context.Context, alert *domain.Alert) {
+	for _, id := range alert.SubscriberIDs {
+		em, err := s.eventManagerRepo.GetByID(ctx, id)
+		if err != nil {
+			s.logger.WarnContext(ctx, "failed to get subscribed event manager for notification", "eventManagerID", id, "error", err)
+			continue
+		}
+		if em.IsDeleted() {
+			continue
+		}
+		s.notifier.NotifyResolved(ctx, alert, em)
+	}
+}
+
+// resolveGroup resolves a parent alert and all its active children. The
+// alerts are updated in one write, then their cached state, and the resolved
+// notification is sent. It returns the number of children resolved.
+func (s *Service) resolveGroup(ctx context.Context, parent *domain.Alert, resolution domain.Resolution) (int, error) {
+	children, err := s.alertRepo.GetChildrenByParent(ctx, parent.DedupKey)
+	if err != nil {
+		return 0, err
+	}
+
+	var resolved []*domain.Alert
+	var transitions []*domain.AlertTransition
+	for _, child := range children {
+		if child.IsActive() {
+			transition, err := child.Resolve(resolution, s.now())
+			if err != nil {
+				return 0, err
+			}
+			resolved = append(resolved, child)
+			transitions = append(transitions, transition)
+		}
+	}
+	transition, err := parent.Resolve(resolution, s.now())
+	if err != nil {
+		return 0, err
+	}
+	resolved = append(resolved, parent)
+	transitions = append(transitions, transition)
+
+	if err := s.alertRepo.UpdateAll(ctx, resolved); err != nil {
+		return 0, err
+	}
+
+	for _, alert := range resolved {
+		s.activeAlertsChanged(ctx, alert, -1)
+		alertState, err := s.stateStore.GetAlert(ctx, alert.DedupKey)
+		if err != nil {
+			return 0, err
+		}
+		if alertState == nil {
+			continue
+		}
+		alertState.Status = string(domain.AlertStatusResolved)
+		alertState.ResolveRequested = false
+		if err := s.stateStore.SetAlert(ctx, alertState); err != nil {
+			return 0, err
+		}
+	}
+	if err := s.stateStore.DeletePendingResolve(ctx, parent.DedupKey); err != nil {
+		s.logger.WarnContext(ctx, "failed to delete pending resolve", "error", err)
+	}
+
+	metrics.AlertGroupSize.Observe(float64(parent.ChildCount))
+
+	s.logger.InfoContext(ctx, "resolved parent alert with its children", "dedupKey", parent.DedupKey, "children", len(resolved)-1)
+
+	em, err := s.eventManagerRepo.GetByID(ctx, parent.EventManagerID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to get event manager for notification", "error", err)
+		em = nil
+	}
+	for i, alert := range resolved {
+		s.dispatchTransitions(ctx, alert, em, transitions[i])
+	}
+	s.notifySubscribersResolved(ctx, parent)
+
+	return len(resolved) - 1, nil
+}
+
+// ResolveEventManagerAlerts resolves every open alert of an event manager,
+// children before parents so no parent is left waiting on its children.
+// It is used when an event manager is deleted and returns the number of
+// alerts resolved.
+func (s *Service) ResolveEventManagerAlerts(ctx context.Context, eventManagerID string) (int, error) {
+	alerts, err := s.alertRepo.List(ctx, domain.AlertFilter{
+		EventManagerID: eventManagerID,
+		Status:         domain.AlertStatusActive,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	em, err := s.eventManagerRepo.GetByID(ctx, eventManagerID)
+	if err != nil {
+		return 0, err
+	}
+
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].IsChild() && !alerts[j].IsChild()
+	})
+
+	resolved := 0
+	for _, alert := range alerts {
+		alertState, err := s.stateStore.GetAlert(ctx, alert.DedupKey)
+		if err != nil {
+			return resolved, err
+		}
+		if alertState != nil {
+			alertState.Status = string(domain.AlertStatusResolved)
+			alertState.ResolveRequested = false
+			if err := s.stateStore.SetAlert(ctx, alertState); err != nil {
+				return resolved, err
+			}
+		}
+		if alert.IsParent() {
+			if err := s.stateStore.DeletePendingResolve(ctx, alert.DedupKey); err != nil {
+				s.logger.WarnContext(ctx, "failed to delete pending resolve", "error", err)
+			}
+		}
+
+		transition, err := alert.Resolve(domain.Resolution{ResolvedBy: domain.ResolvedByAPI, Reason: "event manager deleted"}, s.now())
+		if err != nil {
+			return resolved, err
+		}
+		if err := s.alertRepo.Update(ctx, alert); err != nil {
+			return resolved, err
+		}
+		s.activeAlertsChanged(ctx, alert, -1)
+		s.dispatchTransitions(ctx, alert, em, transition)
+		resolved++
+
+		if alert.IsParent() {
+			metrics.AlertGroupSize.Observe(float64(alert.ChildCount))
+			s.notifySubscribersResolved(ctx, alert)
+		}
+	}
+
+	s.logger.InfoContext(ctx, "resolved alerts of deleted event manager", "event_manager_id", eventManagerID, "count", resolved)
+	return resolved, nil
+}
+
+// PurgeEventManagerAlerts permanently removes every alert of an event manager
+// from the state store and the alert repository, and returns the number of
+// alerts removed.
+func (s *Service) PurgeEventManagerAlerts(ctx context.Context, eventManagerID string) (int, error) {
+	alerts, err := s.alertRepo.List(ctx, domain.AlertFilter{EventManagerID: eventManagerID})
+	if err != nil {
+		return 0, err
+	}
+
+	// Clear cached state first so a reused dedup key starts from scratch
+	for _, alert := range alerts {
+		if err := s.stateStore.DeleteAlert(ctx, alert.DedupKey); err != nil {
+			return 0, err
+		}
+		if alert.IsParent() {
+			if err := s.stateStore.DeletePendingResolve(ctx, alert.DedupKey); err != nil {
+				return 0, err
+			}
+			if err := s.stateStore.DeleteReminder(ctx, alert.DedupKey); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return s.alertRepo.PurgeByEventManager(ctx, eventManagerID)
+}
+
+// DeleteAlert permanently removes an alert from the state store and the
+// alert repository. Its children, if any, are left as they are.
+func (s *Service) DeleteAlert(ctx context.Context, alert *domain.Alert) error {
+	if err := s.stateStore.DeleteAlert(ctx, alert.DedupKey); err != nil {
+		return err
+	}
+	if alert.IsParent() {
+		if err := s.stateStore.DeletePendingResolve(ctx, alert.DedupKey); err != nil {
+			return err
+		}
+		if err := s.stateStore.DeleteReminder(ctx, alert.DedupKey); err != nil {
+			return err
+		}
+	}
+
+	return s.alertRepo.Delete(ctx, alert.DedupKey)
+}
+
 // Stop gracefully stops the processor service.
 func (s *Service) Stop() error {
 	s.logger.Info("stopping processor service")
-	return s.consumer.Close()
+	err := s.consumer.Close()
+
+	// Remediation actions still running are bounded by their timeout, and
+	// watch notifications by the timeout of their plugin
+	s.remediation.Wait()
+	s.watchers.Wait()
+	return err
 }
@@ -3,25 +3,44 @@ package processor
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
 	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
 	"argus-go/internal/notification"
 	"argus-go/internal/queue"
 	"argus-go/internal/queue/memory"
+	"argus-go/internal/silence"
 	"argus-go/internal/store"
 	storemem "argus-go/internal/store/memory"
+	"argus-go/internal/testgen"
+	"argus-go/internal/usage"
 )
 
 // testSetup creates all dependencies needed for processor tests.
 func testSetup() (*Service, *memory.Queue, *storemem.StateStore, *storemem.AlertRepository, *storemem.EventManagerRepository, *storemem.GroupingRuleRepository) {
+	return testSetupWithClock(clock.Real{})
+}
+
+// testSetupWithClock creates the dependencies of processor tests whose
+// processor, state store and alert repository tell time by clk.
+func testSetupWithClock(clk clock.Clock) (*Service, *memory.Queue, *storemem.StateStore, *storemem.AlertRepository, *storemem.EventManagerRepository, *storemem.GroupingRuleRepository) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	msgQueue := memory.NewQueue(100)
-	stateStore := storemem.NewStateStore()
-	alertRepo := storemem.NewAlertRepository()
+	stateStore := storemem.NewStateStoreWithClock(clk)
+	alertRepo := storemem.NewAlertRepositoryWithClock(clk)
 	eventManagerRepo := storemem.NewEventManagerRepository()
 	groupingRuleRepo := storemem.NewGroupingRuleRepository()
 	notifier := notification.NewStubNotifier(logger)
@@ -33,6 +52,15 @@ func testSetup() (*Service, *memory.Queue, *storemem.StateStore, *storemem.Alert
 		eventManagerRepo,
 		groupingRuleRepo,
 		notifier,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		config.DedupConfig{},
+		config.ProcessorConfig{},
+		clk,
 		logger,
 	)
 
@@ -111,7 +139,10 @@ func TestProcessor_HandleTrigger_CreatesParentAlert(t *testing.T) {
 	// Verify state store was updated
 	alertState, _ := stateStore.GetAlert(ctx, "alert-1")
 	if alertState == nil {
-		t.Error("Alert state should be saved")
+		t.Fatal("Alert state should be saved")
+	}
+	if alertState.PartitionKey != event.PartitionKey {
+		t.Errorf("Expected partition key %s, got %s", event.PartitionKey, alertState.PartitionKey)
 	}
 }
 
@@ -185,6 +216,141 @@ func TestProcessor_HandleTrigger_CreatesChildAlert(t *testing.T) {
 	if len(children) != 1 {
 		t.Errorf("Parent should have 1 child, got %d", len(children))
 	}
+
+	// Verify the child counts in the grouping statistics of the rule
+	stats, _ := stateStore.GetGroupingStats(ctx, "rule-1", []string{domain.ReportDay(childAlert.CreatedAt)})
+	if len(stats) != 1 || stats[0].ChildrenGrouped != 1 || stats[0].ParentsCreated != 0 {
+		t.Errorf("grouping stats = %+v, want one child grouped", stats)
+	}
+}
+
+func TestProcessor_HandleTrigger_GroupingDisabled(t *testing.T) {
+	service, _, stateStore, alertRepo, emRepo, _ := testSetup()
+	ctx := context.Background()
+
+	_ = emRepo.Create(ctx, &domain.EventManager{
+		ID:               "em-1",
+		Name:             "Ungrouped EM",
+		GroupingDisabled: true,
+		CreatedAt:        time.Now(),
+	})
+
+	// Two events that would share a group are both created as parents
+	for _, dedupKey := range []string{"alert-1", "alert-2"} {
+		event := &domain.InternalEvent{
+			Event: domain.Event{
+				EventManagerID: "em-1",
+				Summary:        "Test alert",
+				Severity:       domain.SeverityHigh,
+				Action:         domain.ActionTrigger,
+				Class:          "database",
+				DedupKey:       dedupKey,
+			},
+			PartitionKey: "partition-1",
+			ReceivedAt:   time.Now(),
+		}
+		payload, _ := json.Marshal(event)
+		if err := service.handleMessage(ctx, &queue.Message{Key: []byte(event.PartitionKey), Value: payload}); err != nil {
+			t.Fatalf("handleMessage(%s) error: %v", dedupKey, err)
+		}
+
+		alert, err := alertRepo.GetByDedupKey(ctx, dedupKey)
+		if err != nil {
+			t.Fatalf("GetByDedupKey(%s) error: %v", dedupKey, err)
+		}
+		if alert.Type != domain.AlertTypeParent {
+			t.Errorf("%s type = %v, want parent", dedupKey, alert.Type)
+		}
+	}
+
+	// Ungrouped alerts are never registered as open parents
+	parent, _ := stateStore.GetParent(ctx, "em-1", "class", "database")
+	if parent != nil {
+		t.Errorf("GetParent = %+v, want nil", parent)
+	}
+}
+
+func TestProcessor_HandleTrigger_TestAlertNotGrouped(t *testing.T) {
+	service, _, stateStore, alertRepo, emRepo, grRepo := testSetup()
+	ctx := context.Background()
+	setupTestData(ctx, emRepo, grRepo)
+
+	send := func(event domain.Event) *domain.Alert {
+		t.Helper()
+		internal := &domain.InternalEvent{Event: event, PartitionKey: "partition-1", GroupingValue: "database", ReceivedAt: time.Now()}
+		payload, _ := json.Marshal(internal)
+		if err := service.handleMessage(ctx, &queue.Message{Key: []byte(internal.PartitionKey), Value: payload}); err != nil {
+			t.Fatalf("handleMessage(%s) error: %v", event.DedupKey, err)
+		}
+		alert, err := alertRepo.GetByDedupKey(ctx, event.DedupKey)
+		if err != nil {
+			t.Fatalf("GetByDedupKey(%s) error: %v", event.DedupKey, err)
+		}
+		return alert
+	}
+	testAlert := &domain.TestAlert{EventManagerID: "em-1", DedupKey: "argus-test-1", ExpiresAt: time.Now().Add(time.Hour)}
+	disk := domain.Event{
+		EventManagerID: "em-1",
+		Summary:        "Disk full",
+		Severity:       domain.SeverityHigh,
+		Action:         domain.ActionTrigger,
+		Class:          "database",
+		DedupKey:       "alert-1",
+	}
+
+	// A test alert in the group of a real alert is a parent of its own, and
+	// does not open the group
+	if alert := send(*testAlert.Event(domain.SeverityHigh)); alert.Type != domain.AlertTypeParent {
+		t.Errorf("test alert type = %v, want parent", alert.Type)
+	}
+	if parent, _ := stateStore.GetParent(ctx, "em-1", "class", "database"); parent != nil {
+		t.Errorf("GetParent after the test alert = %+v, want nil", parent)
+	}
+
+	// So the real alert never becomes its child
+	if alert := send(disk); alert.Type != domain.AlertTypeParent || alert.ParentDedupKey != "" {
+		t.Errorf("real alert = %v under %q, want a parent", alert.Type, alert.ParentDedupKey)
+	}
+
+	// Nor does a later test alert join the real alert's group
+	testAlert.DedupKey = "argus-test-2"
+	if alert := send(*testAlert.Event(domain.SeverityHigh)); alert.Type != domain.AlertTypeParent {
+		t.Errorf("second test alert type = %v, want parent", alert.Type)
+	}
+	if parent, _ := alertRepo.GetByDedupKey(ctx, "alert-1"); parent.ChildCount != 0 {
+		t.Errorf("real alert child count = %d, want 0", parent.ChildCount)
+	}
+}
+
+func TestProcessor_HandleResolve_TestAlertExpired(t *testing.T) {
+	service, _, _, alertRepo, emRepo, grRepo := testSetup()
+	ctx := context.Background()
+	setupTestData(ctx, emRepo, grRepo)
+
+	testAlert := &domain.TestAlert{EventManagerID: "em-1", DedupKey: "argus-test-1", ExpiresAt: time.Now().Add(time.Minute)}
+	trigger := &domain.InternalEvent{Event: *testAlert.Event(domain.SeverityHigh), PartitionKey: "partition-1", ReceivedAt: time.Now()}
+	payload, _ := json.Marshal(trigger)
+	if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+		t.Fatalf("handleMessage(trigger) error: %v", err)
+	}
+	alert, err := alertRepo.GetByDedupKey(ctx, testAlert.DedupKey)
+	if err != nil {
+		t.Fatalf("GetByDedupKey error: %v", err)
+	}
+
+	resolve := &domain.InternalEvent{Event: *domain.TestAlertExpiredEvent(alert), PartitionKey: "partition-1", ReceivedAt: time.Now()}
+	payload, _ = json.Marshal(resolve)
+	if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+		t.Fatalf("handleMessage(resolve) error: %v", err)
+	}
+
+	alert, err = alertRepo.GetByDedupKey(ctx, testAlert.DedupKey)
+	if err != nil {
+		t.Fatalf("GetByDedupKey error: %v", err)
+	}
+	if alert.Resolution == nil || alert.Resolution.ResolvedBy != domain.ResolvedByAutoTTL {
+		t.Errorf("Resolution = %+v, want resolved by %s", alert.Resolution, domain.ResolvedByAutoTTL)
+	}
 }
 
 func TestProcessor_HandleResolve_ResolvesChildAlert(t *testing.T) {
@@ -287,6 +453,8 @@ func TestProcessor_HandleResolve_ParentWaitsForChildren(t *testing.T) {
 			EventManagerID: "em-1",
 			Action:         domain.ActionResolve,
 			DedupKey:       "parent-alert",
+			Summary:        "replica caught up",
+			Source:         "prometheus",
 		},
 		ReceivedAt: time.Now(),
 	}
@@ -313,6 +481,385 @@ func TestProcessor_HandleResolve_ParentWaitsForChildren(t *testing.T) {
 	if pending == nil {
 		t.Error("Pending resolve should be set")
 	}
+	// Resolving the last child resolves the parent as requested by its own event
+	_ = stateStore.SetAlert(ctx, &store.AlertState{
+		DedupKey:       "child-alert",
+		EventManagerID: "em-1",
+		Type:           string(domain.AlertTypeChild),
+		Status:         string(domain.AlertStatusActive),
+		ParentDedupKey: "parent-alert",
+	})
+	childEvent := &domain.InternalEvent{
+		Event: domain.Event{
+			EventManagerID: "em-1",
+			Action:         domain.ActionResolve,
+			DedupKey:       "child-alert",
+			Source:         "grafana",
+		},
+		ReceivedAt: time.Now(),
+	}
+	payload, _ = json.Marshal(childEvent)
+	if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+		t.Fatalf("handleMessage error: %v", err)
+	}
+
+	parent, _ = alertRepo.GetByDedupKey(ctx, "parent-alert")
+	if parent.Status != domain.AlertStatusResolved {
+		t.Fatal("Parent should be resolved once its children are")
+	}
+	want := domain.Resolution{ResolvedBy: domain.ResolvedByEvent, Actor: "prometheus", Reason: "replica caught up"}
+	if parent.Resolution == nil || *parent.Resolution != want {
+		t.Errorf("parent Resolution = %+v, want %+v", parent.Resolution, want)
+	}
+}
+
+// sweepQueue stands in for the queue the pending resolve sweeper publishes
+// its checks to: they are held until delivered to the processor. Checks of
+// the parents in fail are refused.
+type sweepQueue struct {
+	service *Service
+	events  []*domain.InternalEvent
+	fail    map[string]bool
+}
+
+func (q *sweepQueue) PublishPendingResolveSweep(_ context.Context, alert *domain.Alert, partitionKey string) error {
+	if q.fail[alert.DedupKey] {
+		return errors.New("queue unavailable")
+	}
+	q.events = append(q.events, &domain.InternalEvent{
+		Event:               domain.Event{EventManagerID: alert.EventManagerID, Action: domain.ActionResolve, DedupKey: alert.DedupKey},
+		PartitionKey:        partitionKey,
+		SweepPendingResolve: true,
+		ReceivedAt:          q.service.now(),
+	})
+	return nil
+}
+
+// deliver hands the queued checks to the processor.
+func (q *sweepQueue) deliver(t *testing.T) {
+	t.Helper()
+	for _, event := range q.events {
+		payload, _ := json.Marshal(event)
+		if err := q.service.handleMessage(context.Background(), &queue.Message{Value: payload}); err != nil {
+			t.Fatalf("handleMessage error: %v", err)
+		}
+	}
+	q.events = nil
+}
+
+func TestProcessor_SweepPendingResolves(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	service, _, stateStore, alertRepo, emRepo, grRepo := testSetupWithClock(clk)
+	ctx := context.Background()
+
+	setupTestData(ctx, emRepo, grRepo)
+
+	_ = stateStore.SetAlert(ctx, &store.AlertState{
+		DedupKey:       "parent-alert",
+		EventManagerID: "em-1",
+		Type:           string(domain.AlertTypeParent),
+		Status:         string(domain.AlertStatusActive),
+	})
+	_ = alertRepo.Create(ctx, &domain.Alert{
+		ID:             "parent-id",
+		DedupKey:       "parent-alert",
+		EventManagerID: "em-1",
+		Type:           domain.AlertTypeParent,
+		Status:         domain.AlertStatusActive,
+		ChildCount:     1,
+		CreatedAt:      clk.Now(),
+		UpdatedAt:      clk.Now(),
+	})
+	child := &domain.Alert{
+		ID:             "child-id",
+		DedupKey:       "child-alert",
+		EventManagerID: "em-1",
+		Type:           domain.AlertTypeChild,
+		Status:         domain.AlertStatusActive,
+		ParentDedupKey: "parent-alert",
+		CreatedAt:      clk.Now(),
+	}
+	_ = alertRepo.Create(ctx, child)
+
+	event := &domain.InternalEvent{
+		Event: domain.Event{
+			EventManagerID: "em-1",
+			Action:         domain.ActionResolve,
+			DedupKey:       "parent-alert",
+			Summary:        "replica caught up",
+			Source:         "prometheus",
+		},
+		ReceivedAt: clk.Now(),
+	}
+	payload, _ := json.Marshal(event)
+	if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+		t.Fatalf("handleMessage error: %v", err)
+	}
+
+	sweeps := &sweepQueue{service: service}
+
+	// A parent with active children is not checked while its resolve waits
+	if queued, err := service.SweepPendingResolves(ctx, 0, sweeps); err != nil || queued != 0 {
+		t.Fatalf("SweepPendingResolves() = %d, %v, want 0 with an active child", queued, err)
+	}
+
+	// A parent whose children are active keeps waiting, and gets its pending
+	// resolve back if the state store lost it
+	_ = stateStore.DeletePendingResolve(ctx, "parent-alert")
+	if queued, err := service.SweepPendingResolves(ctx, 0, sweeps); err != nil || queued != 1 {
+		t.Fatalf("SweepPendingResolves() = %d, %v, want the parent checked", queued, err)
+	}
+	if pending, _ := stateStore.GetPendingResolve(ctx, "parent-alert"); pending != nil {
+		t.Errorf("pending resolve = %+v before the check was processed, want the sweep to only read", pending)
+	}
+	sweeps.deliver(t)
+	if pending, _ := stateStore.GetPendingResolve(ctx, "parent-alert"); pending == nil || pending.RemainingChildren != 1 {
+		t.Errorf("pending resolve = %+v, want one remaining child", pending)
+	}
+
+	// The processor stopped after resolving the last child, before checking
+	// on the parent
+	if _, err := child.Resolve(domain.Resolution{ResolvedBy: domain.ResolvedByEvent}, clk.Now()); err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+	_ = alertRepo.Update(ctx, child)
+
+	if queued, err := service.SweepPendingResolves(ctx, time.Minute, sweeps); err != nil || queued != 0 {
+		t.Fatalf("SweepPendingResolves() = %d, %v, want 0 for a parent updated within a minute", queued, err)
+	}
+
+	clk.Advance(2 * time.Minute)
+	if queued, err := service.SweepPendingResolves(ctx, time.Minute, sweeps); err != nil || queued != 1 {
+		t.Fatalf("SweepPendingResolves() = %d, %v, want the parent checked", queued, err)
+	}
+
+	// A child grouped under the parent before the check is processed keeps
+	// the parent waiting
+	late := &domain.Alert{
+		ID:             "late-child-id",
+		DedupKey:       "late-child-alert",
+		EventManagerID: "em-1",
+		Type:           domain.AlertTypeChild,
+		Status:         domain.AlertStatusActive,
+		ParentDedupKey: "parent-alert",
+		CreatedAt:      clk.Now(),
+	}
+	_ = alertRepo.Create(ctx, late)
+	sweeps.deliver(t)
+	if parent, _ := alertRepo.GetByDedupKey(ctx, "parent-alert"); parent.IsResolved() {
+		t.Fatal("parent resolved by a check processed after a child was grouped under it")
+	}
+
+	if _, err := late.Resolve(domain.Resolution{ResolvedBy: domain.ResolvedByEvent}, clk.Now()); err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+	_ = alertRepo.Update(ctx, late)
+	if queued, err := service.SweepPendingResolves(ctx, time.Minute, sweeps); err != nil || queued != 1 {
+		t.Fatalf("SweepPendingResolves() = %d, %v, want the parent checked", queued, err)
+	}
+	// Checks queued by several processors resolve the parent once
+	if _, err := service.SweepPendingResolves(ctx, time.Minute, sweeps); err != nil {
+		t.Fatalf("SweepPendingResolves() error: %v", err)
+	}
+	sweeps.deliver(t)
+
+	parent, _ := alertRepo.GetByDedupKey(ctx, "parent-alert")
+	want := domain.Resolution{ResolvedBy: domain.ResolvedByEvent, Actor: "prometheus", Reason: "replica caught up"}
+	if !parent.IsResolved() || parent.Resolution == nil || *parent.Resolution != want {
+		t.Errorf("parent = %s resolved with %+v, want resolved with %+v", parent.Status, parent.Resolution, want)
+	}
+	if pending, _ := stateStore.GetPendingResolve(ctx, "parent-alert"); pending != nil {
+		t.Errorf("pending resolve = %+v, want it removed", pending)
+	}
+}
+
+func TestProcessor_SweepPendingResolves_PublishFailure(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	service, _, _, alertRepo, emRepo, grRepo := testSetupWithClock(clk)
+	ctx := context.Background()
+
+	setupTestData(ctx, emRepo, grRepo)
+	usageRepo := storemem.NewUsageRepository()
+	service.usage = usage.NewMeter(usageRepo, clk, service.logger)
+
+	// Parents whose resolve was requested with no children left
+	for _, key := range []string{"parent-a", "parent-b", "parent-c"} {
+		_ = alertRepo.Create(ctx, &domain.Alert{
+			ID:               key + "-id",
+			DedupKey:         key,
+			EventManagerID:   "em-1",
+			Type:             domain.AlertTypeParent,
+			Status:           domain.AlertStatusActive,
+			ResolveRequested: true,
+			CreatedAt:        clk.Now(),
+			UpdatedAt:        clk.Now(),
+		})
+	}
+
+	sweeps := &sweepQueue{service: service, fail: map[string]bool{"parent-b": true}}
+	queued, err := service.SweepPendingResolves(ctx, 0, sweeps)
+	if queued != 2 || err == nil {
+		t.Fatalf("SweepPendingResolves() = %d, %v, want 2 queued and the failure returned", queued, err)
+	}
+	var checked []string
+	for _, event := range sweeps.events {
+		checked = append(checked, event.DedupKey)
+	}
+	slices.Sort(checked)
+	if want := []string{"parent-a", "parent-c"}; !slices.Equal(checked, want) {
+		t.Errorf("checked parents = %v, want %v", checked, want)
+	}
+
+	// The checks resolve their parents without counting as ingested events
+	sweeps.deliver(t)
+	for _, key := range []string{"parent-a", "parent-c"} {
+		if parent, _ := alertRepo.GetByDedupKey(ctx, key); !parent.IsResolved() {
+			t.Errorf("%s status = %s, want resolved", key, parent.Status)
+		}
+	}
+	if events, _ := service.storms.measure(clk.Now()); events["em-1"] != 0 {
+		t.Errorf("storm events = %d, want checks not counted", events["em-1"])
+	}
+	if err := service.usage.Flush(ctx); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+	usages, _ := usageRepo.List(ctx, domain.ReportFilter{From: clk.Now(), To: clk.Now()})
+	for _, u := range usages {
+		if u.EventsIngested != 0 {
+			t.Errorf("events ingested = %d, want checks not counted", u.EventsIngested)
+		}
+	}
+}
+
+func TestProcessor_SweepPendingResolves_MaxPendingResolve(t *testing.T) {
+	for _, resolveChildren := range []bool{false, true} {
+		t.Run(fmt.Sprintf("force_resolve_children=%t", resolveChildren), func(t *testing.T) {
+			clk := clock.NewFake(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+			service, _, stateStore, alertRepo, emRepo, grRepo := testSetupWithClock(clk)
+			service.cfg.MaxPendingResolve = time.Hour
+			service.cfg.ForceResolveChildren = resolveChildren
+			ctx := context.Background()
+
+			setupTestData(ctx, emRepo, grRepo)
+			_ = stateStore.SetAlert(ctx, &store.AlertState{
+				DedupKey:       "parent-alert",
+				EventManagerID: "em-1",
+				Type:           string(domain.AlertTypeParent),
+				Status:         string(domain.AlertStatusActive),
+			})
+			_ = alertRepo.Create(ctx, &domain.Alert{
+				ID:             "parent-id",
+				DedupKey:       "parent-alert",
+				EventManagerID: "em-1",
+				Type:           domain.AlertTypeParent,
+				Status:         domain.AlertStatusActive,
+				ChildCount:     1,
+				CreatedAt:      clk.Now(),
+			})
+			_ = alertRepo.Create(ctx, &domain.Alert{
+				ID:             "child-id",
+				DedupKey:       "child-alert",
+				EventManagerID: "em-1",
+				Type:           domain.AlertTypeChild,
+				Status:         domain.AlertStatusActive,
+				ParentDedupKey: "parent-alert",
+				CreatedAt:      clk.Now(),
+			})
+
+			event := &domain.InternalEvent{
+				Event:      domain.Event{EventManagerID: "em-1", Action: domain.ActionResolve, DedupKey: "parent-alert", Source: "prometheus"},
+				ReceivedAt: clk.Now(),
+			}
+			payload, _ := json.Marshal(event)
+			if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+				t.Fatalf("handleMessage error: %v", err)
+			}
+
+			sweeps := &sweepQueue{service: service}
+			clk.Advance(59 * time.Minute)
+			if queued, err := service.SweepPendingResolves(ctx, 0, sweeps); err != nil || queued != 0 {
+				t.Fatalf("SweepPendingResolves() = %d, %v, want 0 before the maximum", queued, err)
+			}
+
+			// The limit applies however recently the parent was updated
+			clk.Advance(time.Minute)
+			if queued, err := service.SweepPendingResolves(ctx, time.Hour, sweeps); err != nil || queued != 1 {
+				t.Fatalf("SweepPendingResolves() = %d, %v, want the parent checked", queued, err)
+			}
+			if parent, _ := alertRepo.GetByDedupKey(ctx, "parent-alert"); parent.IsResolved() {
+				t.Fatal("parent force-resolved by the sweep, want it resolved when the check is processed")
+			}
+			sweeps.deliver(t)
+
+			parent, _ := alertRepo.GetByDedupKey(ctx, "parent-alert")
+			if !parent.IsResolved() || parent.Resolution == nil ||
+				parent.Resolution.ResolvedBy != domain.ResolvedByPendingTimeout || parent.Resolution.Actor != "prometheus" {
+				t.Errorf("parent = %s resolved with %+v, want force-resolved by the timeout", parent.Status, parent.Resolution)
+			}
+			if child, _ := alertRepo.GetByDedupKey(ctx, "child-alert"); child.IsResolved() != resolveChildren {
+				t.Errorf("child resolved = %t, want %t", child.IsResolved(), resolveChildren)
+			}
+			if pending, _ := stateStore.GetPendingResolve(ctx, "parent-alert"); pending != nil {
+				t.Errorf("pending resolve = %+v, want it removed", pending)
+			}
+		})
+	}
+}
+
+func TestProcessor_HandleResolve_RequestedResolveResolvesGroup(t *testing.T) {
+	service, _, stateStore, alertRepo, emRepo, grRepo := testSetup()
+	ctx := context.Background()
+
+	setupTestData(ctx, emRepo, grRepo)
+
+	for _, alert := range []*domain.Alert{
+		{ID: "parent-id", DedupKey: "parent-alert", EventManagerID: "em-1", Type: domain.AlertTypeParent, ChildCount: 1},
+		{ID: "child-id", DedupKey: "child-alert", EventManagerID: "em-1", Type: domain.AlertTypeChild, ParentDedupKey: "parent-alert"},
+	} {
+		alert.Status = domain.AlertStatusActive
+		alert.CreatedAt = time.Now()
+		_ = alertRepo.Create(ctx, alert)
+		_ = stateStore.SetAlert(ctx, &store.AlertState{
+			DedupKey:       alert.DedupKey,
+			EventManagerID: "em-1",
+			Type:           string(alert.Type),
+			Status:         string(alert.Status),
+			ParentDedupKey: alert.ParentDedupKey,
+		})
+	}
+	_ = stateStore.AddChild(ctx, "parent-alert", "child-alert")
+
+	// A resolve requested through the API doesn't wait for the children
+	resolution := domain.Resolution{ResolvedBy: domain.ResolvedByAPI, Actor: "alice", Reason: "failover completed"}
+	event := &domain.InternalEvent{
+		Event: domain.Event{
+			EventManagerID: "em-1",
+			Action:         domain.ActionResolve,
+			DedupKey:       "parent-alert",
+		},
+		Resolution: &resolution,
+		ReceivedAt: time.Now(),
+	}
+	payload, _ := json.Marshal(event)
+	if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+		t.Fatalf("handleMessage error: %v", err)
+	}
+
+	for _, dedupKey := range []string{"parent-alert", "child-alert"} {
+		alert, _ := alertRepo.GetByDedupKey(ctx, dedupKey)
+		if alert.Status != domain.AlertStatusResolved || alert.Resolution == nil || *alert.Resolution != resolution {
+			t.Errorf("%s = %s with resolution %+v, want resolved by alice", dedupKey, alert.Status, alert.Resolution)
+		}
+		state, _ := stateStore.GetAlert(ctx, dedupKey)
+		if state.Status != string(domain.AlertStatusResolved) {
+			t.Errorf("%s state = %s, want resolved", dedupKey, state.Status)
+		}
+	}
+
+	// A repeated resolve is ignored
+	if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+		t.Errorf("handleMessage error for a repeated resolve: %v", err)
+	}
 }
 
 func TestProcessor_DuplicateEvent_Ignored(t *testing.T) {
@@ -365,4 +912,1336 @@ func TestProcessor_DuplicateEvent_Ignored(t *testing.T) {
 	if alert.Summary != "Original summary" {
 		t.Errorf("Alert summary should not change, got %v", alert.Summary)
 	}
+	if alert.TriggerCount != 1 {
+		t.Errorf("TriggerCount = %d, want 1 additional trigger recorded", alert.TriggerCount)
+	}
+}
+
+func TestProcessor_ResolveEventManagerAlerts(t *testing.T) {
+	service, _, stateStore, alertRepo, emRepo, grRepo := testSetup()
+	ctx := context.Background()
+
+	setupTestData(ctx, emRepo, grRepo)
+
+	// A parent with one child, both active
+	for i, dedupKey := range []string{"parent-alert", "child-alert"} {
+		event := &domain.InternalEvent{
+			Event: domain.Event{
+				EventManagerID: "em-1",
+				Summary:        "Test alert",
+				Severity:       domain.SeverityHigh,
+				Action:         domain.ActionTrigger,
+				Class:          "database",
+				DedupKey:       dedupKey,
+			},
+			GroupingValue: "database",
+		}
+		payload, _ := json.Marshal(event)
+		if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+			t.Fatalf("handleMessage %d error: %v", i, err)
+		}
+	}
+
+	_ = emRepo.Delete(ctx, "em-1")
+	resolved, err := service.ResolveEventManagerAlerts(ctx, "em-1")
+	if err != nil {
+		t.Fatalf("ResolveEventManagerAlerts error: %v", err)
+	}
+	if resolved != 2 {
+		t.Errorf("resolved = %d, want 2", resolved)
+	}
+
+	for _, dedupKey := range []string{"parent-alert", "child-alert"} {
+		alert, _ := alertRepo.GetByDedupKey(ctx, dedupKey)
+		if alert.Status != domain.AlertStatusResolved {
+			t.Errorf("%s status = %v, want resolved", dedupKey, alert.Status)
+		}
+		if alert.Resolution == nil || alert.Resolution.ResolvedBy != domain.ResolvedByAPI {
+			t.Errorf("%s resolution = %+v, want resolved by api", dedupKey, alert.Resolution)
+		}
+		state, _ := stateStore.GetAlert(ctx, dedupKey)
+		if state.Status != string(domain.AlertStatusResolved) {
+			t.Errorf("%s state status = %v, want resolved", dedupKey, state.Status)
+		}
+	}
+
+	// Events still queued for the deleted event manager do not reopen alerts
+	event := &domain.InternalEvent{
+		Event: domain.Event{
+			EventManagerID: "em-1",
+			Summary:        "Test alert",
+			Severity:       domain.SeverityHigh,
+			Action:         domain.ActionTrigger,
+			Class:          "database",
+			DedupKey:       "parent-alert",
+		},
+		GroupingValue: "database",
+	}
+	payload, _ := json.Marshal(event)
+	if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+		t.Fatalf("handleMessage error: %v", err)
+	}
+	if alert, _ := alertRepo.GetByDedupKey(ctx, "parent-alert"); alert.Status != domain.AlertStatusResolved {
+		t.Errorf("parent-alert reactivated for deleted event manager")
+	}
+
+	// Purging removes alerts and their cached state
+	purged, err := service.PurgeEventManagerAlerts(ctx, "em-1")
+	if err != nil {
+		t.Fatalf("PurgeEventManagerAlerts error: %v", err)
+	}
+	if purged != 2 {
+		t.Errorf("purged = %d, want 2", purged)
+	}
+	if _, err := alertRepo.GetByDedupKey(ctx, "parent-alert"); err != domain.ErrAlertNotFound {
+		t.Errorf("GetByDedupKey after purge error = %v, want ErrAlertNotFound", err)
+	}
+	if state, _ := stateStore.GetAlert(ctx, "child-alert"); state != nil {
+		t.Error("alert state should be removed by purge")
+	}
+}
+
+func TestProcessor_GlobalDedup_SharesAlert(t *testing.T) {
+	service, _, _, alertRepo, emRepo, grRepo := testSetup()
+	service.globalDedup = true
+	ctx := context.Background()
+
+	setupTestData(ctx, emRepo, grRepo)
+	_ = emRepo.Create(ctx, &domain.EventManager{ID: "em-2", Name: "Second EM", GroupingRuleID: "rule-1", CreatedAt: time.Now()})
+
+	send := func(emID string, action domain.Action) {
+		t.Helper()
+		event := &domain.InternalEvent{
+			Event: domain.Event{
+				EventManagerID: emID,
+				Summary:        "Disk full",
+				Severity:       domain.SeverityHigh,
+				Action:         action,
+				Class:          "storage",
+				DedupKey:       "shared-alert",
+			},
+			GroupingValue: "storage",
+		}
+		payload, _ := json.Marshal(event)
+		if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+			t.Fatalf("handleMessage error: %v", err)
+		}
+	}
+
+	send("em-1", domain.ActionTrigger)
+	send("em-2", domain.ActionTrigger)
+	send("em-2", domain.ActionTrigger)
+
+	alert, err := alertRepo.GetByDedupKey(ctx, "shared-alert")
+	if err != nil {
+		t.Fatalf("GetByDedupKey error: %v", err)
+	}
+	if alert.EventManagerID != "em-1" || len(alert.SubscriberIDs) != 1 || alert.SubscriberIDs[0] != "em-2" {
+		t.Errorf("alert owner = %s, subscribers = %v, want em-1 and [em-2]", alert.EventManagerID, alert.SubscriberIDs)
+	}
+	if alert.TriggerCount != 3 {
+		t.Errorf("TriggerCount = %d, want 3", alert.TriggerCount)
+	}
+
+	// The shared alert is listed for the subscriber, but not counted as its own
+	subscribed, _ := alertRepo.List(ctx, domain.AlertFilter{EventManagerID: "em-2", IncludeSubscribed: true})
+	owned, _ := alertRepo.List(ctx, domain.AlertFilter{EventManagerID: "em-2"})
+	if len(subscribed) != 1 || len(owned) != 0 {
+		t.Errorf("alerts of em-2 = %d subscribed, %d owned, want 1 and 0", len(subscribed), len(owned))
+	}
+
+	// A resolve from any subscriber resolves the shared alert
+	send("em-2", domain.ActionResolve)
+	alert, _ = alertRepo.GetByDedupKey(ctx, "shared-alert")
+	if alert.Status != domain.AlertStatusResolved {
+		t.Errorf("Status = %s, want resolved", alert.Status)
+	}
+}
+
+func TestProcessor_SendDueReminders(t *testing.T) {
+	service, _, stateStore, alertRepo, emRepo, grRepo := testSetup()
+	ctx := context.Background()
+
+	setupTestData(ctx, emRepo, grRepo)
+	em, _ := emRepo.GetByID(ctx, "em-1")
+	em.NotificationConfig = domain.NotificationConfig{ReminderIntervalMinutes: 10, MaxReminders: 2}
+	_ = emRepo.Update(ctx, em)
+
+	// Two unrelated parents
+	for i, class := range []string{"database", "network"} {
+		event := &domain.InternalEvent{
+			Event: domain.Event{
+				EventManagerID: "em-1",
+				Summary:        "Test alert",
+				Severity:       domain.SeverityHigh,
+				Action:         domain.ActionTrigger,
+				Class:          class,
+				DedupKey:       class + "-alert",
+			},
+			GroupingValue: class,
+		}
+		payload, _ := json.Marshal(event)
+		if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+			t.Fatalf("handleMessage %d error: %v", i, err)
+		}
+	}
+
+	// The acknowledged parent is not reminded of
+	acked, _ := alertRepo.GetByDedupKey(ctx, "network-alert")
+	_, _ = acked.Acknowledge(time.Now().UTC())
+	_ = alertRepo.Update(ctx, acked)
+
+	now := time.Now().UTC()
+	steps := []struct {
+		at   time.Duration
+		want int
+	}{
+		{0, 0},                // not due yet
+		{11 * time.Minute, 1}, // first reminder
+		{15 * time.Minute, 0}, // rescheduled interval after the first
+		{22 * time.Minute, 1}, // second and last reminder
+		{60 * time.Minute, 0}, // max reached
+	}
+	for _, step := range steps {
+		sent, err := service.SendDueReminders(ctx, now.Add(step.at))
+		if err != nil {
+			t.Fatalf("SendDueReminders(+%v) error: %v", step.at, err)
+		}
+		if sent != step.want {
+			t.Errorf("SendDueReminders(+%v) = %d, want %d", step.at, sent, step.want)
+		}
+	}
+
+	due, _ := stateStore.GetDueReminders(ctx, now.Add(24*time.Hour), 10)
+	if len(due) != 0 {
+		t.Errorf("remaining reminders = %d, want 0", len(due))
+	}
+}
+
+// flakyAlertRepository fails the next failures calls to Create.
+type flakyAlertRepository struct {
+	*storemem.AlertRepository
+	failures int
+}
+
+func (r *flakyAlertRepository) Create(ctx context.Context, alert *domain.Alert) error {
+	if r.failures > 0 {
+		r.failures--
+		return errors.New("connection reset")
+	}
+	return r.AlertRepository.Create(ctx, alert)
+}
+
+func TestProcessor_RetriesStoreErrors(t *testing.T) {
+	_, _, stateStore, alertRepo, emRepo, grRepo := testSetup()
+	ctx := context.Background()
+	setupTestData(ctx, emRepo, grRepo)
+
+	flaky := &flakyAlertRepository{AlertRepository: alertRepo}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	service := NewService(
+		memory.NewQueue(1),
+		stateStore,
+		flaky,
+		emRepo,
+		grRepo,
+		notification.NewStubNotifier(logger),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		config.DedupConfig{},
+		config.ProcessorConfig{MaxRetries: 2, RetryBackoff: time.Millisecond, MaxRetryBackoff: 2 * time.Millisecond},
+		clock.Real{},
+		logger,
+	)
+
+	trigger := func(dedupKey string) error {
+		event := &domain.InternalEvent{
+			Event: domain.Event{
+				EventManagerID: "em-1",
+				Summary:        "Test alert",
+				Severity:       domain.SeverityHigh,
+				Action:         domain.ActionTrigger,
+				Class:          dedupKey,
+				DedupKey:       dedupKey,
+			},
+			GroupingValue: dedupKey,
+		}
+		payload, _ := json.Marshal(event)
+		return service.handleMessage(ctx, &queue.Message{Value: payload})
+	}
+
+	// Failures within the retry budget are retried until the alert is created
+	flaky.failures = 2
+	if err := trigger("recovers"); err != nil {
+		t.Fatalf("handleMessage error: %v", err)
+	}
+	if _, err := alertRepo.GetByDedupKey(ctx, "recovers"); err != nil {
+		t.Errorf("GetByDedupKey error = %v, want the alert created on retry", err)
+	}
+
+	// Beyond it the event is given up on, leaving no cached state behind
+	flaky.failures = 3
+	if err := trigger("poison"); err == nil {
+		t.Fatal("handleMessage error = nil, want the store error")
+	}
+	if state, _ := stateStore.GetAlert(ctx, "poison"); state != nil {
+		t.Errorf("alert state = %+v, want it rolled back", state)
+	}
+	if flaky.failures != 0 {
+		t.Errorf("remaining failures = %d, want all 3 attempts made", flaky.failures)
+	}
+
+	// Missing resources are not retried
+	_ = emRepo.Purge(ctx, "em-1")
+	flaky.failures = 0
+	if err := trigger("orphan"); !errors.Is(err, domain.ErrEventManagerNotFound) {
+		t.Errorf("handleMessage error = %v, want %v", err, domain.ErrEventManagerNotFound)
+	}
+}
+
+func TestService_RetryBackoff(t *testing.T) {
+	service := &Service{cfg: config.ProcessorConfig{RetryBackoff: 100 * time.Millisecond, MaxRetryBackoff: time.Second}}
+
+	for attempt, limit := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		limit *= time.Millisecond
+		for range 20 {
+			if got := service.retryBackoff(attempt); got < limit/2 || got > limit {
+				t.Fatalf("retryBackoff(%d) = %v, want within [%v, %v]", attempt, got, limit/2, limit)
+			}
+		}
+	}
+}
+
+func TestProcessor_HandleResolve_ResolutionPolicies(t *testing.T) {
+	tests := map[domain.ResolutionPolicy]domain.AlertStatus{
+		domain.ResolveWithAny:      domain.AlertStatusActive,
+		domain.AutoResolveChildren: domain.AlertStatusResolved,
+	}
+
+	for policy, wantChild := range tests {
+		t.Run(string(policy), func(t *testing.T) {
+			service, _, stateStore, alertRepo, emRepo, grRepo := testSetup()
+			ctx := context.Background()
+
+			setupTestData(ctx, emRepo, grRepo)
+			em, _ := emRepo.GetByID(ctx, "em-1")
+			em.ResolutionPolicy = policy
+			_ = emRepo.Update(ctx, em)
+
+			send := func(dedupKey string, action domain.Action) {
+				event := &domain.InternalEvent{
+					Event: domain.Event{
+						EventManagerID: "em-1",
+						Summary:        "Test alert",
+						Severity:       domain.SeverityHigh,
+						Action:         action,
+						Class:          "database",
+						DedupKey:       dedupKey,
+					},
+					GroupingValue: "database",
+				}
+				payload, _ := json.Marshal(event)
+				if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+					t.Fatalf("handleMessage(%s %s) error: %v", action, dedupKey, err)
+				}
+			}
+			send("parent-alert", domain.ActionTrigger)
+			send("child-alert", domain.ActionTrigger)
+			send("parent-alert", domain.ActionResolve)
+
+			// The parent resolves without waiting for its child
+			parent, _ := alertRepo.GetByDedupKey(ctx, "parent-alert")
+			if parent.Status != domain.AlertStatusResolved {
+				t.Errorf("parent status = %v, want resolved", parent.Status)
+			}
+			child, _ := alertRepo.GetByDedupKey(ctx, "child-alert")
+			if child.Status != wantChild {
+				t.Errorf("child status = %v, want %v", child.Status, wantChild)
+			}
+			state, _ := stateStore.GetAlert(ctx, "child-alert")
+			if state.Status != string(wantChild) {
+				t.Errorf("child state status = %v, want %v", state.Status, wantChild)
+			}
+		})
+	}
+}
+
+func TestProcessor_DedupMetrics(t *testing.T) {
+	service, _, _, _, emRepo, grRepo := testSetup()
+	ctx := context.Background()
+	setupTestData(ctx, emRepo, grRepo)
+
+	count := func(counter *prometheus.CounterVec) float64 {
+		var m dto.Metric
+		if err := counter.WithLabelValues("em-1").Write(&m); err != nil {
+			t.Fatalf("Write error: %v", err)
+		}
+		return m.GetCounter().GetValue()
+	}
+	duplicates := count(metrics.DuplicateTriggers)
+	reactivations := count(metrics.AlertReactivations)
+	unknown := count(metrics.UnknownResolves)
+
+	send := func(dedupKey string, action domain.Action) {
+		event := &domain.InternalEvent{
+			Event: domain.Event{
+				EventManagerID: "em-1",
+				Summary:        "Test alert",
+				Severity:       domain.SeverityHigh,
+				Action:         action,
+				Class:          "database",
+				DedupKey:       dedupKey,
+			},
+			GroupingValue: "database",
+		}
+		payload, _ := json.Marshal(event)
+		if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+			t.Fatalf("handleMessage(%s %s) error: %v", action, dedupKey, err)
+		}
+	}
+	send("dedup-metrics", domain.ActionTrigger)
+	send("dedup-metrics", domain.ActionTrigger)
+	send("dedup-metrics", domain.ActionResolve)
+	send("dedup-metrics", domain.ActionTrigger)
+	send("never-triggered", domain.ActionResolve)
+
+	if got := count(metrics.DuplicateTriggers) - duplicates; got != 1 {
+		t.Errorf("duplicate triggers = %v, want 1", got)
+	}
+	if got := count(metrics.AlertReactivations) - reactivations; got != 1 {
+		t.Errorf("reactivations = %v, want 1", got)
+	}
+	if got := count(metrics.UnknownResolves) - unknown; got != 1 {
+		t.Errorf("unknown resolves = %v, want 1", got)
+	}
+}
+
+func TestProcessor_ReactivationRetry(t *testing.T) {
+	service, _, stateStore, alertRepo, emRepo, grRepo := testSetup()
+	ctx := context.Background()
+	setupTestData(ctx, emRepo, grRepo)
+
+	send := func(action domain.Action) {
+		event := &domain.InternalEvent{
+			Event: domain.Event{
+				EventManagerID: "em-1",
+				Summary:        "Test alert",
+				Severity:       domain.SeverityHigh,
+				Action:         action,
+				Class:          "database",
+				DedupKey:       "episodes",
+			},
+			GroupingValue: "database",
+		}
+		payload, _ := json.Marshal(event)
+		if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+			t.Fatalf("handleMessage(%s) error: %v", action, err)
+		}
+	}
+	send(domain.ActionTrigger)
+	send(domain.ActionResolve)
+	send(domain.ActionTrigger)
+
+	// A retry after the state store write failed finds the alert resolved
+	// there, but doesn't start another episode
+	state, err := stateStore.GetAlert(ctx, "episodes")
+	if err != nil || state == nil {
+		t.Fatalf("GetAlert() = %v, %v", state, err)
+	}
+	state.Status = string(domain.AlertStatusResolved)
+	if err := stateStore.SetAlert(ctx, state); err != nil {
+		t.Fatalf("SetAlert() error = %v", err)
+	}
+	send(domain.ActionTrigger)
+
+	alert, err := alertRepo.GetByDedupKey(ctx, "episodes")
+	if err != nil {
+		t.Fatalf("GetByDedupKey() error = %v", err)
+	}
+	if alert.Episode != 2 || len(alert.Episodes) != 1 || alert.Episodes[0].ResolvedAt == nil {
+		t.Errorf("alert = episode %d with history %+v, want episode 2 after one resolved episode", alert.Episode, alert.Episodes)
+	}
+	if state, _ := stateStore.GetAlert(ctx, "episodes"); state.Status != string(domain.AlertStatusActive) {
+		t.Errorf("state status = %s, want active after the retry", state.Status)
+	}
+}
+
+func TestProcessor_TriggerAfterPartitionDetach(t *testing.T) {
+	service, _, stateStore, alertRepo, emRepo, grRepo := testSetup()
+	ctx := context.Background()
+	setupTestData(ctx, emRepo, grRepo)
+
+	send := func(action domain.Action) {
+		t.Helper()
+		event := &domain.InternalEvent{
+			Event: domain.Event{
+				EventManagerID: "em-1",
+				Summary:        "Test alert",
+				Severity:       domain.SeverityHigh,
+				Action:         action,
+				Class:          "database",
+				DedupKey:       "detached",
+			},
+			GroupingValue: "database",
+		}
+		payload, _ := json.Marshal(event)
+		if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+			t.Fatalf("handleMessage(%s) error: %v", action, err)
+		}
+	}
+	send(domain.ActionTrigger)
+	send(domain.ActionResolve)
+
+	old, err := alertRepo.GetByDedupKey(ctx, "detached")
+	if err != nil {
+		t.Fatalf("GetByDedupKey() error = %v", err)
+	}
+
+	// Detaching the partition of the resolved alert releases its dedup key
+	// but leaves its resolved state in the state store
+	if err := alertRepo.Delete(ctx, "detached"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	send(domain.ActionTrigger)
+
+	alert, err := alertRepo.GetByDedupKey(ctx, "detached")
+	if err != nil {
+		t.Fatalf("GetByDedupKey() after the trigger error = %v, want a new alert", err)
+	}
+	if alert.ID == old.ID || alert.Status != domain.AlertStatusActive || alert.Episode > 1 {
+		t.Errorf("alert = %s %s episode %d, want a new active alert", alert.ID, alert.Status, alert.Episode)
+	}
+	state, err := stateStore.GetAlert(ctx, "detached")
+	if err != nil || state == nil || state.Status != string(domain.AlertStatusActive) {
+		t.Errorf("state = %+v, %v, want active", state, err)
+	}
+}
+
+func TestProcessor_PauseResume(t *testing.T) {
+	service, _, stateStore, _, emRepo, grRepo := testSetup()
+	ctx := context.Background()
+	setupTestData(ctx, emRepo, grRepo)
+
+	if service.Status().Paused {
+		t.Fatal("new processor is paused")
+	}
+	status := service.Pause()
+	if !status.Paused || status.PausedAt == nil {
+		t.Fatalf("Pause() = %+v, want paused with a time", status)
+	}
+	if again := service.Pause(); !again.PausedAt.Equal(*status.PausedAt) {
+		t.Errorf("second Pause() moved paused_at from %v to %v", status.PausedAt, again.PausedAt)
+	}
+
+	event := &domain.InternalEvent{
+		Event: domain.Event{
+			EventManagerID: "em-1",
+			Summary:        "Test alert",
+			Severity:       domain.SeverityHigh,
+			Action:         domain.ActionTrigger,
+			Class:          "database",
+			DedupKey:       "paused-alert",
+		},
+		GroupingValue: "database",
+	}
+	payload, _ := json.Marshal(event)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- service.handleMessage(ctx, &queue.Message{Value: payload})
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("handleMessage returned while paused: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if state, _ := stateStore.GetAlert(ctx, "paused-alert"); state != nil {
+		t.Fatal("event was processed while paused")
+	}
+
+	if status := service.Resume(); status.Paused {
+		t.Fatalf("Resume() = %+v, want running", status)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("handleMessage error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handleMessage still blocked after resume")
+	}
+	if state, _ := stateStore.GetAlert(ctx, "paused-alert"); state == nil {
+		t.Error("event was not processed after resume")
+	}
+}
+
+func TestProcessor_PauseCanceled(t *testing.T) {
+	service, _, _, _, _, _ := testSetup()
+	service.Pause()
+	defer service.Resume()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := service.handleMessage(ctx, &queue.Message{Value: []byte("{}")}); !errors.Is(err, context.Canceled) {
+		t.Errorf("handleMessage error = %v, want context.Canceled", err)
+	}
+}
+
+func TestProcessor_Heartbeat(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 10, 15, 14, 30, 0, 0, time.UTC))
+	service, _, _, _, _, _ := testSetupWithClock(clk)
+	service.cfg.StallTimeout = time.Minute
+
+	if status := service.Status(); status.Running || status.Healthy() {
+		t.Fatalf("Status() before Start = %+v, want not running", status)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- service.Start(ctx) }()
+	deadline := time.Now().Add(time.Second)
+	for !service.Status().Running && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// A malformed event is handled too, and moves the heartbeat
+	_ = service.handleMessage(context.Background(), &queue.Message{Value: []byte("{")})
+	if status := service.Status(); !status.Healthy() || status.LastEventAt == nil || !status.LastEventAt.Equal(clk.Now()) {
+		t.Errorf("Status() after an event = %+v, want healthy with last_event_at %v", status, clk.Now())
+	}
+
+	// An event handled for longer than the stall timeout stalls the processor
+	service.heartbeat.begin(clk.Now())
+	clk.Advance(30 * time.Second)
+	if service.Status().Stalled {
+		t.Error("Status() within the stall timeout is stalled")
+	}
+	clk.Advance(time.Minute)
+	if status := service.Status(); !status.Stalled || status.Healthy() {
+		t.Errorf("Status() past the stall timeout = %+v, want stalled", status)
+	}
+	service.heartbeat.end(clk.Now())
+
+	cancel()
+	<-done
+	if service.Status().Running {
+		t.Error("Status() after Start returned is running")
+	}
+}
+
+func TestProcessor_GroupingMetrics(t *testing.T) {
+	service, _, _, _, emRepo, grRepo := testSetup()
+	ctx := context.Background()
+
+	setupTestData(ctx, emRepo, grRepo)
+	_ = emRepo.Create(ctx, &domain.EventManager{ID: "em-solo", Name: "Ungrouped", GroupingDisabled: true, CreatedAt: time.Now()})
+	_ = emRepo.Create(ctx, &domain.EventManager{ID: "em-2", Name: "Deleted", GroupingRuleID: "rule-1", CreatedAt: time.Now()})
+
+	send := func(emID, dedupKey, class string, action domain.Action, resolution *domain.Resolution) {
+		t.Helper()
+		event := &domain.InternalEvent{
+			Event: domain.Event{
+				EventManagerID: emID,
+				Summary:        "Test alert",
+				Severity:       domain.SeverityHigh,
+				Action:         action,
+				Class:          class,
+				DedupKey:       dedupKey,
+			},
+			GroupingValue: class,
+			Resolution:    resolution,
+		}
+		payload, _ := json.Marshal(event)
+		if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+			t.Fatalf("handleMessage(%s %s) error: %v", action, dedupKey, err)
+		}
+	}
+	decisions := func(outcome string) float64 {
+		return testutil.ToFloat64(metrics.GroupingDecisions.WithLabelValues(outcome))
+	}
+	lookups := func(result string) float64 {
+		return testutil.ToFloat64(metrics.GroupingCacheLookups.WithLabelValues(result))
+	}
+	// testutil.ToFloat64 reads no histograms, so the group sizes observed
+	// are read from the metric itself
+	groupSizes := func() (uint64, float64) {
+		t.Helper()
+		var m dto.Metric
+		if err := metrics.AlertGroupSize.Write(&m); err != nil {
+			t.Fatalf("Write error: %v", err)
+		}
+		return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+	}
+	observed := func(what string, wantSize float64, resolve func()) {
+		t.Helper()
+		count, sum := groupSizes()
+		resolve()
+		if gotCount, gotSum := groupSizes(); gotCount != count+1 || gotSum != sum+wantSize {
+			t.Errorf("%s observed %d group sizes summing to %v, want one of %v", what, gotCount-count, gotSum-sum, wantSize)
+		}
+	}
+
+	parents, children, standalone := decisions("parent"), decisions("child"), decisions("standalone")
+	hits, misses := lookups("hit"), lookups("miss")
+
+	// The first alert of a group misses the open parent and becomes it, the
+	// next one finds it and becomes its child
+	send("em-1", "db-1", "database", domain.ActionTrigger, nil)
+	send("em-1", "db-2", "database", domain.ActionTrigger, nil)
+	// Alerts of an event manager without grouping are looked up nowhere
+	send("em-solo", "solo-1", "database", domain.ActionTrigger, nil)
+
+	for _, tt := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"parent decisions", decisions("parent") - parents, 1},
+		{"child decisions", decisions("child") - children, 1},
+		{"standalone decisions", decisions("standalone") - standalone, 1},
+		{"cache hits", lookups("hit") - hits, 1},
+		{"cache misses", lookups("miss") - misses, 1},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// A parent resolved after its children
+	send("em-1", "db-2", "database", domain.ActionResolve, nil)
+	observed("resolve after the children", 1, func() {
+		send("em-1", "db-1", "database", domain.ActionResolve, nil)
+	})
+
+	// A group resolved as a whole
+	send("em-1", "net-1", "network", domain.ActionTrigger, nil)
+	send("em-1", "net-2", "network", domain.ActionTrigger, nil)
+	send("em-1", "net-3", "network", domain.ActionTrigger, nil)
+	observed("requested resolve of the group", 2, func() {
+		send("em-1", "net-1", "network", domain.ActionResolve, &domain.Resolution{ResolvedBy: domain.ResolvedByAPI, Actor: "alice"})
+	})
+
+	// The groups of a deleted event manager
+	send("em-2", "disk-1", "disk", domain.ActionTrigger, nil)
+	send("em-2", "disk-2", "disk", domain.ActionTrigger, nil)
+	_ = emRepo.Delete(ctx, "em-2")
+	observed("resolve of a deleted event manager", 1, func() {
+		if _, err := service.ResolveEventManagerAlerts(ctx, "em-2"); err != nil {
+			t.Fatalf("ResolveEventManagerAlerts error: %v", err)
+		}
+	})
+}
+
+func TestProcessor_CandidateGroupingRule(t *testing.T) {
+	service, _, stateStore, _, emRepo, grRepo := testSetup()
+	ctx := context.Background()
+	setupTestData(ctx, emRepo, grRepo)
+
+	_ = grRepo.Create(ctx, &domain.GroupingRule{
+		ID:                "rule-severity",
+		Name:              "By severity",
+		GroupingKey:       "severity",
+		TimeWindowMinutes: 5,
+		CreatedAt:         time.Now(),
+	})
+	em, _ := emRepo.GetByID(ctx, "em-1")
+	em.CandidateGroupingRuleID = "rule-severity"
+	_ = emRepo.Update(ctx, em)
+
+	count := func(result string) float64 {
+		var m dto.Metric
+		if err := metrics.CandidateGroupingDecisions.WithLabelValues("em-1", result).Write(&m); err != nil {
+			t.Fatalf("Write error: %v", err)
+		}
+		return m.GetCounter().GetValue()
+	}
+	matched, diverged := count("match"), count("diverged")
+
+	send := func(dedupKey, class string, severity domain.Severity) {
+		event := &domain.InternalEvent{
+			Event: domain.Event{
+				EventManagerID: "em-1",
+				Summary:        "Test alert",
+				Severity:       severity,
+				Action:         domain.ActionTrigger,
+				Class:          class,
+				DedupKey:       dedupKey,
+			},
+			GroupingValue: class,
+		}
+		payload, _ := json.Marshal(event)
+		if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+			t.Fatalf("handleMessage(%s) error: %v", dedupKey, err)
+		}
+	}
+	send("cand-a", "database", domain.SeverityHigh) // parent under both rules
+	send("cand-b", "database", domain.SeverityLow)  // child of a, candidate parent
+	send("cand-c", "network", domain.SeverityHigh)  // parent, candidate child of a
+	send("cand-d", "database", domain.SeverityHigh) // child of a under both rules
+
+	if got := count("match") - matched; got != 2 {
+		t.Errorf("matching decisions = %v, want 2", got)
+	}
+	if got := count("diverged") - diverged; got != 2 {
+		t.Errorf("diverged decisions = %v, want 2", got)
+	}
+
+	// The candidate doesn't affect the alerts
+	state, _ := stateStore.GetAlert(ctx, "cand-c")
+	if state == nil || state.Type != string(domain.AlertTypeParent) {
+		t.Errorf("cand-c state = %+v, want a parent", state)
+	}
+}
+
+func TestProcessor_TimeWindowWithFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(start)
+	service, _, stateStore, alertRepo, emRepo, grRepo := testSetupWithClock(clk)
+	ctx := context.Background()
+	setupTestData(ctx, emRepo, grRepo)
+
+	trigger := func(dedupKey string) {
+		event := &domain.InternalEvent{
+			Event: domain.Event{
+				EventManagerID: "em-1",
+				Summary:        "Test alert",
+				Severity:       domain.SeverityHigh,
+				Action:         domain.ActionTrigger,
+				Class:          "database",
+				DedupKey:       dedupKey,
+			},
+			GroupingValue: "database",
+		}
+		payload, _ := json.Marshal(event)
+		if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+			t.Fatalf("handleMessage(%s) error: %v", dedupKey, err)
+		}
+	}
+
+	// rule-1 groups for 5 minutes
+	trigger("window-parent")
+	clk.Advance(4*time.Minute + 59*time.Second)
+	trigger("window-child")
+	clk.Advance(2 * time.Second)
+	trigger("window-next")
+
+	parent, _ := alertRepo.GetByDedupKey(ctx, "window-parent")
+	if !parent.CreatedAt.Equal(start) {
+		t.Errorf("parent CreatedAt = %v, want %v", parent.CreatedAt, start)
+	}
+	if state, _ := stateStore.GetAlert(ctx, "window-child"); state == nil || state.ParentDedupKey != "window-parent" {
+		t.Errorf("window-child state = %+v, want a child of window-parent", state)
+	}
+	next, _ := alertRepo.GetByDedupKey(ctx, "window-next")
+	if !next.IsParent() {
+		t.Errorf("window-next type = %s, want parent after the window expired", next.Type)
+	}
+	if want := start.Add(5*time.Minute + time.Second); !next.CreatedAt.Equal(want) {
+		t.Errorf("window-next CreatedAt = %v, want %v", next.CreatedAt, want)
+	}
+}
+
+// FuzzProcessor_Invariants processes a random sequence of events, spread
+// over several grouping windows, and checks the resulting alerts keep the
+// invariants of testgen.CheckAlerts. The seed reproduces a failing sequence.
+func FuzzProcessor_Invariants(f *testing.F) {
+	for seed := int64(1); seed <= 8; seed++ {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, seed int64) {
+		gen := testgen.New(seed)
+		clk := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+		service, _, _, alertRepo, emRepo, grRepo := testSetupWithClock(clk)
+		ctx := context.Background()
+
+		rule := gen.GroupingRule("rule-gen")
+		em := gen.EventManager("em-gen", rule.ID)
+		if err := grRepo.Create(ctx, rule); err != nil {
+			t.Fatalf("Create rule error: %v", err)
+		}
+		if err := emRepo.Create(ctx, em); err != nil {
+			t.Fatalf("Create event manager error: %v", err)
+		}
+
+		spec := testgen.EventSpec{EventManagerID: em.ID, Keyspace: 30, Classes: 3, ResolveRatio: 0.3}
+		for i := 0; i < 200; i++ {
+			event := gen.Event(spec)
+			payload, _ := json.Marshal(&domain.InternalEvent{
+				Event:          *event,
+				GroupingRuleID: rule.ID,
+				GroupingValue:  rule.ExtractGroupingValue(event),
+			})
+			if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+				t.Fatalf("seed %d: handleMessage(%s %s) error: %v", seed, event.Action, event.DedupKey, err)
+			}
+			clk.Advance(time.Duration(gen.Float64() * float64(rule.TimeWindow()) / 4))
+		}
+
+		alerts, err := alertRepo.List(ctx, domain.AlertFilter{EventManagerID: em.ID})
+		if err != nil {
+			t.Fatalf("List error: %v", err)
+		}
+		if err := testgen.CheckAlerts(alerts); err != nil {
+			t.Errorf("seed %d (grouping by %s, %s): %v", seed, rule.GroupingKey, em.ResolutionPolicy, err)
+		}
+	})
+}
+
+func TestProcessor_ActiveAlertsGauge(t *testing.T) {
+	service, _, stateStore, _, emRepo, grRepo := testSetup()
+	ctx := context.Background()
+	setupTestData(ctx, emRepo, grRepo)
+
+	send := func(action domain.Action, dedupKey string) {
+		event := &domain.InternalEvent{
+			Event: domain.Event{
+				EventManagerID: "em-1",
+				Summary:        "Test alert",
+				Severity:       domain.SeverityHigh,
+				Action:         action,
+				Class:          "gauge",
+				DedupKey:       dedupKey,
+			},
+			GroupingValue: "gauge",
+		}
+		payload, _ := json.Marshal(event)
+		if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+			t.Fatalf("handleMessage(%s %s) error: %v", action, dedupKey, err)
+		}
+	}
+	active := func(alertType domain.AlertType) float64 {
+		var m dto.Metric
+		if err := metrics.ActiveAlerts.WithLabelValues("em-1", string(alertType)).Write(&m); err != nil {
+			t.Fatalf("Write error: %v", err)
+		}
+		return m.GetGauge().GetValue()
+	}
+	exported := func() float64 {
+		var m dto.Metric
+		if err := metrics.AlertActive.WithLabelValues("em-1", string(domain.SeverityHigh), "gauge").Write(&m); err != nil {
+			t.Fatalf("Write error: %v", err)
+		}
+		return m.GetGauge().GetValue()
+	}
+	live := func() int {
+		counts, err := stateStore.GetLiveCounts(ctx)
+		if err != nil {
+			t.Fatalf("GetLiveCounts error: %v", err)
+		}
+		total := 0
+		for _, count := range counts {
+			if count.EventManagerID == "em-1" && count.Severity == string(domain.SeverityHigh) {
+				total += count.Count
+			}
+		}
+		return total
+	}
+	// Other tests share the gauge, so start from its stored counts
+	if err := service.ReconcileActiveAlerts(ctx); err != nil {
+		t.Fatalf("ReconcileActiveAlerts error: %v", err)
+	}
+
+	send(domain.ActionTrigger, "gauge-parent")
+	send(domain.ActionTrigger, "gauge-child")
+	if parents, children := active(domain.AlertTypeParent), active(domain.AlertTypeChild); parents != 1 || children != 1 {
+		t.Errorf("after triggers: active parents = %v, children = %v, want 1 and 1", parents, children)
+	}
+	if got := exported(); got != 2 {
+		t.Errorf("after triggers: argus_alert_active = %v, want 2", got)
+	}
+	if got := live(); got != 2 {
+		t.Errorf("after triggers: live count = %d, want 2", got)
+	}
+
+	send(domain.ActionResolve, "gauge-child")
+	send(domain.ActionResolve, "gauge-parent")
+	if parents, children := active(domain.AlertTypeParent), active(domain.AlertTypeChild); parents != 0 || children != 0 {
+		t.Errorf("after resolves: active parents = %v, children = %v, want 0 and 0", parents, children)
+	}
+	if got := exported(); got != 0 {
+		t.Errorf("after resolves: argus_alert_active = %v, want 0", got)
+	}
+	if got := live(); got != 0 {
+		t.Errorf("after resolves: live count = %d, want 0", got)
+	}
+
+	send(domain.ActionTrigger, "gauge-child")
+	if children := active(domain.AlertTypeChild); children != 1 {
+		t.Errorf("after reactivation: active children = %v, want 1", children)
+	}
+
+	// Drift is corrected from the repository
+	metrics.ActiveAlerts.WithLabelValues("em-1", string(domain.AlertTypeParent)).Set(42)
+	metrics.AlertActive.WithLabelValues("em-1", string(domain.SeverityHigh), "gauge").Set(42)
+	_ = stateStore.AdjustLiveCount(ctx, "em-1", string(domain.SeverityHigh), 41)
+	if err := service.ReconcileActiveAlerts(ctx); err != nil {
+		t.Fatalf("ReconcileActiveAlerts error: %v", err)
+	}
+	if parents, children := active(domain.AlertTypeParent), active(domain.AlertTypeChild); parents != 0 || children != 1 {
+		t.Errorf("after reconciliation: active parents = %v, children = %v, want 0 and 1", parents, children)
+	}
+	if got := exported(); got != 1 {
+		t.Errorf("after reconciliation: argus_alert_active = %v, want 1", got)
+	}
+	if got := live(); got != 1 {
+		t.Errorf("after reconciliation: live count = %d, want 1", got)
+	}
+}
+
+func TestProcessor_ReconcileMutedAlerts(t *testing.T) {
+	service, _, _, alertRepo, _, _ := testSetup()
+	ctx := context.Background()
+	now := time.Now().UTC()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	for i, dedupKey := range []string{"muted-db-1", "muted-db-2", "muted-web-1"} {
+		severity := domain.SeverityHigh
+		if i == 0 {
+			severity = domain.SeverityLow
+		}
+		alert := &domain.Alert{ID: dedupKey, DedupKey: dedupKey, EventManagerID: "em-1", Severity: severity,
+			Type: domain.AlertTypeParent, Status: domain.AlertStatusActive, CreatedAt: now, UpdatedAt: now}
+		if err := alertRepo.Create(ctx, alert); err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+	}
+	muted := func(status domain.AlertStatus) float64 {
+		var m dto.Metric
+		if err := metrics.MutedAlerts.WithLabelValues("em-1", string(status)).Write(&m); err != nil {
+			t.Fatalf("Write error: %v", err)
+		}
+		return m.GetGauge().GetValue()
+	}
+
+	silences := storemem.NewSilenceRepository()
+	scheduler := silence.NewScheduler(silences, time.Minute, clock.Real{}, logger)
+	service.muter = notification.NewMuter(scheduler, nil)
+
+	tests := []struct {
+		name  string
+		match domain.SilenceMatcher
+		want  float64
+	}{
+		// Counted from the active alert counts
+		{"by severity", domain.SilenceMatcher{EventManagerID: "em-1", Severities: []domain.Severity{domain.SeverityLow}}, 1},
+		// Counted by the repository
+		{"by dedup key", domain.SilenceMatcher{DedupKeyPattern: "muted-db-*"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &domain.Silence{ID: tt.name, Match: tt.match, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}
+			if err := silences.Create(ctx, s); err != nil {
+				t.Fatalf("Create error: %v", err)
+			}
+			defer func() { _ = silences.Delete(ctx, s.ID) }()
+			if err := scheduler.Refresh(ctx); err != nil {
+				t.Fatalf("Refresh error: %v", err)
+			}
+
+			if err := service.ReconcileActiveAlerts(ctx); err != nil {
+				t.Fatalf("ReconcileActiveAlerts error: %v", err)
+			}
+			if got := muted(domain.AlertStatusSilenced); got != tt.want {
+				t.Errorf("argus_muted_alerts{status=silenced} = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessor_LifecycleNotifications(t *testing.T) {
+	_, _, stateStore, alertRepo, emRepo, grRepo := testSetup()
+	ctx := context.Background()
+	setupTestData(ctx, emRepo, grRepo)
+	em, _ := emRepo.GetByID(ctx, "em-1")
+	em.NotificationConfig = domain.NotificationConfig{
+		ReminderIntervalMinutes: 10,
+		MaxReminders:            1,
+		Events:                  domain.NotificationEvents{ChildAdded: true, Reactivated: true, Acknowledged: true, Escalated: true},
+	}
+	_ = emRepo.Update(ctx, em)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	notificationLog := storemem.NewNotificationLogRepository()
+	service := NewService(
+		memory.NewQueue(1),
+		stateStore,
+		alertRepo,
+		emRepo,
+		grRepo,
+		notification.NewRecordingNotifier(notification.NewStubNotifier(logger), notificationLog, logger),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		config.DedupConfig{},
+		config.ProcessorConfig{},
+		clock.Real{},
+		logger,
+	)
+
+	send := func(action domain.Action, dedupKey string) {
+		event := &domain.InternalEvent{
+			Event: domain.Event{
+				EventManagerID: "em-1",
+				Summary:        "Test alert",
+				Severity:       domain.SeverityHigh,
+				Action:         action,
+				Class:          "database",
+				DedupKey:       dedupKey,
+			},
+			GroupingValue: "database",
+		}
+		payload, _ := json.Marshal(event)
+		if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+			t.Fatalf("handleMessage(%s %s) error: %v", action, dedupKey, err)
+		}
+	}
+	kinds := func(dedupKey string) []domain.NotificationKind {
+		records, err := notificationLog.ListByDedupKey(ctx, dedupKey)
+		if err != nil {
+			t.Fatalf("ListByDedupKey error: %v", err)
+		}
+		var kinds []domain.NotificationKind
+		for _, record := range records {
+			kinds = append(kinds, record.Kind)
+		}
+		return kinds
+	}
+	assertKinds := func(dedupKey string, want ...domain.NotificationKind) {
+		t.Helper()
+		if got := kinds(dedupKey); !slices.Equal(got, want) {
+			t.Errorf("notifications of %s = %v, want %v", dedupKey, got, want)
+		}
+	}
+
+	send(domain.ActionTrigger, "lc-parent")
+	send(domain.ActionTrigger, "lc-child")
+	assertKinds("lc-child", domain.NotificationChildAdded)
+
+	// Only the first acknowledgement is notified
+	for range 2 {
+		if _, err := service.Acknowledge(ctx, "lc-child"); err != nil {
+			t.Fatalf("Acknowledge error: %v", err)
+		}
+	}
+	assertKinds("lc-child", domain.NotificationChildAdded, domain.NotificationAcknowledged)
+
+	send(domain.ActionResolve, "lc-child")
+	if _, err := service.Acknowledge(ctx, "lc-child"); !errors.Is(err, domain.ErrAlertAlreadyResolved) {
+		t.Errorf("Acknowledge(resolved) error = %v, want %v", err, domain.ErrAlertAlreadyResolved)
+	}
+	send(domain.ActionTrigger, "lc-child")
+	assertKinds("lc-child", domain.NotificationChildAdded, domain.NotificationAcknowledged, domain.NotificationReactivated)
+
+	// The last reminder escalates the unacknowledged parent
+	if _, err := service.SendDueReminders(ctx, time.Now().Add(11*time.Minute)); err != nil {
+		t.Fatalf("SendDueReminders error: %v", err)
+	}
+	assertKinds("lc-parent", domain.NotificationNewParent, domain.NotificationReminder, domain.NotificationEscalated)
+
+	// Event managers that haven't opted in are not notified
+	em.NotificationConfig.Events = domain.NotificationEvents{}
+	_ = emRepo.Update(ctx, em)
+	send(domain.ActionTrigger, "lc-quiet")
+	if _, err := service.Acknowledge(ctx, "lc-quiet"); err != nil {
+		t.Fatalf("Acknowledge error: %v", err)
+	}
+	assertKinds("lc-quiet")
+}
+
+func TestProcessor_ImportAlerts(t *testing.T) {
+	_, _, stateStore, alertRepo, emRepo, grRepo := testSetup()
+	ctx := context.Background()
+	setupTestData(ctx, emRepo, grRepo)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	notificationLog := storemem.NewNotificationLogRepository()
+	service := NewService(
+		memory.NewQueue(1),
+		stateStore,
+		alertRepo,
+		emRepo,
+		grRepo,
+		notification.NewRecordingNotifier(notification.NewStubNotifier(logger), notificationLog, logger),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		config.DedupConfig{},
+		config.ProcessorConfig{},
+		clock.Real{},
+		logger,
+	)
+
+	created := time.Now().UTC().Add(-48 * time.Hour).Truncate(time.Second)
+	resolved := created.Add(time.Hour)
+	req := &domain.AlertImportRequest{Alerts: []domain.AlertImportRecord{
+		{DedupKey: "imp-child", EventManagerID: "em-1", Summary: "Disk full", Type: domain.AlertTypeChild, ParentDedupKey: "imp-parent", CreatedAt: created, ResolvedAt: &resolved},
+		{DedupKey: "imp-parent", EventManagerID: "em-1", Summary: "Database down", Severity: domain.SeverityHigh, CreatedAt: created, ResolvedAt: &resolved},
+		{DedupKey: "imp-active", EventManagerID: "em-1", Summary: "Latency high", CreatedAt: created},
+	}}
+
+	result, err := service.ImportAlerts(ctx, req)
+	if err != nil {
+		t.Fatalf("ImportAlerts error: %v", err)
+	}
+	if result.Imported != 3 || len(result.Skipped) != 0 {
+		t.Errorf("ImportAlerts() = %+v, want 3 imported", result)
+	}
+
+	parent, err := alertRepo.GetByDedupKey(ctx, "imp-parent")
+	if err != nil {
+		t.Fatalf("GetByDedupKey error: %v", err)
+	}
+	if !parent.CreatedAt.Equal(created) || !parent.UpdatedAt.Equal(resolved) {
+		t.Errorf("parent timestamps = %v, %v, want %v, %v", parent.CreatedAt, parent.UpdatedAt, created, resolved)
+	}
+	if parent.ChildCount != 1 || parent.Resolution == nil || parent.Resolution.ResolvedBy != domain.ResolvedByImport {
+		t.Errorf("parent = %+v, want 1 child resolved by import", parent)
+	}
+	alerts, err := alertRepo.List(ctx, domain.AlertFilter{EventManagerID: "em-1"})
+	if err != nil {
+		t.Fatalf("List error: %v", err)
+	}
+	if err := testgen.CheckAlerts(alerts); err != nil {
+		t.Errorf("imported alerts: %v", err)
+	}
+
+	// Imports are not notified
+	for _, key := range []string{"imp-parent", "imp-child", "imp-active"} {
+		if records, _ := notificationLog.ListByDedupKey(ctx, key); len(records) != 0 {
+			t.Errorf("notifications of %s = %d, want none", key, len(records))
+		}
+	}
+
+	// Importing again skips existing alerts and their children
+	result, err = service.ImportAlerts(ctx, req)
+	if err != nil {
+		t.Fatalf("ImportAlerts error: %v", err)
+	}
+	if result.Imported != 0 || len(result.Skipped) != 3 {
+		t.Errorf("ImportAlerts() again = %+v, want 3 skipped", result)
+	}
+
+	// Later events deduplicate against imported alerts
+	event := &domain.InternalEvent{
+		Event: domain.Event{
+			EventManagerID: "em-1",
+			Summary:        "Database down",
+			Severity:       domain.SeverityHigh,
+			Action:         domain.ActionTrigger,
+			Class:          "database",
+			DedupKey:       "imp-parent",
+		},
+		GroupingValue: "database",
+	}
+	payload, _ := json.Marshal(event)
+	if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+		t.Fatalf("handleMessage error: %v", err)
+	}
+	parent, _ = alertRepo.GetByDedupKey(ctx, "imp-parent")
+	if !parent.IsActive() || parent.TriggerCount != 2 {
+		t.Errorf("parent after trigger = %+v, want reactivated", parent)
+	}
+
+	// Unknown event managers reject the whole import
+	req = &domain.AlertImportRequest{Alerts: []domain.AlertImportRecord{
+		{DedupKey: "imp-other", EventManagerID: "em-missing", Summary: "Other", CreatedAt: created},
+	}}
+	if _, err := service.ImportAlerts(ctx, req); !errors.Is(err, domain.ErrInvalidImport) {
+		t.Errorf("ImportAlerts(unknown event manager) error = %v, want %v", err, domain.ErrInvalidImport)
+	}
+}
+
+func TestProcessor_ImportAlerts_EvictedState(t *testing.T) {
+	service, _, stateStore, alertRepo, emRepo, grRepo := testSetup()
+	ctx := context.Background()
+	setupTestData(ctx, emRepo, grRepo)
+
+	created := time.Now().UTC().Add(-48 * time.Hour).Truncate(time.Second)
+	req := &domain.AlertImportRequest{Alerts: []domain.AlertImportRecord{
+		{DedupKey: "imp-evicted", EventManagerID: "em-1", Summary: "Latency high", CreatedAt: created},
+	}}
+	if _, err := service.ImportAlerts(ctx, req); err != nil {
+		t.Fatalf("ImportAlerts error: %v", err)
+	}
+
+	// An alert the state store no longer holds is still skipped
+	if err := stateStore.DeleteAlert(ctx, "imp-evicted"); err != nil {
+		t.Fatalf("DeleteAlert error: %v", err)
+	}
+	result, err := service.ImportAlerts(ctx, req)
+	if err != nil {
+		t.Fatalf("ImportAlerts error: %v", err)
+	}
+	if result.Imported != 0 || len(result.Skipped) != 1 {
+		t.Errorf("ImportAlerts() again = %+v, want 1 skipped", result)
+	}
+	if n, _ := alertRepo.Count(ctx, domain.AlertFilter{EventManagerID: "em-1"}); n != 1 {
+		t.Errorf("alerts = %d, want 1", n)
+	}
+}
+
+func TestProcessor_PatchAlert(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 10, 15, 14, 30, 0, 0, time.UTC))
+	service, _, _, alertRepo, emRepo, grRepo := testSetupWithClock(clk)
+	ctx := context.Background()
+	setupTestData(ctx, emRepo, grRepo)
+
+	event := &domain.InternalEvent{
+		Event: domain.Event{
+			EventManagerID: "em-1",
+			Summary:        "Disk full",
+			Severity:       domain.SeverityLow,
+			Action:         domain.ActionTrigger,
+			Class:          "database",
+			DedupKey:       "patch-1",
+			Tags:           map[string]string{"host": "db-1", "pod": "api-7"},
+		},
+		GroupingValue: "database",
+	}
+	payload, _ := json.Marshal(event)
+	if err := service.handleMessage(ctx, &queue.Message{Value: payload}); err != nil {
+		t.Fatalf("handleMessage error: %v", err)
+	}
+	original, _ := alertRepo.GetByDedupKey(ctx, "patch-1")
+
+	severity, assignee, region := domain.SeverityHigh, "alice", "eu"
+	clk.Advance(time.Minute)
+	patched, err := service.PatchAlert(ctx, "patch-1", &domain.AlertPatch{
+		UpdatedAt: &original.UpdatedAt,
+		Severity:  &severity,
+		Assignee:  &assignee,
+		Tags:      map[string]*string{"region": &region, "pod": nil},
+	})
+	if err != nil {
+		t.Fatalf("PatchAlert error: %v", err)
+	}
+	stored, _ := alertRepo.GetByDedupKey(ctx, "patch-1")
+	for _, alert := range []*domain.Alert{patched, stored} {
+		if alert.Severity != domain.SeverityHigh || alert.Assignee != "alice" || len(alert.Tags) != 2 || alert.Tags["region"] != "eu" {
+			t.Errorf("patched alert = %+v", alert)
+		}
+	}
+
+	// A patch made from the original alert was overtaken by the first one
+	if _, err := service.PatchAlert(ctx, "patch-1", &domain.AlertPatch{UpdatedAt: &original.UpdatedAt, Assignee: new(string)}); !errors.Is(err, domain.ErrAlertModified) {
+		t.Errorf("PatchAlert() with a stale updated_at error = %v, want ErrAlertModified", err)
+	}
+	// Without updated_at the latest alert is patched
+	if alert, err := service.PatchAlert(ctx, "patch-1", &domain.AlertPatch{Assignee: new(string)}); err != nil || alert.Assignee != "" {
+		t.Errorf("PatchAlert() = %+v, %v, want the alert unassigned", alert, err)
+	}
+	if _, err := service.PatchAlert(ctx, "missing", &domain.AlertPatch{Assignee: new(string)}); !errors.Is(err, domain.ErrAlertNotFound) {
+		t.Errorf("PatchAlert() of a missing alert error = %v, want ErrAlertNotFound", err)
+	}
 }
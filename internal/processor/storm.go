@@ -0,0 +1,249 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+	"argus-go/internal/store"
+)
+
+// storm is an ongoing alert storm of an event manager.
+type storm struct {
+	startedAt time.Time
+
+	// calmSince is when the rate last fell to or below the threshold; it is
+	// zero while the rate is above.
+	calmSince time.Time
+}
+
+// stormDetector counts the events of every event manager between rate
+// measurements and tracks the event managers in a storm. State is kept per
+// replica, so each replica measures the events it processes.
+type stormDetector struct {
+	mu         sync.Mutex
+	events     map[string]int
+	measuredAt time.Time
+	storms     map[string]*storm
+}
+
+// newStormDetector creates a detector measuring rates from now.
+func newStormDetector(now time.Time) *stormDetector {
+	return &stormDetector{
+		events:     make(map[string]int),
+		measuredAt: now,
+		storms:     make(map[string]*storm),
+	}
+}
+
+// record counts events of an event manager toward its next rate measurement.
+func (d *stormDetector) record(eventManagerID string, events int) {
+	d.mu.Lock()
+	d.events[eventManagerID] += events
+	d.mu.Unlock()
+}
+
+// active returns true if the event manager is in a storm.
+func (d *stormDetector) active(eventManagerID string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.storms[eventManagerID]
+	return ok
+}
+
+// measure returns the events counted per event manager since the last
+// measurement, including every event manager in a storm, and the time
+// elapsed, and starts the next measurement at now.
+func (d *stormDetector) measure(now time.Time) (map[string]int, time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	events := d.events
+	for id := range d.storms {
+		if _, ok := events[id]; !ok {
+			events[id] = 0
+		}
+	}
+	elapsed := now.Sub(d.measuredAt)
+	d.events = make(map[string]int)
+	d.measuredAt = now
+	return events, elapsed
+}
+
+// StartStormDetection measures event rates every interval until the context
+// is canceled. Ticks are skipped while the processor is paused.
+func (s *Service) StartStormDetection(ctx context.Context, interval time.Duration) {
+	s.logger.InfoContext(ctx, "starting storm detection", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s.Status().Paused {
+				continue
+			}
+			if err := s.CheckStorms(ctx); err != nil && ctx.Err() == nil {
+				s.logger.WarnContext(ctx, "failed to check storms", "error", err)
+			}
+		}
+	}
+}
+
+// CheckStorms measures the event rate of every event manager since the last
+// check. An event manager above its threshold enters a storm, opening its
+// storm alert; one in a storm whose rate stayed at or below the threshold
+// for the quiet period leaves it, resolving the storm alert. The storm
+// alert then waits for its children to resolve, like any parent.
+func (s *Service) CheckStorms(ctx context.Context) error {
+	now := s.now()
+	events, elapsed := s.storms.measure(now)
+	if elapsed <= 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(events))
+	for id := range events {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var errs []error
+	for _, id := range ids {
+		rate := float64(events[id]) / elapsed.Seconds()
+		if err := s.checkStorm(ctx, id, rate, now); err != nil {
+			errs = append(errs, fmt.Errorf("event manager %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkStorm starts or ends the storm of an event manager by its event rate.
+func (s *Service) checkStorm(ctx context.Context, eventManagerID string, rate float64, now time.Time) error {
+	em, err := s.eventManagerRepo.GetByID(ctx, eventManagerID)
+	if errors.Is(err, domain.ErrEventManagerNotFound) {
+		s.forgetStorm(eventManagerID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	cfg := s.stormConfig
+	threshold := em.Storm.Threshold(cfg.EventsPerSecond)
+	above := threshold > 0 && rate > threshold && !em.IsDeleted()
+
+	s.storms.mu.Lock()
+	current, inStorm := s.storms.storms[eventManagerID]
+	if inStorm {
+		switch {
+		case above:
+			current.calmSince = time.Time{}
+		case current.calmSince.IsZero():
+			current.calmSince = now
+		}
+	}
+	s.storms.mu.Unlock()
+
+	switch {
+	case !inStorm && above:
+		return s.startStorm(ctx, em, rate, threshold, now)
+	case inStorm && !above && now.Sub(current.calmSince) >= em.Storm.Quiet(cfg.QuietPeriod):
+		return s.endStorm(ctx, em, now)
+	}
+	return nil
+}
+
+// startStorm opens the storm alert of an event manager, or reactivates it if
+// an earlier storm resolved it, and groups the new alerts of the event
+// manager under it from then on. Another replica may have opened it already.
+func (s *Service) startStorm(ctx context.Context, em *domain.EventManager, rate, threshold float64, now time.Time) error {
+	event := &domain.InternalEvent{
+		Event: domain.Event{
+			EventManagerID: em.ID,
+			Summary: fmt.Sprintf("Alert storm: %.1f events/s for event manager %s, above %g; new alerts are grouped under this alert",
+				rate, em.Name, threshold),
+			Severity: domain.SeverityHigh,
+			Action:   domain.ActionTrigger,
+			Class:    domain.StormClass,
+			DedupKey: domain.StormDedupKey(em.ID),
+		},
+	}
+
+	state, err := s.stateStore.GetAlert(ctx, event.DedupKey)
+	if err != nil {
+		return err
+	}
+	switch {
+	case state == nil:
+		err = s.createParentAlert(ctx, event, nil, em)
+	case state.Status == string(domain.AlertStatusResolved):
+		err = s.reactivateAlert(ctx, event, state)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.storms.mu.Lock()
+	s.storms.storms[em.ID] = &storm{startedAt: now}
+	s.storms.mu.Unlock()
+	metrics.StormActive.WithLabelValues(em.ID).Set(1)
+
+	s.logger.WarnContext(ctx, "event manager entered an alert storm",
+		"event_manager_id", em.ID,
+		"events_per_second", rate,
+		"threshold", threshold,
+	)
+	return nil
+}
+
+// endStorm resolves the storm alert of an event manager; its new alerts are
+// grouped as usual again.
+func (s *Service) endStorm(ctx context.Context, em *domain.EventManager, now time.Time) error {
+	event := &domain.InternalEvent{
+		Event: domain.Event{
+			EventManagerID: em.ID,
+			Action:         domain.ActionResolve,
+			DedupKey:       domain.StormDedupKey(em.ID),
+		},
+	}
+	if err := s.handleResolve(ctx, event); err != nil {
+		return err
+	}
+
+	ended := s.forgetStorm(em.ID)
+	s.logger.InfoContext(ctx, "event manager left an alert storm",
+		"event_manager_id", em.ID,
+		"duration", now.Sub(ended.startedAt),
+	)
+	return nil
+}
+
+// forgetStorm stops tracking the storm of an event manager and returns it,
+// or nil if the event manager was not in a storm.
+func (s *Service) forgetStorm(eventManagerID string) *storm {
+	s.storms.mu.Lock()
+	ended, ok := s.storms.storms[eventManagerID]
+	delete(s.storms.storms, eventManagerID)
+	s.storms.mu.Unlock()
+
+	if ok {
+		metrics.StormActive.WithLabelValues(eventManagerID).Set(0)
+	}
+	return ended
+}
+
+// createStormChild creates a new alert of an event manager in a storm as a
+// child of its storm alert.
+func (s *Service) createStormChild(ctx context.Context, event *domain.InternalEvent, em *domain.EventManager) error {
+	parent := &store.ParentState{DedupKey: domain.StormDedupKey(em.ID)}
+	return s.createChildAlert(ctx, event, parent, nil, em)
+}
@@ -0,0 +1,38 @@
+package processor
+
+import (
+	"context"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+)
+
+// dispatchTransitions hands the transitions of an alert, once persisted, to
+// their consumers. Every transition is counted; with the event manager of
+// the alert, acknowledgements, reactivations and the resolves of parents are
+// notified to it and to the watchers of the alert. em is nil if it couldn't
+// be loaded. Nil transitions, for operations that changed nothing, are
+// skipped.
+func (s *Service) dispatchTransitions(ctx context.Context, alert *domain.Alert, em *domain.EventManager, transitions ...*domain.AlertTransition) {
+	for _, t := range transitions {
+		if t == nil {
+			continue
+		}
+		metrics.AlertTransitions.WithLabelValues(string(t.Kind), string(t.To)).Inc()
+		if em == nil {
+			continue
+		}
+
+		switch t.Kind {
+		case domain.TransitionAcknowledge:
+			s.notifyLifecycle(ctx, alert, em, domain.NotificationAcknowledged)
+		case domain.TransitionReactivate:
+			s.notifyLifecycle(ctx, alert, em, domain.NotificationReactivated)
+		case domain.TransitionResolve:
+			if alert.IsParent() {
+				s.notifier.NotifyResolved(ctx, alert, em)
+				s.watchers.Notify(ctx, alert, em, domain.NotificationResolved)
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package buffered provides a queue.Producer that publishes in the
+// background, so callers don't wait for a slow queue.
+package buffered
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"argus-go/internal/metrics"
+	"argus-go/internal/queue"
+)
+
+// Errors returned by Publish.
+var (
+	// ErrBufferFull is returned while the buffer holds as many messages as
+	// it can, i.e. while the queue can't keep up.
+	ErrBufferFull = errors.New("publish buffer is full")
+
+	// ErrClosed is returned once the producer is closed.
+	ErrClosed = errors.New("producer is closed")
+)
+
+// Backoff between the attempts to publish a message the queue failed.
+const (
+	minRetryBackoff = 100 * time.Millisecond
+	maxRetryBackoff = 5 * time.Second
+)
+
+// Producer implements queue.Producer by adding messages to a bounded buffer,
+// which a single goroutine publishes to the next producer in order. A
+// message the queue fails is retried with backoff until it is published, so
+// the messages after it wait, and the buffer filling up rejects new ones.
+type Producer struct {
+	next   queue.Producer
+	logger *slog.Logger
+
+	// mu guards sending to the buffer against closing it.
+	mu     sync.RWMutex
+	closed bool
+	buffer chan *queue.Message
+
+	// stopping is closed by Close, ending the retries of a failing message.
+	stopping chan struct{}
+	done     chan struct{}
+}
+
+// NewProducer starts publishing the messages buffered, at most size, to next.
+func NewProducer(next queue.Producer, size int, logger *slog.Logger) *Producer {
+	p := &Producer{
+		next:     next,
+		logger:   logger,
+		buffer:   make(chan *queue.Message, size),
+		stopping: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Publish buffers a message, returning ErrBufferFull if the buffer has no
+// room. A nil error means the message will be published, not that it was.
+func (p *Producer) Publish(_ context.Context, msg *queue.Message) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+	select {
+	case p.buffer <- msg:
+		metrics.PublishBufferDepth.Set(float64(len(p.buffer)))
+		return nil
+	default:
+		metrics.PublishBufferRejected.Inc()
+		return ErrBufferFull
+	}
+}
+
+// Close stops accepting messages, publishes those buffered, and closes the
+// next producer. Messages the queue fails while closing, including one being
+// retried, get no further attempt: they are logged, counted by
+// metrics.PublishBufferDropped, and dropped.
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.buffer)
+		close(p.stopping)
+	}
+	p.mu.Unlock()
+
+	<-p.done
+	return p.next.Close()
+}
+
+// run publishes the buffered messages until the buffer is closed and empty.
+func (p *Producer) run() {
+	defer close(p.done)
+
+	for msg := range p.buffer {
+		metrics.PublishBufferDepth.Set(float64(len(p.buffer)))
+		p.publish(msg)
+	}
+}
+
+// publish publishes a message, retrying with backoff until it succeeds or
+// the producer is closing.
+func (p *Producer) publish(msg *queue.Message) {
+	backoff := minRetryBackoff
+	for {
+		err := p.next.Publish(context.Background(), msg)
+		if err == nil {
+			return
+		}
+		metrics.PublishBufferFailures.Inc()
+
+		select {
+		case <-p.stopping:
+			metrics.PublishBufferDropped.Inc()
+			p.logger.Error("dropping buffered message on close", "dedupKey", msg.Headers[queue.DedupKeyHeader], "error", err)
+			return
+		default:
+		}
+		p.logger.Warn("failed to publish buffered message, retrying", "dedupKey", msg.Headers[queue.DedupKeyHeader], "backoff", backoff, "error", err)
+
+		select {
+		case <-time.After(backoff):
+		case <-p.stopping:
+		}
+		backoff = min(2*backoff, maxRetryBackoff)
+	}
+}
@@ -0,0 +1,134 @@
+package buffered
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"argus-go/internal/metrics"
+	"argus-go/internal/queue"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// blockingProducer records published messages, failing the first failures
+// attempts, and blocks each publish until released.
+type blockingProducer struct {
+	release  chan struct{}
+	failures int
+
+	mu        sync.Mutex
+	published []string
+	closed    bool
+}
+
+func (p *blockingProducer) Publish(_ context.Context, msg *queue.Message) error {
+	<-p.release
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, string(msg.Value))
+	return nil
+}
+
+func (p *blockingProducer) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	return nil
+}
+
+func TestProducer_BuffersAndPublishesInOrder(t *testing.T) {
+	ctx := context.Background()
+	next := &blockingProducer{release: make(chan struct{}), failures: 1}
+	p := NewProducer(next, 2, testLogger)
+
+	// The publisher takes the first message and blocks on it, so the
+	// buffer holds two more before rejecting
+	for _, value := range []string{"a", "b", "c"} {
+		if err := p.Publish(ctx, &queue.Message{Value: []byte(value)}); err != nil {
+			t.Fatalf("Publish(%s) error = %v", value, err)
+		}
+		if value == "a" {
+			waitFor(t, func() bool { return len(p.buffer) == 0 })
+		}
+	}
+	if err := p.Publish(ctx, &queue.Message{Value: []byte("d")}); !errors.Is(err, ErrBufferFull) {
+		t.Fatalf("Publish() on a full buffer error = %v, want ErrBufferFull", err)
+	}
+
+	// The failed attempt of the first message is retried before the others
+	close(next.release)
+	waitFor(t, func() bool {
+		next.mu.Lock()
+		defer next.mu.Unlock()
+		return len(next.published) == 3
+	})
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got := next.published; len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
+		t.Errorf("published = %v, want [a b c]", got)
+	}
+	if !next.closed {
+		t.Error("Close() should close the next producer")
+	}
+	if err := p.Publish(ctx, &queue.Message{Value: []byte("e")}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Publish() after Close error = %v, want ErrClosed", err)
+	}
+}
+
+func TestProducer_CloseDropsFailingMessages(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	close(release)
+	next := &blockingProducer{release: release, failures: 1000}
+	p := NewProducer(next, 2, testLogger)
+	failures := testutil.ToFloat64(metrics.PublishBufferFailures)
+	dropped := testutil.ToFloat64(metrics.PublishBufferDropped)
+
+	// The first message is being retried, holding back the second
+	for _, value := range []string{"a", "b"} {
+		if err := p.Publish(ctx, &queue.Message{Value: []byte(value)}); err != nil {
+			t.Fatalf("Publish(%s) error = %v", value, err)
+		}
+	}
+	waitFor(t, func() bool { return testutil.ToFloat64(metrics.PublishBufferFailures) > failures })
+
+	// Closing ends the retries and gives the second message one attempt;
+	// both are dropped and counted
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got := testutil.ToFloat64(metrics.PublishBufferDropped) - dropped; got != 2 {
+		t.Errorf("dropped messages = %v, want 2", got)
+	}
+	if len(next.published) != 0 {
+		t.Errorf("published = %v, want none", next.published)
+	}
+	if !next.closed {
+		t.Error("Close() should close the next producer")
+	}
+}
+
+// waitFor polls cond until it holds, failing the test after a second.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,153 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"time"
+)
+
+// HeaderSchemaVersion is the version of the header schema of event messages
+// written by this build. Messages without a version predate the schema.
+const HeaderSchemaVersion = 1
+
+// Header names of event messages. Values are strings; timestamps are
+// RFC 3339 with nanoseconds in UTC.
+const (
+	SchemaVersionHeader = "argus_schema_version"
+	EventManagerHeader  = "event_manager_id"
+	ActionHeader        = "action"
+	DedupKeyHeader      = "dedupKey"
+	ReceivedAtHeader    = "argus_received_at"
+	RetryCountHeader    = "argus_retry_count"
+
+	// CorrelationIDHeader carries the correlation ID of an event, so its
+	// logs can be followed across components.
+	CorrelationIDHeader = "correlation_id"
+
+	// TraceParentHeader and TraceStateHeader carry the W3C trace context
+	// of the request the event was ingested from.
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+)
+
+// ErrUnsupportedSchema is returned for headers of a newer schema version
+// than this build knows.
+var ErrUnsupportedSchema = errors.New("unsupported header schema version")
+
+// Headers are the headers of an event message, so consumers, tooling and
+// operators can route, trace and inspect events without decoding their
+// payload. The payload stays the source of truth for the event itself.
+type Headers struct {
+	// SchemaVersion is HeaderSchemaVersion for messages written by this
+	// build, and 0 for messages that predate the schema.
+	SchemaVersion int
+
+	// EventManagerID identifies the event manager, the tenant, the event
+	// belongs to.
+	EventManagerID string
+
+	Action   string
+	DedupKey string
+
+	// CorrelationID identifies the request or message the event was
+	// ingested from in logs.
+	CorrelationID string
+
+	// TraceParent and TraceState are the W3C trace context of the ingest
+	// request, if it had one.
+	TraceParent string
+	TraceState  string
+
+	// ReceivedAt is when the ingest service received the event.
+	ReceivedAt time.Time
+
+	// RetryCount is how often the message was published again after it
+	// failed, e.g. from a dead letter queue.
+	RetryCount int
+}
+
+// Encode returns the headers as message headers, with the schema version of
+// this build. Empty fields are left out.
+func (h Headers) Encode() map[string]string {
+	headers := map[string]string{
+		SchemaVersionHeader: strconv.Itoa(HeaderSchemaVersion),
+	}
+	set := func(name, value string) {
+		if value != "" {
+			headers[name] = value
+		}
+	}
+	set(EventManagerHeader, h.EventManagerID)
+	set(ActionHeader, h.Action)
+	set(DedupKeyHeader, h.DedupKey)
+	set(CorrelationIDHeader, h.CorrelationID)
+	set(TraceParentHeader, h.TraceParent)
+	set(TraceStateHeader, h.TraceState)
+	if !h.ReceivedAt.IsZero() {
+		headers[ReceivedAtHeader] = h.ReceivedAt.UTC().Format(time.RFC3339Nano)
+	}
+	if h.RetryCount > 0 {
+		headers[RetryCountHeader] = strconv.Itoa(h.RetryCount)
+	}
+	return headers
+}
+
+// DecodeHeaders reads the headers of an event message. Messages that predate
+// the schema decode with version 0 and the fields they have. Malformed
+// fields, or a schema newer than this build, return an error along with the
+// fields that could be read.
+func DecodeHeaders(headers map[string]string) (Headers, error) {
+	h := Headers{
+		EventManagerID: headers[EventManagerHeader],
+		Action:         headers[ActionHeader],
+		DedupKey:       headers[DedupKeyHeader],
+		CorrelationID:  headers[CorrelationIDHeader],
+		TraceParent:    headers[TraceParentHeader],
+		TraceState:     headers[TraceStateHeader],
+	}
+
+	var errs []error
+	if v, ok := headers[SchemaVersionHeader]; ok {
+		version, err := strconv.Atoi(v)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("invalid %s %q", SchemaVersionHeader, v))
+		case version > HeaderSchemaVersion:
+			errs = append(errs, fmt.Errorf("%w: %d", ErrUnsupportedSchema, version))
+		}
+		h.SchemaVersion = version
+	}
+	if v, ok := headers[ReceivedAtHeader]; ok {
+		receivedAt, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s %q", ReceivedAtHeader, v))
+		}
+		h.ReceivedAt = receivedAt
+	}
+	if v, ok := headers[RetryCountHeader]; ok {
+		count, err := strconv.Atoi(v)
+		if err != nil || count < 0 {
+			errs = append(errs, fmt.Errorf("invalid %s %q", RetryCountHeader, v))
+		} else {
+			h.RetryCount = count
+		}
+	}
+	return h, errors.Join(errs...)
+}
+
+// Retry returns a copy of a message to publish again after it failed, e.g.
+// from a dead letter queue, with its retry count incremented. The other
+// headers are kept as they are.
+func Retry(msg *Message) *Message {
+	h, _ := DecodeHeaders(msg.Headers)
+
+	retried := *msg
+	retried.Headers = maps.Clone(msg.Headers)
+	if retried.Headers == nil {
+		retried.Headers = make(map[string]string)
+	}
+	retried.Headers[RetryCountHeader] = strconv.Itoa(h.RetryCount + 1)
+	return &retried
+}
@@ -0,0 +1,57 @@
+package queue
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHeaders_RoundTrip(t *testing.T) {
+	h := Headers{
+		SchemaVersion:  HeaderSchemaVersion,
+		EventManagerID: "em-1",
+		Action:         "trigger",
+		DedupKey:       "disk-full",
+		CorrelationID:  "req-1",
+		TraceParent:    "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		TraceState:     "vendor=value",
+		ReceivedAt:     time.Date(2026, 10, 15, 14, 30, 0, 123456789, time.UTC),
+		RetryCount:     2,
+	}
+	got, err := DecodeHeaders(h.Encode())
+	if err != nil {
+		t.Fatalf("DecodeHeaders() error = %v", err)
+	}
+	if got != h {
+		t.Errorf("DecodeHeaders() = %+v, want %+v", got, h)
+	}
+}
+
+func TestDecodeHeaders(t *testing.T) {
+	// Messages from before the schema decode as version 0
+	legacy, err := DecodeHeaders(map[string]string{"event_manager_id": "em-1", "dedupKey": "a"})
+	if err != nil || legacy.SchemaVersion != 0 || legacy.EventManagerID != "em-1" || legacy.DedupKey != "a" {
+		t.Errorf("DecodeHeaders(legacy) = %+v, %v", legacy, err)
+	}
+
+	newer, err := DecodeHeaders(map[string]string{SchemaVersionHeader: "2", DedupKeyHeader: "a"})
+	if !errors.Is(err, ErrUnsupportedSchema) || newer.DedupKey != "a" {
+		t.Errorf("DecodeHeaders(version 2) = %+v, %v, want ErrUnsupportedSchema and the known fields", newer, err)
+	}
+
+	if _, err := DecodeHeaders(map[string]string{RetryCountHeader: "-1", ReceivedAtHeader: "yesterday"}); err == nil {
+		t.Error("DecodeHeaders(malformed) error = nil")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	msg := &Message{Key: []byte("k"), Headers: map[string]string{DedupKeyHeader: "a", "custom": "kept"}}
+
+	retried := Retry(Retry(msg))
+	if retried.Headers[RetryCountHeader] != "2" || retried.Headers["custom"] != "kept" || retried.Headers[DedupKeyHeader] != "a" {
+		t.Errorf("headers = %v", retried.Headers)
+	}
+	if _, ok := msg.Headers[RetryCountHeader]; ok {
+		t.Error("Retry() changed the headers of the original message")
+	}
+}
@@ -11,25 +11,44 @@ import (
 	"argus-go/internal/queue"
 )
 
-// Consumer implements queue.Consumer using Kafka.
+// reader reads and commits messages, like kafka.Reader.
+type reader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Config() kafka.ReaderConfig
+	Stats() kafka.ReaderStats
+	Close() error
+}
+
+// Consumer implements queue.Consumer using Kafka. It commits the offset of
+// each message before or after handling it, as configured.
 type Consumer struct {
-	reader *kafka.Reader
-	logger *slog.Logger
+	reader       reader
+	commitBefore bool
+	logger       *slog.Logger
 }
 
-// NewConsumer creates a new Kafka consumer.
+// NewConsumer creates a new Kafka consumer of cfg.Topic and cfg.Topics.
 func NewConsumer(cfg *config.KafkaConfig, logger *slog.Logger) *Consumer {
-	reader := kafka.NewReader(kafka.ReaderConfig{
+	readerConfig := kafka.ReaderConfig{
 		Brokers:  cfg.Brokers,
 		Topic:    cfg.Topic,
 		GroupID:  cfg.ConsumerGroup,
 		MinBytes: 1,
 		MaxBytes: 10e6, // 10MB
-	})
 
+		// Commits are sent every interval, and on close, instead of one by one
+		CommitInterval: max(cfg.Commit.Interval, 0),
+	}
+	// A consumer group reads several topics, instead of one, by GroupTopics
+	if topics := cfg.AllTopics(); len(topics) > 1 && cfg.ConsumerGroup != "" {
+		readerConfig.Topic = ""
+		readerConfig.GroupTopics = topics
+	}
 	return &Consumer{
-		reader: reader,
-		logger: logger,
+		reader:       kafka.NewReader(readerConfig),
+		commitBefore: cfg.Commit.CommitBeforeProcessing(),
+		logger:       logger,
 	}
 }
 
@@ -37,7 +56,10 @@ func NewConsumer(cfg *config.KafkaConfig, logger *slog.Logger) *Consumer {
 func (c *Consumer) Start(ctx context.Context, handler queue.MessageHandler) error {
 	c.logger.Info("starting kafka consumer",
 		"topic", c.reader.Config().Topic,
+		"group_topics", c.reader.Config().GroupTopics,
 		"group", c.reader.Config().GroupID,
+		"commit_before_processing", c.commitBefore,
+		"commit_interval", c.reader.Config().CommitInterval,
 	)
 
 	for {
@@ -68,6 +90,13 @@ func (c *Consumer) Start(ctx context.Context, handler queue.MessageHandler) erro
 			queueMsg.Headers[h.Key] = string(h.Value)
 		}
 
+		// At most once: the message is lost if the process crashes handling it
+		if c.commitBefore {
+			if err := c.commit(ctx, msg); err != nil {
+				return err
+			}
+		}
+
 		// Process the message
 		if err := handler(ctx, queueMsg); err != nil {
 			c.logger.Error("failed to process message",
@@ -79,18 +108,41 @@ func (c *Consumer) Start(ctx context.Context, handler queue.MessageHandler) erro
 			continue
 		}
 
-		// Commit the message after successful processing
-		if err := c.reader.CommitMessages(ctx, msg); err != nil {
-			c.logger.Error("failed to commit message",
-				"error", err,
-				"partition", msg.Partition,
-				"offset", msg.Offset,
-			)
-			return fmt.Errorf("failed to commit message: %w", err)
+		// At least once: the message is handled again if the process crashes
+		// before the commit
+		if !c.commitBefore {
+			if err := c.commit(ctx, msg); err != nil {
+				return err
+			}
 		}
 	}
 }
 
+// commit commits the offset of a message. With a commit interval the commit
+// is only queued, and sent with the others of the interval.
+func (c *Consumer) commit(ctx context.Context, msg kafka.Message) error {
+	if err := c.reader.CommitMessages(ctx, msg); err != nil {
+		c.logger.Error("failed to commit message",
+			"error", err,
+			"partition", msg.Partition,
+			"offset", msg.Offset,
+		)
+		return fmt.Errorf("failed to commit message: %w", err)
+	}
+	return nil
+}
+
+// Backlog returns the messages not yet consumed: the lag of the reader behind
+// the end of the partition it read last, plus the messages fetched but not
+// yet handled. In a consumer group the lag covers one of the partitions
+// assigned to the reader only, so it approximates the backlog of the
+// replica. Reading the stats resets the counters of the reader, which are
+// not used otherwise.
+func (c *Consumer) Backlog() int64 {
+	stats := c.reader.Stats()
+	return max(stats.Lag, 0) + stats.QueueLength
+}
+
 // Close closes the Kafka reader.
 func (c *Consumer) Close() error {
 	if c.reader != nil {
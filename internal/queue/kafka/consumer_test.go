@@ -0,0 +1,140 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"argus-go/internal/config"
+	"argus-go/internal/queue"
+)
+
+// fakeReader serves messages and records the fetches, commits and handling
+// in the order they happen. Once the messages run out it cancels the
+// consumer.
+type fakeReader struct {
+	messages  []kafka.Message
+	commitErr error
+	cancel    context.CancelFunc
+	log       []string
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if len(r.messages) == 0 {
+		r.cancel()
+		return kafka.Message{}, ctx.Err()
+	}
+	msg := r.messages[0]
+	r.messages = r.messages[1:]
+	return msg, nil
+}
+
+func (r *fakeReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	for _, msg := range msgs {
+		r.log = append(r.log, fmt.Sprintf("commit %d", msg.Offset))
+	}
+	return r.commitErr
+}
+
+func (r *fakeReader) Config() kafka.ReaderConfig { return kafka.ReaderConfig{} }
+func (r *fakeReader) Stats() kafka.ReaderStats   { return kafka.ReaderStats{} }
+func (r *fakeReader) Close() error               { return nil }
+
+func TestConsumer_CommitOrder(t *testing.T) {
+	tests := []struct {
+		name         string
+		commitBefore bool
+		want         []string
+	}{
+		{
+			// A message that fails is not committed, yet consumption goes on
+			name: "after processing",
+			want: []string{"handle 0", "commit 0", "handle 1", "handle 2", "commit 2"},
+		},
+		{
+			name:         "before processing",
+			commitBefore: true,
+			want:         []string{"commit 0", "handle 0", "commit 1", "handle 1", "commit 2", "handle 2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			reader := &fakeReader{
+				messages: []kafka.Message{{Offset: 0, Value: []byte("0")}, {Offset: 1, Value: []byte("1")}, {Offset: 2, Value: []byte("2")}},
+				cancel:   cancel,
+			}
+			consumer := &Consumer{reader: reader, commitBefore: tt.commitBefore, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+			err := consumer.Start(ctx, func(ctx context.Context, msg *queue.Message) error {
+				reader.log = append(reader.log, fmt.Sprintf("handle %s", msg.Value))
+				if string(msg.Value) == "1" {
+					return errors.New("handler failed")
+				}
+				return nil
+			})
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("Start() error = %v, want context.Canceled", err)
+			}
+			if !slices.Equal(reader.log, tt.want) {
+				t.Errorf("log = %v, want %v", reader.log, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsumer_CommitError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reader := &fakeReader{
+		messages:  []kafka.Message{{Offset: 0}, {Offset: 1}},
+		commitErr: errors.New("broker unavailable"),
+		cancel:    cancel,
+	}
+	consumer := &Consumer{reader: reader, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	// A failed commit stops the consumer instead of reading past it
+	err := consumer.Start(ctx, func(ctx context.Context, msg *queue.Message) error { return nil })
+	if err == nil || !errors.Is(err, reader.commitErr) {
+		t.Fatalf("Start() error = %v, want %v", err, reader.commitErr)
+	}
+	if want := []string{"commit 0"}; !slices.Equal(reader.log, want) {
+		t.Errorf("log = %v, want %v", reader.log, want)
+	}
+}
+
+func TestNewConsumer_Commit(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tests := []struct {
+		name         string
+		commit       config.KafkaCommitConfig
+		wantBefore   bool
+		wantInterval time.Duration
+	}{
+		{name: "after processing", commit: config.KafkaCommitConfig{Mode: config.KafkaCommitAfterProcessing}},
+		{name: "before processing", commit: config.KafkaCommitConfig{Mode: config.KafkaCommitBeforeProcessing}, wantBefore: true},
+		{name: "interval", commit: config.KafkaCommitConfig{Interval: time.Second}, wantInterval: time.Second},
+		{name: "negative interval", commit: config.KafkaCommitConfig{Interval: -time.Second}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consumer := NewConsumer(&config.KafkaConfig{Brokers: []string{"localhost:9092"}, Topic: "events", Commit: tt.commit}, logger)
+			defer func() { _ = consumer.Close() }()
+
+			if consumer.commitBefore != tt.wantBefore {
+				t.Errorf("commitBefore = %v, want %v", consumer.commitBefore, tt.wantBefore)
+			}
+			if got := consumer.reader.Config().CommitInterval; got != tt.wantInterval {
+				t.Errorf("CommitInterval = %v, want %v", got, tt.wantInterval)
+			}
+		})
+	}
+}
@@ -15,13 +15,15 @@ import (
 // Producer implements queue.Producer using Kafka.
 type Producer struct {
 	writer *kafka.Writer
+	topic  string
 }
 
-// NewProducer creates a new Kafka producer.
+// NewProducer creates a new Kafka producer, publishing to cfg.Topic unless a
+// message names a topic of its own.
 func NewProducer(cfg *config.KafkaConfig) *Producer {
+	// The topic is set per message, so the writer has none
 	writer := &kafka.Writer{
 		Addr:         kafka.TCP(cfg.Brokers...),
-		Topic:        cfg.Topic,
 		Balancer:     &kafka.Hash{}, // Use key-based partitioning
 		BatchTimeout: 10 * time.Millisecond,
 		RequiredAcks: kafka.RequireOne,
@@ -29,12 +31,18 @@ func NewProducer(cfg *config.KafkaConfig) *Producer {
 
 	return &Producer{
 		writer: writer,
+		topic:  cfg.Topic,
 	}
 }
 
 // Publish sends a message to Kafka.
 func (p *Producer) Publish(ctx context.Context, msg *queue.Message) error {
+	topic := msg.Topic
+	if topic == "" {
+		topic = p.topic
+	}
 	kafkaMsg := kafka.Message{
+		Topic: topic,
 		Key:   msg.Key,
 		Value: msg.Value,
 	}
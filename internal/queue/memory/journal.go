@@ -0,0 +1,229 @@
+package memory
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"argus-go/internal/queue"
+)
+
+// offsetsFile is the name of the consumer position checkpoint within the journal directory.
+const offsetsFile = "offsets.json"
+
+// journal persists published messages and the consumer position of each
+// partition to disk, so queued events survive a restart.
+//
+// Every partition has an append-only log of published messages and a count of
+// consumed messages. The counts are checkpointed periodically; on restart the
+// messages after the checkpointed position are replayed, so delivery is
+// at-least-once. Once a partition is fully consumed its log is truncated.
+type journal struct {
+	dir        string
+	partitions []*journalPartition
+	checkpoint sync.Mutex
+}
+
+// journalPartition is the on-disk log of a single partition.
+type journalPartition struct {
+	// mu serializes appends with channel sends so that log order matches
+	// consumption order, and excludes appends during compaction.
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	lastSize int64
+	appended int64
+	consumed atomic.Int64
+}
+
+// journalRecord is the on-disk form of a queue.Message.
+type journalRecord struct {
+	Key     []byte            `json:"key"`
+	Value   []byte            `json:"value"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// openJournal opens or creates the journal in dir and returns the messages
+// of each partition that have not been consumed yet.
+func openJournal(dir string, partitionCount int) (*journal, [][]*queue.Message, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, nil, fmt.Errorf("failed to create queue journal directory: %w", err)
+	}
+
+	offsets, err := readOffsets(filepath.Join(dir, offsetsFile))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	j := &journal{dir: dir, partitions: make([]*journalPartition, partitionCount)}
+	pending := make([][]*queue.Message, partitionCount)
+
+	for i := range j.partitions {
+		path := filepath.Join(dir, fmt.Sprintf("partition-%d.log", i))
+
+		messages, validBytes, err := readLog(path)
+		if err != nil {
+			j.close()
+			return nil, nil, err
+		}
+
+		consumed := int64(0)
+		if i < len(offsets) && offsets[i] <= int64(len(messages)) {
+			consumed = offsets[i]
+		}
+
+		file, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			j.close()
+			return nil, nil, fmt.Errorf("failed to open queue journal: %w", err)
+		}
+
+		// Drop any partially written trailing record so new appends stay readable
+		if err := file.Truncate(validBytes); err != nil {
+			_ = file.Close()
+			j.close()
+			return nil, nil, fmt.Errorf("failed to repair queue journal: %w", err)
+		}
+
+		p := &journalPartition{file: file, size: validBytes, appended: int64(len(messages))}
+		p.consumed.Store(consumed)
+		j.partitions[i] = p
+		pending[i] = messages[consumed:]
+	}
+
+	return j, pending, nil
+}
+
+// append writes a published message to a partition log.
+// The caller must hold the partition lock.
+func (p *journalPartition) append(msg *queue.Message) error {
+	data, err := json.Marshal(journalRecord{Key: msg.Key, Value: msg.Value, Headers: msg.Headers})
+	if err != nil {
+		return fmt.Errorf("failed to encode queue message: %w", err)
+	}
+	n, err := p.file.Write(append(data, '\n'))
+	if err != nil {
+		// Drop any partial write so the log stays readable
+		_ = p.file.Truncate(p.size)
+		return fmt.Errorf("failed to write queue journal: %w", err)
+	}
+	p.lastSize = p.size
+	p.size += int64(n)
+	p.appended++
+	return nil
+}
+
+// unappend removes the message written by the last append, for a publish
+// that was canceled before the message was queued.
+// The caller must hold the partition lock.
+func (p *journalPartition) unappend() error {
+	if err := p.file.Truncate(p.lastSize); err != nil {
+		return fmt.Errorf("failed to truncate queue journal: %w", err)
+	}
+	p.size = p.lastSize
+	p.appended--
+	return nil
+}
+
+// save checkpoints the consumer position of every partition, truncating
+// the logs of partitions that have been fully consumed.
+func (j *journal) save() error {
+	j.checkpoint.Lock()
+	defer j.checkpoint.Unlock()
+
+	offsets := make([]int64, len(j.partitions))
+	for i, p := range j.partitions {
+		p.mu.Lock()
+		consumed := p.consumed.Load()
+		if consumed > 0 && consumed == p.appended {
+			if err := p.file.Truncate(0); err != nil {
+				p.mu.Unlock()
+				return fmt.Errorf("failed to truncate queue journal: %w", err)
+			}
+			p.size, p.lastSize = 0, 0
+			p.appended = 0
+			p.consumed.Store(0)
+			consumed = 0
+		}
+		p.mu.Unlock()
+		offsets[i] = consumed
+	}
+
+	data, err := json.Marshal(offsets)
+	if err != nil {
+		return err
+	}
+
+	// Write atomically so a crash never leaves a partial checkpoint
+	path := filepath.Join(j.dir, offsetsFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write queue offsets: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write queue offsets: %w", err)
+	}
+	return nil
+}
+
+// close closes all partition logs.
+func (j *journal) close() {
+	for _, p := range j.partitions {
+		if p != nil {
+			_ = p.file.Close()
+		}
+	}
+}
+
+// readOffsets reads the checkpointed consumer positions, if any.
+func readOffsets(path string) ([]int64, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read queue offsets: %w", err)
+	}
+
+	var offsets []int64
+	if err := json.Unmarshal(data, &offsets); err != nil {
+		return nil, fmt.Errorf("failed to parse queue offsets: %w", err)
+	}
+	return offsets, nil
+}
+
+// readLog reads every message of a partition log, if it exists, and returns
+// the length in bytes of the readable prefix. A truncated trailing record
+// (from a crash mid-write) is ignored.
+func readLog(path string) ([]*queue.Message, int64, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, fmt.Errorf("failed to open queue journal: %w", err)
+	}
+	defer file.Close()
+
+	var messages []*queue.Message
+	var validBytes int64
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var rec journalRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			break
+		}
+		messages = append(messages, &queue.Message{Key: rec.Key, Value: rec.Value, Headers: rec.Headers})
+		validBytes += int64(len(scanner.Bytes())) + 1
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read queue journal: %w", err)
+	}
+
+	return messages, validBytes, nil
+}
@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"argus-go/internal/queue"
+)
+
+func TestPersistentQueue_ReplaysUnconsumedMessages(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	q, err := NewPersistentQueue(dir, 2, 100, time.Hour)
+	if err != nil {
+		t.Fatalf("NewPersistentQueue error: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		msg := &queue.Message{Key: []byte(fmt.Sprintf("key-%d", i)), Value: []byte(fmt.Sprintf("%d", i))}
+		if err := q.Publish(ctx, msg); err != nil {
+			t.Fatalf("Publish error: %v", err)
+		}
+	}
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+
+	// Nothing was consumed, so everything is replayed
+	q, err = NewPersistentQueue(dir, 2, 100, time.Hour)
+	if err != nil {
+		t.Fatalf("NewPersistentQueue (reopen) error: %v", err)
+	}
+	if got := q.Len(); got != 5 {
+		t.Fatalf("Len after reopen = %d, want 5", got)
+	}
+
+	// Consume everything, then close while the consumer is running
+	var wg sync.WaitGroup
+	wg.Add(5)
+	consumer := q
+	go func() {
+		_ = consumer.Start(ctx, func(ctx context.Context, msg *queue.Message) error {
+			wg.Done()
+			return nil
+		})
+	}()
+
+	done := make(chan struct{})
+	go func() { wg.Wait(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for replayed messages")
+	}
+	if err := consumer.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+
+	q, err = NewPersistentQueue(dir, 2, 100, time.Hour)
+	if err != nil {
+		t.Fatalf("NewPersistentQueue (second reopen) error: %v", err)
+	}
+	defer q.Close()
+	if got := q.Len(); got != 0 {
+		t.Errorf("Len after consuming = %d, want 0", got)
+	}
+}
+
+func TestPersistentQueue_IgnoresTruncatedRecord(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	q, err := NewPersistentQueue(dir, 1, 100, time.Hour)
+	if err != nil {
+		t.Fatalf("NewPersistentQueue error: %v", err)
+	}
+	if err := q.Publish(ctx, &queue.Message{Key: []byte("a"), Value: []byte("1")}); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+
+	// Simulate a crash in the middle of writing a record
+	f, err := os.OpenFile(filepath.Join(dir, "partition-0.log"), os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		t.Fatalf("OpenFile error: %v", err)
+	}
+	_, _ = f.WriteString(`{"key":"Yg==","val`)
+	_ = f.Close()
+
+	q, err = NewPersistentQueue(dir, 1, 100, time.Hour)
+	if err != nil {
+		t.Fatalf("NewPersistentQueue (reopen) error: %v", err)
+	}
+	if got := q.Len(); got != 1 {
+		t.Fatalf("Len after reopen = %d, want 1", got)
+	}
+
+	// New messages must still be readable after the repaired tail
+	if err := q.Publish(ctx, &queue.Message{Key: []byte("c"), Value: []byte("3")}); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+
+	q, err = NewPersistentQueue(dir, 1, 100, time.Hour)
+	if err != nil {
+		t.Fatalf("NewPersistentQueue (second reopen) error: %v", err)
+	}
+	defer q.Close()
+	if got := q.Len(); got != 2 {
+		t.Errorf("Len after second reopen = %d, want 2", got)
+	}
+}
+
+func TestPersistentQueue_CanceledPublishIsNotJournaled(t *testing.T) {
+	dir := t.TempDir()
+
+	q, err := NewPersistentQueue(dir, 1, 1, time.Hour)
+	if err != nil {
+		t.Fatalf("NewPersistentQueue error: %v", err)
+	}
+	if err := q.Publish(context.Background(), &queue.Message{Value: []byte("1")}); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+
+	// The partition is full, so this publish times out
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := q.Publish(ctx, &queue.Message{Value: []byte("2")}); err == nil {
+		t.Fatal("Publish to a full partition should fail when the context expires")
+	}
+	if err := q.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+
+	q, err = NewPersistentQueue(dir, 1, 1, time.Hour)
+	if err != nil {
+		t.Fatalf("NewPersistentQueue (reopen) error: %v", err)
+	}
+	defer q.Close()
+	if got := q.Len(); got != 1 {
+		t.Errorf("Len after reopen = %d, want 1", got)
+	}
+}
@@ -4,66 +4,218 @@ package memory
 
 import (
 	"context"
+	"hash/fnv"
 	"sync"
+	"time"
 
 	"argus-go/internal/queue"
 )
 
 // Queue is an in-memory implementation of both Producer and Consumer interfaces.
-// Messages are stored in a channel, allowing for simple pub/sub within a process.
+// Like a Kafka topic, it is split into partitions: messages are assigned to a
+// partition by a hash of their key, each partition is consumed by its own
+// goroutine, and ordering is only guaranteed within a partition.
 // This implementation is safe for concurrent use.
 type Queue struct {
-	messages chan *queue.Message
-	closed   bool
-	mu       sync.RWMutex
-	wg       sync.WaitGroup
+	partitions []chan *queue.Message
+	closed     bool
+	mu         sync.RWMutex
+	wg         sync.WaitGroup
+
+	// done is closed when Close begins, ending the sends of publishers
+	// blocked on a full partition so Close can take mu.
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// journal is set for persistent queues; see NewPersistentQueue.
+	journal        *journal
+	stopCheckpoint chan struct{}
+	checkpointDone chan struct{}
 }
 
-// NewQueue creates a new in-memory queue with the specified buffer size.
-// The buffer size determines how many messages can be queued before
-// Publish blocks (or fails if the context is canceled).
+// NewQueue creates a new single-partition in-memory queue with the specified
+// buffer size. All messages are consumed in publish order.
 func NewQueue(bufferSize int) *Queue {
+	return NewPartitionedQueue(1, bufferSize)
+}
+
+// NewPartitionedQueue creates a new in-memory queue with the given number of
+// partitions. The buffer size applies to each partition and determines how
+// many messages can be queued before Publish blocks (or fails if the context
+// is canceled).
+func NewPartitionedQueue(partitionCount, bufferSize int) *Queue {
+	if partitionCount < 1 {
+		partitionCount = 1
+	}
+
+	partitions := make([]chan *queue.Message, partitionCount)
+	for i := range partitions {
+		partitions[i] = make(chan *queue.Message, bufferSize)
+	}
+
 	return &Queue{
-		messages: make(chan *queue.Message, bufferSize),
+		partitions: partitions,
+		done:       make(chan struct{}),
+	}
+}
+
+// NewPersistentQueue creates a partitioned in-memory queue whose contents and
+// consumer position are persisted to dir, so queued messages survive a restart.
+// Published messages are appended to a per-partition log and the consumer
+// position is checkpointed every checkpointInterval; on startup, messages
+// published but not consumed as of the last checkpoint are queued again.
+// Delivery is therefore at-least-once: messages consumed after the last
+// checkpoint are redelivered. The partition count must not change between
+// restarts of the same directory.
+func NewPersistentQueue(dir string, partitionCount, bufferSize int, checkpointInterval time.Duration) (*Queue, error) {
+	if partitionCount < 1 {
+		partitionCount = 1
+	}
+
+	j, pending, err := openJournal(dir, partitionCount)
+	if err != nil {
+		return nil, err
+	}
+
+	partitions := make([]chan *queue.Message, partitionCount)
+	for i := range partitions {
+		// Make room for the replayed messages on top of the regular buffer
+		partitions[i] = make(chan *queue.Message, bufferSize+len(pending[i]))
+		for _, msg := range pending[i] {
+			partitions[i] <- msg
+		}
+	}
+
+	q := &Queue{
+		partitions:     partitions,
+		done:           make(chan struct{}),
+		journal:        j,
+		stopCheckpoint: make(chan struct{}),
+		checkpointDone: make(chan struct{}),
+	}
+	go q.checkpointLoop(checkpointInterval)
+
+	return q, nil
+}
+
+// checkpointLoop periodically saves the consumer position until the queue is closed.
+func (q *Queue) checkpointLoop(interval time.Duration) {
+	defer close(q.checkpointDone)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-q.stopCheckpoint:
+			return
+		case <-ticker.C:
+			// Errors are retried on the next tick and surfaced by Close
+			_ = q.journal.save()
+		}
 	}
 }
 
-// Publish sends a message to the in-memory queue.
-// This method blocks if the queue is full until space is available
-// or the context is canceled.
+// Publish sends a message to the partition selected by its key.
+// This method blocks if the partition is full until space is available,
+// the context is canceled, or the queue is closed.
 func (q *Queue) Publish(ctx context.Context, msg *queue.Message) error {
 	q.mu.RLock()
+	defer q.mu.RUnlock()
+
 	if q.closed {
-		q.mu.RUnlock()
 		return ErrQueueClosed
 	}
-	q.mu.RUnlock()
+
+	idx := q.partitionFor(msg.Key)
+	if q.journal != nil {
+		// Hold the partition lock across the send so the log order matches
+		// the order in which messages are consumed.
+		p := q.journal.partitions[idx]
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		if err := p.append(msg); err != nil {
+			return err
+		}
+		select {
+		case q.partitions[idx] <- msg:
+			return nil
+		case <-ctx.Done():
+			// The message was never queued, so drop it from the log again
+			if err := p.unappend(); err != nil {
+				return err
+			}
+			return ctx.Err()
+		case <-q.done:
+			if err := p.unappend(); err != nil {
+				return err
+			}
+			return ErrQueueClosed
+		}
+	}
 
 	select {
-	case q.messages <- msg:
+	case q.partitions[idx] <- msg:
 		return nil
 	case <-ctx.Done():
 		return ctx.Err()
+	case <-q.done:
+		return ErrQueueClosed
+	}
+}
+
+// partitionFor maps a message key to a partition index using FNV-1a,
+// the same hash used by the Kafka producer's balancer.
+func (q *Queue) partitionFor(key []byte) int {
+	if len(q.partitions) == 1 {
+		return 0
 	}
+	h := fnv.New32a()
+	_, _ = h.Write(key)
+	return int(h.Sum32() % uint32(len(q.partitions)))
 }
 
 // Start begins consuming messages and calls the handler for each one.
+// Each partition is consumed by its own goroutine, so the handler is called
+// concurrently for messages in different partitions.
 // This blocks until the context is canceled or the queue is closed.
 func (q *Queue) Start(ctx context.Context, handler queue.MessageHandler) error {
 	q.wg.Add(1)
 	defer q.wg.Done()
 
+	var consumers sync.WaitGroup
+	for i := range q.partitions {
+		consumers.Add(1)
+		go func(partition int) {
+			defer consumers.Done()
+			q.consumePartition(ctx, partition, handler)
+		}(i)
+	}
+	consumers.Wait()
+
+	return ctx.Err()
+}
+
+// consumePartition processes the messages of a single partition in order.
+func (q *Queue) consumePartition(ctx context.Context, partition int, handler queue.MessageHandler) {
+	messages := q.partitions[partition]
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
-		case msg, ok := <-q.messages:
+			return
+		case msg, ok := <-messages:
 			if !ok {
 				// Channel closed
-				return nil
+				return
 			}
 			// Process the message
-			if err := handler(ctx, msg); err != nil {
+			err := handler(ctx, msg)
+			if q.journal != nil {
+				// Failed messages are skipped rather than retried, so they count as consumed too
+				q.journal.partitions[partition].consumed.Add(1)
+			}
+			if err != nil {
 				// In a real implementation, you might want to handle errors differently
 				// (retry, dead letter queue, etc.). For the mock, we just log and continue.
 				continue
@@ -72,8 +224,12 @@ func (q *Queue) Start(ctx context.Context, handler queue.MessageHandler) error {
 	}
 }
 
-// Close shuts down the queue, stopping all consumers.
+// Close shuts down the queue, stopping all consumers. Publishers blocked on
+// a full partition fail with ErrQueueClosed.
 func (q *Queue) Close() error {
+	// Publishers hold mu while they wait for room in a partition
+	q.closeOnce.Do(func() { close(q.done) })
+
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
@@ -82,13 +238,32 @@ func (q *Queue) Close() error {
 	}
 
 	q.closed = true
-	close(q.messages)
+	for _, partition := range q.partitions {
+		close(partition)
+	}
 	q.wg.Wait()
+
+	if q.journal != nil {
+		close(q.stopCheckpoint)
+		<-q.checkpointDone
+		err := q.journal.save()
+		q.journal.close()
+		return err
+	}
 	return nil
 }
 
-// Len returns the current number of messages in the queue.
+// Len returns the current number of messages in the queue across all partitions.
 // Useful for testing to verify queue state.
 func (q *Queue) Len() int {
-	return len(q.messages)
+	total := 0
+	for _, partition := range q.partitions {
+		total += len(partition)
+	}
+	return total
+}
+
+// PartitionCount returns the number of partitions.
+func (q *Queue) PartitionCount() int {
+	return len(q.partitions)
 }
@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"argus-go/internal/queue"
+)
+
+func TestPartitionedQueue_OrderWithinPartition(t *testing.T) {
+	q := NewPartitionedQueue(8, 1000)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const keys, perKey = 20, 50
+
+	var mu sync.Mutex
+	received := make(map[string][]int)
+	var wg sync.WaitGroup
+	wg.Add(keys * perKey)
+
+	go func() {
+		_ = q.Start(ctx, func(ctx context.Context, msg *queue.Message) error {
+			var seq int
+			_, _ = fmt.Sscanf(string(msg.Value), "%d", &seq)
+			mu.Lock()
+			received[string(msg.Key)] = append(received[string(msg.Key)], seq)
+			mu.Unlock()
+			wg.Done()
+			return nil
+		})
+	}()
+
+	for i := 0; i < perKey; i++ {
+		for k := 0; k < keys; k++ {
+			msg := &queue.Message{Key: []byte(fmt.Sprintf("key-%d", k)), Value: []byte(fmt.Sprintf("%d", i))}
+			if err := q.Publish(ctx, msg); err != nil {
+				t.Fatalf("Publish error: %v", err)
+			}
+		}
+	}
+
+	done := make(chan struct{})
+	go func() { wg.Wait(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for messages")
+	}
+
+	for key, seqs := range received {
+		for i, seq := range seqs {
+			if seq != i {
+				t.Fatalf("key %s: message %d has sequence %d, want in-order delivery", key, i, seq)
+			}
+		}
+	}
+}
+
+func TestPartitionedQueue_SameKeySamePartition(t *testing.T) {
+	q := NewPartitionedQueue(16, 1)
+
+	first := q.partitionFor([]byte("em-1:database"))
+	for i := 0; i < 10; i++ {
+		if p := q.partitionFor([]byte("em-1:database")); p != first {
+			t.Fatalf("partitionFor = %d, want stable %d", p, first)
+		}
+	}
+
+	used := make(map[int]bool)
+	for i := 0; i < 200; i++ {
+		used[q.partitionFor([]byte(fmt.Sprintf("key-%d", i)))] = true
+	}
+	if len(used) < 2 {
+		t.Errorf("keys spread over %d partitions, want more than one", len(used))
+	}
+}
+
+func TestQueue_PublishAfterClose(t *testing.T) {
+	q := NewPartitionedQueue(4, 10)
+	_ = q.Close()
+
+	if err := q.Publish(context.Background(), &queue.Message{Key: []byte("k")}); err != ErrQueueClosed {
+		t.Errorf("Publish after close error = %v, want ErrQueueClosed", err)
+	}
+}
+
+func TestQueue_CloseWithBlockedPublisher(t *testing.T) {
+	persistent, err := NewPersistentQueue(t.TempDir(), 1, 1, time.Hour)
+	if err != nil {
+		t.Fatalf("NewPersistentQueue error: %v", err)
+	}
+	queues := map[string]*Queue{"memory": NewQueue(1), "persistent": persistent}
+
+	for name, q := range queues {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if err := q.Publish(ctx, &queue.Message{Value: []byte("1")}); err != nil {
+				t.Fatalf("Publish error: %v", err)
+			}
+
+			// Without a consumer the partition stays full, so this publish
+			// blocks until the queue is closed
+			published := make(chan error, 1)
+			go func() {
+				published <- q.Publish(ctx, &queue.Message{Value: []byte("2")})
+			}()
+			time.Sleep(20 * time.Millisecond)
+
+			closed := make(chan error, 1)
+			go func() { closed <- q.Close() }()
+			select {
+			case err := <-closed:
+				if err != nil {
+					t.Errorf("Close error: %v", err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("Close did not return with a publisher blocked on a full partition")
+			}
+			if err := <-published; err != ErrQueueClosed {
+				t.Errorf("blocked Publish error = %v, want ErrQueueClosed", err)
+			}
+		})
+	}
+}
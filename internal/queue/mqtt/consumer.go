@@ -0,0 +1,129 @@
+// Package mqtt consumes MQTT topics, e.g. of edge device fleets, as a
+// queue.Consumer.
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	pahomqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"argus-go/internal/config"
+	"argus-go/internal/queue"
+)
+
+// TopicHeader is the header of consumed messages holding their MQTT topic.
+const TopicHeader = "mqtt_topic"
+
+// disconnectQuiesce is how long in-flight work may take when disconnecting.
+const disconnectQuiesce = 250 // milliseconds
+
+// Consumer implements queue.Consumer over MQTT subscriptions. Messages are
+// acknowledged once handled, so with QoS 1 or 2 and a persistent session the
+// broker redelivers those not handled before a disconnect.
+type Consumer struct {
+	cfg    config.MQTTSourceConfig
+	client pahomqtt.Client
+	logger *slog.Logger
+}
+
+// NewConsumer creates a new MQTT consumer. It connects when started.
+func NewConsumer(cfg *config.MQTTSourceConfig, logger *slog.Logger) *Consumer {
+	return &Consumer{
+		cfg:    *cfg,
+		logger: logger,
+	}
+}
+
+// Start connects, subscribes and calls the handler for each message, one at
+// a time, until the context is canceled. The client reconnects and
+// subscribes again on its own after losing the connection.
+func (c *Consumer) Start(ctx context.Context, handler queue.MessageHandler) error {
+	messages := make(chan pahomqtt.Message)
+	receive := func(_ pahomqtt.Client, msg pahomqtt.Message) {
+		select {
+		case messages <- msg:
+		case <-ctx.Done():
+		}
+	}
+
+	opts := pahomqtt.NewClientOptions().
+		AddBroker(c.cfg.Broker).
+		SetClientID(c.cfg.ClientID).
+		SetUsername(c.cfg.Username).
+		SetPassword(c.cfg.Password).
+		SetCleanSession(false).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(5 * time.Second).
+		SetAutoAckDisabled(true).
+		SetOnConnectHandler(func(client pahomqtt.Client) {
+			c.subscribe(client, receive)
+		}).
+		SetConnectionLostHandler(func(_ pahomqtt.Client, err error) {
+			c.logger.Warn("mqtt connection lost", "broker", c.cfg.Broker, "error", err)
+		})
+	c.client = pahomqtt.NewClient(opts)
+
+	c.logger.Info("starting mqtt consumer", "broker", c.cfg.Broker, "topics", c.cfg.Topics, "qos", c.cfg.QoS)
+
+	// The connection is retried until it succeeds or the consumer stops
+	token := c.client.Connect()
+	select {
+	case <-token.Done():
+		if err := token.Error(); err != nil {
+			return fmt.Errorf("failed to connect to mqtt broker: %w", err)
+		}
+	case <-ctx.Done():
+		c.client.Disconnect(disconnectQuiesce)
+		return ctx.Err()
+	}
+
+	err := c.consume(ctx, messages, handler)
+	c.client.Disconnect(disconnectQuiesce)
+	return err
+}
+
+// consume calls the handler for each message received, one at a time, and
+// acknowledges those handled, until the context is canceled.
+func (c *Consumer) consume(ctx context.Context, messages <-chan pahomqtt.Message, handler queue.MessageHandler) error {
+	for {
+		select {
+		case <-ctx.Done():
+			c.logger.Info("mqtt consumer stopping due to context cancellation")
+			return ctx.Err()
+		case msg := <-messages:
+			queueMsg := &queue.Message{
+				Key:     []byte(msg.Topic()),
+				Value:   msg.Payload(),
+				Headers: map[string]string{TopicHeader: msg.Topic()},
+			}
+			if err := handler(ctx, queueMsg); err != nil {
+				c.logger.Error("failed to process message", "error", err, "topic", msg.Topic())
+				// Left unacknowledged, so the broker redelivers it after a reconnect
+				continue
+			}
+			msg.Ack()
+		}
+	}
+}
+
+// subscribe subscribes to every topic, on each (re)connection.
+func (c *Consumer) subscribe(client pahomqtt.Client, receive pahomqtt.MessageHandler) {
+	for _, topic := range c.cfg.Topics {
+		token := client.Subscribe(topic, byte(c.cfg.QoS), receive)
+		if token.Wait() && token.Error() != nil {
+			c.logger.Error("failed to subscribe to mqtt topic", "topic", topic, "error", token.Error())
+		}
+	}
+}
+
+// Close disconnects from the broker, if still connected.
+func (c *Consumer) Close() error {
+	if c.client != nil && c.client.IsConnected() {
+		c.client.Disconnect(disconnectQuiesce)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	pahomqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"argus-go/internal/config"
+	"argus-go/internal/queue"
+)
+
+// fakeMessage is a received MQTT message that records its acknowledgement.
+type fakeMessage struct {
+	topic   string
+	payload []byte
+	acked   bool
+}
+
+func (m *fakeMessage) Duplicate() bool   { return false }
+func (m *fakeMessage) Qos() byte         { return 1 }
+func (m *fakeMessage) Retained() bool    { return false }
+func (m *fakeMessage) Topic() string     { return m.topic }
+func (m *fakeMessage) MessageID() uint16 { return 0 }
+func (m *fakeMessage) Payload() []byte   { return m.payload }
+func (m *fakeMessage) Ack()              { m.acked = true }
+
+func TestConsumer_Consume(t *testing.T) {
+	consumer := NewConsumer(&config.MQTTSourceConfig{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ok := &fakeMessage{topic: "devices/gw-1/alerts", payload: []byte(`{"summary":"Overheating"}`)}
+	failing := &fakeMessage{topic: "devices/gw-2/alerts", payload: []byte(`{}`)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	messages := make(chan pahomqtt.Message)
+	done := make(chan error, 1)
+	var handled []*queue.Message
+	go func() {
+		done <- consumer.consume(ctx, messages, func(ctx context.Context, msg *queue.Message) error {
+			handled = append(handled, msg)
+			if msg.Headers[TopicHeader] == failing.topic {
+				return errors.New("ingest failed")
+			}
+			return nil
+		})
+	}()
+
+	messages <- ok
+	messages <- failing
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("consume() error = %v, want context.Canceled", err)
+	}
+
+	if len(handled) != 2 {
+		t.Fatalf("handled %d messages, want 2", len(handled))
+	}
+	if msg := handled[0]; string(msg.Key) != ok.topic || string(msg.Value) != string(ok.payload) || msg.Headers[TopicHeader] != ok.topic {
+		t.Errorf("message = %+v, want the payload keyed by its topic", msg)
+	}
+
+	// Only handled messages are acknowledged; the broker redelivers the others
+	if !ok.acked {
+		t.Error("handled message not acknowledged")
+	}
+	if failing.acked {
+		t.Error("failed message acknowledged")
+	}
+}
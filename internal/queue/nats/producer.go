@@ -0,0 +1,64 @@
+// Package nats provides a NATS-based implementation of queue.Producer.
+package nats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	natsgo "github.com/nats-io/nats.go"
+
+	"argus-go/internal/queue"
+)
+
+// KeyHeader is the header of published messages holding their key.
+const KeyHeader = "key"
+
+// Producer implements queue.Producer by publishing to a NATS subject. NATS
+// has no partitions, so message keys are sent as a header only.
+type Producer struct {
+	conn    *natsgo.Conn
+	subject string
+}
+
+// NewProducer connects to the NATS server at url. The connection reconnects
+// on its own after it is lost.
+func NewProducer(url, subject string) (*Producer, error) {
+	conn, err := natsgo.Connect(url,
+		natsgo.MaxReconnects(-1),
+		natsgo.ReconnectWait(2*time.Second),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+	return &Producer{
+		conn:    conn,
+		subject: subject,
+	}, nil
+}
+
+// Publish sends a message to the subject and flushes it to the server, so an
+// unreachable server is reported instead of buffered silently.
+func (p *Producer) Publish(ctx context.Context, msg *queue.Message) error {
+	natsMsg := natsgo.NewMsg(p.subject)
+	natsMsg.Data = msg.Value
+	if len(msg.Key) > 0 {
+		natsMsg.Header.Set(KeyHeader, string(msg.Key))
+	}
+	for k, v := range msg.Headers {
+		natsMsg.Header.Set(k, v)
+	}
+
+	if err := p.conn.PublishMsg(natsMsg); err != nil {
+		return fmt.Errorf("failed to publish message to nats: %w", err)
+	}
+	if err := p.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("failed to flush message to nats: %w", err)
+	}
+	return nil
+}
+
+// Close drains pending messages and closes the connection.
+func (p *Producer) Close() error {
+	return p.conn.Drain()
+}
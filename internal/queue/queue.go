@@ -17,6 +17,10 @@ type Message struct {
 
 	// Headers contains optional metadata.
 	Headers map[string]string
+
+	// Topic overrides the topic a producer publishes the message to.
+	// Producers without topics ignore it.
+	Topic string
 }
 
 // Producer defines the interface for publishing messages to a queue.
@@ -0,0 +1,262 @@
+// Package quota enforces the quotas of event managers. It periodically checks
+// what each event manager with a quota used, the events in the stored usage
+// and its open alerts, against its limits. Ingestion consults the last check
+// to reject trigger events, and notifications to suppress them, once a limit
+// is reached.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+	"argus-go/internal/store"
+)
+
+// Enforcer checks quotas and decides on events by the last check. A nil
+// Enforcer admits every event, so enforcement can be disabled. It is safe
+// for concurrent use.
+//
+// Events admitted since the last check count toward the quota right away, so
+// a replica doesn't overrun a limit between checks; events admitted by other
+// replicas count from the check after their usage is stored.
+type Enforcer struct {
+	eventManagerRepo store.EventManagerRepository
+	usageRepo        store.UsageRepository
+	alertRepo        store.AlertRepository
+	clock            clock.Clock
+	logger           *slog.Logger
+
+	mu       sync.RWMutex
+	statuses map[string]*domain.QuotaStatus
+	admitted map[string]int64
+}
+
+// NewEnforcer creates an enforcer of the quotas of the event managers in
+// eventManagerRepo.
+func NewEnforcer(
+	eventManagerRepo store.EventManagerRepository,
+	usageRepo store.UsageRepository,
+	alertRepo store.AlertRepository,
+	clk clock.Clock,
+	logger *slog.Logger,
+) *Enforcer {
+	return &Enforcer{
+		eventManagerRepo: eventManagerRepo,
+		usageRepo:        usageRepo,
+		alertRepo:        alertRepo,
+		clock:            clk,
+		logger:           logger,
+		statuses:         make(map[string]*domain.QuotaStatus),
+		admitted:         make(map[string]int64),
+	}
+}
+
+// Start checks the quotas right away and then every interval, until the
+// context is canceled.
+func (e *Enforcer) Start(ctx context.Context, interval time.Duration) {
+	e.logger.Info("starting quota enforcement", "check_interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Check(ctx); err != nil && ctx.Err() == nil {
+			e.logger.Warn("failed to check quotas", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check evaluates the quota of every event manager that has one, against
+// its events this UTC day and month and its open alerts. Quota changes apply
+// from the next check. On error the last check stays in effect.
+func (e *Enforcer) Check(ctx context.Context) error {
+	eventManagers, err := e.eventManagerRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list event managers: %w", err)
+	}
+
+	now := e.clock.Now().UTC()
+	statuses := make(map[string]*domain.QuotaStatus)
+	var limited []*domain.EventManager
+	for _, em := range eventManagers {
+		if !em.IsDeleted() && em.Quota.IsEnabled() {
+			limited = append(limited, em)
+		}
+	}
+
+	if len(limited) > 0 {
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		usage, err := e.usageRepo.List(ctx, domain.ReportFilter{From: monthStart, To: monthStart.AddDate(0, 1, 0)})
+		if err != nil {
+			return fmt.Errorf("failed to list usage: %w", err)
+		}
+		active, err := e.alertRepo.CountActive(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to count active alerts: %w", err)
+		}
+
+		today := domain.ReportDay(now)
+		eventsToday := make(map[string]int64)
+		eventsThisMonth := make(map[string]int64)
+		for _, u := range usage {
+			eventsThisMonth[u.EventManagerID] += u.EventsIngested
+			if u.Day == today {
+				eventsToday[u.EventManagerID] += u.EventsIngested
+			}
+		}
+		openAlerts := make(map[string]int64)
+		for _, count := range active {
+			openAlerts[count.EventManagerID] += int64(count.Count)
+		}
+
+		for _, em := range limited {
+			statuses[em.ID] = em.Quota.Evaluate(eventsToday[em.ID], eventsThisMonth[em.ID], openAlerts[em.ID], now)
+		}
+	}
+
+	e.mu.Lock()
+	previous := e.statuses
+	e.statuses = statuses
+	e.admitted = make(map[string]int64)
+	e.mu.Unlock()
+
+	metrics.QuotaUtilization.Reset()
+	for id, status := range statuses {
+		recordUtilization(id, status)
+		if status.IsExceeded() && (previous[id] == nil || !previous[id].IsExceeded()) {
+			e.logger.Warn("event manager exhausted its quota",
+				"event_manager_id", id,
+				"exceeded", status.Exceeded,
+				"enforcement", status.Enforcement,
+			)
+		}
+	}
+	return nil
+}
+
+// recordUtilization sets the utilization metric of every limit of a quota.
+func recordUtilization(eventManagerID string, status *domain.QuotaStatus) {
+	limits := []struct {
+		name  string
+		used  int64
+		limit int64
+	}{
+		{domain.QuotaEventsPerDay, status.EventsToday, status.MaxEventsPerDay},
+		{domain.QuotaEventsPerMonth, status.EventsThisMonth, status.MaxEventsPerMonth},
+		{domain.QuotaOpenAlerts, status.OpenAlerts, status.MaxOpenAlerts},
+	}
+	for _, l := range limits {
+		if l.limit > 0 {
+			metrics.QuotaUtilization.WithLabelValues(eventManagerID, l.name).Set(float64(l.used) / float64(l.limit))
+		}
+	}
+}
+
+// Status returns the quota status of an event manager, counting the events
+// admitted since the last check, or nil if it has no quota.
+func (e *Enforcer) Status(eventManagerID string) *domain.QuotaStatus {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.status(eventManagerID)
+}
+
+// status returns the quota status of an event manager. The caller holds mu.
+func (e *Enforcer) status(eventManagerID string) *domain.QuotaStatus {
+	status, ok := e.statuses[eventManagerID]
+	if !ok {
+		return nil
+	}
+	admitted := e.admitted[eventManagerID]
+	if admitted == 0 {
+		return status
+	}
+	return status.Quota.Evaluate(
+		status.EventsToday+admitted,
+		status.EventsThisMonth+admitted,
+		status.OpenAlerts,
+		status.CheckedAt,
+	)
+}
+
+// SuppressedEventManagers returns the IDs of the event managers whose quota
+// suppresses notifications.
+func (e *Enforcer) SuppressedEventManagers() []string {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	var ids []string
+	for eventManagerID := range e.statuses {
+		if e.status(eventManagerID).Suppresses() {
+			ids = append(ids, eventManagerID)
+		}
+	}
+	return ids
+}
+
+// Admit decides on an event of an event manager. It returns an error
+// wrapping domain.ErrQuotaExceeded if the quota rejects the event, and
+// otherwise counts it toward the quota.
+func (e *Enforcer) Admit(eventManagerID string, action domain.Action) error {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	status := e.status(eventManagerID)
+	if status == nil {
+		return nil
+	}
+	if status.Rejects(action) {
+		metrics.QuotaRejectedEvents.WithLabelValues(eventManagerID).Inc()
+		return fmt.Errorf("%w: %s", domain.ErrQuotaExceeded, strings.Join(status.Exceeded, ", "))
+	}
+	e.admitted[eventManagerID]++
+	return nil
+}
+
+// Warnings returns a summary for every event manager whose quota is near
+// exhaustion or exhausted, by event manager ID.
+func (e *Enforcer) Warnings() map[string]string {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	warnings := make(map[string]string)
+	for id := range e.statuses {
+		status := e.status(id)
+		switch {
+		case status.IsExceeded():
+			warnings[id] = fmt.Sprintf("Event manager %s exhausted its quota: %s",
+				id, strings.Join(status.Exceeded, ", "))
+		case len(status.NearExhaustion) > 0:
+			warnings[id] = fmt.Sprintf("Event manager %s is near its quota: %s",
+				id, strings.Join(status.NearExhaustion, ", "))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,222 @@
+// Package remediation runs the remediation actions of event managers when
+// their alerts trigger.
+package remediation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+	"argus-go/internal/queue"
+	"argus-go/internal/store"
+)
+
+// Headers of remediation requests published to queues.
+const (
+	QueueActionHeader       = "action"
+	QueueEventManagerHeader = "event_manager_id"
+)
+
+// Request is the body of a remediation request, sent to the URL of an
+// action or published to its queue.
+type Request struct {
+	Action      string        `json:"action"`
+	Command     string        `json:"command,omitempty"`
+	Alert       *domain.Alert `json:"alert"`
+	RequestedAt time.Time     `json:"requested_at"`
+}
+
+// Executor runs remediation actions in the background, so alert processing
+// never waits on them. Every run is logged, recorded in the remediation log
+// and counted; failures are never returned to alert processing.
+type Executor struct {
+	queues  map[string]queue.Producer
+	log     store.RemediationLogRepository
+	client  *http.Client
+	timeout time.Duration
+	clock   clock.Clock
+	logger  *slog.Logger
+
+	mu sync.Mutex
+
+	// lastRun holds when each action last ran for an alert, by cooldownKey
+	lastRun map[string]time.Time
+
+	wg sync.WaitGroup
+}
+
+// NewExecutor creates an executor publishing to the named queues and
+// recording runs in log. timeout bounds each action.
+func NewExecutor(queues map[string]queue.Producer, log store.RemediationLogRepository, timeout time.Duration, clk clock.Clock, logger *slog.Logger) *Executor {
+	return &Executor{
+		queues:  queues,
+		log:     log,
+		client:  &http.Client{Timeout: timeout},
+		timeout: timeout,
+		clock:   clk,
+		logger:  logger,
+		lastRun: make(map[string]time.Time),
+	}
+}
+
+// Trigger runs the actions of the event manager whose guard selects the
+// event that triggered the alert. Actions still cooling down for the alert
+// are recorded as skipped. A nil executor runs nothing.
+func (e *Executor) Trigger(ctx context.Context, alert *domain.Alert, event *domain.Event, em *domain.EventManager) {
+	if e == nil || len(em.Remediations) == 0 {
+		return
+	}
+
+	// Snapshot the alert: processing goes on changing it
+	snapshot := *alert
+	now := e.clock.Now()
+	ctx = context.WithoutCancel(ctx)
+
+	for _, action := range em.Remediations {
+		if !action.Match.Matches(event) {
+			continue
+		}
+		if !e.claim(em.ID, action, alert.DedupKey, now) {
+			e.finish(ctx, &snapshot, em, action, now, domain.RemediationSkipped,
+				fmt.Sprintf("cooling down for %s", action.Cooldown))
+			continue
+		}
+
+		e.wg.Add(1)
+		go func() {
+			defer e.wg.Done()
+			status, detail := e.run(ctx, &snapshot, em, action, now)
+			e.finish(ctx, &snapshot, em, action, now, status, detail)
+		}()
+	}
+}
+
+// Wait blocks until the actions started so far have finished.
+func (e *Executor) Wait() {
+	if e != nil {
+		e.wg.Wait()
+	}
+}
+
+// claim reports whether the action may run for the alert at now, and if so
+// starts its cooldown.
+func (e *Executor) claim(emID string, action domain.RemediationAction, dedupKey string, now time.Time) bool {
+	key := cooldownKey(emID, action.Name, dedupKey)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if last, ok := e.lastRun[key]; ok && now.Sub(last) < time.Duration(action.Cooldown) {
+		return false
+	}
+	e.lastRun[key] = now
+	return true
+}
+
+// run carries out the action and returns its outcome.
+func (e *Executor) run(ctx context.Context, alert *domain.Alert, em *domain.EventManager, action domain.RemediationAction, now time.Time) (domain.RemediationStatus, string) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	body, err := json.Marshal(&Request{
+		Action:      action.Name,
+		Command:     action.Command,
+		Alert:       alert,
+		RequestedAt: now,
+	})
+	if err != nil {
+		return domain.RemediationFailed, err.Error()
+	}
+
+	if action.URL != "" {
+		return e.call(ctx, action.URL, body)
+	}
+	return e.publish(ctx, alert, em, action, body)
+}
+
+// call posts the request to url. A 2xx response is a success.
+func (e *Executor) call(ctx context.Context, url string, body []byte) (domain.RemediationStatus, string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return domain.RemediationFailed, err.Error()
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return domain.RemediationFailed, err.Error()
+	}
+	defer resp.Body.Close()
+
+	detail := fmt.Sprintf("HTTP %d", resp.StatusCode)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return domain.RemediationFailed, detail
+	}
+	return domain.RemediationSucceeded, detail
+}
+
+// publish sends the request to the queue of the action, keyed by dedup key.
+func (e *Executor) publish(ctx context.Context, alert *domain.Alert, em *domain.EventManager, action domain.RemediationAction, body []byte) (domain.RemediationStatus, string) {
+	producer, ok := e.queues[action.Queue]
+	if !ok {
+		return domain.RemediationFailed, fmt.Sprintf("unknown queue %s", action.Queue)
+	}
+
+	err := producer.Publish(ctx, &queue.Message{
+		Key:   []byte(alert.DedupKey),
+		Value: body,
+		Headers: map[string]string{
+			QueueActionHeader:       action.Name,
+			QueueEventManagerHeader: em.ID,
+		},
+	})
+	if err != nil {
+		return domain.RemediationFailed, err.Error()
+	}
+	return domain.RemediationSucceeded, "published"
+}
+
+// finish audits a run: it logs it, records it in the remediation log and
+// counts it.
+func (e *Executor) finish(ctx context.Context, alert *domain.Alert, em *domain.EventManager, action domain.RemediationAction, at time.Time, status domain.RemediationStatus, detail string) {
+	record := &domain.RemediationRecord{
+		DedupKey:       alert.DedupKey,
+		EventManagerID: em.ID,
+		Action:         action.Name,
+		Target:         action.Target(),
+		Status:         status,
+		Detail:         detail,
+		ExecutedAt:     at,
+	}
+
+	e.logger.Info("remediation action executed",
+		"dedupKey", record.DedupKey,
+		"event_manager_id", record.EventManagerID,
+		"action", record.Action,
+		"target", record.Target,
+		"status", record.Status,
+		"detail", record.Detail,
+	)
+	metrics.RemediationActions.WithLabelValues(string(status)).Inc()
+
+	if err := e.log.Record(ctx, record); err != nil {
+		e.logger.Warn("failed to record remediation",
+			"dedupKey", record.DedupKey,
+			"action", record.Action,
+			"error", err,
+		)
+	}
+}
+
+// cooldownKey identifies the cooldown of an action for an alert.
+func cooldownKey(emID, action, dedupKey string) string {
+	return emID + "\x00" + action + "\x00" + dedupKey
+}
@@ -0,0 +1,105 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
+	"argus-go/internal/queue"
+	memoryqueue "argus-go/internal/queue/memory"
+	storemem "argus-go/internal/store/memory"
+)
+
+func TestExecutor_Trigger(t *testing.T) {
+	var mu sync.Mutex
+	var requests []Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+		if req.Action == "broken" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	ops := memoryqueue.NewQueue(10)
+	defer ops.Close()
+
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	log := storemem.NewRemediationLogRepository()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	executor := NewExecutor(map[string]queue.Producer{"ops": ops}, log, time.Second, clk, logger)
+
+	em := &domain.EventManager{ID: "em-1", Remediations: []domain.RemediationAction{
+		{Name: "restart", URL: server.URL, Cooldown: domain.Duration(10 * time.Minute)},
+		{Name: "broken", URL: server.URL},
+		{Name: "scale", Queue: "ops", Command: "scale-up"},
+		{Name: "db-only", URL: server.URL, Match: domain.EventMatcher{Classes: []string{"database"}}},
+	}}
+	alert := &domain.Alert{DedupKey: "web-down", EventManagerID: "em-1"}
+	event := &domain.Event{DedupKey: "web-down", Class: "web"}
+
+	executor.Trigger(context.Background(), alert, event, em)
+	executor.Wait()
+
+	// Within the cooldown the restart is skipped, the others run again
+	clk.Advance(time.Minute)
+	executor.Trigger(context.Background(), alert, event, em)
+	executor.Wait()
+
+	records, err := log.ListByDedupKey(context.Background(), "web-down")
+	if err != nil {
+		t.Fatalf("ListByDedupKey() error = %v", err)
+	}
+	got := map[string][]domain.RemediationStatus{}
+	for _, record := range records {
+		got[record.Action] = append(got[record.Action], record.Status)
+	}
+
+	want := map[string][]domain.RemediationStatus{
+		"restart": {domain.RemediationSucceeded, domain.RemediationSkipped},
+		"broken":  {domain.RemediationFailed, domain.RemediationFailed},
+		"scale":   {domain.RemediationSucceeded, domain.RemediationSucceeded},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("actions run = %v, want %v", got, want)
+	}
+	for action, statuses := range want {
+		if len(got[action]) != len(statuses) {
+			t.Errorf("%s statuses = %v, want %v", action, got[action], statuses)
+			continue
+		}
+		// Runs of one trigger finish in any order, but triggers don't overlap
+		for i := range statuses {
+			if got[action][i] != statuses[i] {
+				t.Errorf("%s statuses = %v, want %v", action, got[action], statuses)
+			}
+		}
+	}
+	if len(requests) != 3 {
+		t.Errorf("HTTP requests = %d, want 3", len(requests))
+	}
+	if ops.Len() != 2 {
+		t.Errorf("published commands = %d, want 2", ops.Len())
+	}
+}
+
+func TestExecutor_NilRunsNothing(t *testing.T) {
+	var executor *Executor
+	em := &domain.EventManager{Remediations: []domain.RemediationAction{{Name: "restart", URL: "http://127.0.0.1:1"}}}
+	executor.Trigger(context.Background(), &domain.Alert{}, &domain.Event{}, em)
+	executor.Wait()
+}
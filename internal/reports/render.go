@@ -0,0 +1,153 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/notification"
+)
+
+// Content types of report emails and attachments.
+const (
+	contentTypeText = "text/plain"
+	contentTypeHTML = "text/html"
+	contentTypeCSV  = "text/csv"
+)
+
+// htmlReport is the body of HTML report emails.
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"seconds": formatSeconds,
+}).Parse(`<html>
+<body>
+<h2>{{.Title}}</h2>
+<p>{{.Range}} (UTC)</p>
+<h3>Alert volume and MTTR</h3>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Event manager</th><th>Alerts</th><th>Acknowledged</th><th>Resolved</th><th>MTTA (s)</th><th>MTTR (s)</th></tr>
+{{- range .Report.EventManagers}}
+<tr><td>{{.EventManagerID}}</td><td>{{.AlertCount}}</td><td>{{.AcknowledgedCount}}</td><td>{{.ResolvedCount}}</td><td>{{seconds .MTTASeconds}}</td><td>{{seconds .MTTRSeconds}}</td></tr>
+{{- else}}
+<tr><td colspan="6">No alerts</td></tr>
+{{- end}}
+</table>
+<h3>Top offenders</h3>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Event manager</th><th>Dedup key</th><th>Summary</th><th>Triggers</th></tr>
+{{- range .Report.TopOffenders}}
+<tr><td>{{.EventManagerID}}</td><td>{{.DedupKey}}</td><td>{{.Summary}}</td><td>{{.TriggerCount}}</td></tr>
+{{- else}}
+<tr><td colspan="4">No alerts</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// Render turns a report into the email of its schedule's format: an HTML
+// body, or a plain text body with the event manager and top offender
+// tables attached as CSV files.
+func Render(report *domain.ScheduledReport) (*notification.PluginReport, error) {
+	schedule := report.Schedule
+	email := &notification.PluginReport{
+		ScheduleID:     schedule.ID,
+		EventManagerID: schedule.EventManagerID,
+		Recipients:     schedule.Recipients,
+		Subject:        "[ArgusGo] " + title(report),
+	}
+
+	if schedule.Format == domain.ReportHTML {
+		var body bytes.Buffer
+		err := htmlReport.Execute(&body, map[string]any{
+			"Title":  title(report),
+			"Range":  period(report),
+			"Report": report,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to render report: %w", err)
+		}
+		email.Body = body.String()
+		email.ContentType = contentTypeHTML
+		return email, nil
+	}
+
+	eventManagers := [][]string{{"event_manager_id", "alerts", "acknowledged", "resolved", "mtta_seconds", "mttr_seconds"}}
+	for _, stats := range report.EventManagers {
+		eventManagers = append(eventManagers, []string{
+			stats.EventManagerID,
+			strconv.Itoa(stats.AlertCount),
+			strconv.Itoa(stats.AcknowledgedCount),
+			strconv.Itoa(stats.ResolvedCount),
+			formatSeconds(stats.MTTASeconds),
+			formatSeconds(stats.MTTRSeconds),
+		})
+	}
+	offenders := [][]string{{"event_manager_id", "dedup_key", "summary", "trigger_count"}}
+	for _, volume := range report.TopOffenders {
+		offenders = append(offenders, []string{
+			volume.EventManagerID,
+			volume.DedupKey,
+			volume.Summary,
+			strconv.Itoa(volume.TriggerCount),
+		})
+	}
+
+	for _, table := range []struct {
+		filename string
+		rows     [][]string
+	}{
+		{"event_managers.csv", eventManagers},
+		{"top_offenders.csv", offenders},
+	} {
+		content, err := encodeCSV(table.rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", table.filename, err)
+		}
+		email.Attachments = append(email.Attachments, notification.PluginAttachment{
+			Filename:    table.filename,
+			ContentType: contentTypeCSV,
+			Content:     content,
+		})
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s\n\n", title(report))
+	fmt.Fprintf(&body, "Alert volume, MTTR and top offenders %s (UTC) are attached.\n", period(report))
+	email.Body = body.String()
+	email.ContentType = contentTypeText
+	return email, nil
+}
+
+// title names the report by its schedule and frequency.
+func title(report *domain.ScheduledReport) string {
+	return fmt.Sprintf("%s: %s report", report.Schedule.Name, report.Schedule.Frequency)
+}
+
+// period describes the days a report covers.
+func period(report *domain.ScheduledReport) string {
+	first := domain.ReportDay(report.From)
+	last := domain.ReportDay(report.To.AddDate(0, 0, -1))
+	if first == last {
+		return "on " + first
+	}
+	return "from " + first + " to " + last
+}
+
+// encodeCSV writes rows as CSV.
+func encodeCSV(rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatSeconds formats a mean time in whole seconds.
+func formatSeconds(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', 0, 64)
+}
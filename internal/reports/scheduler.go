@@ -0,0 +1,164 @@
+// Package reports sends scheduled reports: every day or week, the alert
+// volume, MTTR and top offenders of event managers are emailed to the
+// recipients of each report schedule through a notifier plugin.
+package reports
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+	"argus-go/internal/notification"
+	"argus-go/internal/store"
+)
+
+// Results of sending a scheduled report, the "result" label of
+// metrics.ScheduledReports.
+const (
+	resultSent   = "sent"
+	resultFailed = "failed"
+)
+
+// Sender emails scheduled reports, such as a notifier plugin.
+type Sender interface {
+	SendReport(ctx context.Context, report *notification.PluginReport) error
+}
+
+// Scheduler sends the reports of report schedules as they fall due. Every
+// replica may run one: each report is claimed in the store before it is
+// sent, so it is sent once.
+type Scheduler struct {
+	schedules store.ReportScheduleRepository
+	reports   store.ReportRepository
+	sender    Sender
+	scrubber  *domain.Scrubber
+	interval  time.Duration
+	clock     clock.Clock
+	logger    *slog.Logger
+}
+
+// NewScheduler creates a scheduler of the report schedules in schedules,
+// checked every interval, compiling reports from reports and emailing them
+// through sender. Alert summaries are masked with scrubber, which may be nil.
+func NewScheduler(schedules store.ReportScheduleRepository, reports store.ReportRepository, sender Sender, scrubber *domain.Scrubber, interval time.Duration, clk clock.Clock, logger *slog.Logger) *Scheduler {
+	return &Scheduler{
+		schedules: schedules,
+		reports:   reports,
+		sender:    sender,
+		scrubber:  scrubber,
+		interval:  interval,
+		clock:     clk,
+		logger:    logger,
+	}
+}
+
+// Start checks the schedules right away and then every interval, until the
+// context is canceled.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.logger.Info("starting report scheduler", "check_interval", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Check(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Warn("failed to check report schedules", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check sends the reports due. A report that fails is logged and retried on
+// the next check, without holding up the others.
+func (s *Scheduler) Check(ctx context.Context) error {
+	schedules, err := s.schedules.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.clock.Now().UTC()
+	for _, schedule := range schedules {
+		if !schedule.Due(now) {
+			continue
+		}
+		from, to := schedule.Period(now)
+		if err := s.send(ctx, schedule, from, to); err != nil {
+			metrics.ScheduledReports.WithLabelValues(resultFailed).Inc()
+			s.logger.Warn("failed to send scheduled report", "schedule_id", schedule.ID, "from", from, "to", to, "error", err)
+		}
+	}
+	return nil
+}
+
+// send claims the report of a period, so no other replica sends it, and
+// emails it. The claim is released if the report can't be sent, so the next
+// check retries it.
+func (s *Scheduler) send(ctx context.Context, schedule *domain.ReportSchedule, from, to time.Time) error {
+	claimed, err := s.schedules.MarkSent(ctx, schedule.ID, schedule.LastReportTo, &to)
+	if err != nil || !claimed {
+		return err
+	}
+
+	if err := s.deliver(ctx, schedule, from, to); err != nil {
+		if _, releaseErr := s.schedules.MarkSent(ctx, schedule.ID, &to, schedule.LastReportTo); releaseErr != nil {
+			s.logger.Warn("failed to release scheduled report", "schedule_id", schedule.ID, "error", releaseErr)
+		}
+		return err
+	}
+
+	metrics.ScheduledReports.WithLabelValues(resultSent).Inc()
+	s.logger.Info("sent scheduled report",
+		"schedule_id", schedule.ID,
+		"from", from,
+		"to", to,
+		"recipients", len(schedule.Recipients),
+	)
+	return nil
+}
+
+// deliver compiles, renders and emails the report of a period.
+func (s *Scheduler) deliver(ctx context.Context, schedule *domain.ReportSchedule, from, to time.Time) error {
+	report, err := s.Compile(ctx, schedule, from, to)
+	if err != nil {
+		return err
+	}
+	for _, volume := range report.TopOffenders {
+		volume.Summary = s.scrubber.String(volume.Summary)
+	}
+	email, err := Render(report)
+	if err != nil {
+		return err
+	}
+	return s.sender.SendReport(ctx, email)
+}
+
+// Compile gathers the report of a schedule for the period [from, to).
+func (s *Scheduler) Compile(ctx context.Context, schedule *domain.ReportSchedule, from, to time.Time) (*domain.ScheduledReport, error) {
+	filter := schedule.Filter(from, to)
+
+	mttr, err := s.reports.MTTR(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute mttr: %w", err)
+	}
+	top, err := s.reports.TopDedupKeys(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute top offenders: %w", err)
+	}
+
+	return &domain.ScheduledReport{
+		Schedule:      schedule,
+		From:          from,
+		To:            to,
+		EventManagers: mttr,
+		TopOffenders:  top,
+	}, nil
+}
@@ -0,0 +1,141 @@
+package reports
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
+	"argus-go/internal/notification"
+	storemem "argus-go/internal/store/memory"
+)
+
+// fakeSender records the reports it is asked to send, failing while err is
+// set.
+type fakeSender struct {
+	mu      sync.Mutex
+	err     error
+	reports []*notification.PluginReport
+}
+
+func (s *fakeSender) SendReport(ctx context.Context, report *notification.PluginReport) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.reports = append(s.reports, report)
+	return nil
+}
+
+func TestScheduler_SendsDueReportsOnce(t *testing.T) {
+	ctx := context.Background()
+	alerts := storemem.NewAlertRepository()
+	schedules := storemem.NewReportScheduleRepository()
+	clk := clock.NewFake(time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC))
+	sender := &fakeSender{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	newScheduler := func() *Scheduler {
+		return NewScheduler(schedules, storemem.NewReportRepository(alerts), sender, nil, time.Minute, clk, logger)
+	}
+
+	resolvedAt := time.Date(2026, 3, 4, 10, 10, 0, 0, time.UTC)
+	_ = alerts.Create(ctx, &domain.Alert{
+		ID: "1", DedupKey: "db-1:disk", Summary: "Disk full", EventManagerID: "em-1",
+		Status: domain.AlertStatusResolved, TriggerCount: 7,
+		CreatedAt: time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC), ResolvedAt: &resolvedAt,
+	})
+	_ = schedules.Create(ctx, &domain.ReportSchedule{
+		ID: "daily", Name: "Ops", Frequency: domain.ReportDaily, Format: domain.ReportCSV,
+		Recipients: []string{"ops@example.com"}, CreatedAt: clk.Now(),
+	})
+
+	// Nothing is due on the day the schedule is created
+	if err := newScheduler().Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(sender.reports) != 0 {
+		t.Fatalf("sent %d reports on the day of creation, want 0", len(sender.reports))
+	}
+
+	// A failed report is retried on the next check
+	clk.Advance(24 * time.Hour)
+	sender.err = errors.New("smtp unavailable")
+	if err := newScheduler().Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	sender.err = nil
+
+	// Two replicas checking at once send the report once
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := newScheduler().Check(ctx); err != nil {
+				t.Errorf("Check() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if len(sender.reports) != 1 {
+		t.Fatalf("sent %d reports, want 1", len(sender.reports))
+	}
+
+	report := sender.reports[0]
+	if report.ContentType != contentTypeText || !strings.Contains(report.Body, "on 2026-03-04") {
+		t.Errorf("report body = %q (%s), want a plain text summary of 2026-03-04", report.Body, report.ContentType)
+	}
+	if len(report.Attachments) != 2 {
+		t.Fatalf("attachments = %d, want 2", len(report.Attachments))
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(report.Attachments[0].Content))).ReadAll()
+	if err != nil {
+		t.Fatalf("event managers CSV: %v", err)
+	}
+	if len(rows) != 2 || rows[1][0] != "em-1" || rows[1][1] != "1" || rows[1][5] != "600" {
+		t.Errorf("event managers CSV = %v, want em-1 with 1 alert resolved in 600s", rows)
+	}
+	if !strings.Contains(string(report.Attachments[1].Content), "db-1:disk,Disk full,7") {
+		t.Errorf("top offenders CSV = %q, want db-1:disk", report.Attachments[1].Content)
+	}
+
+	schedule, _ := schedules.GetByID(ctx, "daily")
+	if schedule.LastReportTo == nil || !schedule.LastReportTo.Equal(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("LastReportTo = %v, want the end of 2026-03-04", schedule.LastReportTo)
+	}
+}
+
+func TestRender_HTML(t *testing.T) {
+	report := &domain.ScheduledReport{
+		Schedule: &domain.ReportSchedule{ID: "weekly", Name: "Ops <team>", Frequency: domain.ReportWeekly, Format: domain.ReportHTML},
+		From:     time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		EventManagers: []*domain.MTTRStats{
+			{EventManagerID: "em-1", AlertCount: 3, MTTRSeconds: 90.4},
+		},
+	}
+
+	email, err := Render(report)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if email.ContentType != contentTypeHTML || len(email.Attachments) != 0 {
+		t.Errorf("email = %s with %d attachments, want HTML without attachments", email.ContentType, len(email.Attachments))
+	}
+	for _, want := range []string{"Ops &lt;team&gt;: weekly report", "from 2026-02-23 to 2026-03-01", "<td>em-1</td><td>3</td>", "<td>90</td>"} {
+		if !strings.Contains(email.Body, want) {
+			t.Errorf("body does not contain %q:\n%s", want, email.Body)
+		}
+	}
+	if email.Subject != "[ArgusGo] Ops <team>: weekly report" {
+		t.Errorf("Subject = %q", email.Subject)
+	}
+}
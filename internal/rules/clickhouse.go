@@ -0,0 +1,103 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+)
+
+// maxClickHouseErrorBody bounds the part of an error response kept in the
+// error.
+const maxClickHouseErrorBody = 512
+
+// ClickHouse runs SQL queries against the HTTP interface of ClickHouse. The
+// params of a rule are bound to the query parameters of the same name, e.g.
+// {service:String}, and the evaluation time to {now:UInt32}.
+type ClickHouse struct {
+	url      string
+	username string
+	password string
+	database string
+	client   *http.Client
+}
+
+// NewClickHouse creates a datasource querying the configured ClickHouse.
+func NewClickHouse(cfg config.DatasourceConfig, client *http.Client) *ClickHouse {
+	return &ClickHouse{
+		url:      strings.TrimSuffix(cfg.URL, "/"),
+		username: cfg.Username,
+		password: cfg.Password,
+		database: cfg.Database,
+		client:   client,
+	}
+}
+
+// clickHouseResponse is a result in the JSONCompact format.
+type clickHouseResponse struct {
+	Meta []struct {
+		Name string `json:"name"`
+	} `json:"meta"`
+	Data [][]any `json:"data"`
+}
+
+// Query runs a SQL query with the params of its rule, and the evaluation
+// time as the now parameter.
+func (c *ClickHouse) Query(ctx context.Context, query string, params map[string]string, at time.Time) ([]domain.QuerySeries, error) {
+	values := url.Values{}
+	values.Set("default_format", "JSONCompact")
+	if c.database != "" {
+		values.Set("database", c.database)
+	}
+	for name, value := range params {
+		values.Set("param_"+name, value)
+	}
+	values.Set("param_"+domain.QueryParamNow, strconv.FormatInt(at.Unix(), 10))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/?"+values.Encode(), strings.NewReader(query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build clickhouse request: %w", err)
+	}
+	if c.username != "" {
+		req.Header.Set("X-ClickHouse-User", c.username)
+		req.Header.Set("X-ClickHouse-Key", c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse query failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxClickHouseErrorBody))
+		return nil, fmt.Errorf("clickhouse query failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	// 64-bit integers are sent as strings, other numbers as JSON numbers
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	var result clickHouseResponse
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode clickhouse response: %w", err)
+	}
+
+	columns := make([]string, len(result.Meta))
+	for i, column := range result.Meta {
+		columns[i] = column.Name
+	}
+	for _, row := range result.Data {
+		if len(row) != len(columns) {
+			return nil, fmt.Errorf("clickhouse row has %d columns, want %d", len(row), len(columns))
+		}
+	}
+	return tableSeries(columns, result.Data)
+}
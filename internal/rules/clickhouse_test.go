@@ -0,0 +1,79 @@
+package rules
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"argus-go/internal/config"
+)
+
+func TestClickHouse_Query(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	query := "SELECT service, count() AS value FROM errors WHERE env = {env:String} AND ts > toDateTime({now:UInt32}) - 300 GROUP BY service"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != query {
+			t.Errorf("query = %q, want %q", body, query)
+		}
+		params := r.URL.Query()
+		if params.Get("param_env") != "prod" || params.Get("param_now") != "1772366400" || params.Get("database") != "logs" {
+			t.Errorf("params = %v, want env, now and database", params)
+		}
+		if r.Header.Get("X-ClickHouse-User") != "argus" || r.Header.Get("X-ClickHouse-Key") != "secret" {
+			t.Errorf("credentials = %s/%s", r.Header.Get("X-ClickHouse-User"), r.Header.Get("X-ClickHouse-Key"))
+		}
+		_, _ = w.Write([]byte(`{
+			"meta": [{"name": "service", "type": "String"}, {"name": "value", "type": "UInt64"}],
+			"data": [["api", "153"], ["web", 2.5]],
+			"rows": 2
+		}`))
+	}))
+	defer server.Close()
+
+	ch := NewClickHouse(config.DatasourceConfig{URL: server.URL, Username: "argus", Password: "secret", Database: "logs"}, server.Client())
+	series, err := ch.Query(context.Background(), query, map[string]string{"env": "prod"}, at)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(series) != 2 || series[0].Labels["service"] != "api" || series[0].Value != 153 || series[1].Value != 2.5 {
+		t.Errorf("series = %+v", series)
+	}
+}
+
+func TestTableSeries(t *testing.T) {
+	// Rows are valued by the value column, skipping NULL values
+	series, err := tableSeries([]string{"host", "value"}, [][]any{{"db-1", int64(3)}, {"db-2", nil}, {nil, []byte("1.5")}})
+	if err != nil {
+		t.Fatalf("tableSeries() error = %v", err)
+	}
+	if len(series) != 2 || series[0].Labels["host"] != "db-1" || series[0].Value != 3 || len(series[1].Labels) != 0 || series[1].Value != 1.5 {
+		t.Errorf("series = %+v", series)
+	}
+
+	// Without a value column, rows are counted per set of labels
+	series, err = tableSeries([]string{"host"}, [][]any{{"db-1"}, {"db-2"}, {"db-1"}})
+	if err != nil {
+		t.Fatalf("tableSeries() error = %v", err)
+	}
+	if len(series) != 2 || series[0].Labels["host"] != "db-1" || series[0].Value != 2 || series[1].Value != 1 {
+		t.Errorf("counted series = %+v", series)
+	}
+
+	// Nested objects are labelled by the dotted paths of their leaves
+	series, err = tableSeries([]string{"kubernetes", "value"}, [][]any{{map[string]any{"pod": map[string]any{"name": "api-1"}, "node": nil}, 1.0}})
+	if err != nil {
+		t.Fatalf("tableSeries() error = %v", err)
+	}
+	if len(series) != 1 || len(series[0].Labels) != 1 || series[0].Labels["kubernetes.pod.name"] != "api-1" {
+		t.Errorf("nested series = %+v", series)
+	}
+
+	if _, err := tableSeries([]string{"value"}, [][]any{{"many"}}); err == nil {
+		t.Error("tableSeries() of a non-numeric value error = nil, want an error")
+	}
+}
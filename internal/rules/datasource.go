@@ -0,0 +1,170 @@
+package rules
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+)
+
+// Datasource runs the queries of rules.
+type Datasource interface {
+	// Query runs a query evaluated at the given time, with the parameters
+	// of its rule, and returns the series of its result.
+	Query(ctx context.Context, query string, params map[string]string, at time.Time) ([]domain.QuerySeries, error)
+}
+
+// NewDatasources creates the configured datasources, by name. timeout bounds
+// each query.
+func NewDatasources(cfgs []config.DatasourceConfig, timeout time.Duration) (map[string]Datasource, error) {
+	client := &http.Client{Timeout: timeout}
+	datasources := make(map[string]Datasource, len(cfgs))
+	for _, cfg := range cfgs {
+		switch cfg.Type {
+		case config.DatasourceTypeLoki:
+			datasources[cfg.Name] = NewLoki(cfg, client)
+		case config.DatasourceTypeClickHouse:
+			datasources[cfg.Name] = NewClickHouse(cfg, client)
+		case config.DatasourceTypeSQL:
+			db, err := sql.Open(cfg.Driver, cfg.URL)
+			if err != nil {
+				return nil, fmt.Errorf("datasource %s: %w", cfg.Name, err)
+			}
+			datasources[cfg.Name] = NewSQL(db, cfg.Driver)
+		default:
+			return nil, fmt.Errorf("datasource %s: unknown type %q", cfg.Name, cfg.Type)
+		}
+	}
+	return datasources, nil
+}
+
+// ValueColumn is the column of a SQL result holding the value of its row.
+const ValueColumn = "value"
+
+// tableSeries converts the rows of a SQL result to series. The other columns
+// of a row are its labels; nested objects, e.g. ClickHouse maps and named
+// tuples, make a label per leaf named by its dotted path, like
+// kubernetes.pod.name. If the result has a value column, each row is a
+// series with that value, and rows whose value is NULL are skipped;
+// otherwise each set of labels is a series valued by its number of rows.
+func tableSeries(columns []string, rows [][]any) ([]domain.QuerySeries, error) {
+	valueIndex := slices.Index(columns, ValueColumn)
+	series := make([]domain.QuerySeries, 0, len(rows))
+	counts := make(map[string]int)
+	for _, row := range rows {
+		labels := make(map[string]string, len(columns))
+		for i, column := range columns {
+			if i != valueIndex {
+				addLabels(labels, column, row[i])
+			}
+		}
+
+		if valueIndex < 0 {
+			key := labelsKey(labels)
+			if i, ok := counts[key]; ok {
+				series[i].Value++
+				continue
+			}
+			counts[key] = len(series)
+			series = append(series, domain.QuerySeries{Labels: labels, Value: 1})
+			continue
+		}
+
+		if row[valueIndex] == nil {
+			continue
+		}
+		value, err := numericValue(row[valueIndex])
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, domain.QuerySeries{Labels: labels, Value: value})
+	}
+	return series, nil
+}
+
+// labelsKey identifies a set of labels.
+func labelsKey(labels map[string]string) string {
+	var b strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(labels)) {
+		b.WriteString(strconv.Quote(name))
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(labels[name]))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+// addLabels adds the label of a column value, or the labels of the leaves of
+// a nested object under their dotted paths. NULL values add no label.
+func addLabels(labels map[string]string, name string, v any) {
+	switch v := v.(type) {
+	case nil:
+	case map[string]any:
+		for key, nested := range v {
+			addLabels(labels, name+"."+key, nested)
+		}
+	default:
+		labels[name] = labelValue(v)
+	}
+}
+
+// labelValue formats a column value as a label.
+func labelValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
+
+// numericValue converts the value column of a row to a number.
+func numericValue(v any) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case json.Number:
+		return parseValue(string(v))
+	case string:
+		return parseValue(v)
+	case []byte:
+		return parseValue(string(v))
+	}
+	return 0, fmt.Errorf("non-numeric %s column of type %T", ValueColumn, v)
+}
+
+// parseValue parses a numeric value sent as text.
+func parseValue(text string) (float64, error) {
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("non-numeric %s column %q", ValueColumn, text)
+	}
+	return value, nil
+}
@@ -0,0 +1,149 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+)
+
+// maxLokiErrorBody bounds the part of an error response kept in the error.
+const maxLokiErrorBody = 512
+
+// Loki runs LogQL queries against the instant query API of Grafana Loki.
+// Metric queries, e.g. sum by (service) (count_over_time({app="api"} |=
+// "error" [5m])), return a series per label set with its value; log queries
+// return a series per stream, whose value is the number of lines matched.
+type Loki struct {
+	url    string
+	tenant string
+	client *http.Client
+}
+
+// NewLoki creates a datasource querying the configured Loki.
+func NewLoki(cfg config.DatasourceConfig, client *http.Client) *Loki {
+	return &Loki{
+		url:    strings.TrimSuffix(cfg.URL, "/"),
+		tenant: cfg.Tenant,
+		client: client,
+	}
+}
+
+// lokiResponse is the response of the instant query API.
+type lokiResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// lokiSample is a [timestamp, "value"] pair.
+type lokiSample [2]any
+
+// Query runs a LogQL query evaluated at the given time. LogQL has no
+// parameters; rules querying Loki have no params.
+func (l *Loki) Query(ctx context.Context, query string, params map[string]string, at time.Time) ([]domain.QuerySeries, error) {
+	values := url.Values{}
+	values.Set("query", query)
+	values.Set("time", strconv.FormatInt(at.UnixNano(), 10))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.url+"/loki/api/v1/query?"+values.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build loki request: %w", err)
+	}
+	if l.tenant != "" {
+		req.Header.Set("X-Scope-OrgID", l.tenant)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("loki query failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxLokiErrorBody))
+		return nil, fmt.Errorf("loki query failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var body lokiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode loki response: %w", err)
+	}
+	if body.Status != "success" {
+		return nil, fmt.Errorf("loki query failed: %s", body.Error)
+	}
+	return parseLokiResult(body.Data.ResultType, body.Data.Result)
+}
+
+// parseLokiResult converts the result of an instant query to series.
+func parseLokiResult(resultType string, result json.RawMessage) ([]domain.QuerySeries, error) {
+	switch resultType {
+	case "vector":
+		var vector []struct {
+			Metric map[string]string `json:"metric"`
+			Value  lokiSample        `json:"value"`
+		}
+		if err := json.Unmarshal(result, &vector); err != nil {
+			return nil, fmt.Errorf("failed to decode loki vector: %w", err)
+		}
+		series := make([]domain.QuerySeries, 0, len(vector))
+		for _, sample := range vector {
+			value, err := sample.Value.value()
+			if err != nil {
+				return nil, err
+			}
+			series = append(series, domain.QuerySeries{Labels: sample.Metric, Value: value})
+		}
+		return series, nil
+
+	case "scalar":
+		var sample lokiSample
+		if err := json.Unmarshal(result, &sample); err != nil {
+			return nil, fmt.Errorf("failed to decode loki scalar: %w", err)
+		}
+		value, err := sample.value()
+		if err != nil {
+			return nil, err
+		}
+		return []domain.QuerySeries{{Value: value}}, nil
+
+	case "streams":
+		var streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		}
+		if err := json.Unmarshal(result, &streams); err != nil {
+			return nil, fmt.Errorf("failed to decode loki streams: %w", err)
+		}
+		series := make([]domain.QuerySeries, 0, len(streams))
+		for _, stream := range streams {
+			series = append(series, domain.QuerySeries{Labels: stream.Stream, Value: float64(len(stream.Values))})
+		}
+		return series, nil
+	}
+	return nil, fmt.Errorf("unsupported loki result type %q", resultType)
+}
+
+// value parses the value of a sample.
+func (s lokiSample) value() (float64, error) {
+	text, ok := s[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("invalid loki sample value %v", s[1])
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid loki sample value %q", text)
+	}
+	return value, nil
+}
@@ -0,0 +1,64 @@
+package rules
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"argus-go/internal/config"
+)
+
+func TestLoki_Query(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	responses := map[string]string{
+		"metric": `{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"service":"api"},"value":[1772366400,"153"]},
+			{"metric":{"service":"web"},"value":[1772366400,"2.5"]}]}}`,
+		"logs": `{"status":"success","data":{"resultType":"streams","result":[
+			{"stream":{"app":"api"},"values":[["1772366400000000000","error a"],["1772366400000000001","error b"]]}]}}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/loki/api/v1/query" {
+			t.Errorf("path = %s, want /loki/api/v1/query", r.URL.Path)
+		}
+		if got := r.Header.Get("X-Scope-OrgID"); got != "team-a" {
+			t.Errorf("X-Scope-OrgID = %q, want team-a", got)
+		}
+		if got := r.URL.Query().Get("time"); got != "1772366400000000000" {
+			t.Errorf("time = %s, want the evaluation time in nanoseconds", got)
+		}
+		body, ok := responses[r.URL.Query().Get("query")]
+		if !ok {
+			http.Error(w, "parse error", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	loki := NewLoki(config.DatasourceConfig{URL: server.URL + "/", Tenant: "team-a"}, server.Client())
+	ctx := context.Background()
+
+	series, err := loki.Query(ctx, "metric", nil, at)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(series) != 2 || series[0].Labels["service"] != "api" || series[0].Value != 153 || series[1].Value != 2.5 {
+		t.Errorf("vector series = %+v", series)
+	}
+
+	series, err = loki.Query(ctx, "logs", nil, at)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(series) != 1 || series[0].Labels["app"] != "api" || series[0].Value != 2 {
+		t.Errorf("streams series = %+v, want the line count", series)
+	}
+
+	if _, err := loki.Query(ctx, "invalid", nil, at); err == nil {
+		t.Error("Query() of a failing query error = nil, want an error")
+	}
+}
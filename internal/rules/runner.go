@@ -0,0 +1,429 @@
+// Package rules runs query rules: queries against external datasources, such
+// as LogQL queries against Grafana Loki or SQL queries against ClickHouse,
+// whose result series raise alerts while their value breaches a threshold.
+// Events are ingested through the normal pipeline for the event manager of
+// each rule, so rule alerts are grouped, notified and resolved like any
+// other.
+package rules
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+	"argus-go/internal/store"
+)
+
+// Source is the source of the events of query rules.
+const Source = "argus-rules"
+
+// RuleIDLabel is the label of rule events naming their rule.
+const RuleIDLabel = "rule_id"
+
+// Results of rule runs, used as the label of the evaluation metric.
+const (
+	resultOK     = "ok"
+	resultFailed = "failed"
+)
+
+// Ingester publishes events into the pipeline.
+type Ingester interface {
+	IngestEvent(ctx context.Context, event *domain.Event) error
+}
+
+// ruleState is what a runner remembers of a rule between runs.
+type ruleState struct {
+	// eventManagerID received the events of the rule.
+	eventManagerID string
+
+	// firing holds the dedup keys of the series breaching the condition.
+	firing map[string]bool
+
+	// lastRun is when the rule last ran.
+	lastRun time.Time
+
+	// lastBreached is when the rule last ran with a series breaching the
+	// condition, for the composite rules referencing it.
+	lastBreached time.Time
+}
+
+// Runner runs the query rules due and ingests a trigger event for each series
+// that started breaching its condition, and a resolve event for each one
+// that stopped, or is gone from the result. Every replica runs every rule,
+// unless they are sharded: each rule then runs on one of the replicas in the
+// registry, and moves when its replica leaves or misses its heartbeats. The
+// events of a breach share a dedup key, so replicas running a rule at once,
+// during a move, raise one alert. It is not safe for concurrent use.
+type Runner struct {
+	repo        store.QueryRuleRepository
+	executions  store.RuleExecutionRepository
+	registry    store.RunnerRegistry
+	alertRepo   store.AlertRepository
+	ingester    Ingester
+	datasources map[string]Datasource
+	cfg         config.RulesConfig
+	clock       clock.Clock
+	logger      *slog.Logger
+
+	// states holds the state of each rule run, by rule ID. A rule not run
+	// yet is absent; its first run loads the breaches from its active
+	// alerts, so a restart doesn't leave them open.
+	states map[string]*ruleState
+
+	// instances are the IDs of the live replicas sharing the rules, as of
+	// the last heartbeat.
+	instances []string
+}
+
+// NewRunner creates a runner of the rules in repo against the datasources,
+// recording their runs in executions. If sharding is enabled, the rules are
+// shared with the replicas in registry.
+func NewRunner(
+	repo store.QueryRuleRepository,
+	executions store.RuleExecutionRepository,
+	registry store.RunnerRegistry,
+	alertRepo store.AlertRepository,
+	ingester Ingester,
+	datasources map[string]Datasource,
+	cfg config.RulesConfig,
+	clk clock.Clock,
+	logger *slog.Logger,
+) *Runner {
+	return &Runner{
+		repo:        repo,
+		executions:  executions,
+		registry:    registry,
+		alertRepo:   alertRepo,
+		ingester:    ingester,
+		datasources: datasources,
+		cfg:         cfg,
+		clock:       clk,
+		logger:      logger,
+		states:      make(map[string]*ruleState),
+		instances:   []string{cfg.Sharding.InstanceID},
+	}
+}
+
+// Start runs the rules due right away and then every check interval, until
+// the context is canceled.
+func (r *Runner) Start(ctx context.Context) {
+	r.logger.Info("starting query rule runner", "check_interval", r.cfg.CheckInterval, "datasources", len(r.datasources), "sharded", r.sharded())
+	if r.sharded() {
+		defer r.leave(ctx)
+	}
+
+	ticker := time.NewTicker(r.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Check(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Warn("failed to run query rules", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check runs every enabled rule this replica owns whose interval has passed
+// since it last ran, composite rules after the rules they reference. The
+// breaches of disabled and deleted rules are resolved.
+func (r *Runner) Check(ctx context.Context) error {
+	rules, err := r.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	slices.SortStableFunc(rules, func(a, b *domain.QueryRule) int {
+		return cmp.Compare(compositeOrder(a), compositeOrder(b))
+	})
+
+	now := r.clock.Now()
+	owned := r.owned(ctx, rules, now)
+	var errs []error
+	current := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		current[rule.ID] = true
+		if !owned[rule.ID] {
+			// Another replica runs the rule. Its state is forgotten, so
+			// taking the rule back reloads the breaches from its alerts.
+			delete(r.states, rule.ID)
+			continue
+		}
+		if rule.Disabled {
+			if err := r.disable(ctx, rule); err != nil {
+				errs = append(errs, fmt.Errorf("rule %s: %w", rule.ID, err))
+			}
+			continue
+		}
+		if state, ok := r.states[rule.ID]; ok && now.Sub(state.lastRun) < rule.IntervalOr(r.cfg.DefaultInterval) {
+			continue
+		}
+		if err := r.Run(ctx, rule, now); err != nil {
+			errs = append(errs, fmt.Errorf("rule %s: %w", rule.ID, err))
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(r.states)) {
+		if current[id] {
+			continue
+		}
+		if err := r.resolveAll(ctx, &domain.QueryRule{ID: id}, r.states[id], "rule deleted"); err != nil {
+			errs = append(errs, fmt.Errorf("rule %s: %w", id, err))
+			continue
+		}
+		delete(r.states, id)
+	}
+	return errors.Join(errs...)
+}
+
+// Run runs a rule at now and reports the changes of its breaches. If the
+// query fails, the breaches are left as they are until the next run; while
+// the rule is muted, new breaches are not reported. The run is recorded in
+// the execution log.
+func (r *Runner) Run(ctx context.Context, rule *domain.QueryRule, now time.Time) (err error) {
+	execution := &domain.RuleExecution{
+		RuleID:     rule.ID,
+		StartedAt:  now,
+		Datasource: rule.Datasource,
+		Query:      rule.Query,
+		Params:     rule.Params,
+		Muted:      rule.IsMuted(now),
+	}
+	if rule.Composite != nil {
+		execution.Query = rule.Composite.Expression
+	}
+	start := r.clock.Now()
+	defer func() {
+		execution.Duration = domain.Duration(r.clock.Now().Sub(start))
+		r.record(ctx, execution, err)
+	}()
+
+	state, err := r.state(ctx, rule)
+	if err != nil {
+		return err
+	}
+	state.lastRun = now
+
+	var series []domain.QuerySeries
+	if rule.Composite != nil {
+		series, execution.Outcomes, err = r.evaluate(rule, now)
+	} else {
+		series, err = r.query(ctx, rule, now)
+	}
+	if err != nil {
+		metrics.QueryRuleEvaluations.WithLabelValues(rule.ID, resultFailed).Inc()
+		return err
+	}
+	metrics.QueryRuleEvaluations.WithLabelValues(rule.ID, resultOK).Inc()
+
+	breaching := make(map[string]*domain.QuerySeries)
+	for i := range series {
+		if rule.SkipsSeries(&series[i]) {
+			continue
+		}
+		if rule.Composite != nil || rule.Condition.Breached(series[i].Value) {
+			breaching[rule.DedupKey(&series[i])] = &series[i]
+		}
+	}
+	if len(breaching) > 0 {
+		state.lastBreached = now
+	}
+	execution.Series = len(series)
+	execution.Breaching = slices.Sorted(maps.Keys(breaching))
+
+	var errs []error
+	for _, key := range execution.Breaching {
+		if state.firing[key] || execution.Muted {
+			continue
+		}
+		if err := r.trigger(ctx, rule, key, breaching[key]); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		state.firing[key] = true
+		execution.Triggered = append(execution.Triggered, key)
+	}
+	for _, key := range slices.Sorted(maps.Keys(state.firing)) {
+		if _, ok := breaching[key]; ok {
+			continue
+		}
+		if err := r.resolve(ctx, rule, state.eventManagerID, key, "condition no longer breached"); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		delete(state.firing, key)
+		execution.Resolved = append(execution.Resolved, key)
+	}
+	return errors.Join(errs...)
+}
+
+// disable resolves the breaches of a disabled rule, including those raised
+// before a restart.
+func (r *Runner) disable(ctx context.Context, rule *domain.QueryRule) error {
+	state, err := r.state(ctx, rule)
+	if err != nil {
+		return err
+	}
+	return r.resolveAll(ctx, rule, state, "rule disabled")
+}
+
+// record adds a run to the execution log, unless recording is disabled. A
+// failure to record is logged, not returned.
+func (r *Runner) record(ctx context.Context, execution *domain.RuleExecution, err error) {
+	if r.executions == nil || r.cfg.Executions <= 0 {
+		return
+	}
+	if err != nil {
+		execution.Error = err.Error()
+	}
+	if err := r.executions.Record(ctx, execution, r.cfg.Executions); err != nil {
+		r.logger.Warn("failed to record rule execution", "rule_id", execution.RuleID, "error", err)
+	}
+}
+
+// query runs the query of a rule against its datasource, within the
+// configured timeout.
+func (r *Runner) query(ctx context.Context, rule *domain.QueryRule, now time.Time) ([]domain.QuerySeries, error) {
+	ds, ok := r.datasources[rule.Datasource]
+	if !ok {
+		return nil, fmt.Errorf("unknown datasource %q", rule.Datasource)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Timeout)
+	defer cancel()
+	return ds.Query(ctx, rule.Query, rule.Params, now)
+}
+
+// evaluate evaluates the condition of a composite rule over the outcomes of
+// the rules it references, as of their last runs, and returns them. It
+// yields a single series while the condition holds.
+func (r *Runner) evaluate(rule *domain.QueryRule, now time.Time) ([]domain.QuerySeries, map[string]bool, error) {
+	within := time.Duration(rule.Composite.Within)
+	outcomes := make(map[string]bool, len(rule.Composite.Rules))
+	for variable, id := range rule.Composite.Rules {
+		state, ok := r.states[id]
+		if !ok {
+			return nil, outcomes, fmt.Errorf("referenced rule %s has not run or no longer exists", id)
+		}
+		outcomes[variable] = len(state.firing) > 0 ||
+			(!state.lastBreached.IsZero() && now.Sub(state.lastBreached) <= within)
+	}
+
+	holds, err := rule.Composite.Eval(outcomes)
+	if err != nil || !holds {
+		return nil, outcomes, err
+	}
+	return []domain.QuerySeries{{Value: 1}}, outcomes, nil
+}
+
+// compositeOrder sorts composite rules after the rules they reference.
+func compositeOrder(rule *domain.QueryRule) int {
+	if rule.Composite != nil {
+		return 1
+	}
+	return 0
+}
+
+// state returns the state of a rule, loading the breaches of a rule not run
+// yet from its active alerts. If the rule moved to another event manager,
+// the breaches reported to the former one are resolved first.
+func (r *Runner) state(ctx context.Context, rule *domain.QueryRule) (*ruleState, error) {
+	state, ok := r.states[rule.ID]
+	if ok && state.eventManagerID == rule.EventManagerID {
+		return state, nil
+	}
+	if ok {
+		if err := r.resolveAll(ctx, rule, state, "rule moved to another event manager"); err != nil {
+			return nil, err
+		}
+	}
+
+	alerts, err := r.alertRepo.List(ctx, domain.AlertFilter{
+		EventManagerID: rule.EventManagerID,
+		Status:         domain.AlertStatusActive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rule alerts: %w", err)
+	}
+
+	state = &ruleState{eventManagerID: rule.EventManagerID, firing: make(map[string]bool)}
+	for _, alert := range alerts {
+		key := alert.DedupKey
+		if alert.OriginalDedupKey != "" {
+			key = alert.OriginalDedupKey
+		}
+		if rule.OwnsDedupKey(key) {
+			state.firing[key] = true
+		}
+	}
+	r.states[rule.ID] = state
+	return state, nil
+}
+
+// resolveAll resolves every breach of a rule.
+func (r *Runner) resolveAll(ctx context.Context, rule *domain.QueryRule, state *ruleState, reason string) error {
+	var errs []error
+	for _, key := range slices.Sorted(maps.Keys(state.firing)) {
+		if err := r.resolve(ctx, rule, state.eventManagerID, key, reason); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		delete(state.firing, key)
+	}
+	return errors.Join(errs...)
+}
+
+// trigger ingests the trigger event of a series breaching the condition.
+func (r *Runner) trigger(ctx context.Context, rule *domain.QueryRule, key string, series *domain.QuerySeries) error {
+	labels := make(map[string]string, len(series.Labels)+1)
+	maps.Copy(labels, series.Labels)
+	labels[RuleIDLabel] = rule.ID
+
+	event := &domain.Event{
+		EventManagerID: rule.EventManagerID,
+		Summary:        rule.AlertSummary(series),
+		Severity:       rule.Severity,
+		Action:         domain.ActionTrigger,
+		Class:          rule.AlertClass(),
+		DedupKey:       key,
+		Source:         Source,
+		Labels:         labels,
+	}
+	if err := r.ingester.IngestEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to trigger %s: %w", key, err)
+	}
+
+	r.logger.Info("query rule breached", "rule_id", rule.ID, "dedupKey", key, "value", series.Value)
+	return nil
+}
+
+// resolve ingests the resolve event of a series no longer breaching the
+// condition.
+func (r *Runner) resolve(ctx context.Context, rule *domain.QueryRule, eventManagerID, key, reason string) error {
+	event := &domain.Event{
+		EventManagerID: eventManagerID,
+		Summary:        reason,
+		Action:         domain.ActionResolve,
+		DedupKey:       key,
+		Source:         Source,
+		Labels:         map[string]string{RuleIDLabel: rule.ID},
+	}
+	if err := r.ingester.IngestEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", key, err)
+	}
+
+	r.logger.Info("query rule breach ended", "rule_id", rule.ID, "dedupKey", key, "reason", reason)
+	return nil
+}
@@ -0,0 +1,338 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	storemem "argus-go/internal/store/memory"
+)
+
+// fakeDatasource returns the series set by the test.
+type fakeDatasource struct {
+	series []domain.QuerySeries
+}
+
+func (d *fakeDatasource) Query(ctx context.Context, query string, params map[string]string, at time.Time) ([]domain.QuerySeries, error) {
+	return d.series, nil
+}
+
+// recordingIngester records the events ingested.
+type recordingIngester struct {
+	events []*domain.Event
+}
+
+func (i *recordingIngester) IngestEvent(ctx context.Context, event *domain.Event) error {
+	i.events = append(i.events, event)
+	return nil
+}
+
+// take returns the events recorded since the last call.
+func (i *recordingIngester) take() []*domain.Event {
+	events := i.events
+	i.events = nil
+	return events
+}
+
+func TestRunner_Check(t *testing.T) {
+	ctx := context.Background()
+	repo := storemem.NewQueryRuleRepository()
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	loki := &fakeDatasource{}
+	ingester := &recordingIngester{}
+	cfg := config.RulesConfig{CheckInterval: 10 * time.Second, DefaultInterval: time.Minute, Timeout: time.Second}
+	runner := NewRunner(repo, storemem.NewRuleExecutionRepository(), nil, storemem.NewAlertRepository(), ingester, map[string]Datasource{"loki": loki}, cfg, clk, logger)
+
+	rule := &domain.QueryRule{
+		ID:             "errors",
+		Name:           "API errors",
+		EventManagerID: "em-1",
+		Datasource:     "loki",
+		Query:          `sum by (service) (count_over_time({app="api"} |= "error" [5m]))`,
+		Condition:      domain.QueryCondition{Operator: domain.QueryAbove, Threshold: 100},
+	}
+	if err := repo.Create(ctx, rule); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// A breaching series triggers an alert, the others don't
+	loki.series = []domain.QuerySeries{
+		{Labels: map[string]string{"service": "api"}, Value: 153},
+		{Labels: map[string]string{"service": "web"}, Value: 20},
+	}
+	if err := runner.Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	events := ingester.take()
+	if len(events) != 1 {
+		t.Fatalf("events = %d, want 1", len(events))
+	}
+	if e := events[0]; e.Action != domain.ActionTrigger || e.DedupKey != "rule/errors/service=api" || e.Summary != "API errors: 153 > 100 {service=api}" {
+		t.Errorf("trigger = %+v", e)
+	}
+
+	// The rule doesn't run again before its interval, nor re-trigger after it
+	if err := runner.Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	clk.Advance(time.Minute)
+	if err := runner.Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if events := ingester.take(); len(events) != 0 {
+		t.Fatalf("events while still breached = %d, want 0", len(events))
+	}
+
+	// The alert is resolved once the series stops breaching
+	loki.series = []domain.QuerySeries{{Labels: map[string]string{"service": "api"}, Value: 50}}
+	clk.Advance(time.Minute)
+	if err := runner.Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	events = ingester.take()
+	if len(events) != 1 || events[0].Action != domain.ActionResolve || events[0].DedupKey != "rule/errors/service=api" {
+		t.Fatalf("events after the breach ended = %+v, want a resolve", events)
+	}
+
+	// Deleting the rule resolves its breaches
+	loki.series = []domain.QuerySeries{{Labels: map[string]string{"service": "web"}, Value: 200}}
+	clk.Advance(time.Minute)
+	if err := runner.Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	ingester.take()
+	if err := repo.Delete(ctx, rule.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := runner.Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	events = ingester.take()
+	if len(events) != 1 || events[0].Action != domain.ActionResolve || events[0].DedupKey != "rule/errors/service=web" {
+		t.Errorf("events after deleting the rule = %+v, want a resolve", events)
+	}
+}
+
+func TestRunner_RestoresBreaches(t *testing.T) {
+	ctx := context.Background()
+	repo := storemem.NewQueryRuleRepository()
+	alertRepo := storemem.NewAlertRepository()
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ingester := &recordingIngester{}
+	cfg := config.RulesConfig{CheckInterval: 10 * time.Second, DefaultInterval: time.Minute, Timeout: time.Second}
+	runner := NewRunner(repo, storemem.NewRuleExecutionRepository(), nil, alertRepo, ingester, map[string]Datasource{"loki": &fakeDatasource{}}, cfg, clk, logger)
+
+	rule := &domain.QueryRule{ID: "errors", Name: "API errors", EventManagerID: "em-1", Datasource: "loki", Query: "q"}
+	if err := repo.Create(ctx, rule); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	alert := domain.NewParentAlert(&domain.Event{EventManagerID: "em-1", DedupKey: "rule/errors/service=api", Summary: "s", Severity: domain.SeverityHigh}, clk.Now())
+	if err := alertRepo.Create(ctx, alert); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// A breach raised before a restart is resolved once gone from the result
+	if err := runner.Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	events := ingester.take()
+	if len(events) != 1 || events[0].Action != domain.ActionResolve || events[0].DedupKey != alert.DedupKey {
+		t.Errorf("events = %+v, want a resolve of %s", events, alert.DedupKey)
+	}
+}
+
+func TestRunner_Composite(t *testing.T) {
+	ctx := context.Background()
+	repo := storemem.NewQueryRuleRepository()
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	errorsDS, latencyDS := &fakeDatasource{}, &fakeDatasource{}
+	ingester := &recordingIngester{}
+	cfg := config.RulesConfig{CheckInterval: 10 * time.Second, DefaultInterval: time.Minute, Timeout: time.Second}
+	datasources := map[string]Datasource{"errors": errorsDS, "latency": latencyDS}
+	runner := NewRunner(repo, storemem.NewRuleExecutionRepository(), nil, storemem.NewAlertRepository(), ingester, datasources, cfg, clk, logger)
+
+	// The composite rule is created first, but runs after the rules it references
+	composite := &domain.QueryRule{
+		ID:             "checkout-down",
+		Name:           "Checkout down",
+		EventManagerID: "em-1",
+		Composite: &domain.CompositeCondition{
+			Expression: "A and B",
+			Rules:      map[string]string{"A": "errors", "B": "latency"},
+			Within:     domain.Duration(10 * time.Minute),
+		},
+	}
+	for _, rule := range []*domain.QueryRule{
+		composite,
+		{ID: "errors", Name: "Errors", EventManagerID: "em-1", Datasource: "errors", Query: "q"},
+		{ID: "latency", Name: "Latency", EventManagerID: "em-1", Datasource: "latency", Query: "q"},
+	} {
+		if err := repo.Create(ctx, rule); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	compositeEvents := func() []*domain.Event {
+		var events []*domain.Event
+		for _, event := range ingester.take() {
+			if event.Labels[RuleIDLabel] == composite.ID {
+				events = append(events, event)
+			}
+		}
+		return events
+	}
+	check := func() {
+		t.Helper()
+		clk.Advance(time.Minute)
+		if err := runner.Check(ctx); err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+	}
+
+	// A alone doesn't hold the condition
+	errorsDS.series = []domain.QuerySeries{{Value: 5}}
+	check()
+	if events := compositeEvents(); len(events) != 0 {
+		t.Fatalf("composite events with only A breached = %+v, want none", events)
+	}
+
+	// B breaching within 10m of A does, even though A recovered
+	errorsDS.series = nil
+	latencyDS.series = []domain.QuerySeries{{Value: 5}}
+	check()
+	events := compositeEvents()
+	if len(events) != 1 || events[0].Action != domain.ActionTrigger || events[0].DedupKey != "rule/checkout-down" || events[0].Summary != "Checkout down: A and B" {
+		t.Fatalf("composite events with A and B breached = %+v, want a trigger", events)
+	}
+
+	// Once the breach of A is older than 10m, the alert is resolved
+	clk.Advance(10 * time.Minute)
+	check()
+	events = compositeEvents()
+	if len(events) != 1 || events[0].Action != domain.ActionResolve {
+		t.Errorf("composite events after A aged out = %+v, want a resolve", events)
+	}
+}
+
+// failingDatasource fails every query.
+type failingDatasource struct{}
+
+func (failingDatasource) Query(ctx context.Context, query string, params map[string]string, at time.Time) ([]domain.QuerySeries, error) {
+	return nil, errors.New("parse error")
+}
+
+func TestRunner_Executions(t *testing.T) {
+	ctx := context.Background()
+	repo := storemem.NewQueryRuleRepository()
+	executions := storemem.NewRuleExecutionRepository()
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	loki := &fakeDatasource{series: []domain.QuerySeries{
+		{Labels: map[string]string{"service": "api"}, Value: 153},
+		{Labels: map[string]string{"service": "web"}, Value: 20},
+	}}
+	cfg := config.RulesConfig{CheckInterval: 10 * time.Second, DefaultInterval: time.Minute, Timeout: time.Second, Executions: 2}
+	datasources := map[string]Datasource{"loki": loki, "broken": failingDatasource{}}
+	runner := NewRunner(repo, executions, nil, storemem.NewAlertRepository(), &recordingIngester{}, datasources, cfg, clk, logger)
+
+	rule := &domain.QueryRule{ID: "errors", Name: "Errors", EventManagerID: "em-1", Datasource: "loki", Query: "q", Params: map[string]string{"env": "prod"}, Condition: domain.QueryCondition{Threshold: 100}}
+	broken := &domain.QueryRule{ID: "broken", Name: "Broken", EventManagerID: "em-1", Datasource: "broken", Query: "sum("}
+	for _, r := range []*domain.QueryRule{rule, broken} {
+		if err := repo.Create(ctx, r); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	for range 3 {
+		_ = runner.Check(ctx)
+		clk.Advance(time.Minute)
+	}
+
+	// The latest runs are kept, newest first
+	got, err := executions.ListByRule(ctx, rule.ID, 10)
+	if err != nil {
+		t.Fatalf("ListByRule() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("executions = %d, want the latest 2", len(got))
+	}
+	if latest := got[0]; !latest.StartedAt.Equal(clk.Now().Add(-time.Minute)) || latest.Series != 2 || latest.Query != "q" ||
+		latest.Params["env"] != "prod" || len(latest.Breaching) != 1 || len(latest.Triggered) != 0 {
+		t.Errorf("latest execution = %+v, want a breach triggered earlier", latest)
+	}
+	if first := got[1]; len(first.Breaching) != 1 || len(first.Triggered) != 0 {
+		t.Errorf("second execution = %+v", first)
+	}
+
+	// Failed runs record why
+	got, err = executions.ListByRule(ctx, broken.ID, 1)
+	if err != nil {
+		t.Fatalf("ListByRule() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Error == "" {
+		t.Errorf("failed executions = %+v, want the error", got)
+	}
+}
+
+func TestRunner_DisabledAndMuted(t *testing.T) {
+	ctx := context.Background()
+	repo := storemem.NewQueryRuleRepository()
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	loki := &fakeDatasource{series: []domain.QuerySeries{{Labels: map[string]string{"service": "api"}, Value: 153}}}
+	ingester := &recordingIngester{}
+	cfg := config.RulesConfig{CheckInterval: 10 * time.Second, DefaultInterval: time.Minute, Timeout: time.Second}
+	runner := NewRunner(repo, storemem.NewRuleExecutionRepository(), nil, storemem.NewAlertRepository(), ingester, map[string]Datasource{"loki": loki}, cfg, clk, logger)
+
+	mutedUntil := clk.Now().Add(5 * time.Minute)
+	rule := &domain.QueryRule{ID: "errors", Name: "Errors", EventManagerID: "em-1", Datasource: "loki", Query: "q", Condition: domain.QueryCondition{Threshold: 100}, MutedUntil: &mutedUntil}
+	if err := repo.Create(ctx, rule); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	check := func() []*domain.Event {
+		t.Helper()
+		if err := runner.Check(ctx); err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+		clk.Advance(time.Minute)
+		return ingester.take()
+	}
+
+	// A muted rule raises no alert until the mute ends
+	if events := check(); len(events) != 0 {
+		t.Fatalf("events while muted = %+v, want none", events)
+	}
+	clk.Advance(5 * time.Minute)
+	if events := check(); len(events) != 1 || events[0].Action != domain.ActionTrigger {
+		t.Fatalf("events after the mute = %+v, want a trigger", events)
+	}
+
+	// Disabling the rule resolves its alert, and stops running it
+	rule.Disabled = true
+	if err := repo.Update(ctx, rule); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if events := check(); len(events) != 1 || events[0].Action != domain.ActionResolve {
+		t.Fatalf("events after disabling = %+v, want a resolve", events)
+	}
+	if events := check(); len(events) != 0 {
+		t.Fatalf("events while disabled = %+v, want none", events)
+	}
+
+	// Enabling it again raises the alert again
+	rule.Disabled = false
+	if err := repo.Update(ctx, rule); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if events := check(); len(events) != 1 || events[0].Action != domain.ActionTrigger {
+		t.Errorf("events after enabling = %+v, want a trigger", events)
+	}
+}
@@ -0,0 +1,142 @@
+package rules
+
+import (
+	"context"
+	"hash/fnv"
+	"slices"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+)
+
+// sharded returns true if the rules are shared between the replicas.
+func (r *Runner) sharded() bool {
+	return r.cfg.Sharding.Enabled && r.registry != nil
+}
+
+// heartbeat registers the replica as alive and refreshes the live replicas.
+// If the registry fails, the last known replicas are kept, so the rules
+// stay where they run until it recovers.
+func (r *Runner) heartbeat(ctx context.Context, now time.Time) {
+	id := r.cfg.Sharding.InstanceID
+	instances, err := r.registry.Heartbeat(ctx, id, now, r.cfg.Sharding.HeartbeatTimeout)
+	if err != nil {
+		r.logger.Warn("failed to send rule runner heartbeat, keeping the known replicas", "instances", r.instances, "error", err)
+		return
+	}
+	if !slices.Contains(instances, id) {
+		instances = append(instances, id)
+		slices.Sort(instances)
+	}
+	if !slices.Equal(instances, r.instances) {
+		r.logger.Info("query rule replicas changed", "instance_id", id, "instances", instances)
+	}
+	r.instances = instances
+}
+
+// leave removes the replica from the registry on shutdown, so the others
+// take its rules over without waiting for the heartbeat timeout.
+func (r *Runner) leave(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.cfg.Timeout)
+	defer cancel()
+	if err := r.registry.Leave(ctx, r.cfg.Sharding.InstanceID); err != nil {
+		r.logger.Warn("failed to leave the rule runners", "instance_id", r.cfg.Sharding.InstanceID, "error", err)
+	}
+}
+
+// owned returns the IDs of the rules this replica runs: every rule, unless
+// they are sharded.
+func (r *Runner) owned(ctx context.Context, rules []*domain.QueryRule, now time.Time) map[string]bool {
+	owned := make(map[string]bool, len(rules))
+	if !r.sharded() {
+		for _, rule := range rules {
+			owned[rule.ID] = true
+		}
+		metrics.QueryRulesOwned.Set(float64(len(owned)))
+		return owned
+	}
+
+	r.heartbeat(ctx, now)
+	keys := shardKeys(rules)
+	for _, rule := range rules {
+		if ownerOf(keys[rule.ID], r.instances) == r.cfg.Sharding.InstanceID {
+			owned[rule.ID] = true
+		}
+	}
+	metrics.QueryRulesOwned.Set(float64(len(owned)))
+	return owned
+}
+
+// shardKeys returns the key each rule is sharded by: the smallest ID among
+// the rules linked to it by composite references, so composite rules run on
+// the replica running the rules they reference.
+func shardKeys(rules []*domain.QueryRule) map[string]string {
+	parent := make(map[string]string)
+	var find func(id string) string
+	find = func(id string) string {
+		p, ok := parent[id]
+		if !ok || p == id {
+			parent[id] = id
+			return id
+		}
+		root := find(p)
+		parent[id] = root
+		return root
+	}
+	union := func(a, b string) {
+		ra, rb := find(a), find(b)
+		if ra == rb {
+			return
+		}
+		if rb < ra {
+			ra, rb = rb, ra
+		}
+		parent[rb] = ra
+	}
+
+	for _, rule := range rules {
+		find(rule.ID)
+		if rule.Composite != nil {
+			for _, id := range rule.Composite.Rules {
+				union(rule.ID, id)
+			}
+		}
+	}
+
+	keys := make(map[string]string, len(rules))
+	for _, rule := range rules {
+		keys[rule.ID] = find(rule.ID)
+	}
+	return keys
+}
+
+// ownerOf returns the replica owning a shard key by rendezvous hashing: the
+// replica with the highest hash of its ID and the key. A replica joining or
+// leaving only moves the keys it takes or owned.
+func ownerOf(key string, instances []string) string {
+	var owner string
+	var best uint64
+	for _, instance := range instances {
+		if h := shardHash(instance, key); owner == "" || h > best {
+			owner, best = instance, h
+		}
+	}
+	return owner
+}
+
+// shardHash hashes a replica and a shard key. FNV-1a is finalized with the
+// MurmurHash3 mix, since the hashes of similar IDs would otherwise cluster.
+func shardHash(instance, key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(instance))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(key))
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
@@ -0,0 +1,76 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	storemem "argus-go/internal/store/memory"
+)
+
+func TestRunner_Sharded(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	registry := storemem.NewRunnerRegistry()
+	newRunner := func(id string) *Runner {
+		cfg := config.RulesConfig{
+			CheckInterval: 10 * time.Second,
+			Timeout:       time.Second,
+			Sharding:      config.RuleShardingConfig{Enabled: true, InstanceID: id, HeartbeatTimeout: 30 * time.Second},
+		}
+		return NewRunner(storemem.NewQueryRuleRepository(), nil, registry, nil, nil, nil, cfg, clk, logger)
+	}
+
+	var rules []*domain.QueryRule
+	for i := range 20 {
+		rules = append(rules, &domain.QueryRule{ID: fmt.Sprintf("rule-%02d", i)})
+	}
+	rules = append(rules, &domain.QueryRule{ID: "composite", Composite: &domain.CompositeCondition{
+		Expression: "A and B",
+		Rules:      map[string]string{"A": "rule-03", "B": "rule-17"},
+	}})
+
+	// Until the other replica registers, each replica runs every rule
+	a, b := newRunner("a"), newRunner("b")
+	if owned := a.owned(ctx, rules, clk.Now()); len(owned) != len(rules) {
+		t.Fatalf("a owns %d rules alone, want %d", len(owned), len(rules))
+	}
+	b.owned(ctx, rules, clk.Now())
+
+	ownedA, ownedB := a.owned(ctx, rules, clk.Now()), b.owned(ctx, rules, clk.Now())
+	if len(ownedA) == 0 || len(ownedB) == 0 || len(ownedA)+len(ownedB) != len(rules) {
+		t.Fatalf("a owns %d rules and b %d, want a share each of %d", len(ownedA), len(ownedB), len(rules))
+	}
+	for id := range ownedA {
+		if ownedB[id] {
+			t.Errorf("rule %s is owned by both replicas", id)
+		}
+	}
+	owner := ownedA
+	if ownedB["composite"] {
+		owner = ownedB
+	}
+	if !owner["rule-03"] || !owner["rule-17"] {
+		t.Error("a composite rule must run on the replica running the rules it references")
+	}
+
+	// A replica missing its heartbeats hands its rules over
+	clk.Advance(time.Minute)
+	if owned := a.owned(ctx, rules, clk.Now()); len(owned) != len(rules) {
+		t.Errorf("a owns %d rules once b is silent, want %d", len(owned), len(rules))
+	}
+
+	// So does a replica leaving
+	b.owned(ctx, rules, clk.Now())
+	b.leave(ctx)
+	if owned := a.owned(ctx, rules, clk.Now()); len(owned) != len(rules) {
+		t.Errorf("a owns %d rules once b left, want %d", len(owned), len(rules))
+	}
+}
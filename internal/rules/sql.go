@@ -0,0 +1,79 @@
+package rules
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	// Registers the pgx driver with database/sql.
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"argus-go/internal/domain"
+)
+
+// SQL runs SQL queries through a database/sql driver. The params of a rule
+// and the evaluation time, as now, are passed as named arguments: @name
+// placeholders with the pgx driver, or whatever named parameters the driver
+// supports.
+type SQL struct {
+	db     *sql.DB
+	driver string
+}
+
+// NewSQL creates a datasource querying db, opened with the named driver.
+func NewSQL(db *sql.DB, driver string) *SQL {
+	return &SQL{db: db, driver: driver}
+}
+
+// Query runs a SQL query with the params of its rule, and the evaluation
+// time as the now argument.
+func (s *SQL) Query(ctx context.Context, query string, params map[string]string, at time.Time) ([]domain.QuerySeries, error) {
+	rows, err := s.db.QueryContext(ctx, query, s.args(params, at)...)
+	if err != nil {
+		return nil, fmt.Errorf("sql query failed: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sql columns: %w", err)
+	}
+
+	var table [][]any
+	for rows.Next() {
+		row := make([]any, len(columns))
+		targets := make([]any, len(columns))
+		for i := range row {
+			targets[i] = &row[i]
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return nil, fmt.Errorf("failed to scan sql row: %w", err)
+		}
+		table = append(table, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sql rows: %w", err)
+	}
+	return tableSeries(columns, table)
+}
+
+// args returns the query arguments binding the params and the evaluation
+// time by name. pgx ignores the names of database/sql arguments, so it is
+// passed its own named arguments instead.
+func (s *SQL) args(params map[string]string, at time.Time) []any {
+	if s.driver == "pgx" {
+		named := pgx.NamedArgs{domain.QueryParamNow: at}
+		for name, value := range params {
+			named[name] = value
+		}
+		return []any{named}
+	}
+
+	args := []any{sql.Named(domain.QueryParamNow, at)}
+	for name, value := range params {
+		args = append(args, sql.Named(name, value))
+	}
+	return args
+}
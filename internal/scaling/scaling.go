@@ -0,0 +1,171 @@
+// Package scaling derives the autoscaling signal of the processor tier from
+// the queue backlog, the lag of events and the processing rate. The signal
+// is a single pressure, normalized against the scaling targets so external
+// scalers, like KEDA or the HPA, can scale the processors to keep it at 1.
+package scaling
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
+	"argus-go/internal/metrics"
+)
+
+// MaxPressure caps the pressure, so a processor that stalled with a backlog
+// reports a finite value.
+const MaxPressure = 10
+
+// Sources report the state of the processor the monitor derives the signal
+// from.
+type Sources struct {
+	// Backlog counts the events queued and not yet consumed; nil if the
+	// queue doesn't report it.
+	Backlog func() int64
+
+	// Lag is how long the last processed event waited in the queue.
+	Lag func() time.Duration
+
+	// Processed counts the events handled since the process started.
+	Processed func() uint64
+
+	// Paused reports whether the processor is paused.
+	Paused func() bool
+}
+
+// Signal is the autoscaling signal of a processor replica.
+type Signal struct {
+	// Pressure is the larger of the lag relative to the target lag and the
+	// time to drain the backlog relative to the target drain time, at most
+	// MaxPressure. A paused processor reports 1, so it is neither scaled up
+	// nor down.
+	Pressure float64 `json:"pressure"`
+
+	Backlog int64 `json:"backlog"`
+
+	// LagSeconds is how long the last processed event waited; zero while
+	// the processor is idle.
+	LagSeconds float64 `json:"lag_seconds"`
+
+	// EventsPerSecond is the processing rate over the last interval.
+	EventsPerSecond float64 `json:"events_per_second"`
+
+	// DrainSeconds is how long working off the backlog takes at the current
+	// rate; omitted while nothing is processed.
+	DrainSeconds float64 `json:"drain_seconds,omitempty"`
+
+	Paused bool `json:"paused"`
+
+	TargetLagSeconds   float64 `json:"target_lag_seconds"`
+	TargetDrainSeconds float64 `json:"target_drain_seconds"`
+
+	MeasuredAt time.Time `json:"measured_at"`
+}
+
+// Monitor measures the processing rate periodically and derives the signal
+// from it. It is safe for concurrent use.
+type Monitor struct {
+	cfg     config.ScalingConfig
+	sources Sources
+	clock   clock.Clock
+	logger  *slog.Logger
+
+	mu            sync.Mutex
+	lastProcessed uint64
+	lastAt        time.Time
+	rate          float64
+}
+
+// New creates a monitor deriving the signal from sources. The processing
+// rate is measured from now on.
+func New(cfg config.ScalingConfig, sources Sources, clk clock.Clock, logger *slog.Logger) *Monitor {
+	return &Monitor{
+		cfg:           cfg,
+		sources:       sources,
+		clock:         clk,
+		logger:        logger,
+		lastProcessed: sources.Processed(),
+		lastAt:        clk.Now(),
+	}
+}
+
+// Start measures the processing rate and updates the scaling metrics every
+// interval, until the context is canceled.
+func (m *Monitor) Start(ctx context.Context) {
+	m.logger.InfoContext(ctx, "starting scaling signal", "interval", m.cfg.Interval)
+
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Measure()
+		}
+	}
+}
+
+// Measure measures the processing rate over the events handled since the
+// last measurement, updates the scaling metrics and returns the signal.
+func (m *Monitor) Measure() Signal {
+	now := m.clock.Now()
+	processed := m.sources.Processed()
+
+	m.mu.Lock()
+	if elapsed := now.Sub(m.lastAt); elapsed > 0 {
+		m.rate = float64(processed-m.lastProcessed) / elapsed.Seconds()
+		m.lastProcessed, m.lastAt = processed, now
+	}
+	m.mu.Unlock()
+
+	signal := m.Signal()
+	metrics.ProcessorBacklog.Set(float64(signal.Backlog))
+	metrics.ProcessorEventRate.Set(signal.EventsPerSecond)
+	metrics.ProcessorLag.Set(signal.LagSeconds)
+	metrics.ScalingPressure.Set(signal.Pressure)
+	return signal
+}
+
+// Signal returns the current signal: the backlog and lag as they are now,
+// with the processing rate of the last measurement.
+func (m *Monitor) Signal() Signal {
+	m.mu.Lock()
+	rate := m.rate
+	m.mu.Unlock()
+
+	signal := Signal{
+		EventsPerSecond:    rate,
+		Paused:             m.sources.Paused(),
+		TargetLagSeconds:   m.cfg.TargetLag.Seconds(),
+		TargetDrainSeconds: m.cfg.TargetDrainTime.Seconds(),
+		MeasuredAt:         m.clock.Now().UTC(),
+	}
+	if m.sources.Backlog != nil {
+		signal.Backlog = m.sources.Backlog()
+	}
+	// The lag of the last event goes stale once the processor is idle
+	if rate > 0 {
+		signal.LagSeconds = m.sources.Lag().Seconds()
+	}
+	if signal.Paused {
+		signal.Pressure = 1
+		return signal
+	}
+
+	signal.Pressure = signal.LagSeconds / signal.TargetLagSeconds
+	if signal.Backlog > 0 {
+		drainPressure := float64(MaxPressure)
+		if rate > 0 {
+			signal.DrainSeconds = float64(signal.Backlog) / rate
+			drainPressure = signal.DrainSeconds / signal.TargetDrainSeconds
+		}
+		signal.Pressure = max(signal.Pressure, drainPressure)
+	}
+	signal.Pressure = min(signal.Pressure, MaxPressure)
+	return signal
+}
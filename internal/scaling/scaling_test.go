@@ -0,0 +1,71 @@
+package scaling
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
+)
+
+// fakeSources are sources of a processor under test.
+type fakeSources struct {
+	backlog   int64
+	lag       time.Duration
+	processed uint64
+	paused    bool
+}
+
+func newTestMonitor(sources *fakeSources, clk clock.Clock) *Monitor {
+	return New(config.ScalingConfig{
+		Interval:        15 * time.Second,
+		TargetLag:       30 * time.Second,
+		TargetDrainTime: time.Minute,
+	}, Sources{
+		Backlog:   func() int64 { return sources.backlog },
+		Lag:       func() time.Duration { return sources.lag },
+		Processed: func() uint64 { return sources.processed },
+		Paused:    func() bool { return sources.paused },
+	}, clk, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestMonitor_Measure(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 10, 15, 14, 30, 0, 0, time.UTC))
+	sources := &fakeSources{processed: 1000}
+	monitor := newTestMonitor(sources, clk)
+
+	tests := []struct {
+		name         string
+		processed    uint64
+		backlog      int64
+		lag          time.Duration
+		paused       bool
+		wantRate     float64
+		wantPressure float64
+	}{
+		// A stale lag of the last event is ignored while idle
+		{name: "idle", lag: time.Minute, wantPressure: 0},
+		{name: "keeping up", processed: 150, backlog: 300, lag: 3 * time.Second, wantRate: 10, wantPressure: 0.5},
+		{name: "lagging", processed: 150, backlog: 300, lag: time.Minute, wantRate: 10, wantPressure: 2},
+		{name: "backlog growing", processed: 150, backlog: 1800, lag: 3 * time.Second, wantRate: 10, wantPressure: 3},
+		{name: "stalled", backlog: 10, wantPressure: MaxPressure},
+		{name: "paused", backlog: 1800, paused: true, wantPressure: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk.Advance(15 * time.Second)
+			sources.processed += tt.processed
+			sources.backlog, sources.lag, sources.paused = tt.backlog, tt.lag, tt.paused
+
+			signal := monitor.Measure()
+			if signal.EventsPerSecond != tt.wantRate {
+				t.Errorf("EventsPerSecond = %v, want %v", signal.EventsPerSecond, tt.wantRate)
+			}
+			if signal.Pressure != tt.wantPressure {
+				t.Errorf("Pressure = %v, want %v", signal.Pressure, tt.wantPressure)
+			}
+		})
+	}
+}
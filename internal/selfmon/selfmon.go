@@ -0,0 +1,288 @@
+// Package selfmon raises alerts about ArgusGo itself. It checks the processor
+// and notifications periodically and ingests a trigger event when a condition
+// starts, and a resolve event when it ends, through the normal pipeline for a
+// reserved event manager. Operators route these alerts like any other, with
+// the notification settings of that event manager.
+package selfmon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+	"time"
+
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	"argus-go/internal/notification"
+	"argus-go/internal/store"
+)
+
+// Class is the class of every self-monitoring event.
+const Class = "argus-self-monitoring"
+
+// Ingester publishes events into the pipeline.
+type Ingester interface {
+	IngestEvent(ctx context.Context, event *domain.Event) error
+}
+
+// Sources report the state of ArgusGo the monitor checks. Counters are
+// totals since the process started; the monitor compares them between checks.
+type Sources struct {
+	// ProcessorLag is how long the last processed event waited in the queue.
+	ProcessorLag func() time.Duration
+
+	// PoisonMessages counts the events the processor gave up on.
+	PoisonMessages func() uint64
+
+	// NotificationFailures counts the notifications that failed.
+	NotificationFailures func() uint64
+
+	// QuotaWarnings summarizes the quotas near exhaustion or exhausted, by
+	// event manager ID.
+	QuotaWarnings func() map[string]string
+
+	// ChannelDeliveries counts the notification deliveries, by channel.
+	ChannelDeliveries func() map[string]notification.ChannelDeliveries
+}
+
+// Key prefixes of the conditions that hold per event manager, for quotas,
+// and per notification channel.
+const (
+	quotaKeyPrefix   = "quota-"
+	channelKeyPrefix = "channel-"
+)
+
+// condition is a state of ArgusGo that raises an alert.
+type condition struct {
+	// key is appended to the event manager ID for the dedup key.
+	key string
+
+	// check returns whether the condition holds, and the alert summary.
+	check func() (bool, string)
+}
+
+// Monitor checks the conditions and ingests events when they change. It is
+// not safe for concurrent use.
+type Monitor struct {
+	cfg              config.SelfMonitoringConfig
+	ingester         Ingester
+	eventManagerRepo store.EventManagerRepository
+	conditions       []condition
+	quotaWarnings    func() map[string]string
+	logger           *slog.Logger
+
+	// channelDeliveries is nil if the failure rates of channels aren't
+	// checked; lastDeliveries are the deliveries at the last check.
+	channelDeliveries func() map[string]notification.ChannelDeliveries
+	lastDeliveries    map[string]notification.ChannelDeliveries
+
+	// firing holds the conditions that hold, by key. A condition not yet
+	// checked is absent, so the first check reports either state.
+	firing map[string]bool
+}
+
+// New creates a monitor of the configured conditions. Checks with a negative
+// threshold, or without a source, are skipped.
+func New(
+	cfg config.SelfMonitoringConfig,
+	ingester Ingester,
+	eventManagerRepo store.EventManagerRepository,
+	sources Sources,
+	logger *slog.Logger,
+) *Monitor {
+	m := &Monitor{
+		cfg:              cfg,
+		ingester:         ingester,
+		eventManagerRepo: eventManagerRepo,
+		quotaWarnings:    sources.QuotaWarnings,
+		logger:           logger,
+		firing:           make(map[string]bool),
+	}
+
+	if cfg.ProcessorLagThreshold > 0 && sources.ProcessorLag != nil {
+		m.conditions = append(m.conditions, condition{
+			key: "processor-lag",
+			check: func() (bool, string) {
+				lag := sources.ProcessorLag()
+				return lag >= cfg.ProcessorLagThreshold,
+					fmt.Sprintf("Processor lag of %s exceeds %s", lag.Round(time.Second), cfg.ProcessorLagThreshold)
+			},
+		})
+	}
+	if cfg.PoisonMessagesThreshold > 0 && sources.PoisonMessages != nil {
+		m.conditions = append(m.conditions, condition{
+			key:   "poison-messages",
+			check: growth(sources.PoisonMessages, cfg.PoisonMessagesThreshold, "events given up on"),
+		})
+	}
+	if cfg.NotificationFailuresThreshold > 0 && sources.NotificationFailures != nil {
+		m.conditions = append(m.conditions, condition{
+			key:   "notification-failures",
+			check: growth(sources.NotificationFailures, cfg.NotificationFailuresThreshold, "notifications failed"),
+		})
+	}
+	if cfg.ChannelFailureRateThreshold > 0 && sources.ChannelDeliveries != nil {
+		m.channelDeliveries = sources.ChannelDeliveries
+		m.lastDeliveries = make(map[string]notification.ChannelDeliveries)
+		maps.Copy(m.lastDeliveries, sources.ChannelDeliveries())
+	}
+	return m
+}
+
+// growth returns a check that holds while a counter grows by at least
+// threshold between checks.
+func growth(counter func() uint64, threshold int, what string) func() (bool, string) {
+	last := counter()
+	return func() (bool, string) {
+		current := counter()
+		delta := current - last
+		last = current
+		return delta >= uint64(threshold), fmt.Sprintf("%d %s since the last check", delta, what)
+	}
+}
+
+// EnsureEventManager creates the reserved event manager if it doesn't exist.
+// Its alerts are not grouped, so each condition is an alert of its own.
+func (m *Monitor) EnsureEventManager(ctx context.Context) error {
+	_, err := m.eventManagerRepo.GetByID(ctx, m.cfg.EventManagerID)
+	if err == nil || !errors.Is(err, domain.ErrEventManagerNotFound) {
+		return err
+	}
+
+	now := time.Now().UTC()
+	em := &domain.EventManager{
+		ID:               m.cfg.EventManagerID,
+		Name:             "ArgusGo self-monitoring",
+		Description:      "Alerts ArgusGo raises about itself",
+		GroupingDisabled: true,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := m.eventManagerRepo.Create(ctx, em); err != nil {
+		return fmt.Errorf("failed to create self-monitoring event manager: %w", err)
+	}
+	m.logger.Info("created self-monitoring event manager", "event_manager_id", em.ID)
+	return nil
+}
+
+// Start checks the conditions every interval until the context is canceled.
+func (m *Monitor) Start(ctx context.Context, interval time.Duration) {
+	m.logger.Info("starting self-monitoring", "interval", interval, "event_manager_id", m.cfg.EventManagerID)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := m.Check(ctx); err != nil && ctx.Err() == nil {
+			m.logger.Warn("failed to report self-monitoring alerts", "error", err)
+		}
+	}
+}
+
+// Check evaluates every condition and ingests a trigger event for those that
+// started to hold, and a resolve event for those that stopped. A condition
+// whose event fails is reported again on the next check.
+//
+// Each event manager with a quota warning is a condition of its own, which
+// stops holding when the warning goes away. So is each notification channel,
+// see checkChannels.
+func (m *Monitor) Check(ctx context.Context) error {
+	var errs []error
+	for _, c := range m.conditions {
+		holds, summary := c.check()
+		if err := m.report(ctx, c.key, holds, summary); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if m.quotaWarnings != nil {
+		warnings := m.quotaWarnings()
+		for id, summary := range warnings {
+			if err := m.report(ctx, quotaKeyPrefix+id, true, summary); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		for key, firing := range m.firing {
+			id, isQuota := strings.CutPrefix(key, quotaKeyPrefix)
+			if _, warned := warnings[id]; !isQuota || !firing || warned {
+				continue
+			}
+			summary := fmt.Sprintf("Event manager %s is within its quota", id)
+			if err := m.report(ctx, key, false, summary); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if m.channelDeliveries != nil {
+		errs = append(errs, m.checkChannels(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// checkChannels reports the notification channels whose deliveries since the
+// last check failed at the threshold rate or more. A channel needs
+// ChannelMinDeliveries deliveries for its alert to trigger: fewer are judged
+// again with the deliveries of the next checks, so a quiet channel failing
+// is still found. Without deliveries a channel keeps its state.
+func (m *Monitor) checkChannels(ctx context.Context) error {
+	var errs []error
+	for channel, d := range m.channelDeliveries() {
+		last := m.lastDeliveries[channel]
+		total, failed := d.Total-last.Total, d.Failed-last.Failed
+		if total == 0 {
+			continue
+		}
+		rate := float64(failed) / float64(total)
+		holds := rate >= m.cfg.ChannelFailureRateThreshold
+		if holds && total < uint64(m.cfg.ChannelMinDeliveries) {
+			continue
+		}
+		summary := fmt.Sprintf("%d of the last %d notification deliveries to %s failed (p95 latency %s)",
+			failed, total, channel, d.P95Latency.Round(time.Millisecond))
+		if err := m.report(ctx, channelKeyPrefix+channel, holds, summary); err != nil {
+			// Judge these deliveries again, with the next ones
+			errs = append(errs, err)
+			continue
+		}
+		m.lastDeliveries[channel] = d
+	}
+	return errors.Join(errs...)
+}
+
+// report ingests the event of a condition if its state changed since the
+// last check.
+func (m *Monitor) report(ctx context.Context, key string, holds bool, summary string) error {
+	if firing, checked := m.firing[key]; checked && firing == holds {
+		return nil
+	}
+
+	event := &domain.Event{
+		EventManagerID: m.cfg.EventManagerID,
+		Summary:        summary,
+		Action:         domain.ActionResolve,
+		Class:          Class,
+		DedupKey:       m.cfg.EventManagerID + "/" + key,
+	}
+	if holds {
+		event.Action = domain.ActionTrigger
+	}
+	if err := m.ingester.IngestEvent(ctx, event); err != nil {
+		delete(m.firing, key)
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	m.firing[key] = holds
+	if holds {
+		m.logger.Warn("self-monitoring alert triggered", "condition", key, "summary", summary)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package selfmon
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	"argus-go/internal/notification"
+	storemem "argus-go/internal/store/memory"
+)
+
+// recordingIngester records the events ingested, failing while err is set.
+type recordingIngester struct {
+	events []*domain.Event
+	err    error
+}
+
+func (r *recordingIngester) IngestEvent(ctx context.Context, event *domain.Event) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+func TestMonitor_Check(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	cfg := config.SelfMonitoringConfig{
+		EventManagerID:                "argus-system",
+		ProcessorLagThreshold:         time.Minute,
+		PoisonMessagesThreshold:       2,
+		NotificationFailuresThreshold: -1,
+	}
+
+	var lag time.Duration
+	var poisoned uint64
+	ingester := &recordingIngester{}
+	monitor := New(cfg, ingester, storemem.NewEventManagerRepository(), Sources{
+		ProcessorLag:         func() time.Duration { return lag },
+		PoisonMessages:       func() uint64 { return poisoned },
+		NotificationFailures: func() uint64 { t.Fatal("disabled check was evaluated"); return 0 },
+	}, logger)
+
+	check := func() []*domain.Event {
+		t.Helper()
+		ingester.events = nil
+		if err := monitor.Check(ctx); err != nil {
+			t.Fatalf("Check error: %v", err)
+		}
+		return ingester.events
+	}
+	assertEvent := func(events []*domain.Event, dedupKey string, action domain.Action) {
+		t.Helper()
+		for _, event := range events {
+			if event.DedupKey == dedupKey {
+				if event.Action != action || event.EventManagerID != "argus-system" {
+					t.Errorf("event of %s = %+v, want %s for argus-system", dedupKey, event, action)
+				}
+				return
+			}
+		}
+		t.Errorf("no event of %s in %+v", dedupKey, events)
+	}
+
+	// The first check reports every condition, closing alerts of earlier runs
+	events := check()
+	if len(events) != 2 {
+		t.Fatalf("first check ingested %d events, want 2", len(events))
+	}
+	assertEvent(events, "argus-system/processor-lag", domain.ActionResolve)
+	assertEvent(events, "argus-system/poison-messages", domain.ActionResolve)
+
+	// Only changes are ingested
+	lag, poisoned = 2*time.Minute, 1
+	events = check()
+	if len(events) != 1 {
+		t.Fatalf("check ingested %d events, want 1", len(events))
+	}
+	assertEvent(events, "argus-system/processor-lag", domain.ActionTrigger)
+
+	poisoned = 3
+	assertEvent(check(), "argus-system/poison-messages", domain.ActionTrigger)
+	if events := check(); len(events) != 1 {
+		t.Fatalf("check ingested %d events, want the poison messages resolve", len(events))
+	}
+
+	// Failed events are ingested again on the next check
+	lag = 0
+	ingester.err = errors.New("queue unavailable")
+	if err := monitor.Check(ctx); err == nil {
+		t.Error("Check error = nil, want the ingest error")
+	}
+	ingester.err = nil
+	assertEvent(check(), "argus-system/processor-lag", domain.ActionResolve)
+}
+
+func TestMonitor_QuotaWarnings(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	warnings := map[string]string{"payments": "Event manager payments is near its quota: events_per_day"}
+	ingester := &recordingIngester{}
+	monitor := New(config.SelfMonitoringConfig{EventManagerID: "argus-system"}, ingester, storemem.NewEventManagerRepository(), Sources{
+		QuotaWarnings: func() map[string]string { return warnings },
+	}, logger)
+
+	check := func() []*domain.Event {
+		t.Helper()
+		ingester.events = nil
+		if err := monitor.Check(ctx); err != nil {
+			t.Fatalf("Check error: %v", err)
+		}
+		return ingester.events
+	}
+
+	events := check()
+	if len(events) != 1 || events[0].DedupKey != "argus-system/quota-payments" || events[0].Action != domain.ActionTrigger {
+		t.Fatalf("events = %+v, want a trigger for the payments quota", events)
+	}
+	if events := check(); len(events) != 0 {
+		t.Errorf("unchanged warning ingested %+v", events)
+	}
+
+	// A warning that goes away resolves its alert
+	warnings = map[string]string{}
+	events = check()
+	if len(events) != 1 || events[0].DedupKey != "argus-system/quota-payments" || events[0].Action != domain.ActionResolve {
+		t.Fatalf("events = %+v, want a resolve for the payments quota", events)
+	}
+	if events := check(); len(events) != 0 {
+		t.Errorf("resolved warning ingested %+v", events)
+	}
+}
+
+func TestMonitor_ChannelFailureRate(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	deliveries := map[string]notification.ChannelDeliveries{"plugin:pager": {Total: 10, Failed: 10}}
+	ingester := &recordingIngester{}
+	cfg := config.SelfMonitoringConfig{EventManagerID: "argus-system", ChannelFailureRateThreshold: 0.5, ChannelMinDeliveries: 4}
+	monitor := New(cfg, ingester, storemem.NewEventManagerRepository(), Sources{
+		ChannelDeliveries: func() map[string]notification.ChannelDeliveries { return deliveries },
+	}, logger)
+
+	check := func(total, failed uint64) []*domain.Event {
+		t.Helper()
+		d := deliveries["plugin:pager"]
+		deliveries = map[string]notification.ChannelDeliveries{"plugin:pager": {Total: d.Total + total, Failed: d.Failed + failed}}
+		ingester.events = nil
+		if err := monitor.Check(ctx); err != nil {
+			t.Fatalf("Check error: %v", err)
+		}
+		return ingester.events
+	}
+	assertEvent := func(events []*domain.Event, action domain.Action) {
+		t.Helper()
+		if len(events) != 1 || events[0].DedupKey != "argus-system/channel-plugin:pager" || events[0].Action != action {
+			t.Fatalf("events = %+v, want a %s of the pager channel", events, action)
+		}
+	}
+
+	// Deliveries before the monitor started aren't judged
+	if events := check(0, 0); len(events) != 0 {
+		t.Fatalf("events = %+v, want none without deliveries", events)
+	}
+
+	// Too few failures are judged with the next deliveries
+	if events := check(2, 2); len(events) != 0 {
+		t.Fatalf("events = %+v, want none below the minimum deliveries", events)
+	}
+	assertEvent(check(2, 1), domain.ActionTrigger)
+	if events := check(0, 0); len(events) != 0 {
+		t.Fatalf("events = %+v, want the alert kept without deliveries", events)
+	}
+
+	// A delivery below the threshold rate resolves the alert
+	assertEvent(check(1, 0), domain.ActionResolve)
+	if events := check(3, 1); len(events) != 0 {
+		t.Errorf("healthy channel ingested %+v", events)
+	}
+}
+
+func TestMonitor_EnsureEventManager(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repo := storemem.NewEventManagerRepository()
+	monitor := New(config.SelfMonitoringConfig{EventManagerID: "argus-system"}, &recordingIngester{}, repo, Sources{}, logger)
+
+	for range 2 {
+		if err := monitor.EnsureEventManager(ctx); err != nil {
+			t.Fatalf("EnsureEventManager error: %v", err)
+		}
+	}
+	em, err := repo.GetByID(ctx, "argus-system")
+	if err != nil {
+		t.Fatalf("GetByID error: %v", err)
+	}
+	if err := em.Validate(); err != nil || !em.GroupingDisabled {
+		t.Errorf("event manager = %+v (validate: %v), want a valid one without grouping", em, err)
+	}
+}
@@ -0,0 +1,173 @@
+// Package silence applies silences to notifications. A scheduler
+// materializes the occurrences of recurring silences into silences of their
+// own and keeps the silences in effect in memory, so checking an alert
+// against them takes no store round trip.
+package silence
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// occurrenceNamespace derives the IDs of the occurrences of recurring
+// silences, so every replica materializes an occurrence under the same ID.
+var occurrenceNamespace = uuid.MustParse("6f1c7a52-3b7e-4f0a-9a4d-2d1b8c5e9f30")
+
+// Scheduler materializes recurring silences and decides whether alerts are
+// silenced. A nil Scheduler silences nothing. It is safe for concurrent use.
+type Scheduler struct {
+	repo     store.SilenceRepository
+	interval time.Duration
+	clock    clock.Clock
+	logger   *slog.Logger
+
+	mu       sync.RWMutex
+	silences []*domain.Silence
+}
+
+// NewScheduler creates a scheduler of the silences in repo, checked every
+// interval. Occurrences of recurring silences are materialized an interval
+// ahead, so none starts between two checks unnoticed.
+func NewScheduler(repo store.SilenceRepository, interval time.Duration, clk clock.Clock, logger *slog.Logger) *Scheduler {
+	return &Scheduler{
+		repo:     repo,
+		interval: interval,
+		clock:    clk,
+		logger:   logger,
+	}
+}
+
+// Start checks the silences right away and then every interval, until the
+// context is canceled.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.logger.Info("starting silence scheduler", "check_interval", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Check(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Warn("failed to check silences", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check materializes the occurrences of recurring silences that are active
+// or start within the next interval, then reloads the silences in effect.
+func (s *Scheduler) Check(ctx context.Context) error {
+	silences, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.clock.Now().UTC()
+	materialized := false
+	for _, silence := range silences {
+		if !silence.IsRecurring() {
+			continue
+		}
+		for _, start := range silence.Schedule.Occurrences(now, s.interval) {
+			id := uuid.NewSHA1(occurrenceNamespace, []byte(silence.ID+"/"+start.UTC().Format(time.RFC3339))).String()
+			err := s.repo.Create(ctx, silence.Occurrence(id, start, now))
+			if errors.Is(err, domain.ErrSilenceExists) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			s.logger.Info("materialized recurring silence",
+				"recurring_id", silence.ID,
+				"silence_id", id,
+				"starts_at", start,
+			)
+			materialized = true
+		}
+	}
+
+	if materialized {
+		return s.Refresh(ctx)
+	}
+	s.load(silences, now)
+	return nil
+}
+
+// Refresh reloads the silences in effect, after silences are created or
+// deleted through this replica.
+func (s *Scheduler) Refresh(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	silences, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	s.load(silences, s.clock.Now().UTC())
+	return nil
+}
+
+// load keeps the silences that are active or pending at now.
+func (s *Scheduler) load(silences []*domain.Silence, now time.Time) {
+	var inEffect []*domain.Silence
+	for _, silence := range silences {
+		switch silence.State(now) {
+		case domain.SilenceActive, domain.SilencePending:
+			inEffect = append(inEffect, silence)
+		}
+	}
+
+	s.mu.Lock()
+	s.silences = inEffect
+	s.mu.Unlock()
+}
+
+// Active returns the active silences.
+func (s *Scheduler) Active() []*domain.Silence {
+	if s == nil {
+		return nil
+	}
+	now := s.clock.Now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var active []*domain.Silence
+	for _, silence := range s.silences {
+		if silence.State(now) == domain.SilenceActive {
+			active = append(active, silence)
+		}
+	}
+	return active
+}
+
+// Silenced returns the active silence matching the alert, or nil.
+func (s *Scheduler) Silenced(alert *domain.Alert) *domain.Silence {
+	if s == nil {
+		return nil
+	}
+	now := s.clock.Now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, silence := range s.silences {
+		if silence.State(now) == domain.SilenceActive && silence.Match.Matches(alert) {
+			return silence
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package silence
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
+	storemem "argus-go/internal/store/memory"
+)
+
+func TestScheduler_Recurring(t *testing.T) {
+	ctx := context.Background()
+	repo := storemem.NewSilenceRepository()
+	clk := clock.NewFake(time.Date(2026, 3, 1, 1, 45, 0, 0, time.UTC))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	scheduler := NewScheduler(repo, 30*time.Minute, clk, logger)
+
+	nightly := &domain.Silence{
+		ID:       "nightly",
+		Match:    domain.SilenceMatcher{Classes: []string{"batch"}},
+		Schedule: &domain.SilenceSchedule{Cron: "0 2 * * *", Duration: domain.Duration(time.Hour)},
+	}
+	if err := repo.Create(ctx, nightly); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	batch := &domain.Alert{DedupKey: "batch-1", Class: "batch"}
+	db := &domain.Alert{DedupKey: "db-1", Class: "db"}
+
+	// The occurrence starting within the interval is materialized ahead
+	for range 2 {
+		if err := scheduler.Check(ctx); err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+	}
+	silences, _ := repo.List(ctx)
+	if len(silences) != 2 {
+		t.Fatalf("silences = %d, want the recurring one and one occurrence", len(silences))
+	}
+	if s := scheduler.Silenced(batch); s != nil {
+		t.Errorf("Silenced() before the window = %s, want nil", s.ID)
+	}
+
+	// It takes effect at its start without another check
+	clk.Advance(15 * time.Minute)
+	s := scheduler.Silenced(batch)
+	if s == nil || s.RecurringID != nightly.ID {
+		t.Fatalf("Silenced() in the window = %+v, want an occurrence of %s", s, nightly.ID)
+	}
+	if s := scheduler.Silenced(db); s != nil {
+		t.Errorf("Silenced() of an unmatched alert = %s, want nil", s.ID)
+	}
+
+	clk.Advance(time.Hour)
+	if s := scheduler.Silenced(batch); s != nil {
+		t.Errorf("Silenced() after the window = %s, want nil", s.ID)
+	}
+}
+
+func TestScheduler_Nil(t *testing.T) {
+	var scheduler *Scheduler
+	if s := scheduler.Silenced(&domain.Alert{DedupKey: "db-1"}); s != nil {
+		t.Errorf("Silenced() = %+v, want nil", s)
+	}
+	if err := scheduler.Refresh(context.Background()); err != nil {
+		t.Errorf("Refresh() error = %v", err)
+	}
+}
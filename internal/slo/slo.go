@@ -0,0 +1,218 @@
+// Package slo tracks service level objectives for alert processing.
+// Each objective counts good and total events in one-minute buckets over a
+// rolling window and derives compliance, remaining error budget and burn rates.
+package slo
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"argus-go/internal/config"
+)
+
+// bucketWidth is the resolution of the rolling window.
+const bucketWidth = time.Minute
+
+// burnRateWindows are the short windows reported in addition to the full objective window.
+var burnRateWindows = []time.Duration{5 * time.Minute, time.Hour}
+
+// ErrInvalidObjective is returned when an objective definition is invalid.
+var ErrInvalidObjective = errors.New("invalid slo objective")
+
+// Status is the computed state of a single objective.
+type Status struct {
+	Name                 string             `json:"name"`
+	Description          string             `json:"description,omitempty"`
+	Target               float64            `json:"target"`
+	Threshold            string             `json:"threshold"`
+	Window               string             `json:"window"`
+	TotalEvents          int64              `json:"total_events"`
+	GoodEvents           int64              `json:"good_events"`
+	Compliance           float64            `json:"compliance"`
+	ErrorBudgetRemaining float64            `json:"error_budget_remaining"`
+	BurnRates            map[string]float64 `json:"burn_rates"`
+	Breached             bool               `json:"breached"`
+}
+
+// bucket counts events observed during one minute.
+type bucket struct {
+	minute int64
+	good   int64
+	total  int64
+}
+
+// objective is a configured objective with its rolling window of buckets.
+type objective struct {
+	cfg     config.SLOObjectiveConfig
+	buckets []bucket
+}
+
+// Tracker records alert creation latencies and evaluates them against the configured objectives.
+// It also implements prometheus.Collector to export burn rates and remaining error budgets.
+// A nil Tracker is valid and records nothing.
+type Tracker struct {
+	mu         sync.Mutex
+	objectives []*objective
+	now        func() time.Time
+
+	burnRateDesc    *prometheus.Desc
+	errorBudgetDesc *prometheus.Desc
+	complianceDesc  *prometheus.Desc
+}
+
+// NewTracker creates a tracker for the configured objectives.
+func NewTracker(cfg *config.SLOConfig) (*Tracker, error) {
+	t := &Tracker{
+		now: time.Now,
+		burnRateDesc: prometheus.NewDesc(
+			"argus_slo_burn_rate",
+			"Rate at which the error budget is consumed (1 = exactly on budget).",
+			[]string{"objective", "window"}, nil,
+		),
+		errorBudgetDesc: prometheus.NewDesc(
+			"argus_slo_error_budget_remaining",
+			"Fraction of the error budget left over the objective window.",
+			[]string{"objective"}, nil,
+		),
+		complianceDesc: prometheus.NewDesc(
+			"argus_slo_compliance",
+			"Fraction of good events over the objective window.",
+			[]string{"objective"}, nil,
+		),
+	}
+
+	for _, o := range cfg.Objectives {
+		if o.Name == "" {
+			return nil, fmt.Errorf("%w: name is required", ErrInvalidObjective)
+		}
+		if o.Target <= 0 || o.Target >= 1 {
+			return nil, fmt.Errorf("%w: %s: target must be between 0 and 1", ErrInvalidObjective, o.Name)
+		}
+		if o.Threshold <= 0 {
+			return nil, fmt.Errorf("%w: %s: threshold must be positive", ErrInvalidObjective, o.Name)
+		}
+		if o.Window < bucketWidth {
+			return nil, fmt.Errorf("%w: %s: window must be at least %s", ErrInvalidObjective, o.Name, bucketWidth)
+		}
+		t.objectives = append(t.objectives, &objective{
+			cfg:     o,
+			buckets: make([]bucket, int(o.Window/bucketWidth)),
+		})
+	}
+
+	return t, nil
+}
+
+// RecordAlertCreation records the latency between ingestion and alert creation.
+// The event counts as good for each objective whose threshold it meets.
+func (t *Tracker) RecordAlertCreation(latency time.Duration) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	minute := t.now().Unix() / int64(bucketWidth/time.Second)
+	for _, o := range t.objectives {
+		b := &o.buckets[minute%int64(len(o.buckets))]
+		if b.minute != minute {
+			*b = bucket{minute: minute}
+		}
+		b.total++
+		if latency <= o.cfg.Threshold {
+			b.good++
+		}
+	}
+}
+
+// Status returns the current status of every objective.
+func (t *Tracker) Status() []*Status {
+	if t == nil {
+		return []*Status{}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	minute := t.now().Unix() / int64(bucketWidth/time.Second)
+	statuses := make([]*Status, 0, len(t.objectives))
+	for _, o := range t.objectives {
+		good, total := o.sum(minute, o.cfg.Window)
+		errorBudget := 1 - o.cfg.Target
+
+		status := &Status{
+			Name:                 o.cfg.Name,
+			Description:          o.cfg.Description,
+			Target:               o.cfg.Target,
+			Threshold:            o.cfg.Threshold.String(),
+			Window:               o.cfg.Window.String(),
+			TotalEvents:          total,
+			GoodEvents:           good,
+			Compliance:           ratio(good, total),
+			ErrorBudgetRemaining: 1 - errorRate(good, total)/errorBudget,
+			BurnRates:            make(map[string]float64),
+		}
+		status.Breached = total > 0 && status.Compliance < o.cfg.Target
+
+		for _, w := range burnRateWindows {
+			if w >= o.cfg.Window {
+				continue
+			}
+			wGood, wTotal := o.sum(minute, w)
+			status.BurnRates[w.String()] = errorRate(wGood, wTotal) / errorBudget
+		}
+		status.BurnRates[o.cfg.Window.String()] = errorRate(good, total) / errorBudget
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// Describe implements prometheus.Collector.
+func (t *Tracker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- t.burnRateDesc
+	ch <- t.errorBudgetDesc
+	ch <- t.complianceDesc
+}
+
+// Collect implements prometheus.Collector.
+func (t *Tracker) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range t.Status() {
+		for window, rate := range s.BurnRates {
+			ch <- prometheus.MustNewConstMetric(t.burnRateDesc, prometheus.GaugeValue, rate, s.Name, window)
+		}
+		ch <- prometheus.MustNewConstMetric(t.errorBudgetDesc, prometheus.GaugeValue, s.ErrorBudgetRemaining, s.Name)
+		ch <- prometheus.MustNewConstMetric(t.complianceDesc, prometheus.GaugeValue, s.Compliance, s.Name)
+	}
+}
+
+// sum returns the good and total counts of the buckets within the last window.
+func (o *objective) sum(minute int64, window time.Duration) (good, total int64) {
+	oldest := minute - int64(window/bucketWidth)
+	for _, b := range o.buckets {
+		if b.minute > oldest && b.minute <= minute {
+			good += b.good
+			total += b.total
+		}
+	}
+	return good, total
+}
+
+// ratio returns good/total, treating an empty window as fully compliant.
+func ratio(good, total int64) float64 {
+	if total == 0 {
+		return 1
+	}
+	return float64(good) / float64(total)
+}
+
+// errorRate returns the fraction of bad events.
+func errorRate(good, total int64) float64 {
+	return 1 - ratio(good, total)
+}
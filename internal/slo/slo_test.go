@@ -0,0 +1,81 @@
+package slo
+
+import (
+	"testing"
+	"time"
+
+	"argus-go/internal/config"
+)
+
+func TestTracker_Status(t *testing.T) {
+	tracker, err := NewTracker(&config.SLOConfig{
+		Objectives: []config.SLOObjectiveConfig{{
+			Name:      "creation",
+			Target:    0.9,
+			Threshold: 5 * time.Second,
+			Window:    24 * time.Hour,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("NewTracker error: %v", err)
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	// Two hours ago: 10 good events
+	now = now.Add(-2 * time.Hour)
+	for i := 0; i < 10; i++ {
+		tracker.RecordAlertCreation(time.Second)
+	}
+
+	// Now: 8 good, 2 slow
+	now = now.Add(2 * time.Hour)
+	for i := 0; i < 8; i++ {
+		tracker.RecordAlertCreation(time.Second)
+	}
+	tracker.RecordAlertCreation(10 * time.Second)
+	tracker.RecordAlertCreation(10 * time.Second)
+
+	statuses := tracker.Status()
+	if len(statuses) != 1 {
+		t.Fatalf("Status() = %d objectives, want 1", len(statuses))
+	}
+	s := statuses[0]
+	if s.TotalEvents != 20 || s.GoodEvents != 18 {
+		t.Errorf("events = %d/%d, want 18/20", s.GoodEvents, s.TotalEvents)
+	}
+	if s.Breached {
+		t.Error("objective should not be breached at 90% compliance")
+	}
+	// The last hour only saw the 10 recent events, 20% of them bad: burn rate 2
+	if got := s.BurnRates["1h0m0s"]; got < 1.99 || got > 2.01 {
+		t.Errorf("1h burn rate = %v, want 2", got)
+	}
+	if got := s.ErrorBudgetRemaining; got > 0.01 || got < -0.01 {
+		t.Errorf("error budget remaining = %v, want 0", got)
+	}
+
+	// Events older than the window are dropped
+	now = now.Add(25 * time.Hour)
+	if s := tracker.Status()[0]; s.TotalEvents != 0 || s.Compliance != 1 {
+		t.Errorf("expired window = %d events, compliance %v", s.TotalEvents, s.Compliance)
+	}
+}
+
+func TestNewTracker_InvalidObjective(t *testing.T) {
+	_, err := NewTracker(&config.SLOConfig{
+		Objectives: []config.SLOObjectiveConfig{{Name: "bad", Target: 1.5, Threshold: time.Second, Window: time.Hour}},
+	})
+	if err == nil {
+		t.Error("NewTracker should reject target >= 1")
+	}
+}
+
+func TestTracker_Nil(t *testing.T) {
+	var tracker *Tracker
+	tracker.RecordAlertCreation(time.Second)
+	if len(tracker.Status()) != 0 {
+		t.Error("nil tracker should report no objectives")
+	}
+}
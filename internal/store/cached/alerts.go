@@ -0,0 +1,197 @@
+package cached
+
+import (
+	"context"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// AlertRepository is a read-through cache of the alerts the API serves to
+// dashboards, which poll them. The store.AlertRepository reads are passed
+// through, so the processor never acts on a cached alert; only the Cached
+// reads, used by the GET routes, are served from the cache.
+//
+// Writes through the wrapper invalidate the alert and the children of its
+// parent. Writes by the processors of other replicas are only seen once the
+// entry expires, so the TTLs are kept short.
+type AlertRepository struct {
+	store.AlertRepository
+
+	// alerts and children are nil when their route is not cached.
+	alerts   *cache[domain.Alert]
+	children *cache[[]domain.Alert]
+}
+
+// NewAlertRepository wraps next with caches of alerts by dedup key and of
+// the children of parents, whose entries live for alertTTL and childrenTTL.
+// A TTL that isn't positive disables its cache.
+func NewAlertRepository(next store.AlertRepository, alertTTL, childrenTTL time.Duration) *AlertRepository {
+	r := &AlertRepository{AlertRepository: next}
+	if alertTTL > 0 {
+		r.alerts = newCache[domain.Alert](alertTTL)
+	}
+	if childrenTTL > 0 {
+		r.children = newCache[[]domain.Alert](childrenTTL)
+	}
+	return r
+}
+
+// CachedGetByDedupKey returns the alert with the given dedup key, from the
+// cache if it holds it.
+func (r *AlertRepository) CachedGetByDedupKey(ctx context.Context, dedupKey string) (*domain.Alert, error) {
+	if r.alerts == nil {
+		return r.AlertRepository.GetByDedupKey(ctx, dedupKey)
+	}
+	return r.alerts.get(dedupKey, func() (*domain.Alert, error) {
+		return r.AlertRepository.GetByDedupKey(ctx, dedupKey)
+	})
+}
+
+// CachedGetChildrenByParent returns the children of a parent alert, from
+// the cache if it holds them.
+func (r *AlertRepository) CachedGetChildrenByParent(ctx context.Context, parentDedupKey string) ([]*domain.Alert, error) {
+	if r.children == nil {
+		return r.AlertRepository.GetChildrenByParent(ctx, parentDedupKey)
+	}
+	children, err := r.children.get(parentDedupKey, func() (*[]domain.Alert, error) {
+		loaded, err := r.AlertRepository.GetChildrenByParent(ctx, parentDedupKey)
+		if err != nil {
+			return nil, err
+		}
+		children := make([]domain.Alert, len(loaded))
+		for i, child := range loaded {
+			children[i] = *child
+		}
+		return &children, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The slice is shared with the cache, so return copies of its alerts
+	result := make([]*domain.Alert, len(*children))
+	for i, child := range *children {
+		result[i] = &child
+	}
+	return result, nil
+}
+
+// Create implements store.AlertRepository.
+func (r *AlertRepository) Create(ctx context.Context, alert *domain.Alert) error {
+	if err := r.AlertRepository.Create(ctx, alert); err != nil {
+		return err
+	}
+	r.changed(alert)
+	return nil
+}
+
+// Update implements store.AlertRepository.
+func (r *AlertRepository) Update(ctx context.Context, alert *domain.Alert) error {
+	if err := r.AlertRepository.Update(ctx, alert); err != nil {
+		return err
+	}
+	r.changed(alert)
+	return nil
+}
+
+// UpdateIfUnmodified implements store.AlertRepository.
+func (r *AlertRepository) UpdateIfUnmodified(ctx context.Context, alert *domain.Alert, updatedAt time.Time) error {
+	if err := r.AlertRepository.UpdateIfUnmodified(ctx, alert, updatedAt); err != nil {
+		return err
+	}
+	r.changed(alert)
+	return nil
+}
+
+// UpdateAll implements store.AlertRepository.
+func (r *AlertRepository) UpdateAll(ctx context.Context, alerts []*domain.Alert) error {
+	if err := r.AlertRepository.UpdateAll(ctx, alerts); err != nil {
+		return err
+	}
+	for _, alert := range alerts {
+		r.changed(alert)
+	}
+	return nil
+}
+
+// IncrementTriggerCount implements store.AlertRepository. The children of
+// the parent of the alert are left to expire, as its parent isn't known.
+func (r *AlertRepository) IncrementTriggerCount(ctx context.Context, dedupKey string) error {
+	if err := r.AlertRepository.IncrementTriggerCount(ctx, dedupKey); err != nil {
+		return err
+	}
+	r.changed(&domain.Alert{DedupKey: dedupKey})
+	return nil
+}
+
+// AddSampledEvents implements store.AlertRepository. Like
+// IncrementTriggerCount, it leaves the children of the parent to expire.
+func (r *AlertRepository) AddSampledEvents(ctx context.Context, dedupKey string, count int) error {
+	if err := r.AlertRepository.AddSampledEvents(ctx, dedupKey, count); err != nil {
+		return err
+	}
+	r.changed(&domain.Alert{DedupKey: dedupKey})
+	return nil
+}
+
+// MergeTags implements store.AlertRepository. Like IncrementTriggerCount,
+// it leaves the children of the parent to expire.
+func (r *AlertRepository) MergeTags(ctx context.Context, dedupKey string, tags map[string]string, replace bool) error {
+	if err := r.AlertRepository.MergeTags(ctx, dedupKey, tags, replace); err != nil {
+		return err
+	}
+	r.changed(&domain.Alert{DedupKey: dedupKey})
+	return nil
+}
+
+// UpdateLinks implements store.AlertRepository. Like MergeTags, it leaves
+// the children of the parent to expire.
+func (r *AlertRepository) UpdateLinks(ctx context.Context, dedupKey string, add []domain.AlertLink, remove []string) error {
+	if err := r.AlertRepository.UpdateLinks(ctx, dedupKey, add, remove); err != nil {
+		return err
+	}
+	r.changed(&domain.Alert{DedupKey: dedupKey})
+	return nil
+}
+
+// PurgeByEventManager implements store.AlertRepository.
+func (r *AlertRepository) PurgeByEventManager(ctx context.Context, eventManagerID string) (int, error) {
+	purged, err := r.AlertRepository.PurgeByEventManager(ctx, eventManagerID)
+	if err != nil {
+		return purged, err
+	}
+	if r.alerts != nil {
+		r.alerts.invalidate("")
+	}
+	if r.children != nil {
+		r.children.invalidate("")
+	}
+	return purged, nil
+}
+
+// Delete implements store.AlertRepository. The parent of the alert is not
+// known here, so the children of every parent are invalidated.
+func (r *AlertRepository) Delete(ctx context.Context, dedupKey string) error {
+	if err := r.AlertRepository.Delete(ctx, dedupKey); err != nil {
+		return err
+	}
+	if r.alerts != nil {
+		r.alerts.invalidate(dedupKey)
+	}
+	if r.children != nil {
+		r.children.invalidate("")
+	}
+	return nil
+}
+
+// changed invalidates an alert and the children of its parent.
+func (r *AlertRepository) changed(alert *domain.Alert) {
+	if r.alerts != nil {
+		r.alerts.invalidate(alert.DedupKey)
+	}
+	if r.children != nil && alert.ParentDedupKey != "" {
+		r.children.invalidate(alert.ParentDedupKey)
+	}
+}
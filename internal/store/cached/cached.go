@@ -0,0 +1,102 @@
+// Package cached provides caching wrappers around the event manager and
+// grouping rule repositories, which the ingest path and the processor look
+// up for every event. Writes through a wrapper invalidate the entry and
+// broadcast the change on a store.ChangeBus, so the caches of every replica
+// stay consistent; the TTL only bounds staleness when a change is missed.
+package cached
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"argus-go/internal/store"
+)
+
+// entry is a cached value with its expiry.
+type entry[T any] struct {
+	value   T
+	expires time.Time
+}
+
+// cache is a TTL cache of values by ID. Values are stored and returned as
+// copies, like the memory repositories do, so callers may modify them.
+type cache[T any] struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]entry[T]
+
+	// generation is bumped by every invalidation, so a value loaded while
+	// the cache was invalidated is not stored.
+	generation uint64
+}
+
+func newCache[T any](ttl time.Duration) *cache[T] {
+	return &cache[T]{ttl: ttl, entries: make(map[string]entry[T])}
+}
+
+// get returns the value of id, loading and caching it on a miss.
+// Errors are not cached.
+func (c *cache[T]) get(id string, load func() (*T, error)) (*T, error) {
+	c.mu.Lock()
+	e, ok := c.entries[id]
+	generation := c.generation
+	c.mu.Unlock()
+
+	if ok && time.Now().Before(e.expires) {
+		value := e.value
+		return &value, nil
+	}
+
+	loaded, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.entries[id] = entry[T]{value: *loaded, expires: time.Now().Add(c.ttl)}
+	}
+	c.mu.Unlock()
+
+	return loaded, nil
+}
+
+// invalidate removes the value of id, or every value if id is empty.
+func (c *cache[T]) invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if id == "" {
+		c.entries = make(map[string]entry[T])
+		return
+	}
+	delete(c.entries, id)
+}
+
+// invalidateChange applies a change of the given kind to the cache.
+func (c *cache[T]) invalidateChange(kind string, change store.ConfigChange) {
+	switch change.Kind {
+	case kind:
+		c.invalidate(change.ID)
+	case store.ChangeAll:
+		c.invalidate("")
+	}
+}
+
+// Invalidator is a cache that applies configuration changes.
+type Invalidator interface {
+	Invalidate(change store.ConfigChange)
+}
+
+// Listen applies the changes broadcast on the bus to the caches until the
+// context is canceled.
+func Listen(ctx context.Context, bus store.ChangeBus, caches ...Invalidator) error {
+	return bus.Subscribe(ctx, func(change store.ConfigChange) {
+		for _, c := range caches {
+			c.Invalidate(change)
+		}
+	})
+}
@@ -0,0 +1,199 @@
+package cached
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+	storemem "argus-go/internal/store/memory"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// countingGroupingRuleRepository counts GetByID calls reaching the repository.
+type countingGroupingRuleRepository struct {
+	store.GroupingRuleRepository
+	gets int
+}
+
+func (r *countingGroupingRuleRepository) GetByID(ctx context.Context, id string) (*domain.GroupingRule, error) {
+	r.gets++
+	return r.GroupingRuleRepository.GetByID(ctx, id)
+}
+
+func TestGroupingRuleRepository_Cache(t *testing.T) {
+	ctx := context.Background()
+	next := &countingGroupingRuleRepository{GroupingRuleRepository: storemem.NewGroupingRuleRepository()}
+	r := NewGroupingRuleRepository(next, storemem.NewChangeBus(), time.Minute, testLogger)
+
+	if err := r.Create(ctx, &domain.GroupingRule{ID: "rule-1", Name: "by class", GroupingKey: "class", TimeWindowMinutes: 5}); err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+
+	first, err := r.GetByID(ctx, "rule-1")
+	if err != nil {
+		t.Fatalf("GetByID error: %v", err)
+	}
+	first.Name = "modified by caller"
+	second, err := r.GetByID(ctx, "rule-1")
+	if err != nil {
+		t.Fatalf("GetByID error: %v", err)
+	}
+	if next.gets != 1 {
+		t.Errorf("repository GetByID calls = %d, want 1", next.gets)
+	}
+	if second.Name != "by class" {
+		t.Errorf("cached Name = %q, want the stored value unaffected by callers", second.Name)
+	}
+
+	second.GroupingKey = "severity"
+	if err := r.Update(ctx, second); err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+	updated, _ := r.GetByID(ctx, "rule-1")
+	if updated.GroupingKey != "severity" {
+		t.Errorf("GroupingKey after update = %q, want severity", updated.GroupingKey)
+	}
+}
+
+func TestEventManagerRepository_InvalidatedAcrossReplicas(t *testing.T) {
+	ctx := context.Background()
+
+	// Two replicas share the repository; replica A's changes reach B over the bus
+	next := storemem.NewEventManagerRepository()
+	bus := &recordingBus{}
+	replicaA := NewEventManagerRepository(next, bus, time.Hour, testLogger)
+	replicaB := NewEventManagerRepository(next, storemem.NewChangeBus(), time.Hour, testLogger)
+
+	if err := replicaA.Create(ctx, &domain.EventManager{ID: "em-1", Name: "Payments"}); err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	em, err := replicaB.GetByID(ctx, "em-1")
+	if err != nil || em.Name != "Payments" {
+		t.Fatalf("replica B GetByID = %v, %v", em, err)
+	}
+
+	em.Name = "Billing"
+	if err := replicaA.Update(ctx, em); err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+	if cached, _ := replicaB.GetByID(ctx, "em-1"); cached.Name != "Payments" {
+		t.Fatalf("replica B should serve its cached copy until the change arrives")
+	}
+
+	last := bus.changes[len(bus.changes)-1]
+	if last.Kind != store.ChangeEventManager || last.ID != "em-1" {
+		t.Fatalf("published change = %+v, want the event manager", last)
+	}
+	replicaB.Invalidate(last)
+	if em, err := replicaB.GetByID(ctx, "em-1"); err != nil || em.Name != "Billing" {
+		t.Errorf("replica B GetByID after change = %v, %v; want Billing", em, err)
+	}
+
+	// Missed changes empty the whole cache
+	replicaB.Invalidate(store.ConfigChange{Kind: store.ChangeAll})
+	if len(replicaB.cache.entries) != 0 {
+		t.Error("ChangeAll should empty the cache")
+	}
+}
+
+// recordingBus records published changes without delivering them.
+type recordingBus struct {
+	changes []store.ConfigChange
+}
+
+func (b *recordingBus) Publish(_ context.Context, change store.ConfigChange) error {
+	b.changes = append(b.changes, change)
+	return nil
+}
+
+func (b *recordingBus) Subscribe(ctx context.Context, _ func(store.ConfigChange)) error {
+	<-ctx.Done()
+	return nil
+}
+
+// countingAlertRepository counts the reads reaching the repository.
+type countingAlertRepository struct {
+	store.AlertRepository
+	gets, children int
+}
+
+func (r *countingAlertRepository) GetByDedupKey(ctx context.Context, dedupKey string) (*domain.Alert, error) {
+	r.gets++
+	return r.AlertRepository.GetByDedupKey(ctx, dedupKey)
+}
+
+func (r *countingAlertRepository) GetChildrenByParent(ctx context.Context, parentDedupKey string) ([]*domain.Alert, error) {
+	r.children++
+	return r.AlertRepository.GetChildrenByParent(ctx, parentDedupKey)
+}
+
+func TestAlertRepository_Cache(t *testing.T) {
+	ctx := context.Background()
+	next := &countingAlertRepository{AlertRepository: storemem.NewAlertRepository()}
+	r := NewAlertRepository(next, time.Minute, time.Minute)
+
+	parent := &domain.Alert{ID: "a1", DedupKey: "db", Type: domain.AlertTypeParent, Status: domain.AlertStatusActive}
+	child := &domain.Alert{ID: "a2", DedupKey: "db-1", Type: domain.AlertTypeChild, Status: domain.AlertStatusActive, ParentDedupKey: "db"}
+	for _, alert := range []*domain.Alert{parent, child} {
+		if err := r.Create(ctx, alert); err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+	}
+
+	for range 3 {
+		if _, err := r.CachedGetByDedupKey(ctx, "db"); err != nil {
+			t.Fatalf("CachedGetByDedupKey error: %v", err)
+		}
+		children, err := r.CachedGetChildrenByParent(ctx, "db")
+		if err != nil || len(children) != 1 {
+			t.Fatalf("CachedGetChildrenByParent = %v, %v; want the child", children, err)
+		}
+		children[0].Summary = "modified by caller"
+	}
+	if next.gets != 1 || next.children != 1 {
+		t.Errorf("repository reads = %d gets, %d children; want 1 each", next.gets, next.children)
+	}
+
+	// The processor's reads are never cached
+	if _, err := r.GetByDedupKey(ctx, "db"); err != nil || next.gets != 2 {
+		t.Errorf("GetByDedupKey = %v, gets = %d; want read through", err, next.gets)
+	}
+
+	// Writes invalidate the alert and the children of its parent
+	child.Status = domain.AlertStatusResolved
+	if err := r.Update(ctx, child); err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+	children, _ := r.CachedGetChildrenByParent(ctx, "db")
+	if next.children != 2 || children[0].Status != domain.AlertStatusResolved || children[0].Summary != "" {
+		t.Errorf("children after update = %+v, want the updated child", children[0])
+	}
+	if err := r.IncrementTriggerCount(ctx, "db"); err != nil {
+		t.Fatalf("IncrementTriggerCount error: %v", err)
+	}
+	if alert, _ := r.CachedGetByDedupKey(ctx, "db"); next.gets != 3 || alert.TriggerCount != parent.TriggerCount+1 {
+		t.Errorf("alert after IncrementTriggerCount = %+v, want it reloaded", alert)
+	}
+}
+
+func TestAlertRepository_Disabled(t *testing.T) {
+	ctx := context.Background()
+	next := &countingAlertRepository{AlertRepository: storemem.NewAlertRepository()}
+	r := NewAlertRepository(next, -1, 0)
+
+	if err := r.Create(ctx, &domain.Alert{ID: "a1", DedupKey: "db", Status: domain.AlertStatusActive}); err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	for range 2 {
+		_, _ = r.CachedGetByDedupKey(ctx, "db")
+		_, _ = r.CachedGetChildrenByParent(ctx, "db")
+	}
+	if next.gets != 2 || next.children != 2 {
+		t.Errorf("repository reads = %d gets, %d children; want every read passed through", next.gets, next.children)
+	}
+}
@@ -0,0 +1,166 @@
+package cached
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// publisher broadcasts the changes made through a wrapper.
+type publisher struct {
+	bus    store.ChangeBus
+	logger *slog.Logger
+}
+
+// publish broadcasts a change. Failures are logged only: the write has
+// succeeded, and other replicas catch up when their entry expires.
+func (p publisher) publish(ctx context.Context, kind, id string) {
+	if err := p.bus.Publish(ctx, store.ConfigChange{Kind: kind, ID: id}); err != nil {
+		p.logger.Warn("failed to publish config change", "kind", kind, "id", id, "error", err)
+	}
+}
+
+// EventManagerRepository caches GetByID of a store.EventManagerRepository.
+// The other reads are passed through.
+type EventManagerRepository struct {
+	store.EventManagerRepository
+	publisher
+	cache *cache[domain.EventManager]
+}
+
+// NewEventManagerRepository wraps next with a cache whose entries live for ttl.
+func NewEventManagerRepository(next store.EventManagerRepository, bus store.ChangeBus, ttl time.Duration, logger *slog.Logger) *EventManagerRepository {
+	return &EventManagerRepository{
+		EventManagerRepository: next,
+		publisher:              publisher{bus: bus, logger: logger},
+		cache:                  newCache[domain.EventManager](ttl),
+	}
+}
+
+// GetByID implements store.EventManagerRepository.
+func (r *EventManagerRepository) GetByID(ctx context.Context, id string) (*domain.EventManager, error) {
+	return r.cache.get(id, func() (*domain.EventManager, error) {
+		return r.EventManagerRepository.GetByID(ctx, id)
+	})
+}
+
+// Create implements store.EventManagerRepository.
+func (r *EventManagerRepository) Create(ctx context.Context, em *domain.EventManager) error {
+	if err := r.EventManagerRepository.Create(ctx, em); err != nil {
+		return err
+	}
+	r.changed(ctx, em.ID)
+	return nil
+}
+
+// Update implements store.EventManagerRepository.
+func (r *EventManagerRepository) Update(ctx context.Context, em *domain.EventManager) error {
+	if err := r.EventManagerRepository.Update(ctx, em); err != nil {
+		return err
+	}
+	r.changed(ctx, em.ID)
+	return nil
+}
+
+// Delete implements store.EventManagerRepository.
+func (r *EventManagerRepository) Delete(ctx context.Context, id string) error {
+	if err := r.EventManagerRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.changed(ctx, id)
+	return nil
+}
+
+// Purge implements store.EventManagerRepository.
+func (r *EventManagerRepository) Purge(ctx context.Context, id string) error {
+	if err := r.EventManagerRepository.Purge(ctx, id); err != nil {
+		return err
+	}
+	r.changed(ctx, id)
+	return nil
+}
+
+// changed invalidates an event manager here and on every other replica.
+func (r *EventManagerRepository) changed(ctx context.Context, id string) {
+	r.cache.invalidate(id)
+	r.publish(ctx, store.ChangeEventManager, id)
+}
+
+// Invalidate applies a change broadcast by any replica.
+func (r *EventManagerRepository) Invalidate(change store.ConfigChange) {
+	r.cache.invalidateChange(store.ChangeEventManager, change)
+}
+
+// GroupingRuleRepository caches GetByID of a store.GroupingRuleRepository.
+// List is passed through.
+type GroupingRuleRepository struct {
+	store.GroupingRuleRepository
+	publisher
+	cache *cache[domain.GroupingRule]
+}
+
+// NewGroupingRuleRepository wraps next with a cache whose entries live for ttl.
+func NewGroupingRuleRepository(next store.GroupingRuleRepository, bus store.ChangeBus, ttl time.Duration, logger *slog.Logger) *GroupingRuleRepository {
+	return &GroupingRuleRepository{
+		GroupingRuleRepository: next,
+		publisher:              publisher{bus: bus, logger: logger},
+		cache:                  newCache[domain.GroupingRule](ttl),
+	}
+}
+
+// GetByID implements store.GroupingRuleRepository.
+func (r *GroupingRuleRepository) GetByID(ctx context.Context, id string) (*domain.GroupingRule, error) {
+	return r.cache.get(id, func() (*domain.GroupingRule, error) {
+		return r.GroupingRuleRepository.GetByID(ctx, id)
+	})
+}
+
+// Create implements store.GroupingRuleRepository.
+func (r *GroupingRuleRepository) Create(ctx context.Context, rule *domain.GroupingRule) error {
+	if err := r.GroupingRuleRepository.Create(ctx, rule); err != nil {
+		return err
+	}
+	r.changed(ctx, rule.ID)
+	return nil
+}
+
+// Update implements store.GroupingRuleRepository.
+func (r *GroupingRuleRepository) Update(ctx context.Context, rule *domain.GroupingRule) error {
+	if err := r.GroupingRuleRepository.Update(ctx, rule); err != nil {
+		return err
+	}
+	r.changed(ctx, rule.ID)
+	return nil
+}
+
+// Delete implements store.GroupingRuleRepository.
+func (r *GroupingRuleRepository) Delete(ctx context.Context, id string) error {
+	if err := r.GroupingRuleRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.changed(ctx, id)
+	return nil
+}
+
+// Purge implements store.GroupingRuleRepository.
+func (r *GroupingRuleRepository) Purge(ctx context.Context, id string) error {
+	if err := r.GroupingRuleRepository.Purge(ctx, id); err != nil {
+		return err
+	}
+	r.changed(ctx, id)
+	return nil
+}
+
+// changed invalidates a grouping rule here and on every other replica.
+func (r *GroupingRuleRepository) changed(ctx context.Context, id string) {
+	r.cache.invalidate(id)
+	r.publish(ctx, store.ChangeGroupingRule, id)
+}
+
+// Invalidate applies a change broadcast by any replica.
+func (r *GroupingRuleRepository) Invalidate(change store.ConfigChange) {
+	r.cache.invalidateChange(store.ChangeGroupingRule, change)
+}
@@ -0,0 +1,34 @@
+package store
+
+import "context"
+
+// Kinds of configuration changes.
+const (
+	// ChangeEventManager is a created, updated, deleted or purged event manager.
+	ChangeEventManager = "event_manager"
+
+	// ChangeGroupingRule is a created, updated, deleted or purged grouping rule.
+	ChangeGroupingRule = "grouping_rule"
+
+	// ChangeAll means any configuration may have changed, e.g. because
+	// notifications were missed while a subscriber was disconnected.
+	ChangeAll = "all"
+)
+
+// ConfigChange identifies an event manager or grouping rule that changed.
+type ConfigChange struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id,omitempty"`
+}
+
+// ChangeBus broadcasts configuration changes to every replica, so caches of
+// event managers and grouping rules stay consistent across the fleet.
+type ChangeBus interface {
+	// Publish broadcasts a change to every subscriber, including those of
+	// this replica.
+	Publish(ctx context.Context, change ConfigChange) error
+
+	// Subscribe calls handler for every change until the context is
+	// canceled. Handlers are called one at a time.
+	Subscribe(ctx context.Context, handler func(ConfigChange)) error
+}
@@ -0,0 +1,99 @@
+// Package instrumented provides wrappers around the store implementations
+// that bound every operation with the configured timeout, log slow
+// operations and record their latency and result in the storage metrics, so
+// store hot spots show up whichever backend is used.
+package instrumented
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"argus-go/internal/config"
+	"argus-go/internal/metrics"
+	"argus-go/internal/store"
+)
+
+// Store names used as the "store" label of the storage metrics.
+const (
+	storeState            = "state_store"
+	storeAlerts           = "alerts"
+	storeEventManagers    = "event_managers"
+	storeGroupingRules    = "grouping_rules"
+	storeRoutingRules     = "routing_rules"
+	storeWatches          = "watches"
+	storeSilences         = "silences"
+	storeQueryRules       = "query_rules"
+	storeRuleTemplates    = "rule_templates"
+	storeRuleExecutions   = "rule_executions"
+	storeRuleRunners      = "rule_runners"
+	storeNotificationLogs = "notification_log"
+	storeRemediationLogs  = "remediation_log"
+	storeAlertFeedback    = "alert_feedback"
+	storeAuditLog         = "audit_log"
+	storeUsage            = "usage"
+	storeReportSchedules  = "report_schedules"
+)
+
+// observer times the operations of one store.
+type observer struct {
+	store  string
+	cfg    config.StoreOperationsConfig
+	logger *slog.Logger
+}
+
+// newObserver creates the observer of a store.
+func newObserver(storeName string, cfg config.StoreOperationsConfig, logger *slog.Logger) observer {
+	return observer{store: storeName, cfg: cfg, logger: logger}
+}
+
+// operation is an operation in progress.
+type operation struct {
+	observer
+	name   string
+	start  time.Time
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// begin starts an operation, bounding its context by the operation timeout.
+// The operation must be ended with end, deferred with a pointer to its named
+// error result.
+func (o observer) begin(ctx context.Context, name string) (context.Context, *operation) {
+	op := &operation{observer: o, name: name, start: time.Now(), parent: ctx, ctx: ctx, cancel: func() {}}
+	if o.cfg.Timeout > 0 {
+		op.ctx, op.cancel = context.WithTimeout(ctx, o.cfg.Timeout)
+	}
+	return op.ctx, op
+}
+
+// end finishes an operation that returned *err. An operation that ran out
+// of its own timeout, rather than being canceled by the caller, fails with
+// store.ErrTimeout, so the processor retries it like any other store error.
+func (op *operation) end(err *error) {
+	op.cancel()
+	elapsed := time.Since(op.start)
+
+	if *err != nil && errors.Is(op.ctx.Err(), context.DeadlineExceeded) && op.parent.Err() == nil {
+		*err = fmt.Errorf("%w: %s %s after %s: %v", store.ErrTimeout, op.store, op.name, op.cfg.Timeout, *err)
+	}
+
+	if op.cfg.SlowThreshold > 0 && elapsed > op.cfg.SlowThreshold {
+		op.logger.Warn("slow store operation",
+			"store", op.store,
+			"operation", op.name,
+			"duration", elapsed,
+			"error", *err,
+		)
+	}
+
+	metrics.StorageOperationLatency.WithLabelValues(op.store, op.name).Observe(elapsed.Seconds())
+	result := "ok"
+	if *err != nil {
+		result = "error"
+	}
+	metrics.StorageOperationsTotal.WithLabelValues(op.store, op.name, result).Inc()
+}
@@ -0,0 +1,91 @@
+package instrumented
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	"argus-go/internal/metrics"
+	"argus-go/internal/store"
+	storemem "argus-go/internal/store/memory"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// stuckAlertRepository blocks GetByDedupKey until its context is done.
+type stuckAlertRepository struct {
+	store.AlertRepository
+}
+
+func (r stuckAlertRepository) GetByDedupKey(ctx context.Context, _ string) (*domain.Alert, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// operationCount returns the current value of the operations counter.
+func operationCount(t *testing.T, storeName, operation, result string) float64 {
+	t.Helper()
+	var m dto.Metric
+	if err := metrics.StorageOperationsTotal.WithLabelValues(storeName, operation, result).Write(&m); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestStateStore_RecordsOperations(t *testing.T) {
+	s := NewStateStore(storemem.NewStateStore(), config.StoreOperationsConfig{}, testLogger)
+	ctx := context.Background()
+
+	before := operationCount(t, storeState, "set_alert", "ok")
+	if err := s.SetAlert(ctx, &store.AlertState{DedupKey: "a"}); err != nil {
+		t.Fatalf("SetAlert error: %v", err)
+	}
+	if got := operationCount(t, storeState, "set_alert", "ok"); got != before+1 {
+		t.Errorf("set_alert ok count = %v, want %v", got, before+1)
+	}
+
+	state, err := s.GetAlert(ctx, "a")
+	if err != nil || state == nil {
+		t.Fatalf("GetAlert = %v, %v; want the stored state", state, err)
+	}
+}
+
+func TestAlertRepository_RecordsErrors(t *testing.T) {
+	r := NewAlertRepository(storemem.NewAlertRepository(), config.StoreOperationsConfig{}, testLogger)
+	ctx := context.Background()
+
+	before := operationCount(t, storeAlerts, "get_by_dedup_key", "error")
+	if _, err := r.GetByDedupKey(ctx, "missing"); !errors.Is(err, domain.ErrAlertNotFound) {
+		t.Fatalf("GetByDedupKey error = %v, want ErrAlertNotFound", err)
+	}
+	if got := operationCount(t, storeAlerts, "get_by_dedup_key", "error"); got != before+1 {
+		t.Errorf("get_by_dedup_key error count = %v, want %v", got, before+1)
+	}
+}
+
+func TestAlertRepository_Timeout(t *testing.T) {
+	cfg := config.StoreOperationsConfig{Timeout: 10 * time.Millisecond}
+	r := NewAlertRepository(stuckAlertRepository{}, cfg, testLogger)
+
+	_, err := r.GetByDedupKey(context.Background(), "a")
+	if !errors.Is(err, store.ErrTimeout) {
+		t.Fatalf("GetByDedupKey error = %v, want ErrTimeout", err)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		t.Error("timeout should not look like a deadline of the caller")
+	}
+
+	// A caller's own cancellation is passed through
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.GetByDedupKey(ctx, "a"); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetByDedupKey with canceled context error = %v, want context.Canceled", err)
+	}
+}
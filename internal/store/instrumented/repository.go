@@ -0,0 +1,829 @@
+package instrumented
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// AlertRepository wraps a store.AlertRepository with operation timeouts and storage metrics.
+type AlertRepository struct {
+	observer
+	next store.AlertRepository
+}
+
+// NewAlertRepository wraps next with operation timeouts and storage metrics.
+func NewAlertRepository(next store.AlertRepository, cfg config.StoreOperationsConfig, logger *slog.Logger) *AlertRepository {
+	return &AlertRepository{observer: newObserver(storeAlerts, cfg, logger), next: next}
+}
+
+// Create implements store.AlertRepository.
+func (r *AlertRepository) Create(ctx context.Context, alert *domain.Alert) (err error) {
+	ctx, op := r.begin(ctx, "create")
+	defer op.end(&err)
+	return r.next.Create(ctx, alert)
+}
+
+// Update implements store.AlertRepository.
+func (r *AlertRepository) Update(ctx context.Context, alert *domain.Alert) (err error) {
+	ctx, op := r.begin(ctx, "update")
+	defer op.end(&err)
+	return r.next.Update(ctx, alert)
+}
+
+// UpdateIfUnmodified implements store.AlertRepository.
+func (r *AlertRepository) UpdateIfUnmodified(ctx context.Context, alert *domain.Alert, updatedAt time.Time) (err error) {
+	ctx, op := r.begin(ctx, "update_if_unmodified")
+	defer op.end(&err)
+	return r.next.UpdateIfUnmodified(ctx, alert, updatedAt)
+}
+
+// UpdateAll implements store.AlertRepository.
+func (r *AlertRepository) UpdateAll(ctx context.Context, alerts []*domain.Alert) (err error) {
+	ctx, op := r.begin(ctx, "update_all")
+	defer op.end(&err)
+	return r.next.UpdateAll(ctx, alerts)
+}
+
+// GetByID implements store.AlertRepository.
+func (r *AlertRepository) GetByID(ctx context.Context, id string) (alert *domain.Alert, err error) {
+	ctx, op := r.begin(ctx, "get_by_id")
+	defer op.end(&err)
+	return r.next.GetByID(ctx, id)
+}
+
+// GetByDedupKey implements store.AlertRepository.
+func (r *AlertRepository) GetByDedupKey(ctx context.Context, dedupKey string) (alert *domain.Alert, err error) {
+	ctx, op := r.begin(ctx, "get_by_dedup_key")
+	defer op.end(&err)
+	return r.next.GetByDedupKey(ctx, dedupKey)
+}
+
+// List implements store.AlertRepository.
+func (r *AlertRepository) List(ctx context.Context, filter domain.AlertFilter) (alerts []*domain.Alert, err error) {
+	ctx, op := r.begin(ctx, "list")
+	defer op.end(&err)
+	return r.next.List(ctx, filter)
+}
+
+// Count implements store.AlertRepository.
+func (r *AlertRepository) Count(ctx context.Context, filter domain.AlertFilter) (count int, err error) {
+	ctx, op := r.begin(ctx, "count")
+	defer op.end(&err)
+	return r.next.Count(ctx, filter)
+}
+
+// GetChildrenByParent implements store.AlertRepository.
+func (r *AlertRepository) GetChildrenByParent(ctx context.Context, parentDedupKey string) (alerts []*domain.Alert, err error) {
+	ctx, op := r.begin(ctx, "get_children_by_parent")
+	defer op.end(&err)
+	return r.next.GetChildrenByParent(ctx, parentDedupKey)
+}
+
+// CountActiveChildren implements store.AlertRepository.
+func (r *AlertRepository) CountActiveChildren(ctx context.Context, parentDedupKey string) (count int, err error) {
+	ctx, op := r.begin(ctx, "count_active_children")
+	defer op.end(&err)
+	return r.next.CountActiveChildren(ctx, parentDedupKey)
+}
+
+// CountActive implements store.AlertRepository.
+func (r *AlertRepository) CountActive(ctx context.Context) (counts []*domain.ActiveAlertCount, err error) {
+	ctx, op := r.begin(ctx, "count_active")
+	defer op.end(&err)
+	return r.next.CountActive(ctx)
+}
+
+// IncrementTriggerCount implements store.AlertRepository.
+func (r *AlertRepository) IncrementTriggerCount(ctx context.Context, dedupKey string) (err error) {
+	ctx, op := r.begin(ctx, "increment_trigger_count")
+	defer op.end(&err)
+	return r.next.IncrementTriggerCount(ctx, dedupKey)
+}
+
+// AddSampledEvents implements store.AlertRepository.
+func (r *AlertRepository) AddSampledEvents(ctx context.Context, dedupKey string, count int) (err error) {
+	ctx, op := r.begin(ctx, "add_sampled_events")
+	defer op.end(&err)
+	return r.next.AddSampledEvents(ctx, dedupKey, count)
+}
+
+// MergeTags implements store.AlertRepository.
+func (r *AlertRepository) MergeTags(ctx context.Context, dedupKey string, tags map[string]string, replace bool) (err error) {
+	ctx, op := r.begin(ctx, "merge_tags")
+	defer op.end(&err)
+	return r.next.MergeTags(ctx, dedupKey, tags, replace)
+}
+
+// UpdateLinks implements store.AlertRepository.
+func (r *AlertRepository) UpdateLinks(ctx context.Context, dedupKey string, add []domain.AlertLink, remove []string) (err error) {
+	ctx, op := r.begin(ctx, "update_links")
+	defer op.end(&err)
+	return r.next.UpdateLinks(ctx, dedupKey, add, remove)
+}
+
+// History implements store.AlertRepository.
+func (r *AlertRepository) History(ctx context.Context, dedupKey string) (revisions []*domain.AlertRevision, err error) {
+	ctx, op := r.begin(ctx, "history")
+	defer op.end(&err)
+	return r.next.History(ctx, dedupKey)
+}
+
+// RevisionAt implements store.AlertRepository.
+func (r *AlertRepository) RevisionAt(ctx context.Context, dedupKey string, at time.Time) (revision *domain.AlertRevision, err error) {
+	ctx, op := r.begin(ctx, "revision_at")
+	defer op.end(&err)
+	return r.next.RevisionAt(ctx, dedupKey, at)
+}
+
+// PurgeByEventManager implements store.AlertRepository.
+func (r *AlertRepository) PurgeByEventManager(ctx context.Context, eventManagerID string) (count int, err error) {
+	ctx, op := r.begin(ctx, "purge_by_event_manager")
+	defer op.end(&err)
+	return r.next.PurgeByEventManager(ctx, eventManagerID)
+}
+
+// Delete implements store.AlertRepository.
+func (r *AlertRepository) Delete(ctx context.Context, dedupKey string) (err error) {
+	ctx, op := r.begin(ctx, "delete")
+	defer op.end(&err)
+	return r.next.Delete(ctx, dedupKey)
+}
+
+// ReportRepository wraps a store.ReportRepository with operation timeouts and storage metrics,
+// recorded under the alerts store it reads from.
+type ReportRepository struct {
+	observer
+	next store.ReportRepository
+}
+
+// NewReportRepository wraps next with operation timeouts and storage metrics.
+func NewReportRepository(next store.ReportRepository, cfg config.StoreOperationsConfig, logger *slog.Logger) *ReportRepository {
+	return &ReportRepository{observer: newObserver(storeAlerts, cfg, logger), next: next}
+}
+
+// MTTR implements store.ReportRepository.
+func (r *ReportRepository) MTTR(ctx context.Context, filter domain.ReportFilter) (stats []*domain.MTTRStats, err error) {
+	ctx, op := r.begin(ctx, "report_mttr")
+	defer op.end(&err)
+	return r.next.MTTR(ctx, filter)
+}
+
+// DailyVolume implements store.ReportRepository.
+func (r *ReportRepository) DailyVolume(ctx context.Context, filter domain.ReportFilter) (volume []*domain.DailyVolume, err error) {
+	ctx, op := r.begin(ctx, "report_daily_volume")
+	defer op.end(&err)
+	return r.next.DailyVolume(ctx, filter)
+}
+
+// TopDedupKeys implements store.ReportRepository.
+func (r *ReportRepository) TopDedupKeys(ctx context.Context, filter domain.ReportFilter) (volume []*domain.DedupKeyVolume, err error) {
+	ctx, op := r.begin(ctx, "report_top_dedup_keys")
+	defer op.end(&err)
+	return r.next.TopDedupKeys(ctx, filter)
+}
+
+// NoiseStats implements store.ReportRepository.
+func (r *ReportRepository) NoiseStats(ctx context.Context, filter domain.ReportFilter) (stats []*domain.NoiseStats, err error) {
+	ctx, op := r.begin(ctx, "report_noise_stats")
+	defer op.end(&err)
+	return r.next.NoiseStats(ctx, filter)
+}
+
+// SourceStats implements store.ReportRepository.
+func (r *ReportRepository) SourceStats(ctx context.Context, filter domain.ReportFilter) (stats []*domain.SourceStats, err error) {
+	ctx, op := r.begin(ctx, "report_source_stats")
+	defer op.end(&err)
+	return r.next.SourceStats(ctx, filter)
+}
+
+// EventManagerRepository wraps a store.EventManagerRepository with operation timeouts and storage metrics.
+type EventManagerRepository struct {
+	observer
+	next store.EventManagerRepository
+}
+
+// NewEventManagerRepository wraps next with operation timeouts and storage metrics.
+func NewEventManagerRepository(next store.EventManagerRepository, cfg config.StoreOperationsConfig, logger *slog.Logger) *EventManagerRepository {
+	return &EventManagerRepository{observer: newObserver(storeEventManagers, cfg, logger), next: next}
+}
+
+// Create implements store.EventManagerRepository.
+func (r *EventManagerRepository) Create(ctx context.Context, em *domain.EventManager) (err error) {
+	ctx, op := r.begin(ctx, "create")
+	defer op.end(&err)
+	return r.next.Create(ctx, em)
+}
+
+// Update implements store.EventManagerRepository.
+func (r *EventManagerRepository) Update(ctx context.Context, em *domain.EventManager) (err error) {
+	ctx, op := r.begin(ctx, "update")
+	defer op.end(&err)
+	return r.next.Update(ctx, em)
+}
+
+// Delete implements store.EventManagerRepository.
+func (r *EventManagerRepository) Delete(ctx context.Context, id string) (err error) {
+	ctx, op := r.begin(ctx, "delete")
+	defer op.end(&err)
+	return r.next.Delete(ctx, id)
+}
+
+// Purge implements store.EventManagerRepository.
+func (r *EventManagerRepository) Purge(ctx context.Context, id string) (err error) {
+	ctx, op := r.begin(ctx, "purge")
+	defer op.end(&err)
+	return r.next.Purge(ctx, id)
+}
+
+// GetByID implements store.EventManagerRepository.
+func (r *EventManagerRepository) GetByID(ctx context.Context, id string) (em *domain.EventManager, err error) {
+	ctx, op := r.begin(ctx, "get_by_id")
+	defer op.end(&err)
+	return r.next.GetByID(ctx, id)
+}
+
+// GetByIngestToken implements store.EventManagerRepository.
+func (r *EventManagerRepository) GetByIngestToken(ctx context.Context, token string) (em *domain.EventManager, err error) {
+	ctx, op := r.begin(ctx, "get_by_ingest_token")
+	defer op.end(&err)
+	return r.next.GetByIngestToken(ctx, token)
+}
+
+// List implements store.EventManagerRepository.
+func (r *EventManagerRepository) List(ctx context.Context) (ems []*domain.EventManager, err error) {
+	ctx, op := r.begin(ctx, "list")
+	defer op.end(&err)
+	return r.next.List(ctx)
+}
+
+// ListByGroupingRule implements store.EventManagerRepository.
+func (r *EventManagerRepository) ListByGroupingRule(ctx context.Context, groupingRuleID string) (ems []*domain.EventManager, err error) {
+	ctx, op := r.begin(ctx, "list_by_grouping_rule")
+	defer op.end(&err)
+	return r.next.ListByGroupingRule(ctx, groupingRuleID)
+}
+
+// GroupingRuleRepository wraps a store.GroupingRuleRepository with operation timeouts and storage metrics.
+type GroupingRuleRepository struct {
+	observer
+	next store.GroupingRuleRepository
+}
+
+// NewGroupingRuleRepository wraps next with operation timeouts and storage metrics.
+func NewGroupingRuleRepository(next store.GroupingRuleRepository, cfg config.StoreOperationsConfig, logger *slog.Logger) *GroupingRuleRepository {
+	return &GroupingRuleRepository{observer: newObserver(storeGroupingRules, cfg, logger), next: next}
+}
+
+// Create implements store.GroupingRuleRepository.
+func (r *GroupingRuleRepository) Create(ctx context.Context, rule *domain.GroupingRule) (err error) {
+	ctx, op := r.begin(ctx, "create")
+	defer op.end(&err)
+	return r.next.Create(ctx, rule)
+}
+
+// Update implements store.GroupingRuleRepository.
+func (r *GroupingRuleRepository) Update(ctx context.Context, rule *domain.GroupingRule) (err error) {
+	ctx, op := r.begin(ctx, "update")
+	defer op.end(&err)
+	return r.next.Update(ctx, rule)
+}
+
+// Delete implements store.GroupingRuleRepository.
+func (r *GroupingRuleRepository) Delete(ctx context.Context, id string) (err error) {
+	ctx, op := r.begin(ctx, "delete")
+	defer op.end(&err)
+	return r.next.Delete(ctx, id)
+}
+
+// Purge implements store.GroupingRuleRepository.
+func (r *GroupingRuleRepository) Purge(ctx context.Context, id string) (err error) {
+	ctx, op := r.begin(ctx, "purge")
+	defer op.end(&err)
+	return r.next.Purge(ctx, id)
+}
+
+// GetByID implements store.GroupingRuleRepository.
+func (r *GroupingRuleRepository) GetByID(ctx context.Context, id string) (rule *domain.GroupingRule, err error) {
+	ctx, op := r.begin(ctx, "get_by_id")
+	defer op.end(&err)
+	return r.next.GetByID(ctx, id)
+}
+
+// List implements store.GroupingRuleRepository.
+func (r *GroupingRuleRepository) List(ctx context.Context) (rules []*domain.GroupingRule, err error) {
+	ctx, op := r.begin(ctx, "list")
+	defer op.end(&err)
+	return r.next.List(ctx)
+}
+
+// RoutingRuleRepository wraps a store.RoutingRuleRepository with operation timeouts and storage metrics.
+type RoutingRuleRepository struct {
+	observer
+	next store.RoutingRuleRepository
+}
+
+// NewRoutingRuleRepository wraps next with operation timeouts and storage metrics.
+func NewRoutingRuleRepository(next store.RoutingRuleRepository, cfg config.StoreOperationsConfig, logger *slog.Logger) *RoutingRuleRepository {
+	return &RoutingRuleRepository{observer: newObserver(storeRoutingRules, cfg, logger), next: next}
+}
+
+// Create implements store.RoutingRuleRepository.
+func (r *RoutingRuleRepository) Create(ctx context.Context, rule *domain.RoutingRule) (err error) {
+	ctx, op := r.begin(ctx, "create")
+	defer op.end(&err)
+	return r.next.Create(ctx, rule)
+}
+
+// Update implements store.RoutingRuleRepository.
+func (r *RoutingRuleRepository) Update(ctx context.Context, rule *domain.RoutingRule) (err error) {
+	ctx, op := r.begin(ctx, "update")
+	defer op.end(&err)
+	return r.next.Update(ctx, rule)
+}
+
+// Delete implements store.RoutingRuleRepository.
+func (r *RoutingRuleRepository) Delete(ctx context.Context, id string) (err error) {
+	ctx, op := r.begin(ctx, "delete")
+	defer op.end(&err)
+	return r.next.Delete(ctx, id)
+}
+
+// GetByID implements store.RoutingRuleRepository.
+func (r *RoutingRuleRepository) GetByID(ctx context.Context, id string) (rule *domain.RoutingRule, err error) {
+	ctx, op := r.begin(ctx, "get_by_id")
+	defer op.end(&err)
+	return r.next.GetByID(ctx, id)
+}
+
+// List implements store.RoutingRuleRepository.
+func (r *RoutingRuleRepository) List(ctx context.Context) (rules []*domain.RoutingRule, err error) {
+	ctx, op := r.begin(ctx, "list")
+	defer op.end(&err)
+	return r.next.List(ctx)
+}
+
+// WatchRepository wraps a store.WatchRepository with operation timeouts and storage metrics.
+type WatchRepository struct {
+	observer
+	next store.WatchRepository
+}
+
+// NewWatchRepository wraps next with operation timeouts and storage metrics.
+func NewWatchRepository(next store.WatchRepository, cfg config.StoreOperationsConfig, logger *slog.Logger) *WatchRepository {
+	return &WatchRepository{observer: newObserver(storeWatches, cfg, logger), next: next}
+}
+
+// Create implements store.WatchRepository.
+func (r *WatchRepository) Create(ctx context.Context, watch *domain.Watch) (err error) {
+	ctx, op := r.begin(ctx, "create")
+	defer op.end(&err)
+	return r.next.Create(ctx, watch)
+}
+
+// Delete implements store.WatchRepository.
+func (r *WatchRepository) Delete(ctx context.Context, id string) (err error) {
+	ctx, op := r.begin(ctx, "delete")
+	defer op.end(&err)
+	return r.next.Delete(ctx, id)
+}
+
+// GetByID implements store.WatchRepository.
+func (r *WatchRepository) GetByID(ctx context.Context, id string) (watch *domain.Watch, err error) {
+	ctx, op := r.begin(ctx, "get_by_id")
+	defer op.end(&err)
+	return r.next.GetByID(ctx, id)
+}
+
+// List implements store.WatchRepository.
+func (r *WatchRepository) List(ctx context.Context) (watches []*domain.Watch, err error) {
+	ctx, op := r.begin(ctx, "list")
+	defer op.end(&err)
+	return r.next.List(ctx)
+}
+
+// ListByUser implements store.WatchRepository.
+func (r *WatchRepository) ListByUser(ctx context.Context, user string) (watches []*domain.Watch, err error) {
+	ctx, op := r.begin(ctx, "list_by_user")
+	defer op.end(&err)
+	return r.next.ListByUser(ctx, user)
+}
+
+// SilenceRepository wraps a store.SilenceRepository with operation timeouts and storage metrics.
+type SilenceRepository struct {
+	observer
+	next store.SilenceRepository
+}
+
+// NewSilenceRepository wraps next with operation timeouts and storage metrics.
+func NewSilenceRepository(next store.SilenceRepository, cfg config.StoreOperationsConfig, logger *slog.Logger) *SilenceRepository {
+	return &SilenceRepository{observer: newObserver(storeSilences, cfg, logger), next: next}
+}
+
+// Create implements store.SilenceRepository.
+func (r *SilenceRepository) Create(ctx context.Context, silence *domain.Silence) (err error) {
+	ctx, op := r.begin(ctx, "create")
+	defer op.end(&err)
+	return r.next.Create(ctx, silence)
+}
+
+// Delete implements store.SilenceRepository.
+func (r *SilenceRepository) Delete(ctx context.Context, id string) (err error) {
+	ctx, op := r.begin(ctx, "delete")
+	defer op.end(&err)
+	return r.next.Delete(ctx, id)
+}
+
+// DeleteByRecurringID implements store.SilenceRepository.
+func (r *SilenceRepository) DeleteByRecurringID(ctx context.Context, recurringID string) (err error) {
+	ctx, op := r.begin(ctx, "delete_by_recurring_id")
+	defer op.end(&err)
+	return r.next.DeleteByRecurringID(ctx, recurringID)
+}
+
+// GetByID implements store.SilenceRepository.
+func (r *SilenceRepository) GetByID(ctx context.Context, id string) (silence *domain.Silence, err error) {
+	ctx, op := r.begin(ctx, "get_by_id")
+	defer op.end(&err)
+	return r.next.GetByID(ctx, id)
+}
+
+// List implements store.SilenceRepository.
+func (r *SilenceRepository) List(ctx context.Context) (silences []*domain.Silence, err error) {
+	ctx, op := r.begin(ctx, "list")
+	defer op.end(&err)
+	return r.next.List(ctx)
+}
+
+// QueryRuleRepository wraps a store.QueryRuleRepository with operation timeouts and storage metrics.
+type QueryRuleRepository struct {
+	observer
+	next store.QueryRuleRepository
+}
+
+// NewQueryRuleRepository wraps next with operation timeouts and storage metrics.
+func NewQueryRuleRepository(next store.QueryRuleRepository, cfg config.StoreOperationsConfig, logger *slog.Logger) *QueryRuleRepository {
+	return &QueryRuleRepository{observer: newObserver(storeQueryRules, cfg, logger), next: next}
+}
+
+// Create implements store.QueryRuleRepository.
+func (r *QueryRuleRepository) Create(ctx context.Context, rule *domain.QueryRule) (err error) {
+	ctx, op := r.begin(ctx, "create")
+	defer op.end(&err)
+	return r.next.Create(ctx, rule)
+}
+
+// Update implements store.QueryRuleRepository.
+func (r *QueryRuleRepository) Update(ctx context.Context, rule *domain.QueryRule) (err error) {
+	ctx, op := r.begin(ctx, "update")
+	defer op.end(&err)
+	return r.next.Update(ctx, rule)
+}
+
+// Delete implements store.QueryRuleRepository.
+func (r *QueryRuleRepository) Delete(ctx context.Context, id string) (err error) {
+	ctx, op := r.begin(ctx, "delete")
+	defer op.end(&err)
+	return r.next.Delete(ctx, id)
+}
+
+// GetByID implements store.QueryRuleRepository.
+func (r *QueryRuleRepository) GetByID(ctx context.Context, id string) (rule *domain.QueryRule, err error) {
+	ctx, op := r.begin(ctx, "get_by_id")
+	defer op.end(&err)
+	return r.next.GetByID(ctx, id)
+}
+
+// List implements store.QueryRuleRepository.
+func (r *QueryRuleRepository) List(ctx context.Context) (rules []*domain.QueryRule, err error) {
+	ctx, op := r.begin(ctx, "list")
+	defer op.end(&err)
+	return r.next.List(ctx)
+}
+
+// RuleTemplateRepository wraps a store.RuleTemplateRepository with operation timeouts and storage metrics.
+type RuleTemplateRepository struct {
+	observer
+	next store.RuleTemplateRepository
+}
+
+// NewRuleTemplateRepository wraps next with operation timeouts and storage metrics.
+func NewRuleTemplateRepository(next store.RuleTemplateRepository, cfg config.StoreOperationsConfig, logger *slog.Logger) *RuleTemplateRepository {
+	return &RuleTemplateRepository{observer: newObserver(storeRuleTemplates, cfg, logger), next: next}
+}
+
+// Create implements store.RuleTemplateRepository.
+func (r *RuleTemplateRepository) Create(ctx context.Context, template *domain.RuleTemplate) (err error) {
+	ctx, op := r.begin(ctx, "create")
+	defer op.end(&err)
+	return r.next.Create(ctx, template)
+}
+
+// Update implements store.RuleTemplateRepository.
+func (r *RuleTemplateRepository) Update(ctx context.Context, template *domain.RuleTemplate) (err error) {
+	ctx, op := r.begin(ctx, "update")
+	defer op.end(&err)
+	return r.next.Update(ctx, template)
+}
+
+// Delete implements store.RuleTemplateRepository.
+func (r *RuleTemplateRepository) Delete(ctx context.Context, id string) (err error) {
+	ctx, op := r.begin(ctx, "delete")
+	defer op.end(&err)
+	return r.next.Delete(ctx, id)
+}
+
+// GetByID implements store.RuleTemplateRepository.
+func (r *RuleTemplateRepository) GetByID(ctx context.Context, id string) (template *domain.RuleTemplate, err error) {
+	ctx, op := r.begin(ctx, "get_by_id")
+	defer op.end(&err)
+	return r.next.GetByID(ctx, id)
+}
+
+// List implements store.RuleTemplateRepository.
+func (r *RuleTemplateRepository) List(ctx context.Context) (templates []*domain.RuleTemplate, err error) {
+	ctx, op := r.begin(ctx, "list")
+	defer op.end(&err)
+	return r.next.List(ctx)
+}
+
+// RuleExecutionRepository wraps a store.RuleExecutionRepository with operation timeouts and storage metrics.
+type RuleExecutionRepository struct {
+	observer
+	next store.RuleExecutionRepository
+}
+
+// NewRuleExecutionRepository wraps next with operation timeouts and storage metrics.
+func NewRuleExecutionRepository(next store.RuleExecutionRepository, cfg config.StoreOperationsConfig, logger *slog.Logger) *RuleExecutionRepository {
+	return &RuleExecutionRepository{observer: newObserver(storeRuleExecutions, cfg, logger), next: next}
+}
+
+// Record implements store.RuleExecutionRepository.
+func (r *RuleExecutionRepository) Record(ctx context.Context, execution *domain.RuleExecution, keep int) (err error) {
+	ctx, op := r.begin(ctx, "record")
+	defer op.end(&err)
+	return r.next.Record(ctx, execution, keep)
+}
+
+// ListByRule implements store.RuleExecutionRepository.
+func (r *RuleExecutionRepository) ListByRule(ctx context.Context, ruleID string, limit int) (executions []*domain.RuleExecution, err error) {
+	ctx, op := r.begin(ctx, "list_by_rule")
+	defer op.end(&err)
+	return r.next.ListByRule(ctx, ruleID, limit)
+}
+
+// DeleteByRule implements store.RuleExecutionRepository.
+func (r *RuleExecutionRepository) DeleteByRule(ctx context.Context, ruleID string) (err error) {
+	ctx, op := r.begin(ctx, "delete_by_rule")
+	defer op.end(&err)
+	return r.next.DeleteByRule(ctx, ruleID)
+}
+
+// RunnerRegistry wraps a store.RunnerRegistry with operation timeouts and storage metrics.
+type RunnerRegistry struct {
+	observer
+	next store.RunnerRegistry
+}
+
+// NewRunnerRegistry wraps next with operation timeouts and storage metrics.
+func NewRunnerRegistry(next store.RunnerRegistry, cfg config.StoreOperationsConfig, logger *slog.Logger) *RunnerRegistry {
+	return &RunnerRegistry{observer: newObserver(storeRuleRunners, cfg, logger), next: next}
+}
+
+// Heartbeat implements store.RunnerRegistry.
+func (r *RunnerRegistry) Heartbeat(ctx context.Context, instanceID string, now time.Time, timeout time.Duration) (instances []string, err error) {
+	ctx, op := r.begin(ctx, "heartbeat")
+	defer op.end(&err)
+	return r.next.Heartbeat(ctx, instanceID, now, timeout)
+}
+
+// Leave implements store.RunnerRegistry.
+func (r *RunnerRegistry) Leave(ctx context.Context, instanceID string) (err error) {
+	ctx, op := r.begin(ctx, "leave")
+	defer op.end(&err)
+	return r.next.Leave(ctx, instanceID)
+}
+
+// NotificationLogRepository wraps a store.NotificationLogRepository with operation timeouts and storage metrics.
+type NotificationLogRepository struct {
+	observer
+	next store.NotificationLogRepository
+}
+
+// NewNotificationLogRepository wraps next with operation timeouts and storage metrics.
+func NewNotificationLogRepository(next store.NotificationLogRepository, cfg config.StoreOperationsConfig, logger *slog.Logger) *NotificationLogRepository {
+	return &NotificationLogRepository{observer: newObserver(storeNotificationLogs, cfg, logger), next: next}
+}
+
+// Record implements store.NotificationLogRepository.
+func (r *NotificationLogRepository) Record(ctx context.Context, record *domain.NotificationRecord) (err error) {
+	ctx, op := r.begin(ctx, "record")
+	defer op.end(&err)
+	return r.next.Record(ctx, record)
+}
+
+// ListByDedupKey implements store.NotificationLogRepository.
+func (r *NotificationLogRepository) ListByDedupKey(ctx context.Context, dedupKey string) (records []*domain.NotificationRecord, err error) {
+	ctx, op := r.begin(ctx, "list_by_dedup_key")
+	defer op.end(&err)
+	return r.next.ListByDedupKey(ctx, dedupKey)
+}
+
+// DeleteByEventManager implements store.NotificationLogRepository.
+func (r *NotificationLogRepository) DeleteByEventManager(ctx context.Context, eventManagerID string) (count int, err error) {
+	ctx, op := r.begin(ctx, "delete_by_event_manager")
+	defer op.end(&err)
+	return r.next.DeleteByEventManager(ctx, eventManagerID)
+}
+
+// RemediationLogRepository wraps a store.RemediationLogRepository with operation timeouts and storage metrics.
+type RemediationLogRepository struct {
+	observer
+	next store.RemediationLogRepository
+}
+
+// NewRemediationLogRepository wraps next with operation timeouts and storage metrics.
+func NewRemediationLogRepository(next store.RemediationLogRepository, cfg config.StoreOperationsConfig, logger *slog.Logger) *RemediationLogRepository {
+	return &RemediationLogRepository{observer: newObserver(storeRemediationLogs, cfg, logger), next: next}
+}
+
+// Record implements store.RemediationLogRepository.
+func (r *RemediationLogRepository) Record(ctx context.Context, record *domain.RemediationRecord) (err error) {
+	ctx, op := r.begin(ctx, "record")
+	defer op.end(&err)
+	return r.next.Record(ctx, record)
+}
+
+// ListByDedupKey implements store.RemediationLogRepository.
+func (r *RemediationLogRepository) ListByDedupKey(ctx context.Context, dedupKey string) (records []*domain.RemediationRecord, err error) {
+	ctx, op := r.begin(ctx, "list_by_dedup_key")
+	defer op.end(&err)
+	return r.next.ListByDedupKey(ctx, dedupKey)
+}
+
+// DeleteByEventManager implements store.RemediationLogRepository.
+func (r *RemediationLogRepository) DeleteByEventManager(ctx context.Context, eventManagerID string) (count int, err error) {
+	ctx, op := r.begin(ctx, "delete_by_event_manager")
+	defer op.end(&err)
+	return r.next.DeleteByEventManager(ctx, eventManagerID)
+}
+
+// AlertFeedbackRepository wraps a store.AlertFeedbackRepository with operation timeouts and storage metrics.
+type AlertFeedbackRepository struct {
+	observer
+	next store.AlertFeedbackRepository
+}
+
+// NewAlertFeedbackRepository wraps next with operation timeouts and storage metrics.
+func NewAlertFeedbackRepository(next store.AlertFeedbackRepository, cfg config.StoreOperationsConfig, logger *slog.Logger) *AlertFeedbackRepository {
+	return &AlertFeedbackRepository{observer: newObserver(storeAlertFeedback, cfg, logger), next: next}
+}
+
+// Record implements store.AlertFeedbackRepository.
+func (r *AlertFeedbackRepository) Record(ctx context.Context, feedback *domain.AlertFeedback) (err error) {
+	ctx, op := r.begin(ctx, "record")
+	defer op.end(&err)
+	return r.next.Record(ctx, feedback)
+}
+
+// ListByDedupKey implements store.AlertFeedbackRepository.
+func (r *AlertFeedbackRepository) ListByDedupKey(ctx context.Context, dedupKey string) (feedback []*domain.AlertFeedback, err error) {
+	ctx, op := r.begin(ctx, "list_by_dedup_key")
+	defer op.end(&err)
+	return r.next.ListByDedupKey(ctx, dedupKey)
+}
+
+// List implements store.AlertFeedbackRepository.
+func (r *AlertFeedbackRepository) List(ctx context.Context, filter domain.ReportFilter) (feedback []*domain.AlertFeedback, err error) {
+	ctx, op := r.begin(ctx, "list")
+	defer op.end(&err)
+	return r.next.List(ctx, filter)
+}
+
+// DeleteByEventManager implements store.AlertFeedbackRepository.
+func (r *AlertFeedbackRepository) DeleteByEventManager(ctx context.Context, eventManagerID string) (count int, err error) {
+	ctx, op := r.begin(ctx, "delete_by_event_manager")
+	defer op.end(&err)
+	return r.next.DeleteByEventManager(ctx, eventManagerID)
+}
+
+// AuditLogRepository wraps a store.AuditLogRepository with operation timeouts and storage metrics.
+type AuditLogRepository struct {
+	observer
+	next store.AuditLogRepository
+}
+
+// NewAuditLogRepository wraps next with operation timeouts and storage metrics.
+func NewAuditLogRepository(next store.AuditLogRepository, cfg config.StoreOperationsConfig, logger *slog.Logger) *AuditLogRepository {
+	return &AuditLogRepository{observer: newObserver(storeAuditLog, cfg, logger), next: next}
+}
+
+// Record implements store.AuditLogRepository.
+func (r *AuditLogRepository) Record(ctx context.Context, entry *domain.AuditEntry) (err error) {
+	ctx, op := r.begin(ctx, "record")
+	defer op.end(&err)
+	return r.next.Record(ctx, entry)
+}
+
+// List implements store.AuditLogRepository.
+func (r *AuditLogRepository) List(ctx context.Context, limit int) (entries []*domain.AuditEntry, err error) {
+	ctx, op := r.begin(ctx, "list")
+	defer op.end(&err)
+	return r.next.List(ctx, limit)
+}
+
+// Export implements store.AuditLogRepository.
+func (r *AuditLogRepository) Export(ctx context.Context) (entries []*domain.AuditEntry, err error) {
+	ctx, op := r.begin(ctx, "export")
+	defer op.end(&err)
+	return r.next.Export(ctx)
+}
+
+// UsageRepository wraps a store.UsageRepository with operation timeouts and storage metrics.
+type UsageRepository struct {
+	observer
+	next store.UsageRepository
+}
+
+// NewUsageRepository wraps next with operation timeouts and storage metrics.
+func NewUsageRepository(next store.UsageRepository, cfg config.StoreOperationsConfig, logger *slog.Logger) *UsageRepository {
+	return &UsageRepository{observer: newObserver(storeUsage, cfg, logger), next: next}
+}
+
+// Add implements store.UsageRepository.
+func (r *UsageRepository) Add(ctx context.Context, usage *domain.Usage) (err error) {
+	ctx, op := r.begin(ctx, "add")
+	defer op.end(&err)
+	return r.next.Add(ctx, usage)
+}
+
+// List implements store.UsageRepository.
+func (r *UsageRepository) List(ctx context.Context, filter domain.ReportFilter) (usage []*domain.Usage, err error) {
+	ctx, op := r.begin(ctx, "list")
+	defer op.end(&err)
+	return r.next.List(ctx, filter)
+}
+
+// DeleteByEventManager implements store.UsageRepository.
+func (r *UsageRepository) DeleteByEventManager(ctx context.Context, eventManagerID string) (count int, err error) {
+	ctx, op := r.begin(ctx, "delete_by_event_manager")
+	defer op.end(&err)
+	return r.next.DeleteByEventManager(ctx, eventManagerID)
+}
+
+// ReportScheduleRepository wraps a store.ReportScheduleRepository with operation timeouts and storage metrics.
+type ReportScheduleRepository struct {
+	observer
+	next store.ReportScheduleRepository
+}
+
+// NewReportScheduleRepository wraps next with operation timeouts and storage metrics.
+func NewReportScheduleRepository(next store.ReportScheduleRepository, cfg config.StoreOperationsConfig, logger *slog.Logger) *ReportScheduleRepository {
+	return &ReportScheduleRepository{observer: newObserver(storeReportSchedules, cfg, logger), next: next}
+}
+
+// Create implements store.ReportScheduleRepository.
+func (r *ReportScheduleRepository) Create(ctx context.Context, schedule *domain.ReportSchedule) (err error) {
+	ctx, op := r.begin(ctx, "create")
+	defer op.end(&err)
+	return r.next.Create(ctx, schedule)
+}
+
+// Update implements store.ReportScheduleRepository.
+func (r *ReportScheduleRepository) Update(ctx context.Context, schedule *domain.ReportSchedule) (err error) {
+	ctx, op := r.begin(ctx, "update")
+	defer op.end(&err)
+	return r.next.Update(ctx, schedule)
+}
+
+// Delete implements store.ReportScheduleRepository.
+func (r *ReportScheduleRepository) Delete(ctx context.Context, id string) (err error) {
+	ctx, op := r.begin(ctx, "delete")
+	defer op.end(&err)
+	return r.next.Delete(ctx, id)
+}
+
+// GetByID implements store.ReportScheduleRepository.
+func (r *ReportScheduleRepository) GetByID(ctx context.Context, id string) (schedule *domain.ReportSchedule, err error) {
+	ctx, op := r.begin(ctx, "get_by_id")
+	defer op.end(&err)
+	return r.next.GetByID(ctx, id)
+}
+
+// List implements store.ReportScheduleRepository.
+func (r *ReportScheduleRepository) List(ctx context.Context) (schedules []*domain.ReportSchedule, err error) {
+	ctx, op := r.begin(ctx, "list")
+	defer op.end(&err)
+	return r.next.List(ctx)
+}
+
+// MarkSent implements store.ReportScheduleRepository.
+func (r *ReportScheduleRepository) MarkSent(ctx context.Context, id string, previous, to *time.Time) (marked bool, err error) {
+	ctx, op := r.begin(ctx, "mark_sent")
+	defer op.end(&err)
+	return r.next.MarkSent(ctx, id, previous, to)
+}
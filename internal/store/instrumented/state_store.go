@@ -0,0 +1,202 @@
+package instrumented
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// StateStore wraps a store.StateStore with operation timeouts and storage metrics.
+type StateStore struct {
+	observer
+	next store.StateStore
+}
+
+// NewStateStore wraps next with operation timeouts and storage metrics.
+func NewStateStore(next store.StateStore, cfg config.StoreOperationsConfig, logger *slog.Logger) *StateStore {
+	return &StateStore{observer: newObserver(storeState, cfg, logger), next: next}
+}
+
+// GetParent implements store.StateStore.
+func (s *StateStore) GetParent(ctx context.Context, eventManagerID, groupingKey, groupingValue string) (state *store.ParentState, err error) {
+	ctx, op := s.begin(ctx, "get_parent")
+	defer op.end(&err)
+	return s.next.GetParent(ctx, eventManagerID, groupingKey, groupingValue)
+}
+
+// SetParent implements store.StateStore.
+func (s *StateStore) SetParent(ctx context.Context, eventManagerID, groupingKey, groupingValue string, state *store.ParentState, ttl time.Duration) (err error) {
+	ctx, op := s.begin(ctx, "set_parent")
+	defer op.end(&err)
+	return s.next.SetParent(ctx, eventManagerID, groupingKey, groupingValue, state, ttl)
+}
+
+// DeleteParent implements store.StateStore.
+func (s *StateStore) DeleteParent(ctx context.Context, eventManagerID, groupingKey, groupingValue string) (err error) {
+	ctx, op := s.begin(ctx, "delete_parent")
+	defer op.end(&err)
+	return s.next.DeleteParent(ctx, eventManagerID, groupingKey, groupingValue)
+}
+
+// GetAlert implements store.StateStore.
+func (s *StateStore) GetAlert(ctx context.Context, dedupKey string) (state *store.AlertState, err error) {
+	ctx, op := s.begin(ctx, "get_alert")
+	defer op.end(&err)
+	return s.next.GetAlert(ctx, dedupKey)
+}
+
+// SetAlert implements store.StateStore.
+func (s *StateStore) SetAlert(ctx context.Context, state *store.AlertState) (err error) {
+	ctx, op := s.begin(ctx, "set_alert")
+	defer op.end(&err)
+	return s.next.SetAlert(ctx, state)
+}
+
+// DeleteAlert implements store.StateStore.
+func (s *StateStore) DeleteAlert(ctx context.Context, dedupKey string) (err error) {
+	ctx, op := s.begin(ctx, "delete_alert")
+	defer op.end(&err)
+	return s.next.DeleteAlert(ctx, dedupKey)
+}
+
+// AddChild implements store.StateStore.
+func (s *StateStore) AddChild(ctx context.Context, parentDedupKey, childDedupKey string) (err error) {
+	ctx, op := s.begin(ctx, "add_child")
+	defer op.end(&err)
+	return s.next.AddChild(ctx, parentDedupKey, childDedupKey)
+}
+
+// RemoveChild implements store.StateStore.
+func (s *StateStore) RemoveChild(ctx context.Context, parentDedupKey, childDedupKey string) (err error) {
+	ctx, op := s.begin(ctx, "remove_child")
+	defer op.end(&err)
+	return s.next.RemoveChild(ctx, parentDedupKey, childDedupKey)
+}
+
+// GetChildren implements store.StateStore.
+func (s *StateStore) GetChildren(ctx context.Context, parentDedupKey string) (children []string, err error) {
+	ctx, op := s.begin(ctx, "get_children")
+	defer op.end(&err)
+	return s.next.GetChildren(ctx, parentDedupKey)
+}
+
+// GetChildCount implements store.StateStore.
+func (s *StateStore) GetChildCount(ctx context.Context, parentDedupKey string) (count int, err error) {
+	ctx, op := s.begin(ctx, "get_child_count")
+	defer op.end(&err)
+	return s.next.GetChildCount(ctx, parentDedupKey)
+}
+
+// GetActiveChildCount implements store.StateStore.
+func (s *StateStore) GetActiveChildCount(ctx context.Context, parentDedupKey string) (count int, err error) {
+	ctx, op := s.begin(ctx, "get_active_child_count")
+	defer op.end(&err)
+	return s.next.GetActiveChildCount(ctx, parentDedupKey)
+}
+
+// SetPendingResolve implements store.StateStore.
+func (s *StateStore) SetPendingResolve(ctx context.Context, parentDedupKey string, pending *store.PendingResolve) (err error) {
+	ctx, op := s.begin(ctx, "set_pending_resolve")
+	defer op.end(&err)
+	return s.next.SetPendingResolve(ctx, parentDedupKey, pending)
+}
+
+// GetPendingResolve implements store.StateStore.
+func (s *StateStore) GetPendingResolve(ctx context.Context, parentDedupKey string) (pending *store.PendingResolve, err error) {
+	ctx, op := s.begin(ctx, "get_pending_resolve")
+	defer op.end(&err)
+	return s.next.GetPendingResolve(ctx, parentDedupKey)
+}
+
+// DeletePendingResolve implements store.StateStore.
+func (s *StateStore) DeletePendingResolve(ctx context.Context, parentDedupKey string) (err error) {
+	ctx, op := s.begin(ctx, "delete_pending_resolve")
+	defer op.end(&err)
+	return s.next.DeletePendingResolve(ctx, parentDedupKey)
+}
+
+// SetReminder implements store.StateStore.
+func (s *StateStore) SetReminder(ctx context.Context, reminder *store.Reminder) (err error) {
+	ctx, op := s.begin(ctx, "set_reminder")
+	defer op.end(&err)
+	return s.next.SetReminder(ctx, reminder)
+}
+
+// GetDueReminders implements store.StateStore.
+func (s *StateStore) GetDueReminders(ctx context.Context, now time.Time, limit int) (reminders []*store.Reminder, err error) {
+	ctx, op := s.begin(ctx, "get_due_reminders")
+	defer op.end(&err)
+	return s.next.GetDueReminders(ctx, now, limit)
+}
+
+// DeleteReminder implements store.StateStore.
+func (s *StateStore) DeleteReminder(ctx context.Context, dedupKey string) (err error) {
+	ctx, op := s.begin(ctx, "delete_reminder")
+	defer op.end(&err)
+	return s.next.DeleteReminder(ctx, dedupKey)
+}
+
+// MarkNotificationSent implements store.StateStore.
+func (s *StateStore) MarkNotificationSent(ctx context.Context, notificationID string, ttl time.Duration) (marked bool, err error) {
+	ctx, op := s.begin(ctx, "mark_notification_sent")
+	defer op.end(&err)
+	return s.next.MarkNotificationSent(ctx, notificationID, ttl)
+}
+
+// AdjustLiveCount implements store.StateStore.
+func (s *StateStore) AdjustLiveCount(ctx context.Context, eventManagerID, severity string, delta int) (err error) {
+	ctx, op := s.begin(ctx, "adjust_live_count")
+	defer op.end(&err)
+	return s.next.AdjustLiveCount(ctx, eventManagerID, severity, delta)
+}
+
+// GetLiveCounts implements store.StateStore.
+func (s *StateStore) GetLiveCounts(ctx context.Context) (counts []*store.LiveCount, err error) {
+	ctx, op := s.begin(ctx, "get_live_counts")
+	defer op.end(&err)
+	return s.next.GetLiveCounts(ctx)
+}
+
+// SetLiveCounts implements store.StateStore.
+func (s *StateStore) SetLiveCounts(ctx context.Context, counts []*store.LiveCount) (err error) {
+	ctx, op := s.begin(ctx, "set_live_counts")
+	defer op.end(&err)
+	return s.next.SetLiveCounts(ctx, counts)
+}
+
+// RecordGrouping implements store.StateStore.
+func (s *StateStore) RecordGrouping(ctx context.Context, groupingRuleID, day string, child bool) (err error) {
+	ctx, op := s.begin(ctx, "record_grouping")
+	defer op.end(&err)
+	return s.next.RecordGrouping(ctx, groupingRuleID, day, child)
+}
+
+// GetGroupingStats implements store.StateStore.
+func (s *StateStore) GetGroupingStats(ctx context.Context, groupingRuleID string, days []string) (stats []*store.GroupingStats, err error) {
+	ctx, op := s.begin(ctx, "get_grouping_stats")
+	defer op.end(&err)
+	return s.next.GetGroupingStats(ctx, groupingRuleID, days)
+}
+
+// SetEventStatus implements store.StateStore.
+func (s *StateStore) SetEventStatus(ctx context.Context, status *domain.EventStatus, ttl time.Duration) (err error) {
+	ctx, op := s.begin(ctx, "set_event_status")
+	defer op.end(&err)
+	return s.next.SetEventStatus(ctx, status, ttl)
+}
+
+// GetEventStatus implements store.StateStore.
+func (s *StateStore) GetEventStatus(ctx context.Context, eventID string) (status *domain.EventStatus, err error) {
+	ctx, op := s.begin(ctx, "get_event_status")
+	defer op.end(&err)
+	return s.next.GetEventStatus(ctx, eventID)
+}
+
+// Close closes the wrapped store. Close is not recorded.
+func (s *StateStore) Close() error {
+	return s.next.Close()
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"argus-go/internal/domain"
+)
+
+// AlertFeedbackRepository is an in-memory implementation of store.AlertFeedbackRepository.
+type AlertFeedbackRepository struct {
+	mu sync.RWMutex
+
+	// feedback stores the feedback on each alert by dedup key, oldest first
+	feedback map[string][]*domain.AlertFeedback
+}
+
+// NewAlertFeedbackRepository creates a new in-memory alert feedback repository.
+func NewAlertFeedbackRepository() *AlertFeedbackRepository {
+	return &AlertFeedbackRepository{
+		feedback: make(map[string][]*domain.AlertFeedback),
+	}
+}
+
+// Record appends feedback on an alert.
+func (r *AlertFeedbackRepository) Record(ctx context.Context, feedback *domain.AlertFeedback) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Store a copy
+	feedbackCopy := *feedback
+	r.feedback[feedback.DedupKey] = append(r.feedback[feedback.DedupKey], &feedbackCopy)
+	return nil
+}
+
+// ListByDedupKey retrieves the feedback on an alert, oldest first.
+func (r *AlertFeedbackRepository) ListByDedupKey(ctx context.Context, dedupKey string) ([]*domain.AlertFeedback, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := make([]*domain.AlertFeedback, 0, len(r.feedback[dedupKey]))
+	for _, feedback := range r.feedback[dedupKey] {
+		feedbackCopy := *feedback
+		results = append(results, &feedbackCopy)
+	}
+
+	return results, nil
+}
+
+// List retrieves the feedback given within the range of the filter, of its
+// event manager if set, oldest first.
+func (r *AlertFeedbackRepository) List(ctx context.Context, filter domain.ReportFilter) ([]*domain.AlertFeedback, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := []*domain.AlertFeedback{}
+	for _, feedback := range r.feedback {
+		for _, f := range feedback {
+			if filter.EventManagerID != "" && f.EventManagerID != filter.EventManagerID {
+				continue
+			}
+			if f.CreatedAt.Before(filter.From) || !f.CreatedAt.Before(filter.To) {
+				continue
+			}
+			feedbackCopy := *f
+			results = append(results, &feedbackCopy)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].CreatedAt.Before(results[j].CreatedAt)
+	})
+	return results, nil
+}
+
+// DeleteByEventManager removes the feedback on the alerts of an event manager.
+func (r *AlertFeedbackRepository) DeleteByEventManager(ctx context.Context, eventManagerID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for dedupKey, feedback := range r.feedback {
+		kept := feedback[:0]
+		for _, f := range feedback {
+			if f.EventManagerID == eventManagerID {
+				deleted++
+				continue
+			}
+			kept = append(kept, f)
+		}
+		if len(kept) == 0 {
+			delete(r.feedback, dedupKey)
+		} else {
+			r.feedback[dedupKey] = kept
+		}
+	}
+
+	return deleted, nil
+}
@@ -2,8 +2,13 @@ package memory
 
 import (
 	"context"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	"argus-go/internal/clock"
 	"argus-go/internal/domain"
 )
 
@@ -20,14 +25,27 @@ type AlertRepository struct {
 
 	// byParent provides fast lookup of children by parent dedup key
 	byParent map[string]map[string]*domain.Alert
+
+	// history stores every revision of each alert by dedup key, oldest first
+	history map[string][]*domain.AlertRevision
+
+	clock clock.Clock
 }
 
 // NewAlertRepository creates a new in-memory alert repository.
 func NewAlertRepository() *AlertRepository {
+	return NewAlertRepositoryWithClock(clock.Real{})
+}
+
+// NewAlertRepositoryWithClock creates a new in-memory alert repository that
+// stamps updates and revisions by the given clock, so tests control them.
+func NewAlertRepositoryWithClock(clk clock.Clock) *AlertRepository {
 	return &AlertRepository{
 		alerts:     make(map[string]*domain.Alert),
 		byDedupKey: make(map[string]*domain.Alert),
 		byParent:   make(map[string]map[string]*domain.Alert),
+		history:    make(map[string][]*domain.AlertRevision),
+		clock:      clk,
 	}
 }
 
@@ -36,6 +54,10 @@ func (r *AlertRepository) Create(ctx context.Context, alert *domain.Alert) error
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if _, ok := r.byDedupKey[alert.DedupKey]; ok {
+		return domain.ErrAlertAlreadyExists
+	}
+
 	// Store a copy to prevent external modification
 	alertCopy := *alert
 	r.alerts[alert.ID] = &alertCopy
@@ -49,6 +71,7 @@ func (r *AlertRepository) Create(ctx context.Context, alert *domain.Alert) error
 		r.byParent[alert.ParentDedupKey][alert.DedupKey] = &alertCopy
 	}
 
+	r.recordRevision(&alertCopy)
 	return nil
 }
 
@@ -62,6 +85,49 @@ func (r *AlertRepository) Update(ctx context.Context, alert *domain.Alert) error
 		return domain.ErrAlertNotFound
 	}
 
+	r.update(existing, alert)
+	return nil
+}
+
+// UpdateIfUnmodified modifies an existing alert unless it was updated since
+// updatedAt.
+func (r *AlertRepository) UpdateIfUnmodified(ctx context.Context, alert *domain.Alert, updatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.alerts[alert.ID]
+	if !exists {
+		return domain.ErrAlertNotFound
+	}
+	if !existing.UpdatedAt.Equal(updatedAt) {
+		return domain.ErrAlertModified
+	}
+
+	r.update(existing, alert)
+	return nil
+}
+
+// UpdateAll modifies several existing alerts atomically.
+func (r *AlertRepository) UpdateAll(ctx context.Context, alerts []*domain.Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Check every alert exists before changing any
+	for _, alert := range alerts {
+		if _, exists := r.alerts[alert.ID]; !exists {
+			return domain.ErrAlertNotFound
+		}
+	}
+
+	for _, alert := range alerts {
+		r.update(r.alerts[alert.ID], alert)
+	}
+	return nil
+}
+
+// update replaces an existing alert and records the revision.
+// The caller must hold the write lock.
+func (r *AlertRepository) update(existing, alert *domain.Alert) {
 	// Store a copy
 	alertCopy := *alert
 	r.alerts[alert.ID] = &alertCopy
@@ -80,7 +146,7 @@ func (r *AlertRepository) Update(ctx context.Context, alert *domain.Alert) error
 		delete(r.byParent[existing.ParentDedupKey], existing.DedupKey)
 	}
 
-	return nil
+	r.recordRevision(&alertCopy)
 }
 
 // GetByID retrieves an alert by its database ID.
@@ -121,14 +187,7 @@ func (r *AlertRepository) List(ctx context.Context, filter domain.AlertFilter) (
 	var results []*domain.Alert
 
 	for _, alert := range r.alerts {
-		// Apply filters
-		if filter.EventManagerID != "" && alert.EventManagerID != filter.EventManagerID {
-			continue
-		}
-		if filter.Status != "" && alert.Status != filter.Status {
-			continue
-		}
-		if filter.Type != "" && alert.Type != filter.Type {
+		if !matchesFilter(alert, filter) {
 			continue
 		}
 
@@ -137,6 +196,9 @@ func (r *AlertRepository) List(ctx context.Context, filter domain.AlertFilter) (
 		results = append(results, &alertCopy)
 	}
 
+	// Sorted like the PostgreSQL repository, so pages are stable
+	slices.SortStableFunc(results, filter.Sort.Compare)
+
 	// Apply offset and limit
 	start := filter.Offset
 	if start > len(results) {
@@ -151,6 +213,68 @@ func (r *AlertRepository) List(ctx context.Context, filter domain.AlertFilter) (
 	return results[start:end], nil
 }
 
+// Count returns the number of alerts matching the filter criteria.
+func (r *AlertRepository) Count(ctx context.Context, filter domain.AlertFilter) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for _, alert := range r.alerts {
+		if matchesFilter(alert, filter) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// matchesFilter returns true if the alert matches the filter criteria.
+func matchesFilter(alert *domain.Alert, filter domain.AlertFilter) bool {
+	if filter.EventManagerID != "" && alert.EventManagerID != filter.EventManagerID &&
+		!(filter.IncludeSubscribed && alert.HasEventManager(filter.EventManagerID)) {
+		return false
+	}
+	if filter.ParentDedupKey != "" && alert.ParentDedupKey != filter.ParentDedupKey {
+		return false
+	}
+	if filter.Status != "" {
+		status, _ := filter.Muting.Status(alert)
+		if status != filter.Status {
+			return false
+		}
+	}
+	if filter.Type != "" && alert.Type != filter.Type {
+		return false
+	}
+	if filter.ResolveRequested && !alert.ResolveRequested {
+		return false
+	}
+	if filter.Severity != "" && alert.Severity != filter.Severity {
+		return false
+	}
+	if filter.Service != "" && alert.Service != filter.Service {
+		return false
+	}
+	if filter.Component != "" && !alert.HasComponent(filter.Component) {
+		return false
+	}
+	if filter.Query != "" && !matchesQuery(alert.Summary, filter.Query) {
+		return false
+	}
+	return true
+}
+
+// matchesQuery returns true if the summary contains every word of the query,
+// ignoring case. PostgreSQL matches stemmed words instead.
+func matchesQuery(summary, query string) bool {
+	summary = strings.ToLower(summary)
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(summary, word) {
+			return false
+		}
+	}
+	return true
+}
+
 // GetChildrenByParent retrieves all child alerts for a given parent dedup key.
 func (r *AlertRepository) GetChildrenByParent(ctx context.Context, parentDedupKey string) ([]*domain.Alert, error) {
 	r.mu.RLock()
@@ -190,6 +314,195 @@ func (r *AlertRepository) CountActiveChildren(ctx context.Context, parentDedupKe
 	return count, nil
 }
 
+// CountActive returns the number of active alerts per event manager, type,
+// severity and class.
+func (r *AlertRepository) CountActive(ctx context.Context) ([]*domain.ActiveAlertCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	type countKey struct {
+		eventManagerID string
+		alertType      domain.AlertType
+		severity       domain.Severity
+		class          string
+	}
+	counts := make(map[countKey]int)
+	for _, alert := range r.alerts {
+		if alert.IsActive() {
+			counts[countKey{alert.EventManagerID, alert.Type, alert.Severity, alert.Class}]++
+		}
+	}
+
+	results := make([]*domain.ActiveAlertCount, 0, len(counts))
+	for key, count := range counts {
+		results = append(results, &domain.ActiveAlertCount{
+			EventManagerID: key.eventManagerID,
+			Type:           key.alertType,
+			Severity:       key.severity,
+			Class:          key.class,
+			Count:          count,
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].EventManagerID != results[j].EventManagerID {
+			return results[i].EventManagerID < results[j].EventManagerID
+		}
+		if results[i].Type != results[j].Type {
+			return results[i].Type < results[j].Type
+		}
+		if results[i].Severity != results[j].Severity {
+			return results[i].Severity < results[j].Severity
+		}
+		return results[i].Class < results[j].Class
+	})
+
+	return results, nil
+}
+
+// IncrementTriggerCount records an additional trigger event for an existing alert.
+func (r *AlertRepository) IncrementTriggerCount(ctx context.Context, dedupKey string) error {
+	return r.addTriggers(dedupKey, 1, 0)
+}
+
+// AddSampledEvents records trigger events dropped by sampling for an existing
+// alert, counting them as triggers too.
+func (r *AlertRepository) AddSampledEvents(ctx context.Context, dedupKey string, count int) error {
+	return r.addTriggers(dedupKey, count, count)
+}
+
+// MergeTags merges tags onto the tags of an existing alert.
+func (r *AlertRepository) MergeTags(ctx context.Context, dedupKey string, tags map[string]string, replace bool) error {
+	return r.modify(dedupKey, func(alert *domain.Alert) {
+		alert.Tags = domain.MergeTags(alert.Tags, tags, replace)
+	})
+}
+
+// UpdateLinks removes links from an existing alert, then adds links.
+func (r *AlertRepository) UpdateLinks(ctx context.Context, dedupKey string, add []domain.AlertLink, remove []string) error {
+	return r.modify(dedupKey, func(alert *domain.Alert) {
+		alert.Links = domain.MergeLinks(domain.RemoveLinks(alert.Links, remove), add)
+	})
+}
+
+// addTriggers adds to the trigger and sampled event counts of an alert.
+func (r *AlertRepository) addTriggers(dedupKey string, triggers, sampled int) error {
+	return r.modify(dedupKey, func(alert *domain.Alert) {
+		alert.TriggerCount += triggers
+		alert.SampledEvents += sampled
+	})
+}
+
+// modify applies fn to a copy of an alert and stores the copy.
+func (r *AlertRepository) modify(dedupKey string, fn func(alert *domain.Alert)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alert, exists := r.byDedupKey[dedupKey]
+	if !exists {
+		return domain.ErrAlertNotFound
+	}
+
+	// Replace the stored copy so all indexes observe the change
+	alertCopy := *alert
+	fn(&alertCopy)
+	alertCopy.UpdatedAt = r.clock.Now().UTC()
+	r.alerts[alertCopy.ID] = &alertCopy
+	r.byDedupKey[alertCopy.DedupKey] = &alertCopy
+	if alertCopy.IsChild() && alertCopy.ParentDedupKey != "" && r.byParent[alertCopy.ParentDedupKey] != nil {
+		r.byParent[alertCopy.ParentDedupKey][alertCopy.DedupKey] = &alertCopy
+	}
+
+	r.recordRevision(&alertCopy)
+	return nil
+}
+
+// History returns every revision of an alert, oldest first.
+func (r *AlertRepository) History(ctx context.Context, dedupKey string) ([]*domain.AlertRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revisions := r.history[dedupKey]
+	results := make([]*domain.AlertRevision, 0, len(revisions))
+	for _, rev := range revisions {
+		revCopy := *rev
+		results = append(results, &revCopy)
+	}
+
+	return results, nil
+}
+
+// RevisionAt returns the revision of an alert that was current at the given time.
+func (r *AlertRepository) RevisionAt(ctx context.Context, dedupKey string, at time.Time) (*domain.AlertRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Revisions are recorded in time order, so the last one not after at wins
+	revisions := r.history[dedupKey]
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if !revisions[i].RecordedAt.After(at) {
+			revCopy := *revisions[i]
+			return &revCopy, nil
+		}
+	}
+
+	return nil, domain.ErrAlertNotFound
+}
+
+// PurgeByEventManager permanently removes all alerts of an event manager,
+// including their history.
+func (r *AlertRepository) PurgeByEventManager(ctx context.Context, eventManagerID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	removed := 0
+	for id, alert := range r.alerts {
+		if alert.EventManagerID != eventManagerID {
+			continue
+		}
+		delete(r.alerts, id)
+		delete(r.byDedupKey, alert.DedupKey)
+		delete(r.byParent, alert.DedupKey)
+		if alert.ParentDedupKey != "" {
+			delete(r.byParent[alert.ParentDedupKey], alert.DedupKey)
+		}
+		delete(r.history, alert.DedupKey)
+		removed++
+	}
+
+	return removed, nil
+}
+
+// Delete permanently removes an alert, including its history.
+func (r *AlertRepository) Delete(ctx context.Context, dedupKey string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alert, ok := r.byDedupKey[dedupKey]
+	if !ok {
+		return domain.ErrAlertNotFound
+	}
+	delete(r.alerts, alert.ID)
+	delete(r.byDedupKey, dedupKey)
+	delete(r.byParent, dedupKey)
+	if alert.ParentDedupKey != "" {
+		delete(r.byParent[alert.ParentDedupKey], dedupKey)
+	}
+	delete(r.history, dedupKey)
+
+	return nil
+}
+
+// recordRevision appends a snapshot of the stored alert to its history.
+// The caller must hold the write lock.
+func (r *AlertRepository) recordRevision(alert *domain.Alert) {
+	revisions := r.history[alert.DedupKey]
+	r.history[alert.DedupKey] = append(revisions, &domain.AlertRevision{
+		Revision:   len(revisions) + 1,
+		RecordedAt: r.clock.Now().UTC(),
+		Alert:      *alert,
+	})
+}
+
 // Clear removes all data from the repository. Useful for test cleanup.
 func (r *AlertRepository) Clear() {
 	r.mu.Lock()
@@ -198,4 +511,5 @@ func (r *AlertRepository) Clear() {
 	r.alerts = make(map[string]*domain.Alert)
 	r.byDedupKey = make(map[string]*domain.Alert)
 	r.byParent = make(map[string]map[string]*domain.Alert)
+	r.history = make(map[string][]*domain.AlertRevision)
 }
@@ -0,0 +1,322 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
+)
+
+func TestAlertRepository_History(t *testing.T) {
+	repo := NewAlertRepository()
+	ctx := context.Background()
+
+	alert := &domain.Alert{ID: "1", DedupKey: "a", Status: domain.AlertStatusActive, TriggerCount: 1}
+	if err := repo.Create(ctx, alert); err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	if err := repo.IncrementTriggerCount(ctx, "a"); err != nil {
+		t.Fatalf("IncrementTriggerCount error: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	betweenUpdates := time.Now().UTC()
+	time.Sleep(time.Millisecond)
+
+	alert.TriggerCount = 2
+	alert.Resolve(domain.Resolution{ResolvedBy: domain.ResolvedByEvent}, time.Now().UTC())
+	if err := repo.Update(ctx, alert); err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+
+	history, err := repo.History(ctx, "a")
+	if err != nil {
+		t.Fatalf("History error: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("History returned %d revisions, want 3", len(history))
+	}
+	for i, rev := range history {
+		if rev.Revision != i+1 {
+			t.Errorf("revision %d numbered %d", i, rev.Revision)
+		}
+	}
+	if history[1].Alert.TriggerCount != 2 || history[1].Alert.Status != domain.AlertStatusActive {
+		t.Errorf("revision 2 = %+v, want active with 2 triggers", history[1].Alert)
+	}
+	if history[2].Alert.Status != domain.AlertStatusResolved {
+		t.Errorf("revision 3 status = %s, want resolved", history[2].Alert.Status)
+	}
+
+	// Point-in-time lookups
+	rev, err := repo.RevisionAt(ctx, "a", betweenUpdates)
+	if err != nil {
+		t.Fatalf("RevisionAt error: %v", err)
+	}
+	if rev.Revision != 2 {
+		t.Errorf("RevisionAt(between updates) = revision %d, want 2", rev.Revision)
+	}
+	if _, err := repo.RevisionAt(ctx, "a", history[0].RecordedAt.Add(-time.Second)); !errors.Is(err, domain.ErrAlertNotFound) {
+		t.Errorf("RevisionAt(before creation) error = %v, want ErrAlertNotFound", err)
+	}
+
+	if history, _ := repo.History(ctx, "unknown"); len(history) != 0 {
+		t.Errorf("History(unknown) = %d revisions, want 0", len(history))
+	}
+}
+
+func TestAlertRepository_CreateDuplicate(t *testing.T) {
+	repo := NewAlertRepository()
+	ctx := context.Background()
+
+	created := time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)
+	if err := repo.Create(ctx, &domain.Alert{ID: "1", DedupKey: "a", CreatedAt: created}); err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	duplicate := &domain.Alert{ID: "2", DedupKey: "a", CreatedAt: created.AddDate(0, 1, 0)}
+	if err := repo.Create(ctx, duplicate); !errors.Is(err, domain.ErrAlertAlreadyExists) {
+		t.Fatalf("Create(duplicate) error = %v, want %v", err, domain.ErrAlertAlreadyExists)
+	}
+	if got, _ := repo.GetByDedupKey(ctx, "a"); got.ID != "1" {
+		t.Errorf("GetByDedupKey() = alert %s, want 1", got.ID)
+	}
+}
+
+func TestAlertRepository_UpdateAll(t *testing.T) {
+	repo := NewAlertRepository()
+	ctx := context.Background()
+
+	a := &domain.Alert{ID: "1", DedupKey: "a", Status: domain.AlertStatusActive}
+	b := &domain.Alert{ID: "2", DedupKey: "b", Status: domain.AlertStatusActive}
+	_ = repo.Create(ctx, a)
+	_ = repo.Create(ctx, b)
+
+	// A missing alert fails the whole update
+	a.Resolve(domain.Resolution{ResolvedBy: domain.ResolvedByAPI}, time.Now().UTC())
+	missing := &domain.Alert{ID: "3", DedupKey: "c"}
+	if err := repo.UpdateAll(ctx, []*domain.Alert{a, missing}); !errors.Is(err, domain.ErrAlertNotFound) {
+		t.Fatalf("UpdateAll error = %v, want %v", err, domain.ErrAlertNotFound)
+	}
+	if got, _ := repo.GetByDedupKey(ctx, "a"); got.IsResolved() {
+		t.Error("alert a updated although the update failed")
+	}
+
+	b.Resolve(domain.Resolution{ResolvedBy: domain.ResolvedByAPI}, time.Now().UTC())
+	if err := repo.UpdateAll(ctx, []*domain.Alert{a, b}); err != nil {
+		t.Fatalf("UpdateAll error: %v", err)
+	}
+	for _, dedupKey := range []string{"a", "b"} {
+		if got, _ := repo.GetByDedupKey(ctx, dedupKey); !got.IsResolved() {
+			t.Errorf("alert %s status = %v, want resolved", dedupKey, got.Status)
+		}
+	}
+}
+
+func TestAlertRepository_CountActive(t *testing.T) {
+	repo := NewAlertRepository()
+	ctx := context.Background()
+
+	alerts := []*domain.Alert{
+		{ID: "1", DedupKey: "a", EventManagerID: "em-1", Type: domain.AlertTypeParent, Status: domain.AlertStatusActive},
+		{ID: "2", DedupKey: "b", EventManagerID: "em-1", Type: domain.AlertTypeChild, Status: domain.AlertStatusActive, ParentDedupKey: "a"},
+		{ID: "3", DedupKey: "c", EventManagerID: "em-1", Type: domain.AlertTypeChild, Status: domain.AlertStatusActive, ParentDedupKey: "a"},
+		{ID: "5", DedupKey: "e", EventManagerID: "em-1", Type: domain.AlertTypeChild, Status: domain.AlertStatusActive, ParentDedupKey: "a", Severity: domain.SeverityHigh, Class: "db"},
+		{ID: "4", DedupKey: "d", EventManagerID: "em-2", Type: domain.AlertTypeParent, Status: domain.AlertStatusResolved},
+	}
+	for _, alert := range alerts {
+		if err := repo.Create(ctx, alert); err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+	}
+
+	counts, err := repo.CountActive(ctx)
+	if err != nil {
+		t.Fatalf("CountActive error: %v", err)
+	}
+	want := []domain.ActiveAlertCount{
+		{EventManagerID: "em-1", Type: domain.AlertTypeChild, Count: 2},
+		{EventManagerID: "em-1", Type: domain.AlertTypeChild, Severity: domain.SeverityHigh, Class: "db", Count: 1},
+		{EventManagerID: "em-1", Type: domain.AlertTypeParent, Count: 1},
+	}
+	if len(counts) != len(want) {
+		t.Fatalf("CountActive returned %d counts, want %d", len(counts), len(want))
+	}
+	for i := range want {
+		if *counts[i] != want[i] {
+			t.Errorf("count %d = %+v, want %+v", i, *counts[i], want[i])
+		}
+	}
+}
+
+func TestAlertRepository_ListQuery(t *testing.T) {
+	repo := NewAlertRepository()
+	ctx := context.Background()
+
+	summaries := map[string]string{
+		"a": "Disk full on db-1",
+		"b": "Full DISK on web-2",
+		"c": "Disk latency high",
+	}
+	for dedupKey, summary := range summaries {
+		if err := repo.Create(ctx, &domain.Alert{ID: dedupKey, DedupKey: dedupKey, Summary: summary}); err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"disk full", 2},
+		{"disk", 3},
+		{"  latency  ", 1},
+		{"memory", 0},
+	}
+	for _, tt := range tests {
+		alerts, err := repo.List(ctx, domain.AlertFilter{Query: tt.query})
+		if err != nil {
+			t.Fatalf("List error: %v", err)
+		}
+		if len(alerts) != tt.want {
+			t.Errorf("List(q=%q) returned %d alerts, want %d", tt.query, len(alerts), tt.want)
+		}
+	}
+}
+
+func TestAlertRepository_ListSortAndCount(t *testing.T) {
+	repo := NewAlertRepository()
+	ctx := context.Background()
+
+	created := time.Date(2026, 10, 15, 14, 30, 0, 0, time.UTC)
+	severities := []domain.Severity{domain.SeverityLow, domain.SeverityHigh, domain.SeverityMedium, domain.SeverityHigh}
+	childCounts := []int{2, 0, 5, 2}
+	for i, severity := range severities {
+		dedupKey := fmt.Sprintf("child-%d", i)
+		alert := &domain.Alert{ID: dedupKey, DedupKey: dedupKey, ParentDedupKey: "parent", Type: domain.AlertTypeChild,
+			Severity: severity, ChildCount: childCounts[i], CreatedAt: created.Add(time.Duration(i) * time.Minute),
+			UpdatedAt: created.Add(time.Hour - time.Duration(i)*time.Minute)}
+		if err := repo.Create(ctx, alert); err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		sort domain.AlertSort
+		want []string
+	}{
+		{"", []string{"child-3", "child-2", "child-1", "child-0"}},
+		{domain.AlertSortOldest, []string{"child-0", "child-1", "child-2", "child-3"}},
+		{domain.AlertSortMostSevere, []string{"child-3", "child-1", "child-2", "child-0"}},
+		{domain.AlertSortLeastSevere, []string{"child-0", "child-2", "child-3", "child-1"}},
+		{domain.AlertSortRecentlyUpdated, []string{"child-0", "child-1", "child-2", "child-3"}},
+		{domain.AlertSortLeastRecentlyUpdated, []string{"child-3", "child-2", "child-1", "child-0"}},
+		{domain.AlertSortMostChildren, []string{"child-2", "child-3", "child-0", "child-1"}},
+		{domain.AlertSortFewestChildren, []string{"child-1", "child-3", "child-0", "child-2"}},
+	}
+	for _, tt := range tests {
+		alerts, err := repo.List(ctx, domain.AlertFilter{ParentDedupKey: "parent", Sort: tt.sort})
+		if err != nil {
+			t.Fatalf("List error: %v", err)
+		}
+		var got []string
+		for _, alert := range alerts {
+			got = append(got, alert.DedupKey)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("List(sort=%q) = %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+
+	filter := domain.AlertFilter{ParentDedupKey: "parent", Severity: domain.SeverityHigh, Limit: 1}
+	if alerts, _ := repo.List(ctx, filter); len(alerts) != 1 {
+		t.Errorf("List(severity=high, limit=1) = %d alerts, want 1", len(alerts))
+	}
+	if count, err := repo.Count(ctx, filter); err != nil || count != 2 {
+		t.Errorf("Count(severity=high) = %d, %v, want 2", count, err)
+	}
+}
+
+func TestAlertRepository_ListMuted(t *testing.T) {
+	repo := NewAlertRepository()
+	ctx := context.Background()
+
+	created := time.Date(2026, 10, 15, 14, 30, 0, 0, time.UTC)
+	alerts := []*domain.Alert{
+		{DedupKey: "db-1", EventManagerID: "em-1", Status: domain.AlertStatusActive},
+		{DedupKey: "db-2", EventManagerID: "em-1", Status: domain.AlertStatusActive},
+		{DedupKey: "db-3", EventManagerID: "em-1", Status: domain.AlertStatusResolved},
+		{DedupKey: "web-1", EventManagerID: "em-1", Status: domain.AlertStatusActive},
+		{DedupKey: "web-2", EventManagerID: "em-2", Status: domain.AlertStatusActive},
+		{DedupKey: "web-3", EventManagerID: "em-3", Status: domain.AlertStatusActive},
+	}
+	for i, alert := range alerts {
+		alert.ID = alert.DedupKey
+		alert.CreatedAt = created.Add(time.Duration(i) * time.Minute)
+		if err := repo.Create(ctx, alert); err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+	}
+
+	muting := &domain.AlertMuting{
+		Silences:                []*domain.Silence{{ID: "s-1", Match: domain.SilenceMatcher{DedupKeyPattern: "db-*"}}},
+		SuppressedEventManagers: []string{"em-1", "em-2"},
+	}
+
+	tests := []struct {
+		status domain.AlertStatus
+		limit  int
+		offset int
+		want   []string
+	}{
+		{domain.AlertStatusSilenced, 0, 0, []string{"db-2", "db-1"}},
+		{domain.AlertStatusSilenced, 1, 1, []string{"db-1"}},
+		{domain.AlertStatusSuppressed, 0, 0, []string{"web-2", "web-1"}},
+		{domain.AlertStatusActive, 0, 0, []string{"web-3"}},
+		{domain.AlertStatusResolved, 0, 0, []string{"db-3"}},
+	}
+	for _, tt := range tests {
+		filter := domain.AlertFilter{Status: tt.status, Muting: muting, Limit: tt.limit, Offset: tt.offset}
+		listed, err := repo.List(ctx, filter)
+		if err != nil {
+			t.Fatalf("List error: %v", err)
+		}
+		var got []string
+		for _, alert := range listed {
+			got = append(got, alert.DedupKey)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("List(status=%s, limit=%d, offset=%d) = %v, want %v", tt.status, tt.limit, tt.offset, got, tt.want)
+		}
+	}
+
+	// Without muting, the muted statuses match none
+	if count, err := repo.Count(ctx, domain.AlertFilter{Status: domain.AlertStatusSilenced}); err != nil || count != 0 {
+		t.Errorf("Count(status=silenced) without muting = %d, %v, want 0", count, err)
+	}
+}
+
+func TestAlertRepository_ModifyUsesClock(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 10, 15, 14, 30, 0, 0, time.UTC))
+	repo := NewAlertRepositoryWithClock(clk)
+	ctx := context.Background()
+
+	if err := repo.Create(ctx, &domain.Alert{ID: "a", DedupKey: "a", CreatedAt: clk.Now(), UpdatedAt: clk.Now()}); err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	clk.Advance(time.Hour)
+	if err := repo.IncrementTriggerCount(ctx, "a"); err != nil {
+		t.Fatalf("IncrementTriggerCount error: %v", err)
+	}
+
+	alert, err := repo.GetByDedupKey(ctx, "a")
+	if err != nil {
+		t.Fatalf("GetByDedupKey error: %v", err)
+	}
+	if !alert.UpdatedAt.Equal(clk.Now()) {
+		t.Errorf("UpdatedAt = %v, want %v", alert.UpdatedAt, clk.Now())
+	}
+}
@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"argus-go/internal/domain"
+)
+
+// AuditLogRepository is an in-memory implementation of store.AuditLogRepository.
+type AuditLogRepository struct {
+	mu sync.RWMutex
+
+	// entries holds the audit log, oldest first
+	entries []*domain.AuditEntry
+}
+
+// NewAuditLogRepository creates a new in-memory audit log.
+func NewAuditLogRepository() *AuditLogRepository {
+	return &AuditLogRepository{}
+}
+
+// Record chains an entry to the last one and appends it to the audit log.
+func (r *AuditLogRepository) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var prev *domain.AuditEntry
+	if len(r.entries) > 0 {
+		prev = r.entries[len(r.entries)-1]
+	}
+	entry.Chain(prev)
+
+	// Store a copy
+	entryCopy := *entry
+	r.entries = append(r.entries, &entryCopy)
+	return nil
+}
+
+// List retrieves the most recent entries, newest first, at most limit.
+func (r *AuditLogRepository) List(ctx context.Context, limit int) ([]*domain.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := make([]*domain.AuditEntry, 0, min(limit, len(r.entries)))
+	for i := len(r.entries) - 1; i >= 0 && len(results) < limit; i-- {
+		entryCopy := *r.entries[i]
+		results = append(results, &entryCopy)
+	}
+
+	return results, nil
+}
+
+// Export retrieves every entry, oldest first.
+func (r *AuditLogRepository) Export(ctx context.Context) ([]*domain.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := make([]*domain.AuditEntry, 0, len(r.entries))
+	for _, entry := range r.entries {
+		entryCopy := *entry
+		results = append(results, &entryCopy)
+	}
+
+	return results, nil
+}
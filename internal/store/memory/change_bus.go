@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"argus-go/internal/store"
+)
+
+// ChangeBus is an in-process implementation of store.ChangeBus, for a
+// single replica.
+type ChangeBus struct {
+	mu          sync.Mutex
+	nextID      int
+	subscribers map[int]*subscriber
+}
+
+// subscriber receives published changes until done is closed.
+type subscriber struct {
+	changes chan store.ConfigChange
+	done    chan struct{}
+}
+
+// NewChangeBus creates a new in-process change bus.
+func NewChangeBus() *ChangeBus {
+	return &ChangeBus{subscribers: make(map[int]*subscriber)}
+}
+
+// Publish delivers a change to every subscriber.
+func (b *ChangeBus) Publish(ctx context.Context, change store.ConfigChange) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, sub := range b.subscribers {
+		select {
+		case sub.changes <- change:
+		case <-sub.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Subscribe calls handler for every published change until the context is
+// canceled.
+func (b *ChangeBus) Subscribe(ctx context.Context, handler func(store.ConfigChange)) error {
+	sub := &subscriber{
+		changes: make(chan store.ConfigChange, 64),
+		done:    make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = sub
+	b.mu.Unlock()
+
+	defer func() {
+		// Unblock a Publish waiting on this subscriber before removing it
+		close(sub.done)
+		b.mu.Lock()
+		delete(b.subscribers, id)
+		b.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case change := <-sub.changes:
+			handler(change)
+		}
+	}
+}
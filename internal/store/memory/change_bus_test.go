@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"argus-go/internal/store"
+)
+
+func TestChangeBus_PublishSubscribe(t *testing.T) {
+	bus := NewChangeBus()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	received := make(chan store.ConfigChange, 100)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = bus.Subscribe(ctx, func(change store.ConfigChange) { received <- change })
+	}()
+
+	// Publish until the subscriber is registered
+	want := store.ConfigChange{Kind: store.ChangeGroupingRule, ID: "rule-1"}
+	deadline := time.After(time.Second)
+	for got := false; !got; {
+		if err := bus.Publish(context.Background(), want); err != nil {
+			t.Fatalf("Publish error: %v", err)
+		}
+		select {
+		case change := <-received:
+			if change != want {
+				t.Errorf("received %+v, want %+v", change, want)
+			}
+			got = true
+		case <-time.After(5 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("change not received")
+		}
+	}
+
+	cancel()
+	<-done
+	if err := bus.Publish(context.Background(), want); err != nil {
+		t.Errorf("Publish without subscribers error: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package memory
 import (
 	"context"
 	"sync"
+	"time"
 
 	"argus-go/internal/domain"
 )
@@ -52,11 +53,30 @@ func (r *EventManagerRepository) Update(ctx context.Context, em *domain.EventMan
 	return nil
 }
 
-// Delete removes an event manager by ID.
+// Delete soft-deletes an event manager by ID.
 func (r *EventManagerRepository) Delete(ctx context.Context, id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	em, exists := r.eventManagers[id]
+	if !exists || em.IsDeleted() {
+		return domain.ErrEventManagerNotFound
+	}
+
+	// Replace the stored copy so earlier readers are unaffected
+	now := time.Now().UTC()
+	emCopy := *em
+	emCopy.DeletedAt = &now
+	emCopy.UpdatedAt = now
+	r.eventManagers[em.ID] = &emCopy
+	return nil
+}
+
+// Purge permanently removes an event manager by ID.
+func (r *EventManagerRepository) Purge(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if _, exists := r.eventManagers[id]; !exists {
 		return domain.ErrEventManagerNotFound
 	}
@@ -80,6 +100,24 @@ func (r *EventManagerRepository) GetByID(ctx context.Context, id string) (*domai
 	return &result, nil
 }
 
+// GetByIngestToken retrieves an event manager by its ingest token.
+func (r *EventManagerRepository) GetByIngestToken(ctx context.Context, token string) (*domain.EventManager, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if token == "" {
+		return nil, domain.ErrEventManagerNotFound
+	}
+	for _, em := range r.eventManagers {
+		if em.IngestToken == token {
+			result := *em
+			return &result, nil
+		}
+	}
+
+	return nil, domain.ErrEventManagerNotFound
+}
+
 // List retrieves all event managers.
 func (r *EventManagerRepository) List(ctx context.Context) ([]*domain.EventManager, error) {
 	r.mu.RLock()
@@ -87,6 +125,28 @@ func (r *EventManagerRepository) List(ctx context.Context) ([]*domain.EventManag
 
 	results := make([]*domain.EventManager, 0, len(r.eventManagers))
 	for _, em := range r.eventManagers {
+		if em.IsDeleted() {
+			continue
+		}
+		emCopy := *em
+		results = append(results, &emCopy)
+	}
+
+	return results, nil
+}
+
+// ListByGroupingRule retrieves all event managers referencing a grouping rule,
+// as their default rule, in their grouping rule list or as their candidate
+// rule, including deleted ones.
+func (r *EventManagerRepository) ListByGroupingRule(ctx context.Context, groupingRuleID string) ([]*domain.EventManager, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var results []*domain.EventManager
+	for _, em := range r.eventManagers {
+		if !em.UsesGroupingRule(groupingRuleID) {
+			continue
+		}
 		emCopy := *em
 		results = append(results, &emCopy)
 	}
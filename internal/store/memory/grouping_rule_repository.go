@@ -3,6 +3,7 @@ package memory
 import (
 	"context"
 	"sync"
+	"time"
 
 	"argus-go/internal/domain"
 )
@@ -27,6 +28,10 @@ func (r *GroupingRuleRepository) Create(ctx context.Context, rule *domain.Groupi
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if _, exists := r.groupingRules[rule.ID]; exists {
+		return domain.ErrGroupingRuleAlreadyExists
+	}
+
 	// Store a copy
 	ruleCopy := *rule
 	r.groupingRules[rule.ID] = &ruleCopy
@@ -48,11 +53,30 @@ func (r *GroupingRuleRepository) Update(ctx context.Context, rule *domain.Groupi
 	return nil
 }
 
-// Delete removes a grouping rule by ID.
+// Delete soft-deletes a grouping rule by ID.
 func (r *GroupingRuleRepository) Delete(ctx context.Context, id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	rule, exists := r.groupingRules[id]
+	if !exists || rule.IsDeleted() {
+		return domain.ErrGroupingRuleNotFound
+	}
+
+	// Replace the stored copy so earlier readers are unaffected
+	now := time.Now().UTC()
+	ruleCopy := *rule
+	ruleCopy.DeletedAt = &now
+	ruleCopy.UpdatedAt = now
+	r.groupingRules[rule.ID] = &ruleCopy
+	return nil
+}
+
+// Purge permanently removes a grouping rule by ID.
+func (r *GroupingRuleRepository) Purge(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if _, exists := r.groupingRules[id]; !exists {
 		return domain.ErrGroupingRuleNotFound
 	}
@@ -83,6 +107,9 @@ func (r *GroupingRuleRepository) List(ctx context.Context) ([]*domain.GroupingRu
 
 	results := make([]*domain.GroupingRule, 0, len(r.groupingRules))
 	for _, rule := range r.groupingRules {
+		if rule.IsDeleted() {
+			continue
+		}
 		ruleCopy := *rule
 		results = append(results, &ruleCopy)
 	}
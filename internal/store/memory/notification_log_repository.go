@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"argus-go/internal/domain"
+)
+
+// NotificationLogRepository is an in-memory implementation of store.NotificationLogRepository.
+type NotificationLogRepository struct {
+	mu sync.RWMutex
+
+	// records stores the notifications sent about each alert by dedup key, oldest first
+	records map[string][]*domain.NotificationRecord
+}
+
+// NewNotificationLogRepository creates a new in-memory notification log.
+func NewNotificationLogRepository() *NotificationLogRepository {
+	return &NotificationLogRepository{
+		records: make(map[string][]*domain.NotificationRecord),
+	}
+}
+
+// Record appends a sent notification to the log.
+func (r *NotificationLogRepository) Record(ctx context.Context, record *domain.NotificationRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Store a copy
+	recordCopy := *record
+	r.records[record.DedupKey] = append(r.records[record.DedupKey], &recordCopy)
+	return nil
+}
+
+// ListByDedupKey retrieves the notifications sent about an alert, oldest first.
+func (r *NotificationLogRepository) ListByDedupKey(ctx context.Context, dedupKey string) ([]*domain.NotificationRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := make([]*domain.NotificationRecord, 0, len(r.records[dedupKey]))
+	for _, record := range r.records[dedupKey] {
+		recordCopy := *record
+		results = append(results, &recordCopy)
+	}
+
+	return results, nil
+}
+
+// DeleteByEventManager removes the notifications sent to an event manager.
+func (r *NotificationLogRepository) DeleteByEventManager(ctx context.Context, eventManagerID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for dedupKey, records := range r.records {
+		kept := records[:0]
+		for _, record := range records {
+			if record.EventManagerID == eventManagerID {
+				deleted++
+				continue
+			}
+			kept = append(kept, record)
+		}
+		if len(kept) == 0 {
+			delete(r.records, dedupKey)
+		} else {
+			r.records[dedupKey] = kept
+		}
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+
+	"argus-go/internal/domain"
+)
+
+// QueryRuleRepository is an in-memory implementation of store.QueryRuleRepository.
+type QueryRuleRepository struct {
+	mu sync.RWMutex
+
+	// rules stores all query rules by their ID
+	rules map[string]*domain.QueryRule
+}
+
+// NewQueryRuleRepository creates a new in-memory query rule repository.
+func NewQueryRuleRepository() *QueryRuleRepository {
+	return &QueryRuleRepository{
+		rules: make(map[string]*domain.QueryRule),
+	}
+}
+
+// Create stores a new query rule.
+func (r *QueryRuleRepository) Create(ctx context.Context, rule *domain.QueryRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rules[rule.ID] = copyQueryRule(rule)
+	return nil
+}
+
+// Update modifies an existing query rule.
+func (r *QueryRuleRepository) Update(ctx context.Context, rule *domain.QueryRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.rules[rule.ID]; !exists {
+		return domain.ErrQueryRuleNotFound
+	}
+
+	r.rules[rule.ID] = copyQueryRule(rule)
+	return nil
+}
+
+// Delete permanently removes a query rule by ID.
+func (r *QueryRuleRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.rules[id]; !exists {
+		return domain.ErrQueryRuleNotFound
+	}
+
+	delete(r.rules, id)
+	return nil
+}
+
+// GetByID retrieves a query rule by its ID.
+func (r *QueryRuleRepository) GetByID(ctx context.Context, id string) (*domain.QueryRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rule, exists := r.rules[id]
+	if !exists {
+		return nil, domain.ErrQueryRuleNotFound
+	}
+
+	return copyQueryRule(rule), nil
+}
+
+// List retrieves every query rule, oldest first.
+func (r *QueryRuleRepository) List(ctx context.Context) ([]*domain.QueryRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := make([]*domain.QueryRule, 0, len(r.rules))
+	for _, rule := range r.rules {
+		results = append(results, copyQueryRule(rule))
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if !results[i].CreatedAt.Equal(results[j].CreatedAt) {
+			return results[i].CreatedAt.Before(results[j].CreatedAt)
+		}
+		return results[i].ID < results[j].ID
+	})
+	return results, nil
+}
+
+// copyQueryRule returns a copy of a rule that shares no slices, maps or
+// pointers with it.
+func copyQueryRule(rule *domain.QueryRule) *domain.QueryRule {
+	ruleCopy := *rule
+	ruleCopy.DedupLabels = slices.Clone(rule.DedupLabels)
+	ruleCopy.Params = maps.Clone(rule.Params)
+	ruleCopy.TemplateValues = maps.Clone(rule.TemplateValues)
+	if rule.MutedUntil != nil {
+		mutedUntil := *rule.MutedUntil
+		ruleCopy.MutedUntil = &mutedUntil
+	}
+	if rule.Composite != nil {
+		composite := *rule.Composite
+		composite.Rules = maps.Clone(rule.Composite.Rules)
+		ruleCopy.Composite = &composite
+	}
+	return &ruleCopy
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"argus-go/internal/domain"
+)
+
+// RemediationLogRepository is an in-memory implementation of store.RemediationLogRepository.
+type RemediationLogRepository struct {
+	mu sync.RWMutex
+
+	// records stores the actions run for each alert by dedup key, oldest first
+	records map[string][]*domain.RemediationRecord
+}
+
+// NewRemediationLogRepository creates a new in-memory remediation log.
+func NewRemediationLogRepository() *RemediationLogRepository {
+	return &RemediationLogRepository{
+		records: make(map[string][]*domain.RemediationRecord),
+	}
+}
+
+// Record appends a run of a remediation action to the log.
+func (r *RemediationLogRepository) Record(ctx context.Context, record *domain.RemediationRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Store a copy
+	recordCopy := *record
+	r.records[record.DedupKey] = append(r.records[record.DedupKey], &recordCopy)
+	return nil
+}
+
+// ListByDedupKey retrieves the actions run for an alert, oldest first.
+func (r *RemediationLogRepository) ListByDedupKey(ctx context.Context, dedupKey string) ([]*domain.RemediationRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := make([]*domain.RemediationRecord, 0, len(r.records[dedupKey]))
+	for _, record := range r.records[dedupKey] {
+		recordCopy := *record
+		results = append(results, &recordCopy)
+	}
+
+	return results, nil
+}
+
+// DeleteByEventManager removes the actions run for an event manager.
+func (r *RemediationLogRepository) DeleteByEventManager(ctx context.Context, eventManagerID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for dedupKey, records := range r.records {
+		kept := records[:0]
+		for _, record := range records {
+			if record.EventManagerID == eventManagerID {
+				deleted++
+				continue
+			}
+			kept = append(kept, record)
+		}
+		if len(kept) == 0 {
+			delete(r.records, dedupKey)
+		} else {
+			r.records[dedupKey] = kept
+		}
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,224 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"sort"
+
+	"argus-go/internal/domain"
+)
+
+// ReportRepository is an in-memory implementation of store.ReportRepository.
+// It computes aggregations by scanning the alerts held by an AlertRepository.
+type ReportRepository struct {
+	alerts *AlertRepository
+}
+
+// NewReportRepository creates a report repository over the given alert repository.
+func NewReportRepository(alerts *AlertRepository) *ReportRepository {
+	return &ReportRepository{alerts: alerts}
+}
+
+// inRange returns the alerts matching the filter's event manager and time range.
+func (r *ReportRepository) inRange(filter domain.ReportFilter) []domain.Alert {
+	r.alerts.mu.RLock()
+	defer r.alerts.mu.RUnlock()
+
+	var results []domain.Alert
+	for _, alert := range r.alerts.alerts {
+		if filter.EventManagerID != "" && alert.EventManagerID != filter.EventManagerID {
+			continue
+		}
+		if alert.CreatedAt.Before(filter.From) || !alert.CreatedAt.Before(filter.To) {
+			continue
+		}
+		results = append(results, *alert)
+	}
+	return results
+}
+
+// MTTR returns acknowledgement and resolution timings per event manager.
+func (r *ReportRepository) MTTR(ctx context.Context, filter domain.ReportFilter) ([]*domain.MTTRStats, error) {
+	type totals struct {
+		stats    *domain.MTTRStats
+		ackSum   float64
+		resolved float64
+	}
+	byEM := make(map[string]*totals)
+
+	for _, alert := range r.inRange(filter) {
+		t, ok := byEM[alert.EventManagerID]
+		if !ok {
+			t = &totals{stats: &domain.MTTRStats{EventManagerID: alert.EventManagerID}}
+			byEM[alert.EventManagerID] = t
+		}
+		t.stats.AlertCount++
+		if alert.AcknowledgedAt != nil {
+			t.stats.AcknowledgedCount++
+			t.ackSum += alert.AcknowledgedAt.Sub(alert.CreatedAt).Seconds()
+		}
+		if alert.IsResolved() && alert.ResolvedAt != nil {
+			t.stats.ResolvedCount++
+			t.resolved += alert.ResolvedAt.Sub(alert.CreatedAt).Seconds()
+		}
+	}
+
+	results := make([]*domain.MTTRStats, 0, len(byEM))
+	for _, t := range byEM {
+		if t.stats.AcknowledgedCount > 0 {
+			t.stats.MTTASeconds = t.ackSum / float64(t.stats.AcknowledgedCount)
+		}
+		if t.stats.ResolvedCount > 0 {
+			t.stats.MTTRSeconds = t.resolved / float64(t.stats.ResolvedCount)
+		}
+		results = append(results, t.stats)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].EventManagerID < results[j].EventManagerID
+	})
+	return results, nil
+}
+
+// DailyVolume returns the number of alerts created per event manager per day.
+func (r *ReportRepository) DailyVolume(ctx context.Context, filter domain.ReportFilter) ([]*domain.DailyVolume, error) {
+	counts := make(map[[2]string]int)
+	for _, alert := range r.inRange(filter) {
+		counts[[2]string{alert.EventManagerID, domain.ReportDay(alert.CreatedAt)}]++
+	}
+
+	results := make([]*domain.DailyVolume, 0, len(counts))
+	for key, count := range counts {
+		results = append(results, &domain.DailyVolume{
+			EventManagerID: key[0],
+			Day:            key[1],
+			Count:          count,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].EventManagerID != results[j].EventManagerID {
+			return results[i].EventManagerID < results[j].EventManagerID
+		}
+		return results[i].Day < results[j].Day
+	})
+	return results, nil
+}
+
+// TopDedupKeys returns the most frequently triggered dedup keys per event manager.
+func (r *ReportRepository) TopDedupKeys(ctx context.Context, filter domain.ReportFilter) ([]*domain.DedupKeyVolume, error) {
+	alerts := r.inRange(filter)
+
+	// Order by event manager, then by trigger count descending
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].EventManagerID != alerts[j].EventManagerID {
+			return alerts[i].EventManagerID < alerts[j].EventManagerID
+		}
+		if alerts[i].TriggerCount != alerts[j].TriggerCount {
+			return alerts[i].TriggerCount > alerts[j].TriggerCount
+		}
+		return alerts[i].DedupKey < alerts[j].DedupKey
+	})
+
+	limit := filter.TopLimit
+	if limit <= 0 {
+		limit = domain.DefaultReportTopLimit
+	}
+
+	var results []*domain.DedupKeyVolume
+	perEM := make(map[string]int)
+	for _, alert := range alerts {
+		if perEM[alert.EventManagerID] >= limit {
+			continue
+		}
+		perEM[alert.EventManagerID]++
+		results = append(results, &domain.DedupKeyVolume{
+			EventManagerID: alert.EventManagerID,
+			DedupKey:       alert.DedupKey,
+			Summary:        alert.Summary,
+			TriggerCount:   alert.TriggerCount,
+		})
+	}
+	return results, nil
+}
+
+// NoiseStats returns trigger/resolve counters for alerts active within the range.
+func (r *ReportRepository) NoiseStats(ctx context.Context, filter domain.ReportFilter) ([]*domain.NoiseStats, error) {
+	r.alerts.mu.RLock()
+	defer r.alerts.mu.RUnlock()
+
+	var results []*domain.NoiseStats
+	for _, alert := range r.alerts.alerts {
+		if filter.EventManagerID != "" && alert.EventManagerID != filter.EventManagerID {
+			continue
+		}
+		if !alert.CreatedAt.Before(filter.To) || alert.UpdatedAt.Before(filter.From) {
+			continue
+		}
+		results = append(results, &domain.NoiseStats{
+			EventManagerID: alert.EventManagerID,
+			DedupKey:       alert.DedupKey,
+			Class:          alert.Class,
+			Summary:        alert.Summary,
+			TriggerCount:   alert.TriggerCount,
+			ResolveCount:   alert.ResolveCount,
+			CreatedAt:      alert.CreatedAt,
+		})
+	}
+	return results, nil
+}
+
+// SourceStats returns the alerts created in the range per event manager and origin.
+func (r *ReportRepository) SourceStats(ctx context.Context, filter domain.ReportFilter) ([]*domain.SourceStats, error) {
+	type key struct {
+		eventManagerID string
+		origin         domain.EventOrigin
+	}
+	bySource := make(map[key]*domain.SourceStats)
+
+	for _, alert := range r.inRange(filter) {
+		k := key{eventManagerID: alert.EventManagerID}
+		if alert.Origin != nil {
+			k.origin = *alert.Origin
+		}
+		stats, ok := bySource[k]
+		if !ok {
+			stats = &domain.SourceStats{
+				EventManagerID: k.eventManagerID,
+				Source:         k.origin.Source,
+				IP:             k.origin.IP,
+				UserAgent:      k.origin.UserAgent,
+				Via:            k.origin.Via,
+			}
+			bySource[k] = stats
+		}
+		stats.AlertCount++
+		if alert.IsActive() {
+			stats.ActiveCount++
+		}
+		stats.TriggerCount += alert.TriggerCount
+	}
+
+	results := make([]*domain.SourceStats, 0, len(bySource))
+	for _, stats := range bySource {
+		results = append(results, stats)
+	}
+
+	// Order by event manager, then by trigger count descending
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.EventManagerID != b.EventManagerID {
+			return a.EventManagerID < b.EventManagerID
+		}
+		if a.TriggerCount != b.TriggerCount {
+			return a.TriggerCount > b.TriggerCount
+		}
+		return cmp.Or(
+			cmp.Compare(a.Source, b.Source),
+			cmp.Compare(a.IP, b.IP),
+			cmp.Compare(a.UserAgent, b.UserAgent),
+			cmp.Compare(a.Via, b.Via),
+		) < 0
+	})
+	return results, nil
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+)
+
+func TestReportRepository_MTTRAndVolume(t *testing.T) {
+	alerts := NewAlertRepository()
+	reports := NewReportRepository(alerts)
+	ctx := context.Background()
+
+	base := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	ackAt := base.Add(2 * time.Minute)
+	resolvedAt := base.Add(10 * time.Minute)
+
+	_ = alerts.Create(ctx, &domain.Alert{
+		ID: "1", DedupKey: "a", EventManagerID: "em-1",
+		Status: domain.AlertStatusResolved, TriggerCount: 5,
+		CreatedAt: base, AcknowledgedAt: &ackAt, ResolvedAt: &resolvedAt,
+	})
+	_ = alerts.Create(ctx, &domain.Alert{
+		ID: "2", DedupKey: "b", EventManagerID: "em-1",
+		Status: domain.AlertStatusActive, TriggerCount: 2,
+		CreatedAt: base.Add(24 * time.Hour),
+	})
+	// Outside the range
+	_ = alerts.Create(ctx, &domain.Alert{
+		ID: "3", DedupKey: "c", EventManagerID: "em-1",
+		Status: domain.AlertStatusActive, TriggerCount: 50,
+		CreatedAt: base.Add(-24 * time.Hour),
+	})
+
+	filter := domain.ReportFilter{From: base, To: base.Add(48 * time.Hour), TopLimit: 1}
+
+	stats, err := reports.MTTR(ctx, filter)
+	if err != nil {
+		t.Fatalf("MTTR error: %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("MTTR returned %d event managers, want 1", len(stats))
+	}
+	if stats[0].AlertCount != 2 || stats[0].ResolvedCount != 1 || stats[0].AcknowledgedCount != 1 {
+		t.Errorf("unexpected counts: %+v", stats[0])
+	}
+	if stats[0].MTTASeconds != 120 {
+		t.Errorf("MTTASeconds = %v, want 120", stats[0].MTTASeconds)
+	}
+	if stats[0].MTTRSeconds != 600 {
+		t.Errorf("MTTRSeconds = %v, want 600", stats[0].MTTRSeconds)
+	}
+
+	days, _ := reports.DailyVolume(ctx, filter)
+	if len(days) != 2 || days[0].Day != "2024-03-01" || days[0].Count != 1 {
+		t.Errorf("unexpected daily volume: %+v", days)
+	}
+
+	top, _ := reports.TopDedupKeys(ctx, filter)
+	if len(top) != 1 || top[0].DedupKey != "a" || top[0].TriggerCount != 5 {
+		t.Errorf("unexpected top dedup keys: %+v", top)
+	}
+}
+
+func TestReportRepository_SourceStats(t *testing.T) {
+	alerts := NewAlertRepository()
+	reports := NewReportRepository(alerts)
+	ctx := context.Background()
+
+	base := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	nagios := &domain.EventOrigin{Source: "nagios", IP: "10.0.0.1", Via: "api"}
+
+	_ = alerts.Create(ctx, &domain.Alert{
+		ID: "1", DedupKey: "a", EventManagerID: "em-1",
+		Status: domain.AlertStatusActive, TriggerCount: 3,
+		CreatedAt: base, Origin: nagios,
+	})
+	_ = alerts.Create(ctx, &domain.Alert{
+		ID: "2", DedupKey: "b", EventManagerID: "em-1",
+		Status: domain.AlertStatusResolved, TriggerCount: 4,
+		CreatedAt: base, Origin: nagios,
+	})
+	_ = alerts.Create(ctx, &domain.Alert{
+		ID: "3", DedupKey: "c", EventManagerID: "em-1",
+		Status: domain.AlertStatusActive, TriggerCount: 1,
+		CreatedAt: base,
+	})
+
+	stats, err := reports.SourceStats(ctx, domain.ReportFilter{From: base, To: base.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("SourceStats error: %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("SourceStats returned %d sources, want 2", len(stats))
+	}
+	if got := stats[0]; got.Source != "nagios" || got.IP != "10.0.0.1" || got.AlertCount != 2 || got.ActiveCount != 1 || got.TriggerCount != 7 {
+		t.Errorf("unexpected noisiest source: %+v", got)
+	}
+	if got := stats[1]; got.Source != "" || got.AlertCount != 1 || got.TriggerCount != 1 {
+		t.Errorf("unexpected source of alerts without origin: %+v", got)
+	}
+}
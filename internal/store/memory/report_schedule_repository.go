@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"argus-go/internal/domain"
+)
+
+// ReportScheduleRepository is an in-memory implementation of
+// store.ReportScheduleRepository.
+type ReportScheduleRepository struct {
+	mu sync.RWMutex
+
+	// schedules stores all report schedules by their ID
+	schedules map[string]*domain.ReportSchedule
+}
+
+// NewReportScheduleRepository creates a new in-memory report schedule
+// repository.
+func NewReportScheduleRepository() *ReportScheduleRepository {
+	return &ReportScheduleRepository{
+		schedules: make(map[string]*domain.ReportSchedule),
+	}
+}
+
+// Create stores a new report schedule.
+func (r *ReportScheduleRepository) Create(ctx context.Context, schedule *domain.ReportSchedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Store a copy
+	scheduleCopy := *schedule
+	r.schedules[schedule.ID] = &scheduleCopy
+	return nil
+}
+
+// Update replaces the settings of a report schedule, leaving the end of its
+// last report as it is.
+func (r *ReportScheduleRepository) Update(ctx context.Context, schedule *domain.ReportSchedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.schedules[schedule.ID]
+	if !exists {
+		return domain.ErrReportScheduleNotFound
+	}
+
+	scheduleCopy := *schedule
+	scheduleCopy.LastReportTo = existing.LastReportTo
+	r.schedules[schedule.ID] = &scheduleCopy
+	return nil
+}
+
+// Delete permanently removes a report schedule by ID.
+func (r *ReportScheduleRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.schedules[id]; !exists {
+		return domain.ErrReportScheduleNotFound
+	}
+
+	delete(r.schedules, id)
+	return nil
+}
+
+// GetByID retrieves a report schedule by its ID.
+func (r *ReportScheduleRepository) GetByID(ctx context.Context, id string) (*domain.ReportSchedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schedule, exists := r.schedules[id]
+	if !exists {
+		return nil, domain.ErrReportScheduleNotFound
+	}
+
+	// Return a copy
+	result := *schedule
+	return &result, nil
+}
+
+// List retrieves every report schedule, oldest first.
+func (r *ReportScheduleRepository) List(ctx context.Context) ([]*domain.ReportSchedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := make([]*domain.ReportSchedule, 0, len(r.schedules))
+	for _, schedule := range r.schedules {
+		scheduleCopy := *schedule
+		results = append(results, &scheduleCopy)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if !results[i].CreatedAt.Equal(results[j].CreatedAt) {
+			return results[i].CreatedAt.Before(results[j].CreatedAt)
+		}
+		return results[i].ID < results[j].ID
+	})
+	return results, nil
+}
+
+// MarkSent sets the end of the last report of a schedule to to, if it is
+// still previous.
+func (r *ReportScheduleRepository) MarkSent(ctx context.Context, id string, previous, to *time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	schedule, exists := r.schedules[id]
+	if !exists {
+		return false, domain.ErrReportScheduleNotFound
+	}
+	if !sameTime(schedule.LastReportTo, previous) {
+		return false, nil
+	}
+
+	schedule.LastReportTo = nil
+	if to != nil {
+		end := *to
+		schedule.LastReportTo = &end
+	}
+	return true, nil
+}
+
+// sameTime reports whether two optional times are both unset or equal.
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"argus-go/internal/domain"
+)
+
+// RoutingRuleRepository is an in-memory implementation of store.RoutingRuleRepository.
+type RoutingRuleRepository struct {
+	mu sync.RWMutex
+
+	// routingRules stores all routing rules by their ID
+	routingRules map[string]*domain.RoutingRule
+}
+
+// NewRoutingRuleRepository creates a new in-memory routing rule repository.
+func NewRoutingRuleRepository() *RoutingRuleRepository {
+	return &RoutingRuleRepository{
+		routingRules: make(map[string]*domain.RoutingRule),
+	}
+}
+
+// Create stores a new routing rule.
+func (r *RoutingRuleRepository) Create(ctx context.Context, rule *domain.RoutingRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Store a copy
+	ruleCopy := *rule
+	r.routingRules[rule.ID] = &ruleCopy
+	return nil
+}
+
+// Update modifies an existing routing rule.
+func (r *RoutingRuleRepository) Update(ctx context.Context, rule *domain.RoutingRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.routingRules[rule.ID]; !exists {
+		return domain.ErrRoutingRuleNotFound
+	}
+
+	// Store a copy
+	ruleCopy := *rule
+	r.routingRules[rule.ID] = &ruleCopy
+	return nil
+}
+
+// Delete permanently removes a routing rule by ID.
+func (r *RoutingRuleRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.routingRules[id]; !exists {
+		return domain.ErrRoutingRuleNotFound
+	}
+
+	delete(r.routingRules, id)
+	return nil
+}
+
+// GetByID retrieves a routing rule by its ID.
+func (r *RoutingRuleRepository) GetByID(ctx context.Context, id string) (*domain.RoutingRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rule, exists := r.routingRules[id]
+	if !exists {
+		return nil, domain.ErrRoutingRuleNotFound
+	}
+
+	// Return a copy
+	result := *rule
+	return &result, nil
+}
+
+// List retrieves all routing rules in evaluation order.
+func (r *RoutingRuleRepository) List(ctx context.Context) ([]*domain.RoutingRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := make([]*domain.RoutingRule, 0, len(r.routingRules))
+	for _, rule := range r.routingRules {
+		ruleCopy := *rule
+		results = append(results, &ruleCopy)
+	}
+
+	domain.SortRoutingRules(results)
+	return results, nil
+}
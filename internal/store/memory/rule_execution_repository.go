@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"argus-go/internal/domain"
+)
+
+// RuleExecutionRepository is an in-memory implementation of store.RuleExecutionRepository.
+type RuleExecutionRepository struct {
+	mu sync.RWMutex
+
+	// executions stores the runs of each rule by rule ID, oldest first
+	executions map[string][]*domain.RuleExecution
+}
+
+// NewRuleExecutionRepository creates a new in-memory rule execution log.
+func NewRuleExecutionRepository() *RuleExecutionRepository {
+	return &RuleExecutionRepository{
+		executions: make(map[string][]*domain.RuleExecution),
+	}
+}
+
+// Record appends a run of a rule, and removes its runs older than the
+// latest keep.
+func (r *RuleExecutionRepository) Record(ctx context.Context, execution *domain.RuleExecution, keep int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	executions := append(r.executions[execution.RuleID], copyRuleExecution(execution))
+	if len(executions) > keep {
+		executions = slices.Clone(executions[len(executions)-keep:])
+	}
+	r.executions[execution.RuleID] = executions
+	return nil
+}
+
+// ListByRule retrieves the runs of a rule, newest first, at most limit.
+func (r *RuleExecutionRepository) ListByRule(ctx context.Context, ruleID string, limit int) ([]*domain.RuleExecution, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	executions := r.executions[ruleID]
+	results := make([]*domain.RuleExecution, 0, min(limit, len(executions)))
+	for i := len(executions) - 1; i >= 0 && len(results) < limit; i-- {
+		results = append(results, copyRuleExecution(executions[i]))
+	}
+	return results, nil
+}
+
+// DeleteByRule removes every run of a rule.
+func (r *RuleExecutionRepository) DeleteByRule(ctx context.Context, ruleID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.executions, ruleID)
+	return nil
+}
+
+// copyRuleExecution returns a copy of a run that shares no slices or maps
+// with it.
+func copyRuleExecution(execution *domain.RuleExecution) *domain.RuleExecution {
+	executionCopy := *execution
+	executionCopy.Params = maps.Clone(execution.Params)
+	executionCopy.Outcomes = maps.Clone(execution.Outcomes)
+	executionCopy.Breaching = slices.Clone(execution.Breaching)
+	executionCopy.Triggered = slices.Clone(execution.Triggered)
+	executionCopy.Resolved = slices.Clone(execution.Resolved)
+	return &executionCopy
+}
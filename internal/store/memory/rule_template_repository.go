@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+
+	"argus-go/internal/domain"
+)
+
+// RuleTemplateRepository is an in-memory implementation of store.RuleTemplateRepository.
+type RuleTemplateRepository struct {
+	mu sync.RWMutex
+
+	// templates stores all rule templates by their ID
+	templates map[string]*domain.RuleTemplate
+}
+
+// NewRuleTemplateRepository creates a new in-memory rule template repository.
+func NewRuleTemplateRepository() *RuleTemplateRepository {
+	return &RuleTemplateRepository{
+		templates: make(map[string]*domain.RuleTemplate),
+	}
+}
+
+// Create stores a new rule template.
+func (r *RuleTemplateRepository) Create(ctx context.Context, template *domain.RuleTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.templates[template.ID] = copyRuleTemplate(template)
+	return nil
+}
+
+// Update modifies an existing rule template.
+func (r *RuleTemplateRepository) Update(ctx context.Context, template *domain.RuleTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.templates[template.ID]; !exists {
+		return domain.ErrRuleTemplateNotFound
+	}
+
+	r.templates[template.ID] = copyRuleTemplate(template)
+	return nil
+}
+
+// Delete permanently removes a rule template by ID.
+func (r *RuleTemplateRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.templates[id]; !exists {
+		return domain.ErrRuleTemplateNotFound
+	}
+
+	delete(r.templates, id)
+	return nil
+}
+
+// GetByID retrieves a rule template by its ID.
+func (r *RuleTemplateRepository) GetByID(ctx context.Context, id string) (*domain.RuleTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	template, exists := r.templates[id]
+	if !exists {
+		return nil, domain.ErrRuleTemplateNotFound
+	}
+
+	return copyRuleTemplate(template), nil
+}
+
+// List retrieves every rule template, oldest first.
+func (r *RuleTemplateRepository) List(ctx context.Context) ([]*domain.RuleTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := make([]*domain.RuleTemplate, 0, len(r.templates))
+	for _, template := range r.templates {
+		results = append(results, copyRuleTemplate(template))
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if !results[i].CreatedAt.Equal(results[j].CreatedAt) {
+			return results[i].CreatedAt.Before(results[j].CreatedAt)
+		}
+		return results[i].ID < results[j].ID
+	})
+	return results, nil
+}
+
+// copyRuleTemplate returns a copy of a template that shares no slices, maps
+// or pointers with it.
+func copyRuleTemplate(template *domain.RuleTemplate) *domain.RuleTemplate {
+	templateCopy := *template
+	templateCopy.Variables = slices.Clone(template.Variables)
+	templateCopy.Rule.DedupLabels = slices.Clone(template.Rule.DedupLabels)
+	templateCopy.Rule.Params = maps.Clone(template.Rule.Params)
+	if template.Rule.MutedUntil != nil {
+		mutedUntil := *template.Rule.MutedUntil
+		templateCopy.Rule.MutedUntil = &mutedUntil
+	}
+	return &templateCopy
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// RunnerRegistry is an in-memory implementation of store.RunnerRegistry.
+// It only tracks the replicas sharing the process, as in tests.
+type RunnerRegistry struct {
+	mu sync.Mutex
+
+	// heartbeats stores the last heartbeat of each replica by instance ID
+	heartbeats map[string]time.Time
+}
+
+// NewRunnerRegistry creates a new in-memory runner registry.
+func NewRunnerRegistry() *RunnerRegistry {
+	return &RunnerRegistry{
+		heartbeats: make(map[string]time.Time),
+	}
+}
+
+// Heartbeat records that a replica is alive at now, forgets the replicas
+// silent for longer than timeout, and returns the IDs of the live replicas,
+// sorted.
+func (r *RunnerRegistry) Heartbeat(ctx context.Context, instanceID string, now time.Time, timeout time.Duration) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.heartbeats[instanceID] = now
+	for id, heartbeat := range r.heartbeats {
+		if now.Sub(heartbeat) > timeout {
+			delete(r.heartbeats, id)
+		}
+	}
+	return slices.Sorted(maps.Keys(r.heartbeats)), nil
+}
+
+// Leave removes a replica, handing its rules over to the others.
+func (r *RunnerRegistry) Leave(ctx context.Context, instanceID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.heartbeats, instanceID)
+	return nil
+}
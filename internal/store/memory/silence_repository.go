@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"argus-go/internal/domain"
+)
+
+// SilenceRepository is an in-memory implementation of store.SilenceRepository.
+type SilenceRepository struct {
+	mu sync.RWMutex
+
+	// silences stores all silences by their ID
+	silences map[string]*domain.Silence
+}
+
+// NewSilenceRepository creates a new in-memory silence repository.
+func NewSilenceRepository() *SilenceRepository {
+	return &SilenceRepository{
+		silences: make(map[string]*domain.Silence),
+	}
+}
+
+// Create stores a new silence.
+func (r *SilenceRepository) Create(ctx context.Context, silence *domain.Silence) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.silences[silence.ID]; exists {
+		return domain.ErrSilenceExists
+	}
+
+	// Store a copy
+	silenceCopy := *silence
+	r.silences[silence.ID] = &silenceCopy
+	return nil
+}
+
+// Delete permanently removes a silence by ID.
+func (r *SilenceRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.silences[id]; !exists {
+		return domain.ErrSilenceNotFound
+	}
+
+	delete(r.silences, id)
+	return nil
+}
+
+// DeleteByRecurringID permanently removes the silences materialized from a
+// recurring silence.
+func (r *SilenceRepository) DeleteByRecurringID(ctx context.Context, recurringID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, silence := range r.silences {
+		if silence.RecurringID == recurringID {
+			delete(r.silences, id)
+		}
+	}
+	return nil
+}
+
+// GetByID retrieves a silence by its ID.
+func (r *SilenceRepository) GetByID(ctx context.Context, id string) (*domain.Silence, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	silence, exists := r.silences[id]
+	if !exists {
+		return nil, domain.ErrSilenceNotFound
+	}
+
+	// Return a copy
+	result := *silence
+	return &result, nil
+}
+
+// List retrieves every silence, oldest first.
+func (r *SilenceRepository) List(ctx context.Context) ([]*domain.Silence, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := make([]*domain.Silence, 0, len(r.silences))
+	for _, silence := range r.silences {
+		silenceCopy := *silence
+		results = append(results, &silenceCopy)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if !results[i].CreatedAt.Equal(results[j].CreatedAt) {
+			return results[i].CreatedAt.Before(results[j].CreatedAt)
+		}
+		return results[i].ID < results[j].ID
+	})
+	return results, nil
+}
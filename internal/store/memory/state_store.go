@@ -5,9 +5,12 @@ package memory
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
 	"argus-go/internal/store"
 )
 
@@ -28,6 +31,44 @@ type StateStore struct {
 
 	// pendingResolves stores pending resolution info by parent dedupKey
 	pendingResolves map[string]*store.PendingResolve
+
+	// reminders stores scheduled reminders by parent dedupKey
+	reminders map[string]*store.Reminder
+
+	// notified stores when the record of each notification sent expires,
+	// by notification ID
+	notified map[string]time.Time
+
+	// liveCounts stores the live counts of active alerts by event manager
+	// and severity
+	liveCounts map[liveCountKey]int
+
+	// groupingStats stores the grouping statistics by grouping rule and day
+	groupingStats map[string]map[string]*store.GroupingStats
+
+	// eventStatuses stores the status of events by event ID; expired ones
+	// are pruned at most every eventStatusPruneInterval
+	eventStatuses         map[string]*eventStatusEntry
+	eventStatusesPrunedAt time.Time
+
+	// clock decides when parent entries expire
+	clock clock.Clock
+}
+
+// liveCountKey identifies a live count.
+type liveCountKey struct {
+	eventManagerID string
+	severity       string
+}
+
+// eventStatusPruneInterval is how often expired event statuses are pruned,
+// so a busy store doesn't scan every status on every write.
+const eventStatusPruneInterval = time.Minute
+
+// eventStatusEntry wraps an EventStatus with expiration tracking.
+type eventStatusEntry struct {
+	status    *domain.EventStatus
+	expiresAt time.Time
 }
 
 // parentEntry wraps ParentState with expiration tracking.
@@ -38,11 +79,23 @@ type parentEntry struct {
 
 // NewStateStore creates a new in-memory state store.
 func NewStateStore() *StateStore {
+	return NewStateStoreWithClock(clock.Real{})
+}
+
+// NewStateStoreWithClock creates a new in-memory state store whose parent
+// entries expire by the given clock, so tests can move past grouping windows.
+func NewStateStoreWithClock(clk clock.Clock) *StateStore {
 	return &StateStore{
 		parents:         make(map[string]*parentEntry),
 		alerts:          make(map[string]*store.AlertState),
 		children:        make(map[string]map[string]struct{}),
 		pendingResolves: make(map[string]*store.PendingResolve),
+		reminders:       make(map[string]*store.Reminder),
+		notified:        make(map[string]time.Time),
+		liveCounts:      make(map[liveCountKey]int),
+		groupingStats:   make(map[string]map[string]*store.GroupingStats),
+		eventStatuses:   make(map[string]*eventStatusEntry),
+		clock:           clk,
 	}
 }
 
@@ -66,7 +119,7 @@ func (s *StateStore) GetParent(ctx context.Context, eventManagerID, groupingKey,
 	}
 
 	// Check if expired (lazy expiration)
-	if time.Now().After(entry.expiresAt) {
+	if s.clock.Now().After(entry.expiresAt) {
 		return nil, nil
 	}
 
@@ -85,7 +138,7 @@ func (s *StateStore) SetParent(ctx context.Context, eventManagerID, groupingKey,
 	stateCopy := *state
 	s.parents[key] = &parentEntry{
 		state:     &stateCopy,
-		expiresAt: time.Now().Add(ttl),
+		expiresAt: s.clock.Now().Add(ttl),
 	}
 	return nil
 }
@@ -187,6 +240,20 @@ func (s *StateStore) GetChildCount(ctx context.Context, parentDedupKey string) (
 	return len(s.children[parentDedupKey]), nil
 }
 
+// GetActiveChildCount returns the number of children for a parent whose alert state is active.
+func (s *StateStore) GetActiveChildCount(ctx context.Context, parentDedupKey string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	for childKey := range s.children[parentDedupKey] {
+		if state, ok := s.alerts[childKey]; ok && state.Status == string(domain.AlertStatusActive) {
+			count++
+		}
+	}
+	return count, nil
+}
+
 // --- Pending Resolution Operations ---
 
 // SetPendingResolve marks a parent as having a pending resolve request.
@@ -224,6 +291,206 @@ func (s *StateStore) DeletePendingResolve(ctx context.Context, parentDedupKey st
 	return nil
 }
 
+// --- Reminder Operations ---
+
+// SetReminder schedules or reschedules the reminder of a parent alert.
+func (s *StateStore) SetReminder(ctx context.Context, reminder *store.Reminder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Store a copy
+	reminderCopy := *reminder
+	s.reminders[reminder.DedupKey] = &reminderCopy
+	return nil
+}
+
+// GetDueReminders returns up to limit reminders due at or before now, earliest first.
+func (s *StateStore) GetDueReminders(ctx context.Context, now time.Time, limit int) ([]*store.Reminder, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var due []*store.Reminder
+	for _, reminder := range s.reminders {
+		if !reminder.DueAt.After(now) {
+			// Return a copy
+			reminderCopy := *reminder
+			due = append(due, &reminderCopy)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].DueAt.Before(due[j].DueAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+// DeleteReminder cancels the reminder of a parent alert.
+func (s *StateStore) DeleteReminder(ctx context.Context, dedupKey string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.reminders, dedupKey)
+	return nil
+}
+
+// --- Notification Operations ---
+
+// MarkNotificationSent records a notification as sent for ttl, returning
+// false if it was already recorded. Expired records are dropped as new ones
+// are made.
+func (s *StateStore) MarkNotificationSent(ctx context.Context, notificationID string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	if expiresAt, ok := s.notified[notificationID]; ok && now.Before(expiresAt) {
+		return false, nil
+	}
+	for id, expiresAt := range s.notified {
+		if !now.Before(expiresAt) {
+			delete(s.notified, id)
+		}
+	}
+	s.notified[notificationID] = now.Add(ttl)
+	return true, nil
+}
+
+// --- Live Count Operations ---
+
+// AdjustLiveCount adds delta to the live count of active alerts of an event
+// manager at a severity.
+func (s *StateStore) AdjustLiveCount(ctx context.Context, eventManagerID, severity string, delta int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := liveCountKey{eventManagerID: eventManagerID, severity: severity}
+	s.liveCounts[key] += delta
+	if s.liveCounts[key] == 0 {
+		delete(s.liveCounts, key)
+	}
+	return nil
+}
+
+// GetLiveCounts returns the live counts that are above zero, by event manager
+// and severity.
+func (s *StateStore) GetLiveCounts(ctx context.Context) ([]*store.LiveCount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var counts []*store.LiveCount
+	for key, count := range s.liveCounts {
+		if count > 0 {
+			counts = append(counts, &store.LiveCount{EventManagerID: key.eventManagerID, Severity: key.severity, Count: count})
+		}
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].EventManagerID != counts[j].EventManagerID {
+			return counts[i].EventManagerID < counts[j].EventManagerID
+		}
+		return counts[i].Severity < counts[j].Severity
+	})
+	return counts, nil
+}
+
+// SetLiveCounts replaces all live counts.
+func (s *StateStore) SetLiveCounts(ctx context.Context, counts []*store.LiveCount) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.liveCounts = make(map[liveCountKey]int, len(counts))
+	for _, count := range counts {
+		s.liveCounts[liveCountKey{eventManagerID: count.EventManagerID, severity: count.Severity}] += count.Count
+	}
+	return nil
+}
+
+// --- Grouping Statistics Operations ---
+
+// RecordGrouping counts an alert grouped by a grouping rule on a UTC day.
+// Days older than store.GroupingStatsRetention are dropped.
+func (s *StateStore) RecordGrouping(ctx context.Context, groupingRuleID, day string, child bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	days, ok := s.groupingStats[groupingRuleID]
+	if !ok {
+		days = make(map[string]*store.GroupingStats)
+		s.groupingStats[groupingRuleID] = days
+	}
+	stats, ok := days[day]
+	if !ok {
+		stats = &store.GroupingStats{Day: day}
+		days[day] = stats
+	}
+	if child {
+		stats.ChildrenGrouped++
+	} else {
+		stats.ParentsCreated++
+	}
+
+	cutoff := domain.ReportDay(s.clock.Now().Add(-store.GroupingStatsRetention))
+	for d := range days {
+		if d < cutoff {
+			delete(days, d)
+		}
+	}
+	return nil
+}
+
+// GetGroupingStats returns the statistics of a grouping rule on the given
+// days, skipping days without any.
+func (s *StateStore) GetGroupingStats(ctx context.Context, groupingRuleID string, days []string) ([]*store.GroupingStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []*store.GroupingStats
+	for _, day := range days {
+		if stats, ok := s.groupingStats[groupingRuleID][day]; ok {
+			statsCopy := *stats
+			result = append(result, &statsCopy)
+		}
+	}
+	return result, nil
+}
+
+// --- Event Status Operations ---
+
+// SetEventStatus stores or replaces the status of an event for ttl. Expired
+// statuses are dropped as new ones are stored.
+func (s *StateStore) SetEventStatus(ctx context.Context, status *domain.EventStatus, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	if now.Sub(s.eventStatusesPrunedAt) >= eventStatusPruneInterval {
+		for id, entry := range s.eventStatuses {
+			if !now.Before(entry.expiresAt) {
+				delete(s.eventStatuses, id)
+			}
+		}
+		s.eventStatusesPrunedAt = now
+	}
+	statusCopy := *status
+	s.eventStatuses[status.EventID] = &eventStatusEntry{status: &statusCopy, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// GetEventStatus retrieves the status of an event by its event ID.
+// Returns nil, nil if the event has no status or it expired.
+func (s *StateStore) GetEventStatus(ctx context.Context, eventID string) (*domain.EventStatus, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.eventStatuses[eventID]
+	if !ok || !s.clock.Now().Before(entry.expiresAt) {
+		return nil, nil
+	}
+	statusCopy := *entry.status
+	return &statusCopy, nil
+}
+
 // Close releases any resources (no-op for in-memory store).
 func (s *StateStore) Close() error {
 	return nil
@@ -240,4 +507,9 @@ func (s *StateStore) Clear() {
 	s.alerts = make(map[string]*store.AlertState)
 	s.children = make(map[string]map[string]struct{})
 	s.pendingResolves = make(map[string]*store.PendingResolve)
+	s.reminders = make(map[string]*store.Reminder)
+	s.notified = make(map[string]time.Time)
+	s.liveCounts = make(map[liveCountKey]int)
+	s.groupingStats = make(map[string]map[string]*store.GroupingStats)
+	s.eventStatuses = make(map[string]*eventStatusEntry)
 }
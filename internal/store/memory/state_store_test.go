@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
 	"argus-go/internal/store"
 )
 
@@ -183,6 +185,31 @@ func TestStateStore_ChildOperations(t *testing.T) {
 	}
 }
 
+func TestStateStore_GetActiveChildCount(t *testing.T) {
+	s := NewStateStore()
+	ctx := context.Background()
+
+	_ = s.AddChild(ctx, "parent-1", "child-1")
+	_ = s.AddChild(ctx, "parent-1", "child-2")
+	_ = s.AddChild(ctx, "parent-1", "child-3")
+	_ = s.SetAlert(ctx, &store.AlertState{DedupKey: "child-1", Status: "active"})
+	_ = s.SetAlert(ctx, &store.AlertState{DedupKey: "child-2", Status: "resolved"})
+	// child-3 has no state and is not counted
+
+	count, err := s.GetActiveChildCount(ctx, "parent-1")
+	if err != nil {
+		t.Fatalf("GetActiveChildCount error: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected active count 1, got %d", count)
+	}
+
+	count, _ = s.GetActiveChildCount(ctx, "no-such-parent")
+	if count != 0 {
+		t.Errorf("Expected active count 0 for unknown parent, got %d", count)
+	}
+}
+
 func TestStateStore_PendingResolveOperations(t *testing.T) {
 	s := NewStateStore()
 	ctx := context.Background()
@@ -229,6 +256,125 @@ func TestStateStore_PendingResolveOperations(t *testing.T) {
 	}
 }
 
+func TestStateStore_ReminderOperations(t *testing.T) {
+	s := NewStateStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	_ = s.SetReminder(ctx, &store.Reminder{DedupKey: "later", DueAt: now.Add(time.Minute)})
+	_ = s.SetReminder(ctx, &store.Reminder{DedupKey: "second", DueAt: now.Add(-time.Minute)})
+	_ = s.SetReminder(ctx, &store.Reminder{DedupKey: "first", DueAt: now.Add(-time.Hour)})
+
+	// Due reminders are returned earliest first, up to the limit
+	due, err := s.GetDueReminders(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("GetDueReminders error: %v", err)
+	}
+	if len(due) != 2 || due[0].DedupKey != "first" || due[1].DedupKey != "second" {
+		t.Errorf("due reminders = %+v, want first and second", due)
+	}
+	due, _ = s.GetDueReminders(ctx, now, 1)
+	if len(due) != 1 || due[0].DedupKey != "first" {
+		t.Errorf("due reminders with limit 1 = %+v, want first", due)
+	}
+
+	// Rescheduling and deleting remove reminders from the due ones
+	_ = s.SetReminder(ctx, &store.Reminder{DedupKey: "first", DueAt: now.Add(time.Hour), Sent: 1})
+	if err := s.DeleteReminder(ctx, "second"); err != nil {
+		t.Fatalf("DeleteReminder error: %v", err)
+	}
+	due, _ = s.GetDueReminders(ctx, now, 10)
+	if len(due) != 0 {
+		t.Errorf("due reminders = %+v, want none", due)
+	}
+}
+
+func TestStateStore_LiveCounts(t *testing.T) {
+	s := NewStateStore()
+	ctx := context.Background()
+
+	_ = s.AdjustLiveCount(ctx, "em-2", "low", 1)
+	_ = s.AdjustLiveCount(ctx, "em-1", "high", 2)
+	_ = s.AdjustLiveCount(ctx, "em-1", "high", -1)
+	_ = s.AdjustLiveCount(ctx, "em-1", "medium", -1)
+
+	// Counts are listed by event manager and severity, without those at or
+	// below zero
+	counts, err := s.GetLiveCounts(ctx)
+	if err != nil {
+		t.Fatalf("GetLiveCounts error: %v", err)
+	}
+	want := []store.LiveCount{{EventManagerID: "em-1", Severity: "high", Count: 1}, {EventManagerID: "em-2", Severity: "low", Count: 1}}
+	if len(counts) != len(want) || *counts[0] != want[0] || *counts[1] != want[1] {
+		t.Errorf("live counts = %+v, want %+v", counts, want)
+	}
+
+	// Setting replaces every count
+	if err := s.SetLiveCounts(ctx, []*store.LiveCount{{EventManagerID: "em-3", Severity: "high", Count: 4}}); err != nil {
+		t.Fatalf("SetLiveCounts error: %v", err)
+	}
+	counts, _ = s.GetLiveCounts(ctx)
+	if len(counts) != 1 || counts[0].EventManagerID != "em-3" || counts[0].Count != 4 {
+		t.Errorf("live counts after set = %+v, want em-3 with 4", counts)
+	}
+}
+
+func TestStateStore_GroupingStats(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC))
+	s := NewStateStoreWithClock(clk)
+	ctx := context.Background()
+
+	_ = s.RecordGrouping(ctx, "rule-1", "2026-09-01", false)
+	_ = s.RecordGrouping(ctx, "rule-1", "2026-10-14", false)
+	_ = s.RecordGrouping(ctx, "rule-1", "2026-10-15", false)
+	_ = s.RecordGrouping(ctx, "rule-1", "2026-10-15", true)
+	_ = s.RecordGrouping(ctx, "rule-1", "2026-10-15", true)
+	_ = s.RecordGrouping(ctx, "rule-2", "2026-10-15", false)
+
+	// Days are returned in the order asked, skipping days without any
+	stats, err := s.GetGroupingStats(ctx, "rule-1", []string{"2026-10-13", "2026-10-14", "2026-10-15"})
+	if err != nil {
+		t.Fatalf("GetGroupingStats error: %v", err)
+	}
+	want := []store.GroupingStats{{Day: "2026-10-14", ParentsCreated: 1}, {Day: "2026-10-15", ParentsCreated: 1, ChildrenGrouped: 2}}
+	if len(stats) != len(want) || *stats[0] != want[0] || *stats[1] != want[1] {
+		t.Errorf("grouping stats = %+v, want %+v", stats, want)
+	}
+
+	// Days older than the retention are dropped
+	if stats, _ := s.GetGroupingStats(ctx, "rule-1", []string{"2026-09-01"}); len(stats) != 0 {
+		t.Errorf("grouping stats of an expired day = %+v, want none", stats)
+	}
+}
+
+func TestStateStore_EventStatus(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC))
+	s := NewStateStoreWithClock(clk)
+	ctx := context.Background()
+
+	_ = s.SetEventStatus(ctx, &domain.EventStatus{EventID: "event-1", State: domain.EventQueued}, time.Hour)
+	clk.Advance(30 * time.Minute)
+	_ = s.SetEventStatus(ctx, &domain.EventStatus{EventID: "event-1", State: domain.EventAlertCreated, DedupKey: "alert-1"}, time.Hour)
+
+	// Updates replace the status and restart its TTL
+	clk.Advance(45 * time.Minute)
+	status, err := s.GetEventStatus(ctx, "event-1")
+	if err != nil {
+		t.Fatalf("GetEventStatus error: %v", err)
+	}
+	if status == nil || status.State != domain.EventAlertCreated || status.DedupKey != "alert-1" {
+		t.Errorf("event status = %+v, want alert_created for alert-1", status)
+	}
+
+	clk.Advance(15 * time.Minute)
+	if status, _ := s.GetEventStatus(ctx, "event-1"); status != nil {
+		t.Errorf("expired event status = %+v, want nil", status)
+	}
+	if status, _ := s.GetEventStatus(ctx, "event-2"); status != nil {
+		t.Errorf("unknown event status = %+v, want nil", status)
+	}
+}
+
 func TestStateStore_Clear(t *testing.T) {
 	s := NewStateStore()
 	ctx := context.Background()
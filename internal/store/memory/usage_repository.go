@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"argus-go/internal/domain"
+)
+
+// UsageRepository is an in-memory implementation of store.UsageRepository.
+type UsageRepository struct {
+	mu sync.RWMutex
+
+	// usage stores the usage of each event manager by day
+	usage map[string]map[string]*domain.Usage
+}
+
+// NewUsageRepository creates a new in-memory usage repository.
+func NewUsageRepository() *UsageRepository {
+	return &UsageRepository{
+		usage: make(map[string]map[string]*domain.Usage),
+	}
+}
+
+// Add adds the counts of a usage to those already stored for its event
+// manager and day.
+func (r *UsageRepository) Add(ctx context.Context, usage *domain.Usage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	byDay, ok := r.usage[usage.EventManagerID]
+	if !ok {
+		byDay = make(map[string]*domain.Usage)
+		r.usage[usage.EventManagerID] = byDay
+	}
+	stored, ok := byDay[usage.Day]
+	if !ok {
+		stored = &domain.Usage{EventManagerID: usage.EventManagerID, Day: usage.Day}
+		byDay[usage.Day] = stored
+	}
+	stored.Add(usage)
+	return nil
+}
+
+// List retrieves the usage per event manager per day of the days within the
+// filter range, ordered by event manager and day.
+func (r *UsageRepository) List(ctx context.Context, filter domain.ReportFilter) ([]*domain.Usage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	first, last := domain.UsageDays(filter)
+	results := []*domain.Usage{}
+	for emID, byDay := range r.usage {
+		if filter.EventManagerID != "" && emID != filter.EventManagerID {
+			continue
+		}
+		for day, usage := range byDay {
+			if day < first || day > last {
+				continue
+			}
+			usageCopy := *usage
+			results = append(results, &usageCopy)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].EventManagerID != results[j].EventManagerID {
+			return results[i].EventManagerID < results[j].EventManagerID
+		}
+		return results[i].Day < results[j].Day
+	})
+	return results, nil
+}
+
+// DeleteByEventManager removes the usage of an event manager, and returns
+// the number of days removed.
+func (r *UsageRepository) DeleteByEventManager(ctx context.Context, eventManagerID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := len(r.usage[eventManagerID])
+	delete(r.usage, eventManagerID)
+	return deleted, nil
+}
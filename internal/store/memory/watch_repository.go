@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"argus-go/internal/domain"
+)
+
+// WatchRepository is an in-memory implementation of store.WatchRepository.
+type WatchRepository struct {
+	mu sync.RWMutex
+
+	// watches stores all watches by their ID
+	watches map[string]*domain.Watch
+}
+
+// NewWatchRepository creates a new in-memory watch repository.
+func NewWatchRepository() *WatchRepository {
+	return &WatchRepository{
+		watches: make(map[string]*domain.Watch),
+	}
+}
+
+// Create stores a new watch.
+func (r *WatchRepository) Create(ctx context.Context, watch *domain.Watch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Store a copy
+	watchCopy := *watch
+	r.watches[watch.ID] = &watchCopy
+	return nil
+}
+
+// Delete permanently removes a watch by ID.
+func (r *WatchRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.watches[id]; !exists {
+		return domain.ErrWatchNotFound
+	}
+
+	delete(r.watches, id)
+	return nil
+}
+
+// GetByID retrieves a watch by its ID.
+func (r *WatchRepository) GetByID(ctx context.Context, id string) (*domain.Watch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	watch, exists := r.watches[id]
+	if !exists {
+		return nil, domain.ErrWatchNotFound
+	}
+
+	// Return a copy
+	result := *watch
+	return &result, nil
+}
+
+// List retrieves every watch, oldest first.
+func (r *WatchRepository) List(ctx context.Context) ([]*domain.Watch, error) {
+	return r.list(""), nil
+}
+
+// ListByUser retrieves the watches of a user, oldest first.
+func (r *WatchRepository) ListByUser(ctx context.Context, user string) ([]*domain.Watch, error) {
+	return r.list(user), nil
+}
+
+// list returns copies of the watches of user, or of everyone if user is
+// empty, oldest first.
+func (r *WatchRepository) list(user string) []*domain.Watch {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	results := make([]*domain.Watch, 0, len(r.watches))
+	for _, watch := range r.watches {
+		if user != "" && watch.User != user {
+			continue
+		}
+		watchCopy := *watch
+		results = append(results, &watchCopy)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if !results[i].CreatedAt.Equal(results[j].CreatedAt) {
+			return results[i].CreatedAt.Before(results[j].CreatedAt)
+		}
+		return results[i].ID < results[j].ID
+	})
+	return results
+}
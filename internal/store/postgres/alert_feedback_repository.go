@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"argus-go/internal/domain"
+)
+
+// AlertFeedbackRepository implements store.AlertFeedbackRepository using PostgreSQL.
+type AlertFeedbackRepository struct {
+	db *DB
+}
+
+// NewAlertFeedbackRepository creates a new PostgreSQL-backed alert feedback repository.
+func NewAlertFeedbackRepository(db *DB) *AlertFeedbackRepository {
+	return &AlertFeedbackRepository{db: db}
+}
+
+// alertFeedbackColumns are the columns scanned by scanAlertFeedback.
+const alertFeedbackColumns = `dedup_key, event_manager_id, grouping_rule_id, rating, comment, author, created_at`
+
+// Record appends feedback on an alert.
+func (r *AlertFeedbackRepository) Record(ctx context.Context, feedback *domain.AlertFeedback) error {
+	query := `
+		INSERT INTO alert_feedback (` + alertFeedbackColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.pool.Exec(ctx, query,
+		feedback.DedupKey,
+		feedback.EventManagerID,
+		feedback.GroupingRuleID,
+		feedback.Rating,
+		feedback.Comment,
+		feedback.Author,
+		feedback.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to record alert feedback: %w", err)
+	}
+
+	return nil
+}
+
+// ListByDedupKey retrieves the feedback on an alert, oldest first.
+func (r *AlertFeedbackRepository) ListByDedupKey(ctx context.Context, dedupKey string) ([]*domain.AlertFeedback, error) {
+	query := `
+		SELECT ` + alertFeedbackColumns + `
+		FROM alert_feedback
+		WHERE dedup_key = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, dedupKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert feedback: %w", err)
+	}
+	return scanAlertFeedback(rows)
+}
+
+// List retrieves the feedback given within the range of the filter, of its
+// event manager if set, oldest first.
+func (r *AlertFeedbackRepository) List(ctx context.Context, filter domain.ReportFilter) ([]*domain.AlertFeedback, error) {
+	query := `
+		SELECT ` + alertFeedbackColumns + `
+		FROM alert_feedback
+		WHERE created_at >= $1 AND created_at < $2
+		  AND ($3 = '' OR event_manager_id = $3)
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, filter.From, filter.To, filter.EventManagerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert feedback: %w", err)
+	}
+	return scanAlertFeedback(rows)
+}
+
+// scanAlertFeedback reads rows of alertFeedbackColumns, and closes them.
+func scanAlertFeedback(rows pgx.Rows) ([]*domain.AlertFeedback, error) {
+	defer rows.Close()
+
+	feedback := []*domain.AlertFeedback{}
+
+	for rows.Next() {
+		var f domain.AlertFeedback
+		if err := rows.Scan(
+			&f.DedupKey,
+			&f.EventManagerID,
+			&f.GroupingRuleID,
+			&f.Rating,
+			&f.Comment,
+			&f.Author,
+			&f.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan alert feedback: %w", err)
+		}
+		feedback = append(feedback, &f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alert feedback: %w", err)
+	}
+
+	return feedback, nil
+}
+
+// DeleteByEventManager removes the feedback on the alerts of an event manager.
+func (r *AlertFeedbackRepository) DeleteByEventManager(ctx context.Context, eventManagerID string) (int, error) {
+	result, err := r.db.pool.Exec(ctx, `DELETE FROM alert_feedback WHERE event_manager_id = $1`, eventManagerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete alert feedback: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
@@ -4,12 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 
 	"argus-go/internal/domain"
 )
 
+// alertColumns is the column list selected for every alert query.
+// The order must match scanAlert.
+const alertColumns = `id, dedup_key, event_manager_id, summary, severity, class,
+			   type, status, parent_dedup_key, child_count, resolve_requested,
+			   trigger_count, resolve_count, acknowledged_at, created_at, updated_at, resolved_at,
+			   original_dedup_key, subscriber_ids, resolution, runbook, sampled_events,
+			   episode, reactivated_at, episodes, origin, tags, event_summary, service, components, links, assignee`
+
 // AlertRepository implements store.AlertRepository using PostgreSQL.
 type AlertRepository struct {
 	db *DB
@@ -20,17 +30,37 @@ func NewAlertRepository(db *DB) *AlertRepository {
 	return &AlertRepository{db: db}
 }
 
-// Create stores a new alert.
+// Create stores a new alert. Returns domain.ErrAlertAlreadyExists if an
+// alert with the same dedup key exists, in whichever partition.
 func (r *AlertRepository) Create(ctx context.Context, alert *domain.Alert) error {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create alert: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `INSERT INTO alert_dedup_keys (dedup_key, id, created_at) VALUES ($1, $2, $3)`,
+		alert.DedupKey, alert.ID, alert.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return domain.ErrAlertAlreadyExists
+		}
+		return fmt.Errorf("failed to create alert: %w", err)
+	}
+
 	query := `
 		INSERT INTO alerts (
 			id, dedup_key, event_manager_id, summary, severity, class,
 			type, status, parent_dedup_key, child_count, resolve_requested,
-			created_at, updated_at, resolved_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			trigger_count, resolve_count, acknowledged_at, created_at, updated_at, resolved_at,
+			original_dedup_key, subscriber_ids, resolution, runbook, sampled_events,
+			episode, reactivated_at, episodes, origin, tags, event_summary, service, components, links, assignee
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22,
+			$23, $24, $25, $26, $27, $28, $29, $30, $31, $32)
 	`
 
-	_, err := r.db.pool.Exec(ctx, query,
+	_, err = tx.Exec(ctx, query,
 		alert.ID,
 		alert.DedupKey,
 		alert.EventManagerID,
@@ -42,20 +72,80 @@ func (r *AlertRepository) Create(ctx context.Context, alert *domain.Alert) error
 		nullableString(alert.ParentDedupKey),
 		alert.ChildCount,
 		alert.ResolveRequested,
+		alert.TriggerCount,
+		alert.ResolveCount,
+		alert.AcknowledgedAt,
 		alert.CreatedAt,
 		alert.UpdatedAt,
 		alert.ResolvedAt,
+		nullableString(alert.OriginalDedupKey),
+		subscriberIDs(alert),
+		alert.Resolution,
+		alert.Runbook,
+		alert.SampledEvents,
+		alert.Episode,
+		alert.ReactivatedAt,
+		alert.Episodes,
+		alert.Origin,
+		alert.Tags,
+		alert.EventSummary,
+		alert.Service,
+		components(alert),
+		alert.Links,
+		alert.Assignee,
 	)
-
 	if err != nil {
 		return fmt.Errorf("failed to create alert: %w", err)
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to create alert: %w", err)
+	}
+
 	return nil
 }
 
 // Update modifies an existing alert.
 func (r *AlertRepository) Update(ctx context.Context, alert *domain.Alert) error {
+	return updateAlert(ctx, r.db.pool, alert, nil)
+}
+
+// UpdateIfUnmodified modifies an existing alert unless it was updated since
+// updatedAt.
+func (r *AlertRepository) UpdateIfUnmodified(ctx context.Context, alert *domain.Alert, updatedAt time.Time) error {
+	return updateAlert(ctx, r.db.pool, alert, &updatedAt)
+}
+
+// UpdateAll modifies several existing alerts in one transaction.
+func (r *AlertRepository) UpdateAll(ctx context.Context, alerts []*domain.Alert) error {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update alerts: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for _, alert := range alerts {
+		if err := updateAlert(ctx, tx, alert, nil); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to update alerts: %w", err)
+	}
+
+	return nil
+}
+
+// execer is implemented by both the connection pool and transactions.
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// updateAlert writes the mutable fields of an alert through db. If
+// unmodifiedSince is set, the alert is only written if its updated_at still
+// equals it, and domain.ErrAlertModified is returned otherwise.
+func updateAlert(ctx context.Context, db execer, alert *domain.Alert, unmodifiedSince *time.Time) error {
 	query := `
 		UPDATE alerts SET
 			summary = $2,
@@ -64,12 +154,32 @@ func (r *AlertRepository) Update(ctx context.Context, alert *domain.Alert) error
 			status = $5,
 			child_count = $6,
 			resolve_requested = $7,
-			updated_at = $8,
-			resolved_at = $9
-		WHERE id = $1
+			trigger_count = $8,
+			resolve_count = $9,
+			acknowledged_at = $10,
+			updated_at = $11,
+			resolved_at = $12,
+			subscriber_ids = $13,
+			resolution = $14,
+			episode = $15,
+			reactivated_at = $16,
+			episodes = $17,
+			origin = $18,
+			tags = $19,
+			event_summary = $20,
+			links = $21,
+			assignee = $22,
+			runbook = $23
+		WHERE id = $1 AND created_at >= $24 AND created_at < $25
+			AND ($26::timestamptz IS NULL OR updated_at = $26)
 	`
 
-	result, err := r.db.pool.Exec(ctx, query,
+	// Bounding created_at limits the update to the alert's partition. Postgres
+	// stores microseconds, so the bounds allow for rounding.
+	createdFrom := alert.CreatedAt.Add(-time.Microsecond)
+	createdTo := alert.CreatedAt.Add(time.Microsecond)
+
+	result, err := db.Exec(ctx, query,
 		alert.ID,
 		alert.Summary,
 		alert.Severity,
@@ -77,8 +187,25 @@ func (r *AlertRepository) Update(ctx context.Context, alert *domain.Alert) error
 		alert.Status,
 		alert.ChildCount,
 		alert.ResolveRequested,
+		alert.TriggerCount,
+		alert.ResolveCount,
+		alert.AcknowledgedAt,
 		alert.UpdatedAt,
 		alert.ResolvedAt,
+		subscriberIDs(alert),
+		alert.Resolution,
+		alert.Episode,
+		alert.ReactivatedAt,
+		alert.Episodes,
+		alert.Origin,
+		alert.Tags,
+		alert.EventSummary,
+		alert.Links,
+		alert.Assignee,
+		alert.Runbook,
+		createdFrom,
+		createdTo,
+		unmodifiedSince,
 	)
 
 	if err != nil {
@@ -86,6 +213,9 @@ func (r *AlertRepository) Update(ctx context.Context, alert *domain.Alert) error
 	}
 
 	if result.RowsAffected() == 0 {
+		if unmodifiedSince != nil {
+			return domain.ErrAlertModified
+		}
 		return domain.ErrAlertNotFound
 	}
 
@@ -104,15 +234,7 @@ func (r *AlertRepository) GetByDedupKey(ctx context.Context, dedupKey string) (*
 
 // getOne retrieves a single alert matching the given condition.
 func (r *AlertRepository) getOne(ctx context.Context, condition string, args ...interface{}) (*domain.Alert, error) {
-	query := fmt.Sprintf(`
-		SELECT id, dedup_key, event_manager_id, summary, severity, class,
-			   type, status, parent_dedup_key, child_count, resolve_requested,
-			   created_at, updated_at, resolved_at
-		FROM alerts
-		WHERE %s
-	`, condition)
-
-	row := r.db.pool.QueryRow(ctx, query, args...)
+	row := r.db.pool.QueryRow(ctx, alertQuery(condition), args...)
 
 	alert, err := scanAlert(row)
 	if err != nil {
@@ -125,64 +247,152 @@ func (r *AlertRepository) getOne(ctx context.Context, condition string, args ...
 	return alert, nil
 }
 
+// alertQuery returns the query selecting the alerts matching condition.
+func alertQuery(condition string) string {
+	return fmt.Sprintf(`
+		SELECT `+alertColumns+`
+		FROM alerts
+		WHERE %s
+	`, condition)
+}
+
 // List retrieves alerts matching the filter criteria.
 func (r *AlertRepository) List(ctx context.Context, filter domain.AlertFilter) ([]*domain.Alert, error) {
+	conditions, args := alertConditions(filter)
 	query := `
-		SELECT id, dedup_key, event_manager_id, summary, severity, class,
-			   type, status, parent_dedup_key, child_count, resolve_requested,
-			   created_at, updated_at, resolved_at
+		SELECT ` + alertColumns + `
 		FROM alerts
-		WHERE 1=1
-	`
-	args := []interface{}{}
+		WHERE 1=1` + conditions
+
+	switch filter.Sort {
+	case domain.AlertSortOldest:
+		query += " ORDER BY created_at ASC, id ASC"
+	case domain.AlertSortRecentlyUpdated:
+		query += " ORDER BY updated_at DESC, created_at DESC, id DESC"
+	case domain.AlertSortLeastRecentlyUpdated:
+		query += " ORDER BY updated_at ASC, created_at DESC, id DESC"
+	case domain.AlertSortMostChildren:
+		query += " ORDER BY child_count DESC, created_at DESC, id DESC"
+	case domain.AlertSortFewestChildren:
+		query += " ORDER BY child_count ASC, created_at DESC, id DESC"
+	case domain.AlertSortMostSevere, domain.AlertSortLeastSevere:
+		// Severities are ranked by their position on the scale; those not on
+		// it rank below its least severe level
+		var levels []string
+		for _, level := range domain.CurrentSeverityScale().Levels() {
+			levels = append(levels, string(level))
+		}
+		direction := "ASC"
+		if filter.Sort == domain.AlertSortLeastSevere {
+			direction = "DESC"
+		}
+		query += fmt.Sprintf(" ORDER BY COALESCE(array_position($%d::text[], severity::text), $%d) %s, created_at DESC, id DESC",
+			len(args)+1, len(args)+2, direction)
+		args = append(args, levels, len(levels)+1)
+	default:
+		query += " ORDER BY created_at DESC, id DESC"
+	}
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	rows, err := r.db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAlerts(rows)
+}
+
+// Count returns the number of alerts matching the filter criteria.
+func (r *AlertRepository) Count(ctx context.Context, filter domain.AlertFilter) (int, error) {
+	conditions, args := alertConditions(filter)
+
+	var count int
+	if err := r.db.pool.QueryRow(ctx, "SELECT COUNT(*) FROM alerts WHERE 1=1"+conditions, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count alerts: %w", err)
+	}
+	return count, nil
+}
+
+// alertConditions returns the conditions of a query for the alerts matching
+// the filter criteria, each starting with AND, and their arguments.
+func alertConditions(filter domain.AlertFilter) (string, []any) {
+	query := ""
+	args := []any{}
 	argNum := 1
 
 	if filter.EventManagerID != "" {
-		query += fmt.Sprintf(" AND event_manager_id = $%d", argNum)
+		if filter.IncludeSubscribed {
+			query += fmt.Sprintf(" AND (event_manager_id = $%d OR $%d = ANY(subscriber_ids))", argNum, argNum)
+		} else {
+			query += fmt.Sprintf(" AND event_manager_id = $%d", argNum)
+		}
 		args = append(args, filter.EventManagerID)
 		argNum++
 	}
 
-	if filter.Status != "" {
-		query += fmt.Sprintf(" AND status = $%d", argNum)
-		args = append(args, filter.Status)
+	if filter.ParentDedupKey != "" {
+		query += fmt.Sprintf(" AND parent_dedup_key = $%d", argNum)
+		args = append(args, filter.ParentDedupKey)
 		argNum++
 	}
 
+	if filter.Status != "" {
+		condition, conditionArgs := statusCondition(filter.Status, filter.Muting, argNum)
+		query += condition
+		args = append(args, conditionArgs...)
+		argNum += len(conditionArgs)
+	}
+
 	if filter.Type != "" {
 		query += fmt.Sprintf(" AND type = $%d", argNum)
 		args = append(args, filter.Type)
 		argNum++
 	}
 
-	query += " ORDER BY created_at DESC"
+	if filter.ResolveRequested {
+		query += " AND resolve_requested"
+	}
 
-	if filter.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT $%d", argNum)
-		args = append(args, filter.Limit)
+	if filter.Severity != "" {
+		query += fmt.Sprintf(" AND severity = $%d", argNum)
+		args = append(args, filter.Severity)
 		argNum++
 	}
 
-	if filter.Offset > 0 {
-		query += fmt.Sprintf(" OFFSET $%d", argNum)
-		args = append(args, filter.Offset)
+	if filter.Service != "" {
+		query += fmt.Sprintf(" AND service = $%d", argNum)
+		args = append(args, filter.Service)
+		argNum++
 	}
 
-	rows, err := r.db.pool.Query(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	if filter.Component != "" {
+		query += fmt.Sprintf(" AND components @> ARRAY[$%d::text]", argNum)
+		args = append(args, filter.Component)
+		argNum++
 	}
-	defer rows.Close()
 
-	return scanAlerts(rows)
+	if filter.Query != "" {
+		query += fmt.Sprintf(" AND summary_search @@ websearch_to_tsquery('english', $%d)", argNum)
+		args = append(args, filter.Query)
+	}
+
+	return query, args
 }
 
 // GetChildrenByParent retrieves all child alerts for a given parent dedup key.
 func (r *AlertRepository) GetChildrenByParent(ctx context.Context, parentDedupKey string) ([]*domain.Alert, error) {
 	query := `
-		SELECT id, dedup_key, event_manager_id, summary, severity, class,
-			   type, status, parent_dedup_key, child_count, resolve_requested,
-			   created_at, updated_at, resolved_at
+		SELECT ` + alertColumns + `
 		FROM alerts
 		WHERE parent_dedup_key = $1
 		ORDER BY created_at DESC
@@ -199,26 +409,310 @@ func (r *AlertRepository) GetChildrenByParent(ctx context.Context, parentDedupKe
 
 // CountActiveChildren returns the count of active child alerts for a parent.
 func (r *AlertRepository) CountActiveChildren(ctx context.Context, parentDedupKey string) (int, error) {
-	query := `
+	var count int
+	err := r.db.pool.QueryRow(ctx, countActiveChildrenQuery, parentDedupKey).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count active children: %w", err)
+	}
+
+	return count, nil
+}
+
+// countActiveChildrenQuery counts the active children of a parent alert.
+const countActiveChildrenQuery = `
 		SELECT COUNT(*) FROM alerts
 		WHERE parent_dedup_key = $1 AND status = 'active'
 	`
 
-	var count int
-	err := r.db.pool.QueryRow(ctx, query, parentDedupKey).Scan(&count)
+// CountActive returns the number of active alerts per event manager, type,
+// severity and class.
+func (r *AlertRepository) CountActive(ctx context.Context) ([]*domain.ActiveAlertCount, error) {
+	query := `
+		SELECT event_manager_id, type, severity, class, COUNT(*) FROM alerts
+		WHERE status = 'active'
+		GROUP BY event_manager_id, type, severity, class
+		ORDER BY event_manager_id, type, severity, class
+	`
+
+	rows, err := r.db.pool.Query(ctx, query)
 	if err != nil {
-		return 0, fmt.Errorf("failed to count active children: %w", err)
+		return nil, fmt.Errorf("failed to count active alerts: %w", err)
 	}
+	defer rows.Close()
 
-	return count, nil
+	var counts []*domain.ActiveAlertCount
+	for rows.Next() {
+		count := &domain.ActiveAlertCount{}
+		if err := rows.Scan(&count.EventManagerID, &count.Type, &count.Severity, &count.Class, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan active alert count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count active alerts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// IncrementTriggerCount records an additional trigger event for an existing alert.
+func (r *AlertRepository) IncrementTriggerCount(ctx context.Context, dedupKey string) error {
+	query := `
+		UPDATE alerts SET
+			trigger_count = trigger_count + 1,
+			updated_at = NOW()
+		WHERE dedup_key = $1
+	`
+
+	result, err := r.db.pool.Exec(ctx, query, dedupKey)
+	if err != nil {
+		return fmt.Errorf("failed to increment trigger count: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrAlertNotFound
+	}
+
+	return nil
+}
+
+// AddSampledEvents records trigger events dropped by sampling for an existing
+// alert, counting them as triggers too.
+func (r *AlertRepository) AddSampledEvents(ctx context.Context, dedupKey string, count int) error {
+	query := `
+		UPDATE alerts SET
+			trigger_count = trigger_count + $2,
+			sampled_events = sampled_events + $2,
+			updated_at = NOW()
+		WHERE dedup_key = $1
+	`
+
+	result, err := r.db.pool.Exec(ctx, query, dedupKey, count)
+	if err != nil {
+		return fmt.Errorf("failed to add sampled events: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrAlertNotFound
+	}
+
+	return nil
+}
+
+// MergeTags merges tags onto the tags of an existing alert. Concatenating
+// JSONB objects keeps the values of the right one for keys in both.
+func (r *AlertRepository) MergeTags(ctx context.Context, dedupKey string, tags map[string]string, replace bool) error {
+	query := `
+		UPDATE alerts SET
+			tags = CASE WHEN $3 THEN COALESCE(tags, '{}') || $2::jsonb ELSE $2::jsonb || COALESCE(tags, '{}') END,
+			updated_at = NOW()
+		WHERE dedup_key = $1
+	`
+
+	result, err := r.db.pool.Exec(ctx, query, dedupKey, tags, replace)
+	if err != nil {
+		return fmt.Errorf("failed to merge tags: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrAlertNotFound
+	}
+
+	return nil
+}
+
+// UpdateLinks removes links from an existing alert, then adds links. The
+// alert is locked while its links are merged, so concurrent updates are not
+// lost.
+func (r *AlertRepository) UpdateLinks(ctx context.Context, dedupKey string, add []domain.AlertLink, remove []string) error {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to update links: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var links []domain.AlertLink
+	err = tx.QueryRow(ctx, `SELECT links FROM alerts WHERE dedup_key = $1 FOR UPDATE`, dedupKey).Scan(&links)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrAlertNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update links: %w", err)
+	}
+
+	links = domain.MergeLinks(domain.RemoveLinks(links, remove), add)
+	if _, err := tx.Exec(ctx, `UPDATE alerts SET links = $2, updated_at = NOW() WHERE dedup_key = $1`, dedupKey, links); err != nil {
+		return fmt.Errorf("failed to update links: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to update links: %w", err)
+	}
+
+	return nil
+}
+
+// History returns every revision of an alert, oldest first.
+// Revisions are recorded by the alerts_record_revision trigger.
+func (r *AlertRepository) History(ctx context.Context, dedupKey string) ([]*domain.AlertRevision, error) {
+	query := `
+		SELECT revision, recorded_at, ` + alertColumns + `
+		FROM alerts_history
+		WHERE dedup_key = $1
+		ORDER BY revision
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, dedupKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert history: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []*domain.AlertRevision{}
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert revision: %w", err)
+		}
+		revisions = append(revisions, rev)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alert history: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// RevisionAt returns the revision of an alert that was current at the given time.
+func (r *AlertRepository) RevisionAt(ctx context.Context, dedupKey string, at time.Time) (*domain.AlertRevision, error) {
+	query := `
+		SELECT revision, recorded_at, ` + alertColumns + `
+		FROM alerts_history
+		WHERE dedup_key = $1 AND recorded_at <= $2
+		ORDER BY revision DESC
+		LIMIT 1
+	`
+
+	rev, err := scanRevision(r.db.pool.QueryRow(ctx, query, dedupKey, at))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrAlertNotFound
+		}
+		return nil, fmt.Errorf("failed to get alert revision: %w", err)
+	}
+
+	return rev, nil
+}
+
+// PurgeByEventManager permanently removes all alerts of an event manager,
+// including their history.
+func (r *AlertRepository) PurgeByEventManager(ctx context.Context, eventManagerID string) (int, error) {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge alerts: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM alert_dedup_keys k USING alerts a
+		WHERE k.dedup_key = a.dedup_key AND a.event_manager_id = $1
+	`, eventManagerID); err != nil {
+		return 0, fmt.Errorf("failed to purge alerts: %w", err)
+	}
+
+	result, err := tx.Exec(ctx, `DELETE FROM alerts WHERE event_manager_id = $1`, eventManagerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge alerts: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM alerts_history WHERE event_manager_id = $1`, eventManagerID); err != nil {
+		return 0, fmt.Errorf("failed to purge alert history: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to purge alerts: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// Delete permanently removes an alert, including its history.
+func (r *AlertRepository) Delete(ctx context.Context, dedupKey string) error {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	result, err := tx.Exec(ctx, `DELETE FROM alerts WHERE dedup_key = $1`, dedupKey)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrAlertNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM alert_dedup_keys WHERE dedup_key = $1`, dedupKey); err != nil {
+		return fmt.Errorf("failed to delete alert: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM alerts_history WHERE dedup_key = $1`, dedupKey); err != nil {
+		return fmt.Errorf("failed to delete alert history: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to delete alert: %w", err)
+	}
+
+	return nil
+}
+
+// scanRevision scans a single alerts_history row into an AlertRevision.
+func scanRevision(row pgx.Row) (*domain.AlertRevision, error) {
+	var rev domain.AlertRevision
+	var parentDedupKey, originalDedupKey *string
+
+	dest := append([]any{&rev.Revision, &rev.RecordedAt}, alertScanTargets(&rev.Alert, &parentDedupKey, &originalDedupKey)...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	if parentDedupKey != nil {
+		rev.Alert.ParentDedupKey = *parentDedupKey
+	}
+	if originalDedupKey != nil {
+		rev.Alert.OriginalDedupKey = *originalDedupKey
+	}
+
+	return &rev, nil
 }
 
 // scanAlert scans a single row into an Alert.
 func scanAlert(row pgx.Row) (*domain.Alert, error) {
 	var alert domain.Alert
-	var parentDedupKey *string
+	var parentDedupKey, originalDedupKey *string
+
+	err := row.Scan(alertScanTargets(&alert, &parentDedupKey, &originalDedupKey)...)
 
-	err := row.Scan(
+	if err != nil {
+		return nil, err
+	}
+
+	if parentDedupKey != nil {
+		alert.ParentDedupKey = *parentDedupKey
+	}
+	if originalDedupKey != nil {
+		alert.OriginalDedupKey = *originalDedupKey
+	}
+
+	return &alert, nil
+}
+
+// alertScanTargets returns the scan destinations for alertColumns.
+// Nullable text columns are scanned into the string pointers and copied by the caller.
+func alertScanTargets(alert *domain.Alert, parentDedupKey, originalDedupKey **string) []any {
+	return []any{
 		&alert.ID,
 		&alert.DedupKey,
 		&alert.EventManagerID,
@@ -227,23 +721,31 @@ func scanAlert(row pgx.Row) (*domain.Alert, error) {
 		&alert.Class,
 		&alert.Type,
 		&alert.Status,
-		&parentDedupKey,
+		parentDedupKey,
 		&alert.ChildCount,
 		&alert.ResolveRequested,
+		&alert.TriggerCount,
+		&alert.ResolveCount,
+		&alert.AcknowledgedAt,
 		&alert.CreatedAt,
 		&alert.UpdatedAt,
 		&alert.ResolvedAt,
-	)
-
-	if err != nil {
-		return nil, err
-	}
-
-	if parentDedupKey != nil {
-		alert.ParentDedupKey = *parentDedupKey
+		originalDedupKey,
+		&alert.SubscriberIDs,
+		&alert.Resolution,
+		&alert.Runbook,
+		&alert.SampledEvents,
+		&alert.Episode,
+		&alert.ReactivatedAt,
+		&alert.Episodes,
+		&alert.Origin,
+		&alert.Tags,
+		&alert.EventSummary,
+		&alert.Service,
+		&alert.Components,
+		&alert.Links,
+		&alert.Assignee,
 	}
-
-	return &alert, nil
 }
 
 // scanAlerts scans multiple rows into a slice of Alerts.
@@ -251,35 +753,11 @@ func scanAlerts(rows pgx.Rows) ([]*domain.Alert, error) {
 	var alerts []*domain.Alert
 
 	for rows.Next() {
-		var alert domain.Alert
-		var parentDedupKey *string
-
-		err := rows.Scan(
-			&alert.ID,
-			&alert.DedupKey,
-			&alert.EventManagerID,
-			&alert.Summary,
-			&alert.Severity,
-			&alert.Class,
-			&alert.Type,
-			&alert.Status,
-			&parentDedupKey,
-			&alert.ChildCount,
-			&alert.ResolveRequested,
-			&alert.CreatedAt,
-			&alert.UpdatedAt,
-			&alert.ResolvedAt,
-		)
-
+		alert, err := scanAlert(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan alert: %w", err)
 		}
-
-		if parentDedupKey != nil {
-			alert.ParentDedupKey = *parentDedupKey
-		}
-
-		alerts = append(alerts, &alert)
+		alerts = append(alerts, alert)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -289,6 +767,24 @@ func scanAlerts(rows pgx.Rows) ([]*domain.Alert, error) {
 	return alerts, nil
 }
 
+// subscriberIDs returns the subscribers of an alert for the NOT NULL
+// subscriber_ids column, which rejects a nil slice.
+func subscriberIDs(alert *domain.Alert) []string {
+	if alert.SubscriberIDs == nil {
+		return []string{}
+	}
+	return alert.SubscriberIDs
+}
+
+// components returns the components of an alert for the NOT NULL
+// components column, which rejects a nil slice.
+func components(alert *domain.Alert) []string {
+	if alert.Components == nil {
+		return []string{}
+	}
+	return alert.Components
+}
+
 // nullableString returns nil if the string is empty, otherwise returns a pointer to it.
 func nullableString(s string) *string {
 	if s == "" {
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"argus-go/internal/domain"
+)
+
+// newTestAlert returns an active parent alert created at created.
+func newTestAlert(dedupKey string, created time.Time) *domain.Alert {
+	return &domain.Alert{
+		ID:             uuid.New().String(),
+		DedupKey:       dedupKey,
+		EventManagerID: "em-1",
+		Summary:        "Disk full",
+		Severity:       domain.SeverityHigh,
+		Class:          "database",
+		Type:           domain.AlertTypeParent,
+		Status:         domain.AlertStatusActive,
+		TriggerCount:   1,
+		Episode:        1,
+		CreatedAt:      created,
+		UpdatedAt:      created,
+	}
+}
+
+func TestAlertRepository_CreateDuplicate(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewAlertRepository(db)
+	ctx := context.Background()
+
+	// Alerts of different months live in different partitions, yet their
+	// dedup keys are unique
+	first := newTestAlert("dup-1", monthsAgo(0))
+	if err := repo.Create(ctx, first); err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	if err := repo.Create(ctx, newTestAlert("dup-1", monthsAgo(3))); !errors.Is(err, domain.ErrAlertAlreadyExists) {
+		t.Fatalf("Create(duplicate) error = %v, want %v", err, domain.ErrAlertAlreadyExists)
+	}
+
+	alerts, err := repo.List(ctx, domain.AlertFilter{EventManagerID: "em-1"})
+	if err != nil {
+		t.Fatalf("List error: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].ID != first.ID {
+		t.Errorf("List() = %d alerts, want only the first", len(alerts))
+	}
+
+	// A deleted alert's dedup key can be used again
+	if err := repo.Delete(ctx, "dup-1"); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	if err := repo.Create(ctx, newTestAlert("dup-1", monthsAgo(0))); err != nil {
+		t.Errorf("Create(after delete) error: %v", err)
+	}
+
+	// So can those of purged event managers
+	if _, err := repo.PurgeByEventManager(ctx, "em-1"); err != nil {
+		t.Fatalf("PurgeByEventManager error: %v", err)
+	}
+	if err := repo.Create(ctx, newTestAlert("dup-1", monthsAgo(0))); err != nil {
+		t.Errorf("Create(after purge) error: %v", err)
+	}
+}
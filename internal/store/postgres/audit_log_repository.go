@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"argus-go/internal/domain"
+)
+
+// AuditLogRepository implements store.AuditLogRepository using PostgreSQL.
+type AuditLogRepository struct {
+	db *DB
+}
+
+// NewAuditLogRepository creates a new PostgreSQL-backed audit log.
+func NewAuditLogRepository(db *DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+// auditLogColumns lists the columns of an audit entry in scan order.
+const auditLogColumns = `at, actor, action, operation, target, approval_id, detail, sequence, prev_hash, hash`
+
+// Record chains an entry to the last one and appends it to the audit log.
+// The table is locked until the entry is written, so concurrent writers
+// never chain to the same entry.
+func (r *AuditLogRepository) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `LOCK TABLE audit_log IN EXCLUSIVE MODE`); err != nil {
+		return fmt.Errorf("failed to lock audit log: %w", err)
+	}
+
+	prev, err := scanAuditEntry(tx.QueryRow(ctx, `
+		SELECT `+auditLogColumns+`
+		FROM audit_log
+		WHERE sequence > 0
+		ORDER BY sequence DESC
+		LIMIT 1
+	`))
+	if errors.Is(err, pgx.ErrNoRows) {
+		prev = nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get last audit entry: %w", err)
+	}
+	entry.Chain(prev)
+
+	query := `
+		INSERT INTO audit_log (` + auditLogColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err = tx.Exec(ctx, query,
+		entry.At,
+		entry.Actor,
+		entry.Action,
+		entry.Operation,
+		entry.Target,
+		entry.ApprovalID,
+		entry.Detail,
+		entry.Sequence,
+		entry.PrevHash,
+		entry.Hash,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves the most recent entries, newest first, at most limit.
+func (r *AuditLogRepository) List(ctx context.Context, limit int) ([]*domain.AuditEntry, error) {
+	query := `
+		SELECT ` + auditLogColumns + `
+		FROM audit_log
+		ORDER BY at DESC, id DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+
+	return scanAuditEntries(rows)
+}
+
+// Export retrieves every entry, oldest first: the entries recorded before
+// the audit log was chained, then the chain in order.
+func (r *AuditLogRepository) Export(ctx context.Context) ([]*domain.AuditEntry, error) {
+	query := `
+		SELECT ` + auditLogColumns + `
+		FROM audit_log
+		ORDER BY sequence, id
+	`
+
+	rows, err := r.db.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export audit log: %w", err)
+	}
+
+	return scanAuditEntries(rows)
+}
+
+// scanAuditEntries reads the audit entries of rows and closes them.
+func scanAuditEntries(rows pgx.Rows) ([]*domain.AuditEntry, error) {
+	defer rows.Close()
+
+	entries := []*domain.AuditEntry{}
+
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// scanAuditEntry reads an audit entry selected with auditLogColumns.
+func scanAuditEntry(row pgx.Row) (*domain.AuditEntry, error) {
+	var entry domain.AuditEntry
+	if err := row.Scan(
+		&entry.At,
+		&entry.Actor,
+		&entry.Action,
+		&entry.Operation,
+		&entry.Target,
+		&entry.ApprovalID,
+		&entry.Detail,
+		&entry.Sequence,
+		&entry.PrevHash,
+		&entry.Hash,
+	); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"argus-go/internal/store"
+)
+
+// changeChannel is the NOTIFY channel of configuration changes.
+const changeChannel = "argus_config_changes"
+
+// Reconnect delays of a subscriber whose listening connection failed.
+const (
+	listenRetryBackoff    = time.Second
+	listenMaxRetryBackoff = 30 * time.Second
+)
+
+// ChangeBus implements store.ChangeBus with PostgreSQL LISTEN/NOTIFY, so every
+// replica connected to the database receives the changes.
+type ChangeBus struct {
+	db     *DB
+	logger *slog.Logger
+}
+
+// NewChangeBus creates a change bus on the database.
+func NewChangeBus(db *DB, logger *slog.Logger) *ChangeBus {
+	return &ChangeBus{db: db, logger: logger}
+}
+
+// Publish notifies every listening replica of a change.
+func (b *ChangeBus) Publish(ctx context.Context, change store.ConfigChange) error {
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("failed to encode config change: %w", err)
+	}
+	if _, err := b.db.pool.Exec(ctx, `SELECT pg_notify($1, $2)`, changeChannel, string(payload)); err != nil {
+		return fmt.Errorf("failed to publish config change: %w", err)
+	}
+	return nil
+}
+
+// Subscribe listens for changes on a dedicated connection until the context
+// is canceled. A failed connection is reestablished with backoff; since
+// changes may have been missed meanwhile, handler then receives a
+// store.ChangeAll change.
+func (b *ChangeBus) Subscribe(ctx context.Context, handler func(store.ConfigChange)) error {
+	backoff := listenRetryBackoff
+	resync := false
+	for {
+		listening, err := b.listen(ctx, handler, resync)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if listening {
+			backoff = listenRetryBackoff
+		}
+		b.logger.Warn("config change listener disconnected, reconnecting", "backoff", backoff, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, listenMaxRetryBackoff)
+		resync = true
+	}
+}
+
+// listen receives changes on one connection until it fails, and reports
+// whether it got to listening.
+func (b *ChangeBus) listen(ctx context.Context, handler func(store.ConfigChange), resync bool) (bool, error) {
+	conn, err := b.db.pool.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		// Don't return the connection to the pool still listening
+		_, _ = conn.Exec(context.Background(), "UNLISTEN "+changeChannel)
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+changeChannel); err != nil {
+		return false, err
+	}
+	if resync {
+		handler(store.ConfigChange{Kind: store.ChangeAll})
+	}
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+
+		var change store.ConfigChange
+		if err := json.Unmarshal([]byte(notification.Payload), &change); err != nil {
+			b.logger.Warn("ignoring malformed config change", "payload", notification.Payload, "error", err)
+			continue
+		}
+		handler(change)
+	}
+}
@@ -4,16 +4,32 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"argus-go/internal/config"
 )
 
+// preparedQueries are the queries the processor runs for every event. Once
+// the schema is migrated they are prepared on every new connection, named
+// after their text so queries use them without looking them up, and stay
+// prepared however many distinct queries, e.g. alert list filters, cycle
+// through the statement cache.
+var preparedQueries = []string{
+	alertQuery("dedup_key = $1"),
+	countActiveChildrenQuery,
+}
+
 // DB wraps a PostgreSQL connection pool.
 type DB struct {
 	pool *pgxpool.Pool
+
+	// migrated is set once RunMigrations has succeeded, so the tables of
+	// the prepared queries exist.
+	migrated atomic.Bool
 }
 
 // NewDB creates a new PostgreSQL connection pool.
@@ -38,6 +54,9 @@ func NewDB(ctx context.Context, cfg *config.PostgresConfig) (*DB, error) {
 	poolConfig.MinConns = cfg.MaxIdleConns
 	poolConfig.MaxConnLifetime = time.Hour
 
+	db := &DB{}
+	poolConfig.AfterConnect = db.prepare
+
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create postgres pool: %w", err)
@@ -49,7 +68,22 @@ func NewDB(ctx context.Context, cfg *config.PostgresConfig) (*DB, error) {
 		return nil, fmt.Errorf("failed to ping postgres: %w", err)
 	}
 
-	return &DB{pool: pool}, nil
+	db.pool = pool
+	return db, nil
+}
+
+// prepare prepares the queries run for every event on a new connection,
+// once the schema is migrated.
+func (db *DB) prepare(ctx context.Context, conn *pgx.Conn) error {
+	if !db.migrated.Load() {
+		return nil
+	}
+	for _, query := range preparedQueries {
+		if _, err := conn.Prepare(ctx, query, query); err != nil {
+			return fmt.Errorf("failed to prepare statement: %w", err)
+		}
+	}
+	return nil
 }
 
 // Pool returns the underlying connection pool.
@@ -57,6 +91,17 @@ func (db *DB) Pool() *pgxpool.Pool {
 	return db.pool
 }
 
+// Ping checks the connection to PostgreSQL. On failure the pool's idle
+// connections are dropped, so queries after an outage reconnect instead of
+// failing on connections that broke during it.
+func (db *DB) Ping(ctx context.Context) error {
+	if err := db.pool.Ping(ctx); err != nil {
+		db.pool.Reset()
+		return err
+	}
+	return nil
+}
+
 // Close closes the connection pool.
 func (db *DB) Close() {
 	if db.pool != nil {
@@ -67,9 +112,12 @@ func (db *DB) Close() {
 // RunMigrations creates the required database tables.
 func (db *DB) RunMigrations(ctx context.Context) error {
 	schema := `
+		-- Alerts are partitioned by month of creation. Primary and unique keys
+		-- must include the partition key, so dedup keys are kept unique by
+		-- alert_dedup_keys below.
 		CREATE TABLE IF NOT EXISTS alerts (
-			id VARCHAR(36) PRIMARY KEY,
-			dedup_key VARCHAR(255) UNIQUE NOT NULL,
+			id VARCHAR(36) NOT NULL,
+			dedup_key VARCHAR(255) NOT NULL,
 			event_manager_id VARCHAR(36) NOT NULL,
 			summary TEXT NOT NULL,
 			severity VARCHAR(20) NOT NULL,
@@ -81,13 +129,172 @@ func (db *DB) RunMigrations(ctx context.Context) error {
 			resolve_requested BOOLEAN DEFAULT FALSE,
 			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
 			updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
-			resolved_at TIMESTAMP WITH TIME ZONE
-		);
+			resolved_at TIMESTAMP WITH TIME ZONE,
+			PRIMARY KEY (id, created_at),
+			UNIQUE (dedup_key, created_at)
+		) PARTITION BY RANGE (created_at);
+
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS trigger_count INTEGER NOT NULL DEFAULT 1;
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS resolve_count INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS acknowledged_at TIMESTAMP WITH TIME ZONE;
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS original_dedup_key TEXT;
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS subscriber_ids TEXT[] NOT NULL DEFAULT '{}';
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS resolution JSONB;
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS runbook JSONB;
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS sampled_events INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS episode INTEGER NOT NULL DEFAULT 1;
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS reactivated_at TIMESTAMP WITH TIME ZONE;
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS episodes JSONB;
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS origin JSONB;
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS tags JSONB;
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS event_summary TEXT NOT NULL DEFAULT '';
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS service TEXT NOT NULL DEFAULT '';
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS components TEXT[] NOT NULL DEFAULT '{}';
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS links JSONB;
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS assignee TEXT NOT NULL DEFAULT '';
+
+		-- An alerts table from before partitioning is converted once: its rows
+		-- are copied into monthly partitions of a new partitioned table.
+		DO $$
+		DECLARE
+			partition_month TIMESTAMP;
+		BEGIN
+			IF EXISTS (SELECT 1 FROM pg_class WHERE oid = to_regclass('alerts') AND relkind = 'r') THEN
+				ALTER TABLE alerts RENAME TO alerts_unpartitioned;
+				ALTER TABLE alerts_unpartitioned RENAME CONSTRAINT alerts_pkey TO alerts_unpartitioned_pkey;
+				DROP TRIGGER IF EXISTS alerts_record_revision ON alerts_unpartitioned;
+
+				CREATE TABLE alerts (
+					LIKE alerts_unpartitioned INCLUDING DEFAULTS,
+					PRIMARY KEY (id, created_at),
+					UNIQUE (dedup_key, created_at)
+				) PARTITION BY RANGE (created_at);
+
+				FOR partition_month IN SELECT DISTINCT date_trunc('month', created_at AT TIME ZONE 'UTC') FROM alerts_unpartitioned LOOP
+					EXECUTE format('CREATE TABLE %I PARTITION OF alerts FOR VALUES FROM (%L) TO (%L)',
+						'alerts_p' || to_char(partition_month, 'YYYY_MM'),
+						partition_month AT TIME ZONE 'UTC',
+						(partition_month + INTERVAL '1 month') AT TIME ZONE 'UTC');
+				END LOOP;
+
+				INSERT INTO alerts SELECT * FROM alerts_unpartitioned;
+				DROP TABLE alerts_unpartitioned;
+			END IF;
+		END
+		$$;
+
+		-- Catches alerts of months without a partition, e.g. imported ones
+		CREATE TABLE IF NOT EXISTS alerts_default PARTITION OF alerts DEFAULT;
+
+		-- The dedup key of every alert, written with it, as the unique key of
+		-- alerts can't span partitions. Filled from the alerts table once.
+		DO $$
+		BEGIN
+			IF to_regclass('alert_dedup_keys') IS NULL THEN
+				CREATE TABLE alert_dedup_keys (
+					dedup_key VARCHAR(255) PRIMARY KEY,
+					id VARCHAR(36) NOT NULL,
+					created_at TIMESTAMP WITH TIME ZONE NOT NULL
+				);
+				INSERT INTO alert_dedup_keys (dedup_key, id, created_at)
+				SELECT DISTINCT ON (dedup_key) dedup_key, id, created_at FROM alerts
+				ORDER BY dedup_key, created_at DESC;
+			END IF;
+		END
+		$$;
+
+		-- Full-text search of summaries; generated, so writes needn't set it
+		ALTER TABLE alerts ADD COLUMN IF NOT EXISTS summary_search TSVECTOR
+			GENERATED ALWAYS AS (to_tsvector('english', summary)) STORED;
 
 		CREATE INDEX IF NOT EXISTS idx_alerts_event_manager ON alerts(event_manager_id);
 		CREATE INDEX IF NOT EXISTS idx_alerts_status ON alerts(status);
 		CREATE INDEX IF NOT EXISTS idx_alerts_parent ON alerts(parent_dedup_key);
+		-- Counting the active children of a parent, done for every child resolved
+		CREATE INDEX IF NOT EXISTS idx_alerts_parent_active ON alerts(parent_dedup_key) WHERE status = 'active';
+		-- Sweeping the parents waiting for their children
+		CREATE INDEX IF NOT EXISTS idx_alerts_resolve_requested ON alerts(updated_at) WHERE resolve_requested AND status = 'active';
 		CREATE INDEX IF NOT EXISTS idx_alerts_type ON alerts(type);
+		CREATE INDEX IF NOT EXISTS idx_alerts_event_manager_created ON alerts(event_manager_id, created_at);
+		CREATE INDEX IF NOT EXISTS idx_alerts_updated ON alerts(updated_at);
+		CREATE INDEX IF NOT EXISTS idx_alerts_summary_search ON alerts USING GIN (summary_search);
+		CREATE INDEX IF NOT EXISTS idx_alerts_service ON alerts(service) WHERE service <> '';
+		CREATE INDEX IF NOT EXISTS idx_alerts_components ON alerts USING GIN (components);
+
+		CREATE TABLE IF NOT EXISTS alerts_history (
+			history_id BIGSERIAL PRIMARY KEY,
+			revision INTEGER NOT NULL,
+			recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			id VARCHAR(36) NOT NULL,
+			dedup_key VARCHAR(255) NOT NULL,
+			event_manager_id VARCHAR(36) NOT NULL,
+			summary TEXT NOT NULL,
+			severity VARCHAR(20) NOT NULL,
+			class VARCHAR(100) NOT NULL,
+			type VARCHAR(20) NOT NULL,
+			status VARCHAR(20) NOT NULL,
+			parent_dedup_key VARCHAR(255),
+			child_count INTEGER NOT NULL,
+			resolve_requested BOOLEAN NOT NULL,
+			trigger_count INTEGER NOT NULL,
+			resolve_count INTEGER NOT NULL,
+			acknowledged_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			resolved_at TIMESTAMP WITH TIME ZONE,
+			UNIQUE (dedup_key, revision)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_alerts_history_recorded ON alerts_history(dedup_key, recorded_at);
+
+		ALTER TABLE alerts_history ADD COLUMN IF NOT EXISTS original_dedup_key TEXT;
+		ALTER TABLE alerts_history ADD COLUMN IF NOT EXISTS subscriber_ids TEXT[] NOT NULL DEFAULT '{}';
+		ALTER TABLE alerts_history ADD COLUMN IF NOT EXISTS resolution JSONB;
+		ALTER TABLE alerts_history ADD COLUMN IF NOT EXISTS runbook JSONB;
+		ALTER TABLE alerts_history ADD COLUMN IF NOT EXISTS sampled_events INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE alerts_history ADD COLUMN IF NOT EXISTS episode INTEGER NOT NULL DEFAULT 1;
+		ALTER TABLE alerts_history ADD COLUMN IF NOT EXISTS reactivated_at TIMESTAMP WITH TIME ZONE;
+		ALTER TABLE alerts_history ADD COLUMN IF NOT EXISTS episodes JSONB;
+		ALTER TABLE alerts_history ADD COLUMN IF NOT EXISTS origin JSONB;
+		ALTER TABLE alerts_history ADD COLUMN IF NOT EXISTS tags JSONB;
+		ALTER TABLE alerts_history ADD COLUMN IF NOT EXISTS event_summary TEXT NOT NULL DEFAULT '';
+		ALTER TABLE alerts_history ADD COLUMN IF NOT EXISTS service TEXT NOT NULL DEFAULT '';
+		ALTER TABLE alerts_history ADD COLUMN IF NOT EXISTS components TEXT[] NOT NULL DEFAULT '{}';
+		ALTER TABLE alerts_history ADD COLUMN IF NOT EXISTS links JSONB;
+		ALTER TABLE alerts_history ADD COLUMN IF NOT EXISTS assignee TEXT NOT NULL DEFAULT '';
+
+		-- Record every insert and update of an alert as a new revision, so writes
+		-- that bypass the repository (e.g. manual fixes) are captured too.
+		-- Alerts moved between partitions are not recorded again.
+		CREATE OR REPLACE FUNCTION record_alert_revision() RETURNS TRIGGER AS $$
+		BEGIN
+			IF current_setting('argus.moving_alerts', true) = 'on' THEN
+				RETURN NEW;
+			END IF;
+			INSERT INTO alerts_history (
+				revision, recorded_at,
+				id, dedup_key, event_manager_id, summary, severity, class,
+				type, status, parent_dedup_key, child_count, resolve_requested,
+				trigger_count, resolve_count, acknowledged_at, created_at, updated_at, resolved_at,
+				original_dedup_key, subscriber_ids, resolution, runbook, sampled_events,
+				episode, reactivated_at, episodes, origin, tags, event_summary, service, components, links, assignee
+			) VALUES (
+				COALESCE((SELECT MAX(revision) FROM alerts_history WHERE dedup_key = NEW.dedup_key), 0) + 1,
+				clock_timestamp(),
+				NEW.id, NEW.dedup_key, NEW.event_manager_id, NEW.summary, NEW.severity, NEW.class,
+				NEW.type, NEW.status, NEW.parent_dedup_key, NEW.child_count, NEW.resolve_requested,
+				NEW.trigger_count, NEW.resolve_count, NEW.acknowledged_at, NEW.created_at, NEW.updated_at, NEW.resolved_at,
+				NEW.original_dedup_key, NEW.subscriber_ids, NEW.resolution, NEW.runbook, NEW.sampled_events,
+				NEW.episode, NEW.reactivated_at, NEW.episodes, NEW.origin, NEW.tags, NEW.event_summary, NEW.service, NEW.components, NEW.links, NEW.assignee
+			);
+			RETURN NEW;
+		END;
+		$$ LANGUAGE plpgsql;
+
+		DROP TRIGGER IF EXISTS alerts_record_revision ON alerts;
+		CREATE TRIGGER alerts_record_revision
+			AFTER INSERT OR UPDATE ON alerts
+			FOR EACH ROW EXECUTE FUNCTION record_alert_revision();
 
 		CREATE TABLE IF NOT EXISTS event_managers (
 			id VARCHAR(36) PRIMARY KEY,
@@ -99,6 +306,41 @@ func (db *DB) RunMigrations(ctx context.Context) error {
 			updated_at TIMESTAMP WITH TIME ZONE NOT NULL
 		);
 
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS grouping_rules JSONB NOT NULL DEFAULT '[]';
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS grouping_disabled BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS dedup_key_trim BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS dedup_key_lowercase BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS dedup_key_hash_threshold INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS default_severity VARCHAR(20) NOT NULL DEFAULT '';
+		-- Event managers created before ingest tokens get one when it is rotated
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS ingest_token VARCHAR(64);
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_event_managers_ingest_token ON event_managers(ingest_token);
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS reminder_interval_minutes INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS max_reminders INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS resolution_policy VARCHAR(32) NOT NULL DEFAULT '';
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS tag_policy VARCHAR(16) NOT NULL DEFAULT '';
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS parent_summary JSONB NOT NULL DEFAULT '{}';
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS locale VARCHAR(35) NOT NULL DEFAULT '';
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS webhook_signatures JSONB NOT NULL DEFAULT '{}';
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS links JSONB NOT NULL DEFAULT '[]';
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS candidate_grouping_rule_id VARCHAR(36);
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS notify_child_added BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS notify_reactivated BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS notify_acknowledged BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS notify_escalated BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS webhook_transform JSONB NOT NULL DEFAULT '{}';
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS notify_plugin TEXT NOT NULL DEFAULT '';
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS notify_queue TEXT NOT NULL DEFAULT '';
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS quota JSONB NOT NULL DEFAULT '{}';
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS runbooks JSONB NOT NULL DEFAULT '{}';
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS remediations JSONB NOT NULL DEFAULT '[]';
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS sampling JSONB NOT NULL DEFAULT '{}';
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS storm JSONB NOT NULL DEFAULT '{}';
+		ALTER TABLE event_managers ADD COLUMN IF NOT EXISTS topic TEXT NOT NULL DEFAULT '';
+		-- Event managers without a grouping rule use the system default
+		ALTER TABLE event_managers ALTER COLUMN grouping_rule_id DROP NOT NULL;
+
 		CREATE TABLE IF NOT EXISTS grouping_rules (
 			id VARCHAR(36) PRIMARY KEY,
 			name VARCHAR(255) NOT NULL,
@@ -107,6 +349,201 @@ func (db *DB) RunMigrations(ctx context.Context) error {
 			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
 			updated_at TIMESTAMP WITH TIME ZONE NOT NULL
 		);
+
+		ALTER TABLE grouping_rules ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+		ALTER TABLE grouping_rules ADD COLUMN IF NOT EXISTS value_template TEXT NOT NULL DEFAULT '';
+		ALTER TABLE grouping_rules ADD COLUMN IF NOT EXISTS value_pattern TEXT NOT NULL DEFAULT '';
+		ALTER TABLE grouping_rules ADD COLUMN IF NOT EXISTS runbook JSONB NOT NULL DEFAULT '{}';
+		-- time_window_minutes is kept, rounded down, for older readers
+		ALTER TABLE grouping_rules ADD COLUMN IF NOT EXISTS time_window_seconds INTEGER;
+		UPDATE grouping_rules SET time_window_seconds = time_window_minutes * 60 WHERE time_window_seconds IS NULL;
+
+		-- Grouping rules cannot be removed while event managers reference them.
+		-- NOT VALID skips checking rows that predate the constraint.
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_event_managers_grouping_rule') THEN
+				ALTER TABLE event_managers
+					ADD CONSTRAINT fk_event_managers_grouping_rule
+					FOREIGN KEY (grouping_rule_id) REFERENCES grouping_rules(id)
+					ON DELETE RESTRICT NOT VALID;
+			END IF;
+		END
+		$$;
+
+		CREATE INDEX IF NOT EXISTS idx_event_managers_grouping_rule ON event_managers(grouping_rule_id);
+
+		CREATE TABLE IF NOT EXISTS notification_log (
+			id BIGSERIAL PRIMARY KEY,
+			dedup_key VARCHAR(255) NOT NULL,
+			event_manager_id VARCHAR(36) NOT NULL,
+			kind VARCHAR(20) NOT NULL,
+			target TEXT NOT NULL DEFAULT '',
+			sent_at TIMESTAMP WITH TIME ZONE NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_notification_log_dedup_key ON notification_log(dedup_key, sent_at);
+
+		CREATE TABLE IF NOT EXISTS remediation_log (
+			id BIGSERIAL PRIMARY KEY,
+			dedup_key VARCHAR(255) NOT NULL,
+			event_manager_id VARCHAR(36) NOT NULL,
+			action VARCHAR(255) NOT NULL,
+			target TEXT NOT NULL,
+			status VARCHAR(20) NOT NULL,
+			detail TEXT NOT NULL DEFAULT '',
+			executed_at TIMESTAMP WITH TIME ZONE NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_remediation_log_dedup_key ON remediation_log(dedup_key, executed_at);
+
+		CREATE TABLE IF NOT EXISTS alert_feedback (
+			id BIGSERIAL PRIMARY KEY,
+			dedup_key VARCHAR(255) NOT NULL,
+			event_manager_id VARCHAR(36) NOT NULL,
+			grouping_rule_id VARCHAR(36) NOT NULL DEFAULT '',
+			rating VARCHAR(20) NOT NULL,
+			comment TEXT NOT NULL DEFAULT '',
+			author VARCHAR(255) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_alert_feedback_dedup_key ON alert_feedback(dedup_key, created_at);
+		CREATE INDEX IF NOT EXISTS idx_alert_feedback_created_at ON alert_feedback(created_at);
+
+		CREATE TABLE IF NOT EXISTS audit_log (
+			id BIGSERIAL PRIMARY KEY,
+			at TIMESTAMP WITH TIME ZONE NOT NULL,
+			actor VARCHAR(255) NOT NULL DEFAULT '',
+			action VARCHAR(50) NOT NULL,
+			operation VARCHAR(50) NOT NULL,
+			target VARCHAR(255) NOT NULL,
+			approval_id VARCHAR(36) NOT NULL DEFAULT '',
+			detail TEXT NOT NULL DEFAULT ''
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_audit_log_at ON audit_log(at);
+		ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS sequence BIGINT NOT NULL DEFAULT 0;
+		ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS prev_hash VARCHAR(64) NOT NULL DEFAULT '';
+		ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS hash VARCHAR(64) NOT NULL DEFAULT '';
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_log_sequence ON audit_log(sequence) WHERE sequence > 0;
+
+		CREATE TABLE IF NOT EXISTS routing_rules (
+			id VARCHAR(36) PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			priority INTEGER NOT NULL DEFAULT 0,
+			match JSONB NOT NULL DEFAULT '{}',
+			event_manager_id VARCHAR(36) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS watches (
+			id VARCHAR(36) PRIMARY KEY,
+			user_id VARCHAR(255) NOT NULL,
+			channel_type VARCHAR(20) NOT NULL,
+			channel_address VARCHAR(255) NOT NULL,
+			dedup_keys JSONB NOT NULL DEFAULT '[]',
+			dedup_key_pattern VARCHAR(255) NOT NULL DEFAULT '',
+			filter JSONB NOT NULL DEFAULT '{}',
+			kinds JSONB NOT NULL DEFAULT '[]',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_watches_user_id ON watches(user_id);
+
+		CREATE TABLE IF NOT EXISTS report_schedules (
+			id VARCHAR(36) PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			event_manager_id VARCHAR(255) NOT NULL DEFAULT '',
+			frequency VARCHAR(20) NOT NULL,
+			format VARCHAR(20) NOT NULL,
+			recipients JSONB NOT NULL DEFAULT '[]',
+			top_limit INTEGER NOT NULL DEFAULT 0,
+			last_report_to TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS silences (
+			id VARCHAR(36) PRIMARY KEY,
+			comment TEXT NOT NULL DEFAULT '',
+			created_by VARCHAR(255) NOT NULL DEFAULT '',
+			match JSONB NOT NULL DEFAULT '{}',
+			starts_at TIMESTAMP WITH TIME ZONE,
+			ends_at TIMESTAMP WITH TIME ZONE,
+			schedule JSONB,
+			recurring_id VARCHAR(36) NOT NULL DEFAULT '',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_silences_recurring_id ON silences(recurring_id);
+
+		CREATE TABLE IF NOT EXISTS query_rules (
+			id VARCHAR(36) PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			event_manager_id VARCHAR(36) NOT NULL,
+			datasource VARCHAR(255) NOT NULL,
+			query TEXT NOT NULL,
+			condition JSONB NOT NULL DEFAULT '{}',
+			dedup_labels TEXT[] NOT NULL DEFAULT '{}',
+			summary TEXT NOT NULL DEFAULT '',
+			severity VARCHAR(20) NOT NULL DEFAULT '',
+			class VARCHAR(100) NOT NULL DEFAULT '',
+			interval_seconds INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+		);
+
+		ALTER TABLE query_rules ADD COLUMN IF NOT EXISTS params JSONB NOT NULL DEFAULT '{}';
+		ALTER TABLE query_rules ADD COLUMN IF NOT EXISTS composite JSONB;
+		ALTER TABLE query_rules ADD COLUMN IF NOT EXISTS disabled BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE query_rules ADD COLUMN IF NOT EXISTS muted_until TIMESTAMP WITH TIME ZONE;
+		ALTER TABLE query_rules ADD COLUMN IF NOT EXISTS template_id VARCHAR(36) NOT NULL DEFAULT '';
+		ALTER TABLE query_rules ADD COLUMN IF NOT EXISTS template_values JSONB NOT NULL DEFAULT '{}';
+		ALTER TABLE query_rules ADD COLUMN IF NOT EXISTS missing_labels VARCHAR(20) NOT NULL DEFAULT '';
+
+		CREATE TABLE IF NOT EXISTS rule_templates (
+			id VARCHAR(36) PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			rule JSONB NOT NULL,
+			variables TEXT[] NOT NULL DEFAULT '{}',
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS rule_executions (
+			id BIGSERIAL PRIMARY KEY,
+			rule_id VARCHAR(36) NOT NULL,
+			started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			duration_ms BIGINT NOT NULL DEFAULT 0,
+			datasource VARCHAR(255) NOT NULL DEFAULT '',
+			query TEXT NOT NULL,
+			params JSONB NOT NULL DEFAULT '{}',
+			outcomes JSONB NOT NULL DEFAULT '{}',
+			series INTEGER NOT NULL DEFAULT 0,
+			breaching TEXT[] NOT NULL DEFAULT '{}',
+			triggered TEXT[] NOT NULL DEFAULT '{}',
+			resolved TEXT[] NOT NULL DEFAULT '{}',
+			error TEXT NOT NULL DEFAULT ''
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_rule_executions_rule_id ON rule_executions(rule_id, id);
+		ALTER TABLE rule_executions ADD COLUMN IF NOT EXISTS muted BOOLEAN NOT NULL DEFAULT FALSE;
+
+		CREATE TABLE IF NOT EXISTS rule_runners (
+			instance_id VARCHAR(255) PRIMARY KEY,
+			heartbeat_at TIMESTAMP WITH TIME ZONE NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS usage_daily (
+			event_manager_id VARCHAR(36) NOT NULL,
+			day DATE NOT NULL,
+			events_ingested BIGINT NOT NULL DEFAULT 0,
+			alerts_created BIGINT NOT NULL DEFAULT 0,
+			notifications_sent BIGINT NOT NULL DEFAULT 0,
+			PRIMARY KEY (event_manager_id, day)
+		);
 	`
 
 	_, err := db.pool.Exec(ctx, schema)
@@ -114,5 +551,16 @@ func (db *DB) RunMigrations(ctx context.Context) error {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	// Alerts of this month and the next go to their partitions even before
+	// the retention job runs
+	if err := db.EnsureAlertPartitions(ctx, time.Now(), 1); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	// Reconnect the idle connections, opened before the tables existed, so
+	// every connection has the queries prepared
+	db.migrated.Store(true)
+	db.pool.Reset()
+
 	return nil
 }
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// testDSNEnv names the environment variable with the connection string of
+// the PostgreSQL database the tests needing one run against.
+const testDSNEnv = "ARGUS_TEST_POSTGRES_DSN"
+
+// newTestDB returns a migrated database in a schema of its own, dropped when
+// the test ends. The test is skipped without a database.
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s not set", testDSNEnv)
+	}
+	ctx := context.Background()
+
+	schema := "argus_test_" + uuid.New().String()[:8]
+	admin, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer admin.Close(ctx)
+	if _, err := admin.Exec(ctx, `CREATE SCHEMA `+schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", testDSNEnv, err)
+	}
+	poolConfig.ConnConfig.RuntimeParams["search_path"] = schema
+	db := &DB{}
+	poolConfig.AfterConnect = db.prepare
+	if db.pool, err = pgxpool.NewWithConfig(ctx, poolConfig); err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		conn, err := pgx.Connect(context.Background(), dsn)
+		if err != nil {
+			return
+		}
+		defer conn.Close(context.Background())
+		_, _ = conn.Exec(context.Background(), `DROP SCHEMA `+schema+` CASCADE`)
+	})
+
+	if err := db.RunMigrations(ctx); err != nil {
+		t.Fatalf("RunMigrations error: %v", err)
+	}
+	return db
+}
+
+// partitionOf returns the partition of the alerts table holding the alert
+// with the dedup key.
+func partitionOf(t *testing.T, db *DB, dedupKey string) string {
+	t.Helper()
+	var partition string
+	err := db.pool.QueryRow(context.Background(),
+		`SELECT tableoid::regclass::text FROM alerts WHERE dedup_key = $1`, dedupKey).Scan(&partition)
+	if err != nil {
+		t.Fatalf("failed to find partition of %s: %v", dedupKey, err)
+	}
+	return partition
+}
+
+// monthsAgo returns the middle of the UTC month n months before now.
+func monthsAgo(n int) time.Time {
+	return monthStart(time.Now()).AddDate(0, -n, 14)
+}
+
+func TestDB_PreparedQueries(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	// Connections opened after the migrations have the hot queries prepared
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("Acquire error: %v", err)
+	}
+	defer conn.Release()
+	rows, err := conn.Query(ctx, `SELECT statement FROM pg_prepared_statements`)
+	if err != nil {
+		t.Fatalf("Query error: %v", err)
+	}
+	var prepared []string
+	for rows.Next() {
+		var statement string
+		if err := rows.Scan(&statement); err != nil {
+			t.Fatalf("Scan error: %v", err)
+		}
+		prepared = append(prepared, strings.TrimSpace(statement))
+	}
+	for _, query := range preparedQueries {
+		if !slices.Contains(prepared, strings.TrimSpace(query)) {
+			t.Errorf("query not prepared: %s", query)
+		}
+	}
+
+	// The hot queries work through their prepared statements
+	repo := NewAlertRepository(db)
+	if err := repo.Create(ctx, newTestAlert("parent-1", monthsAgo(0))); err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	if _, err := repo.GetByDedupKey(ctx, "parent-1"); err != nil {
+		t.Errorf("GetByDedupKey error: %v", err)
+	}
+	if count, err := repo.CountActiveChildren(ctx, "parent-1"); err != nil || count != 0 {
+		t.Errorf("CountActiveChildren = %d, %v, want 0", count, err)
+	}
+
+	var indexes int
+	if err := db.pool.QueryRow(ctx, `SELECT COUNT(*) FROM pg_indexes WHERE schemaname = current_schema() AND indexname = 'idx_alerts_parent_active'`).Scan(&indexes); err != nil || indexes == 0 {
+		t.Errorf("idx_alerts_parent_active: %d indexes, %v, want the partial index", indexes, err)
+	}
+}
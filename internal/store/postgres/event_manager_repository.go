@@ -2,10 +2,12 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 
 	"argus-go/internal/domain"
 )
@@ -24,21 +26,99 @@ func NewEventManagerRepository(db *DB) *EventManagerRepository {
 func (r *EventManagerRepository) Create(ctx context.Context, em *domain.EventManager) error {
 	query := `
 		INSERT INTO event_managers (
-			id, name, description, grouping_rule_id, webhook_url, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			id, name, description, grouping_rule_id, grouping_rules, grouping_disabled,
+			dedup_key_trim, dedup_key_lowercase, dedup_key_hash_threshold, default_severity, webhook_url, ingest_token, created_at, updated_at,
+			reminder_interval_minutes, max_reminders, resolution_policy, candidate_grouping_rule_id,
+			notify_child_added, notify_reactivated, notify_acknowledged, notify_escalated, webhook_transform, notify_plugin,
+			notify_queue, quota, runbooks, remediations, sampling, storm, topic, tag_policy, parent_summary, locale, webhook_signatures, links
+		) VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''), $13, $14, $15, $16, $17, NULLIF($18, ''), $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36)
 	`
 
-	_, err := r.db.pool.Exec(ctx, query,
+	groupingRules, err := encodeGroupingRules(em.GroupingRules)
+	if err != nil {
+		return err
+	}
+	webhookTransform, err := json.Marshal(em.WebhookTransform)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook transform: %w", err)
+	}
+	quota, err := json.Marshal(em.Quota)
+	if err != nil {
+		return fmt.Errorf("failed to encode quota: %w", err)
+	}
+	runbooks, err := json.Marshal(em.Runbooks)
+	if err != nil {
+		return fmt.Errorf("failed to encode runbooks: %w", err)
+	}
+	remediations, err := encodeRemediations(em.Remediations)
+	if err != nil {
+		return fmt.Errorf("failed to encode remediations: %w", err)
+	}
+	sampling, err := json.Marshal(em.Sampling)
+	if err != nil {
+		return fmt.Errorf("failed to encode sampling: %w", err)
+	}
+	storm, err := json.Marshal(em.Storm)
+	if err != nil {
+		return fmt.Errorf("failed to encode storm: %w", err)
+	}
+	parentSummary, err := json.Marshal(em.ParentSummary)
+	if err != nil {
+		return fmt.Errorf("failed to encode parent summary: %w", err)
+	}
+	webhookSignatures, err := encodeWebhookSignatures(em.WebhookSignatures)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook signatures: %w", err)
+	}
+	links, err := encodeLinkTemplates(em.Links)
+	if err != nil {
+		return fmt.Errorf("failed to encode links: %w", err)
+	}
+
+	_, err = r.db.pool.Exec(ctx, query,
 		em.ID,
 		em.Name,
 		em.Description,
 		em.GroupingRuleID,
+		groupingRules,
+		em.GroupingDisabled,
+		em.DedupKeyConfig.Trim,
+		em.DedupKeyConfig.Lowercase,
+		em.DedupKeyConfig.HashThreshold,
+		em.EventDefaults.Severity,
 		em.NotificationConfig.WebhookURL,
+		em.IngestToken,
 		em.CreatedAt,
 		em.UpdatedAt,
+		em.NotificationConfig.ReminderIntervalMinutes,
+		em.NotificationConfig.MaxReminders,
+		em.ResolutionPolicy,
+		em.CandidateGroupingRuleID,
+		em.NotificationConfig.Events.ChildAdded,
+		em.NotificationConfig.Events.Reactivated,
+		em.NotificationConfig.Events.Acknowledged,
+		em.NotificationConfig.Events.Escalated,
+		webhookTransform,
+		em.NotificationConfig.Plugin,
+		em.NotificationConfig.Queue,
+		quota,
+		runbooks,
+		remediations,
+		sampling,
+		storm,
+		em.Topic,
+		em.TagPolicy,
+		parentSummary,
+		em.Locale,
+		webhookSignatures,
+		links,
 	)
 
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return domain.ErrEventManagerAlreadyExists
+		}
 		return fmt.Errorf("failed to create event manager: %w", err)
 	}
 
@@ -51,19 +131,118 @@ func (r *EventManagerRepository) Update(ctx context.Context, em *domain.EventMan
 		UPDATE event_managers SET
 			name = $2,
 			description = $3,
-			grouping_rule_id = $4,
-			webhook_url = $5,
-			updated_at = $6
+			grouping_rule_id = NULLIF($4, ''),
+			grouping_rules = $5,
+			grouping_disabled = $6,
+			dedup_key_trim = $7,
+			dedup_key_lowercase = $8,
+			dedup_key_hash_threshold = $9,
+			default_severity = $10,
+			webhook_url = $11,
+			ingest_token = NULLIF($12, ''),
+			updated_at = $13,
+			reminder_interval_minutes = $14,
+			max_reminders = $15,
+			resolution_policy = $16,
+			candidate_grouping_rule_id = NULLIF($17, ''),
+			notify_child_added = $18,
+			notify_reactivated = $19,
+			notify_acknowledged = $20,
+			notify_escalated = $21,
+			webhook_transform = $22,
+			notify_plugin = $23,
+			notify_queue = $24,
+			quota = $25,
+			runbooks = $26,
+			remediations = $27,
+			sampling = $28,
+			storm = $29,
+			topic = $30,
+			tag_policy = $31,
+			parent_summary = $32,
+			locale = $33,
+			webhook_signatures = $34,
+			links = $35
 		WHERE id = $1
 	`
 
+	groupingRules, err := encodeGroupingRules(em.GroupingRules)
+	if err != nil {
+		return err
+	}
+	webhookTransform, err := json.Marshal(em.WebhookTransform)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook transform: %w", err)
+	}
+	quota, err := json.Marshal(em.Quota)
+	if err != nil {
+		return fmt.Errorf("failed to encode quota: %w", err)
+	}
+	runbooks, err := json.Marshal(em.Runbooks)
+	if err != nil {
+		return fmt.Errorf("failed to encode runbooks: %w", err)
+	}
+	remediations, err := encodeRemediations(em.Remediations)
+	if err != nil {
+		return fmt.Errorf("failed to encode remediations: %w", err)
+	}
+	sampling, err := json.Marshal(em.Sampling)
+	if err != nil {
+		return fmt.Errorf("failed to encode sampling: %w", err)
+	}
+	storm, err := json.Marshal(em.Storm)
+	if err != nil {
+		return fmt.Errorf("failed to encode storm: %w", err)
+	}
+	parentSummary, err := json.Marshal(em.ParentSummary)
+	if err != nil {
+		return fmt.Errorf("failed to encode parent summary: %w", err)
+	}
+	webhookSignatures, err := encodeWebhookSignatures(em.WebhookSignatures)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook signatures: %w", err)
+	}
+	links, err := encodeLinkTemplates(em.Links)
+	if err != nil {
+		return fmt.Errorf("failed to encode links: %w", err)
+	}
+
 	result, err := r.db.pool.Exec(ctx, query,
 		em.ID,
 		em.Name,
 		em.Description,
 		em.GroupingRuleID,
+		groupingRules,
+		em.GroupingDisabled,
+		em.DedupKeyConfig.Trim,
+		em.DedupKeyConfig.Lowercase,
+		em.DedupKeyConfig.HashThreshold,
+		em.EventDefaults.Severity,
 		em.NotificationConfig.WebhookURL,
+		em.IngestToken,
 		em.UpdatedAt,
+		em.NotificationConfig.ReminderIntervalMinutes,
+		em.NotificationConfig.MaxReminders,
+		em.ResolutionPolicy,
+		em.CandidateGroupingRuleID,
+		em.NotificationConfig.Events.ChildAdded,
+		em.NotificationConfig.Events.Reactivated,
+		em.NotificationConfig.Events.Acknowledged,
+		em.NotificationConfig.Events.Escalated,
+		webhookTransform,
+		em.NotificationConfig.Plugin,
+		em.NotificationConfig.Queue,
+		quota,
+		runbooks,
+		remediations,
+		sampling,
+		storm,
+		em.Topic,
+		em.TagPolicy,
+		parentSummary,
+		em.Locale,
+		webhookSignatures,
+		links,
 	)
 
 	if err != nil {
@@ -77,9 +256,9 @@ func (r *EventManagerRepository) Update(ctx context.Context, em *domain.EventMan
 	return nil
 }
 
-// Delete removes an event manager by ID.
+// Delete soft-deletes an event manager by ID.
 func (r *EventManagerRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM event_managers WHERE id = $1`
+	query := `UPDATE event_managers SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
 
 	result, err := r.db.pool.Exec(ctx, query, id)
 	if err != nil {
@@ -93,10 +272,31 @@ func (r *EventManagerRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// Purge permanently removes an event manager by ID.
+func (r *EventManagerRepository) Purge(ctx context.Context, id string) error {
+	query := `DELETE FROM event_managers WHERE id = $1`
+
+	result, err := r.db.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to purge event manager: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEventManagerNotFound
+	}
+
+	return nil
+}
+
 // GetByID retrieves an event manager by its ID.
 func (r *EventManagerRepository) GetByID(ctx context.Context, id string) (*domain.EventManager, error) {
 	query := `
-		SELECT id, name, description, grouping_rule_id, webhook_url, created_at, updated_at
+		SELECT id, name, description, COALESCE(grouping_rule_id, ''), grouping_rules, grouping_disabled,
+		       dedup_key_trim, dedup_key_lowercase, dedup_key_hash_threshold, default_severity, webhook_url,
+		       COALESCE(ingest_token, ''), created_at, updated_at, deleted_at,
+		       reminder_interval_minutes, max_reminders, resolution_policy, COALESCE(candidate_grouping_rule_id, ''),
+		       notify_child_added, notify_reactivated, notify_acknowledged, notify_escalated, webhook_transform,
+		       notify_plugin, notify_queue, quota, runbooks, remediations, sampling, storm, topic, tag_policy, parent_summary, locale, webhook_signatures, links
 		FROM event_managers
 		WHERE id = $1
 	`
@@ -114,11 +314,43 @@ func (r *EventManagerRepository) GetByID(ctx context.Context, id string) (*domai
 	return em, nil
 }
 
-// List retrieves all event managers.
+// GetByIngestToken retrieves an event manager by its ingest token.
+func (r *EventManagerRepository) GetByIngestToken(ctx context.Context, token string) (*domain.EventManager, error) {
+	query := `
+		SELECT id, name, description, COALESCE(grouping_rule_id, ''), grouping_rules, grouping_disabled,
+		       dedup_key_trim, dedup_key_lowercase, dedup_key_hash_threshold, default_severity, webhook_url,
+		       COALESCE(ingest_token, ''), created_at, updated_at, deleted_at,
+		       reminder_interval_minutes, max_reminders, resolution_policy, COALESCE(candidate_grouping_rule_id, ''),
+		       notify_child_added, notify_reactivated, notify_acknowledged, notify_escalated, webhook_transform,
+		       notify_plugin, notify_queue, quota, runbooks, remediations, sampling, storm, topic, tag_policy, parent_summary, locale, webhook_signatures, links
+		FROM event_managers
+		WHERE ingest_token = $1
+	`
+
+	row := r.db.pool.QueryRow(ctx, query, token)
+
+	em, err := scanEventManager(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrEventManagerNotFound
+		}
+		return nil, fmt.Errorf("failed to get event manager: %w", err)
+	}
+
+	return em, nil
+}
+
+// List retrieves all event managers that are not deleted.
 func (r *EventManagerRepository) List(ctx context.Context) ([]*domain.EventManager, error) {
 	query := `
-		SELECT id, name, description, grouping_rule_id, webhook_url, created_at, updated_at
+		SELECT id, name, description, COALESCE(grouping_rule_id, ''), grouping_rules, grouping_disabled,
+		       dedup_key_trim, dedup_key_lowercase, dedup_key_hash_threshold, default_severity, webhook_url,
+		       COALESCE(ingest_token, ''), created_at, updated_at, deleted_at,
+		       reminder_interval_minutes, max_reminders, resolution_policy, COALESCE(candidate_grouping_rule_id, ''),
+		       notify_child_added, notify_reactivated, notify_acknowledged, notify_escalated, webhook_transform,
+		       notify_plugin, notify_queue, quota, runbooks, remediations, sampling, storm, topic, tag_policy, parent_summary, locale, webhook_signatures, links
 		FROM event_managers
+		WHERE deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
@@ -145,19 +377,91 @@ func (r *EventManagerRepository) List(ctx context.Context) ([]*domain.EventManag
 	return managers, nil
 }
 
+// ListByGroupingRule retrieves all event managers referencing a grouping rule,
+// as their default rule, in their grouping rule list or as their candidate
+// rule, including deleted ones.
+func (r *EventManagerRepository) ListByGroupingRule(ctx context.Context, groupingRuleID string) ([]*domain.EventManager, error) {
+	query := `
+		SELECT id, name, description, COALESCE(grouping_rule_id, ''), grouping_rules, grouping_disabled,
+		       dedup_key_trim, dedup_key_lowercase, dedup_key_hash_threshold, default_severity, webhook_url,
+		       COALESCE(ingest_token, ''), created_at, updated_at, deleted_at,
+		       reminder_interval_minutes, max_reminders, resolution_policy, COALESCE(candidate_grouping_rule_id, ''),
+		       notify_child_added, notify_reactivated, notify_acknowledged, notify_escalated, webhook_transform,
+		       notify_plugin, notify_queue, quota, runbooks, remediations, sampling, storm, topic, tag_policy, parent_summary, locale, webhook_signatures, links
+		FROM event_managers
+		WHERE grouping_rule_id = $1
+			OR grouping_rules @> jsonb_build_array(jsonb_build_object('grouping_rule_id', $1::text))
+			OR candidate_grouping_rule_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, groupingRuleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list event managers: %w", err)
+	}
+	defer rows.Close()
+
+	var managers []*domain.EventManager
+
+	for rows.Next() {
+		em, err := scanEventManagerRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event manager: %w", err)
+		}
+		managers = append(managers, em)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event managers: %w", err)
+	}
+
+	return managers, nil
+}
+
 // scanEventManager scans a single row into an EventManager.
 func scanEventManager(row pgx.Row) (*domain.EventManager, error) {
 	var em domain.EventManager
 	var webhookURL *string
+	var groupingRules, webhookTransform, quota, runbooks, remediations, sampling, storm, parentSummary, webhookSignatures, links []byte
 
 	err := row.Scan(
 		&em.ID,
 		&em.Name,
 		&em.Description,
 		&em.GroupingRuleID,
+		&groupingRules,
+		&em.GroupingDisabled,
+		&em.DedupKeyConfig.Trim,
+		&em.DedupKeyConfig.Lowercase,
+		&em.DedupKeyConfig.HashThreshold,
+		&em.EventDefaults.Severity,
 		&webhookURL,
+		&em.IngestToken,
 		&em.CreatedAt,
 		&em.UpdatedAt,
+		&em.DeletedAt,
+		&em.NotificationConfig.ReminderIntervalMinutes,
+		&em.NotificationConfig.MaxReminders,
+		&em.ResolutionPolicy,
+		&em.CandidateGroupingRuleID,
+		&em.NotificationConfig.Events.ChildAdded,
+		&em.NotificationConfig.Events.Reactivated,
+		&em.NotificationConfig.Events.Acknowledged,
+		&em.NotificationConfig.Events.Escalated,
+		&webhookTransform,
+		&em.NotificationConfig.Plugin,
+		&em.NotificationConfig.Queue,
+		&quota,
+		&runbooks,
+		&remediations,
+		&sampling,
+		&storm,
+		&em.Topic,
+		&em.TagPolicy,
+		&parentSummary,
+		&em.Locale,
+		&webhookSignatures,
+		&links,
 	)
 
 	if err != nil {
@@ -168,6 +472,37 @@ func scanEventManager(row pgx.Row) (*domain.EventManager, error) {
 		em.NotificationConfig.WebhookURL = *webhookURL
 	}
 
+	if err := json.Unmarshal(groupingRules, &em.GroupingRules); err != nil {
+		return nil, fmt.Errorf("failed to decode grouping rules: %w", err)
+	}
+	if err := json.Unmarshal(webhookTransform, &em.WebhookTransform); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook transform: %w", err)
+	}
+	if err := json.Unmarshal(quota, &em.Quota); err != nil {
+		return nil, fmt.Errorf("failed to decode quota: %w", err)
+	}
+	if err := json.Unmarshal(runbooks, &em.Runbooks); err != nil {
+		return nil, fmt.Errorf("failed to decode runbooks: %w", err)
+	}
+	if err := json.Unmarshal(remediations, &em.Remediations); err != nil {
+		return nil, fmt.Errorf("failed to decode remediations: %w", err)
+	}
+	if err := json.Unmarshal(sampling, &em.Sampling); err != nil {
+		return nil, fmt.Errorf("failed to decode sampling: %w", err)
+	}
+	if err := json.Unmarshal(storm, &em.Storm); err != nil {
+		return nil, fmt.Errorf("failed to decode storm: %w", err)
+	}
+	if err := json.Unmarshal(parentSummary, &em.ParentSummary); err != nil {
+		return nil, fmt.Errorf("failed to decode parent summary: %w", err)
+	}
+	if err := json.Unmarshal(webhookSignatures, &em.WebhookSignatures); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook signatures: %w", err)
+	}
+	if err := json.Unmarshal(links, &em.Links); err != nil {
+		return nil, fmt.Errorf("failed to decode links: %w", err)
+	}
+
 	return &em, nil
 }
 
@@ -175,15 +510,46 @@ func scanEventManager(row pgx.Row) (*domain.EventManager, error) {
 func scanEventManagerRow(rows pgx.Rows) (*domain.EventManager, error) {
 	var em domain.EventManager
 	var webhookURL *string
+	var groupingRules, webhookTransform, quota, runbooks, remediations, sampling, storm, parentSummary, webhookSignatures, links []byte
 
 	err := rows.Scan(
 		&em.ID,
 		&em.Name,
 		&em.Description,
 		&em.GroupingRuleID,
+		&groupingRules,
+		&em.GroupingDisabled,
+		&em.DedupKeyConfig.Trim,
+		&em.DedupKeyConfig.Lowercase,
+		&em.DedupKeyConfig.HashThreshold,
+		&em.EventDefaults.Severity,
 		&webhookURL,
+		&em.IngestToken,
 		&em.CreatedAt,
 		&em.UpdatedAt,
+		&em.DeletedAt,
+		&em.NotificationConfig.ReminderIntervalMinutes,
+		&em.NotificationConfig.MaxReminders,
+		&em.ResolutionPolicy,
+		&em.CandidateGroupingRuleID,
+		&em.NotificationConfig.Events.ChildAdded,
+		&em.NotificationConfig.Events.Reactivated,
+		&em.NotificationConfig.Events.Acknowledged,
+		&em.NotificationConfig.Events.Escalated,
+		&webhookTransform,
+		&em.NotificationConfig.Plugin,
+		&em.NotificationConfig.Queue,
+		&quota,
+		&runbooks,
+		&remediations,
+		&sampling,
+		&storm,
+		&em.Topic,
+		&em.TagPolicy,
+		&parentSummary,
+		&em.Locale,
+		&webhookSignatures,
+		&links,
 	)
 
 	if err != nil {
@@ -194,5 +560,76 @@ func scanEventManagerRow(rows pgx.Rows) (*domain.EventManager, error) {
 		em.NotificationConfig.WebhookURL = *webhookURL
 	}
 
+	if err := json.Unmarshal(groupingRules, &em.GroupingRules); err != nil {
+		return nil, fmt.Errorf("failed to decode grouping rules: %w", err)
+	}
+	if err := json.Unmarshal(webhookTransform, &em.WebhookTransform); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook transform: %w", err)
+	}
+	if err := json.Unmarshal(quota, &em.Quota); err != nil {
+		return nil, fmt.Errorf("failed to decode quota: %w", err)
+	}
+	if err := json.Unmarshal(runbooks, &em.Runbooks); err != nil {
+		return nil, fmt.Errorf("failed to decode runbooks: %w", err)
+	}
+	if err := json.Unmarshal(remediations, &em.Remediations); err != nil {
+		return nil, fmt.Errorf("failed to decode remediations: %w", err)
+	}
+	if err := json.Unmarshal(sampling, &em.Sampling); err != nil {
+		return nil, fmt.Errorf("failed to decode sampling: %w", err)
+	}
+	if err := json.Unmarshal(storm, &em.Storm); err != nil {
+		return nil, fmt.Errorf("failed to decode storm: %w", err)
+	}
+	if err := json.Unmarshal(parentSummary, &em.ParentSummary); err != nil {
+		return nil, fmt.Errorf("failed to decode parent summary: %w", err)
+	}
+	if err := json.Unmarshal(webhookSignatures, &em.WebhookSignatures); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook signatures: %w", err)
+	}
+	if err := json.Unmarshal(links, &em.Links); err != nil {
+		return nil, fmt.Errorf("failed to decode links: %w", err)
+	}
+
 	return &em, nil
 }
+
+// encodeGroupingRules encodes an event manager's grouping rule list as JSON,
+// storing an empty list rather than null.
+func encodeGroupingRules(bindings []domain.GroupingRuleBinding) ([]byte, error) {
+	if bindings == nil {
+		bindings = []domain.GroupingRuleBinding{}
+	}
+	data, err := json.Marshal(bindings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode grouping rules: %w", err)
+	}
+	return data, nil
+}
+
+// encodeRemediations encodes an event manager's remediation actions as JSON,
+// storing an empty list rather than null.
+func encodeRemediations(actions []domain.RemediationAction) ([]byte, error) {
+	if actions == nil {
+		actions = []domain.RemediationAction{}
+	}
+	return json.Marshal(actions)
+}
+
+// encodeWebhookSignatures encodes an event manager's webhook secrets as JSON,
+// storing an empty object rather than null.
+func encodeWebhookSignatures(signatures domain.WebhookSignatures) ([]byte, error) {
+	if signatures == nil {
+		signatures = domain.WebhookSignatures{}
+	}
+	return json.Marshal(signatures)
+}
+
+// encodeLinkTemplates encodes an event manager's link templates as JSON,
+// storing an empty list rather than null.
+func encodeLinkTemplates(templates []domain.AlertLinkTemplate) ([]byte, error) {
+	if templates == nil {
+		templates = []domain.AlertLinkTemplate{}
+	}
+	return json.Marshal(templates)
+}
@@ -2,14 +2,25 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 
 	"argus-go/internal/domain"
 )
 
+// PostgreSQL error codes handled by the repositories.
+const (
+	// foreignKeyViolation is raised by ON DELETE RESTRICT.
+	foreignKeyViolation = "23503"
+	// uniqueViolation is raised when inserting an existing primary key.
+	uniqueViolation = "23505"
+)
+
 // GroupingRuleRepository implements store.GroupingRuleRepository using PostgreSQL.
 type GroupingRuleRepository struct {
 	db *DB
@@ -24,20 +35,34 @@ func NewGroupingRuleRepository(db *DB) *GroupingRuleRepository {
 func (r *GroupingRuleRepository) Create(ctx context.Context, rule *domain.GroupingRule) error {
 	query := `
 		INSERT INTO grouping_rules (
-			id, name, grouping_key, time_window_minutes, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6)
+			id, name, grouping_key, time_window_minutes, value_template, value_pattern, created_at, updated_at,
+			time_window_seconds, runbook
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
-	_, err := r.db.pool.Exec(ctx, query,
+	runbook, err := json.Marshal(rule.Runbook)
+	if err != nil {
+		return fmt.Errorf("failed to encode runbook: %w", err)
+	}
+
+	_, err = r.db.pool.Exec(ctx, query,
 		rule.ID,
 		rule.Name,
 		rule.GroupingKey,
 		rule.TimeWindowMinutes,
+		rule.ValueTemplate,
+		rule.ValuePattern,
 		rule.CreatedAt,
 		rule.UpdatedAt,
+		int(rule.TimeWindow()/time.Second),
+		runbook,
 	)
 
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return domain.ErrGroupingRuleAlreadyExists
+		}
 		return fmt.Errorf("failed to create grouping rule: %w", err)
 	}
 
@@ -51,16 +76,29 @@ func (r *GroupingRuleRepository) Update(ctx context.Context, rule *domain.Groupi
 			name = $2,
 			grouping_key = $3,
 			time_window_minutes = $4,
-			updated_at = $5
+			value_template = $5,
+			value_pattern = $6,
+			updated_at = $7,
+			time_window_seconds = $8,
+			runbook = $9
 		WHERE id = $1
 	`
 
+	runbook, err := json.Marshal(rule.Runbook)
+	if err != nil {
+		return fmt.Errorf("failed to encode runbook: %w", err)
+	}
+
 	result, err := r.db.pool.Exec(ctx, query,
 		rule.ID,
 		rule.Name,
 		rule.GroupingKey,
 		rule.TimeWindowMinutes,
+		rule.ValueTemplate,
+		rule.ValuePattern,
 		rule.UpdatedAt,
+		int(rule.TimeWindow()/time.Second),
+		runbook,
 	)
 
 	if err != nil {
@@ -74,9 +112,9 @@ func (r *GroupingRuleRepository) Update(ctx context.Context, rule *domain.Groupi
 	return nil
 }
 
-// Delete removes a grouping rule by ID.
+// Delete soft-deletes a grouping rule by ID.
 func (r *GroupingRuleRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM grouping_rules WHERE id = $1`
+	query := `UPDATE grouping_rules SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
 
 	result, err := r.db.pool.Exec(ctx, query, id)
 	if err != nil {
@@ -90,10 +128,31 @@ func (r *GroupingRuleRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// Purge permanently removes a grouping rule by ID.
+func (r *GroupingRuleRepository) Purge(ctx context.Context, id string) error {
+	query := `DELETE FROM grouping_rules WHERE id = $1`
+
+	result, err := r.db.pool.Exec(ctx, query, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+			return domain.ErrGroupingRuleInUse
+		}
+		return fmt.Errorf("failed to purge grouping rule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrGroupingRuleNotFound
+	}
+
+	return nil
+}
+
 // GetByID retrieves a grouping rule by its ID.
 func (r *GroupingRuleRepository) GetByID(ctx context.Context, id string) (*domain.GroupingRule, error) {
 	query := `
-		SELECT id, name, grouping_key, time_window_minutes, created_at, updated_at
+		SELECT id, name, grouping_key, time_window_minutes, value_template, value_pattern, created_at, updated_at, deleted_at,
+		       time_window_seconds, runbook
 		FROM grouping_rules
 		WHERE id = $1
 	`
@@ -111,11 +170,13 @@ func (r *GroupingRuleRepository) GetByID(ctx context.Context, id string) (*domai
 	return rule, nil
 }
 
-// List retrieves all grouping rules.
+// List retrieves all grouping rules that are not deleted.
 func (r *GroupingRuleRepository) List(ctx context.Context) ([]*domain.GroupingRule, error) {
 	query := `
-		SELECT id, name, grouping_key, time_window_minutes, created_at, updated_at
+		SELECT id, name, grouping_key, time_window_minutes, value_template, value_pattern, created_at, updated_at, deleted_at,
+		       time_window_seconds, runbook
 		FROM grouping_rules
+		WHERE deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
@@ -145,39 +206,61 @@ func (r *GroupingRuleRepository) List(ctx context.Context) ([]*domain.GroupingRu
 // scanGroupingRule scans a single row into a GroupingRule.
 func scanGroupingRule(row pgx.Row) (*domain.GroupingRule, error) {
 	var rule domain.GroupingRule
+	var windowSeconds int
+	var runbook []byte
 
 	err := row.Scan(
 		&rule.ID,
 		&rule.Name,
 		&rule.GroupingKey,
 		&rule.TimeWindowMinutes,
+		&rule.ValueTemplate,
+		&rule.ValuePattern,
 		&rule.CreatedAt,
 		&rule.UpdatedAt,
+		&rule.DeletedAt,
+		&windowSeconds,
+		&runbook,
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
+	rule.Window = domain.Duration(time.Duration(windowSeconds) * time.Second)
+	if err := json.Unmarshal(runbook, &rule.Runbook); err != nil {
+		return nil, fmt.Errorf("failed to decode runbook: %w", err)
+	}
 	return &rule, nil
 }
 
 // scanGroupingRuleRow scans a row from a Rows iterator into a GroupingRule.
 func scanGroupingRuleRow(rows pgx.Rows) (*domain.GroupingRule, error) {
 	var rule domain.GroupingRule
+	var windowSeconds int
+	var runbook []byte
 
 	err := rows.Scan(
 		&rule.ID,
 		&rule.Name,
 		&rule.GroupingKey,
 		&rule.TimeWindowMinutes,
+		&rule.ValueTemplate,
+		&rule.ValuePattern,
 		&rule.CreatedAt,
 		&rule.UpdatedAt,
+		&rule.DeletedAt,
+		&windowSeconds,
+		&runbook,
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
+	rule.Window = domain.Duration(time.Duration(windowSeconds) * time.Second)
+	if err := json.Unmarshal(runbook, &rule.Runbook); err != nil {
+		return nil, fmt.Errorf("failed to decode runbook: %w", err)
+	}
 	return &rule, nil
 }
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"argus-go/internal/domain"
+)
+
+// statusCondition returns the condition, starting with AND, for the alerts
+// listed with status under the muting, numbering its arguments from
+// argNum, and its arguments. Active alerts are silenced if a silence
+// matches them, suppressed if not and the quota of their event manager
+// suppresses notifications, and active otherwise.
+func statusCondition(status domain.AlertStatus, muting *domain.AlertMuting, argNum int) (string, []any) {
+	// Muting leaves the other statuses as stored
+	if muting == nil || status != domain.AlertStatusActive && !status.IsMuted() {
+		return fmt.Sprintf(" AND status = $%d", argNum), []any{status}
+	}
+
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", argNum+len(args)-1)
+	}
+
+	var silences []string
+	for _, s := range muting.Silences {
+		if cond, ok := silenceCondition(&s.Match, arg); ok {
+			silences = append(silences, cond)
+		}
+	}
+	silenced := "FALSE"
+	if len(silences) > 0 {
+		silenced = "(" + strings.Join(silences, " OR ") + ")"
+	}
+	suppressed := "FALSE"
+	if len(muting.SuppressedEventManagers) > 0 {
+		suppressed = "event_manager_id = ANY(" + arg(muting.SuppressedEventManagers) + ")"
+	}
+
+	switch status {
+	case domain.AlertStatusSilenced:
+		return " AND status = 'active' AND " + silenced, args
+	case domain.AlertStatusSuppressed:
+		return " AND status = 'active' AND NOT " + silenced + " AND " + suppressed, args
+	default:
+		return " AND status = 'active' AND NOT " + silenced + " AND NOT " + suppressed, args
+	}
+}
+
+// silenceCondition returns the condition for the alerts a silence matches,
+// adding its arguments with arg. It returns false if the silence matches
+// none, as one with a malformed dedup key pattern.
+func silenceCondition(m *domain.SilenceMatcher, arg func(any) string) (string, bool) {
+	conditions := []string{"TRUE"}
+	if m.EventManagerID != "" {
+		conditions = append(conditions, "event_manager_id = "+arg(m.EventManagerID))
+	}
+	if m.DedupKeyPattern != "" {
+		re, err := globRegexp(m.DedupKeyPattern)
+		if err != nil {
+			return "", false
+		}
+		conditions = append(conditions, "dedup_key ~ "+arg(re))
+	}
+	if len(m.Classes) > 0 {
+		conditions = append(conditions, "class = ANY("+arg(m.Classes)+")")
+	}
+	if len(m.Severities) > 0 {
+		severities := make([]string, len(m.Severities))
+		for i, severity := range m.Severities {
+			severities[i] = string(severity)
+		}
+		conditions = append(conditions, "severity::text = ANY("+arg(severities)+")")
+	}
+	return "(" + strings.Join(conditions, " AND ") + ")", true
+}
+
+// globRegexp translates a path.Match pattern into an anchored regular
+// expression matching the same strings, for PostgreSQL's ~ operator.
+func globRegexp(pattern string) (string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '*':
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '\\':
+			i++
+			b.WriteString(quoteRune(runes[i]))
+		case '[':
+			// Unlike * and ?, a character class matches a slash too
+			b.WriteString("[")
+			if i++; runes[i] == '^' {
+				b.WriteString("^")
+				i++
+			}
+			for ; runes[i] != ']'; i++ {
+				if runes[i] == '\\' {
+					i++
+				} else if runes[i] == '-' {
+					b.WriteString("-")
+					continue
+				}
+				b.WriteString(quoteRune(runes[i]))
+			}
+			b.WriteString("]")
+		default:
+			b.WriteString(quoteRune(r))
+		}
+	}
+	b.WriteString("$")
+	return b.String(), nil
+}
+
+// quoteRune escapes a rune that is special in regular expressions. Letters
+// and digits are left alone, as escaping them makes class shorthands.
+func quoteRune(r rune) string {
+	if r < utf8.RuneSelf && !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' {
+		return `\` + string(r)
+	}
+	return string(r)
+}
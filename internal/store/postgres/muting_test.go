@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"path"
+	"regexp"
+	"testing"
+
+	"argus-go/internal/domain"
+)
+
+func TestGlobRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		names   []string
+	}{
+		{"db-*", []string{"db-", "db-1", "db-primary", "db/1", "web-1", "xdb-1"}},
+		{"*-1", []string{"db-1", "web-1", "a/b-1", "db-10"}},
+		{"db-?", []string{"db-1", "db-12", "db-", "db-/"}},
+		{"db-[0-9]", []string{"db-1", "db-a", "db-/"}},
+		{"db-[^0-9]", []string{"db-1", "db-a", "db-/"}},
+		{"db-[a\\-]", []string{"db-a", "db--", "db-b"}},
+		{"a.b+c(d)", []string{"a.b+c(d)", "axb+c(d)", "a.bbc(d)"}},
+		{"\\*", []string{"*", "a"}},
+		{"über-*", []string{"über-1", "uber-1"}},
+	}
+	for _, tt := range tests {
+		re, err := globRegexp(tt.pattern)
+		if err != nil {
+			t.Fatalf("globRegexp(%q) error: %v", tt.pattern, err)
+		}
+		compiled := regexp.MustCompile(re)
+		for _, name := range tt.names {
+			want, _ := path.Match(tt.pattern, name)
+			if got := compiled.MatchString(name); got != want {
+				t.Errorf("globRegexp(%q) = %q matches %q: %v, want %v", tt.pattern, re, name, got, want)
+			}
+		}
+	}
+
+	if _, err := globRegexp("db-["); err == nil {
+		t.Error("globRegexp(\"db-[\") error = nil, want an error")
+	}
+}
+
+func TestStatusCondition(t *testing.T) {
+	// The silence with a malformed pattern matches none
+	muting := &domain.AlertMuting{
+		Silences: []*domain.Silence{
+			{Match: domain.SilenceMatcher{EventManagerID: "em-1", Severities: []domain.Severity{domain.SeverityLow}}},
+			{Match: domain.SilenceMatcher{DedupKeyPattern: "db-["}},
+		},
+		SuppressedEventManagers: []string{"em-2"},
+	}
+
+	tests := []struct {
+		status   domain.AlertStatus
+		muting   *domain.AlertMuting
+		want     string
+		wantArgs int
+	}{
+		{domain.AlertStatusSilenced, nil, " AND status = $3", 1},
+		{domain.AlertStatusSilenced, muting, " AND status = 'active' AND ((TRUE AND event_manager_id = $3 AND severity::text = ANY($4)))", 3},
+		{domain.AlertStatusSuppressed, muting, " AND status = 'active' AND NOT ((TRUE AND event_manager_id = $3 AND severity::text = ANY($4))) AND event_manager_id = ANY($5)", 3},
+		{domain.AlertStatusActive, &domain.AlertMuting{}, " AND status = 'active' AND NOT FALSE AND NOT FALSE", 0},
+		{domain.AlertStatusResolved, muting, " AND status = $3", 1},
+	}
+	for _, tt := range tests {
+		got, args := statusCondition(tt.status, tt.muting, 3)
+		if got != tt.want || len(args) != tt.wantArgs {
+			t.Errorf("statusCondition(%s) = %q with %d args, want %q with %d", tt.status, got, len(args), tt.want, tt.wantArgs)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"argus-go/internal/domain"
+)
+
+// NotificationLogRepository implements store.NotificationLogRepository using PostgreSQL.
+type NotificationLogRepository struct {
+	db *DB
+}
+
+// NewNotificationLogRepository creates a new PostgreSQL-backed notification log.
+func NewNotificationLogRepository(db *DB) *NotificationLogRepository {
+	return &NotificationLogRepository{db: db}
+}
+
+// Record appends a sent notification to the log.
+func (r *NotificationLogRepository) Record(ctx context.Context, record *domain.NotificationRecord) error {
+	query := `
+		INSERT INTO notification_log (dedup_key, event_manager_id, kind, target, sent_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.pool.Exec(ctx, query,
+		record.DedupKey,
+		record.EventManagerID,
+		record.Kind,
+		record.Target,
+		record.SentAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to record notification: %w", err)
+	}
+
+	return nil
+}
+
+// ListByDedupKey retrieves the notifications sent about an alert, oldest first.
+func (r *NotificationLogRepository) ListByDedupKey(ctx context.Context, dedupKey string) ([]*domain.NotificationRecord, error) {
+	query := `
+		SELECT dedup_key, event_manager_id, kind, target, sent_at
+		FROM notification_log
+		WHERE dedup_key = $1
+		ORDER BY sent_at, id
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, dedupKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*domain.NotificationRecord{}
+
+	for rows.Next() {
+		var record domain.NotificationRecord
+		if err := rows.Scan(&record.DedupKey, &record.EventManagerID, &record.Kind, &record.Target, &record.SentAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notifications: %w", err)
+	}
+
+	return records, nil
+}
+
+// DeleteByEventManager removes the notifications sent to an event manager.
+func (r *NotificationLogRepository) DeleteByEventManager(ctx context.Context, eventManagerID string) (int, error) {
+	result, err := r.db.pool.Exec(ctx, `DELETE FROM notification_log WHERE event_manager_id = $1`, eventManagerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete notifications: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
@@ -0,0 +1,290 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"argus-go/internal/domain"
+)
+
+// alertPartitionPrefix is the name prefix of the monthly partitions of the
+// alerts table, followed by year and month, e.g. alerts_p2026_03.
+const alertPartitionPrefix = "alerts_p"
+
+// alertPartitionMonth is the layout of the month in partition names.
+const alertPartitionMonth = "2006_01"
+
+// alertDefaultPartition is the partition of the alerts table catching the
+// alerts of months without a partition of their own.
+const alertDefaultPartition = "alerts_default"
+
+// alertPartition is a monthly partition of the alerts table.
+type alertPartition struct {
+	name  string
+	month time.Time
+}
+
+// from returns the inclusive lower bound of the partition.
+func (p alertPartition) from() time.Time {
+	return p.month
+}
+
+// to returns the exclusive upper bound of the partition.
+func (p alertPartition) to() time.Time {
+	return p.month.AddDate(0, 1, 0)
+}
+
+// newAlertPartition returns the partition of the month of t.
+func newAlertPartition(t time.Time) alertPartition {
+	month := monthStart(t)
+	return alertPartition{name: alertPartitionPrefix + month.Format(alertPartitionMonth), month: month}
+}
+
+// parseAlertPartition returns the partition named name, unless the name is
+// not one of a monthly partition.
+func parseAlertPartition(name string) (alertPartition, bool) {
+	suffix, ok := strings.CutPrefix(name, alertPartitionPrefix)
+	if !ok {
+		return alertPartition{}, false
+	}
+	month, err := time.Parse(alertPartitionMonth, suffix)
+	if err != nil {
+		return alertPartition{}, false
+	}
+	return alertPartition{name: name, month: month}, true
+}
+
+// monthStart returns the start of the UTC month of t.
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// upcomingAlertPartitions returns the partitions of the month of now and the
+// ahead months after it.
+func upcomingAlertPartitions(now time.Time, ahead int) []alertPartition {
+	partitions := make([]alertPartition, 0, ahead+1)
+	for i := 0; i <= ahead; i++ {
+		partitions = append(partitions, newAlertPartition(monthStart(now).AddDate(0, i, 0)))
+	}
+	return partitions
+}
+
+// expiredAlertPartitions returns the partitions of months that ended before
+// cutoff.
+func expiredAlertPartitions(partitions []alertPartition, cutoff time.Time) []alertPartition {
+	var expired []alertPartition
+	for _, p := range partitions {
+		if !p.to().After(cutoff) {
+			expired = append(expired, p)
+		}
+	}
+	return expired
+}
+
+// createAlertPartitionQuery returns the statement creating partition p of
+// the alerts table. Partition bounds can't be query parameters.
+func createAlertPartitionQuery(p alertPartition) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s PARTITION OF alerts FOR VALUES FROM ('%s') TO ('%s')`,
+		pgx.Identifier{p.name}.Sanitize(), p.from().Format(time.RFC3339), p.to().Format(time.RFC3339))
+}
+
+// moveDefaultAlertsQueries returns the statements creating partition p and
+// moving the alerts of its month out of the default partition. Postgres
+// refuses a partition whose rows the default partition holds, so the default
+// partition is detached meanwhile. The moved alerts are not recorded as new
+// revisions.
+func moveDefaultAlertsQueries(p alertPartition) []string {
+	table := pgx.Identifier{p.name}.Sanitize()
+	defaultTable := pgx.Identifier{alertDefaultPartition}.Sanitize()
+	bounds := fmt.Sprintf(`created_at >= '%s' AND created_at < '%s'`,
+		p.from().Format(time.RFC3339), p.to().Format(time.RFC3339))
+
+	return []string{
+		`SET LOCAL argus.moving_alerts = 'on'`,
+		`ALTER TABLE alerts DETACH PARTITION ` + defaultTable,
+		createAlertPartitionQuery(p),
+		`INSERT INTO ` + table + ` (` + alertColumns + `) SELECT ` + alertColumns + ` FROM ` + defaultTable + ` WHERE ` + bounds,
+		`DELETE FROM ` + defaultTable + ` WHERE ` + bounds,
+		`ALTER TABLE alerts ATTACH PARTITION ` + defaultTable + ` DEFAULT`,
+	}
+}
+
+// EnsureAlertPartitions creates the partitions of the alerts table for the
+// month of now and the ahead months after it, unless they exist. Alerts of
+// those months in the default partition are moved to the new partitions.
+func (db *DB) EnsureAlertPartitions(ctx context.Context, now time.Time, ahead int) error {
+	for _, p := range upcomingAlertPartitions(now, ahead) {
+		if err := db.ensureAlertPartition(ctx, p); err != nil {
+			return fmt.Errorf("failed to create alert partition %s: %w", p.name, err)
+		}
+	}
+	return nil
+}
+
+// ensureAlertPartition creates partition p, unless it exists.
+func (db *DB) ensureAlertPartition(ctx context.Context, p alertPartition) error {
+	var exists, inDefault bool
+	query := fmt.Sprintf(`
+		SELECT EXISTS (SELECT 1 FROM pg_tables WHERE schemaname = current_schema() AND tablename = $1),
+			EXISTS (SELECT 1 FROM %s WHERE created_at >= $2 AND created_at < $3)
+	`, pgx.Identifier{alertDefaultPartition}.Sanitize())
+	if err := db.pool.QueryRow(ctx, query, p.name, p.from(), p.to()).Scan(&exists, &inDefault); err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	if !inDefault {
+		_, err := db.pool.Exec(ctx, createAlertPartitionQuery(p))
+		return err
+	}
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for _, query := range moveDefaultAlertsQueries(p) {
+		if _, err := tx.Exec(ctx, query); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// DetachAlertPartitions detaches the partitions of the alerts table for months
+// that ended before cutoff. Partitions still holding open alerts are kept
+// until those resolve. Returns the partitions detached and kept, which stay
+// in the database as tables of their own, for archiving. The dedup keys of
+// detached alerts are released, so later events of those keys open new
+// alerts; the processor drops the cached state of such an alert on its next
+// trigger.
+func (db *DB) DetachAlertPartitions(ctx context.Context, cutoff time.Time) (detached, kept []string, err error) {
+	partitions, err := db.alertPartitions(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, p := range expiredAlertPartitions(partitions, cutoff) {
+		table := pgx.Identifier{p.name}.Sanitize()
+
+		var open bool
+		query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE status <> $1)`, table)
+		if err := db.pool.QueryRow(ctx, query, domain.AlertStatusResolved).Scan(&open); err != nil {
+			return detached, kept, fmt.Errorf("failed to check alert partition %s: %w", p.name, err)
+		}
+		if open {
+			kept = append(kept, p.name)
+			continue
+		}
+
+		if err := db.detachAlertPartition(ctx, table); err != nil {
+			return detached, kept, fmt.Errorf("failed to detach alert partition %s: %w", p.name, err)
+		}
+		detached = append(detached, p.name)
+	}
+	return detached, kept, nil
+}
+
+// detachAlertPartition detaches a partition of the alerts table and releases
+// the dedup keys of its alerts.
+func (db *DB) detachAlertPartition(ctx context.Context, table string) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM alert_dedup_keys k USING `+table+` a
+		WHERE k.dedup_key = a.dedup_key AND k.id = a.id
+	`); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `ALTER TABLE alerts DETACH PARTITION `+table); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// PurgeDetachedAlerts permanently removes the alerts of an event manager
+// from the partitions detached by DetachAlertPartitions, which alert
+// queries no longer reach, and returns the number of alerts removed.
+func (db *DB) PurgeDetachedAlerts(ctx context.Context, eventManagerID string) (int, error) {
+	query := `
+		SELECT c.relname FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind = 'r' AND NOT c.relispartition
+			AND n.nspname = current_schema()
+			AND c.relname LIKE 'alerts\_p%'
+		ORDER BY c.relname
+	`
+
+	rows, err := db.pool.Query(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list detached alert partitions: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return 0, fmt.Errorf("failed to scan detached alert partition: %w", err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to list detached alert partitions: %w", err)
+	}
+
+	purged := 0
+	for _, name := range names {
+		table := pgx.Identifier{name}.Sanitize()
+		result, err := db.pool.Exec(ctx, `DELETE FROM `+table+` WHERE event_manager_id = $1`, eventManagerID)
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge detached alert partition %s: %w", name, err)
+		}
+		purged += int(result.RowsAffected())
+	}
+	return purged, nil
+}
+
+// alertPartitions lists the monthly partitions attached to the alerts table,
+// oldest first. The default partition is not listed.
+func (db *DB) alertPartitions(ctx context.Context) ([]alertPartition, error) {
+	query := `
+		SELECT c.relname FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'alerts'::regclass
+		ORDER BY c.relname
+	`
+
+	rows, err := db.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert partitions: %w", err)
+	}
+	defer rows.Close()
+
+	var partitions []alertPartition
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan alert partition: %w", err)
+		}
+		if p, ok := parseAlertPartition(name); ok {
+			partitions = append(partitions, p)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list alert partitions: %w", err)
+	}
+
+	return partitions, nil
+}
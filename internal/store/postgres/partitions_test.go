@@ -0,0 +1,208 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+)
+
+func TestUpcomingAlertPartitions(t *testing.T) {
+	// Months are UTC, whatever the zone of now
+	now := time.Date(2026, 12, 1, 0, 30, 0, 0, time.FixedZone("CET", 3600))
+	partitions := upcomingAlertPartitions(now, 2)
+
+	want := []struct {
+		name     string
+		from, to time.Time
+	}{
+		{"alerts_p2026_11", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{"alerts_p2026_12", time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"alerts_p2027_01", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2027, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	if len(partitions) != len(want) {
+		t.Fatalf("upcomingAlertPartitions() = %d partitions, want %d", len(partitions), len(want))
+	}
+	for i, p := range partitions {
+		if p.name != want[i].name || !p.from().Equal(want[i].from) || !p.to().Equal(want[i].to) {
+			t.Errorf("partition %d = %s [%v, %v), want %s [%v, %v)",
+				i, p.name, p.from(), p.to(), want[i].name, want[i].from, want[i].to)
+		}
+	}
+
+	if partitions := upcomingAlertPartitions(now, 0); len(partitions) != 1 {
+		t.Errorf("upcomingAlertPartitions(ahead 0) = %d partitions, want 1", len(partitions))
+	}
+}
+
+func TestParseAlertPartition(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   time.Time
+		wantOK bool
+	}{
+		{"alerts_p2026_03", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), true},
+		{"alerts_default", time.Time{}, false},
+		{"alerts_p2026_13", time.Time{}, false},
+		{"alerts_history", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		p, ok := parseAlertPartition(tt.name)
+		if ok != tt.wantOK || !p.month.Equal(tt.want) {
+			t.Errorf("parseAlertPartition(%q) = %v, %v, want %v, %v", tt.name, p.month, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestExpiredAlertPartitions(t *testing.T) {
+	partitions := upcomingAlertPartitions(time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), 2)
+
+	// A partition expires once its whole month is before the cutoff
+	tests := []struct {
+		cutoff time.Time
+		want   []string
+	}{
+		{time.Date(2026, 1, 31, 23, 59, 59, 0, time.UTC), nil},
+		{time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), []string{"alerts_p2026_01"}},
+		{time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), []string{"alerts_p2026_01", "alerts_p2026_02"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, p := range expiredAlertPartitions(partitions, tt.cutoff) {
+			got = append(got, p.name)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("expiredAlertPartitions(%v) = %v, want %v", tt.cutoff, got, tt.want)
+		}
+	}
+}
+
+func TestCreateAlertPartitionQuery(t *testing.T) {
+	p := newAlertPartition(time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC))
+
+	want := `CREATE TABLE IF NOT EXISTS "alerts_p2026_03" PARTITION OF alerts ` +
+		`FOR VALUES FROM ('2026-03-01T00:00:00Z') TO ('2026-04-01T00:00:00Z')`
+	if got := createAlertPartitionQuery(p); got != want {
+		t.Errorf("createAlertPartitionQuery() = %s, want %s", got, want)
+	}
+}
+
+func TestMoveDefaultAlertsQueries(t *testing.T) {
+	p := newAlertPartition(time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC))
+	queries := moveDefaultAlertsQueries(p)
+
+	// The default partition is detached while the partition is created and
+	// attached again once the alerts moved
+	prefixes := []string{
+		"SET LOCAL argus.moving_alerts",
+		`ALTER TABLE alerts DETACH PARTITION "alerts_default"`,
+		`CREATE TABLE IF NOT EXISTS "alerts_p2026_03" PARTITION OF alerts`,
+		`INSERT INTO "alerts_p2026_03"`,
+		`DELETE FROM "alerts_default"`,
+		`ALTER TABLE alerts ATTACH PARTITION "alerts_default" DEFAULT`,
+	}
+	if len(queries) != len(prefixes) {
+		t.Fatalf("moveDefaultAlertsQueries() = %d queries, want %d", len(queries), len(prefixes))
+	}
+	for i, prefix := range prefixes {
+		if !strings.HasPrefix(queries[i], prefix) {
+			t.Errorf("query %d = %s, want prefix %s", i, queries[i], prefix)
+		}
+	}
+
+	bounds := `created_at >= '2026-03-01T00:00:00Z' AND created_at < '2026-04-01T00:00:00Z'`
+	for _, i := range []int{3, 4} {
+		if !strings.HasSuffix(queries[i], bounds) {
+			t.Errorf("query %d = %s, want bounds %s", i, queries[i], bounds)
+		}
+	}
+	// The generated search column can't be inserted
+	if strings.Contains(queries[3], "summary_search") {
+		t.Errorf("insert query selects summary_search: %s", queries[3])
+	}
+}
+
+func TestEnsureAlertPartitions_DefaultPartition(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewAlertRepository(db)
+	ctx := context.Background()
+
+	// Alerts of a month without a partition land in the default partition
+	old := newTestAlert("old-1", monthsAgo(6))
+	if err := repo.Create(ctx, old); err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	if got := partitionOf(t, db, "old-1"); got != alertDefaultPartition {
+		t.Fatalf("alert partition = %s, want %s", got, alertDefaultPartition)
+	}
+	history, err := repo.History(ctx, "old-1")
+	if err != nil {
+		t.Fatalf("History error: %v", err)
+	}
+
+	// Creating the partition of their month moves them into it
+	if err := db.EnsureAlertPartitions(ctx, old.CreatedAt, 0); err != nil {
+		t.Fatalf("EnsureAlertPartitions error: %v", err)
+	}
+	want := newAlertPartition(old.CreatedAt).name
+	if got := partitionOf(t, db, "old-1"); got != want {
+		t.Errorf("alert partition = %s, want %s", got, want)
+	}
+	if got, err := repo.GetByDedupKey(ctx, "old-1"); err != nil || got.ID != old.ID {
+		t.Errorf("GetByDedupKey() = %v, %v, want the moved alert", got, err)
+	}
+	if moved, _ := repo.History(ctx, "old-1"); len(moved) != len(history) {
+		t.Errorf("History() = %d revisions after the move, want %d", len(moved), len(history))
+	}
+
+	// Running again, e.g. on the next start, is a no-op
+	if err := db.EnsureAlertPartitions(ctx, old.CreatedAt, 0); err != nil {
+		t.Errorf("EnsureAlertPartitions again error: %v", err)
+	}
+	if err := db.RunMigrations(ctx); err != nil {
+		t.Errorf("RunMigrations again error: %v", err)
+	}
+}
+
+func TestDetachAlertPartitions(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewAlertRepository(db)
+	ctx := context.Background()
+
+	created := monthsAgo(6)
+	if err := db.EnsureAlertPartitions(ctx, created, 1); err != nil {
+		t.Fatalf("EnsureAlertPartitions error: %v", err)
+	}
+	resolved := newTestAlert("resolved-1", created)
+	resolved.Resolve(domain.Resolution{ResolvedBy: domain.ResolvedByEvent}, created)
+	if err := repo.Create(ctx, resolved); err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	if err := repo.Create(ctx, newTestAlert("open-1", created.AddDate(0, 1, 0))); err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+
+	detached, kept, err := db.DetachAlertPartitions(ctx, monthsAgo(1))
+	if err != nil {
+		t.Fatalf("DetachAlertPartitions error: %v", err)
+	}
+	wantDetached := newAlertPartition(created).name
+	wantKept := newAlertPartition(created.AddDate(0, 1, 0)).name
+	if len(detached) != 1 || detached[0] != wantDetached {
+		t.Errorf("detached = %v, want [%s]", detached, wantDetached)
+	}
+	if len(kept) != 1 || kept[0] != wantKept {
+		t.Errorf("kept = %v, want [%s]", kept, wantKept)
+	}
+
+	// The dedup keys of detached alerts are released
+	if _, err := repo.GetByDedupKey(ctx, "resolved-1"); !errors.Is(err, domain.ErrAlertNotFound) {
+		t.Errorf("GetByDedupKey(detached) error = %v, want %v", err, domain.ErrAlertNotFound)
+	}
+	if err := repo.Create(ctx, newTestAlert("resolved-1", monthsAgo(0))); err != nil {
+		t.Errorf("Create(detached dedup key) error: %v", err)
+	}
+}
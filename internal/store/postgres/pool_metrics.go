@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Descriptions of the connection pool metrics exported by the DB.
+var (
+	poolConnsDesc = prometheus.NewDesc(
+		"argus_postgres_pool_conns",
+		"PostgreSQL pool connections, by state (acquired, idle or constructing).",
+		[]string{"state"}, nil,
+	)
+	poolMaxConnsDesc = prometheus.NewDesc(
+		"argus_postgres_pool_max_conns",
+		"Maximum size of the PostgreSQL pool.",
+		nil, nil,
+	)
+	poolAcquiresDesc = prometheus.NewDesc(
+		"argus_postgres_pool_acquires_total",
+		"Connections acquired from the PostgreSQL pool.",
+		nil, nil,
+	)
+	poolEmptyAcquiresDesc = prometheus.NewDesc(
+		"argus_postgres_pool_empty_acquires_total",
+		"Acquires that waited for a connection, as the PostgreSQL pool had none idle.",
+		nil, nil,
+	)
+	poolAcquireWaitDesc = prometheus.NewDesc(
+		"argus_postgres_pool_acquire_wait_seconds_total",
+		"Time spent waiting for a connection of the PostgreSQL pool.",
+		nil, nil,
+	)
+	poolCanceledAcquiresDesc = prometheus.NewDesc(
+		"argus_postgres_pool_canceled_acquires_total",
+		"Acquires canceled before a connection of the PostgreSQL pool was available.",
+		nil, nil,
+	)
+)
+
+// Describe implements prometheus.Collector.
+func (db *DB) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolConnsDesc
+	ch <- poolMaxConnsDesc
+	ch <- poolAcquiresDesc
+	ch <- poolEmptyAcquiresDesc
+	ch <- poolAcquireWaitDesc
+	ch <- poolCanceledAcquiresDesc
+}
+
+// Collect implements prometheus.Collector, exporting the sizing of the
+// pool: a pool often empty, with acquires waiting, is too small.
+func (db *DB) Collect(ch chan<- prometheus.Metric) {
+	stat := db.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(poolConnsDesc, prometheus.GaugeValue, float64(stat.AcquiredConns()), "acquired")
+	ch <- prometheus.MustNewConstMetric(poolConnsDesc, prometheus.GaugeValue, float64(stat.IdleConns()), "idle")
+	ch <- prometheus.MustNewConstMetric(poolConnsDesc, prometheus.GaugeValue, float64(stat.ConstructingConns()), "constructing")
+	ch <- prometheus.MustNewConstMetric(poolMaxConnsDesc, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquiresDesc, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquiresDesc, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireWaitDesc, prometheus.CounterValue, stat.EmptyAcquireWaitTime().Seconds())
+	ch <- prometheus.MustNewConstMetric(poolCanceledAcquiresDesc, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestDB_PoolMetrics(t *testing.T) {
+	// The pool connects lazily, so its metrics are collected without a
+	// database
+	poolConfig, err := pgxpool.ParseConfig("postgres://argus@127.0.0.1:1/argus?pool_max_conns=7")
+	if err != nil {
+		t.Fatalf("ParseConfig error: %v", err)
+	}
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		t.Fatalf("NewWithConfig error: %v", err)
+	}
+	db := &DB{pool: pool}
+	defer db.Close()
+
+	if err := testutil.CollectAndCompare(db, strings.NewReader(`
+# HELP argus_postgres_pool_conns PostgreSQL pool connections, by state (acquired, idle or constructing).
+# TYPE argus_postgres_pool_conns gauge
+argus_postgres_pool_conns{state="acquired"} 0
+argus_postgres_pool_conns{state="constructing"} 0
+argus_postgres_pool_conns{state="idle"} 0
+# HELP argus_postgres_pool_max_conns Maximum size of the PostgreSQL pool.
+# TYPE argus_postgres_pool_max_conns gauge
+argus_postgres_pool_max_conns 7
+`), "argus_postgres_pool_conns", "argus_postgres_pool_max_conns"); err != nil {
+		t.Error(err)
+	}
+
+	// Every described metric is collected, so the DB registers cleanly
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(db); err != nil {
+		t.Fatalf("Register error: %v", err)
+	}
+	if count := testutil.CollectAndCount(db); count != 8 {
+		t.Errorf("collected %d metrics, want 8", count)
+	}
+}
@@ -0,0 +1,320 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"argus-go/internal/domain"
+)
+
+// queryRuleColumns is the column list selected for every query rule query.
+// The order must match scanQueryRule.
+const queryRuleColumns = `id, name, event_manager_id, datasource, query, params, condition, dedup_labels,
+			   summary, severity, class, interval_seconds, created_at, updated_at, composite,
+			   disabled, muted_until, template_id, template_values, missing_labels`
+
+// QueryRuleRepository implements store.QueryRuleRepository using PostgreSQL.
+type QueryRuleRepository struct {
+	db *DB
+}
+
+// NewQueryRuleRepository creates a new PostgreSQL-backed query rule repository.
+func NewQueryRuleRepository(db *DB) *QueryRuleRepository {
+	return &QueryRuleRepository{db: db}
+}
+
+// Create stores a new query rule.
+func (r *QueryRuleRepository) Create(ctx context.Context, rule *domain.QueryRule) error {
+	condition, err := json.Marshal(rule.Condition)
+	if err != nil {
+		return fmt.Errorf("failed to encode rule condition: %w", err)
+	}
+	params, err := encodeQueryParams(rule)
+	if err != nil {
+		return err
+	}
+	composite, err := encodeComposite(rule)
+	if err != nil {
+		return err
+	}
+	templateValues, err := json.Marshal(nonNilMap(rule.TemplateValues))
+	if err != nil {
+		return fmt.Errorf("failed to encode rule template values: %w", err)
+	}
+
+	query := `
+		INSERT INTO query_rules (
+			id, name, event_manager_id, datasource, query, condition, dedup_labels,
+			summary, severity, class, interval_seconds, created_at, updated_at, params, composite,
+			disabled, muted_until, template_id, template_values, missing_labels
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+	`
+
+	_, err = r.db.pool.Exec(ctx, query,
+		rule.ID,
+		rule.Name,
+		rule.EventManagerID,
+		rule.Datasource,
+		rule.Query,
+		condition,
+		dedupLabels(rule),
+		rule.Summary,
+		rule.Severity,
+		rule.Class,
+		int(time.Duration(rule.Interval)/time.Second),
+		rule.CreatedAt,
+		rule.UpdatedAt,
+		params,
+		composite,
+		rule.Disabled,
+		rule.MutedUntil,
+		rule.TemplateID,
+		templateValues,
+		rule.MissingLabels,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create query rule: %w", err)
+	}
+
+	return nil
+}
+
+// Update modifies an existing query rule.
+func (r *QueryRuleRepository) Update(ctx context.Context, rule *domain.QueryRule) error {
+	condition, err := json.Marshal(rule.Condition)
+	if err != nil {
+		return fmt.Errorf("failed to encode rule condition: %w", err)
+	}
+	params, err := encodeQueryParams(rule)
+	if err != nil {
+		return err
+	}
+	composite, err := encodeComposite(rule)
+	if err != nil {
+		return err
+	}
+	templateValues, err := json.Marshal(nonNilMap(rule.TemplateValues))
+	if err != nil {
+		return fmt.Errorf("failed to encode rule template values: %w", err)
+	}
+
+	query := `
+		UPDATE query_rules SET
+			name = $2,
+			event_manager_id = $3,
+			datasource = $4,
+			query = $5,
+			condition = $6,
+			dedup_labels = $7,
+			summary = $8,
+			severity = $9,
+			class = $10,
+			interval_seconds = $11,
+			updated_at = $12,
+			params = $13,
+			composite = $14,
+			disabled = $15,
+			muted_until = $16,
+			template_id = $17,
+			template_values = $18,
+			missing_labels = $19
+		WHERE id = $1
+	`
+
+	result, err := r.db.pool.Exec(ctx, query,
+		rule.ID,
+		rule.Name,
+		rule.EventManagerID,
+		rule.Datasource,
+		rule.Query,
+		condition,
+		dedupLabels(rule),
+		rule.Summary,
+		rule.Severity,
+		rule.Class,
+		int(time.Duration(rule.Interval)/time.Second),
+		rule.UpdatedAt,
+		params,
+		composite,
+		rule.Disabled,
+		rule.MutedUntil,
+		rule.TemplateID,
+		templateValues,
+		rule.MissingLabels,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update query rule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrQueryRuleNotFound
+	}
+
+	return nil
+}
+
+// Delete permanently removes a query rule by ID.
+func (r *QueryRuleRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.pool.Exec(ctx, `DELETE FROM query_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete query rule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrQueryRuleNotFound
+	}
+
+	return nil
+}
+
+// GetByID retrieves a query rule by its ID.
+func (r *QueryRuleRepository) GetByID(ctx context.Context, id string) (*domain.QueryRule, error) {
+	query := `
+		SELECT ` + queryRuleColumns + `
+		FROM query_rules
+		WHERE id = $1
+	`
+
+	rule, err := scanQueryRule(r.db.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrQueryRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get query rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// List retrieves every query rule, oldest first.
+func (r *QueryRuleRepository) List(ctx context.Context) ([]*domain.QueryRule, error) {
+	query := `
+		SELECT ` + queryRuleColumns + `
+		FROM query_rules
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list query rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []*domain.QueryRule{}
+	for rows.Next() {
+		rule, err := scanQueryRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan query rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating query rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// scanQueryRule scans a single row into a QueryRule.
+func scanQueryRule(row pgx.Row) (*domain.QueryRule, error) {
+	var rule domain.QueryRule
+	var params, condition, composite, templateValues []byte
+	var intervalSeconds int
+
+	err := row.Scan(
+		&rule.ID,
+		&rule.Name,
+		&rule.EventManagerID,
+		&rule.Datasource,
+		&rule.Query,
+		&params,
+		&condition,
+		&rule.DedupLabels,
+		&rule.Summary,
+		&rule.Severity,
+		&rule.Class,
+		&intervalSeconds,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+		&composite,
+		&rule.Disabled,
+		&rule.MutedUntil,
+		&rule.TemplateID,
+		&templateValues,
+		&rule.MissingLabels,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(condition, &rule.Condition); err != nil {
+		return nil, fmt.Errorf("failed to decode rule condition: %w", err)
+	}
+	if err := json.Unmarshal(params, &rule.Params); err != nil {
+		return nil, fmt.Errorf("failed to decode rule params: %w", err)
+	}
+	if len(rule.Params) == 0 {
+		rule.Params = nil
+	}
+	if err := json.Unmarshal(templateValues, &rule.TemplateValues); err != nil {
+		return nil, fmt.Errorf("failed to decode rule template values: %w", err)
+	}
+	if len(rule.TemplateValues) == 0 {
+		rule.TemplateValues = nil
+	}
+	if composite != nil {
+		if err := json.Unmarshal(composite, &rule.Composite); err != nil {
+			return nil, fmt.Errorf("failed to decode composite condition: %w", err)
+		}
+	}
+	rule.Interval = domain.Duration(time.Duration(intervalSeconds) * time.Second)
+	if len(rule.DedupLabels) == 0 {
+		rule.DedupLabels = nil
+	}
+
+	return &rule, nil
+}
+
+// dedupLabels returns the dedup labels of a rule for the NOT NULL
+// dedup_labels column, which rejects a nil slice.
+func dedupLabels(rule *domain.QueryRule) []string {
+	if rule.DedupLabels == nil {
+		return []string{}
+	}
+	return rule.DedupLabels
+}
+
+// encodeQueryParams encodes the params of a rule for the NOT NULL params
+// column, storing no params as an empty object.
+func encodeQueryParams(rule *domain.QueryRule) ([]byte, error) {
+	if rule.Params == nil {
+		return []byte("{}"), nil
+	}
+	params, err := json.Marshal(rule.Params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rule params: %w", err)
+	}
+	return params, nil
+}
+
+// encodeComposite encodes the composite condition of a rule, or NULL for a
+// rule running a query.
+func encodeComposite(rule *domain.QueryRule) ([]byte, error) {
+	if rule.Composite == nil {
+		return nil, nil
+	}
+	composite, err := json.Marshal(rule.Composite)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode composite condition: %w", err)
+	}
+	return composite, nil
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"argus-go/internal/domain"
+)
+
+// RemediationLogRepository implements store.RemediationLogRepository using PostgreSQL.
+type RemediationLogRepository struct {
+	db *DB
+}
+
+// NewRemediationLogRepository creates a new PostgreSQL-backed remediation log.
+func NewRemediationLogRepository(db *DB) *RemediationLogRepository {
+	return &RemediationLogRepository{db: db}
+}
+
+// Record appends a run of a remediation action to the log.
+func (r *RemediationLogRepository) Record(ctx context.Context, record *domain.RemediationRecord) error {
+	query := `
+		INSERT INTO remediation_log (dedup_key, event_manager_id, action, target, status, detail, executed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.pool.Exec(ctx, query,
+		record.DedupKey,
+		record.EventManagerID,
+		record.Action,
+		record.Target,
+		record.Status,
+		record.Detail,
+		record.ExecutedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to record remediation: %w", err)
+	}
+
+	return nil
+}
+
+// ListByDedupKey retrieves the actions run for an alert, oldest first.
+func (r *RemediationLogRepository) ListByDedupKey(ctx context.Context, dedupKey string) ([]*domain.RemediationRecord, error) {
+	query := `
+		SELECT dedup_key, event_manager_id, action, target, status, detail, executed_at
+		FROM remediation_log
+		WHERE dedup_key = $1
+		ORDER BY executed_at, id
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, dedupKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remediations: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*domain.RemediationRecord{}
+
+	for rows.Next() {
+		var record domain.RemediationRecord
+		if err := rows.Scan(
+			&record.DedupKey,
+			&record.EventManagerID,
+			&record.Action,
+			&record.Target,
+			&record.Status,
+			&record.Detail,
+			&record.ExecutedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan remediation: %w", err)
+		}
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating remediations: %w", err)
+	}
+
+	return records, nil
+}
+
+// DeleteByEventManager removes the actions run for an event manager.
+func (r *RemediationLogRepository) DeleteByEventManager(ctx context.Context, eventManagerID string) (int, error) {
+	result, err := r.db.pool.Exec(ctx, `DELETE FROM remediation_log WHERE event_manager_id = $1`, eventManagerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete remediation actions: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
@@ -0,0 +1,258 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"argus-go/internal/domain"
+)
+
+// ReportRepository implements store.ReportRepository using SQL aggregation queries.
+type ReportRepository struct {
+	db *DB
+}
+
+// NewReportRepository creates a new PostgreSQL-backed report repository.
+func NewReportRepository(db *DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// reportConditions builds the shared WHERE clause for report queries.
+func reportConditions(filter domain.ReportFilter) (string, []interface{}) {
+	where := "created_at >= $1 AND created_at < $2"
+	args := []interface{}{filter.From, filter.To}
+
+	if filter.EventManagerID != "" {
+		where += " AND event_manager_id = $3"
+		args = append(args, filter.EventManagerID)
+	}
+
+	return where, args
+}
+
+// MTTR returns acknowledgement and resolution timings per event manager.
+func (r *ReportRepository) MTTR(ctx context.Context, filter domain.ReportFilter) ([]*domain.MTTRStats, error) {
+	where, args := reportConditions(filter)
+	query := fmt.Sprintf(`
+		SELECT event_manager_id,
+			   COUNT(*),
+			   COUNT(acknowledged_at),
+			   COUNT(resolved_at) FILTER (WHERE status = 'resolved'),
+			   COALESCE(AVG(EXTRACT(EPOCH FROM acknowledged_at - created_at)), 0),
+			   COALESCE(AVG(EXTRACT(EPOCH FROM resolved_at - created_at)) FILTER (WHERE status = 'resolved'), 0)
+		FROM alerts
+		WHERE %s
+		GROUP BY event_manager_id
+		ORDER BY event_manager_id
+	`, where)
+
+	rows, err := r.db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute mttr report: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*domain.MTTRStats
+	for rows.Next() {
+		var stats domain.MTTRStats
+		if err := rows.Scan(
+			&stats.EventManagerID,
+			&stats.AlertCount,
+			&stats.AcknowledgedCount,
+			&stats.ResolvedCount,
+			&stats.MTTASeconds,
+			&stats.MTTRSeconds,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan mttr stats: %w", err)
+		}
+		results = append(results, &stats)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating mttr stats: %w", err)
+	}
+
+	return results, nil
+}
+
+// DailyVolume returns the number of alerts created per event manager per day.
+func (r *ReportRepository) DailyVolume(ctx context.Context, filter domain.ReportFilter) ([]*domain.DailyVolume, error) {
+	where, args := reportConditions(filter)
+	query := fmt.Sprintf(`
+		SELECT event_manager_id,
+			   date_trunc('day', created_at AT TIME ZONE 'UTC') AS day,
+			   COUNT(*)
+		FROM alerts
+		WHERE %s
+		GROUP BY event_manager_id, day
+		ORDER BY event_manager_id, day
+	`, where)
+
+	rows, err := r.db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute volume report: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*domain.DailyVolume
+	for rows.Next() {
+		var volume domain.DailyVolume
+		var day time.Time
+		if err := rows.Scan(&volume.EventManagerID, &day, &volume.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan daily volume: %w", err)
+		}
+		volume.Day = domain.ReportDay(day)
+		results = append(results, &volume)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating daily volume: %w", err)
+	}
+
+	return results, nil
+}
+
+// TopDedupKeys returns the most frequently triggered dedup keys per event manager.
+func (r *ReportRepository) TopDedupKeys(ctx context.Context, filter domain.ReportFilter) ([]*domain.DedupKeyVolume, error) {
+	where, args := reportConditions(filter)
+	limit := filter.TopLimit
+	if limit <= 0 {
+		limit = domain.DefaultReportTopLimit
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		SELECT event_manager_id, dedup_key, summary, trigger_count
+		FROM (
+			SELECT event_manager_id, dedup_key, summary, trigger_count,
+				   ROW_NUMBER() OVER (
+					   PARTITION BY event_manager_id
+					   ORDER BY trigger_count DESC, dedup_key
+				   ) AS rank
+			FROM alerts
+			WHERE %s
+		) ranked
+		WHERE rank <= $%d
+		ORDER BY event_manager_id, trigger_count DESC, dedup_key
+	`, where, len(args))
+
+	rows, err := r.db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute top dedup keys: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*domain.DedupKeyVolume
+	for rows.Next() {
+		var volume domain.DedupKeyVolume
+		if err := rows.Scan(
+			&volume.EventManagerID,
+			&volume.DedupKey,
+			&volume.Summary,
+			&volume.TriggerCount,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan dedup key volume: %w", err)
+		}
+		results = append(results, &volume)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating dedup key volume: %w", err)
+	}
+
+	return results, nil
+}
+
+// NoiseStats returns trigger/resolve counters for alerts active within the range.
+func (r *ReportRepository) NoiseStats(ctx context.Context, filter domain.ReportFilter) ([]*domain.NoiseStats, error) {
+	query := `
+		SELECT event_manager_id, dedup_key, class, summary, trigger_count, resolve_count, created_at
+		FROM alerts
+		WHERE updated_at >= $1 AND created_at < $2
+	`
+	args := []interface{}{filter.From, filter.To}
+
+	if filter.EventManagerID != "" {
+		query += " AND event_manager_id = $3"
+		args = append(args, filter.EventManagerID)
+	}
+
+	rows, err := r.db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query noise stats: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*domain.NoiseStats
+	for rows.Next() {
+		var st domain.NoiseStats
+		if err := rows.Scan(
+			&st.EventManagerID,
+			&st.DedupKey,
+			&st.Class,
+			&st.Summary,
+			&st.TriggerCount,
+			&st.ResolveCount,
+			&st.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan noise stats: %w", err)
+		}
+		results = append(results, &st)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating noise stats: %w", err)
+	}
+
+	return results, nil
+}
+
+// SourceStats returns the alerts created in the range per event manager and origin.
+func (r *ReportRepository) SourceStats(ctx context.Context, filter domain.ReportFilter) ([]*domain.SourceStats, error) {
+	where, args := reportConditions(filter)
+	query := fmt.Sprintf(`
+		SELECT event_manager_id,
+			   COALESCE(origin->>'source', '') AS source,
+			   COALESCE(origin->>'ip', '') AS ip,
+			   COALESCE(origin->>'user_agent', '') AS user_agent,
+			   COALESCE(origin->>'via', '') AS via,
+			   COUNT(*),
+			   COUNT(*) FILTER (WHERE status = 'active'),
+			   COALESCE(SUM(trigger_count), 0) AS triggers
+		FROM alerts
+		WHERE %s
+		GROUP BY event_manager_id, source, ip, user_agent, via
+		ORDER BY event_manager_id, triggers DESC, source, ip, user_agent, via
+	`, where)
+
+	rows, err := r.db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute source report: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*domain.SourceStats
+	for rows.Next() {
+		var stats domain.SourceStats
+		if err := rows.Scan(
+			&stats.EventManagerID,
+			&stats.Source,
+			&stats.IP,
+			&stats.UserAgent,
+			&stats.Via,
+			&stats.AlertCount,
+			&stats.ActiveCount,
+			&stats.TriggerCount,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan source stats: %w", err)
+		}
+		results = append(results, &stats)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating source stats: %w", err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"argus-go/internal/domain"
+)
+
+// reportScheduleColumns are the columns scanned by scanReportSchedule.
+const reportScheduleColumns = `id, name, event_manager_id, frequency, format, recipients,
+			top_limit, last_report_to, created_at, updated_at`
+
+// ReportScheduleRepository implements store.ReportScheduleRepository using
+// PostgreSQL.
+type ReportScheduleRepository struct {
+	db *DB
+}
+
+// NewReportScheduleRepository creates a new PostgreSQL-backed report schedule
+// repository.
+func NewReportScheduleRepository(db *DB) *ReportScheduleRepository {
+	return &ReportScheduleRepository{db: db}
+}
+
+// Create stores a new report schedule.
+func (r *ReportScheduleRepository) Create(ctx context.Context, schedule *domain.ReportSchedule) error {
+	recipients, err := json.Marshal(schedule.Recipients)
+	if err != nil {
+		return fmt.Errorf("failed to encode report recipients: %w", err)
+	}
+
+	query := `
+		INSERT INTO report_schedules (` + reportScheduleColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err = r.db.pool.Exec(ctx, query,
+		schedule.ID,
+		schedule.Name,
+		schedule.EventManagerID,
+		schedule.Frequency,
+		schedule.Format,
+		recipients,
+		schedule.TopLimit,
+		schedule.LastReportTo,
+		schedule.CreatedAt,
+		schedule.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create report schedule: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces the settings of a report schedule, leaving the end of its
+// last report as it is.
+func (r *ReportScheduleRepository) Update(ctx context.Context, schedule *domain.ReportSchedule) error {
+	recipients, err := json.Marshal(schedule.Recipients)
+	if err != nil {
+		return fmt.Errorf("failed to encode report recipients: %w", err)
+	}
+
+	query := `
+		UPDATE report_schedules
+		SET name = $2, event_manager_id = $3, frequency = $4, format = $5,
+			recipients = $6, top_limit = $7, updated_at = $8
+		WHERE id = $1
+	`
+
+	result, err := r.db.pool.Exec(ctx, query,
+		schedule.ID,
+		schedule.Name,
+		schedule.EventManagerID,
+		schedule.Frequency,
+		schedule.Format,
+		recipients,
+		schedule.TopLimit,
+		schedule.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update report schedule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrReportScheduleNotFound
+	}
+
+	return nil
+}
+
+// Delete permanently removes a report schedule by ID.
+func (r *ReportScheduleRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.pool.Exec(ctx, `DELETE FROM report_schedules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete report schedule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrReportScheduleNotFound
+	}
+
+	return nil
+}
+
+// GetByID retrieves a report schedule by its ID.
+func (r *ReportScheduleRepository) GetByID(ctx context.Context, id string) (*domain.ReportSchedule, error) {
+	query := `SELECT ` + reportScheduleColumns + ` FROM report_schedules WHERE id = $1`
+
+	schedule, err := scanReportSchedule(r.db.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrReportScheduleNotFound
+		}
+		return nil, fmt.Errorf("failed to get report schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// List retrieves every report schedule, oldest first.
+func (r *ReportScheduleRepository) List(ctx context.Context) ([]*domain.ReportSchedule, error) {
+	query := `SELECT ` + reportScheduleColumns + ` FROM report_schedules ORDER BY created_at, id`
+
+	rows, err := r.db.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := []*domain.ReportSchedule{}
+	for rows.Next() {
+		schedule, err := scanReportSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report schedule: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating report schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// MarkSent sets the end of the last report of a schedule to to, if it is
+// still previous.
+func (r *ReportScheduleRepository) MarkSent(ctx context.Context, id string, previous, to *time.Time) (bool, error) {
+	query := `
+		UPDATE report_schedules
+		SET last_report_to = $3
+		WHERE id = $1 AND last_report_to IS NOT DISTINCT FROM $2::timestamptz
+	`
+
+	result, err := r.db.pool.Exec(ctx, query, id, previous, to)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark report sent: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+// scanReportSchedule scans a single row into a ReportSchedule.
+func scanReportSchedule(row pgx.Row) (*domain.ReportSchedule, error) {
+	var schedule domain.ReportSchedule
+	var recipients []byte
+
+	err := row.Scan(
+		&schedule.ID,
+		&schedule.Name,
+		&schedule.EventManagerID,
+		&schedule.Frequency,
+		&schedule.Format,
+		&recipients,
+		&schedule.TopLimit,
+		&schedule.LastReportTo,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(recipients, &schedule.Recipients); err != nil {
+		return nil, fmt.Errorf("failed to decode report recipients: %w", err)
+	}
+
+	return &schedule, nil
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
+)
+
+// alertPartitioner creates and detaches the partitions of the alerts table.
+type alertPartitioner interface {
+	EnsureAlertPartitions(ctx context.Context, now time.Time, ahead int) error
+	DetachAlertPartitions(ctx context.Context, cutoff time.Time) (detached, kept []string, err error)
+}
+
+// RetentionJob maintains the monthly partitions of the alerts table: it
+// creates the partitions of upcoming months, so alerts never fall back to
+// the default partition, and detaches those past the retention period.
+type RetentionJob struct {
+	db     alertPartitioner
+	cfg    config.RetentionConfig
+	clock  clock.Clock
+	logger *slog.Logger
+}
+
+// NewRetentionJob creates a retention job for the alerts table of db.
+func NewRetentionJob(db *DB, cfg config.RetentionConfig, clk clock.Clock, logger *slog.Logger) *RetentionJob {
+	return &RetentionJob{
+		db:     db,
+		cfg:    cfg,
+		clock:  clk,
+		logger: logger,
+	}
+}
+
+// Start runs the job right away and then every check interval, until the
+// context is canceled.
+func (j *RetentionJob) Start(ctx context.Context) {
+	j.logger.Info("starting retention job",
+		"check_interval", j.cfg.CheckInterval,
+		"partitions_ahead", j.cfg.PartitionsAhead,
+		"alerts", j.cfg.Alerts,
+	)
+
+	ticker := time.NewTicker(j.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.Run(ctx); err != nil && ctx.Err() == nil {
+			j.logger.Warn("retention job failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run creates the missing partitions and, if alerts expire, detaches the
+// expired ones.
+func (j *RetentionJob) Run(ctx context.Context) error {
+	now := j.clock.Now()
+	if err := j.db.EnsureAlertPartitions(ctx, now, j.cfg.PartitionsAhead); err != nil {
+		return err
+	}
+	if j.cfg.Alerts <= 0 {
+		return nil
+	}
+
+	detached, kept, err := j.db.DetachAlertPartitions(ctx, now.Add(-j.cfg.Alerts))
+	for _, name := range detached {
+		j.logger.Info("detached expired alert partition", "partition", name)
+	}
+	if len(kept) > 0 {
+		j.logger.Warn("keeping expired alert partitions with open alerts", "partitions", kept)
+	}
+	return err
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
+)
+
+// stubPartitioner records the calls of a retention job.
+type stubPartitioner struct {
+	ensured  []time.Time
+	ahead    int
+	cutoffs  []time.Time
+	detached []string
+	kept     []string
+	err      error
+}
+
+func (p *stubPartitioner) EnsureAlertPartitions(_ context.Context, now time.Time, ahead int) error {
+	p.ensured = append(p.ensured, now)
+	p.ahead = ahead
+	return p.err
+}
+
+func (p *stubPartitioner) DetachAlertPartitions(_ context.Context, cutoff time.Time) ([]string, []string, error) {
+	p.cutoffs = append(p.cutoffs, cutoff)
+	return p.detached, p.kept, nil
+}
+
+func newTestRetentionJob(p *stubPartitioner, cfg config.RetentionConfig, clk clock.Clock) *RetentionJob {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	job := NewRetentionJob(nil, cfg, clk, logger)
+	job.db = p
+	return job
+}
+
+func TestRetentionJob_Run(t *testing.T) {
+	now := time.Date(2026, 10, 15, 14, 30, 0, 0, time.UTC)
+	clk := clock.NewFake(now)
+	ctx := context.Background()
+
+	// Without alert retention, partitions are only created
+	p := &stubPartitioner{}
+	job := newTestRetentionJob(p, config.RetentionConfig{PartitionsAhead: 2}, clk)
+	if err := job.Run(ctx); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if len(p.ensured) != 1 || !p.ensured[0].Equal(now) || p.ahead != 2 {
+		t.Errorf("ensured partitions at %v, %d ahead, want %v, 2 ahead", p.ensured, p.ahead, now)
+	}
+	if len(p.cutoffs) != 0 {
+		t.Errorf("detached partitions before %v without retention", p.cutoffs)
+	}
+
+	// With it, partitions of the months before the retention period are detached
+	p = &stubPartitioner{detached: []string{"alerts_p2026_01"}, kept: []string{"alerts_p2026_02"}}
+	job = newTestRetentionJob(p, config.RetentionConfig{PartitionsAhead: 1, Alerts: 90 * 24 * time.Hour}, clk)
+	if err := job.Run(ctx); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if want := now.Add(-90 * 24 * time.Hour); len(p.cutoffs) != 1 || !p.cutoffs[0].Equal(want) {
+		t.Errorf("detach cutoffs = %v, want %v", p.cutoffs, want)
+	}
+
+	// Failing to create partitions detaches nothing
+	p = &stubPartitioner{err: errors.New("connection refused")}
+	job = newTestRetentionJob(p, config.RetentionConfig{Alerts: time.Hour}, clk)
+	if err := job.Run(ctx); err == nil {
+		t.Error("Run error = nil, want the partition error")
+	}
+	if len(p.cutoffs) != 0 {
+		t.Errorf("detached partitions before %v although creating partitions failed", p.cutoffs)
+	}
+}
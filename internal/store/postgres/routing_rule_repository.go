@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"argus-go/internal/domain"
+)
+
+// RoutingRuleRepository implements store.RoutingRuleRepository using PostgreSQL.
+type RoutingRuleRepository struct {
+	db *DB
+}
+
+// NewRoutingRuleRepository creates a new PostgreSQL-backed routing rule repository.
+func NewRoutingRuleRepository(db *DB) *RoutingRuleRepository {
+	return &RoutingRuleRepository{db: db}
+}
+
+// Create stores a new routing rule.
+func (r *RoutingRuleRepository) Create(ctx context.Context, rule *domain.RoutingRule) error {
+	match, err := json.Marshal(rule.Match)
+	if err != nil {
+		return fmt.Errorf("failed to encode route matcher: %w", err)
+	}
+
+	query := `
+		INSERT INTO routing_rules (
+			id, name, priority, match, event_manager_id, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = r.db.pool.Exec(ctx, query,
+		rule.ID,
+		rule.Name,
+		rule.Priority,
+		match,
+		rule.EventManagerID,
+		rule.CreatedAt,
+		rule.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create routing rule: %w", err)
+	}
+
+	return nil
+}
+
+// Update modifies an existing routing rule.
+func (r *RoutingRuleRepository) Update(ctx context.Context, rule *domain.RoutingRule) error {
+	match, err := json.Marshal(rule.Match)
+	if err != nil {
+		return fmt.Errorf("failed to encode route matcher: %w", err)
+	}
+
+	query := `
+		UPDATE routing_rules SET
+			name = $2,
+			priority = $3,
+			match = $4,
+			event_manager_id = $5,
+			updated_at = $6
+		WHERE id = $1
+	`
+
+	result, err := r.db.pool.Exec(ctx, query,
+		rule.ID,
+		rule.Name,
+		rule.Priority,
+		match,
+		rule.EventManagerID,
+		rule.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update routing rule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrRoutingRuleNotFound
+	}
+
+	return nil
+}
+
+// Delete permanently removes a routing rule by ID.
+func (r *RoutingRuleRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.pool.Exec(ctx, `DELETE FROM routing_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete routing rule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrRoutingRuleNotFound
+	}
+
+	return nil
+}
+
+// GetByID retrieves a routing rule by its ID.
+func (r *RoutingRuleRepository) GetByID(ctx context.Context, id string) (*domain.RoutingRule, error) {
+	query := `
+		SELECT id, name, priority, match, event_manager_id, created_at, updated_at
+		FROM routing_rules
+		WHERE id = $1
+	`
+
+	rule, err := scanRoutingRule(r.db.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrRoutingRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get routing rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// List retrieves all routing rules in evaluation order.
+func (r *RoutingRuleRepository) List(ctx context.Context) ([]*domain.RoutingRule, error) {
+	query := `
+		SELECT id, name, priority, match, event_manager_id, created_at, updated_at
+		FROM routing_rules
+		ORDER BY priority, created_at, id
+	`
+
+	rows, err := r.db.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list routing rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []*domain.RoutingRule{}
+	for rows.Next() {
+		rule, err := scanRoutingRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan routing rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating routing rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// scanRoutingRule scans a single row into a RoutingRule.
+func scanRoutingRule(row pgx.Row) (*domain.RoutingRule, error) {
+	var rule domain.RoutingRule
+	var match []byte
+
+	err := row.Scan(
+		&rule.ID,
+		&rule.Name,
+		&rule.Priority,
+		&match,
+		&rule.EventManagerID,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(match, &rule.Match); err != nil {
+		return nil, fmt.Errorf("failed to decode route matcher: %w", err)
+	}
+
+	return &rule, nil
+}
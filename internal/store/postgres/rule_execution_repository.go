@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"argus-go/internal/domain"
+)
+
+// RuleExecutionRepository implements store.RuleExecutionRepository using PostgreSQL.
+type RuleExecutionRepository struct {
+	db *DB
+}
+
+// NewRuleExecutionRepository creates a new PostgreSQL-backed rule execution log.
+func NewRuleExecutionRepository(db *DB) *RuleExecutionRepository {
+	return &RuleExecutionRepository{db: db}
+}
+
+// Record appends a run of a rule, and removes its runs older than the
+// latest keep.
+func (r *RuleExecutionRepository) Record(ctx context.Context, execution *domain.RuleExecution, keep int) error {
+	params, err := json.Marshal(nonNilMap(execution.Params))
+	if err != nil {
+		return fmt.Errorf("failed to encode execution params: %w", err)
+	}
+	outcomes, err := json.Marshal(nonNilMap(execution.Outcomes))
+	if err != nil {
+		return fmt.Errorf("failed to encode execution outcomes: %w", err)
+	}
+
+	query := `
+		INSERT INTO rule_executions (
+			rule_id, started_at, duration_ms, datasource, query, params, outcomes,
+			series, breaching, triggered, resolved, error, muted
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	_, err = r.db.pool.Exec(ctx, query,
+		execution.RuleID,
+		execution.StartedAt,
+		time.Duration(execution.Duration).Milliseconds(),
+		execution.Datasource,
+		execution.Query,
+		params,
+		outcomes,
+		execution.Series,
+		nonNilSlice(execution.Breaching),
+		nonNilSlice(execution.Triggered),
+		nonNilSlice(execution.Resolved),
+		execution.Error,
+		execution.Muted,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record rule execution: %w", err)
+	}
+
+	// Remove the runs older than the latest keep
+	query = `
+		DELETE FROM rule_executions
+		WHERE rule_id = $1 AND id < (
+			SELECT MIN(id) FROM (
+				SELECT id FROM rule_executions WHERE rule_id = $1 ORDER BY id DESC LIMIT $2
+			) latest
+		)
+	`
+	if _, err := r.db.pool.Exec(ctx, query, execution.RuleID, keep); err != nil {
+		return fmt.Errorf("failed to prune rule executions: %w", err)
+	}
+
+	return nil
+}
+
+// ListByRule retrieves the runs of a rule, newest first, at most limit.
+func (r *RuleExecutionRepository) ListByRule(ctx context.Context, ruleID string, limit int) ([]*domain.RuleExecution, error) {
+	query := `
+		SELECT rule_id, started_at, duration_ms, datasource, query, params, outcomes,
+			   series, breaching, triggered, resolved, error, muted
+		FROM rule_executions
+		WHERE rule_id = $1
+		ORDER BY id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, ruleID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rule executions: %w", err)
+	}
+	defer rows.Close()
+
+	executions := []*domain.RuleExecution{}
+	for rows.Next() {
+		var execution domain.RuleExecution
+		var durationMS int64
+		var params, outcomes []byte
+		if err := rows.Scan(
+			&execution.RuleID,
+			&execution.StartedAt,
+			&durationMS,
+			&execution.Datasource,
+			&execution.Query,
+			&params,
+			&outcomes,
+			&execution.Series,
+			&execution.Breaching,
+			&execution.Triggered,
+			&execution.Resolved,
+			&execution.Error,
+			&execution.Muted,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan rule execution: %w", err)
+		}
+
+		execution.Duration = domain.Duration(time.Duration(durationMS) * time.Millisecond)
+		if err := json.Unmarshal(params, &execution.Params); err != nil {
+			return nil, fmt.Errorf("failed to decode execution params: %w", err)
+		}
+		if err := json.Unmarshal(outcomes, &execution.Outcomes); err != nil {
+			return nil, fmt.Errorf("failed to decode execution outcomes: %w", err)
+		}
+		executions = append(executions, &execution)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rule executions: %w", err)
+	}
+
+	return executions, nil
+}
+
+// DeleteByRule removes every run of a rule.
+func (r *RuleExecutionRepository) DeleteByRule(ctx context.Context, ruleID string) error {
+	if _, err := r.db.pool.Exec(ctx, `DELETE FROM rule_executions WHERE rule_id = $1`, ruleID); err != nil {
+		return fmt.Errorf("failed to delete rule executions: %w", err)
+	}
+	return nil
+}
+
+// nonNilSlice returns s, or an empty slice for the NOT NULL array columns,
+// which reject a nil slice.
+func nonNilSlice(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// nonNilMap returns m, or an empty map, so it encodes as an object.
+func nonNilMap[V any](m map[string]V) map[string]V {
+	if m == nil {
+		return map[string]V{}
+	}
+	return m
+}
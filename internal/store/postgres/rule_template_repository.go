@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"argus-go/internal/domain"
+)
+
+// ruleTemplateColumns is the column list selected for every rule template
+// query. The order must match scanRuleTemplate.
+const ruleTemplateColumns = `id, name, rule, variables, created_at, updated_at`
+
+// RuleTemplateRepository implements store.RuleTemplateRepository using PostgreSQL.
+type RuleTemplateRepository struct {
+	db *DB
+}
+
+// NewRuleTemplateRepository creates a new PostgreSQL-backed rule template repository.
+func NewRuleTemplateRepository(db *DB) *RuleTemplateRepository {
+	return &RuleTemplateRepository{db: db}
+}
+
+// Create stores a new rule template.
+func (r *RuleTemplateRepository) Create(ctx context.Context, template *domain.RuleTemplate) error {
+	rule, err := json.Marshal(template.Rule)
+	if err != nil {
+		return fmt.Errorf("failed to encode template rule: %w", err)
+	}
+
+	query := `
+		INSERT INTO rule_templates (id, name, rule, variables, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err = r.db.pool.Exec(ctx, query,
+		template.ID,
+		template.Name,
+		rule,
+		nonNilSlice(template.Variables),
+		template.CreatedAt,
+		template.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create rule template: %w", err)
+	}
+
+	return nil
+}
+
+// Update modifies an existing rule template.
+func (r *RuleTemplateRepository) Update(ctx context.Context, template *domain.RuleTemplate) error {
+	rule, err := json.Marshal(template.Rule)
+	if err != nil {
+		return fmt.Errorf("failed to encode template rule: %w", err)
+	}
+
+	query := `
+		UPDATE rule_templates SET
+			name = $2,
+			rule = $3,
+			variables = $4,
+			updated_at = $5
+		WHERE id = $1
+	`
+
+	result, err := r.db.pool.Exec(ctx, query,
+		template.ID,
+		template.Name,
+		rule,
+		nonNilSlice(template.Variables),
+		template.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update rule template: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrRuleTemplateNotFound
+	}
+
+	return nil
+}
+
+// Delete permanently removes a rule template by ID.
+func (r *RuleTemplateRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.pool.Exec(ctx, `DELETE FROM rule_templates WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete rule template: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrRuleTemplateNotFound
+	}
+
+	return nil
+}
+
+// GetByID retrieves a rule template by its ID.
+func (r *RuleTemplateRepository) GetByID(ctx context.Context, id string) (*domain.RuleTemplate, error) {
+	query := `
+		SELECT ` + ruleTemplateColumns + `
+		FROM rule_templates
+		WHERE id = $1
+	`
+
+	template, err := scanRuleTemplate(r.db.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrRuleTemplateNotFound
+		}
+		return nil, fmt.Errorf("failed to get rule template: %w", err)
+	}
+
+	return template, nil
+}
+
+// List retrieves every rule template, oldest first.
+func (r *RuleTemplateRepository) List(ctx context.Context) ([]*domain.RuleTemplate, error) {
+	query := `
+		SELECT ` + ruleTemplateColumns + `
+		FROM rule_templates
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rule templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []*domain.RuleTemplate{}
+	for rows.Next() {
+		template, err := scanRuleTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rule template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rule templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// scanRuleTemplate scans a single row into a RuleTemplate.
+func scanRuleTemplate(row pgx.Row) (*domain.RuleTemplate, error) {
+	var template domain.RuleTemplate
+	var rule []byte
+
+	err := row.Scan(
+		&template.ID,
+		&template.Name,
+		&rule,
+		&template.Variables,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(rule, &template.Rule); err != nil {
+		return nil, fmt.Errorf("failed to decode template rule: %w", err)
+	}
+
+	return &template, nil
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RunnerRegistry implements store.RunnerRegistry using PostgreSQL.
+type RunnerRegistry struct {
+	db *DB
+}
+
+// NewRunnerRegistry creates a new PostgreSQL-backed runner registry.
+func NewRunnerRegistry(db *DB) *RunnerRegistry {
+	return &RunnerRegistry{db: db}
+}
+
+// Heartbeat records that a replica is alive at now, forgets the replicas
+// silent for longer than timeout, and returns the IDs of the live replicas,
+// sorted.
+func (r *RunnerRegistry) Heartbeat(ctx context.Context, instanceID string, now time.Time, timeout time.Duration) ([]string, error) {
+	query := `
+		INSERT INTO rule_runners (instance_id, heartbeat_at)
+		VALUES ($1, $2)
+		ON CONFLICT (instance_id) DO UPDATE SET heartbeat_at = EXCLUDED.heartbeat_at
+	`
+	if _, err := r.db.pool.Exec(ctx, query, instanceID, now); err != nil {
+		return nil, fmt.Errorf("failed to record heartbeat: %w", err)
+	}
+
+	expired := now.Add(-timeout)
+	if _, err := r.db.pool.Exec(ctx, `DELETE FROM rule_runners WHERE heartbeat_at < $1`, expired); err != nil {
+		return nil, fmt.Errorf("failed to remove silent runners: %w", err)
+	}
+
+	rows, err := r.db.pool.Query(ctx, `SELECT instance_id FROM rule_runners ORDER BY instance_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runners: %w", err)
+	}
+	defer rows.Close()
+
+	instances := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan runner: %w", err)
+		}
+		instances = append(instances, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating runners: %w", err)
+	}
+
+	return instances, nil
+}
+
+// Leave removes a replica, handing its rules over to the others.
+func (r *RunnerRegistry) Leave(ctx context.Context, instanceID string) error {
+	if _, err := r.db.pool.Exec(ctx, `DELETE FROM rule_runners WHERE instance_id = $1`, instanceID); err != nil {
+		return fmt.Errorf("failed to remove runner: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"argus-go/internal/domain"
+)
+
+// SilenceRepository implements store.SilenceRepository using PostgreSQL.
+type SilenceRepository struct {
+	db *DB
+}
+
+// NewSilenceRepository creates a new PostgreSQL-backed silence repository.
+func NewSilenceRepository(db *DB) *SilenceRepository {
+	return &SilenceRepository{db: db}
+}
+
+// Create stores a new silence. Replicas materializing the same occurrence of
+// a recurring silence race on its ID; the loser gets domain.ErrSilenceExists.
+func (r *SilenceRepository) Create(ctx context.Context, silence *domain.Silence) error {
+	match, err := json.Marshal(silence.Match)
+	if err != nil {
+		return fmt.Errorf("failed to encode silence match: %w", err)
+	}
+	var schedule []byte
+	if silence.Schedule != nil {
+		if schedule, err = json.Marshal(silence.Schedule); err != nil {
+			return fmt.Errorf("failed to encode silence schedule: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO silences (
+			id, comment, created_by, match, starts_at, ends_at, schedule,
+			recurring_id, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	result, err := r.db.pool.Exec(ctx, query,
+		silence.ID,
+		silence.Comment,
+		silence.CreatedBy,
+		match,
+		nullableTime(silence.StartsAt),
+		nullableTime(silence.EndsAt),
+		schedule,
+		silence.RecurringID,
+		silence.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create silence: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrSilenceExists
+	}
+
+	return nil
+}
+
+// Delete permanently removes a silence by ID.
+func (r *SilenceRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.pool.Exec(ctx, `DELETE FROM silences WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete silence: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrSilenceNotFound
+	}
+
+	return nil
+}
+
+// DeleteByRecurringID permanently removes the silences materialized from a
+// recurring silence.
+func (r *SilenceRepository) DeleteByRecurringID(ctx context.Context, recurringID string) error {
+	_, err := r.db.pool.Exec(ctx, `DELETE FROM silences WHERE recurring_id = $1`, recurringID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recurring silence occurrences: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a silence by its ID.
+func (r *SilenceRepository) GetByID(ctx context.Context, id string) (*domain.Silence, error) {
+	query := `
+		SELECT id, comment, created_by, match, starts_at, ends_at, schedule,
+			recurring_id, created_at
+		FROM silences
+		WHERE id = $1
+	`
+
+	silence, err := scanSilence(r.db.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSilenceNotFound
+		}
+		return nil, fmt.Errorf("failed to get silence: %w", err)
+	}
+
+	return silence, nil
+}
+
+// List retrieves every silence, oldest first.
+func (r *SilenceRepository) List(ctx context.Context) ([]*domain.Silence, error) {
+	query := `
+		SELECT id, comment, created_by, match, starts_at, ends_at, schedule,
+			recurring_id, created_at
+		FROM silences
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list silences: %w", err)
+	}
+	defer rows.Close()
+
+	silences := []*domain.Silence{}
+	for rows.Next() {
+		silence, err := scanSilence(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan silence: %w", err)
+		}
+		silences = append(silences, silence)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating silences: %w", err)
+	}
+
+	return silences, nil
+}
+
+// scanSilence scans a single row into a Silence.
+func scanSilence(row pgx.Row) (*domain.Silence, error) {
+	var silence domain.Silence
+	var match, schedule []byte
+	var startsAt, endsAt *time.Time
+
+	err := row.Scan(
+		&silence.ID,
+		&silence.Comment,
+		&silence.CreatedBy,
+		&match,
+		&startsAt,
+		&endsAt,
+		&schedule,
+		&silence.RecurringID,
+		&silence.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(match, &silence.Match); err != nil {
+		return nil, fmt.Errorf("failed to decode silence match: %w", err)
+	}
+	if schedule != nil {
+		silence.Schedule = &domain.SilenceSchedule{}
+		if err := json.Unmarshal(schedule, silence.Schedule); err != nil {
+			return nil, fmt.Errorf("failed to decode silence schedule: %w", err)
+		}
+	}
+	if startsAt != nil {
+		silence.StartsAt = *startsAt
+	}
+	if endsAt != nil {
+		silence.EndsAt = *endsAt
+	}
+
+	return &silence, nil
+}
+
+// nullableTime returns nil if the time is zero, otherwise returns a pointer to it.
+func nullableTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
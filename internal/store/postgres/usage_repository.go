@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"argus-go/internal/domain"
+)
+
+// UsageRepository implements store.UsageRepository using PostgreSQL.
+type UsageRepository struct {
+	db *DB
+}
+
+// NewUsageRepository creates a new PostgreSQL-backed usage repository.
+func NewUsageRepository(db *DB) *UsageRepository {
+	return &UsageRepository{db: db}
+}
+
+// Add adds the counts of a usage to those already stored for its event
+// manager and day. Replicas add their own counts, so the row is updated in
+// place rather than overwritten.
+func (r *UsageRepository) Add(ctx context.Context, usage *domain.Usage) error {
+	query := `
+		INSERT INTO usage_daily (event_manager_id, day, events_ingested, alerts_created, notifications_sent)
+		VALUES ($1, $2::date, $3, $4, $5)
+		ON CONFLICT (event_manager_id, day) DO UPDATE SET
+			events_ingested = usage_daily.events_ingested + EXCLUDED.events_ingested,
+			alerts_created = usage_daily.alerts_created + EXCLUDED.alerts_created,
+			notifications_sent = usage_daily.notifications_sent + EXCLUDED.notifications_sent
+	`
+
+	_, err := r.db.pool.Exec(ctx, query,
+		usage.EventManagerID,
+		usage.Day,
+		usage.EventsIngested,
+		usage.AlertsCreated,
+		usage.NotificationsSent,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to add usage: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves the usage per event manager per day of the days within the
+// filter range, ordered by event manager and day.
+func (r *UsageRepository) List(ctx context.Context, filter domain.ReportFilter) ([]*domain.Usage, error) {
+	first, last := domain.UsageDays(filter)
+	where := "day BETWEEN $1::date AND $2::date"
+	args := []interface{}{first, last}
+	if filter.EventManagerID != "" {
+		where += " AND event_manager_id = $3"
+		args = append(args, filter.EventManagerID)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT event_manager_id, day, events_ingested, alerts_created, notifications_sent
+		FROM usage_daily
+		WHERE %s
+		ORDER BY event_manager_id, day
+	`, where)
+
+	rows, err := r.db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage: %w", err)
+	}
+	defer rows.Close()
+
+	results := []*domain.Usage{}
+
+	for rows.Next() {
+		var usage domain.Usage
+		var day time.Time
+		if err := rows.Scan(&usage.EventManagerID, &day, &usage.EventsIngested, &usage.AlertsCreated, &usage.NotificationsSent); err != nil {
+			return nil, fmt.Errorf("failed to scan usage: %w", err)
+		}
+		usage.Day = domain.ReportDay(day)
+		results = append(results, &usage)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating usage: %w", err)
+	}
+
+	return results, nil
+}
+
+// DeleteByEventManager removes the usage of an event manager, and returns
+// the number of days removed.
+func (r *UsageRepository) DeleteByEventManager(ctx context.Context, eventManagerID string) (int, error) {
+	result, err := r.db.pool.Exec(ctx, `DELETE FROM usage_daily WHERE event_manager_id = $1`, eventManagerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete usage: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"argus-go/internal/domain"
+)
+
+// WatchRepository implements store.WatchRepository using PostgreSQL.
+type WatchRepository struct {
+	db *DB
+}
+
+// NewWatchRepository creates a new PostgreSQL-backed watch repository.
+func NewWatchRepository(db *DB) *WatchRepository {
+	return &WatchRepository{db: db}
+}
+
+// Create stores a new watch.
+func (r *WatchRepository) Create(ctx context.Context, watch *domain.Watch) error {
+	dedupKeys, err := json.Marshal(watch.DedupKeys)
+	if err != nil {
+		return fmt.Errorf("failed to encode watch dedup keys: %w", err)
+	}
+	filter, err := json.Marshal(watch.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to encode watch filter: %w", err)
+	}
+	kinds, err := json.Marshal(watch.Kinds)
+	if err != nil {
+		return fmt.Errorf("failed to encode watch kinds: %w", err)
+	}
+
+	query := `
+		INSERT INTO watches (
+			id, user_id, channel_type, channel_address, dedup_keys,
+			dedup_key_pattern, filter, kinds, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err = r.db.pool.Exec(ctx, query,
+		watch.ID,
+		watch.User,
+		watch.Channel.Type,
+		watch.Channel.Address,
+		dedupKeys,
+		watch.DedupKeyPattern,
+		filter,
+		kinds,
+		watch.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create watch: %w", err)
+	}
+
+	return nil
+}
+
+// Delete permanently removes a watch by ID.
+func (r *WatchRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.pool.Exec(ctx, `DELETE FROM watches WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete watch: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrWatchNotFound
+	}
+
+	return nil
+}
+
+// GetByID retrieves a watch by its ID.
+func (r *WatchRepository) GetByID(ctx context.Context, id string) (*domain.Watch, error) {
+	query := `
+		SELECT id, user_id, channel_type, channel_address, dedup_keys,
+			dedup_key_pattern, filter, kinds, created_at
+		FROM watches
+		WHERE id = $1
+	`
+
+	watch, err := scanWatch(r.db.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrWatchNotFound
+		}
+		return nil, fmt.Errorf("failed to get watch: %w", err)
+	}
+
+	return watch, nil
+}
+
+// List retrieves every watch, oldest first.
+func (r *WatchRepository) List(ctx context.Context) ([]*domain.Watch, error) {
+	query := `
+		SELECT id, user_id, channel_type, channel_address, dedup_keys,
+			dedup_key_pattern, filter, kinds, created_at
+		FROM watches
+		ORDER BY created_at, id
+	`
+
+	return r.query(ctx, query)
+}
+
+// ListByUser retrieves the watches of a user, oldest first.
+func (r *WatchRepository) ListByUser(ctx context.Context, user string) ([]*domain.Watch, error) {
+	query := `
+		SELECT id, user_id, channel_type, channel_address, dedup_keys,
+			dedup_key_pattern, filter, kinds, created_at
+		FROM watches
+		WHERE user_id = $1
+		ORDER BY created_at, id
+	`
+
+	return r.query(ctx, query, user)
+}
+
+// query runs a query selecting watches and scans the rows.
+func (r *WatchRepository) query(ctx context.Context, query string, args ...any) ([]*domain.Watch, error) {
+	rows, err := r.db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watches: %w", err)
+	}
+	defer rows.Close()
+
+	watches := []*domain.Watch{}
+	for rows.Next() {
+		watch, err := scanWatch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan watch: %w", err)
+		}
+		watches = append(watches, watch)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating watches: %w", err)
+	}
+
+	return watches, nil
+}
+
+// scanWatch scans a single row into a Watch.
+func scanWatch(row pgx.Row) (*domain.Watch, error) {
+	var watch domain.Watch
+	var dedupKeys, filter, kinds []byte
+
+	err := row.Scan(
+		&watch.ID,
+		&watch.User,
+		&watch.Channel.Type,
+		&watch.Channel.Address,
+		&dedupKeys,
+		&watch.DedupKeyPattern,
+		&filter,
+		&kinds,
+		&watch.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(dedupKeys, &watch.DedupKeys); err != nil {
+		return nil, fmt.Errorf("failed to decode watch dedup keys: %w", err)
+	}
+	if err := json.Unmarshal(filter, &watch.Filter); err != nil {
+		return nil, fmt.Errorf("failed to decode watch filter: %w", err)
+	}
+	if err := json.Unmarshal(kinds, &watch.Kinds); err != nil {
+		return nil, fmt.Errorf("failed to decode watch kinds: %w", err)
+	}
+
+	return &watch, nil
+}
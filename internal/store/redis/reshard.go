@@ -0,0 +1,184 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"argus-go/internal/config"
+)
+
+// statePrefixes are the prefixes of the keys the state store owns.
+var statePrefixes = []string{prefixParent, prefixAlert, prefixChildren, prefixPendingResolve, prefixReminder, prefixNotified, prefixLiveCounts, prefixGroupingStats, prefixEventStatus}
+
+// scanCount is the number of keys requested per SCAN while resharding.
+const scanCount = 1000
+
+// ReshardResult counts what a resharding found and moved.
+type ReshardResult struct {
+	// Scanned is the number of keys scanned across all instances.
+	Scanned int `json:"scanned"`
+
+	// Moved is the number of keys moved to another shard.
+	Moved int `json:"moved"`
+
+	// RemindersMoved is the number of reminder schedule entries moved.
+	RemindersMoved int `json:"reminders_moved"`
+}
+
+// reshardSource is an instance whose keys are checked: a shard of the new
+// ring, or a removed instance (shard -1) whose keys all move.
+type reshardSource struct {
+	addr   string
+	client *redis.Client
+	shard  int
+}
+
+// Reshard moves every state key to the shard the ring of cfg assigns it,
+// after shards were added to or renamed in cfg. The keys of shards removed
+// from cfg move too, given the addresses of those instances in removed. With
+// dryRun the keys to move are counted but left in place.
+//
+// The processors must be paused while resharding: an event processed while
+// its keys move may miss its state.
+func Reshard(ctx context.Context, cfg *config.RedisConfig, removed []string, dryRun bool, logger *slog.Logger) (*ReshardResult, error) {
+	s := newStateStore(cfg)
+	defer s.Close()
+
+	sources := make([]reshardSource, 0, len(s.shards)+len(removed))
+	for i, client := range s.shards {
+		sources = append(sources, reshardSource{addr: s.addrs[i], client: client, shard: i})
+	}
+	for _, addr := range removed {
+		client := newClient(cfg, addr)
+		defer client.Close()
+		sources = append(sources, reshardSource{addr: addr, client: client, shard: -1})
+	}
+	for _, source := range sources {
+		if err := source.client.Ping(ctx).Err(); err != nil {
+			return nil, fmt.Errorf("failed to connect to redis %s: %w", source.addr, err)
+		}
+	}
+
+	result := &ReshardResult{}
+	for _, source := range sources {
+		before := *result
+		if err := s.reshardSource(ctx, source, dryRun, result); err != nil {
+			return result, fmt.Errorf("failed to reshard %s: %w", source.addr, err)
+		}
+		logger.Info("resharded redis instance",
+			"addr", source.addr,
+			"scanned", result.Scanned-before.Scanned,
+			"moved", result.Moved-before.Moved,
+			"reminders_moved", result.RemindersMoved-before.RemindersMoved,
+			"dry_run", dryRun,
+		)
+	}
+	return result, nil
+}
+
+// reshardSource moves the keys of one instance that belong to another shard.
+func (s *StateStore) reshardSource(ctx context.Context, source reshardSource, dryRun bool, result *ReshardResult) error {
+	iter := source.client.Scan(ctx, 0, "*", scanCount).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		result.Scanned++
+
+		if key == keyReminderSchedule {
+			moved, err := s.moveSchedule(ctx, source, dryRun)
+			if err != nil {
+				return err
+			}
+			result.RemindersMoved += moved
+			continue
+		}
+		if !isStateKey(key) {
+			continue
+		}
+
+		target := s.ring.shard(routingKey(key))
+		if target == source.shard {
+			continue
+		}
+		result.Moved++
+		if dryRun {
+			continue
+		}
+		if err := moveKey(ctx, source.client, s.shards[target], key); err != nil {
+			return fmt.Errorf("failed to move %s: %w", key, err)
+		}
+	}
+	return iter.Err()
+}
+
+// moveKey copies a key with its TTL to another instance and deletes it from
+// its source. Keys that expire in the meantime are skipped.
+func moveKey(ctx context.Context, from, to *redis.Client, key string) error {
+	dump, err := from.Dump(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+		return err
+	}
+	ttl, err := from.PTTL(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	switch {
+	case ttl == -2:
+		// Expired since the dump
+		return nil
+	case ttl < 0:
+		// No expiry
+		ttl = 0
+	}
+
+	if err := to.RestoreReplace(ctx, key, ttl, dump).Err(); err != nil {
+		return err
+	}
+	return from.Del(ctx, key).Err()
+}
+
+// moveSchedule moves the reminder schedule entries of an instance to the
+// schedule of the shard owning each reminder. Returns the entries moved.
+func (s *StateStore) moveSchedule(ctx context.Context, source reshardSource, dryRun bool) (int, error) {
+	entries, err := source.client.ZRangeWithScores(ctx, keyReminderSchedule, 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read reminder schedule: %w", err)
+	}
+
+	moved := 0
+	for _, entry := range entries {
+		dedupKey, _ := entry.Member.(string)
+		target := s.ring.shard(dedupKey)
+		if target == source.shard {
+			continue
+		}
+		moved++
+		if dryRun {
+			continue
+		}
+		if err := s.shards[target].ZAdd(ctx, keyReminderSchedule, entry).Err(); err != nil {
+			return moved, fmt.Errorf("failed to move reminder of %s: %w", dedupKey, err)
+		}
+		if err := source.client.ZRem(ctx, keyReminderSchedule, dedupKey).Err(); err != nil {
+			return moved, fmt.Errorf("failed to move reminder of %s: %w", dedupKey, err)
+		}
+	}
+	return moved, nil
+}
+
+// isStateKey returns true if the key belongs to the state store.
+func isStateKey(key string) bool {
+	for _, prefix := range statePrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
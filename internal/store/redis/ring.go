@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// virtualNodes is the number of points each shard has on the hash ring. More
+// points spread keys more evenly between shards.
+const virtualNodes = 160
+
+// ring assigns routing keys to shards by consistent hashing, so adding or
+// removing a shard only moves the keys of that shard's share of the ring.
+type ring struct {
+	points []uint32
+	shards []int // shard index of each point
+}
+
+// newRing places each named shard on the ring. Shards are identified by
+// their index in names.
+func newRing(names []string) *ring {
+	r := &ring{}
+	type point struct {
+		hash  uint32
+		shard int
+	}
+	points := make([]point, 0, len(names)*virtualNodes)
+	for shard, name := range names {
+		for i := range virtualNodes {
+			points = append(points, point{hash: hashKey(name + "#" + strconv.Itoa(i)), shard: shard})
+		}
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].hash < points[j].hash })
+
+	r.points = make([]uint32, len(points))
+	r.shards = make([]int, len(points))
+	for i, p := range points {
+		r.points[i] = p.hash
+		r.shards[i] = p.shard
+	}
+	return r
+}
+
+// shard returns the index of the shard owning a routing key: the first point
+// at or after the key's hash, wrapping around.
+func (r *ring) shard(routingKey string) int {
+	if len(r.points) == virtualNodes {
+		// A single shard owns every key
+		return 0
+	}
+	h := hashKey(routingKey)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.shards[i]
+}
+
+// hashKey hashes a string onto the ring. FNV-1a is finalized with the
+// MurmurHash3 mix, since similar strings such as the points of one shard
+// would otherwise cluster.
+func hashKey(s string) uint32 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return uint32(x)
+}
+
+// routingKey returns the part of a state key that selects its shard: the key
+// without its type prefix. The states of a dedup key (alert, children,
+// pending resolve and reminder) therefore live on the same shard.
+func routingKey(key string) string {
+	_, rest, _ := strings.Cut(key, ":")
+	return rest
+}
@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRing_Shard(t *testing.T) {
+	const keys = 10000
+	three := newRing([]string{"shard-a", "shard-b", "shard-c"})
+	four := newRing([]string{"shard-a", "shard-b", "shard-c", "shard-d"})
+
+	counts := make([]int, 3)
+	moved := 0
+	for i := range keys {
+		key := fmt.Sprintf("em-1:host-%d", i)
+		shard := three.shard(key)
+		if shard != three.shard(key) {
+			t.Fatalf("shard of %s is not stable", key)
+		}
+		counts[shard]++
+
+		// Adding a shard only moves keys to the new shard
+		if newShard := four.shard(key); newShard != shard {
+			if newShard != 3 {
+				t.Fatalf("key %s moved from shard %d to %d, want to the new shard 3", key, shard, newShard)
+			}
+			moved++
+		}
+	}
+
+	for shard, count := range counts {
+		if count < keys/5 || count > keys/2 {
+			t.Errorf("shard %d owns %d of %d keys, want an even spread", shard, count, keys)
+		}
+	}
+	if moved < keys/8 || moved > keys/3 {
+		t.Errorf("adding a fourth shard moved %d of %d keys, want about a quarter", moved, keys)
+	}
+}
+
+func TestRing_SingleShard(t *testing.T) {
+	r := newRing([]string{"localhost:6379"})
+	for _, key := range []string{"", "a", "em-1:class:storage"} {
+		if shard := r.shard(key); shard != 0 {
+			t.Errorf("shard(%q) = %d, want 0", key, shard)
+		}
+	}
+}
+
+func TestRoutingKey(t *testing.T) {
+	tests := map[string]string{
+		alertKey("db:disk"):                   "db:disk",
+		childrenKey("db:disk"):                "db:disk",
+		reminderKey("db:disk"):                "db:disk",
+		parentKey("em-1", "class", "storage"): "em-1:class:storage",
+	}
+	for key, want := range tests {
+		if got := routingKey(key); got != want {
+			t.Errorf("routingKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
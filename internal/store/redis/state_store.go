@@ -6,11 +6,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 
 	"argus-go/internal/config"
+	"argus-go/internal/domain"
 	"argus-go/internal/store"
 )
 
@@ -20,30 +24,73 @@ const (
 	prefixAlert          = "alert:"
 	prefixChildren       = "children:"
 	prefixPendingResolve = "pending:"
+	prefixReminder       = "reminder:"
+	prefixNotified       = "notified:"
+	prefixLiveCounts     = "live:"
+	prefixGroupingStats  = "grouping:"
+	prefixEventStatus    = "event:"
+
+	// keyReminderSchedule is a sorted set of the dedup keys of scheduled
+	// reminders, scored by due time in Unix milliseconds.
+	keyReminderSchedule = "reminders"
+
+	// keyLiveCounts is a hash of the live counts of active alerts, with a
+	// field "eventManagerID:severity" per count. Event manager IDs contain
+	// no colon.
+	keyLiveCounts = prefixLiveCounts + "counts"
 )
 
-// StateStore implements store.StateStore using Redis.
+// StateStore implements store.StateStore using Redis. Keys are spread across
+// one or more shards by consistent hashing of their routing key.
 type StateStore struct {
-	client *redis.Client
+	shards []*redis.Client
+	addrs  []string
+	ring   *ring
 }
 
-// NewStateStore creates a new Redis-backed state store.
+// NewStateStore creates a new Redis-backed state store over the configured
+// shards, or the single configured instance.
 func NewStateStore(cfg *config.RedisConfig) (*StateStore, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:     cfg.RedisAddr(),
-		Password: cfg.Password,
-		DB:       cfg.DB,
-	})
+	s := newStateStore(cfg)
 
 	// Verify connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if err := client.Ping(ctx).Err(); err != nil {
+	if err := s.Ping(ctx); err != nil {
+		_ = s.Close()
 		return nil, fmt.Errorf("failed to connect to redis: %w", err)
 	}
 
-	return &StateStore{client: client}, nil
+	return s, nil
+}
+
+// newStateStore creates the clients of the shards without connecting.
+func newStateStore(cfg *config.RedisConfig) *StateStore {
+	shardList := cfg.ShardList()
+	s := &StateStore{}
+	names := make([]string, len(shardList))
+	for i, shard := range shardList {
+		s.shards = append(s.shards, newClient(cfg, shard.Addr))
+		s.addrs = append(s.addrs, shard.Addr)
+		names[i] = shard.Name
+	}
+	s.ring = newRing(names)
+	return s
+}
+
+// newClient creates a client of the Redis instance at addr.
+func newClient(cfg *config.RedisConfig, addr string) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+}
+
+// client returns the client of the shard owning a key.
+func (s *StateStore) client(key string) *redis.Client {
+	return s.shards[s.ring.shard(routingKey(key))]
 }
 
 // --- Parent Alert Operations ---
@@ -57,7 +104,7 @@ func parentKey(eventManagerID, groupingKey, groupingValue string) string {
 func (s *StateStore) GetParent(ctx context.Context, eventManagerID, groupingKey, groupingValue string) (*store.ParentState, error) {
 	key := parentKey(eventManagerID, groupingKey, groupingValue)
 
-	data, err := s.client.Get(ctx, key).Bytes()
+	data, err := s.client(key).Get(ctx, key).Bytes()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			return nil, nil
@@ -82,7 +129,7 @@ func (s *StateStore) SetParent(ctx context.Context, eventManagerID, groupingKey,
 		return fmt.Errorf("failed to marshal parent state: %w", err)
 	}
 
-	if err := s.client.Set(ctx, key, data, ttl).Err(); err != nil {
+	if err := s.client(key).Set(ctx, key, data, ttl).Err(); err != nil {
 		return fmt.Errorf("failed to set parent: %w", err)
 	}
 
@@ -93,7 +140,7 @@ func (s *StateStore) SetParent(ctx context.Context, eventManagerID, groupingKey,
 func (s *StateStore) DeleteParent(ctx context.Context, eventManagerID, groupingKey, groupingValue string) error {
 	key := parentKey(eventManagerID, groupingKey, groupingValue)
 
-	if err := s.client.Del(ctx, key).Err(); err != nil {
+	if err := s.client(key).Del(ctx, key).Err(); err != nil {
 		return fmt.Errorf("failed to delete parent: %w", err)
 	}
 
@@ -111,7 +158,7 @@ func alertKey(dedupKey string) string {
 func (s *StateStore) GetAlert(ctx context.Context, dedupKey string) (*store.AlertState, error) {
 	key := alertKey(dedupKey)
 
-	data, err := s.client.Get(ctx, key).Bytes()
+	data, err := s.client(key).Get(ctx, key).Bytes()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			return nil, nil
@@ -137,7 +184,7 @@ func (s *StateStore) SetAlert(ctx context.Context, state *store.AlertState) erro
 	}
 
 	// No TTL for alert state - it persists until explicitly deleted
-	if err := s.client.Set(ctx, key, data, 0).Err(); err != nil {
+	if err := s.client(key).Set(ctx, key, data, 0).Err(); err != nil {
 		return fmt.Errorf("failed to set alert: %w", err)
 	}
 
@@ -148,7 +195,7 @@ func (s *StateStore) SetAlert(ctx context.Context, state *store.AlertState) erro
 func (s *StateStore) DeleteAlert(ctx context.Context, dedupKey string) error {
 	key := alertKey(dedupKey)
 
-	if err := s.client.Del(ctx, key).Err(); err != nil {
+	if err := s.client(key).Del(ctx, key).Err(); err != nil {
 		return fmt.Errorf("failed to delete alert: %w", err)
 	}
 
@@ -166,7 +213,7 @@ func childrenKey(parentDedupKey string) string {
 func (s *StateStore) AddChild(ctx context.Context, parentDedupKey, childDedupKey string) error {
 	key := childrenKey(parentDedupKey)
 
-	if err := s.client.SAdd(ctx, key, childDedupKey).Err(); err != nil {
+	if err := s.client(key).SAdd(ctx, key, childDedupKey).Err(); err != nil {
 		return fmt.Errorf("failed to add child: %w", err)
 	}
 
@@ -177,7 +224,7 @@ func (s *StateStore) AddChild(ctx context.Context, parentDedupKey, childDedupKey
 func (s *StateStore) RemoveChild(ctx context.Context, parentDedupKey, childDedupKey string) error {
 	key := childrenKey(parentDedupKey)
 
-	if err := s.client.SRem(ctx, key, childDedupKey).Err(); err != nil {
+	if err := s.client(key).SRem(ctx, key, childDedupKey).Err(); err != nil {
 		return fmt.Errorf("failed to remove child: %w", err)
 	}
 
@@ -188,7 +235,7 @@ func (s *StateStore) RemoveChild(ctx context.Context, parentDedupKey, childDedup
 func (s *StateStore) GetChildren(ctx context.Context, parentDedupKey string) ([]string, error) {
 	key := childrenKey(parentDedupKey)
 
-	children, err := s.client.SMembers(ctx, key).Result()
+	children, err := s.client(key).SMembers(ctx, key).Result()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get children: %w", err)
 	}
@@ -200,7 +247,7 @@ func (s *StateStore) GetChildren(ctx context.Context, parentDedupKey string) ([]
 func (s *StateStore) GetChildCount(ctx context.Context, parentDedupKey string) (int, error) {
 	key := childrenKey(parentDedupKey)
 
-	count, err := s.client.SCard(ctx, key).Result()
+	count, err := s.client(key).SCard(ctx, key).Result()
 	if err != nil {
 		return 0, fmt.Errorf("failed to get child count: %w", err)
 	}
@@ -208,6 +255,76 @@ func (s *StateStore) GetChildCount(ctx context.Context, parentDedupKey string) (
 	return int(count), nil
 }
 
+// GetActiveChildCount returns the number of children for a parent whose alert state is active.
+// The alert states of all children are fetched in a single MGET per shard.
+func (s *StateStore) GetActiveChildCount(ctx context.Context, parentDedupKey string) (int, error) {
+	children, err := s.GetChildren(ctx, parentDedupKey)
+	if err != nil {
+		return 0, err
+	}
+	if len(children) == 0 {
+		return 0, nil
+	}
+
+	keys := make([]string, len(children))
+	for i, child := range children {
+		keys[i] = alertKey(child)
+	}
+
+	values, err := s.mget(ctx, keys)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get child states: %w", err)
+	}
+
+	count := 0
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			// Missing state
+			continue
+		}
+		var state store.AlertState
+		if err := json.Unmarshal([]byte(data), &state); err != nil {
+			return 0, fmt.Errorf("failed to unmarshal alert state: %w", err)
+		}
+		if state.Status == string(domain.AlertStatusActive) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// mget gets the values of keys spread across shards, with one MGET per
+// shard, in the order of keys.
+func (s *StateStore) mget(ctx context.Context, keys []string) ([]any, error) {
+	if len(s.shards) == 1 {
+		return s.shards[0].MGet(ctx, keys...).Result()
+	}
+
+	byShard := make(map[int][]int)
+	for i, key := range keys {
+		shard := s.ring.shard(routingKey(key))
+		byShard[shard] = append(byShard[shard], i)
+	}
+
+	values := make([]any, len(keys))
+	for shard, indexes := range byShard {
+		shardKeys := make([]string, len(indexes))
+		for j, i := range indexes {
+			shardKeys[j] = keys[i]
+		}
+		shardValues, err := s.shards[shard].MGet(ctx, shardKeys...).Result()
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range indexes {
+			values[i] = shardValues[j]
+		}
+	}
+	return values, nil
+}
+
 // --- Pending Resolution Operations ---
 
 // pendingKey generates the Redis key for pending resolve state.
@@ -224,7 +341,7 @@ func (s *StateStore) SetPendingResolve(ctx context.Context, parentDedupKey strin
 		return fmt.Errorf("failed to marshal pending resolve: %w", err)
 	}
 
-	if err := s.client.Set(ctx, key, data, 0).Err(); err != nil {
+	if err := s.client(key).Set(ctx, key, data, 0).Err(); err != nil {
 		return fmt.Errorf("failed to set pending resolve: %w", err)
 	}
 
@@ -235,7 +352,7 @@ func (s *StateStore) SetPendingResolve(ctx context.Context, parentDedupKey strin
 func (s *StateStore) GetPendingResolve(ctx context.Context, parentDedupKey string) (*store.PendingResolve, error) {
 	key := pendingKey(parentDedupKey)
 
-	data, err := s.client.Get(ctx, key).Bytes()
+	data, err := s.client(key).Get(ctx, key).Bytes()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			return nil, nil
@@ -255,19 +372,342 @@ func (s *StateStore) GetPendingResolve(ctx context.Context, parentDedupKey strin
 func (s *StateStore) DeletePendingResolve(ctx context.Context, parentDedupKey string) error {
 	key := pendingKey(parentDedupKey)
 
-	if err := s.client.Del(ctx, key).Err(); err != nil {
+	if err := s.client(key).Del(ctx, key).Err(); err != nil {
 		return fmt.Errorf("failed to delete pending resolve: %w", err)
 	}
 
 	return nil
 }
 
+// --- Reminder Operations ---
+
+// reminderKey generates the Redis key for a scheduled reminder.
+func reminderKey(dedupKey string) string {
+	return prefixReminder + dedupKey
+}
+
+// SetReminder schedules or reschedules the reminder of a parent alert. Each
+// shard has a schedule of its own reminders, so both writes go to one shard.
+func (s *StateStore) SetReminder(ctx context.Context, reminder *store.Reminder) error {
+	data, err := json.Marshal(reminder)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reminder: %w", err)
+	}
+
+	key := reminderKey(reminder.DedupKey)
+	pipe := s.client(key).TxPipeline()
+	pipe.Set(ctx, key, data, 0)
+	pipe.ZAdd(ctx, keyReminderSchedule, redis.Z{
+		Score:  float64(reminder.DueAt.UnixMilli()),
+		Member: reminder.DedupKey,
+	})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to set reminder: %w", err)
+	}
+
+	return nil
+}
+
+// GetDueReminders returns up to limit reminders due at or before now, earliest first.
+// The schedules of all shards are merged.
+func (s *StateStore) GetDueReminders(ctx context.Context, now time.Time, limit int) ([]*store.Reminder, error) {
+	var reminders []*store.Reminder
+	for _, client := range s.shards {
+		due, err := dueReminders(ctx, client, now, limit)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, due...)
+	}
+
+	if len(s.shards) > 1 {
+		sort.SliceStable(reminders, func(i, j int) bool { return reminders[i].DueAt.Before(reminders[j].DueAt) })
+		if len(reminders) > limit {
+			reminders = reminders[:limit]
+		}
+	}
+	return reminders, nil
+}
+
+// dueReminders returns up to limit reminders of one shard due at or before
+// now, earliest first.
+func dueReminders(ctx context.Context, client *redis.Client, now time.Time, limit int) ([]*store.Reminder, error) {
+	dedupKeys, err := client.ZRangeByScore(ctx, keyReminderSchedule, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   fmt.Sprintf("%d", now.UnixMilli()),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due reminders: %w", err)
+	}
+	if len(dedupKeys) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(dedupKeys))
+	for i, dedupKey := range dedupKeys {
+		keys[i] = reminderKey(dedupKey)
+	}
+	values, err := client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due reminders: %w", err)
+	}
+
+	reminders := make([]*store.Reminder, 0, len(values))
+	for _, value := range values {
+		// Skip reminders deleted since the schedule was read
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var reminder store.Reminder
+		if err := json.Unmarshal([]byte(data), &reminder); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal reminder: %w", err)
+		}
+		reminders = append(reminders, &reminder)
+	}
+
+	return reminders, nil
+}
+
+// DeleteReminder cancels the reminder of a parent alert.
+func (s *StateStore) DeleteReminder(ctx context.Context, dedupKey string) error {
+	key := reminderKey(dedupKey)
+	pipe := s.client(key).TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.ZRem(ctx, keyReminderSchedule, dedupKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to delete reminder: %w", err)
+	}
+
+	return nil
+}
+
+// --- Notification Operations ---
+
+// MarkNotificationSent records a notification as sent for ttl, returning
+// false if it was already recorded. SETNX makes the check and the record
+// atomic, so concurrent replicas send a notification once.
+func (s *StateStore) MarkNotificationSent(ctx context.Context, notificationID string, ttl time.Duration) (bool, error) {
+	key := prefixNotified + notificationID
+
+	marked, err := s.client(key).SetNX(ctx, key, 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to mark notification sent: %w", err)
+	}
+	return marked, nil
+}
+
+// --- Live Count Operations ---
+
+// liveCountField generates the hash field of a live count.
+func liveCountField(eventManagerID, severity string) string {
+	return eventManagerID + ":" + severity
+}
+
+// AdjustLiveCount adds delta to the live count of active alerts of an event
+// manager at a severity. All counts are one hash on one shard, so a
+// dashboard reads them with a single HGETALL.
+func (s *StateStore) AdjustLiveCount(ctx context.Context, eventManagerID, severity string, delta int) error {
+	field := liveCountField(eventManagerID, severity)
+	if err := s.client(keyLiveCounts).HIncrBy(ctx, keyLiveCounts, field, int64(delta)).Err(); err != nil {
+		return fmt.Errorf("failed to adjust live count: %w", err)
+	}
+	return nil
+}
+
+// GetLiveCounts returns the live counts that are above zero, by event manager
+// and severity.
+func (s *StateStore) GetLiveCounts(ctx context.Context) ([]*store.LiveCount, error) {
+	fields, err := s.client(keyLiveCounts).HGetAll(ctx, keyLiveCounts).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get live counts: %w", err)
+	}
+
+	var counts []*store.LiveCount
+	for field, value := range fields {
+		eventManagerID, severity, ok := strings.Cut(field, ":")
+		count, err := strconv.Atoi(value)
+		if !ok || err != nil || count <= 0 {
+			continue
+		}
+		counts = append(counts, &store.LiveCount{EventManagerID: eventManagerID, Severity: severity, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].EventManagerID != counts[j].EventManagerID {
+			return counts[i].EventManagerID < counts[j].EventManagerID
+		}
+		return counts[i].Severity < counts[j].Severity
+	})
+	return counts, nil
+}
+
+// SetLiveCounts replaces all live counts in one transaction.
+func (s *StateStore) SetLiveCounts(ctx context.Context, counts []*store.LiveCount) error {
+	values := make(map[string]any, len(counts))
+	for _, count := range counts {
+		field := liveCountField(count.EventManagerID, count.Severity)
+		if previous, ok := values[field].(int); ok {
+			values[field] = previous + count.Count
+		} else {
+			values[field] = count.Count
+		}
+	}
+
+	pipe := s.client(keyLiveCounts).TxPipeline()
+	pipe.Del(ctx, keyLiveCounts)
+	if len(values) > 0 {
+		pipe.HSet(ctx, keyLiveCounts, values)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to set live counts: %w", err)
+	}
+	return nil
+}
+
+// --- Grouping Statistics Operations ---
+
+// groupingStatsKey generates the Redis key of the grouping statistics of a
+// grouping rule: a hash with the fields "day:parents" and "day:children".
+func groupingStatsKey(groupingRuleID string) string {
+	return prefixGroupingStats + groupingRuleID
+}
+
+// RecordGrouping counts an alert grouped by a grouping rule on a UTC day.
+// The statistics of a rule expire store.GroupingStatsRetention after the
+// last alert it grouped.
+func (s *StateStore) RecordGrouping(ctx context.Context, groupingRuleID, day string, child bool) error {
+	key := groupingStatsKey(groupingRuleID)
+	field := day + ":parents"
+	if child {
+		field = day + ":children"
+	}
+
+	pipe := s.client(key).TxPipeline()
+	pipe.HIncrBy(ctx, key, field, 1)
+	pipe.Expire(ctx, key, store.GroupingStatsRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record grouping: %w", err)
+	}
+	return nil
+}
+
+// GetGroupingStats returns the statistics of a grouping rule on the given
+// days, skipping days without any. Fields of days older than
+// store.GroupingStatsRetention are removed on the way.
+func (s *StateStore) GetGroupingStats(ctx context.Context, groupingRuleID string, days []string) ([]*store.GroupingStats, error) {
+	key := groupingStatsKey(groupingRuleID)
+	fields, err := s.client(key).HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get grouping stats: %w", err)
+	}
+
+	byDay := make(map[string]*store.GroupingStats)
+	var stale []string
+	cutoff := domain.ReportDay(time.Now().Add(-store.GroupingStatsRetention))
+	for field, value := range fields {
+		day, kind, _ := strings.Cut(field, ":")
+		if day < cutoff {
+			stale = append(stale, field)
+			continue
+		}
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			continue
+		}
+		stats, ok := byDay[day]
+		if !ok {
+			stats = &store.GroupingStats{Day: day}
+			byDay[day] = stats
+		}
+		switch kind {
+		case "parents":
+			stats.ParentsCreated = count
+		case "children":
+			stats.ChildrenGrouped = count
+		}
+	}
+	if len(stale) > 0 {
+		if err := s.client(key).HDel(ctx, key, stale...).Err(); err != nil {
+			return nil, fmt.Errorf("failed to prune grouping stats: %w", err)
+		}
+	}
+
+	var result []*store.GroupingStats
+	for _, day := range days {
+		if stats, ok := byDay[day]; ok {
+			result = append(result, stats)
+		}
+	}
+	return result, nil
+}
+
 // --- Lifecycle ---
 
-// Close closes the Redis client connection.
-func (s *StateStore) Close() error {
-	if s.client != nil {
-		return s.client.Close()
+// Ping checks the connection to every shard. The client discards broken
+// connections and dials new ones, so a Ping after an outage reconnects.
+func (s *StateStore) Ping(ctx context.Context) error {
+	var errs []error
+	for i, client := range s.shards {
+		if err := client.Ping(ctx).Err(); err != nil {
+			if len(s.shards) > 1 {
+				err = fmt.Errorf("%s: %w", s.addrs[i], err)
+			}
+			errs = append(errs, err)
+		}
 	}
+	return errors.Join(errs...)
+}
+
+// --- Event Status Operations ---
+
+// eventStatusKey generates the key of the status of an event.
+func eventStatusKey(eventID string) string {
+	return prefixEventStatus + eventID
+}
+
+// SetEventStatus stores or replaces the status of an event for ttl.
+func (s *StateStore) SetEventStatus(ctx context.Context, status *domain.EventStatus, ttl time.Duration) error {
+	key := eventStatusKey(status.EventID)
+
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event status: %w", err)
+	}
+
+	if err := s.client(key).Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set event status: %w", err)
+	}
+
 	return nil
 }
+
+// GetEventStatus retrieves the status of an event by its event ID.
+// Returns nil, nil if the event has no status or it expired.
+func (s *StateStore) GetEventStatus(ctx context.Context, eventID string) (*domain.EventStatus, error) {
+	key := eventStatusKey(eventID)
+
+	data, err := s.client(key).Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get event status: %w", err)
+	}
+
+	var status domain.EventStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event status: %w", err)
+	}
+
+	return &status, nil
+}
+
+// Close closes the connections to every shard.
+func (s *StateStore) Close() error {
+	var errs []error
+	for _, client := range s.shards {
+		errs = append(errs, client.Close())
+	}
+	return errors.Join(errs...)
+}
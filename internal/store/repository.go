@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"time"
 
 	"argus-go/internal/domain"
 )
@@ -15,6 +16,14 @@ type AlertRepository interface {
 	// Update modifies an existing alert.
 	Update(ctx context.Context, alert *domain.Alert) error
 
+	// UpdateIfUnmodified modifies an existing alert unless it was updated
+	// since updatedAt, in which case domain.ErrAlertModified is returned.
+	UpdateIfUnmodified(ctx context.Context, alert *domain.Alert, updatedAt time.Time) error
+
+	// UpdateAll modifies several existing alerts atomically: if any of them
+	// cannot be updated, none is.
+	UpdateAll(ctx context.Context, alerts []*domain.Alert) error
+
 	// GetByID retrieves an alert by its database ID.
 	GetByID(ctx context.Context, id string) (*domain.Alert, error)
 
@@ -24,11 +33,72 @@ type AlertRepository interface {
 	// List retrieves alerts matching the filter criteria.
 	List(ctx context.Context, filter domain.AlertFilter) ([]*domain.Alert, error)
 
+	// Count returns the number of alerts matching the filter criteria,
+	// ignoring its sort order and pagination.
+	Count(ctx context.Context, filter domain.AlertFilter) (int, error)
+
 	// GetChildrenByParent retrieves all child alerts for a given parent dedup key.
 	GetChildrenByParent(ctx context.Context, parentDedupKey string) ([]*domain.Alert, error)
 
 	// CountActiveChildren returns the count of active child alerts for a parent.
 	CountActiveChildren(ctx context.Context, parentDedupKey string) (int, error)
+
+	// CountActive returns the number of active alerts per event manager,
+	// alert type, severity and class. Event managers without active alerts
+	// are omitted.
+	CountActive(ctx context.Context) ([]*domain.ActiveAlertCount, error)
+
+	// IncrementTriggerCount records an additional trigger event for an existing alert.
+	IncrementTriggerCount(ctx context.Context, dedupKey string) error
+
+	// AddSampledEvents records trigger events dropped by sampling for an
+	// existing alert, counting them as triggers too.
+	AddSampledEvents(ctx context.Context, dedupKey string, count int) error
+
+	// MergeTags merges tags onto the tags of an existing alert, replacing
+	// the values of its tags if replace is set. See domain.MergeTags.
+	MergeTags(ctx context.Context, dedupKey string, tags map[string]string, replace bool) error
+
+	// UpdateLinks removes the links with the given URLs from an existing
+	// alert, then adds links. See domain.RemoveLinks and domain.MergeLinks.
+	UpdateLinks(ctx context.Context, dedupKey string, add []domain.AlertLink, remove []string) error
+
+	// History returns every revision of an alert, oldest first.
+	// Returns an empty slice if the alert has no history.
+	History(ctx context.Context, dedupKey string) ([]*domain.AlertRevision, error)
+
+	// RevisionAt returns the revision of an alert that was current at the given time.
+	// Returns domain.ErrAlertNotFound if the alert did not exist yet.
+	RevisionAt(ctx context.Context, dedupKey string, at time.Time) (*domain.AlertRevision, error)
+
+	// PurgeByEventManager permanently removes all alerts of an event manager,
+	// including their history, and returns how many alerts were removed.
+	PurgeByEventManager(ctx context.Context, eventManagerID string) (int, error)
+
+	// Delete permanently removes an alert, including its history. Returns
+	// domain.ErrAlertNotFound if there is no alert with the dedup key.
+	Delete(ctx context.Context, dedupKey string) error
+}
+
+// ReportRepository defines aggregation queries used by the reporting API.
+type ReportRepository interface {
+	// MTTR returns acknowledgement and resolution timings per event manager.
+	MTTR(ctx context.Context, filter domain.ReportFilter) ([]*domain.MTTRStats, error)
+
+	// DailyVolume returns the number of alerts created per event manager per day.
+	DailyVolume(ctx context.Context, filter domain.ReportFilter) ([]*domain.DailyVolume, error)
+
+	// TopDedupKeys returns the most frequently triggered dedup keys per event manager.
+	TopDedupKeys(ctx context.Context, filter domain.ReportFilter) ([]*domain.DedupKeyVolume, error)
+
+	// NoiseStats returns trigger/resolve counters for alerts active within the range,
+	// i.e. created before To and updated at or after From.
+	NoiseStats(ctx context.Context, filter domain.ReportFilter) ([]*domain.NoiseStats, error)
+
+	// SourceStats returns the alerts created in the range per event manager
+	// and origin, most triggered first. Alerts without an origin are grouped
+	// under an empty one.
+	SourceStats(ctx context.Context, filter domain.ReportFilter) ([]*domain.SourceStats, error)
 }
 
 // EventManagerRepository defines the interface for event manager persistence.
@@ -39,14 +109,26 @@ type EventManagerRepository interface {
 	// Update modifies an existing event manager.
 	Update(ctx context.Context, em *domain.EventManager) error
 
-	// Delete removes an event manager by ID.
+	// Delete soft-deletes an event manager by ID, setting its DeletedAt.
+	// Returns domain.ErrEventManagerNotFound if it does not exist or is already deleted.
 	Delete(ctx context.Context, id string) error
 
-	// GetByID retrieves an event manager by its ID.
+	// Purge permanently removes an event manager by ID.
+	Purge(ctx context.Context, id string) error
+
+	// GetByID retrieves an event manager by its ID, including deleted ones.
 	GetByID(ctx context.Context, id string) (*domain.EventManager, error)
 
-	// List retrieves all event managers.
+	// GetByIngestToken retrieves an event manager by its ingest token, including deleted ones.
+	// Returns domain.ErrEventManagerNotFound if no event manager has the token.
+	GetByIngestToken(ctx context.Context, token string) (*domain.EventManager, error)
+
+	// List retrieves all event managers that are not deleted.
 	List(ctx context.Context) ([]*domain.EventManager, error)
+
+	// ListByGroupingRule retrieves all event managers referencing a grouping rule,
+	// including deleted ones.
+	ListByGroupingRule(ctx context.Context, groupingRuleID string) ([]*domain.EventManager, error)
 }
 
 // GroupingRuleRepository defines the interface for grouping rule persistence.
@@ -57,12 +139,230 @@ type GroupingRuleRepository interface {
 	// Update modifies an existing grouping rule.
 	Update(ctx context.Context, rule *domain.GroupingRule) error
 
-	// Delete removes a grouping rule by ID.
+	// Delete soft-deletes a grouping rule by ID, setting its DeletedAt.
+	// Returns domain.ErrGroupingRuleNotFound if it does not exist or is already deleted.
 	Delete(ctx context.Context, id string) error
 
-	// GetByID retrieves a grouping rule by its ID.
+	// Purge permanently removes a grouping rule by ID.
+	// Returns domain.ErrGroupingRuleInUse if an event manager still references it.
+	Purge(ctx context.Context, id string) error
+
+	// GetByID retrieves a grouping rule by its ID, including deleted ones.
 	GetByID(ctx context.Context, id string) (*domain.GroupingRule, error)
 
-	// List retrieves all grouping rules.
+	// List retrieves all grouping rules that are not deleted.
 	List(ctx context.Context) ([]*domain.GroupingRule, error)
 }
+
+// RoutingRuleRepository defines the interface for routing rule persistence.
+type RoutingRuleRepository interface {
+	// Create stores a new routing rule.
+	Create(ctx context.Context, rule *domain.RoutingRule) error
+
+	// Update modifies an existing routing rule.
+	Update(ctx context.Context, rule *domain.RoutingRule) error
+
+	// Delete permanently removes a routing rule by ID.
+	Delete(ctx context.Context, id string) error
+
+	// GetByID retrieves a routing rule by its ID.
+	GetByID(ctx context.Context, id string) (*domain.RoutingRule, error)
+
+	// List retrieves all routing rules in evaluation order.
+	List(ctx context.Context) ([]*domain.RoutingRule, error)
+}
+
+// WatchRepository defines the interface for the persistence of alert watches.
+type WatchRepository interface {
+	// Create stores a new watch.
+	Create(ctx context.Context, watch *domain.Watch) error
+
+	// Delete permanently removes a watch by ID.
+	Delete(ctx context.Context, id string) error
+
+	// GetByID retrieves a watch by its ID.
+	GetByID(ctx context.Context, id string) (*domain.Watch, error)
+
+	// List retrieves every watch, oldest first.
+	List(ctx context.Context) ([]*domain.Watch, error)
+
+	// ListByUser retrieves the watches of a user, oldest first.
+	ListByUser(ctx context.Context, user string) ([]*domain.Watch, error)
+}
+
+// SilenceRepository defines the interface for silence persistence.
+type SilenceRepository interface {
+	// Create stores a new silence. It returns domain.ErrSilenceExists if a
+	// silence with the same ID exists.
+	Create(ctx context.Context, silence *domain.Silence) error
+
+	// Delete permanently removes a silence by ID.
+	Delete(ctx context.Context, id string) error
+
+	// DeleteByRecurringID permanently removes the silences materialized from
+	// a recurring silence.
+	DeleteByRecurringID(ctx context.Context, recurringID string) error
+
+	// GetByID retrieves a silence by its ID.
+	GetByID(ctx context.Context, id string) (*domain.Silence, error)
+
+	// List retrieves every silence, oldest first.
+	List(ctx context.Context) ([]*domain.Silence, error)
+}
+
+// QueryRuleRepository defines the interface for query rule persistence.
+type QueryRuleRepository interface {
+	// Create stores a new query rule.
+	Create(ctx context.Context, rule *domain.QueryRule) error
+
+	// Update modifies an existing query rule.
+	Update(ctx context.Context, rule *domain.QueryRule) error
+
+	// Delete permanently removes a query rule by ID.
+	Delete(ctx context.Context, id string) error
+
+	// GetByID retrieves a query rule by its ID.
+	GetByID(ctx context.Context, id string) (*domain.QueryRule, error)
+
+	// List retrieves every query rule, oldest first.
+	List(ctx context.Context) ([]*domain.QueryRule, error)
+}
+
+// RuleTemplateRepository defines the interface for rule template persistence.
+type RuleTemplateRepository interface {
+	// Create stores a new rule template.
+	Create(ctx context.Context, template *domain.RuleTemplate) error
+
+	// Update modifies an existing rule template.
+	Update(ctx context.Context, template *domain.RuleTemplate) error
+
+	// Delete permanently removes a rule template by ID.
+	Delete(ctx context.Context, id string) error
+
+	// GetByID retrieves a rule template by its ID.
+	GetByID(ctx context.Context, id string) (*domain.RuleTemplate, error)
+
+	// List retrieves every rule template, oldest first.
+	List(ctx context.Context) ([]*domain.RuleTemplate, error)
+}
+
+// RuleExecutionRepository records the runs of query rules.
+type RuleExecutionRepository interface {
+	// Record appends a run of a rule, and removes its runs older than the
+	// latest keep.
+	Record(ctx context.Context, execution *domain.RuleExecution, keep int) error
+
+	// ListByRule retrieves the runs of a rule, newest first, at most limit.
+	ListByRule(ctx context.Context, ruleID string, limit int) ([]*domain.RuleExecution, error)
+
+	// DeleteByRule removes every run of a rule.
+	DeleteByRule(ctx context.Context, ruleID string) error
+}
+
+// RunnerRegistry tracks the replicas running query rules, to share the
+// rules between them.
+type RunnerRegistry interface {
+	// Heartbeat records that a replica is alive at now, forgets the replicas
+	// silent for longer than timeout, and returns the IDs of the live
+	// replicas, sorted.
+	Heartbeat(ctx context.Context, instanceID string, now time.Time, timeout time.Duration) ([]string, error)
+
+	// Leave removes a replica, handing its rules over to the others.
+	Leave(ctx context.Context, instanceID string) error
+}
+
+// NotificationLogRepository records the notifications sent about alerts.
+type NotificationLogRepository interface {
+	// Record appends a sent notification to the log.
+	Record(ctx context.Context, record *domain.NotificationRecord) error
+
+	// ListByDedupKey retrieves the notifications sent about an alert, oldest first.
+	ListByDedupKey(ctx context.Context, dedupKey string) ([]*domain.NotificationRecord, error)
+
+	// DeleteByEventManager removes the notifications sent to an event
+	// manager, and returns how many it removed.
+	DeleteByEventManager(ctx context.Context, eventManagerID string) (int, error)
+}
+
+// RemediationLogRepository records the remediation actions run for alerts.
+type RemediationLogRepository interface {
+	// Record appends a run of a remediation action to the log.
+	Record(ctx context.Context, record *domain.RemediationRecord) error
+
+	// ListByDedupKey retrieves the actions run for an alert, oldest first.
+	ListByDedupKey(ctx context.Context, dedupKey string) ([]*domain.RemediationRecord, error)
+
+	// DeleteByEventManager removes the actions run for an event manager,
+	// and returns how many it removed.
+	DeleteByEventManager(ctx context.Context, eventManagerID string) (int, error)
+}
+
+// AlertFeedbackRepository stores the feedback of responders on alerts.
+type AlertFeedbackRepository interface {
+	// Record appends feedback on an alert.
+	Record(ctx context.Context, feedback *domain.AlertFeedback) error
+
+	// ListByDedupKey retrieves the feedback on an alert, oldest first.
+	ListByDedupKey(ctx context.Context, dedupKey string) ([]*domain.AlertFeedback, error)
+
+	// List retrieves the feedback given within the range of the filter, of
+	// its event manager if set, oldest first.
+	List(ctx context.Context, filter domain.ReportFilter) ([]*domain.AlertFeedback, error)
+
+	// DeleteByEventManager removes the feedback on the alerts of an event
+	// manager, and returns how many it removed.
+	DeleteByEventManager(ctx context.Context, eventManagerID string) (int, error)
+}
+
+// AuditLogRepository records destructive operations and their approvals.
+type AuditLogRepository interface {
+	// Record chains an entry to the last one and appends it to the audit
+	// log, setting its sequence and hashes.
+	Record(ctx context.Context, entry *domain.AuditEntry) error
+
+	// List retrieves the most recent entries, newest first, at most limit.
+	List(ctx context.Context, limit int) ([]*domain.AuditEntry, error)
+
+	// Export retrieves every entry, oldest first, for verification.
+	Export(ctx context.Context) ([]*domain.AuditEntry, error)
+}
+
+// UsageRepository stores what each event manager used per day.
+type UsageRepository interface {
+	// Add adds the counts of a usage to those already stored for its event
+	// manager and day.
+	Add(ctx context.Context, usage *domain.Usage) error
+
+	// List retrieves the usage per event manager per day of the days within
+	// the filter range, ordered by event manager and day.
+	List(ctx context.Context, filter domain.ReportFilter) ([]*domain.Usage, error)
+
+	// DeleteByEventManager removes the usage of an event manager, and
+	// returns the number of days removed.
+	DeleteByEventManager(ctx context.Context, eventManagerID string) (int, error)
+}
+
+// ReportScheduleRepository defines the interface for the persistence of
+// scheduled reports.
+type ReportScheduleRepository interface {
+	// Create stores a new report schedule.
+	Create(ctx context.Context, schedule *domain.ReportSchedule) error
+
+	// Update replaces the settings of a report schedule, leaving the end of
+	// its last report as it is.
+	Update(ctx context.Context, schedule *domain.ReportSchedule) error
+
+	// Delete permanently removes a report schedule by ID.
+	Delete(ctx context.Context, id string) error
+
+	// GetByID retrieves a report schedule by its ID.
+	GetByID(ctx context.Context, id string) (*domain.ReportSchedule, error)
+
+	// List retrieves every report schedule, oldest first.
+	List(ctx context.Context) ([]*domain.ReportSchedule, error)
+
+	// MarkSent sets the end of the last report of a schedule to to, if it is
+	// still previous. It returns false if it was changed in the meantime, so
+	// only one replica sends each report.
+	MarkSent(ctx context.Context, id string, previous, to *time.Time) (bool, error)
+}
@@ -5,9 +5,16 @@ package store
 
 import (
 	"context"
+	"errors"
 	"time"
+
+	"argus-go/internal/domain"
 )
 
+// ErrTimeout is returned by a state store or repository operation that did
+// not complete within the configured operation timeout.
+var ErrTimeout = errors.New("store operation timed out")
+
 // ParentState represents the cached state of a parent alert in the state store.
 // This is used for fast lookups during event processing.
 type ParentState struct {
@@ -40,6 +47,11 @@ type AlertState struct {
 
 	// ResolveRequested indicates if a resolve was requested for this alert.
 	ResolveRequested bool `json:"resolve_requested"`
+
+	// PartitionKey is the partition key of the events that created the
+	// alert, so resolves requested through the API are queued in order
+	// with them. Empty for alerts created before it was recorded.
+	PartitionKey string `json:"partition_key,omitempty"`
 }
 
 // PendingResolve tracks a parent alert waiting for children to resolve.
@@ -51,6 +63,41 @@ type PendingResolve struct {
 	RemainingChildren int `json:"remaining_children"`
 }
 
+// Reminder schedules the next escalation reminder of an active parent alert.
+type Reminder struct {
+	// DedupKey is the deduplication key of the parent alert.
+	DedupKey string `json:"dedupKey"`
+
+	// DueAt is when the next reminder is sent.
+	DueAt time.Time `json:"due_at"`
+
+	// Sent is how many reminders have been sent so far.
+	Sent int `json:"sent"`
+}
+
+// LiveCount is the number of active alerts of an event manager at one
+// severity, kept up to date for dashboards.
+type LiveCount struct {
+	EventManagerID string `json:"event_manager_id"`
+	Severity       string `json:"severity"`
+	Count          int    `json:"count"`
+}
+
+// GroupingStatsRetention is how long the grouping statistics of a day are
+// kept.
+const GroupingStatsRetention = 31 * 24 * time.Hour
+
+// GroupingStats counts what a grouping rule grouped on one UTC day.
+type GroupingStats struct {
+	Day string `json:"day"` // YYYY-MM-DD
+
+	// ParentsCreated counts the parent alerts the rule opened a group with.
+	ParentsCreated int `json:"parents_created"`
+
+	// ChildrenGrouped counts the alerts the rule added to a group.
+	ChildrenGrouped int `json:"children_grouped"`
+}
+
 // StateStore defines the interface for fast in-memory state operations.
 // This is typically backed by Redis for production use.
 // All methods must be safe for concurrent use.
@@ -94,6 +141,10 @@ type StateStore interface {
 	// GetChildCount returns the number of children for a parent.
 	GetChildCount(ctx context.Context, parentDedupKey string) (int, error)
 
+	// GetActiveChildCount returns the number of children for a parent whose
+	// alert state is active.
+	GetActiveChildCount(ctx context.Context, parentDedupKey string) (int, error)
+
 	// --- Pending Resolution Operations ---
 
 	// SetPendingResolve marks a parent as having a pending resolve request.
@@ -106,6 +157,57 @@ type StateStore interface {
 	// DeletePendingResolve removes a pending resolve entry.
 	DeletePendingResolve(ctx context.Context, parentDedupKey string) error
 
+	// --- Reminder Operations ---
+
+	// SetReminder schedules or reschedules the reminder of a parent alert.
+	SetReminder(ctx context.Context, reminder *Reminder) error
+
+	// GetDueReminders returns up to limit reminders due at or before now,
+	// earliest first.
+	GetDueReminders(ctx context.Context, now time.Time, limit int) ([]*Reminder, error)
+
+	// DeleteReminder cancels the reminder of a parent alert.
+	DeleteReminder(ctx context.Context, dedupKey string) error
+
+	// --- Notification Operations ---
+
+	// MarkNotificationSent records a notification as sent for ttl. It
+	// returns false if the notification was already recorded, so it is sent
+	// once however often it is attempted.
+	MarkNotificationSent(ctx context.Context, notificationID string, ttl time.Duration) (bool, error)
+
+	// --- Live Count Operations ---
+
+	// AdjustLiveCount adds delta to the live count of active alerts of an
+	// event manager at a severity.
+	AdjustLiveCount(ctx context.Context, eventManagerID, severity string, delta int) error
+
+	// GetLiveCounts returns the live counts that are above zero.
+	GetLiveCounts(ctx context.Context) ([]*LiveCount, error)
+
+	// SetLiveCounts replaces all live counts, e.g. with the counts of the
+	// alert repository to correct drift.
+	SetLiveCounts(ctx context.Context, counts []*LiveCount) error
+
+	// --- Grouping Statistics Operations ---
+
+	// RecordGrouping counts an alert grouped by a grouping rule on a UTC day
+	// (YYYY-MM-DD): a new parent, or a child added to a group.
+	RecordGrouping(ctx context.Context, groupingRuleID, day string, child bool) error
+
+	// GetGroupingStats returns the statistics of a grouping rule on the
+	// given days, in the order of the days, skipping days without any.
+	GetGroupingStats(ctx context.Context, groupingRuleID string, days []string) ([]*GroupingStats, error)
+
+	// --- Event Status Operations ---
+
+	// SetEventStatus stores or replaces the status of an event for ttl.
+	SetEventStatus(ctx context.Context, status *domain.EventStatus, ttl time.Duration) error
+
+	// GetEventStatus retrieves the status of an event by its event ID.
+	// Returns nil, nil if the event has no status or it expired.
+	GetEventStatus(ctx context.Context, eventID string) (*domain.EventStatus, error)
+
 	// --- Lifecycle ---
 
 	// Close releases any resources held by the store.
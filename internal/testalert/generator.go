@@ -0,0 +1,158 @@
+// Package testalert raises synthetic test alerts, so the setup of an event
+// manager can be verified end to end: the alert goes through ingestion and
+// notification like any other, but is never grouped, and is resolved and
+// deleted once it expires. Test alerts carry their expiry in a tag, so any replica
+// cleans them up, even after the one that raised them restarted.
+package testalert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// dedupKeyPrefix starts the dedup key of every test alert.
+const dedupKeyPrefix = "argus-test-"
+
+// ErrInvalidTTL is returned for a TTL that is not positive or exceeds the
+// maximum.
+var ErrInvalidTTL = errors.New("ttl must be positive and at most max_ttl")
+
+// Ingester publishes events, like ingest.Service.
+type Ingester interface {
+	IngestEvent(ctx context.Context, event *domain.Event) error
+}
+
+// AlertDeleter removes an alert and its state, like processor.Service.
+type AlertDeleter interface {
+	DeleteAlert(ctx context.Context, alert *domain.Alert) error
+}
+
+// Generator raises test alerts and cleans them up once they expire. A nil
+// Generator means test alerts are disabled.
+type Generator struct {
+	ingester  Ingester
+	alertRepo store.AlertRepository
+	deleter   AlertDeleter
+	cfg       config.TestAlertsConfig
+	clock     clock.Clock
+	logger    *slog.Logger
+}
+
+// NewGenerator creates a generator publishing test alerts through ingester,
+// and finding and deleting the expired ones in alertRepo through deleter.
+func NewGenerator(
+	ingester Ingester,
+	alertRepo store.AlertRepository,
+	deleter AlertDeleter,
+	cfg config.TestAlertsConfig,
+	clk clock.Clock,
+	logger *slog.Logger,
+) *Generator {
+	return &Generator{
+		ingester:  ingester,
+		alertRepo: alertRepo,
+		deleter:   deleter,
+		cfg:       cfg,
+		clock:     clk,
+		logger:    logger,
+	}
+}
+
+// Create raises a test alert of the given severity for an event manager,
+// expiring after ttl; zero means the default TTL and an empty severity the
+// default severity. The alert is processed asynchronously, like any event.
+func (g *Generator) Create(ctx context.Context, eventManagerID string, severity domain.Severity, ttl time.Duration) (*domain.TestAlert, error) {
+	if g == nil {
+		return nil, domain.ErrTestAlertsDisabled
+	}
+	if ttl == 0 {
+		ttl = g.cfg.TTL
+	}
+	if ttl < 0 || ttl > g.cfg.MaxTTL {
+		return nil, fmt.Errorf("%w (%s)", ErrInvalidTTL, g.cfg.MaxTTL)
+	}
+
+	testAlert := &domain.TestAlert{
+		EventManagerID: eventManagerID,
+		DedupKey:       dedupKeyPrefix + uuid.New().String(),
+		ExpiresAt:      g.clock.Now().Add(ttl).UTC().Truncate(time.Second),
+	}
+	event := testAlert.Event(severity)
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+	if err := g.ingester.IngestEvent(ctx, event); err != nil {
+		return nil, err
+	}
+
+	g.logger.InfoContext(ctx, "raised test alert", "event_manager_id", eventManagerID, "dedupKey", testAlert.DedupKey, "expires_at", testAlert.ExpiresAt)
+	return testAlert, nil
+}
+
+// Start cleans up expired test alerts every check interval, until the
+// context is canceled.
+func (g *Generator) Start(ctx context.Context) {
+	g.logger.Info("starting test alert cleanup", "check_interval", g.cfg.CheckInterval)
+
+	ticker := time.NewTicker(g.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := g.Cleanup(ctx); err != nil && ctx.Err() == nil {
+			g.logger.Warn("failed to clean up test alerts", "error", err)
+		}
+	}
+}
+
+// Cleanup resolves the expired test alerts still open, through a resolve
+// event, so the resolution is notified too, and deletes those already
+// resolved. Test alerts raised before they were kept out of grouping may
+// have become the parent of other alerts; those are kept, resolved, so
+// their children aren't orphaned. Returns the first error,
+// after trying every alert.
+func (g *Generator) Cleanup(ctx context.Context) error {
+	alerts, err := g.alertRepo.List(ctx, domain.AlertFilter{Query: domain.TestAlertSummary})
+	if err != nil {
+		return fmt.Errorf("failed to list test alerts: %w", err)
+	}
+
+	now := g.clock.Now()
+	var firstErr error
+	for _, alert := range alerts {
+		expiresAt, ok := domain.TestAlertExpiry(alert)
+		if !ok || now.Before(expiresAt) {
+			continue
+		}
+
+		switch {
+		case alert.Status != domain.AlertStatusResolved:
+			err = g.ingester.IngestEvent(ctx, domain.TestAlertExpiredEvent(alert))
+		case alert.ChildCount > 0:
+			continue
+		default:
+			err = g.deleter.DeleteAlert(ctx, alert)
+			if err == nil {
+				g.logger.InfoContext(ctx, "deleted expired test alert", "event_manager_id", alert.EventManagerID, "dedupKey", alert.DedupKey)
+			}
+		}
+		if err != nil && !errors.Is(err, domain.ErrAlertNotFound) && firstErr == nil {
+			firstErr = fmt.Errorf("failed to clean up test alert %s: %w", alert.DedupKey, err)
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,128 @@
+package testalert
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/config"
+	"argus-go/internal/domain"
+	storemem "argus-go/internal/store/memory"
+)
+
+// recordingIngester records the events ingested.
+type recordingIngester struct {
+	events []*domain.Event
+}
+
+func (i *recordingIngester) IngestEvent(_ context.Context, event *domain.Event) error {
+	i.events = append(i.events, event)
+	return nil
+}
+
+// repoDeleter deletes alerts from the repository only.
+type repoDeleter struct {
+	repo *storemem.AlertRepository
+}
+
+func (d repoDeleter) DeleteAlert(ctx context.Context, alert *domain.Alert) error {
+	return d.repo.Delete(ctx, alert.DedupKey)
+}
+
+func newTestGenerator(clk clock.Clock) (*Generator, *recordingIngester, *storemem.AlertRepository) {
+	ingester := &recordingIngester{}
+	repo := storemem.NewAlertRepository()
+	generator := NewGenerator(ingester, repo, repoDeleter{repo: repo}, config.TestAlertsConfig{
+		TTL:    5 * time.Minute,
+		MaxTTL: time.Hour,
+	}, clk, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return generator, ingester, repo
+}
+
+func TestGenerator_Create(t *testing.T) {
+	now := time.Date(2026, 10, 15, 14, 30, 0, 0, time.UTC)
+	generator, ingester, _ := newTestGenerator(clock.NewFake(now))
+	ctx := context.Background()
+
+	testAlert, err := generator.Create(ctx, "em-1", domain.SeverityLow, 0)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if !strings.HasPrefix(testAlert.DedupKey, dedupKeyPrefix) || !testAlert.ExpiresAt.Equal(now.Add(5*time.Minute)) {
+		t.Errorf("Create() = %+v", testAlert)
+	}
+	if len(ingester.events) != 1 {
+		t.Fatalf("ingested = %d events, want 1", len(ingester.events))
+	}
+	event := ingester.events[0]
+	if event.DedupKey != testAlert.DedupKey || event.Summary != domain.TestAlertSummary || event.Tags[domain.TestAlertTag] != "true" {
+		t.Errorf("event = %+v", event)
+	}
+
+	for _, ttl := range []time.Duration{-time.Minute, 2 * time.Hour} {
+		if _, err := generator.Create(ctx, "em-1", domain.SeverityLow, ttl); !errors.Is(err, ErrInvalidTTL) {
+			t.Errorf("Create(ttl %s) error = %v, want ErrInvalidTTL", ttl, err)
+		}
+	}
+
+	var disabled *Generator
+	if _, err := disabled.Create(ctx, "em-1", domain.SeverityLow, 0); !errors.Is(err, domain.ErrTestAlertsDisabled) {
+		t.Errorf("Create() on a nil generator error = %v, want ErrTestAlertsDisabled", err)
+	}
+}
+
+func TestGenerator_Cleanup(t *testing.T) {
+	now := time.Date(2026, 10, 15, 14, 30, 0, 0, time.UTC)
+	clk := clock.NewFake(now)
+	generator, ingester, repo := newTestGenerator(clk)
+	ctx := context.Background()
+
+	testAlert, err := generator.Create(ctx, "em-1", domain.SeverityLow, time.Minute)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	alert := domain.NewParentAlert(ingester.events[0], now)
+	alert.ID = "alert-1"
+	if err := repo.Create(ctx, alert); err != nil {
+		t.Fatalf("Create() alert error = %v", err)
+	}
+	// An alert with the same summary but without the marker tag is no test alert
+	impostor := domain.NewParentAlert(&domain.Event{EventManagerID: "em-1", DedupKey: "other", Summary: domain.TestAlertSummary}, now)
+	impostor.ID = "alert-2"
+	_ = repo.Create(ctx, impostor)
+
+	// Not expired yet
+	if err := generator.Cleanup(ctx); err != nil {
+		t.Fatalf("Cleanup() error = %v", err)
+	}
+	if len(ingester.events) != 1 {
+		t.Fatalf("ingested = %d events, want none before expiry", len(ingester.events)-1)
+	}
+
+	// Expired and active: resolved through a resolve event
+	clk.Advance(time.Minute)
+	if err := generator.Cleanup(ctx); err != nil {
+		t.Fatalf("Cleanup() error = %v", err)
+	}
+	if len(ingester.events) != 2 || ingester.events[1].Action != domain.ActionResolve || ingester.events[1].DedupKey != testAlert.DedupKey {
+		t.Fatalf("ingested = %+v, want a resolve of the test alert", ingester.events)
+	}
+
+	// Expired and resolved: deleted
+	alert.Status = domain.AlertStatusResolved
+	_ = repo.Update(ctx, alert)
+	if err := generator.Cleanup(ctx); err != nil {
+		t.Fatalf("Cleanup() error = %v", err)
+	}
+	if _, err := repo.GetByDedupKey(ctx, testAlert.DedupKey); !errors.Is(err, domain.ErrAlertNotFound) {
+		t.Errorf("GetByDedupKey() error = %v, want ErrAlertNotFound", err)
+	}
+	if _, err := repo.GetByDedupKey(ctx, "other"); err != nil {
+		t.Errorf("the alert without the marker tag was cleaned up: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package testgen
+
+import (
+	"errors"
+	"fmt"
+
+	"argus-go/internal/domain"
+)
+
+// CheckAlerts verifies the invariants of a set of alerts, such as all alerts
+// of an event manager:
+//   - every child references an existing parent alert (no orphan children)
+//   - the child count of every parent matches its children
+//   - resolved alerts have a resolution time, and active ones don't
+//
+// It returns every violation found, joined, or nil.
+func CheckAlerts(alerts []*domain.Alert) error {
+	byKey := make(map[string]*domain.Alert, len(alerts))
+	for _, alert := range alerts {
+		byKey[alert.DedupKey] = alert
+	}
+
+	var errs []error
+	children := make(map[string]int)
+	for _, alert := range alerts {
+		switch alert.Type {
+		case domain.AlertTypeChild:
+			parent, ok := byKey[alert.ParentDedupKey]
+			switch {
+			case !ok:
+				errs = append(errs, fmt.Errorf("child %q: parent %q not found", alert.DedupKey, alert.ParentDedupKey))
+			case parent.Type != domain.AlertTypeParent:
+				errs = append(errs, fmt.Errorf("child %q: parent %q is a %s alert", alert.DedupKey, alert.ParentDedupKey, parent.Type))
+			}
+			children[alert.ParentDedupKey]++
+		case domain.AlertTypeParent:
+			if alert.ParentDedupKey != "" {
+				errs = append(errs, fmt.Errorf("parent %q references parent %q", alert.DedupKey, alert.ParentDedupKey))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("alert %q: unknown type %q", alert.DedupKey, alert.Type))
+		}
+
+		resolved := alert.Status == domain.AlertStatusResolved
+		if resolved != (alert.ResolvedAt != nil) {
+			errs = append(errs, fmt.Errorf("alert %q: status %s with resolved_at %v", alert.DedupKey, alert.Status, alert.ResolvedAt))
+		}
+	}
+
+	for _, alert := range alerts {
+		if alert.Type == domain.AlertTypeParent && alert.ChildCount != children[alert.DedupKey] {
+			errs = append(errs, fmt.Errorf("parent %q: child count %d, but %d children", alert.DedupKey, alert.ChildCount, children[alert.DedupKey]))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,117 @@
+package testgen
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+)
+
+func TestGenerator_SameSeedSameEvents(t *testing.T) {
+	spec := EventSpec{EventManagerID: "em-1", ResolveRatio: 0.5}
+	a, b := New(42), New(42)
+	for i := 0; i < 100; i++ {
+		if got, want := a.Event(spec), b.Event(spec); !reflect.DeepEqual(got, want) {
+			t.Fatalf("event %d differs for the same seed: %+v != %+v", i, got, want)
+		}
+	}
+}
+
+func TestGenerator_ValidData(t *testing.T) {
+	gen := New(1)
+	spec := EventSpec{EventManagerID: "em-1", KeyPrefix: "run-", Keyspace: 10, ResolveRatio: 0.5}
+
+	classes := make(map[string]string)
+	for i := 0; i < 200; i++ {
+		event := gen.Event(spec)
+		if err := event.Validate(); err != nil {
+			t.Fatalf("Event() = %+v is invalid: %v", event, err)
+		}
+		if class, ok := classes[event.DedupKey]; ok && class != event.Class {
+			t.Errorf("dedup key %s has classes %s and %s", event.DedupKey, class, event.Class)
+		}
+		classes[event.DedupKey] = event.Class
+	}
+	if len(classes) > 10 {
+		t.Errorf("got %d dedup keys, want at most the keyspace of 10", len(classes))
+	}
+
+	for i := 0; i < 20; i++ {
+		rule := gen.GroupingRule("rule-1")
+		if err := rule.Validate(); err != nil {
+			t.Errorf("GroupingRule() = %+v is invalid: %v", rule, err)
+		}
+		em := gen.EventManager("em-1", rule.ID)
+		if err := em.Validate(); err != nil {
+			t.Errorf("EventManager() = %+v is invalid: %v", em, err)
+		}
+	}
+}
+
+func TestGenerator_InvalidEvents(t *testing.T) {
+	gen := New(7)
+	spec := EventSpec{EventManagerID: "em-1"}
+	for i := 0; i < 200; i++ {
+		event, want := gen.InvalidEvent(spec)
+		if err := event.Validate(); !errors.Is(err, want) {
+			t.Fatalf("InvalidEvent() = %+v: Validate() = %v, want %v", event, err, want)
+		}
+	}
+}
+
+func TestCheckAlerts(t *testing.T) {
+	now := time.Now().UTC()
+	parent := func(key string, children int) *domain.Alert {
+		return &domain.Alert{DedupKey: key, Type: domain.AlertTypeParent, Status: domain.AlertStatusActive, ChildCount: children}
+	}
+	child := func(key, parentKey string) *domain.Alert {
+		return &domain.Alert{DedupKey: key, Type: domain.AlertTypeChild, Status: domain.AlertStatusActive, ParentDedupKey: parentKey}
+	}
+
+	tests := []struct {
+		name    string
+		alerts  []*domain.Alert
+		wantErr bool
+	}{
+		{
+			name:   "consistent",
+			alerts: []*domain.Alert{parent("p1", 2), child("c1", "p1"), child("c2", "p1"), parent("p2", 0)},
+		},
+		{
+			name:    "orphan child",
+			alerts:  []*domain.Alert{parent("p1", 0), child("c1", "missing")},
+			wantErr: true,
+		},
+		{
+			name:    "child of a child",
+			alerts:  []*domain.Alert{parent("p1", 1), child("c1", "p1"), child("c2", "c1")},
+			wantErr: true,
+		},
+		{
+			name:    "child count mismatch",
+			alerts:  []*domain.Alert{parent("p1", 3), child("c1", "p1")},
+			wantErr: true,
+		},
+		{
+			name:    "resolved without resolution time",
+			alerts:  []*domain.Alert{{DedupKey: "p1", Type: domain.AlertTypeParent, Status: domain.AlertStatusResolved}},
+			wantErr: true,
+		},
+		{
+			name:    "active with resolution time",
+			alerts:  []*domain.Alert{{DedupKey: "p1", Type: domain.AlertTypeParent, Status: domain.AlertStatusActive, ResolvedAt: &now}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckAlerts(tt.alerts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckAlerts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
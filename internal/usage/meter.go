@@ -0,0 +1,133 @@
+// Package usage accounts what each event manager uses per day: the events
+// ingested, the alerts created and the notifications sent, for chargeback
+// and capacity planning. Counts are kept in memory and added to the usage
+// repository periodically, so accounting adds no store write per event.
+package usage
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
+	"argus-go/internal/store"
+)
+
+// key identifies the usage of an event manager on a day.
+type key struct {
+	eventManagerID string
+	day            string
+}
+
+// Meter counts usage and flushes it to a usage repository. A nil Meter
+// counts nothing, so accounting can be disabled. It is safe for concurrent
+// use.
+type Meter struct {
+	repo   store.UsageRepository
+	clock  clock.Clock
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	pending map[key]*domain.Usage
+}
+
+// NewMeter creates a meter flushing to repo, dating usage with clk.
+func NewMeter(repo store.UsageRepository, clk clock.Clock, logger *slog.Logger) *Meter {
+	return &Meter{
+		repo:    repo,
+		clock:   clk,
+		logger:  logger,
+		pending: make(map[key]*domain.Usage),
+	}
+}
+
+// RecordEvent counts an event ingested for an event manager.
+func (m *Meter) RecordEvent(eventManagerID string) {
+	m.record(eventManagerID, func(u *domain.Usage) { u.EventsIngested++ })
+}
+
+// RecordAlert counts an alert created for an event manager.
+func (m *Meter) RecordAlert(eventManagerID string) {
+	m.record(eventManagerID, func(u *domain.Usage) { u.AlertsCreated++ })
+}
+
+// RecordNotification counts a notification sent for an event manager.
+func (m *Meter) RecordNotification(eventManagerID string) {
+	m.record(eventManagerID, func(u *domain.Usage) { u.NotificationsSent++ })
+}
+
+// record applies a count to the pending usage of an event manager today.
+func (m *Meter) record(eventManagerID string, count func(*domain.Usage)) {
+	if m == nil || eventManagerID == "" {
+		return
+	}
+	k := key{eventManagerID: eventManagerID, day: domain.ReportDay(m.clock.Now())}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u, ok := m.pending[k]
+	if !ok {
+		u = &domain.Usage{EventManagerID: k.eventManagerID, Day: k.day}
+		m.pending[k] = u
+	}
+	count(u)
+}
+
+// Start flushes the pending usage every interval until the context is
+// canceled. Usage counted after that is flushed by a last call to Flush.
+func (m *Meter) Start(ctx context.Context, interval time.Duration) {
+	m.logger.Info("starting usage accounting", "flush_interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Flush(ctx); err != nil {
+				m.logger.Warn("failed to flush usage", "error", err)
+			}
+		}
+	}
+}
+
+// Flush adds the pending usage to the repository. Usage that fails to be
+// added stays pending for the next flush, and the first error is returned.
+func (m *Meter) Flush(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	pending := m.pending
+	m.pending = make(map[key]*domain.Usage)
+	m.mu.Unlock()
+
+	var firstErr error
+	for k, u := range pending {
+		if err := m.repo.Add(ctx, u); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			m.restore(k, u)
+		}
+	}
+	return firstErr
+}
+
+// restore puts back usage that could not be flushed.
+func (m *Meter) restore(k key, u *domain.Usage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if current, ok := m.pending[k]; ok {
+		current.Add(u)
+		return
+	}
+	m.pending[k] = u
+}
@@ -0,0 +1,102 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"argus-go/internal/clock"
+	"argus-go/internal/domain"
+	memorystor "argus-go/internal/store/memory"
+)
+
+// failingRepo fails every Add while fail is set.
+type failingRepo struct {
+	*memorystor.UsageRepository
+	fail bool
+}
+
+func (r *failingRepo) Add(ctx context.Context, usage *domain.Usage) error {
+	if r.fail {
+		return errors.New("store unavailable")
+	}
+	return r.UsageRepository.Add(ctx, usage)
+}
+
+func listAll(t *testing.T, repo *memorystor.UsageRepository, now time.Time) []*domain.Usage {
+	t.Helper()
+	usage, err := repo.List(context.Background(), domain.ReportFilter{From: now.Add(-7 * 24 * time.Hour), To: now})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	return usage
+}
+
+func TestMeter_FlushByDay(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC))
+	repo := memorystor.NewUsageRepository()
+	meter := NewMeter(repo, clk, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	meter.RecordEvent("em-1")
+	meter.RecordEvent("em-1")
+	meter.RecordAlert("em-1")
+	meter.RecordNotification("em-2")
+	clk.Advance(2 * time.Hour)
+	meter.RecordEvent("em-1")
+
+	if err := meter.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	// Flushing again adds nothing
+	if err := meter.Flush(context.Background()); err != nil {
+		t.Fatalf("second Flush() error = %v", err)
+	}
+
+	got := listAll(t, repo, clk.Now())
+	want := []domain.Usage{
+		{EventManagerID: "em-1", Day: "2026-03-01", EventsIngested: 2, AlertsCreated: 1},
+		{EventManagerID: "em-1", Day: "2026-03-02", EventsIngested: 1},
+		{EventManagerID: "em-2", Day: "2026-03-01", NotificationsSent: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("usage = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if *got[i] != want[i] {
+			t.Errorf("usage[%d] = %+v, want %+v", i, *got[i], want[i])
+		}
+	}
+}
+
+func TestMeter_FailedFlushIsRetried(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	repo := &failingRepo{UsageRepository: memorystor.NewUsageRepository(), fail: true}
+	meter := NewMeter(repo, clk, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	meter.RecordEvent("em-1")
+	if err := meter.Flush(context.Background()); err == nil {
+		t.Fatal("Flush() error = nil, want the store error")
+	}
+
+	meter.RecordEvent("em-1")
+	repo.fail = false
+	if err := meter.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	got := listAll(t, repo.UsageRepository, clk.Now())
+	if len(got) != 1 || got[0].EventsIngested != 2 {
+		t.Errorf("usage = %+v, want 2 events of em-1", got)
+	}
+}
+
+func TestMeter_Nil(t *testing.T) {
+	var meter *Meter
+	meter.RecordEvent("em-1")
+	if err := meter.Flush(context.Background()); err != nil {
+		t.Errorf("Flush() on a nil meter error = %v", err)
+	}
+}
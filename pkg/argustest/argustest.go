@@ -0,0 +1,487 @@
+// Package argustest provides a fully wired, in-process ArgusGo instance for tests.
+// It runs the HTTP server and the processor on the in-memory queue and stores,
+// so services that integrate with ArgusGo can exercise it without containers.
+//
+// Typical use:
+//
+//	h := argustest.Start(t)
+//	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+//	h.Ingest(t, &argustest.Event{EventManagerID: emID, ...})
+//	alert := h.AwaitStatus(t, "db-1", argustest.AlertStatusActive)
+package argustest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"maps"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"argus-go/internal/api"
+	"argus-go/internal/approval"
+	"argus-go/internal/clock"
+	"argus-go/internal/clone"
+	"argus-go/internal/config"
+	"argus-go/internal/declarative"
+	"argus-go/internal/domain"
+	"argus-go/internal/erasure"
+	"argus-go/internal/eventstatus"
+	"argus-go/internal/ingest"
+	"argus-go/internal/notification"
+	"argus-go/internal/processor"
+	"argus-go/internal/queue"
+	memoryqueue "argus-go/internal/queue/memory"
+	"argus-go/internal/quota"
+	"argus-go/internal/remediation"
+	"argus-go/internal/silence"
+	memorystor "argus-go/internal/store/memory"
+	"argus-go/internal/testalert"
+	"argus-go/internal/usage"
+)
+
+// Aliases of the domain types used by the harness API. The domain package is
+// internal to ArgusGo, so downstream modules refer to these instead.
+type (
+	Event       = domain.Event
+	Alert       = domain.Alert
+	AlertStatus = domain.AlertStatus
+)
+
+// Event actions, severities and alert statuses re-exported for downstream modules.
+const (
+	ActionTrigger = domain.ActionTrigger
+	ActionResolve = domain.ActionResolve
+
+	SeverityHigh   = domain.SeverityHigh
+	SeverityMedium = domain.SeverityMedium
+	SeverityLow    = domain.SeverityLow
+
+	AlertStatusActive   = domain.AlertStatusActive
+	AlertStatusResolved = domain.AlertStatusResolved
+)
+
+// DefaultTimeout is how long the Await helpers wait before failing the test.
+const DefaultTimeout = 5 * time.Second
+
+// pollInterval is how often the Await helpers re-check the alert repository.
+const pollInterval = 5 * time.Millisecond
+
+// Harness is a running in-memory ArgusGo instance.
+type Harness struct {
+	// URL is the base URL of the HTTP API, e.g. "http://127.0.0.1:54321".
+	URL string
+
+	// Timeout bounds Sync and the Await helpers. Defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// The in-memory stores backing the instance, exposed for direct inspection.
+	StateStore       *memorystor.StateStore
+	AlertRepo        *memorystor.AlertRepository
+	EventManagerRepo *memorystor.EventManagerRepository
+	GroupingRuleRepo *memorystor.GroupingRuleRepository
+	RoutingRuleRepo  *memorystor.RoutingRuleRepository
+	WatchRepo        *memorystor.WatchRepository
+	SilenceRepo      *memorystor.SilenceRepository
+	NotificationLog  *memorystor.NotificationLogRepository
+	RemediationLog   *memorystor.RemediationLogRepository
+	AlertFeedback    *memorystor.AlertFeedbackRepository
+	AuditLog         *memorystor.AuditLogRepository
+	UsageRepo        *memorystor.UsageRepository
+
+	// GroupingDefaults holds the system-wide default grouping rule, initially unset.
+	GroupingDefaults *ingest.GroupingDefaults
+
+	ingestService *ingest.Service
+	router        *ingest.Router
+	queue         *trackingQueue
+	clock         *clock.Fake
+	usage         *usage.Meter
+	events        *eventstatus.Tracker
+	quotas        *quota.Enforcer
+	silences      *silence.Scheduler
+	processor     *processor.Service
+}
+
+// Start wires and starts an in-memory ArgusGo instance listening on an
+// ephemeral localhost port. The instance is stopped when the test ends.
+func Start(tb testing.TB) *Harness {
+	tb.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	clk := clock.NewFake(time.Now().UTC())
+
+	h := &Harness{
+		Timeout:          DefaultTimeout,
+		StateStore:       memorystor.NewStateStoreWithClock(clk),
+		AlertRepo:        memorystor.NewAlertRepositoryWithClock(clk),
+		EventManagerRepo: memorystor.NewEventManagerRepository(),
+		GroupingRuleRepo: memorystor.NewGroupingRuleRepository(),
+		RoutingRuleRepo:  memorystor.NewRoutingRuleRepository(),
+		WatchRepo:        memorystor.NewWatchRepository(),
+		SilenceRepo:      memorystor.NewSilenceRepository(),
+		NotificationLog:  memorystor.NewNotificationLogRepository(),
+		RemediationLog:   memorystor.NewRemediationLogRepository(),
+		AlertFeedback:    memorystor.NewAlertFeedbackRepository(),
+		AuditLog:         memorystor.NewAuditLogRepository(),
+		UsageRepo:        memorystor.NewUsageRepository(),
+		GroupingDefaults: ingest.NewGroupingDefaults(""),
+		queue:            newTrackingQueue(memoryqueue.NewQueue(10000)),
+		clock:            clk,
+	}
+
+	h.usage = usage.NewMeter(h.UsageRepo, clk, logger)
+	h.quotas = quota.NewEnforcer(h.EventManagerRepo, h.UsageRepo, h.AlertRepo, clk, logger)
+	h.silences = silence.NewScheduler(h.SilenceRepo, time.Minute, clk, logger)
+	h.router = ingest.NewRouter(h.RoutingRuleRepo, logger)
+	h.events = eventstatus.NewTracker(h.StateStore, time.Hour, clk, logger)
+	h.ingestService = ingest.NewService(h.queue, h.EventManagerRepo, h.GroupingRuleRepo, h.GroupingDefaults, h.quotas, nil, h.events, logger)
+
+	processorService := processor.NewService(
+		h.queue,
+		h.StateStore,
+		h.AlertRepo,
+		h.EventManagerRepo,
+		h.GroupingRuleRepo,
+		notification.NewDedupNotifier(
+			notification.NewSilenceNotifier(
+				notification.NewQuotaNotifier(
+					notification.NewUsageNotifier(notification.NewRecordingNotifier(notification.NewStubNotifier(logger), h.NotificationLog, logger), h.usage),
+					h.quotas,
+					logger,
+				),
+				h.silences,
+				logger,
+			),
+			h.StateStore,
+			config.NotificationDedupConfig{Window: time.Minute, TTL: 24 * time.Hour},
+			clk,
+			logger,
+		),
+		remediation.NewExecutor(nil, h.RemediationLog, DefaultTimeout, clk, logger),
+		nil,
+		notification.NewMuter(h.silences, h.quotas),
+		nil,
+		h.usage,
+		h.events,
+		config.DedupConfig{},
+		config.ProcessorConfig{Storms: config.StormsConfig{QuietPeriod: 5 * time.Minute}},
+		clk,
+		logger,
+	)
+
+	h.processor = processorService
+
+	approvals := approval.NewGate(config.ApprovalsConfig{}, h.AuditLog, clk, logger)
+	cloner := clone.NewCloner(h.EventManagerRepo, h.GroupingRuleRepo, h.RoutingRuleRepo, h.SilenceRepo, h.silences, clk, logger)
+	testAlerts := testalert.NewGenerator(h.ingestService, h.AlertRepo, processorService, config.TestAlertsConfig{TTL: 5 * time.Minute, MaxTTL: time.Hour}, clk, logger)
+	queryRuleHandler := api.NewQueryRuleHandler(memorystor.NewQueryRuleRepository(), memorystor.NewRuleExecutionRepository(), h.EventManagerRepo, nil, clk, logger)
+
+	graphQLHandler, err := api.NewGraphQLHandler(h.AlertRepo, h.EventManagerRepo, h.GroupingRuleRepo, logger)
+	if err != nil {
+		tb.Fatalf("argustest: failed to build GraphQL schema: %v", err)
+	}
+
+	server := api.NewServer(api.ServerDeps{
+		Config: &config.ServerConfig{
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 10 * time.Second,
+			IdleTimeout:  time.Minute,
+			Compression: config.CompressionConfig{
+				Enabled:      true,
+				ContentTypes: []string{"application/json"},
+			},
+		},
+		Logger:              logger,
+		EventManagerHandler: api.NewEventManagerHandler(h.EventManagerRepo, h.GroupingRuleRepo, processorService, notification.NewTester(logger), testAlerts, cloner, approvals, logger),
+		GroupingRuleHandler: api.NewGroupingRuleHandler(h.GroupingRuleRepo, h.EventManagerRepo, h.GroupingDefaults, h.StateStore, approvals, logger),
+		AlertHandler:        api.NewAlertHandler(h.AlertRepo, nil, h.StateStore, h.NotificationLog, h.RemediationLog, processorService, h.ingestService, approvals, notification.NewMuter(h.silences, h.quotas), clk, logger),
+		IngestHandler:       api.NewIngestHandler(h.ingestService, h.router, h.StateStore, h.events, logger),
+		ReportHandler:       api.NewReportHandler(memorystor.NewReportRepository(h.AlertRepo), logger),
+		UsageHandler:        api.NewUsageHandler(h.UsageRepo, h.EventManagerRepo, h.quotas, logger),
+		SLOHandler:          api.NewSLOHandler(nil, logger),
+		ConfigHandler:       api.NewConfigHandler(declarative.NewService(h.EventManagerRepo, h.GroupingRuleRepo, logger), logger),
+		RoutingRuleHandler:  api.NewRoutingRuleHandler(h.RoutingRuleRepo, h.EventManagerRepo, logger),
+		ProcessorHandler:    api.NewProcessorHandler(processorService, nil, logger),
+		ApprovalHandler:     api.NewApprovalHandler(approvals, h.AuditLog, logger),
+		DataHandler:         api.NewDataHandler(erasure.NewDeleter(processorService, nil, h.NotificationLog, h.RemediationLog, h.AlertFeedback, h.UsageRepo, nil, clk, logger), approvals, logger),
+		WatchHandler:        api.NewWatchHandler(h.WatchRepo, nil, logger),
+		FeedbackHandler:     api.NewFeedbackHandler(h.AlertFeedback, h.AlertRepo, h.EventManagerRepo, clk, logger),
+		SilenceHandler:      api.NewSilenceHandler(h.SilenceRepo, h.silences, clk, logger),
+		QueryRuleHandler:    queryRuleHandler,
+		RuleTemplateHandler: api.NewRuleTemplateHandler(memorystor.NewRuleTemplateRepository(), queryRuleHandler, logger),
+		GraphQLHandler:      graphQLHandler,
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatalf("argustest: failed to listen: %v", err)
+	}
+	h.URL = "http://" + ln.Addr().String()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { _ = processorService.Start(ctx) }()
+	go func() { _ = server.Serve(ln) }()
+
+	tb.Cleanup(func() {
+		cancel()
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		_ = server.Shutdown(shutdownCtx)
+		_ = h.queue.Close()
+	})
+
+	return h
+}
+
+// Now returns the time of the instance's clock. The clock starts at the
+// time the harness was started and only moves with Advance, so alert
+// timestamps are deterministic.
+func (h *Harness) Now() time.Time {
+	return h.clock.Now()
+}
+
+// Advance moves the instance's clock forward, e.g. past the time window of a
+// grouping rule so the next event opens a new parent alert.
+func (h *Harness) Advance(d time.Duration) {
+	h.clock.Advance(d)
+}
+
+// FlushUsage stores the usage counted so far, which the instance otherwise
+// keeps in memory, so the usage API reports it.
+func (h *Harness) FlushUsage(tb testing.TB) {
+	tb.Helper()
+
+	if err := h.usage.Flush(context.Background()); err != nil {
+		tb.Fatalf("argustest: failed to flush usage: %v", err)
+	}
+}
+
+// CheckQuotas stores the usage counted so far and checks the quotas of event
+// managers against it. The instance checks quotas only when told to, so tests
+// decide when a limit takes effect.
+func (h *Harness) CheckQuotas(tb testing.TB) {
+	tb.Helper()
+
+	h.FlushUsage(tb)
+	if err := h.quotas.Check(context.Background()); err != nil {
+		tb.Fatalf("argustest: failed to check quotas: %v", err)
+	}
+}
+
+// CheckSilences materializes the occurrences of recurring silences due
+// within a minute and reloads the silences in effect. Silences created
+// through the API take effect right away; the instance materializes
+// recurring ones only when told to.
+func (h *Harness) CheckSilences(tb testing.TB) {
+	tb.Helper()
+
+	if err := h.silences.Check(context.Background()); err != nil {
+		tb.Fatalf("argustest: failed to check silences: %v", err)
+	}
+}
+
+// CheckStorms measures the event rate of every event manager since the last
+// check, starting or ending their alert storms. The instance detects storms
+// only when told to, so tests decide over which period a rate is measured.
+func (h *Harness) CheckStorms(tb testing.TB) {
+	tb.Helper()
+
+	h.Sync(tb)
+	if err := h.processor.CheckStorms(context.Background()); err != nil {
+		tb.Fatalf("argustest: failed to check storms: %v", err)
+	}
+}
+
+// CreateEventManager creates a grouping rule on groupingKey with the given
+// time window and an event manager using it. Returns the event manager ID.
+func (h *Harness) CreateEventManager(tb testing.TB, groupingKey string, window time.Duration) string {
+	tb.Helper()
+
+	ctx := context.Background()
+	now := h.clock.Now()
+
+	rule := &domain.GroupingRule{
+		ID:                uuid.New().String(),
+		Name:              "argustest " + groupingKey,
+		GroupingKey:       groupingKey,
+		Window:            domain.Duration(window),
+		TimeWindowMinutes: int(window / time.Minute),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := rule.Validate(); err != nil {
+		tb.Fatalf("argustest: invalid grouping rule: %v", err)
+	}
+	if err := h.GroupingRuleRepo.Create(ctx, rule); err != nil {
+		tb.Fatalf("argustest: failed to create grouping rule: %v", err)
+	}
+
+	em := &domain.EventManager{
+		ID:             uuid.New().String(),
+		Name:           "argustest",
+		GroupingRuleID: rule.ID,
+		IngestToken:    domain.NewIngestToken(),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := h.EventManagerRepo.Create(ctx, em); err != nil {
+		tb.Fatalf("argustest: failed to create event manager: %v", err)
+	}
+
+	return em.ID
+}
+
+// Ingest routes, validates and ingests an event exactly as POST /v1/events would.
+func (h *Harness) Ingest(tb testing.TB, event *domain.Event) {
+	tb.Helper()
+
+	if err := h.router.Route(context.Background(), event); err != nil {
+		tb.Fatalf("argustest: failed to route event %q: %v", event.DedupKey, err)
+	}
+	if err := event.Validate(); err != nil {
+		tb.Fatalf("argustest: invalid event %q: %v", event.DedupKey, err)
+	}
+	if err := h.ingestService.IngestEvent(context.Background(), event); err != nil {
+		tb.Fatalf("argustest: failed to ingest event %q: %v", event.DedupKey, err)
+	}
+}
+
+// ObserveProcessing registers fn to be called with the time each event
+// spent between being queued and being processed, e.g. to measure the
+// latency of the pipeline. Only events ingested afterwards are observed.
+func (h *Harness) ObserveProcessing(fn func(latency time.Duration)) {
+	h.queue.mu.Lock()
+	defer h.queue.mu.Unlock()
+	h.queue.observe = fn
+}
+
+// Sync blocks until every event ingested so far has been processed.
+// Combined with Ingest this makes test flows fully deterministic.
+func (h *Harness) Sync(tb testing.TB) {
+	tb.Helper()
+
+	if err := h.queue.waitIdle(h.Timeout); err != nil {
+		tb.Fatalf("argustest: %v", err)
+	}
+}
+
+// AwaitAlert waits until the alert with the given dedup key exists and
+// satisfies cond, and returns it. A nil cond only waits for existence.
+func (h *Harness) AwaitAlert(tb testing.TB, dedupKey string, cond func(*domain.Alert) bool) *domain.Alert {
+	tb.Helper()
+
+	deadline := time.Now().Add(h.Timeout)
+	var last *domain.Alert
+	for {
+		alert, err := h.AlertRepo.GetByDedupKey(context.Background(), dedupKey)
+		if err == nil {
+			last = alert
+			if cond == nil || cond(alert) {
+				return alert
+			}
+		} else if !errors.Is(err, domain.ErrAlertNotFound) {
+			tb.Fatalf("argustest: failed to get alert %q: %v", dedupKey, err)
+		}
+
+		if time.Now().After(deadline) {
+			if last == nil {
+				tb.Fatalf("argustest: alert %q not created within %s", dedupKey, h.Timeout)
+			}
+			tb.Fatalf("argustest: alert %q did not reach the expected state within %s (status=%s, type=%s)",
+				dedupKey, h.Timeout, last.Status, last.Type)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// AwaitStatus waits until the alert with the given dedup key has the given status.
+func (h *Harness) AwaitStatus(tb testing.TB, dedupKey string, status domain.AlertStatus) *domain.Alert {
+	tb.Helper()
+	return h.AwaitAlert(tb, dedupKey, func(a *domain.Alert) bool {
+		return a.Status == status
+	})
+}
+
+// publishedAtHeader stamps the messages published while an observer is
+// registered with the time they were published, in Unix nanoseconds.
+const publishedAtHeader = "argustest-published-at"
+
+// trackingQueue wraps the memory queue and counts published and handled
+// messages, so Sync can tell when the processor has caught up.
+type trackingQueue struct {
+	*memoryqueue.Queue
+
+	mu        sync.Mutex
+	published int64
+	handled   int64
+	observe   func(latency time.Duration)
+}
+
+// newTrackingQueue wraps q.
+func newTrackingQueue(q *memoryqueue.Queue) *trackingQueue {
+	return &trackingQueue{Queue: q}
+}
+
+// Publish publishes a message and records it as outstanding.
+func (q *trackingQueue) Publish(ctx context.Context, msg *queue.Message) error {
+	q.mu.Lock()
+	q.published++
+	observed := q.observe != nil
+	q.mu.Unlock()
+
+	if observed {
+		headers := make(map[string]string, len(msg.Headers)+1)
+		maps.Copy(headers, msg.Headers)
+		headers[publishedAtHeader] = strconv.FormatInt(time.Now().UnixNano(), 10)
+		msg.Headers = headers
+	}
+	if err := q.Queue.Publish(ctx, msg); err != nil {
+		q.mu.Lock()
+		q.published--
+		q.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Start consumes messages, recording each one once the handler returns.
+func (q *trackingQueue) Start(ctx context.Context, handler queue.MessageHandler) error {
+	return q.Queue.Start(ctx, func(ctx context.Context, msg *queue.Message) error {
+		defer func() {
+			q.mu.Lock()
+			q.handled++
+			observe := q.observe
+			q.mu.Unlock()
+
+			if publishedAt, err := strconv.ParseInt(msg.Headers[publishedAtHeader], 10, 64); err == nil && observe != nil {
+				observe(time.Since(time.Unix(0, publishedAt)))
+			}
+		}()
+		return handler(ctx, msg)
+	})
+}
+
+// waitIdle waits until all published messages have been handled.
+func (q *trackingQueue) waitIdle(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		q.mu.Lock()
+		idle := q.handled >= q.published
+		q.mu.Unlock()
+
+		if idle {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.New("processor did not drain the queue in time")
+		}
+		time.Sleep(pollInterval)
+	}
+}
@@ -0,0 +1,260 @@
+package argustest
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"testing"
+	"time"
+
+	"argus-go/internal/domain"
+)
+
+func TestHarness_GroupAndResolve(t *testing.T) {
+	h := Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	trigger := func(dedupKey string) *domain.Event {
+		return &domain.Event{
+			EventManagerID: emID,
+			Summary:        "disk full on " + dedupKey,
+			Severity:       domain.SeverityHigh,
+			Action:         domain.ActionTrigger,
+			Class:          "storage",
+			DedupKey:       dedupKey,
+		}
+	}
+
+	h.Ingest(t, trigger("host-1"))
+	h.Ingest(t, trigger("host-2"))
+	h.Sync(t)
+
+	parent := h.AwaitStatus(t, "host-1", domain.AlertStatusActive)
+	if !parent.IsParent() {
+		t.Errorf("host-1 type = %v, want parent", parent.Type)
+	}
+	child := h.AwaitStatus(t, "host-2", domain.AlertStatusActive)
+	if child.ParentDedupKey != "host-1" {
+		t.Errorf("host-2 parent = %q, want host-1", child.ParentDedupKey)
+	}
+
+	resolve := trigger("host-1")
+	resolve.Action = domain.ActionResolve
+	h.Ingest(t, resolve)
+	h.Sync(t)
+
+	parent = h.AwaitAlert(t, "host-1", nil)
+	if !parent.ResolveRequested {
+		t.Error("parent should wait for its child before resolving")
+	}
+
+	resolve = trigger("host-2")
+	resolve.Action = domain.ActionResolve
+	h.Ingest(t, resolve)
+
+	h.AwaitStatus(t, "host-2", domain.AlertStatusResolved)
+	h.AwaitStatus(t, "host-1", domain.AlertStatusResolved)
+}
+
+func TestHarness_AdvancePastWindow(t *testing.T) {
+	h := Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+	start := h.Now()
+
+	trigger := func(dedupKey string) *domain.Event {
+		return &domain.Event{
+			EventManagerID: emID,
+			Summary:        "disk full on " + dedupKey,
+			Severity:       domain.SeverityHigh,
+			Action:         domain.ActionTrigger,
+			Class:          "storage",
+			DedupKey:       dedupKey,
+		}
+	}
+
+	h.Ingest(t, trigger("host-1"))
+	h.Sync(t)
+	h.Advance(6 * time.Minute)
+	h.Ingest(t, trigger("host-2"))
+	h.Sync(t)
+
+	first := h.AwaitAlert(t, "host-1", nil)
+	if !first.CreatedAt.Equal(start) {
+		t.Errorf("host-1 created at %v, want %v", first.CreatedAt, start)
+	}
+	second := h.AwaitAlert(t, "host-2", nil)
+	if !second.IsParent() {
+		t.Errorf("host-2 type = %v, want parent after the window", second.Type)
+	}
+	if want := start.Add(6 * time.Minute); !second.CreatedAt.Equal(want) {
+		t.Errorf("host-2 created at %v, want %v", second.CreatedAt, want)
+	}
+}
+
+func TestHarness_ServesAPI(t *testing.T) {
+	h := Start(t)
+
+	resp, err := http.Get(h.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /healthz status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestHarness_Tags(t *testing.T) {
+	h := Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	trigger := func(tags map[string]string) {
+		t.Helper()
+		h.Ingest(t, &domain.Event{
+			EventManagerID: emID,
+			Summary:        "Disk full",
+			Action:         domain.ActionTrigger,
+			Class:          "disk",
+			DedupKey:       "disk-1",
+			Tags:           tags,
+		})
+		h.Sync(t)
+	}
+	awaitTags := func(want map[string]string) {
+		t.Helper()
+		h.AwaitAlert(t, "disk-1", func(a *domain.Alert) bool { return maps.Equal(a.Tags, want) })
+	}
+
+	trigger(map[string]string{"host": "db-1"})
+	awaitTags(map[string]string{"host": "db-1"})
+
+	// By default repeated triggers only add tags
+	trigger(map[string]string{"host": "db-2", "disk": "/var"})
+	awaitTags(map[string]string{"host": "db-1", "disk": "/var"})
+
+	em, err := h.EventManagerRepo.GetByID(context.Background(), emID)
+	if err != nil {
+		t.Fatalf("GetByID error: %v", err)
+	}
+	em.TagPolicy = domain.TagPolicyReplace
+	if err := h.EventManagerRepo.Update(context.Background(), em); err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+	trigger(map[string]string{"host": "db-2"})
+	awaitTags(map[string]string{"host": "db-2", "disk": "/var"})
+}
+
+func TestHarness_ParentSummary(t *testing.T) {
+	h := Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	em, err := h.EventManagerRepo.GetByID(context.Background(), emID)
+	if err != nil {
+		t.Fatalf("GetByID error: %v", err)
+	}
+	em.ParentSummary = domain.ParentSummaryPolicy{Strategy: domain.ParentSummaryLatest}
+	if err := h.EventManagerRepo.Update(context.Background(), em); err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+
+	send := func(dedupKey string, action domain.Action) {
+		t.Helper()
+		h.Ingest(t, &domain.Event{
+			EventManagerID: emID,
+			Summary:        "Disk full on " + dedupKey,
+			Action:         action,
+			Class:          "disk",
+			DedupKey:       dedupKey,
+		})
+		h.Sync(t)
+	}
+	awaitSummary := func(want string) {
+		t.Helper()
+		h.AwaitAlert(t, "db-1", func(a *domain.Alert) bool { return a.Summary == want })
+	}
+
+	send("db-1", domain.ActionTrigger)
+	awaitSummary("Disk full on db-1")
+
+	// The parent shows the summary of its latest child
+	send("db-2", domain.ActionTrigger)
+	awaitSummary("Disk full on db-2")
+
+	// and falls back to its own once the child resolves
+	send("db-2", domain.ActionResolve)
+	awaitSummary("Disk full on db-1")
+}
+
+func TestHarness_Storm(t *testing.T) {
+	ctx := context.Background()
+	h := Start(t)
+	emID := h.CreateEventManager(t, "class", 5*time.Minute)
+
+	em, err := h.EventManagerRepo.GetByID(ctx, emID)
+	if err != nil {
+		t.Fatalf("GetByID error: %v", err)
+	}
+	em.Storm = domain.StormPolicy{EventsPerSecond: 1}
+	if err := h.EventManagerRepo.Update(ctx, em); err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+
+	trigger := func(dedupKey string) {
+		t.Helper()
+		h.Ingest(t, &domain.Event{EventManagerID: emID, Summary: dedupKey + " down", Action: domain.ActionTrigger, Class: "web", DedupKey: dedupKey})
+	}
+	notifications := func(dedupKey string) int {
+		t.Helper()
+		records, err := h.NotificationLog.ListByDedupKey(ctx, dedupKey)
+		if err != nil {
+			t.Fatalf("ListByDedupKey error: %v", err)
+		}
+		return len(records)
+	}
+
+	// 10 events in 2s is above 1 event/s
+	for i := range 10 {
+		trigger(fmt.Sprintf("web-%d", i))
+	}
+	h.Advance(2 * time.Second)
+	h.CheckStorms(t)
+
+	stormKey := domain.StormDedupKey(emID)
+	storm := h.AwaitStatus(t, stormKey, domain.AlertStatusActive)
+	if storm.Class != domain.StormClass || !storm.IsParent() {
+		t.Fatalf("storm alert = %+v, want a parent of class %s", storm, domain.StormClass)
+	}
+
+	// New alerts are grouped under the storm alert, which is notified once
+	trigger("db-1")
+	trigger("db-2")
+	h.Sync(t)
+	for _, dedupKey := range []string{"db-1", "db-2"} {
+		child := h.AwaitStatus(t, dedupKey, domain.AlertStatusActive)
+		if child.ParentDedupKey != stormKey {
+			t.Errorf("parent of %s = %q, want %q", dedupKey, child.ParentDedupKey, stormKey)
+		}
+		if n := notifications(dedupKey); n != 0 {
+			t.Errorf("notifications of %s = %d, want 0", dedupKey, n)
+		}
+	}
+	h.AwaitAlert(t, stormKey, func(a *domain.Alert) bool { return a.ChildCount == 2 })
+	if n := notifications(stormKey); n != 1 {
+		t.Errorf("notifications of the storm alert = %d, want 1", n)
+	}
+
+	// The storm ends once the rate stayed low for the quiet period
+	h.Advance(time.Minute)
+	h.CheckStorms(t)
+	h.Advance(5 * time.Minute)
+	h.CheckStorms(t)
+	h.AwaitAlert(t, stormKey, func(a *domain.Alert) bool { return a.ResolveRequested })
+
+	trigger("cache-1")
+	h.Sync(t)
+	if alert := h.AwaitStatus(t, "cache-1", domain.AlertStatusActive); alert.ParentDedupKey == stormKey {
+		t.Errorf("alert after the storm was grouped under the storm alert")
+	}
+}